@@ -0,0 +1,234 @@
+// Command allinone runs the gateway and every service in one process, for
+// demos and small self-hosted installs. The services share one database
+// connection and the gateway calls them in-process instead of over HTTP,
+// so clients see the same API as from a gateway in front of separate
+// services.
+//
+// Usage:
+//
+//	allinone [--migrate] [--wait-for-deps]
+//	STORAGE=memory allinone
+//
+// Recommendations score recipes with SQL and need PostgreSQL; on SQLite or
+// in-memory storage their routes answer 503.
+package main
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"os"
+
+	"meal-prep/migrations"
+	authapp "meal-prep/services/auth/app"
+	"meal-prep/services/gateway/proxy"
+	catalogueapp "meal-prep/services/recipe-catalogue/app"
+	recommendationsapp "meal-prep/services/recommendations/app"
+	"meal-prep/shared/database"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/metrics"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"meal-prep/shared/mtls"
+	"meal-prep/shared/server"
+	"meal-prep/shared/tracing"
+
+	"github.com/gorilla/mux"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	waitForDeps := flag.Bool("wait-for-deps", false, "retry the database connection with backoff instead of exiting")
+	migrate := flag.Bool("migrate", false, "apply pending schema migrations before serving")
+	flag.Parse()
+
+	// Initialize logging first
+	logging.Init("allinone")
+
+	if err := godotenv.Load(); err != nil {
+		logging.Logger.Debug("No .env file found, using system environment variables")
+	}
+
+	// Cancelled on SIGTERM or SIGINT: background jobs stop and the server
+	// drains in-flight requests before main returns
+	ctx, stop := server.SignalContext()
+	defer stop()
+
+	// Export request and query spans when an OTLP endpoint is configured
+	shutdownTracing, err := tracing.Init(ctx, "allinone")
+	if err != nil {
+		logging.Logger.Error("Failed to initialize tracing", "error", err)
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+
+	cfg, err := proxy.LoadConfig()
+	if err != nil {
+		logging.Logger.Error("Failed to load gateway config", "error", err)
+		os.Exit(1)
+	}
+
+	verifier, err := proxy.VerifierFromEnv()
+	if err != nil {
+		logging.Logger.Error("Failed to configure token verification", "error", err)
+		os.Exit(1)
+	}
+
+	// Upstreams of a custom GATEWAY_CONFIG that aren't mounted here are
+	// still called over HTTP
+	upstreamClient, err := mtls.HTTPClientFromEnv(0)
+	if err != nil {
+		logging.Logger.Error("Failed to configure upstream client", "error", err)
+		os.Exit(1)
+	}
+
+	var db *database.DB
+	if !database.UseMemoryStorage() {
+		// One connection pool for every service's schema
+		db, err = database.ConnectWithRetry(ctx, database.RetryConfigFromEnv(*waitForDeps), database.NewConnection)
+		if err != nil {
+			logging.Logger.Error("Failed to connect to database", "error", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+
+		logging.Logger.Info("Database connected successfully")
+	}
+	withRecommendations := db != nil && db.Dialect() == database.DialectPostgres
+
+	if *migrate && db != nil {
+		for _, service := range migrations.Services {
+			if service == "recommendations" && !withRecommendations {
+				continue
+			}
+			if err := migrations.Apply(ctx, db, service); err != nil {
+				logging.Logger.Error("Failed to migrate database", "service", service, "error", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	// Users tagged with a data region are served from that region's
+	// database; in memory mode the one store serves every region
+	var regionDBs map[string]*database.DB
+	if db != nil {
+		regionDBs, err = database.ConnectRegions(ctx, database.RetryConfigFromEnv(*waitForDeps), database.RegionsFromEnv())
+		if err != nil {
+			logging.Logger.Error("Failed to connect to region database", "error", err)
+			os.Exit(1)
+		}
+		for region, regionDB := range regionDBs {
+			defer regionDB.Close()
+
+			if *migrate {
+				for _, service := range migrations.Services {
+					if err := migrations.Apply(ctx, regionDB, service); err != nil {
+						logging.Logger.Error("Failed to migrate database", "region", region, "service", service, "error", err)
+						os.Exit(1)
+					}
+				}
+			}
+		}
+	}
+
+	recommendations, catalogue, err := services(ctx, db, withRecommendations, catalogueapp.New)
+	if err != nil {
+		logging.Logger.Error("Failed to start recipe catalogue", "error", err)
+		os.Exit(1)
+	}
+	if !withRecommendations {
+		logging.Logger.Warn("Recommendations need PostgreSQL, their routes answer 503")
+	}
+
+	regionalRecommendations := make(map[string]http.Handler, len(regionDBs))
+	regionalCatalogue := make(map[string]http.Handler, len(regionDBs))
+	for region, regionDB := range regionDBs {
+		regionalRecommendations[region], regionalCatalogue[region], err = services(ctx, regionDB, regionDB.Dialect() == database.DialectPostgres, catalogueapp.NewRegional)
+		if err != nil {
+			logging.Logger.Error("Failed to start recipe catalogue", "region", region, "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Each upstream of the gateway config is pointed at a host that only
+	// exists in this process. Logins are global, so auth stays on the home
+	// database.
+	mounted := map[string]http.Handler{
+		"auth-service":            authapp.New(db),
+		"recipe-service":          middleware.RouteByRegion(catalogue, regionalCatalogue, catalogueapp.Catalogue),
+		"recommendations-service": middleware.RouteByRegion(recommendations, regionalRecommendations, nil),
+	}
+	hosts := make(map[string]http.RoundTripper, len(mounted))
+	for name, handler := range mounted {
+		cfg.Upstreams[name] = "http://" + name
+		hosts[name] = inProcess{handler: handler}
+	}
+
+	gatewayMetrics := metrics.New("gateway")
+
+	// Routes with tracing, logging and metrics middleware
+	router := mux.NewRouter()
+	router.Use(middleware.TracingMiddleware("gateway"))
+	router.Use(middleware.LoggingMiddleware("gateway"))
+	router.Use(gatewayMetrics.Middleware)
+
+	// Answered by the gateway itself, ahead of the proxied routes
+	router.HandleFunc("/health", healthCheck).Methods("GET")
+	router.Handle("/metrics", gatewayMetrics.Handler()).Methods("GET")
+
+	transport := upstreams{hosts: hosts, fallback: upstreamClient.Transport}
+	if err := proxy.Register(router, cfg, verifier, transport); err != nil {
+		logging.Logger.Error("Failed to register routes", "error", err)
+		os.Exit(1)
+	}
+
+	port := os.Getenv("GATEWAY_PORT")
+	if port == "" {
+		port = "8000"
+	}
+
+	serverCfg := server.ConfigFromEnv()
+	srv, err := server.New(":"+port, proxy.NewHandler(cfg, router), serverCfg)
+	if err != nil {
+		logging.Logger.Error("Failed to configure server", "error", err)
+		os.Exit(1)
+	}
+	// Like the gateway, serve clients plain HTTP; public TLS ends at the
+	// load balancer in front
+	srv.TLSConfig = nil
+
+	logging.Logger.Info("Starting all-in-one server", "port", port, "routes", len(cfg.Routes),
+		"recommendations", withRecommendations, "regions", len(regionDBs))
+	if err := server.Serve(ctx, srv, serverCfg.DrainPeriod); err != nil {
+		logging.Logger.Error("Server failed", "error", err)
+	}
+}
+
+// services returns the recommendations and recipe catalogue handlers on db,
+// the catalogue built by newCatalogue and calling recommendations
+// in-process. Without withRecommendations their routes answer 503.
+func services(ctx context.Context, db *database.DB, withRecommendations bool,
+	newCatalogue func(context.Context, *database.DB, http.RoundTripper) (http.Handler, error)) (http.Handler, http.Handler, error) {
+	recommendations := unavailable("Recommendations need PostgreSQL")
+	if withRecommendations {
+		recommendations = recommendationsapp.New(ctx, db)
+	}
+
+	catalogue, err := newCatalogue(ctx, db, inProcess{handler: recommendations})
+	if err != nil {
+		return nil, nil, err
+	}
+	return recommendations, catalogue, nil
+}
+
+func unavailable(message string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		models.WriteErrorResponse(w, message, http.StatusServiceUnavailable)
+	})
+}
+
+func healthCheck(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status": "healthy", "service": "allinone"}`))
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"meal-prep/shared/logging"
+)
+
+// inProcess carries requests straight to a service's handler instead of
+// over the network. The handler gets a fresh context, cancelled with the
+// caller's, so nothing the caller stored in its context leaks across as it
+// couldn't over HTTP. Responses stream as the handler writes them, which
+// server-sent events rely on.
+type inProcess struct {
+	handler http.Handler
+}
+
+func (t inProcess) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopCancel := context.AfterFunc(req.Context(), cancel)
+
+	in := req.Clone(ctx)
+	in.RequestURI = req.URL.RequestURI()
+	in.RemoteAddr = "in-process"
+	if in.Body == nil {
+		in.Body = http.NoBody
+	}
+
+	body, pipe := io.Pipe()
+	w := &pipeResponseWriter{header: make(http.Header), pipe: pipe, ready: make(chan struct{}), req: req, body: body}
+
+	go func() {
+		defer stopCancel()
+		defer cancel()
+		defer func() {
+			if p := recover(); p != nil {
+				logging.Logger.Error("In-process handler panicked", "path", req.URL.Path, "panic", p)
+				pipe.CloseWithError(fmt.Errorf("handler panicked: %v", p))
+				w.fail()
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			pipe.Close()
+		}()
+		t.handler.ServeHTTP(w, in)
+	}()
+
+	select {
+	case <-w.ready:
+		if w.resp == nil {
+			return nil, fmt.Errorf("in-process %s %s failed", req.Method, req.URL.Path)
+		}
+		return w.resp, nil
+	case <-req.Context().Done():
+		body.CloseWithError(req.Context().Err())
+		return nil, req.Context().Err()
+	}
+}
+
+// pipeResponseWriter hands the response to RoundTrip once the status is
+// written, then pipes the body through to its reader.
+type pipeResponseWriter struct {
+	header http.Header
+	pipe   *io.PipeWriter
+	req    *http.Request
+	body   *io.PipeReader
+
+	once  sync.Once
+	ready chan struct{}
+	resp  *http.Response
+}
+
+func (w *pipeResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *pipeResponseWriter) WriteHeader(code int) {
+	w.once.Do(func() {
+		w.resp = &http.Response{
+			Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+			StatusCode:    code,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        w.header.Clone(),
+			Body:          w.body,
+			ContentLength: -1,
+			Request:       w.req,
+		}
+		close(w.ready)
+	})
+}
+
+func (w *pipeResponseWriter) Write(p []byte) (int, error) {
+	if w.resp == nil && w.header.Get("Content-Type") == "" {
+		w.header.Set("Content-Type", http.DetectContentType(p))
+	}
+	w.WriteHeader(http.StatusOK)
+	return w.pipe.Write(p)
+}
+
+// Flush has nothing to do: every Write waits for the reader.
+func (w *pipeResponseWriter) Flush() {}
+
+// fail releases RoundTrip without a response if none was written.
+func (w *pipeResponseWriter) fail() {
+	w.once.Do(func() { close(w.ready) })
+}
+
+// upstreams routes each request by host to the service mounted under it,
+// and anything else over the network.
+type upstreams struct {
+	hosts    map[string]http.RoundTripper
+	fallback http.RoundTripper
+}
+
+func (u upstreams) RoundTrip(req *http.Request) (*http.Response, error) {
+	if transport, ok := u.hosts[req.URL.Host]; ok {
+		return transport.RoundTrip(req)
+	}
+	return u.fallback.RoundTrip(req)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"meal-prep/shared/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type contextKey struct{}
+
+func TestInProcess_ServesRequest(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "/recipes/3", r.URL.Path)
+		assert.Equal(t, "/recipes/3?lang=fr", r.RequestURI)
+		assert.Nil(t, r.Context().Value(contextKey{}), "caller context values must not cross over")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(append([]byte("echo:"), body...))
+	})
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "gateway")
+	req := httptest.NewRequest("POST", "http://recipe-service/recipes/3?lang=fr", strings.NewReader("hi")).WithContext(ctx)
+	resp, err := inProcess{handler: handler}.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "echo:hi", string(body))
+}
+
+func TestInProcess_StreamsBeforeHandlerReturns(t *testing.T) {
+	done := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, "event: list\n\n")
+		<-r.Context().Done()
+		close(done)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "http://recipe-service/shared-lists/abc/events", nil).WithContext(ctx)
+	resp, err := inProcess{handler: handler}.RoundTrip(req)
+	require.NoError(t, err)
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: list\n", line)
+
+	// The caller going away cancels the handler
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handler was not cancelled with the caller")
+	}
+	resp.Body.Close()
+}
+
+func TestInProcess_PanicFailsTheRequest(t *testing.T) {
+	logging.Init("test")
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	_, err := inProcess{handler: handler}.RoundTrip(httptest.NewRequest("GET", "http://auth-service/login", nil))
+	assert.Error(t, err)
+}
+
+func TestUpstreams_FallsBackToNetworkForUnmountedHosts(t *testing.T) {
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "remote")
+	}))
+	defer remote.Close()
+	mounted := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "mounted")
+	})
+	transport := upstreams{
+		hosts:    map[string]http.RoundTripper{"auth-service": inProcess{handler: mounted}},
+		fallback: http.DefaultTransport,
+	}
+
+	for target, expected := range map[string]string{"http://auth-service/login": "mounted", remote.URL + "/x": "remote"} {
+		req, err := http.NewRequest("GET", target, nil)
+		require.NoError(t, err)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, expected, string(body), target)
+	}
+}
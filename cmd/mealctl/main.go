@@ -0,0 +1,299 @@
+// Command mealctl bundles operational tasks for the meal-prep stack.
+//
+// Usage:
+//
+//	mealctl seed [--dataset default|demo]
+//	mealctl import --file dataset.json
+//	mealctl import-taxonomy --file taxonomy.json
+//	mealctl grant-admin --email someone@example.com
+//	mealctl migrate [--service auth|recipe-catalogue|recommendations] [--status]
+//	mealctl backfill --job normalize-units|recipe-costs|search-indexes
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"meal-prep/migrations"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/database"
+	"meal-prep/shared/events"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+	"meal-prep/shared/seed"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	logging.Init("mealctl")
+
+	if err := godotenv.Load(); err != nil {
+		logging.Logger.Debug("No .env file found, using system environment variables")
+	}
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "seed":
+		err = runSeed(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	case "import-taxonomy":
+		err = runImportTaxonomy(os.Args[2:])
+	case "grant-admin":
+		err = runGrantAdmin(os.Args[2:])
+	case "migrate":
+		err = runMigrate(os.Args[2:])
+	case "backfill":
+		err = runBackfill(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		logging.Logger.Error("Command failed", "command", os.Args[1], "error", err)
+		os.Exit(1)
+	}
+}
+
+func runSeed(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	dataset := fs.String("dataset", "default", "dataset to load: "+strings.Join(datasetNames(), ", "))
+	fs.Parse(args)
+
+	datasets, ok := seed.Datasets[*dataset]
+	if !ok {
+		return fmt.Errorf("unknown dataset %q", *dataset)
+	}
+
+	db, err := database.NewConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for _, ds := range datasets {
+		if err := seed.Run(db, ds); err != nil {
+			return err
+		}
+	}
+
+	logging.Logger.Info("Seed completed", "dataset", *dataset)
+	return nil
+}
+
+// runImport bulk-loads a JSON dataset shaped like seed.Dataset. It shares the
+// seed code path, so re-importing the same file is a no-op.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "path to a JSON dataset with users, categories, ingredients and recipes")
+	fs.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("--file is required")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	var ds seed.Dataset
+	if err := json.Unmarshal(data, &ds); err != nil {
+		return fmt.Errorf("parse %s: %w", *file, err)
+	}
+
+	db, err := database.NewConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := seed.Run(db, ds); err != nil {
+		return err
+	}
+
+	logging.Logger.Info("Import completed", "file", *file,
+		"ingredients", len(ds.Ingredients), "recipes", len(ds.Recipes))
+	return nil
+}
+
+// runImportTaxonomy maps a food taxonomy subset onto the catalogue's
+// ingredients, setting their categories and adding allergen tags and
+// aliases. Like import, running it twice is a no-op.
+func runImportTaxonomy(args []string) error {
+	fs := flag.NewFlagSet("import-taxonomy", flag.ExitOnError)
+	file := fs.String("file", "", "path to a JSON taxonomy with terms mapped to categories and allergens")
+	fs.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("--file is required")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	var taxonomy seed.Taxonomy
+	if err := json.Unmarshal(data, &taxonomy); err != nil {
+		return fmt.Errorf("parse %s: %w", *file, err)
+	}
+
+	db, err := database.NewConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	result, err := seed.ImportTaxonomy(db, taxonomy)
+	if err != nil {
+		return err
+	}
+
+	logging.Logger.Info("Taxonomy import completed", "file", *file, "source", taxonomy.Source,
+		"ingredients", result.Ingredients, "created", result.Created, "recategorized", result.Recategorized,
+		"allergens_tagged", result.AllergensTagged, "aliases_added", result.AliasesAdded)
+	return nil
+}
+
+// runGrantAdmin promotes an existing user to the admin role. The new role is
+// picked up the next time the user logs in and receives a fresh token.
+func runGrantAdmin(args []string) error {
+	fs := flag.NewFlagSet("grant-admin", flag.ExitOnError)
+	email := fs.String("email", "", "email of the user to promote")
+	fs.Parse(args)
+
+	if *email == "" {
+		return fmt.Errorf("--email is required")
+	}
+
+	db, err := database.NewConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	result, err := db.Exec(`
+		UPDATE auth.users SET role = 'admin', updated_at = CURRENT_TIMESTAMP
+		WHERE email = $1`, *email)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("no user with email %q", *email)
+	}
+
+	logging.Logger.Info("Granted admin role", "email", *email)
+	return nil
+}
+
+// runMigrate applies pending migrations to every service schema, or to one
+// with --service. --status lists each migration and whether it has run
+// instead.
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	only := fs.String("service", "", "migrate only this service's schema: "+strings.Join(migrations.Services, ", "))
+	status := fs.Bool("status", false, "list migrations and whether they have been applied, without applying any")
+	fs.Parse(args)
+
+	services := migrations.Services
+	if *only != "" {
+		services = []string{*only}
+	}
+
+	db, err := database.NewPostgresConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	for _, service := range services {
+		if !*status {
+			if err := migrations.Apply(ctx, db, service); err != nil {
+				return err
+			}
+			continue
+		}
+
+		set, err := migrations.For(service)
+		if err != nil {
+			return err
+		}
+		states, err := db.MigrationStatus(ctx, set)
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			applied := "pending"
+			if state.Applied {
+				applied = "applied"
+			}
+			fmt.Printf("%-17s V%03d  %-8s %s\n", set.Schema, state.Version, applied, state.Description)
+		}
+	}
+	return nil
+}
+
+// runBackfill runs one of the recipe catalogue's data quality backfills to
+// completion, printing its progress. It is the same job admins start with
+// POST /backfills, for when one is too long to follow over HTTP.
+func runBackfill(args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	job := fs.String("job", "", "backfill to run: "+strings.Join(models.BackfillJobs, ", "))
+	fs.Parse(args)
+
+	if *job == "" {
+		return fmt.Errorf("--job is required")
+	}
+
+	db, err := database.NewConnection()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	costService := service.NewCostService(repository.NewCostRepository(db), repository.NewRecipeRepository(db),
+		repository.NewIngredientRepository(db), events.NewBus())
+	backfills := service.NewBackfillService(repository.NewBackfillRepository(db), costService)
+
+	// The service logs the outcome
+	_, err = backfills.RunBackfill(context.Background(), *job, func(run models.BackfillRun) {
+		fmt.Printf("%s: %d/%d done, %d updated\n", run.Job, run.Processed, run.Total, run.Updated)
+	})
+	return err
+}
+
+func datasetNames() []string {
+	names := make([]string, 0, len(seed.Datasets))
+	for name := range seed.Datasets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: mealctl <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  seed             load categories, ingredients and demo users idempotently")
+	fmt.Fprintln(os.Stderr, "  import           bulk-load recipes and ingredients from a JSON dataset")
+	fmt.Fprintln(os.Stderr, "  import-taxonomy  set ingredient categories, allergens and aliases from a food taxonomy")
+	fmt.Fprintln(os.Stderr, "  grant-admin      give an existing user the admin role")
+	fmt.Fprintln(os.Stderr, "  migrate          apply pending schema migrations, or list them with --status")
+	fmt.Fprintln(os.Stderr, "  backfill         repair recipe catalogue data: normalize units, reprice recipes, rebuild search indexes")
+}
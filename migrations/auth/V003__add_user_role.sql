@@ -0,0 +1,7 @@
+-- Roles are carried in the JWT so services can gate admin-only endpoints.
+-- Promote users with `mealctl grant-admin --email ...`.
+ALTER TABLE auth.users
+    ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
+
+ALTER TABLE auth.users
+    ADD CONSTRAINT chk_users_role CHECK (role IN ('user', 'admin'));
@@ -0,0 +1,15 @@
+-- Long-lived refresh tokens, exchanged at /refresh for a new access token.
+-- Only a SHA-256 hash of each token is stored. A token is revoked when it is
+-- rotated or on logout; presenting a revoked token again revokes all of the
+-- user's tokens, as it may have been stolen.
+CREATE TABLE IF NOT EXISTS auth.refresh_tokens
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER   NOT NULL REFERENCES auth.users (id) ON DELETE CASCADE,
+    token_hash CHAR(64)  NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON auth.refresh_tokens (user_id);
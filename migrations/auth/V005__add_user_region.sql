@@ -0,0 +1,5 @@
+-- Data residency region a user's data is kept in, e.g. 'eu'. Empty means
+-- the home database. Carried in the JWT so services can route the user's
+-- requests to their region's database.
+ALTER TABLE auth.users
+    ADD COLUMN IF NOT EXISTS region VARCHAR(32) NOT NULL DEFAULT '';
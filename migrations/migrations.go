@@ -0,0 +1,52 @@
+// Package migrations embeds the versioned SQL of each service's schema, so
+// services and mealctl can apply it without the files on disk. The same
+// files are what the Flyway containers in docker-compose run.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/logging"
+)
+
+//go:embed auth/*.sql recipe-catalogue/*.sql recommendations/*.sql
+var files embed.FS
+
+// Services lists the services with a schema, in the order they are migrated.
+var Services = []string{"auth", "recipe-catalogue", "recommendations"}
+
+// For returns the migrations of a service's schema, e.g. recipe_catalogue
+// for "recipe-catalogue".
+func For(service string) (database.MigrationSet, error) {
+	dir, err := fs.Sub(files, service)
+	if err != nil {
+		return database.MigrationSet{}, err
+	}
+	set, err := database.LoadMigrations(strings.ReplaceAll(service, "-", "_"), dir)
+	if err != nil {
+		return database.MigrationSet{}, err
+	}
+	if len(set.Migrations) == 0 {
+		return database.MigrationSet{}, fmt.Errorf("no migrations for service %q", service)
+	}
+	return set, nil
+}
+
+// Apply brings a service's schema up to date.
+func Apply(ctx context.Context, db *database.DB, service string) error {
+	set, err := For(service)
+	if err != nil {
+		return err
+	}
+	ran, err := db.Migrate(ctx, set)
+	if err != nil {
+		return err
+	}
+	logging.Logger.Info("Schema up to date", "schema", set.Schema, "applied", len(ran))
+	return nil
+}
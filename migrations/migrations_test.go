@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFor_EveryServiceHasContiguousVersions(t *testing.T) {
+	for _, service := range Services {
+		t.Run(service, func(t *testing.T) {
+			set, err := For(service)
+			require.NoError(t, err)
+
+			for i, m := range set.Migrations {
+				assert.Equal(t, i+1, m.Version, m.Description)
+			}
+		})
+	}
+}
+
+func TestFor_SchemaNames(t *testing.T) {
+	set, err := For("recipe-catalogue")
+	require.NoError(t, err)
+	assert.Equal(t, "recipe_catalogue", set.Schema)
+
+	_, err = For("billing")
+	assert.Error(t, err)
+}
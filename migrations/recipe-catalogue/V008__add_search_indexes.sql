@@ -0,0 +1,26 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Ingredient search matches ILIKE '%term%' on name and description, which a
+-- btree index cannot serve. Trigram GIN indexes turn it into a bitmap scan.
+CREATE INDEX IF NOT EXISTS idx_ingredients_name_trgm
+    ON recipe_catalogue.ingredients USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_ingredients_description_trgm
+    ON recipe_catalogue.ingredients USING GIN (description gin_trgm_ops);
+
+-- Category listings filter on the category and order by name; the composite
+-- indexes return rows pre-sorted and replace the single-column ones.
+CREATE INDEX IF NOT EXISTS idx_recipes_category_name
+    ON recipe_catalogue.recipes (category_id, name);
+DROP INDEX IF EXISTS recipe_catalogue.idx_recipes_category;
+
+CREATE INDEX IF NOT EXISTS idx_ingredients_category_name
+    ON recipe_catalogue.ingredients (category, name);
+DROP INDEX IF EXISTS recipe_catalogue.idx_ingredients_category;
+
+-- Recipe search by ingredients reads recipe_id straight from the index.
+-- Lookups by recipe are already served by the recipe_ingredients_unique_per_recipe
+-- constraint, which leads with recipe_id.
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_ingredient_recipe
+    ON recipe_catalogue.recipe_ingredients (ingredient_id, recipe_id);
+DROP INDEX IF EXISTS recipe_catalogue.idx_recipe_ingredients_ingredient_id;
+DROP INDEX IF EXISTS recipe_catalogue.idx_recipe_ingredients_recipe_id;
@@ -0,0 +1,15 @@
+-- Ingredient usage counts, refreshed in the background so the popular
+-- ingredients endpoint reads a precomputed row set instead of aggregating
+-- recipe_ingredients on every request.
+CREATE MATERIALIZED VIEW IF NOT EXISTS recipe_catalogue.ingredient_usage AS
+SELECT i.id AS ingredient_id,
+       COUNT(DISTINCT ri.recipe_id) AS recipe_count
+FROM recipe_catalogue.ingredients i
+LEFT JOIN recipe_catalogue.recipe_ingredients ri ON ri.ingredient_id = i.id
+GROUP BY i.id;
+
+-- REFRESH ... CONCURRENTLY requires a unique index
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ingredient_usage_ingredient_id
+    ON recipe_catalogue.ingredient_usage(ingredient_id);
+CREATE INDEX IF NOT EXISTS idx_ingredient_usage_recipe_count
+    ON recipe_catalogue.ingredient_usage(recipe_count DESC);
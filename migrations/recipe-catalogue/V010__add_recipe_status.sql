@@ -0,0 +1,10 @@
+-- Recipes default to published so existing rows stay visible on public
+-- listings; drafts and private recipes are only listed to their owner.
+ALTER TABLE recipe_catalogue.recipes
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published';
+
+ALTER TABLE recipe_catalogue.recipes
+    ADD CONSTRAINT chk_recipes_status CHECK (status IN ('draft', 'private', 'published'));
+
+CREATE INDEX IF NOT EXISTS idx_recipes_user_status
+    ON recipe_catalogue.recipes (user_id, status, updated_at DESC);
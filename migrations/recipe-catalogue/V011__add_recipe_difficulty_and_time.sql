@@ -0,0 +1,9 @@
+-- Both are optional. When difficulty is NULL the catalogue estimates it from
+-- the recipe's ingredients and total time and marks the response as estimated.
+ALTER TABLE recipe_catalogue.recipes
+    ADD COLUMN IF NOT EXISTS difficulty VARCHAR(10),
+    ADD COLUMN IF NOT EXISTS total_time_minutes INT;
+
+ALTER TABLE recipe_catalogue.recipes
+    ADD CONSTRAINT chk_recipes_difficulty CHECK (difficulty IN ('easy', 'medium', 'hard')),
+    ADD CONSTRAINT chk_recipes_total_time CHECK (total_time_minutes >= 0);
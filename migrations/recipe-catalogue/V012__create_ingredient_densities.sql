@@ -0,0 +1,21 @@
+-- Densities let the catalogue convert between volume and weight measures of
+-- the same ingredient. Maintained by admins; ingredients without a row simply
+-- cannot be converted across mass and volume.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_densities
+(
+    ingredient_id INTEGER PRIMARY KEY REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    grams_per_ml  NUMERIC(8, 4)                       NOT NULL,
+    source        TEXT,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT ingredient_densities_positive CHECK (grams_per_ml > 0)
+);
+
+INSERT INTO recipe_catalogue.ingredient_densities (ingredient_id, grams_per_ml, source)
+SELECT i.id, d.grams_per_ml, 'USDA FoodData Central'
+FROM (VALUES ('Rice', 0.85),
+             ('Parmesan Cheese', 0.42),
+             ('Olive Oil', 0.91),
+             ('Salt', 1.22),
+             ('Black Pepper', 0.46)) AS d(name, grams_per_ml)
+JOIN recipe_catalogue.ingredients i ON i.name = d.name
+ON CONFLICT (ingredient_id) DO NOTHING;
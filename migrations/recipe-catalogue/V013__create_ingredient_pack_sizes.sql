@@ -0,0 +1,24 @@
+-- Sizes an ingredient is typically sold in (e.g. a 400 g can of tomatoes).
+-- The grocery list rounds totals up to whole packs using these.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_pack_sizes
+(
+    id            SERIAL PRIMARY KEY,
+    ingredient_id INTEGER                             NOT NULL REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    quantity      DECIMAL(8, 2)                       NOT NULL,
+    unit          VARCHAR(20)                         NOT NULL,
+    label         VARCHAR(50),
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT ingredient_pack_sizes_quantity_positive CHECK (quantity > 0),
+    CONSTRAINT ingredient_pack_sizes_unit_not_empty CHECK (length(trim(unit)) > 0),
+    CONSTRAINT ingredient_pack_sizes_unique UNIQUE (ingredient_id, quantity, unit)
+);
+
+INSERT INTO recipe_catalogue.ingredient_pack_sizes (ingredient_id, quantity, unit, label)
+SELECT i.id, p.quantity, p.unit, p.label
+FROM (VALUES ('Rice', 1000, 'g', 'bag'),
+             ('Olive Oil', 500, 'ml', 'bottle'),
+             ('Parmesan Cheese', 200, 'g', 'block'),
+             ('Eggs', 12, 'pieces', 'box'),
+             ('Eggs', 6, 'pieces', 'box')) AS p(name, quantity, unit, label)
+JOIN recipe_catalogue.ingredients i ON i.name = p.name
+ON CONFLICT (ingredient_id, quantity, unit) DO NOTHING;
@@ -0,0 +1,21 @@
+-- A store layout is a user's ordering of ingredient categories matching the
+-- aisles of a shop they use, so grocery lists can be walked front to back.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.store_layouts
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER                             NOT NULL,
+    name       VARCHAR(100)                        NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT store_layouts_name_not_empty CHECK (length(trim(name)) > 0),
+    CONSTRAINT store_layouts_unique_name_per_user UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_catalogue.store_layout_aisles
+(
+    layout_id INTEGER     NOT NULL REFERENCES recipe_catalogue.store_layouts (id) ON DELETE CASCADE,
+    position  INTEGER     NOT NULL,
+    category  VARCHAR(50) NOT NULL,
+    PRIMARY KEY (layout_id, position),
+    CONSTRAINT store_layout_aisles_unique_category UNIQUE (layout_id, category)
+);
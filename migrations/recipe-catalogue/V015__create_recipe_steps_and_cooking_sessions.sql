@@ -0,0 +1,39 @@
+-- Ordered method steps of a recipe. Each step may name the ingredients it
+-- uses so cooking mode can show the amounts needed at that point.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_steps
+(
+    id               SERIAL PRIMARY KEY,
+    recipe_id        INTEGER                             NOT NULL REFERENCES recipe_catalogue.recipes (id) ON DELETE CASCADE,
+    position         INTEGER                             NOT NULL,
+    instruction      TEXT                                NOT NULL,
+    duration_minutes INTEGER,
+    created_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT recipe_steps_instruction_not_empty CHECK (length(trim(instruction)) > 0),
+    CONSTRAINT recipe_steps_duration_positive CHECK (duration_minutes > 0),
+    CONSTRAINT recipe_steps_unique_position UNIQUE (recipe_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_step_ingredients
+(
+    step_id       INTEGER NOT NULL REFERENCES recipe_catalogue.recipe_steps (id) ON DELETE CASCADE,
+    ingredient_id INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    PRIMARY KEY (step_id, ingredient_id)
+);
+
+-- A cooking session tracks how far a user has got through a recipe in
+-- cooking mode, so a hands-free UI can resume on another device.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.cooking_sessions
+(
+    token        VARCHAR(36) PRIMARY KEY,
+    user_id      INTEGER                             NOT NULL,
+    recipe_id    INTEGER                             NOT NULL REFERENCES recipe_catalogue.recipes (id) ON DELETE CASCADE,
+    scale        NUMERIC(6, 2)                       NOT NULL DEFAULT 1,
+    current_step INTEGER                             NOT NULL DEFAULT 0,
+    started_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP,
+    CONSTRAINT cooking_sessions_scale_positive CHECK (scale > 0),
+    CONSTRAINT cooking_sessions_step_not_negative CHECK (current_step >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cooking_sessions_user ON recipe_catalogue.cooking_sessions (user_id, started_at DESC);
@@ -0,0 +1,12 @@
+-- Timers a cooking UI can start when the user reaches a step, e.g.
+-- "Simmer" for 20 minutes. A step may run several at once.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_step_timers
+(
+    step_id          INTEGER      NOT NULL REFERENCES recipe_catalogue.recipe_steps (id) ON DELETE CASCADE,
+    position         INTEGER      NOT NULL,
+    label            VARCHAR(100) NOT NULL,
+    duration_seconds INTEGER      NOT NULL,
+    PRIMARY KEY (step_id, position),
+    CONSTRAINT recipe_step_timers_label_not_empty CHECK (length(trim(label)) > 0),
+    CONSTRAINT recipe_step_timers_duration_positive CHECK (duration_seconds > 0)
+);
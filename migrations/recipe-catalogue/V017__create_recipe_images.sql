@@ -0,0 +1,15 @@
+-- Photos of a recipe, shown in position order. At most one per recipe is
+-- the cover used in listings and link previews.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_images
+(
+    id         SERIAL PRIMARY KEY,
+    recipe_id  INTEGER                             NOT NULL REFERENCES recipe_catalogue.recipes (id) ON DELETE CASCADE,
+    url        TEXT                                NOT NULL,
+    caption    VARCHAR(200),
+    position   INTEGER                             NOT NULL,
+    is_cover   BOOLEAN   DEFAULT FALSE             NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_images_recipe ON recipe_catalogue.recipe_images (recipe_id, position);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_images_one_cover ON recipe_catalogue.recipe_images (recipe_id) WHERE is_cover;
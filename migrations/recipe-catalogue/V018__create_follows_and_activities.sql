@@ -0,0 +1,26 @@
+-- Who follows whom. Users live in the auth service, so there is no foreign
+-- key on either side.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.user_follows
+(
+    follower_id INTEGER                             NOT NULL,
+    followee_id INTEGER                             NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_follows_followee ON recipe_catalogue.user_follows (followee_id);
+
+-- Activity recorded from domain events, read back as followers' feeds. One
+-- row per event type and recipe, so re-publishing a recipe is a no-op.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.activities
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER                             NOT NULL,
+    type       VARCHAR(50)                         NOT NULL,
+    recipe_id  INTEGER                             NOT NULL REFERENCES recipe_catalogue.recipes (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (type, recipe_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_activities_user_created ON recipe_catalogue.activities (user_id, created_at DESC);
@@ -0,0 +1,12 @@
+-- Public profile settings. A user without a row has the defaults: a public
+-- profile showing both recipes and stats.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.user_profiles
+(
+    user_id      INTEGER PRIMARY KEY,
+    display_name VARCHAR(50),
+    bio          VARCHAR(500),
+    is_public    BOOLEAN   DEFAULT TRUE              NOT NULL,
+    show_recipes BOOLEAN   DEFAULT TRUE              NOT NULL,
+    show_stats   BOOLEAN   DEFAULT TRUE              NOT NULL,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
@@ -0,0 +1,10 @@
+-- Where a recipe was adapted from. Deleting the original keeps the fork but
+-- ends its "adapted from" chain there.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_lineage
+(
+    recipe_id  INTEGER PRIMARY KEY REFERENCES recipe_catalogue.recipes (id) ON DELETE CASCADE,
+    parent_id  INTEGER                             REFERENCES recipe_catalogue.recipes (id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_lineage_parent ON recipe_catalogue.recipe_lineage (parent_id);
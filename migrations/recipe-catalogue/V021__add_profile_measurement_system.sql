@@ -0,0 +1,4 @@
+-- Units recipes and grocery lists are shown in. NULL keeps each recipe's
+-- units as written.
+ALTER TABLE recipe_catalogue.user_profiles
+    ADD COLUMN IF NOT EXISTS measurement_system VARCHAR(10) CHECK (measurement_system IN ('metric', 'imperial'));
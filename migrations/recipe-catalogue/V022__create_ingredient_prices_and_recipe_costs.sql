@@ -0,0 +1,32 @@
+-- What an ingredient costs, e.g. 1.29 for 400 g. Rows are never updated, the
+-- newest one is the current price and the rest are its history.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_prices
+(
+    id            SERIAL PRIMARY KEY,
+    ingredient_id INTEGER                             NOT NULL REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    price         DECIMAL(10, 2)                      NOT NULL,
+    quantity      DECIMAL(8, 2)                       NOT NULL,
+    unit          VARCHAR(20)                         NOT NULL,
+    recorded_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT ingredient_prices_price_not_negative CHECK (price >= 0),
+    CONSTRAINT ingredient_prices_quantity_positive CHECK (quantity > 0),
+    CONSTRAINT ingredient_prices_unit_not_empty CHECK (length(trim(unit)) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ingredient_prices_ingredient_recorded
+    ON recipe_catalogue.ingredient_prices (ingredient_id, recorded_at DESC, id DESC);
+
+-- A recipe's cost at the moment one of its ingredients changed price.
+-- Ingredients without a usable price are left out of the cost and counted.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_cost_snapshots
+(
+    id                 SERIAL PRIMARY KEY,
+    recipe_id          INTEGER                             NOT NULL REFERENCES recipe_catalogue.recipes (id) ON DELETE CASCADE,
+    cost               DECIMAL(10, 2)                      NOT NULL,
+    priced_ingredients INTEGER                             NOT NULL,
+    total_ingredients  INTEGER                             NOT NULL,
+    recorded_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_cost_snapshots_recipe_recorded
+    ON recipe_catalogue.recipe_cost_snapshots (recipe_id, recorded_at, id);
@@ -0,0 +1,17 @@
+-- Ingredients that can stand in for another. ratio is how much of the
+-- substitute replaces one unit of the original (0.75 cup of oil for a cup
+-- of butter).
+CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_substitutions
+(
+    ingredient_id INTEGER                             NOT NULL REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    substitute_id INTEGER                             NOT NULL REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    ratio         DECIMAL(6, 3)                       NOT NULL DEFAULT 1,
+    notes         VARCHAR(200),
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (ingredient_id, substitute_id),
+    CONSTRAINT ingredient_substitutions_ratio_positive CHECK (ratio > 0),
+    CONSTRAINT ingredient_substitutions_not_self CHECK (ingredient_id <> substitute_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ingredient_substitutions_substitute
+    ON recipe_catalogue.ingredient_substitutions (substitute_id);
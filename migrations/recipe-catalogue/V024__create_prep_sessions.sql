@@ -0,0 +1,26 @@
+-- A prep session is a batch-cooking plan: several recipes cooked in one go,
+-- each scaled up and split into portions for the week.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.prep_sessions
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER                             NOT NULL,
+    name       VARCHAR(100)                        NOT NULL,
+    prep_date  DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT prep_sessions_name_not_empty CHECK (length(trim(name)) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_prep_sessions_user ON recipe_catalogue.prep_sessions (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS recipe_catalogue.prep_session_recipes
+(
+    session_id INTEGER       NOT NULL REFERENCES recipe_catalogue.prep_sessions (id) ON DELETE CASCADE,
+    recipe_id  INTEGER       NOT NULL REFERENCES recipe_catalogue.recipes (id) ON DELETE CASCADE,
+    position   INTEGER       NOT NULL,
+    scale      NUMERIC(6, 2) NOT NULL DEFAULT 1,
+    portions   INTEGER       NOT NULL DEFAULT 1,
+    PRIMARY KEY (session_id, recipe_id),
+    CONSTRAINT prep_session_recipes_scale_positive CHECK (scale > 0),
+    CONSTRAINT prep_session_recipes_portions_positive CHECK (portions > 0)
+);
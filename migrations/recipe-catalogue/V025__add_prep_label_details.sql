@@ -0,0 +1,8 @@
+-- How long a prepped batch keeps and how to reheat it, printed on the
+-- container labels.
+ALTER TABLE recipe_catalogue.prep_session_recipes
+    ADD COLUMN IF NOT EXISTS keeps_days          INTEGER NOT NULL DEFAULT 4,
+    ADD COLUMN IF NOT EXISTS reheat_instructions VARCHAR(200);
+
+ALTER TABLE recipe_catalogue.prep_session_recipes
+    ADD CONSTRAINT prep_session_recipes_keeps_days_range CHECK (keeps_days BETWEEN 1 AND 365);
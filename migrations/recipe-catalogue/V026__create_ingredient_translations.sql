@@ -0,0 +1,60 @@
+-- Ingredient names in other languages. locale is a lower-case language tag
+-- such as 'ru' or 'pt-br'; ingredients.name stays the English name and is the
+-- fallback when a locale has no translation.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_translations
+(
+    ingredient_id INTEGER                             NOT NULL REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    locale        VARCHAR(12)                         NOT NULL,
+    name          VARCHAR(100)                        NOT NULL,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (ingredient_id, locale),
+    CONSTRAINT ingredient_translations_name_not_empty CHECK (length(trim(name)) > 0)
+);
+
+-- Ingredient search matches translated names with ILIKE '%term%' as well
+CREATE INDEX IF NOT EXISTS idx_ingredient_translations_name_trgm
+    ON recipe_catalogue.ingredient_translations USING GIN (name gin_trgm_ops);
+
+INSERT INTO recipe_catalogue.ingredient_translations (ingredient_id, locale, name)
+SELECT i.id, t.locale, t.name
+FROM (VALUES ('Chicken Breast', 'ru', 'Куриная грудка'),
+             ('Chicken Breast', 'de', 'Hähnchenbrust'),
+             ('Chicken Breast', 'es', 'Pechuga de pollo'),
+             ('Ground Beef', 'ru', 'Говяжий фарш'),
+             ('Ground Beef', 'de', 'Rinderhackfleisch'),
+             ('Ground Beef', 'es', 'Carne molida de res'),
+             ('Salmon Fillet', 'ru', 'Филе лосося'),
+             ('Salmon Fillet', 'de', 'Lachsfilet'),
+             ('Salmon Fillet', 'es', 'Filete de salmón'),
+             ('Rice', 'ru', 'Рис'),
+             ('Rice', 'de', 'Reis'),
+             ('Rice', 'es', 'Arroz'),
+             ('Onion', 'ru', 'Лук'),
+             ('Onion', 'de', 'Zwiebel'),
+             ('Onion', 'es', 'Cebolla'),
+             ('Garlic', 'ru', 'Чеснок'),
+             ('Garlic', 'de', 'Knoblauch'),
+             ('Garlic', 'es', 'Ajo'),
+             ('Tomato', 'ru', 'Помидор'),
+             ('Tomato', 'de', 'Tomate'),
+             ('Tomato', 'es', 'Tomate'),
+             ('Lettuce', 'ru', 'Салат ромэн'),
+             ('Lettuce', 'de', 'Römersalat'),
+             ('Lettuce', 'es', 'Lechuga romana'),
+             ('Parmesan Cheese', 'ru', 'Сыр пармезан'),
+             ('Parmesan Cheese', 'de', 'Parmesan'),
+             ('Parmesan Cheese', 'es', 'Queso parmesano'),
+             ('Olive Oil', 'ru', 'Оливковое масло'),
+             ('Olive Oil', 'de', 'Olivenöl'),
+             ('Olive Oil', 'es', 'Aceite de oliva'),
+             ('Salt', 'ru', 'Соль'),
+             ('Salt', 'de', 'Salz'),
+             ('Salt', 'es', 'Sal'),
+             ('Black Pepper', 'ru', 'Чёрный перец'),
+             ('Black Pepper', 'de', 'Schwarzer Pfeffer'),
+             ('Black Pepper', 'es', 'Pimienta negra'),
+             ('Eggs', 'ru', 'Яйца'),
+             ('Eggs', 'de', 'Eier'),
+             ('Eggs', 'es', 'Huevos')) AS t(ingredient, locale, name)
+JOIN recipe_catalogue.ingredients i ON i.name = t.ingredient
+ON CONFLICT (ingredient_id, locale) DO NOTHING;
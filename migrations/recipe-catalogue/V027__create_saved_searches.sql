@@ -0,0 +1,28 @@
+-- A saved search keeps a recipe search, its ingredients plus optional
+-- category and time filters, so a user can rerun it or be alerted when new
+-- recipes match.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.saved_searches
+(
+    id               SERIAL PRIMARY KEY,
+    user_id          INTEGER                             NOT NULL,
+    name             VARCHAR(100)                        NOT NULL,
+    category_id      INTEGER                             REFERENCES recipe_catalogue.categories (id) ON DELETE SET NULL,
+    max_time_minutes INTEGER,
+    notify           BOOLEAN   DEFAULT FALSE             NOT NULL,
+    -- Alerts cover recipes created after this; saving the search resets it
+    last_checked_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    created_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT saved_searches_name_not_empty CHECK (length(trim(name)) > 0),
+    CONSTRAINT saved_searches_max_time_positive CHECK (max_time_minutes > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user ON recipe_catalogue.saved_searches (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_notify ON recipe_catalogue.saved_searches (last_checked_at) WHERE notify;
+
+CREATE TABLE IF NOT EXISTS recipe_catalogue.saved_search_ingredients
+(
+    saved_search_id INTEGER NOT NULL REFERENCES recipe_catalogue.saved_searches (id) ON DELETE CASCADE,
+    ingredient_id   INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    PRIMARY KEY (saved_search_id, ingredient_id)
+);
@@ -0,0 +1,27 @@
+-- Ingredients no recipe has used for a while can be archived (hidden from
+-- listings and search) or deleted. last_used_at records when the ingredient
+-- last left a recipe; one that never did counts from created_at. Using an
+-- archived ingredient in a recipe again brings it back.
+ALTER TABLE recipe_catalogue.ingredients
+    ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS archived_at  TIMESTAMP;
+
+CREATE OR REPLACE FUNCTION recipe_catalogue.track_ingredient_use() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE recipe_catalogue.ingredients SET archived_at = NULL
+        WHERE id = NEW.ingredient_id AND archived_at IS NOT NULL;
+    ELSE
+        UPDATE recipe_catalogue.ingredients SET last_used_at = CURRENT_TIMESTAMP WHERE id = OLD.ingredient_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS recipe_ingredients_track_use ON recipe_catalogue.recipe_ingredients;
+CREATE TRIGGER recipe_ingredients_track_use
+    AFTER INSERT OR DELETE
+    ON recipe_catalogue.recipe_ingredients
+    FOR EACH ROW
+EXECUTE FUNCTION recipe_catalogue.track_ingredient_use();
@@ -0,0 +1,6 @@
+-- How much of each planned recipe to cook. Recipes do not record a yield,
+-- so a plan's servings are kept as a multiplier of the recipes as written,
+-- like the scale of a prep session recipe. Printouts and the plan's shopping
+-- list use it.
+ALTER TABLE recipe_catalogue.meal_plans
+    ADD COLUMN IF NOT EXISTS scale NUMERIC(6, 2) NOT NULL DEFAULT 1 CHECK (scale > 0);
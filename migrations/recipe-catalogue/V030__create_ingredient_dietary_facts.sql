@@ -0,0 +1,32 @@
+-- What the dietary classification rules know about an ingredient beyond its
+-- category. Flags record what it is made from; an override settles the diet
+-- outright where the rules would get it wrong.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_dietary_flags
+(
+    ingredient_id INTEGER     NOT NULL REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    flag          VARCHAR(20) NOT NULL CHECK (flag IN
+                                              ('meat', 'gelatin', 'fish', 'shellfish', 'dairy', 'egg', 'honey',
+                                               'plant_based')),
+    PRIMARY KEY (ingredient_id, flag)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_diet_overrides
+(
+    ingredient_id INTEGER PRIMARY KEY REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    diet          VARCHAR(20) NOT NULL CHECK (diet IN ('vegan', 'vegetarian', 'pescatarian', 'omnivore')),
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+INSERT INTO recipe_catalogue.ingredient_dietary_flags (ingredient_id, flag)
+SELECT i.id, f.flag
+FROM (VALUES ('Eggs', 'egg'),
+             ('Parmesan Cheese', 'dairy')) AS f(ingredient, flag)
+JOIN recipe_catalogue.ingredients i ON i.name = f.ingredient
+ON CONFLICT (ingredient_id, flag) DO NOTHING;
+
+-- Parmigiano is made with calf rennet, so it is not vegetarian
+INSERT INTO recipe_catalogue.ingredient_diet_overrides (ingredient_id, diet)
+SELECT id, 'omnivore'
+FROM recipe_catalogue.ingredients
+WHERE name = 'Parmesan Cheese'
+ON CONFLICT (ingredient_id) DO NOTHING;
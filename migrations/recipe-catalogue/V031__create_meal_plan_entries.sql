@@ -0,0 +1,28 @@
+-- Plans say which day and meal each recipe is for. A meal can have several
+-- recipes, but the same recipe only once.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.meal_plan_entries
+(
+    id           SERIAL PRIMARY KEY,
+    meal_plan_id INTEGER     NOT NULL REFERENCES recipe_catalogue.meal_plans (id) ON DELETE CASCADE,
+    recipe_id    INTEGER     NOT NULL REFERENCES recipe_catalogue.recipes (id) ON DELETE CASCADE,
+    planned_on   DATE        NOT NULL,
+    slot         VARCHAR(20) NOT NULL CHECK (slot IN ('breakfast', 'lunch', 'dinner')),
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT meal_plan_entries_unique_slot_recipe UNIQUE (meal_plan_id, planned_on, slot, recipe_id)
+);
+
+CREATE INDEX idx_meal_plan_entries_plan ON recipe_catalogue.meal_plan_entries (meal_plan_id, planned_on);
+CREATE INDEX idx_meal_plan_entries_recipe ON recipe_catalogue.meal_plan_entries (recipe_id);
+
+-- meal_plan_recipes had no dates: its recipes go on the first day of their
+-- plan, mains as dinner
+INSERT INTO recipe_catalogue.meal_plan_entries (meal_plan_id, recipe_id, planned_on, slot, created_at)
+SELECT mpr.meal_plan_id, mpr.recipe_id, mp.period_start,
+       CASE mpr.meal_type WHEN 'breakfast' THEN 'breakfast' ELSE 'dinner' END,
+       mpr.created_at
+FROM recipe_catalogue.meal_plan_recipes mpr
+JOIN recipe_catalogue.meal_plans mp ON mp.id = mpr.meal_plan_id
+ON CONFLICT DO NOTHING;
+
+DROP TABLE recipe_catalogue.meal_plan_recipes;
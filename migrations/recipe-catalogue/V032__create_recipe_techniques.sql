@@ -0,0 +1,26 @@
+-- Cooking techniques a recipe practises. Recommendations compare them with
+-- what a user has already cooked to suggest recipes that teach one new
+-- technique at a time.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_techniques
+(
+    recipe_id INTEGER     NOT NULL REFERENCES recipe_catalogue.recipes (id) ON DELETE CASCADE,
+    technique VARCHAR(30) NOT NULL CHECK (technique IN
+                                          ('knife_work', 'sauteing', 'boiling', 'roasting', 'grilling',
+                                           'braising', 'poaching', 'steaming', 'deep_frying', 'emulsifying',
+                                           'baking', 'bread_making', 'pastry', 'fermenting')),
+    PRIMARY KEY (recipe_id, technique)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_techniques_technique ON recipe_catalogue.recipe_techniques (technique);
+
+INSERT INTO recipe_catalogue.recipe_techniques (recipe_id, technique)
+SELECT r.id, t.technique
+FROM (VALUES ('Pasta with Meatballs', 'boiling'),
+             ('Pasta with Meatballs', 'sauteing'),
+             ('Caesar Salad', 'knife_work'),
+             ('Caesar Salad', 'emulsifying'),
+             ('Grilled Chicken', 'grilling'),
+             ('Grilled Salmon', 'grilling'),
+             ('Banana Bread', 'baking')) AS t(recipe, technique)
+JOIN recipe_catalogue.recipes r ON r.name = t.recipe
+ON CONFLICT (recipe_id, technique) DO NOTHING;
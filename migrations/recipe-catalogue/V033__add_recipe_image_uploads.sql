@@ -0,0 +1,6 @@
+-- Uploaded photos are kept in image storage. storage_key locates the
+-- original there so deleting the image can remove the files too; images
+-- added by URL have neither a key nor a thumbnail.
+ALTER TABLE recipe_catalogue.recipe_images
+    ADD COLUMN IF NOT EXISTS thumbnail_url TEXT,
+    ADD COLUMN IF NOT EXISTS storage_key   VARCHAR(255);
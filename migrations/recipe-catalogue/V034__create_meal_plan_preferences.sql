@@ -0,0 +1,11 @@
+-- How a user likes generated meal plans: the longest a recipe may take on
+-- weekdays and at weekends (NULL for no limit), and whether weekends get
+-- the more elaborate recipes
+CREATE TABLE IF NOT EXISTS recipe_catalogue.meal_plan_preferences
+(
+    user_id             INTEGER PRIMARY KEY,
+    weekday_max_minutes INTEGER CHECK (weekday_max_minutes BETWEEN 1 AND 1440),
+    weekend_max_minutes INTEGER CHECK (weekend_max_minutes BETWEEN 1 AND 1440),
+    elaborate_weekends  BOOLEAN   DEFAULT FALSE NOT NULL,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
@@ -0,0 +1,12 @@
+-- Recipes users keep as favorites. Users live in the auth service, so only
+-- the recipe has a foreign key.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.user_favorites
+(
+    user_id    INTEGER                             NOT NULL,
+    recipe_id  INTEGER                             NOT NULL REFERENCES recipe_catalogue.recipes (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, recipe_id)
+);
+
+-- Favorite counts on recipe listings
+CREATE INDEX IF NOT EXISTS idx_user_favorites_recipe ON recipe_catalogue.user_favorites (recipe_id);
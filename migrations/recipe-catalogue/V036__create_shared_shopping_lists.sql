@@ -0,0 +1,28 @@
+-- Grocery lists shared by link. The items are a snapshot of the list when it
+-- was shared, so later recipe edits don't move things around while someone
+-- is in the shop. version goes up with every checked or unchecked item.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.shared_shopping_lists
+(
+    token      VARCHAR(36) PRIMARY KEY,
+    user_id    INTEGER                             NOT NULL,
+    version    INTEGER   DEFAULT 0                 NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_shared_shopping_lists_user ON recipe_catalogue.shared_shopping_lists (user_id);
+
+CREATE TABLE IF NOT EXISTS recipe_catalogue.shared_shopping_list_items
+(
+    token         VARCHAR(36)      NOT NULL REFERENCES recipe_catalogue.shared_shopping_lists (token) ON DELETE CASCADE,
+    position      INTEGER          NOT NULL,
+    ingredient_id INTEGER          NOT NULL,
+    name          VARCHAR(255)     NOT NULL,
+    category      VARCHAR(100),
+    quantity      DOUBLE PRECISION NOT NULL,
+    unit          VARCHAR(50)      NOT NULL,
+    note          TEXT,
+    checked       BOOLEAN   DEFAULT FALSE NOT NULL,
+    checked_at    TIMESTAMP,
+    PRIMARY KEY (token, position)
+);
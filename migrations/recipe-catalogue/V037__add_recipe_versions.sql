@@ -0,0 +1,5 @@
+-- version counts edits to a recipe. An update may name the version it was
+-- made against; it is refused when someone else has edited the recipe since,
+-- instead of silently overwriting their changes.
+ALTER TABLE recipe_catalogue.recipes
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
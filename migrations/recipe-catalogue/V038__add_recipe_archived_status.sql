@@ -0,0 +1,8 @@
+-- Archived recipes drop out of listings, search and recommendations like
+-- drafts, but stay readable by anyone at their own URL. Unlike deleting,
+-- archiving keeps forks, favorites and cooking history pointing at them.
+ALTER TABLE recipe_catalogue.recipes
+    DROP CONSTRAINT IF EXISTS chk_recipes_status;
+
+ALTER TABLE recipe_catalogue.recipes
+    ADD CONSTRAINT chk_recipes_status CHECK (status IN ('draft', 'private', 'published', 'archived'));
@@ -0,0 +1,57 @@
+-- Allergen flags mark ingredients people avoid for reasons other than diet.
+-- They leave the diet to the other flags or the category.
+ALTER TABLE recipe_catalogue.ingredient_dietary_flags
+    DROP CONSTRAINT IF EXISTS ingredient_dietary_flags_flag_check;
+
+ALTER TABLE recipe_catalogue.ingredient_dietary_flags
+    ADD CONSTRAINT ingredient_dietary_flags_flag_check CHECK (flag IN
+                                                              ('meat', 'gelatin', 'fish', 'shellfish', 'dairy', 'egg',
+                                                               'honey', 'plant_based', 'gluten', 'peanut', 'tree_nut',
+                                                               'soy', 'sesame'));
+
+-- Each ingredient's diet and flags as the classification rules in the
+-- recipe catalogue's dietary.go decide them, for queries that filter
+-- recipes in SQL, like recommendations. diet_rank orders the diets from
+-- vegan (0) to omnivore (3). An ingredient without flags of its own is
+-- taken to carry its category's, as the rules do.
+CREATE OR REPLACE VIEW recipe_catalogue.ingredient_diets AS
+WITH ingredient_flags AS (
+    SELECT i.id AS ingredient_id,
+           i.category,
+           o.diet AS override,
+           ARRAY_REMOVE(ARRAY_AGG(f.flag::text), NULL) AS flags
+    FROM recipe_catalogue.ingredients i
+    LEFT JOIN recipe_catalogue.ingredient_diet_overrides o ON o.ingredient_id = i.id
+    LEFT JOIN recipe_catalogue.ingredient_dietary_flags f ON f.ingredient_id = i.id
+    GROUP BY i.id, i.category, o.diet
+),
+classified AS (
+    SELECT ingredient_id,
+           flags,
+           category,
+           flags && ARRAY['meat', 'gelatin', 'fish', 'shellfish', 'dairy', 'egg', 'honey', 'plant_based']::text[] AS has_diet_flag,
+           CASE
+               WHEN override IS NOT NULL THEN override
+               WHEN flags && ARRAY['meat', 'gelatin']::text[] THEN 'omnivore'
+               WHEN flags && ARRAY['fish', 'shellfish']::text[] THEN 'pescatarian'
+               WHEN flags && ARRAY['dairy', 'egg', 'honey']::text[] THEN 'vegetarian'
+               WHEN flags && ARRAY['plant_based']::text[] THEN 'vegan'
+               WHEN category = 'Meat' THEN 'omnivore'
+               WHEN category = 'Fish' THEN 'pescatarian'
+               WHEN category = 'Dairy' THEN 'vegetarian'
+               WHEN category IN ('Vegetables', 'Fruits', 'Spices', 'Grains', 'Oils') THEN 'vegan'
+               ELSE 'omnivore'
+           END AS diet
+    FROM ingredient_flags
+)
+SELECT ingredient_id,
+       diet,
+       ARRAY_POSITION(ARRAY['vegan', 'vegetarian', 'pescatarian', 'omnivore']::text[], diet::text) - 1 AS diet_rank,
+       CASE
+           WHEN has_diet_flag THEN flags
+           WHEN category = 'Meat' THEN flags || 'meat'::text
+           WHEN category = 'Fish' THEN flags || 'fish'::text
+           WHEN category = 'Dairy' THEN flags || 'dairy'::text
+           ELSE flags
+       END AS flags
+FROM classified;
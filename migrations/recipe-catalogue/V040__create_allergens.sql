@@ -0,0 +1,105 @@
+-- Allergens ingredients can be tagged with, so recipe pages can warn people
+-- who avoid them. They replace the allergen dietary flags, which said
+-- nothing about diet.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.allergens
+(
+    id   SERIAL PRIMARY KEY,
+    code VARCHAR(30)  NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_allergens
+(
+    ingredient_id INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    allergen_id   INTEGER NOT NULL REFERENCES recipe_catalogue.allergens (id) ON DELETE CASCADE,
+    PRIMARY KEY (ingredient_id, allergen_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ingredient_allergens_allergen ON recipe_catalogue.ingredient_allergens (allergen_id);
+
+-- The major food allergens most labelling rules name
+INSERT INTO recipe_catalogue.allergens (code, name)
+VALUES ('gluten', 'Gluten'),
+       ('dairy', 'Milk'),
+       ('egg', 'Eggs'),
+       ('fish', 'Fish'),
+       ('shellfish', 'Crustacean shellfish'),
+       ('mollusc', 'Molluscs'),
+       ('peanut', 'Peanuts'),
+       ('tree_nut', 'Tree nuts'),
+       ('soy', 'Soy'),
+       ('sesame', 'Sesame'),
+       ('mustard', 'Mustard'),
+       ('celery', 'Celery'),
+       ('lupin', 'Lupin'),
+       ('sulphites', 'Sulphites')
+ON CONFLICT (code) DO NOTHING;
+
+-- Dietary flags naming an allergen become allergen tags; the allergen-only
+-- flags are then dropped
+INSERT INTO recipe_catalogue.ingredient_allergens (ingredient_id, allergen_id)
+SELECT f.ingredient_id, a.id
+FROM recipe_catalogue.ingredient_dietary_flags f
+JOIN recipe_catalogue.allergens a ON a.code = f.flag
+ON CONFLICT (ingredient_id, allergen_id) DO NOTHING;
+
+DELETE FROM recipe_catalogue.ingredient_dietary_flags
+WHERE flag IN ('gluten', 'peanut', 'tree_nut', 'soy', 'sesame');
+
+ALTER TABLE recipe_catalogue.ingredient_dietary_flags
+    DROP CONSTRAINT IF EXISTS ingredient_dietary_flags_flag_check;
+
+ALTER TABLE recipe_catalogue.ingredient_dietary_flags
+    ADD CONSTRAINT ingredient_dietary_flags_flag_check CHECK (flag IN
+                                                              ('meat', 'gelatin', 'fish', 'shellfish', 'dairy', 'egg',
+                                                               'honey', 'plant_based'));
+
+-- flags now carries the ingredient's allergen codes alongside its dietary
+-- flags, so filters on either keep working
+CREATE OR REPLACE VIEW recipe_catalogue.ingredient_diets AS
+WITH ingredient_flags AS (
+    SELECT i.id AS ingredient_id,
+           i.category,
+           o.diet AS override,
+           ARRAY_REMOVE(ARRAY_AGG(f.flag::text), NULL) AS flags
+    FROM recipe_catalogue.ingredients i
+    LEFT JOIN recipe_catalogue.ingredient_diet_overrides o ON o.ingredient_id = i.id
+    LEFT JOIN recipe_catalogue.ingredient_dietary_flags f ON f.ingredient_id = i.id
+    GROUP BY i.id, i.category, o.diet
+),
+ingredient_allergen_codes AS (
+    SELECT ia.ingredient_id, ARRAY_AGG(a.code::text) AS codes
+    FROM recipe_catalogue.ingredient_allergens ia
+    JOIN recipe_catalogue.allergens a ON a.id = ia.allergen_id
+    GROUP BY ia.ingredient_id
+),
+classified AS (
+    SELECT ingredient_id,
+           flags,
+           category,
+           CASE
+               WHEN override IS NOT NULL THEN override
+               WHEN flags && ARRAY['meat', 'gelatin']::text[] THEN 'omnivore'
+               WHEN flags && ARRAY['fish', 'shellfish']::text[] THEN 'pescatarian'
+               WHEN flags && ARRAY['dairy', 'egg', 'honey']::text[] THEN 'vegetarian'
+               WHEN flags && ARRAY['plant_based']::text[] THEN 'vegan'
+               WHEN category = 'Meat' THEN 'omnivore'
+               WHEN category = 'Fish' THEN 'pescatarian'
+               WHEN category = 'Dairy' THEN 'vegetarian'
+               WHEN category IN ('Vegetables', 'Fruits', 'Spices', 'Grains', 'Oils') THEN 'vegan'
+               ELSE 'omnivore'
+           END AS diet
+    FROM ingredient_flags
+)
+SELECT c.ingredient_id,
+       c.diet,
+       ARRAY_POSITION(ARRAY['vegan', 'vegetarian', 'pescatarian', 'omnivore']::text[], c.diet::text) - 1 AS diet_rank,
+       CASE
+           WHEN CARDINALITY(c.flags) > 0 THEN c.flags
+           WHEN c.category = 'Meat' THEN ARRAY['meat']::text[]
+           WHEN c.category = 'Fish' THEN ARRAY['fish']::text[]
+           WHEN c.category = 'Dairy' THEN ARRAY['dairy']::text[]
+           ELSE c.flags
+       END || COALESCE(iac.codes, ARRAY[]::text[]) AS flags
+FROM classified c
+LEFT JOIN ingredient_allergen_codes iac ON iac.ingredient_id = c.ingredient_id;
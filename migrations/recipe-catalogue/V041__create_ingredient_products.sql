@@ -0,0 +1,26 @@
+-- Branded products of a generic ingredient (e.g. one brand's spaghetti under
+-- Pasta) with the nutrition per 100 g from their label. A recipe ingredient
+-- can name the product it uses, for users tracking macros precisely.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_products
+(
+    id            SERIAL PRIMARY KEY,
+    ingredient_id INTEGER                             NOT NULL REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    brand         VARCHAR(100)                        NOT NULL,
+    name          VARCHAR(100)                        NOT NULL,
+    calories_kcal NUMERIC(6, 1)                       NOT NULL,
+    protein_g     NUMERIC(5, 1)                       NOT NULL,
+    carbs_g       NUMERIC(5, 1)                       NOT NULL,
+    fat_g         NUMERIC(5, 1)                       NOT NULL,
+    fiber_g       NUMERIC(5, 1)                       NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT ingredient_products_nutrition_not_negative
+        CHECK (calories_kcal >= 0 AND protein_g >= 0 AND carbs_g >= 0 AND fat_g >= 0 AND fiber_g >= 0),
+    CONSTRAINT ingredient_products_unique UNIQUE (ingredient_id, brand, name)
+);
+
+-- Deleting a product leaves the recipes using it on the generic ingredient
+ALTER TABLE recipe_catalogue.recipe_ingredients
+    ADD COLUMN IF NOT EXISTS product_id INTEGER REFERENCES recipe_catalogue.ingredient_products (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_product_id
+    ON recipe_catalogue.recipe_ingredients (product_id) WHERE product_id IS NOT NULL;
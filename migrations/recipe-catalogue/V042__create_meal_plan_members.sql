@@ -0,0 +1,19 @@
+-- The people of a household cooking from a plan, in the order they take
+-- turns. Each entry says who cooks it; removing a member leaves their meals
+-- unassigned.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.meal_plan_members
+(
+    id           SERIAL PRIMARY KEY,
+    meal_plan_id INTEGER     NOT NULL REFERENCES recipe_catalogue.meal_plans (id) ON DELETE CASCADE,
+    position     INTEGER     NOT NULL,
+    name         VARCHAR(50) NOT NULL,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT meal_plan_members_unique_position UNIQUE (meal_plan_id, position)
+);
+
+ALTER TABLE recipe_catalogue.meal_plan_entries
+    ADD COLUMN IF NOT EXISTS assignee_id INTEGER REFERENCES recipe_catalogue.meal_plan_members (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_meal_plan_entries_assignee
+    ON recipe_catalogue.meal_plan_entries (assignee_id, planned_on) WHERE assignee_id IS NOT NULL;
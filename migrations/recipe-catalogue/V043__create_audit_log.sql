@@ -0,0 +1,21 @@
+-- Every change made through the catalogue API: who made it, to which entity,
+-- and the entity's JSON before and after. Written by the shared audit
+-- middleware and read by admins at GET /audit.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.audit_log
+(
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      INTEGER,
+    entity       VARCHAR(100) NOT NULL,
+    entity_id    VARCHAR(100),
+    action       VARCHAR(10)  NOT NULL,
+    path         TEXT         NOT NULL,
+    before_state JSONB,
+    after_state  JSONB,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT audit_log_action_valid CHECK (action IN ('create', 'update', 'delete'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON recipe_catalogue.audit_log (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON recipe_catalogue.audit_log (entity, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_user ON recipe_catalogue.audit_log (user_id, created_at DESC);
@@ -0,0 +1,12 @@
+-- Recipes admins have picked as starting points, such as a basic stir fry,
+-- that users copy into recipes of their own. A template is its recipe: it is
+-- edited like any other and goes away with it.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_templates
+(
+    id          SERIAL PRIMARY KEY,
+    recipe_id   INTEGER                             NOT NULL REFERENCES recipe_catalogue.recipes (id) ON DELETE CASCADE,
+    description TEXT,
+    created_by  INTEGER                             NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT recipe_templates_recipe_id_key UNIQUE (recipe_id)
+);
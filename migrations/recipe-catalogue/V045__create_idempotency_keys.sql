@@ -0,0 +1,17 @@
+-- The Idempotency-Key sent with a create and the response to its first
+-- request, replayed on retries by the shared idempotency middleware. Rows
+-- older than a day are deleted as new keys come in.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.idempotency_keys
+(
+    user_id         INTEGER      NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash    CHAR(64)     NOT NULL,
+    status_code     INTEGER,
+    content_type    VARCHAR(100),
+    response_body   BYTEA,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_id, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON recipe_catalogue.idempotency_keys (created_at);
@@ -0,0 +1,4 @@
+-- Language the user reads the API in, e.g. de or pt-br. NULL leaves it to
+-- the request's Accept-Language header.
+ALTER TABLE recipe_catalogue.user_profiles
+    ADD COLUMN IF NOT EXISTS locale VARCHAR(20);
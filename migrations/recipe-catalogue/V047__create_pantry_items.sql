@@ -0,0 +1,9 @@
+-- Ingredients users have at home, for finding recipes they can cook. Users
+-- live in the auth service, so only the ingredient has a foreign key.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.pantry_items
+(
+    user_id       INTEGER                             NOT NULL,
+    ingredient_id INTEGER                             NOT NULL REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    added_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, ingredient_id)
+);
@@ -0,0 +1,15 @@
+-- Other names an ingredient goes by in English, e.g. 'Scallion' for Green
+-- Onion. Imported from food taxonomies; recipe imports match them like
+-- translations.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_aliases
+(
+    ingredient_id INTEGER                             NOT NULL REFERENCES recipe_catalogue.ingredients (id) ON DELETE CASCADE,
+    name          VARCHAR(100)                        NOT NULL,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (ingredient_id, name),
+    CONSTRAINT ingredient_aliases_name_not_empty CHECK (length(trim(name)) > 0)
+);
+
+-- Name matching narrows candidates with the trigram operator
+CREATE INDEX IF NOT EXISTS idx_ingredient_aliases_name_trgm
+    ON recipe_catalogue.ingredient_aliases USING GIN (name gin_trgm_ops);
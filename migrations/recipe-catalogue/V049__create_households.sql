@@ -0,0 +1,57 @@
+-- Households share a pantry. Whoever creates one owns it and hands out its
+-- invite code; a user belongs to at most one household. Users live in the
+-- auth service, so only the household side has foreign keys.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.households
+(
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(100)                        NOT NULL,
+    owner_id    INTEGER                             NOT NULL,
+    invite_code VARCHAR(36)                         NOT NULL UNIQUE,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS recipe_catalogue.household_members
+(
+    household_id INTEGER                             NOT NULL REFERENCES recipe_catalogue.households (id) ON DELETE CASCADE,
+    user_id      INTEGER                             NOT NULL,
+    joined_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (household_id, user_id),
+
+    CONSTRAINT household_members_one_household UNIQUE (user_id)
+);
+
+-- A pantry belongs to either a user or a household. Its version goes up with
+-- every change, so members editing it at once can't overwrite each other.
+CREATE TABLE IF NOT EXISTS recipe_catalogue.pantries
+(
+    id           SERIAL PRIMARY KEY,
+    user_id      INTEGER UNIQUE,
+    household_id INTEGER UNIQUE REFERENCES recipe_catalogue.households (id) ON DELETE CASCADE,
+    version      INTEGER   DEFAULT 0                 NOT NULL,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT pantries_one_owner CHECK ((user_id IS NULL) <> (household_id IS NULL))
+);
+
+-- Pantry items move from their user onto that user's pantry, and remember
+-- who added them.
+INSERT INTO recipe_catalogue.pantries (user_id)
+SELECT DISTINCT user_id
+FROM recipe_catalogue.pantry_items;
+
+ALTER TABLE recipe_catalogue.pantry_items
+    ADD COLUMN IF NOT EXISTS pantry_id INTEGER REFERENCES recipe_catalogue.pantries (id) ON DELETE CASCADE;
+
+UPDATE recipe_catalogue.pantry_items pi
+SET pantry_id = p.id
+FROM recipe_catalogue.pantries p
+WHERE p.user_id = pi.user_id;
+
+ALTER TABLE recipe_catalogue.pantry_items
+    DROP CONSTRAINT IF EXISTS pantry_items_pkey;
+ALTER TABLE recipe_catalogue.pantry_items
+    RENAME COLUMN user_id TO added_by;
+ALTER TABLE recipe_catalogue.pantry_items
+    ALTER COLUMN pantry_id SET NOT NULL;
+ALTER TABLE recipe_catalogue.pantry_items
+    ADD PRIMARY KEY (pantry_id, ingredient_id);
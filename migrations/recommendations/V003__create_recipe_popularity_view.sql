@@ -0,0 +1,17 @@
+-- Per-recipe cooking stats across all users, refreshed in the background so
+-- the popular recipes endpoint does not aggregate cooking_history per request.
+CREATE MATERIALIZED VIEW IF NOT EXISTS recommendations.recipe_popularity AS
+SELECT recipe_id,
+       COUNT(*) AS times_cooked,
+       COUNT(DISTINCT user_id) AS unique_cooks,
+       COUNT(*) FILTER (WHERE cooked_at > CURRENT_TIMESTAMP - INTERVAL '30 days') AS cooked_last_30_days,
+       AVG(rating)::numeric(3, 2) AS average_rating,
+       MAX(cooked_at) AS last_cooked_at
+FROM recommendations.cooking_history
+GROUP BY recipe_id;
+
+-- REFRESH ... CONCURRENTLY requires a unique index
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_popularity_recipe_id
+    ON recommendations.recipe_popularity(recipe_id);
+CREATE INDEX IF NOT EXISTS idx_recipe_popularity_ranking
+    ON recommendations.recipe_popularity(cooked_last_30_days DESC, times_cooked DESC);
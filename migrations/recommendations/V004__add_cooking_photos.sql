@@ -0,0 +1,16 @@
+-- Photos attached to cooking-log entries. A photo shared with the community
+-- waits in 'pending' until a moderator approves or rejects it; private photos
+-- have no status.
+ALTER TABLE recommendations.cooking_history
+    ADD COLUMN IF NOT EXISTS photo_url TEXT,
+    ADD COLUMN IF NOT EXISTS photo_caption VARCHAR(200),
+    ADD COLUMN IF NOT EXISTS photo_shared BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS photo_status VARCHAR(20) CHECK (photo_status IN ('pending', 'approved', 'rejected'));
+
+CREATE INDEX IF NOT EXISTS idx_cooking_history_approved_photos
+    ON recommendations.cooking_history(recipe_id, cooked_at DESC)
+    WHERE photo_status = 'approved';
+
+CREATE INDEX IF NOT EXISTS idx_cooking_history_pending_photos
+    ON recommendations.cooking_history(cooked_at)
+    WHERE photo_status = 'pending';
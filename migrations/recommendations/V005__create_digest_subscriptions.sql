@@ -0,0 +1,12 @@
+-- Users who opted in to the weekly summary, with the locale it is written in.
+-- last_sent_at is NULL until the first digest goes out.
+CREATE TABLE IF NOT EXISTS recommendations.digest_subscriptions
+(
+    user_id       INTEGER PRIMARY KEY,
+    locale        VARCHAR(10) DEFAULT 'en'              NOT NULL,
+    subscribed_at TIMESTAMP   DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    last_sent_at  TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_digest_subscriptions_last_sent
+    ON recommendations.digest_subscriptions (last_sent_at);
@@ -0,0 +1,16 @@
+-- Food a user threw away. Frequently wasted ingredients nudge the hybrid
+-- recommendations towards recipes that use them up.
+CREATE TABLE IF NOT EXISTS recommendations.waste_log
+(
+    id            SERIAL PRIMARY KEY,
+    user_id       INTEGER                             NOT NULL,
+    ingredient_id INTEGER                             NOT NULL,
+    quantity      DECIMAL(10, 2)                      NOT NULL CHECK (quantity > 0),
+    unit          VARCHAR(20)                         NOT NULL,
+    reason        VARCHAR(20)                         NOT NULL
+        CHECK (reason IN ('expired', 'spoiled', 'leftover', 'overbought', 'other')),
+    wasted_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_waste_log_user_wasted_at
+    ON recommendations.waste_log (user_id, wasted_at DESC);
@@ -0,0 +1,16 @@
+-- Prepped portions a user has put in the freezer. They come back as
+-- zero-cook options alongside recommendations until they are eaten.
+CREATE TABLE IF NOT EXISTS recommendations.freezer_items
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER                             NOT NULL,
+    recipe_id  INTEGER                             NOT NULL,
+    portions   INTEGER                             NOT NULL CHECK (portions > 0),
+    frozen_on  DATE                                NOT NULL,
+    eat_before DATE                                NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CHECK (eat_before > frozen_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_freezer_items_user_eat_before
+    ON recommendations.freezer_items (user_id, eat_before);
@@ -0,0 +1,15 @@
+-- How often each recipe was cooked in each calendar month, across all users
+-- and years, so public recommendations can favour what people cook this time
+-- of year. Refreshed in the background with recipe_popularity.
+CREATE MATERIALIZED VIEW IF NOT EXISTS recommendations.recipe_seasonality AS
+SELECT recipe_id,
+       EXTRACT(MONTH FROM cooked_at)::int AS month,
+       COUNT(*) AS times_cooked
+FROM recommendations.cooking_history
+GROUP BY recipe_id, EXTRACT(MONTH FROM cooked_at)::int;
+
+-- REFRESH ... CONCURRENTLY requires a unique index
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_seasonality_recipe_month
+    ON recommendations.recipe_seasonality(recipe_id, month);
+CREATE INDEX IF NOT EXISTS idx_recipe_seasonality_month
+    ON recommendations.recipe_seasonality(month);
@@ -0,0 +1,4 @@
+-- version counts edits to a user's preferences, so an update made from a
+-- stale copy (e.g. in another tab) is refused instead of overwriting.
+ALTER TABLE recommendations.user_preferences
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+-- Dietary restrictions keep recipes a user can't eat out of their
+-- recommendations: diet is the most restrictive diet every ingredient must
+-- suit, allergens the dietary flags no ingredient may carry.
+ALTER TABLE recommendations.user_preferences
+    ADD COLUMN IF NOT EXISTS diet VARCHAR(20) CHECK (diet IN ('vegan', 'vegetarian', 'pescatarian', 'omnivore')),
+    ADD COLUMN IF NOT EXISTS allergens TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,25 @@
+-- Every list of personalized recommendations served is kept as a slate, so
+-- what users saw and clicked can be put down to the algorithm that picked it.
+CREATE TABLE IF NOT EXISTS recommendations.recommendation_slates
+(
+    id        SERIAL PRIMARY KEY,
+    user_id   INTEGER                             NOT NULL,
+    algorithm VARCHAR(50)                         NOT NULL,
+    served_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS recommendations.recommendation_slate_items
+(
+    slate_id     INTEGER          NOT NULL REFERENCES recommendations.recommendation_slates (id) ON DELETE CASCADE,
+    recipe_id    INTEGER          NOT NULL,
+    position     INTEGER          NOT NULL,
+    score        DOUBLE PRECISION NOT NULL,
+    impressed_at TIMESTAMP,
+    clicked_at   TIMESTAMP,
+    PRIMARY KEY (slate_id, recipe_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recommendation_slates_user_served_at
+    ON recommendations.recommendation_slates (user_id, served_at);
+CREATE INDEX IF NOT EXISTS idx_recommendation_slates_served_at
+    ON recommendations.recommendation_slates (served_at);
@@ -0,0 +1,14 @@
+-- Item-based collaborative filtering: for each recipe, the recipes most often
+-- cooked by the same people. Rebuilt in the background from cooking_history
+-- across all users; score is the cosine similarity of the two recipes' sets
+-- of cooks and co_cooks how many people cooked both.
+CREATE TABLE IF NOT EXISTS recommendations.recipe_similarities
+(
+    recipe_id         INTEGER                             NOT NULL,
+    similar_recipe_id INTEGER                             NOT NULL,
+    score             DOUBLE PRECISION                    NOT NULL,
+    co_cooks          INTEGER                             NOT NULL,
+    computed_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (recipe_id, similar_recipe_id),
+    CONSTRAINT recipe_similarities_not_self CHECK (recipe_id <> similar_recipe_id)
+);
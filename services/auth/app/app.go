@@ -0,0 +1,76 @@
+// Package app wires the auth service's repositories, services and routes
+// into one handler, which services/auth serves on its own port and
+// cmd/allinone mounts beside the other services.
+package app
+
+import (
+	"net/http"
+
+	"meal-prep/services/auth/handlers"
+	"meal-prep/services/auth/repository"
+	"meal-prep/services/auth/repository/memory"
+	"meal-prep/services/auth/service"
+	"meal-prep/shared/database"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/metrics"
+	"meal-prep/shared/middleware"
+
+	"github.com/gorilla/mux"
+)
+
+// New returns the auth service's routes on db, or on in-memory repositories
+// when db is nil.
+func New(db *database.DB) http.Handler {
+	var (
+		userRepo    repository.UserRepository
+		refreshRepo repository.RefreshTokenRepository
+	)
+
+	if db == nil {
+		logging.Logger.Warn("Using in-memory storage, data will be lost on restart")
+		userRepo = memory.NewUserRepository()
+		refreshRepo = memory.NewRefreshTokenRepository()
+	} else {
+		userRepo = repository.NewUserRepository(db)
+		refreshRepo = repository.NewRefreshTokenRepository(db)
+	}
+
+	// Dependency injection chain
+	authService := service.NewAuthService(userRepo, refreshRepo)
+	authHandler := handlers.NewAuthHandler(authService)
+
+	// Request and connection pool metrics for Prometheus to scrape
+	serviceMetrics := metrics.New("auth-service")
+	if db != nil {
+		serviceMetrics.RegisterDB(db, "auth")
+	}
+
+	// Routes with tracing, logging and metrics middleware
+	router := mux.NewRouter()
+
+	router.Use(middleware.TracingMiddleware("auth-service"))
+	router.Use(middleware.LoggingMiddleware("auth-service"))
+	router.Use(serviceMetrics.Middleware)
+
+	router.HandleFunc("/register", authHandler.Register).Methods("POST")
+	router.HandleFunc("/login", authHandler.Login).Methods("POST")
+	router.HandleFunc("/refresh", authHandler.Refresh).Methods("POST")
+	router.HandleFunc("/logout", authHandler.Logout).Methods("POST")
+	router.Handle("/auth/me", middleware.ExtractUserFromGatewayHeaders(
+		http.HandlerFunc(authHandler.Me),
+	)).Methods("GET")
+	router.HandleFunc("/health", healthCheck).Methods("GET")
+	router.Handle("/metrics", serviceMetrics.Handler()).Methods("GET")
+	if db != nil {
+		router.Handle("/debug/queries", db.SlowQueriesHandler(10)).Methods("GET")
+	}
+	return router
+}
+
+func healthCheck(w http.ResponseWriter, r *http.Request) {
+	//logging.WithContext(r.Context()).Debug("Health check requested")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status": "healthy", "service": "auth"}`))
+}
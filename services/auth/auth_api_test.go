@@ -38,7 +38,7 @@ func (suite *AuthE2ETestSuite) SetupSuite() {
 
 	// Create real services with real database
 	userRepo := repository.NewUserRepository(suite.testDB.DB)
-	authService := service.NewAuthService(userRepo)
+	authService := service.NewAuthService(userRepo, repository.NewRefreshTokenRepository(suite.testDB.DB))
 	authHandler := handlers.NewAuthHandler(authService)
 
 	// Setup real HTTP server
@@ -46,6 +46,8 @@ func (suite *AuthE2ETestSuite) SetupSuite() {
 	router.Use(middleware.LoggingMiddleware("test-auth-service"))
 	router.HandleFunc("/register", authHandler.Register).Methods("POST")
 	router.HandleFunc("/login", authHandler.Login).Methods("POST")
+	router.HandleFunc("/refresh", authHandler.Refresh).Methods("POST")
+	router.HandleFunc("/logout", authHandler.Logout).Methods("POST")
 	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(`{"status": "healthy"}`))
@@ -32,13 +32,14 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	req.Region = strings.ToLower(strings.TrimSpace(req.Region))
 
-	response, err := h.authService.Register(req.Email, req.Password)
+	response, err := h.authService.Register(req.Email, req.Password, req.Region)
 	if err != nil {
 		switch err {
 		case service.ErrUserExists:
 			models.WriteErrorResponse(w, err.Error(), http.StatusConflict)
-		case service.ErrWeakPassword:
+		case service.ErrWeakPassword, service.ErrUnknownRegion:
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		default:
 			models.WriteErrorResponse(w, "Internal server error", http.StatusInternalServerError)
@@ -93,3 +94,54 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	models.WriteSuccessResponse(w, response, http.StatusOK)
 }
 
+
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.RefreshToken == "" {
+		models.WriteErrorResponse(w, "Refresh token is required", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidRefreshToken:
+			models.WriteErrorResponse(w, err.Error(), http.StatusUnauthorized)
+		default:
+			models.WriteErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, response, http.StatusOK)
+}
+
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req models.LogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.RefreshToken == "" {
+		models.WriteErrorResponse(w, "Refresh token is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authService.Logout(req.RefreshToken, req.All); err != nil {
+		switch err {
+		case service.ErrInvalidRefreshToken:
+			models.WriteErrorResponse(w, err.Error(), http.StatusUnauthorized)
+		default:
+			models.WriteErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Logged out successfully"}, http.StatusNoContent)
+}
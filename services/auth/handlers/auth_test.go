@@ -37,7 +37,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			contentType: "application/json",
 			description: "Valid registration request should return 201 with token",
 			setupMocks: func(mockService *mocks.MockAuthService) {
-				mockService.On("Register", "test@example.com", "password123").Return(
+				mockService.On("Register", "test@example.com", "password123", "").Return(
 					&models.AuthResponse{
 						Token: "jwt_token_here",
 						User: models.User{
@@ -110,7 +110,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			contentType: "application/json",
 			description: "Duplicate user should return 409 Conflict",
 			setupMocks: func(mockService *mocks.MockAuthService) {
-				mockService.On("Register", "existing@example.com", "password123").Return(
+				mockService.On("Register", "existing@example.com", "password123", "").Return(
 					(*models.AuthResponse)(nil), service.ErrUserExists)
 			},
 			expectedStatus: http.StatusConflict,
@@ -126,7 +126,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			contentType: "application/json",
 			description: "Weak password should return 400 Bad Request",
 			setupMocks: func(mockService *mocks.MockAuthService) {
-				mockService.On("Register", "test@example.com", "123").Return(
+				mockService.On("Register", "test@example.com", "123", "").Return(
 					(*models.AuthResponse)(nil), service.ErrWeakPassword)
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -136,13 +136,29 @@ func TestAuthHandler_Register(t *testing.T) {
 				"message": service.ErrWeakPassword.Error(),
 			},
 		},
+		{
+			name:        "unknown_region",
+			requestBody: `{"email":"test@example.com","password":"password123","region":" EU "}`,
+			contentType: "application/json",
+			description: "Region is normalised and an unknown one returns 400 Bad Request",
+			setupMocks: func(mockService *mocks.MockAuthService) {
+				mockService.On("Register", "test@example.com", "password123", "eu").Return(
+					(*models.AuthResponse)(nil), service.ErrUnknownRegion)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: map[string]interface{}{
+				"error":   "error",
+				"code":    float64(400),
+				"message": service.ErrUnknownRegion.Error(),
+			},
+		},
 		{
 			name:        "internal_server_error",
 			requestBody: `{"email":"test@example.com","password":"password123"}`,
 			contentType: "application/json",
 			description: "Unexpected service errors should return 500",
 			setupMocks: func(mockService *mocks.MockAuthService) {
-				mockService.On("Register", "test@example.com", "password123").Return(
+				mockService.On("Register", "test@example.com", "password123", "").Return(
 					(*models.AuthResponse)(nil), errors.New("unexpected database error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -158,7 +174,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			contentType: "", // No content type
 			description: "Missing content type should still work (graceful handling)",
 			setupMocks: func(mockService *mocks.MockAuthService) {
-				mockService.On("Register", "test@example.com", "password123").Return(
+				mockService.On("Register", "test@example.com", "password123", "").Return(
 					&models.AuthResponse{
 						Token: "jwt_token",
 						User:  models.User{ID: 1, Email: "test@example.com"},
@@ -316,6 +332,136 @@ func TestAuthHandler_Login(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_Refresh(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMocks     func(*mocks.MockAuthService)
+		expectedStatus int
+		expectToken    bool
+	}{
+		{
+			name:        "successful_refresh",
+			requestBody: `{"refresh_token":"old_refresh"}`,
+			setupMocks: func(mockService *mocks.MockAuthService) {
+				mockService.On("Refresh", "old_refresh").Return(
+					&models.AuthResponse{
+						Token:        "new_jwt_token",
+						ExpiresIn:    900,
+						RefreshToken: "new_refresh",
+						User:         models.User{ID: 1, Email: "user@example.com"},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectToken:    true,
+		},
+		{
+			name:        "invalid_refresh_token",
+			requestBody: `{"refresh_token":"revoked_refresh"}`,
+			setupMocks: func(mockService *mocks.MockAuthService) {
+				mockService.On("Refresh", "revoked_refresh").Return(
+					(*models.AuthResponse)(nil), service.ErrInvalidRefreshToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing_refresh_token",
+			requestBody:    `{}`,
+			setupMocks:     func(mockService *mocks.MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(mocks.MockAuthService)
+			tt.setupMocks(mockService)
+			handler := NewAuthHandler(mockService)
+
+			req := httptest.NewRequest("POST", "/refresh", bytes.NewBufferString(tt.requestBody))
+			recorder := httptest.NewRecorder()
+
+			// Act
+			handler.Refresh(recorder, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+			if tt.expectToken {
+				assert.Equal(t, "new_jwt_token", response["token"])
+				assert.Equal(t, "new_refresh", response["refresh_token"])
+				assert.Equal(t, float64(900), response["expires_in"])
+			} else {
+				assert.Contains(t, response, "error")
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMocks     func(*mocks.MockAuthService)
+		expectedStatus int
+	}{
+		{
+			name:        "logout_this_device",
+			requestBody: `{"refresh_token":"refresh"}`,
+			setupMocks: func(mockService *mocks.MockAuthService) {
+				mockService.On("Logout", "refresh", false).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:        "logout_everywhere",
+			requestBody: `{"refresh_token":"refresh","all":true}`,
+			setupMocks: func(mockService *mocks.MockAuthService) {
+				mockService.On("Logout", "refresh", true).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:        "unknown_refresh_token",
+			requestBody: `{"refresh_token":"unknown"}`,
+			setupMocks: func(mockService *mocks.MockAuthService) {
+				mockService.On("Logout", "unknown", false).Return(service.ErrInvalidRefreshToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing_refresh_token",
+			requestBody:    `{"all":true}`,
+			setupMocks:     func(mockService *mocks.MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(mocks.MockAuthService)
+			tt.setupMocks(mockService)
+			handler := NewAuthHandler(mockService)
+
+			req := httptest.NewRequest("POST", "/logout", bytes.NewBufferString(tt.requestBody))
+			recorder := httptest.NewRecorder()
+
+			// Act
+			handler.Logout(recorder, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestAuthHandler_Me(t *testing.T) {
 	now := time.Now()
 
@@ -448,7 +594,7 @@ func TestAuthHandler_MethodNotAllowed(t *testing.T) {
 // Benchmark handler performance
 func BenchmarkAuthHandler_Register(b *testing.B) {
 	mockService := new(mocks.MockAuthService)
-	mockService.On("Register", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(
+	mockService.On("Register", mock.AnythingOfType("string"), mock.AnythingOfType("string"), "").Return(
 		&models.AuthResponse{
 			Token: "benchmark_token",
 			User:  models.User{ID: 1, Email: "bench@example.com"},
@@ -10,8 +10,8 @@ type MockAuthService struct {
 	mock.Mock
 }
 
-func (m *MockAuthService) Register(email, password string) (*models.AuthResponse, error) {
-	args := m.Called(email, password)
+func (m *MockAuthService) Register(email, password, region string) (*models.AuthResponse, error) {
+	args := m.Called(email, password, region)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -33,3 +33,16 @@ func (m *MockAuthService) Me(userID int) (*models.User, error) {
 	}
 	return args.Get(0).(*models.User), args.Error(1)
 }
+
+func (m *MockAuthService) Refresh(refreshToken string) (*models.AuthResponse, error) {
+	args := m.Called(refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthResponse), args.Error(1)
+}
+
+func (m *MockAuthService) Logout(refreshToken string, all bool) error {
+	args := m.Called(refreshToken, all)
+	return args.Error(0)
+}
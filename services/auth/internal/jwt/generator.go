@@ -10,6 +10,8 @@ import (
 type Claims struct {
 	UserID int    `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role,omitempty"`
+	Region string `json:"region,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -21,12 +23,14 @@ func NewGenerator(config *Config) *Generator {
 	return &Generator{config: config}
 }
 
-func (g *Generator) Generate(userID int, email string) (string, error) {
+func (g *Generator) Generate(userID int, email, role, region string) (string, error) {
 	now := time.Now()
 
 	claims := Claims{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
+		Region: region,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   fmt.Sprintf("%d", userID),
 			Issuer:    g.config.Issuer,
@@ -42,7 +42,7 @@ func TestGenerator_Generate_Success(t *testing.T) {
 	email := "test@example.com"
 
 	// Act
-	token, err := generator.Generate(userID, email)
+	token, err := generator.Generate(userID, email, "user", "")
 
 	// Assert
 	require.NoError(t, err, "Should generate token without error")
@@ -66,7 +66,7 @@ func TestGenerator_Generate_ValidClaims(t *testing.T) {
 	beforeGeneration := time.Now()
 
 	// Act
-	tokenString, err := generator.Generate(userID, email)
+	tokenString, err := generator.Generate(userID, email, "admin", "eu")
 	require.NoError(t, err)
 
 	// Parse token to verify claims
@@ -85,6 +85,8 @@ func TestGenerator_Generate_ValidClaims(t *testing.T) {
 	// Custom claims
 	assert.Equal(t, userID, claims.UserID, "UserID should match")
 	assert.Equal(t, email, claims.Email, "Email should match")
+	assert.Equal(t, "admin", claims.Role, "Role should match")
+	assert.Equal(t, "eu", claims.Region, "Region should match")
 
 	// Standard claims
 	assert.Equal(t, "123", claims.Subject, "Subject should be string user ID")
@@ -120,8 +122,8 @@ func TestGenerator_Generate_DifferentUsers(t *testing.T) {
 	generator := NewGenerator(config)
 
 	// Act - Generate tokens for different users
-	token1, err1 := generator.Generate(1, "user1@example.com")
-	token2, err2 := generator.Generate(2, "user2@example.com")
+	token1, err1 := generator.Generate(1, "user1@example.com", "user", "")
+	token2, err2 := generator.Generate(2, "user2@example.com", "user", "")
 
 	// Assert
 	require.NoError(t, err1)
@@ -146,14 +148,14 @@ func TestGenerator_Generate_SameUserSameSecond(t *testing.T) {
 	email := "test@example.com"
 
 	// Act - Generate same user token in different seconds
-	token1, err1 := generator.Generate(userID, email)
+	token1, err1 := generator.Generate(userID, email, "user", "")
 	require.NoError(t, err1)
 
 	// Sleep FULL second to ensure different timestamp
 	// JWT timestamps are second-precision, not millisecond
 	time.Sleep(1100 * time.Millisecond)
 
-	token2, err2 := generator.Generate(userID, email)
+	token2, err2 := generator.Generate(userID, email, "user", "")
 	require.NoError(t, err2)
 
 	// Assert - Tokens generated in different seconds should be different
@@ -229,7 +231,7 @@ func TestGenerator_Generate_TableDriven(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Act
-			token, err := generator.Generate(tt.userID, tt.email)
+			token, err := generator.Generate(tt.userID, tt.email, "user", "")
 
 			// Assert
 			if tt.expectError {
@@ -265,7 +267,7 @@ func TestGenerator_TokenValidation(t *testing.T) {
 	}
 	generator := NewGenerator(config)
 
-	token, err := generator.Generate(1, "test@example.com")
+	token, err := generator.Generate(1, "test@example.com", "user", "")
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -318,7 +320,7 @@ func TestGenerator_ExpiredToken(t *testing.T) {
 	generator := NewGenerator(config)
 
 	// Act - Generate token
-	token, err := generator.Generate(1, "test@example.com")
+	token, err := generator.Generate(1, "test@example.com", "user", "")
 	require.NoError(t, err)
 
 	// Wait for token to expire (need >1 second because of JWT second precision)
@@ -349,7 +351,7 @@ func TestGenerator_SigningMethodValidation(t *testing.T) {
 	generator := NewGenerator(config)
 
 	// Act
-	token, err := generator.Generate(1, "test@example.com")
+	token, err := generator.Generate(1, "test@example.com", "user", "")
 	require.NoError(t, err)
 
 	// Parse to check signing method
@@ -465,7 +467,7 @@ func BenchmarkGenerator_Generate(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = generator.Generate(i, "bench@example.com")
+		_, _ = generator.Generate(i, "bench@example.com", "user", "")
 	}
 }
 
@@ -481,7 +483,7 @@ func BenchmarkGenerator_GenerateAndParse(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		token, _ := generator.Generate(i, "bench@example.com")
+		token, _ := generator.Generate(i, "bench@example.com", "user", "")
 		_, _ = jwt.ParseWithClaims(token, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 			return []byte(config.Secret), nil
 		})
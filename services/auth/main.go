@@ -1,21 +1,25 @@
 package main
 
 import (
-	"net/http"
+	"context"
+	"flag"
 	"os"
 
-	"meal-prep/services/auth/handlers"
-	"meal-prep/services/auth/repository"
-	"meal-prep/services/auth/service"
+	"meal-prep/migrations"
+	"meal-prep/services/auth/app"
 	"meal-prep/shared/database"
 	"meal-prep/shared/logging"
-	"meal-prep/shared/middleware"
+	"meal-prep/shared/server"
+	"meal-prep/shared/tracing"
 
-	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 )
 
 func main() {
+	waitForDeps := flag.Bool("wait-for-deps", false, "retry the database connection with backoff instead of exiting")
+	migrate := flag.Bool("migrate", false, "apply pending schema migrations before serving")
+	flag.Parse()
+
 	// Initialize logging first
 	logging.Init("auth-service")
 
@@ -23,32 +27,39 @@ func main() {
 		logging.Logger.Debug("No .env file found, using system environment variables")
 	}
 
-	// Database connection
-	db, err := database.NewPostgresConnection()
+	// Cancelled on SIGTERM or SIGINT so the server drains in-flight requests
+	// before main returns
+	ctx, stop := server.SignalContext()
+	defer stop()
+
+	// Export request and query spans when an OTLP endpoint is configured
+	shutdownTracing, err := tracing.Init(ctx, "auth-service")
 	if err != nil {
-		logging.Logger.Error("Failed to connect to database", "error", err)
+		logging.Logger.Error("Failed to initialize tracing", "error", err)
 		os.Exit(1)
 	}
-	defer db.Close()
-
-	logging.Logger.Info("Database connected successfully")
-
-	// Dependency injection chain
-	userRepo := repository.NewUserRepository(db)
-	authService := service.NewAuthService(userRepo)
-	authHandler := handlers.NewAuthHandler(authService)
+	defer shutdownTracing(context.Background())
 
-	// Routes with logging middleware
-	router := mux.NewRouter()
+	var db *database.DB
+	if !database.UseMemoryStorage() {
+		// Database connection
+		var err error
+		db, err = database.ConnectWithRetry(ctx, database.RetryConfigFromEnv(*waitForDeps), database.NewConnection)
+		if err != nil {
+			logging.Logger.Error("Failed to connect to database", "error", err)
+			os.Exit(1)
+		}
+		defer db.Close()
 
-	router.Use(middleware.LoggingMiddleware("auth-service"))
+		logging.Logger.Info("Database connected successfully")
 
-	router.HandleFunc("/register", authHandler.Register).Methods("POST")
-	router.HandleFunc("/login", authHandler.Login).Methods("POST")
-	router.Handle("/auth/me", middleware.ExtractUserFromGatewayHeaders(
-		http.HandlerFunc(authHandler.Me),
-	)).Methods("GET")
-	router.HandleFunc("/health", healthCheck).Methods("GET")
+		if *migrate {
+			if err := migrations.Apply(ctx, db, "auth"); err != nil {
+				logging.Logger.Error("Failed to migrate database", "error", err)
+				os.Exit(1)
+			}
+		}
+	}
 
 	port := os.Getenv("AUTH_PORT")
 	if port == "" {
@@ -56,15 +67,7 @@ func main() {
 	}
 
 	logging.Logger.Info("Starting auth service", "port", port)
-	if err := http.ListenAndServe(":"+port, router); err != nil {
-		logging.Logger.Error("Server failed to start", "error", err)
+	if err := server.Run(ctx, ":"+port, app.New(db), server.ConfigFromEnv()); err != nil {
+		logging.Logger.Error("Server failed", "error", err)
 	}
 }
-
-func healthCheck(w http.ResponseWriter, r *http.Request) {
-	//logging.WithContext(r.Context()).Debug("Health check requested")
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status": "healthy", "service": "auth"}`))
-}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"meal-prep/services/auth/repository"
+	"meal-prep/shared/models"
+)
+
+type refreshTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]models.RefreshToken // by token hash
+	nextID int
+}
+
+func NewRefreshTokenRepository() repository.RefreshTokenRepository {
+	return &refreshTokenRepository{tokens: make(map[string]models.RefreshToken)}
+}
+
+func (r *refreshTokenRepository) Create(userID int, tokenHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.insert(userID, tokenHash, expiresAt)
+	return nil
+}
+
+func (r *refreshTokenRepository) GetByHash(tokenHash string) (*models.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenRepository) Rotate(id, userID int, newHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.revokeWhere(func(token models.RefreshToken) bool { return token.ID == id }) {
+		return sql.ErrNoRows
+	}
+	r.insert(userID, newHash, expiresAt)
+	return nil
+}
+
+func (r *refreshTokenRepository) Revoke(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revokeWhere(func(token models.RefreshToken) bool { return token.ID == id })
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revokeWhere(func(token models.RefreshToken) bool { return token.UserID == userID })
+	return nil
+}
+
+// insert stores a new token. Callers must hold mu.
+func (r *refreshTokenRepository) insert(userID int, tokenHash string, expiresAt time.Time) {
+	r.nextID++
+	r.tokens[tokenHash] = models.RefreshToken{
+		ID:        r.nextID,
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// revokeWhere revokes the matching unrevoked tokens and reports whether there
+// were any. Callers must hold mu.
+func (r *refreshTokenRepository) revokeWhere(match func(models.RefreshToken) bool) bool {
+	now := time.Now().UTC()
+	revoked := false
+	for hash, token := range r.tokens {
+		if token.RevokedAt == nil && match(token) {
+			token.RevokedAt = &now
+			r.tokens[hash] = token
+			revoked = true
+		}
+	}
+	return revoked
+}
@@ -0,0 +1,82 @@
+// Package memory provides an in-memory UserRepository for local development
+// (STORAGE=memory). Registered users are lost when the process exits.
+package memory
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"meal-prep/services/auth/repository"
+	"meal-prep/shared/models"
+)
+
+type userRepository struct {
+	mu     sync.RWMutex
+	users  map[int]models.User
+	nextID int
+}
+
+func NewUserRepository() repository.UserRepository {
+	return &userRepository{users: make(map[int]models.User)}
+}
+
+func (r *userRepository) Create(email, passwordHash, region string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	now := time.Now().UTC()
+	user := models.User{
+		ID:           r.nextID,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         models.RoleUser,
+		Region:       region,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	r.users[user.ID] = user
+
+	user.PasswordHash = ""
+	return &user, nil
+}
+
+func (r *userRepository) GetByEmail(email string) (*models.User, string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			passwordHash := user.PasswordHash
+			user.PasswordHash = ""
+			return &user, passwordHash, nil
+		}
+	}
+	return nil, "", sql.ErrNoRows
+}
+
+func (r *userRepository) GetByID(id int) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	user.PasswordHash = ""
+	return &user, nil
+}
+
+func (r *userRepository) EmailExists(email string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type RefreshTokenRepository interface {
+	Create(userID int, tokenHash string, expiresAt time.Time) error
+	GetByHash(tokenHash string) (*models.RefreshToken, error)
+	// Rotate revokes the token and stores its replacement in one go. It
+	// returns sql.ErrNoRows when the token was already revoked.
+	Rotate(id, userID int, newHash string, expiresAt time.Time) error
+	Revoke(id int) error
+	RevokeAllForUser(userID int) error
+}
+
+type refreshTokenRepository struct {
+	db *database.DB
+}
+
+func NewRefreshTokenRepository(db *database.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(userID int, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.Exec(`
+		INSERT INTO auth.refresh_tokens (user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP)`, userID, tokenHash, expiresAt)
+	return err
+}
+
+func (r *refreshTokenRepository) GetByHash(tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	var revokedAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT id, user_id, token_hash, expires_at, revoked_at, created_at
+		FROM auth.refresh_tokens WHERE token_hash = $1`, tokenHash).Scan(
+		&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &revokedAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenRepository) Rotate(id, userID int, newHash string, expiresAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Only one of two concurrent refreshes with the same token gets through
+	result, err := tx.Exec(`
+		UPDATE auth.refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO auth.refresh_tokens (user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP)`, userID, newHash, expiresAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *refreshTokenRepository) Revoke(id int) error {
+	_, err := r.db.Exec(`
+		UPDATE auth.refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL`, id)
+	return err
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(userID int) error {
+	_, err := r.db.Exec(`
+		UPDATE auth.refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	return err
+}
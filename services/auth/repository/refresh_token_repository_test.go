@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RefreshTokenRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo RefreshTokenRepository
+}
+
+func (suite *RefreshTokenRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewRefreshTokenRepository(suite.db)
+}
+
+func (suite *RefreshTokenRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *RefreshTokenRepositoryTestSuite) TestGetByHash_Revoked() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM auth.refresh_tokens WHERE token_hash = $1")).
+		WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "token_hash", "expires_at", "revoked_at", "created_at"}).
+			AddRow(3, 1, "hash", now.Add(time.Hour), now, now))
+
+	// Act
+	token, err := suite.repo.GetByHash("hash")
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, token.ID)
+	assert.Equal(suite.T(), 1, token.UserID)
+	require.NotNil(suite.T(), token.RevokedAt)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RefreshTokenRepositoryTestSuite) TestRotate_Success() {
+	// Arrange
+	expiresAt := time.Now().Add(time.Hour)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE auth.refresh_tokens SET revoked_at = CURRENT_TIMESTAMP")).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO auth.refresh_tokens")).
+		WithArgs(1, "new-hash", expiresAt).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	err := suite.repo.Rotate(3, 1, "new-hash", expiresAt)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RefreshTokenRepositoryTestSuite) TestRotate_AlreadyRevoked() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE auth.refresh_tokens SET revoked_at = CURRENT_TIMESTAMP")).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+
+	// Act
+	err := suite.repo.Rotate(3, 1, "new-hash", time.Now().Add(time.Hour))
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenRepositoryTestSuite))
+}
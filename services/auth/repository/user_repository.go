@@ -6,7 +6,7 @@ import (
 )
 
 type UserRepository interface {
-	Create(email, passwordHash string) (*models.User, error)
+	Create(email, passwordHash, region string) (*models.User, error)
 	GetByEmail(email string) (*models.User, string, error)
 	GetByID(id int) (*models.User, error)
 	EmailExists(email string) (bool, error)
@@ -20,14 +20,14 @@ func NewUserRepository(db *database.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(email, passwordHash string) (*models.User, error) {
+func (r *userRepository) Create(email, passwordHash, region string) (*models.User, error) {
 	var user models.User
 	err := r.db.QueryRow(`
-		INSERT INTO auth.users (email, password_hash, created_at, updated_at) 
-		VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) 
-		RETURNING id, email, created_at, updated_at`,
-		email, passwordHash).Scan(
-		&user.ID, &user.Email, &user.CreatedAt, &user.UpdatedAt)
+		INSERT INTO auth.users (email, password_hash, region, created_at, updated_at) 
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) 
+		RETURNING id, email, role, region, created_at, updated_at`,
+		email, passwordHash, region).Scan(
+		&user.ID, &user.Email, &user.Role, &user.Region, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		return nil, err
@@ -40,9 +40,9 @@ func (r *userRepository) GetByEmail(email string) (*models.User, string, error)
 	var user models.User
 	var passwordHash string
 	err := r.db.QueryRow(`
-		SELECT id, email, password_hash, created_at, updated_at 
+		SELECT id, email, password_hash, role, region, created_at, updated_at 
 		FROM auth.users WHERE email = $1`, email).Scan(
-		&user.ID, &user.Email, &passwordHash, &user.CreatedAt, &user.UpdatedAt)
+		&user.ID, &user.Email, &passwordHash, &user.Role, &user.Region, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		return nil, "", err
@@ -53,9 +53,9 @@ func (r *userRepository) GetByEmail(email string) (*models.User, string, error)
 func (r *userRepository) GetByID(id int) (*models.User, error) {
 	var user models.User
 	err := r.db.QueryRow(`
-		SELECT id, email, created_at, updated_at
+		SELECT id, email, role, region, created_at, updated_at
 		FROM auth.users WHERE id = $1`, id).Scan(
-		&user.ID, &user.Email, &user.CreatedAt, &user.UpdatedAt)
+		&user.ID, &user.Email, &user.Role, &user.Region, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
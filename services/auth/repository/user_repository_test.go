@@ -43,15 +43,15 @@ func (suite *UserRepositoryTestSuite) TestCreate_Success() {
 	now := time.Now()
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		INSERT INTO auth.users (email, password_hash, created_at, updated_at) 
-		VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) 
-		RETURNING id, email, created_at, updated_at`)).
-		WithArgs(email, passwordHash).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "created_at", "updated_at"}).
-			AddRow(1, email, now, now))
+		INSERT INTO auth.users (email, password_hash, region, created_at, updated_at) 
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) 
+		RETURNING id, email, role, region, created_at, updated_at`)).
+		WithArgs(email, passwordHash, "").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role", "region", "created_at", "updated_at"}).
+			AddRow(1, email, "user", "", now, now))
 
 	// Act
-	user, err := suite.repo.Create(email, passwordHash)
+	user, err := suite.repo.Create(email, passwordHash, "")
 
 	// Assert
 	assert.NoError(suite.T(), err)
@@ -71,14 +71,14 @@ func (suite *UserRepositoryTestSuite) TestCreate_SQLSyntaxError() {
 	passwordHash := "hashed_password"
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		INSERT INTO auth.users (email, password_hash, created_at, updated_at) 
-		VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) 
-		RETURNING id, email, created_at, updated_at`)).
-		WithArgs(email, passwordHash).
+		INSERT INTO auth.users (email, password_hash, region, created_at, updated_at) 
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) 
+		RETURNING id, email, role, region, created_at, updated_at`)).
+		WithArgs(email, passwordHash, "").
 		WillReturnError(errors.New(`pq: syntax error at or near "RETURNING"`))
 
 	// Act
-	user, err := suite.repo.Create(email, passwordHash)
+	user, err := suite.repo.Create(email, passwordHash, "")
 
 	// Assert
 	assert.Error(suite.T(), err)
@@ -94,14 +94,14 @@ func (suite *UserRepositoryTestSuite) TestCreate_DuplicateKeyError() {
 	passwordHash := "hashed_password"
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		INSERT INTO auth.users (email, password_hash, created_at, updated_at) 
-		VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) 
-		RETURNING id, email, created_at, updated_at`)).
-		WithArgs(email, passwordHash).
+		INSERT INTO auth.users (email, password_hash, region, created_at, updated_at) 
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) 
+		RETURNING id, email, role, region, created_at, updated_at`)).
+		WithArgs(email, passwordHash, "").
 		WillReturnError(errors.New(`pq: duplicate key value violates unique constraint "users_email_key"`))
 
 	// Act
-	user, err := suite.repo.Create(email, passwordHash)
+	user, err := suite.repo.Create(email, passwordHash, "")
 
 	// Assert
 	assert.Error(suite.T(), err)
@@ -173,11 +173,11 @@ func (suite *UserRepositoryTestSuite) TestGetByEmail_Success() {
 	now := time.Now()
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT id, email, password_hash, created_at, updated_at 
+		SELECT id, email, password_hash, role, region, created_at, updated_at 
 		FROM auth.users WHERE email = $1`)).
 		WithArgs(email).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "created_at", "updated_at"}).
-			AddRow(1, email, passwordHash, now, now))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "role", "region", "created_at", "updated_at"}).
+			AddRow(1, email, passwordHash, "user", "eu", now, now))
 
 	// Act
 	user, hash, err := suite.repo.GetByEmail(email)
@@ -188,6 +188,7 @@ func (suite *UserRepositoryTestSuite) TestGetByEmail_Success() {
 	assert.Equal(suite.T(), 1, user.ID)
 	assert.Equal(suite.T(), email, user.Email)
 	assert.Equal(suite.T(), passwordHash, hash)
+	assert.Equal(suite.T(), "eu", user.Region)
 	assert.WithinDuration(suite.T(), now, user.CreatedAt, time.Second)
 
 	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
@@ -198,7 +199,7 @@ func (suite *UserRepositoryTestSuite) TestGetByEmail_NotFound() {
 	email := "notfound@example.com"
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT id, email, password_hash, created_at, updated_at 
+		SELECT id, email, password_hash, role, region, created_at, updated_at 
 		FROM auth.users WHERE email = $1`)).
 		WithArgs(email).
 		WillReturnError(sql.ErrNoRows)
@@ -221,11 +222,11 @@ func (suite *UserRepositoryTestSuite) TestGetByEmail_ScanError() {
 
 	// Return wrong data type for ID column (string instead of int)
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT id, email, password_hash, created_at, updated_at 
+		SELECT id, email, password_hash, role, region, created_at, updated_at 
 		FROM auth.users WHERE email = $1`)).
 		WithArgs(email).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "created_at", "updated_at"}).
-			AddRow("invalid_id", email, "hash", time.Now(), time.Now()))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "role", "region", "created_at", "updated_at"}).
+			AddRow("invalid_id", email, "hash", "user", "", time.Now(), time.Now()))
 
 	// Act
 	user, hash, err := suite.repo.GetByEmail(email)
@@ -1,9 +1,19 @@
 package service
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"meal-prep/services/auth/repository"
+	"meal-prep/shared/database"
+	"meal-prep/shared/logging"
 	"meal-prep/shared/models"
+	"os"
+	"slices"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
@@ -11,23 +21,49 @@ import (
 )
 
 var (
-	ErrUserExists         = errors.New("user with this email already exists")
-	ErrInvalidCredentials = errors.New("invalid email or password")
-	ErrWeakPassword       = errors.New("password must be at least 6 characters")
+	ErrUserExists          = errors.New("user with this email already exists")
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrWeakPassword        = errors.New("password must be at least 6 characters")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrUnknownRegion       = errors.New("unknown data region")
 )
 
+const defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+// RefreshTokenTTLFromEnv reads how long a refresh token stays valid
+// (REFRESH_TOKEN_TTL, default 30 days). Every refresh issues a new one, so
+// this is how long a user may stay away before having to log in again.
+func RefreshTokenTTLFromEnv() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("REFRESH_TOKEN_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return defaultRefreshTokenTTL
+}
+
 type AuthService interface {
-	Register(email, password string) (*models.AuthResponse, error)
+	// Register creates a user whose data is kept in region, one of
+	// DATA_REGIONS, or in the home database if region is empty
+	Register(email, password, region string) (*models.AuthResponse, error)
 	Login(email, password string) (*models.AuthResponse, error)
 	Me(userID int) (*models.User, error)
+
+	// Refresh exchanges a refresh token for a new access token and a new
+	// refresh token; the one presented is revoked
+	Refresh(refreshToken string) (*models.AuthResponse, error)
+	// Logout revokes the refresh token, or with all every one of its user's
+	Logout(refreshToken string, all bool) error
 }
 
 type authService struct {
 	userRepo     repository.UserRepository
+	refreshRepo  repository.RefreshTokenRepository
 	jwtGenerator *jwt.Generator
+	accessTTL    time.Duration
+	refreshTTL   time.Duration
+	regions      []string
 }
 
-func NewAuthService(userRepo repository.UserRepository) AuthService {
+func NewAuthService(userRepo repository.UserRepository, refreshRepo repository.RefreshTokenRepository) AuthService {
 	jwtConfig, err := jwt.LoadConfig()
 	if err != nil {
 		panic("Failed to load JWT config: " + err.Error())
@@ -35,7 +71,11 @@ func NewAuthService(userRepo repository.UserRepository) AuthService {
 
 	return &authService{
 		userRepo:     userRepo,
+		refreshRepo:  refreshRepo,
 		jwtGenerator: jwt.NewGenerator(jwtConfig),
+		accessTTL:    jwtConfig.TTL,
+		refreshTTL:   RefreshTokenTTLFromEnv(),
+		regions:      database.RegionsFromEnv(),
 	}
 }
 
@@ -43,10 +83,13 @@ func (s *authService) Me(userID int) (*models.User, error) {
 	return s.userRepo.GetByID(userID)
 }
 
-func (s *authService) Register(email, password string) (*models.AuthResponse, error) {
+func (s *authService) Register(email, password, region string) (*models.AuthResponse, error) {
 	if len(password) < 6 {
 		return nil, ErrWeakPassword
 	}
+	if region != "" && !slices.Contains(s.regions, region) {
+		return nil, ErrUnknownRegion
+	}
 
 	exists, err := s.userRepo.EmailExists(email)
 	if err != nil {
@@ -61,20 +104,20 @@ func (s *authService) Register(email, password string) (*models.AuthResponse, er
 		return nil, err
 	}
 
-	user, err := s.userRepo.Create(email, string(hashedPassword))
+	user, err := s.userRepo.Create(email, string(hashedPassword), region)
 	if err != nil {
 		return nil, err
 	}
 
-	token, err := s.jwtGenerator.Generate(user.ID, user.Email)
+	refreshToken, err := newRefreshToken()
 	if err != nil {
 		return nil, err
 	}
+	if err := s.refreshRepo.Create(user.ID, hashRefreshToken(refreshToken), time.Now().Add(s.refreshTTL)); err != nil {
+		return nil, err
+	}
 
-	return &models.AuthResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	return s.authResponse(user, refreshToken)
 }
 
 func (s *authService) Login(email, password string) (*models.AuthResponse, error) {
@@ -88,13 +131,119 @@ func (s *authService) Login(email, password string) (*models.AuthResponse, error
 		return nil, ErrInvalidCredentials
 	}
 
-	token, err := s.jwtGenerator.Generate(user.ID, user.Email)
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refreshRepo.Create(user.ID, hashRefreshToken(refreshToken), time.Now().Add(s.refreshTTL)); err != nil {
+		return nil, err
+	}
+
+	return s.authResponse(user, refreshToken)
+}
+
+func (s *authService) Refresh(refreshToken string) (*models.AuthResponse, error) {
+	stored, err := s.lookupRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if stored.RevokedAt != nil {
+		// A rotated token coming back means two parties hold it; cut both off
+		return nil, s.revokeOnReuse(stored.UserID)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.GetByID(stored.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	next, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.refreshRepo.Rotate(stored.ID, user.ID, hashRefreshToken(next), time.Now().Add(s.refreshTTL))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Refreshed concurrently with the same token
+			return nil, s.revokeOnReuse(stored.UserID)
+		}
+		return nil, err
+	}
+
+	return s.authResponse(user, next)
+}
+
+func (s *authService) Logout(refreshToken string, all bool) error {
+	stored, err := s.lookupRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+
+	if all {
+		return s.refreshRepo.RevokeAllForUser(stored.UserID)
+	}
+	// Logging out twice is fine
+	if stored.RevokedAt != nil {
+		return nil
+	}
+	return s.refreshRepo.Revoke(stored.ID)
+}
+
+func (s *authService) lookupRefreshToken(refreshToken string) (*models.RefreshToken, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	stored, err := s.refreshRepo.GetByHash(hashRefreshToken(refreshToken))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	return stored, nil
+}
+
+func (s *authService) revokeOnReuse(userID int) error {
+	logging.Logger.Warn("Revoked refresh token reused, revoking all of the user's tokens", "user_id", userID)
+	if err := s.refreshRepo.RevokeAllForUser(userID); err != nil {
+		return err
+	}
+	return ErrInvalidRefreshToken
+}
+
+func (s *authService) authResponse(user *models.User, refreshToken string) (*models.AuthResponse, error) {
+	token, err := s.jwtGenerator.Generate(user.ID, user.Email, user.Role, user.Region)
 	if err != nil {
 		return nil, err
 	}
 
 	return &models.AuthResponse{
-		Token: token,
-		User:  *user,
+		Token:        token,
+		ExpiresIn:    int(s.accessTTL.Seconds()),
+		RefreshToken: refreshToken,
+		User:         *user,
 	}, nil
 }
+
+// newRefreshToken returns 32 random bytes, base64url encoded.
+func newRefreshToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashRefreshToken is what the repository stores and looks tokens up by.
+// Tokens are random, so an unsalted hash is enough.
+func hashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
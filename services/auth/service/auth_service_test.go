@@ -1,14 +1,17 @@
 package service
 
 import (
+	"database/sql"
 	"errors"
 	"meal-prep/services/auth/service/mocks"
+	"meal-prep/shared/logging"
 	"meal-prep/shared/models"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -16,7 +19,7 @@ func TestAuthService_MissingJWTConfig(t *testing.T) {
 	mockRepo := new(mocks.MockUserRepository)
 
 	assert.Panics(t, func() {
-		NewAuthService(mockRepo)
+		NewAuthService(mockRepo, new(mocks.MockRefreshTokenRepository))
 	}, "Should panic when JWT config missing")
 }
 
@@ -27,7 +30,7 @@ func TestAuthService_TokenGeneration(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
 	mockRepo.On("EmailExists", "test@example.com").Return(false, nil)
-	mockRepo.On("Create", "test@example.com", mock.AnythingOfType("string")).Return(
+	mockRepo.On("Create", "test@example.com", mock.AnythingOfType("string"), "").Return(
 		&models.User{
 			ID:        1,
 			Email:     "test@example.com",
@@ -35,10 +38,10 @@ func TestAuthService_TokenGeneration(t *testing.T) {
 			UpdatedAt: time.Now(),
 		}, nil)
 
-	service := NewAuthService(mockRepo)
+	service := NewAuthService(mockRepo, storingRefreshTokens())
 
 	// Act
-	result, err := service.Register("test@example.com", "password")
+	result, err := service.Register("test@example.com", "password", "")
 
 	// Assert
 	assert.NoError(t, err)
@@ -55,18 +58,18 @@ func TestAuthService_TokenUniqueness(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
 	mockRepo.On("EmailExists", "user1@example.com").Return(false, nil)
-	mockRepo.On("Create", "user1@example.com", mock.AnythingOfType("string")).Return(
+	mockRepo.On("Create", "user1@example.com", mock.AnythingOfType("string"), "").Return(
 		&models.User{ID: 1, Email: "user1@example.com", CreatedAt: time.Now(), UpdatedAt: time.Now()}, nil)
 
 	mockRepo.On("EmailExists", "user2@example.com").Return(false, nil)
-	mockRepo.On("Create", "user2@example.com", mock.AnythingOfType("string")).Return(
+	mockRepo.On("Create", "user2@example.com", mock.AnythingOfType("string"), "").Return(
 		&models.User{ID: 2, Email: "user2@example.com", CreatedAt: time.Now(), UpdatedAt: time.Now()}, nil)
 
-	service := NewAuthService(mockRepo)
+	service := NewAuthService(mockRepo, storingRefreshTokens())
 
 	// Act
-	result1, _ := service.Register("user1@example.com", "password")
-	result2, _ := service.Register("user2@example.com", "password")
+	result1, _ := service.Register("user1@example.com", "password", "")
+	result2, _ := service.Register("user2@example.com", "password", "")
 
 	// Assert
 	assert.NotEqual(t, result1.Token, result2.Token)
@@ -91,7 +94,7 @@ func TestAuthService_Register(t *testing.T) {
 			password: "password123",
 			setupMocks: func(mockRepo *mocks.MockUserRepository) {
 				mockRepo.On("EmailExists", "test@example.com").Return(false, nil)
-				mockRepo.On("Create", "test@example.com", mock.AnythingOfType("string")).Return(
+				mockRepo.On("Create", "test@example.com", mock.AnythingOfType("string"), "").Return(
 					&models.User{
 						ID:        1,
 						Email:     "test@example.com",
@@ -138,7 +141,7 @@ func TestAuthService_Register(t *testing.T) {
 			password: "validpassword123",
 			setupMocks: func(mockRepo *mocks.MockUserRepository) {
 				mockRepo.On("EmailExists", "test@example.com").Return(false, nil)
-				mockRepo.On("Create", "test@example.com", mock.AnythingOfType("string")).Return(
+				mockRepo.On("Create", "test@example.com", mock.AnythingOfType("string"), "").Return(
 					(*models.User)(nil), errors.New("insert failed"))
 			},
 			expectedError: errors.New("insert failed"),
@@ -151,10 +154,10 @@ func TestAuthService_Register(t *testing.T) {
 			// Arrange
 			mockRepo := new(mocks.MockUserRepository)
 			tt.setupMocks(mockRepo)
-			service := NewAuthService(mockRepo)
+			service := NewAuthService(mockRepo, storingRefreshTokens())
 
 			// Act
-			result, err := service.Register(tt.email, tt.password)
+			result, err := service.Register(tt.email, tt.password, "")
 
 			// Assert
 			if tt.expectedError != nil {
@@ -167,6 +170,8 @@ func TestAuthService_Register(t *testing.T) {
 					assert.NotNil(t, result)
 					assert.Equal(t, tt.email, result.User.Email)
 					assert.NotEmpty(t, result.Token)
+					assert.NotEmpty(t, result.RefreshToken)
+					assert.Equal(t, 86400, result.ExpiresIn)
 				}
 			}
 
@@ -239,7 +244,7 @@ func TestAuthService_Login(t *testing.T) {
 			// Arrange
 			mockRepo := new(mocks.MockUserRepository)
 			tt.setupMocks(mockRepo)
-			service := NewAuthService(mockRepo)
+			service := NewAuthService(mockRepo, storingRefreshTokens())
 
 			// Act
 			result, err := service.Login(tt.email, tt.password)
@@ -255,6 +260,8 @@ func TestAuthService_Login(t *testing.T) {
 					assert.NotNil(t, result)
 					assert.Equal(t, tt.email, result.User.Email)
 					assert.NotEmpty(t, result.Token)
+					assert.NotEmpty(t, result.RefreshToken)
+					assert.Equal(t, 86400, result.ExpiresIn)
 				}
 			}
 
@@ -283,12 +290,12 @@ func TestAuthService_validateInput(t *testing.T) {
 			mockRepo := new(mocks.MockUserRepository)
 			if tt.wantError == nil && tt.password != "" {
 				mockRepo.On("EmailExists", tt.email).Return(false, nil)
-				mockRepo.On("Create", tt.email, mock.AnythingOfType("string")).Return(
+				mockRepo.On("Create", tt.email, mock.AnythingOfType("string"), "").Return(
 					&models.User{ID: 1, Email: tt.email}, nil)
 			}
 
-			service := NewAuthService(mockRepo)
-			_, err := service.Register(tt.email, tt.password)
+			service := NewAuthService(mockRepo, storingRefreshTokens())
+			_, err := service.Register(tt.email, tt.password, "")
 
 			if tt.wantError != nil {
 				assert.Error(t, err)
@@ -338,7 +345,7 @@ func TestAuthService_Me(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mocks.MockUserRepository)
 			tt.setupMocks(mockRepo)
-			svc := NewAuthService(mockRepo)
+			svc := NewAuthService(mockRepo, storingRefreshTokens())
 
 			result, err := svc.Me(tt.userID)
 
@@ -356,6 +363,153 @@ func TestAuthService_Me(t *testing.T) {
 	}
 }
 
+func TestAuthService_Register_Region(t *testing.T) {
+	setupTestJWTEnv(t)
+	t.Setenv("DATA_REGIONS", "eu,us")
+
+	t.Run("served_region", func(t *testing.T) {
+		mockRepo := new(mocks.MockUserRepository)
+		mockRepo.On("EmailExists", "test@example.com").Return(false, nil)
+		mockRepo.On("Create", "test@example.com", mock.AnythingOfType("string"), "eu").Return(
+			&models.User{ID: 1, Email: "test@example.com", Region: "eu"}, nil)
+		service := NewAuthService(mockRepo, storingRefreshTokens())
+
+		result, err := service.Register("test@example.com", "password123", "eu")
+
+		require.NoError(t, err)
+		assert.Equal(t, "eu", result.User.Region)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown_region", func(t *testing.T) {
+		mockRepo := new(mocks.MockUserRepository)
+		service := NewAuthService(mockRepo, storingRefreshTokens())
+
+		_, err := service.Register("test@example.com", "password123", "apac")
+
+		assert.Equal(t, ErrUnknownRegion, err)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthService_Refresh_RotatesToken(t *testing.T) {
+	setupTestJWTEnv(t)
+
+	// Arrange
+	userRepo := new(mocks.MockUserRepository)
+	refreshRepo := new(mocks.MockRefreshTokenRepository)
+	refreshRepo.On("GetByHash", hashRefreshToken("old-token")).Return(
+		&models.RefreshToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	userRepo.On("GetByID", 1).Return(&models.User{ID: 1, Email: "user@example.com", Role: models.RoleAdmin}, nil)
+	refreshRepo.On("Rotate", 3, 1, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+	service := NewAuthService(userRepo, refreshRepo)
+
+	// Act
+	result, err := service.Refresh("old-token")
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Token)
+	assert.NotEmpty(t, result.RefreshToken)
+	assert.NotEqual(t, "old-token", result.RefreshToken)
+	assert.Equal(t, models.RoleAdmin, result.User.Role)
+	// The new token is stored hashed
+	newHash := refreshRepo.Calls[1].Arguments.String(2)
+	assert.Equal(t, hashRefreshToken(result.RefreshToken), newHash)
+	refreshRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_Rejected(t *testing.T) {
+	setupTestJWTEnv(t)
+	logging.Init("test")
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name       string
+		setupMocks func(*mocks.MockUserRepository, *mocks.MockRefreshTokenRepository)
+	}{
+		{
+			name: "unknown_token",
+			setupMocks: func(userRepo *mocks.MockUserRepository, refreshRepo *mocks.MockRefreshTokenRepository) {
+				refreshRepo.On("GetByHash", hashRefreshToken("token")).Return(nil, sql.ErrNoRows)
+			},
+		},
+		{
+			name: "expired_token",
+			setupMocks: func(userRepo *mocks.MockUserRepository, refreshRepo *mocks.MockRefreshTokenRepository) {
+				refreshRepo.On("GetByHash", hashRefreshToken("token")).Return(
+					&models.RefreshToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(-time.Second)}, nil)
+			},
+		},
+		{
+			name: "reused_token_revokes_all",
+			setupMocks: func(userRepo *mocks.MockUserRepository, refreshRepo *mocks.MockRefreshTokenRepository) {
+				refreshRepo.On("GetByHash", hashRefreshToken("token")).Return(
+					&models.RefreshToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil)
+				refreshRepo.On("RevokeAllForUser", 1).Return(nil)
+			},
+		},
+		{
+			name: "concurrent_refresh_revokes_all",
+			setupMocks: func(userRepo *mocks.MockUserRepository, refreshRepo *mocks.MockRefreshTokenRepository) {
+				refreshRepo.On("GetByHash", hashRefreshToken("token")).Return(
+					&models.RefreshToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+				userRepo.On("GetByID", 1).Return(&models.User{ID: 1, Email: "user@example.com"}, nil)
+				refreshRepo.On("Rotate", 3, 1, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(sql.ErrNoRows)
+				refreshRepo.On("RevokeAllForUser", 1).Return(nil)
+			},
+		},
+		{
+			name: "deleted_user",
+			setupMocks: func(userRepo *mocks.MockUserRepository, refreshRepo *mocks.MockRefreshTokenRepository) {
+				refreshRepo.On("GetByHash", hashRefreshToken("token")).Return(
+					&models.RefreshToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+				userRepo.On("GetByID", 1).Return(nil, sql.ErrNoRows)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			userRepo := new(mocks.MockUserRepository)
+			refreshRepo := new(mocks.MockRefreshTokenRepository)
+			tt.setupMocks(userRepo, refreshRepo)
+			service := NewAuthService(userRepo, refreshRepo)
+
+			// Act
+			result, err := service.Refresh("token")
+
+			// Assert
+			assert.Equal(t, ErrInvalidRefreshToken, err)
+			assert.Nil(t, result)
+			refreshRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	setupTestJWTEnv(t)
+
+	// Arrange
+	userRepo := new(mocks.MockUserRepository)
+	refreshRepo := new(mocks.MockRefreshTokenRepository)
+	refreshRepo.On("GetByHash", hashRefreshToken("phone")).Return(
+		&models.RefreshToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	refreshRepo.On("Revoke", 3).Return(nil)
+	refreshRepo.On("GetByHash", hashRefreshToken("laptop")).Return(
+		&models.RefreshToken{ID: 4, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	refreshRepo.On("RevokeAllForUser", 1).Return(nil)
+	refreshRepo.On("GetByHash", hashRefreshToken("unknown")).Return(nil, sql.ErrNoRows)
+	service := NewAuthService(userRepo, refreshRepo)
+
+	// Act & Assert
+	assert.NoError(t, service.Logout("phone", false))
+	assert.NoError(t, service.Logout("laptop", true))
+	assert.Equal(t, ErrInvalidRefreshToken, service.Logout("unknown", false))
+	refreshRepo.AssertExpectations(t)
+}
+
 // =============================================================================
 // TEST HELPERS
 // =============================================================================
@@ -366,3 +520,11 @@ func setupTestJWTEnv(t *testing.T) {
 	t.Setenv("JWT_ISSUER", "meal-prep-auth")
 	t.Setenv("JWT_AUDIENCE", "meal-prep-api")
 }
+
+// storingRefreshTokens accepts every refresh token Register and Login issue.
+func storingRefreshTokens() *mocks.MockRefreshTokenRepository {
+	refreshRepo := new(mocks.MockRefreshTokenRepository)
+	refreshRepo.On("Create", mock.AnythingOfType("int"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+		Return(nil).Maybe()
+	return refreshRepo
+}
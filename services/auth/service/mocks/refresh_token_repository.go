@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"time"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRefreshTokenRepository is a mock implementation of repository.RefreshTokenRepository
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(userID int, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(userID, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(tokenHash string) (*models.RefreshToken, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Rotate(id, userID int, newHash string, expiresAt time.Time) error {
+	args := m.Called(id, userID, newHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) Revoke(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
@@ -10,8 +10,8 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) Create(email, passwordHash string) (*models.User, error) {
-	args := m.Called(email, passwordHash)
+func (m *MockUserRepository) Create(email, passwordHash, region string) (*models.User, error) {
+	args := m.Called(email, passwordHash, region)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -14,6 +14,6 @@ func GenerateTestJWT(userID int, email string) string {
 	}
 
 	generator := jwt.NewGenerator(config)
-	token, _ := generator.Generate(userID, email)
+	token, _ := generator.Generate(userID, email, "user", "")
 	return token
 }
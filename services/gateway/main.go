@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"meal-prep/services/gateway/proxy"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/metrics"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/mtls"
+	"meal-prep/shared/server"
+	"meal-prep/shared/tracing"
+
+	"github.com/gorilla/mux"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Initialize logging first
+	logging.Init("gateway")
+
+	if err := godotenv.Load(); err != nil {
+		logging.Logger.Debug("No .env file found, using system environment variables")
+	}
+
+	// Cancelled on SIGTERM or SIGINT so the server drains in-flight requests
+	// before main returns
+	ctx, stop := server.SignalContext()
+	defer stop()
+
+	// Export request spans when an OTLP endpoint is configured
+	shutdownTracing, err := tracing.Init(ctx, "gateway")
+	if err != nil {
+		logging.Logger.Error("Failed to initialize tracing", "error", err)
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+
+	cfg, err := proxy.LoadConfig()
+	if err != nil {
+		logging.Logger.Error("Failed to load gateway config", "error", err)
+		os.Exit(1)
+	}
+
+	verifier, err := proxy.VerifierFromEnv()
+	if err != nil {
+		logging.Logger.Error("Failed to configure token verification", "error", err)
+		os.Exit(1)
+	}
+
+	// Calls to the services go over mTLS when it is configured; the route
+	// timeouts bound them, not the client
+	upstreamClient, err := mtls.HTTPClientFromEnv(0)
+	if err != nil {
+		logging.Logger.Error("Failed to configure upstream client", "error", err)
+		os.Exit(1)
+	}
+
+	gatewayMetrics := metrics.New("gateway")
+
+	// Routes with tracing, logging and metrics middleware
+	router := mux.NewRouter()
+	router.Use(middleware.TracingMiddleware("gateway"))
+	router.Use(middleware.LoggingMiddleware("gateway"))
+	router.Use(gatewayMetrics.Middleware)
+
+	// Answered by the gateway itself, ahead of the proxied routes
+	router.HandleFunc("/health", healthCheck).Methods("GET")
+	router.Handle("/metrics", gatewayMetrics.Handler()).Methods("GET")
+
+	if err := proxy.Register(router, cfg, verifier, upstreamClient.Transport); err != nil {
+		logging.Logger.Error("Failed to register routes", "error", err)
+		os.Exit(1)
+	}
+
+	port := os.Getenv("GATEWAY_PORT")
+	if port == "" {
+		port = "8000"
+	}
+
+	serverCfg := server.ConfigFromEnv()
+	srv, err := server.New(":"+port, proxy.NewHandler(cfg, router), serverCfg)
+	if err != nil {
+		logging.Logger.Error("Failed to configure server", "error", err)
+		os.Exit(1)
+	}
+	// Clients are browsers and apps without internal certificates, so the
+	// gateway serves them plain HTTP even when it calls the services over
+	// mTLS; public TLS ends at the load balancer in front of it
+	srv.TLSConfig = nil
+
+	logging.Logger.Info("Starting gateway", "port", port, "routes", len(cfg.Routes))
+	if err := server.Serve(ctx, srv, serverCfg.DrainPeriod); err != nil {
+		logging.Logger.Error("Server failed", "error", err)
+	}
+}
+
+func healthCheck(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status": "healthy", "service": "gateway"}`))
+}
@@ -0,0 +1,129 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/policy"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Verifier checks the signature, expiry, issuer and audience of tokens
+// issued by the auth service.
+type Verifier struct {
+	secret []byte
+	parser *jwt.Parser
+}
+
+func NewVerifier(secret, issuer, audience string) *Verifier {
+	return &Verifier{
+		secret: []byte(secret),
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+			jwt.WithIssuer(issuer),
+			jwt.WithAudience(audience),
+			jwt.WithExpirationRequired(),
+		),
+	}
+}
+
+// VerifierFromEnv reads the same JWT_SECRET, JWT_ISSUER and JWT_AUDIENCE as
+// the auth service signs with.
+func VerifierFromEnv() (*Verifier, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil, fmt.Errorf("JWT_SECRET not set")
+	}
+
+	issuer := os.Getenv("JWT_ISSUER")
+	if issuer == "" {
+		issuer = "meal-prep-auth"
+	}
+
+	audience := os.Getenv("JWT_AUDIENCE")
+	if audience == "" {
+		audience = "meal-prep-api"
+	}
+
+	return NewVerifier(secret, issuer, audience), nil
+}
+
+// Verify returns the user of a bearer Authorization header. The error text
+// is what clients are told.
+func (v *Verifier) Verify(authHeader string) (*middleware.UserContext, error) {
+	if authHeader == "" {
+		return nil, errors.New("Missing authorization token")
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		return nil, errors.New("Invalid authorization format")
+	}
+
+	var claims middleware.Claims
+	_, err := v.parser.ParseWithClaims(tokenString, &claims, func(*jwt.Token) (interface{}, error) {
+		return v.secret, nil
+	})
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errors.New("Token expired")
+		}
+		return nil, errors.New("Invalid token")
+	}
+	if claims.UserID <= 0 {
+		return nil, errors.New("Invalid token claims")
+	}
+
+	return &middleware.UserContext{
+		UserID: claims.UserID,
+		Email:  claims.Email,
+		Role:   claims.Role,
+		Scopes: policy.ParseScopes(claims.Scope),
+		Region: claims.Region,
+	}, nil
+}
+
+// authenticate applies the route's Auth to the request before it is
+// proxied. The verified user travels in the context to the rewrite, which
+// decides whether the token is forwarded.
+func (v *Verifier) authenticate(auth Auth, next http.Handler) http.Handler {
+	if auth == AuthNone {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := v.Verify(r.Header.Get("Authorization"))
+		if err != nil {
+			if auth == AuthRequired {
+				logging.WithContext(r.Context()).Warn("Rejected request", "reason", err.Error())
+				writeError(w, "authentication_failed", err.Error(), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), middleware.UserCtxKey, user)
+		ctx = logging.WithUserID(ctx, user.UserID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// forwardAuthorization drops a token that didn't verify on an optional
+// route. Services read the user from the forwarded bearer token, the same
+// contract as behind Kong, so only a verified token may reach them.
+func forwardAuthorization(ctx context.Context, auth Auth, header http.Header) {
+	if auth == AuthNone {
+		return
+	}
+	if _, ok := middleware.GetUserFromGatewayContext(ctx); !ok {
+		header.Del("Authorization")
+	}
+}
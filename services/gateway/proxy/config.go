@@ -0,0 +1,150 @@
+package proxy
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultConfig mirrors the routes of kong.yml, so the gateway can stand in
+// for Kong without further setup.
+//
+//go:embed routes.json
+var defaultConfig []byte
+
+// Auth says what a route does with the caller's token.
+type Auth string
+
+const (
+	// AuthRequired rejects requests without a valid token with 401.
+	AuthRequired Auth = "required"
+	// AuthOptional passes the user on when the token is valid and drops an
+	// invalid one, so public routes can personalise but never for a forged
+	// user.
+	AuthOptional Auth = "optional"
+	// AuthNone passes the request on untouched, for the auth service's own
+	// login and refresh endpoints.
+	AuthNone Auth = "none"
+)
+
+type Config struct {
+	// Upstreams maps service names to base URLs
+	Upstreams map[string]string `json:"upstreams"`
+	Routes    []Route           `json:"routes"`
+	CORS      CORSConfig        `json:"cors"`
+}
+
+// Route sends requests under any of Paths to Upstream. Like Kong, the
+// longest matching path wins, and of routes with the same path the one
+// listing the request's method.
+type Route struct {
+	Name     string   `json:"name"`
+	Upstream string   `json:"upstream"`
+	Paths    []string `json:"paths"`
+	// Methods restricts the route to these methods; empty means any
+	Methods []string `json:"methods,omitempty"`
+	// StripPrefix removes the matched path before proxying, so /auth/login
+	// reaches the auth service as /login
+	StripPrefix bool `json:"strip_prefix,omitempty"`
+	Auth        Auth `json:"auth"`
+	// TimeoutSeconds bounds the whole upstream exchange; 0 means
+	// defaultRouteTimeout
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	// Stream flushes responses as they arrive and lifts the timeout, for
+	// server-sent events
+	Stream bool `json:"stream,omitempty"`
+}
+
+type CORSConfig struct {
+	Origins        []string `json:"origins"`
+	Methods        []string `json:"methods"`
+	Headers        []string `json:"headers"`
+	ExposedHeaders []string `json:"exposed_headers"`
+	Credentials    bool     `json:"credentials"`
+	MaxAgeSeconds  int      `json:"max_age_seconds"`
+}
+
+const defaultRouteTimeout = 30 * time.Second
+
+func (r Route) timeout() time.Duration {
+	if r.TimeoutSeconds > 0 {
+		return time.Duration(r.TimeoutSeconds) * time.Second
+	}
+	return defaultRouteTimeout
+}
+
+// LoadConfig reads the routes from the JSON file named by GATEWAY_CONFIG,
+// or the built-in defaults when it is unset. An upstream's URL can be
+// overridden with <NAME>_URL, e.g. RECIPE_SERVICE_URL for recipe-service.
+func LoadConfig() (*Config, error) {
+	data := defaultConfig
+	if path := os.Getenv("GATEWAY_CONFIG"); path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("gateway: failed to read config: %w", err)
+		}
+	}
+
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range cfg.Upstreams {
+		if value := os.Getenv(upstreamEnvVar(name)); value != "" {
+			cfg.Upstreams[name] = value
+		}
+	}
+	return cfg, cfg.validate()
+}
+
+// ParseConfig decodes a JSON config, rejecting unknown fields so a typo in a
+// route doesn't silently open it up.
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("gateway: invalid config: %w", err)
+	}
+	return &cfg, cfg.validate()
+}
+
+func (c *Config) validate() error {
+	for name, raw := range c.Upstreams {
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("gateway: upstream %s: invalid URL %q", name, raw)
+		}
+	}
+
+	for _, route := range c.Routes {
+		if _, ok := c.Upstreams[route.Upstream]; !ok {
+			return fmt.Errorf("gateway: route %s: unknown upstream %q", route.Name, route.Upstream)
+		}
+		if len(route.Paths) == 0 {
+			return fmt.Errorf("gateway: route %s: no paths", route.Name)
+		}
+		for _, path := range route.Paths {
+			if !strings.HasPrefix(path, "/") {
+				return fmt.Errorf("gateway: route %s: path %q must start with /", route.Name, path)
+			}
+		}
+		switch route.Auth {
+		case AuthRequired, AuthOptional, AuthNone:
+		default:
+			return fmt.Errorf("gateway: route %s: auth must be required, optional or none", route.Name)
+		}
+	}
+	return nil
+}
+
+func upstreamEnvVar(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_URL"
+}
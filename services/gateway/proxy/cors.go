@@ -0,0 +1,56 @@
+package proxy
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// cors answers preflight requests itself and adds the CORS headers to every
+// response from an allowed origin, so the services behind the gateway need
+// no CORS handling of their own.
+func cors(cfg CORSConfig, next http.Handler) http.Handler {
+	origins := make(map[string]bool, len(cfg.Origins))
+	for _, origin := range cfg.Origins {
+		origins[origin] = true
+	}
+	methods := strings.Join(cfg.Methods, ", ")
+	headers := strings.Join(cfg.Headers, ", ")
+	exposed := strings.Join(cfg.ExposedHeaders, ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		allowed := origins[origin] || origins["*"]
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		if allowed {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if cfg.Credentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			if exposed != "" {
+				w.Header().Set("Access-Control-Expose-Headers", exposed)
+			}
+		}
+
+		if preflight {
+			if allowed {
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+				if cfg.MaxAgeSeconds > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAgeSeconds))
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,143 @@
+// Package proxy is the API gateway: it answers CORS, verifies tokens once at
+// the edge and reverse-proxies each route's paths to its service.
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/metrics"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+// Register adds the routes of cfg to router, proxying through transport,
+// e.g. an mTLS client's. Routes the gateway answers itself, like /health,
+// must be registered on router first to take precedence.
+func Register(router *mux.Router, cfg *Config, verifier *Verifier, transport http.RoundTripper) error {
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, "not_found", "No route matches the request", http.StatusNotFound)
+	})
+	router.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, "method_not_allowed", "Method not allowed", http.StatusMethodNotAllowed)
+	})
+
+	for _, entry := range routeEntries(cfg.Routes) {
+		target, err := url.Parse(cfg.Upstreams[entry.route.Upstream])
+		if err != nil {
+			return err
+		}
+
+		upstream := metrics.Transport(entry.route.Upstream, transport)
+		handler := verifier.authenticate(entry.route.Auth, upstreamHandler(entry.route, entry.path, target, upstream))
+		match := router.PathPrefix(entry.path).MatcherFunc(onSegmentBoundary(entry.path))
+		if len(entry.route.Methods) > 0 {
+			match = match.Methods(entry.route.Methods...)
+		}
+		match.Handler(handler)
+	}
+	return nil
+}
+
+// NewHandler wraps the router in the CORS handling, which must see
+// preflight requests before routing does.
+func NewHandler(cfg *Config, router http.Handler) http.Handler {
+	return cors(cfg.CORS, router)
+}
+
+type routeEntry struct {
+	route Route
+	path  string
+}
+
+// routeEntries orders every path of every route the way they must be tried:
+// longest path first and, for the same path, routes restricted to some
+// methods before those taking any.
+func routeEntries(routes []Route) []routeEntry {
+	var entries []routeEntry
+	for _, route := range routes {
+		for _, path := range route.Paths {
+			entries = append(entries, routeEntry{route: route, path: path})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if len(entries[i].path) != len(entries[j].path) {
+			return len(entries[i].path) > len(entries[j].path)
+		}
+		return len(entries[i].route.Methods) > 0 && len(entries[j].route.Methods) == 0
+	})
+	return entries
+}
+
+// onSegmentBoundary keeps /recipes from matching /recipes-archive.
+func onSegmentBoundary(prefix string) mux.MatcherFunc {
+	return func(r *http.Request, _ *mux.RouteMatch) bool {
+		rest := strings.TrimPrefix(r.URL.Path, prefix)
+		return rest == "" || strings.HasPrefix(rest, "/") || strings.HasSuffix(prefix, "/")
+	}
+}
+
+func upstreamHandler(route Route, prefix string, target *url.URL, transport http.RoundTripper) http.Handler {
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			if route.StripPrefix {
+				pr.Out.URL.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(pr.In.URL.Path, prefix), "/")
+				pr.Out.URL.RawPath = ""
+			}
+			pr.SetURL(target)
+			pr.SetXForwarded()
+
+			if requestID, ok := logging.RequestIDFromContext(pr.In.Context()); ok {
+				pr.Out.Header.Set(logging.RequestIDHeader, requestID)
+			}
+			forwardAuthorization(pr.In.Context(), route.Auth, pr.Out.Header)
+		},
+		Transport: transport,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			logging.WithContext(r.Context()).Error("Upstream request failed",
+				"route", route.Name, "upstream", route.Upstream, "error", err)
+			if errors.Is(err, context.DeadlineExceeded) {
+				writeError(w, "gateway_timeout", "The service took too long to respond", http.StatusGatewayTimeout)
+				return
+			}
+			writeError(w, "bad_gateway", "The service is unavailable", http.StatusBadGateway)
+		},
+	}
+
+	if route.Stream {
+		// Events are passed on as they arrive, for as long as the stream
+		// stays open
+		proxy.FlushInterval = -1
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.NewResponseController(w).SetWriteDeadline(time.Time{})
+			proxy.ServeHTTP(w, r)
+		})
+	}
+
+	timeout := route.timeout()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		proxy.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func writeError(w http.ResponseWriter, errorType, message string, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(models.ErrorResponse{
+		Error:   errorType,
+		Code:    code,
+		Message: message,
+	})
+}
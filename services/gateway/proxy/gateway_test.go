@@ -0,0 +1,232 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+// upstreamCall is what a fake service saw of a proxied request.
+type upstreamCall struct {
+	Service       string
+	Path          string
+	Authorization string
+	RequestID     string
+}
+
+func fakeService(t *testing.T, name string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(upstreamCall{
+			Service:       name,
+			Path:          r.URL.Path,
+			Authorization: r.Header.Get("Authorization"),
+			RequestID:     r.Header.Get("X-Request-ID"),
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func setupGateway(t *testing.T) http.Handler {
+	logging.Init("test")
+	auth := fakeService(t, "auth")
+	recipes := fakeService(t, "recipes")
+
+	cfg, err := ParseConfig([]byte(`{
+		"upstreams": {"auth-service": "` + auth.URL + `", "recipe-service": "` + recipes.URL + `"},
+		"routes": [
+			{"name": "auth-me", "upstream": "auth-service", "paths": ["/auth/me"], "methods": ["GET"], "auth": "required"},
+			{"name": "auth", "upstream": "auth-service", "paths": ["/auth"], "strip_prefix": true, "auth": "none"},
+			{"name": "recipes-public", "upstream": "recipe-service", "paths": ["/recipes"], "methods": ["GET"], "auth": "optional"},
+			{"name": "recipes-protected", "upstream": "recipe-service", "paths": ["/recipes", "/meal-plans"], "auth": "required"}
+		],
+		"cors": {"origins": ["http://localhost:3000"], "methods": ["GET", "POST"], "headers": ["Authorization"], "exposed_headers": ["ETag"], "credentials": true, "max_age_seconds": 600}
+	}`))
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.Use(middleware.LoggingMiddleware("gateway"))
+	require.NoError(t, Register(router, cfg, NewVerifier(testSecret, "meal-prep-auth", "meal-prep-api"), http.DefaultTransport))
+	return NewHandler(cfg, router)
+}
+
+func signToken(t *testing.T, secret string, expiresIn time.Duration) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": 7,
+		"email":   "cook@example.com",
+		"role":    "admin",
+		"region":  "eu",
+		"iss":     "meal-prep-auth",
+		"aud":     []string{"meal-prep-api"},
+		"exp":     time.Now().Add(expiresIn).Unix(),
+	})
+	signed, err := token.SignedString([]byte(secret))
+	require.NoError(t, err)
+	return "Bearer " + signed
+}
+
+func serve(t *testing.T, handler http.Handler, req *http.Request) (*httptest.ResponseRecorder, upstreamCall) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var call upstreamCall
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&call))
+	}
+	return rec, call
+}
+
+func TestGateway_RequiredRouteForwardsVerifiedToken(t *testing.T) {
+	handler := setupGateway(t)
+
+	token := signToken(t, testSecret, time.Hour)
+	req := httptest.NewRequest(http.MethodPost, "/meal-plans", nil)
+	req.Header.Set("Authorization", token)
+	rec, call := serve(t, handler, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "recipes", call.Service)
+	assert.Equal(t, "/meal-plans", call.Path)
+	assert.Equal(t, token, call.Authorization)
+}
+
+func TestGateway_RequiredRouteRejectsBadTokens(t *testing.T) {
+	handler := setupGateway(t)
+
+	tests := []struct {
+		name          string
+		authorization string
+		message       string
+	}{
+		{"missing", "", "Missing authorization token"},
+		{"wrong secret", signToken(t, "other-secret", time.Hour), "Invalid token"},
+		{"expired", signToken(t, testSecret, -time.Minute), "Token expired"},
+		{"not bearer", "Basic abc", "Invalid authorization format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/recipes", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec, _ := serve(t, handler, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			var response models.ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+			assert.Equal(t, tt.message, response.Message)
+		})
+	}
+}
+
+func TestGateway_OptionalRouteDropsForgedIdentity(t *testing.T) {
+	handler := setupGateway(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/recipes/3", nil)
+	req.Header.Set("Authorization", signToken(t, "other-secret", time.Hour))
+	rec, call := serve(t, handler, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "/recipes/3", call.Path)
+	assert.Empty(t, call.Authorization)
+}
+
+func TestGateway_RoutesByLongestPathAndStripsPrefix(t *testing.T) {
+	handler := setupGateway(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+	rec, call := serve(t, handler, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "auth", call.Service)
+	assert.Equal(t, "/login", call.Path)
+	assert.NotEmpty(t, call.RequestID)
+
+	// /auth/me is a route of its own, which needs a token
+	rec, _ = serve(t, handler, httptest.NewRequest(http.MethodGet, "/auth/me", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// A prefix only matches whole path segments
+	rec, _ = serve(t, handler, httptest.NewRequest(http.MethodGet, "/recipes-archive", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGateway_AnswersCORSPreflight(t *testing.T) {
+	handler := setupGateway(t)
+
+	req := httptest.NewRequest(http.MethodOptions, "/meal-plans", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "http://localhost:3000", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+
+	req = httptest.NewRequest(http.MethodGet, "/recipes", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestGateway_UpstreamDown(t *testing.T) {
+	logging.Init("test")
+	cfg, err := ParseConfig([]byte(`{
+		"upstreams": {"recipe-service": "http://127.0.0.1:1"},
+		"routes": [{"name": "recipes", "upstream": "recipe-service", "paths": ["/recipes"], "auth": "none"}]
+	}`))
+	require.NoError(t, err)
+	router := mux.NewRouter()
+	require.NoError(t, Register(router, cfg, NewVerifier(testSecret, "meal-prep-auth", "meal-prep-api"), http.DefaultTransport))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recipes", nil))
+
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+}
+
+func TestLoadConfig_DefaultsCoverKongRoutes(t *testing.T) {
+	t.Setenv("RECIPE_SERVICE_URL", "http://localhost:8002")
+
+	cfg, err := LoadConfig()
+
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8002", cfg.Upstreams["recipe-service"])
+	assert.Len(t, cfg.Routes, 8)
+
+	// Public catalogue reads the recipe service serves without a token
+	for _, route := range cfg.Routes {
+		if route.Name == "recipes-public" {
+			assert.Contains(t, route.Paths, "/allergens")
+		}
+	}
+}
+
+func TestParseConfig_RejectsInvalidRoutes(t *testing.T) {
+	_, err := ParseConfig([]byte(`{"upstreams": {}, "routes": [{"name": "x", "upstream": "nope", "paths": ["/x"], "auth": "none"}]}`))
+	assert.ErrorContains(t, err, "unknown upstream")
+
+	_, err = ParseConfig([]byte(`{"upstreams": {"a": "http://a"}, "routes": [{"name": "x", "upstream": "a", "paths": ["/x"], "auth": "sometimes"}]}`))
+	assert.ErrorContains(t, err, "auth must be")
+
+	_, err = ParseConfig([]byte(`{"upstreams": {"a": "http://a"}, "routes": [], "typo": true}`))
+	assert.ErrorContains(t, err, "unknown field")
+}
@@ -0,0 +1,261 @@
+// Package app wires the recipe catalogue's repositories, services,
+// background jobs and routes into one handler, which
+// services/recipe-catalogue serves on its own port and cmd/allinone mounts
+// beside the other services.
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"meal-prep/services/recipe-catalogue/handlers"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/services/recipe-catalogue/repository/memory"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/services/recipe-catalogue/storage"
+	"meal-prep/shared/audit"
+	"meal-prep/shared/database"
+	"meal-prep/shared/events"
+	"meal-prep/shared/idempotency"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/metrics"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+// New returns the recipe catalogue's routes on db, or on in-memory
+// repositories seeded with defaults when db is nil. Calls to the
+// recommendations service go through recommendations when it is not nil,
+// and over HTTP otherwise. Background jobs run until ctx is cancelled.
+func New(ctx context.Context, db *database.DB, recommendations http.RoundTripper) (http.Handler, error) {
+	return newHandler(ctx, db, recommendations, true)
+}
+
+// NewRegional returns the routes serving a data region's users on its
+// database, which holds their own data beside a read-only replica of the
+// home catalogue. Requests that write the catalogue are served by home (see
+// Catalogue), and so are the jobs that keep it tidy.
+func NewRegional(ctx context.Context, db *database.DB, recommendations http.RoundTripper) (http.Handler, error) {
+	return newHandler(ctx, db, recommendations, false)
+}
+
+func newHandler(ctx context.Context, db *database.DB, recommendations http.RoundTripper, home bool) (http.Handler, error) {
+	// Page sizes list endpoints accept
+	models.ConfigurePagination(models.PaginationConfigFromEnv())
+
+	var (
+		recipeRepo      repository.RecipeRepository
+		categoryRepo    repository.CategoryRepository
+		ingredientRepo  repository.IngredientRepository
+		storeLayoutRepo repository.StoreLayoutRepository
+		sharedListRepo  repository.SharedListRepository
+		stepRepo        repository.StepRepository
+		sessionRepo     repository.CookingSessionRepository
+		imageRepo       repository.RecipeImageRepository
+		followRepo      repository.FollowRepository
+		activityRepo    repository.ActivityRepository
+		profileRepo     repository.ProfileRepository
+		lineageRepo     repository.LineageRepository
+		costRepo        repository.CostRepository
+		prepRepo        repository.PrepSessionRepository
+		savedSearchRepo repository.SavedSearchRepository
+		qualityRepo     repository.RecipeQualityRepository
+		cleanupRepo     repository.IngredientCleanupRepository
+		ownershipRepo   repository.RecipeOwnershipRepository
+		mealPlanRepo    repository.MealPlanRepository
+		favoriteRepo    repository.FavoriteRepository
+		templateRepo    repository.RecipeTemplateRepository
+		backfillRepo    repository.BackfillRepository
+		pantryRepo      repository.PantryRepository
+		householdRepo   repository.HouseholdRepository
+		auditStore      audit.Store
+		idempotentKeys  idempotency.Store
+	)
+
+	if db == nil {
+		logging.Logger.Warn("Using in-memory storage, data will be lost on restart")
+		store := memory.NewStore()
+		store.SeedDefaults()
+
+		recipeRepo = memory.NewRecipeRepository(store)
+		categoryRepo = memory.NewCategoryRepository(store)
+		ingredientRepo = memory.NewIngredientRepository(store)
+		storeLayoutRepo = memory.NewStoreLayoutRepository(store)
+		sharedListRepo = memory.NewSharedListRepository(store)
+		stepRepo = memory.NewStepRepository(store)
+		sessionRepo = memory.NewCookingSessionRepository(store)
+		imageRepo = memory.NewRecipeImageRepository(store)
+		followRepo = memory.NewFollowRepository(store)
+		activityRepo = memory.NewActivityRepository(store)
+		profileRepo = memory.NewProfileRepository(store)
+		lineageRepo = memory.NewLineageRepository(store)
+		costRepo = memory.NewCostRepository(store)
+		prepRepo = memory.NewPrepSessionRepository(store)
+		savedSearchRepo = memory.NewSavedSearchRepository(store)
+		qualityRepo = memory.NewRecipeQualityRepository(store)
+		cleanupRepo = memory.NewIngredientCleanupRepository(store)
+		ownershipRepo = memory.NewRecipeOwnershipRepository(store)
+		mealPlanRepo = memory.NewMealPlanRepository(store)
+		favoriteRepo = memory.NewFavoriteRepository(store)
+		templateRepo = memory.NewRecipeTemplateRepository(store)
+		backfillRepo = memory.NewBackfillRepository(store)
+		pantryRepo = memory.NewPantryRepository(store)
+		householdRepo = memory.NewHouseholdRepository(store)
+		auditStore = audit.NewMemoryStore()
+		idempotentKeys = idempotency.NewMemoryStore()
+	} else {
+		// Keep the stats views behind /ingredients/popular fresh
+		if home {
+			go db.RunViewRefresher(ctx, database.ViewRefreshIntervalFromEnv(),
+				"recipe_catalogue.ingredient_usage")
+		}
+
+		recipeRepo = repository.NewRecipeRepository(db)
+		categoryRepo = repository.NewCachedCategoryRepository(
+			repository.NewCategoryRepository(db), repository.CategoryCacheTTLFromEnv())
+		ingredientRepo = repository.NewIngredientRepository(db)
+		storeLayoutRepo = repository.NewStoreLayoutRepository(db)
+		sharedListRepo = repository.NewSharedListRepository(db)
+		stepRepo = repository.NewStepRepository(db)
+		sessionRepo = repository.NewCookingSessionRepository(db)
+		imageRepo = repository.NewRecipeImageRepository(db)
+		followRepo = repository.NewFollowRepository(db)
+		activityRepo = repository.NewActivityRepository(db)
+		profileRepo = repository.NewProfileRepository(db)
+		lineageRepo = repository.NewLineageRepository(db)
+		costRepo = repository.NewCostRepository(db)
+		prepRepo = repository.NewPrepSessionRepository(db)
+		savedSearchRepo = repository.NewSavedSearchRepository(db)
+		qualityRepo = repository.NewRecipeQualityRepository(db)
+		cleanupRepo = repository.NewIngredientCleanupRepository(db)
+		ownershipRepo = repository.NewRecipeOwnershipRepository(db)
+		mealPlanRepo = repository.NewMealPlanRepository(db)
+		favoriteRepo = repository.NewFavoriteRepository(db)
+		templateRepo = repository.NewRecipeTemplateRepository(db)
+		backfillRepo = repository.NewBackfillRepository(db)
+		pantryRepo = repository.NewPantryRepository(db)
+		householdRepo = repository.NewHouseholdRepository(db)
+		auditStore = audit.NewSQLStore(db, "recipe_catalogue.audit_log")
+		idempotentKeys = idempotency.NewSQLStore(db, "recipe_catalogue.idempotency_keys")
+	}
+
+	// Dependency injection chain
+	bus := events.NewBus()
+	socialService := service.NewSocialService(followRepo, activityRepo)
+	bus.Subscribe(events.RecipePublished, socialService.RecordActivity)
+
+	imageStore, err := storage.FromEnv(handlers.PublicBaseURLFromEnv() + "/images")
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure image storage: %w", err)
+	}
+
+	recipeService := service.NewRecipeService(recipeRepo, categoryRepo, ingredientRepo, imageRepo, favoriteRepo, bus)
+	ingredientService := service.NewIngredientService(ingredientRepo, recipeRepo)
+	groceryService := service.NewGroceryService(ingredientRepo, recipeRepo, storeLayoutRepo, costRepo, mealPlanRepo)
+	cookingService := service.NewCookingService(recipeRepo, ingredientRepo, stepRepo, sessionRepo)
+	imageService := service.NewRecipeImageService(recipeRepo, imageRepo, imageStore)
+	profileService := service.NewProfileService(profileRepo, recipeRepo)
+	lineageService := service.NewLineageService(lineageRepo, recipeRepo, stepRepo, categoryRepo)
+	costService := service.NewCostService(costRepo, recipeRepo, ingredientRepo, bus)
+	bus.Subscribe(events.IngredientPriceUpdated, costService.SnapshotRecipeCosts)
+	prepService := service.NewPrepService(prepRepo, recipeRepo, ingredientRepo, stepRepo)
+	savedSearchService := service.NewSavedSearchService(savedSearchRepo, recipeRepo, categoryRepo, ingredientRepo,
+		service.NewLogSearchAlertNotifier())
+	go service.RunSavedSearchAlerts(ctx, savedSearchService, service.SavedSearchCheckIntervalFromEnv())
+	cleanupService := service.NewIngredientCleanupService(cleanupRepo, service.IngredientUnusedMonthsFromEnv())
+	if home {
+		go service.RunIngredientCleanup(ctx, cleanupService, service.IngredientCleanupIntervalFromEnv())
+	}
+
+	recipeHandler := handlers.NewRecipeHandler(recipeService)
+	ingredientHandler := handlers.NewIngredientHandler(ingredientService)
+	groceryHandler := handlers.NewGroceryHandler(groceryService)
+	sharedListHandler := handlers.NewSharedListHandler(groceryService, service.NewSharedListService(sharedListRepo), handlers.PublicBaseURLFromEnv())
+	cookingHandler := handlers.NewCookingHandler(cookingService)
+	imageHandler := handlers.NewRecipeImageHandler(imageService)
+	socialHandler := handlers.NewSocialHandler(socialService)
+	profileHandler := handlers.NewProfileHandler(profileService)
+	lineageHandler := handlers.NewLineageHandler(lineageService)
+	costHandler := handlers.NewCostHandler(costService)
+	prepHandler := handlers.NewPrepHandler(prepService)
+	recommender, err := handlers.RecommenderFromEnv(recommendations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure recommendations client: %w", err)
+	}
+	favoriteHandler := handlers.NewFavoriteHandler(service.NewFavoriteService(favoriteRepo, recipeRepo, imageRepo))
+	mealPlanHandler := handlers.NewMealPlanHandler(service.NewMealPlanService(mealPlanRepo, recipeRepo, ingredientRepo, stepRepo, recommender))
+	savedSearchHandler := handlers.NewSavedSearchHandler(savedSearchService)
+	qualityHandler := handlers.NewQualityHandler(service.NewRecipeQualityService(qualityRepo, ingredientRepo))
+	cleanupHandler := handlers.NewIngredientCleanupHandler(cleanupService)
+	ownershipHandler := handlers.NewRecipeOwnershipHandler(service.NewRecipeOwnershipService(ownershipRepo))
+	templateHandler := handlers.NewRecipeTemplateHandler(service.NewRecipeTemplateService(templateRepo, recipeRepo, stepRepo, categoryRepo))
+	backfillHandler := handlers.NewBackfillHandler(service.NewBackfillService(backfillRepo, costService))
+	importHandler := handlers.NewRecipeImportHandler(service.NewRecipeImportService(recipeRepo, stepRepo, categoryRepo, ingredientRepo,
+		handlers.NewPageFetcher()))
+	nutritionHandler := handlers.NewNutritionHandler(service.NewNutritionLookupService(handlers.NutritionSourceFromEnv(),
+		service.NutritionCacheTTLFromEnv(), service.NutritionLookupsPerMinuteFromEnv()))
+	pantryEvents := service.NewPantryEvents()
+	pantryHandler := handlers.NewPantryHandler(service.NewPantryService(pantryRepo, ingredientRepo, imageRepo, favoriteRepo, pantryEvents))
+	householdHandler := handlers.NewHouseholdHandler(service.NewHouseholdService(householdRepo, pantryEvents))
+	feedHandler := handlers.NewFeedHandler(service.NewFeedService(recipeRepo), handlers.PublicBaseURLFromEnv())
+	embedHandler := handlers.NewEmbedHandler(service.NewEmbedService(recipeRepo, imageRepo), handlers.PublicBaseURLFromEnv())
+	recommendationsUsage, err := handlers.RecommendationsUsageClientFromEnv(recommendations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure recommendations usage client: %w", err)
+	}
+	usageHandler := handlers.NewUsageHandler(handlers.UsageMeterFromEnv(), recommendationsUsage)
+
+	// Request and connection pool metrics for Prometheus to scrape
+	serviceMetrics := metrics.New("recipe-catalogue-service")
+	if db != nil {
+		serviceMetrics.RegisterDB(db, "recipe_catalogue")
+	}
+
+	// Routes with tracing, logging and metrics middleware
+	router := mux.NewRouter()
+	router.Use(middleware.TracingMiddleware("recipe-catalogue-service"))
+	router.Use(middleware.LoggingMiddleware("recipe-catalogue-service"))
+	router.Use(serviceMetrics.Middleware)
+
+	// Health check and metrics
+	router.HandleFunc("/health", healthCheck).Methods("GET")
+	router.Handle("/metrics", serviceMetrics.Handler()).Methods("GET")
+	if db != nil {
+		router.Handle("/debug/queries", db.SlowQueriesHandler(10)).Methods("GET")
+	}
+
+	handlers.RegisterRoutes(router, recipeHandler, ingredientHandler, groceryHandler, cookingHandler, imageHandler, socialHandler, profileHandler, lineageHandler, costHandler, prepHandler, feedHandler, embedHandler, savedSearchHandler, qualityHandler, cleanupHandler, usageHandler, mealPlanHandler, favoriteHandler, sharedListHandler, ownershipHandler, templateHandler, backfillHandler, importHandler, nutritionHandler, pantryHandler, householdHandler, audit.NewAuditor(auditStore), idempotency.NewKeys(idempotentKeys))
+	return router, nil
+}
+
+// catalogueRoutes read or write the catalogue every region shares:
+// recipes, ingredients, categories and what is built from them, along with
+// the public profiles and follows around them. The pantry's cookable
+// recipes and the user's favorites are their own.
+var catalogueRoutes = middleware.PathsUnder([]string{
+	"/recipes", "/ingredients", "/categories", "/allergens", "/templates", "/backfills",
+	"/sitemap.xml", "/sitemaps", "/feeds", "/embed", "/oembed", "/images",
+	"/me/recipes", "/me/profile", "/me/following", "/me/followers", "/me/feed", "/users",
+}, "/recipes/cookable", "/users/me/favorites")
+
+// Catalogue reports whether r reads or writes the catalogue, for
+// middleware.RouteByRegion. The catalogue is only written at home, so a
+// recipe or ingredient has the same ID in every region's replica. Users'
+// own data, such as meal plans, the pantry, favorites or cooking sessions,
+// stays in their region.
+func Catalogue(r *http.Request) bool {
+	return catalogueRoutes(r) &&
+		!strings.HasSuffix(r.URL.Path, "/favorite") && !strings.HasSuffix(r.URL.Path, "/cooking-sessions")
+}
+
+func healthCheck(w http.ResponseWriter, r *http.Request) {
+	//logging.WithContext(r.Context()).Debug("Health check requested")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status": "healthy", "service": "recipe-catalogue"}`))
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogue(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		expected bool
+	}{
+		{http.MethodGet, "/recipes", true},
+		{http.MethodPost, "/recipes", true},
+		{http.MethodPut, "/recipes/3/ingredients", true},
+		{http.MethodPost, "/recipes/import-url", true},
+		{http.MethodPost, "/ingredients", true},
+		{http.MethodGet, "/recipes/3/quality", true},
+		{http.MethodGet, "/me/recipes", true},
+		{http.MethodGet, "/users/4/profile", true},
+		{http.MethodGet, "/recipes/cookable", false},
+		{http.MethodPost, "/recipes/3/favorite", false},
+		{http.MethodGet, "/users/me/favorites", false},
+		{http.MethodPost, "/recipes/3/cooking-sessions", false},
+		{http.MethodPost, "/meal-plans", false},
+		{http.MethodPut, "/me/pantry", false},
+		{http.MethodGet, "/me/saved-searches", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Catalogue(httptest.NewRequest(tt.method, tt.path, nil)))
+		})
+	}
+}
@@ -4,17 +4,191 @@ import "errors"
 
 var (
 	// Recipe-specific (only recipe-catalogue uses these)
-	ErrRecipeNotFound     = errors.New("recipe not found")
-	ErrRecipeNameRequired = errors.New("recipe name is required")
-	ErrCategoryNotFound   = errors.New("category not found")
-	ErrInvalidCategory    = errors.New("invalid category ID")
-	ErrForbidden          = errors.New("you do not have permission to modify this recipe")
+	ErrRecipeNotFound      = errors.New("recipe not found")
+	ErrRecipeNameRequired  = errors.New("recipe name is required")
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrInvalidCategory     = errors.New("invalid category ID")
+	ErrForbidden           = errors.New("you do not have permission to modify this recipe")
+	ErrInvalidRecipeStatus = errors.New("status must be one of draft, private, published, archived")
+	ErrInvalidDifficulty   = errors.New("difficulty must be one of easy, medium, hard")
+	ErrInvalidTotalTime    = errors.New("total time must not be negative")
 
 	// Ingredient-specific (only recipe-catalogue uses these)
 	ErrIngredientNotFound     = errors.New("ingredient not found")
 	ErrIngredientExists       = errors.New("ingredient already exists")
 	ErrIngredientNameRequired = errors.New("ingredient with this name already exists")
 	ErrCannotDeleteIngredient = errors.New("cannot delete ingredient - it is used in recipes")
+	ErrDensityNotFound        = errors.New("no density recorded for this ingredient")
+	ErrInvalidDensity         = errors.New("grams_per_ml must be greater than 0 and at most 25")
+	ErrInvalidPrice           = errors.New("price must not be negative")
+	ErrInvalidSubstitution    = errors.New("substitutes must be other existing ingredients, each listed once, with a ratio greater than 0 and at most 100")
+	ErrInvalidLocale          = errors.New("locale must be a language tag such as ru or pt-BR")
+	ErrInvalidTranslation     = errors.New("translations need a name of at most 100 characters and a locale other than English, each listed once")
+
+	// Ingredients named while authoring a recipe (only recipe-catalogue uses these)
+	ErrInvalidIngredientName      = errors.New("ingredient_name must be at most 100 characters")
+	ErrInvalidIngredientCategory  = errors.New("ingredient_category must be one of Meat, Vegetables, Dairy, Grains, Spices, Oils, Fish, Fruits")
+	ErrIngredientCategoryRequired = errors.New("ingredient_category is required for an ingredient the catalogue doesn't have")
+
+	// Store layouts (only recipe-catalogue uses these)
+	ErrStoreLayoutNotFound     = errors.New("store layout not found")
+	ErrStoreLayoutNameRequired = errors.New("store layout name is required")
+	ErrStoreLayoutExists       = errors.New("you already have a store layout with this name")
+	ErrInvalidStoreLayout      = errors.New("store layout categories must be non-empty and unique")
+	ErrInvalidBudget           = errors.New("budget must be greater than 0")
+	ErrInvalidGrocerySource    = errors.New("give only one of recipe_ids, meal_plan_id, or from and to")
+	ErrInvalidGroceryDateRange = errors.New("from and to must be YYYY-MM-DD dates at most 31 days apart, to not before from")
+
+	// Shared shopping lists (only recipe-catalogue uses these)
+	ErrSharedListNotFound     = errors.New("shared shopping list not found")
+	ErrSharedListItemNotFound = errors.New("shopping list item not found")
+	ErrEmptyShoppingList      = errors.New("the grocery list is empty, there is nothing to share")
+	ErrCheckedRequired        = errors.New("checked is required")
+
+	// Recipe steps and cooking mode (only recipe-catalogue uses these)
+	ErrStepInstructionRequired   = errors.New("step instruction is required")
+	ErrInvalidStepDuration       = errors.New("step duration must be greater than 0")
+	ErrStepIngredientNotInRecipe = errors.New("step ingredients must be ingredients of the recipe")
+	ErrInvalidStepTimer          = errors.New("step timers need a label and a duration of 1 second to 24 hours")
+	ErrStepNotFound              = errors.New("step not found")
+	ErrInvalidStepOrder          = errors.New("step order must list every step of the recipe exactly once")
+	ErrInvalidTechnique          = errors.New("unknown cooking technique")
+	ErrCookingSessionNotFound    = errors.New("cooking session not found")
+	ErrInvalidScale              = errors.New("scale must be greater than 0 and at most 20")
+	ErrInvalidCookingStep        = errors.New("current step is outside the recipe")
+
+	// Batch-cooking prep sessions (only recipe-catalogue uses these)
+	ErrPrepSessionNotFound     = errors.New("prep session not found")
+	ErrPrepSessionNameRequired = errors.New("prep session name is required and must be at most 100 characters")
+	ErrInvalidPrepRecipes      = errors.New("a prep session needs 1 to 10 different recipes")
+	ErrInvalidPortions         = errors.New("portions must be between 1 and 50")
+	ErrInvalidPrepDate         = errors.New("prep date must be in YYYY-MM-DD format")
+	ErrInvalidKeepsDays        = errors.New("keeps_days must be between 1 and 365")
+	ErrInvalidReheatNote       = errors.New("reheat instructions must be at most 200 characters")
+
+	// Meal plans (only recipe-catalogue uses these)
+	ErrMealPlanNotFound           = errors.New("meal plan not found")
+	ErrMealPlanEntryNotFound      = errors.New("meal plan entry not found")
+	ErrMealPlanTitleRequired      = errors.New("meal plan title is required and must be at most 200 characters")
+	ErrInvalidMealPlanPeriod      = errors.New("meal plan period must be YYYY-MM-DD dates at most 31 days apart, with period_type week, biweek, month or custom")
+	ErrInvalidMealPlanEntry       = errors.New("entry needs a date within the plan's period and a slot of breakfast, lunch or dinner")
+	ErrMealPlanEntryExists        = errors.New("this recipe is already planned for that meal")
+	ErrInvalidMealPlanGeneration  = errors.New("a generated plan covers 1 to 14 days with different slots of breakfast, lunch or dinner")
+	ErrNoRecommendations          = errors.New("there are no recommended recipes to plan with")
+	ErrRecommendationsUnavailable = errors.New("recommendations are unavailable, try again later")
+	ErrInvalidMealPlanPreferences = errors.New("weekday and weekend max minutes must be between 1 and 1440, if set")
+	ErrNoRecipesFitPreferences    = errors.New("none of your recommended recipes fit your meal plan time limits")
+	ErrMealPlanMemberNotFound     = errors.New("meal plan member not found")
+	ErrInvalidMealPlanMembers     = errors.New("a plan has at most 12 members, with different names of at most 50 characters")
+	ErrInvalidMealPlanAssignee    = errors.New("assignee_id must be one of the plan's members")
+
+	// Saved searches (only recipe-catalogue uses these)
+	ErrSavedSearchNotFound     = errors.New("saved search not found")
+	ErrSavedSearchNameRequired = errors.New("saved search name is required and must be at most 100 characters")
+	ErrInvalidSavedSearch      = errors.New("a saved search needs 1 to 20 different ingredients and a positive max time, if any")
+
+	// Recipe images (only recipe-catalogue uses these)
+	ErrRecipeImageNotFound = errors.New("recipe image not found")
+	ErrInvalidImageURL     = errors.New("image url must be an absolute http or https URL")
+	ErrInvalidImageCaption = errors.New("image caption must be at most 200 characters")
+	ErrInvalidImageOrder   = errors.New("order must list every image of the recipe exactly once")
+	ErrImageFileRequired   = errors.New("an image file is required")
+	ErrUnsupportedImage    = errors.New("image must be a JPEG, PNG or GIF")
+	ErrImageTooLarge       = errors.New("image must be at most 10 MB and 24 megapixels")
+
+	// Following and activity feed (only recipe-catalogue uses these)
+	ErrInvalidFollowTarget = errors.New("user to follow is invalid")
+	ErrCannotFollowSelf    = errors.New("you cannot follow yourself")
+	ErrNotFollowing        = errors.New("you are not following this user")
+
+	// Favorites (only recipe-catalogue uses these)
+	ErrNotFavorite = errors.New("recipe is not in your favorites")
+
+	// Pantry and cookable recipes (only recipe-catalogue uses these)
+	ErrTooManyPantryIngredients = errors.New("list at most 500 ingredients")
+	ErrPantryItemNotFound       = errors.New("ingredient is not in your pantry")
+
+	// Households sharing a pantry (only recipe-catalogue uses these)
+	ErrHouseholdNotFound       = errors.New("you don't belong to a household")
+	ErrInvalidHouseholdName    = errors.New("household name is required and must be at most 100 characters")
+	ErrAlreadyInHousehold      = errors.New("you already belong to a household")
+	ErrInvalidInviteCode       = errors.New("invite code doesn't match any household")
+	ErrHouseholdMemberNotFound = errors.New("user is not a member of your household")
+	ErrHouseholdOwnerLeaving   = errors.New("the owner can leave only once everyone else has")
+	ErrNotHouseholdOwner       = errors.New("only the household's owner can remove other members")
+
+	// Public profiles (only recipe-catalogue uses these)
+	ErrProfileNotFound    = errors.New("profile not found")
+	ErrInvalidDisplayName = errors.New("display name must be at most 50 characters")
+	ErrInvalidBio         = errors.New("bio must be at most 500 characters")
+
+	// Measurement systems (only recipe-catalogue uses these)
+	ErrInvalidMeasurementSystem = errors.New("measurement system must be metric or imperial")
+
+	// Sitemaps (only recipe-catalogue uses these)
+	ErrSitemapChunkNotFound = errors.New("sitemap not found")
+
+	// Recipe forks (only recipe-catalogue uses these)
+	ErrForkCategoryRequired = errors.New("the original recipe has no category, choose one for the fork")
+
+	// Recipe templates (only recipe-catalogue uses these)
+	ErrTemplateNotFound           = errors.New("template not found")
+	ErrTemplateExists             = errors.New("this recipe is already a template")
+	ErrTemplateDescriptionTooLong = errors.New("description must be at most 500 characters")
+	ErrTemplateCategoryRequired   = errors.New("the template has no category, choose one for the recipe")
+
+	// Recipe comparison (only recipe-catalogue uses these)
+	ErrInvalidCompareIDs = errors.New("ids must list two recipe IDs, e.g. ids=1,2")
+	ErrCompareSameRecipe = errors.New("ids must list two different recipes")
+
+	// Recipe quality report (only recipe-catalogue uses these)
+	ErrInvalidQualityCheck = errors.New("failing must be one of description, ingredients, ingredient_units, steps, image, total_time")
+
+	// Unused ingredient cleanup (only recipe-catalogue uses these)
+	ErrInvalidCleanupAction     = errors.New("action must be archive or delete")
+	ErrCleanupIngredientsNeeded = errors.New("ingredient_ids must list between 1 and 500 ingredients")
+	ErrInvalidUnusedMonths      = errors.New("months must be a positive whole number")
+
+	// Duplicate ingredient merging (only recipe-catalogue uses these)
+	ErrMergeIntoItself      = errors.New("an ingredient cannot be merged into itself")
+	ErrInvalidMinSimilarity = errors.New("min_similarity must be between 0.3 and 1")
+
+	// Recipe ownership report (only recipe-catalogue uses these)
+	ErrInvalidInactiveDays = errors.New("inactive_days must be a positive whole number")
+
+	// Data quality backfills (only recipe-catalogue uses these)
+	ErrUnknownBackfillJob = errors.New("job must be normalize-units, recipe-costs or search-indexes")
+	ErrBackfillRunning    = errors.New("this backfill is already running")
+	ErrBackfillNotFound   = errors.New("backfill run not found")
+
+	// Dietary classification (only recipe-catalogue uses these)
+	ErrInvalidDietaryFlag      = errors.New("flags must be among meat, gelatin, fish, shellfish, dairy, egg, honey, plant_based")
+	ErrInvalidDiet             = errors.New("diet must be vegan, vegetarian, pescatarian or omnivore")
+	ErrComplianceRecipesNeeded = errors.New("recipe_ids must list between 1 and 100 recipes")
+	ErrInvalidMinConfidence    = errors.New("min_confidence must be between 0 and 1")
+
+	// Allergen tagging (only recipe-catalogue uses these)
+	ErrInvalidAllergen            = errors.New("allergen must be one of the codes listed at /allergens")
+	ErrIngredientAllergenNotFound = errors.New("ingredient is not tagged with this allergen")
+
+	// Branded products (only recipe-catalogue uses these)
+	ErrProductNotFound         = errors.New("product not found")
+	ErrProductExists           = errors.New("this ingredient already has a product of that brand and name")
+	ErrInvalidProduct          = errors.New("products need a brand and a name of at most 100 characters")
+	ErrInvalidNutrition        = errors.New("nutrition per 100 g must not be negative, with at most 100 g of each nutrient and 900 kcal")
+	ErrProductNotForIngredient = errors.New("product must be one of the ingredient's products")
+
+	// Nutrition lookup through the external nutrition API (only recipe-catalogue uses these)
+	ErrInvalidNutritionQuery      = errors.New("query must be between 2 and 100 characters")
+	ErrNutritionLookupUnavailable = errors.New("nutrition lookup is not configured")
+	ErrNutritionLookupLimited     = errors.New("too many nutrition lookups, try again in a minute")
+	ErrNutritionSourceUnavailable = errors.New("the nutrition database could not be reached")
+
+	// Recipe import from a web page (only recipe-catalogue uses these)
+	ErrInvalidImportURL       = errors.New("url must be an http or https address")
+	ErrImportPageUnavailable  = errors.New("the page could not be fetched")
+	ErrNoRecipeOnPage         = errors.New("the page has no schema.org recipe")
+	ErrImportCategoryRequired = errors.New("category_id is required when the page's recipe category isn't one of the catalogue's")
 
 	// Recipe-Ingredient relationship (only recipe-catalogue uses these)
 	ErrRecipeIngredientAlreadyExists = errors.New("ingredient already added to this recipe")
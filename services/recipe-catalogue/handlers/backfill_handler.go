@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+// BackfillHandler lets admins start the data quality backfills and follow
+// their progress. The routes are mounted behind middleware.RequireAdmin.
+// Runs are remembered by the instance that runs them.
+type BackfillHandler struct {
+	backfillService service.BackfillService
+}
+
+func NewBackfillHandler(backfillService service.BackfillService) *BackfillHandler {
+	return &BackfillHandler{backfillService: backfillService}
+}
+
+// StartBackfill answers 202 straight away with the run to poll.
+func (h *BackfillHandler) StartBackfill(w http.ResponseWriter, r *http.Request) {
+	var req models.StartBackfillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	run, err := h.backfillService.StartBackfill(r.Context(), req.Job)
+	if err != nil {
+		writeBackfillError(w, err, "Failed to start backfill")
+		return
+	}
+
+	models.WriteSuccessResponse(w, run, http.StatusAccepted)
+}
+
+func (h *BackfillHandler) ListBackfills(w http.ResponseWriter, r *http.Request) {
+	models.WriteSuccessResponse(w, h.backfillService.ListBackfills(), http.StatusOK)
+}
+
+func (h *BackfillHandler) GetBackfill(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid backfill ID", http.StatusBadRequest)
+		return
+	}
+
+	run, err := h.backfillService.GetBackfill(id)
+	if err != nil {
+		writeBackfillError(w, err, "Failed to fetch backfill")
+		return
+	}
+
+	models.WriteSuccessResponse(w, run, http.StatusOK)
+}
+
+func writeBackfillError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrUnknownBackfillJob:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrBackfillNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	case domain.ErrBackfillRunning:
+		models.WriteErrorResponse(w, err.Error(), http.StatusConflict)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillHandler_StartBackfill(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		expected int
+	}{
+		{"started", `{"job":"normalize-units"}`, nil, http.StatusAccepted},
+		{"unknown job", `{"job":"vacuum"}`, domain.ErrUnknownBackfillJob, http.StatusBadRequest},
+		{"already running", `{"job":"normalize-units"}`, domain.ErrBackfillRunning, http.StatusConflict},
+		{"invalid JSON", `{"job":`, nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockBackfillService)
+			handler := NewBackfillHandler(mockService)
+			var req models.StartBackfillRequest
+			if json.Unmarshal([]byte(tt.body), &req) == nil {
+				if tt.err != nil {
+					mockService.On("StartBackfill", mock.Anything, req.Job).Return(nil, tt.err)
+				} else {
+					mockService.On("StartBackfill", mock.Anything, req.Job).Return(
+						&models.BackfillRun{ID: 1, Job: req.Job, Status: models.BackfillRunning}, nil)
+				}
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.StartBackfill(recorder, httptest.NewRequest("POST", "/backfills", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expected, recorder.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBackfillHandler_GetBackfill(t *testing.T) {
+	mockService := new(mocks.MockBackfillService)
+	handler := NewBackfillHandler(mockService)
+	mockService.On("GetBackfill", 3).Return(
+		&models.BackfillRun{ID: 3, Job: models.BackfillRecipeCosts, Status: models.BackfillRunning, Total: 40, Processed: 20}, nil)
+	mockService.On("GetBackfill", 4).Return(nil, domain.ErrBackfillNotFound)
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/backfills/3", nil), map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+	handler.GetBackfill(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var run models.BackfillRun
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &run))
+	assert.Equal(t, 20, run.Processed)
+	assert.Equal(t, 40, run.Total)
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/backfills/4", nil), map[string]string{"id": "4"})
+	recorder = httptest.NewRecorder()
+	handler.GetBackfill(recorder, req)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	mockService.AssertExpectations(t)
+}
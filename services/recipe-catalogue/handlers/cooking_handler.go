@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type CookingHandler struct {
+	cookingService service.CookingService
+}
+
+func NewCookingHandler(cookingService service.CookingService) *CookingHandler {
+	return &CookingHandler{cookingService: cookingService}
+}
+
+func (h *CookingHandler) GetRecipeSteps(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	steps, err := h.cookingService.GetRecipeSteps(recipeID)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch recipe steps", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, steps, http.StatusOK)
+}
+
+func (h *CookingHandler) SetRecipeSteps(w http.ResponseWriter, r *http.Request) {
+	_, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	var req []models.CreateStepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	steps, err := h.cookingService.SetRecipeSteps(r.Context(), recipeID, req)
+	if err != nil {
+		writeStepError(w, err, "Failed to save recipe steps")
+		return
+	}
+
+	models.WriteSuccessResponse(w, steps, http.StatusOK)
+}
+
+func (h *CookingHandler) AddRecipeStep(w http.ResponseWriter, r *http.Request) {
+	_, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateStepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	step, err := h.cookingService.AddRecipeStep(r.Context(), recipeID, req)
+	if err != nil {
+		writeStepError(w, err, "Failed to add recipe step")
+		return
+	}
+
+	models.WriteSuccessResponse(w, step, http.StatusCreated)
+}
+
+func (h *CookingHandler) UpdateRecipeStep(w http.ResponseWriter, r *http.Request) {
+	_, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	recipeID, stepID, ok := stepURLVars(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateStepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	step, err := h.cookingService.UpdateRecipeStep(r.Context(), recipeID, stepID, req)
+	if err != nil {
+		writeStepError(w, err, "Failed to update recipe step")
+		return
+	}
+
+	models.WriteSuccessResponse(w, step, http.StatusOK)
+}
+
+func (h *CookingHandler) DeleteRecipeStep(w http.ResponseWriter, r *http.Request) {
+	_, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	recipeID, stepID, ok := stepURLVars(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.cookingService.DeleteRecipeStep(r.Context(), recipeID, stepID); err != nil {
+		writeStepError(w, err, "Failed to delete recipe step")
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Step deleted successfully"}, http.StatusNoContent)
+}
+
+func (h *CookingHandler) ReorderRecipeSteps(w http.ResponseWriter, r *http.Request) {
+	_, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ReorderStepsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	steps, err := h.cookingService.ReorderRecipeSteps(r.Context(), recipeID, req.StepIDs)
+	if err != nil {
+		writeStepError(w, err, "Failed to reorder recipe steps")
+		return
+	}
+
+	models.WriteSuccessResponse(w, steps, http.StatusOK)
+}
+
+func (h *CookingHandler) GetRecipeTechniques(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	techniques, err := h.cookingService.GetRecipeTechniques(recipeID)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch recipe techniques", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, techniques, http.StatusOK)
+}
+
+func (h *CookingHandler) SetRecipeTechniques(w http.ResponseWriter, r *http.Request) {
+	_, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetTechniquesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	techniques, err := h.cookingService.SetRecipeTechniques(r.Context(), recipeID, req.Techniques)
+	if err != nil {
+		writeStepError(w, err, "Failed to save recipe techniques")
+		return
+	}
+
+	models.WriteSuccessResponse(w, techniques, http.StatusOK)
+}
+
+func stepURLVars(w http.ResponseWriter, r *http.Request) (recipeID, stepID int, ok bool) {
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["recipeId"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	stepID, err = strconv.Atoi(vars["stepId"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid step ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return recipeID, stepID, true
+}
+
+func writeStepError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrRecipeNotFound, domain.ErrStepNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	case domain.ErrForbidden:
+		models.WriteErrorResponse(w, err.Error(), http.StatusForbidden)
+	case domain.ErrStepInstructionRequired, domain.ErrInvalidStepDuration, domain.ErrStepIngredientNotInRecipe,
+		domain.ErrInvalidStepTimer, domain.ErrInvalidStepOrder, domain.ErrInvalidTechnique:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+func (h *CookingHandler) StartCookingSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	// The body is optional; without one the recipe is cooked as written
+	var req models.StartCookingSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	view, err := h.cookingService.StartSession(r.Context(), user.UserID, recipeID, req)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrInvalidScale:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to start cooking session", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, view, http.StatusCreated)
+}
+
+func (h *CookingHandler) GetCookingSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	view, err := h.cookingService.GetSession(r.Context(), user.UserID, mux.Vars(r)["token"])
+	if err != nil {
+		switch err {
+		case domain.ErrCookingSessionNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch cooking session", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, view, http.StatusOK)
+}
+
+func (h *CookingHandler) UpdateCookingSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdateCookingSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	view, err := h.cookingService.UpdateSession(r.Context(), user.UserID, mux.Vars(r)["token"], req)
+	if err != nil {
+		switch err {
+		case domain.ErrCookingSessionNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrInvalidCookingStep:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to update cooking session", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, view, http.StatusOK)
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type cookingHandlerTestSetup struct {
+	handler        *CookingHandler
+	cookingService *mocks.MockCookingService
+}
+
+func setupCookingHandlerTest() *cookingHandlerTestSetup {
+	mockService := new(mocks.MockCookingService)
+	handler := NewCookingHandler(mockService)
+
+	return &cookingHandlerTestSetup{
+		handler:        handler,
+		cookingService: mockService,
+	}
+}
+
+// =============================================================================
+// RECIPE STEP TESTS
+// =============================================================================
+
+func TestCookingHandler_SetRecipeSteps_Forbidden(t *testing.T) {
+	setup := setupCookingHandlerTest()
+	steps := []models.CreateStepRequest{{Instruction: "Boil the pasta"}}
+	setup.cookingService.On("SetRecipeSteps", 3, steps).Return(nil, domain.ErrForbidden)
+
+	body, _ := json.Marshal(steps)
+	req := httptest.NewRequest("PUT", "/recipes/3/steps", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetRecipeSteps(recorder, req)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	setup.cookingService.AssertExpectations(t)
+}
+
+func TestCookingHandler_SetRecipeSteps_Unauthenticated(t *testing.T) {
+	setup := setupCookingHandlerTest()
+
+	req := httptest.NewRequest("PUT", "/recipes/3/steps", bytes.NewBufferString("[]"))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetRecipeSteps(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	setup.cookingService.AssertNotCalled(t, "SetRecipeSteps", mock.Anything, mock.Anything)
+}
+
+func TestCookingHandler_AddRecipeStep_Created(t *testing.T) {
+	setup := setupCookingHandlerTest()
+	step := models.CreateStepRequest{Instruction: "Serve"}
+	setup.cookingService.On("AddRecipeStep", 3, step).
+		Return(&models.RecipeStep{ID: 12, RecipeID: 3, Position: 4, Instruction: "Serve"}, nil)
+
+	body, _ := json.Marshal(step)
+	req := httptest.NewRequest("POST", "/recipes/3/steps", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.AddRecipeStep(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var response models.RecipeStep
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, 4, response.Position)
+}
+
+func TestCookingHandler_UpdateRecipeStep_NotFound(t *testing.T) {
+	setup := setupCookingHandlerTest()
+	step := models.CreateStepRequest{Instruction: "Serve"}
+	setup.cookingService.On("UpdateRecipeStep", 3, 12, step).Return(nil, domain.ErrStepNotFound)
+
+	body, _ := json.Marshal(step)
+	req := httptest.NewRequest("PUT", "/recipes/3/steps/12", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"recipeId": "3", "stepId": "12"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UpdateRecipeStep(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestCookingHandler_DeleteRecipeStep(t *testing.T) {
+	setup := setupCookingHandlerTest()
+	setup.cookingService.On("DeleteRecipeStep", 3, 12).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/recipes/3/steps/12", nil)
+	req = mux.SetURLVars(req, map[string]string{"recipeId": "3", "stepId": "12"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.DeleteRecipeStep(recorder, req)
+
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	setup.cookingService.AssertExpectations(t)
+}
+
+func TestCookingHandler_ReorderRecipeSteps_InvalidOrder(t *testing.T) {
+	setup := setupCookingHandlerTest()
+	setup.cookingService.On("ReorderRecipeSteps", 3, []int{12, 10}).Return(nil, domain.ErrInvalidStepOrder)
+
+	req := httptest.NewRequest("PUT", "/recipes/3/steps/order", bytes.NewBufferString(`{"step_ids": [12, 10]}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.ReorderRecipeSteps(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestCookingHandler_SetRecipeTechniques_UnknownTechnique(t *testing.T) {
+	setup := setupCookingHandlerTest()
+	setup.cookingService.On("SetRecipeTechniques", 3, []string{"juggling"}).Return(nil, domain.ErrInvalidTechnique)
+
+	req := httptest.NewRequest("PUT", "/recipes/3/techniques", bytes.NewBufferString(`{"techniques": ["juggling"]}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetRecipeTechniques(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestCookingHandler_GetRecipeTechniques(t *testing.T) {
+	setup := setupCookingHandlerTest()
+	setup.cookingService.On("GetRecipeTechniques", 3).
+		Return(&models.RecipeTechniques{RecipeID: 3, Techniques: []string{"braising"}}, nil)
+
+	req := httptest.NewRequest("GET", "/recipes/3/techniques", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetRecipeTechniques(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response models.RecipeTechniques
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, []string{"braising"}, response.Techniques)
+}
+
+// =============================================================================
+// COOKING SESSION TESTS
+// =============================================================================
+
+func TestCookingHandler_StartCookingSession_WithoutBody(t *testing.T) {
+	setup := setupCookingHandlerTest()
+	view := &models.CookingSessionView{
+		CookingSession: models.CookingSession{Token: "token-1", UserID: 1, RecipeID: 3, Scale: 1},
+		RecipeName:     "Pasta",
+		Steps:          []models.CookingStep{{Number: 1, Instruction: "Gather the ingredients"}},
+	}
+	setup.cookingService.On("StartSession", 1, 3, models.StartCookingSessionRequest{}).Return(view, nil)
+
+	req := httptest.NewRequest("POST", "/recipes/3/cooking-sessions", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.StartCookingSession(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	var response models.CookingSessionView
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, "token-1", response.Token)
+	assert.Len(t, response.Steps, 1)
+}
+
+func TestCookingHandler_StartCookingSession_InvalidScale(t *testing.T) {
+	setup := setupCookingHandlerTest()
+	setup.cookingService.On("StartSession", 1, 3, models.StartCookingSessionRequest{Scale: 50}).
+		Return(nil, domain.ErrInvalidScale)
+
+	req := httptest.NewRequest("POST", "/recipes/3/cooking-sessions", bytes.NewBufferString(`{"scale": 50}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.StartCookingSession(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestCookingHandler_GetCookingSession_NotFound(t *testing.T) {
+	setup := setupCookingHandlerTest()
+	setup.cookingService.On("GetSession", 1, "token-1").Return(nil, domain.ErrCookingSessionNotFound)
+
+	req := httptest.NewRequest("GET", "/cooking-sessions/token-1", nil)
+	req = mux.SetURLVars(req, map[string]string{"token": "token-1"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetCookingSession(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestCookingHandler_UpdateCookingSession_StepOutOfRange(t *testing.T) {
+	setup := setupCookingHandlerTest()
+	setup.cookingService.On("UpdateSession", 1, "token-1", models.UpdateCookingSessionRequest{CurrentStep: 9}).
+		Return(nil, domain.ErrInvalidCookingStep)
+
+	req := httptest.NewRequest("PUT", "/cooking-sessions/token-1", bytes.NewBufferString(`{"current_step": 9}`))
+	req = mux.SetURLVars(req, map[string]string{"token": "token-1"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UpdateCookingSession(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	setup.cookingService.AssertExpectations(t)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type CostHandler struct {
+	costService service.CostService
+}
+
+func NewCostHandler(costService service.CostService) *CostHandler {
+	return &CostHandler{costService: costService}
+}
+
+// SetIngredientPrice is admin-only; see RegisterRoutes.
+func (h *CostHandler) SetIngredientPrice(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetIngredientPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	price, err := h.costService.SetIngredientPrice(user.UserID, id, req)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrInvalidPrice, domain.ErrInvalidQuantity, domain.ErrInvalidUnit:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to save ingredient price", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, price, http.StatusCreated)
+}
+
+func (h *CostHandler) GetIngredientPrices(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	prices, err := h.costService.GetIngredientPrices(id)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch ingredient prices", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, prices, http.StatusOK)
+}
+
+func (h *CostHandler) GetRecipeCostHistory(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.costService.GetRecipeCostHistory(recipeID)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch recipe cost history", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, history, http.StatusOK)
+}
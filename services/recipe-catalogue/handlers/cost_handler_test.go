@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type costHandlerTestSetup struct {
+	handler     *CostHandler
+	costService *mocks.MockCostService
+}
+
+func setupCostHandlerTest() *costHandlerTestSetup {
+	mockService := new(mocks.MockCostService)
+
+	return &costHandlerTestSetup{
+		handler:     NewCostHandler(mockService),
+		costService: mockService,
+	}
+}
+
+func TestCostHandler_SetIngredientPrice_Created(t *testing.T) {
+	setup := setupCostHandlerTest()
+	request := models.SetIngredientPriceRequest{Price: 1.29, Quantity: 400, Unit: "g"}
+	setup.costService.On("SetIngredientPrice", 1, 4, request).
+		Return(&models.IngredientPrice{ID: 1, IngredientID: 4, Price: 1.29, Quantity: 400, Unit: "g"}, nil)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("POST", "/ingredients/4/prices", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "4"})
+	req = test.AddAuthContext(req, 1, "admin@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetIngredientPrice(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	setup.costService.AssertExpectations(t)
+}
+
+func TestCostHandler_SetIngredientPrice_Invalid(t *testing.T) {
+	setup := setupCostHandlerTest()
+	request := models.SetIngredientPriceRequest{Price: -1, Quantity: 400, Unit: "g"}
+	setup.costService.On("SetIngredientPrice", 1, 4, request).Return(nil, domain.ErrInvalidPrice)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("POST", "/ingredients/4/prices", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "4"})
+	req = test.AddAuthContext(req, 1, "admin@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetIngredientPrice(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestCostHandler_GetRecipeCostHistory(t *testing.T) {
+	setup := setupCostHandlerTest()
+	change := 12.5
+	setup.costService.On("GetRecipeCostHistory", 3).Return(&models.RecipeCostHistory{
+		RecipeID:      3,
+		Snapshots:     []models.RecipeCostSnapshot{{RecipeID: 3, Cost: 4, PricedIngredients: 2, TotalIngredients: 2}},
+		ChangePercent: &change,
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/recipes/3/cost-history", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetRecipeCostHistory(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"change_percent":12.5`)
+}
+
+func TestCostHandler_GetRecipeCostHistory_NotFound(t *testing.T) {
+	setup := setupCostHandlerTest()
+	setup.costService.On("GetRecipeCostHistory", 3).Return(nil, domain.ErrRecipeNotFound)
+
+	req := httptest.NewRequest("GET", "/recipes/3/cost-history", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetRecipeCostHistory(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
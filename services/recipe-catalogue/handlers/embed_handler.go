@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// embedCacheAge is how long, in seconds, embeds and oEmbed responses may
+	// be cached. It is kept short so embedded cards follow recipe edits.
+	embedCacheAge = 300
+
+	// Default size of the embed iframe; oEmbed consumers can ask for less
+	defaultEmbedWidth  = 480
+	defaultEmbedHeight = 640
+)
+
+// embedURLPath matches the recipe URLs the oEmbed endpoint accepts: the
+// recipe itself or its embed.
+var embedURLPath = regexp.MustCompile(`^/(?:embed/)?recipes/([0-9]+)/?$`)
+
+// embedTemplate is a standalone page meant for an iframe. Styles are inline
+// so the card looks the same on every site, and links open outside the frame.
+var embedTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Name}}</title>
+<link rel="alternate" type="application/json+oembed" href="{{.OEmbedURL}}" title="{{.Name}}">
+<style>
+body{margin:0;font-family:system-ui,-apple-system,"Segoe UI",sans-serif;color:#262626;background:#faf7f2}
+.card{padding:16px}
+.card img{display:block;width:100%;max-height:240px;object-fit:cover;border-radius:6px}
+h1{font-size:1.4em;margin:12px 0 4px}
+h1 a{color:inherit;text-decoration:none}
+.details{color:#6e6e6e;margin:0 0 8px}
+h2{font-size:1em;margin:16px 0 4px}
+ul{margin:0;padding-left:20px}
+footer{margin-top:16px;font-size:.9em}
+footer a{color:#2e7d32}
+</style>
+</head>
+<body>
+<article class="card">
+{{- with .PhotoURL}}
+<img src="{{.}}" alt="{{$.Name}}">
+{{- end}}
+<h1><a href="{{.RecipeURL}}" target="_blank" rel="noopener">{{.Name}}</a></h1>
+{{- with .Details}}
+<p class="details">{{.}}</p>
+{{- end}}
+{{- with .Description}}
+<p>{{.}}</p>
+{{- end}}
+{{- if .Ingredients}}
+<h2>Ingredients</h2>
+<ul>
+{{- range .Ingredients}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+<footer><a href="{{.RecipeURL}}" target="_blank" rel="noopener">View the full recipe on meal-prep</a></footer>
+</article>
+</body>
+</html>
+`))
+
+type embedPage struct {
+	Name        string
+	Description string
+	Details     string
+	PhotoURL    string
+	Ingredients []string
+	RecipeURL   string
+	OEmbedURL   string
+}
+
+type EmbedHandler struct {
+	embedService service.EmbedService
+	baseURL      string
+}
+
+func NewEmbedHandler(embedService service.EmbedService, baseURL string) *EmbedHandler {
+	return &EmbedHandler{embedService: embedService, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// GetRecipeEmbed renders the recipe card other sites put in an iframe. The
+// ETag lets embedding pages revalidate cheaply once the cache expires.
+func (h *EmbedHandler) GetRecipeEmbed(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	embed, err := h.embedService.GetRecipeEmbed(recipeID)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to build recipe embed", http.StatusInternalServerError)
+		}
+		return
+	}
+	localizeRecipeIngredients(r.Context(), embed.Recipe.Ingredients)
+
+	var buf bytes.Buffer
+	if err := embedTemplate.Execute(&buf, h.embedPage(embed)); err != nil {
+		models.WriteErrorResponse(w, "Failed to render recipe embed", http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", embedCacheAge))
+	if models.NotModified(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; img-src http: https:; style-src 'unsafe-inline'; frame-ancestors *")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// GetOEmbed is the oEmbed provider endpoint. It takes the URL of a recipe or
+// its embed and answers with an iframe of the embed; only JSON is offered.
+func (h *EmbedHandler) GetOEmbed(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if format := query.Get("format"); format != "" && format != "json" {
+		models.WriteErrorResponse(w, "Only the json format is supported", http.StatusNotImplemented)
+		return
+	}
+
+	rawURL := query.Get("url")
+	if rawURL == "" {
+		models.WriteErrorResponse(w, "url is required", http.StatusBadRequest)
+		return
+	}
+	recipeID, ok := h.recipeIDFromURL(rawURL)
+	if !ok {
+		models.WriteErrorResponse(w, domain.ErrRecipeNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	width, height := defaultEmbedWidth, defaultEmbedHeight
+	maxWidth, err := optionalPositiveInt(query.Get("maxwidth"))
+	if err != nil {
+		models.WriteErrorResponse(w, "maxwidth must be a positive number", http.StatusBadRequest)
+		return
+	}
+	maxHeight, err := optionalPositiveInt(query.Get("maxheight"))
+	if err != nil {
+		models.WriteErrorResponse(w, "maxheight must be a positive number", http.StatusBadRequest)
+		return
+	}
+	if maxWidth > 0 {
+		width = min(width, maxWidth)
+	}
+	if maxHeight > 0 {
+		height = min(height, maxHeight)
+	}
+
+	embed, err := h.embedService.GetRecipeEmbed(recipeID)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to build recipe embed", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	name := embed.Recipe.Name
+	response := models.OEmbedResponse{
+		Type:         "rich",
+		Version:      "1.0",
+		Title:        name,
+		ProviderName: "meal-prep",
+		ProviderURL:  h.baseURL,
+		CacheAge:     embedCacheAge,
+		HTML: fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" title="%s" style="border:0;max-width:100%%" loading="lazy"></iframe>`,
+			html.EscapeString(h.embedURL(recipeID)), width, height, html.EscapeString(name)),
+		Width:  width,
+		Height: height,
+	}
+	// The share card is the thumbnail, unless it is larger than allowed
+	if (maxWidth == 0 || maxWidth >= shareCardWidth) && (maxHeight == 0 || maxHeight >= shareCardHeight) {
+		response.ThumbnailURL = h.recipeURL(recipeID) + "/card.png"
+		response.ThumbnailWidth = shareCardWidth
+		response.ThumbnailHeight = shareCardHeight
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", embedCacheAge))
+	models.WriteSuccessResponse(w, response, http.StatusOK)
+}
+
+func (h *EmbedHandler) embedPage(embed *models.RecipeEmbed) embedPage {
+	recipe := embed.Recipe
+	page := embedPage{
+		Name:        recipe.Name,
+		Details:     cardDetails(recipe.Recipe),
+		Ingredients: make([]string, 0, len(recipe.Ingredients)),
+		RecipeURL:   h.recipeURL(recipe.ID),
+		OEmbedURL:   h.baseURL + "/oembed?url=" + url.QueryEscape(h.recipeURL(recipe.ID)),
+	}
+	if recipe.Description != nil {
+		page.Description = *recipe.Description
+	}
+	if embed.CoverImage != nil {
+		page.PhotoURL = embed.CoverImage.URL
+	}
+	for _, ingredient := range recipe.Ingredients {
+		line := strings.TrimSpace(strconv.FormatFloat(ingredient.Quantity, 'f', -1, 64) + " " + ingredient.Unit + " " + ingredient.Ingredient.Name)
+		if ingredient.Notes != nil && *ingredient.Notes != "" {
+			line += " (" + *ingredient.Notes + ")"
+		}
+		page.Ingredients = append(page.Ingredients, line)
+	}
+	return page
+}
+
+// recipeIDFromURL accepts recipe and embed URLs on this site's host.
+func (h *EmbedHandler) recipeIDFromURL(rawURL string) (int, bool) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, false
+	}
+	base, err := url.Parse(h.baseURL)
+	if err != nil || !strings.EqualFold(parsed.Host, base.Host) {
+		return 0, false
+	}
+
+	path := strings.TrimPrefix(parsed.Path, strings.TrimRight(base.Path, "/"))
+	match := embedURLPath.FindStringSubmatch(path)
+	if match == nil {
+		return 0, false
+	}
+	id, err := strconv.Atoi(match[1])
+	return id, err == nil
+}
+
+func (h *EmbedHandler) recipeURL(id int) string {
+	return fmt.Sprintf("%s/recipes/%d", h.baseURL, id)
+}
+
+func (h *EmbedHandler) embedURL(id int) string {
+	return fmt.Sprintf("%s/embed/recipes/%d", h.baseURL, id)
+}
+
+// optionalPositiveInt parses an optional query parameter; empty is 0.
+func optionalPositiveInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid positive number %q", value)
+	}
+	return n, nil
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type embedHandlerTestSetup struct {
+	handler      *EmbedHandler
+	embedService *mocks.MockEmbedService
+}
+
+func setupEmbedHandlerTest() *embedHandlerTestSetup {
+	mockService := new(mocks.MockEmbedService)
+
+	return &embedHandlerTestSetup{
+		handler:      NewEmbedHandler(mockService, "https://meals.example/"),
+		embedService: mockService,
+	}
+}
+
+func sampleRecipeEmbed() *models.RecipeEmbed {
+	description := "Weeknight <quick> curry"
+	minutes := 35
+	difficulty := "easy"
+	notes := "rinsed"
+	return &models.RecipeEmbed{
+		Recipe: models.RecipeWithIngredients{
+			Recipe: models.Recipe{
+				ID: 3, Name: "Chickpea curry", Description: &description,
+				TotalTimeMinutes: &minutes, Difficulty: &difficulty,
+			},
+			Ingredients: []models.RecipeIngredient{
+				{Ingredient: models.Ingredient{Name: "Chickpeas"}, Quantity: 400, Unit: "g", Notes: &notes},
+				{Ingredient: models.Ingredient{Name: "Coconut milk"}, Quantity: 0.5, Unit: "can"},
+			},
+		},
+		CoverImage: &models.RecipeImage{ID: 11, URL: "https://img.example/curry.jpg"},
+	}
+}
+
+func TestEmbedHandler_GetRecipeEmbed(t *testing.T) {
+	setup := setupEmbedHandlerTest()
+	setup.embedService.On("GetRecipeEmbed", 3).Return(sampleRecipeEmbed(), nil)
+
+	req := httptest.NewRequest("GET", "/embed/recipes/3", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetRecipeEmbed(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/html; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=300", recorder.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, recorder.Header().Get("ETag"))
+
+	body := recorder.Body.String()
+	assert.Contains(t, body, `<a href="https://meals.example/recipes/3" target="_blank" rel="noopener">Chickpea curry</a>`)
+	assert.Contains(t, body, `<img src="https://img.example/curry.jpg" alt="Chickpea curry">`)
+	assert.Contains(t, body, "35 min / Easy")
+	assert.Contains(t, body, "Weeknight &lt;quick&gt; curry")
+	assert.Contains(t, body, "<li>400 g Chickpeas (rinsed)</li>")
+	assert.Contains(t, body, "<li>0.5 can Coconut milk</li>")
+	assert.Contains(t, body, `href="https://meals.example/oembed?url=https%3A%2F%2Fmeals.example%2Frecipes%2F3"`)
+}
+
+func TestEmbedHandler_GetRecipeEmbed_NotModified(t *testing.T) {
+	setup := setupEmbedHandlerTest()
+	setup.embedService.On("GetRecipeEmbed", 3).Return(sampleRecipeEmbed(), nil)
+
+	first := httptest.NewRecorder()
+	setup.handler.GetRecipeEmbed(first, mux.SetURLVars(httptest.NewRequest("GET", "/embed/recipes/3", nil), map[string]string{"id": "3"}))
+	etag := first.Header().Get("ETag")
+
+	req := httptest.NewRequest("GET", "/embed/recipes/3", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req.Header.Set("If-None-Match", etag)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetRecipeEmbed(recorder, req)
+
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+}
+
+func TestEmbedHandler_GetRecipeEmbed_NotFound(t *testing.T) {
+	setup := setupEmbedHandlerTest()
+	setup.embedService.On("GetRecipeEmbed", 9).Return(nil, domain.ErrRecipeNotFound)
+
+	req := httptest.NewRequest("GET", "/embed/recipes/9", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetRecipeEmbed(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestEmbedHandler_GetOEmbed(t *testing.T) {
+	setup := setupEmbedHandlerTest()
+	setup.embedService.On("GetRecipeEmbed", 3).Return(sampleRecipeEmbed(), nil)
+
+	req := httptest.NewRequest("GET", "/oembed?url=https%3A%2F%2Fmeals.example%2Frecipes%2F3&format=json", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetOEmbed(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response models.OEmbedResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, models.OEmbedResponse{
+		Type:            "rich",
+		Version:         "1.0",
+		Title:           "Chickpea curry",
+		ProviderName:    "meal-prep",
+		ProviderURL:     "https://meals.example",
+		CacheAge:        300,
+		HTML:            `<iframe src="https://meals.example/embed/recipes/3" width="480" height="640" title="Chickpea curry" style="border:0;max-width:100%" loading="lazy"></iframe>`,
+		Width:           480,
+		Height:          640,
+		ThumbnailURL:    "https://meals.example/recipes/3/card.png",
+		ThumbnailWidth:  1200,
+		ThumbnailHeight: 630,
+	}, response)
+}
+
+func TestEmbedHandler_GetOEmbed_MaxSize(t *testing.T) {
+	setup := setupEmbedHandlerTest()
+	setup.embedService.On("GetRecipeEmbed", 3).Return(sampleRecipeEmbed(), nil)
+
+	req := httptest.NewRequest("GET", "/oembed?url=https://meals.example/embed/recipes/3&maxwidth=320&maxheight=900", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetOEmbed(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response models.OEmbedResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, 320, response.Width)
+	assert.Equal(t, 640, response.Height)
+	assert.Empty(t, response.ThumbnailURL)
+}
+
+func TestEmbedHandler_GetOEmbed_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"xml format", "?url=https://meals.example/recipes/3&format=xml", http.StatusNotImplemented},
+		{"missing url", "", http.StatusBadRequest},
+		{"other host", "?url=https://elsewhere.example/recipes/3", http.StatusNotFound},
+		{"not a recipe", "?url=https://meals.example/ingredients/3", http.StatusNotFound},
+		{"invalid maxwidth", "?url=https://meals.example/recipes/3&maxwidth=wide", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupEmbedHandlerTest()
+
+			recorder := httptest.NewRecorder()
+			setup.handler.GetOEmbed(recorder, httptest.NewRequest("GET", "/oembed"+tt.query, nil))
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			setup.embedService.AssertNotCalled(t, "GetRecipeEmbed")
+		})
+	}
+}
+
+func TestEmbedHandler_GetOEmbed_HiddenRecipe(t *testing.T) {
+	setup := setupEmbedHandlerTest()
+	setup.embedService.On("GetRecipeEmbed", 4).Return(nil, domain.ErrRecipeNotFound)
+
+	req := httptest.NewRequest("GET", "/oembed?url=https://meals.example/recipes/4", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetOEmbed(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
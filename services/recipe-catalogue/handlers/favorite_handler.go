@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type FavoriteHandler struct {
+	favoriteService service.FavoriteService
+}
+
+func NewFavoriteHandler(favoriteService service.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{favoriteService: favoriteService}
+}
+
+func (h *FavoriteHandler) AddFavorite(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	recipeID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.favoriteService.AddFavorite(r.Context(), user.UserID, recipeID)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to add favorite", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Recipe added to favorites"}, http.StatusOK)
+}
+
+func (h *FavoriteHandler) RemoveFavorite(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	recipeID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.favoriteService.RemoveFavorite(user.UserID, recipeID)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFavorite:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to remove favorite", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Recipe removed from favorites"}, http.StatusNoContent)
+}
+
+func (h *FavoriteHandler) GetMyFavorites(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	recipes, meta, err := h.favoriteService.GetFavorites(user.UserID, params)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch favorites", http.StatusInternalServerError)
+		return
+	}
+
+	models.WritePaginatedResponse(w, recipes, meta, http.StatusOK)
+}
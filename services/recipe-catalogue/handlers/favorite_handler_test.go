@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type favoriteHandlerTestSetup struct {
+	handler         *FavoriteHandler
+	favoriteService *mocks.MockFavoriteService
+}
+
+func setupFavoriteHandlerTest() *favoriteHandlerTestSetup {
+	mockService := new(mocks.MockFavoriteService)
+
+	return &favoriteHandlerTestSetup{
+		handler:         NewFavoriteHandler(mockService),
+		favoriteService: mockService,
+	}
+}
+
+func TestFavoriteHandler_AddFavorite_Success(t *testing.T) {
+	setup := setupFavoriteHandlerTest()
+	setup.favoriteService.On("AddFavorite", 1, 9).Return(nil)
+
+	req := httptest.NewRequest("POST", "/recipes/9/favorite", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.AddFavorite(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	setup.favoriteService.AssertExpectations(t)
+}
+
+func TestFavoriteHandler_AddFavorite_RecipeNotFound(t *testing.T) {
+	setup := setupFavoriteHandlerTest()
+	setup.favoriteService.On("AddFavorite", 1, 9).Return(domain.ErrRecipeNotFound)
+
+	req := httptest.NewRequest("POST", "/recipes/9/favorite", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.AddFavorite(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestFavoriteHandler_RemoveFavorite_NotFavorite(t *testing.T) {
+	setup := setupFavoriteHandlerTest()
+	setup.favoriteService.On("RemoveFavorite", 1, 9).Return(domain.ErrNotFavorite)
+
+	req := httptest.NewRequest("DELETE", "/recipes/9/favorite", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.RemoveFavorite(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestFavoriteHandler_GetMyFavorites_Paginated(t *testing.T) {
+	setup := setupFavoriteHandlerTest()
+	params := models.PaginationParams{Page: 2, PerPage: 5}
+	count := 3
+	favorites := []models.Recipe{{ID: 9, UserID: 2, Name: "Shakshuka", FavoriteCount: &count}}
+	setup.favoriteService.On("GetFavorites", 1, params).Return(favorites, models.NewPaginationMeta(params, 6), nil)
+
+	req := httptest.NewRequest("GET", "/users/me/favorites?page=2&per_page=5", nil)
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetMyFavorites(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data       []models.Recipe       `json:"data"`
+		Pagination models.PaginationMeta `json:"pagination"`
+	}
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, favorites, response.Data)
+	assert.Equal(t, 2, response.Pagination.TotalPages)
+}
+
+func TestFavoriteHandler_GetMyFavorites_Unauthenticated(t *testing.T) {
+	setup := setupFavoriteHandlerTest()
+
+	req := httptest.NewRequest("GET", "/users/me/favorites", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetMyFavorites(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	setup.favoriteService.AssertNotCalled(t, "GetFavorites")
+}
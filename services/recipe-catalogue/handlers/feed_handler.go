@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+const defaultPublicBaseURL = "http://localhost:8000"
+
+// PublicBaseURLFromEnv reads PUBLIC_BASE_URL, the address the API gateway is
+// reached at from outside. Sitemaps and feeds need absolute links.
+func PublicBaseURLFromEnv() string {
+	if value := strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/"); value != "" {
+		return value
+	}
+	return defaultPublicBaseURL
+}
+
+type FeedHandler struct {
+	feedService service.FeedService
+	baseURL     string
+}
+
+func NewFeedHandler(feedService service.FeedService, baseURL string) *FeedHandler {
+	return &FeedHandler{feedService: feedService, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name       `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name       `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapEntry `xml:"url"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title     string        `xml:"title"`
+	ID        string        `xml:"id"`
+	Link      atomLink      `xml:"link"`
+	Published string        `xml:"published"`
+	Updated   string        `xml:"updated"`
+	Summary   string        `xml:"summary,omitempty"`
+	Category  *atomCategory `xml:"category,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// GetSitemapIndex lists the recipe sitemaps, each covering up to
+// service.SitemapChunkSize recipes.
+func (h *FeedHandler) GetSitemapIndex(w http.ResponseWriter, r *http.Request) {
+	chunks, err := h.feedService.GetSitemapIndex()
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to build sitemap", http.StatusInternalServerError)
+		return
+	}
+
+	index := sitemapIndex{Sitemaps: make([]sitemapEntry, 0, len(chunks))}
+	for _, chunk := range chunks {
+		index.Sitemaps = append(index.Sitemaps, sitemapEntry{
+			Loc:     fmt.Sprintf("%s/sitemaps/recipes-%d.xml", h.baseURL, chunk.Number),
+			LastMod: w3cTime(chunk.LastModified),
+		})
+	}
+	writeXML(w, "application/xml; charset=utf-8", index)
+}
+
+func (h *FeedHandler) GetSitemapChunk(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.Atoi(mux.Vars(r)["number"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid sitemap number", http.StatusBadRequest)
+		return
+	}
+
+	recipes, err := h.feedService.GetSitemapChunk(number)
+	if err != nil {
+		switch err {
+		case domain.ErrSitemapChunkNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to build sitemap", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	urlSet := sitemapURLSet{URLs: make([]sitemapEntry, 0, len(recipes))}
+	for _, recipe := range recipes {
+		urlSet.URLs = append(urlSet.URLs, sitemapEntry{Loc: h.recipeURL(recipe.ID), LastMod: w3cTime(recipe.UpdatedAt)})
+	}
+	writeXML(w, "application/xml; charset=utf-8", urlSet)
+}
+
+// GetRecipeFeed is an Atom feed of the most recently updated public recipes.
+func (h *FeedHandler) GetRecipeFeed(w http.ResponseWriter, r *http.Request) {
+	recipes, err := h.feedService.GetRecipeFeed()
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to build feed", http.StatusInternalServerError)
+		return
+	}
+
+	self := h.baseURL + "/feeds/recipes.atom"
+	feed := atomFeed{
+		Title:   "meal-prep recipes",
+		ID:      self,
+		Updated: w3cTime(time.Now()),
+		Links:   []atomLink{{Rel: "self", Href: self}, {Href: h.baseURL + "/recipes"}},
+		Author:  atomAuthor{Name: "meal-prep"},
+		Entries: make([]atomEntry, 0, len(recipes)),
+	}
+	if len(recipes) > 0 {
+		// Newest first, so the first entry is the latest change
+		feed.Updated = w3cTime(recipes[0].UpdatedAt)
+	}
+	for _, recipe := range recipes {
+		entry := atomEntry{
+			Title:     recipe.Name,
+			ID:        h.recipeURL(recipe.ID),
+			Link:      atomLink{Href: h.recipeURL(recipe.ID)},
+			Published: w3cTime(recipe.CreatedAt),
+			Updated:   w3cTime(recipe.UpdatedAt),
+		}
+		if recipe.Description != nil {
+			entry.Summary = *recipe.Description
+		}
+		if recipe.Category != nil {
+			entry.Category = &atomCategory{Term: recipe.Category.Name}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	writeXML(w, "application/atom+xml; charset=utf-8", feed)
+}
+
+func (h *FeedHandler) recipeURL(id int) string {
+	return fmt.Sprintf("%s/recipes/%d", h.baseURL, id)
+}
+
+// w3cTime formats t the way sitemaps and Atom expect; the zero time is left
+// empty.
+func w3cTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// writeXML encodes v before writing anything, so a failure still gets a
+// proper error response. The service caches for a few minutes; clients may
+// cache as long.
+func writeXML(w http.ResponseWriter, contentType string, v any) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		models.WriteErrorResponse(w, "Failed to encode XML", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=600")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type feedHandlerTestSetup struct {
+	handler     *FeedHandler
+	feedService *mocks.MockFeedService
+}
+
+func setupFeedHandlerTest() *feedHandlerTestSetup {
+	mockService := new(mocks.MockFeedService)
+
+	return &feedHandlerTestSetup{
+		handler:     NewFeedHandler(mockService, "https://meals.example/"),
+		feedService: mockService,
+	}
+}
+
+func TestFeedHandler_GetSitemapIndex(t *testing.T) {
+	setup := setupFeedHandlerTest()
+	modified := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+	setup.feedService.On("GetSitemapIndex").Return([]models.SitemapChunk{
+		{Number: 1, LastModified: modified},
+		{Number: 2},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/sitemap.xml", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetSitemapIndex(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+
+	var index sitemapIndex
+	require.NoError(t, xml.Unmarshal(recorder.Body.Bytes(), &index))
+	assert.Equal(t, []sitemapEntry{
+		{Loc: "https://meals.example/sitemaps/recipes-1.xml", LastMod: "2026-10-01T09:30:00Z"},
+		{Loc: "https://meals.example/sitemaps/recipes-2.xml"},
+	}, index.Sitemaps)
+}
+
+func TestFeedHandler_GetSitemapChunk(t *testing.T) {
+	setup := setupFeedHandlerTest()
+	modified := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+	setup.feedService.On("GetSitemapChunk", 1).Return([]models.PublishedRecipe{{ID: 7, UpdatedAt: modified}}, nil)
+
+	req := httptest.NewRequest("GET", "/sitemaps/recipes-1.xml", nil)
+	req = mux.SetURLVars(req, map[string]string{"number": "1"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetSitemapChunk(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var urlSet sitemapURLSet
+	require.NoError(t, xml.Unmarshal(recorder.Body.Bytes(), &urlSet))
+	assert.Equal(t, []sitemapEntry{{Loc: "https://meals.example/recipes/7", LastMod: "2026-10-01T09:30:00Z"}}, urlSet.URLs)
+}
+
+func TestFeedHandler_GetSitemapChunk_NotFound(t *testing.T) {
+	setup := setupFeedHandlerTest()
+	setup.feedService.On("GetSitemapChunk", 9).Return(nil, domain.ErrSitemapChunkNotFound)
+
+	req := httptest.NewRequest("GET", "/sitemaps/recipes-9.xml", nil)
+	req = mux.SetURLVars(req, map[string]string{"number": "9"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetSitemapChunk(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestFeedHandler_GetRecipeFeed(t *testing.T) {
+	setup := setupFeedHandlerTest()
+	created := time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC)
+	updated := time.Date(2026, 10, 2, 8, 0, 0, 0, time.UTC)
+	description := "Weeknight curry <quick>"
+	setup.feedService.On("GetRecipeFeed").Return([]models.Recipe{{
+		ID: 3, Name: "Chickpea curry", Description: &description,
+		Category: &models.Category{Name: "Vegetarian"}, CreatedAt: created, UpdatedAt: updated,
+	}}, nil)
+
+	req := httptest.NewRequest("GET", "/feeds/recipes.atom", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetRecipeFeed(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "Weeknight curry &lt;quick&gt;")
+
+	var feed atomFeed
+	require.NoError(t, xml.Unmarshal(recorder.Body.Bytes(), &feed))
+	assert.Equal(t, "https://meals.example/feeds/recipes.atom", feed.ID)
+	assert.Equal(t, "2026-10-02T08:00:00Z", feed.Updated)
+	require.Len(t, feed.Entries, 1)
+	assert.Equal(t, atomEntry{
+		Title:     "Chickpea curry",
+		ID:        "https://meals.example/recipes/3",
+		Link:      atomLink{Href: "https://meals.example/recipes/3"},
+		Published: "2026-09-01T08:00:00Z",
+		Updated:   "2026-10-02T08:00:00Z",
+		Summary:   description,
+		Category:  &atomCategory{Term: "Vegetarian"},
+	}, feed.Entries[0])
+}
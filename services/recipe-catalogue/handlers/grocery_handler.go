@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"meal-prep/services/recipe-catalogue/domain"
 	"meal-prep/services/recipe-catalogue/service"
 	"meal-prep/shared/middleware"
 	"meal-prep/shared/models"
 	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
 )
 
 type GroceryHandler struct {
@@ -23,7 +27,6 @@ func (h *GroceryHandler) GenerateGroceryList(w http.ResponseWriter, r *http.Requ
 		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
 		return
 	}
-	_ = user
 
 	var req models.GroceryListRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -31,21 +34,239 @@ func (h *GroceryHandler) GenerateGroceryList(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if len(req.RecipeIDs) == 0 {
-		models.WriteErrorResponse(w, "At least one recipe ID is required", http.StatusBadRequest)
+	if len(req.RecipeIDs) == 0 && req.MealPlanID == nil && req.From == "" && req.To == "" {
+		models.WriteErrorResponse(w, "Recipe IDs, a meal plan ID or a date range is required", http.StatusBadRequest)
+		return
+	}
+	req.MeasurementSystem = measurementSystemFrom(r.Context())
+
+	if req.Budget != nil {
+		h.generateBudgetedGroceryList(w, r, user.UserID, req)
 		return
 	}
 
-	groceryList, err := h.groceryService.GenerateGroceryList(req)
+	groceryList, err := h.groceryService.GenerateGroceryList(r.Context(), user.UserID, req)
+	if err != nil {
+		writeGroceryListError(w, err)
+		return
+	}
+	localizeGroceryItems(r.Context(), groceryList)
+
+	if r.URL.Query().Get("format") == "text" {
+		writeGroceryListText(w, groceryList, middleware.LocaleFromContext(r.Context()))
+		return
+	}
+
+	models.WriteSuccessResponse(w, groceryList, http.StatusOK)
+}
+
+func (h *GroceryHandler) generateBudgetedGroceryList(w http.ResponseWriter, r *http.Request, userID int, req models.GroceryListRequest) {
+	budgeted, err := h.groceryService.GenerateBudgetedGroceryList(r.Context(), userID, req)
+	if err != nil {
+		writeGroceryListError(w, err)
+		return
+	}
+	localizeGroceryItems(r.Context(), budgeted.Items)
+
+	if r.URL.Query().Get("format") == "text" {
+		locale := middleware.LocaleFromContext(r.Context())
+		writeGroceryListText(w, budgeted.Items, locale)
+		writeBudgetText(w, budgeted, locale)
+		return
+	}
+
+	models.WriteSuccessResponse(w, budgeted, http.StatusOK)
+}
+
+func writeGroceryListError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrRecipeNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrStoreLayoutNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrInvalidBudget, domain.ErrInvalidGrocerySource, domain.ErrInvalidGroceryDateRange:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrMealPlanNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		models.WriteErrorResponse(w, "Failed to generate grocery list", http.StatusInternalServerError)
+	}
+}
+
+// writeGroceryListText renders the list as plain text with a heading per
+// category, keeping the order the service returned (the store's aisle order
+// when a layout was selected).
+func writeGroceryListText(w http.ResponseWriter, items []models.GroceryListItem, locale string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	heading := ""
+	for i, item := range items {
+		category := groceryCategory(item)
+		if i == 0 || category != heading {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintln(w, category)
+			heading = category
+		}
+		fmt.Fprintln(w, "- "+groceryItemText(item, locale))
+	}
+}
+
+// groceryCategory is the heading an item is listed under.
+func groceryCategory(item models.GroceryListItem) string {
+	if item.Ingredient.Category != nil && *item.Ingredient.Category != "" {
+		return *item.Ingredient.Category
+	}
+	return "Other"
+}
+
+// groceryItemText describes an item with its total, written the way locale
+// writes numbers, and purchase hint.
+func groceryItemText(item models.GroceryListItem, locale string) string {
+	line := item.Ingredient.Name
+	if item.TotalQuantity >= 0 {
+		line = middleware.FormatNumber(locale, item.TotalQuantity, -1) + " " + item.Unit + " " + line
+	} else {
+		line += " (mixed units, check recipes)"
+	}
+	if item.Purchase != nil {
+		line += " - " + item.Purchase.Note
+	}
+	return line
+}
+
+// writeBudgetText follows the text grocery list with its estimated total and
+// the suggested swaps.
+func writeBudgetText(w http.ResponseWriter, list *models.BudgetedGroceryList, locale string) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Estimated total: %s (budget %s)\n",
+		middleware.FormatNumber(locale, list.EstimatedTotal, 2), middleware.FormatNumber(locale, list.Budget, 2))
+	if list.UnpricedItems > 0 {
+		fmt.Fprintf(w, "%d items have no price and are not included\n", list.UnpricedItems)
+	}
+	for _, suggestion := range list.Suggestions {
+		fmt.Fprintf(w, "- Swap %s for %s %s %s to save %s\n", suggestion.Ingredient,
+			middleware.FormatNumber(locale, suggestion.Quantity, -1), suggestion.Unit, suggestion.Substitute,
+			middleware.FormatNumber(locale, suggestion.Saving, 2))
+	}
+	if len(list.Suggestions) > 0 {
+		fmt.Fprintf(w, "With these swaps: %s\n", middleware.FormatNumber(locale, list.EstimatedTotalWithSuggestions, 2))
+	}
+}
+
+func (h *GroceryHandler) GetStoreLayouts(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	layouts, err := h.groceryService.GetStoreLayouts(user.UserID)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch store layouts", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, layouts, http.StatusOK)
+}
+
+func (h *GroceryHandler) CreateStoreLayout(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.StoreLayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	layout, err := h.groceryService.CreateStoreLayout(user.UserID, req)
 	if err != nil {
 		switch err {
-		case domain.ErrRecipeNotFound:
+		case domain.ErrStoreLayoutNameRequired:
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidStoreLayout:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrStoreLayoutExists:
+			models.WriteErrorResponse(w, err.Error(), http.StatusConflict)
 		default:
-			models.WriteErrorResponse(w, "Failed to generate grocery list", http.StatusInternalServerError)
+			models.WriteErrorResponse(w, "Failed to create store layout", http.StatusInternalServerError)
 		}
 		return
 	}
 
-	models.WriteSuccessResponse(w, groceryList, http.StatusOK)
+	models.WriteSuccessResponse(w, layout, http.StatusCreated)
+}
+
+func (h *GroceryHandler) UpdateStoreLayout(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid store layout ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.StoreLayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	layout, err := h.groceryService.UpdateStoreLayout(r.Context(), user.UserID, id, req)
+	if err != nil {
+		switch err {
+		case domain.ErrStoreLayoutNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrStoreLayoutNameRequired:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidStoreLayout:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrStoreLayoutExists:
+			models.WriteErrorResponse(w, err.Error(), http.StatusConflict)
+		default:
+			models.WriteErrorResponse(w, "Failed to update store layout", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, layout, http.StatusOK)
+}
+
+func (h *GroceryHandler) DeleteStoreLayout(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid store layout ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.groceryService.DeleteStoreLayout(r.Context(), user.UserID, id)
+	if err != nil {
+		switch err {
+		case domain.ErrStoreLayoutNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to delete store layout", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Store layout deleted successfully"}, http.StatusNoContent)
 }
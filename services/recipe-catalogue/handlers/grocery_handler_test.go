@@ -3,14 +3,16 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"meal-prep/services/recipe-catalogue/domain"
 	"meal-prep/services/recipe-catalogue/handlers/mocks"
-	"meal-prep/services/recipe-catalogue/service/testdata"
 	"meal-prep/services/recipe-catalogue/test"
 	"meal-prep/shared/models"
+	"meal-prep/shared/testing/factory"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -40,14 +42,14 @@ func TestIngredientHandler_GenerateGroceryList_Success(t *testing.T) {
 	expectedGroceryList := []models.GroceryListItem{
 		{
 			IngredientID:  1,
-			Ingredient:    testdata.ValidIngredient(),
+			Ingredient:    factory.ValidIngredient(),
 			TotalQuantity: 150.0,
 			Unit:          "grams",
 			Recipes:       []string{"Recipe 1", "Recipe 2"},
 		},
 	}
 
-	setup.groceryService.On("GenerateGroceryList", mock.AnythingOfType("models.GroceryListRequest")).
+	setup.groceryService.On("GenerateGroceryList", 1, mock.AnythingOfType("models.GroceryListRequest")).
 		Return(expectedGroceryList, nil)
 
 	requestBody, _ := json.Marshal(request)
@@ -71,6 +73,52 @@ func TestIngredientHandler_GenerateGroceryList_Success(t *testing.T) {
 	setup.groceryService.AssertExpectations(t)
 }
 
+func TestGroceryHandler_GenerateGroceryList_WithBudget(t *testing.T) {
+	setup := setupGroceryHandlerTest()
+	budget := 20.0
+	cost := 2.5
+	budgeted := &models.BudgetedGroceryList{
+		Items:          []models.GroceryListItem{{IngredientID: 1, Ingredient: models.Ingredient{Name: "Butter"}, TotalQuantity: 250, Unit: "g", EstimatedCost: &cost}},
+		Budget:         budget,
+		EstimatedTotal: 24.10,
+		Suggestions: []models.SubstitutionSuggestion{
+			{IngredientID: 1, Ingredient: "Butter", SubstituteID: 9, Substitute: "Margarine", Quantity: 250, Unit: "g", Saving: 1.5},
+		},
+		EstimatedTotalWithSuggestions: 22.60,
+	}
+	setup.groceryService.On("GenerateBudgetedGroceryList", 1, mock.MatchedBy(func(req models.GroceryListRequest) bool {
+		return req.Budget != nil && *req.Budget == budget
+	})).Return(budgeted, nil)
+
+	requestBody, _ := json.Marshal(models.GroceryListRequest{RecipeIDs: []int{1}, Budget: &budget})
+	req := httptest.NewRequest("POST", "/grocery-list?format=text", bytes.NewBuffer(requestBody))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GenerateGroceryList(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Estimated total: 24.10 (budget 20.00)")
+	assert.Contains(t, recorder.Body.String(), "- Swap Butter for 250 g Margarine to save 1.50")
+	setup.groceryService.AssertNotCalled(t, "GenerateGroceryList", mock.Anything, mock.Anything)
+}
+
+func TestGroceryHandler_GenerateGroceryList_InvalidBudget(t *testing.T) {
+	setup := setupGroceryHandlerTest()
+	budget := -5.0
+	setup.groceryService.On("GenerateBudgetedGroceryList", 1, mock.AnythingOfType("models.GroceryListRequest")).
+		Return(nil, domain.ErrInvalidBudget)
+
+	requestBody, _ := json.Marshal(models.GroceryListRequest{RecipeIDs: []int{1}, Budget: &budget})
+	req := httptest.NewRequest("POST", "/grocery-list", bytes.NewBuffer(requestBody))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GenerateGroceryList(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
 func TestIngredientHandler_GenerateGroceryList_EmptyRecipeList(t *testing.T) {
 	setup := setupGroceryHandlerTest()
 	request := models.GroceryListRequest{RecipeIDs: []int{}}
@@ -90,7 +138,143 @@ func TestIngredientHandler_GenerateGroceryList_EmptyRecipeList(t *testing.T) {
 	var response models.ErrorResponse
 	err := json.NewDecoder(recorder.Body).Decode(&response)
 	assert.NoError(t, err)
-	assert.Equal(t, "At least one recipe ID is required", response.Message)
+	assert.Equal(t, "Recipe IDs, a meal plan ID or a date range is required", response.Message)
 
 	setup.groceryService.AssertNotCalled(t, "GenerateGroceryList")
 }
+
+func TestGroceryHandler_GenerateGroceryList_FromMealPlan(t *testing.T) {
+	setup := setupGroceryHandlerTest()
+	planID := 3
+	request := models.GroceryListRequest{MealPlanID: &planID}
+	setup.groceryService.On("GenerateGroceryList", 1, request).Return([]models.GroceryListItem{
+		{IngredientID: 4, TotalQuantity: 400, Unit: "grams"},
+	}, nil)
+
+	req := httptest.NewRequest("POST", "/grocery-list", bytes.NewBufferString(`{"meal_plan_id": 3}`))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GenerateGroceryList(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	setup.groceryService.AssertExpectations(t)
+}
+
+func TestGroceryHandler_GenerateGroceryList_SourceErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"several sources", domain.ErrInvalidGrocerySource},
+		{"bad date range", domain.ErrInvalidGroceryDateRange},
+		{"unknown plan", domain.ErrMealPlanNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupGroceryHandlerTest()
+			setup.groceryService.On("GenerateGroceryList", 1, mock.AnythingOfType("models.GroceryListRequest")).Return(nil, tt.err)
+
+			req := httptest.NewRequest("POST", "/grocery-list", bytes.NewBufferString(`{"from": "2025-10-12", "to": "2025-10-06"}`))
+			req = test.AddAuthContext(req, 1, "test@example.com")
+			recorder := httptest.NewRecorder()
+
+			setup.handler.GenerateGroceryList(recorder, req)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func TestIngredientHandler_GenerateGroceryList_TextExportFollowsServiceOrder(t *testing.T) {
+	setup := setupGroceryHandlerTest()
+	layoutID := 3
+	request := models.GroceryListRequest{RecipeIDs: []int{1}, StoreLayoutID: &layoutID}
+	label := "can"
+	groceryList := []models.GroceryListItem{
+		{Ingredient: factory.NewIngredientBuilder().WithName("Onion").WithCategory("Vegetables").Build(), TotalQuantity: 2, Unit: "pieces"},
+		{Ingredient: factory.NewIngredientBuilder().WithName("Tomatoes").WithCategory("Vegetables").Build(), TotalQuantity: 650, Unit: "g",
+			Purchase: &models.PackPurchase{PackSize: models.PackSize{Quantity: 400, Unit: "g", Label: &label}, Packs: 2, Leftover: 150,
+				Note: "Buy 2 × 400 g can; you'll have 150 g left over"}},
+		{Ingredient: factory.NewIngredientBuilder().WithName("Milk").WithCategory("Dairy").Build(), TotalQuantity: -1, Unit: "ml"},
+	}
+	setup.groceryService.On("GenerateGroceryList", 1, request).Return(groceryList, nil)
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest("POST", "/grocery-list?format=text", bytes.NewBuffer(requestBody))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GenerateGroceryList(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "Vegetables\n"+
+		"- 2 pieces Onion\n"+
+		"- 650 g Tomatoes - Buy 2 × 400 g can; you'll have 150 g left over\n"+
+		"\n"+
+		"Dairy\n"+
+		"- Milk (mixed units, check recipes)\n", recorder.Body.String())
+}
+
+func TestIngredientHandler_GenerateGroceryList_UnknownStoreLayout(t *testing.T) {
+	setup := setupGroceryHandlerTest()
+	setup.groceryService.On("GenerateGroceryList", 1, mock.AnythingOfType("models.GroceryListRequest")).
+		Return(nil, domain.ErrStoreLayoutNotFound)
+
+	req := httptest.NewRequest("POST", "/grocery-list", bytes.NewBufferString(`{"recipe_ids":[1],"store_layout_id":99}`))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GenerateGroceryList(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+// =============================================================================
+// STORE LAYOUT TESTS
+// =============================================================================
+
+func TestGroceryHandler_CreateStoreLayout_Conflict(t *testing.T) {
+	setup := setupGroceryHandlerTest()
+	request := models.StoreLayoutRequest{Name: "Supermarket", Categories: []string{"Dairy"}}
+	setup.groceryService.On("CreateStoreLayout", 1, request).Return(nil, domain.ErrStoreLayoutExists)
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest("POST", "/me/store-layouts", bytes.NewBuffer(requestBody))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.CreateStoreLayout(recorder, req)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	setup.groceryService.AssertExpectations(t)
+}
+
+func TestGroceryHandler_UpdateStoreLayout_NotFound(t *testing.T) {
+	setup := setupGroceryHandlerTest()
+	setup.groceryService.On("UpdateStoreLayout", 1, 4, mock.AnythingOfType("models.StoreLayoutRequest")).
+		Return(nil, domain.ErrStoreLayoutNotFound)
+
+	req := httptest.NewRequest("PUT", "/me/store-layouts/4", bytes.NewBufferString(`{"name":"Shop","categories":["Dairy"]}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "4"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UpdateStoreLayout(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestGroceryHandler_GetStoreLayouts_RequiresAuth(t *testing.T) {
+	setup := setupGroceryHandlerTest()
+
+	req := httptest.NewRequest("GET", "/me/store-layouts", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetStoreLayouts(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	setup.groceryService.AssertNotCalled(t, "GetStoreLayouts", mock.Anything)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+// HouseholdHandler serves the households that share a pantry. The pantry
+// itself stays on /me/pantry, which is the household's for its members.
+type HouseholdHandler struct {
+	householdService service.HouseholdService
+}
+
+func NewHouseholdHandler(householdService service.HouseholdService) *HouseholdHandler {
+	return &HouseholdHandler{householdService: householdService}
+}
+
+func (h *HouseholdHandler) CreateHousehold(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateHouseholdRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	household, err := h.householdService.CreateHousehold(user.UserID, req)
+	if err != nil {
+		writeHouseholdError(w, err, "Failed to create household")
+		return
+	}
+
+	models.WriteSuccessResponse(w, household, http.StatusCreated)
+}
+
+func (h *HouseholdHandler) GetHousehold(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	household, err := h.householdService.GetHousehold(r.Context(), user.UserID)
+	if err != nil {
+		writeHouseholdError(w, err, "Failed to fetch household")
+		return
+	}
+
+	models.WriteSuccessResponse(w, household, http.StatusOK)
+}
+
+// JoinHousehold serves POST /households/join. From then on the caller's
+// pantry is the household's; their own comes back if they leave.
+func (h *HouseholdHandler) JoinHousehold(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.JoinHouseholdRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	household, err := h.householdService.JoinHousehold(user.UserID, req)
+	if err != nil {
+		writeHouseholdError(w, err, "Failed to join household")
+		return
+	}
+
+	models.WriteSuccessResponse(w, household, http.StatusOK)
+}
+
+// RemoveMember serves DELETE /me/household/members/{user_id}; members use
+// it with their own ID to leave.
+func (h *HouseholdHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	memberID, err := strconv.Atoi(mux.Vars(r)["user_id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.householdService.RemoveMember(r.Context(), user.UserID, memberID); err != nil {
+		writeHouseholdError(w, err, "Failed to remove household member")
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Household member removed"}, http.StatusNoContent)
+}
+
+func writeHouseholdError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrInvalidHouseholdName:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrHouseholdNotFound, domain.ErrInvalidInviteCode, domain.ErrHouseholdMemberNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	case domain.ErrNotHouseholdOwner:
+		models.WriteErrorResponse(w, err.Error(), http.StatusForbidden)
+	case domain.ErrAlreadyInHousehold, domain.ErrHouseholdOwnerLeaving:
+		models.WriteErrorResponse(w, err.Error(), http.StatusConflict)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
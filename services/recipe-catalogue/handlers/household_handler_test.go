@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type householdHandlerTestSetup struct {
+	handler          *HouseholdHandler
+	householdService *mocks.MockHouseholdService
+}
+
+func setupHouseholdHandlerTest() *householdHandlerTestSetup {
+	mockService := new(mocks.MockHouseholdService)
+
+	return &householdHandlerTestSetup{
+		handler:          NewHouseholdHandler(mockService),
+		householdService: mockService,
+	}
+}
+
+func TestHouseholdHandler_CreateHousehold_Success(t *testing.T) {
+	setup := setupHouseholdHandlerTest()
+	req := models.CreateHouseholdRequest{Name: "Flat 4"}
+	setup.householdService.On("CreateHousehold", 1, req).
+		Return(&models.Household{ID: 2, Name: "Flat 4", OwnerID: 1, InviteCode: "code-1"}, nil)
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest("POST", "/households", bytes.NewReader(body))
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.CreateHousehold(recorder, httpReq)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var household models.Household
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&household))
+	assert.Equal(t, "code-1", household.InviteCode)
+}
+
+func TestHouseholdHandler_CreateHousehold_AlreadyInHousehold(t *testing.T) {
+	setup := setupHouseholdHandlerTest()
+	setup.householdService.On("CreateHousehold", 1, mock.Anything).Return(nil, domain.ErrAlreadyInHousehold)
+
+	httpReq := httptest.NewRequest("POST", "/households", bytes.NewReader([]byte(`{"name": "Flat 4"}`)))
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.CreateHousehold(recorder, httpReq)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestHouseholdHandler_GetHousehold_NotInOne(t *testing.T) {
+	setup := setupHouseholdHandlerTest()
+	setup.householdService.On("GetHousehold", 1).Return(nil, domain.ErrHouseholdNotFound)
+
+	httpReq := httptest.NewRequest("GET", "/me/household", nil)
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetHousehold(recorder, httpReq)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestHouseholdHandler_JoinHousehold_InvalidCode(t *testing.T) {
+	setup := setupHouseholdHandlerTest()
+	setup.householdService.On("JoinHousehold", 1, models.JoinHouseholdRequest{InviteCode: "nope"}).
+		Return(nil, domain.ErrInvalidInviteCode)
+
+	httpReq := httptest.NewRequest("POST", "/households/join", bytes.NewReader([]byte(`{"invite_code": "nope"}`)))
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.JoinHousehold(recorder, httpReq)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	setup.householdService.AssertExpectations(t)
+}
+
+func TestHouseholdHandler_RemoveMember(t *testing.T) {
+	tests := []struct {
+		name         string
+		serviceErr   error
+		expectedCode int
+	}{
+		{name: "removed", expectedCode: http.StatusNoContent},
+		{name: "not the owner", serviceErr: domain.ErrNotHouseholdOwner, expectedCode: http.StatusForbidden},
+		{name: "owner leaving members behind", serviceErr: domain.ErrHouseholdOwnerLeaving, expectedCode: http.StatusConflict},
+		{name: "not a member", serviceErr: domain.ErrHouseholdMemberNotFound, expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupHouseholdHandlerTest()
+			setup.householdService.On("RemoveMember", 1, 8).Return(tt.serviceErr)
+
+			httpReq := httptest.NewRequest("DELETE", "/me/household/members/8", nil)
+			httpReq = mux.SetURLVars(httpReq, map[string]string{"user_id": "8"})
+			httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+			recorder := httptest.NewRecorder()
+
+			setup.handler.RemoveMember(recorder, httpReq)
+
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/policy"
+)
+
+// WithHouseholds adds the households the signed-in user belongs to to their
+// policy subject, which is what lets members read and update what their
+// household shares. The token does not carry them, since membership changes
+// without a new login.
+func (h *HouseholdHandler) WithHouseholds(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject, ok := policy.SubjectFrom(r.Context())
+		if !ok || subject.UserID <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		households, err := h.householdService.Households(subject.UserID)
+		if err != nil {
+			// Owners keep their access; only household sharing is lost
+			logging.WithContext(r.Context()).Warn("Failed to load households", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		subject.Households = households
+		next.ServeHTTP(w, r.WithContext(policy.WithSubject(r.Context(), subject)))
+	})
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/policy"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWithHouseholds(setup *householdHandlerTestSetup, ctx context.Context) policy.Subject {
+	var subject policy.Subject
+	handler := setup.handler.WithHouseholds(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject, _ = policy.SubjectFrom(r.Context())
+	}))
+	req := httptest.NewRequest("GET", "/me/pantry", nil).WithContext(ctx)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	return subject
+}
+
+func TestWithHouseholds_FillsSubject(t *testing.T) {
+	setup := setupHouseholdHandlerTest()
+	setup.householdService.On("Households", 8).Return([]int{2}, nil)
+
+	subject := serveWithHouseholds(setup, policy.WithSubject(context.Background(), policy.Subject{UserID: 8}))
+
+	assert.Equal(t, 8, subject.UserID)
+	assert.Equal(t, []int{2}, subject.Households)
+}
+
+func TestWithHouseholds_Anonymous(t *testing.T) {
+	setup := setupHouseholdHandlerTest()
+
+	subject := serveWithHouseholds(setup, context.Background())
+
+	assert.Zero(t, subject.UserID)
+	setup.householdService.AssertNotCalled(t, "Households")
+}
+
+func TestWithHouseholds_LookupFailureKeepsSubject(t *testing.T) {
+	logging.Init("test")
+	setup := setupHouseholdHandlerTest()
+	setup.householdService.On("Households", 8).Return(nil, errors.New("database error"))
+
+	subject := serveWithHouseholds(setup, policy.WithSubject(context.Background(), policy.Subject{UserID: 8}))
+
+	assert.Equal(t, 8, subject.UserID)
+	assert.Empty(t, subject.Households)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+// IngredientCleanupHandler lets admins review ingredients no recipe has used
+// for a while and archive or delete them in bulk, and find and merge
+// ingredients entered twice. The routes are mounted behind
+// middleware.RequireAdmin; the unused ones accept ?months= to override how
+// long an ingredient must have gone unused.
+type IngredientCleanupHandler struct {
+	cleanupService service.IngredientCleanupService
+}
+
+func NewIngredientCleanupHandler(cleanupService service.IngredientCleanupService) *IngredientCleanupHandler {
+	return &IngredientCleanupHandler{cleanupService: cleanupService}
+}
+
+func (h *IngredientCleanupHandler) GetUnusedIngredients(w http.ResponseWriter, r *http.Request) {
+	months, ok := parseUnusedMonths(w, r)
+	if !ok {
+		return
+	}
+
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	ingredients, meta, err := h.cleanupService.ListUnusedIngredients(months, params)
+	if err != nil {
+		writeIngredientCleanupError(w, err, "Failed to fetch unused ingredients")
+		return
+	}
+
+	models.WritePaginatedResponse(w, ingredients, meta, http.StatusOK)
+}
+
+func (h *IngredientCleanupHandler) CleanupIngredients(w http.ResponseWriter, r *http.Request) {
+	months, ok := parseUnusedMonths(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.IngredientCleanupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.cleanupService.CleanupIngredients(months, req)
+	if err != nil {
+		writeIngredientCleanupError(w, err, "Failed to clean up ingredients")
+		return
+	}
+
+	models.WriteSuccessResponse(w, result, http.StatusOK)
+}
+
+func (h *IngredientCleanupHandler) GetDuplicateIngredients(w http.ResponseWriter, r *http.Request) {
+	var minSimilarity float64
+	if raw := r.URL.Query().Get("min_similarity"); raw != "" {
+		var err error
+		if minSimilarity, err = strconv.ParseFloat(raw, 64); err != nil || minSimilarity <= 0 {
+			models.WriteErrorResponse(w, domain.ErrInvalidMinSimilarity.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	duplicates, err := h.cleanupService.FindDuplicateIngredients(minSimilarity, limit)
+	if err != nil {
+		writeIngredientCleanupError(w, err, "Failed to find duplicate ingredients")
+		return
+	}
+
+	models.WriteSuccessResponse(w, duplicates, http.StatusOK)
+}
+
+// MergeIngredient folds the ingredient {id} into {targetId}.
+func (h *IngredientCleanupHandler) MergeIngredient(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	duplicateID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+	targetID, err := strconv.Atoi(vars["targetId"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid target ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.cleanupService.MergeIngredients(r.Context(), duplicateID, targetID)
+	if err != nil {
+		writeIngredientCleanupError(w, err, "Failed to merge ingredients")
+		return
+	}
+
+	models.WriteSuccessResponse(w, result, http.StatusOK)
+}
+
+// parseUnusedMonths reads ?months=; 0 leaves the service default in place.
+func parseUnusedMonths(w http.ResponseWriter, r *http.Request) (int, bool) {
+	raw := r.URL.Query().Get("months")
+	if raw == "" {
+		return 0, true
+	}
+	months, err := strconv.Atoi(raw)
+	if err != nil || months <= 0 {
+		models.WriteErrorResponse(w, domain.ErrInvalidUnusedMonths.Error(), http.StatusBadRequest)
+		return 0, false
+	}
+	return months, true
+}
+
+func writeIngredientCleanupError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrInvalidCleanupAction, domain.ErrCleanupIngredientsNeeded, domain.ErrInvalidUnusedMonths,
+		domain.ErrInvalidMinSimilarity, domain.ErrMergeIntoItself:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrIngredientNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
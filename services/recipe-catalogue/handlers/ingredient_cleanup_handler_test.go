@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngredientCleanupHandler_GetUnusedIngredients(t *testing.T) {
+	mockService := new(mocks.MockIngredientCleanupService)
+	handler := NewIngredientCleanupHandler(mockService)
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+	mockService.On("ListUnusedIngredients", 12, params).Return(
+		[]models.UnusedIngredient{{Ingredient: models.Ingredient{ID: 4, Name: "Saffron"}}}, models.NewPaginationMeta(params, 1), nil)
+
+	req := httptest.NewRequest("GET", "/ingredients/unused?months=12", nil)
+	recorder := httptest.NewRecorder()
+
+	handler.GetUnusedIngredients(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data       []models.UnusedIngredient `json:"data"`
+		Pagination models.PaginationMeta     `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "Saffron", response.Data[0].Name)
+	assert.Equal(t, 1, response.Pagination.Total)
+	mockService.AssertExpectations(t)
+}
+
+func TestIngredientCleanupHandler_GetUnusedIngredients_InvalidMonths(t *testing.T) {
+	handler := NewIngredientCleanupHandler(new(mocks.MockIngredientCleanupService))
+
+	for _, target := range []string{"/ingredients/unused?months=soon", "/ingredients/unused?months=0"} {
+		recorder := httptest.NewRecorder()
+		handler.GetUnusedIngredients(recorder, httptest.NewRequest("GET", target, nil))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, target)
+	}
+}
+
+func TestIngredientCleanupHandler_CleanupIngredients(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		expected int
+	}{
+		{"archive", `{"action":"archive","ingredient_ids":[4,5]}`, nil, http.StatusOK},
+		{"unknown action", `{"action":"purge","ingredient_ids":[4,5]}`, domain.ErrInvalidCleanupAction, http.StatusBadRequest},
+		{"no ingredients", `{"action":"archive"}`, domain.ErrCleanupIngredientsNeeded, http.StatusBadRequest},
+		{"invalid JSON", `{"action":`, nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockIngredientCleanupService)
+			handler := NewIngredientCleanupHandler(mockService)
+			var req models.IngredientCleanupRequest
+			if json.Unmarshal([]byte(tt.body), &req) == nil {
+				if tt.err != nil {
+					mockService.On("CleanupIngredients", 0, req).Return(nil, tt.err)
+				} else {
+					mockService.On("CleanupIngredients", 0, req).Return(
+						&models.IngredientCleanupResult{Action: req.Action, IngredientIDs: []int{4}}, nil)
+				}
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.CleanupIngredients(recorder, httptest.NewRequest("POST", "/ingredients/unused/cleanup", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expected, recorder.Code)
+			if tt.expected == http.StatusOK {
+				var response models.IngredientCleanupResult
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				assert.Equal(t, []int{4}, response.IngredientIDs)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIngredientCleanupHandler_GetDuplicateIngredients(t *testing.T) {
+	mockService := new(mocks.MockIngredientCleanupService)
+	handler := NewIngredientCleanupHandler(mockService)
+	mockService.On("FindDuplicateIngredients", 0.6, 10).Return([]models.DuplicateIngredients{{
+		Ingredient: models.Ingredient{ID: 4, Name: "Mozzarella"},
+		Duplicate:  models.Ingredient{ID: 9, Name: "Mozarella"},
+		Similarity: 0.64,
+	}}, nil)
+
+	recorder := httptest.NewRecorder()
+	handler.GetDuplicateIngredients(recorder, httptest.NewRequest("GET", "/ingredients/duplicates?min_similarity=0.6&limit=10", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"duplicate":{"id":9,"name":"Mozarella"`)
+	mockService.AssertExpectations(t)
+
+	recorder = httptest.NewRecorder()
+	handler.GetDuplicateIngredients(recorder, httptest.NewRequest("GET", "/ingredients/duplicates?min_similarity=high", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestIngredientCleanupHandler_MergeIngredient(t *testing.T) {
+	tests := []struct {
+		name     string
+		targetID int
+		err      error
+		expected int
+	}{
+		{"merged", 4, nil, http.StatusOK},
+		{"missing ingredient", 5, domain.ErrIngredientNotFound, http.StatusNotFound},
+		{"into itself", 9, domain.ErrMergeIntoItself, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockIngredientCleanupService)
+			handler := NewIngredientCleanupHandler(mockService)
+			if tt.err != nil {
+				mockService.On("MergeIngredients", mock.Anything, 9, tt.targetID).Return(nil, tt.err)
+			} else {
+				mockService.On("MergeIngredients", mock.Anything, 9, tt.targetID).Return(
+					&models.IngredientMergeResult{Ingredient: models.Ingredient{ID: 4, Name: "Egg"}, MergedID: 9, RecipeIDs: []int{2}}, nil)
+			}
+
+			req := httptest.NewRequest("POST", fmt.Sprintf("/ingredients/9/merge/%d", tt.targetID), nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "9", "targetId": strconv.Itoa(tt.targetID)})
+			recorder := httptest.NewRecorder()
+
+			handler.MergeIngredient(recorder, req)
+
+			assert.Equal(t, tt.expected, recorder.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"meal-prep/services/recipe-catalogue/domain"
 	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/logging"
 	"meal-prep/shared/middleware"
 	"meal-prep/shared/models"
 	"net/http"
@@ -23,7 +24,10 @@ func NewIngredientHandler(ingredientService service.IngredientService) *Ingredie
 func (h *IngredientHandler) GetAllIngredients(w http.ResponseWriter, r *http.Request) {
 	category := r.URL.Query().Get("category")
 	searchQuery := r.URL.Query().Get("search")
-	params := models.ParsePaginationParams(r)
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
 
 	var ingredients []models.Ingredient
 	var meta models.PaginationMeta
@@ -42,7 +46,26 @@ func (h *IngredientHandler) GetAllIngredients(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	models.WritePaginatedResponse(w, ingredients, meta, http.StatusOK)
+	localizeIngredients(r.Context(), ingredients)
+	if searchQuery != "" && meta.Total == 0 && len(ingredients) == 0 {
+		if ingredients == nil {
+			ingredients = []models.Ingredient{}
+		}
+		models.WriteSearchResponse(w, ingredients, meta, h.searchSuggestions(r, searchQuery), http.StatusOK)
+		return
+	}
+	models.WriteCacheableResponse(w, r, models.PaginatedResponse{Data: ingredients, Pagination: meta})
+}
+
+// searchSuggestions finds "did you mean" names for a search without results.
+// They are a nicety, so a failure only leaves them out.
+func (h *IngredientHandler) searchSuggestions(r *http.Request, query string) []string {
+	suggestions, err := h.ingredientService.SuggestIngredients(query)
+	if err != nil {
+		logging.WithContext(r.Context()).Warn("Failed to suggest ingredients", "query", query, "error", err)
+		return nil
+	}
+	return suggestions
 }
 
 func (h *IngredientHandler) GetIngredientByID(w http.ResponseWriter, r *http.Request) {
@@ -64,7 +87,9 @@ func (h *IngredientHandler) GetIngredientByID(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	models.WriteSuccessResponse(w, ingredient, http.StatusOK)
+	localized := []models.Ingredient{*ingredient}
+	localizeIngredients(r.Context(), localized)
+	models.WriteCacheableResponse(w, r, localized[0])
 }
 
 func (h *IngredientHandler) CreateIngredient(w http.ResponseWriter, r *http.Request) {
@@ -173,7 +198,10 @@ func (h *IngredientHandler) GetRecipesUsingIngredient(w http.ResponseWriter, r *
 		return
 	}
 
-	params := models.ParsePaginationParams(r)
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
 
 	recipes, meta, err := h.ingredientService.GetRecipesUsingIngredient(id, params)
 	if err != nil {
@@ -189,6 +217,596 @@ func (h *IngredientHandler) GetRecipesUsingIngredient(w http.ResponseWriter, r *
 	models.WritePaginatedResponse(w, recipes, meta, http.StatusOK)
 }
 
+func (h *IngredientHandler) GetPopularIngredients(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	usage, err := h.ingredientService.GetPopularIngredients(limit)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch popular ingredients", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, usage, http.StatusOK)
+}
+
+func (h *IngredientHandler) GetIngredientDensity(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	density, err := h.ingredientService.GetIngredientDensity(id)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrDensityNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch ingredient density", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, density, http.StatusOK)
+}
+
+// ListIngredientDensities, SetIngredientDensity and DeleteIngredientDensity are
+// mounted behind middleware.RequireAdmin.
+func (h *IngredientHandler) ListIngredientDensities(w http.ResponseWriter, r *http.Request) {
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	densities, meta, err := h.ingredientService.ListIngredientDensities(params)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch ingredient densities", http.StatusInternalServerError)
+		return
+	}
+
+	models.WritePaginatedResponse(w, densities, meta, http.StatusOK)
+}
+
+func (h *IngredientHandler) SetIngredientDensity(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetIngredientDensityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	density, err := h.ingredientService.SetIngredientDensity(id, req)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrInvalidDensity:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to save ingredient density", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, density, http.StatusOK)
+}
+
+func (h *IngredientHandler) DeleteIngredientDensity(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.ingredientService.DeleteIngredientDensity(id)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrDensityNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to delete ingredient density", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Ingredient density deleted successfully"}, http.StatusNoContent)
+}
+
+func (h *IngredientHandler) GetPackSizes(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	packSizes, err := h.ingredientService.GetPackSizes(id)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch pack sizes", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, packSizes, http.StatusOK)
+}
+
+func (h *IngredientHandler) SetPackSizes(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	_ = user
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	var req []models.PackSizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	packSizes, err := h.ingredientService.SetPackSizes(id, req)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrInvalidQuantity:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidUnit:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to save pack sizes", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, packSizes, http.StatusOK)
+}
+
+func (h *IngredientHandler) GetIngredientProducts(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	products, err := h.ingredientService.GetIngredientProducts(id)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch products", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, products, http.StatusOK)
+}
+
+// CreateIngredientProduct adds a branded product under the ingredient. It
+// and the other product writes are admin-only; see RegisterRoutes.
+func (h *IngredientHandler) CreateIngredientProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.IngredientProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	product, err := h.ingredientService.CreateIngredientProduct(id, req)
+	if err != nil {
+		writeProductError(w, err, "Failed to create product")
+		return
+	}
+
+	models.WriteSuccessResponse(w, product, http.StatusCreated)
+}
+
+func (h *IngredientHandler) UpdateIngredientProduct(w http.ResponseWriter, r *http.Request) {
+	id, productID, ok := productIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.IngredientProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	product, err := h.ingredientService.UpdateIngredientProduct(id, productID, req)
+	if err != nil {
+		writeProductError(w, err, "Failed to update product")
+		return
+	}
+
+	models.WriteSuccessResponse(w, product, http.StatusOK)
+}
+
+func (h *IngredientHandler) DeleteIngredientProduct(w http.ResponseWriter, r *http.Request) {
+	id, productID, ok := productIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.ingredientService.DeleteIngredientProduct(id, productID); err != nil {
+		writeProductError(w, err, "Failed to delete product")
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Product deleted successfully"}, http.StatusNoContent)
+}
+
+func productIDs(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	productID, err := strconv.Atoi(vars["productId"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid product ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return id, productID, true
+}
+
+func writeProductError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrIngredientNotFound, domain.ErrProductNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidProduct, domain.ErrInvalidNutrition:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrProductExists:
+		models.WriteErrorResponse(w, err.Error(), http.StatusConflict)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+func (h *IngredientHandler) GetSubstitutions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	substitutions, err := h.ingredientService.GetSubstitutions(id)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch substitutions", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, substitutions, http.StatusOK)
+}
+
+// SetSubstitutions is admin-only; see RegisterRoutes.
+func (h *IngredientHandler) SetSubstitutions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	var req []models.SubstitutionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	substitutions, err := h.ingredientService.SetSubstitutions(id, req)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrInvalidSubstitution:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to save substitutions", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, substitutions, http.StatusOK)
+}
+
+func (h *IngredientHandler) GetTranslations(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	translations, err := h.ingredientService.GetTranslations(id)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch translations", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, translations, http.StatusOK)
+}
+
+// SetTranslations replaces every translation of the ingredient. It is
+// admin-only; see RegisterRoutes.
+func (h *IngredientHandler) SetTranslations(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	var req []models.IngredientTranslation
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	translations, err := h.ingredientService.SetTranslations(id, req)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrInvalidLocale, domain.ErrInvalidTranslation:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to save translations", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, translations, http.StatusOK)
+}
+
+func (h *IngredientHandler) GetIngredientDietary(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	dietary, err := h.ingredientService.GetIngredientDietary(id)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to classify ingredient", http.StatusInternalServerError)
+		}
+		return
+	}
+	localizeIngredientDietary(r.Context(), []models.IngredientDietary{*dietary})
+
+	models.WriteSuccessResponse(w, dietary, http.StatusOK)
+}
+
+// SetIngredientDietary replaces the ingredient's dietary flags and override.
+// It is admin-only; see RegisterRoutes.
+func (h *IngredientHandler) SetIngredientDietary(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.DietaryFacts
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	dietary, err := h.ingredientService.SetIngredientDietary(id, req)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrInvalidDietaryFlag, domain.ErrInvalidDiet:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to save dietary flags", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, dietary, http.StatusOK)
+}
+
+func (h *IngredientHandler) GetRecipeDietary(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	dietary, err := h.ingredientService.GetRecipeDietary(recipeID)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to classify recipe", http.StatusInternalServerError)
+		}
+		return
+	}
+	localizeIngredientDietary(r.Context(), dietary.Ingredients)
+
+	models.WriteSuccessResponse(w, dietary, http.StatusOK)
+}
+
+// FilterCompliance checks recipes against a diet profile, for meal planning
+// or validating a menu.
+func (h *IngredientHandler) FilterCompliance(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.ComplianceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.ingredientService.FilterCompliance(r.Context(), user.UserID, req)
+	if err != nil {
+		switch err {
+		case domain.ErrComplianceRecipesNeeded, domain.ErrInvalidDiet, domain.ErrInvalidDietaryFlag, domain.ErrInvalidMinConfidence:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to check recipe compliance", http.StatusInternalServerError)
+		}
+		return
+	}
+	for i := range result.Recipes {
+		localizeComplianceViolations(r.Context(), result.Recipes[i].Violations)
+	}
+
+	models.WriteSuccessResponse(w, result, http.StatusOK)
+}
+
+func (h *IngredientHandler) GetAllergens(w http.ResponseWriter, r *http.Request) {
+	allergens, err := h.ingredientService.GetAllergens()
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to retrieve allergens", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, allergens, http.StatusOK)
+}
+
+func (h *IngredientHandler) GetIngredientAllergens(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	allergens, err := h.ingredientService.GetIngredientAllergens(id)
+	if err != nil {
+		switch err {
+		case domain.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to retrieve ingredient allergens", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, allergens, http.StatusOK)
+}
+
+// SetIngredientAllergens replaces the ingredient's allergens. It and the
+// other allergen writes are admin-only; see RegisterRoutes.
+func (h *IngredientHandler) SetIngredientAllergens(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.IngredientAllergensRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	allergens, err := h.ingredientService.SetIngredientAllergens(id, req.Allergens)
+	if err != nil {
+		h.writeAllergenError(w, err, "Failed to save ingredient allergens")
+		return
+	}
+
+	models.WriteSuccessResponse(w, allergens, http.StatusOK)
+}
+
+func (h *IngredientHandler) AddIngredientAllergen(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.AddIngredientAllergenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	allergens, err := h.ingredientService.AddIngredientAllergen(id, req.Allergen)
+	if err != nil {
+		h.writeAllergenError(w, err, "Failed to tag ingredient with allergen")
+		return
+	}
+
+	models.WriteSuccessResponse(w, allergens, http.StatusCreated)
+}
+
+func (h *IngredientHandler) RemoveIngredientAllergen(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.ingredientService.RemoveIngredientAllergen(id, vars["code"]); err != nil {
+		h.writeAllergenError(w, err, "Failed to remove ingredient allergen")
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Ingredient allergen removed successfully"}, http.StatusNoContent)
+}
+
+func (h *IngredientHandler) writeAllergenError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrIngredientNotFound, domain.ErrIngredientAllergenNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidAllergen:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
+
 func (h *IngredientHandler) GetRecipeIngredients(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	recipeID, err := strconv.Atoi(vars["id"])
@@ -208,6 +826,8 @@ func (h *IngredientHandler) GetRecipeIngredients(w http.ResponseWriter, r *http.
 		return
 	}
 
+	convertIngredients(ingredients, measurementSystemFrom(r.Context()))
+	localizeRecipeIngredients(r.Context(), ingredients)
 	models.WriteSuccessResponse(w, ingredients, http.StatusOK)
 }
 
@@ -241,7 +861,7 @@ func (h *IngredientHandler) AddRecipeIngredient(w http.ResponseWriter, r *http.R
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		case domain.ErrInvalidQuantity:
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
-		case domain.ErrInvalidUnit:
+		case domain.ErrInvalidUnit, domain.ErrProductNotForIngredient:
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		case domain.ErrRecipeIngredientAlreadyExists:
 			models.WriteErrorResponse(w, err.Error(), http.StatusConflict)
@@ -290,7 +910,7 @@ func (h *IngredientHandler) UpdateRecipeIngredient(w http.ResponseWriter, r *htt
 			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
 		case domain.ErrInvalidQuantity:
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
-		case domain.ErrInvalidUnit:
+		case domain.ErrInvalidUnit, domain.ErrProductNotForIngredient:
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		default:
 			models.WriteErrorResponse(w, "Failed to update recipe ingredient", http.StatusInternalServerError)
@@ -368,7 +988,7 @@ func (h *IngredientHandler) SetRecipeIngredients(w http.ResponseWriter, r *http.
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		case domain.ErrInvalidQuantity:
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
-		case domain.ErrInvalidUnit:
+		case domain.ErrInvalidUnit, domain.ErrProductNotForIngredient:
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		default:
 			models.WriteErrorResponse(w, "Failed to update recipe ingredients", http.StatusInternalServerError)
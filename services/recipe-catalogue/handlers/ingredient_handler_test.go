@@ -11,12 +11,14 @@ import (
 	"testing"
 
 	"meal-prep/services/recipe-catalogue/handlers/mocks"
-	"meal-prep/services/recipe-catalogue/service/testdata"
+	"meal-prep/shared/logging"
 	"meal-prep/shared/models"
+	"meal-prep/shared/testing/factory"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type ingredientHandlerTestSetup struct {
@@ -41,8 +43,8 @@ func setupIngredientHandlerTest() *ingredientHandlerTestSetup {
 func TestIngredientHandler_GetAllIngredients_Success(t *testing.T) {
 	setup := setupIngredientHandlerTest()
 	expectedIngredients := []models.Ingredient{
-		testdata.NewIngredientBuilder().WithID(1).WithName("Tomato").Build(),
-		testdata.NewIngredientBuilder().WithID(2).WithName("Basil").Build(),
+		factory.NewIngredientBuilder().WithID(1).WithName("Tomato").Build(),
+		factory.NewIngredientBuilder().WithID(2).WithName("Basil").Build(),
 	}
 
 	setup.ingredientService.On("GetAllIngredients", mock.AnythingOfType("models.PaginationParams")).Return(expectedIngredients, models.PaginationMeta{}, nil)
@@ -70,7 +72,7 @@ func TestIngredientHandler_GetAllIngredients_Success(t *testing.T) {
 func TestIngredientHandler_GetAllIngredients_WithCategoryFilter(t *testing.T) {
 	setup := setupIngredientHandlerTest()
 	expectedIngredients := []models.Ingredient{
-		testdata.NewIngredientBuilder().WithCategory("Vegetable").Build(),
+		factory.NewIngredientBuilder().WithCategory("Vegetable").Build(),
 	}
 
 	setup.ingredientService.On("GetIngredientsByCategory", "Vegetable", mock.AnythingOfType("models.PaginationParams")).Return(expectedIngredients, models.PaginationMeta{}, nil)
@@ -96,7 +98,7 @@ func TestIngredientHandler_GetAllIngredients_WithCategoryFilter(t *testing.T) {
 func TestIngredientHandler_GetAllIngredients_WithSearchQuery(t *testing.T) {
 	setup := setupIngredientHandlerTest()
 	expectedIngredients := []models.Ingredient{
-		testdata.NewIngredientBuilder().WithName("Cherry Tomato").Build(),
+		factory.NewIngredientBuilder().WithName("Cherry Tomato").Build(),
 	}
 
 	setup.ingredientService.On("SearchIngredients", "tomato", mock.AnythingOfType("models.PaginationParams")).Return(expectedIngredients, models.PaginationMeta{}, nil)
@@ -119,6 +121,38 @@ func TestIngredientHandler_GetAllIngredients_WithSearchQuery(t *testing.T) {
 	setup.ingredientService.AssertExpectations(t)
 }
 
+func TestIngredientHandler_GetAllIngredients_SearchSuggestions(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	setup.ingredientService.On("SearchIngredients", "garlik", mock.AnythingOfType("models.PaginationParams")).Return(nil, models.PaginationMeta{Page: 1, PerPage: 20}, nil)
+	setup.ingredientService.On("SuggestIngredients", "garlik").Return([]string{"Garlic", "Garlic Powder"}, nil)
+
+	req := httptest.NewRequest("GET", "/ingredients?search=garlik", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetAllIngredients(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response models.PaginatedResponse
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, []interface{}{}, response.Data)
+	assert.Equal(t, []string{"Garlic", "Garlic Powder"}, response.Suggestions)
+}
+
+func TestIngredientHandler_GetAllIngredients_SuggestionsFailure(t *testing.T) {
+	logging.Init("test")
+	setup := setupIngredientHandlerTest()
+	setup.ingredientService.On("SearchIngredients", "garlik", mock.AnythingOfType("models.PaginationParams")).Return(nil, models.PaginationMeta{Page: 1, PerPage: 20}, nil)
+	setup.ingredientService.On("SuggestIngredients", "garlik").Return(nil, errors.New("database error"))
+
+	req := httptest.NewRequest("GET", "/ingredients?search=garlik", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetAllIngredients(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "suggestions")
+}
+
 func TestIngredientHandler_GetAllIngredients_ServiceError(t *testing.T) {
 	setup := setupIngredientHandlerTest()
 
@@ -145,7 +179,7 @@ func TestIngredientHandler_GetAllIngredients_ServiceError(t *testing.T) {
 
 func TestIngredientHandler_GetIngredientByID_Success(t *testing.T) {
 	setup := setupIngredientHandlerTest()
-	expectedIngredient := testdata.NewIngredientBuilder().WithID(1).WithName("Tomato").BuildPtr()
+	expectedIngredient := factory.NewIngredientBuilder().WithID(1).WithName("Tomato").BuildPtr()
 
 	setup.ingredientService.On("GetIngredientByID", 1).Return(expectedIngredient, nil)
 
@@ -166,6 +200,37 @@ func TestIngredientHandler_GetIngredientByID_Success(t *testing.T) {
 	setup.ingredientService.AssertExpectations(t)
 }
 
+func TestIngredientHandler_GetIngredientByID_NotModified(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	ingredient := factory.NewIngredientBuilder().WithID(1).WithName("Tomato").BuildPtr()
+
+	setup.ingredientService.On("GetIngredientByID", 1).Return(ingredient, nil)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/ingredients/1", nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		recorder := httptest.NewRecorder()
+		setup.handler.GetIngredientByID(recorder, req)
+		return recorder
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.NotEmpty(t, etag)
+
+	unchanged := get(etag)
+	assert.Equal(t, http.StatusNotModified, unchanged.Code)
+	assert.Empty(t, unchanged.Body.String())
+
+	// Renaming it changes the payload, and so the tag
+	ingredient.Name = "Cherry tomato"
+	renamed := get(etag)
+	assert.Equal(t, http.StatusOK, renamed.Code)
+	assert.NotEqual(t, etag, renamed.Header().Get("ETag"))
+}
+
 func TestIngredientHandler_GetIngredientByID_InvalidID(t *testing.T) {
 	setup := setupIngredientHandlerTest()
 
@@ -212,8 +277,8 @@ func TestIngredientHandler_GetIngredientByID_NotFound(t *testing.T) {
 
 func TestIngredientHandler_CreateIngredient_Success(t *testing.T) {
 	setup := setupIngredientHandlerTest()
-	request := testdata.ValidCreateIngredientRequest()
-	expectedIngredient := testdata.NewIngredientBuilder().WithName(request.Name).BuildPtr()
+	request := factory.ValidCreateIngredientRequest()
+	expectedIngredient := factory.NewIngredientBuilder().WithName(request.Name).BuildPtr()
 
 	setup.ingredientService.
 		On("CreateIngredient", mock.AnythingOfType("models.CreateIngredientRequest")).
@@ -241,7 +306,7 @@ func TestIngredientHandler_CreateIngredient_Success(t *testing.T) {
 
 func TestIngredientHandler_CreateIngredient_MissingAuthentication(t *testing.T) {
 	setup := setupIngredientHandlerTest()
-	request := testdata.ValidCreateIngredientRequest()
+	request := factory.ValidCreateIngredientRequest()
 
 	requestBody, _ := json.Marshal(request)
 	req := httptest.NewRequest("POST", "/ingredients", bytes.NewBuffer(requestBody))
@@ -304,7 +369,7 @@ func TestIngredientHandler_CreateIngredient_ValidationErrors(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			setup := setupIngredientHandlerTest()
-			request := testdata.ValidCreateIngredientRequest()
+			request := factory.ValidCreateIngredientRequest()
 
 			setup.ingredientService.On("CreateIngredient", mock.AnythingOfType("models.CreateIngredientRequest")).
 				Return(nil, tc.serviceError)
@@ -342,7 +407,7 @@ func TestIngredientHandler_UpdateIngredient_Success(t *testing.T) {
 		Description: "Updated description",
 		Category:    "Updated Category",
 	}
-	expectedIngredient := testdata.NewIngredientBuilder().WithName("Updated Ingredient").BuildPtr()
+	expectedIngredient := factory.NewIngredientBuilder().WithName("Updated Ingredient").BuildPtr()
 
 	setup.ingredientService.On("UpdateIngredient", 1, mock.AnythingOfType("models.UpdateIngredientRequest")).
 		Return(expectedIngredient, nil)
@@ -452,7 +517,7 @@ func TestIngredientHandler_DeleteIngredient_CannotDelete(t *testing.T) {
 func TestIngredientHandler_GetRecipesUsingIngredient_Success(t *testing.T) {
 	setup := setupIngredientHandlerTest()
 	expectedRecipes := []models.Recipe{
-		testdata.NewRecipeBuilder().WithID(1).WithName("Tomato Sauce").Build(),
+		factory.NewRecipeBuilder().WithID(1).WithName("Tomato Sauce").Build(),
 	}
 
 	setup.ingredientService.On("GetRecipesUsingIngredient", 1, mock.AnythingOfType("models.PaginationParams")).Return(expectedRecipes, models.PaginationMeta{}, nil)
@@ -477,6 +542,200 @@ func TestIngredientHandler_GetRecipesUsingIngredient_Success(t *testing.T) {
 	setup.ingredientService.AssertExpectations(t)
 }
 
+// =============================================================================
+// POPULAR INGREDIENTS TESTS
+// =============================================================================
+
+func TestIngredientHandler_GetPopularIngredients_Success(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	usage := []models.IngredientUsage{
+		{Ingredient: factory.NewIngredientBuilder().WithID(1).WithName("Garlic").Build(), RecipeCount: 12},
+	}
+
+	setup.ingredientService.On("GetPopularIngredients", 5).Return(usage, nil)
+
+	req := httptest.NewRequest("GET", "/ingredients/popular?limit=5", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetPopularIngredients(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response []models.IngredientUsage
+	err := json.NewDecoder(recorder.Body).Decode(&response)
+	assert.NoError(t, err)
+	if assert.Len(t, response, 1) {
+		assert.Equal(t, "Garlic", response[0].Name)
+		assert.Equal(t, 12, response[0].RecipeCount)
+	}
+
+	setup.ingredientService.AssertExpectations(t)
+}
+
+func TestIngredientHandler_GetPopularIngredients_ServiceError(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	setup.ingredientService.On("GetPopularIngredients", 0).Return(nil, errors.New("database error"))
+
+	req := httptest.NewRequest("GET", "/ingredients/popular", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetPopularIngredients(recorder, req)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	setup.ingredientService.AssertExpectations(t)
+}
+
+// =============================================================================
+// INGREDIENT DENSITY TESTS
+// =============================================================================
+
+func TestIngredientHandler_GetIngredientDensity_NotRecorded(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	setup.ingredientService.On("GetIngredientDensity", 5).Return(nil, domain.ErrDensityNotFound)
+
+	req := httptest.NewRequest("GET", "/ingredients/5/density", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetIngredientDensity(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	setup.ingredientService.AssertExpectations(t)
+}
+
+func TestIngredientHandler_SetIngredientDensity_Success(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	request := models.SetIngredientDensityRequest{GramsPerML: 0.53}
+	setup.ingredientService.On("SetIngredientDensity", 7, request).
+		Return(&models.IngredientDensity{IngredientID: 7, GramsPerML: 0.53}, nil)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("PUT", "/ingredients/7/density", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	req = test.AddAdminContext(req, 1, "admin@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetIngredientDensity(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response models.IngredientDensity
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, 0.53, response.GramsPerML)
+	setup.ingredientService.AssertExpectations(t)
+}
+
+func TestIngredientHandler_SetIngredientDensity_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"invalid density", domain.ErrInvalidDensity, http.StatusBadRequest},
+		{"unknown ingredient", domain.ErrIngredientNotFound, http.StatusNotFound},
+		{"database error", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientHandlerTest()
+			setup.ingredientService.On("SetIngredientDensity", 7, mock.AnythingOfType("models.SetIngredientDensityRequest")).
+				Return(nil, tt.err)
+
+			req := httptest.NewRequest("PUT", "/ingredients/7/density", bytes.NewBufferString(`{"grams_per_ml": -1}`))
+			req = mux.SetURLVars(req, map[string]string{"id": "7"})
+			req = test.AddAdminContext(req, 1, "admin@example.com")
+			recorder := httptest.NewRecorder()
+
+			setup.handler.SetIngredientDensity(recorder, req)
+
+			assert.Equal(t, tt.expected, recorder.Code)
+		})
+	}
+}
+
+func TestIngredientHandler_DeleteIngredientDensity_Success(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	setup.ingredientService.On("DeleteIngredientDensity", 7).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/ingredients/7/density", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	req = test.AddAdminContext(req, 1, "admin@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.DeleteIngredientDensity(recorder, req)
+
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	setup.ingredientService.AssertExpectations(t)
+}
+
+// =============================================================================
+// PACK SIZE TESTS
+// =============================================================================
+
+func TestIngredientHandler_SetPackSizes_Success(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	request := []models.PackSizeRequest{{Quantity: 400, Unit: "g"}}
+	stored := []models.PackSize{{ID: 1, IngredientID: 3, Quantity: 400, Unit: "g"}}
+	setup.ingredientService.On("SetPackSizes", 3, request).Return(stored, nil)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("PUT", "/ingredients/3/pack-sizes", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetPackSizes(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response []models.PackSize
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Len(t, response, 1)
+	setup.ingredientService.AssertExpectations(t)
+}
+
+func TestIngredientHandler_SetSubstitutions_Invalid(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	request := []models.SubstitutionRequest{{SubstituteID: 3}}
+	setup.ingredientService.On("SetSubstitutions", 3, request).Return(nil, domain.ErrInvalidSubstitution)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("PUT", "/ingredients/3/substitutions", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "admin@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetSubstitutions(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	setup.ingredientService.AssertExpectations(t)
+}
+
+func TestIngredientHandler_SetPackSizes_RequiresAuth(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+
+	req := httptest.NewRequest("PUT", "/ingredients/3/pack-sizes", bytes.NewBufferString(`[]`))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetPackSizes(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	setup.ingredientService.AssertNotCalled(t, "SetPackSizes", mock.Anything, mock.Anything)
+}
+
+func TestIngredientHandler_GetPackSizes_IngredientNotFound(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	setup.ingredientService.On("GetPackSizes", 999).Return(nil, domain.ErrIngredientNotFound)
+
+	req := httptest.NewRequest("GET", "/ingredients/999/pack-sizes", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "999"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetPackSizes(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
 // =============================================================================
 // RECIPE INGREDIENTS TESTS
 // =============================================================================
@@ -484,7 +743,7 @@ func TestIngredientHandler_GetRecipesUsingIngredient_Success(t *testing.T) {
 func TestIngredientHandler_GetRecipeIngredients_Success(t *testing.T) {
 	setup := setupIngredientHandlerTest()
 	expectedIngredients := []models.RecipeIngredient{
-		testdata.NewRecipeIngredientBuilder().WithRecipeID(1).Build(),
+		factory.NewRecipeIngredientBuilder().WithRecipeID(1).Build(),
 	}
 
 	setup.ingredientService.On("GetRecipeIngredients", 1).Return(expectedIngredients, nil)
@@ -508,12 +767,12 @@ func TestIngredientHandler_GetRecipeIngredients_Success(t *testing.T) {
 func TestIngredientHandler_AddRecipeIngredient_Success(t *testing.T) {
 	setup := setupIngredientHandlerTest()
 
-	expectedRecipeIngredient := testdata.NewRecipeIngredientBuilder().BuildPtr()
+	expectedRecipeIngredient := factory.NewRecipeIngredientBuilder().BuildPtr()
 	setup.ingredientService.
 		On("AddRecipeIngredient", 1, mock.AnythingOfType("models.AddRecipeIngredientRequest")).
 		Return(expectedRecipeIngredient, nil)
 
-	request := testdata.ValidAddRecipeIngredientRequest()
+	request := factory.ValidAddRecipeIngredientRequest()
 	requestBody, _ := json.Marshal(request)
 
 	req := httptest.NewRequest("POST", "/recipes/1/ingredients", bytes.NewBuffer(requestBody))
@@ -566,7 +825,7 @@ func TestIngredientHandler_AddRecipeIngredient_ValidationErrors(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			setup := setupIngredientHandlerTest()
-			request := testdata.ValidAddRecipeIngredientRequest()
+			request := factory.ValidAddRecipeIngredientRequest()
 
 			setup.ingredientService.On("AddRecipeIngredient", 1, mock.AnythingOfType("models.AddRecipeIngredientRequest")).
 				Return(nil, tc.serviceError)
@@ -593,3 +852,254 @@ func TestIngredientHandler_AddRecipeIngredient_ValidationErrors(t *testing.T) {
 		})
 	}
 }
+
+// =============================================================================
+// DIETARY CLASSIFICATION TESTS
+// =============================================================================
+
+func TestIngredientHandler_SetIngredientDietary_Success(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	request := models.DietaryFacts{Flags: []string{"egg"}}
+	setup.ingredientService.On("SetIngredientDietary", 12, request).
+		Return(&models.IngredientDietary{IngredientID: 12, Diet: models.DietVegetarian, Confidence: 0.95, Source: models.DietSourceFlags}, nil)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("PUT", "/ingredients/12/dietary", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "12"})
+	req = test.AddAdminContext(req, 1, "admin@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetIngredientDietary(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response models.IngredientDietary
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, models.DietVegetarian, response.Diet)
+	setup.ingredientService.AssertExpectations(t)
+}
+
+func TestIngredientHandler_SetIngredientDietary_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"invalid flag", domain.ErrInvalidDietaryFlag, http.StatusBadRequest},
+		{"invalid diet", domain.ErrInvalidDiet, http.StatusBadRequest},
+		{"unknown ingredient", domain.ErrIngredientNotFound, http.StatusNotFound},
+		{"database error", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientHandlerTest()
+			setup.ingredientService.On("SetIngredientDietary", 12, mock.AnythingOfType("models.DietaryFacts")).
+				Return(nil, tt.err)
+
+			req := httptest.NewRequest("PUT", "/ingredients/12/dietary", bytes.NewBufferString(`{"flags": ["gluten"]}`))
+			req = mux.SetURLVars(req, map[string]string{"id": "12"})
+			req = test.AddAdminContext(req, 1, "admin@example.com")
+			recorder := httptest.NewRecorder()
+
+			setup.handler.SetIngredientDietary(recorder, req)
+
+			assert.Equal(t, tt.expected, recorder.Code)
+		})
+	}
+}
+
+func TestIngredientHandler_GetRecipeDietary_NotFound(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	setup.ingredientService.On("GetRecipeDietary", 3).Return(nil, domain.ErrRecipeNotFound)
+
+	req := httptest.NewRequest("GET", "/recipes/3/dietary", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetRecipeDietary(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	setup.ingredientService.AssertExpectations(t)
+}
+
+func TestIngredientHandler_FilterCompliance(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	request := models.ComplianceRequest{Profile: models.DietProfile{Diet: "vegetarian"}, RecipeIDs: []int{1, 2}}
+	setup.ingredientService.On("FilterCompliance", 1, request).Return(&models.ComplianceResult{
+		Recipes: []models.RecipeCompliance{
+			{RecipeID: 1, Passes: true, Diet: models.DietVegan, Violations: []models.ComplianceViolation{}},
+		},
+		NotFound: []int{2},
+	}, nil)
+
+	req := httptest.NewRequest("POST", "/recipes/filter-compliance",
+		bytes.NewBufferString(`{"profile": {"diet": "vegetarian"}, "recipe_ids": [1, 2]}`))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.FilterCompliance(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response models.ComplianceResult
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Len(t, response.Recipes, 1)
+	assert.Equal(t, []int{2}, response.NotFound)
+	setup.ingredientService.AssertExpectations(t)
+}
+
+func TestIngredientHandler_FilterCompliance_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"no recipes", domain.ErrComplianceRecipesNeeded, http.StatusBadRequest},
+		{"invalid diet", domain.ErrInvalidDiet, http.StatusBadRequest},
+		{"invalid confidence", domain.ErrInvalidMinConfidence, http.StatusBadRequest},
+		{"database error", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientHandlerTest()
+			setup.ingredientService.On("FilterCompliance", 1, mock.AnythingOfType("models.ComplianceRequest")).
+				Return(nil, tt.err)
+
+			req := httptest.NewRequest("POST", "/recipes/filter-compliance", bytes.NewBufferString(`{"recipe_ids": []}`))
+			req = test.AddAuthContext(req, 1, "test@example.com")
+			recorder := httptest.NewRecorder()
+
+			setup.handler.FilterCompliance(recorder, req)
+
+			assert.Equal(t, tt.expected, recorder.Code)
+		})
+	}
+}
+
+// =============================================================================
+// ALLERGEN TESTS
+// =============================================================================
+
+func TestIngredientHandler_SetIngredientAllergens_Success(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	allergens := []models.Allergen{{ID: 2, Code: "dairy", Name: "Milk"}, {ID: 9, Code: "soy", Name: "Soy"}}
+	setup.ingredientService.On("SetIngredientAllergens", 12, []string{"dairy", "soy"}).Return(allergens, nil)
+
+	req := httptest.NewRequest("PUT", "/ingredients/12/allergens", bytes.NewBufferString(`{"allergens": ["dairy", "soy"]}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "12"})
+	req = test.AddAdminContext(req, 1, "admin@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetIngredientAllergens(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response []models.Allergen
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, allergens, response)
+	setup.ingredientService.AssertExpectations(t)
+}
+
+func TestIngredientHandler_AddIngredientAllergen_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"unknown allergen", domain.ErrInvalidAllergen, http.StatusBadRequest},
+		{"unknown ingredient", domain.ErrIngredientNotFound, http.StatusNotFound},
+		{"database error", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientHandlerTest()
+			setup.ingredientService.On("AddIngredientAllergen", 12, "lupine").Return(nil, tt.err)
+
+			req := httptest.NewRequest("POST", "/ingredients/12/allergens", bytes.NewBufferString(`{"allergen": "lupine"}`))
+			req = mux.SetURLVars(req, map[string]string{"id": "12"})
+			req = test.AddAdminContext(req, 1, "admin@example.com")
+			recorder := httptest.NewRecorder()
+
+			setup.handler.AddIngredientAllergen(recorder, req)
+
+			assert.Equal(t, tt.expected, recorder.Code)
+		})
+	}
+}
+
+func TestIngredientHandler_RemoveIngredientAllergen(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	setup.ingredientService.On("RemoveIngredientAllergen", 12, "dairy").Return(nil).Once()
+	setup.ingredientService.On("RemoveIngredientAllergen", 12, "dairy").Return(domain.ErrIngredientAllergenNotFound).Once()
+
+	for _, expected := range []int{http.StatusNoContent, http.StatusNotFound} {
+		req := httptest.NewRequest("DELETE", "/ingredients/12/allergens/dairy", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "12", "code": "dairy"})
+		req = test.AddAdminContext(req, 1, "admin@example.com")
+		recorder := httptest.NewRecorder()
+
+		setup.handler.RemoveIngredientAllergen(recorder, req)
+
+		assert.Equal(t, expected, recorder.Code)
+	}
+	setup.ingredientService.AssertExpectations(t)
+}
+
+// =============================================================================
+// BRANDED PRODUCT TESTS
+// =============================================================================
+
+func TestIngredientHandler_CreateIngredientProduct_Success(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	request := models.IngredientProductRequest{
+		Brand:     "Barilla",
+		Name:      "Spaghetti No. 5",
+		Nutrition: models.ProductNutrition{CaloriesKcal: 359, ProteinG: 12.5, CarbsG: 71.2, FatG: 2, FiberG: 3},
+	}
+	setup.ingredientService.On("CreateIngredientProduct", 5, request).
+		Return(&models.IngredientProduct{ID: 1, IngredientID: 5, Brand: request.Brand, Name: request.Name, Nutrition: request.Nutrition}, nil)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("POST", "/ingredients/5/products", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "5"})
+	req = test.AddAdminContext(req, 1, "admin@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.CreateIngredientProduct(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var response models.IngredientProduct
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, 12.5, response.Nutrition.ProteinG)
+	setup.ingredientService.AssertExpectations(t)
+}
+
+func TestIngredientHandler_UpdateIngredientProduct_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"invalid nutrition", domain.ErrInvalidNutrition, http.StatusBadRequest},
+		{"unknown product", domain.ErrProductNotFound, http.StatusNotFound},
+		{"duplicate", domain.ErrProductExists, http.StatusConflict},
+		{"database error", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientHandlerTest()
+			setup.ingredientService.On("UpdateIngredientProduct", 5, 9, mock.AnythingOfType("models.IngredientProductRequest")).
+				Return(nil, tt.err)
+
+			req := httptest.NewRequest("PUT", "/ingredients/5/products/9", bytes.NewBufferString(`{"brand": "Barilla", "name": "Penne"}`))
+			req = mux.SetURLVars(req, map[string]string{"id": "5", "productId": "9"})
+			req = test.AddAdminContext(req, 1, "admin@example.com")
+			recorder := httptest.NewRecorder()
+
+			setup.handler.UpdateIngredientProduct(recorder, req)
+
+			assert.Equal(t, tt.expected, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type LineageHandler struct {
+	lineageService service.LineageService
+}
+
+func NewLineageHandler(lineageService service.LineageService) *LineageHandler {
+	return &LineageHandler{lineageService: lineageService}
+}
+
+func (h *LineageHandler) ForkRecipe(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	recipeID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	// The body is optional; without one the fork keeps the original's name
+	var req models.ForkRecipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	recipe, err := h.lineageService.ForkRecipe(r.Context(), user.UserID, recipeID, req)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrRecipeNameRequired:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidCategory:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrCategoryNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrForkCategoryRequired:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to fork recipe", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, recipe, http.StatusCreated)
+}
+
+func (h *LineageHandler) GetRecipeLineage(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	lineage, err := h.lineageService.GetLineage(recipeID)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch recipe lineage", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, lineage, http.StatusOK)
+}
+
+func (h *LineageHandler) GetRecipeForks(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	forks, err := h.lineageService.GetForks(recipeID)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch recipe forks", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, forks, http.StatusOK)
+}
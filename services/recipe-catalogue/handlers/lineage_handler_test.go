@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type lineageHandlerTestSetup struct {
+	handler        *LineageHandler
+	lineageService *mocks.MockLineageService
+}
+
+func setupLineageHandlerTest() *lineageHandlerTestSetup {
+	mockService := new(mocks.MockLineageService)
+
+	return &lineageHandlerTestSetup{
+		handler:        NewLineageHandler(mockService),
+		lineageService: mockService,
+	}
+}
+
+func TestLineageHandler_ForkRecipe_WithoutBody(t *testing.T) {
+	setup := setupLineageHandlerTest()
+	forked := &models.RecipeWithIngredients{Recipe: models.Recipe{ID: 10, UserID: 1, Name: "Shakshuka", Status: models.RecipeStatusDraft}}
+	setup.lineageService.On("ForkRecipe", 1, 3, models.ForkRecipeRequest{}).Return(forked, nil)
+
+	req := httptest.NewRequest("POST", "/recipes/3/fork", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.ForkRecipe(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	setup.lineageService.AssertExpectations(t)
+}
+
+func TestLineageHandler_ForkRecipe_NoCategory(t *testing.T) {
+	setup := setupLineageHandlerTest()
+	name := "Green shakshuka"
+	request := models.ForkRecipeRequest{Name: &name}
+	setup.lineageService.On("ForkRecipe", 1, 3, request).Return(nil, domain.ErrForkCategoryRequired)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("POST", "/recipes/3/fork", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.ForkRecipe(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestLineageHandler_ForkRecipe_Unauthenticated(t *testing.T) {
+	setup := setupLineageHandlerTest()
+
+	req := httptest.NewRequest("POST", "/recipes/3/fork", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.ForkRecipe(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	setup.lineageService.AssertNotCalled(t, "ForkRecipe")
+}
+
+func TestLineageHandler_GetRecipeLineage_Success(t *testing.T) {
+	setup := setupLineageHandlerTest()
+	expected := &models.RecipeLineage{
+		RecipeID:    10,
+		AdaptedFrom: []models.RecipeAttribution{{RecipeID: 1, UserID: 3, Name: "Shakshuka", Public: true}},
+	}
+	setup.lineageService.On("GetLineage", 10).Return(expected, nil)
+
+	req := httptest.NewRequest("GET", "/recipes/10/lineage", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "10"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetRecipeLineage(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response models.RecipeLineage
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, *expected, response)
+}
+
+func TestLineageHandler_GetRecipeForks_NotFound(t *testing.T) {
+	setup := setupLineageHandlerTest()
+	setup.lineageService.On("GetForks", 1).Return(nil, domain.ErrRecipeNotFound)
+
+	req := httptest.NewRequest("GET", "/recipes/1/forks", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetRecipeForks(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+)
+
+// maxLocales caps how many locales a request names ingredients in, however
+// long its Accept-Language header is.
+const maxLocales = 6
+
+type localeKey struct{}
+
+// ingredientLocale is what WithLocale leaves in the request context: the
+// locales to name ingredients in, most preferred first, and where to look
+// the names up.
+type ingredientLocale struct {
+	locales []string
+	names   func(ingredientIDs []int, locales []string) (map[int]string, error)
+}
+
+// WithLocale names ingredients in the wrapped handler's responses in the
+// locales middleware.Locale chose. A regional locale falls back to its
+// language ("pt-br" to "pt"), and ingredients without a translation keep
+// their English name.
+func (h *IngredientHandler) WithLocale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), localeKey{}, ingredientLocale{
+			locales: localeFallbacks(middleware.LocalesFromContext(r.Context())),
+			names:   h.ingredientService.GetLocalizedNames,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// PreferredLocale is the signed-in user's locale setting, for
+// middleware.Locale; "" for anonymous users and users without one.
+func (h *ProfileHandler) PreferredLocale(r *http.Request) string {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		return ""
+	}
+	settings, err := h.profileService.GetSettings(user.UserID)
+	if err != nil {
+		// Accept-Language still gives a sensible language
+		logging.WithContext(r.Context()).Warn("Failed to load locale setting", "error", err)
+		return ""
+	}
+	if settings.Locale == nil {
+		return ""
+	}
+	return *settings.Locale
+}
+
+// localeFallbacks expands preferred tags into the locales to look names up
+// in, adding each regional tag's language after it. It stops at English,
+// which the ingredient names are already written in.
+func localeFallbacks(tags []string) []string {
+	var locales []string
+	seen := make(map[string]bool)
+	add := func(locale string) {
+		if !seen[locale] && len(locales) < maxLocales {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+
+	for _, tag := range tags {
+		language, _, regional := strings.Cut(tag, "-")
+		if language == "en" {
+			break
+		}
+		add(tag)
+		if regional {
+			add(language)
+		}
+	}
+	return locales
+}
+
+// localizedNames looks up the translated names of the ingredients for the
+// request's locale. It returns nil when English names should be kept.
+func localizedNames(ctx context.Context, ingredientIDs []int) map[int]string {
+	locale, ok := ctx.Value(localeKey{}).(ingredientLocale)
+	if !ok || len(locale.locales) == 0 || len(ingredientIDs) == 0 {
+		return nil
+	}
+
+	names, err := locale.names(ingredientIDs, locale.locales)
+	if err != nil {
+		// English names are still correct, just not translated
+		logging.WithContext(ctx).Warn("Failed to load ingredient translations", "error", err)
+		return nil
+	}
+	return names
+}
+
+// localizeIngredients renames ingredients in place.
+func localizeIngredients(ctx context.Context, ingredients []models.Ingredient) {
+	ids := make([]int, len(ingredients))
+	for i := range ingredients {
+		ids[i] = ingredients[i].ID
+	}
+	names := localizedNames(ctx, ids)
+	for i := range ingredients {
+		if name, ok := names[ingredients[i].ID]; ok {
+			ingredients[i].Name = name
+		}
+	}
+}
+
+// localizeRecipeIngredients renames recipe ingredients in place.
+func localizeRecipeIngredients(ctx context.Context, ingredients []models.RecipeIngredient) {
+	ids := make([]int, len(ingredients))
+	for i := range ingredients {
+		ids[i] = ingredients[i].IngredientID
+	}
+	names := localizedNames(ctx, ids)
+	for i := range ingredients {
+		if name, ok := names[ingredients[i].IngredientID]; ok {
+			ingredients[i].Ingredient.Name = name
+		}
+	}
+}
+
+// localizeGroceryItems renames grocery list items in place.
+func localizeGroceryItems(ctx context.Context, items []models.GroceryListItem) {
+	ids := make([]int, len(items))
+	for i := range items {
+		ids[i] = items[i].IngredientID
+	}
+	names := localizedNames(ctx, ids)
+	for i := range items {
+		if name, ok := names[items[i].IngredientID]; ok {
+			items[i].Ingredient.Name = name
+		}
+	}
+}
+
+// localizeIngredientDietary renames classified ingredients in place.
+func localizeIngredientDietary(ctx context.Context, ingredients []models.IngredientDietary) {
+	ids := make([]int, len(ingredients))
+	for i := range ingredients {
+		ids[i] = ingredients[i].IngredientID
+	}
+	names := localizedNames(ctx, ids)
+	for i := range ingredients {
+		if name, ok := names[ingredients[i].IngredientID]; ok {
+			ingredients[i].Name = name
+		}
+	}
+}
+
+// localizeComplianceViolations renames failing ingredients in place.
+func localizeComplianceViolations(ctx context.Context, violations []models.ComplianceViolation) {
+	ids := make([]int, len(violations))
+	for i := range violations {
+		ids[i] = violations[i].IngredientID
+	}
+	names := localizedNames(ctx, ids)
+	for i := range violations {
+		if name, ok := names[violations[i].IngredientID]; ok {
+			violations[i].Name = name
+		}
+	}
+}
+
+// localizeIngredientComparisons renames compared ingredients in place.
+func localizeIngredientComparisons(ctx context.Context, comparisons []models.IngredientComparison) {
+	ids := make([]int, len(comparisons))
+	for i := range comparisons {
+		ids[i] = comparisons[i].IngredientID
+	}
+	names := localizedNames(ctx, ids)
+	for i := range comparisons {
+		if name, ok := names[comparisons[i].IngredientID]; ok {
+			comparisons[i].Name = name
+		}
+	}
+}
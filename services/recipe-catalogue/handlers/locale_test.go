@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"meal-prep/shared/testing/factory"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocaleFallbacks(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           []string
+	}{
+		{"regional falls back to language", "pt-BR", []string{"pt-br", "pt"}},
+		{"ordered by quality", "de;q=0.5, ru-RU, es;q=0.8", []string{"ru-ru", "ru", "es", "de"}},
+		{"stops at english", "ru, en-GB;q=0.9, de;q=0.8", []string{"ru"}},
+		{"english only", "en-US,en;q=0.9", nil},
+		{"wildcard and refusals skipped", "*, fr;q=0, it", []string{"it"}},
+		{"empty header", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, localeFallbacks(middleware.AcceptedLanguages(tt.acceptLanguage)))
+		})
+	}
+}
+
+// localized serves handler the way the routes do, behind the shared locale
+// middleware.
+func localized(h *IngredientHandler, handler http.HandlerFunc) http.Handler {
+	return middleware.Locale(nil)(h.WithLocale(handler))
+}
+
+func TestWithLocale_LocalizesIngredientNames(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	ingredients := []models.Ingredient{
+		factory.NewIngredientBuilder().WithID(1).WithName("Tomato").Build(),
+		factory.NewIngredientBuilder().WithID(2).WithName("Basil").Build(),
+	}
+	setup.ingredientService.On("GetAllIngredients", mock.AnythingOfType("models.PaginationParams")).Return(ingredients, models.PaginationMeta{}, nil)
+	setup.ingredientService.On("GetLocalizedNames", []int{1, 2}, []string{"de-at", "de"}).Return(map[int]string{1: "Paradeiser"}, nil)
+
+	req := httptest.NewRequest("GET", "/ingredients", nil)
+	req.Header.Set("Accept-Language", "de-AT, en;q=0.5")
+	recorder := httptest.NewRecorder()
+
+	localized(setup.handler, setup.handler.GetAllIngredients).ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Accept-Language", recorder.Header().Get("Vary"))
+	assert.Contains(t, recorder.Body.String(), `"name":"Paradeiser"`)
+	assert.Contains(t, recorder.Body.String(), `"name":"Basil"`)
+}
+
+func TestWithLocale_QueryParameterWins(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	setup.ingredientService.On("GetIngredientByID", 1).Return(factory.NewIngredientBuilder().WithID(1).WithName("Tomato").BuildPtr(), nil)
+	setup.ingredientService.On("GetLocalizedNames", []int{1}, []string{"ru"}).Return(map[int]string{1: "Помидор"}, nil)
+
+	req := httptest.NewRequest("GET", "/ingredients/1?lang=ru", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	req.Header.Set("Accept-Language", "de")
+	recorder := httptest.NewRecorder()
+
+	localized(setup.handler, setup.handler.GetIngredientByID).ServeHTTP(recorder, req)
+
+	var response models.Ingredient
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, "Помидор", response.Name)
+}
+
+func TestWithLocale_InvalidLang(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+
+	recorder := httptest.NewRecorder()
+	localized(setup.handler, setup.handler.GetAllIngredients).
+		ServeHTTP(recorder, httptest.NewRequest("GET", "/ingredients?lang=klingon!", nil))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	setup.ingredientService.AssertNotCalled(t, "GetAllIngredients", mock.Anything)
+}
+
+func TestWithLocale_KeepsEnglishNamesWhenLookupFails(t *testing.T) {
+	logging.Init("test")
+	setup := setupIngredientHandlerTest()
+	setup.ingredientService.On("GetRecipeIngredients", 3).Return([]models.RecipeIngredient{
+		{IngredientID: 1, Quantity: 2, Unit: "pcs", Ingredient: models.Ingredient{ID: 1, Name: "Tomato"}},
+	}, nil)
+	setup.ingredientService.On("GetLocalizedNames", []int{1}, []string{"es"}).Return(nil, errors.New("db down"))
+
+	req := httptest.NewRequest("GET", "/recipes/3/ingredients?lang=es", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	localized(setup.handler, setup.handler.GetRecipeIngredients).ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"name":"Tomato"`)
+}
+
+func TestIngredientHandler_SetTranslations(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	request := []models.IngredientTranslation{{Locale: "ru", Name: "Помидор"}}
+	setup.ingredientService.On("SetTranslations", 1, request).Return(request, nil)
+
+	req := httptest.NewRequest("PUT", "/ingredients/1/translations", strings.NewReader(`[{"locale":"ru","name":"Помидор"}]`))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetTranslations(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response []models.IngredientTranslation
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, request, response)
+}
+
+func TestIngredientHandler_SetTranslations_Invalid(t *testing.T) {
+	setup := setupIngredientHandlerTest()
+	request := []models.IngredientTranslation{{Locale: "en", Name: "Tomato"}}
+	setup.ingredientService.On("SetTranslations", 1, request).Return(nil, domain.ErrInvalidTranslation)
+
+	req := httptest.NewRequest("PUT", "/ingredients/1/translations", strings.NewReader(`[{"locale":"en","name":"Tomato"}]`))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SetTranslations(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestLocale_UsesProfileSetting(t *testing.T) {
+	setup := setupProfileHandlerTest()
+	german := "de"
+	setup.profileService.On("GetSettings", 1).Return(&models.ProfileSettings{UserID: 1, Locale: &german}, nil)
+
+	var locales []string
+	handler := middleware.Locale(setup.handler.PreferredLocale)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locales = middleware.LocalesFromContext(r.Context())
+	}))
+	req := test.AddAuthContext(httptest.NewRequest("GET", "/recipes/3/ingredients", nil), 1, "test@example.com")
+	req.Header.Set("Accept-Language", "fr")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []string{"de"}, locales)
+}
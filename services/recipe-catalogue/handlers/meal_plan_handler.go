@@ -0,0 +1,418 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+// MealPlanHandler serves meal plans.
+type MealPlanHandler struct {
+	mealPlanService service.MealPlanService
+}
+
+func NewMealPlanHandler(mealPlanService service.MealPlanService) *MealPlanHandler {
+	return &MealPlanHandler{mealPlanService: mealPlanService}
+}
+
+// recommenderTimeout allows for the recommendations service scoring every
+// published recipe
+const recommenderTimeout = 5 * time.Second
+
+// RecommenderFromEnv fetches recommendations for generated meal plans from
+// the service at RECOMMENDATIONS_SERVICE_URL, through transport when it is
+// not nil.
+func RecommenderFromEnv(transport http.RoundTripper) (*service.RecommendationsClient, error) {
+	client, err := recommendationsClient(recommenderTimeout, transport)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewRecommendationsClient(recommendationsURL()+"/internal/recommendations", client), nil
+}
+
+func (h *MealPlanHandler) GetMealPlans(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	plans, err := h.mealPlanService.GetMealPlans(user.UserID)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch meal plans", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, plans, http.StatusOK)
+}
+
+func (h *MealPlanHandler) GetMealPlan(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid meal plan ID", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.mealPlanService.GetMealPlan(r.Context(), user.UserID, id)
+	if err != nil {
+		writeMealPlanError(w, err, "Failed to fetch meal plan")
+		return
+	}
+
+	models.WriteSuccessResponse(w, plan, http.StatusOK)
+}
+
+func (h *MealPlanHandler) CreateMealPlan(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.MealPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.mealPlanService.CreateMealPlan(user.UserID, req)
+	if err != nil {
+		writeMealPlanError(w, err, "Failed to create meal plan")
+		return
+	}
+
+	models.WriteSuccessResponse(w, plan, http.StatusCreated)
+}
+
+func (h *MealPlanHandler) UpdateMealPlan(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid meal plan ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.MealPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.mealPlanService.UpdateMealPlan(r.Context(), user.UserID, id, req)
+	if err != nil {
+		writeMealPlanError(w, err, "Failed to update meal plan")
+		return
+	}
+
+	models.WriteSuccessResponse(w, plan, http.StatusOK)
+}
+
+func (h *MealPlanHandler) DeleteMealPlan(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid meal plan ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.mealPlanService.DeleteMealPlan(r.Context(), user.UserID, id); err != nil {
+		writeMealPlanError(w, err, "Failed to delete meal plan")
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Meal plan deleted successfully"}, http.StatusNoContent)
+}
+
+func (h *MealPlanHandler) AddEntry(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid meal plan ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.MealPlanEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.mealPlanService.AddEntry(r.Context(), user.UserID, id, req)
+	if err != nil {
+		writeMealPlanError(w, err, "Failed to add meal plan entry")
+		return
+	}
+
+	models.WriteSuccessResponse(w, entry, http.StatusCreated)
+}
+
+func (h *MealPlanHandler) UpdateEntry(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid meal plan ID", http.StatusBadRequest)
+		return
+	}
+	entryID, err := strconv.Atoi(vars["entryId"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.MealPlanEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.mealPlanService.UpdateEntry(r.Context(), user.UserID, id, entryID, req)
+	if err != nil {
+		writeMealPlanError(w, err, "Failed to update meal plan entry")
+		return
+	}
+
+	models.WriteSuccessResponse(w, entry, http.StatusOK)
+}
+
+func (h *MealPlanHandler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid meal plan ID", http.StatusBadRequest)
+		return
+	}
+	entryID, err := strconv.Atoi(vars["entryId"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.mealPlanService.DeleteEntry(r.Context(), user.UserID, id, entryID); err != nil {
+		writeMealPlanError(w, err, "Failed to delete meal plan entry")
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Meal plan entry deleted successfully"}, http.StatusNoContent)
+}
+
+// SetMembers replaces the household cooking the plan and returns the plan
+// with its meals dealt out among them.
+func (h *MealPlanHandler) SetMembers(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid meal plan ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.MealPlanMembersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.mealPlanService.SetMembers(r.Context(), user.UserID, id, req)
+	if err != nil {
+		writeMealPlanError(w, err, "Failed to update meal plan members")
+		return
+	}
+
+	models.WriteSuccessResponse(w, plan, http.StatusOK)
+}
+
+// GetMemberMeals lists the meals a member has coming up.
+func (h *MealPlanHandler) GetMemberMeals(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid meal plan ID", http.StatusBadRequest)
+		return
+	}
+	memberID, err := strconv.Atoi(vars["memberId"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+
+	meals, err := h.mealPlanService.GetMemberMeals(r.Context(), user.UserID, id, memberID)
+	if err != nil {
+		writeMealPlanError(w, err, "Failed to fetch member's meals")
+		return
+	}
+
+	models.WriteSuccessResponse(w, meals, http.StatusOK)
+}
+
+// GenerateMealPlan creates a plan from the user's recommendations.
+func (h *MealPlanHandler) GenerateMealPlan(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.GenerateMealPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.mealPlanService.GenerateMealPlan(r.Context(), user.UserID, req)
+	if err != nil {
+		writeMealPlanError(w, err, "Failed to generate meal plan")
+		return
+	}
+
+	models.WriteSuccessResponse(w, plan, http.StatusCreated)
+}
+
+// GetPreferences returns how the user's generated plans fit their week.
+func (h *MealPlanHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	prefs, err := h.mealPlanService.GetPreferences(user.UserID)
+	if err != nil {
+		writeMealPlanError(w, err, "Failed to fetch meal plan preferences")
+		return
+	}
+
+	models.WriteSuccessResponse(w, prefs, http.StatusOK)
+}
+
+func (h *MealPlanHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.MealPlanPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.mealPlanService.UpdatePreferences(user.UserID, req)
+	if err != nil {
+		writeMealPlanError(w, err, "Failed to update meal plan preferences")
+		return
+	}
+
+	models.WriteSuccessResponse(w, prefs, http.StatusOK)
+}
+
+// PrintMealPlan returns one printable PDF with every recipe of the plan,
+// scaled by the plan, and the shopping list for all of them, in the user's
+// units and language.
+func (h *MealPlanHandler) PrintMealPlan(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid meal plan ID", http.StatusBadRequest)
+		return
+	}
+
+	system := measurementSystemFrom(r.Context())
+	printout, err := h.mealPlanService.GetPrintout(r.Context(), user.UserID, id, system)
+	if err != nil {
+		switch err {
+		case domain.ErrMealPlanNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to print meal plan", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	for _, recipe := range printout.Recipes {
+		convertIngredients(recipe.Ingredients, system)
+		localizeRecipeIngredients(r.Context(), recipe.Ingredients)
+	}
+	localizeGroceryItems(r.Context(), printout.ShoppingList)
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="meal-plan-%d.pdf"`, id))
+	w.WriteHeader(http.StatusOK)
+	renderMealPlan(printout, middleware.LocaleFromContext(r.Context())).WriteTo(w)
+}
+
+func writeMealPlanError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrMealPlanNotFound, domain.ErrMealPlanEntryNotFound, domain.ErrMealPlanMemberNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	case domain.ErrMealPlanTitleRequired, domain.ErrInvalidMealPlanPeriod, domain.ErrInvalidMealPlanEntry,
+		domain.ErrInvalidMealPlanGeneration, domain.ErrInvalidScale, domain.ErrRecipeNotFound, domain.ErrInvalidMealPlanPreferences,
+		domain.ErrInvalidMealPlanMembers, domain.ErrInvalidMealPlanAssignee:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrMealPlanEntryExists:
+		models.WriteErrorResponse(w, err.Error(), http.StatusConflict)
+	case domain.ErrNoRecommendations, domain.ErrNoRecipesFitPreferences:
+		models.WriteErrorResponse(w, err.Error(), http.StatusUnprocessableEntity)
+	case domain.ErrRecommendationsUnavailable:
+		models.WriteErrorResponse(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMealPlanHandler_PrintMealPlan_Success(t *testing.T) {
+	mockService := new(mocks.MockMealPlanService)
+	handler := NewMealPlanHandler(mockService)
+	category := "Pantry"
+	printout := &models.MealPlanPrintout{
+		MealPlan: models.MealPlan{ID: 3, UserID: 1, Title: "Week of Oct 6-12", PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12", Scale: 2,
+			Entries: []models.MealPlanEntry{
+				{RecipeID: 2, Date: "2025-10-06", Slot: models.MealSlotBreakfast},
+				{RecipeID: 1, Date: "2025-10-06", Slot: models.MealSlotDinner},
+				{RecipeID: 9, Date: "2025-10-07", Slot: models.MealSlotLunch},
+			}},
+		ShoppingList: []models.GroceryListItem{
+			{IngredientID: 4, Ingredient: models.Ingredient{Name: "Pasta", Category: &category}, TotalQuantity: 400, Unit: "grams"},
+		},
+	}
+	for i, name := range []string{"Pasta", "Porridge", "Dal"} {
+		printout.Recipes = append(printout.Recipes, models.PrintedRecipe{
+			Recipe:      models.Recipe{ID: i + 1, Name: name},
+			Ingredients: []models.RecipeIngredient{{IngredientID: 4, Ingredient: models.Ingredient{Name: "Pasta"}, Quantity: 400, Unit: "grams"}},
+			Steps:       []models.RecipeStep{{Instruction: "Boil the pasta"}},
+		})
+	}
+	mockService.On("GetPrintout", 1, 3, "").Return(printout, nil)
+
+	req := httptest.NewRequest("GET", "/meal-plans/3/print", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	handler.PrintMealPlan(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/pdf", recorder.Header().Get("Content-Type"))
+	body := recorder.Body.String()
+	assert.True(t, strings.HasPrefix(body, "%PDF-"))
+	// Cover, one page per recipe, shopping list
+	assert.Contains(t, body, "/Count 5")
+	assert.Contains(t, body, "(Amounts are 2 times the recipes as written) Tj")
+	assert.Contains(t, body, "(1. Boil the pasta) Tj")
+	assert.Contains(t, body, "(- 400 grams Pasta) Tj")
+	assert.Contains(t, body, "(Pantry) Tj")
+	// The cover lists the plan day by day, skipping recipes not printed
+	assert.Contains(t, body, "(Monday 6 October) Tj")
+	assert.Contains(t, body, "(Breakfast: Porridge) Tj")
+	assert.Contains(t, body, "(Dinner: Pasta) Tj")
+	assert.NotContains(t, body, "Tuesday 7 October")
+}
+
+func TestMealPlanHandler_PrintMealPlan_NotFound(t *testing.T) {
+	mockService := new(mocks.MockMealPlanService)
+	handler := NewMealPlanHandler(mockService)
+	mockService.On("GetPrintout", 1, 3, "").Return(nil, domain.ErrMealPlanNotFound)
+
+	req := httptest.NewRequest("GET", "/meal-plans/3/print", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	handler.PrintMealPlan(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestMealPlanHandler_CreateMealPlan_Success(t *testing.T) {
+	mockService := new(mocks.MockMealPlanService)
+	handler := NewMealPlanHandler(mockService)
+	req := models.MealPlanRequest{Title: "Week of Oct 6-12", PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12"}
+	mockService.On("CreateMealPlan", 1, req).Return(&models.MealPlan{ID: 3, UserID: 1, Title: req.Title}, nil)
+
+	httpReq := httptest.NewRequest("POST", "/meal-plans",
+		strings.NewReader(`{"title":"Week of Oct 6-12","period_start":"2025-10-06","period_end":"2025-10-12"}`))
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	handler.CreateMealPlan(recorder, httpReq)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestMealPlanHandler_AddEntry_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{"plan not found", domain.ErrMealPlanNotFound, http.StatusNotFound},
+		{"outside the period", domain.ErrInvalidMealPlanEntry, http.StatusBadRequest},
+		{"recipe not plannable", domain.ErrRecipeNotFound, http.StatusBadRequest},
+		{"already planned", domain.ErrMealPlanEntryExists, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockMealPlanService)
+			handler := NewMealPlanHandler(mockService)
+			entry := models.MealPlanEntryRequest{RecipeID: 4, Date: "2025-10-06", Slot: "dinner"}
+			mockService.On("AddEntry", 1, 3, entry).Return(nil, tt.err)
+
+			req := httptest.NewRequest("POST", "/meal-plans/3/entries",
+				strings.NewReader(`{"recipe_id":4,"date":"2025-10-06","slot":"dinner"}`))
+			req = mux.SetURLVars(req, map[string]string{"id": "3"})
+			req = test.AddAuthContext(req, 1, "test@example.com")
+			recorder := httptest.NewRecorder()
+
+			handler.AddEntry(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestMealPlanHandler_SetMembers(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{"success", nil, http.StatusOK},
+		{"plan not found", domain.ErrMealPlanNotFound, http.StatusNotFound},
+		{"invalid names", domain.ErrInvalidMealPlanMembers, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockMealPlanService)
+			handler := NewMealPlanHandler(mockService)
+			members := models.MealPlanMembersRequest{Members: []string{"Ann", "Bo"}}
+			if tt.err != nil {
+				mockService.On("SetMembers", 1, 3, members).Return(nil, tt.err)
+			} else {
+				mockService.On("SetMembers", 1, 3, members).Return(&models.MealPlan{ID: 3}, nil)
+			}
+
+			req := httptest.NewRequest("PUT", "/meal-plans/3/members", strings.NewReader(`{"members":["Ann","Bo"]}`))
+			req = mux.SetURLVars(req, map[string]string{"id": "3"})
+			req = test.AddAuthContext(req, 1, "test@example.com")
+			recorder := httptest.NewRecorder()
+
+			handler.SetMembers(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMealPlanHandler_GetMemberMeals_UnknownMember(t *testing.T) {
+	mockService := new(mocks.MockMealPlanService)
+	handler := NewMealPlanHandler(mockService)
+	mockService.On("GetMemberMeals", 1, 3, 99).Return(nil, domain.ErrMealPlanMemberNotFound)
+
+	req := httptest.NewRequest("GET", "/meal-plans/3/members/99/meals", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3", "memberId": "99"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	handler.GetMemberMeals(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestMealPlanHandler_GenerateMealPlan_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{"no recommendations", domain.ErrNoRecommendations, http.StatusUnprocessableEntity},
+		{"recommendations unavailable", domain.ErrRecommendationsUnavailable, http.StatusServiceUnavailable},
+		{"too many days", domain.ErrInvalidMealPlanGeneration, http.StatusBadRequest},
+		{"nothing fits the time limits", domain.ErrNoRecipesFitPreferences, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockMealPlanService)
+			handler := NewMealPlanHandler(mockService)
+			mockService.On("GenerateMealPlan", 1, models.GenerateMealPlanRequest{PeriodStart: "2025-10-06", Days: 7}).Return(nil, tt.err)
+
+			req := httptest.NewRequest("POST", "/meal-plans/generate", strings.NewReader(`{"period_start":"2025-10-06","days":7}`))
+			req = test.AddAuthContext(req, 1, "test@example.com")
+			recorder := httptest.NewRecorder()
+
+			handler.GenerateMealPlan(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestMealPlanHandler_UpdatePreferences(t *testing.T) {
+	mockService := new(mocks.MockMealPlanService)
+	handler := NewMealPlanHandler(mockService)
+	weekday := 30
+	request := models.MealPlanPreferencesRequest{WeekdayMaxMinutes: &weekday, ElaborateWeekends: true}
+	mockService.On("UpdatePreferences", 1, request).
+		Return(&models.MealPlanPreferences{UserID: 1, WeekdayMaxMinutes: &weekday, ElaborateWeekends: true}, nil)
+
+	req := httptest.NewRequest("PUT", "/meal-plans/preferences",
+		strings.NewReader(`{"weekday_max_minutes":30,"weekend_max_minutes":null,"elaborate_weekends":true}`))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	handler.UpdatePreferences(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"weekend_max_minutes":null`)
+	mockService.AssertExpectations(t)
+}
+
+func TestMealPlanHandler_UpdatePreferences_Invalid(t *testing.T) {
+	mockService := new(mocks.MockMealPlanService)
+	handler := NewMealPlanHandler(mockService)
+	mockService.On("UpdatePreferences", 1, mock.Anything).Return(nil, domain.ErrInvalidMealPlanPreferences)
+
+	req := httptest.NewRequest("PUT", "/meal-plans/preferences", strings.NewReader(`{"weekday_max_minutes":0}`))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	handler.UpdatePreferences(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestRenderMealPlan_ContinuesLongListsOnNewPages(t *testing.T) {
+	printout := &models.MealPlanPrintout{MealPlan: models.MealPlan{Title: "Week", Scale: 1}}
+	for i := 0; i < 60; i++ {
+		printout.ShoppingList = append(printout.ShoppingList, models.GroceryListItem{
+			Ingredient: models.Ingredient{Name: "Item"}, TotalQuantity: 1, Unit: "pieces",
+		})
+	}
+
+	var body strings.Builder
+	_, err := renderMealPlan(printout, "en").WriteTo(&body)
+
+	assert.NoError(t, err)
+	// Cover and two pages of shopping list
+	assert.Contains(t, body.String(), "/Count 3")
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"meal-prep/shared/pdf"
+)
+
+// Printed page geometry: A4 with 20 mm margins.
+const (
+	printMargin  = 20 * pdf.MM
+	printWidth   = pdf.A4Width - 2*printMargin
+	printBottom  = pdf.A4Height - printMargin
+	printIndent  = 5 * pdf.MM
+	printLeading = 1.4 // line height as a multiple of the font size
+)
+
+var mealSlotNames = map[string]string{
+	models.MealSlotBreakfast: "Breakfast",
+	models.MealSlotLunch:     "Lunch",
+	models.MealSlotDinner:    "Dinner",
+}
+
+// renderMealPlan prints a cover page with the plan day by day, then each
+// recipe starting on a page of its own, then the shopping list grouped by
+// category. Amounts are written the way locale writes numbers.
+func renderMealPlan(printout *models.MealPlanPrintout, locale string) *pdf.Document {
+	w := &printWriter{doc: pdf.New()}
+	plan := printout.MealPlan
+
+	w.newPage()
+	w.paragraph(pdf.Bold, 20, 0, plan.Title)
+	w.line(pdf.Regular, 11, 0, plan.PeriodStart+" to "+plan.PeriodEnd)
+	if plan.Scale != 1 {
+		w.line(pdf.Regular, 11, 0, "Amounts are "+middleware.FormatNumber(locale, plan.Scale, -1)+" times the recipes as written")
+	}
+
+	// Entries for recipes left out of the printout are skipped
+	names := make(map[int]string, len(printout.Recipes))
+	for _, recipe := range printout.Recipes {
+		names[recipe.Recipe.ID] = recipe.Recipe.Name
+	}
+	day := ""
+	for _, entry := range plan.Entries {
+		name, ok := names[entry.RecipeID]
+		if !ok {
+			continue
+		}
+		if entry.Date != day {
+			w.space(8)
+			w.line(pdf.Bold, 11, 0, printedDate(entry.Date))
+			day = entry.Date
+		}
+		w.paragraph(pdf.Regular, 10, printIndent, mealSlotName(entry.Slot)+": "+name)
+	}
+
+	for _, recipe := range printout.Recipes {
+		w.newPage()
+		renderPrintedRecipe(w, recipe, locale)
+	}
+
+	w.newPage()
+	w.line(pdf.Bold, 16, 0, "Shopping list")
+	heading := ""
+	for i, item := range printout.ShoppingList {
+		if category := groceryCategory(item); i == 0 || category != heading {
+			w.space(6)
+			w.line(pdf.Bold, 11, 0, category)
+			heading = category
+		}
+		w.paragraph(pdf.Regular, 10, printIndent, "- "+groceryItemText(item, locale))
+	}
+	if len(printout.ShoppingList) == 0 {
+		w.line(pdf.Regular, 10, 0, "Nothing to buy.")
+	}
+
+	return w.doc
+}
+
+func renderPrintedRecipe(w *printWriter, recipe models.PrintedRecipe, locale string) {
+	w.paragraph(pdf.Bold, 16, 0, recipe.Recipe.Name)
+	if recipe.Recipe.TotalTimeMinutes != nil {
+		w.line(pdf.Regular, 10, 0, fmt.Sprintf("%d minutes", *recipe.Recipe.TotalTimeMinutes))
+	}
+	if recipe.Recipe.Description != nil && strings.TrimSpace(*recipe.Recipe.Description) != "" {
+		w.space(4)
+		w.paragraph(pdf.Regular, 10, 0, strings.TrimSpace(*recipe.Recipe.Description))
+	}
+
+	w.space(8)
+	w.line(pdf.Bold, 12, 0, "Ingredients")
+	for _, ingredient := range recipe.Ingredients {
+		text := middleware.FormatNumber(locale, ingredient.Quantity, -1) + " " + ingredient.Unit + " " + ingredient.Ingredient.Name
+		if ingredient.Notes != nil && *ingredient.Notes != "" {
+			text += ", " + *ingredient.Notes
+		}
+		w.paragraph(pdf.Regular, 10, printIndent, "- "+text)
+	}
+
+	if len(recipe.Steps) > 0 {
+		w.space(8)
+		w.line(pdf.Bold, 12, 0, "Method")
+		for i, step := range recipe.Steps {
+			w.paragraph(pdf.Regular, 10, printIndent, fmt.Sprintf("%d. %s", i+1, step.Instruction))
+		}
+	}
+}
+
+func mealSlotName(slot string) string {
+	if name, ok := mealSlotNames[slot]; ok {
+		return name
+	}
+	return slot
+}
+
+// printedDate spells out a YYYY-MM-DD date, e.g. "Monday 6 October".
+func printedDate(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.Format("Monday 2 January")
+}
+
+// printWriter flows lines of text down the page, starting a new page when
+// one is full.
+type printWriter struct {
+	doc  *pdf.Document
+	page *pdf.Page
+	y    float64 // baseline of the last line written
+}
+
+func (w *printWriter) newPage() {
+	w.page = w.doc.AddPage()
+	w.y = printMargin
+}
+
+func (w *printWriter) line(font pdf.Font, size, indent float64, s string) {
+	w.y += size * printLeading
+	if w.y > printBottom {
+		w.newPage()
+		w.y += size * printLeading
+	}
+	w.page.Text(printMargin+indent, w.y, font, size, s)
+}
+
+// paragraph wraps s to the page width. Bold text runs about 5% wider than
+// pdf.Wrap measures, so it is wrapped as if slightly larger.
+func (w *printWriter) paragraph(font pdf.Font, size, indent float64, s string) {
+	measured := size
+	if font == pdf.Bold {
+		measured *= 1.05
+	}
+	for _, text := range pdf.Wrap(s, measured, printWidth-indent) {
+		w.line(font, size, indent, text)
+	}
+}
+
+func (w *printWriter) space(points float64) {
+	w.y += points
+}
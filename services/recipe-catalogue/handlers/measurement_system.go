@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"meal-prep/shared/units"
+)
+
+// unitsOriginal asks for quantities as the recipe wrote them, overriding the
+// user's measurement system.
+const unitsOriginal = "original"
+
+type measurementSystemKey struct{}
+
+// WithMeasurementSystem decides which measurement system the wrapped handler
+// presents quantities in: ?units= (metric, imperial or original) wins, then
+// the signed-in user's profile setting. Without either, quantities keep the
+// units they were written in.
+func (h *ProfileHandler) WithMeasurementSystem(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		system := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("units")))
+		switch {
+		case system == unitsOriginal:
+			system = ""
+		case system != "":
+			if !units.IsValidSystem(system) {
+				models.WriteErrorResponse(w, "units must be metric, imperial or original", http.StatusBadRequest)
+				return
+			}
+		default:
+			if user, ok := middleware.GetUserFromGatewayContext(r.Context()); ok {
+				settings, err := h.profileService.GetSettings(user.UserID)
+				if err != nil {
+					// Quantities as written are still correct, just not localised
+					logging.WithContext(r.Context()).Warn("Failed to load measurement system", "error", err)
+				} else if settings.MeasurementSystem != nil {
+					system = *settings.MeasurementSystem
+				}
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), measurementSystemKey{}, system)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// measurementSystemFrom returns the system chosen by WithMeasurementSystem,
+// or "" to keep units as written.
+func measurementSystemFrom(ctx context.Context) string {
+	system, _ := ctx.Value(measurementSystemKey{}).(string)
+	return system
+}
+
+// convertIngredients rewrites recipe ingredient quantities in place.
+func convertIngredients(ingredients []models.RecipeIngredient, system string) {
+	if system == "" {
+		return
+	}
+	for i := range ingredients {
+		ingredients[i].Quantity, ingredients[i].Unit = units.ToSystem(ingredients[i].Quantity, ingredients[i].Unit, system)
+	}
+}
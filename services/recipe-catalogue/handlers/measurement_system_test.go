@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+	"meal-prep/shared/units"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWithMeasurementSystem(setup *profileHandlerTestSetup, req *http.Request) (string, *httptest.ResponseRecorder) {
+	var system string
+	handler := setup.handler.WithMeasurementSystem(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		system = measurementSystemFrom(r.Context())
+	}))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return system, recorder
+}
+
+func TestWithMeasurementSystem_UsesProfileSetting(t *testing.T) {
+	setup := setupProfileHandlerTest()
+	imperial := units.Imperial
+	setup.profileService.On("GetSettings", 1).Return(&models.ProfileSettings{UserID: 1, MeasurementSystem: &imperial}, nil)
+
+	req := test.AddAuthContext(httptest.NewRequest("GET", "/recipes/3/ingredients", nil), 1, "test@example.com")
+	system, _ := serveWithMeasurementSystem(setup, req)
+
+	assert.Equal(t, units.Imperial, system)
+}
+
+func TestWithMeasurementSystem_QueryParameterWins(t *testing.T) {
+	setup := setupProfileHandlerTest()
+
+	req := test.AddAuthContext(httptest.NewRequest("GET", "/recipes/3/ingredients?units=original", nil), 1, "test@example.com")
+	system, _ := serveWithMeasurementSystem(setup, req)
+
+	assert.Equal(t, "", system)
+	setup.profileService.AssertNotCalled(t, "GetSettings")
+}
+
+func TestWithMeasurementSystem_Anonymous(t *testing.T) {
+	setup := setupProfileHandlerTest()
+
+	system, _ := serveWithMeasurementSystem(setup, httptest.NewRequest("GET", "/recipes/3/ingredients?units=Metric", nil))
+
+	assert.Equal(t, units.Metric, system)
+}
+
+func TestWithMeasurementSystem_InvalidQueryParameter(t *testing.T) {
+	setup := setupProfileHandlerTest()
+
+	_, recorder := serveWithMeasurementSystem(setup, httptest.NewRequest("GET", "/recipes/3/ingredients?units=nautical", nil))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestWithMeasurementSystem_ProfileErrorKeepsUnits(t *testing.T) {
+	logging.Init("test")
+	setup := setupProfileHandlerTest()
+	setup.profileService.On("GetSettings", 1).Return(nil, errors.New("database error"))
+
+	req := test.AddAuthContext(httptest.NewRequest("GET", "/recipes/3/ingredients", nil), 1, "test@example.com")
+	system, recorder := serveWithMeasurementSystem(setup, req)
+
+	assert.Equal(t, "", system)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestConvertIngredients(t *testing.T) {
+	ingredients := []models.RecipeIngredient{
+		{IngredientID: 1, Quantity: 2, Unit: "cups"},
+		{IngredientID: 2, Quantity: 3, Unit: "cloves"},
+	}
+
+	convertIngredients(ingredients, units.Metric)
+
+	assert.Equal(t, 473.18, ingredients[0].Quantity)
+	assert.Equal(t, units.Millilitre, ingredients[0].Unit)
+	assert.Equal(t, 3.0, ingredients[1].Quantity)
+	assert.Equal(t, "cloves", ingredients[1].Unit)
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"context"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBackfillService struct {
+	mock.Mock
+}
+
+func (m *MockBackfillService) StartBackfill(ctx context.Context, job string) (*models.BackfillRun, error) {
+	args := m.Called(ctx, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BackfillRun), args.Error(1)
+}
+
+func (m *MockBackfillService) GetBackfill(id int) (*models.BackfillRun, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BackfillRun), args.Error(1)
+}
+
+func (m *MockBackfillService) ListBackfills() []models.BackfillRun {
+	args := m.Called()
+	return args.Get(0).([]models.BackfillRun)
+}
+
+func (m *MockBackfillService) RunBackfill(ctx context.Context, job string, progress func(models.BackfillRun)) (*models.BackfillRun, error) {
+	args := m.Called(ctx, job, progress)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BackfillRun), args.Error(1)
+}
@@ -0,0 +1,98 @@
+package mocks
+
+import (
+	"context"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCookingService struct {
+	mock.Mock
+}
+
+func (m *MockCookingService) GetRecipeSteps(recipeID int) ([]models.RecipeStep, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeStep), args.Error(1)
+}
+
+func (m *MockCookingService) SetRecipeSteps(ctx context.Context, recipeID int, steps []models.CreateStepRequest) ([]models.RecipeStep, error) {
+	args := m.Called(recipeID, steps)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeStep), args.Error(1)
+}
+
+func (m *MockCookingService) AddRecipeStep(ctx context.Context, recipeID int, step models.CreateStepRequest) (*models.RecipeStep, error) {
+	args := m.Called(recipeID, step)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeStep), args.Error(1)
+}
+
+func (m *MockCookingService) UpdateRecipeStep(ctx context.Context, recipeID, stepID int, step models.CreateStepRequest) (*models.RecipeStep, error) {
+	args := m.Called(recipeID, stepID, step)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeStep), args.Error(1)
+}
+
+func (m *MockCookingService) DeleteRecipeStep(ctx context.Context, recipeID, stepID int) error {
+	args := m.Called(recipeID, stepID)
+	return args.Error(0)
+}
+
+func (m *MockCookingService) ReorderRecipeSteps(ctx context.Context, recipeID int, stepIDs []int) ([]models.RecipeStep, error) {
+	args := m.Called(recipeID, stepIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeStep), args.Error(1)
+}
+
+func (m *MockCookingService) GetRecipeTechniques(recipeID int) (*models.RecipeTechniques, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeTechniques), args.Error(1)
+}
+
+func (m *MockCookingService) SetRecipeTechniques(ctx context.Context, recipeID int, techniques []string) (*models.RecipeTechniques, error) {
+	args := m.Called(recipeID, techniques)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeTechniques), args.Error(1)
+}
+
+func (m *MockCookingService) StartSession(ctx context.Context, userID, recipeID int, req models.StartCookingSessionRequest) (*models.CookingSessionView, error) {
+	args := m.Called(userID, recipeID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CookingSessionView), args.Error(1)
+}
+
+func (m *MockCookingService) GetSession(ctx context.Context, userID int, token string) (*models.CookingSessionView, error) {
+	args := m.Called(userID, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CookingSessionView), args.Error(1)
+}
+
+func (m *MockCookingService) UpdateSession(ctx context.Context, userID int, token string, req models.UpdateCookingSessionRequest) (*models.CookingSessionView, error) {
+	args := m.Called(userID, token, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CookingSessionView), args.Error(1)
+}
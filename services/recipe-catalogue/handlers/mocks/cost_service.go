@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"meal-prep/shared/events"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCostService struct {
+	mock.Mock
+}
+
+func (m *MockCostService) SetIngredientPrice(userID, ingredientID int, req models.SetIngredientPriceRequest) (*models.IngredientPrice, error) {
+	args := m.Called(userID, ingredientID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientPrice), args.Error(1)
+}
+
+func (m *MockCostService) GetIngredientPrices(ingredientID int) ([]models.IngredientPrice, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IngredientPrice), args.Error(1)
+}
+
+func (m *MockCostService) GetRecipeCostHistory(recipeID int) (*models.RecipeCostHistory, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeCostHistory), args.Error(1)
+}
+
+func (m *MockCostService) SnapshotRecipeCosts(event events.Event) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockCostService) RefreshRecipeCosts(recipeIDs []int) (int, error) {
+	args := m.Called(recipeIDs)
+	return args.Int(0), args.Error(1)
+}
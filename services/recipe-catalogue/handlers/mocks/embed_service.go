@@ -0,0 +1,19 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEmbedService struct {
+	mock.Mock
+}
+
+func (m *MockEmbedService) GetRecipeEmbed(recipeID int) (*models.RecipeEmbed, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeEmbed), args.Error(1)
+}
@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"context"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFavoriteService struct {
+	mock.Mock
+}
+
+func (m *MockFavoriteService) AddFavorite(ctx context.Context, userID, recipeID int) error {
+	args := m.Called(userID, recipeID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteService) RemoveFavorite(userID, recipeID int) error {
+	args := m.Called(userID, recipeID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteService) GetFavorites(userID int, params models.PaginationParams) ([]models.Recipe, models.PaginationMeta, error) {
+	args := m.Called(userID, params)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(models.PaginationMeta), args.Error(2)
+	}
+	return args.Get(0).([]models.Recipe), args.Get(1).(models.PaginationMeta), args.Error(2)
+}
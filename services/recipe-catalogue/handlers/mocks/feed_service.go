@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFeedService struct {
+	mock.Mock
+}
+
+func (m *MockFeedService) GetSitemapIndex() ([]models.SitemapChunk, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SitemapChunk), args.Error(1)
+}
+
+func (m *MockFeedService) GetSitemapChunk(number int) ([]models.PublishedRecipe, error) {
+	args := m.Called(number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PublishedRecipe), args.Error(1)
+}
+
+func (m *MockFeedService) GetRecipeFeed() ([]models.Recipe, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Recipe), args.Error(1)
+}
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"meal-prep/shared/models"
 
 	"github.com/stretchr/testify/mock"
@@ -10,10 +11,47 @@ type MockGroceryService struct {
 	mock.Mock
 }
 
-func (m *MockGroceryService) GenerateGroceryList(req models.GroceryListRequest) ([]models.GroceryListItem, error) {
-	args := m.Called(req)
+func (m *MockGroceryService) GenerateGroceryList(ctx context.Context, userID int, req models.GroceryListRequest) ([]models.GroceryListItem, error) {
+	args := m.Called(userID, req)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.GroceryListItem), args.Error(1)
 }
+
+func (m *MockGroceryService) GetStoreLayouts(userID int) ([]models.StoreLayout, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StoreLayout), args.Error(1)
+}
+
+func (m *MockGroceryService) CreateStoreLayout(userID int, req models.StoreLayoutRequest) (*models.StoreLayout, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StoreLayout), args.Error(1)
+}
+
+func (m *MockGroceryService) UpdateStoreLayout(ctx context.Context, userID, layoutID int, req models.StoreLayoutRequest) (*models.StoreLayout, error) {
+	args := m.Called(userID, layoutID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StoreLayout), args.Error(1)
+}
+
+func (m *MockGroceryService) DeleteStoreLayout(ctx context.Context, userID, layoutID int) error {
+	args := m.Called(userID, layoutID)
+	return args.Error(0)
+}
+
+func (m *MockGroceryService) GenerateBudgetedGroceryList(ctx context.Context, userID int, req models.GroceryListRequest) (*models.BudgetedGroceryList, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BudgetedGroceryList), args.Error(1)
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"context"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHouseholdService struct {
+	mock.Mock
+}
+
+func (m *MockHouseholdService) CreateHousehold(userID int, req models.CreateHouseholdRequest) (*models.Household, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Household), args.Error(1)
+}
+
+func (m *MockHouseholdService) GetHousehold(ctx context.Context, userID int) (*models.Household, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Household), args.Error(1)
+}
+
+func (m *MockHouseholdService) JoinHousehold(userID int, req models.JoinHouseholdRequest) (*models.Household, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Household), args.Error(1)
+}
+
+func (m *MockHouseholdService) RemoveMember(ctx context.Context, userID, memberID int) error {
+	args := m.Called(userID, memberID)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdService) Households(userID int) ([]int, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
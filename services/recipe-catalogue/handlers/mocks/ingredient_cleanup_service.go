@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIngredientCleanupService struct {
+	mock.Mock
+}
+
+func (m *MockIngredientCleanupService) ListUnusedIngredients(months int, params models.PaginationParams) ([]models.UnusedIngredient, models.PaginationMeta, error) {
+	args := m.Called(months, params)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(models.PaginationMeta), args.Error(2)
+	}
+	return args.Get(0).([]models.UnusedIngredient), args.Get(1).(models.PaginationMeta), args.Error(2)
+}
+
+func (m *MockIngredientCleanupService) CleanupIngredients(months int, req models.IngredientCleanupRequest) (*models.IngredientCleanupResult, error) {
+	args := m.Called(months, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientCleanupResult), args.Error(1)
+}
+
+func (m *MockIngredientCleanupService) ArchiveUnusedIngredients(now time.Time) ([]int, error) {
+	args := m.Called(now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockIngredientCleanupService) FindDuplicateIngredients(minSimilarity float64, limit int) ([]models.DuplicateIngredients, error) {
+	args := m.Called(minSimilarity, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DuplicateIngredients), args.Error(1)
+}
+
+func (m *MockIngredientCleanupService) MergeIngredients(ctx context.Context, duplicateID, targetID int) (*models.IngredientMergeResult, error) {
+	args := m.Called(ctx, duplicateID, targetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientMergeResult), args.Error(1)
+}
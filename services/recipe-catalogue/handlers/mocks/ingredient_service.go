@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"meal-prep/shared/models"
 
 	"github.com/stretchr/testify/mock"
@@ -47,6 +48,14 @@ func (m *MockIngredientService) SearchIngredients(query string, params models.Pa
 	return args.Get(0).([]models.Ingredient), args.Get(1).(models.PaginationMeta), args.Error(2)
 }
 
+func (m *MockIngredientService) SuggestIngredients(query string) ([]string, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockIngredientService) CreateIngredient(req models.CreateIngredientRequest) (*models.Ingredient, error) {
 	args := m.Called(req)
 	if args.Get(0) == nil {
@@ -117,3 +126,194 @@ func (m *MockIngredientService) GetRecipesUsingIngredient(ingredientID int, para
 	}
 	return args.Get(0).([]models.Recipe), args.Get(1).(models.PaginationMeta), args.Error(2)
 }
+
+func (m *MockIngredientService) GetPopularIngredients(limit int) ([]models.IngredientUsage, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IngredientUsage), args.Error(1)
+}
+
+func (m *MockIngredientService) GetIngredientDensity(ingredientID int) (*models.IngredientDensity, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientDensity), args.Error(1)
+}
+
+func (m *MockIngredientService) ListIngredientDensities(params models.PaginationParams) ([]models.IngredientDensity, models.PaginationMeta, error) {
+	args := m.Called(params)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(models.PaginationMeta), args.Error(2)
+	}
+	return args.Get(0).([]models.IngredientDensity), args.Get(1).(models.PaginationMeta), args.Error(2)
+}
+
+func (m *MockIngredientService) SetIngredientDensity(ingredientID int, req models.SetIngredientDensityRequest) (*models.IngredientDensity, error) {
+	args := m.Called(ingredientID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientDensity), args.Error(1)
+}
+
+func (m *MockIngredientService) DeleteIngredientDensity(ingredientID int) error {
+	args := m.Called(ingredientID)
+	return args.Error(0)
+}
+
+func (m *MockIngredientService) GetPackSizes(ingredientID int) ([]models.PackSize, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PackSize), args.Error(1)
+}
+
+func (m *MockIngredientService) SetPackSizes(ingredientID int, packSizes []models.PackSizeRequest) ([]models.PackSize, error) {
+	args := m.Called(ingredientID, packSizes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PackSize), args.Error(1)
+}
+
+func (m *MockIngredientService) GetIngredientProducts(ingredientID int) ([]models.IngredientProduct, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IngredientProduct), args.Error(1)
+}
+
+func (m *MockIngredientService) CreateIngredientProduct(ingredientID int, req models.IngredientProductRequest) (*models.IngredientProduct, error) {
+	args := m.Called(ingredientID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientProduct), args.Error(1)
+}
+
+func (m *MockIngredientService) UpdateIngredientProduct(ingredientID, productID int, req models.IngredientProductRequest) (*models.IngredientProduct, error) {
+	args := m.Called(ingredientID, productID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientProduct), args.Error(1)
+}
+
+func (m *MockIngredientService) DeleteIngredientProduct(ingredientID, productID int) error {
+	args := m.Called(ingredientID, productID)
+	return args.Error(0)
+}
+
+func (m *MockIngredientService) GetSubstitutions(ingredientID int) ([]models.IngredientSubstitution, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IngredientSubstitution), args.Error(1)
+}
+
+func (m *MockIngredientService) SetSubstitutions(ingredientID int, substitutions []models.SubstitutionRequest) ([]models.IngredientSubstitution, error) {
+	args := m.Called(ingredientID, substitutions)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IngredientSubstitution), args.Error(1)
+}
+
+func (m *MockIngredientService) GetTranslations(ingredientID int) ([]models.IngredientTranslation, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IngredientTranslation), args.Error(1)
+}
+
+func (m *MockIngredientService) SetTranslations(ingredientID int, translations []models.IngredientTranslation) ([]models.IngredientTranslation, error) {
+	args := m.Called(ingredientID, translations)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IngredientTranslation), args.Error(1)
+}
+
+func (m *MockIngredientService) GetLocalizedNames(ingredientIDs []int, locales []string) (map[int]string, error) {
+	args := m.Called(ingredientIDs, locales)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]string), args.Error(1)
+}
+
+func (m *MockIngredientService) GetIngredientDietary(ingredientID int) (*models.IngredientDietary, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientDietary), args.Error(1)
+}
+
+func (m *MockIngredientService) SetIngredientDietary(ingredientID int, facts models.DietaryFacts) (*models.IngredientDietary, error) {
+	args := m.Called(ingredientID, facts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientDietary), args.Error(1)
+}
+
+func (m *MockIngredientService) GetRecipeDietary(recipeID int) (*models.RecipeDietary, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeDietary), args.Error(1)
+}
+
+func (m *MockIngredientService) FilterCompliance(ctx context.Context, userID int, req models.ComplianceRequest) (*models.ComplianceResult, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ComplianceResult), args.Error(1)
+}
+
+func (m *MockIngredientService) GetAllergens() ([]models.Allergen, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Allergen), args.Error(1)
+}
+
+func (m *MockIngredientService) GetIngredientAllergens(ingredientID int) ([]models.Allergen, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Allergen), args.Error(1)
+}
+
+func (m *MockIngredientService) SetIngredientAllergens(ingredientID int, codes []string) ([]models.Allergen, error) {
+	args := m.Called(ingredientID, codes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Allergen), args.Error(1)
+}
+
+func (m *MockIngredientService) AddIngredientAllergen(ingredientID int, code string) ([]models.Allergen, error) {
+	args := m.Called(ingredientID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Allergen), args.Error(1)
+}
+
+func (m *MockIngredientService) RemoveIngredientAllergen(ingredientID int, code string) error {
+	args := m.Called(ingredientID, code)
+	return args.Error(0)
+}
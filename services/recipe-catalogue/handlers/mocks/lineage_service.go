@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLineageService struct {
+	mock.Mock
+}
+
+func (m *MockLineageService) ForkRecipe(ctx context.Context, userID, recipeID int, req models.ForkRecipeRequest) (*models.RecipeWithIngredients, error) {
+	args := m.Called(userID, recipeID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeWithIngredients), args.Error(1)
+}
+
+func (m *MockLineageService) GetLineage(recipeID int) (*models.RecipeLineage, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeLineage), args.Error(1)
+}
+
+func (m *MockLineageService) GetForks(recipeID int) ([]models.RecipeFork, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeFork), args.Error(1)
+}
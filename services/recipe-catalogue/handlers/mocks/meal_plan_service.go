@@ -0,0 +1,119 @@
+package mocks
+
+import (
+	"context"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMealPlanService struct {
+	mock.Mock
+}
+
+func (m *MockMealPlanService) GetMealPlans(userID int) ([]models.MealPlan, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MealPlan), args.Error(1)
+}
+
+func (m *MockMealPlanService) GetMealPlan(ctx context.Context, userID, planID int) (*models.MealPlan, error) {
+	args := m.Called(userID, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlan), args.Error(1)
+}
+
+func (m *MockMealPlanService) CreateMealPlan(userID int, req models.MealPlanRequest) (*models.MealPlan, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlan), args.Error(1)
+}
+
+func (m *MockMealPlanService) UpdateMealPlan(ctx context.Context, userID, planID int, req models.MealPlanRequest) (*models.MealPlan, error) {
+	args := m.Called(userID, planID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlan), args.Error(1)
+}
+
+func (m *MockMealPlanService) DeleteMealPlan(ctx context.Context, userID, planID int) error {
+	args := m.Called(userID, planID)
+	return args.Error(0)
+}
+
+func (m *MockMealPlanService) AddEntry(ctx context.Context, userID, planID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error) {
+	args := m.Called(userID, planID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlanEntry), args.Error(1)
+}
+
+func (m *MockMealPlanService) UpdateEntry(ctx context.Context, userID, planID, entryID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error) {
+	args := m.Called(userID, planID, entryID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlanEntry), args.Error(1)
+}
+
+func (m *MockMealPlanService) DeleteEntry(ctx context.Context, userID, planID, entryID int) error {
+	args := m.Called(userID, planID, entryID)
+	return args.Error(0)
+}
+
+func (m *MockMealPlanService) SetMembers(ctx context.Context, userID, planID int, req models.MealPlanMembersRequest) (*models.MealPlan, error) {
+	args := m.Called(userID, planID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlan), args.Error(1)
+}
+
+func (m *MockMealPlanService) GetMemberMeals(ctx context.Context, userID, planID, memberID int) ([]models.MealPlanEntry, error) {
+	args := m.Called(userID, planID, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MealPlanEntry), args.Error(1)
+}
+
+func (m *MockMealPlanService) GenerateMealPlan(ctx context.Context, userID int, req models.GenerateMealPlanRequest) (*models.MealPlan, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlan), args.Error(1)
+}
+
+func (m *MockMealPlanService) GetPreferences(userID int) (*models.MealPlanPreferences, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlanPreferences), args.Error(1)
+}
+
+func (m *MockMealPlanService) UpdatePreferences(userID int, req models.MealPlanPreferencesRequest) (*models.MealPlanPreferences, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlanPreferences), args.Error(1)
+}
+
+func (m *MockMealPlanService) GetPrintout(ctx context.Context, userID, planID int, measurementSystem string) (*models.MealPlanPrintout, error) {
+	args := m.Called(userID, planID, measurementSystem)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlanPrintout), args.Error(1)
+}
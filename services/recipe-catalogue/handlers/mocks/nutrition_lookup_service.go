@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNutritionLookupService struct {
+	mock.Mock
+}
+
+func (m *MockNutritionLookupService) LookupNutrition(ctx context.Context, query string) (*models.NutritionLookup, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NutritionLookup), args.Error(1)
+}
@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPantryService struct {
+	mock.Mock
+}
+
+func (m *MockPantryService) GetPantry(userID int) (*models.Pantry, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Pantry), args.Error(1)
+}
+
+func (m *MockPantryService) UpdatePantry(userID int, req models.UpdatePantryRequest) (*models.Pantry, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Pantry), args.Error(1)
+}
+
+func (m *MockPantryService) AddPantryItem(userID, ingredientID int) (*models.PantryChange, error) {
+	args := m.Called(userID, ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PantryChange), args.Error(1)
+}
+
+func (m *MockPantryService) RemovePantryItem(userID, ingredientID int) (*models.PantryChange, error) {
+	args := m.Called(userID, ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PantryChange), args.Error(1)
+}
+
+// Watch expects a chan models.PantryChange, which the test closes to end the
+// stream.
+func (m *MockPantryService) Watch(userID int) (<-chan models.PantryChange, func(), error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(1)
+	}
+	return args.Get(0).(chan models.PantryChange), func() {}, args.Error(1)
+}
+
+func (m *MockPantryService) GetCookableRecipes(userID int, ingredientIDs []int, params models.PaginationParams) ([]models.CookableRecipe, models.PaginationMeta, error) {
+	args := m.Called(userID, ingredientIDs, params)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(models.PaginationMeta), args.Error(2)
+	}
+	return args.Get(0).([]models.CookableRecipe), args.Get(1).(models.PaginationMeta), args.Error(2)
+}
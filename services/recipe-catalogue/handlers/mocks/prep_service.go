@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"context"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPrepService struct {
+	mock.Mock
+}
+
+func (m *MockPrepService) GetSessions(userID int) ([]models.PrepSession, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PrepSession), args.Error(1)
+}
+
+func (m *MockPrepService) GetSession(ctx context.Context, userID, sessionID int) (*models.PrepSessionPlan, error) {
+	args := m.Called(userID, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PrepSessionPlan), args.Error(1)
+}
+
+func (m *MockPrepService) CreateSession(ctx context.Context, userID int, req models.PrepSessionRequest) (*models.PrepSessionPlan, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PrepSessionPlan), args.Error(1)
+}
+
+func (m *MockPrepService) UpdateSession(ctx context.Context, userID, sessionID int, req models.PrepSessionRequest) (*models.PrepSessionPlan, error) {
+	args := m.Called(userID, sessionID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PrepSessionPlan), args.Error(1)
+}
+
+func (m *MockPrepService) DeleteSession(ctx context.Context, userID, sessionID int) error {
+	args := m.Called(userID, sessionID)
+	return args.Error(0)
+}
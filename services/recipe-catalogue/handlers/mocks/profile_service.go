@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProfileService struct {
+	mock.Mock
+}
+
+func (m *MockProfileService) GetPublicProfile(userID int) (*models.UserProfile, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserProfile), args.Error(1)
+}
+
+func (m *MockProfileService) GetSettings(userID int) (*models.ProfileSettings, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProfileSettings), args.Error(1)
+}
+
+func (m *MockProfileService) UpdateSettings(userID int, req models.UpdateProfileSettingsRequest) (*models.ProfileSettings, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProfileSettings), args.Error(1)
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"context"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecipeQualityService struct {
+	mock.Mock
+}
+
+func (m *MockRecipeQualityService) GetRecipeQuality(ctx context.Context, userID, recipeID int) (*models.RecipeQuality, error) {
+	args := m.Called(userID, recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeQuality), args.Error(1)
+}
+
+func (m *MockRecipeQualityService) ListRecipeQuality(filter models.RecipeQualityFilter, params models.PaginationParams) ([]models.RecipeQuality, models.PaginationMeta, error) {
+	args := m.Called(filter, params)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(models.PaginationMeta), args.Error(2)
+	}
+	return args.Get(0).([]models.RecipeQuality), args.Get(1).(models.PaginationMeta), args.Error(2)
+}
@@ -0,0 +1,66 @@
+package mocks
+
+import (
+	"context"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecipeImageService struct {
+	mock.Mock
+}
+
+func (m *MockRecipeImageService) GetRecipeImages(recipeID int) ([]models.RecipeImage, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeImage), args.Error(1)
+}
+
+func (m *MockRecipeImageService) AddRecipeImage(ctx context.Context, recipeID int, req models.AddRecipeImageRequest) (*models.RecipeImage, error) {
+	args := m.Called(recipeID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeImage), args.Error(1)
+}
+
+func (m *MockRecipeImageService) UpdateRecipeImages(ctx context.Context, recipeID int, req models.UpdateRecipeImagesRequest) ([]models.RecipeImage, error) {
+	args := m.Called(recipeID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeImage), args.Error(1)
+}
+
+func (m *MockRecipeImageService) DeleteRecipeImage(ctx context.Context, recipeID, imageID int) error {
+	args := m.Called(recipeID, imageID)
+	return args.Error(0)
+}
+
+func (m *MockRecipeImageService) UploadRecipeImage(ctx context.Context, recipeID int, upload models.ImageUpload) (*models.RecipeImage, error) {
+	args := m.Called(recipeID, upload)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeImage), args.Error(1)
+}
+
+func (m *MockRecipeImageService) GetStoredImage(key string) ([]byte, string, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).([]byte), args.String(1), args.Error(2)
+}
+
+func (m *MockRecipeImageService) GetShareCard(recipeID int) (*models.RecipeShareCard, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeShareCard), args.Error(1)
+}
@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecipeImportService struct {
+	mock.Mock
+}
+
+func (m *MockRecipeImportService) ImportFromURL(ctx context.Context, userID int, req models.ImportRecipeRequest) (*models.ImportedRecipe, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportedRecipe), args.Error(1)
+}
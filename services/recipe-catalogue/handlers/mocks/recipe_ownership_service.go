@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecipeOwnershipService struct {
+	mock.Mock
+}
+
+func (m *MockRecipeOwnershipService) ListRecipeOwners(inactiveDays int, params models.PaginationParams) ([]models.RecipeOwnership, models.PaginationMeta, error) {
+	args := m.Called(inactiveDays, params)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(models.PaginationMeta), args.Error(2)
+	}
+	return args.Get(0).([]models.RecipeOwnership), args.Get(1).(models.PaginationMeta), args.Error(2)
+}
+
+func (m *MockRecipeOwnershipService) ExportRecipeOwners(inactiveDays int) ([]models.RecipeOwnership, error) {
+	args := m.Called(inactiveDays)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeOwnership), args.Error(1)
+}
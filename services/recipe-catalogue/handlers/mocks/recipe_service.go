@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"meal-prep/shared/models"
 
 	"github.com/stretchr/testify/mock"
@@ -39,6 +41,14 @@ func (m *MockRecipeService) GetRecipesByCategory(categoryID int, params models.P
 	return args.Get(0).([]models.Recipe), args.Get(1).(models.PaginationMeta), args.Error(2)
 }
 
+func (m *MockRecipeService) GetMyRecipes(userID int, status string, params models.PaginationParams) ([]models.Recipe, models.PaginationMeta, error) {
+	args := m.Called(userID, status, params)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(models.PaginationMeta), args.Error(2)
+	}
+	return args.Get(0).([]models.Recipe), args.Get(1).(models.PaginationMeta), args.Error(2)
+}
+
 func (m *MockRecipeService) CreateRecipe(userID int, req models.CreateRecipeRequest) (*models.Recipe, error) {
 	args := m.Called(userID, req)
 	if args.Get(0) == nil {
@@ -47,16 +57,16 @@ func (m *MockRecipeService) CreateRecipe(userID int, req models.CreateRecipeRequ
 	return args.Get(0).(*models.Recipe), args.Error(1)
 }
 
-func (m *MockRecipeService) UpdateRecipe(userID int, id int, req models.UpdateRecipeRequest) (*models.Recipe, error) {
-	args := m.Called(userID, id, req)
+func (m *MockRecipeService) UpdateRecipe(ctx context.Context, id int, req models.UpdateRecipeRequest) (*models.Recipe, error) {
+	args := m.Called(id, req)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Recipe), args.Error(1)
 }
 
-func (m *MockRecipeService) DeleteRecipe(userID int, id int) error {
-	args := m.Called(userID, id)
+func (m *MockRecipeService) DeleteRecipe(ctx context.Context, id int) error {
+	args := m.Called(id)
 	return args.Error(0)
 }
 
@@ -104,8 +114,8 @@ func (m *MockRecipeService) CreateRecipeWithIngredients(userID int, req models.C
 	return args.Get(0).(*models.RecipeWithIngredients), args.Error(1)
 }
 
-func (m *MockRecipeService) UpdateRecipeWithIngredients(userID int, id int, req models.UpdateRecipeWithIngredientsRequest) (*models.RecipeWithIngredients, error) {
-	args := m.Called(userID, id, req)
+func (m *MockRecipeService) UpdateRecipeWithIngredients(ctx context.Context, id int, req models.UpdateRecipeWithIngredientsRequest) (*models.RecipeWithIngredients, error) {
+	args := m.Called(id, req)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -131,3 +141,19 @@ func (m *MockRecipeService) SearchRecipesByIngredientsWithIngredients(ingredient
 	}
 	return args.Get(0).([]models.RecipeWithIngredients), args.Get(1).(models.PaginationMeta), args.Error(2)
 }
+
+func (m *MockRecipeService) GetSearchFacets(ingredientIDs []int) (*models.RecipeFacets, error) {
+	args := m.Called(ingredientIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeFacets), args.Error(1)
+}
+
+func (m *MockRecipeService) CompareRecipes(ctx context.Context, userID, firstID, secondID int) (*models.RecipeComparison, error) {
+	args := m.Called(userID, firstID, secondID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeComparison), args.Error(1)
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"context"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecipeTemplateService struct {
+	mock.Mock
+}
+
+func (m *MockRecipeTemplateService) GetTemplates() ([]models.RecipeTemplate, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeTemplate), args.Error(1)
+}
+
+func (m *MockRecipeTemplateService) GetTemplate(id int) (*models.RecipeTemplate, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeTemplate), args.Error(1)
+}
+
+func (m *MockRecipeTemplateService) CreateTemplate(ctx context.Context, userID int, req models.CreateRecipeTemplateRequest) (*models.RecipeTemplate, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeTemplate), args.Error(1)
+}
+
+func (m *MockRecipeTemplateService) DeleteTemplate(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRecipeTemplateService) InstantiateTemplate(userID, id int, req models.InstantiateTemplateRequest) (*models.RecipeWithIngredients, error) {
+	args := m.Called(userID, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeWithIngredients), args.Error(1)
+}
@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSavedSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchService) GetSavedSearches(userID int) ([]models.SavedSearch, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchService) CreateSavedSearch(userID int, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchService) UpdateSavedSearch(ctx context.Context, userID, searchID int, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	args := m.Called(userID, searchID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchService) DeleteSavedSearch(ctx context.Context, userID, searchID int) error {
+	args := m.Called(userID, searchID)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchService) CheckAlerts(now time.Time) (int, error) {
+	args := m.Called(now)
+	return args.Int(0), args.Error(1)
+}
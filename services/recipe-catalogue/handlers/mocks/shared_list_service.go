@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"context"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSharedListService struct {
+	mock.Mock
+}
+
+func (m *MockSharedListService) ShareGroceryList(userID int, items []models.GroceryListItem) (*models.SharedShoppingList, error) {
+	args := m.Called(userID, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SharedShoppingList), args.Error(1)
+}
+
+func (m *MockSharedListService) RevokeSharedList(ctx context.Context, userID int, token string) error {
+	args := m.Called(userID, token)
+	return args.Error(0)
+}
+
+func (m *MockSharedListService) GetSharedList(token string) (*models.SharedShoppingList, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SharedShoppingList), args.Error(1)
+}
+
+func (m *MockSharedListService) CheckItem(token string, position int, checked bool) (*models.SharedShoppingListChange, error) {
+	args := m.Called(token, position, checked)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SharedShoppingListChange), args.Error(1)
+}
+
+// Watch expects a chan models.SharedShoppingListChange, which the test closes
+// to end the stream.
+func (m *MockSharedListService) Watch(token string) (<-chan models.SharedShoppingListChange, func(), error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(1)
+	}
+	return args.Get(0).(chan models.SharedShoppingListChange), func() {}, args.Error(1)
+}
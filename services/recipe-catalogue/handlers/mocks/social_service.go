@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"meal-prep/shared/events"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSocialService struct {
+	mock.Mock
+}
+
+func (m *MockSocialService) Follow(userID, targetID int) error {
+	args := m.Called(userID, targetID)
+	return args.Error(0)
+}
+
+func (m *MockSocialService) Unfollow(userID, targetID int) error {
+	args := m.Called(userID, targetID)
+	return args.Error(0)
+}
+
+func (m *MockSocialService) GetFollowing(userID int) ([]models.Follow, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Follow), args.Error(1)
+}
+
+func (m *MockSocialService) GetFollowers(userID int) ([]models.Follow, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Follow), args.Error(1)
+}
+
+func (m *MockSocialService) GetFeed(userID int, params models.PaginationParams) ([]models.Activity, models.PaginationMeta, error) {
+	args := m.Called(userID, params)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(models.PaginationMeta), args.Error(2)
+	}
+	return args.Get(0).([]models.Activity), args.Get(1).(models.PaginationMeta), args.Error(2)
+}
+
+func (m *MockSocialService) RecordActivity(event events.Event) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
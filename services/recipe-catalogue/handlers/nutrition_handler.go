@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/metrics"
+	"meal-prep/shared/models"
+)
+
+const (
+	defaultNutritionAPIURL = "https://api.nal.usda.gov/fdc/v1/foods/search"
+	nutritionAPITimeout    = 5 * time.Second
+)
+
+// NutritionHandler lets clients preview nutrition from the external
+// nutrition API without holding its key themselves.
+type NutritionHandler struct {
+	nutritionService service.NutritionLookupService
+}
+
+func NewNutritionHandler(nutritionService service.NutritionLookupService) *NutritionHandler {
+	return &NutritionHandler{nutritionService: nutritionService}
+}
+
+// NutritionSourceFromEnv searches FoodData Central, or the search endpoint at
+// NUTRITION_API_URL, with the key in NUTRITION_API_KEY. It returns nil when
+// no key is set, leaving lookups unavailable.
+func NutritionSourceFromEnv() service.NutritionSource {
+	apiKey := os.Getenv("NUTRITION_API_KEY")
+	if apiKey == "" {
+		return nil
+	}
+	endpoint := os.Getenv("NUTRITION_API_URL")
+	if endpoint == "" {
+		endpoint = defaultNutritionAPIURL
+	}
+	client := &http.Client{
+		Timeout:   nutritionAPITimeout,
+		Transport: metrics.Transport("nutrition-api", http.DefaultTransport),
+	}
+	return service.NewFoodDataClient(endpoint, apiKey, client)
+}
+
+func (h *NutritionHandler) LookupNutrition(w http.ResponseWriter, r *http.Request) {
+	lookup, err := h.nutritionService.LookupNutrition(r.Context(), r.URL.Query().Get("query"))
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidNutritionQuery:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrNutritionLookupLimited:
+			// The cap is per clock minute
+			w.Header().Set("Retry-After", "60")
+			models.WriteErrorResponse(w, err.Error(), http.StatusTooManyRequests)
+		case domain.ErrNutritionLookupUnavailable:
+			models.WriteErrorResponse(w, err.Error(), http.StatusServiceUnavailable)
+		case domain.ErrNutritionSourceUnavailable:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadGateway)
+		default:
+			models.WriteErrorResponse(w, "Failed to look up nutrition", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Answers change rarely, so browsers may reuse them while authoring
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	models.WriteSuccessResponse(w, lookup, http.StatusOK)
+}
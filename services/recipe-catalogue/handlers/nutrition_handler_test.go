@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNutritionHandler_LookupNutrition(t *testing.T) {
+	nutritionService := new(mocks.MockNutritionLookupService)
+	lookup := &models.NutritionLookup{Query: "rolled oats", Matches: []models.NutritionMatch{
+		{SourceID: "173904", Name: "Oats", Nutrition: models.ProductNutrition{CaloriesKcal: 389, ProteinG: 16.9}},
+	}}
+	nutritionService.On("LookupNutrition", mock.Anything, "Rolled oats").Return(lookup, nil)
+
+	req := httptest.NewRequest("GET", "/ingredients/nutrition-lookup?query=Rolled+oats", nil)
+	recorder := httptest.NewRecorder()
+
+	NewNutritionHandler(nutritionService).LookupNutrition(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "public, max-age=3600", recorder.Header().Get("Cache-Control"))
+	assert.Contains(t, recorder.Body.String(), `"calories_kcal":389`)
+	nutritionService.AssertExpectations(t)
+}
+
+func TestNutritionHandler_LookupNutrition_Errors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{domain.ErrInvalidNutritionQuery, http.StatusBadRequest},
+		{domain.ErrNutritionLookupLimited, http.StatusTooManyRequests},
+		{domain.ErrNutritionLookupUnavailable, http.StatusServiceUnavailable},
+		{domain.ErrNutritionSourceUnavailable, http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			nutritionService := new(mocks.MockNutritionLookupService)
+			nutritionService.On("LookupNutrition", mock.Anything, "oats").Return(nil, tt.err)
+
+			req := httptest.NewRequest("GET", "/ingredients/nutrition-lookup?query=oats", nil)
+			recorder := httptest.NewRecorder()
+
+			NewNutritionHandler(nutritionService).LookupNutrition(recorder, req)
+
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.Empty(t, recorder.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestNutritionHandler_LookupNutrition_LimitedSetsRetryAfter(t *testing.T) {
+	nutritionService := new(mocks.MockNutritionLookupService)
+	nutritionService.On("LookupNutrition", mock.Anything, "oats").Return(nil, domain.ErrNutritionLookupLimited)
+
+	req := httptest.NewRequest("GET", "/ingredients/nutrition-lookup?query=oats", nil)
+	recorder := httptest.NewRecorder()
+
+	NewNutritionHandler(nutritionService).LookupNutrition(recorder, req)
+
+	assert.Equal(t, "60", recorder.Header().Get("Retry-After"))
+}
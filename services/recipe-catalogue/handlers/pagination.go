@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"net/http"
+
+	"meal-prep/shared/models"
+)
+
+// parsePagination reads ?page= and ?per_page=, answering 400 when either is
+// out of range.
+func parsePagination(w http.ResponseWriter, r *http.Request) (models.PaginationParams, bool) {
+	params, err := models.ParsePaginationParams(r)
+	if err != nil {
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return models.PaginationParams{}, false
+	}
+	return params, true
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+type PantryHandler struct {
+	pantryService service.PantryService
+}
+
+func NewPantryHandler(pantryService service.PantryService) *PantryHandler {
+	return &PantryHandler{pantryService: pantryService}
+}
+
+func (h *PantryHandler) GetPantry(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	pantry, err := h.pantryService.GetPantry(user.UserID)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch pantry", http.StatusInternalServerError)
+		return
+	}
+
+	models.SetETag(w, pantry.Version)
+	models.WriteSuccessResponse(w, pantry, http.StatusOK)
+}
+
+func (h *PantryHandler) UpdatePantry(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdatePantryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	// The version may come in the body or as If-Match; the body wins
+	ifMatch, ok := models.IfMatchVersion(r)
+	if !ok {
+		models.WriteErrorResponse(w, "Invalid If-Match header", http.StatusBadRequest)
+		return
+	}
+	if req.Version == nil {
+		req.Version = ifMatch
+	}
+
+	pantry, err := h.pantryService.UpdatePantry(user.UserID, req)
+	var conflict *models.VersionConflictError
+	if errors.As(err, &conflict) {
+		models.WriteVersionConflictResponse(w, conflict.CurrentVersion)
+		return
+	}
+	if err != nil {
+		writePantryError(w, err, "Failed to update pantry")
+		return
+	}
+
+	models.SetETag(w, pantry.Version)
+	models.WriteSuccessResponse(w, pantry, http.StatusOK)
+}
+
+// AddItem serves PUT /me/pantry/items/{ingredient_id}. Adding an ingredient
+// already there changes nothing.
+func (h *PantryHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	h.changeItem(w, r, h.pantryService.AddPantryItem, "Failed to add to pantry")
+}
+
+func (h *PantryHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	h.changeItem(w, r, h.pantryService.RemovePantryItem, "Failed to remove from pantry")
+}
+
+func (h *PantryHandler) changeItem(w http.ResponseWriter, r *http.Request, change func(userID, ingredientID int) (*models.PantryChange, error), fallback string) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	ingredientID, err := strconv.Atoi(mux.Vars(r)["ingredient_id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid ingredient ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := change(user.UserID, ingredientID)
+	if err != nil {
+		writePantryError(w, err, fallback)
+		return
+	}
+
+	models.SetETag(w, result.Version)
+	models.WriteSuccessResponse(w, result, http.StatusOK)
+}
+
+// StreamPantry serves GET /me/pantry/events, a server-sent event stream: a
+// "pantry" event with the whole pantry, then a "change" event for every
+// edit, each with the version as its id. The stream ends when the user
+// joins or leaves a household or falls behind; reconnecting starts again
+// with a fresh pantry.
+func (h *PantryHandler) StreamPantry(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	changes, stop, err := h.pantryService.Watch(user.UserID)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to open pantry", http.StatusInternalServerError)
+		return
+	}
+	defer stop()
+
+	// Loaded after subscribing so no change is missed in between; changes
+	// already in the pantry are skipped by their version
+	pantry, err := h.pantryService.GetPantry(user.UserID)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to open pantry", http.StatusInternalServerError)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeServerSentEvent(w, "pantry", pantry.Version, pantry); err != nil {
+		return
+	}
+	_ = controller.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			if change.Version <= pantry.Version {
+				continue
+			}
+			if err := writeServerSentEvent(w, "change", change.Version, change); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		_ = controller.Flush()
+	}
+}
+
+// GetCookableRecipes serves GET /recipes/cookable, ranking recipes by how
+// much of them the caller's pantry covers, or the ingredients in
+// ?ingredient_ids= when given.
+func (h *PantryHandler) GetCookableRecipes(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var ingredientIDs []int
+	if raw := r.URL.Query().Get("ingredient_ids"); raw != "" {
+		var ok bool
+		if ingredientIDs, ok = parseIngredientIDList(raw); !ok {
+			models.WriteErrorResponse(w, "Invalid ingredient IDs format. Use comma-separated integers", http.StatusBadRequest)
+			return
+		}
+	}
+
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	recipes, meta, err := h.pantryService.GetCookableRecipes(user.UserID, ingredientIDs, params)
+	if err != nil {
+		writePantryError(w, err, "Failed to find cookable recipes")
+		return
+	}
+
+	models.WritePaginatedResponse(w, recipes, meta, http.StatusOK)
+}
+
+// parseIngredientIDList reads comma-separated positive IDs, skipping blanks.
+func parseIngredientIDList(raw string) ([]int, bool) {
+	ids := make([]int, 0)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.Atoi(part)
+		if err != nil || id <= 0 {
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
+
+func writePantryError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrIngredientNotFound, domain.ErrTooManyPantryIngredients:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrPantryItemNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
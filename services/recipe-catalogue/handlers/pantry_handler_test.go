@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type pantryHandlerTestSetup struct {
+	handler       *PantryHandler
+	pantryService *mocks.MockPantryService
+}
+
+func setupPantryHandlerTest() *pantryHandlerTestSetup {
+	mockService := new(mocks.MockPantryService)
+
+	return &pantryHandlerTestSetup{
+		handler:       NewPantryHandler(mockService),
+		pantryService: mockService,
+	}
+}
+
+func TestPantryHandler_UpdatePantry_Success(t *testing.T) {
+	setup := setupPantryHandlerTest()
+	req := models.UpdatePantryRequest{IngredientIDs: []int{1, 6}}
+	setup.pantryService.On("UpdatePantry", 1, req).Return(&models.Pantry{Version: 2,
+		Items: []models.PantryItem{{IngredientID: 1, Name: "Chicken Breast"}, {IngredientID: 6, Name: "Garlic"}}}, nil)
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest("PUT", "/me/pantry", bytes.NewReader(body))
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UpdatePantry(recorder, httpReq)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+	setup.pantryService.AssertExpectations(t)
+}
+
+func TestPantryHandler_UpdatePantry_VersionConflict(t *testing.T) {
+	setup := setupPantryHandlerTest()
+	setup.pantryService.On("UpdatePantry", 1, mock.MatchedBy(func(req models.UpdatePantryRequest) bool {
+		return req.Version != nil && *req.Version == 3
+	})).Return(nil, &models.VersionConflictError{CurrentVersion: 4})
+
+	httpReq := httptest.NewRequest("PUT", "/me/pantry", bytes.NewReader([]byte(`{"ingredient_ids": [1]}`)))
+	httpReq.Header.Set("If-Match", `"3"`)
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UpdatePantry(recorder, httpReq)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	var response models.VersionConflictResponse
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, 4, response.CurrentVersion)
+	setup.pantryService.AssertExpectations(t)
+}
+
+func TestPantryHandler_UpdatePantry_UnknownIngredient(t *testing.T) {
+	setup := setupPantryHandlerTest()
+	setup.pantryService.On("UpdatePantry", 1, mock.Anything).Return(nil, domain.ErrIngredientNotFound)
+
+	httpReq := httptest.NewRequest("PUT", "/me/pantry", bytes.NewReader([]byte(`{"ingredient_ids": [99]}`)))
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UpdatePantry(recorder, httpReq)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestPantryHandler_AddItem(t *testing.T) {
+	setup := setupPantryHandlerTest()
+	setup.pantryService.On("AddPantryItem", 1, 6).Return(&models.PantryChange{Version: 5, ChangedBy: 1,
+		Added: []models.PantryItem{{IngredientID: 6, Name: "Garlic", AddedBy: 1}}, Removed: []int{}}, nil)
+
+	httpReq := httptest.NewRequest("PUT", "/me/pantry/items/6", nil)
+	httpReq = mux.SetURLVars(httpReq, map[string]string{"ingredient_id": "6"})
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.AddItem(recorder, httpReq)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `"5"`, recorder.Header().Get("ETag"))
+	setup.pantryService.AssertExpectations(t)
+}
+
+func TestPantryHandler_RemoveItem_NotInPantry(t *testing.T) {
+	setup := setupPantryHandlerTest()
+	setup.pantryService.On("RemovePantryItem", 1, 6).Return(nil, domain.ErrPantryItemNotFound)
+
+	httpReq := httptest.NewRequest("DELETE", "/me/pantry/items/6", nil)
+	httpReq = mux.SetURLVars(httpReq, map[string]string{"ingredient_id": "6"})
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.RemoveItem(recorder, httpReq)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestPantryHandler_StreamPantry(t *testing.T) {
+	setup := setupPantryHandlerTest()
+	changes := make(chan models.PantryChange, 2)
+	changes <- models.PantryChange{Version: 3, ChangedBy: 2, Removed: []int{9}}
+	changes <- models.PantryChange{Version: 4, ChangedBy: 2, Added: []models.PantryItem{{IngredientID: 6, Name: "Garlic"}}}
+	close(changes)
+	setup.pantryService.On("Watch", 1).Return(changes, nil)
+	setup.pantryService.On("GetPantry", 1).Return(&models.Pantry{Version: 3, Items: []models.PantryItem{}}, nil)
+
+	httpReq := httptest.NewRequest("GET", "/me/pantry/events", nil)
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.StreamPantry(recorder, httpReq)
+
+	assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+	body := recorder.Body.String()
+	assert.Contains(t, body, "event: pantry\nid: 3\ndata: {\"version\":3")
+	// The change already in the pantry is skipped
+	assert.NotContains(t, body, "event: change\nid: 3")
+	assert.Contains(t, body, "event: change\nid: 4\ndata: {\"version\":4")
+	assert.Less(t, strings.Index(body, "event: pantry"), strings.Index(body, "event: change"))
+}
+
+func TestPantryHandler_GetCookableRecipes_FromPantry(t *testing.T) {
+	setup := setupPantryHandlerTest()
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+	recipes := []models.CookableRecipe{{Recipe: models.Recipe{ID: 4, Name: "Garlic Chicken"}, TotalIngredients: 2, OnHand: 2, Coverage: 1,
+		MissingIngredients: []models.RecipeIngredient{}}}
+	setup.pantryService.On("GetCookableRecipes", 1, []int(nil), params).Return(recipes, models.NewPaginationMeta(params, 1), nil)
+
+	httpReq := httptest.NewRequest("GET", "/recipes/cookable", nil)
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetCookableRecipes(recorder, httpReq)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data []models.CookableRecipe `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "Garlic Chicken", response.Data[0].Name)
+	assert.Equal(t, 1.0, response.Data[0].Coverage)
+}
+
+func TestPantryHandler_GetCookableRecipes_ExplicitIngredients(t *testing.T) {
+	setup := setupPantryHandlerTest()
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+	setup.pantryService.On("GetCookableRecipes", 1, []int{1, 6}, params).
+		Return([]models.CookableRecipe{}, models.NewPaginationMeta(params, 0), nil)
+
+	httpReq := httptest.NewRequest("GET", "/recipes/cookable?ingredient_ids=1,%206", nil)
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetCookableRecipes(recorder, httpReq)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	setup.pantryService.AssertExpectations(t)
+}
+
+func TestPantryHandler_GetCookableRecipes_InvalidIngredientIDs(t *testing.T) {
+	setup := setupPantryHandlerTest()
+
+	httpReq := httptest.NewRequest("GET", "/recipes/cookable?ingredient_ids=1,garlic", nil)
+	httpReq = test.AddAuthContext(httpReq, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetCookableRecipes(recorder, httpReq)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	setup.pantryService.AssertNotCalled(t, "GetCookableRecipes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPantryHandler_GetPantry_RequiresAuth(t *testing.T) {
+	setup := setupPantryHandlerTest()
+
+	recorder := httptest.NewRecorder()
+	setup.handler.GetPantry(recorder, httptest.NewRequest("GET", "/me/pantry", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+// PrepHandler serves batch-cooking prep sessions.
+type PrepHandler struct {
+	prepService service.PrepService
+}
+
+func NewPrepHandler(prepService service.PrepService) *PrepHandler {
+	return &PrepHandler{prepService: prepService}
+}
+
+func (h *PrepHandler) GetPrepSessions(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	sessions, err := h.prepService.GetSessions(user.UserID)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch prep sessions", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, sessions, http.StatusOK)
+}
+
+func (h *PrepHandler) GetPrepSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid prep session ID", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.prepService.GetSession(r.Context(), user.UserID, id)
+	if err != nil {
+		switch err {
+		case domain.ErrPrepSessionNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch prep session", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, plan, http.StatusOK)
+}
+
+// PrintPrepLabels returns a printable PDF sheet with a label for every
+// container in the session.
+func (h *PrepHandler) PrintPrepLabels(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid prep session ID", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.prepService.GetSession(r.Context(), user.UserID, id)
+	if err != nil {
+		switch err {
+		case domain.ErrPrepSessionNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch prep session", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="prep-session-%d-labels.pdf"`, id))
+	w.WriteHeader(http.StatusOK)
+	renderPrepLabels(plan.Labels).WriteTo(w)
+}
+
+func (h *PrepHandler) CreatePrepSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.PrepSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.prepService.CreateSession(r.Context(), user.UserID, req)
+	if err != nil {
+		writePrepSessionError(w, err, "Failed to create prep session")
+		return
+	}
+
+	models.WriteSuccessResponse(w, plan, http.StatusCreated)
+}
+
+func (h *PrepHandler) UpdatePrepSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid prep session ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.PrepSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.prepService.UpdateSession(r.Context(), user.UserID, id, req)
+	if err != nil {
+		writePrepSessionError(w, err, "Failed to update prep session")
+		return
+	}
+
+	models.WriteSuccessResponse(w, plan, http.StatusOK)
+}
+
+func (h *PrepHandler) DeletePrepSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid prep session ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.prepService.DeleteSession(r.Context(), user.UserID, id); err != nil {
+		switch err {
+		case domain.ErrPrepSessionNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to delete prep session", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Prep session deleted successfully"}, http.StatusNoContent)
+}
+
+func writePrepSessionError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrPrepSessionNotFound, domain.ErrRecipeNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	case domain.ErrPrepSessionNameRequired, domain.ErrInvalidPrepDate, domain.ErrInvalidPrepRecipes,
+		domain.ErrInvalidScale, domain.ErrInvalidPortions, domain.ErrInvalidKeepsDays, domain.ErrInvalidReheatNote:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
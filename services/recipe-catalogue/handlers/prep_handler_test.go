@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+	"meal-prep/shared/pdf"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type prepHandlerTestSetup struct {
+	handler     *PrepHandler
+	prepService *mocks.MockPrepService
+}
+
+func setupPrepHandlerTest() *prepHandlerTestSetup {
+	mockService := new(mocks.MockPrepService)
+	return &prepHandlerTestSetup{
+		handler:     NewPrepHandler(mockService),
+		prepService: mockService,
+	}
+}
+
+func TestPrepHandler_CreatePrepSession_Success(t *testing.T) {
+	setup := setupPrepHandlerTest()
+	reqBody := models.PrepSessionRequest{
+		Name:    "Sunday prep",
+		Recipes: []models.PrepSessionRecipe{{RecipeID: 3, Scale: 2, Portions: 6}},
+	}
+	plan := &models.PrepSessionPlan{
+		PrepSession:  models.PrepSession{ID: 9, UserID: 1, Name: "Sunday prep"},
+		TotalMinutes: 45,
+	}
+	setup.prepService.On("CreateSession", 1, reqBody).Return(plan, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/prep-sessions", bytes.NewBuffer(body))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.CreatePrepSession(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var response models.PrepSessionPlan
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, 9, response.ID)
+	assert.Equal(t, 45, response.TotalMinutes)
+}
+
+func TestPrepHandler_CreatePrepSession_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"invalid recipes", domain.ErrInvalidPrepRecipes, http.StatusBadRequest},
+		{"invalid portions", domain.ErrInvalidPortions, http.StatusBadRequest},
+		{"unknown recipe", domain.ErrRecipeNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPrepHandlerTest()
+			setup.prepService.On("CreateSession", 1, mock.Anything).Return(nil, tt.err)
+
+			req := httptest.NewRequest("POST", "/prep-sessions", bytes.NewBufferString(`{"name": "Prep"}`))
+			req = test.AddAuthContext(req, 1, "test@example.com")
+			recorder := httptest.NewRecorder()
+
+			setup.handler.CreatePrepSession(recorder, req)
+
+			assert.Equal(t, tt.expected, recorder.Code)
+		})
+	}
+}
+
+func TestPrepHandler_CreatePrepSession_Unauthenticated(t *testing.T) {
+	setup := setupPrepHandlerTest()
+
+	req := httptest.NewRequest("POST", "/prep-sessions", bytes.NewBufferString("{}"))
+	recorder := httptest.NewRecorder()
+
+	setup.handler.CreatePrepSession(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	setup.prepService.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+}
+
+func TestPrepHandler_GetPrepSession_NotFound(t *testing.T) {
+	setup := setupPrepHandlerTest()
+	setup.prepService.On("GetSession", 1, 9).Return(nil, domain.ErrPrepSessionNotFound)
+
+	req := httptest.NewRequest("GET", "/prep-sessions/9", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetPrepSession(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestPrepHandler_DeletePrepSession_Success(t *testing.T) {
+	setup := setupPrepHandlerTest()
+	setup.prepService.On("DeleteSession", 1, 9).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/prep-sessions/9", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.DeletePrepSession(recorder, req)
+
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	setup.prepService.AssertExpectations(t)
+}
+
+func TestPrepHandler_PrintPrepLabels_Success(t *testing.T) {
+	setup := setupPrepHandlerTest()
+	plan := &models.PrepSessionPlan{PrepSession: models.PrepSession{ID: 9, UserID: 1}}
+	for portion := 1; portion <= 15; portion++ {
+		plan.Labels = append(plan.Labels, models.PrepLabel{
+			RecipeName: "Chili con carne", Portion: portion, Portions: 15,
+			PreparedOn: "2026-10-18", UseBy: "2026-10-22", ReheatInstructions: "Microwave 3 minutes, stir halfway",
+		})
+	}
+	setup.prepService.On("GetSession", 1, 9).Return(plan, nil)
+
+	req := httptest.NewRequest("GET", "/prep-sessions/9/labels", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.PrintPrepLabels(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/pdf", recorder.Header().Get("Content-Type"))
+	body := recorder.Body.String()
+	assert.True(t, strings.HasPrefix(body, "%PDF-"))
+	// 14 labels fit on a sheet
+	assert.Contains(t, body, "/Count 2")
+	assert.Contains(t, body, "(Portion 15 of 15) Tj")
+	assert.Contains(t, body, "(Prepared 2026-10-18    Use by 2026-10-22) Tj")
+}
+
+func TestPrepHandler_PrintPrepLabels_NotFound(t *testing.T) {
+	setup := setupPrepHandlerTest()
+	setup.prepService.On("GetSession", 1, 9).Return(nil, domain.ErrPrepSessionNotFound)
+
+	req := httptest.NewRequest("GET", "/prep-sessions/9/labels", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.PrintPrepLabels(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestFitText(t *testing.T) {
+	assert.Equal(t, "Dal", fitText("Dal", 11, 100))
+
+	fitted := fitText("Slow-cooked smoky black bean and sweet potato chili with lime crema", 11, 100)
+	assert.True(t, strings.HasSuffix(fitted, "…"))
+	assert.LessOrEqual(t, pdf.TextWidth(fitted, 11), 100.0)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"meal-prep/shared/models"
+	"meal-prep/shared/pdf"
+)
+
+// Label sheet geometry, matching the common 14-per-sheet A4 layout of two
+// columns of 99.1 x 38.1 mm labels.
+const (
+	labelColumns   = 2
+	labelRows      = 7
+	labelWidth     = 99.1 * pdf.MM
+	labelHeight    = 38.1 * pdf.MM
+	labelTop       = 15.1 * pdf.MM
+	labelLeft      = 4.65 * pdf.MM
+	labelColumnGap = 2.5 * pdf.MM
+	labelPadding   = 4 * pdf.MM
+
+	// maxReheatLines is what fits below the dates
+	maxReheatLines = 4
+)
+
+// renderPrepLabels lays the labels out on as many sheets as they need, each
+// with the dish, the portion number, its dates and how to reheat it.
+func renderPrepLabels(labels []models.PrepLabel) *pdf.Document {
+	doc := pdf.New()
+	var page *pdf.Page
+	for i, label := range labels {
+		slot := i % (labelColumns * labelRows)
+		if slot == 0 {
+			page = doc.AddPage()
+		}
+
+		x := labelLeft + float64(slot%labelColumns)*(labelWidth+labelColumnGap)
+		y := labelTop + float64(slot/labelColumns)*labelHeight
+		page.Rect(x, y, labelWidth, labelHeight)
+
+		x += labelPadding
+		width := labelWidth - 2*labelPadding
+		page.Text(x, y+16, pdf.Bold, 11, fitText(label.RecipeName, 11*1.05, width))
+		page.Text(x, y+29, pdf.Regular, 9, fmt.Sprintf("Portion %d of %d", label.Portion, label.Portions))
+		page.Text(x, y+41, pdf.Regular, 9, "Prepared "+label.PreparedOn+"    Use by "+label.UseBy)
+
+		if label.ReheatInstructions != "" {
+			lines := pdf.Wrap("Reheat: "+label.ReheatInstructions, 8, width)
+			if len(lines) > maxReheatLines {
+				rest := strings.Join(lines[maxReheatLines-1:], " ")
+				lines = append(lines[:maxReheatLines-1], fitText(rest, 8, width))
+			}
+			for n, line := range lines {
+				page.Text(x, y+54+float64(n)*10, pdf.Regular, 8, line)
+			}
+		}
+	}
+	return doc
+}
+
+// fitText shortens s with an ellipsis until it fits width.
+func fitText(s string, size, width float64) string {
+	if pdf.TextWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.TextWidth(string(runes)+"…", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
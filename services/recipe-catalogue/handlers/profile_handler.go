@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type ProfileHandler struct {
+	profileService service.ProfileService
+}
+
+func NewProfileHandler(profileService service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+func (h *ProfileHandler) GetPublicProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.profileService.GetPublicProfile(userID)
+	if err != nil {
+		switch err {
+		case domain.ErrProfileNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch profile", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, profile, http.StatusOK)
+}
+
+func (h *ProfileHandler) GetProfileSettings(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	settings, err := h.profileService.GetSettings(user.UserID)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch profile settings", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, settings, http.StatusOK)
+}
+
+func (h *ProfileHandler) UpdateProfileSettings(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdateProfileSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.profileService.UpdateSettings(user.UserID, req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidDisplayName:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidBio:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidMeasurementSystem:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidLocale:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to update profile settings", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, settings, http.StatusOK)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type profileHandlerTestSetup struct {
+	handler        *ProfileHandler
+	profileService *mocks.MockProfileService
+}
+
+func setupProfileHandlerTest() *profileHandlerTestSetup {
+	mockService := new(mocks.MockProfileService)
+
+	return &profileHandlerTestSetup{
+		handler:        NewProfileHandler(mockService),
+		profileService: mockService,
+	}
+}
+
+func TestProfileHandler_GetPublicProfile_Success(t *testing.T) {
+	setup := setupProfileHandlerTest()
+	expected := &models.UserProfile{UserID: 3, DisplayName: "Hanna", Stats: &models.ProfileStats{RecipesPublished: 2}}
+	setup.profileService.On("GetPublicProfile", 3).Return(expected, nil)
+
+	req := httptest.NewRequest("GET", "/users/3/profile", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetPublicProfile(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response models.UserProfile
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, *expected, response)
+}
+
+func TestProfileHandler_GetPublicProfile_Private(t *testing.T) {
+	setup := setupProfileHandlerTest()
+	setup.profileService.On("GetPublicProfile", 3).Return(nil, domain.ErrProfileNotFound)
+
+	req := httptest.NewRequest("GET", "/users/3/profile", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetPublicProfile(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestProfileHandler_UpdateProfileSettings_InvalidBio(t *testing.T) {
+	setup := setupProfileHandlerTest()
+	bio := "too long"
+	request := models.UpdateProfileSettingsRequest{Bio: &bio}
+	setup.profileService.On("UpdateSettings", 1, request).Return(nil, domain.ErrInvalidBio)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("PUT", "/me/profile", bytes.NewBuffer(body))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UpdateProfileSettings(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
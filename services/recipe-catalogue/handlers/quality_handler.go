@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+// QualityHandler serves recipe quality scores: to authors for their own
+// recipes, and as a filterable report to admins.
+type QualityHandler struct {
+	qualityService service.RecipeQualityService
+}
+
+func NewQualityHandler(qualityService service.RecipeQualityService) *QualityHandler {
+	return &QualityHandler{qualityService: qualityService}
+}
+
+func (h *QualityHandler) GetRecipeQuality(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	quality, err := h.qualityService.GetRecipeQuality(r.Context(), user.UserID, id)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrForbidden:
+			models.WriteErrorResponse(w, "Only the author can see a recipe's quality score", http.StatusForbidden)
+		default:
+			models.WriteErrorResponse(w, "Failed to score recipe", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, quality, http.StatusOK)
+}
+
+// ListRecipeQuality is mounted behind middleware.RequireAdmin. It accepts
+// ?max_score=, ?failing= (a check name) and ?status=.
+func (h *QualityHandler) ListRecipeQuality(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.RecipeQualityFilter{
+		Failing: query.Get("failing"),
+		Status:  query.Get("status"),
+	}
+	if maxScore := query.Get("max_score"); maxScore != "" {
+		score, err := strconv.Atoi(maxScore)
+		if err != nil || score < 0 || score > 100 {
+			models.WriteErrorResponse(w, "max_score must be a number from 0 to 100", http.StatusBadRequest)
+			return
+		}
+		filter.MaxScore = &score
+	}
+
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	report, meta, err := h.qualityService.ListRecipeQuality(filter, params)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidRecipeStatus, domain.ErrInvalidQualityCheck:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch recipe quality report", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WritePaginatedResponse(w, report, meta, http.StatusOK)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQualityHandler_GetRecipeQuality(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"author", nil, http.StatusOK},
+		{"someone else", domain.ErrForbidden, http.StatusForbidden},
+		{"missing recipe", domain.ErrRecipeNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockRecipeQualityService)
+			handler := NewQualityHandler(mockService)
+			if tt.err != nil {
+				mockService.On("GetRecipeQuality", 1, 9).Return(nil, tt.err)
+			} else {
+				mockService.On("GetRecipeQuality", 1, 9).Return(&models.RecipeQuality{RecipeID: 9, Score: 85, Checks: []models.QualityCheck{
+					{Name: models.QualityCheckImage, Weight: 15, Hint: "Add a photo of the finished dish"},
+				}}, nil)
+			}
+
+			req := httptest.NewRequest("GET", "/recipes/9/quality", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "9"})
+			req = test.AddAuthContext(req, 1, "test@example.com")
+			recorder := httptest.NewRecorder()
+
+			handler.GetRecipeQuality(recorder, req)
+
+			assert.Equal(t, tt.expected, recorder.Code)
+			if tt.err == nil {
+				var response models.RecipeQuality
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				assert.Equal(t, 85, response.Score)
+				assert.Equal(t, "Add a photo of the finished dish", response.Checks[0].Hint)
+			}
+		})
+	}
+}
+
+func TestQualityHandler_GetRecipeQuality_Unauthenticated(t *testing.T) {
+	handler := NewQualityHandler(new(mocks.MockRecipeQualityService))
+
+	req := httptest.NewRequest("GET", "/recipes/9/quality", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	recorder := httptest.NewRecorder()
+
+	handler.GetRecipeQuality(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestQualityHandler_ListRecipeQuality(t *testing.T) {
+	mockService := new(mocks.MockRecipeQualityService)
+	handler := NewQualityHandler(mockService)
+	maxScore := 60
+	filter := models.RecipeQualityFilter{MaxScore: &maxScore, Failing: models.QualityCheckImage, Status: models.RecipeStatusPublished}
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+	mockService.On("ListRecipeQuality", filter, params).Return(
+		[]models.RecipeQuality{{RecipeID: 3, Score: 40}}, models.NewPaginationMeta(params, 1), nil)
+
+	req := httptest.NewRequest("GET", "/recipes/quality?max_score=60&failing=image&status=published", nil)
+	recorder := httptest.NewRecorder()
+
+	handler.ListRecipeQuality(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data       []models.RecipeQuality `json:"data"`
+		Pagination models.PaginationMeta  `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, 3, response.Data[0].RecipeID)
+	assert.Equal(t, 1, response.Pagination.Total)
+	mockService.AssertExpectations(t)
+}
+
+func TestQualityHandler_ListRecipeQuality_BadRequest(t *testing.T) {
+	mockService := new(mocks.MockRecipeQualityService)
+	handler := NewQualityHandler(mockService)
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+	mockService.On("ListRecipeQuality", models.RecipeQualityFilter{Failing: "nutrition"}, params).
+		Return(nil, models.PaginationMeta{}, domain.ErrInvalidQualityCheck)
+
+	for _, target := range []string{"/recipes/quality?max_score=high", "/recipes/quality?max_score=101", "/recipes/quality?failing=nutrition"} {
+		recorder := httptest.NewRecorder()
+		handler.ListRecipeQuality(recorder, httptest.NewRequest("GET", target, nil))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, target)
+	}
+}
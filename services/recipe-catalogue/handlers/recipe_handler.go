@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"meal-prep/services/recipe-catalogue/domain"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/logging"
 	"meal-prep/shared/middleware"
 	"meal-prep/shared/models"
 
@@ -23,7 +25,11 @@ func NewRecipeHandler(recipeService service.RecipeService) *RecipeHandler {
 }
 
 func (h *RecipeHandler) GetAllRecipes(w http.ResponseWriter, r *http.Request) {
-	params := models.ParsePaginationParams(r)
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
 	includeIngredients := r.URL.Query().Get("include_ingredients") == "true"
 
 	if includeIngredients {
@@ -32,7 +38,7 @@ func (h *RecipeHandler) GetAllRecipes(w http.ResponseWriter, r *http.Request) {
 			models.WriteErrorResponse(w, "Failed to fetch recipes with ingredients", http.StatusInternalServerError)
 			return
 		}
-		models.WritePaginatedResponse(w, recipes, meta, http.StatusOK)
+		models.WriteCacheableResponse(w, r, models.PaginatedResponse{Data: recipes, Pagination: meta})
 		return
 	}
 
@@ -42,7 +48,7 @@ func (h *RecipeHandler) GetAllRecipes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	models.WritePaginatedResponse(w, recipes, meta, http.StatusOK)
+	models.WriteCacheableResponse(w, r, models.PaginatedResponse{Data: recipes, Pagination: meta})
 }
 
 func (h *RecipeHandler) GetRecipeByID(w http.ResponseWriter, r *http.Request) {
@@ -53,6 +59,9 @@ func (h *RecipeHandler) GetRecipeByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Responses are tagged by a hash of the body rather than the version,
+	// which edits to ingredients, steps or images leave alone and which is
+	// the same whatever the language, units or include_ingredients
 	includeIngredients := r.URL.Query().Get("include_ingredients") == "true"
 
 	if includeIngredients {
@@ -66,7 +75,9 @@ func (h *RecipeHandler) GetRecipeByID(w http.ResponseWriter, r *http.Request) {
 			}
 			return
 		}
-		models.WriteSuccessResponse(w, recipe, http.StatusOK)
+		convertIngredients(recipe.Ingredients, measurementSystemFrom(r.Context()))
+		localizeRecipeIngredients(r.Context(), recipe.Ingredients)
+		models.WriteCacheableResponse(w, r, recipe)
 		return
 	}
 
@@ -81,7 +92,7 @@ func (h *RecipeHandler) GetRecipeByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	models.WriteSuccessResponse(w, recipe, http.StatusOK)
+	models.WriteCacheableResponse(w, r, recipe)
 }
 
 func (h *RecipeHandler) GetAllCategories(w http.ResponseWriter, r *http.Request) {
@@ -102,7 +113,11 @@ func (h *RecipeHandler) GetRecipesByCategory(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	params := models.ParsePaginationParams(r)
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
 	includeIngredients := r.URL.Query().Get("include_ingredients") == "true"
 
 	if includeIngredients {
@@ -138,6 +153,34 @@ func (h *RecipeHandler) GetRecipesByCategory(w http.ResponseWriter, r *http.Requ
 	models.WritePaginatedResponse(w, recipes, meta, http.StatusOK)
 }
 
+func (h *RecipeHandler) GetMyRecipes(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	status := strings.TrimSpace(r.URL.Query().Get("status"))
+
+	recipes, meta, err := h.recipeService.GetMyRecipes(user.UserID, status, params)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidRecipeStatus:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch recipes", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WritePaginatedResponse(w, recipes, meta, http.StatusOK)
+}
+
 func (h *RecipeHandler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
 	user, ok := middleware.GetUserFromGatewayContext(r.Context())
 	if !ok {
@@ -160,6 +203,12 @@ func (h *RecipeHandler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		case domain.ErrInvalidCategory:
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidRecipeStatus:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidDifficulty:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidTotalTime:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		default:
 			models.WriteErrorResponse(w, "Failed to create recipe", http.StatusInternalServerError)
 		}
@@ -170,7 +219,7 @@ func (h *RecipeHandler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *RecipeHandler) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
-	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	_, ok := middleware.GetUserFromGatewayContext(r.Context())
 	if !ok {
 		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
 		return
@@ -189,7 +238,22 @@ func (h *RecipeHandler) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	recipe, err := h.recipeService.UpdateRecipe(user.UserID, id, req)
+	// The version may come in the body or as If-Match; the body wins
+	ifMatch, ok := models.IfMatchVersion(r)
+	if !ok {
+		models.WriteErrorResponse(w, "Invalid If-Match header", http.StatusBadRequest)
+		return
+	}
+	if req.Version == nil {
+		req.Version = ifMatch
+	}
+
+	recipe, err := h.recipeService.UpdateRecipe(r.Context(), id, req)
+	var conflict *models.VersionConflictError
+	if errors.As(err, &conflict) {
+		models.WriteVersionConflictResponse(w, conflict.CurrentVersion)
+		return
+	}
 	if err != nil {
 		switch err {
 		case domain.ErrRecipeNameRequired:
@@ -202,6 +266,12 @@ func (h *RecipeHandler) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		case domain.ErrInvalidCategory:
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidRecipeStatus:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidDifficulty:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidTotalTime:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		default:
 			models.WriteErrorResponse(w, "Failed to update recipe", http.StatusInternalServerError)
 		}
@@ -212,7 +282,7 @@ func (h *RecipeHandler) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *RecipeHandler) DeleteRecipe(w http.ResponseWriter, r *http.Request) {
-	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	_, ok := middleware.GetUserFromGatewayContext(r.Context())
 	if !ok {
 		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
 		return
@@ -225,7 +295,7 @@ func (h *RecipeHandler) DeleteRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.recipeService.DeleteRecipe(user.UserID, id)
+	err = h.recipeService.DeleteRecipe(r.Context(), id)
 	if err != nil {
 		switch err {
 		case domain.ErrRecipeNotFound:
@@ -261,7 +331,10 @@ func (h *RecipeHandler) SearchRecipesByIngredients(w http.ResponseWriter, r *htt
 		return
 	}
 
-	params := models.ParsePaginationParams(r)
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
 
 	// Check if client wants recipes with ingredients included
 	includeIngredients := r.URL.Query().Get("include_ingredients") == "true"
@@ -272,7 +345,7 @@ func (h *RecipeHandler) SearchRecipesByIngredients(w http.ResponseWriter, r *htt
 			models.WriteErrorResponse(w, "Failed to search recipes with ingredients", http.StatusInternalServerError)
 			return
 		}
-		models.WritePaginatedResponse(w, recipes, meta, http.StatusOK)
+		h.writeSearchResults(w, r, recipes, meta, ingredientIDs)
 		return
 	}
 
@@ -282,7 +355,62 @@ func (h *RecipeHandler) SearchRecipesByIngredients(w http.ResponseWriter, r *htt
 		return
 	}
 
-	models.WritePaginatedResponse(w, recipes, meta, http.StatusOK)
+	h.writeSearchResults(w, r, recipes, meta, ingredientIDs)
+}
+
+// CompareRecipes serves GET /recipes/compare?ids=1,2, a diff of two recipes'
+// ingredients, times and nutrition. Signed-in users can compare their own
+// unpublished recipes too.
+func (h *RecipeHandler) CompareRecipes(w http.ResponseWriter, r *http.Request) {
+	ids := strings.Split(r.URL.Query().Get("ids"), ",")
+	if len(ids) != 2 {
+		models.WriteErrorResponse(w, domain.ErrInvalidCompareIDs.Error(), http.StatusBadRequest)
+		return
+	}
+	var recipeIDs [2]int
+	for i, id := range ids {
+		recipeID, err := strconv.Atoi(strings.TrimSpace(id))
+		if err != nil || recipeID <= 0 {
+			models.WriteErrorResponse(w, domain.ErrInvalidCompareIDs.Error(), http.StatusBadRequest)
+			return
+		}
+		recipeIDs[i] = recipeID
+	}
+
+	userID := 0
+	if user, ok := middleware.GetUserFromGatewayContext(r.Context()); ok {
+		userID = user.UserID
+	}
+
+	comparison, err := h.recipeService.CompareRecipes(r.Context(), userID, recipeIDs[0], recipeIDs[1])
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrCompareSameRecipe:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to compare recipes", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	localizeIngredientComparisons(r.Context(), comparison.Ingredients)
+	models.WriteSuccessResponse(w, comparison, http.StatusOK)
+}
+
+// writeSearchResults sends a page of search results with facet counts over
+// all of them, so filter sidebars need no extra requests. The facets are
+// left out when they cannot be counted.
+func (h *RecipeHandler) writeSearchResults(w http.ResponseWriter, r *http.Request, recipes interface{}, meta models.PaginationMeta, ingredientIDs []int) {
+	response := models.PaginatedResponse{Data: recipes, Pagination: meta}
+	facets, err := h.recipeService.GetSearchFacets(ingredientIDs)
+	if err != nil {
+		logging.WithContext(r.Context()).Warn("Failed to count search facets", "error", err)
+	} else {
+		response.Facets = facets
+	}
+	models.WriteSuccessResponse(w, response, http.StatusOK)
 }
 
 func (h *RecipeHandler) parseIngredientIDs(ingredientIDsParam string) ([]int, error) {
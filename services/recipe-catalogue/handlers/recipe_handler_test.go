@@ -11,12 +11,14 @@ import (
 	"testing"
 
 	"meal-prep/services/recipe-catalogue/handlers/mocks"
-	"meal-prep/services/recipe-catalogue/service/testdata"
+	"meal-prep/shared/logging"
 	"meal-prep/shared/models"
+	"meal-prep/shared/testing/factory"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type recipeHandlerTestSetup struct {
@@ -44,8 +46,8 @@ func setupRecipeHandlerTest() *recipeHandlerTestSetup {
 func TestRecipeHandler_GetAllRecipes_Success(t *testing.T) {
 	setup := setupRecipeHandlerTest()
 	expectedRecipes := []models.Recipe{
-		testdata.NewRecipeBuilder().WithID(1).WithName("Recipe 1").Build(),
-		testdata.NewRecipeBuilder().WithID(2).WithName("Recipe 2").Build(),
+		factory.NewRecipeBuilder().WithID(1).WithName("Recipe 1").Build(),
+		factory.NewRecipeBuilder().WithID(2).WithName("Recipe 2").Build(),
 	}
 
 	setup.recipeService.On("GetAllRecipes", mock.AnythingOfType("models.PaginationParams")).Return(expectedRecipes, models.PaginationMeta{}, nil)
@@ -70,6 +72,75 @@ func TestRecipeHandler_GetAllRecipes_Success(t *testing.T) {
 	setup.recipeService.AssertExpectations(t)
 }
 
+func TestRecipeHandler_GetAllRecipes_Paginates(t *testing.T) {
+	setup := setupRecipeHandlerTest()
+	params := models.PaginationParams{Page: 3, PerPage: 5}
+	setup.recipeService.On("GetAllRecipes", params).Return(
+		[]models.Recipe{factory.NewRecipeBuilder().WithID(11).Build()}, models.NewPaginationMeta(params, 11), nil)
+
+	req := httptest.NewRequest("GET", "/recipes?page=3&per_page=5", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetAllRecipes(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data       []models.Recipe       `json:"data"`
+		Pagination models.PaginationMeta `json:"pagination"`
+	}
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, models.PaginationMeta{Page: 3, PerPage: 5, Total: 11, TotalPages: 3}, response.Pagination)
+
+	setup.recipeService.AssertExpectations(t)
+}
+
+func TestRecipeHandler_GetAllRecipes_RejectsOutOfRangePagination(t *testing.T) {
+	tests := []struct {
+		target  string
+		message string
+	}{
+		{"/recipes?per_page=500", "per_page must be between 1 and 100"},
+		{"/recipes?per_page=0", "per_page must be between 1 and 100"},
+		{"/recipes?page=0", "page must be a positive whole number"},
+		{"/recipes?page=two", "page must be a positive whole number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			setup := setupRecipeHandlerTest()
+			recorder := httptest.NewRecorder()
+
+			setup.handler.GetAllRecipes(recorder, httptest.NewRequest("GET", tt.target, nil))
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			var response models.ErrorResponse
+			assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+			assert.Equal(t, tt.message, response.Message)
+			setup.recipeService.AssertNotCalled(t, "GetAllRecipes", mock.Anything)
+		})
+	}
+}
+
+func TestRecipeHandler_GetAllRecipes_ConfiguredPageSizes(t *testing.T) {
+	models.ConfigurePagination(models.PaginationConfig{DefaultPerPage: 10, MaxPerPage: 50})
+	defer models.ConfigurePagination(models.PaginationConfig{DefaultPerPage: 20, MaxPerPage: 100})
+
+	setup := setupRecipeHandlerTest()
+	params := models.PaginationParams{Page: 1, PerPage: 10}
+	setup.recipeService.On("GetAllRecipes", params).Return([]models.Recipe{}, models.NewPaginationMeta(params, 0), nil)
+
+	recorder := httptest.NewRecorder()
+	setup.handler.GetAllRecipes(recorder, httptest.NewRequest("GET", "/recipes", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	setup.handler.GetAllRecipes(recorder, httptest.NewRequest("GET", "/recipes?per_page=60", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	setup.recipeService.AssertExpectations(t)
+}
+
 func TestRecipeHandler_GetAllRecipes_ServiceError(t *testing.T) {
 	setup := setupRecipeHandlerTest()
 	expectedError := errors.New("database error")
@@ -98,7 +169,7 @@ func TestRecipeHandler_GetAllRecipes_ServiceError(t *testing.T) {
 
 func TestRecipeHandler_GetRecipeByID_Success(t *testing.T) {
 	setup := setupRecipeHandlerTest()
-	expectedRecipe := testdata.NewRecipeBuilder().WithID(1).WithName("Test Recipe").BuildPtr()
+	expectedRecipe := factory.NewRecipeBuilder().WithID(1).WithName("Test Recipe").BuildPtr()
 
 	setup.recipeService.On("GetRecipeByID", 1).Return(expectedRecipe, nil)
 
@@ -119,6 +190,77 @@ func TestRecipeHandler_GetRecipeByID_Success(t *testing.T) {
 	setup.recipeService.AssertExpectations(t)
 }
 
+func TestRecipeHandler_GetRecipeByID_NotModified(t *testing.T) {
+	setup := setupRecipeHandlerTest()
+	recipe := factory.NewRecipeBuilder().WithID(1).BuildPtr()
+	recipe.Version = 3
+
+	setup.recipeService.On("GetRecipeByID", 1).Return(recipe, nil)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/recipes/1", nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		recorder := httptest.NewRecorder()
+		setup.handler.GetRecipeByID(recorder, req)
+		return recorder
+	}
+
+	first := get("")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.NotEqual(t, `"3"`, etag, "tagged by the body, not the version")
+
+	unchanged := get(`"2", W/` + etag)
+	assert.Equal(t, http.StatusNotModified, unchanged.Code)
+	assert.Equal(t, etag, unchanged.Header().Get("ETag"))
+	assert.Empty(t, unchanged.Body.String())
+
+	changed := get(`"3"`)
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.NotEmpty(t, changed.Body.String())
+}
+
+func TestRecipeHandler_GetRecipeByID_IngredientEditChangesETag(t *testing.T) {
+	setup := setupRecipeHandlerTest()
+	recipe := factory.NewRecipeBuilder().WithID(1).Build()
+	recipe.Version = 3
+	withQuantity := func(quantity float64) *models.RecipeWithIngredients {
+		return &models.RecipeWithIngredients{Recipe: recipe, Ingredients: []models.RecipeIngredient{
+			{ID: 1, RecipeID: 1, IngredientID: 5, Ingredient: models.Ingredient{ID: 5, Name: "Flour"}, Quantity: quantity, Unit: "g"},
+		}}
+	}
+
+	// Editing an ingredient leaves the recipe's version alone
+	setup.recipeService.On("GetRecipeByIDWithIngredients", 1).Return(withQuantity(200), nil).Once()
+	setup.recipeService.On("GetRecipeByIDWithIngredients", 1).Return(withQuantity(250), nil).Once()
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/recipes/1?include_ingredients=true", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		recorder := httptest.NewRecorder()
+		setup.handler.GetRecipeByID(recorder, req)
+		return recorder
+	}
+
+	before := get("")
+	require.Equal(t, http.StatusOK, before.Code)
+
+	after := get(before.Header().Get("ETag"))
+	assert.Equal(t, http.StatusOK, after.Code)
+	assert.NotEqual(t, before.Header().Get("ETag"), after.Header().Get("ETag"))
+
+	var response models.RecipeWithIngredients
+	require.NoError(t, json.NewDecoder(after.Body).Decode(&response))
+	require.Len(t, response.Ingredients, 1)
+	assert.Equal(t, 250.0, response.Ingredients[0].Quantity)
+	setup.recipeService.AssertExpectations(t)
+}
+
 func TestRecipeHandler_GetRecipeByID_InvalidID(t *testing.T) {
 	setup := setupRecipeHandlerTest()
 
@@ -166,7 +308,7 @@ func TestRecipeHandler_GetRecipeByID_NotFound(t *testing.T) {
 func TestRecipeHandler_GetRecipesByCategory_Success(t *testing.T) {
 	setup := setupRecipeHandlerTest()
 	expectedRecipes := []models.Recipe{
-		testdata.NewRecipeBuilder().WithID(1).WithCategoryID(1).Build(),
+		factory.NewRecipeBuilder().WithID(1).WithCategoryID(1).Build(),
 	}
 
 	setup.recipeService.On("GetRecipesByCategory", 1, mock.AnythingOfType("models.PaginationParams")).Return(expectedRecipes, models.PaginationMeta{}, nil)
@@ -256,14 +398,75 @@ func TestRecipeHandler_GetRecipesByCategory_RecipesDontExistForCategory(t *testi
 	setup.recipeService.AssertExpectations(t)
 }
 
+// =============================================================================
+// GET MY RECIPES TESTS
+// =============================================================================
+
+func TestRecipeHandler_GetMyRecipes_Success(t *testing.T) {
+	setup := setupRecipeHandlerTest()
+	expectedRecipes := []models.Recipe{
+		factory.NewRecipeBuilder().WithID(1).WithStatus(models.RecipeStatusDraft).Build(),
+	}
+
+	setup.recipeService.On("GetMyRecipes", 1, models.RecipeStatusDraft, mock.AnythingOfType("models.PaginationParams")).
+		Return(expectedRecipes, models.PaginationMeta{Total: 1}, nil)
+
+	req := httptest.NewRequest("GET", "/me/recipes?status=draft", nil)
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetMyRecipes(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data       []models.Recipe       `json:"data"`
+		Pagination models.PaginationMeta `json:"pagination"`
+	}
+	err := json.NewDecoder(recorder.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, models.RecipeStatusDraft, response.Data[0].Status)
+
+	setup.recipeService.AssertExpectations(t)
+}
+
+func TestRecipeHandler_GetMyRecipes_MissingAuthentication(t *testing.T) {
+	setup := setupRecipeHandlerTest()
+
+	req := httptest.NewRequest("GET", "/me/recipes", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetMyRecipes(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	setup.recipeService.AssertNotCalled(t, "GetMyRecipes")
+}
+
+func TestRecipeHandler_GetMyRecipes_InvalidStatus(t *testing.T) {
+	setup := setupRecipeHandlerTest()
+
+	setup.recipeService.On("GetMyRecipes", 1, "deleted", mock.AnythingOfType("models.PaginationParams")).
+		Return(nil, models.PaginationMeta{}, domain.ErrInvalidRecipeStatus)
+
+	req := httptest.NewRequest("GET", "/me/recipes?status=deleted", nil)
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetMyRecipes(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	setup.recipeService.AssertExpectations(t)
+}
+
 // =============================================================================
 // CREATE RECIPE TESTS (PROTECTED ENDPOINT)
 // =============================================================================
 
 func TestRecipeHandler_CreateRecipe_Success(t *testing.T) {
 	setup := setupRecipeHandlerTest()
-	request := testdata.ValidCreateRequest()
-	expectedRecipe := testdata.NewRecipeBuilder().WithName(request.Name).BuildPtr()
+	request := factory.ValidCreateRequest()
+	expectedRecipe := factory.NewRecipeBuilder().WithName(request.Name).BuildPtr()
 
 	setup.recipeService.On("CreateRecipe", 1, mock.AnythingOfType("models.CreateRecipeRequest")).
 		Return(expectedRecipe, nil)
@@ -290,7 +493,7 @@ func TestRecipeHandler_CreateRecipe_Success(t *testing.T) {
 
 func TestRecipeHandler_CreateRecipe_MissingAuthentication(t *testing.T) {
 	setup := setupRecipeHandlerTest()
-	request := testdata.ValidCreateRequest()
+	request := factory.ValidCreateRequest()
 
 	requestBody, _ := json.Marshal(request)
 	req := httptest.NewRequest("POST", "/recipes", bytes.NewBuffer(requestBody))
@@ -358,7 +561,7 @@ func TestRecipeHandler_CreateRecipe_ValidationErrors(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			setup := setupRecipeHandlerTest()
-			request := testdata.ValidCreateRequest()
+			request := factory.ValidCreateRequest()
 
 			setup.recipeService.On("CreateRecipe", 1, mock.AnythingOfType("models.CreateRecipeRequest")).
 				Return(nil, tc.serviceError)
@@ -387,7 +590,7 @@ func TestRecipeHandler_CreateRecipe_ValidationErrors(t *testing.T) {
 
 func TestRecipeHandler_CreateRecipe_InternalServerError(t *testing.T) {
 	setup := setupRecipeHandlerTest()
-	request := testdata.ValidCreateRequest()
+	request := factory.ValidCreateRequest()
 
 	setup.recipeService.On("CreateRecipe", 1, mock.AnythingOfType("models.CreateRecipeRequest")).
 		Return(nil, errors.New("database connection failed"))
@@ -423,9 +626,9 @@ func TestRecipeHandler_UpdateRecipe_Success(t *testing.T) {
 		Description: "Updated description",
 		CategoryID:  1,
 	}
-	expectedRecipe := testdata.NewRecipeBuilder().WithName("Updated Recipe").BuildPtr()
+	expectedRecipe := factory.NewRecipeBuilder().WithName("Updated Recipe").BuildPtr()
 
-	setup.recipeService.On("UpdateRecipe", 1, 1, mock.AnythingOfType("models.UpdateRecipeRequest")).
+	setup.recipeService.On("UpdateRecipe", 1, mock.AnythingOfType("models.UpdateRecipeRequest")).
 		Return(expectedRecipe, nil)
 
 	requestBody, _ := json.Marshal(request)
@@ -478,7 +681,7 @@ func TestRecipeHandler_UpdateRecipe_RecipeNotFound(t *testing.T) {
 	setup := setupRecipeHandlerTest()
 	request := models.UpdateRecipeRequest{Name: "Updated"}
 
-	setup.recipeService.On("UpdateRecipe", 1, 999, mock.AnythingOfType("models.UpdateRecipeRequest")).
+	setup.recipeService.On("UpdateRecipe", 999, mock.AnythingOfType("models.UpdateRecipeRequest")).
 		Return(nil, domain.ErrRecipeNotFound)
 
 	requestBody, _ := json.Marshal(request)
@@ -509,7 +712,7 @@ func TestRecipeHandler_UpdateRecipe_RecipeNotFound(t *testing.T) {
 func TestRecipeHandler_DeleteRecipe_Success(t *testing.T) {
 	setup := setupRecipeHandlerTest()
 
-	setup.recipeService.On("DeleteRecipe", 1, 1).Return(nil)
+	setup.recipeService.On("DeleteRecipe", 1).Return(nil)
 
 	req := httptest.NewRequest("DELETE", "/recipes/1", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
@@ -555,7 +758,7 @@ func TestRecipeHandler_DeleteRecipe_InvalidID(t *testing.T) {
 func TestRecipeHandler_DeleteRecipe_NotFound(t *testing.T) {
 	setup := setupRecipeHandlerTest()
 
-	setup.recipeService.On("DeleteRecipe", 1, 999).Return(domain.ErrRecipeNotFound)
+	setup.recipeService.On("DeleteRecipe", 999).Return(domain.ErrRecipeNotFound)
 
 	req := httptest.NewRequest("DELETE", "/recipes/999", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "999"})
@@ -576,6 +779,52 @@ func TestRecipeHandler_DeleteRecipe_NotFound(t *testing.T) {
 	setup.recipeService.AssertExpectations(t)
 }
 
+func TestRecipeHandler_UpdateRecipe_VersionConflict(t *testing.T) {
+	setup := setupRecipeHandlerTest()
+	request := models.UpdateRecipeRequest{Name: "Updated", CategoryID: 1}
+
+	setup.recipeService.On("UpdateRecipe", 1, mock.MatchedBy(func(req models.UpdateRecipeRequest) bool {
+		return req.Version != nil && *req.Version == 3
+	})).Return(nil, &models.VersionConflictError{CurrentVersion: 4})
+
+	requestBody, _ := json.Marshal(request)
+	req := httptest.NewRequest("PUT", "/recipes/1", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"3"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+
+	recorder := httptest.NewRecorder()
+	setup.handler.UpdateRecipe(recorder, req)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, `"4"`, recorder.Header().Get("ETag"))
+
+	var response models.VersionConflictResponse
+	err := json.NewDecoder(recorder.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, response.CurrentVersion)
+
+	setup.recipeService.AssertExpectations(t)
+}
+
+func TestRecipeHandler_UpdateRecipe_InvalidIfMatch(t *testing.T) {
+	setup := setupRecipeHandlerTest()
+
+	requestBody, _ := json.Marshal(models.UpdateRecipeRequest{Name: "Updated", CategoryID: 1})
+	req := httptest.NewRequest("PUT", "/recipes/1", bytes.NewBuffer(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"abc"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+
+	recorder := httptest.NewRecorder()
+	setup.handler.UpdateRecipe(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	setup.recipeService.AssertNotCalled(t, "UpdateRecipe")
+}
+
 // =============================================================================
 // OWNERSHIP / FORBIDDEN TESTS
 // =============================================================================
@@ -584,7 +833,7 @@ func TestRecipeHandler_UpdateRecipe_Forbidden(t *testing.T) {
 	setup := setupRecipeHandlerTest()
 	request := models.UpdateRecipeRequest{Name: "Updated", CategoryID: 1}
 
-	setup.recipeService.On("UpdateRecipe", 1, 1, mock.AnythingOfType("models.UpdateRecipeRequest")).
+	setup.recipeService.On("UpdateRecipe", 1, mock.AnythingOfType("models.UpdateRecipeRequest")).
 		Return(nil, domain.ErrForbidden)
 
 	requestBody, _ := json.Marshal(request)
@@ -609,7 +858,7 @@ func TestRecipeHandler_UpdateRecipe_Forbidden(t *testing.T) {
 func TestRecipeHandler_DeleteRecipe_Forbidden(t *testing.T) {
 	setup := setupRecipeHandlerTest()
 
-	setup.recipeService.On("DeleteRecipe", 1, 1).Return(domain.ErrForbidden)
+	setup.recipeService.On("DeleteRecipe", 1).Return(domain.ErrForbidden)
 
 	req := httptest.NewRequest("DELETE", "/recipes/1", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
@@ -635,8 +884,8 @@ func TestRecipeHandler_DeleteRecipe_Forbidden(t *testing.T) {
 func TestRecipeHandler_GetAllCategories_Success(t *testing.T) {
 	setup := setupRecipeHandlerTest()
 	expectedCategories := []models.Category{
-		testdata.NewCategoryBuilder().WithID(1).WithName("Italian").Build(),
-		testdata.NewCategoryBuilder().WithID(2).WithName("Mexican").Build(),
+		factory.NewCategoryBuilder().WithID(1).WithName("Italian").Build(),
+		factory.NewCategoryBuilder().WithID(2).WithName("Mexican").Build(),
 	}
 
 	setup.recipeService.On("GetAllCategories").Return(expectedCategories, nil)
@@ -676,3 +925,102 @@ func TestRecipeHandler_GetAllCategories_ServiceError(t *testing.T) {
 
 	setup.recipeService.AssertExpectations(t)
 }
+
+// =============================================================================
+// SEARCH FACETS TESTS
+// =============================================================================
+
+func TestRecipeHandler_SearchRecipesByIngredients_IncludesFacets(t *testing.T) {
+	setup := setupRecipeHandlerTest()
+	recipes := []models.Recipe{factory.NewRecipeBuilder().WithID(1).WithName("Omelette").Build()}
+	facets := &models.RecipeFacets{
+		Categories: []models.CategoryFacet{{CategoryID: 2, Name: "Breakfast", Count: 3}},
+		MaxTime:    []models.TimeFacet{{MaxMinutes: 15, Count: 1}, {MaxMinutes: 30, Count: 3}},
+	}
+	setup.recipeService.On("SearchRecipesByIngredients", []int{12, 5}, mock.AnythingOfType("models.PaginationParams")).Return(recipes, models.PaginationMeta{Page: 1, PerPage: 1, Total: 3, TotalPages: 3}, nil)
+	setup.recipeService.On("GetSearchFacets", []int{12, 5}).Return(facets, nil)
+
+	req := httptest.NewRequest("GET", "/recipes/search?ingredient_ids=12,5&per_page=1", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SearchRecipesByIngredients(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data   []models.Recipe     `json:"data"`
+		Facets models.RecipeFacets `json:"facets"`
+	}
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, *facets, response.Facets)
+}
+
+func TestRecipeHandler_SearchRecipesByIngredients_FacetsFailure(t *testing.T) {
+	logging.Init("test")
+	setup := setupRecipeHandlerTest()
+	setup.recipeService.On("SearchRecipesByIngredients", []int{12}, mock.AnythingOfType("models.PaginationParams")).Return([]models.Recipe{}, models.PaginationMeta{}, nil)
+	setup.recipeService.On("GetSearchFacets", []int{12}).Return(nil, errors.New("database error"))
+
+	req := httptest.NewRequest("GET", "/recipes/search?ingredient_ids=12", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.SearchRecipesByIngredients(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "facets")
+}
+
+// =============================================================================
+// COMPARE RECIPES TESTS
+// =============================================================================
+
+func TestRecipeHandler_CompareRecipes(t *testing.T) {
+	tests := []struct {
+		name           string
+		ids            string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "compared", ids: "1,2", expectedStatus: http.StatusOK},
+		{name: "hidden recipe", ids: "1,2", serviceErr: domain.ErrRecipeNotFound, expectedStatus: http.StatusNotFound},
+		{name: "same recipe", ids: "1,2", serviceErr: domain.ErrCompareSameRecipe, expectedStatus: http.StatusBadRequest},
+		{name: "one id", ids: "1", expectedStatus: http.StatusBadRequest},
+		{name: "three ids", ids: "1,2,3", expectedStatus: http.StatusBadRequest},
+		{name: "not a number", ids: "1,abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupRecipeHandlerTest()
+			if tt.serviceErr != nil {
+				setup.recipeService.On("CompareRecipes", 0, 1, 2).Return(nil, tt.serviceErr)
+			} else {
+				setup.recipeService.On("CompareRecipes", 0, 1, 2).Return(&models.RecipeComparison{}, nil)
+			}
+
+			req := httptest.NewRequest("GET", "/recipes/compare?ids="+tt.ids, nil)
+			recorder := httptest.NewRecorder()
+
+			setup.handler.CompareRecipes(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			if tt.expectedStatus == http.StatusBadRequest && tt.serviceErr == nil {
+				setup.recipeService.AssertNotCalled(t, "CompareRecipes", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRecipeHandler_CompareRecipes_SignedIn(t *testing.T) {
+	setup := setupRecipeHandlerTest()
+	setup.recipeService.On("CompareRecipes", 7, 1, 2).Return(&models.RecipeComparison{}, nil)
+
+	req := httptest.NewRequest("GET", "/recipes/compare?ids=1,2", nil)
+	req = test.AddAuthContext(req, 7, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.CompareRecipes(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	setup.recipeService.AssertExpectations(t)
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type RecipeImageHandler struct {
+	imageService service.RecipeImageService
+
+	// fetchPhoto downloads cover photos for share cards
+	fetchPhoto func(url string) (image.Image, error)
+}
+
+func NewRecipeImageHandler(imageService service.RecipeImageService) *RecipeImageHandler {
+	return &RecipeImageHandler{imageService: imageService, fetchPhoto: fetchPhoto}
+}
+
+func (h *RecipeImageHandler) GetRecipeImages(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	images, err := h.imageService.GetRecipeImages(recipeID)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch recipe images", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, images, http.StatusOK)
+}
+
+func (h *RecipeImageHandler) AddRecipeImage(w http.ResponseWriter, r *http.Request) {
+	_, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.AddRecipeImageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	image, err := h.imageService.AddRecipeImage(r.Context(), recipeID, req)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrForbidden:
+			models.WriteErrorResponse(w, err.Error(), http.StatusForbidden)
+		case domain.ErrInvalidImageURL:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidImageCaption:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to add recipe image", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, image, http.StatusCreated)
+}
+
+// uploadFormOverhead allows for the multipart boundaries and caption
+// around an image of the maximum size.
+const uploadFormOverhead = 64 << 10
+
+// UploadRecipeImage takes a multipart form with the photo in "image" and an
+// optional "caption".
+func (h *RecipeImageHandler) UploadRecipeImage(w http.ResponseWriter, r *http.Request) {
+	_, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxImageUploadBytes+uploadFormOverhead)
+	if err := r.ParseMultipartForm(service.MaxImageUploadBytes + uploadFormOverhead); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			models.WriteErrorResponse(w, domain.ErrImageTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		models.WriteErrorResponse(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		models.WriteErrorResponse(w, domain.ErrImageFileRequired.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to read image", http.StatusBadRequest)
+		return
+	}
+
+	upload := models.ImageUpload{Data: data}
+	if captions := r.MultipartForm.Value["caption"]; len(captions) > 0 {
+		upload.Caption = &captions[0]
+	}
+
+	image, err := h.imageService.UploadRecipeImage(r.Context(), recipeID, upload)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrForbidden:
+			models.WriteErrorResponse(w, err.Error(), http.StatusForbidden)
+		case domain.ErrImageFileRequired, domain.ErrInvalidImageCaption:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrImageTooLarge:
+			models.WriteErrorResponse(w, err.Error(), http.StatusRequestEntityTooLarge)
+		case domain.ErrUnsupportedImage:
+			models.WriteErrorResponse(w, err.Error(), http.StatusUnsupportedMediaType)
+		default:
+			models.WriteErrorResponse(w, "Failed to upload recipe image", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, image, http.StatusCreated)
+}
+
+// GetStoredImage serves uploaded photos and thumbnails. Keys are never
+// reused, so they can be cached for good.
+func (h *RecipeImageHandler) GetStoredImage(w http.ResponseWriter, r *http.Request) {
+	data, contentType, err := h.imageService.GetStoredImage(mux.Vars(r)["key"])
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeImageNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch image", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *RecipeImageHandler) UpdateRecipeImages(w http.ResponseWriter, r *http.Request) {
+	_, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateRecipeImagesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	images, err := h.imageService.UpdateRecipeImages(r.Context(), recipeID, req)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrForbidden:
+			models.WriteErrorResponse(w, err.Error(), http.StatusForbidden)
+		case domain.ErrRecipeImageNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidImageOrder:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrInvalidImageCaption:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to update recipe images", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, images, http.StatusOK)
+}
+
+func (h *RecipeImageHandler) DeleteRecipeImage(w http.ResponseWriter, r *http.Request) {
+	_, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	recipeID, err := strconv.Atoi(vars["recipeId"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+	imageID, err := strconv.Atoi(vars["imageId"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid image ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.imageService.DeleteRecipeImage(r.Context(), recipeID, imageID)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case domain.ErrForbidden:
+			models.WriteErrorResponse(w, err.Error(), http.StatusForbidden)
+		case domain.ErrRecipeImageNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to delete recipe image", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Recipe image deleted successfully"}, http.StatusNoContent)
+}
+
+// GetShareCard renders the recipe's link-preview image. A cover photo that
+// cannot be fetched is left out rather than failing the card.
+func (h *RecipeImageHandler) GetShareCard(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	card, err := h.imageService.GetShareCard(recipeID)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to build share card", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	var photo image.Image
+	if card.CoverImage != nil {
+		if photo, err = h.fetchPhoto(card.CoverImage.URL); err != nil {
+			logging.WithContext(r.Context()).Warn("Failed to fetch share card photo", "recipe_id", recipeID, "error", err)
+			photo = nil
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, renderShareCard(card, photo)); err != nil {
+		models.WriteErrorResponse(w, "Failed to render share card", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
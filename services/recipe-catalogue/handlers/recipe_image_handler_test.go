@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/png"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type recipeImageHandlerTestSetup struct {
+	handler      *RecipeImageHandler
+	imageService *mocks.MockRecipeImageService
+}
+
+func setupRecipeImageHandlerTest() *recipeImageHandlerTestSetup {
+	mockService := new(mocks.MockRecipeImageService)
+
+	return &recipeImageHandlerTestSetup{
+		handler:      NewRecipeImageHandler(mockService),
+		imageService: mockService,
+	}
+}
+
+func TestRecipeImageHandler_UpdateRecipeImages_Success(t *testing.T) {
+	setup := setupRecipeImageHandlerTest()
+	cover := 11
+	request := models.UpdateRecipeImagesRequest{Order: []int{11, 10}, Captions: map[int]string{11: "Plated"}, CoverImageID: &cover}
+	expected := []models.RecipeImage{
+		{ID: 11, RecipeID: 3, Position: 1, IsCover: true},
+		{ID: 10, RecipeID: 3, Position: 2},
+	}
+	setup.imageService.On("UpdateRecipeImages", 3, request).Return(expected, nil)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("PATCH", "/recipes/3/images", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UpdateRecipeImages(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response []models.RecipeImage
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, expected, response)
+	setup.imageService.AssertExpectations(t)
+}
+
+func TestRecipeImageHandler_UpdateRecipeImages_InvalidOrder(t *testing.T) {
+	setup := setupRecipeImageHandlerTest()
+	request := models.UpdateRecipeImagesRequest{Order: []int{10}}
+	setup.imageService.On("UpdateRecipeImages", 3, request).Return(nil, domain.ErrInvalidImageOrder)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("PATCH", "/recipes/3/images", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UpdateRecipeImages(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestRecipeImageHandler_AddRecipeImage_Forbidden(t *testing.T) {
+	setup := setupRecipeImageHandlerTest()
+	request := models.AddRecipeImageRequest{URL: "https://img.example/a.jpg"}
+	setup.imageService.On("AddRecipeImage", 3, request).Return(nil, domain.ErrForbidden)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("POST", "/recipes/3/images", bytes.NewBuffer(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.AddRecipeImage(recorder, req)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestRecipeImageHandler_DeleteRecipeImage_NotFound(t *testing.T) {
+	setup := setupRecipeImageHandlerTest()
+	setup.imageService.On("DeleteRecipeImage", 3, 99).Return(domain.ErrRecipeImageNotFound)
+
+	req := httptest.NewRequest("DELETE", "/recipes/3/images/99", nil)
+	req = mux.SetURLVars(req, map[string]string{"recipeId": "3", "imageId": "99"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.DeleteRecipeImage(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+// uploadRequest builds a multipart upload of data as "image", with a
+// caption when one is given.
+func uploadRequest(t *testing.T, data []byte, caption string) *http.Request {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if data != nil {
+		part, err := form.CreateFormFile("image", "photo.png")
+		assert.NoError(t, err)
+		part.Write(data)
+	}
+	if caption != "" {
+		form.WriteField("caption", caption)
+	}
+	form.Close()
+
+	req := httptest.NewRequest("POST", "/recipes/3/image", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	return test.AddAuthContext(req, 1, "test@example.com")
+}
+
+func TestRecipeImageHandler_UploadRecipeImage_Success(t *testing.T) {
+	setup := setupRecipeImageHandlerTest()
+	caption := "Plated"
+	expected := &models.RecipeImage{ID: 20, RecipeID: 3, URL: "http://localhost:8000/images/recipes/3/a.png"}
+	setup.imageService.On("UploadRecipeImage", 3, models.ImageUpload{Data: []byte("png bytes"), Caption: &caption}).
+		Return(expected, nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UploadRecipeImage(recorder, uploadRequest(t, []byte("png bytes"), caption))
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var response models.RecipeImage
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, *expected, response)
+	setup.imageService.AssertExpectations(t)
+}
+
+func TestRecipeImageHandler_UploadRecipeImage_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{"not owner", domain.ErrForbidden, http.StatusForbidden},
+		{"unsupported", domain.ErrUnsupportedImage, http.StatusUnsupportedMediaType},
+		{"too large", domain.ErrImageTooLarge, http.StatusRequestEntityTooLarge},
+		{"bad caption", domain.ErrInvalidImageCaption, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupRecipeImageHandlerTest()
+			setup.imageService.On("UploadRecipeImage", 3, mock.Anything).Return(nil, tt.serviceErr)
+			recorder := httptest.NewRecorder()
+
+			setup.handler.UploadRecipeImage(recorder, uploadRequest(t, []byte("data"), ""))
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestRecipeImageHandler_UploadRecipeImage_MissingFile(t *testing.T) {
+	setup := setupRecipeImageHandlerTest()
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UploadRecipeImage(recorder, uploadRequest(t, nil, "Plated"))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	setup.imageService.AssertNotCalled(t, "UploadRecipeImage", mock.Anything, mock.Anything)
+}
+
+func TestRecipeImageHandler_UploadRecipeImage_BodyTooLarge(t *testing.T) {
+	setup := setupRecipeImageHandlerTest()
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UploadRecipeImage(recorder, uploadRequest(t, make([]byte, service.MaxImageUploadBytes+uploadFormOverhead), ""))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	setup.imageService.AssertNotCalled(t, "UploadRecipeImage", mock.Anything, mock.Anything)
+}
+
+func TestRecipeImageHandler_GetStoredImage(t *testing.T) {
+	setup := setupRecipeImageHandlerTest()
+	setup.imageService.On("GetStoredImage", "recipes/3/a-thumb.jpg").Return([]byte("jpeg bytes"), "image/jpeg", nil)
+	setup.imageService.On("GetStoredImage", "recipes/3/missing.jpg").Return(nil, "", domain.ErrRecipeImageNotFound)
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/images/recipes/3/a-thumb.jpg", nil), map[string]string{"key": "recipes/3/a-thumb.jpg"})
+	recorder := httptest.NewRecorder()
+	setup.handler.GetStoredImage(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "image/jpeg", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", recorder.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "jpeg bytes", recorder.Body.String())
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/images/recipes/3/missing.jpg", nil), map[string]string{"key": "recipes/3/missing.jpg"})
+	recorder = httptest.NewRecorder()
+	setup.handler.GetStoredImage(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestRecipeImageHandler_GetShareCard_WithPhoto(t *testing.T) {
+	setup := setupRecipeImageHandlerTest()
+	minutes := 45
+	card := &models.RecipeShareCard{
+		Recipe:     models.Recipe{ID: 3, Name: "Chickpea curry", TotalTimeMinutes: &minutes},
+		CoverImage: &models.RecipeImage{ID: 11, URL: "https://img.example/curry.jpg", IsCover: true},
+	}
+	setup.imageService.On("GetShareCard", 3).Return(card, nil)
+	var fetched string
+	setup.handler.fetchPhoto = func(url string) (image.Image, error) {
+		fetched = url
+		return image.NewRGBA(image.Rect(0, 0, 64, 48)), nil
+	}
+
+	req := httptest.NewRequest("GET", "/recipes/3/card.png", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetShareCard(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "image/png", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "https://img.example/curry.jpg", fetched)
+	rendered, err := png.Decode(recorder.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, shareCardWidth, shareCardHeight), rendered.Bounds())
+}
+
+func TestRecipeImageHandler_GetShareCard_PhotoUnavailable(t *testing.T) {
+	setup := setupRecipeImageHandlerTest()
+	card := &models.RecipeShareCard{
+		Recipe:     models.Recipe{ID: 3, Name: "Chickpea curry"},
+		CoverImage: &models.RecipeImage{ID: 11, URL: "https://img.example/gone.jpg", IsCover: true},
+	}
+	setup.imageService.On("GetShareCard", 3).Return(card, nil)
+	setup.handler.fetchPhoto = func(url string) (image.Image, error) {
+		return nil, errors.New("404 Not Found")
+	}
+
+	req := httptest.NewRequest("GET", "/recipes/3/card.png", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetShareCard(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "image/png", recorder.Header().Get("Content-Type"))
+}
+
+func TestRecipeImageHandler_GetShareCard_NotFound(t *testing.T) {
+	setup := setupRecipeImageHandlerTest()
+	setup.imageService.On("GetShareCard", 3).Return(nil, domain.ErrRecipeNotFound)
+
+	req := httptest.NewRequest("GET", "/recipes/3/card.png", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetShareCard(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestTitleLines_ShortensLongNames(t *testing.T) {
+	lines := titleLines("Slow cooked smoky black bean and sweet potato chili with lime crema and pickled onions", 532)
+
+	assert.Len(t, lines, maxTitleLines)
+	assert.True(t, strings.HasSuffix(lines[maxTitleLines-1], "..."))
+}
+
+func TestRejectPrivateAddress(t *testing.T) {
+	assert.Equal(t, errHostNotAllowed, rejectPrivateAddress("tcp", "127.0.0.1:80", nil))
+	assert.Equal(t, errHostNotAllowed, rejectPrivateAddress("tcp", "10.1.2.3:443", nil))
+	assert.Equal(t, errHostNotAllowed, rejectPrivateAddress("tcp", "[fe80::1]:443", nil))
+	assert.Equal(t, errHostNotAllowed, rejectPrivateAddress("tcp", "169.254.169.254:80", nil))
+	assert.NoError(t, rejectPrivateAddress("tcp", "93.184.216.34:443", nil))
+}
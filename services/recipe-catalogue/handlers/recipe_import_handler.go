@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/metrics"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+)
+
+const (
+	importPageTimeout = 10 * time.Second
+
+	// maxImportPageBytes is well above a recipe page's HTML, ads and all
+	maxImportPageBytes = 2 << 20
+)
+
+var (
+	errPageNotHTML  = errors.New("page is not HTML")
+	errPageTooLarge = errors.New("page is too large")
+)
+
+// RecipeImportHandler turns recipes on other sites into drafts of the
+// user's own.
+type RecipeImportHandler struct {
+	importService service.RecipeImportService
+}
+
+func NewRecipeImportHandler(importService service.RecipeImportService) *RecipeImportHandler {
+	return &RecipeImportHandler{importService: importService}
+}
+
+func (h *RecipeImportHandler) ImportRecipe(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.ImportRecipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	imported, err := h.importService.ImportFromURL(r.Context(), user.UserID, req)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidImportURL, domain.ErrInvalidCategory, domain.ErrCategoryNotFound, domain.ErrImportCategoryRequired:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrNoRecipeOnPage:
+			models.WriteErrorResponse(w, err.Error(), http.StatusUnprocessableEntity)
+		case domain.ErrImportPageUnavailable:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadGateway)
+		default:
+			models.WriteErrorResponse(w, "Failed to import recipe", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, imported, http.StatusCreated)
+}
+
+// pageClient, like photoClient, refuses to connect to internal addresses,
+// so an imported URL cannot be used to probe the internal network.
+var pageClient = &http.Client{
+	Timeout: importPageTimeout,
+	Transport: metrics.Transport("recipe-pages", &http.Transport{
+		DialContext: (&net.Dialer{Timeout: 3 * time.Second, Control: rejectPrivateAddress}).DialContext,
+	}),
+}
+
+// NewPageFetcher downloads the pages recipes are imported from.
+func NewPageFetcher() service.PageFetcher {
+	return pageFetcher{client: pageClient}
+}
+
+type pageFetcher struct {
+	client *http.Client
+}
+
+func (f pageFetcher) FetchPage(ctx context.Context, pageURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching page: %s", resp.Status)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, errPageNotHTML
+	}
+
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxImportPageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(page) > maxImportPageBytes {
+		return nil, errPageTooLarge
+	}
+	return page, nil
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeImportHandler_ImportRecipe(t *testing.T) {
+	importService := new(mocks.MockRecipeImportService)
+	handler := NewRecipeImportHandler(importService)
+	imported := &models.ImportedRecipe{
+		Draft:                models.RecipeWithIngredients{Recipe: models.Recipe{ID: 20, Name: "Shakshuka", Status: models.RecipeStatusDraft}},
+		SourceURL:            "https://example.com/shakshuka",
+		UnmatchedIngredients: []string{"1 tin chopped tomatoes"},
+	}
+	importService.On("ImportFromURL", mock.Anything, 1, models.ImportRecipeRequest{URL: "https://example.com/shakshuka"}).
+		Return(imported, nil)
+
+	req := httptest.NewRequest("POST", "/recipes/import-url", strings.NewReader(`{"url": "https://example.com/shakshuka"}`))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	handler.ImportRecipe(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"unmatched_ingredients":["1 tin chopped tomatoes"]`)
+	importService.AssertExpectations(t)
+}
+
+func TestRecipeImportHandler_ImportRecipe_Errors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{domain.ErrInvalidImportURL, http.StatusBadRequest},
+		{domain.ErrImportCategoryRequired, http.StatusBadRequest},
+		{domain.ErrNoRecipeOnPage, http.StatusUnprocessableEntity},
+		{domain.ErrImportPageUnavailable, http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			importService := new(mocks.MockRecipeImportService)
+			importService.On("ImportFromURL", mock.Anything, 1, mock.Anything).Return(nil, tt.err)
+
+			req := httptest.NewRequest("POST", "/recipes/import-url", strings.NewReader(`{"url": "https://example.com/x"}`))
+			req = test.AddAuthContext(req, 1, "test@example.com")
+			recorder := httptest.NewRecorder()
+
+			NewRecipeImportHandler(importService).ImportRecipe(recorder, req)
+
+			assert.Equal(t, tt.status, recorder.Code)
+		})
+	}
+}
+
+func TestPageFetcher_FetchPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/recipe":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<html>recipe</html>"))
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("png"))
+		case "/huge":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(strings.Repeat("a", maxImportPageBytes+1)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	// The test server listens on loopback, which pageClient refuses
+	fetcher := pageFetcher{client: server.Client()}
+
+	page, err := fetcher.FetchPage(context.Background(), server.URL+"/recipe")
+	require.NoError(t, err)
+	assert.Equal(t, "<html>recipe</html>", string(page))
+
+	_, err = fetcher.FetchPage(context.Background(), server.URL+"/image")
+	assert.Equal(t, errPageNotHTML, err)
+	_, err = fetcher.FetchPage(context.Background(), server.URL+"/huge")
+	assert.Equal(t, errPageTooLarge, err)
+	_, err = fetcher.FetchPage(context.Background(), server.URL+"/missing")
+	assert.Error(t, err)
+}
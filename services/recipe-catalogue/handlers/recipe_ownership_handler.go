@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+)
+
+// RecipeOwnershipHandler lists how many recipes each user owns and when they
+// last touched one, so admins can spot abandoned content before a cleanup.
+// The route is mounted behind middleware.RequireAdmin, takes ?inactive_days=
+// to keep only owners idle for that long and ?format=csv to download every
+// matching owner instead of a page.
+type RecipeOwnershipHandler struct {
+	ownershipService service.RecipeOwnershipService
+}
+
+func NewRecipeOwnershipHandler(ownershipService service.RecipeOwnershipService) *RecipeOwnershipHandler {
+	return &RecipeOwnershipHandler{ownershipService: ownershipService}
+}
+
+func (h *RecipeOwnershipHandler) ListRecipeOwners(w http.ResponseWriter, r *http.Request) {
+	inactiveDays, ok := parseInactiveDays(w, r)
+	if !ok {
+		return
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+	case "csv":
+		h.exportRecipeOwners(w, r, inactiveDays)
+		return
+	default:
+		models.WriteErrorResponse(w, "format must be json or csv", http.StatusBadRequest)
+		return
+	}
+
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	owners, meta, err := h.ownershipService.ListRecipeOwners(inactiveDays, params)
+	if err != nil {
+		writeRecipeOwnershipError(w, err)
+		return
+	}
+
+	models.WritePaginatedResponse(w, owners, meta, http.StatusOK)
+}
+
+func (h *RecipeOwnershipHandler) exportRecipeOwners(w http.ResponseWriter, r *http.Request, inactiveDays int) {
+	owners, err := h.ownershipService.ExportRecipeOwners(inactiveDays)
+	if err != nil {
+		writeRecipeOwnershipError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="recipe-owners-%s.csv"`, time.Now().UTC().Format("2006-01-02")))
+	w.WriteHeader(http.StatusOK)
+
+	out := csv.NewWriter(w)
+	out.Write([]string{"user_id", "display_name", "recipe_count", "published_count", "draft_count", "private_count", "archived_count", "first_recipe_at", "last_activity_at"})
+	for _, owner := range owners {
+		displayName := ""
+		if owner.DisplayName != nil {
+			displayName = *owner.DisplayName
+		}
+		out.Write([]string{
+			strconv.Itoa(owner.UserID),
+			displayName,
+			strconv.Itoa(owner.RecipeCount),
+			strconv.Itoa(owner.PublishedCount),
+			strconv.Itoa(owner.DraftCount),
+			strconv.Itoa(owner.PrivateCount),
+			strconv.Itoa(owner.ArchivedCount),
+			owner.FirstRecipeAt.UTC().Format(time.RFC3339),
+			owner.LastActivityAt.UTC().Format(time.RFC3339),
+		})
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		logging.WithContext(r.Context()).Error("Failed to write recipe owners CSV", "error", err)
+	}
+}
+
+// parseInactiveDays reads ?inactive_days=; 0 lists every owner.
+func parseInactiveDays(w http.ResponseWriter, r *http.Request) (int, bool) {
+	raw := r.URL.Query().Get("inactive_days")
+	if raw == "" {
+		return 0, true
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil || days <= 0 {
+		models.WriteErrorResponse(w, domain.ErrInvalidInactiveDays.Error(), http.StatusBadRequest)
+		return 0, false
+	}
+	return days, true
+}
+
+func writeRecipeOwnershipError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidInactiveDays:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		models.WriteErrorResponse(w, "Failed to fetch recipe owners", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeOwnershipHandler_ListRecipeOwners(t *testing.T) {
+	mockService := new(mocks.MockRecipeOwnershipService)
+	handler := NewRecipeOwnershipHandler(mockService)
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+	mockService.On("ListRecipeOwners", 180, params).Return(
+		[]models.RecipeOwnership{{UserID: 7, RecipeCount: 3}}, models.NewPaginationMeta(params, 1), nil)
+
+	recorder := httptest.NewRecorder()
+	handler.ListRecipeOwners(recorder, httptest.NewRequest("GET", "/admin/recipes/owners?inactive_days=180", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response struct {
+		Data       []models.RecipeOwnership `json:"data"`
+		Pagination models.PaginationMeta    `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, 3, response.Data[0].RecipeCount)
+	assert.Equal(t, 1, response.Pagination.Total)
+	mockService.AssertExpectations(t)
+}
+
+func TestRecipeOwnershipHandler_ListRecipeOwners_CSV(t *testing.T) {
+	mockService := new(mocks.MockRecipeOwnershipService)
+	handler := NewRecipeOwnershipHandler(mockService)
+	name := "Grandma, the cook"
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	mockService.On("ExportRecipeOwners", 0).Return([]models.RecipeOwnership{
+		{UserID: 7, DisplayName: &name, RecipeCount: 3, PublishedCount: 2, DraftCount: 1, FirstRecipeAt: at, LastActivityAt: at},
+		{UserID: 9, RecipeCount: 1, PrivateCount: 1, FirstRecipeAt: at, LastActivityAt: at},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+	handler.ListRecipeOwners(recorder, httptest.NewRequest("GET", "/admin/recipes/owners?format=csv", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Header().Get("Content-Disposition"), "attachment")
+	records, err := csv.NewReader(recorder.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "last_activity_at", records[0][8])
+	assert.Equal(t, []string{"7", name, "3", "2", "1", "0", "0", "2025-06-01T12:00:00Z", "2025-06-01T12:00:00Z"}, records[1])
+	assert.Equal(t, "", records[2][1])
+	mockService.AssertExpectations(t)
+}
+
+func TestRecipeOwnershipHandler_ListRecipeOwners_BadRequests(t *testing.T) {
+	handler := NewRecipeOwnershipHandler(new(mocks.MockRecipeOwnershipService))
+
+	for _, target := range []string{
+		"/admin/recipes/owners?inactive_days=soon",
+		"/admin/recipes/owners?inactive_days=-3",
+		"/admin/recipes/owners?format=xlsx",
+	} {
+		recorder := httptest.NewRecorder()
+		handler.ListRecipeOwners(recorder, httptest.NewRequest("GET", target, nil))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, target)
+	}
+}
+
+func TestRecipeOwnershipHandler_ListRecipeOwners_ServiceError(t *testing.T) {
+	mockService := new(mocks.MockRecipeOwnershipService)
+	handler := NewRecipeOwnershipHandler(mockService)
+	mockService.On("ExportRecipeOwners", 30).Return(nil, errors.New("db down"))
+
+	recorder := httptest.NewRecorder()
+	handler.ListRecipeOwners(recorder, httptest.NewRequest("GET", "/admin/recipes/owners?inactive_days=30&format=csv", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+// RecipeTemplateHandler serves the template library: recipes admins curate
+// as starting points, which users instantiate into drafts of their own.
+// Creating and deleting templates is mounted behind middleware.RequireAdmin.
+type RecipeTemplateHandler struct {
+	templateService service.RecipeTemplateService
+}
+
+func NewRecipeTemplateHandler(templateService service.RecipeTemplateService) *RecipeTemplateHandler {
+	return &RecipeTemplateHandler{templateService: templateService}
+}
+
+func (h *RecipeTemplateHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.templateService.GetTemplates()
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch templates", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, templates, http.StatusOK)
+}
+
+func (h *RecipeTemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(id)
+	if err != nil {
+		writeRecipeTemplateError(w, err, "Failed to fetch template")
+		return
+	}
+
+	models.WriteSuccessResponse(w, template, http.StatusOK)
+}
+
+func (h *RecipeTemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateRecipeTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(r.Context(), user.UserID, req)
+	if err != nil {
+		switch err {
+		case domain.ErrRecipeNotFound, domain.ErrTemplateDescriptionTooLong:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrTemplateExists:
+			models.WriteErrorResponse(w, err.Error(), http.StatusConflict)
+		default:
+			models.WriteErrorResponse(w, "Failed to create template", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, template, http.StatusCreated)
+}
+
+func (h *RecipeTemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(id); err != nil {
+		writeRecipeTemplateError(w, err, "Failed to delete template")
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Template deleted successfully"}, http.StatusNoContent)
+}
+
+func (h *RecipeTemplateHandler) InstantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	// The body is optional; without one the recipe keeps the template's name
+	var req models.InstantiateTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	recipe, err := h.templateService.InstantiateTemplate(user.UserID, id, req)
+	if err != nil {
+		writeRecipeTemplateError(w, err, "Failed to create recipe from template")
+		return
+	}
+
+	models.WriteSuccessResponse(w, recipe, http.StatusCreated)
+}
+
+func writeRecipeTemplateError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrTemplateNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	case domain.ErrRecipeNameRequired, domain.ErrInvalidCategory, domain.ErrCategoryNotFound,
+		domain.ErrTemplateCategoryRequired:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
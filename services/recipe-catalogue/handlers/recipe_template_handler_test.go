@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type recipeTemplateHandlerTestSetup struct {
+	handler         *RecipeTemplateHandler
+	templateService *mocks.MockRecipeTemplateService
+}
+
+func setupRecipeTemplateHandlerTest() *recipeTemplateHandlerTestSetup {
+	mockService := new(mocks.MockRecipeTemplateService)
+
+	return &recipeTemplateHandlerTestSetup{
+		handler:         NewRecipeTemplateHandler(mockService),
+		templateService: mockService,
+	}
+}
+
+func TestRecipeTemplateHandler_InstantiateTemplate(t *testing.T) {
+	name := "Tofu stir fry"
+	tests := []struct {
+		name           string
+		body           []byte
+		request        models.InstantiateTemplateRequest
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "without body", expectedStatus: http.StatusCreated},
+		{name: "renamed", body: []byte(`{"name": "Tofu stir fry"}`), request: models.InstantiateTemplateRequest{Name: &name}, expectedStatus: http.StatusCreated},
+		{name: "missing template", serviceErr: domain.ErrTemplateNotFound, expectedStatus: http.StatusNotFound},
+		{name: "no category", serviceErr: domain.ErrTemplateCategoryRequired, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupRecipeTemplateHandlerTest()
+			created := &models.RecipeWithIngredients{Recipe: models.Recipe{ID: 10, UserID: 1, Status: models.RecipeStatusDraft}}
+			if tt.serviceErr != nil {
+				setup.templateService.On("InstantiateTemplate", 1, 3, tt.request).Return(nil, tt.serviceErr)
+			} else {
+				setup.templateService.On("InstantiateTemplate", 1, 3, tt.request).Return(created, nil)
+			}
+
+			req := httptest.NewRequest("POST", "/templates/3/instantiate", bytes.NewBuffer(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "3"})
+			req = test.AddAuthContext(req, 1, "test@example.com")
+			recorder := httptest.NewRecorder()
+
+			setup.handler.InstantiateTemplate(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			setup.templateService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecipeTemplateHandler_InstantiateTemplate_Unauthenticated(t *testing.T) {
+	setup := setupRecipeTemplateHandlerTest()
+
+	req := httptest.NewRequest("POST", "/templates/3/instantiate", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.InstantiateTemplate(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	setup.templateService.AssertNotCalled(t, "InstantiateTemplate")
+}
+
+func TestRecipeTemplateHandler_CreateTemplate(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "created", expectedStatus: http.StatusCreated},
+		{name: "already a template", serviceErr: domain.ErrTemplateExists, expectedStatus: http.StatusConflict},
+		{name: "hidden recipe", serviceErr: domain.ErrRecipeNotFound, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupRecipeTemplateHandlerTest()
+			request := models.CreateRecipeTemplateRequest{RecipeID: 1}
+			if tt.serviceErr != nil {
+				setup.templateService.On("CreateTemplate", 1, request).Return(nil, tt.serviceErr)
+			} else {
+				setup.templateService.On("CreateTemplate", 1, request).Return(&models.RecipeTemplate{ID: 3, RecipeID: 1}, nil)
+			}
+
+			body, _ := json.Marshal(request)
+			req := httptest.NewRequest("POST", "/templates", bytes.NewBuffer(body))
+			req = test.AddAuthContext(req, 1, "admin@example.com")
+			recorder := httptest.NewRecorder()
+
+			setup.handler.CreateTemplate(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestRecipeTemplateHandler_GetTemplate_NotFound(t *testing.T) {
+	setup := setupRecipeTemplateHandlerTest()
+	setup.templateService.On("GetTemplate", 3).Return(nil, domain.ErrTemplateNotFound)
+
+	req := httptest.NewRequest("GET", "/templates/3", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetTemplate(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"meal-prep/shared/audit"
+	"meal-prep/shared/idempotency"
+	"meal-prep/shared/middleware"
+
+	"github.com/gorilla/mux"
+)
+
+// RegisterRoutes mounts the catalogue API on router. It is shared by main and
+// the contract tests so both exercise exactly the same routing table.
+func RegisterRoutes(router *mux.Router, recipeHandler *RecipeHandler, ingredientHandler *IngredientHandler, groceryHandler *GroceryHandler, cookingHandler *CookingHandler, imageHandler *RecipeImageHandler, socialHandler *SocialHandler, profileHandler *ProfileHandler, lineageHandler *LineageHandler, costHandler *CostHandler, prepHandler *PrepHandler, feedHandler *FeedHandler, embedHandler *EmbedHandler, savedSearchHandler *SavedSearchHandler, qualityHandler *QualityHandler, cleanupHandler *IngredientCleanupHandler, usageHandler *UsageHandler, mealPlanHandler *MealPlanHandler, favoriteHandler *FavoriteHandler, sharedListHandler *SharedListHandler, ownershipHandler *RecipeOwnershipHandler, templateHandler *RecipeTemplateHandler, backfillHandler *BackfillHandler, importHandler *RecipeImportHandler, nutritionHandler *NutritionHandler, pantryHandler *PantryHandler, householdHandler *HouseholdHandler, auditor *audit.Auditor, idempotencyKeys *idempotency.Keys) {
+	// Quantities follow ?units= or the user's measurement system, and
+	// ingredient names ?lang=, the user's locale or Accept-Language. Public
+	// routes read the user from the token when there is one.
+	withUnits := profileHandler.WithMeasurementSystem
+	localize := middleware.Locale(profileHandler.PreferredLocale)
+	withLocale := func(handler http.Handler) http.Handler {
+		return localize(ingredientHandler.WithLocale(handler))
+	}
+	publicWithUnits := func(handler http.HandlerFunc) http.Handler {
+		return middleware.OptionalUserFromGatewayHeaders(withLocale(withUnits(handler)))
+	}
+
+	// Public routes - Recipes
+	router.HandleFunc("/recipes", recipeHandler.GetAllRecipes).Methods("GET")
+	router.Handle("/recipes/{id:[0-9]+}", publicWithUnits(recipeHandler.GetRecipeByID)).Methods("GET")
+	router.HandleFunc("/categories", recipeHandler.GetAllCategories).Methods("GET")
+	router.HandleFunc("/categories/{id:[0-9]+}/recipes", recipeHandler.GetRecipesByCategory).Methods("GET")
+	router.HandleFunc("/recipes/search", recipeHandler.SearchRecipesByIngredients).Methods("GET")
+	router.Handle("/recipes/compare", middleware.OptionalUserFromGatewayHeaders(withLocale(http.HandlerFunc(recipeHandler.CompareRecipes)))).Methods("GET")
+
+	// Public routes - Ingredients
+	router.Handle("/ingredients", withLocale(http.HandlerFunc(ingredientHandler.GetAllIngredients))).Methods("GET")
+	router.Handle("/ingredients/{id:[0-9]+}", withLocale(http.HandlerFunc(ingredientHandler.GetIngredientByID))).Methods("GET")
+	router.HandleFunc("/ingredients/{id:[0-9]+}/recipes", ingredientHandler.GetRecipesUsingIngredient).Methods("GET")
+	router.HandleFunc("/ingredients/popular", ingredientHandler.GetPopularIngredients).Methods("GET")
+	router.HandleFunc("/ingredients/{id:[0-9]+}/density", ingredientHandler.GetIngredientDensity).Methods("GET")
+	router.HandleFunc("/ingredients/{id:[0-9]+}/pack-sizes", ingredientHandler.GetPackSizes).Methods("GET")
+	router.HandleFunc("/ingredients/{id:[0-9]+}/products", ingredientHandler.GetIngredientProducts).Methods("GET")
+	router.HandleFunc("/ingredients/{id:[0-9]+}/prices", costHandler.GetIngredientPrices).Methods("GET")
+	router.HandleFunc("/ingredients/{id:[0-9]+}/substitutions", ingredientHandler.GetSubstitutions).Methods("GET")
+	router.HandleFunc("/ingredients/{id:[0-9]+}/translations", ingredientHandler.GetTranslations).Methods("GET")
+	router.Handle("/ingredients/{id:[0-9]+}/dietary", withLocale(http.HandlerFunc(ingredientHandler.GetIngredientDietary))).Methods("GET")
+	router.HandleFunc("/ingredients/{id:[0-9]+}/allergens", ingredientHandler.GetIngredientAllergens).Methods("GET")
+	router.HandleFunc("/allergens", ingredientHandler.GetAllergens).Methods("GET")
+
+	// Public routes - Nutrition preview from the external nutrition API
+	router.HandleFunc("/ingredients/nutrition-lookup", nutritionHandler.LookupNutrition).Methods("GET")
+
+	// Public routes - Recipe ingredients (read-only)
+	router.Handle("/recipes/{id:[0-9]+}/ingredients", publicWithUnits(ingredientHandler.GetRecipeIngredients)).Methods("GET")
+	router.HandleFunc("/recipes/{id:[0-9]+}/steps", cookingHandler.GetRecipeSteps).Methods("GET")
+	router.HandleFunc("/recipes/{id:[0-9]+}/techniques", cookingHandler.GetRecipeTechniques).Methods("GET")
+	router.HandleFunc("/recipes/{id:[0-9]+}/images", imageHandler.GetRecipeImages).Methods("GET")
+	router.HandleFunc("/recipes/{id:[0-9]+}/card.png", imageHandler.GetShareCard).Methods("GET")
+	router.HandleFunc("/images/{key:.+}", imageHandler.GetStoredImage).Methods("GET")
+	router.HandleFunc("/recipes/{id:[0-9]+}/lineage", lineageHandler.GetRecipeLineage).Methods("GET")
+	router.HandleFunc("/recipes/{id:[0-9]+}/forks", lineageHandler.GetRecipeForks).Methods("GET")
+	router.HandleFunc("/recipes/{id:[0-9]+}/cost-history", costHandler.GetRecipeCostHistory).Methods("GET")
+	router.Handle("/recipes/{id:[0-9]+}/dietary", withLocale(http.HandlerFunc(ingredientHandler.GetRecipeDietary))).Methods("GET")
+
+	// Public routes - User profiles
+	router.HandleFunc("/users/{id:[0-9]+}/profile", profileHandler.GetPublicProfile).Methods("GET")
+
+	// Public routes - Grocery lists shared by link, checked off by anyone
+	// with the token, with changes streamed as server-sent events
+	router.HandleFunc("/shared-lists/{token}", sharedListHandler.GetSharedList).Methods("GET")
+	router.HandleFunc("/shared-lists/{token}/events", sharedListHandler.StreamSharedList).Methods("GET")
+	router.HandleFunc("/shared-lists/{token}/items/{position:[0-9]+}", sharedListHandler.CheckItem).Methods("PUT")
+
+	// Public routes - Sitemaps and feeds for search engines and feed readers
+	router.HandleFunc("/sitemap.xml", feedHandler.GetSitemapIndex).Methods("GET")
+	router.HandleFunc("/sitemaps/recipes-{number:[0-9]+}.xml", feedHandler.GetSitemapChunk).Methods("GET")
+	router.HandleFunc("/feeds/recipes.atom", feedHandler.GetRecipeFeed).Methods("GET")
+
+	// Public routes - Recipe cards embedded on other sites, with oEmbed discovery
+	router.Handle("/embed/recipes/{id:[0-9]+}", withLocale(http.HandlerFunc(embedHandler.GetRecipeEmbed))).Methods("GET")
+	router.HandleFunc("/oembed", embedHandler.GetOEmbed).Methods("GET")
+
+	// Protected routes - Recipes
+	protected := router.PathPrefix("").Subrouter()
+	protected.Use(middleware.ExtractUserFromGatewayHeaders)
+	// Household members share what their household owns
+	protected.Use(householdHandler.WithHouseholds)
+	protected.Use(usageHandler.Track)
+	// Every successful mutation from here on lands in the audit log. POSTs
+	// that only compute an answer are registered with auditor.Skip
+	protected.Use(auditor.Middleware(router))
+	// Creates sent with an Idempotency-Key replay their first response on retries
+	idempotent := func(handler http.HandlerFunc) http.Handler {
+		return idempotencyKeys.Middleware(handler)
+	}
+
+	// Recipe management
+	protected.Handle("/recipes", idempotent(recipeHandler.CreateRecipe)).Methods("POST")
+	protected.HandleFunc("/recipes/{id:[0-9]+}", recipeHandler.UpdateRecipe).Methods("PUT")
+	protected.HandleFunc("/recipes/{id:[0-9]+}", recipeHandler.DeleteRecipe).Methods("DELETE")
+	protected.HandleFunc("/me/recipes", recipeHandler.GetMyRecipes).Methods("GET")
+	protected.Handle("/recipes/{id:[0-9]+}/fork", idempotent(lineageHandler.ForkRecipe)).Methods("POST")
+	// Imports fetch and parse someone else's page, so they get their own
+	// bulkhead to keep slow sites from tying up the catalogue
+	importBulkhead := middleware.Bulkhead("recipe-import",
+		middleware.BulkheadLimit("RECIPE_IMPORT_MAX_CONCURRENT", 4), 2*time.Second)
+	protected.Handle("/recipes/import-url", importBulkhead(idempotent(importHandler.ImportRecipe))).Methods("POST")
+	auditor.Skip(protected.Handle("/recipes/filter-compliance", withLocale(http.HandlerFunc(ingredientHandler.FilterCompliance))).Methods("POST"))
+	protected.HandleFunc("/recipes/{id:[0-9]+}/quality", qualityHandler.GetRecipeQuality).Methods("GET")
+
+	// Recipe photos
+	protected.HandleFunc("/recipes/{id:[0-9]+}/images", imageHandler.AddRecipeImage).Methods("POST")
+	protected.HandleFunc("/recipes/{id:[0-9]+}/image", imageHandler.UploadRecipeImage).Methods("POST")
+	protected.HandleFunc("/recipes/{id:[0-9]+}/images", imageHandler.UpdateRecipeImages).Methods("PATCH")
+	protected.HandleFunc("/recipes/{recipeId:[0-9]+}/images/{imageId:[0-9]+}", imageHandler.DeleteRecipeImage).Methods("DELETE")
+
+	// Ingredient management
+	protected.Handle("/ingredients", idempotent(ingredientHandler.CreateIngredient)).Methods("POST")
+	protected.HandleFunc("/ingredients/{id:[0-9]+}", ingredientHandler.UpdateIngredient).Methods("PUT")
+	protected.HandleFunc("/ingredients/{id:[0-9]+}", ingredientHandler.DeleteIngredient).Methods("DELETE")
+	protected.HandleFunc("/ingredients/{id:[0-9]+}/pack-sizes", ingredientHandler.SetPackSizes).Methods("PUT")
+
+	// Recipe-ingredient relationships
+	protected.HandleFunc("/recipes/{id:[0-9]+}/ingredients", ingredientHandler.AddRecipeIngredient).Methods("POST")
+	protected.HandleFunc("/recipes/{id:[0-9]+}/ingredients", ingredientHandler.SetRecipeIngredients).Methods("PUT")
+	protected.HandleFunc("/recipes/{recipeId:[0-9]+}/ingredients/{ingredientId:[0-9]+}", ingredientHandler.UpdateRecipeIngredient).Methods("PUT")
+	protected.HandleFunc("/recipes/{recipeId:[0-9]+}/ingredients/{ingredientId:[0-9]+}", ingredientHandler.RemoveRecipeIngredient).Methods("DELETE")
+
+	// Recipe method and hands-free cooking mode
+	protected.HandleFunc("/recipes/{id:[0-9]+}/steps", cookingHandler.SetRecipeSteps).Methods("PUT")
+	protected.HandleFunc("/recipes/{id:[0-9]+}/steps", cookingHandler.AddRecipeStep).Methods("POST")
+	protected.HandleFunc("/recipes/{id:[0-9]+}/steps/order", cookingHandler.ReorderRecipeSteps).Methods("PUT")
+	protected.HandleFunc("/recipes/{id:[0-9]+}/techniques", cookingHandler.SetRecipeTechniques).Methods("PUT")
+	protected.HandleFunc("/recipes/{recipeId:[0-9]+}/steps/{stepId:[0-9]+}", cookingHandler.UpdateRecipeStep).Methods("PUT")
+	protected.HandleFunc("/recipes/{recipeId:[0-9]+}/steps/{stepId:[0-9]+}", cookingHandler.DeleteRecipeStep).Methods("DELETE")
+	protected.HandleFunc("/recipes/{id:[0-9]+}/cooking-sessions", cookingHandler.StartCookingSession).Methods("POST")
+	protected.HandleFunc("/cooking-sessions/{token}", cookingHandler.GetCookingSession).Methods("GET")
+	protected.HandleFunc("/cooking-sessions/{token}", cookingHandler.UpdateCookingSession).Methods("PUT")
+
+	// Batch-cooking prep sessions
+	protected.HandleFunc("/prep-sessions", prepHandler.GetPrepSessions).Methods("GET")
+	protected.HandleFunc("/prep-sessions", prepHandler.CreatePrepSession).Methods("POST")
+	protected.HandleFunc("/prep-sessions/{id:[0-9]+}", prepHandler.GetPrepSession).Methods("GET")
+	protected.HandleFunc("/prep-sessions/{id:[0-9]+}", prepHandler.UpdatePrepSession).Methods("PUT")
+	protected.HandleFunc("/prep-sessions/{id:[0-9]+}", prepHandler.DeletePrepSession).Methods("DELETE")
+	protected.HandleFunc("/prep-sessions/{id:[0-9]+}/labels", prepHandler.PrintPrepLabels).Methods("GET")
+
+	// Meal plans
+	protected.HandleFunc("/meal-plans", mealPlanHandler.GetMealPlans).Methods("GET")
+	protected.HandleFunc("/meal-plans", mealPlanHandler.CreateMealPlan).Methods("POST")
+	protected.HandleFunc("/meal-plans/generate", mealPlanHandler.GenerateMealPlan).Methods("POST")
+	protected.HandleFunc("/meal-plans/preferences", mealPlanHandler.GetPreferences).Methods("GET")
+	protected.HandleFunc("/meal-plans/preferences", mealPlanHandler.UpdatePreferences).Methods("PUT")
+	protected.HandleFunc("/meal-plans/{id:[0-9]+}", mealPlanHandler.GetMealPlan).Methods("GET")
+	protected.HandleFunc("/meal-plans/{id:[0-9]+}", mealPlanHandler.UpdateMealPlan).Methods("PUT")
+	protected.HandleFunc("/meal-plans/{id:[0-9]+}", mealPlanHandler.DeleteMealPlan).Methods("DELETE")
+	protected.HandleFunc("/meal-plans/{id:[0-9]+}/entries", mealPlanHandler.AddEntry).Methods("POST")
+	protected.HandleFunc("/meal-plans/{id:[0-9]+}/entries/{entryId:[0-9]+}", mealPlanHandler.UpdateEntry).Methods("PUT")
+	protected.HandleFunc("/meal-plans/{id:[0-9]+}/entries/{entryId:[0-9]+}", mealPlanHandler.DeleteEntry).Methods("DELETE")
+	protected.HandleFunc("/meal-plans/{id:[0-9]+}/members", mealPlanHandler.SetMembers).Methods("PUT")
+	protected.HandleFunc("/meal-plans/{id:[0-9]+}/members/{memberId:[0-9]+}/meals", mealPlanHandler.GetMemberMeals).Methods("GET")
+	protected.Handle("/meal-plans/{id:[0-9]+}/print", withLocale(withUnits(http.HandlerFunc(mealPlanHandler.PrintMealPlan)))).Methods("GET")
+
+	// Protected routes - Recipe templates, copied into the user's own drafts
+	protected.HandleFunc("/templates", templateHandler.GetTemplates).Methods("GET")
+	protected.HandleFunc("/templates/{id:[0-9]+}", templateHandler.GetTemplate).Methods("GET")
+	protected.Handle("/templates/{id:[0-9]+}/instantiate", idempotent(templateHandler.InstantiateTemplate)).Methods("POST")
+
+	// Reference data maintenance - admins only
+	admin := protected.PathPrefix("").Subrouter()
+	admin.Use(middleware.RequireAdmin)
+	admin.HandleFunc("/ingredients/densities", ingredientHandler.ListIngredientDensities).Methods("GET")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/density", ingredientHandler.SetIngredientDensity).Methods("PUT")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/density", ingredientHandler.DeleteIngredientDensity).Methods("DELETE")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/prices", costHandler.SetIngredientPrice).Methods("POST")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/products", ingredientHandler.CreateIngredientProduct).Methods("POST")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/products/{productId:[0-9]+}", ingredientHandler.UpdateIngredientProduct).Methods("PUT")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/products/{productId:[0-9]+}", ingredientHandler.DeleteIngredientProduct).Methods("DELETE")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/substitutions", ingredientHandler.SetSubstitutions).Methods("PUT")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/translations", ingredientHandler.SetTranslations).Methods("PUT")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/dietary", ingredientHandler.SetIngredientDietary).Methods("PUT")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/allergens", ingredientHandler.SetIngredientAllergens).Methods("PUT")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/allergens", ingredientHandler.AddIngredientAllergen).Methods("POST")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/allergens/{code}", ingredientHandler.RemoveIngredientAllergen).Methods("DELETE")
+	admin.HandleFunc("/recipes/quality", qualityHandler.ListRecipeQuality).Methods("GET")
+	admin.HandleFunc("/recipes/owners", ownershipHandler.ListRecipeOwners).Methods("GET")
+	admin.HandleFunc("/ingredients/unused", cleanupHandler.GetUnusedIngredients).Methods("GET")
+	admin.HandleFunc("/ingredients/unused/cleanup", cleanupHandler.CleanupIngredients).Methods("POST")
+	admin.HandleFunc("/ingredients/duplicates", cleanupHandler.GetDuplicateIngredients).Methods("GET")
+	admin.HandleFunc("/ingredients/{id:[0-9]+}/merge/{targetId:[0-9]+}", cleanupHandler.MergeIngredient).Methods("POST")
+	admin.HandleFunc("/templates", templateHandler.CreateTemplate).Methods("POST")
+	admin.HandleFunc("/templates/{id:[0-9]+}", templateHandler.DeleteTemplate).Methods("DELETE")
+	admin.HandleFunc("/backfills", backfillHandler.StartBackfill).Methods("POST")
+	admin.HandleFunc("/backfills", backfillHandler.ListBackfills).Methods("GET")
+	admin.HandleFunc("/backfills/{id:[0-9]+}", backfillHandler.GetBackfill).Methods("GET")
+	admin.HandleFunc("/audit", auditor.ListEntries).Methods("GET")
+
+	// Grocery list generation - bulkheaded, it fans out over many recipes
+	groceryBulkhead := middleware.Bulkhead("grocery-list",
+		middleware.BulkheadLimit("GROCERY_LIST_MAX_CONCURRENT", 8), 2*time.Second)
+	auditor.Skip(protected.Handle("/grocery-list", groceryBulkhead(withLocale(withUnits(http.HandlerFunc(groceryHandler.GenerateGroceryList))))).Methods("POST"))
+	protected.Handle("/grocery-list/shares", groceryBulkhead(withLocale(withUnits(http.HandlerFunc(sharedListHandler.ShareGroceryList))))).Methods("POST")
+	protected.HandleFunc("/grocery-list/shares/{token}", sharedListHandler.RevokeSharedList).Methods("DELETE")
+
+	// Store layouts used to order grocery lists by aisle
+	protected.HandleFunc("/me/store-layouts", groceryHandler.GetStoreLayouts).Methods("GET")
+	protected.HandleFunc("/me/store-layouts", groceryHandler.CreateStoreLayout).Methods("POST")
+	protected.HandleFunc("/me/store-layouts/{id:[0-9]+}", groceryHandler.UpdateStoreLayout).Methods("PUT")
+	protected.HandleFunc("/me/store-layouts/{id:[0-9]+}", groceryHandler.DeleteStoreLayout).Methods("DELETE")
+
+	// Saved recipe searches and their new-recipe alerts
+	protected.HandleFunc("/me/saved-searches", savedSearchHandler.GetSavedSearches).Methods("GET")
+	protected.HandleFunc("/me/saved-searches", savedSearchHandler.CreateSavedSearch).Methods("POST")
+	protected.HandleFunc("/me/saved-searches/{id:[0-9]+}", savedSearchHandler.UpdateSavedSearch).Methods("PUT")
+	protected.HandleFunc("/me/saved-searches/{id:[0-9]+}", savedSearchHandler.DeleteSavedSearch).Methods("DELETE")
+
+	// Following and the activity feed
+	protected.HandleFunc("/me/following", socialHandler.GetFollowing).Methods("GET")
+	protected.HandleFunc("/me/following/{userId:[0-9]+}", socialHandler.Follow).Methods("PUT")
+	protected.HandleFunc("/me/following/{userId:[0-9]+}", socialHandler.Unfollow).Methods("DELETE")
+	protected.HandleFunc("/me/followers", socialHandler.GetFollowers).Methods("GET")
+	protected.HandleFunc("/me/feed", socialHandler.GetFeed).Methods("GET")
+
+	// Pantry and the recipes it can make
+	protected.HandleFunc("/me/pantry", pantryHandler.GetPantry).Methods("GET")
+	protected.HandleFunc("/me/pantry", pantryHandler.UpdatePantry).Methods("PUT")
+	protected.HandleFunc("/me/pantry/events", pantryHandler.StreamPantry).Methods("GET")
+	protected.HandleFunc("/me/pantry/items/{ingredient_id:[0-9]+}", pantryHandler.AddItem).Methods("PUT")
+	protected.HandleFunc("/me/pantry/items/{ingredient_id:[0-9]+}", pantryHandler.RemoveItem).Methods("DELETE")
+	protected.Handle("/recipes/cookable", withLocale(withUnits(http.HandlerFunc(pantryHandler.GetCookableRecipes)))).Methods("GET")
+
+	// Households sharing a pantry
+	protected.HandleFunc("/households", householdHandler.CreateHousehold).Methods("POST")
+	protected.HandleFunc("/households/join", householdHandler.JoinHousehold).Methods("POST")
+	protected.HandleFunc("/me/household", householdHandler.GetHousehold).Methods("GET")
+	protected.HandleFunc("/me/household/members/{user_id:[0-9]+}", householdHandler.RemoveMember).Methods("DELETE")
+
+	// Favorites
+	protected.HandleFunc("/recipes/{id:[0-9]+}/favorite", favoriteHandler.AddFavorite).Methods("POST")
+	protected.HandleFunc("/recipes/{id:[0-9]+}/favorite", favoriteHandler.RemoveFavorite).Methods("DELETE")
+	protected.HandleFunc("/users/me/favorites", favoriteHandler.GetMyFavorites).Methods("GET")
+
+	// Standing against the gateway's rate-limit quotas
+	protected.HandleFunc("/me/usage", usageHandler.GetMyUsage).Methods("GET")
+
+	// Profile settings
+	protected.HandleFunc("/me/profile", profileHandler.GetProfileSettings).Methods("GET")
+	protected.HandleFunc("/me/profile", profileHandler.UpdateProfileSettings).Methods("PUT")
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+// SavedSearchHandler serves the searches users keep and get alerts for.
+type SavedSearchHandler struct {
+	savedSearchService service.SavedSearchService
+}
+
+func NewSavedSearchHandler(savedSearchService service.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{savedSearchService: savedSearchService}
+}
+
+func (h *SavedSearchHandler) GetSavedSearches(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	searches, err := h.savedSearchService.GetSavedSearches(user.UserID)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch saved searches", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, searches, http.StatusOK)
+}
+
+func (h *SavedSearchHandler) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	search, err := h.savedSearchService.CreateSavedSearch(user.UserID, req)
+	if err != nil {
+		writeSavedSearchError(w, err, "Failed to create saved search")
+		return
+	}
+
+	models.WriteSuccessResponse(w, search, http.StatusCreated)
+}
+
+func (h *SavedSearchHandler) UpdateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid saved search ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	search, err := h.savedSearchService.UpdateSavedSearch(r.Context(), user.UserID, id, req)
+	if err != nil {
+		writeSavedSearchError(w, err, "Failed to update saved search")
+		return
+	}
+
+	models.WriteSuccessResponse(w, search, http.StatusOK)
+}
+
+func (h *SavedSearchHandler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid saved search ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.savedSearchService.DeleteSavedSearch(r.Context(), user.UserID, id); err != nil {
+		switch err {
+		case domain.ErrSavedSearchNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to delete saved search", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Saved search deleted successfully"}, http.StatusNoContent)
+}
+
+func writeSavedSearchError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrSavedSearchNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	case domain.ErrSavedSearchNameRequired, domain.ErrInvalidSavedSearch,
+		domain.ErrIngredientNotFound, domain.ErrCategoryNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
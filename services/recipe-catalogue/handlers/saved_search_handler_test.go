@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type savedSearchHandlerTestSetup struct {
+	handler            *SavedSearchHandler
+	savedSearchService *mocks.MockSavedSearchService
+}
+
+func setupSavedSearchHandlerTest() *savedSearchHandlerTestSetup {
+	mockService := new(mocks.MockSavedSearchService)
+	return &savedSearchHandlerTestSetup{
+		handler:            NewSavedSearchHandler(mockService),
+		savedSearchService: mockService,
+	}
+}
+
+func TestSavedSearchHandler_CreateSavedSearch_Success(t *testing.T) {
+	setup := setupSavedSearchHandlerTest()
+	maxTime := 30
+	reqBody := models.SavedSearchRequest{Name: "Quick chicken", IngredientIDs: []int{2, 5}, MaxTimeMinutes: &maxTime, Notify: true}
+	setup.savedSearchService.On("CreateSavedSearch", 1, reqBody).Return(&models.SavedSearch{
+		ID: 4, UserID: 1, Name: "Quick chicken", IngredientIDs: []int{2, 5}, MaxTimeMinutes: &maxTime, Notify: true,
+	}, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/me/saved-searches", bytes.NewBuffer(body))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.CreateSavedSearch(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var response models.SavedSearch
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, 4, response.ID)
+	assert.Equal(t, []int{2, 5}, response.IngredientIDs)
+	assert.True(t, response.Notify)
+}
+
+func TestSavedSearchHandler_CreateSavedSearch_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"missing name", domain.ErrSavedSearchNameRequired, http.StatusBadRequest},
+		{"no ingredients", domain.ErrInvalidSavedSearch, http.StatusBadRequest},
+		{"unknown ingredient", domain.ErrIngredientNotFound, http.StatusBadRequest},
+		{"unknown category", domain.ErrCategoryNotFound, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupSavedSearchHandlerTest()
+			setup.savedSearchService.On("CreateSavedSearch", 1, mock.Anything).Return(nil, tt.err)
+
+			req := httptest.NewRequest("POST", "/me/saved-searches", bytes.NewBufferString(`{"name": "Search"}`))
+			req = test.AddAuthContext(req, 1, "test@example.com")
+			recorder := httptest.NewRecorder()
+
+			setup.handler.CreateSavedSearch(recorder, req)
+
+			assert.Equal(t, tt.expected, recorder.Code)
+		})
+	}
+}
+
+func TestSavedSearchHandler_CreateSavedSearch_Unauthenticated(t *testing.T) {
+	setup := setupSavedSearchHandlerTest()
+
+	req := httptest.NewRequest("POST", "/me/saved-searches", bytes.NewBufferString("{}"))
+	recorder := httptest.NewRecorder()
+
+	setup.handler.CreateSavedSearch(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	setup.savedSearchService.AssertNotCalled(t, "CreateSavedSearch", mock.Anything, mock.Anything)
+}
+
+func TestSavedSearchHandler_GetSavedSearches(t *testing.T) {
+	setup := setupSavedSearchHandlerTest()
+	setup.savedSearchService.On("GetSavedSearches", 1).Return([]models.SavedSearch{
+		{ID: 4, UserID: 1, Name: "Quick chicken", IngredientIDs: []int{2}},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/me/saved-searches", nil)
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetSavedSearches(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response []models.SavedSearch
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, "Quick chicken", response[0].Name)
+}
+
+func TestSavedSearchHandler_UpdateSavedSearch_NotFound(t *testing.T) {
+	setup := setupSavedSearchHandlerTest()
+	setup.savedSearchService.On("UpdateSavedSearch", 1, 9, mock.Anything).Return(nil, domain.ErrSavedSearchNotFound)
+
+	req := httptest.NewRequest("PUT", "/me/saved-searches/9", bytes.NewBufferString(`{"name": "Search", "ingredient_ids": [2]}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.UpdateSavedSearch(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestSavedSearchHandler_DeleteSavedSearch_Success(t *testing.T) {
+	setup := setupSavedSearchHandlerTest()
+	setup.savedSearchService.On("DeleteSavedSearch", 1, 9).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/me/saved-searches/9", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "9"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.DeleteSavedSearch(recorder, req)
+
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	setup.savedSearchService.AssertExpectations(t)
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"  // cover photos may be GIFs
+	_ "image/jpeg" // or JPEGs
+	_ "image/png"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"meal-prep/shared/metrics"
+	"meal-prep/shared/models"
+	"meal-prep/shared/pixelfont"
+)
+
+// Share cards use the OpenGraph image size, with the cover photo filling a
+// panel on the left and the text beside it.
+const (
+	shareCardWidth  = 1200
+	shareCardHeight = 630
+	photoPanelWidth = 540
+	cardMargin      = 64
+
+	titleScale    = 5
+	maxTitleLines = 4
+	detailScale   = 3
+
+	// Cover photos are fetched from wherever their URL points, so both the
+	// download and the decoded image are capped
+	maxPhotoBytes  = 8 << 20
+	maxPhotoPixels = 40_000_000
+)
+
+var (
+	cardBackground = color.RGBA{250, 247, 242, 255}
+	cardInk        = color.RGBA{38, 38, 38, 255}
+	cardMuted      = color.RGBA{110, 110, 110, 255}
+	cardAccent     = color.RGBA{46, 125, 50, 255}
+
+	errHostNotAllowed = errors.New("host resolves to a private address")
+	errPhotoTooLarge  = errors.New("photo is too large")
+)
+
+// renderShareCard draws the card. Without a photo the panel shows the
+// recipe's initial instead.
+func renderShareCard(card *models.RecipeShareCard, photo image.Image) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, shareCardWidth, shareCardHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(cardBackground), image.Point{}, draw.Src)
+
+	panel := image.Rect(0, 0, photoPanelWidth, shareCardHeight)
+	if photo != nil {
+		drawCoverFill(img, panel, photo)
+	} else {
+		draw.Draw(img, panel, image.NewUniform(cardAccent), image.Point{}, draw.Src)
+		initial := strings.ToUpper(string([]rune(strings.TrimSpace(card.Recipe.Name) + "?")[0]))
+		scale := 30
+		pixelfont.Draw(img, (photoPanelWidth-pixelfont.Width(initial, scale))/2,
+			(shareCardHeight-pixelfont.Height(scale))/2, scale, color.White, initial)
+	}
+
+	x := photoPanelWidth + cardMargin
+	width := shareCardWidth - x - cardMargin
+	y := cardMargin + 16
+	for _, line := range titleLines(card.Recipe.Name, width) {
+		pixelfont.Draw(img, x, y, titleScale, cardInk, line)
+		y += pixelfont.Height(titleScale) + 16
+	}
+
+	if details := cardDetails(card.Recipe); details != "" {
+		pixelfont.Draw(img, x, y+24, detailScale, cardMuted, fitLine(details, detailScale, width))
+	}
+
+	footerY := shareCardHeight - cardMargin - pixelfont.Height(detailScale)
+	pixelfont.Draw(img, x, footerY, detailScale, cardAccent, "meal-prep")
+	draw.Draw(img, image.Rect(photoPanelWidth, shareCardHeight-8, shareCardWidth, shareCardHeight),
+		image.NewUniform(cardAccent), image.Point{}, draw.Src)
+
+	return img
+}
+
+// titleLines wraps the recipe name, shortening the last line when it runs
+// past maxTitleLines.
+func titleLines(name string, width int) []string {
+	lines := pixelfont.Wrap(name, titleScale, width)
+	if len(lines) > maxTitleLines {
+		lines = lines[:maxTitleLines]
+		lines[maxTitleLines-1] = fitLine(lines[maxTitleLines-1]+"...", titleScale, width)
+	}
+	for i, line := range lines {
+		lines[i] = fitLine(line, titleScale, width)
+	}
+	return lines
+}
+
+// fitLine shortens s with an ellipsis until it is no wider than width.
+func fitLine(s string, scale, width int) string {
+	if pixelfont.Width(s, scale) <= width {
+		return s
+	}
+	runes := []rune(strings.TrimSuffix(s, "..."))
+	for len(runes) > 0 && pixelfont.Width(string(runes)+"...", scale) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
+
+// cardDetails is the line under the title: total time and difficulty, when
+// the recipe has them.
+func cardDetails(recipe models.Recipe) string {
+	var parts []string
+	if recipe.TotalTimeMinutes != nil && *recipe.TotalTimeMinutes > 0 {
+		minutes := *recipe.TotalTimeMinutes
+		switch {
+		case minutes < 60:
+			parts = append(parts, fmt.Sprintf("%d min", minutes))
+		case minutes%60 == 0:
+			parts = append(parts, fmt.Sprintf("%d h", minutes/60))
+		default:
+			parts = append(parts, fmt.Sprintf("%d h %d min", minutes/60, minutes%60))
+		}
+	}
+	if recipe.Difficulty != nil && *recipe.Difficulty != "" {
+		parts = append(parts, strings.ToUpper((*recipe.Difficulty)[:1])+(*recipe.Difficulty)[1:])
+	}
+	return strings.Join(parts, " / ")
+}
+
+// drawCoverFill scales photo to cover rect, cropping the overflow evenly on
+// both sides.
+func drawCoverFill(dst *image.RGBA, rect image.Rectangle, photo image.Image) {
+	src := photo.Bounds()
+	if src.Empty() {
+		return
+	}
+	scale := max(float64(rect.Dx())/float64(src.Dx()), float64(rect.Dy())/float64(src.Dy()))
+	offsetX := (float64(src.Dx()) - float64(rect.Dx())/scale) / 2
+	offsetY := (float64(src.Dy()) - float64(rect.Dy())/scale) / 2
+	for y := 0; y < rect.Dy(); y++ {
+		sy := src.Min.Y + min(int(offsetY+float64(y)/scale), src.Dy()-1)
+		for x := 0; x < rect.Dx(); x++ {
+			sx := src.Min.X + min(int(offsetX+float64(x)/scale), src.Dx()-1)
+			dst.Set(rect.Min.X+x, rect.Min.Y+y, photo.At(sx, sy))
+		}
+	}
+}
+
+// photoClient refuses to connect to loopback, private and link-local
+// addresses, redirects included, so a photo URL cannot be used to probe
+// the internal network.
+var photoClient = &http.Client{
+	Timeout: 5 * time.Second,
+	Transport: metrics.Transport("recipe-photos", &http.Transport{
+		DialContext: (&net.Dialer{Timeout: 3 * time.Second, Control: rejectPrivateAddress}).DialContext,
+	}),
+}
+
+func rejectPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return errHostNotAllowed
+	}
+	return nil
+}
+
+// fetchPhoto downloads and decodes a cover photo.
+func fetchPhoto(url string) (image.Image, error) {
+	resp, err := photoClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching photo: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPhotoBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxPhotoBytes {
+		return nil, errPhotoTooLarge
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > maxPhotoPixels {
+		return nil, errPhotoTooLarge
+	}
+	photo, _, err := image.Decode(bytes.NewReader(body))
+	return photo, err
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// eventStreamHeartbeat is how often an idle event stream gets a comment, so
+// proxies don't close it.
+const eventStreamHeartbeat = 25 * time.Second
+
+// SharedListHandler serves grocery lists shared by link. Only sharing and
+// revoking need an account; the rest is open to anyone with the token.
+type SharedListHandler struct {
+	groceryService    service.GroceryService
+	sharedListService service.SharedListService
+	baseURL           string
+}
+
+func NewSharedListHandler(groceryService service.GroceryService, sharedListService service.SharedListService, baseURL string) *SharedListHandler {
+	return &SharedListHandler{
+		groceryService:    groceryService,
+		sharedListService: sharedListService,
+		baseURL:           strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// ShareGroceryList generates a grocery list from the same request body as
+// POST /grocery-list and shares it. Names and units are fixed in the
+// owner's language and measurement system.
+func (h *SharedListHandler) ShareGroceryList(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.GroceryListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.RecipeIDs) == 0 && req.MealPlanID == nil && req.From == "" && req.To == "" {
+		models.WriteErrorResponse(w, "Recipe IDs, a meal plan ID or a date range is required", http.StatusBadRequest)
+		return
+	}
+	req.MeasurementSystem = measurementSystemFrom(r.Context())
+
+	items, err := h.groceryService.GenerateGroceryList(r.Context(), user.UserID, req)
+	if err != nil {
+		writeGroceryListError(w, err)
+		return
+	}
+	localizeGroceryItems(r.Context(), items)
+
+	list, err := h.sharedListService.ShareGroceryList(user.UserID, items)
+	if err != nil {
+		writeSharedListError(w, err, "Failed to share grocery list")
+		return
+	}
+	list.URL = h.baseURL + "/shared-lists/" + list.Token
+
+	models.WriteSuccessResponse(w, list, http.StatusCreated)
+}
+
+func (h *SharedListHandler) RevokeSharedList(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.sharedListService.RevokeSharedList(r.Context(), user.UserID, mux.Vars(r)["token"]); err != nil {
+		writeSharedListError(w, err, "Failed to revoke shared list")
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Shared list revoked"}, http.StatusNoContent)
+}
+
+func (h *SharedListHandler) GetSharedList(w http.ResponseWriter, r *http.Request) {
+	list, err := h.sharedListService.GetSharedList(mux.Vars(r)["token"])
+	if err != nil {
+		writeSharedListError(w, err, "Failed to fetch shared list")
+		return
+	}
+
+	models.WriteSuccessResponse(w, list, http.StatusOK)
+}
+
+func (h *SharedListHandler) CheckItem(w http.ResponseWriter, r *http.Request) {
+	position, err := strconv.Atoi(mux.Vars(r)["position"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid item position", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CheckShoppingListItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Checked == nil {
+		models.WriteErrorResponse(w, domain.ErrCheckedRequired.Error(), http.StatusBadRequest)
+		return
+	}
+
+	change, err := h.sharedListService.CheckItem(mux.Vars(r)["token"], position, *req.Checked)
+	if err != nil {
+		writeSharedListError(w, err, "Failed to update item")
+		return
+	}
+
+	models.WriteSuccessResponse(w, change, http.StatusOK)
+}
+
+// StreamSharedList is a server-sent event stream: a "list" event with the
+// whole list, then an "item" event for every change, each with the version
+// as its id. The stream ends when the list is revoked or the client falls
+// behind; reconnecting starts again with a fresh list.
+func (h *SharedListHandler) StreamSharedList(w http.ResponseWriter, r *http.Request) {
+	token := mux.Vars(r)["token"]
+	changes, stop, err := h.sharedListService.Watch(token)
+	if err != nil {
+		writeSharedListError(w, err, "Failed to open shared list")
+		return
+	}
+	defer stop()
+
+	// Loaded after subscribing so no change is missed in between; changes
+	// already in the list are skipped by their version
+	list, err := h.sharedListService.GetSharedList(token)
+	if err != nil {
+		writeSharedListError(w, err, "Failed to open shared list")
+		return
+	}
+
+	// The stream outlives the server's write timeout. Writers that can't
+	// clear the deadline (or flush) just deliver events less promptly.
+	controller := http.NewResponseController(w)
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeServerSentEvent(w, "list", list.Version, list); err != nil {
+		return
+	}
+	_ = controller.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			if err := writeServerSentEvent(w, "item", change.Version, change); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		_ = controller.Flush()
+	}
+}
+
+func writeServerSentEvent(w http.ResponseWriter, event string, id int, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\nid: %d\ndata: %s\n\n", event, id, data)
+	return err
+}
+
+func writeSharedListError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case domain.ErrSharedListNotFound, domain.ErrSharedListItemNotFound:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	case domain.ErrEmptyShoppingList:
+		models.WriteErrorResponse(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type sharedListHandlerTestSetup struct {
+	handler           *SharedListHandler
+	groceryService    *mocks.MockGroceryService
+	sharedListService *mocks.MockSharedListService
+}
+
+func setupSharedListHandlerTest() *sharedListHandlerTestSetup {
+	groceryService := new(mocks.MockGroceryService)
+	sharedListService := new(mocks.MockSharedListService)
+
+	return &sharedListHandlerTestSetup{
+		handler:           NewSharedListHandler(groceryService, sharedListService, "http://localhost:8000/"),
+		groceryService:    groceryService,
+		sharedListService: sharedListService,
+	}
+}
+
+func TestSharedListHandler_ShareGroceryList_Success(t *testing.T) {
+	setup := setupSharedListHandlerTest()
+	items := []models.GroceryListItem{{IngredientID: 3, Ingredient: models.Ingredient{Name: "Onion"}, TotalQuantity: 2, Unit: "piece"}}
+	setup.groceryService.On("GenerateGroceryList", 1, mock.AnythingOfType("models.GroceryListRequest")).Return(items, nil)
+	setup.sharedListService.On("ShareGroceryList", 1, items).Return(&models.SharedShoppingList{Token: "token-1", UserID: 1}, nil)
+
+	body, _ := json.Marshal(models.GroceryListRequest{RecipeIDs: []int{4}})
+	req := httptest.NewRequest("POST", "/grocery-list/shares", bytes.NewBuffer(body))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.ShareGroceryList(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var list models.SharedShoppingList
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&list))
+	assert.Equal(t, "http://localhost:8000/shared-lists/token-1", list.URL)
+}
+
+func TestSharedListHandler_ShareGroceryList_NoSource(t *testing.T) {
+	setup := setupSharedListHandlerTest()
+
+	req := httptest.NewRequest("POST", "/grocery-list/shares", strings.NewReader(`{}`))
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.ShareGroceryList(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	setup.groceryService.AssertNotCalled(t, "GenerateGroceryList", mock.Anything, mock.Anything)
+}
+
+func TestSharedListHandler_CheckItem(t *testing.T) {
+	setup := setupSharedListHandlerTest()
+	change := &models.SharedShoppingListChange{Version: 2, Item: models.SharedShoppingListItem{Position: 3, Checked: true}}
+	setup.sharedListService.On("CheckItem", "token-1", 3, true).Return(change, nil)
+
+	req := httptest.NewRequest("PUT", "/shared-lists/token-1/items/3", strings.NewReader(`{"checked": true}`))
+	req = mux.SetURLVars(req, map[string]string{"token": "token-1", "position": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.CheckItem(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	setup.sharedListService.AssertExpectations(t)
+}
+
+func TestSharedListHandler_CheckItem_CheckedRequired(t *testing.T) {
+	setup := setupSharedListHandlerTest()
+
+	req := httptest.NewRequest("PUT", "/shared-lists/token-1/items/3", strings.NewReader(`{}`))
+	req = mux.SetURLVars(req, map[string]string{"token": "token-1", "position": "3"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.CheckItem(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestSharedListHandler_GetSharedList_NotFound(t *testing.T) {
+	setup := setupSharedListHandlerTest()
+	setup.sharedListService.On("GetSharedList", "gone").Return(nil, domain.ErrSharedListNotFound)
+
+	req := httptest.NewRequest("GET", "/shared-lists/gone", nil)
+	req = mux.SetURLVars(req, map[string]string{"token": "gone"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetSharedList(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestSharedListHandler_StreamSharedList(t *testing.T) {
+	setup := setupSharedListHandlerTest()
+	changes := make(chan models.SharedShoppingListChange, 1)
+	changes <- models.SharedShoppingListChange{Version: 4, Item: models.SharedShoppingListItem{Position: 1, Checked: true}}
+	close(changes)
+	setup.sharedListService.On("Watch", "token-1").Return(changes, nil)
+	setup.sharedListService.On("GetSharedList", "token-1").Return(&models.SharedShoppingList{Token: "token-1", Version: 3}, nil)
+
+	req := httptest.NewRequest("GET", "/shared-lists/token-1/events", nil)
+	req = mux.SetURLVars(req, map[string]string{"token": "token-1"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.StreamSharedList(recorder, req)
+
+	assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+	body := recorder.Body.String()
+	assert.Contains(t, body, "event: list\nid: 3\ndata: {\"token\":\"token-1\"")
+	assert.Contains(t, body, "event: item\nid: 4\ndata: {\"version\":4")
+	assert.Less(t, strings.Index(body, "event: list"), strings.Index(body, "event: item"))
+}
+
+func TestSharedListHandler_StreamSharedList_NotFound(t *testing.T) {
+	setup := setupSharedListHandlerTest()
+	setup.sharedListService.On("Watch", "gone").Return(nil, domain.ErrSharedListNotFound)
+
+	req := httptest.NewRequest("GET", "/shared-lists/gone/events", nil)
+	req = mux.SetURLVars(req, map[string]string{"token": "gone"})
+	recorder := httptest.NewRecorder()
+
+	setup.handler.StreamSharedList(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type SocialHandler struct {
+	socialService service.SocialService
+}
+
+func NewSocialHandler(socialService service.SocialService) *SocialHandler {
+	return &SocialHandler{socialService: socialService}
+}
+
+func (h *SocialHandler) Follow(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	targetID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.socialService.Follow(user.UserID, targetID)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidFollowTarget:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrCannotFollowSelf:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to follow user", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "User followed successfully"}, http.StatusOK)
+}
+
+func (h *SocialHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	targetID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.socialService.Unfollow(user.UserID, targetID)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidFollowTarget:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrCannotFollowSelf:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrNotFollowing:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to unfollow user", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "User unfollowed successfully"}, http.StatusNoContent)
+}
+
+func (h *SocialHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	following, err := h.socialService.GetFollowing(user.UserID)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch followed users", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, following, http.StatusOK)
+}
+
+func (h *SocialHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	followers, err := h.socialService.GetFollowers(user.UserID)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch followers", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, followers, http.StatusOK)
+}
+
+func (h *SocialHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	params, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	feed, meta, err := h.socialService.GetFeed(user.UserID, params)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch activity feed", http.StatusInternalServerError)
+		return
+	}
+
+	models.WritePaginatedResponse(w, feed, meta, http.StatusOK)
+}
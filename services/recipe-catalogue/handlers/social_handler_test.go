@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/handlers/mocks"
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/events"
+	"meal-prep/shared/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type socialHandlerTestSetup struct {
+	handler       *SocialHandler
+	socialService *mocks.MockSocialService
+}
+
+func setupSocialHandlerTest() *socialHandlerTestSetup {
+	mockService := new(mocks.MockSocialService)
+
+	return &socialHandlerTestSetup{
+		handler:       NewSocialHandler(mockService),
+		socialService: mockService,
+	}
+}
+
+func TestSocialHandler_Follow_Success(t *testing.T) {
+	setup := setupSocialHandlerTest()
+	setup.socialService.On("Follow", 1, 2).Return(nil)
+
+	req := httptest.NewRequest("PUT", "/me/following/2", nil)
+	req = mux.SetURLVars(req, map[string]string{"userId": "2"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.Follow(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	setup.socialService.AssertExpectations(t)
+}
+
+func TestSocialHandler_Follow_Self(t *testing.T) {
+	setup := setupSocialHandlerTest()
+	setup.socialService.On("Follow", 1, 1).Return(domain.ErrCannotFollowSelf)
+
+	req := httptest.NewRequest("PUT", "/me/following/1", nil)
+	req = mux.SetURLVars(req, map[string]string{"userId": "1"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.Follow(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestSocialHandler_Unfollow_NotFollowing(t *testing.T) {
+	setup := setupSocialHandlerTest()
+	setup.socialService.On("Unfollow", 1, 2).Return(domain.ErrNotFollowing)
+
+	req := httptest.NewRequest("DELETE", "/me/following/2", nil)
+	req = mux.SetURLVars(req, map[string]string{"userId": "2"})
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.Unfollow(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestSocialHandler_GetFeed_Paginated(t *testing.T) {
+	setup := setupSocialHandlerTest()
+	params := models.PaginationParams{Page: 2, PerPage: 5}
+	feed := []models.Activity{{ID: 4, UserID: 2, Type: events.RecipePublished, RecipeID: 9, RecipeName: "Shakshuka"}}
+	setup.socialService.On("GetFeed", 1, params).Return(feed, models.NewPaginationMeta(params, 6), nil)
+
+	req := httptest.NewRequest("GET", "/me/feed?page=2&per_page=5", nil)
+	req = test.AddAuthContext(req, 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetFeed(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data       []models.Activity     `json:"data"`
+		Pagination models.PaginationMeta `json:"pagination"`
+	}
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, feed, response.Data)
+	assert.Equal(t, 2, response.Pagination.TotalPages)
+}
+
+func TestSocialHandler_GetFeed_Unauthenticated(t *testing.T) {
+	setup := setupSocialHandlerTest()
+
+	req := httptest.NewRequest("GET", "/me/feed", nil)
+	recorder := httptest.NewRecorder()
+
+	setup.handler.GetFeed(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	setup.socialService.AssertNotCalled(t, "GetFeed")
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/metrics"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"meal-prep/shared/mtls"
+	"meal-prep/shared/tracing"
+)
+
+// UsageHandler shows users their standing against the gateway's quotas. The
+// catalogue's own quota is metered here; other services' are fetched from them.
+// Quotas are counted by the services that serve them because Kong keeps its
+// counters to itself and the Go gateway does not rate limit, so the figures
+// are an estimate of what Kong enforces.
+type UsageHandler struct {
+	meter  *middleware.UsageMeter
+	remote []middleware.UsageReporter
+}
+
+func NewUsageHandler(meter *middleware.UsageMeter, remote ...middleware.UsageReporter) *UsageHandler {
+	return &UsageHandler{meter: meter, remote: remote}
+}
+
+const (
+	defaultRecommendationsURL = "http://recommendations-service:8003"
+	// recommendationsTarget labels calls to it in logs and metrics
+	recommendationsTarget = "recommendations-service"
+	// usageClientTimeout keeps a slow service from holding up /me/usage
+	usageClientTimeout = 2 * time.Second
+)
+
+// UsageMeterFromEnv meters the gateway's recipes quota. The limits must be
+// set to match kong.yml.
+func UsageMeterFromEnv() *middleware.UsageMeter {
+	return middleware.NewUsageMeter("recipes",
+		middleware.UsageLimit("RECIPES_RATE_LIMIT_PER_MINUTE", 100),
+		middleware.UsageLimit("RECIPES_RATE_LIMIT_PER_HOUR", 5000)).
+		WarnAt(middleware.UsageWarningThresholdFromEnv(), middleware.NewLogUsageWarningNotifier())
+}
+
+// RecommendationsUsageClientFromEnv fetches usage of the recommendations
+// quota from the service at RECOMMENDATIONS_SERVICE_URL, through transport
+// when it is not nil.
+func RecommendationsUsageClientFromEnv(transport http.RoundTripper) (*middleware.UsageClient, error) {
+	client, err := recommendationsClient(usageClientTimeout, transport)
+	if err != nil {
+		return nil, err
+	}
+	return middleware.NewUsageClient(recommendationsURL()+"/internal/usage", client), nil
+}
+
+// recommendationsClient calls the recommendations service over HTTP, mTLS
+// when it is configured, or through transport, which cmd/allinone sets to
+// reach the service in the same process.
+func recommendationsClient(timeout time.Duration, transport http.RoundTripper) (*http.Client, error) {
+	var client *http.Client
+	if transport != nil {
+		client = &http.Client{Transport: tracing.Transport(logging.Transport(transport)), Timeout: timeout}
+	} else {
+		var err error
+		client, err = mtls.HTTPClientFromEnv(timeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+	client.Transport = metrics.Transport(recommendationsTarget, client.Transport)
+	return client, nil
+}
+
+func recommendationsURL() string {
+	if baseURL := strings.TrimRight(os.Getenv("RECOMMENDATIONS_SERVICE_URL"), "/"); baseURL != "" {
+		return baseURL
+	}
+	return defaultRecommendationsURL
+}
+
+// Track counts the request against the user's recipes quota.
+func (h *UsageHandler) Track(next http.Handler) http.Handler {
+	return h.meter.Middleware(next)
+}
+
+// GetMyUsage leaves out quotas that cannot be fetched rather than failing.
+func (h *UsageHandler) GetMyUsage(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	usage := models.APIUsage{UserID: user.UserID, Quotas: make([]models.QuotaUsage, 0, 1+len(h.remote))}
+	for _, reporter := range append([]middleware.UsageReporter{h.meter}, h.remote...) {
+		quota, err := reporter.Usage(r.Context(), user.UserID)
+		if err != nil {
+			logging.WithContext(r.Context()).Warn("Failed to fetch quota usage", "error", err)
+			continue
+		}
+		usage.Quotas = append(usage.Quotas, quota)
+	}
+
+	models.WriteSuccessResponse(w, usage, http.StatusOK)
+}
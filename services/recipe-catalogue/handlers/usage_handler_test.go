@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/test"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubUsageReporter struct {
+	usage models.QuotaUsage
+	err   error
+}
+
+func (s stubUsageReporter) Usage(ctx context.Context, userID int) (models.QuotaUsage, error) {
+	return s.usage, s.err
+}
+
+func TestUsageHandler_GetMyUsage(t *testing.T) {
+	logging.Init("test")
+
+	handler := NewUsageHandler(middleware.NewUsageMeter("recipes", 100, 5000),
+		stubUsageReporter{usage: models.QuotaUsage{Quota: "recommendations", RequestsToday: 12}},
+		stubUsageReporter{err: errors.New("connection refused")})
+
+	req := test.AddAuthContext(httptest.NewRequest("GET", "/me/usage", nil), 1, "test@example.com")
+	recorder := httptest.NewRecorder()
+
+	handler.Track(http.HandlerFunc(handler.GetMyUsage)).ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var response models.APIUsage
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.UserID)
+	require.Len(t, response.Quotas, 2)
+	assert.Equal(t, "recipes", response.Quotas[0].Quota)
+	assert.Equal(t, 1, response.Quotas[0].Minute.Used)
+	assert.Equal(t, 99, response.Quotas[0].Minute.Remaining)
+	assert.Equal(t, 4999, response.Quotas[0].Hour.Remaining)
+	assert.Equal(t, "recommendations", response.Quotas[1].Quota)
+	assert.Equal(t, 12, response.Quotas[1].RequestsToday)
+}
+
+func TestUsageHandler_GetMyUsage_Unauthenticated(t *testing.T) {
+	handler := NewUsageHandler(middleware.NewUsageMeter("recipes", 100, 5000))
+	recorder := httptest.NewRecorder()
+
+	handler.GetMyUsage(recorder, httptest.NewRequest("GET", "/me/usage", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
@@ -1,21 +1,27 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"net/http"
 	"os"
 
-	"meal-prep/services/recipe-catalogue/handlers"
-	"meal-prep/services/recipe-catalogue/repository"
-	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/migrations"
+	"meal-prep/services/recipe-catalogue/app"
 	"meal-prep/shared/database"
 	"meal-prep/shared/logging"
 	"meal-prep/shared/middleware"
+	"meal-prep/shared/server"
+	"meal-prep/shared/tracing"
 
-	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 )
 
 func main() {
+	waitForDeps := flag.Bool("wait-for-deps", false, "retry the database connection with backoff instead of exiting")
+	migrate := flag.Bool("migrate", false, "apply pending schema migrations before serving")
+	flag.Parse()
+
 	// Initialize logging first
 	logging.Init("recipe-catalogue-service")
 
@@ -23,89 +29,86 @@ func main() {
 		logging.Logger.Debug("No .env file found, using system environment variables")
 	}
 
-	// Database connection
-	db, err := database.NewPostgresConnection()
+	// Cancelled on SIGTERM or SIGINT: background jobs stop and the server
+	// drains in-flight requests before main returns
+	ctx, stop := server.SignalContext()
+	defer stop()
+
+	// Export request and query spans when an OTLP endpoint is configured
+	shutdownTracing, err := tracing.Init(ctx, "recipe-catalogue-service")
 	if err != nil {
-		logging.Logger.Error("Failed to connect to database", "error", err)
+		logging.Logger.Error("Failed to initialize tracing", "error", err)
 		os.Exit(1)
 	}
-	defer db.Close()
-
-	logging.Logger.Info("Database connected successfully")
-
-	// Dependency injection chain
-	recipeRepo := repository.NewRecipeRepository(db)
-	categoryRepo := repository.NewCategoryRepository(db)
-	ingredientRepo := repository.NewIngredientRepository(db)
-
-	recipeService := service.NewRecipeService(recipeRepo, categoryRepo, ingredientRepo)
-	ingredientService := service.NewIngredientService(ingredientRepo, recipeRepo)
-	groceryService := service.NewGroceryService(ingredientRepo, recipeRepo)
-
-	recipeHandler := handlers.NewRecipeHandler(recipeService)
-	ingredientHandler := handlers.NewIngredientHandler(ingredientService)
-	groceryHandler := handlers.NewGroceryHandler(groceryService)
-
-	// Routes with logging middleware
-	router := mux.NewRouter()
-	router.Use(middleware.LoggingMiddleware("recipe-catalogue-service"))
-
-	// Public routes - Recipes
-	router.HandleFunc("/recipes", recipeHandler.GetAllRecipes).Methods("GET")
-	router.HandleFunc("/recipes/{id:[0-9]+}", recipeHandler.GetRecipeByID).Methods("GET")
-	router.HandleFunc("/categories", recipeHandler.GetAllCategories).Methods("GET")
-	router.HandleFunc("/categories/{id:[0-9]+}/recipes", recipeHandler.GetRecipesByCategory).Methods("GET")
-	router.HandleFunc("/recipes/search", recipeHandler.SearchRecipesByIngredients).Methods("GET")
-
-	// Public routes - Ingredients
-	router.HandleFunc("/ingredients", ingredientHandler.GetAllIngredients).Methods("GET")
-	router.HandleFunc("/ingredients/{id:[0-9]+}", ingredientHandler.GetIngredientByID).Methods("GET")
-	router.HandleFunc("/ingredients/{id:[0-9]+}/recipes", ingredientHandler.GetRecipesUsingIngredient).Methods("GET")
-
-	// Public routes - Recipe ingredients (read-only)
-	router.HandleFunc("/recipes/{id:[0-9]+}/ingredients", ingredientHandler.GetRecipeIngredients).Methods("GET")
-
-	// Health check
-	router.HandleFunc("/health", healthCheck).Methods("GET")
-
-	// Protected routes - Recipes
-	protected := router.PathPrefix("").Subrouter()
-	protected.Use(middleware.ExtractUserFromGatewayHeaders)
-
-	// Recipe management
-	protected.HandleFunc("/recipes", recipeHandler.CreateRecipe).Methods("POST")
-	protected.HandleFunc("/recipes/{id:[0-9]+}", recipeHandler.UpdateRecipe).Methods("PUT")
-	protected.HandleFunc("/recipes/{id:[0-9]+}", recipeHandler.DeleteRecipe).Methods("DELETE")
+	defer shutdownTracing(context.Background())
+
+	var db *database.DB
+	if !database.UseMemoryStorage() {
+		// Database connection
+		var err error
+		db, err = database.ConnectWithRetry(ctx, database.RetryConfigFromEnv(*waitForDeps), database.NewConnection)
+		if err != nil {
+			logging.Logger.Error("Failed to connect to database", "error", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+
+		logging.Logger.Info("Database connected successfully")
+
+		if *migrate {
+			if err := migrations.Apply(ctx, db, "recipe-catalogue"); err != nil {
+				logging.Logger.Error("Failed to migrate database", "error", err)
+				os.Exit(1)
+			}
+		}
+	}
 
-	// Ingredient management
-	protected.HandleFunc("/ingredients", ingredientHandler.CreateIngredient).Methods("POST")
-	protected.HandleFunc("/ingredients/{id:[0-9]+}", ingredientHandler.UpdateIngredient).Methods("PUT")
-	protected.HandleFunc("/ingredients/{id:[0-9]+}", ingredientHandler.DeleteIngredient).Methods("DELETE")
+	// Users tagged with a data region are served from that region's
+	// database; in memory mode the one store serves every region
+	var regionDBs map[string]*database.DB
+	if db != nil {
+		regionDBs, err = database.ConnectRegions(ctx, database.RetryConfigFromEnv(*waitForDeps), database.RegionsFromEnv())
+		if err != nil {
+			logging.Logger.Error("Failed to connect to region database", "error", err)
+			os.Exit(1)
+		}
+		for region, regionDB := range regionDBs {
+			defer regionDB.Close()
+
+			// Nothing else migrates a region's database, and the catalogue's
+			// migrations add its system user to the auth schema
+			if *migrate {
+				for _, service := range []string{"auth", "recipe-catalogue"} {
+					if err := migrations.Apply(ctx, regionDB, service); err != nil {
+						logging.Logger.Error("Failed to migrate database", "region", region, "error", err)
+						os.Exit(1)
+					}
+				}
+			}
+		}
+	}
 
-	// Recipe-ingredient relationships
-	protected.HandleFunc("/recipes/{id:[0-9]+}/ingredients", ingredientHandler.AddRecipeIngredient).Methods("POST")
-	protected.HandleFunc("/recipes/{id:[0-9]+}/ingredients", ingredientHandler.SetRecipeIngredients).Methods("PUT")
-	protected.HandleFunc("/recipes/{recipeId:[0-9]+}/ingredients/{ingredientId:[0-9]+}", ingredientHandler.UpdateRecipeIngredient).Methods("PUT")
-	protected.HandleFunc("/recipes/{recipeId:[0-9]+}/ingredients/{ingredientId:[0-9]+}", ingredientHandler.RemoveRecipeIngredient).Methods("DELETE")
+	router, err := app.New(ctx, db, nil)
+	if err != nil {
+		logging.Logger.Error("Failed to start recipe catalogue", "error", err)
+		os.Exit(1)
+	}
 
-	// Grocery list generation
-	protected.HandleFunc("/grocery-list", groceryHandler.GenerateGroceryList).Methods("POST")
+	regional := make(map[string]http.Handler, len(regionDBs))
+	for region, regionDB := range regionDBs {
+		if regional[region], err = app.NewRegional(ctx, regionDB, nil); err != nil {
+			logging.Logger.Error("Failed to start recipe catalogue", "region", region, "error", err)
+			os.Exit(1)
+		}
+	}
 
 	port := os.Getenv("RECIPE_CATALOGUE_PORT")
 	if port == "" {
 		port = "8002"
 	}
 
-	logging.Logger.Info("Starting recipe catalogue service", "port", port)
-	if err := http.ListenAndServe(":"+port, router); err != nil {
-		logging.Logger.Error("Server failed to start", "error", err)
+	logging.Logger.Info("Starting recipe catalogue service", "port", port, "regions", len(regional))
+	if err := server.Run(ctx, ":"+port, middleware.RouteByRegion(router, regional, app.Catalogue), server.ConfigFromEnv()); err != nil {
+		logging.Logger.Error("Server failed", "error", err)
 	}
 }
-
-func healthCheck(w http.ResponseWriter, r *http.Request) {
-	//logging.WithContext(r.Context()).Debug("Health check requested")
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status": "healthy", "service": "recipe-catalogue"}`))
-}
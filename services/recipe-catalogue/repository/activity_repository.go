@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type ActivityRepository interface {
+	Record(activity models.Activity) error
+	GetFeed(userID int, params models.PaginationParams) ([]models.Activity, int, error)
+}
+
+type activityRepository struct {
+	db *database.DB
+}
+
+func NewActivityRepository(db *database.DB) ActivityRepository {
+	return &activityRepository{db: db}
+}
+
+// Record stores an activity once per type and recipe; repeats are ignored.
+func (r *activityRepository) Record(activity models.Activity) error {
+	_, err := r.db.Exec(`
+		INSERT INTO recipe_catalogue.activities (user_id, type, recipe_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (type, recipe_id) DO NOTHING`,
+		activity.UserID, activity.Type, activity.RecipeID, activity.CreatedAt)
+	return err
+}
+
+// GetFeed returns activity of the users userID follows, newest first.
+// Activity about recipes that are no longer published is left out.
+func (r *activityRepository) GetFeed(userID int, params models.PaginationParams) ([]models.Activity, int, error) {
+	const from = `
+		FROM recipe_catalogue.activities a
+		JOIN recipe_catalogue.user_follows f ON f.followee_id = a.user_id AND f.follower_id = $1
+		JOIN recipe_catalogue.recipes d ON d.id = a.recipe_id
+		WHERE d.status = 'published'`
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*)`+from, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT a.id, a.user_id, a.type, a.recipe_id, d.name, a.created_at`+from+`
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT $2 OFFSET $3`, userID, params.PerPage, params.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	activities := make([]models.Activity, 0)
+	for rows.Next() {
+		var activity models.Activity
+		err := rows.Scan(&activity.ID, &activity.UserID, &activity.Type, &activity.RecipeID, &activity.RecipeName, &activity.CreatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		activities = append(activities, activity)
+	}
+	return activities, total, rows.Err()
+}
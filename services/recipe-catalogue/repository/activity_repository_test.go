@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/events"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ActivityRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo ActivityRepository
+}
+
+func (suite *ActivityRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewActivityRepository(suite.db)
+}
+
+func (suite *ActivityRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *ActivityRepositoryTestSuite) TestRecord() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.activities`)).
+		WithArgs(2, events.RecipePublished, 9, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Act
+	err := suite.repo.Record(models.Activity{UserID: 2, Type: events.RecipePublished, RecipeID: 9, CreatedAt: now})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *ActivityRepositoryTestSuite) TestGetFeed() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*)`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY a.created_at DESC, a.id DESC`)).
+		WithArgs(1, 2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "recipe_id", "name", "created_at"}).
+			AddRow(4, 2, events.RecipePublished, 9, "Shakshuka", now))
+
+	// Act
+	feed, total, err := suite.repo.GetFeed(1, models.PaginationParams{Page: 2, PerPage: 2})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, total)
+	require.Len(suite.T(), feed, 1)
+	assert.Equal(suite.T(), "Shakshuka", feed[0].RecipeName)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestActivityRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ActivityRepositoryTestSuite))
+}
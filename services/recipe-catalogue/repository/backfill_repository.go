@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+
+	"meal-prep/shared/database"
+)
+
+// BackfillRepository reads and repairs catalogue data for the backfill jobs.
+type BackfillRepository interface {
+	// ListUnits returns every distinct unit written on a recipe ingredient
+	ListUnits() ([]string, error)
+	// RenameUnit rewrites the unit from to to on every recipe ingredient,
+	// bumping the version of each recipe it changes, and returns how many
+	// ingredients it rewrote
+	RenameUnit(from, to string) (int, error)
+	// ListRecipeIDs returns the ID of every recipe, lowest first
+	ListRecipeIDs() ([]int, error)
+	// ListSearchIndexes returns the indexes RebuildSearchIndex rebuilds;
+	// dialects without trigram indexes have none
+	ListSearchIndexes() ([]string, error)
+	RebuildSearchIndex(ctx context.Context, index string) error
+}
+
+type backfillRepository struct {
+	db *database.DB
+}
+
+func NewBackfillRepository(db *database.DB) BackfillRepository {
+	return &backfillRepository{db: db}
+}
+
+func (r *backfillRepository) ListUnits() ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT DISTINCT unit FROM recipe_catalogue.recipe_ingredients
+		ORDER BY unit`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	units := make([]string, 0)
+	for rows.Next() {
+		var unit string
+		if err := rows.Scan(&unit); err != nil {
+			return nil, err
+		}
+		units = append(units, unit)
+	}
+	return units, rows.Err()
+}
+
+func (r *backfillRepository) RenameUnit(from, to string) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Clients holding the old version see the recipe changed
+	_, err = tx.Exec(`
+		UPDATE recipe_catalogue.recipes
+		SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (SELECT recipe_id FROM recipe_catalogue.recipe_ingredients WHERE unit = $1)`, from)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE recipe_catalogue.recipe_ingredients SET unit = $2 WHERE unit = $1`, from, to)
+	if err != nil {
+		return 0, err
+	}
+	renamed, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(renamed), tx.Commit()
+}
+
+func (r *backfillRepository) ListRecipeIDs() ([]int, error) {
+	rows, err := r.db.Query(`SELECT id FROM recipe_catalogue.recipes ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ListSearchIndexes finds the trigram indexes ingredient search runs on, so
+// ones added by later migrations are rebuilt too.
+func (r *backfillRepository) ListSearchIndexes() ([]string, error) {
+	if r.db.Dialect() != database.DialectPostgres {
+		return []string{}, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT schemaname || '.' || indexname FROM pg_indexes
+		WHERE schemaname = 'recipe_catalogue' AND indexdef LIKE '%gin_trgm_ops%'
+		ORDER BY indexname`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	indexes := make([]string, 0)
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, rows.Err()
+}
+
+// RebuildSearchIndex rebuilds index from the table, dropping the bloat left
+// by updates. CONCURRENTLY keeps searches and writes running meanwhile.
+func (r *backfillRepository) RebuildSearchIndex(ctx context.Context, index string) error {
+	_, err := r.db.ExecContext(ctx, "REINDEX INDEX CONCURRENTLY "+index)
+	return err
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"meal-prep/shared/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillRepository_RenameUnit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewBackfillRepository(&database.DB{DB: db})
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SET version = version + 1")).
+		WithArgs("grams").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE recipe_catalogue.recipe_ingredients SET unit = $2 WHERE unit = $1")).
+		WithArgs("grams", "g").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	renamed, err := repo.RenameUnit("grams", "g")
+	require.NoError(t, err)
+	assert.Equal(t, 3, renamed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBackfillRepository_ListUnits(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewBackfillRepository(&database.DB{DB: db})
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT unit FROM recipe_catalogue.recipe_ingredients")).
+		WillReturnRows(sqlmock.NewRows([]string{"unit"}).AddRow("g").AddRow("grams"))
+
+	units, err := repo.ListUnits()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"g", "grams"}, units)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBackfillRepository_SearchIndexes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewBackfillRepository(&database.DB{DB: db})
+
+	mock.ExpectQuery(regexp.QuoteMeta("indexdef LIKE '%gin_trgm_ops%'")).
+		WillReturnRows(sqlmock.NewRows([]string{"index"}).AddRow("recipe_catalogue.idx_ingredients_name_trgm"))
+	mock.ExpectExec(regexp.QuoteMeta("REINDEX INDEX CONCURRENTLY recipe_catalogue.idx_ingredients_name_trgm")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	indexes, err := repo.ListSearchIndexes()
+	require.NoError(t, err)
+	require.Equal(t, []string{"recipe_catalogue.idx_ingredients_name_trgm"}, indexes)
+	require.NoError(t, repo.RebuildSearchIndex(context.Background(), indexes[0]))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"meal-prep/shared/models"
+)
+
+const defaultCategoryCacheTTL = 5 * time.Minute
+
+// cachedCategoryRepository keeps the full category list in memory. Categories
+// change rarely but are read on almost every recipe write and category
+// listing, so GetAll, GetByID and Exists are served from a snapshot that is
+// dropped on any write through this repository. The TTL bounds staleness when
+// another replica writes.
+type cachedCategoryRepository struct {
+	next CategoryRepository
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu       sync.RWMutex
+	list     []models.Category
+	byID     map[int]models.Category
+	loadedAt time.Time
+}
+
+// NewCachedCategoryRepository wraps next with an in-process read cache.
+func NewCachedCategoryRepository(next CategoryRepository, ttl time.Duration) CategoryRepository {
+	return &cachedCategoryRepository{next: next, ttl: ttl, now: time.Now}
+}
+
+// CategoryCacheTTLFromEnv reads CATEGORY_CACHE_TTL, accepting either a Go
+// duration ("30s") or a bare number of seconds.
+func CategoryCacheTTLFromEnv() time.Duration {
+	value := os.Getenv("CATEGORY_CACHE_TTL")
+	if value == "" {
+		return defaultCategoryCacheTTL
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	if s, err := strconv.Atoi(value); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	return defaultCategoryCacheTTL
+}
+
+func (r *cachedCategoryRepository) GetAll() ([]models.Category, error) {
+	list, _, err := r.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	categories := make([]models.Category, len(list))
+	copy(categories, list)
+	return categories, nil
+}
+
+func (r *cachedCategoryRepository) GetByID(id int) (*models.Category, error) {
+	_, byID, err := r.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	category, ok := byID[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &category, nil
+}
+
+func (r *cachedCategoryRepository) Exists(id int) (bool, error) {
+	_, byID, err := r.snapshot()
+	if err != nil {
+		return false, err
+	}
+	_, ok := byID[id]
+	return ok, nil
+}
+
+func (r *cachedCategoryRepository) Create(req models.CreateCategoryRequest) (*models.Category, error) {
+	defer r.invalidate()
+	return r.next.Create(req)
+}
+
+func (r *cachedCategoryRepository) Update(id int, req models.UpdateCategoryRequest) (*models.Category, error) {
+	defer r.invalidate()
+	return r.next.Update(id, req)
+}
+
+func (r *cachedCategoryRepository) Delete(id int) error {
+	defer r.invalidate()
+	return r.next.Delete(id)
+}
+
+// snapshot returns the cached categories, reloading them when the cache is
+// empty or older than the TTL.
+func (r *cachedCategoryRepository) snapshot() ([]models.Category, map[int]models.Category, error) {
+	r.mu.RLock()
+	if r.byID != nil && r.now().Sub(r.loadedAt) < r.ttl {
+		list, byID := r.list, r.byID
+		r.mu.RUnlock()
+		return list, byID, nil
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Another reader may have reloaded while we waited for the lock.
+	if r.byID != nil && r.now().Sub(r.loadedAt) < r.ttl {
+		return r.list, r.byID, nil
+	}
+
+	list, err := r.next.GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[int]models.Category, len(list))
+	for _, category := range list {
+		byID[category.ID] = category
+	}
+
+	r.list, r.byID, r.loadedAt = list, byID, r.now()
+	return list, byID, nil
+}
+
+func (r *cachedCategoryRepository) invalidate() {
+	r.mu.Lock()
+	r.list, r.byID = nil, nil
+	r.mu.Unlock()
+}
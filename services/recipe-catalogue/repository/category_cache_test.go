@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meal-prep/shared/models"
+)
+
+// countingCategoryRepository stands in for the database and counts how many
+// times each method reaches it.
+type countingCategoryRepository struct {
+	categories []models.Category
+	getAllErr  error
+	calls      map[string]int
+}
+
+func newCountingCategoryRepository(categories ...models.Category) *countingCategoryRepository {
+	return &countingCategoryRepository{categories: categories, calls: make(map[string]int)}
+}
+
+func (r *countingCategoryRepository) GetAll() ([]models.Category, error) {
+	r.calls["GetAll"]++
+	if r.getAllErr != nil {
+		return nil, r.getAllErr
+	}
+	return append([]models.Category(nil), r.categories...), nil
+}
+
+func (r *countingCategoryRepository) GetByID(id int) (*models.Category, error) {
+	r.calls["GetByID"]++
+	return nil, sql.ErrNoRows
+}
+
+func (r *countingCategoryRepository) Exists(id int) (bool, error) {
+	r.calls["Exists"]++
+	return false, nil
+}
+
+func (r *countingCategoryRepository) Create(req models.CreateCategoryRequest) (*models.Category, error) {
+	r.calls["Create"]++
+	category := models.Category{ID: len(r.categories) + 1, Name: req.Name}
+	r.categories = append(r.categories, category)
+	return &category, nil
+}
+
+func (r *countingCategoryRepository) Update(id int, req models.UpdateCategoryRequest) (*models.Category, error) {
+	r.calls["Update"]++
+	for i := range r.categories {
+		if r.categories[i].ID == id {
+			if req.Name != nil {
+				r.categories[i].Name = *req.Name
+			}
+			category := r.categories[i]
+			return &category, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *countingCategoryRepository) Delete(id int) error {
+	r.calls["Delete"]++
+	for i := range r.categories {
+		if r.categories[i].ID == id {
+			r.categories = append(r.categories[:i], r.categories[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func TestCachedCategoryRepository_ServesReadsFromOneLoad(t *testing.T) {
+	inner := newCountingCategoryRepository(
+		models.Category{ID: 1, Name: "Chicken"},
+		models.Category{ID: 2, Name: "Fish"},
+	)
+	repo := NewCachedCategoryRepository(inner, time.Minute)
+
+	for i := 0; i < 10; i++ {
+		exists, err := repo.Exists(1)
+		require.NoError(t, err)
+		assert.True(t, exists)
+	}
+	missing, err := repo.Exists(99)
+	require.NoError(t, err)
+	assert.False(t, missing)
+
+	category, err := repo.GetByID(2)
+	require.NoError(t, err)
+	assert.Equal(t, "Fish", category.Name)
+
+	_, err = repo.GetByID(99)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	all, err := repo.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	assert.Equal(t, 1, inner.calls["GetAll"])
+	assert.Zero(t, inner.calls["Exists"])
+	assert.Zero(t, inner.calls["GetByID"])
+}
+
+func TestCachedCategoryRepository_WritesInvalidate(t *testing.T) {
+	inner := newCountingCategoryRepository(models.Category{ID: 1, Name: "Chicken"})
+	repo := NewCachedCategoryRepository(inner, time.Minute)
+
+	exists, err := repo.Exists(2)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = repo.Create(models.CreateCategoryRequest{Name: "Fish"})
+	require.NoError(t, err)
+
+	exists, err = repo.Exists(2)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	name := "Poultry"
+	_, err = repo.Update(1, models.UpdateCategoryRequest{Name: &name})
+	require.NoError(t, err)
+
+	category, err := repo.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Poultry", category.Name)
+
+	require.NoError(t, repo.Delete(2))
+	exists, err = repo.Exists(2)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.Equal(t, 4, inner.calls["GetAll"])
+}
+
+func TestCachedCategoryRepository_ReloadsAfterTTL(t *testing.T) {
+	inner := newCountingCategoryRepository(models.Category{ID: 1, Name: "Chicken"})
+	repo := NewCachedCategoryRepository(inner, time.Minute).(*cachedCategoryRepository)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return now }
+
+	_, err := repo.GetAll()
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Second)
+	_, err = repo.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, 1, inner.calls["GetAll"])
+
+	now = now.Add(time.Minute)
+	_, err = repo.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, 2, inner.calls["GetAll"])
+}
+
+func TestCachedCategoryRepository_DoesNotCacheErrors(t *testing.T) {
+	inner := newCountingCategoryRepository(models.Category{ID: 1, Name: "Chicken"})
+	inner.getAllErr = errors.New("connection refused")
+	repo := NewCachedCategoryRepository(inner, time.Minute)
+
+	_, err := repo.Exists(1)
+	assert.Error(t, err)
+
+	inner.getAllErr = nil
+	exists, err := repo.Exists(1)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, 2, inner.calls["GetAll"])
+}
+
+func TestCategoryCacheTTLFromEnv(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":      defaultCategoryCacheTTL,
+		"30s":   30 * time.Second,
+		"90":    90 * time.Second,
+		"bogus": defaultCategoryCacheTTL,
+		"-5s":   defaultCategoryCacheTTL,
+	}
+	for value, want := range tests {
+		t.Setenv("CATEGORY_CACHE_TTL", value)
+		assert.Equal(t, want, CategoryCacheTTLFromEnv(), "CATEGORY_CACHE_TTL=%q", value)
+	}
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type CookingSessionRepository interface {
+	Create(session models.CookingSession) (*models.CookingSession, error)
+	GetByToken(token string) (*models.CookingSession, error)
+	UpdateProgress(token string, currentStep int, completed bool) (*models.CookingSession, error)
+}
+
+type cookingSessionRepository struct {
+	db *database.DB
+}
+
+func NewCookingSessionRepository(db *database.DB) CookingSessionRepository {
+	return &cookingSessionRepository{db: db}
+}
+
+// Create stores a new session. The caller picks the token.
+func (r *cookingSessionRepository) Create(session models.CookingSession) (*models.CookingSession, error) {
+	err := r.db.QueryRow(`
+		INSERT INTO recipe_catalogue.cooking_sessions (token, user_id, recipe_id, scale, current_step, started_at, updated_at)
+		VALUES ($1, $2, $3, $4, 0, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING current_step, started_at, updated_at`,
+		session.Token, session.UserID, session.RecipeID, session.Scale).
+		Scan(&session.CurrentStep, &session.StartedAt, &session.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *cookingSessionRepository) GetByToken(token string) (*models.CookingSession, error) {
+	var session models.CookingSession
+	err := r.db.QueryRow(`
+		SELECT token, user_id, recipe_id, scale, current_step, started_at, updated_at, completed_at
+		FROM recipe_catalogue.cooking_sessions
+		WHERE token = $1`, token).
+		Scan(&session.Token, &session.UserID, &session.RecipeID, &session.Scale, &session.CurrentStep,
+			&session.StartedAt, &session.UpdatedAt, &session.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UpdateProgress moves the session to currentStep. completed stamps
+// completed_at the first time the last step is passed; stepping back keeps
+// the original completion time.
+func (r *cookingSessionRepository) UpdateProgress(token string, currentStep int, completed bool) (*models.CookingSession, error) {
+	var session models.CookingSession
+	err := r.db.QueryRow(`
+		UPDATE recipe_catalogue.cooking_sessions
+		SET current_step = $2,
+		    completed_at = CASE WHEN $3 THEN COALESCE(completed_at, CURRENT_TIMESTAMP) ELSE completed_at END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE token = $1
+		RETURNING token, user_id, recipe_id, scale, current_step, started_at, updated_at, completed_at`,
+		token, currentStep, completed).
+		Scan(&session.Token, &session.UserID, &session.RecipeID, &session.Scale, &session.CurrentStep,
+			&session.StartedAt, &session.UpdatedAt, &session.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CookingSessionRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo CookingSessionRepository
+}
+
+func (suite *CookingSessionRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewCookingSessionRepository(suite.db)
+}
+
+func (suite *CookingSessionRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *CookingSessionRepositoryTestSuite) TestCreate_Success() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.cooking_sessions`)).
+		WithArgs("token-1", 3, 1, 1.5).
+		WillReturnRows(sqlmock.NewRows([]string{"current_step", "started_at", "updated_at"}).AddRow(0, now, now))
+
+	// Act
+	session, err := suite.repo.Create(models.CookingSession{Token: "token-1", UserID: 3, RecipeID: 1, Scale: 1.5})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "token-1", session.Token)
+	assert.Equal(suite.T(), now, session.StartedAt)
+}
+
+func (suite *CookingSessionRepositoryTestSuite) TestGetByToken_NotFound() {
+	// Arrange
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.cooking_sessions`)).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	// Act
+	session, err := suite.repo.GetByToken("missing")
+
+	// Assert
+	assert.Nil(suite.T(), session)
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+}
+
+func (suite *CookingSessionRepositoryTestSuite) TestUpdateProgress_Completed() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`UPDATE recipe_catalogue.cooking_sessions`)).
+		WithArgs("token-1", 4, true).
+		WillReturnRows(sqlmock.NewRows([]string{"token", "user_id", "recipe_id", "scale", "current_step", "started_at", "updated_at", "completed_at"}).
+			AddRow("token-1", 3, 1, 1.0, 4, now, now, now))
+
+	// Act
+	session, err := suite.repo.UpdateProgress("token-1", 4, true)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 4, session.CurrentStep)
+	require.NotNil(suite.T(), session.CompletedAt)
+}
+
+func TestCookingSessionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(CookingSessionRepositoryTestSuite))
+}
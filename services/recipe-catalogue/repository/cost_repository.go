@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"github.com/lib/pq"
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type CostRepository interface {
+	AddPrice(ingredientID int, req models.SetIngredientPriceRequest) (*models.IngredientPrice, error)
+	GetPriceHistory(ingredientID int) ([]models.IngredientPrice, error)
+	GetCurrentPrices(ingredientIDs []int) (map[int]models.IngredientPrice, error)
+	GetRecipeIDsUsingIngredient(ingredientID int) ([]int, error)
+
+	RecordSnapshots(snapshots []models.RecipeCostSnapshot) error
+	GetSnapshots(recipeID int) ([]models.RecipeCostSnapshot, error)
+}
+
+type costRepository struct {
+	db *database.DB
+}
+
+func NewCostRepository(db *database.DB) CostRepository {
+	return &costRepository{db: db}
+}
+
+func (r *costRepository) AddPrice(ingredientID int, req models.SetIngredientPriceRequest) (*models.IngredientPrice, error) {
+	return r.scanPrice(r.db.QueryRow(`
+		INSERT INTO recipe_catalogue.ingredient_prices (ingredient_id, price, quantity, unit, recorded_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+		RETURNING id, ingredient_id, price, quantity, unit, recorded_at`,
+		ingredientID, req.Price, req.Quantity, req.Unit))
+}
+
+// GetPriceHistory returns every price recorded for an ingredient, newest
+// first.
+func (r *costRepository) GetPriceHistory(ingredientID int) ([]models.IngredientPrice, error) {
+	rows, err := r.db.Query(`
+		SELECT id, ingredient_id, price, quantity, unit, recorded_at
+		FROM recipe_catalogue.ingredient_prices
+		WHERE ingredient_id = $1
+		ORDER BY recorded_at DESC, id DESC`, ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := make([]models.IngredientPrice, 0)
+	for rows.Next() {
+		price, err := r.scanPrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, *price)
+	}
+	return prices, rows.Err()
+}
+
+// GetCurrentPrices returns the newest price of each ingredient keyed by
+// ingredient ID. Ingredients that were never priced are absent from the map.
+func (r *costRepository) GetCurrentPrices(ingredientIDs []int) (map[int]models.IngredientPrice, error) {
+	prices := make(map[int]models.IngredientPrice)
+	if len(ingredientIDs) == 0 {
+		return prices, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT DISTINCT ON (ingredient_id) id, ingredient_id, price, quantity, unit, recorded_at
+		FROM recipe_catalogue.ingredient_prices
+		WHERE ingredient_id = ANY($1)
+		ORDER BY ingredient_id, recorded_at DESC, id DESC`, pq.Array(ingredientIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		price, err := r.scanPrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		prices[price.IngredientID] = *price
+	}
+	return prices, rows.Err()
+}
+
+// GetRecipeIDsUsingIngredient returns every recipe with the ingredient,
+// whatever its status, so drafts have a history by the time they publish.
+func (r *costRepository) GetRecipeIDsUsingIngredient(ingredientID int) ([]int, error) {
+	rows, err := r.db.Query(`
+		SELECT DISTINCT recipe_id
+		FROM recipe_catalogue.recipe_ingredients
+		WHERE ingredient_id = $1
+		ORDER BY recipe_id`, ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipeIDs := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		recipeIDs = append(recipeIDs, id)
+	}
+	return recipeIDs, rows.Err()
+}
+
+func (r *costRepository) RecordSnapshots(snapshots []models.RecipeCostSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, snapshot := range snapshots {
+		_, err = tx.Exec(`
+			INSERT INTO recipe_catalogue.recipe_cost_snapshots
+				(recipe_id, cost, priced_ingredients, total_ingredients, recorded_at)
+			VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)`,
+			snapshot.RecipeID, snapshot.Cost, snapshot.PricedIngredients, snapshot.TotalIngredients)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetSnapshots returns the cost history of a recipe, oldest first.
+func (r *costRepository) GetSnapshots(recipeID int) ([]models.RecipeCostSnapshot, error) {
+	rows, err := r.db.Query(`
+		SELECT recipe_id, cost, priced_ingredients, total_ingredients, recorded_at
+		FROM recipe_catalogue.recipe_cost_snapshots
+		WHERE recipe_id = $1
+		ORDER BY recorded_at, id`, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := make([]models.RecipeCostSnapshot, 0)
+	for rows.Next() {
+		var snapshot models.RecipeCostSnapshot
+		err := rows.Scan(&snapshot.RecipeID, &snapshot.Cost, &snapshot.PricedIngredients,
+			&snapshot.TotalIngredients, &snapshot.RecordedAt)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+func (r *costRepository) scanPrice(scanner interface {
+	Scan(...interface{}) error
+}) (*models.IngredientPrice, error) {
+	var price models.IngredientPrice
+	err := scanner.Scan(&price.ID, &price.IngredientID, &price.Price, &price.Quantity, &price.Unit, &price.RecordedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CostRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo CostRepository
+}
+
+func (suite *CostRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewCostRepository(suite.db)
+}
+
+func (suite *CostRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+var priceColumns = []string{"id", "ingredient_id", "price", "quantity", "unit", "recorded_at"}
+
+func (suite *CostRepositoryTestSuite) TestAddPrice() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.ingredient_prices`)).
+		WithArgs(4, 1.29, 400.0, "g").
+		WillReturnRows(sqlmock.NewRows(priceColumns).AddRow(1, 4, 1.29, 400.0, "g", now))
+
+	// Act
+	price, err := suite.repo.AddPrice(4, models.SetIngredientPriceRequest{Price: 1.29, Quantity: 400, Unit: "g"})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1.29, price.Price)
+	assert.Equal(suite.T(), "g", price.Unit)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *CostRepositoryTestSuite) TestGetCurrentPrices_KeyedByIngredient() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT ON (ingredient_id)`)).
+		WillReturnRows(sqlmock.NewRows(priceColumns).
+			AddRow(3, 4, 1.49, 400.0, "g", now).
+			AddRow(2, 5, 2.10, 1.0, "l", now))
+
+	// Act
+	prices, err := suite.repo.GetCurrentPrices([]int{4, 5, 6})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), prices, 2)
+	assert.Equal(suite.T(), 1.49, prices[4].Price)
+	assert.Equal(suite.T(), "l", prices[5].Unit)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *CostRepositoryTestSuite) TestGetCurrentPrices_NoIDsSkipsQuery() {
+	// Act
+	prices, err := suite.repo.GetCurrentPrices(nil)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), prices)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *CostRepositoryTestSuite) TestRecordSnapshots_InTransaction() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipe_cost_snapshots`)).
+		WithArgs(1, 4.5, 2, 3).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipe_cost_snapshots`)).
+		WithArgs(2, 1.2, 1, 1).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	err := suite.repo.RecordSnapshots([]models.RecipeCostSnapshot{
+		{RecipeID: 1, Cost: 4.5, PricedIngredients: 2, TotalIngredients: 3},
+		{RecipeID: 2, Cost: 1.2, PricedIngredients: 1, TotalIngredients: 1},
+	})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *CostRepositoryTestSuite) TestGetSnapshots_OldestFirst() {
+	// Arrange
+	earlier := time.Now().Add(-24 * time.Hour)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.recipe_cost_snapshots`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "cost", "priced_ingredients", "total_ingredients", "recorded_at"}).
+			AddRow(1, 4.5, 3, 3, earlier).
+			AddRow(1, 4.9, 3, 3, time.Now()))
+
+	// Act
+	snapshots, err := suite.repo.GetSnapshots(1)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), snapshots, 2)
+	assert.Equal(suite.T(), 4.5, snapshots[0].Cost)
+	assert.True(suite.T(), snapshots[1].Complete())
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestCostRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(CostRepositoryTestSuite))
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "meal-prep/shared/models"
+
+// CountTimeBuckets turns a histogram of total times into cumulative max-time
+// facets, one per bound in maxMinutes.
+func CountTimeBuckets(recipesByTime map[int]int, maxMinutes []int) []models.TimeFacet {
+	facets := make([]models.TimeFacet, len(maxMinutes))
+	for i, bound := range maxMinutes {
+		facets[i].MaxMinutes = bound
+		for minutes, count := range recipesByTime {
+			if minutes <= bound {
+				facets[i].Count += count
+			}
+		}
+	}
+	return facets
+}
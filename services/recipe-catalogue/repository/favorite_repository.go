@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"database/sql"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/lib/pq"
+)
+
+type FavoriteRepository interface {
+	Add(userID, recipeID int) error
+	Remove(userID, recipeID int) error
+	// GetFavorites returns the user's favorites they can still see: their
+	// own recipes and published ones
+	GetFavorites(userID int, params models.PaginationParams) ([]models.Recipe, int, error)
+	// CountByRecipeIDs leaves out recipes nobody favorited
+	CountByRecipeIDs(recipeIDs []int) (map[int]int, error)
+}
+
+type favoriteRepository struct {
+	db *database.DB
+}
+
+func NewFavoriteRepository(db *database.DB) FavoriteRepository {
+	return &favoriteRepository{db: db}
+}
+
+// Add is idempotent: favoriting a recipe twice keeps the original date.
+func (r *favoriteRepository) Add(userID, recipeID int) error {
+	_, err := r.db.Exec(`
+		INSERT INTO recipe_catalogue.user_favorites (user_id, recipe_id, created_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id, recipe_id) DO NOTHING`, userID, recipeID)
+	return err
+}
+
+// Remove returns sql.ErrNoRows when the recipe was not a favorite.
+func (r *favoriteRepository) Remove(userID, recipeID int) error {
+	result, err := r.db.Exec(`
+		DELETE FROM recipe_catalogue.user_favorites
+		WHERE user_id = $1 AND recipe_id = $2`, userID, recipeID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetFavorites lists the most recently favorited first. Favorites still
+// readable by ID, published or archived, are kept along with the user's own.
+func (r *favoriteRepository) GetFavorites(userID int, params models.PaginationParams) ([]models.Recipe, int, error) {
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM recipe_catalogue.user_favorites f
+		JOIN recipe_catalogue.recipes d ON d.id = f.recipe_id
+		WHERE f.user_id = $1 AND (d.status IN ('published', 'archived') OR d.user_id = $1)`, userID,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
+		       c.id, c.name, c.description
+		FROM recipe_catalogue.user_favorites f
+		JOIN recipe_catalogue.recipes d ON d.id = f.recipe_id
+		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		WHERE f.user_id = $1 AND (d.status IN ('published', 'archived') OR d.user_id = $1)
+		ORDER BY f.created_at DESC, d.id DESC
+		LIMIT $2 OFFSET $3`, userID, params.PerPage, params.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	recipes := make([]models.Recipe, 0)
+	for rows.Next() {
+		recipe := models.Recipe{}
+		category := models.Category{}
+		var categoryDesc sql.NullString
+
+		err := rows.Scan(
+			&recipe.ID, &recipe.UserID, &recipe.Name, &recipe.Description, &recipe.CategoryID,
+			&recipe.CreatedAt, &recipe.UpdatedAt, &recipe.Status, &recipe.Difficulty, &recipe.TotalTimeMinutes,
+			&category.ID, &category.Name, &categoryDesc,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if categoryDesc.Valid {
+			category.Description = &categoryDesc.String
+		}
+		recipe.Category = &category
+
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, total, rows.Err()
+}
+
+func (r *favoriteRepository) CountByRecipeIDs(recipeIDs []int) (map[int]int, error) {
+	counts := make(map[int]int)
+	if len(recipeIDs) == 0 {
+		return counts, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT recipe_id, COUNT(*)
+		FROM recipe_catalogue.user_favorites
+		WHERE recipe_id = ANY($1)
+		GROUP BY recipe_id`, pq.Array(recipeIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipeID, count int
+		if err := rows.Scan(&recipeID, &count); err != nil {
+			return nil, err
+		}
+		counts[recipeID] = count
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"meal-prep/shared/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type FavoriteRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo FavoriteRepository
+}
+
+func (suite *FavoriteRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewFavoriteRepository(suite.db)
+}
+
+func (suite *FavoriteRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *FavoriteRepositoryTestSuite) TestAdd_IgnoresExistingFavorite() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta(`ON CONFLICT (user_id, recipe_id) DO NOTHING`)).
+		WithArgs(1, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := suite.repo.Add(1, 9)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *FavoriteRepositoryTestSuite) TestRemove_NotFavorite() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recipe_catalogue.user_favorites`)).
+		WithArgs(1, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := suite.repo.Remove(1, 9)
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *FavoriteRepositoryTestSuite) TestCountByRecipeIDs() {
+	// Arrange
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT recipe_id, COUNT(*)`)).
+		WithArgs(pq.Array([]int{1, 2})).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id", "count"}).AddRow(2, 5))
+
+	// Act
+	counts, err := suite.repo.CountByRecipeIDs([]int{1, 2})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[int]int{2: 5}, counts)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestFavoriteRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(FavoriteRepositoryTestSuite))
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"database/sql"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type FollowRepository interface {
+	Follow(followerID, followeeID int) error
+	Unfollow(followerID, followeeID int) error
+	GetFollowing(userID int) ([]models.Follow, error)
+	GetFollowers(userID int) ([]models.Follow, error)
+}
+
+type followRepository struct {
+	db *database.DB
+}
+
+func NewFollowRepository(db *database.DB) FollowRepository {
+	return &followRepository{db: db}
+}
+
+// Follow is idempotent: following someone twice keeps the original date.
+func (r *followRepository) Follow(followerID, followeeID int) error {
+	_, err := r.db.Exec(`
+		INSERT INTO recipe_catalogue.user_follows (follower_id, followee_id, created_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (follower_id, followee_id) DO NOTHING`, followerID, followeeID)
+	return err
+}
+
+// Unfollow returns sql.ErrNoRows when the follow did not exist.
+func (r *followRepository) Unfollow(followerID, followeeID int) error {
+	result, err := r.db.Exec(`
+		DELETE FROM recipe_catalogue.user_follows
+		WHERE follower_id = $1 AND followee_id = $2`, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *followRepository) GetFollowing(userID int) ([]models.Follow, error) {
+	return r.list(`
+		SELECT followee_id, created_at
+		FROM recipe_catalogue.user_follows
+		WHERE follower_id = $1
+		ORDER BY created_at DESC, followee_id`, userID)
+}
+
+func (r *followRepository) GetFollowers(userID int) ([]models.Follow, error) {
+	return r.list(`
+		SELECT follower_id, created_at
+		FROM recipe_catalogue.user_follows
+		WHERE followee_id = $1
+		ORDER BY created_at DESC, follower_id`, userID)
+}
+
+func (r *followRepository) list(query string, userID int) ([]models.Follow, error) {
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	follows := make([]models.Follow, 0)
+	for rows.Next() {
+		var follow models.Follow
+		if err := rows.Scan(&follow.UserID, &follow.FollowedAt); err != nil {
+			return nil, err
+		}
+		follows = append(follows, follow)
+	}
+	return follows, rows.Err()
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type FollowRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo FollowRepository
+}
+
+func (suite *FollowRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewFollowRepository(suite.db)
+}
+
+func (suite *FollowRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *FollowRepositoryTestSuite) TestFollow_IgnoresExistingFollow() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta(`ON CONFLICT (follower_id, followee_id) DO NOTHING`)).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := suite.repo.Follow(1, 2)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *FollowRepositoryTestSuite) TestUnfollow_NotFollowing() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recipe_catalogue.user_follows`)).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := suite.repo.Unfollow(1, 2)
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *FollowRepositoryTestSuite) TestGetFollowing() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT followee_id, created_at`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"followee_id", "created_at"}).
+			AddRow(3, now).
+			AddRow(2, now.Add(-time.Hour)))
+
+	// Act
+	following, err := suite.repo.GetFollowing(1)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), following, 2)
+	assert.Equal(suite.T(), 3, following[0].UserID)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestFollowRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(FollowRepositoryTestSuite))
+}
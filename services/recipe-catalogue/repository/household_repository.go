@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"database/sql"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type HouseholdRepository interface {
+	// Create makes ownerID the first member of a new household, whose pantry
+	// starts with what is in the owner's own. Returns
+	// domain.ErrAlreadyInHousehold when the owner belongs to one already.
+	Create(ownerID int, name, inviteCode string) (*models.Household, error)
+	// GetByMember returns the household userID belongs to, with its members
+	// in the order they joined, or sql.ErrNoRows.
+	GetByMember(userID int) (*models.Household, error)
+	GetByInviteCode(inviteCode string) (*models.Household, error)
+	// GetHouseholdIDs lists the households userID belongs to, at most one.
+	GetHouseholdIDs(userID int) ([]int, error)
+	// AddMember returns domain.ErrAlreadyInHousehold when the user belongs
+	// to a household already.
+	AddMember(householdID, userID int) error
+	// RemoveMember returns sql.ErrNoRows when the user is not a member. They
+	// get their own pantry back as they left it.
+	RemoveMember(householdID, userID int) error
+	// Delete removes the household with its pantry.
+	Delete(householdID int) error
+}
+
+type householdRepository struct {
+	db *database.DB
+}
+
+func NewHouseholdRepository(db *database.DB) HouseholdRepository {
+	return &householdRepository{db: db}
+}
+
+func (r *householdRepository) Create(ownerID int, name, inviteCode string) (*models.Household, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	household := models.Household{Name: name, OwnerID: ownerID, InviteCode: inviteCode}
+	err = tx.QueryRow(`
+		INSERT INTO recipe_catalogue.households (name, owner_id, invite_code)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at`, name, ownerID, inviteCode).
+		Scan(&household.ID, &household.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	member := models.HouseholdMember{UserID: ownerID}
+	err = tx.QueryRow(`
+		INSERT INTO recipe_catalogue.household_members (household_id, user_id)
+		VALUES ($1, $2)
+		RETURNING joined_at`, household.ID, ownerID).Scan(&member.JoinedAt)
+	if err != nil {
+		if database.IsUniqueViolation(err, "household_members_one_household") {
+			return nil, domain.ErrAlreadyInHousehold
+		}
+		return nil, err
+	}
+	household.Members = []models.HouseholdMember{member}
+
+	var pantryID int
+	err = tx.QueryRow(`
+		INSERT INTO recipe_catalogue.pantries (household_id) VALUES ($1)
+		RETURNING id`, household.ID).Scan(&pantryID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO recipe_catalogue.pantry_items (pantry_id, ingredient_id, added_by, added_at)
+		SELECT $1, pi.ingredient_id, pi.added_by, pi.added_at
+		FROM recipe_catalogue.pantry_items pi
+		JOIN recipe_catalogue.pantries p ON p.id = pi.pantry_id
+		WHERE p.user_id = $2`, pantryID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &household, tx.Commit()
+}
+
+func (r *householdRepository) GetByMember(userID int) (*models.Household, error) {
+	return r.get(`
+		SELECT h.id, h.name, h.owner_id, h.invite_code, h.created_at
+		FROM recipe_catalogue.households h
+		JOIN recipe_catalogue.household_members m ON m.household_id = h.id
+		WHERE m.user_id = $1`, userID)
+}
+
+func (r *householdRepository) GetByInviteCode(inviteCode string) (*models.Household, error) {
+	return r.get(`
+		SELECT id, name, owner_id, invite_code, created_at
+		FROM recipe_catalogue.households
+		WHERE invite_code = $1`, inviteCode)
+}
+
+func (r *householdRepository) get(query string, arg interface{}) (*models.Household, error) {
+	var household models.Household
+	err := r.db.QueryRow(query, arg).
+		Scan(&household.ID, &household.Name, &household.OwnerID, &household.InviteCode, &household.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT user_id, joined_at
+		FROM recipe_catalogue.household_members
+		WHERE household_id = $1
+		ORDER BY joined_at, user_id`, household.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	household.Members = make([]models.HouseholdMember, 0)
+	for rows.Next() {
+		var member models.HouseholdMember
+		if err := rows.Scan(&member.UserID, &member.JoinedAt); err != nil {
+			return nil, err
+		}
+		household.Members = append(household.Members, member)
+	}
+	return &household, rows.Err()
+}
+
+func (r *householdRepository) GetHouseholdIDs(userID int) ([]int, error) {
+	rows, err := r.db.Query(`
+		SELECT household_id FROM recipe_catalogue.household_members WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	householdIDs := make([]int, 0, 1)
+	for rows.Next() {
+		var householdID int
+		if err := rows.Scan(&householdID); err != nil {
+			return nil, err
+		}
+		householdIDs = append(householdIDs, householdID)
+	}
+	return householdIDs, rows.Err()
+}
+
+func (r *householdRepository) AddMember(householdID, userID int) error {
+	_, err := r.db.Exec(`
+		INSERT INTO recipe_catalogue.household_members (household_id, user_id)
+		VALUES ($1, $2)`, householdID, userID)
+	if database.IsUniqueViolation(err, "household_members_one_household") {
+		return domain.ErrAlreadyInHousehold
+	}
+	return err
+}
+
+func (r *householdRepository) RemoveMember(householdID, userID int) error {
+	result, err := r.db.Exec(`
+		DELETE FROM recipe_catalogue.household_members
+		WHERE household_id = $1 AND user_id = $2`, householdID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *householdRepository) Delete(householdID int) error {
+	_, err := r.db.Exec(`DELETE FROM recipe_catalogue.households WHERE id = $1`, householdID)
+	return err
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HouseholdRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo HouseholdRepository
+}
+
+func (suite *HouseholdRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewHouseholdRepository(suite.db)
+}
+
+func (suite *HouseholdRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *HouseholdRepositoryTestSuite) TestCreate_CopiesOwnersPantry() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.households`)).
+		WithArgs("Flat 4", 7, "code").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, now))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.household_members`)).
+		WithArgs(2, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"joined_at"}).AddRow(now))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.pantries (household_id)`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`WHERE p.user_id = $2`)).
+		WithArgs(5, 7).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	suite.mock.ExpectCommit()
+
+	// Act
+	household, err := suite.repo.Create(7, "Flat 4", "code")
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, household.ID)
+	require.Len(suite.T(), household.Members, 1)
+	assert.Equal(suite.T(), 7, household.Members[0].UserID)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *HouseholdRepositoryTestSuite) TestCreate_AlreadyInHousehold() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.households`)).
+		WithArgs("Flat 4", 7, "code").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, time.Now()))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.household_members`)).
+		WithArgs(2, 7).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "household_members_one_household"})
+	suite.mock.ExpectRollback()
+
+	// Act
+	_, err := suite.repo.Create(7, "Flat 4", "code")
+
+	// Assert
+	assert.Equal(suite.T(), domain.ErrAlreadyInHousehold, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *HouseholdRepositoryTestSuite) TestGetByMember_ListsMembers() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WHERE m.user_id = $1`)).
+		WithArgs(8).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owner_id", "invite_code", "created_at"}).
+			AddRow(2, "Flat 4", 7, "code", now))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY joined_at, user_id`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "joined_at"}).AddRow(7, now).AddRow(8, now))
+
+	// Act
+	household, err := suite.repo.GetByMember(8)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Flat 4", household.Name)
+	require.Len(suite.T(), household.Members, 2)
+	assert.Equal(suite.T(), 8, household.Members[1].UserID)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *HouseholdRepositoryTestSuite) TestRemoveMember_NotAMember() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recipe_catalogue.household_members`)).
+		WithArgs(2, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := suite.repo.RemoveMember(2, 9)
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestHouseholdRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(HouseholdRepositoryTestSuite))
+}
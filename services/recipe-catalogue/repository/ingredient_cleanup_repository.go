@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type IngredientCleanupRepository interface {
+	// ListUnused returns ingredients no recipe has used since unusedSince,
+	// archived ones included, longest unused first
+	ListUnused(unusedSince time.Time, params models.PaginationParams) ([]models.UnusedIngredient, int, error)
+	// Archive and Delete act on the given ingredients, or on every eligible
+	// one when ids is nil, skipping any that are used again. They return the
+	// IDs they acted on.
+	Archive(ids []int, unusedSince time.Time) ([]int, error)
+	Delete(ids []int, unusedSince time.Time) ([]int, error)
+
+	// ListDuplicates returns pairs of unarchived ingredients whose names have
+	// a trigram similarity of at least minSimilarity, most alike first
+	ListDuplicates(minSimilarity float64, limit int) ([]models.DuplicateIngredients, error)
+	// Merge moves the recipes, steps, saved searches, translations and
+	// reference data of the ingredient duplicateID onto targetID and deletes
+	// the duplicate, all in one transaction. It returns
+	// domain.ErrIngredientNotFound when either ingredient is missing.
+	Merge(duplicateID, targetID int) (*models.IngredientMergeResult, error)
+}
+
+type ingredientCleanupRepository struct {
+	db *database.DB
+}
+
+func NewIngredientCleanupRepository(db *database.DB) IngredientCleanupRepository {
+	return &ingredientCleanupRepository{db: db}
+}
+
+// unusedIngredientCondition matches ingredients no recipe uses whose last use,
+// or creation when they were never used, is before $1.
+const unusedIngredientCondition = `NOT EXISTS (
+			SELECT 1 FROM recipe_catalogue.recipe_ingredients ri WHERE ri.ingredient_id = i.id)
+		  AND COALESCE(i.last_used_at, i.created_at) < $1`
+
+func (r *ingredientCleanupRepository) ListUnused(unusedSince time.Time, params models.PaginationParams) ([]models.UnusedIngredient, int, error) {
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM recipe_catalogue.ingredients i
+		WHERE `+unusedIngredientCondition, unusedSince).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT i.id, i.name, i.description, i.category, i.created_at, i.last_used_at, i.archived_at
+		FROM recipe_catalogue.ingredients i
+		WHERE `+unusedIngredientCondition+`
+		ORDER BY COALESCE(i.last_used_at, i.created_at), i.id
+		LIMIT $2 OFFSET $3`, unusedSince, params.PerPage, params.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	ingredients := make([]models.UnusedIngredient, 0)
+	for rows.Next() {
+		var ingredient models.UnusedIngredient
+		var description, category sql.NullString
+		var lastUsedAt, archivedAt sql.NullTime
+		err := rows.Scan(&ingredient.ID, &ingredient.Name, &description, &category, &ingredient.CreatedAt,
+			&lastUsedAt, &archivedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if description.Valid {
+			ingredient.Description = &description.String
+		}
+		if category.Valid {
+			ingredient.Category = &category.String
+		}
+		if lastUsedAt.Valid {
+			ingredient.LastUsedAt = &lastUsedAt.Time
+		}
+		if archivedAt.Valid {
+			ingredient.ArchivedAt = &archivedAt.Time
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+	return ingredients, total, rows.Err()
+}
+
+// Archive leaves already archived ingredients alone so their archive date
+// keeps counting from the first time.
+func (r *ingredientCleanupRepository) Archive(ids []int, unusedSince time.Time) ([]int, error) {
+	query, args := withIngredientIDs(`
+		UPDATE recipe_catalogue.ingredients AS i
+		SET archived_at = CURRENT_TIMESTAMP
+		WHERE i.archived_at IS NULL
+		  AND `+unusedIngredientCondition, ids, unusedSince)
+	return r.affectedIngredientIDs(query, args)
+}
+
+func (r *ingredientCleanupRepository) Delete(ids []int, unusedSince time.Time) ([]int, error) {
+	query, args := withIngredientIDs(`
+		DELETE FROM recipe_catalogue.ingredients AS i
+		WHERE `+unusedIngredientCondition, ids, unusedSince)
+	return r.affectedIngredientIDs(query, args)
+}
+
+// withIngredientIDs narrows a cleanup statement to ids unless ids is nil.
+func withIngredientIDs(query string, ids []int, unusedSince time.Time) (string, []interface{}) {
+	args := []interface{}{unusedSince}
+	if ids != nil {
+		query += `
+		  AND i.id = ANY($2)`
+		args = append(args, pq.Array(ids))
+	}
+	return query + `
+		RETURNING id`, args
+}
+
+func (r *ingredientCleanupRepository) affectedIngredientIDs(query string, args []interface{}) ([]int, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
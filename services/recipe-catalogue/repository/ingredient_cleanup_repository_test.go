@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngredientCleanupRepository_ListUnused(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewIngredientCleanupRepository(&database.DB{DB: db})
+	unusedSince := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM recipe_catalogue.ingredients i")).
+		WithArgs(unusedSince).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY COALESCE(i.last_used_at, i.created_at), i.id")).
+		WithArgs(unusedSince, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "category", "created_at", "last_used_at", "archived_at"}).
+			AddRow(4, "Saffron", nil, "Spices", now, nil, now).
+			AddRow(9, "Sumac", "Tangy", nil, now, now, nil))
+
+	unused, total, err := repo.ListUnused(unusedSince, models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, unused, 2)
+	assert.Nil(t, unused[0].LastUsedAt)
+	assert.NotNil(t, unused[0].ArchivedAt)
+	assert.NotNil(t, unused[1].LastUsedAt)
+	assert.Nil(t, unused[1].Category)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientCleanupRepository_ArchiveAndDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewIngredientCleanupRepository(&database.DB{DB: db})
+	unusedSince := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SET archived_at = CURRENT_TIMESTAMP[\s\S]+AND i\.id = ANY\(\$2\)\s+RETURNING id`).
+		WithArgs(unusedSince, pq.Array([]int{4, 5})).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectQuery(`DELETE FROM recipe_catalogue\.ingredients AS i[\s\S]+< \$1\s+RETURNING id`).
+		WithArgs(unusedSince).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(9))
+
+	archived, err := repo.Archive([]int{4, 5}, unusedSince)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4}, archived)
+
+	deleted, err := repo.Delete(nil, unusedSince)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 9}, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientCleanupRepository_ListDuplicates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewIngredientCleanupRepository(&database.DB{DB: db})
+	now := time.Now()
+
+	mock.ExpectQuery(`JOIN recipe_catalogue\.ingredients b ON a\.id < b\.id AND a\.name % b\.name[\s\S]+similarity\(a\.name, b\.name\) >= \$1[\s\S]+LIMIT \$2`).
+		WithArgs(0.5, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "category", "created_at",
+			"id", "name", "description", "category", "created_at", "score"}).
+			AddRow(4, "Egg", nil, "Dairy", now, 9, "Eggs", "Free range", nil, now, 0.5))
+
+	pairs, err := repo.ListDuplicates(0.5, 50)
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, "Egg", pairs[0].Ingredient.Name)
+	assert.Equal(t, "Dairy", *pairs[0].Ingredient.Category)
+	assert.Equal(t, 9, pairs[0].Duplicate.ID)
+	assert.Nil(t, pairs[0].Duplicate.Category)
+	assert.Equal(t, 0.5, pairs[0].Similarity)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientCleanupRepository_Merge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewIngredientCleanupRepository(&database.DB{DB: db})
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE id IN ($1, $2)")).
+		WithArgs(9, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`SELECT recipe_id FROM recipe_catalogue\.recipe_ingredients\s+WHERE ingredient_id = \$1`).
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"recipe_id"}).AddRow(2).AddRow(7))
+	mock.ExpectExec(`SET version = version \+ 1`).WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`SET quantity = kept\.quantity \+`).WithArgs(9, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM recipe_catalogue\.recipe_ingredients\s+WHERE ingredient_id = \$1 AND recipe_id IN`).
+		WithArgs(9, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE recipe_catalogue.recipe_ingredients SET ingredient_id = $2 WHERE ingredient_id = $1")).
+		WithArgs(9, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SET product_id = \(`).WithArgs(9, 4).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM recipe_catalogue\.ingredient_substitutions`).WithArgs(9, 4).WillReturnResult(sqlmock.NewResult(0, 0))
+	for _, table := range []string{"recipe_step_ingredients", "saved_search_ingredients", "pantry_items", "ingredient_translations",
+		"ingredient_aliases", "ingredient_products", "ingredient_pack_sizes", "ingredient_prices", "ingredient_densities",
+		"ingredient_dietary_flags", "ingredient_diet_overrides", "ingredient_allergens", "ingredient_substitutions",
+		"ingredient_substitutions"} {
+		mock.ExpectExec(`UPDATE recipe_catalogue\.`+table+` AS moved`).WithArgs(9, 4).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectExec(`SET archived_at = NULL`).WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.ingredients WHERE id = $1")).
+		WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id, name, description, category, created_at`).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "category", "created_at"}).
+			AddRow(4, "Egg", nil, "Dairy", now))
+	mock.ExpectCommit()
+
+	result, err := repo.Merge(9, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Ingredient.ID)
+	assert.Equal(t, 9, result.MergedID)
+	assert.Equal(t, []int{2, 7}, result.RecipeIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngredientCleanupRepository_Merge_MissingIngredient(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewIngredientCleanupRepository(&database.DB{DB: db})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE id IN ($1, $2)")).
+		WithArgs(9, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	_, err = repo.Merge(9, 4)
+	assert.Equal(t, domain.ErrIngredientNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoveIngredientRows(t *testing.T) {
+	assert.Equal(t, `
+		UPDATE recipe_catalogue.ingredient_prices AS moved SET ingredient_id = $2
+		WHERE moved.ingredient_id = $1`, moveIngredientRows("ingredient_prices", "ingredient_id", false, nil))
+	assert.Contains(t, moveIngredientRows("ingredient_products", "ingredient_id", true, []string{"brand", "name"}),
+		"WHERE kept.ingredient_id = $2 AND kept.brand = moved.brand AND kept.name = moved.name)")
+	assert.Contains(t, moveIngredientRows("ingredient_densities", "ingredient_id", true, nil),
+		"NOT EXISTS (\n\t\t\tSELECT 1 FROM recipe_catalogue.ingredient_densities kept WHERE kept.ingredient_id = $2)")
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"strings"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/database"
+)
+
+// MatchThreshold is the trigram similarity a typed name needs to be taken for
+// an existing ingredient, e.g. "mozarella" for "Mozzarella". It is well above
+// SimilarityThreshold: a wrong suggestion is only shown, a wrong match is
+// saved into the recipe.
+const MatchThreshold = 0.7
+
+// IngredientName is a name an ingredient goes by, its own or, as an alias,
+// one of its translations or imported aliases.
+type IngredientName struct {
+	IngredientID int
+	Name         string
+	Alias        bool
+}
+
+// MatchIngredientName returns the ingredient among candidates that name
+// means, if any. The same name wins over the same alias, which wins over the
+// plural or singular of either, which wins over the closest spelling at
+// MatchThreshold or above. Case and spacing are ignored, and ties go to the
+// oldest ingredient.
+func MatchIngredientName(name string, candidates []IngredientName) (int, bool) {
+	key := nameKey(name)
+	bestID, bestRank := 0, 0.0
+	for _, candidate := range candidates {
+		rank := matchRank(key, candidate)
+		if rank > bestRank || (rank > 0 && rank == bestRank && candidate.IngredientID < bestID) {
+			bestID, bestRank = candidate.IngredientID, rank
+		}
+	}
+	return bestID, bestRank > 0
+}
+
+// matchRank scores how well candidate matches the name key, 0 being no
+// match. Similarities are at most 1, below every exact tier.
+func matchRank(key string, candidate IngredientName) float64 {
+	other := nameKey(candidate.Name)
+	switch {
+	case other == key && !candidate.Alias:
+		return 4
+	case other == key:
+		return 3
+	case singular(other) == singular(key):
+		return 2
+	}
+	if similarity := TrigramSimilarity(key, other); similarity >= MatchThreshold {
+		return similarity
+	}
+	return 0
+}
+
+func nameKey(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// singular drops an English plural ending from the last word of a name key,
+// so "cherry tomatoes" and "cherry tomato" compare equal.
+func singular(key string) string {
+	switch {
+	case strings.HasSuffix(key, "ies") && len(key) > 4:
+		return strings.TrimSuffix(key, "ies") + "y"
+	case strings.HasSuffix(key, "oes"), strings.HasSuffix(key, "ches"), strings.HasSuffix(key, "shes"),
+		strings.HasSuffix(key, "sses"), strings.HasSuffix(key, "xes"):
+		return strings.TrimSuffix(key, "es")
+	case strings.HasSuffix(key, "ss"), strings.HasSuffix(key, "us"), strings.HasSuffix(key, "is"):
+		return key
+	}
+	return strings.TrimSuffix(key, "s")
+}
+
+// matchOrCreateIngredient resolves a recipe line given by name inside tx: to
+// the ingredient the name matches, or to a new ingredient in category when
+// none does. Archived ingredients match too; using them again unarchives
+// them.
+func matchOrCreateIngredient(tx *database.Tx, name, category string) (int, error) {
+	candidates, err := ingredientNameCandidates(tx, name)
+	if err != nil {
+		return 0, err
+	}
+	if id, ok := MatchIngredientName(name, candidates); ok {
+		return id, nil
+	}
+
+	if category == "" {
+		return 0, domain.ErrIngredientCategoryRequired
+	}
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO recipe_catalogue.ingredients (name, category, created_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		RETURNING id`, name, category).Scan(&id)
+	if database.IsUniqueViolation(err, "ingredients_name_key") {
+		return 0, domain.ErrIngredientExists
+	}
+	return id, err
+}
+
+func (r *ingredientRepository) MatchIngredientNames(names []string) (map[string]int, error) {
+	// Read through a transaction to share ingredientNameCandidates with
+	// matchOrCreateIngredient; nothing is written
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	matches := make(map[string]int)
+	for _, name := range names {
+		candidates, err := ingredientNameCandidates(tx, name)
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := MatchIngredientName(name, candidates); ok {
+			matches[name] = id
+		}
+	}
+	return matches, nil
+}
+
+// ingredientNameCandidates lists the names that could match name. pg_trgm
+// narrows them down on PostgreSQL, where its cut-off is below
+// MatchThreshold; elsewhere every name is a candidate.
+func ingredientNameCandidates(tx *database.Tx, name string) ([]IngredientName, error) {
+	filter := ""
+	if tx.Dialect() == database.DialectPostgres {
+		filter = "WHERE name % $1 OR lower(name) = lower($1)"
+	}
+	query := `
+		SELECT id, name, FALSE FROM recipe_catalogue.ingredients ` + filter + `
+		UNION ALL
+		SELECT ingredient_id, name, TRUE FROM recipe_catalogue.ingredient_translations ` + filter + `
+		UNION ALL
+		SELECT ingredient_id, name, TRUE FROM recipe_catalogue.ingredient_aliases ` + filter
+
+	var args []interface{}
+	if filter != "" {
+		args = append(args, name)
+	}
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []IngredientName
+	for rows.Next() {
+		var candidate IngredientName
+		if err := rows.Scan(&candidate.IngredientID, &candidate.Name, &candidate.Alias); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, rows.Err()
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchIngredientName(t *testing.T) {
+	candidates := []IngredientName{
+		{IngredientID: 1, Name: "Mozzarella"},
+		{IngredientID: 2, Name: "Cherry Tomato"},
+		{IngredientID: 3, Name: "Coriander"},
+		{IngredientID: 4, Name: "Cilantro", Alias: true},
+		{IngredientID: 5, Name: "Cilantro"},
+		{IngredientID: 6, Name: "Onion"},
+	}
+
+	tests := []struct {
+		name   string
+		wantID int
+		wantOK bool
+	}{
+		{"  mozzarella ", 1, true},
+		{"cherry   tomatoes", 2, true},
+		{"cilantro", 5, true}, // an ingredient's own name beats an alias
+		{"corriander", 3, true},
+		{"mozarella", 1, true},
+		{"red onion", 0, false},
+		{"butter", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := MatchIngredientName(tt.name, candidates)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantID, id)
+		})
+	}
+}
+
+func TestMatchIngredientName_AliasAndTies(t *testing.T) {
+	candidates := []IngredientName{
+		{IngredientID: 9, Name: "Zucchini"},
+		{IngredientID: 9, Name: "Courgette", Alias: true},
+		{IngredientID: 8, Name: "Egg"},
+		{IngredientID: 7, Name: "Eggs"},
+	}
+
+	id, ok := MatchIngredientName("courgettes", candidates)
+	assert.True(t, ok)
+	assert.Equal(t, 9, id)
+
+	// Both are the plural or singular of "eggs"; the exact name wins
+	id, _ = MatchIngredientName("eggs", candidates)
+	assert.Equal(t, 7, id)
+	id, _ = MatchIngredientName("EGG", candidates)
+	assert.Equal(t, 8, id)
+}
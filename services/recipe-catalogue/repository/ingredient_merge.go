@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+// ingredientMergeTables are the tables whose rows follow a merged ingredient
+// onto the one it is merged into. Where rows are unique per ingredient, by
+// keys if there are any, a row the kept ingredient already has an equal of
+// stays behind and is deleted with the duplicate.
+var ingredientMergeTables = []struct {
+	table  string
+	column string
+	unique bool
+	keys   []string
+}{
+	{"recipe_step_ingredients", "ingredient_id", true, []string{"step_id"}},
+	{"saved_search_ingredients", "ingredient_id", true, []string{"saved_search_id"}},
+	{"pantry_items", "ingredient_id", true, []string{"pantry_id"}},
+	{"ingredient_translations", "ingredient_id", true, []string{"locale"}},
+	{"ingredient_aliases", "ingredient_id", true, []string{"name"}},
+	{"ingredient_products", "ingredient_id", true, []string{"brand", "name"}},
+	{"ingredient_pack_sizes", "ingredient_id", true, []string{"quantity", "unit"}},
+	{"ingredient_prices", "ingredient_id", false, nil},
+	{"ingredient_densities", "ingredient_id", true, nil},
+	{"ingredient_dietary_flags", "ingredient_id", true, []string{"flag"}},
+	{"ingredient_diet_overrides", "ingredient_id", true, nil},
+	{"ingredient_allergens", "ingredient_id", true, []string{"allergen_id"}},
+	{"ingredient_substitutions", "ingredient_id", true, []string{"substitute_id"}},
+	{"ingredient_substitutions", "substitute_id", true, []string{"ingredient_id"}},
+}
+
+// ListDuplicates pairs ingredients up through the trigram indexes on
+// PostgreSQL; other backends rank every pair in Go.
+func (r *ingredientCleanupRepository) ListDuplicates(minSimilarity float64, limit int) ([]models.DuplicateIngredients, error) {
+	if r.db.Dialect() != database.DialectPostgres {
+		ingredients, err := r.listActiveIngredients()
+		if err != nil {
+			return nil, err
+		}
+		return RankDuplicates(ingredients, minSimilarity, limit), nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT a.id, a.name, a.description, a.category, a.created_at,
+		       b.id, b.name, b.description, b.category, b.created_at,
+		       similarity(a.name, b.name) AS score
+		FROM recipe_catalogue.ingredients a
+		JOIN recipe_catalogue.ingredients b ON a.id < b.id AND a.name % b.name
+		WHERE a.archived_at IS NULL AND b.archived_at IS NULL
+		  AND similarity(a.name, b.name) >= $1
+		ORDER BY score DESC, a.id, b.id
+		LIMIT $2`, minSimilarity, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pairs := make([]models.DuplicateIngredients, 0)
+	for rows.Next() {
+		var pair models.DuplicateIngredients
+		var description, category, duplicateDescription, duplicateCategory sql.NullString
+		err := rows.Scan(&pair.Ingredient.ID, &pair.Ingredient.Name, &description, &category, &pair.Ingredient.CreatedAt,
+			&pair.Duplicate.ID, &pair.Duplicate.Name, &duplicateDescription, &duplicateCategory, &pair.Duplicate.CreatedAt,
+			&pair.Similarity)
+		if err != nil {
+			return nil, err
+		}
+
+		pair.Ingredient.Description, pair.Ingredient.Category = optionalString(description), optionalString(category)
+		pair.Duplicate.Description, pair.Duplicate.Category = optionalString(duplicateDescription), optionalString(duplicateCategory)
+		pairs = append(pairs, pair)
+	}
+	return pairs, rows.Err()
+}
+
+func (r *ingredientCleanupRepository) listActiveIngredients() ([]models.Ingredient, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, description, category, created_at
+		FROM recipe_catalogue.ingredients
+		WHERE archived_at IS NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ingredients []models.Ingredient
+	for rows.Next() {
+		var ingredient models.Ingredient
+		var description, category sql.NullString
+		if err := rows.Scan(&ingredient.ID, &ingredient.Name, &description, &category, &ingredient.CreatedAt); err != nil {
+			return nil, err
+		}
+		ingredient.Description, ingredient.Category = optionalString(description), optionalString(category)
+		ingredients = append(ingredients, ingredient)
+	}
+	return ingredients, rows.Err()
+}
+
+// Merge keeps the target's own line in recipes that use both ingredients,
+// adding the duplicate's amount to it when both are in the same unit.
+func (r *ingredientCleanupRepository) Merge(duplicateID, targetID int) (*models.IngredientMergeResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var found int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE id IN ($1, $2)`, duplicateID, targetID).Scan(&found)
+	if err != nil {
+		return nil, err
+	}
+	if found != 2 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	recipeIDs, err := mergedRecipeIDs(tx, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clients holding the old version see the recipe changed
+	_, err = tx.Exec(`
+		UPDATE recipe_catalogue.recipes
+		SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (SELECT recipe_id FROM recipe_catalogue.recipe_ingredients WHERE ingredient_id = $1)`, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every statement moves rows from the duplicate ($1) to the target ($2)
+	statements := []string{
+		`UPDATE recipe_catalogue.recipe_ingredients AS kept
+		SET quantity = kept.quantity + (
+			SELECT dup.quantity FROM recipe_catalogue.recipe_ingredients dup
+			WHERE dup.recipe_id = kept.recipe_id AND dup.ingredient_id = $1 AND dup.unit = kept.unit)
+		WHERE kept.ingredient_id = $2 AND EXISTS (
+			SELECT 1 FROM recipe_catalogue.recipe_ingredients dup
+			WHERE dup.recipe_id = kept.recipe_id AND dup.ingredient_id = $1 AND dup.unit = kept.unit)`,
+		`DELETE FROM recipe_catalogue.recipe_ingredients
+		WHERE ingredient_id = $1 AND recipe_id IN (
+			SELECT recipe_id FROM recipe_catalogue.recipe_ingredients WHERE ingredient_id = $2)`,
+		`UPDATE recipe_catalogue.recipe_ingredients SET ingredient_id = $2 WHERE ingredient_id = $1`,
+		// Recipes naming a product the target has an equal of name that one
+		// instead of falling back to the generic ingredient
+		`UPDATE recipe_catalogue.recipe_ingredients AS ri
+		SET product_id = (
+			SELECT kept.id FROM recipe_catalogue.ingredient_products kept
+			JOIN recipe_catalogue.ingredient_products dup ON dup.brand = kept.brand AND dup.name = kept.name
+			WHERE dup.id = ri.product_id AND kept.ingredient_id = $2)
+		WHERE ri.product_id IN (
+			SELECT dup.id FROM recipe_catalogue.ingredient_products dup
+			JOIN recipe_catalogue.ingredient_products kept ON kept.brand = dup.brand AND kept.name = dup.name
+			WHERE dup.ingredient_id = $1 AND kept.ingredient_id = $2)`,
+		// The two can no longer stand in for each other
+		`DELETE FROM recipe_catalogue.ingredient_substitutions
+		WHERE (ingredient_id = $1 AND substitute_id = $2) OR (ingredient_id = $2 AND substitute_id = $1)`,
+	}
+	for _, t := range ingredientMergeTables {
+		statements = append(statements, moveIngredientRows(t.table, t.column, t.unique, t.keys))
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, duplicateID, targetID); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(recipeIDs) > 0 {
+		// Recipes use the target now, so it is no longer unused
+		_, err = tx.Exec(`
+			UPDATE recipe_catalogue.ingredients SET archived_at = NULL
+			WHERE id = $1 AND archived_at IS NOT NULL`, targetID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM recipe_catalogue.ingredients WHERE id = $1`, duplicateID); err != nil {
+		return nil, err
+	}
+
+	result := &models.IngredientMergeResult{MergedID: duplicateID, RecipeIDs: recipeIDs}
+	var description, category sql.NullString
+	err = tx.QueryRow(`
+		SELECT id, name, description, category, created_at
+		FROM recipe_catalogue.ingredients
+		WHERE id = $1`, targetID).Scan(&result.Ingredient.ID, &result.Ingredient.Name, &description, &category,
+		&result.Ingredient.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	result.Ingredient.Description, result.Ingredient.Category = optionalString(description), optionalString(category)
+	return result, tx.Commit()
+}
+
+func mergedRecipeIDs(tx *database.Tx, duplicateID int) ([]int, error) {
+	rows, err := tx.Query(`
+		SELECT recipe_id FROM recipe_catalogue.recipe_ingredients
+		WHERE ingredient_id = $1
+		ORDER BY recipe_id`, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// moveIngredientRows builds the statement moving a table's rows from
+// ingredient $1 to ingredient $2.
+func moveIngredientRows(table, column string, unique bool, keys []string) string {
+	query := `
+		UPDATE recipe_catalogue.` + table + ` AS moved SET ` + column + ` = $2
+		WHERE moved.` + column + ` = $1`
+	if !unique {
+		return query
+	}
+
+	conditions := []string{"kept." + column + " = $2"}
+	for _, key := range keys {
+		conditions = append(conditions, "kept."+key+" = moved."+key)
+	}
+	return query + ` AND NOT EXISTS (
+			SELECT 1 FROM recipe_catalogue.` + table + ` kept WHERE ` + strings.Join(conditions, " AND ") + `)`
+}
+
+func optionalString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
@@ -13,10 +13,15 @@ type IngredientRepository interface {
 	GetIngredientByID(id int) (*models.Ingredient, error)
 	GetIngredientsByCategory(category string, params models.PaginationParams) ([]models.Ingredient, int, error)
 	SearchIngredients(query string, params models.PaginationParams) ([]models.Ingredient, int, error)
+	SuggestIngredientNames(query string, limit int) ([]string, error)
 	CreateIngredient(req models.CreateIngredientRequest) (*models.Ingredient, error)
 	UpdateIngredient(id int, req models.UpdateIngredientRequest) (*models.Ingredient, error)
 	DeleteIngredient(id int) error
 	IngredientExists(id int) (bool, error)
+	FindMissingIngredientIDs(ids []int) ([]int, error)
+	// MatchIngredientNames returns the ingredient each of names means, as
+	// MatchIngredientName decides, leaving out names that match none
+	MatchIngredientNames(names []string) (map[string]int, error)
 
 	GetRecipeIngredients(recipeID int) ([]models.RecipeIngredient, error)
 	AddRecipeIngredient(recipeID int, req models.AddRecipeIngredientRequest) (*models.RecipeIngredient, error)
@@ -26,6 +31,45 @@ type IngredientRepository interface {
 
 	GetIngredientsForRecipes(recipeIDs []int) (map[int][]models.RecipeIngredient, error)
 	GetRecipesUsingIngredient(ingredientID int, params models.PaginationParams) ([]models.Recipe, int, error)
+	GetPopularIngredients(limit int) ([]models.IngredientUsage, error)
+
+	GetDensity(ingredientID int) (*models.IngredientDensity, error)
+	ListDensities(params models.PaginationParams) ([]models.IngredientDensity, int, error)
+	UpsertDensity(ingredientID int, req models.SetIngredientDensityRequest) (*models.IngredientDensity, error)
+	DeleteDensity(ingredientID int) error
+	GetDensities(ingredientIDs []int) (map[int]float64, error)
+
+	GetPackSizes(ingredientID int) ([]models.PackSize, error)
+	SetPackSizes(ingredientID int, packSizes []models.PackSizeRequest) error
+	GetPackSizesForIngredients(ingredientIDs []int) (map[int][]models.PackSize, error)
+
+	GetProducts(ingredientID int) ([]models.IngredientProduct, error)
+	CreateProduct(ingredientID int, req models.IngredientProductRequest) (*models.IngredientProduct, error)
+	// UpdateProduct and DeleteProduct return sql.ErrNoRows when the
+	// ingredient has no product with that ID.
+	UpdateProduct(ingredientID, productID int, req models.IngredientProductRequest) (*models.IngredientProduct, error)
+	DeleteProduct(ingredientID, productID int) error
+	GetProductsByID(productIDs []int) (map[int]models.IngredientProduct, error)
+
+	GetSubstitutions(ingredientID int) ([]models.IngredientSubstitution, error)
+	SetSubstitutions(ingredientID int, substitutions []models.SubstitutionRequest) error
+	GetSubstitutionsForIngredients(ingredientIDs []int) (map[int][]models.IngredientSubstitution, error)
+
+	GetTranslations(ingredientID int) ([]models.IngredientTranslation, error)
+	SetTranslations(ingredientID int, translations []models.IngredientTranslation) error
+	GetLocalizedNames(ingredientIDs []int, locales []string) (map[int]string, error)
+
+	GetDietaryFacts(ingredientIDs []int) (map[int]models.DietaryFacts, error)
+	SetDietaryFacts(ingredientID int, facts models.DietaryFacts) error
+
+	GetAllergens() ([]models.Allergen, error)
+	GetIngredientAllergens(ingredientID int) ([]models.Allergen, error)
+	SetIngredientAllergens(ingredientID int, allergenIDs []int) error
+	AddIngredientAllergen(ingredientID, allergenID int) error
+	// RemoveIngredientAllergen returns sql.ErrNoRows when the ingredient
+	// isn't tagged with the allergen.
+	RemoveIngredientAllergen(ingredientID, allergenID int) error
+	GetRecipeAllergens(recipeID int) ([]models.RecipeAllergen, error)
 }
 
 type ingredientRepository struct {
@@ -38,7 +82,7 @@ func NewIngredientRepository(db *database.DB) IngredientRepository {
 
 func (r *ingredientRepository) GetAllIngredients(params models.PaginationParams) ([]models.Ingredient, int, error) {
 	var total int
-	err := r.db.QueryRow(`SELECT COUNT(*) FROM recipe_catalogue.ingredients`).Scan(&total)
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE archived_at IS NULL`).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -46,6 +90,7 @@ func (r *ingredientRepository) GetAllIngredients(params models.PaginationParams)
 	query := `
 		SELECT id, name, description, category, created_at
 		FROM recipe_catalogue.ingredients
+		WHERE archived_at IS NULL
 		ORDER BY category, name
 		LIMIT $1 OFFSET $2`
 
@@ -80,7 +125,7 @@ func (r *ingredientRepository) GetIngredientByID(id int) (*models.Ingredient, er
 func (r *ingredientRepository) GetIngredientsByCategory(category string, params models.PaginationParams) ([]models.Ingredient, int, error) {
 	var total int
 	err := r.db.QueryRow(
-		`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE category = $1`, category,
+		`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE category = $1 AND archived_at IS NULL`, category,
 	).Scan(&total)
 	if err != nil {
 		return nil, 0, err
@@ -89,7 +134,7 @@ func (r *ingredientRepository) GetIngredientsByCategory(category string, params
 	query := `
 		SELECT id, name, description, category, created_at
 		FROM recipe_catalogue.ingredients
-		WHERE category = $1
+		WHERE category = $1 AND archived_at IS NULL
 		ORDER BY name
 		LIMIT $2 OFFSET $3`
 
@@ -111,12 +156,18 @@ func (r *ingredientRepository) GetIngredientsByCategory(category string, params
 	return ingredients, total, nil
 }
 
+// ingredientSearchCondition matches $1 against the name, the description and
+// the name in any language, so "помидор" finds Tomato. Archived ingredients
+// never match.
+const ingredientSearchCondition = `archived_at IS NULL AND (name ILIKE $1 OR description ILIKE $1 OR id IN (
+	SELECT ingredient_id FROM recipe_catalogue.ingredient_translations WHERE name ILIKE $1))`
+
 func (r *ingredientRepository) SearchIngredients(query string, params models.PaginationParams) ([]models.Ingredient, int, error) {
 	pattern := "%" + query + "%"
 
 	var total int
 	err := r.db.QueryRow(
-		`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE name ILIKE $1 OR description ILIKE $1`, pattern,
+		`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE `+ingredientSearchCondition, pattern,
 	).Scan(&total)
 	if err != nil {
 		return nil, 0, err
@@ -125,7 +176,7 @@ func (r *ingredientRepository) SearchIngredients(query string, params models.Pag
 	searchQuery := `
 		SELECT id, name, description, category, created_at
 		FROM recipe_catalogue.ingredients
-		WHERE name ILIKE $1 OR description ILIKE $1
+		WHERE ` + ingredientSearchCondition + `
 		ORDER BY name
 		LIMIT $2 OFFSET $3`
 
@@ -147,6 +198,67 @@ func (r *ingredientRepository) SearchIngredients(query string, params models.Pag
 	return ingredients, total, nil
 }
 
+// SuggestIngredientNames returns ingredient names, in any language, that look
+// like query, closest first, leaving out archived ingredients. The trigram
+// indexes serve "name % $1" on PostgreSQL; other backends rank the names in Go.
+func (r *ingredientRepository) SuggestIngredientNames(query string, limit int) ([]string, error) {
+	if r.db.Dialect() != database.DialectPostgres {
+		return r.suggestIngredientNamesInGo(query, limit)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT name FROM (
+			SELECT name, similarity(name, $1) AS score
+			FROM recipe_catalogue.ingredients WHERE name % $1 AND archived_at IS NULL
+			UNION ALL
+			SELECT name, similarity(name, $1)
+			FROM recipe_catalogue.ingredient_translations WHERE name % $1 AND ingredient_id IN (
+				SELECT id FROM recipe_catalogue.ingredients WHERE archived_at IS NULL)
+		) candidates
+		GROUP BY name
+		ORDER BY MAX(score) DESC, name
+		LIMIT $2`, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func (r *ingredientRepository) suggestIngredientNamesInGo(query string, limit int) ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT name FROM recipe_catalogue.ingredients WHERE archived_at IS NULL
+		UNION
+		SELECT name FROM recipe_catalogue.ingredient_translations WHERE ingredient_id IN (
+			SELECT id FROM recipe_catalogue.ingredients WHERE archived_at IS NULL)`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return RankSuggestions(query, names, limit), nil
+}
+
 func (r *ingredientRepository) CreateIngredient(req models.CreateIngredientRequest) (*models.Ingredient, error) {
 	query := `
 		INSERT INTO recipe_catalogue.ingredients (name, description, category, created_at)
@@ -157,10 +269,8 @@ func (r *ingredientRepository) CreateIngredient(req models.CreateIngredientReque
 
 	ingredient, err := r.scanIngredient(row)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-			if pqErr.Constraint == "ingredients_name_key" {
-				return nil, domain.ErrIngredientExists
-			}
+		if database.IsUniqueViolation(err, "ingredients_name_key") {
+			return nil, domain.ErrIngredientExists
 		}
 		return nil, err
 	}
@@ -208,9 +318,46 @@ func (r *ingredientRepository) IngredientExists(id int) (bool, error) {
 	return exists, err
 }
 
+// FindMissingIngredientIDs checks a whole set of IDs in one query and returns
+// the ones that do not exist, in input order and without duplicates.
+func (r *ingredientRepository) FindMissingIngredientIDs(ids []int) ([]int, error) {
+	if len(ids) == 0 {
+		return []int{}, nil
+	}
+
+	rows, err := r.db.Query(
+		"SELECT id FROM recipe_catalogue.ingredients WHERE id = ANY($1)",
+		pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := make(map[int]bool, len(ids))
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		found[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	missing := []int{}
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+			found[id] = true
+		}
+	}
+	return missing, nil
+}
+
 func (r *ingredientRepository) GetRecipeIngredients(recipeID int) ([]models.RecipeIngredient, error) {
 	query := `
-		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at,
+		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at, ri.product_id,
 		       i.id, i.name, i.description, i.category, i.created_at
 		FROM recipe_catalogue.recipe_ingredients ri
 		JOIN recipe_catalogue.ingredients i ON ri.ingredient_id = i.id
@@ -232,24 +379,25 @@ func (r *ingredientRepository) GetRecipeIngredients(recipeID int) ([]models.Reci
 		recipeIngredients = append(recipeIngredients, *recipeIngredient)
 	}
 
+	if err := r.attachProducts(recipeIngredients); err != nil {
+		return nil, err
+	}
 	return recipeIngredients, nil
 }
 
 func (r *ingredientRepository) AddRecipeIngredient(recipeID int, req models.AddRecipeIngredientRequest) (*models.RecipeIngredient, error) {
 	query := `
-		INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, created_at)
-		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
-		RETURNING id, recipe_id, ingredient_id, quantity, unit, notes, created_at`
+		INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)
+		RETURNING id, recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at`
 
 	var ri models.RecipeIngredient
-	err := r.db.QueryRow(query, recipeID, req.IngredientID, req.Quantity, req.Unit, req.Notes).Scan(
-		&ri.ID, &ri.RecipeID, &ri.IngredientID, &ri.Quantity, &ri.Unit, &ri.Notes, &ri.CreatedAt)
+	err := r.db.QueryRow(query, recipeID, req.IngredientID, req.Quantity, req.Unit, req.Notes, req.ProductID).Scan(
+		&ri.ID, &ri.RecipeID, &ri.IngredientID, &ri.Quantity, &ri.Unit, &ri.Notes, &ri.ProductID, &ri.CreatedAt)
 
 	if err != nil {
-		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
-			if pgErr.Constraint == "recipe_ingredients_unique_per_recipe" {
-				return nil, domain.ErrRecipeIngredientAlreadyExists
-			}
+		if database.IsUniqueViolation(err, "recipe_ingredients_unique_per_recipe") {
+			return nil, domain.ErrRecipeIngredientAlreadyExists
 		}
 		return nil, err
 	}
@@ -261,19 +409,22 @@ func (r *ingredientRepository) AddRecipeIngredient(recipeID int, req models.AddR
 	}
 
 	ri.Ingredient = *ingredient
+	if err := r.attachProducts([]models.RecipeIngredient{ri}); err != nil {
+		return nil, err
+	}
 	return &ri, nil
 }
 
 func (r *ingredientRepository) UpdateRecipeIngredient(recipeID, ingredientID int, req models.AddRecipeIngredientRequest) (*models.RecipeIngredient, error) {
 	query := `
 		UPDATE recipe_catalogue.recipe_ingredients
-		SET quantity = $3, unit = $4, notes = $5
+		SET quantity = $3, unit = $4, notes = $5, product_id = $6
 		WHERE recipe_id = $1 AND ingredient_id = $2
-		RETURNING id, recipe_id, ingredient_id, quantity, unit, notes, created_at`
+		RETURNING id, recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at`
 
 	var ri models.RecipeIngredient
-	err := r.db.QueryRow(query, recipeID, ingredientID, req.Quantity, req.Unit, req.Notes).Scan(
-		&ri.ID, &ri.RecipeID, &ri.IngredientID, &ri.Quantity, &ri.Unit, &ri.Notes, &ri.CreatedAt)
+	err := r.db.QueryRow(query, recipeID, ingredientID, req.Quantity, req.Unit, req.Notes, req.ProductID).Scan(
+		&ri.ID, &ri.RecipeID, &ri.IngredientID, &ri.Quantity, &ri.Unit, &ri.Notes, &ri.ProductID, &ri.CreatedAt)
 
 	if err != nil {
 		return nil, err
@@ -286,6 +437,9 @@ func (r *ingredientRepository) UpdateRecipeIngredient(recipeID, ingredientID int
 	}
 
 	ri.Ingredient = *ingredient
+	if err := r.attachProducts([]models.RecipeIngredient{ri}); err != nil {
+		return nil, err
+	}
 	return &ri, nil
 }
 
@@ -323,9 +477,9 @@ func (r *ingredientRepository) SetRecipeIngredients(recipeID int, ingredients []
 	// Add all new ingredients
 	for _, ingredient := range ingredients {
 		_, err = tx.Exec(`
-			INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, created_at)
-			VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)`,
-			recipeID, ingredient.IngredientID, ingredient.Quantity, ingredient.Unit, ingredient.Notes)
+			INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)`,
+			recipeID, ingredient.IngredientID, ingredient.Quantity, ingredient.Unit, ingredient.Notes, ingredient.ProductID)
 		if err != nil {
 			return err
 		}
@@ -340,7 +494,7 @@ func (r *ingredientRepository) GetIngredientsForRecipes(recipeIDs []int) (map[in
 	}
 
 	query := `
-        SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at,
+        SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at, ri.product_id,
                i.id, i.name, i.description, i.category, i.created_at
         FROM recipe_catalogue.recipe_ingredients ri
         JOIN recipe_catalogue.ingredients i ON ri.ingredient_id = i.id
@@ -355,14 +509,21 @@ func (r *ingredientRepository) GetIngredientsForRecipes(recipeIDs []int) (map[in
 	defer rows.Close()
 
 	// ... rest of the method stays the same
-	result := make(map[int][]models.RecipeIngredient)
+	var recipeIngredients []models.RecipeIngredient
 	for rows.Next() {
 		recipeIngredient, err := r.scanRecipeIngredient(rows)
 		if err != nil {
 			return nil, err
 		}
+		recipeIngredients = append(recipeIngredients, *recipeIngredient)
+	}
+	if err := r.attachProducts(recipeIngredients); err != nil {
+		return nil, err
+	}
 
-		result[recipeIngredient.RecipeID] = append(result[recipeIngredient.RecipeID], *recipeIngredient)
+	result := make(map[int][]models.RecipeIngredient)
+	for _, recipeIngredient := range recipeIngredients {
+		result[recipeIngredient.RecipeID] = append(result[recipeIngredient.RecipeID], recipeIngredient)
 	}
 
 	return result, nil
@@ -374,19 +535,19 @@ func (r *ingredientRepository) GetRecipesUsingIngredient(ingredientID int, param
 		SELECT COUNT(DISTINCT r.id)
 		FROM recipe_catalogue.recipes r
 		JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
-		WHERE ri.ingredient_id = $1`, ingredientID,
+		WHERE ri.ingredient_id = $1 AND r.status = 'published'`, ingredientID,
 	).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	query := `
-		SELECT DISTINCT r.id, r.user_id, r.name, r.description, r.category_id, r.created_at, r.updated_at,
+		SELECT DISTINCT r.id, r.user_id, r.name, r.description, r.category_id, r.created_at, r.updated_at, r.status, r.difficulty, r.total_time_minutes,
 		                c.id, c.name, c.description
 		FROM recipe_catalogue.recipes r
 		LEFT JOIN recipe_catalogue.categories c ON r.category_id = c.id
 		JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
-		WHERE ri.ingredient_id = $1
+		WHERE ri.ingredient_id = $1 AND r.status = 'published'
 		ORDER BY r.name
 		LIMIT $2 OFFSET $3`
 
@@ -404,7 +565,7 @@ func (r *ingredientRepository) GetRecipesUsingIngredient(ingredientID int, param
 
 		err := rows.Scan(
 			&recipe.ID, &recipe.UserID, &recipe.Name, &recipe.Description, &recipe.CategoryID,
-			&recipe.CreatedAt, &recipe.UpdatedAt,
+			&recipe.CreatedAt, &recipe.UpdatedAt, &recipe.Status, &recipe.Difficulty, &recipe.TotalTimeMinutes,
 			&category.ID, &category.Name, &categoryDesc,
 		)
 		if err != nil {
@@ -422,7 +583,773 @@ func (r *ingredientRepository) GetRecipesUsingIngredient(ingredientID int, param
 	return recipes, total, nil
 }
 
+// GetPopularIngredients reads usage counts from the ingredient_usage
+// materialized view, so results lag writes until the next refresh.
+func (r *ingredientRepository) GetPopularIngredients(limit int) ([]models.IngredientUsage, error) {
+	query := `
+		SELECT i.id, i.name, i.description, i.category, i.created_at, u.recipe_count
+		FROM recipe_catalogue.ingredient_usage u
+		JOIN recipe_catalogue.ingredients i ON i.id = u.ingredient_id
+		WHERE u.recipe_count > 0
+		ORDER BY u.recipe_count DESC, i.name
+		LIMIT $1`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := make([]models.IngredientUsage, 0)
+	for rows.Next() {
+		var u models.IngredientUsage
+		var description, category sql.NullString
+
+		err := rows.Scan(&u.ID, &u.Name, &description, &category, &u.CreatedAt, &u.RecipeCount)
+		if err != nil {
+			return nil, err
+		}
+		if description.Valid {
+			u.Description = &description.String
+		}
+		if category.Valid {
+			u.Category = &category.String
+		}
+		usage = append(usage, u)
+	}
+
+	return usage, rows.Err()
+}
+
+// GetDensity returns sql.ErrNoRows when the ingredient has no density.
+func (r *ingredientRepository) GetDensity(ingredientID int) (*models.IngredientDensity, error) {
+	return r.scanDensity(r.db.QueryRow(`
+		SELECT ingredient_id, grams_per_ml, source, updated_at
+		FROM recipe_catalogue.ingredient_densities
+		WHERE ingredient_id = $1`, ingredientID))
+}
+
+func (r *ingredientRepository) ListDensities(params models.PaginationParams) ([]models.IngredientDensity, int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM recipe_catalogue.ingredient_densities`).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT ingredient_id, grams_per_ml, source, updated_at
+		FROM recipe_catalogue.ingredient_densities
+		ORDER BY ingredient_id
+		LIMIT $1 OFFSET $2`, params.PerPage, params.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	densities := make([]models.IngredientDensity, 0)
+	for rows.Next() {
+		density, err := r.scanDensity(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		densities = append(densities, *density)
+	}
+
+	return densities, total, rows.Err()
+}
+
+func (r *ingredientRepository) UpsertDensity(ingredientID int, req models.SetIngredientDensityRequest) (*models.IngredientDensity, error) {
+	return r.scanDensity(r.db.QueryRow(`
+		INSERT INTO recipe_catalogue.ingredient_densities (ingredient_id, grams_per_ml, source, updated_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+		ON CONFLICT (ingredient_id) DO UPDATE
+		SET grams_per_ml = EXCLUDED.grams_per_ml,
+		    source = EXCLUDED.source,
+		    updated_at = CURRENT_TIMESTAMP
+		RETURNING ingredient_id, grams_per_ml, source, updated_at`,
+		ingredientID, req.GramsPerML, req.Source))
+}
+
+// DeleteDensity returns sql.ErrNoRows when there was nothing to delete.
+func (r *ingredientRepository) DeleteDensity(ingredientID int) error {
+	result, err := r.db.Exec(
+		"DELETE FROM recipe_catalogue.ingredient_densities WHERE ingredient_id = $1", ingredientID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetDensities returns grams per millilitre keyed by ingredient ID. Ingredients
+// without a density are absent from the map.
+func (r *ingredientRepository) GetDensities(ingredientIDs []int) (map[int]float64, error) {
+	densities := make(map[int]float64)
+	if len(ingredientIDs) == 0 {
+		return densities, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT ingredient_id, grams_per_ml
+		FROM recipe_catalogue.ingredient_densities
+		WHERE ingredient_id = ANY($1)`, pq.Array(ingredientIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var gramsPerML float64
+		if err := rows.Scan(&id, &gramsPerML); err != nil {
+			return nil, err
+		}
+		densities[id] = gramsPerML
+	}
+
+	return densities, rows.Err()
+}
+
+func (r *ingredientRepository) GetPackSizes(ingredientID int) ([]models.PackSize, error) {
+	rows, err := r.db.Query(`
+		SELECT id, ingredient_id, quantity, unit, label, created_at
+		FROM recipe_catalogue.ingredient_pack_sizes
+		WHERE ingredient_id = $1
+		ORDER BY quantity`, ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	packSizes := make([]models.PackSize, 0)
+	for rows.Next() {
+		packSize, err := r.scanPackSize(rows)
+		if err != nil {
+			return nil, err
+		}
+		packSizes = append(packSizes, *packSize)
+	}
+
+	return packSizes, rows.Err()
+}
+
+// SetPackSizes replaces all pack sizes of an ingredient.
+func (r *ingredientRepository) SetPackSizes(ingredientID int, packSizes []models.PackSizeRequest) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM recipe_catalogue.ingredient_pack_sizes WHERE ingredient_id = $1", ingredientID)
+	if err != nil {
+		return err
+	}
+
+	for _, packSize := range packSizes {
+		_, err = tx.Exec(`
+			INSERT INTO recipe_catalogue.ingredient_pack_sizes (ingredient_id, quantity, unit, label, created_at)
+			VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)`,
+			ingredientID, packSize.Quantity, packSize.Unit, packSize.Label)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *ingredientRepository) GetPackSizesForIngredients(ingredientIDs []int) (map[int][]models.PackSize, error) {
+	packSizes := make(map[int][]models.PackSize)
+	if len(ingredientIDs) == 0 {
+		return packSizes, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, ingredient_id, quantity, unit, label, created_at
+		FROM recipe_catalogue.ingredient_pack_sizes
+		WHERE ingredient_id = ANY($1)
+		ORDER BY ingredient_id, quantity`, pq.Array(ingredientIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		packSize, err := r.scanPackSize(rows)
+		if err != nil {
+			return nil, err
+		}
+		packSizes[packSize.IngredientID] = append(packSizes[packSize.IngredientID], *packSize)
+	}
+
+	return packSizes, rows.Err()
+}
+
+const productColumns = `id, ingredient_id, brand, name, calories_kcal, protein_g, carbs_g, fat_g, fiber_g, created_at`
+
+func (r *ingredientRepository) GetProducts(ingredientID int) ([]models.IngredientProduct, error) {
+	rows, err := r.db.Query(`
+		SELECT `+productColumns+`
+		FROM recipe_catalogue.ingredient_products
+		WHERE ingredient_id = $1
+		ORDER BY brand, name`, ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]models.IngredientProduct, 0)
+	for rows.Next() {
+		product, err := r.scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, *product)
+	}
+
+	return products, rows.Err()
+}
+
+func (r *ingredientRepository) CreateProduct(ingredientID int, req models.IngredientProductRequest) (*models.IngredientProduct, error) {
+	n := req.Nutrition
+	product, err := r.scanProduct(r.db.QueryRow(`
+		INSERT INTO recipe_catalogue.ingredient_products
+		    (ingredient_id, brand, name, calories_kcal, protein_g, carbs_g, fat_g, fiber_g, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP)
+		RETURNING `+productColumns,
+		ingredientID, req.Brand, req.Name, n.CaloriesKcal, n.ProteinG, n.CarbsG, n.FatG, n.FiberG))
+	if database.IsUniqueViolation(err, "ingredient_products_unique") {
+		return nil, domain.ErrProductExists
+	}
+	return product, err
+}
+
+func (r *ingredientRepository) UpdateProduct(ingredientID, productID int, req models.IngredientProductRequest) (*models.IngredientProduct, error) {
+	n := req.Nutrition
+	product, err := r.scanProduct(r.db.QueryRow(`
+		UPDATE recipe_catalogue.ingredient_products
+		SET brand = $3, name = $4, calories_kcal = $5, protein_g = $6, carbs_g = $7, fat_g = $8, fiber_g = $9
+		WHERE ingredient_id = $1 AND id = $2
+		RETURNING `+productColumns,
+		ingredientID, productID, req.Brand, req.Name, n.CaloriesKcal, n.ProteinG, n.CarbsG, n.FatG, n.FiberG))
+	if database.IsUniqueViolation(err, "ingredient_products_unique") {
+		return nil, domain.ErrProductExists
+	}
+	return product, err
+}
+
+func (r *ingredientRepository) DeleteProduct(ingredientID, productID int) error {
+	result, err := r.db.Exec(
+		"DELETE FROM recipe_catalogue.ingredient_products WHERE ingredient_id = $1 AND id = $2",
+		ingredientID, productID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *ingredientRepository) GetProductsByID(productIDs []int) (map[int]models.IngredientProduct, error) {
+	products := make(map[int]models.IngredientProduct)
+	if len(productIDs) == 0 {
+		return products, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+productColumns+`
+		FROM recipe_catalogue.ingredient_products
+		WHERE id = ANY($1)`, pq.Array(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		product, err := r.scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products[product.ID] = *product
+	}
+
+	return products, rows.Err()
+}
+
+// attachProducts fills in the product of the recipe ingredients that name
+// one.
+func (r *ingredientRepository) attachProducts(recipeIngredients []models.RecipeIngredient) error {
+	var productIDs []int
+	for _, ri := range recipeIngredients {
+		if ri.ProductID != nil {
+			productIDs = append(productIDs, *ri.ProductID)
+		}
+	}
+	if len(productIDs) == 0 {
+		return nil
+	}
+
+	products, err := r.GetProductsByID(productIDs)
+	if err != nil {
+		return err
+	}
+	for i, ri := range recipeIngredients {
+		if ri.ProductID == nil {
+			continue
+		}
+		if product, ok := products[*ri.ProductID]; ok {
+			recipeIngredients[i].Product = &product
+		}
+	}
+	return nil
+}
+
+func (r *ingredientRepository) GetSubstitutions(ingredientID int) ([]models.IngredientSubstitution, error) {
+	substitutions, err := r.GetSubstitutionsForIngredients([]int{ingredientID})
+	if err != nil {
+		return nil, err
+	}
+	if substitutions[ingredientID] == nil {
+		return []models.IngredientSubstitution{}, nil
+	}
+	return substitutions[ingredientID], nil
+}
+
+// SetSubstitutions replaces all substitutes of an ingredient.
+func (r *ingredientRepository) SetSubstitutions(ingredientID int, substitutions []models.SubstitutionRequest) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM recipe_catalogue.ingredient_substitutions WHERE ingredient_id = $1", ingredientID)
+	if err != nil {
+		return err
+	}
+
+	for _, substitution := range substitutions {
+		_, err = tx.Exec(`
+			INSERT INTO recipe_catalogue.ingredient_substitutions (ingredient_id, substitute_id, ratio, notes, created_at)
+			VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)`,
+			ingredientID, substitution.SubstituteID, substitution.Ratio, substitution.Notes)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetSubstitutionsForIngredients returns substitutes keyed by the ingredient
+// they replace, each list ordered by substitute name.
+func (r *ingredientRepository) GetSubstitutionsForIngredients(ingredientIDs []int) (map[int][]models.IngredientSubstitution, error) {
+	substitutions := make(map[int][]models.IngredientSubstitution)
+	if len(ingredientIDs) == 0 {
+		return substitutions, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT s.ingredient_id, s.ratio, s.notes,
+		       i.id, i.name, i.description, i.category, i.created_at
+		FROM recipe_catalogue.ingredient_substitutions s
+		JOIN recipe_catalogue.ingredients i ON i.id = s.substitute_id
+		WHERE s.ingredient_id = ANY($1)
+		ORDER BY s.ingredient_id, i.name`, pq.Array(ingredientIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var substitution models.IngredientSubstitution
+		var notes, description, category sql.NullString
+		err := rows.Scan(&substitution.IngredientID, &substitution.Ratio, &notes,
+			&substitution.Substitute.ID, &substitution.Substitute.Name, &description,
+			&category, &substitution.Substitute.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		substitution.SubstituteID = substitution.Substitute.ID
+		if notes.Valid {
+			substitution.Notes = &notes.String
+		}
+		if description.Valid {
+			substitution.Substitute.Description = &description.String
+		}
+		if category.Valid {
+			substitution.Substitute.Category = &category.String
+		}
+		substitutions[substitution.IngredientID] = append(substitutions[substitution.IngredientID], substitution)
+	}
+
+	return substitutions, rows.Err()
+}
+
+func (r *ingredientRepository) GetTranslations(ingredientID int) ([]models.IngredientTranslation, error) {
+	rows, err := r.db.Query(`
+		SELECT locale, name
+		FROM recipe_catalogue.ingredient_translations
+		WHERE ingredient_id = $1
+		ORDER BY locale`, ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := make([]models.IngredientTranslation, 0)
+	for rows.Next() {
+		var translation models.IngredientTranslation
+		if err := rows.Scan(&translation.Locale, &translation.Name); err != nil {
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+	return translations, rows.Err()
+}
+
+// SetTranslations replaces all translations of an ingredient.
+func (r *ingredientRepository) SetTranslations(ingredientID int, translations []models.IngredientTranslation) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM recipe_catalogue.ingredient_translations WHERE ingredient_id = $1", ingredientID)
+	if err != nil {
+		return err
+	}
+
+	for _, translation := range translations {
+		_, err = tx.Exec(`
+			INSERT INTO recipe_catalogue.ingredient_translations (ingredient_id, locale, name, created_at)
+			VALUES ($1, $2, $3, CURRENT_TIMESTAMP)`,
+			ingredientID, translation.Locale, translation.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetLocalizedNames returns the names of the ingredients in the first of
+// locales they have a translation for. Ingredients without any are left out,
+// callers fall back to the English name.
+func (r *ingredientRepository) GetLocalizedNames(ingredientIDs []int, locales []string) (map[int]string, error) {
+	names := make(map[int]string)
+	if len(ingredientIDs) == 0 || len(locales) == 0 {
+		return names, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT ingredient_id, locale, name
+		FROM recipe_catalogue.ingredient_translations
+		WHERE ingredient_id = ANY($1) AND locale = ANY($2)`,
+		pq.Array(ingredientIDs), pq.Array(locales))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	preference := make(map[string]int, len(locales))
+	for i, locale := range locales {
+		preference[locale] = i
+	}
+	chosen := make(map[int]int)
+	for rows.Next() {
+		var ingredientID int
+		var locale, name string
+		if err := rows.Scan(&ingredientID, &locale, &name); err != nil {
+			return nil, err
+		}
+		if rank, ok := chosen[ingredientID]; ok && rank <= preference[locale] {
+			continue
+		}
+		chosen[ingredientID] = preference[locale]
+		names[ingredientID] = name
+	}
+	return names, rows.Err()
+}
+
+// GetDietaryFacts returns the flags and overrides of the ingredients.
+// Ingredients with neither are left out.
+func (r *ingredientRepository) GetDietaryFacts(ingredientIDs []int) (map[int]models.DietaryFacts, error) {
+	facts := make(map[int]models.DietaryFacts)
+	if len(ingredientIDs) == 0 {
+		return facts, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT ingredient_id, flag
+		FROM recipe_catalogue.ingredient_dietary_flags
+		WHERE ingredient_id = ANY($1)
+		ORDER BY ingredient_id, flag`, pq.Array(ingredientIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ingredientID int
+		var flag string
+		if err := rows.Scan(&ingredientID, &flag); err != nil {
+			return nil, err
+		}
+		f := facts[ingredientID]
+		f.Flags = append(f.Flags, flag)
+		facts[ingredientID] = f
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	overrides, err := r.db.Query(`
+		SELECT ingredient_id, diet
+		FROM recipe_catalogue.ingredient_diet_overrides
+		WHERE ingredient_id = ANY($1)`, pq.Array(ingredientIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer overrides.Close()
+
+	for overrides.Next() {
+		var ingredientID int
+		var diet string
+		if err := overrides.Scan(&ingredientID, &diet); err != nil {
+			return nil, err
+		}
+		f := facts[ingredientID]
+		f.Override = diet
+		facts[ingredientID] = f
+	}
+	return facts, overrides.Err()
+}
+
+// SetDietaryFacts replaces an ingredient's flags and override; an empty
+// override removes it.
+func (r *ingredientRepository) SetDietaryFacts(ingredientID int, facts models.DietaryFacts) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM recipe_catalogue.ingredient_dietary_flags WHERE ingredient_id = $1", ingredientID); err != nil {
+		return err
+	}
+	for _, flag := range facts.Flags {
+		_, err = tx.Exec(`
+			INSERT INTO recipe_catalogue.ingredient_dietary_flags (ingredient_id, flag)
+			VALUES ($1, $2)`, ingredientID, flag)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM recipe_catalogue.ingredient_diet_overrides WHERE ingredient_id = $1", ingredientID); err != nil {
+		return err
+	}
+	if facts.Override != "" {
+		_, err = tx.Exec(`
+			INSERT INTO recipe_catalogue.ingredient_diet_overrides (ingredient_id, diet, updated_at)
+			VALUES ($1, $2, CURRENT_TIMESTAMP)`, ingredientID, facts.Override)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetAllergens lists every allergen ingredients can be tagged with, by name.
+func (r *ingredientRepository) GetAllergens() ([]models.Allergen, error) {
+	rows, err := r.db.Query(`
+		SELECT id, code, name
+		FROM recipe_catalogue.allergens
+		ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAllergens(rows)
+}
+
+func (r *ingredientRepository) GetIngredientAllergens(ingredientID int) ([]models.Allergen, error) {
+	rows, err := r.db.Query(`
+		SELECT a.id, a.code, a.name
+		FROM recipe_catalogue.ingredient_allergens ia
+		JOIN recipe_catalogue.allergens a ON a.id = ia.allergen_id
+		WHERE ia.ingredient_id = $1
+		ORDER BY a.name`, ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAllergens(rows)
+}
+
+// SetIngredientAllergens replaces all allergens of an ingredient.
+func (r *ingredientRepository) SetIngredientAllergens(ingredientID int, allergenIDs []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM recipe_catalogue.ingredient_allergens WHERE ingredient_id = $1", ingredientID); err != nil {
+		return err
+	}
+	for _, allergenID := range allergenIDs {
+		_, err = tx.Exec(`
+			INSERT INTO recipe_catalogue.ingredient_allergens (ingredient_id, allergen_id)
+			VALUES ($1, $2)`, ingredientID, allergenID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AddIngredientAllergen tags an ingredient with an allergen; tagging it
+// again changes nothing.
+func (r *ingredientRepository) AddIngredientAllergen(ingredientID, allergenID int) error {
+	_, err := r.db.Exec(`
+		INSERT INTO recipe_catalogue.ingredient_allergens (ingredient_id, allergen_id)
+		VALUES ($1, $2)
+		ON CONFLICT (ingredient_id, allergen_id) DO NOTHING`, ingredientID, allergenID)
+	return err
+}
+
+func (r *ingredientRepository) RemoveIngredientAllergen(ingredientID, allergenID int) error {
+	result, err := r.db.Exec(`
+		DELETE FROM recipe_catalogue.ingredient_allergens
+		WHERE ingredient_id = $1 AND allergen_id = $2`, ingredientID, allergenID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetRecipeAllergens returns each allergen any of the recipe's ingredients
+// carries, by name, with those ingredients by name.
+func (r *ingredientRepository) GetRecipeAllergens(recipeID int) ([]models.RecipeAllergen, error) {
+	rows, err := r.db.Query(`
+		SELECT a.code, a.name, i.name
+		FROM recipe_catalogue.recipe_ingredients ri
+		JOIN recipe_catalogue.ingredients i ON i.id = ri.ingredient_id
+		JOIN recipe_catalogue.ingredient_allergens ia ON ia.ingredient_id = ri.ingredient_id
+		JOIN recipe_catalogue.allergens a ON a.id = ia.allergen_id
+		WHERE ri.recipe_id = $1
+		ORDER BY a.name, i.name`, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	allergens := make([]models.RecipeAllergen, 0)
+	for rows.Next() {
+		var code, name, ingredient string
+		if err := rows.Scan(&code, &name, &ingredient); err != nil {
+			return nil, err
+		}
+		if n := len(allergens); n > 0 && allergens[n-1].Code == code {
+			allergens[n-1].Ingredients = append(allergens[n-1].Ingredients, ingredient)
+			continue
+		}
+		allergens = append(allergens, models.RecipeAllergen{Code: code, Name: name, Ingredients: []string{ingredient}})
+	}
+	return allergens, rows.Err()
+}
+
 // Helper methods
+func scanAllergens(rows *sql.Rows) ([]models.Allergen, error) {
+	allergens := make([]models.Allergen, 0)
+	for rows.Next() {
+		var allergen models.Allergen
+		if err := rows.Scan(&allergen.ID, &allergen.Code, &allergen.Name); err != nil {
+			return nil, err
+		}
+		allergens = append(allergens, allergen)
+	}
+	return allergens, rows.Err()
+}
+
+func (r *ingredientRepository) scanPackSize(scanner interface {
+	Scan(...interface{}) error
+}) (*models.PackSize, error) {
+	var packSize models.PackSize
+	var label sql.NullString
+
+	err := scanner.Scan(&packSize.ID, &packSize.IngredientID, &packSize.Quantity, &packSize.Unit, &label, &packSize.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if label.Valid {
+		packSize.Label = &label.String
+	}
+	return &packSize, nil
+}
+
+func (r *ingredientRepository) scanProduct(scanner interface {
+	Scan(...interface{}) error
+}) (*models.IngredientProduct, error) {
+	var product models.IngredientProduct
+	n := &product.Nutrition
+
+	err := scanner.Scan(&product.ID, &product.IngredientID, &product.Brand, &product.Name,
+		&n.CaloriesKcal, &n.ProteinG, &n.CarbsG, &n.FatG, &n.FiberG, &product.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *ingredientRepository) scanDensity(scanner interface {
+	Scan(...interface{}) error
+}) (*models.IngredientDensity, error) {
+	var density models.IngredientDensity
+	var source sql.NullString
+
+	err := scanner.Scan(&density.IngredientID, &density.GramsPerML, &source, &density.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.Valid {
+		density.Source = &source.String
+	}
+	return &density, nil
+}
+
 func (r *ingredientRepository) scanIngredient(scanner interface {
 	Scan(...interface{}) error
 }) (*models.Ingredient, error) {
@@ -452,7 +1379,7 @@ func (r *ingredientRepository) scanRecipeIngredient(scanner interface {
 	var riNotes, ingredientDesc, ingredientCategory sql.NullString
 
 	err := scanner.Scan(
-		&ri.ID, &ri.RecipeID, &ri.IngredientID, &ri.Quantity, &ri.Unit, &riNotes, &ri.CreatedAt,
+		&ri.ID, &ri.RecipeID, &ri.IngredientID, &ri.Quantity, &ri.Unit, &riNotes, &ri.CreatedAt, &ri.ProductID,
 		&ingredient.ID, &ingredient.Name, &ingredientDesc, &ingredientCategory, &ingredient.CreatedAt,
 	)
 	if err != nil {
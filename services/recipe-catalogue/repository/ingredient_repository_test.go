@@ -47,12 +47,13 @@ func (suite *IngredientRepositoryTestSuite) TestGetAllIngredients_ReturnsAllIngr
 	now := time.Now()
 	params := models.PaginationParams{Page: 1, PerPage: 20}
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.ingredients`)).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE archived_at IS NULL`)).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
 		SELECT id, name, description, category, created_at
 		FROM recipe_catalogue.ingredients
+		WHERE archived_at IS NULL
 		ORDER BY category, name
 		LIMIT $1 OFFSET $2`)).
 		WithArgs(20, 0).
@@ -82,7 +83,7 @@ func (suite *IngredientRepositoryTestSuite) TestGetAllIngredients_DatabaseError(
 	// Arrange
 	params := models.PaginationParams{Page: 1, PerPage: 20}
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.ingredients`)).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE archived_at IS NULL`)).
 		WillReturnError(errors.New("database connection lost"))
 
 	// Act
@@ -141,14 +142,14 @@ func (suite *IngredientRepositoryTestSuite) TestGetIngredientsByCategory_Returns
 	now := time.Now()
 	params := models.PaginationParams{Page: 1, PerPage: 20}
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE category = $1`)).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE category = $1 AND archived_at IS NULL`)).
 		WithArgs("Vegetable").
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
 		SELECT id, name, description, category, created_at
 		FROM recipe_catalogue.ingredients
-		WHERE category = $1
+		WHERE category = $1 AND archived_at IS NULL
 		ORDER BY name
 		LIMIT $2 OFFSET $3`)).
 		WithArgs("Vegetable", 20, 0).
@@ -172,14 +173,14 @@ func (suite *IngredientRepositoryTestSuite) TestSearchIngredients_ReturnsMatchin
 	now := time.Now()
 	params := models.PaginationParams{Page: 1, PerPage: 20}
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE name ILIKE $1 OR description ILIKE $1`)).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE ` + ingredientSearchCondition)).
 		WithArgs("%tomato%").
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
 		SELECT id, name, description, category, created_at
 		FROM recipe_catalogue.ingredients
-		WHERE name ILIKE $1 OR description ILIKE $1
+		WHERE `+ingredientSearchCondition+`
 		ORDER BY name
 		LIMIT $2 OFFSET $3`)).
 		WithArgs("%tomato%", 20, 0).
@@ -365,6 +366,30 @@ func (suite *IngredientRepositoryTestSuite) TestIngredientExists_ReturnsFalse()
 	assert.False(suite.T(), exists)
 }
 
+func (suite *IngredientRepositoryTestSuite) TestFindMissingIngredientIDs_SingleQuery() {
+	// Arrange
+	ids := []int{1, 2, 999, 2}
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM recipe_catalogue.ingredients WHERE id = ANY($1)")).
+		WithArgs(pq.Array(ids)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+	// Act
+	missing, err := suite.repo.FindMissingIngredientIDs(ids)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []int{999}, missing)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestFindMissingIngredientIDs_EmptyInputSkipsQuery() {
+	// Act
+	missing, err := suite.repo.FindMissingIngredientIDs([]int{})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), missing)
+}
+
 // =============================================================================
 // RECIPE-INGREDIENT RELATIONSHIP OPERATIONS
 // =============================================================================
@@ -373,7 +398,7 @@ func (suite *IngredientRepositoryTestSuite) TestGetRecipeIngredients_ReturnsReci
 	// Arrange
 	now := time.Now()
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at,
+		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at, ri.product_id,
 		       i.id, i.name, i.description, i.category, i.created_at
 		FROM recipe_catalogue.recipe_ingredients ri
 		JOIN recipe_catalogue.ingredients i ON ri.ingredient_id = i.id
@@ -381,11 +406,11 @@ func (suite *IngredientRepositoryTestSuite) TestGetRecipeIngredients_ReturnsReci
 		ORDER BY ri.id`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at",
+			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at", "product_id",
 			"i_id", "i_name", "i_description", "i_category", "i_created_at",
 		}).
-			AddRow(1, 1, 1, 200.0, "grams", "Fresh", now, 1, "Tomato", "Red tomato", "Vegetable", now).
-			AddRow(2, 1, 2, 1.0, "piece", nil, now, 2, "Onion", "Yellow onion", "Vegetable", now))
+			AddRow(1, 1, 1, 200.0, "grams", "Fresh", now, nil, 1, "Tomato", "Red tomato", "Vegetable", now).
+			AddRow(2, 1, 2, 1.0, "piece", nil, now, nil, 2, "Onion", "Yellow onion", "Vegetable", now))
 
 	// Act
 	recipeIngredients, err := suite.repo.GetRecipeIngredients(1)
@@ -416,12 +441,12 @@ func (suite *IngredientRepositoryTestSuite) TestAddRecipeIngredient_AddsAndRetur
 
 	// Expect ingredient addition
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, created_at)
-		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
-		RETURNING id, recipe_id, ingredient_id, quantity, unit, notes, created_at`)).
-		WithArgs(1, req.IngredientID, req.Quantity, req.Unit, req.Notes).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "created_at"}).
-			AddRow(1, 1, req.IngredientID, req.Quantity, req.Unit, *req.Notes, now))
+		INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)
+		RETURNING id, recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at`)).
+		WithArgs(1, req.IngredientID, req.Quantity, req.Unit, req.Notes, req.ProductID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "product_id", "created_at"}).
+			AddRow(1, 1, req.IngredientID, req.Quantity, req.Unit, *req.Notes, nil, now))
 
 	// Expect ingredient details retrieval
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
@@ -458,12 +483,12 @@ func (suite *IngredientRepositoryTestSuite) TestUpdateRecipeIngredient_UpdatesAn
 	// Expect recipe ingredient update
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
 		UPDATE recipe_catalogue.recipe_ingredients 
-		SET quantity = $3, unit = $4, notes = $5
+		SET quantity = $3, unit = $4, notes = $5, product_id = $6
 		WHERE recipe_id = $1 AND ingredient_id = $2
-		RETURNING id, recipe_id, ingredient_id, quantity, unit, notes, created_at`)).
-		WithArgs(1, 1, req.Quantity, req.Unit, req.Notes).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "created_at"}).
-			AddRow(1, 1, 1, req.Quantity, req.Unit, *req.Notes, now))
+		RETURNING id, recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at`)).
+		WithArgs(1, 1, req.Quantity, req.Unit, req.Notes, req.ProductID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "product_id", "created_at"}).
+			AddRow(1, 1, 1, req.Quantity, req.Unit, *req.Notes, nil, now))
 
 	// Expect ingredient details retrieval
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
@@ -534,9 +559,9 @@ func (suite *IngredientRepositoryTestSuite) TestSetRecipeIngredients_CommitsTran
 	// Expect ingredient insertions
 	for _, ingredient := range ingredients {
 		suite.mock.ExpectExec(regexp.QuoteMeta(`
-			INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, created_at)
-			VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)`)).
-			WithArgs(1, ingredient.IngredientID, ingredient.Quantity, ingredient.Unit, ingredient.Notes).
+			INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)`)).
+			WithArgs(1, ingredient.IngredientID, ingredient.Quantity, ingredient.Unit, ingredient.Notes, ingredient.ProductID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 	}
 
@@ -567,16 +592,16 @@ func (suite *IngredientRepositoryTestSuite) TestSetRecipeIngredients_RollsBackOn
 
 	// Expect first insertion to succeed
 	suite.mock.ExpectExec(regexp.QuoteMeta(`
-		INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, created_at)
-		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)`)).
-		WithArgs(1, ingredients[0].IngredientID, ingredients[0].Quantity, ingredients[0].Unit, ingredients[0].Notes).
+		INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)`)).
+		WithArgs(1, ingredients[0].IngredientID, ingredients[0].Quantity, ingredients[0].Unit, ingredients[0].Notes, ingredients[0].ProductID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Expect second insertion to fail
 	suite.mock.ExpectExec(regexp.QuoteMeta(`
-		INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, created_at)
-		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)`)).
-		WithArgs(1, ingredients[1].IngredientID, ingredients[1].Quantity, ingredients[1].Unit, ingredients[1].Notes).
+		INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)`)).
+		WithArgs(1, ingredients[1].IngredientID, ingredients[1].Quantity, ingredients[1].Unit, ingredients[1].Notes, ingredients[1].ProductID).
 		WillReturnError(errors.New("constraint violation"))
 
 	// Expect rollback
@@ -621,7 +646,7 @@ func (suite *IngredientRepositoryTestSuite) TestGetIngredientsForRecipes_Returns
 	now := time.Now()
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at,
+		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at, ri.product_id,
 		       i.id, i.name, i.description, i.category, i.created_at
 		FROM recipe_catalogue.recipe_ingredients ri
 		JOIN recipe_catalogue.ingredients i ON ri.ingredient_id = i.id
@@ -629,12 +654,12 @@ func (suite *IngredientRepositoryTestSuite) TestGetIngredientsForRecipes_Returns
 		ORDER BY ri.recipe_id, ri.id`)).
 		WithArgs(pq.Array(recipeIDs)).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at",
+			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at", "product_id",
 			"i_id", "i_name", "i_description", "i_category", "i_created_at",
 		}).
-			AddRow(1, 1, 1, 100.0, "g", nil, now, 1, "Ingredient 1", "Desc 1", "Cat 1", now).
-			AddRow(2, 1, 2, 200.0, "ml", "Fresh", now, 2, "Ingredient 2", "Desc 2", "Cat 2", now).
-			AddRow(3, 2, 1, 150.0, "g", nil, now, 1, "Ingredient 1", "Desc 1", "Cat 1", now))
+			AddRow(1, 1, 1, 100.0, "g", nil, now, nil, 1, "Ingredient 1", "Desc 1", "Cat 1", now).
+			AddRow(2, 1, 2, 200.0, "ml", "Fresh", now, nil, 2, "Ingredient 2", "Desc 2", "Cat 2", now).
+			AddRow(3, 2, 1, 150.0, "g", nil, now, nil, 1, "Ingredient 1", "Desc 1", "Cat 1", now))
 
 	// Act
 	result, err := suite.repo.GetIngredientsForRecipes(recipeIDs)
@@ -672,26 +697,26 @@ func (suite *IngredientRepositoryTestSuite) TestGetRecipesUsingIngredient_Return
 		SELECT COUNT(DISTINCT r.id)
 		FROM recipe_catalogue.recipes r
 		JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
-		WHERE ri.ingredient_id = $1`)).
+		WHERE ri.ingredient_id = $1 AND r.status = 'published'`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT DISTINCT r.id, r.user_id, r.name, r.description, r.category_id, r.created_at, r.updated_at,
+		SELECT DISTINCT r.id, r.user_id, r.name, r.description, r.category_id, r.created_at, r.updated_at, r.status, r.difficulty, r.total_time_minutes,
 		                c.id, c.name, c.description
 		FROM recipe_catalogue.recipes r
 		LEFT JOIN recipe_catalogue.categories c ON r.category_id = c.id
 		JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
-		WHERE ri.ingredient_id = $1
+		WHERE ri.ingredient_id = $1 AND r.status = 'published'
 		ORDER BY r.name
 		LIMIT $2 OFFSET $3`)).
 		WithArgs(1, 20, 0).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at",
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
 			"c_id", "c_name", "c_description",
 		}).
-			AddRow(1, 1, "Pasta Dish", "Italian pasta", 1, now, now, 1, "Italian", "Italian cuisine").
-			AddRow(2, 1, "Tomato Soup", "Fresh soup", 2, now, now, 2, "Soup", "Soup category"))
+			AddRow(1, 1, "Pasta Dish", "Italian pasta", 1, now, now, "published", nil, nil, 1, "Italian", "Italian cuisine").
+			AddRow(2, 1, "Tomato Soup", "Fresh soup", 2, now, now, "published", nil, nil, 2, "Soup", "Soup category"))
 
 	// Act
 	recipes, total, err := suite.repo.GetRecipesUsingIngredient(1, params)
@@ -705,6 +730,142 @@ func (suite *IngredientRepositoryTestSuite) TestGetRecipesUsingIngredient_Return
 	assert.Equal(suite.T(), "Tomato Soup", recipes[1].Name)
 }
 
+// =============================================================================
+// INGREDIENT DENSITIES
+// =============================================================================
+
+func (suite *IngredientRepositoryTestSuite) TestGetDensity_ReturnsDensity() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT ingredient_id, grams_per_ml, source, updated_at
+		FROM recipe_catalogue.ingredient_densities
+		WHERE ingredient_id = $1`)).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"ingredient_id", "grams_per_ml", "source", "updated_at"}).
+			AddRow(4, 0.85, nil, now))
+
+	// Act
+	density, err := suite.repo.GetDensity(4)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0.85, density.GramsPerML)
+	assert.Nil(suite.T(), density.Source)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestUpsertDensity_InsertsOrUpdates() {
+	// Arrange
+	now := time.Now()
+	source := "kitchen scale"
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		INSERT INTO recipe_catalogue.ingredient_densities (ingredient_id, grams_per_ml, source, updated_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+		ON CONFLICT (ingredient_id) DO UPDATE`)).
+		WithArgs(5, 0.6, &source).
+		WillReturnRows(sqlmock.NewRows([]string{"ingredient_id", "grams_per_ml", "source", "updated_at"}).
+			AddRow(5, 0.6, source, now))
+
+	// Act
+	density, err := suite.repo.UpsertDensity(5, models.SetIngredientDensityRequest{GramsPerML: 0.6, Source: &source})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 5, density.IngredientID)
+	assert.Equal(suite.T(), "kitchen scale", *density.Source)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestDeleteDensity_NotFound() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.ingredient_densities WHERE ingredient_id = $1")).
+		WithArgs(999).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := suite.repo.DeleteDensity(999)
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestGetDensities_ReturnsMapKeyedByIngredient() {
+	// Arrange
+	ids := []int{4, 5, 9}
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT ingredient_id, grams_per_ml
+		FROM recipe_catalogue.ingredient_densities
+		WHERE ingredient_id = ANY($1)`)).
+		WithArgs(pq.Array(ids)).
+		WillReturnRows(sqlmock.NewRows([]string{"ingredient_id", "grams_per_ml"}).
+			AddRow(4, 0.85).
+			AddRow(9, 0.91))
+
+	// Act
+	densities, err := suite.repo.GetDensities(ids)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[int]float64{4: 0.85, 9: 0.91}, densities)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestGetDensities_EmptySlice() {
+	// Act
+	densities, err := suite.repo.GetDensities(nil)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), densities)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestSetPackSizes_ReplacesInTransaction() {
+	// Arrange
+	label := "can"
+	packSizes := []models.PackSizeRequest{{Quantity: 400, Unit: "g", Label: &label}}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.ingredient_pack_sizes WHERE ingredient_id = $1")).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`
+			INSERT INTO recipe_catalogue.ingredient_pack_sizes (ingredient_id, quantity, unit, label, created_at)
+			VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)`)).
+		WithArgs(3, 400.0, "g", &label).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	err := suite.repo.SetPackSizes(3, packSizes)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestGetPackSizesForIngredients_GroupsByIngredient() {
+	// Arrange
+	now := time.Now()
+	ids := []int{3, 12}
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT id, ingredient_id, quantity, unit, label, created_at
+		FROM recipe_catalogue.ingredient_pack_sizes
+		WHERE ingredient_id = ANY($1)
+		ORDER BY ingredient_id, quantity`)).
+		WithArgs(pq.Array(ids)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ingredient_id", "quantity", "unit", "label", "created_at"}).
+			AddRow(1, 3, 400.0, "g", "can", now).
+			AddRow(2, 12, 6.0, "pieces", nil, now).
+			AddRow(3, 12, 12.0, "pieces", "box", now))
+
+	// Act
+	result, err := suite.repo.GetPackSizesForIngredients(ids)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), result[3], 1)
+	assert.Equal(suite.T(), "can", *result[3][0].Label)
+	assert.Len(suite.T(), result[12], 2)
+	assert.Nil(suite.T(), result[12][0].Label)
+}
+
 // =============================================================================
 // ERROR HANDLING AND EDGE CASES
 // =============================================================================
@@ -736,7 +897,7 @@ func (suite *IngredientRepositoryTestSuite) TestScanRecipeIngredient_HandlesNull
 	// Test through GetRecipeIngredients with null notes
 	now := time.Now()
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at,
+		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at, ri.product_id,
 		       i.id, i.name, i.description, i.category, i.created_at
 		FROM recipe_catalogue.recipe_ingredients ri
 		JOIN recipe_catalogue.ingredients i ON ri.ingredient_id = i.id
@@ -744,10 +905,10 @@ func (suite *IngredientRepositoryTestSuite) TestScanRecipeIngredient_HandlesNull
 		ORDER BY ri.id`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at",
+			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at", "product_id",
 			"i_id", "i_name", "i_description", "i_category", "i_created_at",
 		}).
-			AddRow(1, 1, 1, 200.0, "grams", nil, now, 1, "Tomato", nil, nil, now))
+			AddRow(1, 1, 1, 200.0, "grams", nil, now, nil, 1, "Tomato", nil, nil, now))
 
 	// Act
 	recipeIngredients, err := suite.repo.GetRecipeIngredients(1)
@@ -764,6 +925,269 @@ func (suite *IngredientRepositoryTestSuite) TestScanRecipeIngredient_HandlesNull
 // RUN TEST SUITE
 // =============================================================================
 
+func (suite *IngredientRepositoryTestSuite) TestSetSubstitutions_ReplacesInTransaction() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.ingredient_substitutions WHERE ingredient_id = $1")).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.ingredient_substitutions`)).
+		WithArgs(3, 7, 0.75, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	err := suite.repo.SetSubstitutions(3, []models.SubstitutionRequest{{SubstituteID: 7, Ratio: 0.75}})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *IngredientRepositoryTestSuite) TestGetSubstitutionsForIngredients_GroupsByIngredient() {
+	// Arrange
+	now := time.Now()
+	ids := []int{3, 12}
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.ingredient_substitutions s`)).
+		WithArgs(pq.Array(ids)).
+		WillReturnRows(sqlmock.NewRows([]string{"ingredient_id", "ratio", "notes", "id", "name", "description", "category", "created_at"}).
+			AddRow(3, 1.0, nil, 7, "Margarine", nil, "Dairy", now).
+			AddRow(3, 0.75, "for baking", 8, "Vegetable Oil", "neutral oil", nil, now))
+
+	// Act
+	result, err := suite.repo.GetSubstitutionsForIngredients(ids)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), result[3], 2)
+	assert.Equal(suite.T(), 7, result[3][0].SubstituteID)
+	assert.Equal(suite.T(), "Dairy", *result[3][0].Substitute.Category)
+	assert.Equal(suite.T(), "for baking", *result[3][1].Notes)
+	assert.NotContains(suite.T(), result, 12)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *IngredientRepositoryTestSuite) TestSetTranslations_ReplacesInTransaction() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.ingredient_translations WHERE ingredient_id = $1")).
+		WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.ingredient_translations`)).
+		WithArgs(10, "ru", "Помидор").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	err := suite.repo.SetTranslations(10, []models.IngredientTranslation{{Locale: "ru", Name: "Помидор"}})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *IngredientRepositoryTestSuite) TestGetDietaryFacts_MergesFlagsAndOverrides() {
+	// Arrange
+	ids := []int{8, 12}
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.ingredient_dietary_flags`)).
+		WithArgs(pq.Array(ids)).
+		WillReturnRows(sqlmock.NewRows([]string{"ingredient_id", "flag"}).
+			AddRow(8, "dairy").
+			AddRow(12, "egg"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.ingredient_diet_overrides`)).
+		WithArgs(pq.Array(ids)).
+		WillReturnRows(sqlmock.NewRows([]string{"ingredient_id", "diet"}).
+			AddRow(8, "omnivore"))
+
+	// Act
+	facts, err := suite.repo.GetDietaryFacts(ids)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[int]models.DietaryFacts{
+		8:  {Flags: []string{"dairy"}, Override: "omnivore"},
+		12: {Flags: []string{"egg"}},
+	}, facts)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *IngredientRepositoryTestSuite) TestSetDietaryFacts_ClearsOverride() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.ingredient_dietary_flags WHERE ingredient_id = $1")).
+		WithArgs(8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.ingredient_dietary_flags`)).
+		WithArgs(8, "dairy").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.ingredient_diet_overrides WHERE ingredient_id = $1")).
+		WithArgs(8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	err := suite.repo.SetDietaryFacts(8, models.DietaryFacts{Flags: []string{"dairy"}})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *IngredientRepositoryTestSuite) TestSetIngredientAllergens_ReplacesInTransaction() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.ingredient_allergens WHERE ingredient_id = $1")).
+		WithArgs(8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.ingredient_allergens`)).
+		WithArgs(8, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	err := suite.repo.SetIngredientAllergens(8, []int{2})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *IngredientRepositoryTestSuite) TestRemoveIngredientAllergen_NotTagged() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recipe_catalogue.ingredient_allergens`)).
+		WithArgs(8, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := suite.repo.RemoveIngredientAllergen(8, 1)
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *IngredientRepositoryTestSuite) TestGetRecipeIngredients_AttachesProducts() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.recipe_ingredients ri`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at", "product_id",
+			"i_id", "i_name", "i_description", "i_category", "i_created_at",
+		}).
+			AddRow(1, 1, 1, 200.0, "g", nil, now, 5, 1, "Pasta", nil, "Grains", now).
+			AddRow(2, 1, 2, 1.0, "piece", nil, now, nil, 2, "Onion", nil, "Vegetables", now))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.ingredient_products`)).
+		WithArgs(pq.Array([]int{5})).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "ingredient_id", "brand", "name", "calories_kcal", "protein_g", "carbs_g", "fat_g", "fiber_g", "created_at",
+		}).
+			AddRow(5, 1, "Barilla", "Spaghetti No. 5", 359.0, 12.5, 71.2, 2.0, 3.0, now))
+
+	// Act
+	recipeIngredients, err := suite.repo.GetRecipeIngredients(1)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), recipeIngredients, 2)
+	require.NotNil(suite.T(), recipeIngredients[0].Product)
+	assert.Equal(suite.T(), "Barilla", recipeIngredients[0].Product.Brand)
+	assert.Equal(suite.T(), 12.5, recipeIngredients[0].Product.Nutrition.ProteinG)
+	assert.Nil(suite.T(), recipeIngredients[1].Product)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *IngredientRepositoryTestSuite) TestDeleteProduct_NotFound() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recipe_catalogue.ingredient_products`)).
+		WithArgs(1, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := suite.repo.DeleteProduct(1, 9)
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *IngredientRepositoryTestSuite) TestGetRecipeAllergens_GroupsIngredientsByAllergen() {
+	// Arrange
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.recipe_ingredients ri`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"code", "name", "name"}).
+			AddRow("egg", "Eggs", "Eggs").
+			AddRow("dairy", "Milk", "Butter").
+			AddRow("dairy", "Milk", "Parmesan Cheese"))
+
+	// Act
+	allergens, err := suite.repo.GetRecipeAllergens(3)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []models.RecipeAllergen{
+		{Code: "egg", Name: "Eggs", Ingredients: []string{"Eggs"}},
+		{Code: "dairy", Name: "Milk", Ingredients: []string{"Butter", "Parmesan Cheese"}},
+	}, allergens)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *IngredientRepositoryTestSuite) TestGetLocalizedNames_PrefersEarlierLocales() {
+	// Arrange
+	ids := []int{10, 11}
+	locales := []string{"pt-br", "pt"}
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.ingredient_translations`)).
+		WithArgs(pq.Array(ids), pq.Array(locales)).
+		WillReturnRows(sqlmock.NewRows([]string{"ingredient_id", "locale", "name"}).
+			AddRow(10, "pt", "Tomate").
+			AddRow(10, "pt-br", "Tomate-cereja").
+			AddRow(11, "pt", "Cebola"))
+
+	// Act
+	names, err := suite.repo.GetLocalizedNames(ids, locales)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[int]string{10: "Tomate-cereja", 11: "Cebola"}, names)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *IngredientRepositoryTestSuite) TestSuggestIngredientNames_RanksByTrigramSimilarity() {
+	// Arrange
+	suite.mock.ExpectQuery(`similarity\(name, \$1\)[\s\S]+ingredient_translations WHERE name % \$1[\s\S]+LIMIT \$2`).
+		WithArgs("garlik", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Garlic").AddRow("Garlic Powder"))
+
+	// Act
+	names, err := suite.repo.SuggestIngredientNames("garlik", 5)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"Garlic", "Garlic Powder"}, names)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *IngredientRepositoryTestSuite) TestMatchIngredientNames_MatchesEachNameWithoutWriting() {
+	// Arrange
+	columns := []string{"id", "name", "alias"}
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(`FROM recipe_catalogue.ingredients WHERE name % \$1[\s\S]+ingredient_translations WHERE name % \$1`).
+		WithArgs("olive oil").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(11, "Olive Oil", false))
+	suite.mock.ExpectQuery(`FROM recipe_catalogue.ingredients WHERE name % \$1[\s\S]+ingredient_translations WHERE name % \$1`).
+		WithArgs("tinned tomatoes").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(12, "Tomato Paste", false))
+	suite.mock.ExpectRollback()
+
+	// Act
+	matches, err := suite.repo.MatchIngredientNames([]string{"olive oil", "tinned tomatoes"})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[string]int{"olive oil": 11}, matches)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
 func TestIngredientRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(IngredientRepositoryTestSuite))
 }
@@ -829,14 +1253,14 @@ func TestIngredientRepository_EdgeCases(t *testing.T) {
 
 			// Setup expectation
 			if tt.expectedQueries > 0 {
-				mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE name ILIKE $1 OR description ILIKE $1`)).
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.ingredients WHERE ` + ingredientSearchCondition)).
 					WithArgs("%" + tt.searchQuery + "%").
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
 				mock.ExpectQuery(regexp.QuoteMeta(`
 		SELECT id, name, description, category, created_at
 		FROM recipe_catalogue.ingredients
-		WHERE name ILIKE $1 OR description ILIKE $1
+		WHERE `+ingredientSearchCondition+`
 		ORDER BY name
 		LIMIT $2 OFFSET $3`)).
 					WithArgs("%"+tt.searchQuery+"%", 20, 0).
@@ -882,6 +1306,7 @@ func BenchmarkIngredientRepository_GetAllIngredients(b *testing.B) {
 		mock.ExpectQuery(regexp.QuoteMeta(`
 		SELECT id, name, description, category, created_at
 		FROM recipe_catalogue.ingredients
+		WHERE archived_at IS NULL
 		ORDER BY category, name
 		LIMIT $1 OFFSET $2`)).
 			WithArgs(20, 0).
@@ -922,3 +1347,97 @@ func BenchmarkIngredientRepository_IngredientExists(b *testing.B) {
 		}
 	}
 }
+
+// The prepared variant reuses one statement for every call, so sqlmock sees a
+// single Prepare followed by b.N executions of it.
+func BenchmarkIngredientRepository_IngredientExists_Prepared(b *testing.B) {
+	db, mock, err := sqlmock.New()
+	require.NoError(b, err)
+	defer db.Close()
+
+	wrapped := &database.DB{DB: db}
+	wrapped.EnableStatementCache(16)
+	repo := NewIngredientRepository(wrapped)
+
+	prepared := mock.ExpectPrepare(regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM recipe_catalogue.ingredients WHERE id = $1)"))
+	for i := 0; i < b.N; i++ {
+		prepared.ExpectQuery().
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := repo.IngredientExists(1)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+const benchmarkRecipeIngredientsQuery = `
+		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at, ri.product_id,
+		       i.id, i.name, i.description, i.category, i.created_at
+		FROM recipe_catalogue.recipe_ingredients ri
+		JOIN recipe_catalogue.ingredients i ON ri.ingredient_id = i.id
+		WHERE ri.recipe_id = $1
+		ORDER BY ri.id`
+
+func benchmarkRecipeIngredientsRows(now time.Time) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "created_at", "product_id",
+		"id", "name", "description", "category", "created_at",
+	}).
+		AddRow(1, 1, 1, 200.0, "grams", "diced", now, nil, 1, "Chicken Breast", "Desc", "Meat", now).
+		AddRow(2, 1, 2, 1.0, "pieces", nil, now, nil, 2, "Onion", "Desc", "Vegetables", now)
+}
+
+func BenchmarkIngredientRepository_GetRecipeIngredients(b *testing.B) {
+	db, mock, err := sqlmock.New()
+	require.NoError(b, err)
+	defer db.Close()
+
+	repo := NewIngredientRepository(&database.DB{DB: db})
+	now := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		mock.ExpectQuery(regexp.QuoteMeta(benchmarkRecipeIngredientsQuery)).
+			WithArgs(1).
+			WillReturnRows(benchmarkRecipeIngredientsRows(now))
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetRecipeIngredients(1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIngredientRepository_GetRecipeIngredients_Prepared(b *testing.B) {
+	db, mock, err := sqlmock.New()
+	require.NoError(b, err)
+	defer db.Close()
+
+	wrapped := &database.DB{DB: db}
+	wrapped.EnableStatementCache(16)
+	repo := NewIngredientRepository(wrapped)
+	now := time.Now()
+
+	prepared := mock.ExpectPrepare(regexp.QuoteMeta(benchmarkRecipeIngredientsQuery))
+	for i := 0; i < b.N; i++ {
+		prepared.ExpectQuery().
+			WithArgs(1).
+			WillReturnRows(benchmarkRecipeIngredientsRows(now))
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetRecipeIngredients(1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
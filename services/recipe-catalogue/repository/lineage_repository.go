@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type LineageRepository interface {
+	Record(recipeID, parentID int) error
+	GetAncestors(recipeID int) ([]models.Recipe, error)
+	GetForks(recipeID int) ([]models.RecipeFork, error)
+}
+
+type lineageRepository struct {
+	db *database.DB
+}
+
+func NewLineageRepository(db *database.DB) LineageRepository {
+	return &lineageRepository{db: db}
+}
+
+func (r *lineageRepository) Record(recipeID, parentID int) error {
+	_, err := r.db.Exec(`
+		INSERT INTO recipe_catalogue.recipe_lineage (recipe_id, parent_id, created_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)`, recipeID, parentID)
+	return err
+}
+
+// GetAncestors walks the "adapted from" chain up from a recipe, nearest
+// first. Only id, user_id, name and status are filled in.
+func (r *lineageRepository) GetAncestors(recipeID int) ([]models.Recipe, error) {
+	rows, err := r.db.Query(`
+		WITH RECURSIVE ancestors (parent_id, depth) AS (
+			SELECT parent_id, 1
+			FROM recipe_catalogue.recipe_lineage
+			WHERE recipe_id = $1
+			UNION ALL
+			SELECT l.parent_id, a.depth + 1
+			FROM recipe_catalogue.recipe_lineage l
+			JOIN ancestors a ON l.recipe_id = a.parent_id
+		)
+		SELECT d.id, d.user_id, d.name, d.status
+		FROM ancestors a
+		JOIN recipe_catalogue.recipes d ON d.id = a.parent_id
+		ORDER BY a.depth`, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ancestors := make([]models.Recipe, 0)
+	for rows.Next() {
+		var recipe models.Recipe
+		if err := rows.Scan(&recipe.ID, &recipe.UserID, &recipe.Name, &recipe.Status); err != nil {
+			return nil, err
+		}
+		ancestors = append(ancestors, recipe)
+	}
+	return ancestors, rows.Err()
+}
+
+// GetForks returns every published recipe descended from recipeID, direct
+// forks first. Unpublished forks are skipped but their own forks are not.
+func (r *lineageRepository) GetForks(recipeID int) ([]models.RecipeFork, error) {
+	rows, err := r.db.Query(`
+		WITH RECURSIVE forks (recipe_id, parent_id, depth, created_at) AS (
+			SELECT recipe_id, parent_id, 1, created_at
+			FROM recipe_catalogue.recipe_lineage
+			WHERE parent_id = $1
+			UNION ALL
+			SELECT l.recipe_id, l.parent_id, f.depth + 1, l.created_at
+			FROM recipe_catalogue.recipe_lineage l
+			JOIN forks f ON l.parent_id = f.recipe_id
+		)
+		SELECT d.id, f.parent_id, d.user_id, d.name, f.depth, f.created_at
+		FROM forks f
+		JOIN recipe_catalogue.recipes d ON d.id = f.recipe_id
+		WHERE d.status = 'published'
+		ORDER BY f.depth, f.created_at, d.id`, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	forks := make([]models.RecipeFork, 0)
+	for rows.Next() {
+		var fork models.RecipeFork
+		err := rows.Scan(&fork.RecipeID, &fork.AdaptedFromID, &fork.UserID, &fork.Name, &fork.Depth, &fork.ForkedAt)
+		if err != nil {
+			return nil, err
+		}
+		forks = append(forks, fork)
+	}
+	return forks, rows.Err()
+}
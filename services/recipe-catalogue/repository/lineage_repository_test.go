@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type LineageRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo LineageRepository
+}
+
+func (suite *LineageRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewLineageRepository(suite.db)
+}
+
+func (suite *LineageRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *LineageRepositoryTestSuite) TestRecord() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipe_lineage`)).
+		WithArgs(5, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := suite.repo.Record(5, 2)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *LineageRepositoryTestSuite) TestGetAncestors_NearestFirst() {
+	// Arrange
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WITH RECURSIVE ancestors`)).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "status"}).
+			AddRow(2, 7, "Green shakshuka", models.RecipeStatusPrivate).
+			AddRow(1, 3, "Shakshuka", models.RecipeStatusPublished))
+
+	// Act
+	ancestors, err := suite.repo.GetAncestors(5)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), ancestors, 2)
+	assert.Equal(suite.T(), 2, ancestors[0].ID)
+	assert.Equal(suite.T(), models.RecipeStatusPrivate, ancestors[0].Status)
+	assert.Equal(suite.T(), "Shakshuka", ancestors[1].Name)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *LineageRepositoryTestSuite) TestGetForks() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WITH RECURSIVE forks`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "user_id", "name", "depth", "created_at"}).
+			AddRow(4, 1, 4, "Spicy shakshuka", 1, now).
+			AddRow(3, 2, 3, "Feta shakshuka", 2, now))
+
+	// Act
+	forks, err := suite.repo.GetForks(1)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), forks, 2)
+	assert.Equal(suite.T(), 4, forks[0].RecipeID)
+	assert.Equal(suite.T(), 2, forks[1].AdaptedFromID)
+	assert.Equal(suite.T(), 2, forks[1].Depth)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestLineageRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(LineageRepositoryTestSuite))
+}
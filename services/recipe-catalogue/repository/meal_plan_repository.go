@@ -0,0 +1,491 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type MealPlanRepository interface {
+	GetByUserID(userID int) ([]models.MealPlan, error)
+	GetByUserIDInRange(userID int, from, to string) ([]models.MealPlan, error)
+	GetByID(id int) (*models.MealPlan, error)
+	Create(userID int, req models.MealPlanRequest, slug string, entries []models.MealPlanEntryRequest) (*models.MealPlan, error)
+	Update(id int, req models.MealPlanRequest) (*models.MealPlan, error)
+	Delete(id int) error
+
+	// Entries
+	AddEntry(planID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error)
+	UpdateEntry(planID, entryID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error)
+	DeleteEntry(planID, entryID int) error
+
+	// SetMembers replaces the plan's members and deals out its meals, each
+	// the IDs of one meal's entries, to the members in turn
+	SetMembers(planID int, names []string, meals [][]int) ([]models.MealPlanMember, error)
+
+	// Preferences for generated plans; GetPreferences returns sql.ErrNoRows
+	// for users who never saved any
+	GetPreferences(userID int) (*models.MealPlanPreferences, error)
+	SavePreferences(prefs models.MealPlanPreferences) (*models.MealPlanPreferences, error)
+}
+
+type mealPlanRepository struct {
+	db *database.DB
+}
+
+func NewMealPlanRepository(db *database.DB) MealPlanRepository {
+	return &mealPlanRepository{db: db}
+}
+
+const mealPlanColumns = `id, user_id, title, period_start, period_end, period_type, is_public, scale, created_at, updated_at`
+
+// mealPlanEntryOrder sorts entries by day, then through the day.
+const mealPlanEntryOrder = `planned_on, CASE slot WHEN 'breakfast' THEN 0 WHEN 'lunch' THEN 1 ELSE 2 END, id`
+
+// GetByUserID returns the user's plans, latest period first, with their
+// members and entries. Deleted plans are left out.
+func (r *mealPlanRepository) GetByUserID(userID int) ([]models.MealPlan, error) {
+	rows, err := r.db.Query(`
+		SELECT `+mealPlanColumns+`
+		FROM recipe_catalogue.meal_plans
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY period_start DESC, id DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := make([]models.MealPlan, 0)
+	for rows.Next() {
+		plan, err := scanMealPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, *plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range plans {
+		if plans[i].Members, err = r.getMembers(plans[i].ID); err != nil {
+			return nil, err
+		}
+		if plans[i].Entries, err = r.getEntries(plans[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return plans, nil
+}
+
+// GetByUserIDInRange returns the user's plans with entries between from and
+// to, inclusive, keeping only those entries. Members are left out.
+func (r *mealPlanRepository) GetByUserIDInRange(userID int, from, to string) ([]models.MealPlan, error) {
+	rows, err := r.db.Query(`
+		SELECT `+mealPlanColumns+`
+		FROM recipe_catalogue.meal_plans
+		WHERE user_id = $1 AND deleted_at IS NULL AND period_start <= $3 AND period_end >= $2
+		ORDER BY period_start, id`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := make([]models.MealPlan, 0)
+	for rows.Next() {
+		plan, err := scanMealPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, *plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	inRange := make([]models.MealPlan, 0, len(plans))
+	for _, plan := range plans {
+		if plan.Entries, err = r.getEntriesInRange(plan.ID, from, to); err != nil {
+			return nil, err
+		}
+		if len(plan.Entries) > 0 {
+			inRange = append(inRange, plan)
+		}
+	}
+	return inRange, nil
+}
+
+// GetByID returns the plan with its members and entries. Deleted plans are
+// treated as missing.
+func (r *mealPlanRepository) GetByID(id int) (*models.MealPlan, error) {
+	plan, err := scanMealPlan(r.db.QueryRow(`
+		SELECT `+mealPlanColumns+`
+		FROM recipe_catalogue.meal_plans
+		WHERE id = $1 AND deleted_at IS NULL`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.Members, err = r.getMembers(id); err != nil {
+		return nil, err
+	}
+	if plan.Entries, err = r.getEntries(id); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Create stores the plan together with its first entries, if any.
+func (r *mealPlanRepository) Create(userID int, req models.MealPlanRequest, slug string, entries []models.MealPlanEntryRequest) (*models.MealPlan, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	plan := mealPlanFromRequest(req)
+	plan.UserID = userID
+	err = tx.QueryRow(`
+		INSERT INTO recipe_catalogue.meal_plans
+			(user_id, title, period_start, period_end, period_type, is_public, slug, scale, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id, created_at, updated_at`,
+		userID, req.Title, req.PeriodStart, req.PeriodEnd, req.PeriodType, req.IsPublic, slug, req.Scale).
+		Scan(&plan.ID, &plan.CreatedAt, &plan.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		added, err := insertMealPlanEntry(tx, plan.ID, entry)
+		if err != nil {
+			return nil, err
+		}
+		plan.Entries = append(plan.Entries, *added)
+	}
+
+	return &plan, tx.Commit()
+}
+
+// Update changes the plan's details. Entries outside a shortened period are
+// dropped.
+func (r *mealPlanRepository) Update(id int, req models.MealPlanRequest) (*models.MealPlan, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	plan := mealPlanFromRequest(req)
+	plan.ID = id
+	err = tx.QueryRow(`
+		UPDATE recipe_catalogue.meal_plans
+		SET title = $2, period_start = $3, period_end = $4, period_type = $5, is_public = $6, scale = $7,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING user_id, created_at, updated_at`,
+		id, req.Title, req.PeriodStart, req.PeriodEnd, req.PeriodType, req.IsPublic, req.Scale).
+		Scan(&plan.UserID, &plan.CreatedAt, &plan.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM recipe_catalogue.meal_plan_entries
+		WHERE meal_plan_id = $1 AND (planned_on < $2 OR planned_on > $3)`, id, req.PeriodStart, req.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if plan.Members, err = r.getMembers(id); err != nil {
+		return nil, err
+	}
+	if plan.Entries, err = r.getEntries(id); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// Delete soft-deletes the plan, keeping its entries for the record.
+func (r *mealPlanRepository) Delete(id int) error {
+	result, err := r.db.Exec(`
+		UPDATE recipe_catalogue.meal_plans
+		SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *mealPlanRepository) AddEntry(planID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	entry, err := insertMealPlanEntry(tx, planID, req)
+	if err != nil {
+		return nil, err
+	}
+	return entry, tx.Commit()
+}
+
+func (r *mealPlanRepository) UpdateEntry(planID, entryID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error) {
+	result, err := r.db.Exec(`
+		UPDATE recipe_catalogue.meal_plan_entries
+		SET recipe_id = $3, planned_on = $4, slot = $5, assignee_id = $6
+		WHERE id = $1 AND meal_plan_id = $2`, entryID, planID, req.RecipeID, req.Date, req.Slot, req.AssigneeID)
+	if err != nil {
+		if database.IsUniqueViolation(err, "meal_plan_entries_unique_slot_recipe") {
+			return nil, domain.ErrMealPlanEntryExists
+		}
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &models.MealPlanEntry{ID: entryID, RecipeID: req.RecipeID, Date: req.Date, Slot: req.Slot, AssigneeID: req.AssigneeID}, nil
+}
+
+func (r *mealPlanRepository) DeleteEntry(planID, entryID int) error {
+	result, err := r.db.Exec(`
+		DELETE FROM recipe_catalogue.meal_plan_entries
+		WHERE id = $1 AND meal_plan_id = $2`, entryID, planID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetMembers numbers the members by their turn. Meals are dealt out in the
+// order given, the first to the first member; with no members every entry
+// is left unassigned.
+func (r *mealPlanRepository) SetMembers(planID int, names []string, meals [][]int) ([]models.MealPlanMember, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE recipe_catalogue.meal_plans
+		SET updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL`, planID)
+	if err != nil {
+		return nil, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec(`UPDATE recipe_catalogue.meal_plan_entries SET assignee_id = NULL WHERE meal_plan_id = $1`, planID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM recipe_catalogue.meal_plan_members WHERE meal_plan_id = $1`, planID); err != nil {
+		return nil, err
+	}
+
+	members := make([]models.MealPlanMember, 0, len(names))
+	for position, name := range names {
+		member := models.MealPlanMember{Name: name}
+		err := tx.QueryRow(`
+			INSERT INTO recipe_catalogue.meal_plan_members (meal_plan_id, position, name, created_at)
+			VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+			RETURNING id`, planID, position, name).
+			Scan(&member.ID)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	if len(members) > 0 {
+		for i, entryIDs := range meals {
+			assignee := members[i%len(members)].ID
+			for _, entryID := range entryIDs {
+				_, err := tx.Exec(`
+					UPDATE recipe_catalogue.meal_plan_entries
+					SET assignee_id = $3
+					WHERE id = $1 AND meal_plan_id = $2`, entryID, planID, assignee)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return members, tx.Commit()
+}
+
+func (r *mealPlanRepository) GetPreferences(userID int) (*models.MealPlanPreferences, error) {
+	var prefs models.MealPlanPreferences
+	err := r.db.QueryRow(`
+		SELECT user_id, weekday_max_minutes, weekend_max_minutes, elaborate_weekends, updated_at
+		FROM recipe_catalogue.meal_plan_preferences
+		WHERE user_id = $1`, userID).
+		Scan(&prefs.UserID, &prefs.WeekdayMaxMinutes, &prefs.WeekendMaxMinutes, &prefs.ElaborateWeekends, &prefs.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+func (r *mealPlanRepository) SavePreferences(prefs models.MealPlanPreferences) (*models.MealPlanPreferences, error) {
+	err := r.db.QueryRow(`
+		INSERT INTO recipe_catalogue.meal_plan_preferences (user_id, weekday_max_minutes, weekend_max_minutes,
+		                                                    elaborate_weekends, updated_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE
+		SET weekday_max_minutes = EXCLUDED.weekday_max_minutes,
+		    weekend_max_minutes = EXCLUDED.weekend_max_minutes,
+		    elaborate_weekends = EXCLUDED.elaborate_weekends,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING updated_at`,
+		prefs.UserID, prefs.WeekdayMaxMinutes, prefs.WeekendMaxMinutes, prefs.ElaborateWeekends).
+		Scan(&prefs.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+func (r *mealPlanRepository) getMembers(planID int) ([]models.MealPlanMember, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name
+		FROM recipe_catalogue.meal_plan_members
+		WHERE meal_plan_id = $1
+		ORDER BY position`, planID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]models.MealPlanMember, 0)
+	for rows.Next() {
+		var member models.MealPlanMember
+		if err := rows.Scan(&member.ID, &member.Name); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+func (r *mealPlanRepository) getEntries(planID int) ([]models.MealPlanEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT id, recipe_id, planned_on, slot, assignee_id
+		FROM recipe_catalogue.meal_plan_entries
+		WHERE meal_plan_id = $1
+		ORDER BY `+mealPlanEntryOrder, planID)
+	if err != nil {
+		return nil, err
+	}
+	return scanMealPlanEntries(rows)
+}
+
+func (r *mealPlanRepository) getEntriesInRange(planID int, from, to string) ([]models.MealPlanEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT id, recipe_id, planned_on, slot, assignee_id
+		FROM recipe_catalogue.meal_plan_entries
+		WHERE meal_plan_id = $1 AND planned_on BETWEEN $2 AND $3
+		ORDER BY `+mealPlanEntryOrder, planID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanMealPlanEntries(rows)
+}
+
+func scanMealPlanEntries(rows *sql.Rows) ([]models.MealPlanEntry, error) {
+	defer rows.Close()
+
+	entries := make([]models.MealPlanEntry, 0)
+	for rows.Next() {
+		var entry models.MealPlanEntry
+		var plannedOn time.Time
+		if err := rows.Scan(&entry.ID, &entry.RecipeID, &plannedOn, &entry.Slot, &entry.AssigneeID); err != nil {
+			return nil, err
+		}
+		entry.Date = plannedOn.Format(prepDateLayout)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func insertMealPlanEntry(tx *database.Tx, planID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error) {
+	entry := models.MealPlanEntry{RecipeID: req.RecipeID, Date: req.Date, Slot: req.Slot, AssigneeID: req.AssigneeID}
+	err := tx.QueryRow(`
+		INSERT INTO recipe_catalogue.meal_plan_entries (meal_plan_id, recipe_id, planned_on, slot, assignee_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
+		RETURNING id`, planID, req.RecipeID, req.Date, req.Slot, req.AssigneeID).
+		Scan(&entry.ID)
+	if err != nil {
+		if database.IsUniqueViolation(err, "meal_plan_entries_unique_slot_recipe") {
+			return nil, domain.ErrMealPlanEntryExists
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func scanMealPlan(scanner interface {
+	Scan(...interface{}) error
+}) (*models.MealPlan, error) {
+	var plan models.MealPlan
+	var periodStart, periodEnd time.Time
+	err := scanner.Scan(&plan.ID, &plan.UserID, &plan.Title, &periodStart, &periodEnd, &plan.PeriodType, &plan.IsPublic,
+		&plan.Scale, &plan.CreatedAt, &plan.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	plan.PeriodStart = periodStart.Format(prepDateLayout)
+	plan.PeriodEnd = periodEnd.Format(prepDateLayout)
+	plan.Members = make([]models.MealPlanMember, 0)
+	plan.Entries = make([]models.MealPlanEntry, 0)
+	return &plan, nil
+}
+
+func mealPlanFromRequest(req models.MealPlanRequest) models.MealPlan {
+	return models.MealPlan{
+		Title:       req.Title,
+		PeriodStart: req.PeriodStart,
+		PeriodEnd:   req.PeriodEnd,
+		PeriodType:  req.PeriodType,
+		IsPublic:    req.IsPublic,
+		Scale:       req.Scale,
+		Members:     make([]models.MealPlanMember, 0),
+		Entries:     make([]models.MealPlanEntry, 0),
+	}
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type MealPlanRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo MealPlanRepository
+}
+
+func (suite *MealPlanRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewMealPlanRepository(suite.db)
+}
+
+func (suite *MealPlanRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *MealPlanRepositoryTestSuite) TestGetByID_ReturnsPlanWithEntries() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.meal_plans`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "period_start", "period_end", "period_type", "is_public", "scale", "created_at", "updated_at"}).
+			AddRow(1, 7, "Week of Oct 6-12", time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC), time.Date(2025, 10, 12, 0, 0, 0, 0, time.UTC),
+				"week", false, 2.0, now, now))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.meal_plan_members`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(20, "Ann"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.meal_plan_entries`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recipe_id", "planned_on", "slot", "assignee_id"}).
+			AddRow(10, 5, time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC), "breakfast", nil).
+			AddRow(11, 4, time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC), "dinner", 20))
+
+	// Act
+	plan, err := suite.repo.GetByID(1)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2025-10-06", plan.PeriodStart)
+	assert.Equal(suite.T(), "2025-10-12", plan.PeriodEnd)
+	assert.Equal(suite.T(), 2.0, plan.Scale)
+	assert.Equal(suite.T(), []models.MealPlanMember{{ID: 20, Name: "Ann"}}, plan.Members)
+	ann := 20
+	assert.Equal(suite.T(), []models.MealPlanEntry{
+		{ID: 10, RecipeID: 5, Date: "2025-10-06", Slot: "breakfast"},
+		{ID: 11, RecipeID: 4, Date: "2025-10-06", Slot: "dinner", AssigneeID: &ann},
+	}, plan.Entries)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *MealPlanRepositoryTestSuite) TestGetByID_NotFound() {
+	// Arrange
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WHERE id = $1 AND deleted_at IS NULL`)).
+		WithArgs(99).
+		WillReturnError(sql.ErrNoRows)
+
+	// Act
+	plan, err := suite.repo.GetByID(99)
+
+	// Assert
+	assert.Nil(suite.T(), plan)
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *MealPlanRepositoryTestSuite) TestCreate_InsertsPlanAndEntries() {
+	// Arrange
+	now := time.Now()
+	req := models.MealPlanRequest{Title: "Week", PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12", PeriodType: "week", Scale: 1}
+	entries := []models.MealPlanEntryRequest{{RecipeID: 4, Date: "2025-10-06", Slot: "dinner"}}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.meal_plans`)).
+		WithArgs(7, "Week", "2025-10-06", "2025-10-12", "week", false, "week-0a1b2c3d", 1.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(3, now, now))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.meal_plan_entries`)).
+		WithArgs(3, 4, "2025-10-06", "dinner", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	suite.mock.ExpectCommit()
+
+	// Act
+	plan, err := suite.repo.Create(7, req, "week-0a1b2c3d", entries)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, plan.ID)
+	assert.Equal(suite.T(), []models.MealPlanEntry{{ID: 10, RecipeID: 4, Date: "2025-10-06", Slot: "dinner"}}, plan.Entries)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *MealPlanRepositoryTestSuite) TestAddEntry_DuplicateMeal() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.meal_plan_entries`)).
+		WithArgs(3, 4, "2025-10-06", "dinner", nil).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "meal_plan_entries_unique_slot_recipe"})
+	suite.mock.ExpectRollback()
+
+	// Act
+	entry, err := suite.repo.AddEntry(3, models.MealPlanEntryRequest{RecipeID: 4, Date: "2025-10-06", Slot: "dinner"})
+
+	// Assert
+	assert.Nil(suite.T(), entry)
+	assert.Equal(suite.T(), domain.ErrMealPlanEntryExists, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *MealPlanRepositoryTestSuite) TestUpdate_DropsEntriesOutsideThePeriod() {
+	// Arrange
+	now := time.Now()
+	req := models.MealPlanRequest{Title: "Short week", PeriodStart: "2025-10-06", PeriodEnd: "2025-10-08", PeriodType: "custom", Scale: 1}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`UPDATE recipe_catalogue.meal_plans`)).
+		WithArgs(3, "Short week", "2025-10-06", "2025-10-08", "custom", false, 1.0).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "created_at", "updated_at"}).AddRow(7, now, now))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recipe_catalogue.meal_plan_entries`)).
+		WithArgs(3, "2025-10-06", "2025-10-08").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectCommit()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.meal_plan_members`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.meal_plan_entries`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recipe_id", "planned_on", "slot", "assignee_id"}))
+
+	// Act
+	plan, err := suite.repo.Update(3, req)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 7, plan.UserID)
+	assert.Empty(suite.T(), plan.Entries)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *MealPlanRepositoryTestSuite) TestGetByUserIDInRange_KeepsPlansWithEntriesInRange() {
+	// Arrange
+	now := time.Now()
+	columns := []string{"id", "user_id", "title", "period_start", "period_end", "period_type", "is_public", "scale", "created_at", "updated_at"}
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WHERE user_id = $1 AND deleted_at IS NULL AND period_start <= $3 AND period_end >= $2`)).
+		WithArgs(7, "2025-10-11", "2025-10-14").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, 7, "Week 1", time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC), time.Date(2025, 10, 12, 0, 0, 0, 0, time.UTC), "week", false, 1.0, now, now).
+			AddRow(2, 7, "Week 2", time.Date(2025, 10, 13, 0, 0, 0, 0, time.UTC), time.Date(2025, 10, 19, 0, 0, 0, 0, time.UTC), "week", false, 2.0, now, now))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WHERE meal_plan_id = $1 AND planned_on BETWEEN $2 AND $3`)).
+		WithArgs(1, "2025-10-11", "2025-10-14").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recipe_id", "planned_on", "slot", "assignee_id"}))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WHERE meal_plan_id = $1 AND planned_on BETWEEN $2 AND $3`)).
+		WithArgs(2, "2025-10-11", "2025-10-14").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recipe_id", "planned_on", "slot", "assignee_id"}).
+			AddRow(10, 4, time.Date(2025, 10, 13, 0, 0, 0, 0, time.UTC), "dinner", nil))
+
+	// Act
+	plans, err := suite.repo.GetByUserIDInRange(7, "2025-10-11", "2025-10-14")
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), plans, 1)
+	assert.Equal(suite.T(), 2, plans[0].ID)
+	assert.Equal(suite.T(), []models.MealPlanEntry{{ID: 10, RecipeID: 4, Date: "2025-10-13", Slot: "dinner"}}, plans[0].Entries)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *MealPlanRepositoryTestSuite) TestSetMembers_DealsMealsInTurn() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE recipe_catalogue.meal_plans`)).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SET assignee_id = NULL`)).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recipe_catalogue.meal_plan_members`)).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.meal_plan_members`)).
+		WithArgs(3, 0, "Ann").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.meal_plan_members`)).
+		WithArgs(3, 1, "Bo").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(21))
+	for _, assignment := range [][2]int{{10, 20}, {11, 20}, {12, 21}, {13, 20}} {
+		suite.mock.ExpectExec(regexp.QuoteMeta(`SET assignee_id = $3`)).
+			WithArgs(assignment[0], 3, assignment[1]).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	suite.mock.ExpectCommit()
+
+	// Act
+	members, err := suite.repo.SetMembers(3, []string{"Ann", "Bo"}, [][]int{{10, 11}, {12}, {13}})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []models.MealPlanMember{{ID: 20, Name: "Ann"}, {ID: 21, Name: "Bo"}}, members)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *MealPlanRepositoryTestSuite) TestSavePreferences_Upserts() {
+	// Arrange
+	weekday := 30
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`ON CONFLICT (user_id) DO UPDATE`)).
+		WithArgs(7, &weekday, nil, true).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(now))
+
+	// Act
+	prefs, err := suite.repo.SavePreferences(models.MealPlanPreferences{UserID: 7, WeekdayMaxMinutes: &weekday, ElaborateWeekends: true})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 30, *prefs.WeekdayMaxMinutes)
+	assert.Nil(suite.T(), prefs.WeekendMaxMinutes)
+	assert.Equal(suite.T(), now, prefs.UpdatedAt)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestMealPlanRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(MealPlanRepositoryTestSuite))
+}
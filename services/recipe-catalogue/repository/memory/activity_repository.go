@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type activityRepository struct {
+	store *Store
+}
+
+func NewActivityRepository(store *Store) repository.ActivityRepository {
+	return &activityRepository{store: store}
+}
+
+func (r *activityRepository) Record(activity models.Activity) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.activities {
+		if existing.Type == activity.Type && existing.RecipeID == activity.RecipeID {
+			return nil
+		}
+	}
+
+	r.store.nextActivityID++
+	activity.ID = r.store.nextActivityID
+	activity.RecipeName = ""
+	r.store.activities[activity.ID] = activity
+	return nil
+}
+
+func (r *activityRepository) GetFeed(userID int, params models.PaginationParams) ([]models.Activity, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	feed := make([]models.Activity, 0)
+	for _, activity := range r.store.activities {
+		if _, ok := r.store.follows[followKey{followerID: userID, followeeID: activity.UserID}]; !ok {
+			continue
+		}
+		recipe, ok := r.store.recipes[activity.RecipeID]
+		if !ok || !isPublished(recipe) {
+			continue
+		}
+		activity.RecipeName = recipe.Name
+		feed = append(feed, activity)
+	}
+
+	sort.Slice(feed, func(i, j int) bool {
+		if !feed[i].CreatedAt.Equal(feed[j].CreatedAt) {
+			return feed[i].CreatedAt.After(feed[j].CreatedAt)
+		}
+		return feed[i].ID > feed[j].ID
+	})
+	return paginate(feed, params), len(feed), nil
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"meal-prep/shared/events"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityRepository_GetFeed(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, UserID: 2, Name: "Shakshuka", Status: models.RecipeStatusPublished}
+	store.recipes[2] = models.Recipe{ID: 2, UserID: 2, Name: "Dal", Status: models.RecipeStatusPublished}
+	store.recipes[3] = models.Recipe{ID: 3, UserID: 4, Name: "Ramen", Status: models.RecipeStatusPublished}
+	follows := NewFollowRepository(store)
+	activities := NewActivityRepository(store)
+
+	require.NoError(t, follows.Follow(1, 2))
+	earlier := time.Now().Add(-time.Hour)
+	require.NoError(t, activities.Record(models.Activity{UserID: 2, Type: events.RecipePublished, RecipeID: 1, CreatedAt: earlier}))
+	require.NoError(t, activities.Record(models.Activity{UserID: 2, Type: events.RecipePublished, RecipeID: 2, CreatedAt: time.Now()}))
+	require.NoError(t, activities.Record(models.Activity{UserID: 2, Type: events.RecipePublished, RecipeID: 2, CreatedAt: time.Now()})) // repeat ignored
+	require.NoError(t, activities.Record(models.Activity{UserID: 4, Type: events.RecipePublished, RecipeID: 3, CreatedAt: time.Now()})) // not followed
+
+	feed, total, err := activities.GetFeed(1, models.PaginationParams{Page: 1, PerPage: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, feed, 2)
+	assert.Equal(t, "Dal", feed[0].RecipeName)
+	assert.Equal(t, "Shakshuka", feed[1].RecipeName)
+
+	// Recipes taken private drop out of the feed
+	recipe := store.recipes[2]
+	recipe.Status = models.RecipeStatusPrivate
+	store.recipes[2] = recipe
+
+	feed, total, err = activities.GetFeed(1, models.PaginationParams{Page: 1, PerPage: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, 1, feed[0].RecipeID)
+}
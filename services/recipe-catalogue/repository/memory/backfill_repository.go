@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/repository"
+)
+
+type backfillRepository struct {
+	store *Store
+}
+
+func NewBackfillRepository(store *Store) repository.BackfillRepository {
+	return &backfillRepository{store: store}
+}
+
+func (r *backfillRepository) ListUnits() ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	units := make([]string, 0)
+	for _, ri := range r.store.recipeIngredients {
+		if !seen[ri.Unit] {
+			seen[ri.Unit] = true
+			units = append(units, ri.Unit)
+		}
+	}
+	sort.Strings(units)
+	return units, nil
+}
+
+func (r *backfillRepository) RenameUnit(from, to string) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	renamed := 0
+	changedRecipes := make(map[int]bool)
+	for id, ri := range r.store.recipeIngredients {
+		if ri.Unit != from {
+			continue
+		}
+		ri.Unit = to
+		r.store.recipeIngredients[id] = ri
+		changedRecipes[ri.RecipeID] = true
+		renamed++
+	}
+
+	for recipeID := range changedRecipes {
+		if recipe, ok := r.store.recipes[recipeID]; ok {
+			recipe.Version++
+			recipe.UpdatedAt = now()
+			r.store.recipes[recipeID] = recipe
+		}
+	}
+	return renamed, nil
+}
+
+func (r *backfillRepository) ListRecipeIDs() ([]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ids := make([]int, 0, len(r.store.recipes))
+	for id := range r.store.recipes {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// ListSearchIndexes has nothing to list: searches scan the store directly.
+func (r *backfillRepository) ListSearchIndexes() ([]string, error) {
+	return []string{}, nil
+}
+
+func (r *backfillRepository) RebuildSearchIndex(ctx context.Context, index string) error {
+	return nil
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillRepository_RenameUnitBumpsRecipeVersion(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, Version: 1}
+	store.recipes[2] = models.Recipe{ID: 2, Version: 1}
+	ingredients := NewIngredientRepository(store)
+	_, err := ingredients.AddRecipeIngredient(1, models.AddRecipeIngredientRequest{IngredientID: 4, Quantity: 200, Unit: "grams"})
+	require.NoError(t, err)
+	_, err = ingredients.AddRecipeIngredient(2, models.AddRecipeIngredientRequest{IngredientID: 4, Quantity: 1, Unit: "kg"})
+	require.NoError(t, err)
+	repo := NewBackfillRepository(store)
+
+	units, err := repo.ListUnits()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grams", "kg"}, units)
+
+	renamed, err := repo.RenameUnit("grams", "g")
+	require.NoError(t, err)
+	assert.Equal(t, 1, renamed)
+	assert.Equal(t, 2, store.recipes[1].Version)
+	assert.Equal(t, 1, store.recipes[2].Version)
+
+	units, err = repo.ListUnits()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"g", "kg"}, units)
+
+	ids, err := repo.ListRecipeIDs()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ids)
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type categoryRepository struct {
+	store *Store
+}
+
+func NewCategoryRepository(store *Store) repository.CategoryRepository {
+	return &categoryRepository{store: store}
+}
+
+func (r *categoryRepository) GetAll() ([]models.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var categories []models.Category
+	for _, category := range r.store.categories {
+		categories = append(categories, category)
+	}
+
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories, nil
+}
+
+func (r *categoryRepository) GetByID(id int) (*models.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	category, ok := r.store.categories[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) Exists(id int) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	_, ok := r.store.categories[id]
+	return ok, nil
+}
+
+func (r *categoryRepository) Create(req models.CreateCategoryRequest) (*models.Category, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextCategoryID++
+	category := models.Category{
+		ID:          r.store.nextCategoryID,
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedAt:   now(),
+		UpdatedAt:   now(),
+	}
+	r.store.categories[category.ID] = category
+
+	return &category, nil
+}
+
+func (r *categoryRepository) Update(id int, req models.UpdateCategoryRequest) (*models.Category, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	category, ok := r.store.categories[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	if req.Name != nil {
+		category.Name = *req.Name
+	}
+	if req.Description != nil {
+		category.Description = req.Description
+	}
+	category.UpdatedAt = now()
+	r.store.categories[id] = category
+
+	return &category, nil
+}
+
+func (r *categoryRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.categories[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.categories, id)
+	return nil
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"database/sql"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type cookingSessionRepository struct {
+	store *Store
+}
+
+func NewCookingSessionRepository(store *Store) repository.CookingSessionRepository {
+	return &cookingSessionRepository{store: store}
+}
+
+func (r *cookingSessionRepository) Create(session models.CookingSession) (*models.CookingSession, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	session.CurrentStep = 0
+	session.StartedAt = now()
+	session.UpdatedAt = session.StartedAt
+	session.CompletedAt = nil
+	r.store.cookingSessions[session.Token] = session
+
+	return &session, nil
+}
+
+func (r *cookingSessionRepository) GetByToken(token string) (*models.CookingSession, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	session, ok := r.store.cookingSessions[token]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &session, nil
+}
+
+func (r *cookingSessionRepository) UpdateProgress(token string, currentStep int, completed bool) (*models.CookingSession, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	session, ok := r.store.cookingSessions[token]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	session.CurrentStep = currentStep
+	session.UpdatedAt = now()
+	if completed && session.CompletedAt == nil {
+		completedAt := session.UpdatedAt
+		session.CompletedAt = &completedAt
+	}
+	r.store.cookingSessions[token] = session
+
+	return &session, nil
+}
@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCookingSessionRepository_Progress(t *testing.T) {
+	repo := NewCookingSessionRepository(NewStore())
+
+	created, err := repo.Create(models.CookingSession{Token: "token-1", UserID: 3, RecipeID: 1, Scale: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 0, created.CurrentStep)
+
+	completed, err := repo.UpdateProgress("token-1", 3, true)
+	require.NoError(t, err)
+	require.NotNil(t, completed.CompletedAt)
+
+	// Stepping back keeps the completion time
+	back, err := repo.UpdateProgress("token-1", 2, false)
+	require.NoError(t, err)
+	assert.Equal(t, completed.CompletedAt, back.CompletedAt)
+
+	_, err = repo.GetByToken("missing")
+	assert.Equal(t, sql.ErrNoRows, err)
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type costRepository struct {
+	store *Store
+}
+
+func NewCostRepository(store *Store) repository.CostRepository {
+	return &costRepository{store: store}
+}
+
+func (r *costRepository) AddPrice(ingredientID int, req models.SetIngredientPriceRequest) (*models.IngredientPrice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextPriceID++
+	price := models.IngredientPrice{
+		ID:           r.store.nextPriceID,
+		IngredientID: ingredientID,
+		Price:        req.Price,
+		Quantity:     req.Quantity,
+		Unit:         req.Unit,
+		RecordedAt:   now(),
+	}
+	r.store.prices[ingredientID] = append(r.store.prices[ingredientID], price)
+	return &price, nil
+}
+
+func (r *costRepository) GetPriceHistory(ingredientID int) ([]models.IngredientPrice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	history := r.store.prices[ingredientID]
+	prices := make([]models.IngredientPrice, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		prices = append(prices, history[i])
+	}
+	return prices, nil
+}
+
+func (r *costRepository) GetCurrentPrices(ingredientIDs []int) (map[int]models.IngredientPrice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	prices := make(map[int]models.IngredientPrice)
+	for _, id := range ingredientIDs {
+		if history := r.store.prices[id]; len(history) > 0 {
+			prices[id] = history[len(history)-1]
+		}
+	}
+	return prices, nil
+}
+
+func (r *costRepository) GetRecipeIDsUsingIngredient(ingredientID int) ([]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	seen := make(map[int]bool)
+	recipeIDs := make([]int, 0)
+	for _, ri := range r.store.recipeIngredients {
+		if ri.IngredientID == ingredientID && !seen[ri.RecipeID] {
+			seen[ri.RecipeID] = true
+			recipeIDs = append(recipeIDs, ri.RecipeID)
+		}
+	}
+	sort.Ints(recipeIDs)
+	return recipeIDs, nil
+}
+
+func (r *costRepository) RecordSnapshots(snapshots []models.RecipeCostSnapshot) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	recordedAt := now()
+	for _, snapshot := range snapshots {
+		snapshot.RecordedAt = recordedAt
+		r.store.costSnapshots[snapshot.RecipeID] = append(r.store.costSnapshots[snapshot.RecipeID], snapshot)
+	}
+	return nil
+}
+
+func (r *costRepository) GetSnapshots(recipeID int) ([]models.RecipeCostSnapshot, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return append(make([]models.RecipeCostSnapshot, 0), r.store.costSnapshots[recipeID]...), nil
+}
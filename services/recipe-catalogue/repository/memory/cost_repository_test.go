@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostRepository_PriceHistoryAndCurrentPrice(t *testing.T) {
+	store := NewStore()
+	repo := NewCostRepository(store)
+
+	_, err := repo.AddPrice(4, models.SetIngredientPriceRequest{Price: 1.29, Quantity: 400, Unit: "g"})
+	require.NoError(t, err)
+	_, err = repo.AddPrice(4, models.SetIngredientPriceRequest{Price: 1.49, Quantity: 400, Unit: "g"})
+	require.NoError(t, err)
+
+	history, err := repo.GetPriceHistory(4)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, 1.49, history[0].Price)
+
+	current, err := repo.GetCurrentPrices([]int{4, 5})
+	require.NoError(t, err)
+	require.Len(t, current, 1)
+	assert.Equal(t, 1.49, current[4].Price)
+}
+
+func TestCostRepository_SnapshotsGoWithTheirRecipe(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, UserID: 1, Name: "Dal"}
+	store.recipeIngredients[1] = models.RecipeIngredient{ID: 1, RecipeID: 1, IngredientID: 4, Quantity: 200, Unit: "g"}
+	repo := NewCostRepository(store)
+
+	recipeIDs, err := repo.GetRecipeIDsUsingIngredient(4)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, recipeIDs)
+
+	require.NoError(t, repo.RecordSnapshots([]models.RecipeCostSnapshot{
+		{RecipeID: 1, Cost: 0.65, PricedIngredients: 1, TotalIngredients: 1},
+	}))
+	snapshots, err := repo.GetSnapshots(1)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.False(t, snapshots[0].RecordedAt.IsZero())
+
+	require.NoError(t, NewRecipeRepository(store).Delete(1))
+	snapshots, err = repo.GetSnapshots(1)
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type favoriteRepository struct {
+	store *Store
+}
+
+func NewFavoriteRepository(store *Store) repository.FavoriteRepository {
+	return &favoriteRepository{store: store}
+}
+
+func (r *favoriteRepository) Add(userID, recipeID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := favoriteKey{userID: userID, recipeID: recipeID}
+	if _, ok := r.store.favorites[key]; !ok {
+		r.store.favorites[key] = now()
+	}
+	return nil
+}
+
+func (r *favoriteRepository) Remove(userID, recipeID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := favoriteKey{userID: userID, recipeID: recipeID}
+	if _, ok := r.store.favorites[key]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.favorites, key)
+	return nil
+}
+
+func (r *favoriteRepository) GetFavorites(userID int, params models.PaginationParams) ([]models.Recipe, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	favoritedAt := make(map[int]time.Time)
+	for key, at := range r.store.favorites {
+		if key.userID == userID {
+			favoritedAt[key.recipeID] = at
+		}
+	}
+	recipes := r.store.recipesWhere(func(recipe models.Recipe) bool {
+		_, ok := favoritedAt[recipe.ID]
+		return ok && (isPublished(recipe) || recipe.Status == models.RecipeStatusArchived || recipe.UserID == userID)
+	})
+
+	// Most recently favorited first, like the SQL repository
+	sort.SliceStable(recipes, func(i, j int) bool {
+		a, b := favoritedAt[recipes[i].ID], favoritedAt[recipes[j].ID]
+		if a.Equal(b) {
+			return recipes[i].ID > recipes[j].ID
+		}
+		return a.After(b)
+	})
+	return paginate(recipes, params), len(recipes), nil
+}
+
+func (r *favoriteRepository) CountByRecipeIDs(recipeIDs []int) (map[int]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[int]bool, len(recipeIDs))
+	for _, id := range recipeIDs {
+		wanted[id] = true
+	}
+
+	counts := make(map[int]int)
+	for key := range r.store.favorites {
+		if wanted[key.recipeID] {
+			counts[key.recipeID]++
+		}
+	}
+	return counts, nil
+}
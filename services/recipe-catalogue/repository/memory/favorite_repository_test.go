@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFavoriteRepository_AddAndRemove(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, UserID: 2, Name: "Shakshuka", Status: models.RecipeStatusPublished}
+	store.recipes[2] = models.Recipe{ID: 2, UserID: 2, Name: "Dal", Status: models.RecipeStatusPublished}
+	repo := NewFavoriteRepository(store)
+
+	require.NoError(t, repo.Add(1, 1))
+	require.NoError(t, repo.Add(1, 1)) // idempotent
+	require.NoError(t, repo.Add(3, 1))
+	require.NoError(t, repo.Add(1, 2))
+
+	counts, err := repo.CountByRecipeIDs([]int{1, 2, 5})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 2, 2: 1}, counts)
+
+	require.NoError(t, repo.Remove(1, 1))
+	assert.Equal(t, sql.ErrNoRows, repo.Remove(1, 1))
+}
+
+func TestFavoriteRepository_GetFavorites(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, UserID: 2, Name: "Shakshuka", Status: models.RecipeStatusPublished}
+	store.recipes[2] = models.Recipe{ID: 2, UserID: 2, Name: "Dal", Status: models.RecipeStatusDraft}
+	store.recipes[3] = models.Recipe{ID: 3, UserID: 1, Name: "Ramen", Status: models.RecipeStatusPrivate}
+	store.recipes[4] = models.Recipe{ID: 4, UserID: 2, Name: "Pho", Status: models.RecipeStatusArchived}
+	repo := NewFavoriteRepository(store)
+
+	for _, recipeID := range []int{1, 2, 3, 4} {
+		require.NoError(t, repo.Add(1, recipeID))
+	}
+
+	// Someone else's draft drops out; the user's own private recipe and an
+	// archived one stay
+	recipes, total, err := repo.GetFavorites(1, models.PaginationParams{Page: 1, PerPage: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, recipes, 3)
+	assert.Equal(t, 4, recipes[0].ID)
+	assert.Equal(t, 3, recipes[1].ID)
+	assert.Equal(t, 1, recipes[2].ID)
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type followRepository struct {
+	store *Store
+}
+
+func NewFollowRepository(store *Store) repository.FollowRepository {
+	return &followRepository{store: store}
+}
+
+func (r *followRepository) Follow(followerID, followeeID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := followKey{followerID: followerID, followeeID: followeeID}
+	if _, ok := r.store.follows[key]; !ok {
+		r.store.follows[key] = now()
+	}
+	return nil
+}
+
+func (r *followRepository) Unfollow(followerID, followeeID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := followKey{followerID: followerID, followeeID: followeeID}
+	if _, ok := r.store.follows[key]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.follows, key)
+	return nil
+}
+
+func (r *followRepository) GetFollowing(userID int) ([]models.Follow, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	follows := make([]models.Follow, 0)
+	for key, followedAt := range r.store.follows {
+		if key.followerID == userID {
+			follows = append(follows, models.Follow{UserID: key.followeeID, FollowedAt: followedAt})
+		}
+	}
+	sortFollows(follows)
+	return follows, nil
+}
+
+func (r *followRepository) GetFollowers(userID int) ([]models.Follow, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	follows := make([]models.Follow, 0)
+	for key, followedAt := range r.store.follows {
+		if key.followeeID == userID {
+			follows = append(follows, models.Follow{UserID: key.followerID, FollowedAt: followedAt})
+		}
+	}
+	sortFollows(follows)
+	return follows, nil
+}
+
+// sortFollows orders follows newest first, like the SQL repository.
+func sortFollows(follows []models.Follow) {
+	sort.Slice(follows, func(i, j int) bool {
+		if !follows[i].FollowedAt.Equal(follows[j].FollowedAt) {
+			return follows[i].FollowedAt.After(follows[j].FollowedAt)
+		}
+		return follows[i].UserID < follows[j].UserID
+	})
+}
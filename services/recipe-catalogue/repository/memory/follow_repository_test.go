@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowRepository_FollowAndUnfollow(t *testing.T) {
+	repo := NewFollowRepository(NewStore())
+
+	require.NoError(t, repo.Follow(1, 2))
+	require.NoError(t, repo.Follow(1, 2)) // idempotent
+	require.NoError(t, repo.Follow(3, 2))
+
+	following, err := repo.GetFollowing(1)
+	require.NoError(t, err)
+	require.Len(t, following, 1)
+	assert.Equal(t, 2, following[0].UserID)
+
+	followers, err := repo.GetFollowers(2)
+	require.NoError(t, err)
+	assert.Len(t, followers, 2)
+
+	require.NoError(t, repo.Unfollow(1, 2))
+	assert.Equal(t, sql.ErrNoRows, repo.Unfollow(1, 2))
+
+	following, err = repo.GetFollowing(1)
+	require.NoError(t, err)
+	assert.Empty(t, following)
+}
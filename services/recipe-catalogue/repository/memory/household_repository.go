@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"database/sql"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type householdRepository struct {
+	store *Store
+}
+
+func NewHouseholdRepository(store *Store) repository.HouseholdRepository {
+	return &householdRepository{store: store}
+}
+
+func (r *householdRepository) Create(ownerID int, name, inviteCode string) (*models.Household, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.householdOf(ownerID) != 0 {
+		return nil, domain.ErrAlreadyInHousehold
+	}
+
+	// The household's pantry starts as a copy of the owner's
+	items := make(map[int]pantryEntry)
+	if own := r.store.pantryOf(ownerID); own != nil {
+		for ingredientID, entry := range own.items {
+			items[ingredientID] = entry
+		}
+	}
+
+	r.store.nextHouseholdID++
+	createdAt := now()
+	household := models.Household{
+		ID:         r.store.nextHouseholdID,
+		Name:       name,
+		OwnerID:    ownerID,
+		InviteCode: inviteCode,
+		Members:    []models.HouseholdMember{{UserID: ownerID, JoinedAt: createdAt}},
+		CreatedAt:  createdAt,
+	}
+	r.store.households[household.ID] = household
+
+	r.store.nextPantryID++
+	r.store.pantries[r.store.nextPantryID] = &pantry{householdID: household.ID, items: items}
+	return copyHousehold(household), nil
+}
+
+func (r *householdRepository) GetByMember(userID int) (*models.Household, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	household, ok := r.store.households[r.store.householdOf(userID)]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return copyHousehold(household), nil
+}
+
+func (r *householdRepository) GetByInviteCode(inviteCode string) (*models.Household, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, household := range r.store.households {
+		if household.InviteCode == inviteCode {
+			return copyHousehold(household), nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *householdRepository) GetHouseholdIDs(userID int) ([]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	householdIDs := make([]int, 0, 1)
+	if householdID := r.store.householdOf(userID); householdID != 0 {
+		householdIDs = append(householdIDs, householdID)
+	}
+	return householdIDs, nil
+}
+
+func (r *householdRepository) AddMember(householdID, userID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.householdOf(userID) != 0 {
+		return domain.ErrAlreadyInHousehold
+	}
+	household, ok := r.store.households[householdID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	household.Members = append(household.Members, models.HouseholdMember{UserID: userID, JoinedAt: now()})
+	r.store.households[householdID] = household
+	return nil
+}
+
+func (r *householdRepository) RemoveMember(householdID, userID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	household, ok := r.store.households[householdID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	members := make([]models.HouseholdMember, 0, len(household.Members))
+	for _, member := range household.Members {
+		if member.UserID != userID {
+			members = append(members, member)
+		}
+	}
+	if len(members) == len(household.Members) {
+		return sql.ErrNoRows
+	}
+	household.Members = members
+	r.store.households[householdID] = household
+	return nil
+}
+
+func (r *householdRepository) Delete(householdID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.households, householdID)
+	for id, pantry := range r.store.pantries {
+		if pantry.householdID == householdID {
+			delete(r.store.pantries, id)
+		}
+	}
+	return nil
+}
+
+// householdOf returns the ID of the household userID belongs to, or 0.
+// Callers must hold mu.
+func (s *Store) householdOf(userID int) int {
+	for id, household := range s.households {
+		for _, member := range household.Members {
+			if member.UserID == userID {
+				return id
+			}
+		}
+	}
+	return 0
+}
+
+func copyHousehold(household models.Household) *models.Household {
+	household.Members = append([]models.HouseholdMember{}, household.Members...)
+	return &household
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHouseholdRepository_MembersShareAPantry(t *testing.T) {
+	store := NewStore()
+	store.ingredients[1] = models.Ingredient{ID: 1, Name: "Onion"}
+	store.ingredients[2] = models.Ingredient{ID: 2, Name: "Garlic"}
+	store.ingredients[3] = models.Ingredient{ID: 3, Name: "Rice"}
+	pantries := NewPantryRepository(store)
+	repo := NewHouseholdRepository(store)
+	_, err := pantries.SetPantry(7, []int{1}, 0)
+	require.NoError(t, err)
+	_, err = pantries.SetPantry(8, []int{2}, 0)
+	require.NoError(t, err)
+
+	household, err := repo.Create(7, "Flat 4", "code")
+	require.NoError(t, err)
+	require.NoError(t, repo.AddMember(household.ID, 8))
+	_, err = pantries.EditPantry(8, []int{3}, nil)
+	require.NoError(t, err)
+
+	// Both see the owner's items plus what the new member added
+	for _, userID := range []int{7, 8} {
+		pantry, err := pantries.GetPantry(userID)
+		require.NoError(t, err)
+		require.NotNil(t, pantry.HouseholdID)
+		assert.Equal(t, household.ID, *pantry.HouseholdID)
+		require.Len(t, pantry.Items, 2)
+		assert.Equal(t, "Onion", pantry.Items[0].Name)
+		assert.Equal(t, 8, pantry.Items[1].AddedBy)
+	}
+
+	// Leaving gives the member their own pantry back
+	require.NoError(t, repo.RemoveMember(household.ID, 8))
+	own, err := pantries.GetPantry(8)
+	require.NoError(t, err)
+	assert.Nil(t, own.HouseholdID)
+	require.Len(t, own.Items, 1)
+	assert.Equal(t, "Garlic", own.Items[0].Name)
+}
+
+func TestHouseholdRepository_OneHouseholdPerUser(t *testing.T) {
+	store := NewStore()
+	repo := NewHouseholdRepository(store)
+	first, err := repo.Create(7, "Flat 4", "first")
+	require.NoError(t, err)
+	second, err := repo.Create(8, "Cabin", "second")
+	require.NoError(t, err)
+
+	_, err = repo.Create(7, "Another", "third")
+	assert.Equal(t, domain.ErrAlreadyInHousehold, err)
+	assert.Equal(t, domain.ErrAlreadyInHousehold, repo.AddMember(second.ID, 7))
+
+	found, err := repo.GetByInviteCode("first")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID)
+	assert.Equal(t, sql.ErrNoRows, repo.RemoveMember(first.ID, 8))
+}
+
+func TestHouseholdRepository_DeleteRemovesPantry(t *testing.T) {
+	store := NewStore()
+	store.ingredients[1] = models.Ingredient{ID: 1, Name: "Onion"}
+	repo := NewHouseholdRepository(store)
+	household, err := repo.Create(7, "Flat 4", "code")
+	require.NoError(t, err)
+	_, err = NewPantryRepository(store).EditPantry(7, []int{1}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.RemoveMember(household.ID, 7))
+	require.NoError(t, repo.Delete(household.ID))
+
+	_, err = repo.GetByMember(7)
+	assert.Equal(t, sql.ErrNoRows, err)
+	pantry, err := NewPantryRepository(store).GetPantry(7)
+	require.NoError(t, err)
+	assert.Empty(t, pantry.Items)
+	assert.Len(t, store.pantries, 0)
+}
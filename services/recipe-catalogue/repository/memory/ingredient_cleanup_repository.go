@@ -0,0 +1,323 @@
+package memory
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type ingredientCleanupRepository struct {
+	store *Store
+}
+
+func NewIngredientCleanupRepository(store *Store) repository.IngredientCleanupRepository {
+	return &ingredientCleanupRepository{store: store}
+}
+
+func (r *ingredientCleanupRepository) ListUnused(unusedSince time.Time, params models.PaginationParams) ([]models.UnusedIngredient, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	unused := make([]models.UnusedIngredient, 0)
+	for _, id := range r.unusedIDs(nil, unusedSince) {
+		ingredient := models.UnusedIngredient{Ingredient: r.store.ingredients[id]}
+		if usedAt, ok := r.store.ingredientsUsedAt[id]; ok {
+			ingredient.LastUsedAt = &usedAt
+		}
+		if archivedAt, ok := r.store.archived[id]; ok {
+			ingredient.ArchivedAt = &archivedAt
+		}
+		unused = append(unused, ingredient)
+	}
+
+	sort.Slice(unused, func(i, j int) bool {
+		ti, tj := r.lastUsed(unused[i].ID), r.lastUsed(unused[j].ID)
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return unused[i].ID < unused[j].ID
+	})
+	return paginate(unused, params), len(unused), nil
+}
+
+func (r *ingredientCleanupRepository) Archive(ids []int, unusedSince time.Time) ([]int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	archived := make([]int, 0)
+	for _, id := range r.unusedIDs(ids, unusedSince) {
+		if _, ok := r.store.archived[id]; !ok {
+			r.store.archived[id] = now()
+			archived = append(archived, id)
+		}
+	}
+	return archived, nil
+}
+
+func (r *ingredientCleanupRepository) Delete(ids []int, unusedSince time.Time) ([]int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	deleted := r.unusedIDs(ids, unusedSince)
+	for _, id := range deleted {
+		r.store.deleteIngredient(id)
+	}
+	return deleted, nil
+}
+
+func (r *ingredientCleanupRepository) ListDuplicates(minSimilarity float64, limit int) ([]models.DuplicateIngredients, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ingredients := make([]models.Ingredient, 0, len(r.store.ingredients))
+	for id, ingredient := range r.store.ingredients {
+		if _, archived := r.store.archived[id]; !archived {
+			ingredients = append(ingredients, ingredient)
+		}
+	}
+	return repository.RankDuplicates(ingredients, minSimilarity, limit), nil
+}
+
+// Merge mirrors the SQL repository: rows the target already has an equal of
+// stay with the duplicate and go when it is deleted.
+func (r *ingredientCleanupRepository) Merge(duplicateID, targetID int) (*models.IngredientMergeResult, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	target, ok := s.ingredients[targetID]
+	if _, found := s.ingredients[duplicateID]; !found || !ok {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	targetLines := make(map[int]int) // recipe ID -> the target's line in it
+	for id, ri := range s.recipeIngredients {
+		if ri.IngredientID == targetID {
+			targetLines[ri.RecipeID] = id
+		}
+	}
+	recipeIDs := make([]int, 0)
+	for id, ri := range s.recipeIngredients {
+		if ri.IngredientID != duplicateID {
+			continue
+		}
+		recipeIDs = append(recipeIDs, ri.RecipeID)
+		if keptID, ok := targetLines[ri.RecipeID]; ok {
+			if kept := s.recipeIngredients[keptID]; kept.Unit == ri.Unit {
+				kept.Quantity += ri.Quantity
+				s.recipeIngredients[keptID] = kept
+			}
+			delete(s.recipeIngredients, id)
+			continue
+		}
+		ri.IngredientID = targetID
+		s.recipeIngredients[id] = ri
+	}
+	sort.Ints(recipeIDs)
+	for _, recipeID := range recipeIDs {
+		if recipe, ok := s.recipes[recipeID]; ok {
+			recipe.Version++
+			recipe.UpdatedAt = now()
+			s.recipes[recipeID] = recipe
+		}
+	}
+	if len(recipeIDs) > 0 {
+		delete(s.archived, targetID)
+	}
+
+	for recipeID, steps := range s.recipeSteps {
+		for i := range steps {
+			steps[i].IngredientIDs = replaceIngredientID(steps[i].IngredientIDs, duplicateID, targetID)
+		}
+		s.recipeSteps[recipeID] = steps
+	}
+	for searchID, search := range s.savedSearches {
+		search.IngredientIDs = replaceIngredientID(search.IngredientIDs, duplicateID, targetID)
+		s.savedSearches[searchID] = search
+	}
+	for _, pantry := range s.pantries {
+		if entry, ok := pantry.items[duplicateID]; ok {
+			if _, exists := pantry.items[targetID]; !exists {
+				pantry.items[targetID] = entry
+			}
+			delete(pantry.items, duplicateID)
+		}
+	}
+
+	for locale, name := range s.translations[duplicateID] {
+		if _, ok := s.translations[targetID][locale]; !ok {
+			if s.translations[targetID] == nil {
+				s.translations[targetID] = make(map[string]string)
+			}
+			s.translations[targetID][locale] = name
+		}
+	}
+	r.mergeProducts(duplicateID, targetID)
+	for _, packSize := range s.packSizes[duplicateID] {
+		if !hasPackSize(s.packSizes[targetID], packSize) {
+			packSize.IngredientID = targetID
+			s.packSizes[targetID] = append(s.packSizes[targetID], packSize)
+		}
+	}
+	if prices := s.prices[duplicateID]; len(prices) > 0 {
+		merged := append(s.prices[targetID], prices...)
+		for i := range merged {
+			merged[i].IngredientID = targetID
+		}
+		sort.SliceStable(merged, func(i, j int) bool { return merged[i].RecordedAt.Before(merged[j].RecordedAt) })
+		s.prices[targetID] = merged
+	}
+	if density, ok := s.densities[duplicateID]; ok {
+		if _, exists := s.densities[targetID]; !exists {
+			density.IngredientID = targetID
+			s.densities[targetID] = density
+		}
+	}
+	if facts, ok := s.dietaryFacts[duplicateID]; ok {
+		merged := s.dietaryFacts[targetID]
+		for _, flag := range facts.Flags {
+			if !slices.Contains(merged.Flags, flag) {
+				merged.Flags = append(merged.Flags, flag)
+			}
+		}
+		if merged.Override == "" {
+			merged.Override = facts.Override
+		}
+		s.dietaryFacts[targetID] = merged
+	}
+	for allergenID := range s.ingredientAllergens[duplicateID] {
+		if s.ingredientAllergens[targetID] == nil {
+			s.ingredientAllergens[targetID] = make(map[int]bool)
+		}
+		s.ingredientAllergens[targetID][allergenID] = true
+	}
+	r.mergeSubstitutions(duplicateID, targetID)
+
+	s.deleteIngredient(duplicateID)
+	return &models.IngredientMergeResult{Ingredient: target, MergedID: duplicateID, RecipeIDs: recipeIDs}, nil
+}
+
+// mergeProducts moves the duplicate's products to the target. Recipes naming
+// one the target has an equal of name the target's. Callers must hold mu.
+func (r *ingredientCleanupRepository) mergeProducts(duplicateID, targetID int) {
+	s := r.store
+	for productID, product := range s.products {
+		if product.IngredientID != duplicateID {
+			continue
+		}
+		keptID := 0
+		for otherID, other := range s.products {
+			if other.IngredientID == targetID && other.Brand == product.Brand && other.Name == product.Name {
+				keptID = otherID
+			}
+		}
+		if keptID == 0 {
+			product.IngredientID = targetID
+			s.products[productID] = product
+			continue
+		}
+		for riID, ri := range s.recipeIngredients {
+			if ri.ProductID != nil && *ri.ProductID == productID {
+				ri.ProductID = &keptID
+				s.recipeIngredients[riID] = ri
+			}
+		}
+	}
+}
+
+// mergeSubstitutions moves substitutions from and to the duplicate onto the
+// target, dropping the two standing in for each other. Callers must hold mu.
+func (r *ingredientCleanupRepository) mergeSubstitutions(duplicateID, targetID int) {
+	s := r.store
+	substitutes := func(ingredientID, substituteID int) bool {
+		for _, substitution := range s.substitutions[ingredientID] {
+			if substitution.SubstituteID == substituteID {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, substitution := range s.substitutions[duplicateID] {
+		if substitution.SubstituteID != targetID && !substitutes(targetID, substitution.SubstituteID) {
+			s.substitutions[targetID] = append(s.substitutions[targetID], substitution)
+		}
+	}
+	for ingredientID, substitutions := range s.substitutions {
+		if ingredientID == duplicateID {
+			continue
+		}
+		kept := make([]models.SubstitutionRequest, 0, len(substitutions))
+		for _, substitution := range substitutions {
+			if substitution.SubstituteID == duplicateID {
+				if ingredientID == targetID || substitutes(ingredientID, targetID) {
+					continue
+				}
+				substitution.SubstituteID = targetID
+			}
+			kept = append(kept, substitution)
+		}
+		s.substitutions[ingredientID] = kept
+	}
+}
+
+func replaceIngredientID(ids []int, from, to int) []int {
+	replaced := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id == from {
+			id = to
+		}
+		if !slices.Contains(replaced, id) {
+			replaced = append(replaced, id)
+		}
+	}
+	return replaced
+}
+
+func hasPackSize(packSizes []models.PackSize, packSize models.PackSize) bool {
+	for _, existing := range packSizes {
+		if existing.Quantity == packSize.Quantity && existing.Unit == packSize.Unit {
+			return true
+		}
+	}
+	return false
+}
+
+// unusedIDs returns, by ID, the ingredients among ids (all of them when ids is
+// nil) that no recipe has used since unusedSince. Callers must hold mu.
+func (r *ingredientCleanupRepository) unusedIDs(ids []int, unusedSince time.Time) []int {
+	used := make(map[int]bool)
+	for _, ri := range r.store.recipeIngredients {
+		used[ri.IngredientID] = true
+	}
+
+	var wanted map[int]bool
+	if ids != nil {
+		wanted = make(map[int]bool, len(ids))
+		for _, id := range ids {
+			wanted[id] = true
+		}
+	}
+
+	unused := make([]int, 0)
+	for id := range r.store.ingredients {
+		if used[id] || (wanted != nil && !wanted[id]) || !r.lastUsed(id).Before(unusedSince) {
+			continue
+		}
+		unused = append(unused, id)
+	}
+	sort.Ints(unused)
+	return unused
+}
+
+// lastUsed mirrors COALESCE(last_used_at, created_at). Callers must hold mu.
+func (r *ingredientCleanupRepository) lastUsed(id int) time.Time {
+	if usedAt, ok := r.store.ingredientsUsedAt[id]; ok {
+		return usedAt
+	}
+	return r.store.ingredients[id].CreatedAt
+}
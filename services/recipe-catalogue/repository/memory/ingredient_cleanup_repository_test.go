@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngredientCleanupRepository_ArchiveHidesAndReuseRestores(t *testing.T) {
+	store := NewStore()
+	ingredients := NewIngredientRepository(store)
+	repo := NewIngredientCleanupRepository(store)
+
+	saffron, err := ingredients.CreateIngredient(models.CreateIngredientRequest{Name: "Saffron"})
+	require.NoError(t, err)
+	salt, err := ingredients.CreateIngredient(models.CreateIngredientRequest{Name: "Salt"})
+	require.NoError(t, err)
+	_, err = ingredients.AddRecipeIngredient(1, models.AddRecipeIngredientRequest{IngredientID: salt.ID, Quantity: 1, Unit: "tsp"})
+	require.NoError(t, err)
+
+	unusedSince := time.Now().Add(time.Hour)
+	unused, total, err := repo.ListUnused(unusedSince, models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, saffron.ID, unused[0].ID)
+	assert.Nil(t, unused[0].LastUsedAt)
+
+	archived, err := repo.Archive([]int{saffron.ID, salt.ID}, unusedSince)
+	require.NoError(t, err)
+	assert.Equal(t, []int{saffron.ID}, archived)
+
+	// Archiving twice is a no-op
+	archived, err = repo.Archive(nil, unusedSince)
+	require.NoError(t, err)
+	assert.Empty(t, archived)
+
+	listed, _, err := ingredients.SearchIngredients("sa", models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "Salt", listed[0].Name)
+	_, err = ingredients.GetIngredientByID(saffron.ID)
+	assert.NoError(t, err, "archived ingredients can still be fetched by ID")
+
+	_, err = ingredients.AddRecipeIngredient(1, models.AddRecipeIngredientRequest{IngredientID: saffron.ID, Quantity: 1, Unit: "pinch"})
+	require.NoError(t, err)
+	_, total, err = ingredients.GetAllIngredients(models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+}
+
+func TestIngredientCleanupRepository_DeleteCountsFromLastUse(t *testing.T) {
+	store := NewStore()
+	ingredients := NewIngredientRepository(store)
+	repo := NewIngredientCleanupRepository(store)
+
+	saffron, err := ingredients.CreateIngredient(models.CreateIngredientRequest{Name: "Saffron"})
+	require.NoError(t, err)
+	_, err = ingredients.AddRecipeIngredient(1, models.AddRecipeIngredientRequest{IngredientID: saffron.ID, Quantity: 1, Unit: "pinch"})
+	require.NoError(t, err)
+
+	// Still used
+	deleted, err := repo.Delete([]int{saffron.ID}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	removedAt := time.Now()
+	require.NoError(t, ingredients.RemoveRecipeIngredient(1, saffron.ID))
+
+	// Removed just now, so not unused for long enough
+	deleted, err = repo.Delete([]int{saffron.ID}, removedAt.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	unused, _, err := repo.ListUnused(time.Now().Add(time.Hour), models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	require.Len(t, unused, 1)
+	require.NotNil(t, unused[0].LastUsedAt)
+	assert.False(t, unused[0].LastUsedAt.Before(removedAt.UTC()))
+
+	deleted, err = repo.Delete([]int{saffron.ID}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []int{saffron.ID}, deleted)
+	exists, err := ingredients.IngredientExists(saffron.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestIngredientCleanupRepository_MergeMovesRecipesAndAliases(t *testing.T) {
+	store := NewStore()
+	ingredients := NewIngredientRepository(store)
+	repo := NewIngredientCleanupRepository(store)
+
+	egg, err := ingredients.CreateIngredient(models.CreateIngredientRequest{Name: "Egg"})
+	require.NoError(t, err)
+	eggs, err := ingredients.CreateIngredient(models.CreateIngredientRequest{Name: "Eggs"})
+	require.NoError(t, err)
+	tofu, err := ingredients.CreateIngredient(models.CreateIngredientRequest{Name: "Silken Tofu"})
+	require.NoError(t, err)
+
+	_, err = ingredients.AddRecipeIngredient(1, models.AddRecipeIngredientRequest{IngredientID: eggs.ID, Quantity: 2, Unit: "piece"})
+	require.NoError(t, err)
+	_, err = ingredients.AddRecipeIngredient(2, models.AddRecipeIngredientRequest{IngredientID: egg.ID, Quantity: 1, Unit: "piece"})
+	require.NoError(t, err)
+	_, err = ingredients.AddRecipeIngredient(2, models.AddRecipeIngredientRequest{IngredientID: eggs.ID, Quantity: 2, Unit: "piece"})
+	require.NoError(t, err)
+	require.NoError(t, ingredients.SetTranslations(egg.ID, []models.IngredientTranslation{{Locale: "de", Name: "Ei"}}))
+	require.NoError(t, ingredients.SetTranslations(eggs.ID, []models.IngredientTranslation{
+		{Locale: "de", Name: "Eier"}, {Locale: "es", Name: "Huevos"},
+	}))
+	require.NoError(t, ingredients.SetSubstitutions(tofu.ID, []models.SubstitutionRequest{{SubstituteID: eggs.ID, Ratio: 1}}))
+
+	duplicates, err := repo.ListDuplicates(0.5, 10)
+	require.NoError(t, err)
+	require.Len(t, duplicates, 1)
+	assert.Equal(t, egg.ID, duplicates[0].Ingredient.ID)
+	assert.Equal(t, eggs.ID, duplicates[0].Duplicate.ID)
+
+	result, err := repo.Merge(eggs.ID, egg.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Egg", result.Ingredient.Name)
+	assert.Equal(t, []int{1, 2}, result.RecipeIDs)
+
+	lines, err := ingredients.GetRecipeIngredients(1)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, egg.ID, lines[0].IngredientID)
+	lines, err = ingredients.GetRecipeIngredients(2)
+	require.NoError(t, err)
+	require.Len(t, lines, 1, "a recipe using both keeps one line")
+	assert.Equal(t, 3.0, lines[0].Quantity)
+
+	translations, err := ingredients.GetTranslations(egg.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []models.IngredientTranslation{{Locale: "de", Name: "Ei"}, {Locale: "es", Name: "Huevos"}}, translations)
+	substitutions, err := ingredients.GetSubstitutions(tofu.ID)
+	require.NoError(t, err)
+	require.Len(t, substitutions, 1)
+	assert.Equal(t, egg.ID, substitutions[0].SubstituteID)
+
+	exists, err := ingredients.IngredientExists(eggs.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	_, err = repo.Merge(eggs.ID, egg.ID)
+	assert.Equal(t, domain.ErrIngredientNotFound, err)
+}
@@ -0,0 +1,819 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type ingredientRepository struct {
+	store *Store
+}
+
+func NewIngredientRepository(store *Store) repository.IngredientRepository {
+	return &ingredientRepository{store: store}
+}
+
+func (r *ingredientRepository) GetAllIngredients(params models.PaginationParams) ([]models.Ingredient, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ingredients := r.ingredientsWhere(func(models.Ingredient) bool { return true })
+
+	// Same ordering as ORDER BY category, name: NULL categories sort last.
+	sort.SliceStable(ingredients, func(i, j int) bool {
+		ci, cj := ingredients[i].Category, ingredients[j].Category
+		switch {
+		case ci == nil && cj == nil:
+			return false
+		case ci == nil:
+			return false
+		case cj == nil:
+			return true
+		default:
+			return *ci < *cj
+		}
+	})
+
+	return paginate(ingredients, params), len(ingredients), nil
+}
+
+func (r *ingredientRepository) GetIngredientByID(id int) (*models.Ingredient, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ingredient, ok := r.store.ingredients[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &ingredient, nil
+}
+
+func (r *ingredientRepository) GetIngredientsByCategory(category string, params models.PaginationParams) ([]models.Ingredient, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ingredients := r.ingredientsWhere(func(i models.Ingredient) bool {
+		return i.Category != nil && *i.Category == category
+	})
+
+	return paginate(ingredients, params), len(ingredients), nil
+}
+
+func (r *ingredientRepository) SearchIngredients(query string, params models.PaginationParams) ([]models.Ingredient, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	needle := strings.ToLower(query)
+	ingredients := r.ingredientsWhere(func(i models.Ingredient) bool {
+		if strings.Contains(strings.ToLower(i.Name), needle) {
+			return true
+		}
+		for _, name := range r.store.translations[i.ID] {
+			if strings.Contains(strings.ToLower(name), needle) {
+				return true
+			}
+		}
+		return i.Description != nil && strings.Contains(strings.ToLower(*i.Description), needle)
+	})
+
+	return paginate(ingredients, params), len(ingredients), nil
+}
+
+func (r *ingredientRepository) SuggestIngredientNames(query string, limit int) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var names []string
+	for _, ingredient := range r.store.ingredients {
+		if _, archived := r.store.archived[ingredient.ID]; archived {
+			continue
+		}
+		names = append(names, ingredient.Name)
+		for _, name := range r.store.translations[ingredient.ID] {
+			names = append(names, name)
+		}
+	}
+	return repository.RankSuggestions(query, names, limit), nil
+}
+
+func (r *ingredientRepository) CreateIngredient(req models.CreateIngredientRequest) (*models.Ingredient, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.nameTaken(req.Name, 0) {
+		return nil, domain.ErrIngredientExists
+	}
+
+	r.store.nextIngredientID++
+	ingredient := models.Ingredient{
+		ID:          r.store.nextIngredientID,
+		Name:        req.Name,
+		Description: req.Description,
+		Category:    req.Category,
+		CreatedAt:   now(),
+	}
+	r.store.ingredients[ingredient.ID] = ingredient
+
+	return &ingredient, nil
+}
+
+func (r *ingredientRepository) UpdateIngredient(id int, req models.UpdateIngredientRequest) (*models.Ingredient, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	ingredient, ok := r.store.ingredients[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	if r.nameTaken(req.Name, id) {
+		return nil, domain.ErrIngredientExists
+	}
+
+	description, category := req.Description, req.Category
+	ingredient.Name = req.Name
+	ingredient.Description = &description
+	ingredient.Category = &category
+	r.store.ingredients[id] = ingredient
+
+	return &ingredient, nil
+}
+
+func (r *ingredientRepository) DeleteIngredient(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.ingredients[id]; !ok {
+		return sql.ErrNoRows
+	}
+	r.store.deleteIngredient(id)
+	return nil
+}
+
+func (r *ingredientRepository) IngredientExists(id int) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	_, ok := r.store.ingredients[id]
+	return ok, nil
+}
+
+func (r *ingredientRepository) FindMissingIngredientIDs(ids []int) ([]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	missing := []int{}
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := r.store.ingredients[id]; !ok && !seen[id] {
+			missing = append(missing, id)
+		}
+		seen[id] = true
+	}
+	return missing, nil
+}
+
+func (r *ingredientRepository) MatchIngredientNames(names []string) (map[string]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	candidates := r.store.ingredientNames()
+	matches := make(map[string]int)
+	for _, name := range names {
+		if id, ok := repository.MatchIngredientName(name, candidates); ok {
+			matches[name] = id
+		}
+	}
+	return matches, nil
+}
+
+func (r *ingredientRepository) GetRecipeIngredients(recipeID int) ([]models.RecipeIngredient, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.recipeIngredientsFor(recipeID), nil
+}
+
+func (r *ingredientRepository) AddRecipeIngredient(recipeID int, req models.AddRecipeIngredientRequest) (*models.RecipeIngredient, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, _, found := r.findRecipeIngredient(recipeID, req.IngredientID); found {
+		return nil, domain.ErrRecipeIngredientAlreadyExists
+	}
+
+	ri := r.store.insertRecipeIngredient(recipeID, req)
+	return &ri, nil
+}
+
+func (r *ingredientRepository) UpdateRecipeIngredient(recipeID, ingredientID int, req models.AddRecipeIngredientRequest) (*models.RecipeIngredient, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	id, ri, found := r.findRecipeIngredient(recipeID, ingredientID)
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+
+	ri.Quantity = req.Quantity
+	ri.Unit = req.Unit
+	ri.Notes = req.Notes
+	ri.ProductID = req.ProductID
+	r.store.recipeIngredients[id] = ri
+
+	ri.Ingredient = r.store.ingredients[ingredientID]
+	ri.Product = r.store.productOf(ri)
+	return &ri, nil
+}
+
+func (r *ingredientRepository) RemoveRecipeIngredient(recipeID, ingredientID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	id, _, found := r.findRecipeIngredient(recipeID, ingredientID)
+	if !found {
+		return sql.ErrNoRows
+	}
+	r.store.deleteRecipeIngredient(id)
+	return nil
+}
+
+func (r *ingredientRepository) SetRecipeIngredients(recipeID int, ingredients []models.AddRecipeIngredientRequest) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.replaceRecipeIngredients(recipeID, ingredients)
+	return nil
+}
+
+func (r *ingredientRepository) GetIngredientsForRecipes(recipeIDs []int) (map[int][]models.RecipeIngredient, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	result := make(map[int][]models.RecipeIngredient)
+	for _, recipeID := range recipeIDs {
+		if ingredients := r.store.recipeIngredientsFor(recipeID); len(ingredients) > 0 {
+			result[recipeID] = ingredients
+		}
+	}
+	return result, nil
+}
+
+func (r *ingredientRepository) GetRecipesUsingIngredient(ingredientID int, params models.PaginationParams) ([]models.Recipe, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	usedBy := make(map[int]bool)
+	for _, ri := range r.store.recipeIngredients {
+		if ri.IngredientID == ingredientID {
+			usedBy[ri.RecipeID] = true
+		}
+	}
+
+	recipes := r.store.recipesWhere(func(recipe models.Recipe) bool { return isPublished(recipe) && usedBy[recipe.ID] })
+	return paginate(recipes, params), len(recipes), nil
+}
+
+// GetPopularIngredients computes usage counts on the fly; the store is small
+// enough that it needs no precomputed view.
+func (r *ingredientRepository) GetPopularIngredients(limit int) ([]models.IngredientUsage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recipesByIngredient := make(map[int]map[int]bool)
+	for _, ri := range r.store.recipeIngredients {
+		if recipesByIngredient[ri.IngredientID] == nil {
+			recipesByIngredient[ri.IngredientID] = make(map[int]bool)
+		}
+		recipesByIngredient[ri.IngredientID][ri.RecipeID] = true
+	}
+
+	usage := make([]models.IngredientUsage, 0, len(recipesByIngredient))
+	for ingredientID, recipes := range recipesByIngredient {
+		ingredient, ok := r.store.ingredients[ingredientID]
+		if !ok {
+			continue
+		}
+		usage = append(usage, models.IngredientUsage{Ingredient: ingredient, RecipeCount: len(recipes)})
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].RecipeCount != usage[j].RecipeCount {
+			return usage[i].RecipeCount > usage[j].RecipeCount
+		}
+		return usage[i].Name < usage[j].Name
+	})
+	if len(usage) > limit {
+		usage = usage[:limit]
+	}
+	return usage, nil
+}
+
+func (r *ingredientRepository) GetDensity(ingredientID int) (*models.IngredientDensity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	density, ok := r.store.densities[ingredientID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &density, nil
+}
+
+func (r *ingredientRepository) ListDensities(params models.PaginationParams) ([]models.IngredientDensity, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	densities := make([]models.IngredientDensity, 0, len(r.store.densities))
+	for _, density := range r.store.densities {
+		densities = append(densities, density)
+	}
+	sort.Slice(densities, func(i, j int) bool { return densities[i].IngredientID < densities[j].IngredientID })
+
+	return paginate(densities, params), len(densities), nil
+}
+
+func (r *ingredientRepository) UpsertDensity(ingredientID int, req models.SetIngredientDensityRequest) (*models.IngredientDensity, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	density := models.IngredientDensity{
+		IngredientID: ingredientID,
+		GramsPerML:   req.GramsPerML,
+		Source:       req.Source,
+		UpdatedAt:    now(),
+	}
+	r.store.densities[ingredientID] = density
+	return &density, nil
+}
+
+func (r *ingredientRepository) DeleteDensity(ingredientID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.densities[ingredientID]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.densities, ingredientID)
+	return nil
+}
+
+func (r *ingredientRepository) GetDensities(ingredientIDs []int) (map[int]float64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	densities := make(map[int]float64)
+	for _, id := range ingredientIDs {
+		if density, ok := r.store.densities[id]; ok {
+			densities[id] = density.GramsPerML
+		}
+	}
+	return densities, nil
+}
+
+func (r *ingredientRepository) GetPackSizes(ingredientID int) ([]models.PackSize, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return append([]models.PackSize{}, r.store.packSizes[ingredientID]...), nil
+}
+
+func (r *ingredientRepository) SetPackSizes(ingredientID int, packSizes []models.PackSizeRequest) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored := make([]models.PackSize, 0, len(packSizes))
+	for _, packSize := range packSizes {
+		r.store.nextPackSizeID++
+		stored = append(stored, models.PackSize{
+			ID:           r.store.nextPackSizeID,
+			IngredientID: ingredientID,
+			Quantity:     packSize.Quantity,
+			Unit:         packSize.Unit,
+			Label:        packSize.Label,
+			CreatedAt:    now(),
+		})
+	}
+	sort.Slice(stored, func(i, j int) bool { return stored[i].Quantity < stored[j].Quantity })
+
+	if len(stored) == 0 {
+		delete(r.store.packSizes, ingredientID)
+	} else {
+		r.store.packSizes[ingredientID] = stored
+	}
+	return nil
+}
+
+func (r *ingredientRepository) GetPackSizesForIngredients(ingredientIDs []int) (map[int][]models.PackSize, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	packSizes := make(map[int][]models.PackSize)
+	for _, id := range ingredientIDs {
+		if sizes, ok := r.store.packSizes[id]; ok {
+			packSizes[id] = append([]models.PackSize{}, sizes...)
+		}
+	}
+	return packSizes, nil
+}
+
+func (r *ingredientRepository) GetProducts(ingredientID int) ([]models.IngredientProduct, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	products := make([]models.IngredientProduct, 0)
+	for _, product := range r.store.products {
+		if product.IngredientID == ingredientID {
+			products = append(products, product)
+		}
+	}
+	sort.Slice(products, func(i, j int) bool {
+		if products[i].Brand != products[j].Brand {
+			return products[i].Brand < products[j].Brand
+		}
+		return products[i].Name < products[j].Name
+	})
+	return products, nil
+}
+
+func (r *ingredientRepository) CreateProduct(ingredientID int, req models.IngredientProductRequest) (*models.IngredientProduct, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.productExists(ingredientID, 0, req) {
+		return nil, domain.ErrProductExists
+	}
+
+	r.store.nextProductID++
+	product := models.IngredientProduct{
+		ID:           r.store.nextProductID,
+		IngredientID: ingredientID,
+		Brand:        req.Brand,
+		Name:         req.Name,
+		Nutrition:    req.Nutrition,
+		CreatedAt:    now(),
+	}
+	r.store.products[product.ID] = product
+	return &product, nil
+}
+
+func (r *ingredientRepository) UpdateProduct(ingredientID, productID int, req models.IngredientProductRequest) (*models.IngredientProduct, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	product, ok := r.store.products[productID]
+	if !ok || product.IngredientID != ingredientID {
+		return nil, sql.ErrNoRows
+	}
+	if r.productExists(ingredientID, productID, req) {
+		return nil, domain.ErrProductExists
+	}
+
+	product.Brand = req.Brand
+	product.Name = req.Name
+	product.Nutrition = req.Nutrition
+	r.store.products[productID] = product
+	return &product, nil
+}
+
+func (r *ingredientRepository) DeleteProduct(ingredientID, productID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	product, ok := r.store.products[productID]
+	if !ok || product.IngredientID != ingredientID {
+		return sql.ErrNoRows
+	}
+	r.store.deleteProduct(productID)
+	return nil
+}
+
+func (r *ingredientRepository) GetProductsByID(productIDs []int) (map[int]models.IngredientProduct, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	products := make(map[int]models.IngredientProduct)
+	for _, id := range productIDs {
+		if product, ok := r.store.products[id]; ok {
+			products[id] = product
+		}
+	}
+	return products, nil
+}
+
+// productExists reports whether another product of the ingredient than
+// exceptID has the brand and name of req, as the unique constraint would.
+// Callers must hold mu.
+func (r *ingredientRepository) productExists(ingredientID, exceptID int, req models.IngredientProductRequest) bool {
+	for id, product := range r.store.products {
+		if id != exceptID && product.IngredientID == ingredientID && product.Brand == req.Brand && product.Name == req.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ingredientRepository) GetSubstitutions(ingredientID int) ([]models.IngredientSubstitution, error) {
+	substitutions, err := r.GetSubstitutionsForIngredients([]int{ingredientID})
+	if err != nil {
+		return nil, err
+	}
+	if substitutions[ingredientID] == nil {
+		return []models.IngredientSubstitution{}, nil
+	}
+	return substitutions[ingredientID], nil
+}
+
+func (r *ingredientRepository) SetSubstitutions(ingredientID int, substitutions []models.SubstitutionRequest) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.substitutions[ingredientID] = append([]models.SubstitutionRequest{}, substitutions...)
+	return nil
+}
+
+func (r *ingredientRepository) GetSubstitutionsForIngredients(ingredientIDs []int) (map[int][]models.IngredientSubstitution, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	substitutions := make(map[int][]models.IngredientSubstitution)
+	for _, id := range ingredientIDs {
+		for _, substitution := range r.store.substitutions[id] {
+			substitute, ok := r.store.ingredients[substitution.SubstituteID]
+			if !ok {
+				continue
+			}
+			substitutions[id] = append(substitutions[id], models.IngredientSubstitution{
+				IngredientID: id,
+				SubstituteID: substitute.ID,
+				Substitute:   substitute,
+				Ratio:        substitution.Ratio,
+				Notes:        substitution.Notes,
+			})
+		}
+		sort.Slice(substitutions[id], func(i, j int) bool {
+			return substitutions[id][i].Substitute.Name < substitutions[id][j].Substitute.Name
+		})
+	}
+	return substitutions, nil
+}
+
+func (r *ingredientRepository) GetTranslations(ingredientID int) ([]models.IngredientTranslation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	translations := make([]models.IngredientTranslation, 0, len(r.store.translations[ingredientID]))
+	for locale, name := range r.store.translations[ingredientID] {
+		translations = append(translations, models.IngredientTranslation{Locale: locale, Name: name})
+	}
+	sort.Slice(translations, func(i, j int) bool { return translations[i].Locale < translations[j].Locale })
+	return translations, nil
+}
+
+func (r *ingredientRepository) SetTranslations(ingredientID int, translations []models.IngredientTranslation) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	names := make(map[string]string, len(translations))
+	for _, translation := range translations {
+		names[translation.Locale] = translation.Name
+	}
+	r.store.translations[ingredientID] = names
+	return nil
+}
+
+func (r *ingredientRepository) GetLocalizedNames(ingredientIDs []int, locales []string) (map[int]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	names := make(map[int]string)
+	for _, id := range ingredientIDs {
+		for _, locale := range locales {
+			if name, ok := r.store.translations[id][locale]; ok {
+				names[id] = name
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+func (r *ingredientRepository) GetDietaryFacts(ingredientIDs []int) (map[int]models.DietaryFacts, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	facts := make(map[int]models.DietaryFacts)
+	for _, id := range ingredientIDs {
+		if f, ok := r.store.dietaryFacts[id]; ok {
+			f.Flags = append([]string{}, f.Flags...)
+			facts[id] = f
+		}
+	}
+	return facts, nil
+}
+
+func (r *ingredientRepository) SetDietaryFacts(ingredientID int, facts models.DietaryFacts) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if len(facts.Flags) == 0 && facts.Override == "" {
+		delete(r.store.dietaryFacts, ingredientID)
+		return nil
+	}
+	facts.Flags = append([]string{}, facts.Flags...)
+	sort.Strings(facts.Flags)
+	r.store.dietaryFacts[ingredientID] = facts
+	return nil
+}
+
+func (r *ingredientRepository) GetAllergens() ([]models.Allergen, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	allergens := make([]models.Allergen, 0, len(r.store.allergens))
+	for _, allergen := range r.store.allergens {
+		allergens = append(allergens, allergen)
+	}
+	sort.Slice(allergens, func(i, j int) bool { return allergens[i].Name < allergens[j].Name })
+	return allergens, nil
+}
+
+func (r *ingredientRepository) GetIngredientAllergens(ingredientID int) ([]models.Allergen, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	allergens := make([]models.Allergen, 0, len(r.store.ingredientAllergens[ingredientID]))
+	for allergenID := range r.store.ingredientAllergens[ingredientID] {
+		allergens = append(allergens, r.store.allergens[allergenID])
+	}
+	sort.Slice(allergens, func(i, j int) bool { return allergens[i].Name < allergens[j].Name })
+	return allergens, nil
+}
+
+func (r *ingredientRepository) SetIngredientAllergens(ingredientID int, allergenIDs []int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if len(allergenIDs) == 0 {
+		delete(r.store.ingredientAllergens, ingredientID)
+		return nil
+	}
+	tagged := make(map[int]bool, len(allergenIDs))
+	for _, allergenID := range allergenIDs {
+		tagged[allergenID] = true
+	}
+	r.store.ingredientAllergens[ingredientID] = tagged
+	return nil
+}
+
+func (r *ingredientRepository) AddIngredientAllergen(ingredientID, allergenID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.ingredientAllergens[ingredientID] == nil {
+		r.store.ingredientAllergens[ingredientID] = make(map[int]bool)
+	}
+	r.store.ingredientAllergens[ingredientID][allergenID] = true
+	return nil
+}
+
+func (r *ingredientRepository) RemoveIngredientAllergen(ingredientID, allergenID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.ingredientAllergens[ingredientID][allergenID] {
+		return sql.ErrNoRows
+	}
+	delete(r.store.ingredientAllergens[ingredientID], allergenID)
+	return nil
+}
+
+func (r *ingredientRepository) GetRecipeAllergens(recipeID int) ([]models.RecipeAllergen, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byAllergen := make(map[int]*models.RecipeAllergen)
+	for _, ri := range r.store.recipeIngredientsFor(recipeID) {
+		for allergenID := range r.store.ingredientAllergens[ri.IngredientID] {
+			rollup, ok := byAllergen[allergenID]
+			if !ok {
+				allergen := r.store.allergens[allergenID]
+				rollup = &models.RecipeAllergen{Code: allergen.Code, Name: allergen.Name}
+				byAllergen[allergenID] = rollup
+			}
+			rollup.Ingredients = append(rollup.Ingredients, ri.Ingredient.Name)
+		}
+	}
+
+	allergens := make([]models.RecipeAllergen, 0, len(byAllergen))
+	for _, rollup := range byAllergen {
+		sort.Strings(rollup.Ingredients)
+		allergens = append(allergens, *rollup)
+	}
+	sort.Slice(allergens, func(i, j int) bool { return allergens[i].Name < allergens[j].Name })
+	return allergens, nil
+}
+
+// ingredientsWhere returns matching, unarchived ingredients ordered by name.
+// Callers must hold mu.
+func (r *ingredientRepository) ingredientsWhere(match func(models.Ingredient) bool) []models.Ingredient {
+	ingredients := make([]models.Ingredient, 0)
+	for _, ingredient := range r.store.ingredients {
+		if _, archived := r.store.archived[ingredient.ID]; archived {
+			continue
+		}
+		if match(ingredient) {
+			ingredients = append(ingredients, ingredient)
+		}
+	}
+
+	sort.Slice(ingredients, func(i, j int) bool { return ingredients[i].Name < ingredients[j].Name })
+	return ingredients
+}
+
+// nameTaken reports whether another ingredient already uses name, enforcing
+// the same uniqueness as the ingredients_name_key constraint.
+func (r *ingredientRepository) nameTaken(name string, exceptID int) bool {
+	for _, ingredient := range r.store.ingredients {
+		if ingredient.ID != exceptID && ingredient.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ingredientRepository) findRecipeIngredient(recipeID, ingredientID int) (int, models.RecipeIngredient, bool) {
+	for id, ri := range r.store.recipeIngredients {
+		if ri.RecipeID == recipeID && ri.IngredientID == ingredientID {
+			return id, ri, true
+		}
+	}
+	return 0, models.RecipeIngredient{}, false
+}
+
+// matchOrCreateIngredients resolves the recipe lines given by name as the
+// SQL repository does, to a matching ingredient or a new one. Like the SQL
+// transaction it is all or nothing: on error the ingredients it created are
+// removed again. Callers must hold mu for writing.
+func (s *Store) matchOrCreateIngredients(ingredients []models.AddRecipeIngredientRequest) ([]models.AddRecipeIngredientRequest, error) {
+	resolved := make([]models.AddRecipeIngredientRequest, len(ingredients))
+	used := make(map[int]bool)
+	var created []int
+	fail := func(err error) ([]models.AddRecipeIngredientRequest, error) {
+		for _, id := range created {
+			delete(s.ingredients, id)
+		}
+		return nil, err
+	}
+
+	for i, ingredient := range ingredients {
+		if ingredient.IngredientID == 0 {
+			id, ok := repository.MatchIngredientName(ingredient.IngredientName, s.ingredientNames())
+			if !ok {
+				if ingredient.IngredientCategory == "" {
+					return fail(domain.ErrIngredientCategoryRequired)
+				}
+				id = s.createIngredient(ingredient.IngredientName, ingredient.IngredientCategory)
+				created = append(created, id)
+			}
+			ingredient.IngredientID = id
+		}
+
+		if used[ingredient.IngredientID] {
+			return fail(domain.ErrRecipeIngredientAlreadyExists)
+		}
+		used[ingredient.IngredientID] = true
+		resolved[i] = ingredient
+	}
+	return resolved, nil
+}
+
+// ingredientNames lists every ingredient's name and translations, archived
+// ones included. Callers must hold mu.
+func (s *Store) ingredientNames() []repository.IngredientName {
+	var names []repository.IngredientName
+	for id, ingredient := range s.ingredients {
+		names = append(names, repository.IngredientName{IngredientID: id, Name: ingredient.Name})
+		for _, name := range s.translations[id] {
+			names = append(names, repository.IngredientName{IngredientID: id, Name: name, Alias: true})
+		}
+	}
+	return names
+}
+
+// createIngredient adds an ingredient and returns its ID. Callers must hold
+// mu for writing.
+func (s *Store) createIngredient(name, category string) int {
+	s.nextIngredientID++
+	s.ingredients[s.nextIngredientID] = models.Ingredient{
+		ID:        s.nextIngredientID,
+		Name:      name,
+		Category:  &category,
+		CreatedAt: now(),
+	}
+	return s.nextIngredientID
+}
@@ -0,0 +1,289 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type IngredientRepositoryTestSuite struct {
+	suite.Suite
+	repo repository.IngredientRepository
+}
+
+func (suite *IngredientRepositoryTestSuite) SetupTest() {
+	store := NewStore()
+	store.SeedDefaults()
+	suite.repo = NewIngredientRepository(store)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func (suite *IngredientRepositoryTestSuite) TestCreateIngredient_RejectsDuplicateName() {
+	_, err := suite.repo.CreateIngredient(models.CreateIngredientRequest{Name: "Garlic", Category: stringPtr("Vegetables")})
+
+	assert.Equal(suite.T(), domain.ErrIngredientExists, err)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestSearchIngredients_IsCaseInsensitiveOnNameAndDescription() {
+	ingredients, total, err := suite.repo.SearchIngredients("CHICKEN", models.PaginationParams{Page: 1, PerPage: 20})
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, total)
+	assert.Equal(suite.T(), "Chicken Breast", ingredients[0].Name)
+
+	_, total, err = suite.repo.SearchIngredients("cloves", models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, total)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestGetAllIngredients_OrdersByCategoryThenName() {
+	ingredients, total, err := suite.repo.GetAllIngredients(models.PaginationParams{Page: 1, PerPage: 3})
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 12, total)
+	require.Len(suite.T(), ingredients, 3)
+	assert.Equal(suite.T(), "Eggs", ingredients[0].Name)
+	assert.Equal(suite.T(), "Parmesan Cheese", ingredients[1].Name)
+	assert.Equal(suite.T(), "Salmon Fillet", ingredients[2].Name)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestFindMissingIngredientIDs_ReturnsUnknownIDsOnce() {
+	missing, err := suite.repo.FindMissingIngredientIDs([]int{1, 998, 2, 999, 998})
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []int{998, 999}, missing)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestRecipeIngredientLifecycle() {
+	added, err := suite.repo.AddRecipeIngredient(1, models.AddRecipeIngredientRequest{IngredientID: 5, Quantity: 1, Unit: "piece"})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Onion", added.Ingredient.Name)
+
+	_, err = suite.repo.AddRecipeIngredient(1, models.AddRecipeIngredientRequest{IngredientID: 5, Quantity: 2, Unit: "piece"})
+	assert.Equal(suite.T(), domain.ErrRecipeIngredientAlreadyExists, err)
+
+	updated, err := suite.repo.UpdateRecipeIngredient(1, 5, models.AddRecipeIngredientRequest{Quantity: 2, Unit: "pieces"})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2.0, updated.Quantity)
+
+	require.NoError(suite.T(), suite.repo.RemoveRecipeIngredient(1, 5))
+	assert.Equal(suite.T(), sql.ErrNoRows, suite.repo.RemoveRecipeIngredient(1, 5))
+}
+
+func (suite *IngredientRepositoryTestSuite) TestGetPopularIngredients_RanksByRecipeCount() {
+	for _, link := range []struct{ recipeID, ingredientID int }{{1, 6}, {2, 6}, {3, 6}, {1, 5}, {2, 5}, {1, 10}} {
+		_, err := suite.repo.AddRecipeIngredient(link.recipeID, models.AddRecipeIngredientRequest{
+			IngredientID: link.ingredientID, Quantity: 1, Unit: "piece",
+		})
+		require.NoError(suite.T(), err)
+	}
+
+	usage, err := suite.repo.GetPopularIngredients(2)
+
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), usage, 2)
+	assert.Equal(suite.T(), "Garlic", usage[0].Name)
+	assert.Equal(suite.T(), 3, usage[0].RecipeCount)
+	assert.Equal(suite.T(), "Onion", usage[1].Name)
+	assert.Equal(suite.T(), 2, usage[1].RecipeCount)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestDensityLifecycle() {
+	densities, err := suite.repo.GetDensities([]int{4, 5, 9})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[int]float64{4: 0.85, 9: 0.91}, densities)
+
+	saved, err := suite.repo.UpsertDensity(5, models.SetIngredientDensityRequest{GramsPerML: 0.6})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0.6, saved.GramsPerML)
+
+	_, err = suite.repo.UpsertDensity(5, models.SetIngredientDensityRequest{GramsPerML: 0.64, Source: stringPtr("kitchen scale")})
+	require.NoError(suite.T(), err)
+	got, err := suite.repo.GetDensity(5)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0.64, got.GramsPerML)
+	assert.Equal(suite.T(), "kitchen scale", *got.Source)
+
+	list, total, err := suite.repo.ListDensities(models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 6, total)
+	assert.Equal(suite.T(), 4, list[0].IngredientID)
+
+	require.NoError(suite.T(), suite.repo.DeleteDensity(5))
+	assert.Equal(suite.T(), sql.ErrNoRows, suite.repo.DeleteDensity(5))
+	_, err = suite.repo.GetDensity(5)
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestDietaryFactsLifecycle() {
+	facts, err := suite.repo.GetDietaryFacts([]int{5, 8, 12})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[int]models.DietaryFacts{
+		8:  {Flags: []string{"dairy"}, Override: "omnivore"},
+		12: {Flags: []string{"egg"}},
+	}, facts)
+
+	require.NoError(suite.T(), suite.repo.SetDietaryFacts(5, models.DietaryFacts{Flags: []string{"plant_based"}}))
+	require.NoError(suite.T(), suite.repo.SetDietaryFacts(8, models.DietaryFacts{}))
+
+	facts, err = suite.repo.GetDietaryFacts([]int{5, 8})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[int]models.DietaryFacts{5: {Flags: []string{"plant_based"}}}, facts)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestIngredientAllergensLifecycle() {
+	allergens, err := suite.repo.GetAllergens()
+	require.NoError(suite.T(), err)
+	codes := make(map[string]int, len(allergens))
+	for _, allergen := range allergens {
+		codes[allergen.Code] = allergen.ID
+	}
+	require.Contains(suite.T(), codes, "gluten")
+
+	tagged, err := suite.repo.GetIngredientAllergens(8)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), tagged, 1)
+	assert.Equal(suite.T(), "dairy", tagged[0].Code)
+
+	require.NoError(suite.T(), suite.repo.AddIngredientAllergen(4, codes["gluten"]))
+	require.NoError(suite.T(), suite.repo.AddIngredientAllergen(4, codes["gluten"]))
+	require.NoError(suite.T(), suite.repo.SetIngredientAllergens(8, []int{codes["dairy"], codes["soy"]}))
+	assert.Equal(suite.T(), sql.ErrNoRows, suite.repo.RemoveIngredientAllergen(5, codes["gluten"]))
+
+	tagged, err = suite.repo.GetIngredientAllergens(4)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), tagged, 1)
+
+	require.NoError(suite.T(), suite.repo.RemoveIngredientAllergen(4, codes["gluten"]))
+	tagged, err = suite.repo.GetIngredientAllergens(4)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), tagged)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestIngredientProductsLifecycle() {
+	req := models.IngredientProductRequest{
+		Brand:     "Barilla",
+		Name:      "Spaghetti No. 5",
+		Nutrition: models.ProductNutrition{CaloriesKcal: 359, ProteinG: 12.5, CarbsG: 71.2, FatG: 2, FiberG: 3},
+	}
+	product, err := suite.repo.CreateProduct(5, req)
+	require.NoError(suite.T(), err)
+	_, err = suite.repo.CreateProduct(5, req)
+	assert.Equal(suite.T(), domain.ErrProductExists, err)
+	_, err = suite.repo.UpdateProduct(6, product.ID, req)
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+
+	added, err := suite.repo.AddRecipeIngredient(1, models.AddRecipeIngredientRequest{IngredientID: 5, Quantity: 100, Unit: "g", ProductID: &product.ID})
+	require.NoError(suite.T(), err)
+	require.NotNil(suite.T(), added.Product)
+	assert.Equal(suite.T(), "Barilla", added.Product.Brand)
+
+	// Deleting the product leaves the recipe on the generic ingredient
+	require.NoError(suite.T(), suite.repo.DeleteProduct(5, product.ID))
+	ingredients, err := suite.repo.GetRecipeIngredients(1)
+	require.NoError(suite.T(), err)
+	for _, ri := range ingredients {
+		assert.Nil(suite.T(), ri.ProductID)
+		assert.Nil(suite.T(), ri.Product)
+	}
+	products, err := suite.repo.GetProducts(5)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), products)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestDeleteIngredient_DropsDensity() {
+	require.NoError(suite.T(), suite.repo.DeleteIngredient(10))
+
+	_, err := suite.repo.GetDensity(10)
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestSetPackSizes_ReplacesAndOrdersBySize() {
+	require.NoError(suite.T(), suite.repo.SetPackSizes(3, []models.PackSizeRequest{
+		{Quantity: 800, Unit: "g"},
+		{Quantity: 400, Unit: "g", Label: stringPtr("can")},
+	}))
+
+	sizes, err := suite.repo.GetPackSizes(3)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), sizes, 2)
+	assert.Equal(suite.T(), 400.0, sizes[0].Quantity)
+
+	byIngredient, err := suite.repo.GetPackSizesForIngredients([]int{3, 5, 12})
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), byIngredient[3], 2)
+	assert.Len(suite.T(), byIngredient[12], 2) // seeded egg boxes
+	assert.NotContains(suite.T(), byIngredient, 5)
+
+	require.NoError(suite.T(), suite.repo.SetPackSizes(3, nil))
+	sizes, err = suite.repo.GetPackSizes(3)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), sizes)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestSetSubstitutions_FollowDeletedSubstitute() {
+	require.NoError(suite.T(), suite.repo.SetSubstitutions(3, []models.SubstitutionRequest{
+		{SubstituteID: 5, Ratio: 1},
+		{SubstituteID: 6, Ratio: 0.5, Notes: stringPtr("milder")},
+	}))
+
+	substitutions, err := suite.repo.GetSubstitutions(3)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), substitutions, 2)
+	assert.LessOrEqual(suite.T(), substitutions[0].Substitute.Name, substitutions[1].Substitute.Name)
+
+	require.NoError(suite.T(), suite.repo.DeleteIngredient(6))
+	byIngredient, err := suite.repo.GetSubstitutionsForIngredients([]int{3, 4})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), byIngredient[3], 1)
+	assert.Equal(suite.T(), 5, byIngredient[3][0].SubstituteID)
+	assert.NotContains(suite.T(), byIngredient, 4)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestTranslations_SearchAndFallback() {
+	found, total, err := suite.repo.SearchIngredients("чеснок", models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, total)
+	assert.Equal(suite.T(), "Garlic", found[0].Name)
+
+	require.NoError(suite.T(), suite.repo.SetTranslations(5, []models.IngredientTranslation{
+		{Locale: "pt", Name: "Cebola"},
+		{Locale: "pt-br", Name: "Cebola-amarela"},
+	}))
+	translations, err := suite.repo.GetTranslations(5)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []models.IngredientTranslation{{Locale: "pt", Name: "Cebola"}, {Locale: "pt-br", Name: "Cebola-amarela"}}, translations)
+
+	names, err := suite.repo.GetLocalizedNames([]int{5, 6}, []string{"pt-br", "pt"})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[int]string{5: "Cebola-amarela"}, names)
+
+	require.NoError(suite.T(), suite.repo.DeleteIngredient(5))
+	translations, err = suite.repo.GetTranslations(5)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), translations)
+}
+
+func (suite *IngredientRepositoryTestSuite) TestSuggestIngredientNames_IncludesTranslations() {
+	names, err := suite.repo.SuggestIngredientNames("garlik", 5)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"Garlic"}, names)
+
+	names, err = suite.repo.SuggestIngredientNames("чесног", 5)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"Чеснок"}, names)
+}
+
+func TestIngredientRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(IngredientRepositoryTestSuite))
+}
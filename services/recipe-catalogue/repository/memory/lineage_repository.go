@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type lineageRepository struct {
+	store *Store
+}
+
+func NewLineageRepository(store *Store) repository.LineageRepository {
+	return &lineageRepository{store: store}
+}
+
+func (r *lineageRepository) Record(recipeID, parentID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.lineage[recipeID] = lineageLink{parentID: parentID, createdAt: now()}
+	return nil
+}
+
+func (r *lineageRepository) GetAncestors(recipeID int) ([]models.Recipe, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ancestors := make([]models.Recipe, 0)
+	for link, ok := r.store.lineage[recipeID]; ok && link.parentID != 0; link, ok = r.store.lineage[link.parentID] {
+		parent, exists := r.store.recipes[link.parentID]
+		if !exists {
+			break
+		}
+		ancestors = append(ancestors, models.Recipe{
+			ID:     parent.ID,
+			UserID: parent.UserID,
+			Name:   parent.Name,
+			Status: parent.Status,
+		})
+	}
+	return ancestors, nil
+}
+
+func (r *lineageRepository) GetForks(recipeID int) ([]models.RecipeFork, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	forks := make([]models.RecipeFork, 0)
+	level := []int{recipeID}
+	for depth := 1; len(level) > 0; depth++ {
+		var next []int
+		for _, parentID := range level {
+			for forkID, link := range r.store.lineage {
+				if link.parentID != parentID {
+					continue
+				}
+				next = append(next, forkID)
+				fork, ok := r.store.recipes[forkID]
+				if !ok || !isPublished(fork) {
+					continue
+				}
+				forks = append(forks, models.RecipeFork{
+					RecipeID:      fork.ID,
+					AdaptedFromID: parentID,
+					UserID:        fork.UserID,
+					Name:          fork.Name,
+					Depth:         depth,
+					ForkedAt:      link.createdAt,
+				})
+			}
+		}
+		level = next
+	}
+
+	sort.SliceStable(forks, func(i, j int) bool {
+		if forks[i].Depth != forks[j].Depth {
+			return forks[i].Depth < forks[j].Depth
+		}
+		if !forks[i].ForkedAt.Equal(forks[j].ForkedAt) {
+			return forks[i].ForkedAt.Before(forks[j].ForkedAt)
+		}
+		return forks[i].RecipeID < forks[j].RecipeID
+	})
+	return forks, nil
+}
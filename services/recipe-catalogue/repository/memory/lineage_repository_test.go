@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineageRepository_AncestorsAndForks(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, UserID: 1, Name: "Shakshuka", Status: models.RecipeStatusPublished}
+	store.recipes[2] = models.Recipe{ID: 2, UserID: 2, Name: "Green shakshuka", Status: models.RecipeStatusDraft}
+	store.recipes[3] = models.Recipe{ID: 3, UserID: 3, Name: "Feta shakshuka", Status: models.RecipeStatusPublished}
+	store.recipes[4] = models.Recipe{ID: 4, UserID: 4, Name: "Spicy shakshuka", Status: models.RecipeStatusPublished}
+	repo := NewLineageRepository(store)
+
+	require.NoError(t, repo.Record(2, 1))
+	require.NoError(t, repo.Record(3, 2))
+	require.NoError(t, repo.Record(4, 1))
+
+	ancestors, err := repo.GetAncestors(3)
+	require.NoError(t, err)
+	require.Len(t, ancestors, 2)
+	assert.Equal(t, 2, ancestors[0].ID)
+	assert.Equal(t, 1, ancestors[1].ID)
+
+	// The draft fork is hidden, its published fork is not
+	forks, err := repo.GetForks(1)
+	require.NoError(t, err)
+	require.Len(t, forks, 2)
+	assert.Equal(t, 4, forks[0].RecipeID)
+	assert.Equal(t, 1, forks[0].Depth)
+	assert.Equal(t, 3, forks[1].RecipeID)
+	assert.Equal(t, 2, forks[1].AdaptedFromID)
+	assert.Equal(t, 2, forks[1].Depth)
+}
+
+func TestLineageRepository_DeletedOriginalEndsChain(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, UserID: 1, Name: "Dal", Status: models.RecipeStatusPublished}
+	store.recipes[2] = models.Recipe{ID: 2, UserID: 2, Name: "Tadka dal", Status: models.RecipeStatusPublished}
+	repo := NewLineageRepository(store)
+	require.NoError(t, repo.Record(2, 1))
+
+	require.NoError(t, NewRecipeRepository(store).Delete(1))
+
+	ancestors, err := repo.GetAncestors(2)
+	require.NoError(t, err)
+	assert.Empty(t, ancestors)
+}
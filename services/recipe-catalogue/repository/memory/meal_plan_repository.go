@@ -0,0 +1,325 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type mealPlanRepository struct {
+	store *Store
+}
+
+func NewMealPlanRepository(store *Store) repository.MealPlanRepository {
+	return &mealPlanRepository{store: store}
+}
+
+func (r *mealPlanRepository) GetByUserID(userID int) ([]models.MealPlan, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	plans := make([]models.MealPlan, 0)
+	for _, plan := range r.store.mealPlans {
+		if plan.UserID == userID {
+			plans = append(plans, copyMealPlan(plan))
+		}
+	}
+
+	sort.Slice(plans, func(i, j int) bool {
+		if plans[i].PeriodStart != plans[j].PeriodStart {
+			return plans[i].PeriodStart > plans[j].PeriodStart
+		}
+		return plans[i].ID > plans[j].ID
+	})
+	return plans, nil
+}
+
+func (r *mealPlanRepository) GetByUserIDInRange(userID int, from, to string) ([]models.MealPlan, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	plans := make([]models.MealPlan, 0)
+	for _, plan := range r.store.mealPlans {
+		if plan.UserID != userID {
+			continue
+		}
+		entries := make([]models.MealPlanEntry, 0)
+		for _, entry := range plan.Entries {
+			// Dates are YYYY-MM-DD, so they compare as strings
+			if entry.Date >= from && entry.Date <= to {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) > 0 {
+			plan.Members = make([]models.MealPlanMember, 0)
+			plan.Entries = entries
+			plans = append(plans, plan)
+		}
+	}
+
+	sort.Slice(plans, func(i, j int) bool {
+		if plans[i].PeriodStart != plans[j].PeriodStart {
+			return plans[i].PeriodStart < plans[j].PeriodStart
+		}
+		return plans[i].ID < plans[j].ID
+	})
+	return plans, nil
+}
+
+func (r *mealPlanRepository) GetByID(id int) (*models.MealPlan, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	plan, ok := r.store.mealPlans[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	plan = copyMealPlan(plan)
+	return &plan, nil
+}
+
+// Create ignores slug; plans are only looked up by ID.
+func (r *mealPlanRepository) Create(userID int, req models.MealPlanRequest, slug string, entries []models.MealPlanEntryRequest) (*models.MealPlan, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	seen := make(map[models.MealPlanEntryRequest]bool, len(entries))
+	for _, req := range entries {
+		meal := models.MealPlanEntryRequest{RecipeID: req.RecipeID, Date: req.Date, Slot: req.Slot}
+		if seen[meal] {
+			return nil, domain.ErrMealPlanEntryExists
+		}
+		seen[meal] = true
+	}
+
+	r.store.nextMealPlanID++
+	plan := models.MealPlan{
+		ID:          r.store.nextMealPlanID,
+		UserID:      userID,
+		Title:       req.Title,
+		PeriodStart: req.PeriodStart,
+		PeriodEnd:   req.PeriodEnd,
+		PeriodType:  req.PeriodType,
+		IsPublic:    req.IsPublic,
+		Scale:       req.Scale,
+		Members:     make([]models.MealPlanMember, 0),
+		Entries:     make([]models.MealPlanEntry, 0, len(entries)),
+		CreatedAt:   now(),
+		UpdatedAt:   now(),
+	}
+	for _, req := range entries {
+		r.store.nextMealPlanEntryID++
+		plan.Entries = append(plan.Entries, mealPlanEntry(r.store.nextMealPlanEntryID, req))
+	}
+	sortMealPlanEntries(plan.Entries)
+	r.store.mealPlans[plan.ID] = plan
+
+	plan = copyMealPlan(plan)
+	return &plan, nil
+}
+
+func (r *mealPlanRepository) Update(id int, req models.MealPlanRequest) (*models.MealPlan, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	plan, ok := r.store.mealPlans[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	plan.Title = req.Title
+	plan.PeriodStart = req.PeriodStart
+	plan.PeriodEnd = req.PeriodEnd
+	plan.PeriodType = req.PeriodType
+	plan.IsPublic = req.IsPublic
+	plan.Scale = req.Scale
+	plan.UpdatedAt = now()
+
+	entries := make([]models.MealPlanEntry, 0, len(plan.Entries))
+	for _, entry := range plan.Entries {
+		// Dates are YYYY-MM-DD, so they compare as strings
+		if entry.Date >= req.PeriodStart && entry.Date <= req.PeriodEnd {
+			entries = append(entries, entry)
+		}
+	}
+	plan.Entries = entries
+	r.store.mealPlans[id] = plan
+
+	plan = copyMealPlan(plan)
+	return &plan, nil
+}
+
+func (r *mealPlanRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.mealPlans[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.mealPlans, id)
+	return nil
+}
+
+func (r *mealPlanRepository) AddEntry(planID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	plan, ok := r.store.mealPlans[planID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	if hasMealPlanEntry(plan, 0, req) {
+		return nil, domain.ErrMealPlanEntryExists
+	}
+
+	r.store.nextMealPlanEntryID++
+	entry := mealPlanEntry(r.store.nextMealPlanEntryID, req)
+	plan.Entries = append(append([]models.MealPlanEntry{}, plan.Entries...), entry)
+	sortMealPlanEntries(plan.Entries)
+	r.store.mealPlans[planID] = plan
+	return &entry, nil
+}
+
+func (r *mealPlanRepository) UpdateEntry(planID, entryID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	plan, ok := r.store.mealPlans[planID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	if hasMealPlanEntry(plan, entryID, req) {
+		return nil, domain.ErrMealPlanEntryExists
+	}
+
+	plan = copyMealPlan(plan)
+	for i := range plan.Entries {
+		if plan.Entries[i].ID == entryID {
+			plan.Entries[i] = mealPlanEntry(entryID, req)
+			sortMealPlanEntries(plan.Entries)
+			r.store.mealPlans[planID] = plan
+
+			entry := mealPlanEntry(entryID, req)
+			return &entry, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *mealPlanRepository) DeleteEntry(planID, entryID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	plan, ok := r.store.mealPlans[planID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	entries := make([]models.MealPlanEntry, 0, len(plan.Entries))
+	for _, entry := range plan.Entries {
+		if entry.ID != entryID {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == len(plan.Entries) {
+		return sql.ErrNoRows
+	}
+	plan.Entries = entries
+	r.store.mealPlans[planID] = plan
+	return nil
+}
+
+func (r *mealPlanRepository) SetMembers(planID int, names []string, meals [][]int) ([]models.MealPlanMember, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	plan, ok := r.store.mealPlans[planID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	plan = copyMealPlan(plan)
+
+	plan.Members = make([]models.MealPlanMember, 0, len(names))
+	for _, name := range names {
+		r.store.nextMealPlanMemberID++
+		plan.Members = append(plan.Members, models.MealPlanMember{ID: r.store.nextMealPlanMemberID, Name: name})
+	}
+
+	assignees := make(map[int]int)
+	if len(plan.Members) > 0 {
+		for i, entryIDs := range meals {
+			for _, entryID := range entryIDs {
+				assignees[entryID] = plan.Members[i%len(plan.Members)].ID
+			}
+		}
+	}
+	for i := range plan.Entries {
+		plan.Entries[i].AssigneeID = nil
+		if assignee, ok := assignees[plan.Entries[i].ID]; ok {
+			plan.Entries[i].AssigneeID = &assignee
+		}
+	}
+
+	plan.UpdatedAt = now()
+	r.store.mealPlans[planID] = plan
+	return append([]models.MealPlanMember{}, plan.Members...), nil
+}
+
+func (r *mealPlanRepository) GetPreferences(userID int) (*models.MealPlanPreferences, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	prefs, ok := r.store.mealPlanPrefs[userID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &prefs, nil
+}
+
+func (r *mealPlanRepository) SavePreferences(prefs models.MealPlanPreferences) (*models.MealPlanPreferences, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	prefs.UpdatedAt = now()
+	r.store.mealPlanPrefs[prefs.UserID] = prefs
+	return &prefs, nil
+}
+
+// hasMealPlanEntry reports whether another entry than exceptID already plans
+// the recipe for that meal, as the unique constraint would.
+func hasMealPlanEntry(plan models.MealPlan, exceptID int, req models.MealPlanEntryRequest) bool {
+	for _, entry := range plan.Entries {
+		if entry.ID != exceptID && entry.RecipeID == req.RecipeID && entry.Date == req.Date && entry.Slot == req.Slot {
+			return true
+		}
+	}
+	return false
+}
+
+func mealPlanEntry(id int, req models.MealPlanEntryRequest) models.MealPlanEntry {
+	return models.MealPlanEntry{ID: id, RecipeID: req.RecipeID, Date: req.Date, Slot: req.Slot, AssigneeID: req.AssigneeID}
+}
+
+// sortMealPlanEntries orders entries by day, then through the day, as the SQL
+// repository returns them.
+func sortMealPlanEntries(entries []models.MealPlanEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Date != entries[j].Date {
+			return entries[i].Date < entries[j].Date
+		}
+		if a, b := models.MealSlotOrder(entries[i].Slot), models.MealSlotOrder(entries[j].Slot); a != b {
+			return a < b
+		}
+		return entries[i].ID < entries[j].ID
+	})
+}
+
+// copyMealPlan keeps callers from mutating the stored members and entries.
+func copyMealPlan(plan models.MealPlan) models.MealPlan {
+	plan.Members = append([]models.MealPlanMember{}, plan.Members...)
+	plan.Entries = append([]models.MealPlanEntry{}, plan.Entries...)
+	return plan
+}
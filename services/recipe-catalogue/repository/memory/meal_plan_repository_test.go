@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMealPlanRepository_GetByID(t *testing.T) {
+	store := NewStore()
+	store.mealPlans[1] = models.MealPlan{ID: 1, UserID: 7, Title: "Week of Oct 6-12", Scale: 2,
+		Entries: []models.MealPlanEntry{{ID: 1, RecipeID: 4, Date: "2025-10-06", Slot: "dinner"}}}
+	repo := NewMealPlanRepository(store)
+
+	plan, err := repo.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Week of Oct 6-12", plan.Title)
+
+	// The caller's copy does not alias the store
+	plan.Entries[0].RecipeID = 5
+	again, _ := repo.GetByID(1)
+	assert.Equal(t, 4, again.Entries[0].RecipeID)
+
+	_, err = repo.GetByID(2)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestMealPlanRepository_Entries(t *testing.T) {
+	repo := NewMealPlanRepository(NewStore())
+	plan, err := repo.Create(7, models.MealPlanRequest{Title: "Week", PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12"}, "week",
+		[]models.MealPlanEntryRequest{{RecipeID: 4, Date: "2025-10-07", Slot: "dinner"}})
+	require.NoError(t, err)
+
+	// Entries come back by day, then through the day
+	breakfast, err := repo.AddEntry(plan.ID, models.MealPlanEntryRequest{RecipeID: 5, Date: "2025-10-07", Slot: "breakfast"})
+	require.NoError(t, err)
+	_, err = repo.AddEntry(plan.ID, models.MealPlanEntryRequest{RecipeID: 5, Date: "2025-10-07", Slot: "breakfast"})
+	assert.Equal(t, domain.ErrMealPlanEntryExists, err)
+
+	got, _ := repo.GetByID(plan.ID)
+	require.Len(t, got.Entries, 2)
+	assert.Equal(t, breakfast.ID, got.Entries[0].ID)
+
+	inRange, err := repo.GetByUserIDInRange(7, "2025-10-07", "2025-10-08")
+	require.NoError(t, err)
+	require.Len(t, inRange, 1)
+	assert.Len(t, inRange[0].Entries, 2)
+	inRange, _ = repo.GetByUserIDInRange(7, "2025-10-08", "2025-10-12")
+	assert.Empty(t, inRange)
+
+	// Shortening the period drops entries that no longer fit
+	_, err = repo.Update(plan.ID, models.MealPlanRequest{Title: "Monday", PeriodStart: "2025-10-06", PeriodEnd: "2025-10-06"})
+	require.NoError(t, err)
+	got, _ = repo.GetByID(plan.ID)
+	assert.Empty(t, got.Entries)
+
+	assert.Equal(t, sql.ErrNoRows, repo.DeleteEntry(plan.ID, breakfast.ID))
+	require.NoError(t, repo.Delete(plan.ID))
+	_, err = repo.GetByID(plan.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestMealPlanRepository_Members(t *testing.T) {
+	repo := NewMealPlanRepository(NewStore())
+	plan, err := repo.Create(7, models.MealPlanRequest{Title: "Week", PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12"}, "week",
+		[]models.MealPlanEntryRequest{
+			{RecipeID: 4, Date: "2025-10-06", Slot: "dinner"},
+			{RecipeID: 5, Date: "2025-10-06", Slot: "dinner"},
+			{RecipeID: 6, Date: "2025-10-07", Slot: "dinner"},
+		})
+	require.NoError(t, err)
+	meals := [][]int{{plan.Entries[0].ID, plan.Entries[1].ID}, {plan.Entries[2].ID}}
+
+	members, err := repo.SetMembers(plan.ID, []string{"Ann", "Bo"}, meals)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+
+	got, _ := repo.GetByID(plan.ID)
+	assert.Equal(t, members, got.Members)
+	assert.Equal(t, members[0].ID, *got.Entries[0].AssigneeID)
+	assert.Equal(t, members[0].ID, *got.Entries[1].AssigneeID)
+	assert.Equal(t, members[1].ID, *got.Entries[2].AssigneeID)
+
+	// Without members nothing is assigned
+	_, err = repo.SetMembers(plan.ID, nil, meals)
+	require.NoError(t, err)
+	got, _ = repo.GetByID(plan.ID)
+	assert.Empty(t, got.Members)
+	assert.Nil(t, got.Entries[0].AssigneeID)
+
+	_, err = repo.SetMembers(99, []string{"Ann"}, nil)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestMealPlanRepository_Preferences(t *testing.T) {
+	repo := NewMealPlanRepository(NewStore())
+
+	_, err := repo.GetPreferences(7)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	weekday := 30
+	_, err = repo.SavePreferences(models.MealPlanPreferences{UserID: 7, WeekdayMaxMinutes: &weekday, ElaborateWeekends: true})
+	require.NoError(t, err)
+
+	prefs, err := repo.GetPreferences(7)
+	require.NoError(t, err)
+	assert.Equal(t, 30, *prefs.WeekdayMaxMinutes)
+	assert.True(t, prefs.ElaborateWeekends)
+	assert.False(t, prefs.UpdatedAt.IsZero())
+}
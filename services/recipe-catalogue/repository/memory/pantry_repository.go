@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type pantryRepository struct {
+	store *Store
+}
+
+func NewPantryRepository(store *Store) repository.PantryRepository {
+	return &pantryRepository{store: store}
+}
+
+func (r *pantryRepository) GetPantry(userID int) (*models.Pantry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	result := models.Pantry{Items: []models.PantryItem{}}
+	pantry := r.store.pantryOf(userID)
+	if pantry == nil {
+		return &result, nil
+	}
+	result.HouseholdID = pantry.household()
+	result.Version = pantry.version
+	result.Items = r.store.pantryItems(pantry, nil)
+	return &result, nil
+}
+
+func (r *pantryRepository) SetPantry(userID int, ingredientIDs []int, version int) (*models.PantryChange, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	pantry := r.store.ensurePantry(userID)
+	if version != 0 && pantry.version != version {
+		return nil, sql.ErrNoRows
+	}
+
+	keep := make(map[int]bool, len(ingredientIDs))
+	for _, ingredientID := range ingredientIDs {
+		keep[ingredientID] = true
+	}
+	var remove []int
+	for ingredientID := range pantry.items {
+		if !keep[ingredientID] {
+			remove = append(remove, ingredientID)
+		}
+	}
+	return r.store.changePantry(pantry, userID, ingredientIDs, remove), nil
+}
+
+func (r *pantryRepository) EditPantry(userID int, add, remove []int) (*models.PantryChange, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	pantry := r.store.ensurePantry(userID)
+	return r.store.changePantry(pantry, userID, add, remove), nil
+}
+
+// pantryOf returns the pantry userID sees, their household's or their own,
+// or nil when they have none yet. Callers must hold mu.
+func (s *Store) pantryOf(userID int) *pantry {
+	householdID := s.householdOf(userID)
+	for _, pantry := range s.pantries {
+		if (householdID != 0 && pantry.householdID == householdID) || (householdID == 0 && pantry.userID == userID) {
+			return pantry
+		}
+	}
+	return nil
+}
+
+// ensurePantry is pantryOf, creating the user's own pantry if need be.
+// Callers must hold mu for writing.
+func (s *Store) ensurePantry(userID int) *pantry {
+	if pantry := s.pantryOf(userID); pantry != nil {
+		return pantry
+	}
+	s.nextPantryID++
+	s.pantries[s.nextPantryID] = &pantry{userID: userID, items: make(map[int]pantryEntry)}
+	return s.pantries[s.nextPantryID]
+}
+
+// household returns the ID of the household the pantry belongs to, or nil.
+func (p *pantry) household() *int {
+	if p.householdID == 0 {
+		return nil
+	}
+	householdID := p.householdID
+	return &householdID
+}
+
+// changePantry removes, then adds, ingredients and counts a version if
+// anything changed. Callers must hold mu for writing.
+func (s *Store) changePantry(pantry *pantry, userID int, add, remove []int) *models.PantryChange {
+	change := models.PantryChange{HouseholdID: pantry.household(), ChangedBy: userID, Added: []models.PantryItem{}, Removed: []int{}}
+	for _, ingredientID := range remove {
+		if _, ok := pantry.items[ingredientID]; ok {
+			delete(pantry.items, ingredientID)
+			change.Removed = append(change.Removed, ingredientID)
+		}
+	}
+	sort.Ints(change.Removed)
+
+	added := make(map[int]bool)
+	for _, ingredientID := range add {
+		if _, ok := pantry.items[ingredientID]; !ok {
+			pantry.items[ingredientID] = pantryEntry{addedBy: userID, addedAt: now()}
+			added[ingredientID] = true
+		}
+	}
+	change.Added = s.pantryItems(pantry, added)
+
+	if !change.Empty() {
+		pantry.version++
+	}
+	change.Version = pantry.version
+	return &change
+}
+
+// pantryItems lists the pantry's items, or those in only when it is not
+// nil, by name. Callers must hold mu.
+func (s *Store) pantryItems(pantry *pantry, only map[int]bool) []models.PantryItem {
+	items := make([]models.PantryItem, 0, len(pantry.items))
+	for ingredientID, entry := range pantry.items {
+		if only != nil && !only[ingredientID] {
+			continue
+		}
+		items = append(items, models.PantryItem{
+			IngredientID: ingredientID,
+			Name:         s.ingredients[ingredientID].Name,
+			AddedBy:      entry.addedBy,
+			AddedAt:      entry.addedAt,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Name == items[j].Name {
+			return items[i].IngredientID < items[j].IngredientID
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+func (r *pantryRepository) FindCookable(ingredientIDs []int, params models.PaginationParams) ([]models.CookableRecipe, int, error) {
+	if len(ingredientIDs) == 0 {
+		return []models.CookableRecipe{}, 0, nil
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	onHand := make(map[int]bool, len(ingredientIDs))
+	for _, id := range ingredientIDs {
+		onHand[id] = true
+	}
+	totals := make(map[int]int)
+	haves := make(map[int]int)
+	for _, ri := range r.store.recipeIngredients {
+		totals[ri.RecipeID]++
+		if onHand[ri.IngredientID] {
+			haves[ri.RecipeID]++
+		}
+	}
+
+	// By name first, so recipes ranked equal stay in name order as in SQL
+	recipes := r.store.recipesWhere(func(recipe models.Recipe) bool { return isPublished(recipe) && haves[recipe.ID] > 0 })
+	cookable := make([]models.CookableRecipe, len(recipes))
+	for i, recipe := range recipes {
+		cookable[i] = models.CookableRecipe{Recipe: recipe, TotalIngredients: totals[recipe.ID], OnHand: haves[recipe.ID]}
+	}
+	sort.SliceStable(cookable, func(i, j int) bool {
+		a, b := cookable[i], cookable[j]
+		if a.OnHand*b.TotalIngredients != b.OnHand*a.TotalIngredients {
+			return a.OnHand*b.TotalIngredients > b.OnHand*a.TotalIngredients
+		}
+		return a.TotalIngredients-a.OnHand < b.TotalIngredients-b.OnHand
+	})
+	return paginate(cookable, params), len(cookable), nil
+}
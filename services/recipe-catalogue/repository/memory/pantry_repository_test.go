@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPantryRepository_SetPantryKeepsWhenItemsWereAdded(t *testing.T) {
+	store := NewStore()
+	store.ingredients[1] = models.Ingredient{ID: 1, Name: "Onion"}
+	store.ingredients[2] = models.Ingredient{ID: 2, Name: "Garlic"}
+	store.ingredients[3] = models.Ingredient{ID: 3, Name: "Rice"}
+	repo := NewPantryRepository(store)
+
+	_, err := repo.SetPantry(7, []int{1, 2}, 0)
+	require.NoError(t, err)
+	before, err := repo.GetPantry(7)
+	require.NoError(t, err)
+
+	change, err := repo.SetPantry(7, []int{2, 3}, before.Version)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, change.Removed)
+	require.Len(t, change.Added, 1)
+	assert.Equal(t, "Rice", change.Added[0].Name)
+	assert.Equal(t, 2, change.Version)
+
+	pantry, err := repo.GetPantry(7)
+	require.NoError(t, err)
+	require.Len(t, pantry.Items, 2)
+	assert.Equal(t, "Garlic", pantry.Items[0].Name)
+	assert.Equal(t, before.Items[0].AddedAt, pantry.Items[0].AddedAt)
+	assert.Equal(t, 7, pantry.Items[0].AddedBy)
+	assert.Equal(t, "Rice", pantry.Items[1].Name)
+	assert.Equal(t, 2, pantry.Version)
+
+	other, err := repo.GetPantry(8)
+	require.NoError(t, err)
+	assert.Empty(t, other.Items)
+	assert.Equal(t, 0, other.Version)
+}
+
+func TestPantryRepository_SetPantryAtStaleVersion(t *testing.T) {
+	store := NewStore()
+	store.ingredients[1] = models.Ingredient{ID: 1, Name: "Onion"}
+	store.ingredients[2] = models.Ingredient{ID: 2, Name: "Garlic"}
+	repo := NewPantryRepository(store)
+	_, err := repo.SetPantry(7, []int{1}, 0)
+	require.NoError(t, err)
+	_, err = repo.EditPantry(7, []int{2}, nil)
+	require.NoError(t, err)
+
+	_, err = repo.SetPantry(7, nil, 1)
+
+	assert.Equal(t, sql.ErrNoRows, err)
+	pantry, err := repo.GetPantry(7)
+	require.NoError(t, err)
+	assert.Len(t, pantry.Items, 2)
+	assert.Equal(t, 2, pantry.Version)
+}
+
+func TestPantryRepository_EditPantryOnlyCountsRealChanges(t *testing.T) {
+	store := NewStore()
+	store.ingredients[1] = models.Ingredient{ID: 1, Name: "Onion"}
+	repo := NewPantryRepository(store)
+	_, err := repo.EditPantry(7, []int{1}, nil)
+	require.NoError(t, err)
+
+	change, err := repo.EditPantry(7, []int{1}, []int{2})
+
+	require.NoError(t, err)
+	assert.True(t, change.Empty())
+	assert.Equal(t, 1, change.Version)
+}
+
+func TestPantryRepository_FindCookable_RanksByCoverage(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, Name: "Fried Rice", Status: models.RecipeStatusPublished}
+	store.recipes[2] = models.Recipe{ID: 2, Name: "Garlic Rice", Status: models.RecipeStatusPublished}
+	store.recipes[3] = models.Recipe{ID: 3, Name: "Pilaf", Status: models.RecipeStatusPublished}
+	store.recipes[4] = models.Recipe{ID: 4, Name: "Draft Rice", Status: models.RecipeStatusDraft}
+	store.recipes[5] = models.Recipe{ID: 5, Name: "Salad", Status: models.RecipeStatusPublished}
+	store.recipes[6] = models.Recipe{ID: 6, Name: "Rice Bowl", Status: models.RecipeStatusPublished}
+	for id, ri := range []models.RecipeIngredient{
+		{RecipeID: 1, IngredientID: 3}, {RecipeID: 1, IngredientID: 4}, {RecipeID: 1, IngredientID: 5}, {RecipeID: 1, IngredientID: 6},
+		{RecipeID: 2, IngredientID: 2}, {RecipeID: 2, IngredientID: 3},
+		{RecipeID: 3, IngredientID: 1}, {RecipeID: 3, IngredientID: 3}, {RecipeID: 3, IngredientID: 7}, {RecipeID: 3, IngredientID: 8},
+		{RecipeID: 4, IngredientID: 3},
+		{RecipeID: 5, IngredientID: 9},
+		{RecipeID: 6, IngredientID: 3}, {RecipeID: 6, IngredientID: 9},
+	} {
+		store.recipeIngredients[id+1] = ri
+	}
+	repo := NewPantryRepository(store)
+
+	recipes, total, err := repo.FindCookable([]int{1, 2, 3}, models.PaginationParams{Page: 1, PerPage: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	require.Len(t, recipes, 4)
+	// Garlic Rice is all on hand. Rice Bowl and Pilaf both have half, but
+	// Rice Bowl is missing less. Fried Rice has a quarter
+	assert.Equal(t, 2, recipes[0].ID)
+	assert.Equal(t, 2, recipes[0].OnHand)
+	assert.Equal(t, 6, recipes[1].ID)
+	assert.Equal(t, 3, recipes[2].ID)
+	assert.Equal(t, 4, recipes[2].TotalIngredients)
+	assert.Equal(t, 1, recipes[3].ID)
+	assert.Equal(t, 1, recipes[3].OnHand)
+}
+
+func TestPantryRepository_DeletedIngredientLeavesPantry(t *testing.T) {
+	store := NewStore()
+	store.ingredients[1] = models.Ingredient{ID: 1, Name: "Onion"}
+	store.ingredients[2] = models.Ingredient{ID: 2, Name: "Garlic"}
+	repo := NewPantryRepository(store)
+	_, err := repo.SetPantry(7, []int{1, 2}, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, NewIngredientRepository(store).DeleteIngredient(1))
+
+	pantry, err := repo.GetPantry(7)
+	require.NoError(t, err)
+	require.Len(t, pantry.Items, 1)
+	assert.Equal(t, 2, pantry.Items[0].IngredientID)
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type prepSessionRepository struct {
+	store *Store
+}
+
+func NewPrepSessionRepository(store *Store) repository.PrepSessionRepository {
+	return &prepSessionRepository{store: store}
+}
+
+func (r *prepSessionRepository) GetByUserID(userID int) ([]models.PrepSession, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	sessions := make([]models.PrepSession, 0)
+	for _, session := range r.store.prepSessions {
+		if session.UserID == userID {
+			sessions = append(sessions, copyPrepSession(session))
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].CreatedAt.Equal(sessions[j].CreatedAt) {
+			return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+		}
+		return sessions[i].ID > sessions[j].ID
+	})
+	return sessions, nil
+}
+
+func (r *prepSessionRepository) GetByID(id int) (*models.PrepSession, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	session, ok := r.store.prepSessions[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	session = copyPrepSession(session)
+	return &session, nil
+}
+
+func (r *prepSessionRepository) Create(userID int, req models.PrepSessionRequest) (*models.PrepSession, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextPrepSessionID++
+	session := models.PrepSession{
+		ID:        r.store.nextPrepSessionID,
+		UserID:    userID,
+		Name:      req.Name,
+		PrepDate:  req.PrepDate,
+		Recipes:   append([]models.PrepSessionRecipe{}, req.Recipes...),
+		CreatedAt: now(),
+		UpdatedAt: now(),
+	}
+	r.store.prepSessions[session.ID] = session
+
+	session = copyPrepSession(session)
+	return &session, nil
+}
+
+func (r *prepSessionRepository) Update(id int, req models.PrepSessionRequest) (*models.PrepSession, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	session, ok := r.store.prepSessions[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	session.Name = req.Name
+	session.PrepDate = req.PrepDate
+	session.Recipes = append([]models.PrepSessionRecipe{}, req.Recipes...)
+	session.UpdatedAt = now()
+	r.store.prepSessions[id] = session
+
+	session = copyPrepSession(session)
+	return &session, nil
+}
+
+func (r *prepSessionRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.prepSessions[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.prepSessions, id)
+	return nil
+}
+
+// copyPrepSession keeps callers from mutating the stored recipes slice.
+func copyPrepSession(session models.PrepSession) models.PrepSession {
+	session.Recipes = append([]models.PrepSessionRecipe{}, session.Recipes...)
+	return session
+}
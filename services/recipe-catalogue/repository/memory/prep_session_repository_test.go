@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepSessionRepository_Lifecycle(t *testing.T) {
+	repo := NewPrepSessionRepository(NewStore())
+
+	created, err := repo.Create(7, models.PrepSessionRequest{
+		Name:    "Sunday prep",
+		Recipes: []models.PrepSessionRecipe{{RecipeID: 1, Scale: 2, Portions: 6}},
+	})
+	require.NoError(t, err)
+
+	_, err = repo.Create(8, models.PrepSessionRequest{Name: "Someone else's"})
+	require.NoError(t, err)
+
+	updated, err := repo.Update(created.ID, models.PrepSessionRequest{
+		Name:     "Weekday lunches",
+		PrepDate: "2026-10-18",
+		Recipes:  []models.PrepSessionRecipe{{RecipeID: 2, Scale: 1, Portions: 4}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.PrepSessionRecipe{{RecipeID: 2, Scale: 1, Portions: 4}}, updated.Recipes)
+
+	sessions, err := repo.GetByUserID(7)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "Weekday lunches", sessions[0].Name)
+	assert.Equal(t, "2026-10-18", sessions[0].PrepDate)
+
+	require.NoError(t, repo.Delete(created.ID))
+	_, err = repo.GetByID(created.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestPrepSessionRepository_DeletingRecipeRemovesItFromSessions(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, UserID: 7, Name: "Chili"}
+	store.recipes[2] = models.Recipe{ID: 2, UserID: 7, Name: "Rice"}
+	repo := NewPrepSessionRepository(store)
+
+	session, err := repo.Create(7, models.PrepSessionRequest{
+		Name: "Sunday prep",
+		Recipes: []models.PrepSessionRecipe{
+			{RecipeID: 1, Scale: 1, Portions: 4},
+			{RecipeID: 2, Scale: 1, Portions: 4},
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, NewRecipeRepository(store).Delete(2))
+
+	got, err := repo.GetByID(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []models.PrepSessionRecipe{{RecipeID: 1, Scale: 1, Portions: 4}}, got.Recipes)
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"database/sql"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type profileRepository struct {
+	store *Store
+}
+
+func NewProfileRepository(store *Store) repository.ProfileRepository {
+	return &profileRepository{store: store}
+}
+
+func (r *profileRepository) GetSettings(userID int) (*models.ProfileSettings, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	settings, ok := r.store.profiles[userID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &settings, nil
+}
+
+func (r *profileRepository) SaveSettings(settings models.ProfileSettings) (*models.ProfileSettings, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	settings.UpdatedAt = now()
+	r.store.profiles[settings.UserID] = settings
+	return &settings, nil
+}
+
+// GetRatingSummary reports no ratings: they are recorded by the
+// recommendations service, which has no in-memory mode.
+func (r *profileRepository) GetRatingSummary(userID int) (*float64, int, error) {
+	return nil, 0, nil
+}
@@ -0,0 +1,29 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileRepository_SaveAndGetSettings(t *testing.T) {
+	repo := NewProfileRepository(NewStore())
+
+	_, err := repo.GetSettings(3)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	name := "Hanna"
+	saved, err := repo.SaveSettings(models.ProfileSettings{UserID: 3, DisplayName: &name, IsPublic: true})
+	require.NoError(t, err)
+	assert.False(t, saved.UpdatedAt.IsZero())
+
+	settings, err := repo.GetSettings(3)
+	require.NoError(t, err)
+	assert.Equal(t, "Hanna", *settings.DisplayName)
+	assert.True(t, settings.IsPublic)
+	assert.False(t, settings.ShowStats)
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type recipeImageRepository struct {
+	store *Store
+}
+
+func NewRecipeImageRepository(store *Store) repository.RecipeImageRepository {
+	return &recipeImageRepository{store: store}
+}
+
+func (r *recipeImageRepository) GetByRecipeID(recipeID int) ([]models.RecipeImage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.imagesOf(recipeID), nil
+}
+
+func (r *recipeImageRepository) GetByRecipeIDs(recipeIDs []int) (map[int][]models.RecipeImage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	result := make(map[int][]models.RecipeImage)
+	for _, recipeID := range recipeIDs {
+		if images := r.imagesOf(recipeID); len(images) > 0 {
+			result[recipeID] = images
+		}
+	}
+	return result, nil
+}
+
+func (r *recipeImageRepository) Add(recipeID int, req models.AddRecipeImageRequest) (*models.RecipeImage, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing := r.imagesOf(recipeID)
+	hasCover := false
+	for _, image := range existing {
+		hasCover = hasCover || image.IsCover
+	}
+
+	r.store.nextRecipeImageID++
+	image := models.RecipeImage{
+		ID:           r.store.nextRecipeImageID,
+		RecipeID:     recipeID,
+		URL:          req.URL,
+		ThumbnailURL: req.ThumbnailURL,
+		Caption:      req.Caption,
+		Position:     len(existing) + 1,
+		IsCover:      !hasCover,
+		CreatedAt:    now(),
+		StorageKey:   req.StorageKey,
+	}
+	r.store.recipeImages[image.ID] = image
+
+	return &image, nil
+}
+
+func (r *recipeImageRepository) Save(recipeID int, images []models.RecipeImage) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, image := range images {
+		stored, ok := r.store.recipeImages[image.ID]
+		if !ok || stored.RecipeID != recipeID {
+			continue
+		}
+		stored.Position = image.Position
+		stored.Caption = image.Caption
+		stored.IsCover = image.IsCover
+		r.store.recipeImages[image.ID] = stored
+	}
+	return nil
+}
+
+func (r *recipeImageRepository) Delete(recipeID, imageID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	deleted, ok := r.store.recipeImages[imageID]
+	if !ok || deleted.RecipeID != recipeID {
+		return sql.ErrNoRows
+	}
+	delete(r.store.recipeImages, imageID)
+
+	for i, image := range r.imagesOf(recipeID) {
+		image.Position = i + 1
+		if deleted.IsCover && i == 0 {
+			image.IsCover = true
+		}
+		r.store.recipeImages[image.ID] = image
+	}
+	return nil
+}
+
+// imagesOf returns the images of a recipe in display order. Callers must hold mu.
+func (r *recipeImageRepository) imagesOf(recipeID int) []models.RecipeImage {
+	images := make([]models.RecipeImage, 0)
+	for _, image := range r.store.recipeImages {
+		if image.RecipeID == recipeID {
+			images = append(images, image)
+		}
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Position != images[j].Position {
+			return images[i].Position < images[j].Position
+		}
+		return images[i].ID < images[j].ID
+	})
+	return images
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeImageRepository_CoverFollowsFirstImage(t *testing.T) {
+	repo := NewRecipeImageRepository(NewStore())
+
+	first, err := repo.Add(1, models.AddRecipeImageRequest{URL: "https://img.example/1.jpg"})
+	require.NoError(t, err)
+	second, err := repo.Add(1, models.AddRecipeImageRequest{URL: "https://img.example/2.jpg"})
+	require.NoError(t, err)
+	third, err := repo.Add(1, models.AddRecipeImageRequest{URL: "https://img.example/3.jpg"})
+	require.NoError(t, err)
+
+	assert.True(t, first.IsCover)
+	assert.False(t, second.IsCover)
+	assert.Equal(t, 3, third.Position)
+
+	// Deleting the cover renumbers and promotes the new first image
+	require.NoError(t, repo.Delete(1, first.ID))
+	images, err := repo.GetByRecipeID(1)
+	require.NoError(t, err)
+	require.Len(t, images, 2)
+	assert.Equal(t, second.ID, images[0].ID)
+	assert.True(t, images[0].IsCover)
+	assert.Equal(t, 2, images[1].Position)
+
+	assert.Equal(t, sql.ErrNoRows, repo.Delete(2, second.ID))
+}
+
+func TestRecipeImageRepository_Save(t *testing.T) {
+	repo := NewRecipeImageRepository(NewStore())
+	first, _ := repo.Add(1, models.AddRecipeImageRequest{URL: "https://img.example/1.jpg"})
+	second, _ := repo.Add(1, models.AddRecipeImageRequest{URL: "https://img.example/2.jpg"})
+
+	caption := "Plated"
+	require.NoError(t, repo.Save(1, []models.RecipeImage{
+		{ID: second.ID, Position: 1, Caption: &caption, IsCover: true},
+		{ID: first.ID, Position: 2},
+	}))
+
+	images, err := repo.GetByRecipeID(1)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, images[0].ID)
+	assert.True(t, images[0].IsCover)
+	assert.Equal(t, "Plated", *images[0].Caption)
+	assert.False(t, images[1].IsCover)
+	assert.Equal(t, "https://img.example/1.jpg", images[1].URL)
+}
+
+func TestRecipeImageRepository_GetByRecipeIDs(t *testing.T) {
+	repo := NewRecipeImageRepository(NewStore())
+	key := "recipes/1/a.png"
+	thumb := "http://localhost:8000/images/recipes/1/a-thumb.jpg"
+	_, err := repo.Add(1, models.AddRecipeImageRequest{URL: "https://img.example/1.png", ThumbnailURL: &thumb, StorageKey: &key})
+	require.NoError(t, err)
+	_, err = repo.Add(2, models.AddRecipeImageRequest{URL: "https://img.example/2.jpg"})
+	require.NoError(t, err)
+	_, err = repo.Add(3, models.AddRecipeImageRequest{URL: "https://img.example/3.jpg"})
+	require.NoError(t, err)
+
+	images, err := repo.GetByRecipeIDs([]int{1, 2, 4})
+	require.NoError(t, err)
+	assert.Len(t, images, 2)
+	assert.Equal(t, thumb, *images[1][0].ThumbnailURL)
+	assert.Equal(t, key, *images[1][0].StorageKey)
+	assert.Equal(t, "https://img.example/2.jpg", images[2][0].URL)
+	assert.NotContains(t, images, 3)
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type recipeOwnershipRepository struct {
+	store *Store
+}
+
+func NewRecipeOwnershipRepository(store *Store) repository.RecipeOwnershipRepository {
+	return &recipeOwnershipRepository{store: store}
+}
+
+func (r *recipeOwnershipRepository) ListOwners(inactiveSince time.Time, params models.PaginationParams) ([]models.RecipeOwnership, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byUser := make(map[int]*models.RecipeOwnership)
+	for _, recipe := range r.store.recipes {
+		owner, ok := byUser[recipe.UserID]
+		if !ok {
+			owner = &models.RecipeOwnership{
+				UserID:         recipe.UserID,
+				FirstRecipeAt:  recipe.CreatedAt,
+				LastActivityAt: recipe.UpdatedAt,
+			}
+			if settings, ok := r.store.profiles[recipe.UserID]; ok {
+				owner.DisplayName = settings.DisplayName
+			}
+			byUser[recipe.UserID] = owner
+		}
+
+		owner.RecipeCount++
+		switch recipe.Status {
+		case models.RecipeStatusPublished:
+			owner.PublishedCount++
+		case models.RecipeStatusDraft:
+			owner.DraftCount++
+		case models.RecipeStatusPrivate:
+			owner.PrivateCount++
+		case models.RecipeStatusArchived:
+			owner.ArchivedCount++
+		}
+		if recipe.CreatedAt.Before(owner.FirstRecipeAt) {
+			owner.FirstRecipeAt = recipe.CreatedAt
+		}
+		if recipe.UpdatedAt.After(owner.LastActivityAt) {
+			owner.LastActivityAt = recipe.UpdatedAt
+		}
+	}
+
+	owners := make([]models.RecipeOwnership, 0, len(byUser))
+	for _, owner := range byUser {
+		if inactiveSince.IsZero() || owner.LastActivityAt.Before(inactiveSince) {
+			owners = append(owners, *owner)
+		}
+	}
+
+	sort.Slice(owners, func(i, j int) bool {
+		if !owners[i].LastActivityAt.Equal(owners[j].LastActivityAt) {
+			return owners[i].LastActivityAt.Before(owners[j].LastActivityAt)
+		}
+		return owners[i].UserID < owners[j].UserID
+	})
+	return paginate(owners, params), len(owners), nil
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeOwnershipRepository_GroupsRecipesByOwner(t *testing.T) {
+	store := NewStore()
+	old := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	name := "Grandma"
+	store.profiles[7] = models.ProfileSettings{UserID: 7, DisplayName: &name}
+	store.recipes[1] = models.Recipe{ID: 1, UserID: 7, Status: models.RecipeStatusPublished, CreatedAt: old, UpdatedAt: old}
+	store.recipes[2] = models.Recipe{ID: 2, UserID: 7, Status: models.RecipeStatusDraft, CreatedAt: old.AddDate(0, 1, 0), UpdatedAt: old.AddDate(0, 2, 0)}
+	store.recipes[3] = models.Recipe{ID: 3, UserID: 9, Status: models.RecipeStatusPrivate, CreatedAt: recent, UpdatedAt: recent}
+	repo := NewRecipeOwnershipRepository(store)
+
+	owners, total, err := repo.ListOwners(time.Time{}, models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, owners, 2)
+	assert.Equal(t, 7, owners[0].UserID)
+	assert.Equal(t, &name, owners[0].DisplayName)
+	assert.Equal(t, 2, owners[0].RecipeCount)
+	assert.Equal(t, 1, owners[0].PublishedCount)
+	assert.Equal(t, 1, owners[0].DraftCount)
+	assert.Equal(t, old, owners[0].FirstRecipeAt)
+	assert.Equal(t, old.AddDate(0, 2, 0), owners[0].LastActivityAt)
+	assert.Equal(t, 9, owners[1].UserID)
+	assert.Nil(t, owners[1].DisplayName)
+	assert.Equal(t, 1, owners[1].PrivateCount)
+
+	inactive, total, err := repo.ListOwners(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, inactive, 1)
+	assert.Equal(t, 7, inactive[0].UserID)
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type recipeQualityRepository struct {
+	store *Store
+}
+
+func NewRecipeQualityRepository(store *Store) repository.RecipeQualityRepository {
+	return &recipeQualityRepository{store: store}
+}
+
+func (r *recipeQualityRepository) GetFacts(recipeID int) (*models.RecipeQualityFacts, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recipe, ok := r.store.recipes[recipeID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	facts := r.facts(recipe)
+	return &facts, nil
+}
+
+func (r *recipeQualityRepository) ListFacts(status string) ([]models.RecipeQualityFacts, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	facts := make([]models.RecipeQualityFacts, 0)
+	for _, recipe := range r.store.recipes {
+		if status == "" || recipe.Status == status {
+			facts = append(facts, r.facts(recipe))
+		}
+	}
+
+	sort.Slice(facts, func(i, j int) bool { return facts[i].ID < facts[j].ID })
+	return facts, nil
+}
+
+// facts must be called with the store lock held.
+func (r *recipeQualityRepository) facts(recipe models.Recipe) models.RecipeQualityFacts {
+	imageCount := 0
+	for _, image := range r.store.recipeImages {
+		if image.RecipeID == recipe.ID {
+			imageCount++
+		}
+	}
+	return models.RecipeQualityFacts{
+		Recipe:     recipe,
+		StepCount:  len(r.store.recipeSteps[recipe.ID]),
+		ImageCount: imageCount,
+	}
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeQualityRepository_CountsStepsAndImages(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, UserID: 7, Name: "Pasta", Status: models.RecipeStatusPublished}
+	store.recipes[2] = models.Recipe{ID: 2, UserID: 7, Name: "Draft", Status: models.RecipeStatusDraft}
+	repo := NewRecipeQualityRepository(store)
+
+	_, err := NewStepRepository(store).ReplaceForRecipe(1, []models.CreateStepRequest{{Instruction: "Boil"}, {Instruction: "Serve"}})
+	require.NoError(t, err)
+	_, err = NewRecipeImageRepository(store).Add(1, models.AddRecipeImageRequest{URL: "https://example.com/pasta.jpg"})
+	require.NoError(t, err)
+
+	facts, err := repo.GetFacts(1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, facts.StepCount)
+	assert.Equal(t, 1, facts.ImageCount)
+
+	_, err = repo.GetFacts(3)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	all, err := repo.ListFacts("")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, 1, all[0].ID)
+
+	drafts, err := repo.ListFacts(models.RecipeStatusDraft)
+	require.NoError(t, err)
+	require.Len(t, drafts, 1)
+	assert.Equal(t, 0, drafts[0].StepCount)
+}
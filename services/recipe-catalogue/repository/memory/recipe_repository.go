@@ -0,0 +1,440 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type recipeRepository struct {
+	store *Store
+}
+
+func NewRecipeRepository(store *Store) repository.RecipeRepository {
+	return &recipeRepository{store: store}
+}
+
+func (r *recipeRepository) GetAll(params models.PaginationParams) ([]models.Recipe, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recipes := r.store.recipesWhere(isPublished)
+	return paginate(recipes, params), len(recipes), nil
+}
+
+func (r *recipeRepository) GetByID(id int) (*models.Recipe, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recipe, ok := r.store.recipes[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	recipe = r.store.withCategory(recipe)
+	return &recipe, nil
+}
+
+func (r *recipeRepository) GetByCategory(categoryID int, params models.PaginationParams) ([]models.Recipe, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recipes := r.store.recipesWhere(func(recipe models.Recipe) bool {
+		return isPublished(recipe) && recipe.CategoryID != nil && *recipe.CategoryID == categoryID
+	})
+	return paginate(recipes, params), len(recipes), nil
+}
+
+func (r *recipeRepository) GetByOwner(userID int, status string, params models.PaginationParams) ([]models.Recipe, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recipes := r.store.recipesWhere(func(recipe models.Recipe) bool {
+		return recipe.UserID == userID && (status == "" || recipe.Status == status)
+	})
+	sort.SliceStable(recipes, func(i, j int) bool {
+		if recipes[i].UpdatedAt.Equal(recipes[j].UpdatedAt) {
+			return recipes[i].ID > recipes[j].ID
+		}
+		return recipes[i].UpdatedAt.After(recipes[j].UpdatedAt)
+	})
+	return paginate(recipes, params), len(recipes), nil
+}
+
+func (r *recipeRepository) GetOwnerID(id int) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recipe, ok := r.store.recipes[id]
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+	return recipe.UserID, nil
+}
+
+func (r *recipeRepository) Create(userID int, req models.CreateRecipeRequest) (*models.Recipe, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	recipe := r.insertRecipe(userID, req.Name, &req.Description, req.CategoryID, req.Status, req.Difficulty, req.TotalTimeMinutes)
+	return &recipe, nil
+}
+
+func (r *recipeRepository) Update(id int, req models.UpdateRecipeRequest) (*models.Recipe, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	recipe, ok := r.store.recipes[id]
+	if !ok || !versionMatches(recipe, req.Version) {
+		return nil, sql.ErrNoRows
+	}
+
+	description, categoryID := req.Description, req.CategoryID
+	recipe.Name = req.Name
+	recipe.Description = &description
+	recipe.CategoryID = &categoryID
+	if req.Status != "" {
+		recipe.Status = req.Status
+	}
+	if req.Difficulty != nil {
+		recipe.Difficulty = req.Difficulty
+	}
+	if req.TotalTimeMinutes != nil {
+		recipe.TotalTimeMinutes = req.TotalTimeMinutes
+	}
+	recipe.Version++
+	recipe.UpdatedAt = now()
+	r.store.recipes[id] = recipe
+
+	return &recipe, nil
+}
+
+func (r *recipeRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.recipes[id]; !ok {
+		return sql.ErrNoRows
+	}
+
+	r.store.deleteRecipeIngredients(id)
+	delete(r.store.recipeSteps, id)
+	delete(r.store.techniques, id)
+	for imageID, image := range r.store.recipeImages {
+		if image.RecipeID == id {
+			delete(r.store.recipeImages, imageID)
+		}
+	}
+	for token, session := range r.store.cookingSessions {
+		if session.RecipeID == id {
+			delete(r.store.cookingSessions, token)
+		}
+	}
+	for activityID, activity := range r.store.activities {
+		if activity.RecipeID == id {
+			delete(r.store.activities, activityID)
+		}
+	}
+	for key := range r.store.favorites {
+		if key.recipeID == id {
+			delete(r.store.favorites, key)
+		}
+	}
+	for templateID, template := range r.store.templates {
+		if template.RecipeID == id {
+			delete(r.store.templates, templateID)
+		}
+	}
+	for planID, plan := range r.store.mealPlans {
+		entries := make([]models.MealPlanEntry, 0, len(plan.Entries))
+		for _, entry := range plan.Entries {
+			if entry.RecipeID != id {
+				entries = append(entries, entry)
+			}
+		}
+		plan.Entries = entries
+		r.store.mealPlans[planID] = plan
+	}
+	delete(r.store.lineage, id)
+	delete(r.store.costSnapshots, id)
+	for sessionID, session := range r.store.prepSessions {
+		kept := session.Recipes[:0]
+		for _, recipe := range session.Recipes {
+			if recipe.RecipeID != id {
+				kept = append(kept, recipe)
+			}
+		}
+		session.Recipes = kept
+		r.store.prepSessions[sessionID] = session
+	}
+	for forkID, link := range r.store.lineage {
+		if link.parentID == id {
+			link.parentID = 0
+			r.store.lineage[forkID] = link
+		}
+	}
+	delete(r.store.recipes, id)
+	return nil
+}
+
+func (r *recipeRepository) GetAllWithIngredients(params models.PaginationParams) ([]models.RecipeWithIngredients, int, error) {
+	recipes, total, err := r.GetAll(params)
+	if err != nil {
+		return nil, 0, err
+	}
+	return r.attachIngredients(recipes), total, nil
+}
+
+func (r *recipeRepository) GetByIDWithIngredients(id int) (*models.RecipeWithIngredients, error) {
+	recipe, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return &models.RecipeWithIngredients{
+		Recipe:      *recipe,
+		Ingredients: r.store.recipeIngredientsFor(id),
+		Steps:       copySteps(r.store.recipeSteps[id]),
+	}, nil
+}
+
+func (r *recipeRepository) GetByCategoryWithIngredients(categoryID int, params models.PaginationParams) ([]models.RecipeWithIngredients, int, error) {
+	recipes, total, err := r.GetByCategory(categoryID, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	return r.attachIngredients(recipes), total, nil
+}
+
+func (r *recipeRepository) CreateWithIngredients(userID int, req models.CreateRecipeWithIngredientsRequest) (*models.RecipeWithIngredients, error) {
+	r.store.mu.Lock()
+	ingredients, err := r.store.matchOrCreateIngredients(req.Ingredients)
+	if err != nil {
+		r.store.mu.Unlock()
+		return nil, err
+	}
+	recipe := r.insertRecipe(userID, req.Name, req.Description, req.CategoryID, req.Status, req.Difficulty, req.TotalTimeMinutes)
+	r.store.replaceRecipeIngredients(recipe.ID, ingredients)
+	r.store.mu.Unlock()
+
+	return r.GetByIDWithIngredients(recipe.ID)
+}
+
+func (r *recipeRepository) UpdateWithIngredients(id int, req models.UpdateRecipeWithIngredientsRequest) (*models.RecipeWithIngredients, error) {
+	r.store.mu.Lock()
+	recipe, ok := r.store.recipes[id]
+	if !ok || !versionMatches(recipe, req.Version) {
+		r.store.mu.Unlock()
+		return nil, sql.ErrNoRows
+	}
+
+	// Same partial-update semantics as the SQL repository: empty name and zero
+	// category keep the current values, nil ingredients leave them untouched.
+	if req.Name != "" {
+		recipe.Name = req.Name
+	}
+	description := req.Description
+	recipe.Description = &description
+	if req.CategoryID != 0 {
+		categoryID := req.CategoryID
+		recipe.CategoryID = &categoryID
+	}
+	if req.Status != "" {
+		recipe.Status = req.Status
+	}
+	if req.Difficulty != nil {
+		recipe.Difficulty = req.Difficulty
+	}
+	if req.TotalTimeMinutes != nil {
+		recipe.TotalTimeMinutes = req.TotalTimeMinutes
+	}
+	recipe.Version++
+	recipe.UpdatedAt = now()
+	r.store.recipes[id] = recipe
+
+	if req.Ingredients != nil {
+		r.store.replaceRecipeIngredients(id, req.Ingredients)
+	}
+	r.store.mu.Unlock()
+
+	return r.GetByIDWithIngredients(id)
+}
+
+func (r *recipeRepository) SearchRecipesByIngredients(ingredientIDs []int, params models.PaginationParams) ([]models.Recipe, int, error) {
+	if len(ingredientIDs) == 0 {
+		return []models.Recipe{}, 0, nil
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recipes := r.recipesWithIngredients(ingredientIDs)
+	return paginate(recipes, params), len(recipes), nil
+}
+
+// recipesWithIngredients returns the published recipes using any of the
+// ingredients. Callers must hold mu.
+func (r *recipeRepository) recipesWithIngredients(ingredientIDs []int) []models.Recipe {
+	wanted := make(map[int]bool, len(ingredientIDs))
+	for _, id := range ingredientIDs {
+		wanted[id] = true
+	}
+
+	matches := make(map[int]bool)
+	for _, ri := range r.store.recipeIngredients {
+		if wanted[ri.IngredientID] {
+			matches[ri.RecipeID] = true
+		}
+	}
+
+	return r.store.recipesWhere(func(recipe models.Recipe) bool { return isPublished(recipe) && matches[recipe.ID] })
+}
+
+func (r *recipeRepository) SearchRecipesByIngredientsWithIngredients(ingredientIDs []int, params models.PaginationParams) ([]models.RecipeWithIngredients, int, error) {
+	recipes, total, err := r.SearchRecipesByIngredients(ingredientIDs, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	return r.attachIngredients(recipes), total, nil
+}
+
+func (r *recipeRepository) GetSearchFacets(ingredientIDs []int, maxMinutes []int) (*models.RecipeFacets, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byCategory := make(map[int]*models.CategoryFacet)
+	recipesByTime := make(map[int]int)
+	for _, recipe := range r.recipesWithIngredients(ingredientIDs) {
+		if recipe.Category != nil {
+			facet, ok := byCategory[recipe.Category.ID]
+			if !ok {
+				facet = &models.CategoryFacet{CategoryID: recipe.Category.ID, Name: recipe.Category.Name}
+				byCategory[recipe.Category.ID] = facet
+			}
+			facet.Count++
+		}
+		if recipe.TotalTimeMinutes != nil {
+			recipesByTime[*recipe.TotalTimeMinutes]++
+		}
+	}
+
+	facets := &models.RecipeFacets{Categories: make([]models.CategoryFacet, 0, len(byCategory)), MaxTime: repository.CountTimeBuckets(recipesByTime, maxMinutes)}
+	for _, facet := range byCategory {
+		facets.Categories = append(facets.Categories, *facet)
+	}
+	sort.Slice(facets.Categories, func(i, j int) bool {
+		if facets.Categories[i].Count != facets.Categories[j].Count {
+			return facets.Categories[i].Count > facets.Categories[j].Count
+		}
+		return facets.Categories[i].Name < facets.Categories[j].Name
+	})
+	return facets, nil
+}
+
+// insertRecipe stores a new recipe. Callers must hold mu for writing.
+func (r *recipeRepository) insertRecipe(userID int, name string, description *string, categoryID int, status string, difficulty *string, totalTimeMinutes *int) models.Recipe {
+	if status == "" {
+		status = models.RecipeStatusPublished
+	}
+
+	r.store.nextRecipeID++
+	recipe := models.Recipe{
+		ID:          r.store.nextRecipeID,
+		UserID:      userID,
+		Name:        name,
+		Description: description,
+		CategoryID:  &categoryID,
+		CreatedAt:   now(),
+		UpdatedAt:   now(),
+		Status:      status,
+		Version:     1,
+
+		Difficulty:       difficulty,
+		TotalTimeMinutes: totalTimeMinutes,
+	}
+	r.store.recipes[recipe.ID] = recipe
+
+	return recipe
+}
+
+// versionMatches is the SQL repository's version precondition: nil matches
+// any version.
+func versionMatches(recipe models.Recipe, version *int) bool {
+	return version == nil || recipe.Version == *version
+}
+
+func (r *recipeRepository) attachIngredients(recipes []models.Recipe) []models.RecipeWithIngredients {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	result := make([]models.RecipeWithIngredients, 0, len(recipes))
+	for _, recipe := range recipes {
+		ingredients := r.store.recipeIngredientsFor(recipe.ID)
+		if ingredients == nil {
+			ingredients = []models.RecipeIngredient{}
+		}
+		result = append(result, models.RecipeWithIngredients{
+			Recipe:      recipe,
+			Ingredients: ingredients,
+		})
+	}
+	return result
+}
+
+func (r *recipeRepository) GetPublishedRecipes() ([]models.PublishedRecipe, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recipes := make([]models.PublishedRecipe, 0)
+	for _, recipe := range r.store.recipes {
+		if isPublished(recipe) {
+			recipes = append(recipes, models.PublishedRecipe{ID: recipe.ID, UpdatedAt: recipe.UpdatedAt})
+		}
+	}
+	sort.Slice(recipes, func(i, j int) bool { return recipes[i].ID < recipes[j].ID })
+	return recipes, nil
+}
+
+func (r *recipeRepository) GetRecentlyUpdated(limit int) ([]models.Recipe, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recipes := r.store.recipesWhere(isPublished)
+	sort.SliceStable(recipes, func(i, j int) bool {
+		if recipes[i].UpdatedAt.Equal(recipes[j].UpdatedAt) {
+			return recipes[i].ID > recipes[j].ID
+		}
+		return recipes[i].UpdatedAt.After(recipes[j].UpdatedAt)
+	})
+	if len(recipes) > limit {
+		recipes = recipes[:limit]
+	}
+	return recipes, nil
+}
+
+func (r *recipeRepository) GetCreatedSince(since time.Time) ([]models.Recipe, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recipes := r.store.recipesWhere(func(recipe models.Recipe) bool {
+		return isPublished(recipe) && recipe.CreatedAt.After(since)
+	})
+	sort.SliceStable(recipes, func(i, j int) bool {
+		if recipes[i].CreatedAt.Equal(recipes[j].CreatedAt) {
+			return recipes[i].ID < recipes[j].ID
+		}
+		return recipes[i].CreatedAt.Before(recipes[j].CreatedAt)
+	})
+	return recipes, nil
+}
+
+// isPublished matches the recipes that public listings and search may return.
+func isPublished(recipe models.Recipe) bool {
+	return recipe.Status == models.RecipeStatusPublished
+}
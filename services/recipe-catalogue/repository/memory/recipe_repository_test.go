@@ -0,0 +1,358 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecipeRepositoryTestSuite struct {
+	suite.Suite
+	store          *Store
+	repo           repository.RecipeRepository
+	ingredientRepo repository.IngredientRepository
+}
+
+func (suite *RecipeRepositoryTestSuite) SetupTest() {
+	suite.store = NewStore()
+	suite.store.SeedDefaults()
+	suite.repo = NewRecipeRepository(suite.store)
+	suite.ingredientRepo = NewIngredientRepository(suite.store)
+}
+
+// =============================================================================
+// BASIC CRUD OPERATIONS
+// =============================================================================
+
+func (suite *RecipeRepositoryTestSuite) TestCreate_AssignsIDsAndAttachesCategory() {
+	first, err := suite.repo.Create(1, models.CreateRecipeRequest{Name: "Pasta", CategoryID: 1})
+	require.NoError(suite.T(), err)
+	second, err := suite.repo.Create(1, models.CreateRecipeRequest{Name: "Salad", CategoryID: 2})
+	require.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), 1, first.ID)
+	assert.Equal(suite.T(), 2, second.ID)
+
+	fetched, err := suite.repo.GetByID(first.ID)
+	require.NoError(suite.T(), err)
+	require.NotNil(suite.T(), fetched.Category)
+	assert.Equal(suite.T(), "Meat", fetched.Category.Name)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestGetByID_NotFound() {
+	recipe, err := suite.repo.GetByID(999)
+
+	assert.Nil(suite.T(), recipe)
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestGetAll_OrdersByNameAndPaginates() {
+	for _, name := range []string{"Tacos", "Curry", "Omelette"} {
+		_, err := suite.repo.Create(1, models.CreateRecipeRequest{Name: name, CategoryID: 1})
+		require.NoError(suite.T(), err)
+	}
+
+	recipes, total, err := suite.repo.GetAll(models.PaginationParams{Page: 1, PerPage: 2})
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, total)
+	require.Len(suite.T(), recipes, 2)
+	assert.Equal(suite.T(), "Curry", recipes[0].Name)
+	assert.Equal(suite.T(), "Omelette", recipes[1].Name)
+
+	recipes, _, err = suite.repo.GetAll(models.PaginationParams{Page: 3, PerPage: 2})
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), recipes)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestDelete_RemovesRecipeAndItsIngredients() {
+	created, err := suite.repo.CreateWithIngredients(1, models.CreateRecipeWithIngredientsRequest{
+		Name:        "Fried Rice",
+		CategoryID:  1,
+		Ingredients: []models.AddRecipeIngredientRequest{{IngredientID: 4, Quantity: 200, Unit: "g"}},
+	})
+	require.NoError(suite.T(), err)
+
+	require.NoError(suite.T(), suite.repo.Delete(created.ID))
+
+	_, err = suite.repo.GetByID(created.ID)
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+	ingredients, err := suite.ingredientRepo.GetRecipeIngredients(created.ID)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), ingredients)
+
+	assert.Equal(suite.T(), sql.ErrNoRows, suite.repo.Delete(created.ID))
+}
+
+// =============================================================================
+// RECIPES WITH INGREDIENTS
+// =============================================================================
+
+func (suite *RecipeRepositoryTestSuite) TestCreateWithIngredients_ReturnsIngredientDetails() {
+	created, err := suite.repo.CreateWithIngredients(7, models.CreateRecipeWithIngredientsRequest{
+		Name:       "Garlic Chicken",
+		CategoryID: 2,
+		Ingredients: []models.AddRecipeIngredientRequest{
+			{IngredientID: 1, Quantity: 2, Unit: "pieces"},
+			{IngredientID: 6, Quantity: 3, Unit: "cloves"},
+		},
+	})
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 7, created.UserID)
+	require.Len(suite.T(), created.Ingredients, 2)
+	assert.Equal(suite.T(), "Chicken Breast", created.Ingredients[0].Ingredient.Name)
+	assert.Equal(suite.T(), "Garlic", created.Ingredients[1].Ingredient.Name)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestCreateWithIngredients_MatchesOrCreatesNamedIngredients() {
+	created, err := suite.repo.CreateWithIngredients(1, models.CreateRecipeWithIngredientsRequest{
+		Name:       "Shakshuka",
+		CategoryID: 4,
+		Ingredients: []models.AddRecipeIngredientRequest{
+			{IngredientName: "egg", Quantity: 4, Unit: "pieces"},
+			{IngredientName: "onions", Quantity: 1, Unit: "pieces"},
+			{IngredientName: "Sumac", IngredientCategory: "Spices", Quantity: 1, Unit: "tsp"},
+		},
+	})
+
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), created.Ingredients, 3)
+	assert.Equal(suite.T(), "Eggs", created.Ingredients[0].Ingredient.Name)
+	assert.Equal(suite.T(), "Onion", created.Ingredients[1].Ingredient.Name)
+	assert.Equal(suite.T(), "Sumac", created.Ingredients[2].Ingredient.Name)
+	assert.Equal(suite.T(), "Spices", *created.Ingredients[2].Ingredient.Category)
+
+	// The next recipe finds the new ingredient
+	again, err := suite.repo.CreateWithIngredients(1, models.CreateRecipeWithIngredientsRequest{
+		Name:        "Fattoush",
+		CategoryID:  2,
+		Ingredients: []models.AddRecipeIngredientRequest{{IngredientName: "sumac", Quantity: 1, Unit: "tsp"}},
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), created.Ingredients[2].IngredientID, again.Ingredients[0].IngredientID)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestCreateWithIngredients_NamedIngredientsAreAllOrNothing() {
+	_, err := suite.repo.CreateWithIngredients(1, models.CreateRecipeWithIngredientsRequest{
+		Name:       "Mystery Stew",
+		CategoryID: 2,
+		Ingredients: []models.AddRecipeIngredientRequest{
+			{IngredientName: "Sumac", IngredientCategory: "Spices", Quantity: 1, Unit: "tsp"},
+			{IngredientName: "Dragonfruit", Quantity: 1, Unit: "pieces"},
+		},
+	})
+	assert.Equal(suite.T(), domain.ErrIngredientCategoryRequired, err)
+
+	_, err = suite.repo.CreateWithIngredients(1, models.CreateRecipeWithIngredientsRequest{
+		Name:       "Garlic Soup",
+		CategoryID: 2,
+		Ingredients: []models.AddRecipeIngredientRequest{
+			{IngredientID: 6, Quantity: 3, Unit: "cloves"},
+			{IngredientName: "garlic", Quantity: 1, Unit: "cloves"},
+		},
+	})
+	assert.Equal(suite.T(), domain.ErrRecipeIngredientAlreadyExists, err)
+
+	suggestions, err := suite.ingredientRepo.SuggestIngredientNames("sumac", 5)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), suggestions)
+	recipes, total, err := suite.repo.GetAll(models.PaginationParams{Page: 1, PerPage: 10})
+	require.NoError(suite.T(), err)
+	assert.Zero(suite.T(), total)
+	assert.Empty(suite.T(), recipes)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestUpdateWithIngredients_KeepsIngredientsWhenNil() {
+	created, err := suite.repo.CreateWithIngredients(1, models.CreateRecipeWithIngredientsRequest{
+		Name:        "Omelette",
+		CategoryID:  4,
+		Ingredients: []models.AddRecipeIngredientRequest{{IngredientID: 12, Quantity: 3, Unit: "pieces"}},
+	})
+	require.NoError(suite.T(), err)
+
+	updated, err := suite.repo.UpdateWithIngredients(created.ID, models.UpdateRecipeWithIngredientsRequest{
+		Description: "Fluffy",
+	})
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Omelette", updated.Name)
+	assert.Equal(suite.T(), "Fluffy", *updated.Description)
+	assert.Equal(suite.T(), 4, *updated.CategoryID)
+	assert.Len(suite.T(), updated.Ingredients, 1)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestUpdate_RejectsStaleVersion() {
+	created, err := suite.repo.Create(1, models.CreateRecipeRequest{Name: "Pasta", CategoryID: 1})
+	require.NoError(suite.T(), err)
+
+	version := 1
+	updated, err := suite.repo.Update(created.ID, models.UpdateRecipeRequest{Name: "Carbonara", CategoryID: 1, Version: &version})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, updated.Version)
+
+	_, err = suite.repo.Update(created.ID, models.UpdateRecipeRequest{Name: "Amatriciana", CategoryID: 1, Version: &version})
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+
+	fetched, err := suite.repo.GetByID(created.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Carbonara", fetched.Name)
+	assert.Equal(suite.T(), 2, fetched.Version)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestSearchRecipesByIngredients_MatchesAnyIngredient() {
+	_, err := suite.repo.CreateWithIngredients(1, models.CreateRecipeWithIngredientsRequest{
+		Name: "Salmon Rice", CategoryID: 3,
+		Ingredients: []models.AddRecipeIngredientRequest{{IngredientID: 3, Quantity: 1, Unit: "fillet"}, {IngredientID: 4, Quantity: 100, Unit: "g"}},
+	})
+	require.NoError(suite.T(), err)
+	_, err = suite.repo.CreateWithIngredients(1, models.CreateRecipeWithIngredientsRequest{
+		Name: "Caesar Salad", CategoryID: 4,
+		Ingredients: []models.AddRecipeIngredientRequest{{IngredientID: 7, Quantity: 1, Unit: "head"}},
+	})
+	require.NoError(suite.T(), err)
+
+	recipes, total, err := suite.repo.SearchRecipesByIngredientsWithIngredients([]int{4, 99}, models.PaginationParams{Page: 1, PerPage: 20})
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, total)
+	require.Len(suite.T(), recipes, 1)
+	assert.Equal(suite.T(), "Salmon Rice", recipes[0].Name)
+	assert.Len(suite.T(), recipes[0].Ingredients, 2)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestGetSearchFacets_CountsAcrossAllMatches() {
+	twenty, fifty := 20, 50
+	for _, req := range []models.CreateRecipeWithIngredientsRequest{
+		{Name: "Salmon Rice", CategoryID: 3, TotalTimeMinutes: &twenty},
+		{Name: "Fish Pie", CategoryID: 3, TotalTimeMinutes: &fifty},
+		{Name: "Rice Cakes", CategoryID: 4},
+		{Name: "Secret Risotto", CategoryID: 4, Status: models.RecipeStatusPrivate},
+	} {
+		req.Ingredients = []models.AddRecipeIngredientRequest{{IngredientID: 4, Quantity: 100, Unit: "g"}}
+		_, err := suite.repo.CreateWithIngredients(1, req)
+		require.NoError(suite.T(), err)
+	}
+
+	facets, err := suite.repo.GetSearchFacets([]int{4}, []int{15, 30, 60})
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []models.CategoryFacet{
+		{CategoryID: 3, Name: "Fish", Count: 2},
+		{CategoryID: 4, Name: "Snacks", Count: 1},
+	}, facets.Categories)
+	assert.Equal(suite.T(), []models.TimeFacet{
+		{MaxMinutes: 15, Count: 0}, {MaxMinutes: 30, Count: 1}, {MaxMinutes: 60, Count: 2},
+	}, facets.MaxTime)
+}
+
+// =============================================================================
+// VISIBILITY
+// =============================================================================
+
+func (suite *RecipeRepositoryTestSuite) TestGetByOwner_IncludesDraftsAndFiltersByStatus() {
+	draft, err := suite.repo.Create(1, models.CreateRecipeRequest{Name: "Draft Stew", CategoryID: 1, Status: models.RecipeStatusDraft})
+	require.NoError(suite.T(), err)
+	_, err = suite.repo.Create(1, models.CreateRecipeRequest{Name: "Public Pie", CategoryID: 1})
+	require.NoError(suite.T(), err)
+	_, err = suite.repo.Create(2, models.CreateRecipeRequest{Name: "Someone Else's", CategoryID: 1})
+	require.NoError(suite.T(), err)
+
+	recipes, total, err := suite.repo.GetByOwner(1, "", models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, total)
+	require.Len(suite.T(), recipes, 2)
+
+	recipes, total, err = suite.repo.GetByOwner(1, models.RecipeStatusDraft, models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, total)
+	require.Len(suite.T(), recipes, 1)
+	assert.Equal(suite.T(), draft.ID, recipes[0].ID)
+	assert.Equal(suite.T(), models.RecipeStatusDraft, recipes[0].Status)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestGetAll_HidesUnpublishedRecipes() {
+	_, err := suite.repo.Create(1, models.CreateRecipeRequest{Name: "Secret", CategoryID: 1, Status: models.RecipeStatusPrivate})
+	require.NoError(suite.T(), err)
+	published, err := suite.repo.Create(1, models.CreateRecipeRequest{Name: "Shared", CategoryID: 1})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.RecipeStatusPublished, published.Status)
+
+	recipes, total, err := suite.repo.GetAll(models.PaginationParams{Page: 1, PerPage: 20})
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, total)
+	require.Len(suite.T(), recipes, 1)
+	assert.Equal(suite.T(), "Shared", recipes[0].Name)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestSitemapAndFeed_OnlyPublished() {
+	var ids []int
+	for _, status := range []string{models.RecipeStatusPublished, models.RecipeStatusDraft, models.RecipeStatusPublished, models.RecipeStatusPublished} {
+		recipe, err := suite.repo.Create(1, models.CreateRecipeRequest{Name: "Recipe", CategoryID: 1, Status: status})
+		require.NoError(suite.T(), err)
+		ids = append(ids, recipe.ID)
+	}
+	// The first recipe was edited most recently
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range ids {
+		recipe := suite.store.recipes[id]
+		recipe.UpdatedAt = base.AddDate(0, 0, i)
+		if i == 0 {
+			recipe.UpdatedAt = base.AddDate(0, 1, 0)
+		}
+		suite.store.recipes[id] = recipe
+	}
+
+	published, err := suite.repo.GetPublishedRecipes()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []models.PublishedRecipe{
+		{ID: ids[0], UpdatedAt: base.AddDate(0, 1, 0)},
+		{ID: ids[2], UpdatedAt: base.AddDate(0, 0, 2)},
+		{ID: ids[3], UpdatedAt: base.AddDate(0, 0, 3)},
+	}, published)
+
+	feed, err := suite.repo.GetRecentlyUpdated(2)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), feed, 2)
+	assert.Equal(suite.T(), ids[0], feed[0].ID)
+	assert.Equal(suite.T(), ids[3], feed[1].ID)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestGetCreatedSince_PublishedOldestFirst() {
+	var ids []int
+	for _, status := range []string{models.RecipeStatusPublished, models.RecipeStatusPublished, models.RecipeStatusDraft, models.RecipeStatusPublished} {
+		recipe, err := suite.repo.Create(1, models.CreateRecipeRequest{Name: "Recipe", CategoryID: 1, Status: status})
+		require.NoError(suite.T(), err)
+		ids = append(ids, recipe.ID)
+	}
+	// The last recipe was created first
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range ids {
+		recipe := suite.store.recipes[id]
+		recipe.CreatedAt = base.AddDate(0, 0, i)
+		if i == 3 {
+			recipe.CreatedAt = base.AddDate(0, 0, 1).Add(time.Hour)
+		}
+		suite.store.recipes[id] = recipe
+	}
+
+	recipes, err := suite.repo.GetCreatedSince(base)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), recipes, 2)
+	assert.Equal(suite.T(), ids[1], recipes[0].ID)
+	assert.Equal(suite.T(), ids[3], recipes[1].ID)
+	require.NotNil(suite.T(), recipes[0].Category)
+}
+
+func TestRecipeRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RecipeRepositoryTestSuite))
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type recipeTemplateRepository struct {
+	store *Store
+}
+
+func NewRecipeTemplateRepository(store *Store) repository.RecipeTemplateRepository {
+	return &recipeTemplateRepository{store: store}
+}
+
+func (r *recipeTemplateRepository) GetAll() ([]models.RecipeTemplate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	templates := make([]models.RecipeTemplate, 0, len(r.store.templates))
+	for _, template := range r.store.templates {
+		templates = append(templates, r.store.withRecipe(template))
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Name == templates[j].Name {
+			return templates[i].ID < templates[j].ID
+		}
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+func (r *recipeTemplateRepository) GetByID(id int) (*models.RecipeTemplate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	template, ok := r.store.templates[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	template = r.store.withRecipe(template)
+	return &template, nil
+}
+
+func (r *recipeTemplateRepository) Create(userID int, req models.CreateRecipeTemplateRequest) (*models.RecipeTemplate, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, template := range r.store.templates {
+		if template.RecipeID == req.RecipeID {
+			return nil, domain.ErrTemplateExists
+		}
+	}
+
+	r.store.nextTemplateID++
+	template := models.RecipeTemplate{
+		ID:          r.store.nextTemplateID,
+		RecipeID:    req.RecipeID,
+		Description: req.Description,
+		CreatedBy:   userID,
+		CreatedAt:   now(),
+	}
+	r.store.templates[template.ID] = template
+
+	template = r.store.withRecipe(template)
+	return &template, nil
+}
+
+func (r *recipeTemplateRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.templates[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.templates, id)
+	return nil
+}
+
+// withRecipe fills in the name and category a template takes from its
+// recipe, mirroring the JOIN done by the SQL repository. Callers must hold mu.
+func (s *Store) withRecipe(template models.RecipeTemplate) models.RecipeTemplate {
+	recipe := s.recipes[template.RecipeID]
+	template.Name = recipe.Name
+	template.CategoryID = recipe.CategoryID
+	return template
+}
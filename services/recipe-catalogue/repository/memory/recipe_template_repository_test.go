@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeTemplateRepository_CreateListAndDelete(t *testing.T) {
+	store := NewStore()
+	categoryID := 2
+	store.recipes[1] = models.Recipe{ID: 1, UserID: 9, Name: "Basic stir fry", CategoryID: &categoryID, Status: models.RecipeStatusPrivate}
+	store.recipes[2] = models.Recipe{ID: 2, UserID: 9, Name: "Basic risotto", Status: models.RecipeStatusPrivate}
+	repo := NewRecipeTemplateRepository(store)
+	description := "Swap in any vegetables"
+
+	stirFry, err := repo.Create(9, models.CreateRecipeTemplateRequest{RecipeID: 1, Description: &description})
+	require.NoError(t, err)
+	assert.Equal(t, "Basic stir fry", stirFry.Name)
+	assert.Equal(t, &categoryID, stirFry.CategoryID)
+	assert.Equal(t, 9, stirFry.CreatedBy)
+	_, err = repo.Create(9, models.CreateRecipeTemplateRequest{RecipeID: 2})
+	require.NoError(t, err)
+
+	_, err = repo.Create(9, models.CreateRecipeTemplateRequest{RecipeID: 1})
+	assert.Equal(t, domain.ErrTemplateExists, err)
+
+	templates, err := repo.GetAll()
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	assert.Equal(t, "Basic risotto", templates[0].Name)
+	assert.Equal(t, "Basic stir fry", templates[1].Name)
+
+	require.NoError(t, repo.Delete(stirFry.ID))
+	_, err = repo.GetByID(stirFry.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Equal(t, sql.ErrNoRows, repo.Delete(stirFry.ID))
+}
+
+func TestRecipeTemplateRepository_GoesWithItsRecipe(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, UserID: 9, Name: "Basic stir fry", Status: models.RecipeStatusPrivate}
+	repo := NewRecipeTemplateRepository(store)
+
+	template, err := repo.Create(9, models.CreateRecipeTemplateRequest{RecipeID: 1})
+	require.NoError(t, err)
+	require.NoError(t, NewRecipeRepository(store).Delete(1))
+
+	_, err = repo.GetByID(template.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type savedSearchRepository struct {
+	store *Store
+}
+
+func NewSavedSearchRepository(store *Store) repository.SavedSearchRepository {
+	return &savedSearchRepository{store: store}
+}
+
+func (r *savedSearchRepository) GetByUserID(userID int) ([]models.SavedSearch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	searches := make([]models.SavedSearch, 0)
+	for _, search := range r.store.savedSearches {
+		if search.UserID == userID {
+			searches = append(searches, copySavedSearch(search))
+		}
+	}
+
+	sort.Slice(searches, func(i, j int) bool {
+		if !searches[i].CreatedAt.Equal(searches[j].CreatedAt) {
+			return searches[i].CreatedAt.After(searches[j].CreatedAt)
+		}
+		return searches[i].ID > searches[j].ID
+	})
+	return searches, nil
+}
+
+func (r *savedSearchRepository) GetByID(id int) (*models.SavedSearch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	search, ok := r.store.savedSearches[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	search = copySavedSearch(search)
+	return &search, nil
+}
+
+func (r *savedSearchRepository) Create(userID int, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextSavedSearchID++
+	search := models.SavedSearch{
+		ID:             r.store.nextSavedSearchID,
+		UserID:         userID,
+		Name:           req.Name,
+		IngredientIDs:  sortedIDs(req.IngredientIDs),
+		CategoryID:     req.CategoryID,
+		MaxTimeMinutes: req.MaxTimeMinutes,
+		Notify:         req.Notify,
+		LastCheckedAt:  now(),
+		CreatedAt:      now(),
+		UpdatedAt:      now(),
+	}
+	r.store.savedSearches[search.ID] = search
+
+	search = copySavedSearch(search)
+	return &search, nil
+}
+
+func (r *savedSearchRepository) Update(id int, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	search, ok := r.store.savedSearches[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	search.Name = req.Name
+	search.IngredientIDs = sortedIDs(req.IngredientIDs)
+	search.CategoryID = req.CategoryID
+	search.MaxTimeMinutes = req.MaxTimeMinutes
+	search.Notify = req.Notify
+	search.LastCheckedAt = now()
+	search.UpdatedAt = now()
+	r.store.savedSearches[id] = search
+
+	search = copySavedSearch(search)
+	return &search, nil
+}
+
+func (r *savedSearchRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.savedSearches[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.savedSearches, id)
+	return nil
+}
+
+func (r *savedSearchRepository) GetAlerting() ([]models.SavedSearch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	searches := make([]models.SavedSearch, 0)
+	for _, search := range r.store.savedSearches {
+		if search.Notify {
+			searches = append(searches, copySavedSearch(search))
+		}
+	}
+
+	sort.Slice(searches, func(i, j int) bool {
+		if !searches[i].LastCheckedAt.Equal(searches[j].LastCheckedAt) {
+			return searches[i].LastCheckedAt.Before(searches[j].LastCheckedAt)
+		}
+		return searches[i].ID < searches[j].ID
+	})
+	return searches, nil
+}
+
+func (r *savedSearchRepository) MarkChecked(id int, checkedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if search, ok := r.store.savedSearches[id]; ok {
+		search.LastCheckedAt = checkedAt
+		r.store.savedSearches[id] = search
+	}
+	return nil
+}
+
+// copySavedSearch keeps callers from mutating the stored ingredient IDs.
+func copySavedSearch(search models.SavedSearch) models.SavedSearch {
+	search.IngredientIDs = append([]int{}, search.IngredientIDs...)
+	return search
+}
+
+// sortedIDs copies ids in ascending order, as the SQL repository returns them.
+func sortedIDs(ids []int) []int {
+	sorted := append([]int{}, ids...)
+	sort.Ints(sorted)
+	return sorted
+}
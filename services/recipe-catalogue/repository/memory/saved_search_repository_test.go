@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedSearchRepository_Lifecycle(t *testing.T) {
+	repo := NewSavedSearchRepository(NewStore())
+
+	created, err := repo.Create(7, models.SavedSearchRequest{Name: "Chicken", IngredientIDs: []int{5, 2}})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 5}, created.IngredientIDs)
+
+	_, err = repo.Create(8, models.SavedSearchRequest{Name: "Someone else's", IngredientIDs: []int{1}, Notify: true})
+	require.NoError(t, err)
+
+	maxTime := 30
+	updated, err := repo.Update(created.ID, models.SavedSearchRequest{
+		Name: "Quick chicken", IngredientIDs: []int{2}, MaxTimeMinutes: &maxTime, Notify: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &maxTime, updated.MaxTimeMinutes)
+
+	searches, err := repo.GetByUserID(7)
+	require.NoError(t, err)
+	require.Len(t, searches, 1)
+	assert.Equal(t, "Quick chicken", searches[0].Name)
+	assert.Equal(t, []int{2}, searches[0].IngredientIDs)
+
+	require.NoError(t, repo.Delete(created.ID))
+	_, err = repo.GetByID(created.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Equal(t, sql.ErrNoRows, repo.Delete(created.ID))
+}
+
+func TestSavedSearchRepository_AlertingAndMarkChecked(t *testing.T) {
+	repo := NewSavedSearchRepository(NewStore())
+
+	quiet, err := repo.Create(7, models.SavedSearchRequest{Name: "Quiet", IngredientIDs: []int{1}})
+	require.NoError(t, err)
+	first, err := repo.Create(7, models.SavedSearchRequest{Name: "First", IngredientIDs: []int{1}, Notify: true})
+	require.NoError(t, err)
+	second, err := repo.Create(8, models.SavedSearchRequest{Name: "Second", IngredientIDs: []int{2}, Notify: true})
+	require.NoError(t, err)
+
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, repo.MarkChecked(first.ID, later))
+
+	searches, err := repo.GetAlerting()
+	require.NoError(t, err)
+	require.Len(t, searches, 2)
+	assert.Equal(t, second.ID, searches[0].ID)
+	assert.Equal(t, first.ID, searches[1].ID)
+	assert.True(t, searches[1].LastCheckedAt.Equal(later))
+	assert.NotEqual(t, quiet.ID, searches[0].ID)
+}
+
+func TestSavedSearchRepository_DeletingIngredientRemovesItFromSearches(t *testing.T) {
+	store := NewStore()
+	store.SeedDefaults()
+	repo := NewSavedSearchRepository(store)
+
+	search, err := repo.Create(7, models.SavedSearchRequest{Name: "Soup", IngredientIDs: []int{5, 6}})
+	require.NoError(t, err)
+
+	require.NoError(t, NewIngredientRepository(store).DeleteIngredient(6))
+
+	stored, err := repo.GetByID(search.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{5}, stored.IngredientIDs)
+}
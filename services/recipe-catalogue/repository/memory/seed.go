@@ -0,0 +1,164 @@
+package memory
+
+import "meal-prep/shared/models"
+
+// SeedDefaults loads the same reference data the Flyway migrations insert,
+// so a fresh in-memory catalogue is usable without creating categories by hand.
+func (s *Store) SeedDefaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categories := []struct{ name, description string }{
+		{"Meat", "Meat-based dishes"},
+		{"Chicken", "Chicken dishes"},
+		{"Fish", "Fish and seafood"},
+		{"Snacks", "Light meals and snacks"},
+		{"Desserts", "Sweet treats"},
+	}
+	for _, c := range categories {
+		description := c.description
+		s.nextCategoryID++
+		s.categories[s.nextCategoryID] = models.Category{
+			ID:          s.nextCategoryID,
+			Name:        c.name,
+			Description: &description,
+			CreatedAt:   now(),
+			UpdatedAt:   now(),
+		}
+	}
+
+	ingredients := []struct{ name, description, category string }{
+		{"Chicken Breast", "Boneless skinless chicken breast", "Meat"},
+		{"Ground Beef", "Lean ground beef", "Meat"},
+		{"Salmon Fillet", "Fresh salmon fillet", "Fish"},
+		{"Rice", "Long grain rice", "Grains"},
+		{"Onion", "Yellow onion", "Vegetables"},
+		{"Garlic", "Fresh garlic cloves", "Vegetables"},
+		{"Lettuce", "Romaine lettuce", "Vegetables"},
+		{"Parmesan Cheese", "Grated parmesan", "Dairy"},
+		{"Olive Oil", "Extra virgin olive oil", "Oils"},
+		{"Salt", "Table salt", "Spices"},
+		{"Black Pepper", "Ground black pepper", "Spices"},
+		{"Eggs", "Large eggs", "Dairy"},
+	}
+	for _, i := range ingredients {
+		description, category := i.description, i.category
+		s.nextIngredientID++
+		s.ingredients[s.nextIngredientID] = models.Ingredient{
+			ID:          s.nextIngredientID,
+			Name:        i.name,
+			Description: &description,
+			Category:    &category,
+			CreatedAt:   now(),
+		}
+	}
+
+	source := "USDA FoodData Central"
+	densities := map[string]float64{
+		"Rice": 0.85, "Parmesan Cheese": 0.42, "Olive Oil": 0.91, "Salt": 1.22, "Black Pepper": 0.46,
+	}
+	for _, ingredient := range s.ingredients {
+		if gramsPerML, ok := densities[ingredient.Name]; ok {
+			s.densities[ingredient.ID] = models.IngredientDensity{
+				IngredientID: ingredient.ID,
+				GramsPerML:   gramsPerML,
+				Source:       &source,
+				UpdatedAt:    now(),
+			}
+		}
+	}
+
+	packSizes := []struct {
+		name     string
+		quantity float64
+		unit     string
+		label    string
+	}{
+		{"Rice", 1000, "g", "bag"},
+		{"Olive Oil", 500, "ml", "bottle"},
+		{"Parmesan Cheese", 200, "g", "block"},
+		{"Eggs", 6, "pieces", "box"},
+		{"Eggs", 12, "pieces", "box"},
+	}
+	for _, p := range packSizes {
+		for _, ingredient := range s.ingredients {
+			if ingredient.Name != p.name {
+				continue
+			}
+			label := p.label
+			s.nextPackSizeID++
+			s.packSizes[ingredient.ID] = append(s.packSizes[ingredient.ID], models.PackSize{
+				ID:           s.nextPackSizeID,
+				IngredientID: ingredient.ID,
+				Quantity:     p.quantity,
+				Unit:         p.unit,
+				Label:        &label,
+				CreatedAt:    now(),
+			})
+		}
+	}
+
+	translations := map[string]map[string]string{
+		"Chicken Breast":  {"ru": "Куриная грудка", "de": "Hähnchenbrust", "es": "Pechuga de pollo"},
+		"Ground Beef":     {"ru": "Говяжий фарш", "de": "Rinderhackfleisch", "es": "Carne molida de res"},
+		"Salmon Fillet":   {"ru": "Филе лосося", "de": "Lachsfilet", "es": "Filete de salmón"},
+		"Rice":            {"ru": "Рис", "de": "Reis", "es": "Arroz"},
+		"Onion":           {"ru": "Лук", "de": "Zwiebel", "es": "Cebolla"},
+		"Garlic":          {"ru": "Чеснок", "de": "Knoblauch", "es": "Ajo"},
+		"Lettuce":         {"ru": "Салат ромэн", "de": "Römersalat", "es": "Lechuga romana"},
+		"Parmesan Cheese": {"ru": "Сыр пармезан", "de": "Parmesan", "es": "Queso parmesano"},
+		"Olive Oil":       {"ru": "Оливковое масло", "de": "Olivenöl", "es": "Aceite de oliva"},
+		"Salt":            {"ru": "Соль", "de": "Salz", "es": "Sal"},
+		"Black Pepper":    {"ru": "Чёрный перец", "de": "Schwarzer Pfeffer", "es": "Pimienta negra"},
+		"Eggs":            {"ru": "Яйца", "de": "Eier", "es": "Huevos"},
+	}
+	for _, ingredient := range s.ingredients {
+		if names, ok := translations[ingredient.Name]; ok {
+			s.translations[ingredient.ID] = names
+		}
+	}
+
+	// Parmigiano is made with calf rennet, so it is not vegetarian
+	dietaryFacts := map[string]models.DietaryFacts{
+		"Eggs":            {Flags: []string{models.DietaryFlagEgg}},
+		"Parmesan Cheese": {Flags: []string{models.DietaryFlagDairy}, Override: models.DietOmnivore},
+	}
+	for _, ingredient := range s.ingredients {
+		if facts, ok := dietaryFacts[ingredient.Name]; ok {
+			s.dietaryFacts[ingredient.ID] = facts
+		}
+	}
+
+	allergens := []struct{ code, name string }{
+		{"gluten", "Gluten"},
+		{"dairy", "Milk"},
+		{"egg", "Eggs"},
+		{"fish", "Fish"},
+		{"shellfish", "Crustacean shellfish"},
+		{"mollusc", "Molluscs"},
+		{"peanut", "Peanuts"},
+		{"tree_nut", "Tree nuts"},
+		{"soy", "Soy"},
+		{"sesame", "Sesame"},
+		{"mustard", "Mustard"},
+		{"celery", "Celery"},
+		{"lupin", "Lupin"},
+		{"sulphites", "Sulphites"},
+	}
+	allergenIDs := make(map[string]int, len(allergens))
+	for _, a := range allergens {
+		s.nextAllergenID++
+		s.allergens[s.nextAllergenID] = models.Allergen{ID: s.nextAllergenID, Code: a.code, Name: a.name}
+		allergenIDs[a.code] = s.nextAllergenID
+	}
+
+	ingredientAllergens := map[string]string{
+		"Eggs":            "egg",
+		"Parmesan Cheese": "dairy",
+	}
+	for _, ingredient := range s.ingredients {
+		if code, ok := ingredientAllergens[ingredient.Name]; ok {
+			s.ingredientAllergens[ingredient.ID] = map[int]bool{allergenIDs[code]: true}
+		}
+	}
+}
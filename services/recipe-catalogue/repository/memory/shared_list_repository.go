@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"database/sql"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type sharedListRepository struct {
+	store *Store
+}
+
+func NewSharedListRepository(store *Store) repository.SharedListRepository {
+	return &sharedListRepository{store: store}
+}
+
+func (r *sharedListRepository) Create(list models.SharedShoppingList) (*models.SharedShoppingList, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	list.Version = 0
+	list.CreatedAt = now()
+	list.UpdatedAt = list.CreatedAt
+	list.Items = append([]models.SharedShoppingListItem{}, list.Items...)
+	for i := range list.Items {
+		list.Items[i].Checked = false
+		list.Items[i].CheckedAt = nil
+	}
+	r.store.sharedLists[list.Token] = list
+
+	return copySharedList(list), nil
+}
+
+func (r *sharedListRepository) GetByToken(token string) (*models.SharedShoppingList, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	list, ok := r.store.sharedLists[token]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return copySharedList(list), nil
+}
+
+func (r *sharedListRepository) SetItemChecked(token string, position int, checked bool) (*models.SharedShoppingListChange, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	list, ok := r.store.sharedLists[token]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	for i := range list.Items {
+		item := &list.Items[i]
+		if item.Position != position {
+			continue
+		}
+
+		item.Checked = checked
+		item.CheckedAt = nil
+		if checked {
+			checkedAt := now()
+			item.CheckedAt = &checkedAt
+		}
+		list.Version++
+		list.UpdatedAt = now()
+		r.store.sharedLists[token] = list
+
+		return &models.SharedShoppingListChange{Version: list.Version, Item: *item}, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *sharedListRepository) Delete(token string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.sharedLists[token]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.sharedLists, token)
+	return nil
+}
+
+// copySharedList keeps callers from changing the stored items.
+func copySharedList(list models.SharedShoppingList) *models.SharedShoppingList {
+	list.Items = append(make([]models.SharedShoppingListItem, 0, len(list.Items)), list.Items...)
+	return &list
+}
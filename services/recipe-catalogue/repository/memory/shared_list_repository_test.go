@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedListRepository_CheckItems(t *testing.T) {
+	repo := NewSharedListRepository(NewStore())
+
+	_, err := repo.Create(models.SharedShoppingList{Token: "token-1", UserID: 7, Items: []models.SharedShoppingListItem{
+		{Position: 1, IngredientID: 3, Name: "Onion", Quantity: 2, Unit: "piece"},
+		{Position: 2, IngredientID: 5, Name: "Tomatoes", Quantity: 800, Unit: "g"},
+	}})
+	require.NoError(t, err)
+
+	change, err := repo.SetItemChecked("token-1", 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, change.Version)
+	assert.NotNil(t, change.Item.CheckedAt)
+
+	change, err = repo.SetItemChecked("token-1", 2, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, change.Version)
+	assert.Nil(t, change.Item.CheckedAt)
+
+	_, err = repo.SetItemChecked("token-1", 3, true)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	list, err := repo.GetByToken("token-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Version)
+	assert.False(t, list.Items[1].Checked)
+
+	require.NoError(t, repo.Delete("token-1"))
+	_, err = repo.GetByToken("token-1")
+	assert.Equal(t, sql.ErrNoRows, err)
+}
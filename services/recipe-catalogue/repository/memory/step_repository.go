@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type stepRepository struct {
+	store *Store
+}
+
+func NewStepRepository(store *Store) repository.StepRepository {
+	return &stepRepository{store: store}
+}
+
+func (r *stepRepository) GetByRecipeID(recipeID int) ([]models.RecipeStep, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return copySteps(r.store.recipeSteps[recipeID]), nil
+}
+
+func (r *stepRepository) ReplaceForRecipe(recipeID int, steps []models.CreateStepRequest) ([]models.RecipeStep, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored := make([]models.RecipeStep, 0, len(steps))
+	for i, req := range steps {
+		r.store.nextRecipeStepID++
+		stored = append(stored, models.RecipeStep{
+			ID:              r.store.nextRecipeStepID,
+			RecipeID:        recipeID,
+			Position:        i + 1,
+			Instruction:     req.Instruction,
+			DurationMinutes: req.DurationMinutes,
+			IngredientIDs:   append([]int{}, req.IngredientIDs...),
+			Timers:          append([]models.StepTimer{}, req.Timers...),
+			CreatedAt:       now(),
+		})
+	}
+
+	if len(stored) == 0 {
+		delete(r.store.recipeSteps, recipeID)
+	} else {
+		r.store.recipeSteps[recipeID] = stored
+	}
+	return copySteps(stored), nil
+}
+
+func (r *stepRepository) Add(recipeID int, req models.CreateStepRequest) (*models.RecipeStep, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextRecipeStepID++
+	step := models.RecipeStep{
+		ID:              r.store.nextRecipeStepID,
+		RecipeID:        recipeID,
+		Position:        len(r.store.recipeSteps[recipeID]) + 1,
+		Instruction:     req.Instruction,
+		DurationMinutes: req.DurationMinutes,
+		IngredientIDs:   append([]int{}, req.IngredientIDs...),
+		Timers:          append([]models.StepTimer{}, req.Timers...),
+		CreatedAt:       now(),
+	}
+	r.store.recipeSteps[recipeID] = append(r.store.recipeSteps[recipeID], step)
+	return &copySteps([]models.RecipeStep{step})[0], nil
+}
+
+func (r *stepRepository) Update(recipeID, stepID int, req models.CreateStepRequest) (*models.RecipeStep, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	steps := r.store.recipeSteps[recipeID]
+	i := stepIndex(steps, stepID)
+	if i < 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	steps[i].Instruction = req.Instruction
+	steps[i].DurationMinutes = req.DurationMinutes
+	steps[i].IngredientIDs = append([]int{}, req.IngredientIDs...)
+	steps[i].Timers = append([]models.StepTimer{}, req.Timers...)
+	return &copySteps(steps[i : i+1])[0], nil
+}
+
+func (r *stepRepository) Delete(recipeID, stepID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	steps := r.store.recipeSteps[recipeID]
+	i := stepIndex(steps, stepID)
+	if i < 0 {
+		return sql.ErrNoRows
+	}
+
+	steps = append(steps[:i], steps[i+1:]...)
+	if len(steps) == 0 {
+		delete(r.store.recipeSteps, recipeID)
+		return nil
+	}
+	for j := i; j < len(steps); j++ {
+		steps[j].Position = j + 1
+	}
+	r.store.recipeSteps[recipeID] = steps
+	return nil
+}
+
+func (r *stepRepository) Reorder(recipeID int, stepIDs []int) ([]models.RecipeStep, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	steps := r.store.recipeSteps[recipeID]
+	reordered := make([]models.RecipeStep, 0, len(steps))
+	for _, stepID := range stepIDs {
+		if i := stepIndex(steps, stepID); i >= 0 {
+			step := steps[i]
+			step.Position = len(reordered) + 1
+			reordered = append(reordered, step)
+		}
+	}
+	if len(reordered) > 0 {
+		r.store.recipeSteps[recipeID] = reordered
+	}
+	return copySteps(reordered), nil
+}
+
+func (r *stepRepository) GetTechniques(recipeID int) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return append([]string{}, r.store.techniques[recipeID]...), nil
+}
+
+func (r *stepRepository) ReplaceTechniques(recipeID int, techniques []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if len(techniques) == 0 {
+		delete(r.store.techniques, recipeID)
+		return nil
+	}
+	stored := append([]string{}, techniques...)
+	sort.Strings(stored)
+	r.store.techniques[recipeID] = stored
+	return nil
+}
+
+func stepIndex(steps []models.RecipeStep, stepID int) int {
+	for i, step := range steps {
+		if step.ID == stepID {
+			return i
+		}
+	}
+	return -1
+}
+
+// copySteps keeps callers from mutating the stored steps.
+func copySteps(steps []models.RecipeStep) []models.RecipeStep {
+	result := make([]models.RecipeStep, len(steps))
+	for i, step := range steps {
+		step.IngredientIDs = append([]int{}, step.IngredientIDs...)
+		step.Timers = append([]models.StepTimer{}, step.Timers...)
+		result[i] = step
+	}
+	return result
+}
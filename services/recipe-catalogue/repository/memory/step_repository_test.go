@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepRepository_ReplaceAndDeleteWithRecipe(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, Name: "Pasta"}
+	steps := NewStepRepository(store)
+	sessions := NewCookingSessionRepository(store)
+
+	stored, err := steps.ReplaceForRecipe(1, []models.CreateStepRequest{
+		{Instruction: "Boil the pasta", IngredientIDs: []int{4}, Timers: []models.StepTimer{{Label: "Pasta", DurationSeconds: 600}}},
+		{Instruction: "Serve"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored[1].Position)
+
+	stored[0].IngredientIDs[0] = 99
+	stored[0].Timers[0].DurationSeconds = 1
+	fetched, err := steps.GetByRecipeID(1)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4}, fetched[0].IngredientIDs)
+	assert.Equal(t, []models.StepTimer{{Label: "Pasta", DurationSeconds: 600}}, fetched[0].Timers)
+
+	_, err = sessions.Create(models.CookingSession{Token: "token-1", UserID: 3, RecipeID: 1, Scale: 1})
+	require.NoError(t, err)
+
+	require.NoError(t, NewRecipeRepository(store).Delete(1))
+	fetched, err = steps.GetByRecipeID(1)
+	require.NoError(t, err)
+	assert.Empty(t, fetched)
+	_, err = sessions.GetByToken("token-1")
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestStepRepository_AddUpdateDeleteAndReorder(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, Name: "Pasta"}
+	steps := NewStepRepository(store)
+
+	first, err := steps.Add(1, models.CreateStepRequest{Instruction: "Boil the pasta"})
+	require.NoError(t, err)
+	second, _ := steps.Add(1, models.CreateStepRequest{Instruction: "Fry the garlic"})
+	third, _ := steps.Add(1, models.CreateStepRequest{Instruction: "Serve"})
+	assert.Equal(t, 3, third.Position)
+
+	updated, err := steps.Update(1, second.ID, models.CreateStepRequest{Instruction: "Fry the garlic gently", IngredientIDs: []int{7}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.Position)
+	assert.Equal(t, []int{7}, updated.IngredientIDs)
+
+	_, err = steps.Update(2, second.ID, models.CreateStepRequest{Instruction: "Elsewhere"})
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	reordered, err := steps.Reorder(1, []int{third.ID, first.ID, second.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []int{third.ID, first.ID, second.ID}, []int{reordered[0].ID, reordered[1].ID, reordered[2].ID})
+	assert.Equal(t, 3, reordered[2].Position)
+
+	require.NoError(t, steps.Delete(1, third.ID))
+	fetched, _ := steps.GetByRecipeID(1)
+	require.Len(t, fetched, 2)
+	assert.Equal(t, first.ID, fetched[0].ID)
+	assert.Equal(t, 1, fetched[0].Position)
+	assert.Equal(t, 2, fetched[1].Position)
+	assert.Equal(t, sql.ErrNoRows, steps.Delete(1, third.ID))
+}
+
+func TestStepRepository_TechniquesGoWithRecipe(t *testing.T) {
+	store := NewStore()
+	store.recipes[1] = models.Recipe{ID: 1, Name: "Stew"}
+	steps := NewStepRepository(store)
+
+	require.NoError(t, steps.ReplaceTechniques(1, []string{"knife_work", "braising"}))
+	techniques, err := steps.GetTechniques(1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"braising", "knife_work"}, techniques)
+
+	require.NoError(t, NewRecipeRepository(store).Delete(1))
+	techniques, err = steps.GetTechniques(1)
+	require.NoError(t, err)
+	assert.Empty(t, techniques)
+}
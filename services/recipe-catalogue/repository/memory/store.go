@@ -0,0 +1,327 @@
+// Package memory provides in-memory implementations of the recipe catalogue
+// repositories. They are meant for local development (STORAGE=memory) and
+// keep everything in process memory, so data is lost on restart.
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"meal-prep/shared/models"
+)
+
+// Store holds the tables shared by the in-memory repositories. Recipes,
+// ingredients and categories reference each other, so all repositories built
+// from the same Store see a consistent view, as they would with PostgreSQL.
+type Store struct {
+	mu sync.RWMutex
+
+	categories          map[int]models.Category
+	recipes             map[int]models.Recipe
+	ingredients         map[int]models.Ingredient
+	recipeIngredients   map[int]models.RecipeIngredient
+	densities           map[int]models.IngredientDensity
+	packSizes           map[int][]models.PackSize
+	products            map[int]models.IngredientProduct
+	storeLayouts        map[int]models.StoreLayout
+	sharedLists         map[string]models.SharedShoppingList
+	recipeSteps         map[int][]models.RecipeStep // by recipe ID, in position order
+	techniques          map[int][]string            // by recipe ID, sorted
+	cookingSessions     map[string]models.CookingSession
+	recipeImages        map[int]models.RecipeImage
+	follows             map[followKey]time.Time
+	favorites           map[favoriteKey]time.Time
+	activities          map[int]models.Activity
+	profiles            map[int]models.ProfileSettings
+	templates           map[int]models.RecipeTemplate
+	lineage             map[int]lineageLink                  // by forked recipe ID
+	prices              map[int][]models.IngredientPrice     // by ingredient ID, oldest first
+	costSnapshots       map[int][]models.RecipeCostSnapshot  // by recipe ID, oldest first
+	substitutions       map[int][]models.SubstitutionRequest // by the ingredient they replace
+	translations        map[int]map[string]string            // ingredient ID -> locale -> name
+	dietaryFacts        map[int]models.DietaryFacts
+	allergens           map[int]models.Allergen
+	ingredientAllergens map[int]map[int]bool // ingredient ID -> allergen IDs
+	prepSessions        map[int]models.PrepSession
+	mealPlans           map[int]models.MealPlan
+	mealPlanPrefs       map[int]models.MealPlanPreferences // by user ID
+	savedSearches       map[int]models.SavedSearch
+	households          map[int]models.Household // members in the order they joined
+	pantries            map[int]*pantry          // by pantry ID
+	ingredientsUsedAt   map[int]time.Time        // when each ingredient last left a recipe
+	archived            map[int]time.Time        // when each archived ingredient was archived
+
+	nextCategoryID         int
+	nextRecipeID           int
+	nextIngredientID       int
+	nextRecipeIngredientID int
+	nextPackSizeID         int
+	nextProductID          int
+	nextStoreLayoutID      int
+	nextRecipeStepID       int
+	nextRecipeImageID      int
+	nextActivityID         int
+	nextPriceID            int
+	nextPrepSessionID      int
+	nextMealPlanID         int
+	nextMealPlanEntryID    int
+	nextMealPlanMemberID   int
+	nextSavedSearchID      int
+	nextTemplateID         int
+	nextAllergenID         int
+	nextHouseholdID        int
+	nextPantryID           int
+}
+
+// lineageLink records which recipe a fork was adapted from. A zero parentID
+// means the original has been deleted.
+type lineageLink struct {
+	parentID  int
+	createdAt time.Time
+}
+
+// pantry belongs to a user or, when householdID is set, a household.
+type pantry struct {
+	userID      int
+	householdID int
+	version     int
+	items       map[int]pantryEntry // by ingredient ID
+}
+
+type pantryEntry struct {
+	addedBy int
+	addedAt time.Time
+}
+
+type followKey struct {
+	followerID int
+	followeeID int
+}
+
+type favoriteKey struct {
+	userID   int
+	recipeID int
+}
+
+func NewStore() *Store {
+	return &Store{
+		categories:          make(map[int]models.Category),
+		recipes:             make(map[int]models.Recipe),
+		ingredients:         make(map[int]models.Ingredient),
+		recipeIngredients:   make(map[int]models.RecipeIngredient),
+		densities:           make(map[int]models.IngredientDensity),
+		packSizes:           make(map[int][]models.PackSize),
+		products:            make(map[int]models.IngredientProduct),
+		storeLayouts:        make(map[int]models.StoreLayout),
+		recipeSteps:         make(map[int][]models.RecipeStep),
+		techniques:          make(map[int][]string),
+		cookingSessions:     make(map[string]models.CookingSession),
+		recipeImages:        make(map[int]models.RecipeImage),
+		follows:             make(map[followKey]time.Time),
+		favorites:           make(map[favoriteKey]time.Time),
+		sharedLists:         make(map[string]models.SharedShoppingList),
+		activities:          make(map[int]models.Activity),
+		profiles:            make(map[int]models.ProfileSettings),
+		lineage:             make(map[int]lineageLink),
+		templates:           make(map[int]models.RecipeTemplate),
+		prices:              make(map[int][]models.IngredientPrice),
+		costSnapshots:       make(map[int][]models.RecipeCostSnapshot),
+		substitutions:       make(map[int][]models.SubstitutionRequest),
+		translations:        make(map[int]map[string]string),
+		dietaryFacts:        make(map[int]models.DietaryFacts),
+		allergens:           make(map[int]models.Allergen),
+		ingredientAllergens: make(map[int]map[int]bool),
+		prepSessions:        make(map[int]models.PrepSession),
+		mealPlans:           make(map[int]models.MealPlan),
+		mealPlanPrefs:       make(map[int]models.MealPlanPreferences),
+		savedSearches:       make(map[int]models.SavedSearch),
+		households:          make(map[int]models.Household),
+		pantries:            make(map[int]*pantry),
+		ingredientsUsedAt:   make(map[int]time.Time),
+		archived:            make(map[int]time.Time),
+	}
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// withCategory returns a copy of the recipe with its category attached,
+// mirroring the LEFT JOIN done by the SQL repository. Callers must hold mu.
+func (s *Store) withCategory(recipe models.Recipe) models.Recipe {
+	if recipe.CategoryID != nil {
+		if category, ok := s.categories[*recipe.CategoryID]; ok {
+			recipe.Category = &category
+		}
+	}
+	return recipe
+}
+
+// recipeIngredientsFor returns the ingredients of a recipe in insertion
+// order with ingredient details attached. Callers must hold mu.
+func (s *Store) recipeIngredientsFor(recipeID int) []models.RecipeIngredient {
+	var result []models.RecipeIngredient
+	for _, ri := range s.recipeIngredients {
+		if ri.RecipeID != recipeID {
+			continue
+		}
+		ri.Ingredient = s.ingredients[ri.IngredientID]
+		ri.Product = s.productOf(ri)
+		result = append(result, ri)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// recipesWhere returns the matching recipes ordered by name. Callers must hold mu.
+func (s *Store) recipesWhere(match func(models.Recipe) bool) []models.Recipe {
+	recipes := make([]models.Recipe, 0)
+	for _, recipe := range s.recipes {
+		if match(recipe) {
+			recipes = append(recipes, s.withCategory(recipe))
+		}
+	}
+
+	sort.Slice(recipes, func(i, j int) bool {
+		if recipes[i].Name == recipes[j].Name {
+			return recipes[i].ID < recipes[j].ID
+		}
+		return recipes[i].Name < recipes[j].Name
+	})
+	return recipes
+}
+
+// paginate slices an already ordered result set the same way LIMIT/OFFSET would.
+func paginate[T any](items []T, params models.PaginationParams) []T {
+	offset := params.Offset()
+	if offset >= len(items) {
+		return []T{}
+	}
+
+	end := offset + params.PerPage
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
+// insertRecipeIngredient adds one ingredient line to a recipe, bringing back
+// the ingredient if it was archived, and returns it with the ingredient
+// details attached. Callers must hold mu for writing.
+func (s *Store) insertRecipeIngredient(recipeID int, req models.AddRecipeIngredientRequest) models.RecipeIngredient {
+	s.nextRecipeIngredientID++
+	ri := models.RecipeIngredient{
+		ID:           s.nextRecipeIngredientID,
+		RecipeID:     recipeID,
+		IngredientID: req.IngredientID,
+		Quantity:     req.Quantity,
+		Unit:         req.Unit,
+		Notes:        req.Notes,
+		CreatedAt:    now(),
+		ProductID:    req.ProductID,
+	}
+	s.recipeIngredients[ri.ID] = ri
+	delete(s.archived, req.IngredientID)
+
+	ri.Ingredient = s.ingredients[req.IngredientID]
+	ri.Product = s.productOf(ri)
+	return ri
+}
+
+// productOf returns the product a recipe ingredient names, if any. Callers
+// must hold mu.
+func (s *Store) productOf(ri models.RecipeIngredient) *models.IngredientProduct {
+	if ri.ProductID == nil {
+		return nil
+	}
+	product, ok := s.products[*ri.ProductID]
+	if !ok {
+		return nil
+	}
+	return &product
+}
+
+// replaceRecipeIngredients swaps the full ingredient list of a recipe.
+// Callers must hold mu for writing.
+func (s *Store) replaceRecipeIngredients(recipeID int, ingredients []models.AddRecipeIngredientRequest) {
+	s.deleteRecipeIngredients(recipeID)
+	for _, ingredient := range ingredients {
+		s.insertRecipeIngredient(recipeID, ingredient)
+	}
+}
+
+// deleteRecipeIngredients removes every ingredient line of a recipe.
+// Callers must hold mu for writing.
+func (s *Store) deleteRecipeIngredients(recipeID int) {
+	for id, ri := range s.recipeIngredients {
+		if ri.RecipeID == recipeID {
+			s.deleteRecipeIngredient(id)
+		}
+	}
+}
+
+// deleteRecipeIngredient removes one ingredient line, recording when its
+// ingredient was last used as the SQL trigger does. Callers must hold mu for
+// writing.
+func (s *Store) deleteRecipeIngredient(id int) {
+	if ri, ok := s.recipeIngredients[id]; ok {
+		s.ingredientsUsedAt[ri.IngredientID] = now()
+		delete(s.recipeIngredients, id)
+	}
+}
+
+// deleteProduct removes a product, leaving the recipes that used it on the
+// generic ingredient. Callers must hold mu for writing.
+func (s *Store) deleteProduct(id int) {
+	delete(s.products, id)
+	for riID, ri := range s.recipeIngredients {
+		if ri.ProductID != nil && *ri.ProductID == id {
+			ri.ProductID = nil
+			s.recipeIngredients[riID] = ri
+		}
+	}
+}
+
+// deleteIngredient removes an ingredient and everything that hangs off it.
+// Callers must hold mu for writing.
+func (s *Store) deleteIngredient(id int) {
+	delete(s.ingredients, id)
+	delete(s.densities, id)
+	delete(s.packSizes, id)
+	for productID, product := range s.products {
+		if product.IngredientID == id {
+			s.deleteProduct(productID)
+		}
+	}
+	delete(s.prices, id)
+	delete(s.substitutions, id)
+	delete(s.translations, id)
+	delete(s.dietaryFacts, id)
+	delete(s.ingredientAllergens, id)
+	delete(s.ingredientsUsedAt, id)
+	delete(s.archived, id)
+	for ingredientID, substitutions := range s.substitutions {
+		kept := substitutions[:0]
+		for _, substitution := range substitutions {
+			if substitution.SubstituteID != id {
+				kept = append(kept, substitution)
+			}
+		}
+		s.substitutions[ingredientID] = kept
+	}
+	for _, pantry := range s.pantries {
+		delete(pantry.items, id)
+	}
+	for searchID, search := range s.savedSearches {
+		kept := make([]int, 0, len(search.IngredientIDs))
+		for _, ingredientID := range search.IngredientIDs {
+			if ingredientID != id {
+				kept = append(kept, ingredientID)
+			}
+		}
+		search.IngredientIDs = kept
+		s.savedSearches[searchID] = search
+	}
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type storeLayoutRepository struct {
+	store *Store
+}
+
+func NewStoreLayoutRepository(store *Store) repository.StoreLayoutRepository {
+	return &storeLayoutRepository{store: store}
+}
+
+func (r *storeLayoutRepository) GetByUserID(userID int) ([]models.StoreLayout, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	layouts := make([]models.StoreLayout, 0)
+	for _, layout := range r.store.storeLayouts {
+		if layout.UserID == userID {
+			layouts = append(layouts, copyLayout(layout))
+		}
+	}
+
+	sort.Slice(layouts, func(i, j int) bool {
+		if layouts[i].Name != layouts[j].Name {
+			return layouts[i].Name < layouts[j].Name
+		}
+		return layouts[i].ID < layouts[j].ID
+	})
+	return layouts, nil
+}
+
+func (r *storeLayoutRepository) GetByID(id int) (*models.StoreLayout, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	layout, ok := r.store.storeLayouts[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	layout = copyLayout(layout)
+	return &layout, nil
+}
+
+func (r *storeLayoutRepository) Create(userID int, req models.StoreLayoutRequest) (*models.StoreLayout, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.nameTaken(userID, req.Name, 0) {
+		return nil, domain.ErrStoreLayoutExists
+	}
+
+	r.store.nextStoreLayoutID++
+	layout := models.StoreLayout{
+		ID:         r.store.nextStoreLayoutID,
+		UserID:     userID,
+		Name:       req.Name,
+		Categories: append([]string{}, req.Categories...),
+		CreatedAt:  now(),
+		UpdatedAt:  now(),
+	}
+	r.store.storeLayouts[layout.ID] = layout
+
+	layout = copyLayout(layout)
+	return &layout, nil
+}
+
+func (r *storeLayoutRepository) Update(id int, req models.StoreLayoutRequest) (*models.StoreLayout, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	layout, ok := r.store.storeLayouts[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	if r.nameTaken(layout.UserID, req.Name, id) {
+		return nil, domain.ErrStoreLayoutExists
+	}
+
+	layout.Name = req.Name
+	layout.Categories = append([]string{}, req.Categories...)
+	layout.UpdatedAt = now()
+	r.store.storeLayouts[id] = layout
+
+	layout = copyLayout(layout)
+	return &layout, nil
+}
+
+func (r *storeLayoutRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.storeLayouts[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.storeLayouts, id)
+	return nil
+}
+
+// nameTaken mirrors the (user_id, name) unique constraint. Callers must hold mu.
+func (r *storeLayoutRepository) nameTaken(userID int, name string, exceptID int) bool {
+	for _, layout := range r.store.storeLayouts {
+		if layout.UserID == userID && layout.Name == name && layout.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// copyLayout keeps callers from mutating the stored categories slice.
+func copyLayout(layout models.StoreLayout) models.StoreLayout {
+	layout.Categories = append([]string{}, layout.Categories...)
+	return layout
+}
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreLayoutRepository_Lifecycle(t *testing.T) {
+	repo := NewStoreLayoutRepository(NewStore())
+
+	created, err := repo.Create(7, models.StoreLayoutRequest{Name: "Supermarket", Categories: []string{"Vegetables", "Dairy"}})
+	require.NoError(t, err)
+
+	_, err = repo.Create(7, models.StoreLayoutRequest{Name: "Supermarket"})
+	assert.Equal(t, domain.ErrStoreLayoutExists, err)
+
+	// Another user may reuse the name
+	_, err = repo.Create(8, models.StoreLayoutRequest{Name: "Supermarket"})
+	require.NoError(t, err)
+
+	updated, err := repo.Update(created.ID, models.StoreLayoutRequest{Name: "Big shop", Categories: []string{"Dairy"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Dairy"}, updated.Categories)
+
+	layouts, err := repo.GetByUserID(7)
+	require.NoError(t, err)
+	require.Len(t, layouts, 1)
+	assert.Equal(t, "Big shop", layouts[0].Name)
+
+	require.NoError(t, repo.Delete(created.ID))
+	_, err = repo.GetByID(created.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"database/sql"
+	"sort"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/lib/pq"
+)
+
+type PantryRepository interface {
+	// GetPantry returns the pantry userID sees: their household's when they
+	// belong to one, else their own, which is empty at version 0 until it is
+	// first changed. Items come by name.
+	GetPantry(userID int) (*models.Pantry, error)
+	// SetPantry replaces the ingredients of userID's pantry, keeping when the
+	// ones already there were added. It returns sql.ErrNoRows when version
+	// is not 0 and the pantry is no longer at it.
+	SetPantry(userID int, ingredientIDs []int, version int) (*models.PantryChange, error)
+	// EditPantry adds and removes ingredients whatever the version, so people
+	// changing different items never conflict.
+	EditPantry(userID int, add, remove []int) (*models.PantryChange, error)
+
+	// FindCookable ranks the published recipes using any of the ingredients
+	// by the fraction of their ingredients among them, then by fewest
+	// missing. Recipes come without their missing ingredients.
+	FindCookable(ingredientIDs []int, params models.PaginationParams) ([]models.CookableRecipe, int, error)
+}
+
+type pantryRepository struct {
+	db *database.DB
+}
+
+func NewPantryRepository(db *database.DB) PantryRepository {
+	return &pantryRepository{db: db}
+}
+
+func (r *pantryRepository) GetPantry(userID int) (*models.Pantry, error) {
+	pantry := models.Pantry{Items: []models.PantryItem{}}
+	var pantryID int
+	var householdID sql.NullInt64
+	err := r.db.QueryRow(`
+		SELECT p.id, p.household_id, p.version
+		FROM recipe_catalogue.pantries p
+		LEFT JOIN recipe_catalogue.household_members m ON m.user_id = $1
+		WHERE p.household_id = m.household_id
+		   OR (m.household_id IS NULL AND p.user_id = $1)`, userID).
+		Scan(&pantryID, &householdID, &pantry.Version)
+	if err == sql.ErrNoRows {
+		return &pantry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if householdID.Valid {
+		id := int(householdID.Int64)
+		pantry.HouseholdID = &id
+	}
+
+	pantry.Items, err = queryPantryItems(r.db.Query, pantryID, "")
+	if err != nil {
+		return nil, err
+	}
+	return &pantry, nil
+}
+
+func (r *pantryRepository) SetPantry(userID int, ingredientIDs []int, version int) (*models.PantryChange, error) {
+	if len(ingredientIDs) == 0 {
+		return r.changePantry(userID, version, nil, `
+			DELETE FROM recipe_catalogue.pantry_items WHERE pantry_id = $1
+			RETURNING ingredient_id`)
+	}
+	return r.changePantry(userID, version, ingredientIDs, `
+		DELETE FROM recipe_catalogue.pantry_items
+		WHERE pantry_id = $1 AND NOT ingredient_id = ANY($2)
+		RETURNING ingredient_id`, pq.Array(ingredientIDs))
+}
+
+func (r *pantryRepository) EditPantry(userID int, add, remove []int) (*models.PantryChange, error) {
+	if len(remove) == 0 {
+		return r.changePantry(userID, 0, add, "")
+	}
+	return r.changePantry(userID, 0, add, `
+		DELETE FROM recipe_catalogue.pantry_items
+		WHERE pantry_id = $1 AND ingredient_id = ANY($2)
+		RETURNING ingredient_id`, pq.Array(remove))
+}
+
+// changePantry edits userID's pantry in one transaction: it locks the
+// pantry at version, runs removeQuery on it and adds the ingredients in
+// add. The version only goes up when something changed.
+func (r *pantryRepository) changePantry(userID, version int, add []int, removeQuery string, removeArgs ...interface{}) (*models.PantryChange, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	pantryID, householdID, current, err := lockPantry(tx, userID, version)
+	if err != nil {
+		return nil, err
+	}
+	change := models.PantryChange{
+		HouseholdID: householdID,
+		Version:     current,
+		ChangedBy:   userID,
+		Added:       []models.PantryItem{},
+		Removed:     []int{},
+	}
+
+	if removeQuery != "" {
+		rows, err := tx.Query(removeQuery, append([]interface{}{pantryID}, removeArgs...)...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var ingredientID int
+			if err := rows.Scan(&ingredientID); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			change.Removed = append(change.Removed, ingredientID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		sort.Ints(change.Removed)
+	}
+
+	var added []int
+	for _, ingredientID := range add {
+		err := tx.QueryRow(`
+			INSERT INTO recipe_catalogue.pantry_items (pantry_id, ingredient_id, added_by)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (pantry_id, ingredient_id) DO NOTHING
+			RETURNING ingredient_id`, pantryID, ingredientID, userID).Scan(&ingredientID)
+		if err == sql.ErrNoRows {
+			continue // already there
+		}
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, ingredientID)
+	}
+	if len(added) > 0 {
+		if change.Added, err = queryPantryItems(tx.Query, pantryID, "AND p.ingredient_id = ANY($2)", pq.Array(added)); err != nil {
+			return nil, err
+		}
+	}
+
+	if !change.Empty() {
+		err := tx.QueryRow(`
+			UPDATE recipe_catalogue.pantries
+			SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+			RETURNING version`, pantryID).Scan(&change.Version)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &change, tx.Commit()
+}
+
+// lockPantry finds the pantry userID edits, creating their own on first
+// use, and holds it until the transaction ends. It returns sql.ErrNoRows
+// when version is not 0 and the pantry is no longer at it.
+func lockPantry(tx *database.Tx, userID, version int) (pantryID int, householdID *int, current int, err error) {
+	var member int
+	err = tx.QueryRow(`
+		SELECT household_id FROM recipe_catalogue.household_members WHERE user_id = $1`, userID).Scan(&member)
+	switch {
+	case err == nil:
+		householdID = &member
+	case err != sql.ErrNoRows:
+		return 0, nil, 0, err
+	}
+
+	owner, ownerID := "user_id", userID
+	if householdID != nil {
+		owner, ownerID = "household_id", *householdID
+	} else {
+		_, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.pantries (user_id) VALUES ($1)
+			ON CONFLICT (user_id) DO NOTHING`, userID)
+		if err != nil {
+			return 0, nil, 0, err
+		}
+	}
+
+	// Updating the row, if only to itself, locks it against other edits
+	err = tx.QueryRow(`
+		UPDATE recipe_catalogue.pantries
+		SET version = version
+		WHERE `+owner+` = $1 AND ($2 = 0 OR version = $2)
+		RETURNING id, version`, ownerID, version).Scan(&pantryID, &current)
+	return pantryID, householdID, current, err
+}
+
+// queryPantryItems lists a pantry's items, by name, that match filter.
+func queryPantryItems(query func(string, ...interface{}) (*sql.Rows, error), pantryID int, filter string, args ...interface{}) ([]models.PantryItem, error) {
+	rows, err := query(`
+		SELECT p.ingredient_id, i.name, p.added_by, p.added_at
+		FROM recipe_catalogue.pantry_items p
+		JOIN recipe_catalogue.ingredients i ON i.id = p.ingredient_id
+		WHERE p.pantry_id = $1 `+filter+`
+		ORDER BY i.name, p.ingredient_id`, append([]interface{}{pantryID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.PantryItem, 0)
+	for rows.Next() {
+		var item models.PantryItem
+		if err := rows.Scan(&item.IngredientID, &item.Name, &item.AddedBy, &item.AddedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *pantryRepository) FindCookable(ingredientIDs []int, params models.PaginationParams) ([]models.CookableRecipe, int, error) {
+	if len(ingredientIDs) == 0 {
+		return []models.CookableRecipe{}, 0, nil
+	}
+
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(DISTINCT r.id)
+		FROM recipe_catalogue.recipes r
+		JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
+		WHERE ri.ingredient_id = ANY($1) AND r.status = 'published'`, pq.Array(ingredientIDs),
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT r.id, r.user_id, r.name, r.description, r.category_id, r.created_at, r.updated_at, r.status, r.difficulty, r.total_time_minutes,
+		       c.id, c.name, c.description,
+		       COUNT(*), SUM(CASE WHEN ri.ingredient_id = ANY($1) THEN 1 ELSE 0 END)
+		FROM recipe_catalogue.recipes r
+		LEFT JOIN recipe_catalogue.categories c ON r.category_id = c.id
+		JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
+		WHERE r.status = 'published'
+		GROUP BY r.id, c.id
+		HAVING SUM(CASE WHEN ri.ingredient_id = ANY($1) THEN 1 ELSE 0 END) > 0
+		ORDER BY SUM(CASE WHEN ri.ingredient_id = ANY($1) THEN 1 ELSE 0 END) * 1.0 / COUNT(*) DESC,
+		         COUNT(*) - SUM(CASE WHEN ri.ingredient_id = ANY($1) THEN 1 ELSE 0 END),
+		         r.name, r.id
+		LIMIT $2 OFFSET $3`, pq.Array(ingredientIDs), params.PerPage, params.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	recipes := make([]models.CookableRecipe, 0)
+	for rows.Next() {
+		var cookable models.CookableRecipe
+		category := models.Category{}
+		var categoryDesc sql.NullString
+
+		err := rows.Scan(
+			&cookable.ID, &cookable.UserID, &cookable.Name, &cookable.Description, &cookable.CategoryID,
+			&cookable.CreatedAt, &cookable.UpdatedAt, &cookable.Status, &cookable.Difficulty, &cookable.TotalTimeMinutes,
+			&category.ID, &category.Name, &categoryDesc,
+			&cookable.TotalIngredients, &cookable.OnHand,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if categoryDesc.Valid {
+			category.Description = &categoryDesc.String
+		}
+		cookable.Category = &category
+
+		recipes = append(recipes, cookable)
+	}
+	return recipes, total, rows.Err()
+}
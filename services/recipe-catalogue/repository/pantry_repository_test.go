@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PantryRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo PantryRepository
+}
+
+func (suite *PantryRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewPantryRepository(suite.db)
+}
+
+func (suite *PantryRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// expectLockPantry expects user 7's own pantry, 3 at version 4, to be
+// locked for an edit at version.
+func (suite *PantryRepositoryTestSuite) expectLockPantry(version int) {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.household_members WHERE user_id = $1`)).
+		WithArgs(7).
+		WillReturnError(sql.ErrNoRows)
+	suite.mock.ExpectExec(regexp.QuoteMeta(`ON CONFLICT (user_id) DO NOTHING`)).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WHERE user_id = $1 AND ($2 = 0 OR version = $2)`)).
+		WithArgs(7, version).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(3, 4))
+}
+
+func (suite *PantryRepositoryTestSuite) TestSetPantry_KeepsListedIngredients() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectBegin()
+	suite.expectLockPantry(4)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WHERE pantry_id = $1 AND NOT ingredient_id = ANY($2)`)).
+		WithArgs(3, pq.Array([]int{1, 6})).
+		WillReturnRows(sqlmock.NewRows([]string{"ingredient_id"}).AddRow(9).AddRow(2))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`ON CONFLICT (pantry_id, ingredient_id) DO NOTHING`)).
+		WithArgs(3, 1, 7).
+		WillReturnError(sql.ErrNoRows)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`ON CONFLICT (pantry_id, ingredient_id) DO NOTHING`)).
+		WithArgs(3, 6, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"ingredient_id"}).AddRow(6))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WHERE p.pantry_id = $1 AND p.ingredient_id = ANY($2)`)).
+		WithArgs(3, pq.Array([]int{6})).
+		WillReturnRows(sqlmock.NewRows([]string{"ingredient_id", "name", "added_by", "added_at"}).AddRow(6, "Garlic", 7, now))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SET version = version + 1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+	suite.mock.ExpectCommit()
+
+	// Act
+	change, err := suite.repo.SetPantry(7, []int{1, 6}, 4)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Nil(suite.T(), change.HouseholdID)
+	assert.Equal(suite.T(), 5, change.Version)
+	assert.Equal(suite.T(), []int{2, 9}, change.Removed)
+	require.Len(suite.T(), change.Added, 1)
+	assert.Equal(suite.T(), "Garlic", change.Added[0].Name)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *PantryRepositoryTestSuite) TestSetPantry_EmptyListEmptiesPantry() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.expectLockPantry(0)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM recipe_catalogue.pantry_items WHERE pantry_id = $1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"ingredient_id"}))
+	suite.mock.ExpectCommit()
+
+	// Act
+	change, err := suite.repo.SetPantry(7, nil, 0)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), change.Empty())
+	assert.Equal(suite.T(), 4, change.Version)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *PantryRepositoryTestSuite) TestSetPantry_StaleVersion() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.household_members WHERE user_id = $1`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"household_id"}).AddRow(2))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WHERE household_id = $1 AND ($2 = 0 OR version = $2)`)).
+		WithArgs(2, 3).
+		WillReturnError(sql.ErrNoRows)
+	suite.mock.ExpectRollback()
+
+	// Act
+	_, err := suite.repo.SetPantry(7, []int{1}, 3)
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *PantryRepositoryTestSuite) TestGetPantry_NoneYet() {
+	// Arrange
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.pantries p`)).
+		WithArgs(7).
+		WillReturnError(sql.ErrNoRows)
+
+	// Act
+	pantry, err := suite.repo.GetPantry(7)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), pantry.Items)
+	assert.Equal(suite.T(), 0, pantry.Version)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *PantryRepositoryTestSuite) TestFindCookable_ScansCounts() {
+	// Arrange
+	now := time.Now()
+	ingredientIDs := pq.Array([]int{1, 6})
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(DISTINCT r.id)`)).
+		WithArgs(ingredientIDs).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`HAVING SUM(CASE WHEN ri.ingredient_id = ANY($1) THEN 1 ELSE 0 END) > 0`)).
+		WithArgs(ingredientIDs, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at",
+			"status", "difficulty", "total_time_minutes", "id", "name", "description", "count", "sum"}).
+			AddRow(4, 7, "Garlic Chicken", nil, 2, now, now, "published", nil, nil, 2, "Dinner", nil, 3, 2))
+
+	// Act
+	recipes, total, err := suite.repo.FindCookable([]int{1, 6}, models.PaginationParams{Page: 1, PerPage: 20})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, total)
+	require.Len(suite.T(), recipes, 1)
+	assert.Equal(suite.T(), "Garlic Chicken", recipes[0].Name)
+	assert.Equal(suite.T(), "Dinner", recipes[0].Category.Name)
+	assert.Equal(suite.T(), 3, recipes[0].TotalIngredients)
+	assert.Equal(suite.T(), 2, recipes[0].OnHand)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *PantryRepositoryTestSuite) TestFindCookable_NoIngredients() {
+	// Act
+	recipes, total, err := suite.repo.FindCookable(nil, models.PaginationParams{Page: 1, PerPage: 20})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, total)
+	assert.Empty(suite.T(), recipes)
+}
+
+func TestPantryRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(PantryRepositoryTestSuite))
+}
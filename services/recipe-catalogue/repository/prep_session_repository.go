@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"database/sql"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+// prepDateLayout is how prep dates travel in requests and responses.
+const prepDateLayout = "2006-01-02"
+
+type PrepSessionRepository interface {
+	GetByUserID(userID int) ([]models.PrepSession, error)
+	GetByID(id int) (*models.PrepSession, error)
+	Create(userID int, req models.PrepSessionRequest) (*models.PrepSession, error)
+	Update(id int, req models.PrepSessionRequest) (*models.PrepSession, error)
+	Delete(id int) error
+}
+
+type prepSessionRepository struct {
+	db *database.DB
+}
+
+func NewPrepSessionRepository(db *database.DB) PrepSessionRepository {
+	return &prepSessionRepository{db: db}
+}
+
+// GetByUserID returns the user's sessions, newest first.
+func (r *prepSessionRepository) GetByUserID(userID int) ([]models.PrepSession, error) {
+	rows, err := r.db.Query(`
+		SELECT s.id, s.user_id, s.name, s.prep_date, s.created_at, s.updated_at,
+		       pr.recipe_id, pr.scale, pr.portions, pr.keeps_days, pr.reheat_instructions
+		FROM recipe_catalogue.prep_sessions s
+		LEFT JOIN recipe_catalogue.prep_session_recipes pr ON pr.session_id = s.id
+		WHERE s.user_id = $1
+		ORDER BY s.created_at DESC, s.id DESC, pr.position`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]models.PrepSession, 0)
+	for rows.Next() {
+		var session models.PrepSession
+		var prepDate sql.NullTime
+		var recipeID, portions, keepsDays sql.NullInt64
+		var scale sql.NullFloat64
+		var reheat sql.NullString
+		err := rows.Scan(&session.ID, &session.UserID, &session.Name, &prepDate, &session.CreatedAt, &session.UpdatedAt,
+			&recipeID, &scale, &portions, &keepsDays, &reheat)
+		if err != nil {
+			return nil, err
+		}
+
+		// Rows arrive grouped by session, one per recipe
+		if n := len(sessions); n == 0 || sessions[n-1].ID != session.ID {
+			session.PrepDate = formatPrepDate(prepDate)
+			session.Recipes = make([]models.PrepSessionRecipe, 0)
+			sessions = append(sessions, session)
+		}
+		if recipeID.Valid {
+			last := &sessions[len(sessions)-1]
+			last.Recipes = append(last.Recipes, models.PrepSessionRecipe{
+				RecipeID:           int(recipeID.Int64),
+				Scale:              scale.Float64,
+				Portions:           int(portions.Int64),
+				KeepsDays:          int(keepsDays.Int64),
+				ReheatInstructions: reheat.String,
+			})
+		}
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *prepSessionRepository) GetByID(id int) (*models.PrepSession, error) {
+	var session models.PrepSession
+	var prepDate sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT id, user_id, name, prep_date, created_at, updated_at
+		FROM recipe_catalogue.prep_sessions
+		WHERE id = $1`, id).
+		Scan(&session.ID, &session.UserID, &session.Name, &prepDate, &session.CreatedAt, &session.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	session.PrepDate = formatPrepDate(prepDate)
+
+	if session.Recipes, err = r.getRecipes(id); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *prepSessionRepository) Create(userID int, req models.PrepSessionRequest) (*models.PrepSession, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	session := models.PrepSession{UserID: userID, Name: req.Name, PrepDate: req.PrepDate, Recipes: req.Recipes}
+	err = tx.QueryRow(`
+		INSERT INTO recipe_catalogue.prep_sessions (user_id, name, prep_date, created_at, updated_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id, created_at, updated_at`, userID, req.Name, nullableText(req.PrepDate)).
+		Scan(&session.ID, &session.CreatedAt, &session.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertPrepRecipes(tx, session.ID, req.Recipes); err != nil {
+		return nil, err
+	}
+
+	return &session, tx.Commit()
+}
+
+// Update renames and redates the session and replaces its recipes.
+func (r *prepSessionRepository) Update(id int, req models.PrepSessionRequest) (*models.PrepSession, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	session := models.PrepSession{ID: id, Name: req.Name, PrepDate: req.PrepDate, Recipes: req.Recipes}
+	err = tx.QueryRow(`
+		UPDATE recipe_catalogue.prep_sessions
+		SET name = $2, prep_date = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING user_id, created_at, updated_at`, id, req.Name, nullableText(req.PrepDate)).
+		Scan(&session.UserID, &session.CreatedAt, &session.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM recipe_catalogue.prep_session_recipes WHERE session_id = $1", id); err != nil {
+		return nil, err
+	}
+	if err := insertPrepRecipes(tx, id, req.Recipes); err != nil {
+		return nil, err
+	}
+
+	return &session, tx.Commit()
+}
+
+func (r *prepSessionRepository) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM recipe_catalogue.prep_sessions WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *prepSessionRepository) getRecipes(sessionID int) ([]models.PrepSessionRecipe, error) {
+	rows, err := r.db.Query(`
+		SELECT recipe_id, scale, portions, keeps_days, reheat_instructions
+		FROM recipe_catalogue.prep_session_recipes
+		WHERE session_id = $1
+		ORDER BY position`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := make([]models.PrepSessionRecipe, 0)
+	for rows.Next() {
+		var recipe models.PrepSessionRecipe
+		var reheat sql.NullString
+		if err := rows.Scan(&recipe.RecipeID, &recipe.Scale, &recipe.Portions, &recipe.KeepsDays, &reheat); err != nil {
+			return nil, err
+		}
+		recipe.ReheatInstructions = reheat.String
+		recipes = append(recipes, recipe)
+	}
+	return recipes, rows.Err()
+}
+
+func insertPrepRecipes(tx *database.Tx, sessionID int, recipes []models.PrepSessionRecipe) error {
+	for position, recipe := range recipes {
+		_, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.prep_session_recipes
+				(session_id, recipe_id, position, scale, portions, keeps_days, reheat_instructions)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`, sessionID, recipe.RecipeID, position, recipe.Scale, recipe.Portions,
+			recipe.KeepsDays, nullableText(recipe.ReheatInstructions))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nullableText stores an empty string as NULL.
+func nullableText(text string) *string {
+	if text == "" {
+		return nil
+	}
+	return &text
+}
+
+func formatPrepDate(date sql.NullTime) string {
+	if !date.Valid {
+		return ""
+	}
+	return date.Time.Format(prepDateLayout)
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PrepSessionRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo PrepSessionRepository
+}
+
+func (suite *PrepSessionRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewPrepSessionRepository(suite.db)
+}
+
+func (suite *PrepSessionRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *PrepSessionRepositoryTestSuite) TestGetByUserID_GroupsRecipesBySession() {
+	// Arrange
+	now := time.Now()
+	prepDate := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.prep_sessions s`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "prep_date", "created_at", "updated_at", "recipe_id", "scale", "portions", "keeps_days", "reheat_instructions"}).
+			AddRow(2, 7, "Empty", nil, now, now, nil, nil, nil, nil, nil).
+			AddRow(1, 7, "Sunday prep", prepDate, now, now, 10, 2.0, 6, 4, "Microwave 3 minutes").
+			AddRow(1, 7, "Sunday prep", prepDate, now, now, 11, 1.0, 4, 3, nil))
+
+	// Act
+	sessions, err := suite.repo.GetByUserID(7)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), sessions, 2)
+	assert.Empty(suite.T(), sessions[0].Recipes)
+	assert.Empty(suite.T(), sessions[0].PrepDate)
+	assert.Equal(suite.T(), "2026-10-18", sessions[1].PrepDate)
+	assert.Equal(suite.T(), []models.PrepSessionRecipe{
+		{RecipeID: 10, Scale: 2, Portions: 6, KeepsDays: 4, ReheatInstructions: "Microwave 3 minutes"},
+		{RecipeID: 11, Scale: 1, Portions: 4, KeepsDays: 3},
+	}, sessions[1].Recipes)
+}
+
+func (suite *PrepSessionRepositoryTestSuite) TestCreate_InsertsRecipesInOrder() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.prep_sessions`)).
+		WithArgs(7, "Sunday prep", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(3, now, now))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.prep_session_recipes`)).
+		WithArgs(3, 10, 0, 2.0, 6, 4, "Microwave 3 minutes").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.prep_session_recipes`)).
+		WithArgs(3, 11, 1, 1.0, 4, 3, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	session, err := suite.repo.Create(7, models.PrepSessionRequest{
+		Name: "Sunday prep",
+		Recipes: []models.PrepSessionRecipe{
+			{RecipeID: 10, Scale: 2, Portions: 6, KeepsDays: 4, ReheatInstructions: "Microwave 3 minutes"},
+			{RecipeID: 11, Scale: 1, Portions: 4, KeepsDays: 3},
+		},
+	})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, session.ID)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *PrepSessionRepositoryTestSuite) TestUpdate_NotFound() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`UPDATE recipe_catalogue.prep_sessions`)).
+		WithArgs(99, "Sunday prep", "2026-10-18").
+		WillReturnError(sql.ErrNoRows)
+	suite.mock.ExpectRollback()
+
+	// Act
+	session, err := suite.repo.Update(99, models.PrepSessionRequest{Name: "Sunday prep", PrepDate: "2026-10-18"})
+
+	// Assert
+	assert.Nil(suite.T(), session)
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+}
+
+func (suite *PrepSessionRepositoryTestSuite) TestDelete_NotFound() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.prep_sessions WHERE id = $1")).
+		WithArgs(99).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := suite.repo.Delete(99)
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+}
+
+func TestPrepSessionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(PrepSessionRepositoryTestSuite))
+}
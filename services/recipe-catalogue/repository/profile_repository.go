@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type ProfileRepository interface {
+	GetSettings(userID int) (*models.ProfileSettings, error)
+	SaveSettings(settings models.ProfileSettings) (*models.ProfileSettings, error)
+	GetRatingSummary(userID int) (*float64, int, error)
+}
+
+type profileRepository struct {
+	db *database.DB
+}
+
+func NewProfileRepository(db *database.DB) ProfileRepository {
+	return &profileRepository{db: db}
+}
+
+// GetSettings returns sql.ErrNoRows for users who never saved settings.
+func (r *profileRepository) GetSettings(userID int) (*models.ProfileSettings, error) {
+	var settings models.ProfileSettings
+	err := r.db.QueryRow(`
+		SELECT user_id, display_name, bio, is_public, show_recipes, show_stats, measurement_system, locale, updated_at
+		FROM recipe_catalogue.user_profiles
+		WHERE user_id = $1`, userID).
+		Scan(&settings.UserID, &settings.DisplayName, &settings.Bio,
+			&settings.IsPublic, &settings.ShowRecipes, &settings.ShowStats, &settings.MeasurementSystem,
+			&settings.Locale, &settings.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *profileRepository) SaveSettings(settings models.ProfileSettings) (*models.ProfileSettings, error) {
+	err := r.db.QueryRow(`
+		INSERT INTO recipe_catalogue.user_profiles (user_id, display_name, bio, is_public, show_recipes, show_stats,
+		                                            measurement_system, locale, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE
+		SET display_name = EXCLUDED.display_name,
+		    bio = EXCLUDED.bio,
+		    is_public = EXCLUDED.is_public,
+		    show_recipes = EXCLUDED.show_recipes,
+		    show_stats = EXCLUDED.show_stats,
+		    measurement_system = EXCLUDED.measurement_system,
+		    locale = EXCLUDED.locale,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING updated_at`,
+		settings.UserID, settings.DisplayName, settings.Bio,
+		settings.IsPublic, settings.ShowRecipes, settings.ShowStats, settings.MeasurementSystem, settings.Locale).
+		Scan(&settings.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// GetRatingSummary averages the ratings cooks gave the user's published
+// recipes. Ratings are kept in the recommendations schema, which only exists
+// on PostgreSQL; other backends report no ratings.
+func (r *profileRepository) GetRatingSummary(userID int) (*float64, int, error) {
+	if r.db.Dialect() != database.DialectPostgres {
+		return nil, 0, nil
+	}
+
+	var average *float64
+	var count int
+	err := r.db.QueryRow(`
+		SELECT AVG(ch.rating)::float8, COUNT(ch.rating)
+		FROM recommendations.cooking_history ch
+		JOIN recipe_catalogue.recipes d ON d.id = ch.recipe_id
+		WHERE d.user_id = $1 AND d.status = 'published'`, userID).
+		Scan(&average, &count)
+	if err != nil {
+		return nil, 0, err
+	}
+	return average, count, nil
+}
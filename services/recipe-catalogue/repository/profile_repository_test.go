@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProfileRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo ProfileRepository
+}
+
+func (suite *ProfileRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewProfileRepository(suite.db)
+}
+
+func (suite *ProfileRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *ProfileRepositoryTestSuite) TestGetSettings_NotSaved() {
+	// Arrange
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.user_profiles`)).
+		WithArgs(3).
+		WillReturnError(sql.ErrNoRows)
+
+	// Act
+	settings, err := suite.repo.GetSettings(3)
+
+	// Assert
+	assert.Nil(suite.T(), settings)
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *ProfileRepositoryTestSuite) TestSaveSettings_Upserts() {
+	// Arrange
+	now := time.Now()
+	name := "Hanna"
+	locale := "de"
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`ON CONFLICT (user_id) DO UPDATE`)).
+		WithArgs(3, &name, nil, true, false, true, nil, &locale).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(now))
+
+	// Act
+	settings, err := suite.repo.SaveSettings(models.ProfileSettings{
+		UserID: 3, DisplayName: &name, IsPublic: true, ShowRecipes: false, ShowStats: true, Locale: &locale,
+	})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), now, settings.UpdatedAt)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *ProfileRepositoryTestSuite) TestGetRatingSummary() {
+	// Arrange
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recommendations.cooking_history ch`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"avg", "count"}).AddRow(4.25, 8))
+
+	// Act
+	average, count, err := suite.repo.GetRatingSummary(3)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 4.25, *average)
+	assert.Equal(suite.T(), 8, count)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestProfileRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ProfileRepositoryTestSuite))
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"database/sql"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/lib/pq"
+)
+
+type RecipeImageRepository interface {
+	GetByRecipeID(recipeID int) ([]models.RecipeImage, error)
+	// GetByRecipeIDs groups the images of several recipes by recipe ID,
+	// each in display order. Recipes without images are left out.
+	GetByRecipeIDs(recipeIDs []int) (map[int][]models.RecipeImage, error)
+	Add(recipeID int, req models.AddRecipeImageRequest) (*models.RecipeImage, error)
+	Save(recipeID int, images []models.RecipeImage) error
+	Delete(recipeID, imageID int) error
+}
+
+type recipeImageRepository struct {
+	db *database.DB
+}
+
+func NewRecipeImageRepository(db *database.DB) RecipeImageRepository {
+	return &recipeImageRepository{db: db}
+}
+
+func (r *recipeImageRepository) GetByRecipeID(recipeID int) ([]models.RecipeImage, error) {
+	rows, err := r.db.Query(`
+		SELECT id, recipe_id, url, thumbnail_url, caption, position, is_cover, created_at, storage_key
+		FROM recipe_catalogue.recipe_images
+		WHERE recipe_id = $1
+		ORDER BY position, id`, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := make([]models.RecipeImage, 0)
+	for rows.Next() {
+		image, err := scanRecipeImage(rows)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	return images, rows.Err()
+}
+
+func (r *recipeImageRepository) GetByRecipeIDs(recipeIDs []int) (map[int][]models.RecipeImage, error) {
+	result := make(map[int][]models.RecipeImage)
+	if len(recipeIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, recipe_id, url, thumbnail_url, caption, position, is_cover, created_at, storage_key
+		FROM recipe_catalogue.recipe_images
+		WHERE recipe_id = ANY($1)
+		ORDER BY recipe_id, position, id`, pq.Array(recipeIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		image, err := scanRecipeImage(rows)
+		if err != nil {
+			return nil, err
+		}
+		result[image.RecipeID] = append(result[image.RecipeID], image)
+	}
+	return result, rows.Err()
+}
+
+func scanRecipeImage(rows *sql.Rows) (models.RecipeImage, error) {
+	var image models.RecipeImage
+	err := rows.Scan(&image.ID, &image.RecipeID, &image.URL, &image.ThumbnailURL, &image.Caption,
+		&image.Position, &image.IsCover, &image.CreatedAt, &image.StorageKey)
+	return image, err
+}
+
+// Add appends an image after the recipe's existing ones. The first image of
+// a recipe becomes its cover.
+func (r *recipeImageRepository) Add(recipeID int, req models.AddRecipeImageRequest) (*models.RecipeImage, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var lastPosition, hasCover int
+	err = tx.QueryRow(`
+		SELECT COALESCE(MAX(position), 0), COALESCE(MAX(CASE WHEN is_cover THEN 1 ELSE 0 END), 0)
+		FROM recipe_catalogue.recipe_images
+		WHERE recipe_id = $1`, recipeID).
+		Scan(&lastPosition, &hasCover)
+	if err != nil {
+		return nil, err
+	}
+
+	image := models.RecipeImage{
+		RecipeID:     recipeID,
+		URL:          req.URL,
+		ThumbnailURL: req.ThumbnailURL,
+		Caption:      req.Caption,
+		Position:     lastPosition + 1,
+		IsCover:      hasCover == 0,
+		StorageKey:   req.StorageKey,
+	}
+	err = tx.QueryRow(`
+		INSERT INTO recipe_catalogue.recipe_images (recipe_id, url, thumbnail_url, caption, position, is_cover, storage_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP)
+		RETURNING id, created_at`, recipeID, image.URL, image.ThumbnailURL, image.Caption, image.Position, image.IsCover, image.StorageKey).
+		Scan(&image.ID, &image.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &image, tx.Commit()
+}
+
+// Save writes the position, caption and cover flag of each image. The cover
+// is cleared first so moving it never trips the one-cover index.
+func (r *recipeImageRepository) Save(recipeID int, images []models.RecipeImage) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE recipe_catalogue.recipe_images SET is_cover = FALSE WHERE recipe_id = $1", recipeID); err != nil {
+		return err
+	}
+
+	for _, image := range images {
+		_, err := tx.Exec(`
+			UPDATE recipe_catalogue.recipe_images
+			SET position = $3, caption = $4, is_cover = $5
+			WHERE id = $1 AND recipe_id = $2`, image.ID, recipeID, image.Position, image.Caption, image.IsCover)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes an image, closes the gap in positions and, when it was the
+// cover, promotes the new first image.
+func (r *recipeImageRepository) Delete(recipeID, imageID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var position int
+	var wasCover bool
+	err = tx.QueryRow(`
+		DELETE FROM recipe_catalogue.recipe_images
+		WHERE id = $1 AND recipe_id = $2
+		RETURNING position, is_cover`, imageID, recipeID).
+		Scan(&position, &wasCover)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE recipe_catalogue.recipe_images
+		SET position = position - 1
+		WHERE recipe_id = $1 AND position > $2`, recipeID, position)
+	if err != nil {
+		return err
+	}
+
+	if wasCover {
+		_, err = tx.Exec(`
+			UPDATE recipe_catalogue.recipe_images
+			SET is_cover = TRUE
+			WHERE id = (SELECT id FROM recipe_catalogue.recipe_images WHERE recipe_id = $1 ORDER BY position, id LIMIT 1)`, recipeID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecipeImageRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo RecipeImageRepository
+}
+
+func (suite *RecipeImageRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewRecipeImageRepository(suite.db)
+}
+
+func (suite *RecipeImageRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *RecipeImageRepositoryTestSuite) TestAdd_FirstImageBecomesCover() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.recipe_images`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"max", "has_cover"}).AddRow(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipe_images`)).
+		WithArgs(1, "https://img.example/1.jpg", nil, nil, 1, true, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, now))
+	suite.mock.ExpectCommit()
+
+	// Act
+	image, err := suite.repo.Add(1, models.AddRecipeImageRequest{URL: "https://img.example/1.jpg"})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 7, image.ID)
+	assert.True(suite.T(), image.IsCover)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RecipeImageRepositoryTestSuite) TestDelete_PromotesNextCover() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM recipe_catalogue.recipe_images`)).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"position", "is_cover"}).AddRow(1, true))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SET position = position - 1`)).
+		WithArgs(1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SET is_cover = TRUE`)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	err := suite.repo.Delete(1, 7)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RecipeImageRepositoryTestSuite) TestDelete_NotFound() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM recipe_catalogue.recipe_images`)).
+		WithArgs(99, 1).
+		WillReturnError(sql.ErrNoRows)
+	suite.mock.ExpectRollback()
+
+	// Act
+	err := suite.repo.Delete(1, 99)
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+}
+
+func TestRecipeImageRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RecipeImageRepositoryTestSuite))
+}
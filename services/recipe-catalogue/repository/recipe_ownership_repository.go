@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type RecipeOwnershipRepository interface {
+	// ListOwners returns every user owning recipes, least recently active
+	// first. A non-zero inactiveSince keeps only those whose last recipe
+	// activity is before it.
+	ListOwners(inactiveSince time.Time, params models.PaginationParams) ([]models.RecipeOwnership, int, error)
+}
+
+type recipeOwnershipRepository struct {
+	db *database.DB
+}
+
+func NewRecipeOwnershipRepository(db *database.DB) RecipeOwnershipRepository {
+	return &recipeOwnershipRepository{db: db}
+}
+
+func (r *recipeOwnershipRepository) ListOwners(inactiveSince time.Time, params models.PaginationParams) ([]models.RecipeOwnership, int, error) {
+	having := ""
+	args := []interface{}{}
+	if !inactiveSince.IsZero() {
+		having = "HAVING MAX(d.updated_at) < $1"
+		args = append(args, inactiveSince)
+	}
+
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM (
+			SELECT d.user_id FROM recipe_catalogue.recipes d
+			GROUP BY d.user_id `+having+`
+		) owners`, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT d.user_id, p.display_name, COUNT(*),
+		       SUM(CASE WHEN d.status = 'published' THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN d.status = 'draft' THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN d.status = 'private' THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN d.status = 'archived' THEN 1 ELSE 0 END),
+		       MIN(d.created_at), MAX(d.updated_at)
+		FROM recipe_catalogue.recipes d
+		LEFT JOIN recipe_catalogue.user_profiles p ON p.user_id = d.user_id
+		GROUP BY d.user_id, p.display_name %s
+		ORDER BY MAX(d.updated_at), d.user_id
+		LIMIT $%d OFFSET $%d`, having, len(args)+1, len(args)+2)
+
+	rows, err := r.db.Query(query, append(args, params.PerPage, params.Offset())...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	owners := make([]models.RecipeOwnership, 0)
+	for rows.Next() {
+		var owner models.RecipeOwnership
+		var displayName sql.NullString
+		err := rows.Scan(&owner.UserID, &displayName, &owner.RecipeCount,
+			&owner.PublishedCount, &owner.DraftCount, &owner.PrivateCount, &owner.ArchivedCount,
+			&owner.FirstRecipeAt, &owner.LastActivityAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		if displayName.Valid {
+			owner.DisplayName = &displayName.String
+		}
+		owners = append(owners, owner)
+	}
+	return owners, total, rows.Err()
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeOwnershipRepository_ListOwners(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewRecipeOwnershipRepository(&database.DB{DB: db})
+	inactiveSince := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	then := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("HAVING MAX(d.updated_at) < $1")).
+		WithArgs(inactiveSince).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY MAX(d.updated_at), d.user_id")).
+		WithArgs(inactiveSince, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "display_name", "count", "published", "draft", "private", "archived", "min", "max"}).
+			AddRow(7, "Grandma", 3, 2, 1, 0, 0, then, then).
+			AddRow(9, nil, 1, 0, 0, 1, 0, then, then))
+
+	owners, total, err := repo.ListOwners(inactiveSince, models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, owners, 2)
+	assert.Equal(t, "Grandma", *owners[0].DisplayName)
+	assert.Equal(t, 2, owners[0].PublishedCount)
+	assert.Nil(t, owners[1].DisplayName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeOwnershipRepository_ListOwnersWithoutCutoff(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewRecipeOwnershipRepository(&database.DB{DB: db})
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM")).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta("LIMIT $1 OFFSET $2")).
+		WithArgs(50, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "display_name", "count", "published", "draft", "private", "archived", "min", "max"}))
+
+	owners, total, err := repo.ListOwners(time.Time{}, models.PaginationParams{Page: 2, PerPage: 50})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, owners)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"database/sql"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type RecipeQualityRepository interface {
+	GetFacts(recipeID int) (*models.RecipeQualityFacts, error)
+	// ListFacts returns facts for every recipe in status, or for all recipes
+	// when status is empty, by ID
+	ListFacts(status string) ([]models.RecipeQualityFacts, error)
+}
+
+type recipeQualityRepository struct {
+	db *database.DB
+}
+
+func NewRecipeQualityRepository(db *database.DB) RecipeQualityRepository {
+	return &recipeQualityRepository{db: db}
+}
+
+const recipeQualityQuery = `
+		SELECT r.id, r.user_id, r.name, r.description, r.category_id, r.status, r.total_time_minutes,
+		       r.created_at, r.updated_at,
+		       (SELECT COUNT(*) FROM recipe_catalogue.recipe_steps s WHERE s.recipe_id = r.id),
+		       (SELECT COUNT(*) FROM recipe_catalogue.recipe_images i WHERE i.recipe_id = r.id)
+		FROM recipe_catalogue.recipes r`
+
+func (r *recipeQualityRepository) GetFacts(recipeID int) (*models.RecipeQualityFacts, error) {
+	rows, err := r.db.Query(recipeQualityQuery+`
+		WHERE r.id = $1`, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	facts, err := scanRecipeQualityFacts(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(facts) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &facts[0], nil
+}
+
+func (r *recipeQualityRepository) ListFacts(status string) ([]models.RecipeQualityFacts, error) {
+	rows, err := r.db.Query(recipeQualityQuery+`
+		WHERE $1 = '' OR r.status = $1
+		ORDER BY r.id`, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRecipeQualityFacts(rows)
+}
+
+func scanRecipeQualityFacts(rows *sql.Rows) ([]models.RecipeQualityFacts, error) {
+	facts := make([]models.RecipeQualityFacts, 0)
+	for rows.Next() {
+		var f models.RecipeQualityFacts
+		var categoryID, totalTime sql.NullInt64
+		err := rows.Scan(&f.ID, &f.UserID, &f.Name, &f.Description, &categoryID, &f.Status, &totalTime,
+			&f.CreatedAt, &f.UpdatedAt, &f.StepCount, &f.ImageCount)
+		if err != nil {
+			return nil, err
+		}
+		f.CategoryID = nullableInt(categoryID)
+		f.TotalTimeMinutes = nullableInt(totalTime)
+		facts = append(facts, f)
+	}
+	return facts, rows.Err()
+}
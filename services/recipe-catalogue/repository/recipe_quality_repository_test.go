@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var recipeQualityRowColumns = []string{"id", "user_id", "name", "description", "category_id", "status",
+	"total_time_minutes", "created_at", "updated_at", "step_count", "image_count"}
+
+func TestRecipeQualityRepository_GetFacts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewRecipeQualityRepository(&database.DB{DB: db})
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE r.id = $1")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(recipeQualityRowColumns).
+			AddRow(3, 7, "Soup", nil, 2, "published", 40, now, now, 4, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE r.id = $1")).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows(recipeQualityRowColumns))
+
+	facts, err := repo.GetFacts(3)
+	require.NoError(t, err)
+	assert.Equal(t, 7, facts.UserID)
+	assert.Nil(t, facts.Description)
+	require.NotNil(t, facts.TotalTimeMinutes)
+	assert.Equal(t, 40, *facts.TotalTimeMinutes)
+	assert.Equal(t, 4, facts.StepCount)
+	assert.Equal(t, 0, facts.ImageCount)
+
+	_, err = repo.GetFacts(4)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeQualityRepository_ListFacts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewRecipeQualityRepository(&database.DB{DB: db})
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE $1 = '' OR r.status = $1")).
+		WithArgs("draft").
+		WillReturnRows(sqlmock.NewRows(recipeQualityRowColumns).
+			AddRow(1, 7, "Soup", "Warming", nil, "draft", nil, now, now, 0, 2).
+			AddRow(5, 8, "Stew", nil, 2, "draft", 90, now, now, 3, 1))
+
+	facts, err := repo.ListFacts("draft")
+	require.NoError(t, err)
+	require.Len(t, facts, 2)
+	assert.Equal(t, "Warming", *facts[0].Description)
+	assert.Nil(t, facts[0].CategoryID)
+	assert.Nil(t, facts[0].TotalTimeMinutes)
+	assert.Equal(t, 2, facts[0].ImageCount)
+	assert.Equal(t, 5, facts[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
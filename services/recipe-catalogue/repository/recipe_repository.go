@@ -2,8 +2,11 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
+	"meal-prep/services/recipe-catalogue/domain"
 	"meal-prep/shared/database"
 	"meal-prep/shared/models"
+	"time"
 
 	"github.com/lib/pq"
 )
@@ -12,6 +15,7 @@ type RecipeRepository interface {
 	GetAll(params models.PaginationParams) ([]models.Recipe, int, error)
 	GetByID(id int) (*models.Recipe, error)
 	GetByCategory(categoryID int, params models.PaginationParams) ([]models.Recipe, int, error)
+	GetByOwner(userID int, status string, params models.PaginationParams) ([]models.Recipe, int, error)
 	GetOwnerID(id int) (int, error)
 	Create(userID int, req models.CreateRecipeRequest) (*models.Recipe, error)
 	Update(id int, req models.UpdateRecipeRequest) (*models.Recipe, error)
@@ -25,32 +29,43 @@ type RecipeRepository interface {
 
 	SearchRecipesByIngredients(ingredientIDs []int, params models.PaginationParams) ([]models.Recipe, int, error)
 	SearchRecipesByIngredientsWithIngredients(ingredientIDs []int, params models.PaginationParams) ([]models.RecipeWithIngredients, int, error)
+	GetSearchFacets(ingredientIDs []int, maxMinutes []int) (*models.RecipeFacets, error)
+
+	// Sitemap and feed
+	GetPublishedRecipes() ([]models.PublishedRecipe, error)
+	GetRecentlyUpdated(limit int) ([]models.Recipe, error)
+
+	// Saved search alerts
+	GetCreatedSince(since time.Time) ([]models.Recipe, error)
 }
 
 type recipeRepository struct {
 	db             *database.DB
 	ingredientRepo IngredientRepository
+	stepRepo       StepRepository
 }
 
 func NewRecipeRepository(db *database.DB) RecipeRepository {
 	return &recipeRepository{
 		db:             db,
 		ingredientRepo: NewIngredientRepository(db),
+		stepRepo:       NewStepRepository(db),
 	}
 }
 
 func (r *recipeRepository) GetAll(params models.PaginationParams) ([]models.Recipe, int, error) {
 	var total int
-	err := r.db.QueryRow(`SELECT COUNT(*) FROM recipe_catalogue.recipes`).Scan(&total)
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM recipe_catalogue.recipes WHERE status = 'published'`).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	query := `
-		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
 		       c.id, c.name, c.description
 		FROM recipe_catalogue.recipes d
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		WHERE d.status = 'published'
 		ORDER BY d.name
 		LIMIT $1 OFFSET $2`
 
@@ -74,30 +89,36 @@ func (r *recipeRepository) GetAll(params models.PaginationParams) ([]models.Reci
 
 func (r *recipeRepository) GetByID(id int) (*models.Recipe, error) {
 	query := `
-		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
-		       c.id, c.name, c.description
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
+		       c.id, c.name, c.description, d.version
 		FROM recipe_catalogue.recipes d
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
 		WHERE d.id = $1`
 
-	return r.scanRecipeWithCategory(r.db.QueryRow(query, id))
+	var version int
+	recipe, err := r.scanRecipeWithCategory(r.db.QueryRow(query, id), &version)
+	if err != nil {
+		return nil, err
+	}
+	recipe.Version = version
+	return recipe, nil
 }
 
 func (r *recipeRepository) GetByCategory(categoryID int, params models.PaginationParams) ([]models.Recipe, int, error) {
 	var total int
 	err := r.db.QueryRow(
-		`SELECT COUNT(*) FROM recipe_catalogue.recipes WHERE category_id = $1`, categoryID,
+		`SELECT COUNT(*) FROM recipe_catalogue.recipes WHERE category_id = $1 AND status = 'published'`, categoryID,
 	).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	query := `
-		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
 		       c.id, c.name, c.description
 		FROM recipe_catalogue.recipes d
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
-		WHERE d.category_id = $1
+		WHERE d.category_id = $1 AND d.status = 'published'
 		ORDER BY d.name
 		LIMIT $2 OFFSET $3`
 
@@ -119,6 +140,51 @@ func (r *recipeRepository) GetByCategory(categoryID int, params models.Paginatio
 	return recipes, total, nil
 }
 
+// GetByOwner lists a user's own recipes regardless of visibility, most recently
+// edited first. An empty status returns every status.
+func (r *recipeRepository) GetByOwner(userID int, status string, params models.PaginationParams) ([]models.Recipe, int, error) {
+	where := "d.user_id = $1"
+	args := []interface{}{userID}
+	if status != "" {
+		where += " AND d.status = $2"
+		args = append(args, status)
+	}
+
+	var total int
+	err := r.db.QueryRow(
+		`SELECT COUNT(*) FROM recipe_catalogue.recipes d WHERE `+where, args...,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
+		       c.id, c.name, c.description
+		FROM recipe_catalogue.recipes d
+		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		WHERE %s
+		ORDER BY d.updated_at DESC, d.id DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+
+	rows, err := r.db.Query(query, append(args, params.PerPage, params.Offset())...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	recipes := make([]models.Recipe, 0)
+	for rows.Next() {
+		recipe, err := r.scanRecipeWithCategory(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		recipes = append(recipes, *recipe)
+	}
+
+	return recipes, total, nil
+}
+
 // GetOwnerID returns the user_id of the recipe owner, or sql.ErrNoRows if not found.
 // Used by the service layer to enforce ownership before allowing mutations.
 func (r *recipeRepository) GetOwnerID(id int) (int, error) {
@@ -132,12 +198,12 @@ func (r *recipeRepository) GetOwnerID(id int) (int, error) {
 func (r *recipeRepository) Create(userID int, req models.CreateRecipeRequest) (*models.Recipe, error) {
 	var recipe models.Recipe
 	err := r.db.QueryRow(`
-		INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		RETURNING id, user_id, name, description, category_id, created_at, updated_at`,
-		req.Name, req.Description, req.CategoryID, userID).Scan(
+		INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id, status, difficulty, total_time_minutes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'published'), $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id, user_id, name, description, category_id, created_at, updated_at, status, difficulty, total_time_minutes`,
+		req.Name, req.Description, req.CategoryID, userID, req.Status, req.Difficulty, req.TotalTimeMinutes).Scan(
 		&recipe.ID, &recipe.UserID, &recipe.Name, &recipe.Description, &recipe.CategoryID,
-		&recipe.CreatedAt, &recipe.UpdatedAt)
+		&recipe.CreatedAt, &recipe.UpdatedAt, &recipe.Status, &recipe.Difficulty, &recipe.TotalTimeMinutes)
 
 	if err != nil {
 		return nil, err
@@ -146,6 +212,8 @@ func (r *recipeRepository) Create(userID int, req models.CreateRecipeRequest) (*
 	return &recipe, nil
 }
 
+// Update applies req when the recipe is still at req.Version (any version
+// when nil), returning sql.ErrNoRows otherwise.
 func (r *recipeRepository) Update(id int, req models.UpdateRecipeRequest) (*models.Recipe, error) {
 	var recipe models.Recipe
 	err := r.db.QueryRow(`
@@ -153,12 +221,16 @@ func (r *recipeRepository) Update(id int, req models.UpdateRecipeRequest) (*mode
         SET name = $2,
             description = $3,
             category_id = $4,
+            status = COALESCE(NULLIF($5, ''), status),
+            difficulty = COALESCE($6, difficulty),
+            total_time_minutes = COALESCE($7, total_time_minutes),
+            version = version + 1,
             updated_at = CURRENT_TIMESTAMP
-        WHERE id = $1
-		RETURNING id, user_id, name, description, category_id, created_at, updated_at`,
-		id, req.Name, req.Description, req.CategoryID).Scan(
+        WHERE id = $1 AND ($8 = 0 OR version = $8)
+		RETURNING id, user_id, name, description, category_id, created_at, updated_at, status, difficulty, total_time_minutes, version`,
+		id, req.Name, req.Description, req.CategoryID, req.Status, req.Difficulty, req.TotalTimeMinutes, expectedVersion(req.Version)).Scan(
 		&recipe.ID, &recipe.UserID, &recipe.Name, &recipe.Description, &recipe.CategoryID,
-		&recipe.CreatedAt, &recipe.UpdatedAt)
+		&recipe.CreatedAt, &recipe.UpdatedAt, &recipe.Status, &recipe.Difficulty, &recipe.TotalTimeMinutes, &recipe.Version)
 
 	if err != nil {
 		return nil, err
@@ -185,6 +257,85 @@ func (r *recipeRepository) Delete(id int) error {
 	return nil
 }
 
+// GetPublishedRecipes lists every published recipe in ID order, so sitemap
+// chunks stay stable as recipes are added.
+func (r *recipeRepository) GetPublishedRecipes() ([]models.PublishedRecipe, error) {
+	rows, err := r.db.Query(`
+		SELECT id, updated_at
+		FROM recipe_catalogue.recipes
+		WHERE status = 'published'
+		ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := make([]models.PublishedRecipe, 0)
+	for rows.Next() {
+		var recipe models.PublishedRecipe
+		if err := rows.Scan(&recipe.ID, &recipe.UpdatedAt); err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, recipe)
+	}
+	return recipes, rows.Err()
+}
+
+func (r *recipeRepository) GetRecentlyUpdated(limit int) ([]models.Recipe, error) {
+	query := `
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
+		       c.id, c.name, c.description
+		FROM recipe_catalogue.recipes d
+		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		WHERE d.status = 'published'
+		ORDER BY d.updated_at DESC, d.id DESC
+		LIMIT $1`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := make([]models.Recipe, 0)
+	for rows.Next() {
+		recipe, err := r.scanRecipeWithCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, *recipe)
+	}
+	return recipes, rows.Err()
+}
+
+// GetCreatedSince returns the published recipes created after since, oldest
+// first.
+func (r *recipeRepository) GetCreatedSince(since time.Time) ([]models.Recipe, error) {
+	query := `
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
+		       c.id, c.name, c.description
+		FROM recipe_catalogue.recipes d
+		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		WHERE d.status = 'published' AND d.created_at > $1
+		ORDER BY d.created_at, d.id`
+
+	rows, err := r.db.Query(query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := make([]models.Recipe, 0)
+	for rows.Next() {
+		recipe, err := r.scanRecipeWithCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, *recipe)
+	}
+	return recipes, rows.Err()
+}
+
 func (r *recipeRepository) GetAllWithIngredients(params models.PaginationParams) ([]models.RecipeWithIngredients, int, error) {
 	recipes, total, err := r.GetAll(params)
 	if err != nil {
@@ -210,9 +361,15 @@ func (r *recipeRepository) GetByIDWithIngredients(id int) (*models.RecipeWithIng
 		return nil, err
 	}
 
+	steps, err := r.stepRepo.GetByRecipeID(id)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.RecipeWithIngredients{
 		Recipe:      *recipe,
 		Ingredients: ingredients,
+		Steps:       steps,
 	}, nil
 }
 
@@ -239,12 +396,12 @@ func (r *recipeRepository) CreateWithIngredients(userID int, req models.CreateRe
 
 	var recipe models.Recipe
 	err = tx.QueryRow(`
-		INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		RETURNING id, user_id, name, description, category_id, created_at, updated_at`,
-		req.Name, req.Description, req.CategoryID, userID).Scan(
+		INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id, status, difficulty, total_time_minutes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'published'), $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id, user_id, name, description, category_id, created_at, updated_at, status, difficulty, total_time_minutes`,
+		req.Name, req.Description, req.CategoryID, userID, req.Status, req.Difficulty, req.TotalTimeMinutes).Scan(
 		&recipe.ID, &recipe.UserID, &recipe.Name, &recipe.Description, &recipe.CategoryID,
-		&recipe.CreatedAt, &recipe.UpdatedAt)
+		&recipe.CreatedAt, &recipe.UpdatedAt, &recipe.Status, &recipe.Difficulty, &recipe.TotalTimeMinutes)
 	if err != nil {
 		return nil, err
 	}
@@ -252,11 +409,24 @@ func (r *recipeRepository) CreateWithIngredients(userID int, req models.CreateRe
 	var ingredients []models.RecipeIngredient
 	if len(req.Ingredients) > 0 {
 		for _, ingredient := range req.Ingredients {
+			// Lines given by name are matched or created in this transaction,
+			// so a failed recipe leaves no new ingredients behind
+			ingredientID := ingredient.IngredientID
+			if ingredientID == 0 {
+				ingredientID, err = matchOrCreateIngredient(tx, ingredient.IngredientName, ingredient.IngredientCategory)
+				if err != nil {
+					return nil, err
+				}
+			}
+
 			_, err = tx.Exec(`
-				INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, created_at)
-				VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)`,
-				recipe.ID, ingredient.IngredientID, ingredient.Quantity, ingredient.Unit, ingredient.Notes)
+				INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)`,
+				recipe.ID, ingredientID, ingredient.Quantity, ingredient.Unit, ingredient.Notes, ingredient.ProductID)
 			if err != nil {
+				if database.IsUniqueViolation(err, "recipe_ingredients_unique_per_recipe") {
+					return nil, domain.ErrRecipeIngredientAlreadyExists
+				}
 				return nil, err
 			}
 		}
@@ -302,12 +472,16 @@ func (r *recipeRepository) UpdateWithIngredients(id int, req models.UpdateRecipe
 		SET name = COALESCE(NULLIF($2, ''), name),
 		    description = COALESCE($3, description),
 		    category_id = COALESCE(NULLIF($4, 0), category_id),
+		    status = COALESCE(NULLIF($5, ''), status),
+		    difficulty = COALESCE($6, difficulty),
+		    total_time_minutes = COALESCE($7, total_time_minutes),
+		    version = version + 1,
 		    updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1
-		RETURNING id, user_id, name, description, category_id, created_at, updated_at`,
-		id, req.Name, req.Description, req.CategoryID).Scan(
+		WHERE id = $1 AND ($8 = 0 OR version = $8)
+		RETURNING id, user_id, name, description, category_id, created_at, updated_at, status, difficulty, total_time_minutes`,
+		id, req.Name, req.Description, req.CategoryID, req.Status, req.Difficulty, req.TotalTimeMinutes, expectedVersion(req.Version)).Scan(
 		&recipe.ID, &recipe.UserID, &recipe.Name, &recipe.Description, &recipe.CategoryID,
-		&recipe.CreatedAt, &recipe.UpdatedAt)
+		&recipe.CreatedAt, &recipe.UpdatedAt, &recipe.Status, &recipe.Difficulty, &recipe.TotalTimeMinutes)
 	if err != nil {
 		return nil, err
 	}
@@ -320,9 +494,9 @@ func (r *recipeRepository) UpdateWithIngredients(id int, req models.UpdateRecipe
 
 		for _, ingredient := range req.Ingredients {
 			_, err = tx.Exec(`
-				INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, created_at)
-				VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)`,
-				id, ingredient.IngredientID, ingredient.Quantity, ingredient.Unit, ingredient.Notes)
+				INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)`,
+				id, ingredient.IngredientID, ingredient.Quantity, ingredient.Unit, ingredient.Notes, ingredient.ProductID)
 			if err != nil {
 				return nil, err
 			}
@@ -351,19 +525,19 @@ func (r *recipeRepository) SearchRecipesByIngredients(ingredientIDs []int, param
 		SELECT COUNT(DISTINCT r.id)
 		FROM recipe_catalogue.recipes r
 		JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
-		WHERE ri.ingredient_id = ANY($1)`, pq.Array(ingredientIDs),
+		WHERE ri.ingredient_id = ANY($1) AND r.status = 'published'`, pq.Array(ingredientIDs),
 	).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	query := `
-        SELECT DISTINCT r.id, r.user_id, r.name, r.description, r.category_id, r.created_at, r.updated_at,
+        SELECT DISTINCT r.id, r.user_id, r.name, r.description, r.category_id, r.created_at, r.updated_at, r.status, r.difficulty, r.total_time_minutes,
                         c.id, c.name, c.description
         FROM recipe_catalogue.recipes r
         LEFT JOIN recipe_catalogue.categories c ON r.category_id = c.id
         JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
-        WHERE ri.ingredient_id = ANY($1)
+        WHERE ri.ingredient_id = ANY($1) AND r.status = 'published'
         ORDER BY r.name
         LIMIT $2 OFFSET $3`
 
@@ -385,6 +559,65 @@ func (r *recipeRepository) SearchRecipesByIngredients(ingredientIDs []int, param
 	return recipes, total, nil
 }
 
+// GetSearchFacets counts the recipes SearchRecipesByIngredients would find,
+// per category and per max-time bucket.
+func (r *recipeRepository) GetSearchFacets(ingredientIDs []int, maxMinutes []int) (*models.RecipeFacets, error) {
+	facets := &models.RecipeFacets{Categories: []models.CategoryFacet{}, MaxTime: CountTimeBuckets(nil, maxMinutes)}
+	if len(ingredientIDs) == 0 {
+		return facets, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT c.id, c.name, COUNT(*)
+		FROM recipe_catalogue.recipes r
+		JOIN recipe_catalogue.categories c ON r.category_id = c.id
+		WHERE r.status = 'published' AND r.id IN (
+			SELECT recipe_id FROM recipe_catalogue.recipe_ingredients WHERE ingredient_id = ANY($1))
+		GROUP BY c.id, c.name
+		ORDER BY COUNT(*) DESC, c.name`, pq.Array(ingredientIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var facet models.CategoryFacet
+		if err := rows.Scan(&facet.CategoryID, &facet.Name, &facet.Count); err != nil {
+			return nil, err
+		}
+		facets.Categories = append(facets.Categories, facet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	timeRows, err := r.db.Query(`
+		SELECT r.total_time_minutes, COUNT(*)
+		FROM recipe_catalogue.recipes r
+		WHERE r.status = 'published' AND r.total_time_minutes IS NOT NULL AND r.id IN (
+			SELECT recipe_id FROM recipe_catalogue.recipe_ingredients WHERE ingredient_id = ANY($1))
+		GROUP BY r.total_time_minutes`, pq.Array(ingredientIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer timeRows.Close()
+
+	recipesByTime := make(map[int]int)
+	for timeRows.Next() {
+		var minutes, count int
+		if err := timeRows.Scan(&minutes, &count); err != nil {
+			return nil, err
+		}
+		recipesByTime[minutes] = count
+	}
+	if err := timeRows.Err(); err != nil {
+		return nil, err
+	}
+
+	facets.MaxTime = CountTimeBuckets(recipesByTime, maxMinutes)
+	return facets, nil
+}
+
 func (r *recipeRepository) SearchRecipesByIngredientsWithIngredients(ingredientIDs []int, params models.PaginationParams) ([]models.RecipeWithIngredients, int, error) {
 	recipes, total, err := r.SearchRecipesByIngredients(ingredientIDs, params)
 	if err != nil {
@@ -401,18 +634,21 @@ func (r *recipeRepository) SearchRecipesByIngredientsWithIngredients(ingredientI
 
 // scanRecipeWithCategory reads a recipe + its left-joined category from any scanner
 // (sql.Row or sql.Rows). Centralising scan logic here prevents drift between queries.
+// scanRecipeWithCategory scans the recipe and category columns, then any
+// extra columns the query selected after them into extra.
 func (r *recipeRepository) scanRecipeWithCategory(scanner interface {
 	Scan(...interface{}) error
-}) (*models.Recipe, error) {
+}, extra ...interface{}) (*models.Recipe, error) {
 	recipe := models.Recipe{}
 	category := models.Category{}
 	var categoryDesc sql.NullString
 
-	err := scanner.Scan(
+	dest := []interface{}{
 		&recipe.ID, &recipe.UserID, &recipe.Name, &recipe.Description, &recipe.CategoryID,
-		&recipe.CreatedAt, &recipe.UpdatedAt,
+		&recipe.CreatedAt, &recipe.UpdatedAt, &recipe.Status, &recipe.Difficulty, &recipe.TotalTimeMinutes,
 		&category.ID, &category.Name, &categoryDesc,
-	)
+	}
+	err := scanner.Scan(append(dest, extra...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -455,3 +691,11 @@ func (r *recipeRepository) attachIngredientsToRecipes(recipes []models.Recipe) (
 
 	return result, nil
 }
+
+// expectedVersion is the version an update is conditional on, 0 for any.
+func expectedVersion(version *int) int {
+	if version == nil {
+		return 0
+	}
+	return *version
+}
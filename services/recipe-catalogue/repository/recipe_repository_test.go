@@ -7,10 +7,12 @@ import (
 	"testing"
 	"time"
 
+	"meal-prep/services/recipe-catalogue/domain"
 	"meal-prep/shared/database"
 	"meal-prep/shared/models"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -46,23 +48,24 @@ func (suite *RecipeRepositoryTestSuite) TestGetAll_ReturnsRecipes() {
 	now := time.Now()
 	params := models.PaginationParams{Page: 1, PerPage: 20}
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.recipes`)).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.recipes WHERE status = 'published'`)).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
 		       c.id, c.name, c.description
 		FROM recipe_catalogue.recipes d
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		WHERE d.status = 'published'
 		ORDER BY d.name
 		LIMIT $1 OFFSET $2`)).
 		WithArgs(20, 0).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at",
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
 			"c_id", "c_name", "c_description",
 		}).
-			AddRow(1, 1, "Pasta", "Italian dish", 1, now, now, 1, "Italian", "Italian cuisine").
-			AddRow(2, 1, "Salad", "Fresh salad", 2, now, now, 2, "Healthy", "Healthy food"))
+			AddRow(1, 1, "Pasta", "Italian dish", 1, now, now, "published", nil, nil, 1, "Italian", "Italian cuisine").
+			AddRow(2, 1, "Salad", "Fresh salad", 2, now, now, "published", nil, nil, 2, "Healthy", "Healthy food"))
 
 	// Act
 	recipes, total, err := suite.repo.GetAll(params)
@@ -80,7 +83,7 @@ func (suite *RecipeRepositoryTestSuite) TestGetAll_DatabaseError() {
 	// Arrange
 	params := models.PaginationParams{Page: 1, PerPage: 20}
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.recipes`)).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.recipes WHERE status = 'published'`)).
 		WillReturnError(errors.New("connection lost"))
 
 	// Act
@@ -97,17 +100,17 @@ func (suite *RecipeRepositoryTestSuite) TestGetByID_ReturnsRecipe() {
 	// Arrange
 	now := time.Now()
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
-		       c.id, c.name, c.description
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
+		       c.id, c.name, c.description, d.version
 		FROM recipe_catalogue.recipes d
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
 		WHERE d.id = $1`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at",
-			"c_id", "c_name", "c_description",
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
+			"c_id", "c_name", "c_description", "version",
 		}).
-			AddRow(1, 1, "Carbonara", "Creamy pasta", 1, now, now, 1, "Italian", "Italian cuisine"))
+			AddRow(1, 1, "Carbonara", "Creamy pasta", 1, now, now, "published", nil, nil, 1, "Italian", "Italian cuisine", 1))
 
 	// Act
 	recipe, err := suite.repo.GetByID(1)
@@ -122,8 +125,8 @@ func (suite *RecipeRepositoryTestSuite) TestGetByID_ReturnsRecipe() {
 func (suite *RecipeRepositoryTestSuite) TestGetByID_NotFound() {
 	// Arrange
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
-		       c.id, c.name, c.description
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
+		       c.id, c.name, c.description, d.version
 		FROM recipe_catalogue.recipes d
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
 		WHERE d.id = $1`)).
@@ -144,24 +147,24 @@ func (suite *RecipeRepositoryTestSuite) TestGetByCategory_ReturnsFilteredRecipes
 	now := time.Now()
 	params := models.PaginationParams{Page: 1, PerPage: 20}
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.recipes WHERE category_id = $1`)).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.recipes WHERE category_id = $1 AND status = 'published'`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
 		       c.id, c.name, c.description
 		FROM recipe_catalogue.recipes d
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
-		WHERE d.category_id = $1
+		WHERE d.category_id = $1 AND d.status = 'published'
 		ORDER BY d.name
 		LIMIT $2 OFFSET $3`)).
 		WithArgs(1, 20, 0).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at",
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
 			"c_id", "c_name", "c_description",
 		}).
-			AddRow(1, 1, "Pasta Dish", "Italian pasta", 1, now, now, 1, "Italian", "Italian cuisine"))
+			AddRow(1, 1, "Pasta Dish", "Italian pasta", 1, now, now, "published", nil, nil, 1, "Italian", "Italian cuisine"))
 
 	// Act
 	recipes, total, err := suite.repo.GetByCategory(1, params)
@@ -173,6 +176,66 @@ func (suite *RecipeRepositoryTestSuite) TestGetByCategory_ReturnsFilteredRecipes
 	assert.Equal(suite.T(), 1, *recipes[0].CategoryID)
 }
 
+func (suite *RecipeRepositoryTestSuite) TestGetByOwner_FiltersByStatus() {
+	// Arrange
+	now := time.Now()
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.recipes d WHERE d.user_id = $1 AND d.status = $2`)).
+		WithArgs(7, "draft").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
+		       c.id, c.name, c.description
+		FROM recipe_catalogue.recipes d
+		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		WHERE d.user_id = $1 AND d.status = $2
+		ORDER BY d.updated_at DESC, d.id DESC
+		LIMIT $3 OFFSET $4`)).
+		WithArgs(7, "draft", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
+			"c_id", "c_name", "c_description",
+		}).
+			AddRow(3, 7, "Work In Progress", nil, 1, now, now, "draft", nil, nil, 1, "Italian", "Italian cuisine"))
+
+	// Act
+	recipes, total, err := suite.repo.GetByOwner(7, "draft", params)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, total)
+	assert.Len(suite.T(), recipes, 1)
+	assert.Equal(suite.T(), "draft", recipes[0].Status)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestGetByOwner_AllStatuses() {
+	// Arrange
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.recipes d WHERE d.user_id = $1`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WHERE d.user_id = $1
+		ORDER BY d.updated_at DESC, d.id DESC
+		LIMIT $2 OFFSET $3`)).
+		WithArgs(7, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
+			"c_id", "c_name", "c_description",
+		}))
+
+	// Act
+	recipes, total, err := suite.repo.GetByOwner(7, "", params)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, total)
+	assert.Empty(suite.T(), recipes)
+}
+
 func (suite *RecipeRepositoryTestSuite) TestCreate_InsertsAndReturnsRecipe() {
 	// Arrange
 	userID := 1
@@ -184,12 +247,12 @@ func (suite *RecipeRepositoryTestSuite) TestCreate_InsertsAndReturnsRecipe() {
 	now := time.Now()
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		RETURNING id, user_id, name, description, category_id, created_at, updated_at`)).
-		WithArgs(req.Name, req.Description, req.CategoryID, userID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at"}).
-			AddRow(1, userID, req.Name, req.Description, req.CategoryID, now, now))
+		INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id, status, difficulty, total_time_minutes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'published'), $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id, user_id, name, description, category_id, created_at, updated_at, status, difficulty, total_time_minutes`)).
+		WithArgs(req.Name, req.Description, req.CategoryID, userID, req.Status, req.Difficulty, req.TotalTimeMinutes).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes"}).
+			AddRow(1, userID, req.Name, req.Description, req.CategoryID, now, now, "published", nil, nil))
 
 	// Act
 	recipe, err := suite.repo.Create(userID, req)
@@ -213,10 +276,10 @@ func (suite *RecipeRepositoryTestSuite) TestCreate_DatabaseError() {
 	}
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		RETURNING id, user_id, name, description, category_id, created_at, updated_at`)).
-		WithArgs(req.Name, req.Description, req.CategoryID, userID).
+		INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id, status, difficulty, total_time_minutes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'published'), $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id, user_id, name, description, category_id, created_at, updated_at, status, difficulty, total_time_minutes`)).
+		WithArgs(req.Name, req.Description, req.CategoryID, userID, req.Status, req.Difficulty, req.TotalTimeMinutes).
 		WillReturnError(errors.New("constraint violation"))
 
 	// Act
@@ -241,12 +304,16 @@ func (suite *RecipeRepositoryTestSuite) TestUpdate_UpdatesAndReturnsRecipe() {
         SET name = $2,
             description = $3,
             category_id = $4,
+            status = COALESCE(NULLIF($5, ''), status),
+            difficulty = COALESCE($6, difficulty),
+            total_time_minutes = COALESCE($7, total_time_minutes),
+            version = version + 1,
             updated_at = CURRENT_TIMESTAMP
-        WHERE id = $1
-		RETURNING id, user_id, name, description, category_id, created_at, updated_at`)).
-		WithArgs(1, req.Name, req.Description, req.CategoryID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at"}).
-			AddRow(1, 1, req.Name, req.Description, req.CategoryID, now, now))
+        WHERE id = $1 AND ($8 = 0 OR version = $8)
+		RETURNING id, user_id, name, description, category_id, created_at, updated_at, status, difficulty, total_time_minutes, version`)).
+		WithArgs(1, req.Name, req.Description, req.CategoryID, req.Status, req.Difficulty, req.TotalTimeMinutes, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes", "version"}).
+			AddRow(1, 1, req.Name, req.Description, req.CategoryID, now, now, "published", nil, nil, 2))
 
 	// Act
 	recipe, err := suite.repo.Update(1, req)
@@ -254,6 +321,7 @@ func (suite *RecipeRepositoryTestSuite) TestUpdate_UpdatesAndReturnsRecipe() {
 	// Assert
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), recipe)
+	assert.Equal(suite.T(), 2, recipe.Version)
 	assert.Equal(suite.T(), req.Name, recipe.Name)
 }
 
@@ -266,10 +334,14 @@ func (suite *RecipeRepositoryTestSuite) TestUpdate_NotFound() {
         SET name = $2,
             description = $3,
             category_id = $4,
+            status = COALESCE(NULLIF($5, ''), status),
+            difficulty = COALESCE($6, difficulty),
+            total_time_minutes = COALESCE($7, total_time_minutes),
+            version = version + 1,
             updated_at = CURRENT_TIMESTAMP
-        WHERE id = $1
-		RETURNING id, user_id, name, description, category_id, created_at, updated_at`)).
-		WithArgs(999, req.Name, req.Description, req.CategoryID).
+        WHERE id = $1 AND ($8 = 0 OR version = $8)
+		RETURNING id, user_id, name, description, category_id, created_at, updated_at, status, difficulty, total_time_minutes, version`)).
+		WithArgs(999, req.Name, req.Description, req.CategoryID, req.Status, req.Difficulty, req.TotalTimeMinutes, 0).
 		WillReturnError(sql.ErrNoRows)
 
 	// Act
@@ -318,25 +390,25 @@ func (suite *RecipeRepositoryTestSuite) TestSearchRecipesByIngredients_ReturnsMa
 		SELECT COUNT(DISTINCT r.id)
 		FROM recipe_catalogue.recipes r
 		JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
-		WHERE ri.ingredient_id = ANY($1)`)).
+		WHERE ri.ingredient_id = ANY($1) AND r.status = 'published'`)).
 		WithArgs(sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-        SELECT DISTINCT r.id, r.user_id, r.name, r.description, r.category_id, r.created_at, r.updated_at,
+        SELECT DISTINCT r.id, r.user_id, r.name, r.description, r.category_id, r.created_at, r.updated_at, r.status, r.difficulty, r.total_time_minutes,
                         c.id, c.name, c.description
         FROM recipe_catalogue.recipes r
         LEFT JOIN recipe_catalogue.categories c ON r.category_id = c.id
         JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
-        WHERE ri.ingredient_id = ANY($1)
+        WHERE ri.ingredient_id = ANY($1) AND r.status = 'published'
         ORDER BY r.name
         LIMIT $2 OFFSET $3`)).
 		WithArgs(sqlmock.AnyArg(), 20, 0).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at",
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
 			"c_id", "c_name", "c_description",
 		}).
-			AddRow(1, 1, "Recipe with Ingredients", "Uses ingredients", 1, now, now, 1, "Category", "Desc"))
+			AddRow(1, 1, "Recipe with Ingredients", "Uses ingredients", 1, now, now, "published", nil, nil, 1, "Category", "Desc"))
 
 	// Act
 	recipes, total, err := suite.repo.SearchRecipesByIngredients(ingredientIDs, params)
@@ -369,21 +441,21 @@ func (suite *RecipeRepositoryTestSuite) TestGetByIDWithIngredients_ReturnsRecipe
 
 	// Mock GetByID call
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
-		       c.id, c.name, c.description
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
+		       c.id, c.name, c.description, d.version
 		FROM recipe_catalogue.recipes d
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
 		WHERE d.id = $1`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at",
-			"c_id", "c_name", "c_description",
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
+			"c_id", "c_name", "c_description", "version",
 		}).
-			AddRow(1, 1, "Carbonara", "Italian pasta", 1, now, now, 1, "Italian", "Italian cuisine"))
+			AddRow(1, 1, "Carbonara", "Italian pasta", 1, now, now, "published", nil, nil, 1, "Italian", "Italian cuisine", 1))
 
 	// Mock GetRecipeIngredients call
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at,
+		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at, ri.product_id,
 		       i.id, i.name, i.description, i.category, i.created_at
 		FROM recipe_catalogue.recipe_ingredients ri
 		JOIN recipe_catalogue.ingredients i ON ri.ingredient_id = i.id
@@ -391,11 +463,30 @@ func (suite *RecipeRepositoryTestSuite) TestGetByIDWithIngredients_ReturnsRecipe
 		ORDER BY ri.id`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at",
+			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at", "product_id",
 			"i_id", "i_name", "i_description", "i_category", "i_created_at",
 		}).
-			AddRow(1, 1, 1, 200.0, "grams", "Fresh pasta", now, 1, "Pasta", "Spaghetti", "Grain", now).
-			AddRow(2, 1, 2, 2.0, "pieces", nil, now, 2, "Eggs", "Fresh eggs", "Protein", now))
+			AddRow(1, 1, 1, 200.0, "grams", "Fresh pasta", now, nil, 1, "Pasta", "Spaghetti", "Grain", now).
+			AddRow(2, 1, 2, 2.0, "pieces", nil, now, nil, 2, "Eggs", "Fresh eggs", "Protein", now))
+
+	// Mock GetByRecipeID call for the method
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT s.id, s.recipe_id, s.position, s.instruction, s.duration_minutes, s.created_at, si.ingredient_id
+		FROM recipe_catalogue.recipe_steps s
+		LEFT JOIN recipe_catalogue.recipe_step_ingredients si ON si.step_id = s.id
+		WHERE s.recipe_id = $1
+		ORDER BY s.position, si.ingredient_id`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recipe_id", "position", "instruction", "duration_minutes", "created_at", "ingredient_id"}).
+			AddRow(10, 1, 1, "Boil the pasta", 10, now, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT t.step_id, t.label, t.duration_seconds
+		FROM recipe_catalogue.recipe_step_timers t
+		JOIN recipe_catalogue.recipe_steps s ON s.id = t.step_id
+		WHERE s.recipe_id = $1
+		ORDER BY t.step_id, t.position`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"step_id", "label", "duration_seconds"}))
 
 	// Act
 	result, err := suite.repo.GetByIDWithIngredients(1)
@@ -408,6 +499,16 @@ func (suite *RecipeRepositoryTestSuite) TestGetByIDWithIngredients_ReturnsRecipe
 	assert.Equal(suite.T(), 200.0, result.Ingredients[0].Quantity)
 	assert.Equal(suite.T(), "grams", result.Ingredients[0].Unit)
 	assert.Equal(suite.T(), "Pasta", result.Ingredients[0].Ingredient.Name)
+	require.Len(suite.T(), result.Steps, 1)
+	assert.Equal(suite.T(), "Boil the pasta", result.Steps[0].Instruction)
+	assert.Equal(suite.T(), []int{1}, result.Steps[0].IngredientIDs)
+}
+
+// expectNoSteps mocks GetByIDWithIngredients loading a recipe without a method.
+func (suite *RecipeRepositoryTestSuite) expectNoSteps(recipeID int) {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.recipe_steps s`)).
+		WithArgs(recipeID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recipe_id", "position", "instruction", "duration_minutes", "created_at", "ingredient_id"}))
 }
 
 func (suite *RecipeRepositoryTestSuite) TestGetAllWithIngredients_ReturnsRecipesWithIngredients() {
@@ -416,28 +517,29 @@ func (suite *RecipeRepositoryTestSuite) TestGetAllWithIngredients_ReturnsRecipes
 	params := models.PaginationParams{Page: 1, PerPage: 20}
 
 	// Mock COUNT query
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.recipes`)).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM recipe_catalogue.recipes WHERE status = 'published'`)).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
 	// Mock GetAll call with LIMIT/OFFSET
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
 		       c.id, c.name, c.description
 		FROM recipe_catalogue.recipes d
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		WHERE d.status = 'published'
 		ORDER BY d.name
 		LIMIT $1 OFFSET $2`)).
 		WithArgs(20, 0).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at",
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
 			"c_id", "c_name", "c_description",
 		}).
-			AddRow(1, 1, "Recipe 1", "Desc 1", 1, now, now, 1, "Category 1", "Cat desc").
-			AddRow(2, 1, "Recipe 2", "Desc 2", 2, now, now, 2, "Category 2", "Cat desc"))
+			AddRow(1, 1, "Recipe 1", "Desc 1", 1, now, now, "published", nil, nil, 1, "Category 1", "Cat desc").
+			AddRow(2, 1, "Recipe 2", "Desc 2", 2, now, now, "published", nil, nil, 2, "Category 2", "Cat desc"))
 
 	// Mock GetIngredientsForRecipes call
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-        SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at,
+        SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at, ri.product_id,
                i.id, i.name, i.description, i.category, i.created_at
         FROM recipe_catalogue.recipe_ingredients ri
         JOIN recipe_catalogue.ingredients i ON ri.ingredient_id = i.id
@@ -445,11 +547,11 @@ func (suite *RecipeRepositoryTestSuite) TestGetAllWithIngredients_ReturnsRecipes
         ORDER BY ri.recipe_id, ri.id`)).
 		WithArgs(sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at",
+			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at", "product_id",
 			"i_id", "i_name", "i_description", "i_category", "i_created_at",
 		}).
-			AddRow(1, 1, 1, 100.0, "g", nil, now, 1, "Ingredient 1", "Desc", "Cat", now).
-			AddRow(2, 2, 2, 200.0, "ml", nil, now, 2, "Ingredient 2", "Desc", "Cat", now))
+			AddRow(1, 1, 1, 100.0, "g", nil, now, nil, 1, "Ingredient 1", "Desc", "Cat", now).
+			AddRow(2, 2, 2, 200.0, "ml", nil, now, nil, 2, "Ingredient 2", "Desc", "Cat", now))
 
 	// Act
 	result, total, err := suite.repo.GetAllWithIngredients(params)
@@ -485,18 +587,18 @@ func (suite *RecipeRepositoryTestSuite) TestCreateWithIngredients_CommitsTransac
 
 	// Expect recipe creation
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		RETURNING id, user_id, name, description, category_id, created_at, updated_at`)).
-		WithArgs(req.Name, req.Description, req.CategoryID, userID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at"}).
-			AddRow(1, userID, req.Name, *req.Description, req.CategoryID, now, now))
+		INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id, status, difficulty, total_time_minutes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'published'), $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id, user_id, name, description, category_id, created_at, updated_at, status, difficulty, total_time_minutes`)).
+		WithArgs(req.Name, req.Description, req.CategoryID, userID, req.Status, req.Difficulty, req.TotalTimeMinutes).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes"}).
+			AddRow(1, userID, req.Name, *req.Description, req.CategoryID, now, now, "published", nil, nil))
 
 	// Expect ingredient addition
 	suite.mock.ExpectExec(regexp.QuoteMeta(`
-			INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, created_at)
-			VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)`)).
-		WithArgs(1, 1, 100.0, "grams", stringPtr("Fresh")).
+			INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)`)).
+		WithArgs(1, 1, 100.0, "grams", stringPtr("Fresh"), (*int)(nil)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Expect commit
@@ -504,7 +606,7 @@ func (suite *RecipeRepositoryTestSuite) TestCreateWithIngredients_CommitsTransac
 
 	// Expect ingredient retrieval after commit
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at,
+		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at, ri.product_id,
 		       i.id, i.name, i.description, i.category, i.created_at
 		FROM recipe_catalogue.recipe_ingredients ri
 		JOIN recipe_catalogue.ingredients i ON ri.ingredient_id = i.id
@@ -512,24 +614,24 @@ func (suite *RecipeRepositoryTestSuite) TestCreateWithIngredients_CommitsTransac
 		ORDER BY ri.id`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at",
+			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at", "product_id",
 			"i_id", "i_name", "i_description", "i_category", "i_created_at",
 		}).
-			AddRow(1, 1, 1, 100.0, "grams", "Fresh", now, 1, "Ingredient", "Desc", "Cat", now))
+			AddRow(1, 1, 1, 100.0, "grams", "Fresh", now, nil, 1, "Ingredient", "Desc", "Cat", now))
 
 	// Expect GetByID call for category information
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
-		       c.id, c.name, c.description
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
+		       c.id, c.name, c.description, d.version
 		FROM recipe_catalogue.recipes d
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
 		WHERE d.id = $1`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at",
-			"c_id", "c_name", "c_description",
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
+			"c_id", "c_name", "c_description", "version",
 		}).
-			AddRow(1, userID, req.Name, *req.Description, req.CategoryID, now, now, req.CategoryID, "Category", "Category desc"))
+			AddRow(1, userID, req.Name, *req.Description, req.CategoryID, now, now, "published", nil, nil, req.CategoryID, "Category", "Category desc", 1))
 
 	// Act
 	result, err := suite.repo.CreateWithIngredients(userID, req)
@@ -558,18 +660,18 @@ func (suite *RecipeRepositoryTestSuite) TestCreateWithIngredients_RollsBackOnErr
 
 	// Expect recipe creation to succeed
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		RETURNING id, user_id, name, description, category_id, created_at, updated_at`)).
-		WithArgs(req.Name, req.Description, req.CategoryID, userID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at"}).
-			AddRow(1, userID, req.Name, "", req.CategoryID, now, now))
+		INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id, status, difficulty, total_time_minutes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'published'), $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id, user_id, name, description, category_id, created_at, updated_at, status, difficulty, total_time_minutes`)).
+		WithArgs(req.Name, req.Description, req.CategoryID, userID, req.Status, req.Difficulty, req.TotalTimeMinutes).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes"}).
+			AddRow(1, userID, req.Name, "", req.CategoryID, now, now, "published", nil, nil))
 
 	// Expect ingredient addition to fail
 	suite.mock.ExpectExec(regexp.QuoteMeta(`
-			INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, created_at)
-			VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)`)).
-		WithArgs(1, 1, 100.0, "grams", (*string)(nil)).
+			INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)`)).
+		WithArgs(1, 1, 100.0, "grams", (*string)(nil), (*int)(nil)).
 		WillReturnError(errors.New("constraint violation"))
 
 	// Expect rollback
@@ -584,6 +686,129 @@ func (suite *RecipeRepositoryTestSuite) TestCreateWithIngredients_RollsBackOnErr
 	assert.Contains(suite.T(), err.Error(), "constraint violation")
 }
 
+func (suite *RecipeRepositoryTestSuite) TestCreateWithIngredients_MatchesNamedIngredientInTransaction() {
+	// Arrange
+	userID := 1
+	req := models.CreateRecipeWithIngredientsRequest{
+		Name:       "Recipe",
+		CategoryID: 1,
+		Ingredients: []models.AddRecipeIngredientRequest{
+			{IngredientName: "tomatoes", Quantity: 2, Unit: "pieces"},
+		},
+	}
+	now := time.Now()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipes`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes"}).
+			AddRow(1, userID, req.Name, "", req.CategoryID, now, now, "published", nil, nil))
+
+	// The name is matched against ingredient names and translations
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT id, name, FALSE FROM recipe_catalogue.ingredients WHERE name % $1 OR lower(name) = lower($1)
+		UNION ALL
+		SELECT ingredient_id, name, TRUE FROM recipe_catalogue.ingredient_translations WHERE name % $1 OR lower(name) = lower($1)`)).
+		WithArgs("tomatoes").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "alias"}).
+			AddRow(14, "Tomato", false).
+			AddRow(15, "Tomato Paste", false))
+
+	// The line uses the matched ingredient; failing it rolls everything back
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipe_ingredients`)).
+		WithArgs(1, 14, 2.0, "pieces", (*string)(nil), (*int)(nil)).
+		WillReturnError(errors.New("connection reset"))
+	suite.mock.ExpectRollback()
+
+	// Act
+	result, err := suite.repo.CreateWithIngredients(userID, req)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), result)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RecipeRepositoryTestSuite) TestCreateWithIngredients_CreatesUnmatchedIngredient() {
+	// Arrange
+	userID := 1
+	req := models.CreateRecipeWithIngredientsRequest{
+		Name:       "Recipe",
+		CategoryID: 1,
+		Ingredients: []models.AddRecipeIngredientRequest{
+			{IngredientName: "Sumac", IngredientCategory: "Spices", Quantity: 1, Unit: "tsp"},
+		},
+	}
+	now := time.Now()
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipes`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes"}).
+			AddRow(1, userID, req.Name, "", req.CategoryID, now, now, "published", nil, nil))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, FALSE FROM recipe_catalogue.ingredients`)).
+		WithArgs("Sumac").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "alias"}).AddRow(10, "Salt", false))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		INSERT INTO recipe_catalogue.ingredients (name, category, created_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		RETURNING id`)).
+		WithArgs("Sumac", "Spices").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipe_ingredients`)).
+		WithArgs(1, 40, 1.0, "tsp", (*string)(nil), (*int)(nil)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.recipe_ingredients ri`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at", "product_id",
+			"i_id", "i_name", "i_description", "i_category", "i_created_at",
+		}).
+			AddRow(1, 1, 40, 1.0, "tsp", nil, now, nil, 40, "Sumac", nil, "Spices", now))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.recipes d`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
+			"c_id", "c_name", "c_description", "version",
+		}).
+			AddRow(1, userID, req.Name, "", req.CategoryID, now, now, "published", nil, nil, req.CategoryID, "Category", "Category desc", 1))
+
+	// Act
+	result, err := suite.repo.CreateWithIngredients(userID, req)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result.Ingredients, 1)
+	assert.Equal(suite.T(), "Sumac", result.Ingredients[0].Ingredient.Name)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RecipeRepositoryTestSuite) TestCreateWithIngredients_UnmatchedIngredientNeedsCategory() {
+	// Arrange
+	now := time.Now()
+	req := models.CreateRecipeWithIngredientsRequest{
+		Name:        "Recipe",
+		CategoryID:  1,
+		Ingredients: []models.AddRecipeIngredientRequest{{IngredientName: "Sumac", Quantity: 1, Unit: "tsp"}},
+	}
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipes`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes"}).
+			AddRow(1, 1, req.Name, "", req.CategoryID, now, now, "published", nil, nil))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, FALSE FROM recipe_catalogue.ingredients`)).
+		WithArgs("Sumac").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "alias"}))
+	suite.mock.ExpectRollback()
+
+	// Act
+	result, err := suite.repo.CreateWithIngredients(1, req)
+
+	// Assert
+	assert.Equal(suite.T(), domain.ErrIngredientCategoryRequired, err)
+	assert.Nil(suite.T(), result)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
 func (suite *RecipeRepositoryTestSuite) TestUpdateWithIngredients_UpdatesBothRecipeAndIngredients() {
 	// Arrange
 	req := models.UpdateRecipeWithIngredientsRequest{
@@ -604,12 +829,16 @@ func (suite *RecipeRepositoryTestSuite) TestUpdateWithIngredients_UpdatesBothRec
 		SET name = COALESCE(NULLIF($2, ''), name),
 		    description = COALESCE($3, description),
 		    category_id = COALESCE(NULLIF($4, 0), category_id),
+		    status = COALESCE(NULLIF($5, ''), status),
+		    difficulty = COALESCE($6, difficulty),
+		    total_time_minutes = COALESCE($7, total_time_minutes),
+		    version = version + 1,
 		    updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1
-		RETURNING id, user_id, name, description, category_id, created_at, updated_at`)).
-		WithArgs(1, req.Name, req.Description, req.CategoryID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at"}).
-			AddRow(1, 1, req.Name, req.Description, 1, now, now))
+		WHERE id = $1 AND ($8 = 0 OR version = $8)
+		RETURNING id, user_id, name, description, category_id, created_at, updated_at, status, difficulty, total_time_minutes`)).
+		WithArgs(1, req.Name, req.Description, req.CategoryID, req.Status, req.Difficulty, req.TotalTimeMinutes, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes"}).
+			AddRow(1, 1, req.Name, req.Description, 1, now, now, "published", nil, nil))
 
 	// Expect ingredient deletion
 	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.recipe_ingredients WHERE recipe_id = $1")).
@@ -618,9 +847,9 @@ func (suite *RecipeRepositoryTestSuite) TestUpdateWithIngredients_UpdatesBothRec
 
 	// Expect ingredient addition
 	suite.mock.ExpectExec(regexp.QuoteMeta(`
-			INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, created_at)
-			VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)`)).
-		WithArgs(1, 1, 150.0, "grams", (*string)(nil)).
+			INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes, product_id, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)`)).
+		WithArgs(1, 1, 150.0, "grams", (*string)(nil), (*int)(nil)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Expect commit
@@ -628,20 +857,20 @@ func (suite *RecipeRepositoryTestSuite) TestUpdateWithIngredients_UpdatesBothRec
 
 	// Mock GetByIDWithIngredients call after commit
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
-		       c.id, c.name, c.description
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
+		       c.id, c.name, c.description, d.version
 		FROM recipe_catalogue.recipes d
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
 		WHERE d.id = $1`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at",
-			"c_id", "c_name", "c_description",
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
+			"c_id", "c_name", "c_description", "version",
 		}).
-			AddRow(1, 1, req.Name, req.Description, 1, now, now, 1, "Category", "Desc"))
+			AddRow(1, 1, req.Name, req.Description, 1, now, now, "published", nil, nil, 1, "Category", "Desc", 1))
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at,
+		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at, ri.product_id,
 		       i.id, i.name, i.description, i.category, i.created_at
 		FROM recipe_catalogue.recipe_ingredients ri
 		JOIN recipe_catalogue.ingredients i ON ri.ingredient_id = i.id
@@ -649,10 +878,11 @@ func (suite *RecipeRepositoryTestSuite) TestUpdateWithIngredients_UpdatesBothRec
 		ORDER BY ri.id`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at",
+			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at", "product_id",
 			"i_id", "i_name", "i_description", "i_category", "i_created_at",
 		}).
-			AddRow(1, 1, 1, 150.0, "grams", nil, now, 1, "Ingredient", "Desc", "Cat", now))
+			AddRow(1, 1, 1, 150.0, "grams", nil, now, nil, 1, "Ingredient", "Desc", "Cat", now))
+	suite.expectNoSteps(1)
 
 	// Act
 	result, err := suite.repo.UpdateWithIngredients(1, req)
@@ -682,32 +912,36 @@ func (suite *RecipeRepositoryTestSuite) TestUpdateWithIngredients_SkipsIngredien
 		SET name = COALESCE(NULLIF($2, ''), name),
 		    description = COALESCE($3, description),
 		    category_id = COALESCE(NULLIF($4, 0), category_id),
+		    status = COALESCE(NULLIF($5, ''), status),
+		    difficulty = COALESCE($6, difficulty),
+		    total_time_minutes = COALESCE($7, total_time_minutes),
+		    version = version + 1,
 		    updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1
-		RETURNING id, user_id, name, description, category_id, created_at, updated_at`)).
-		WithArgs(1, req.Name, req.Description, req.CategoryID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at"}).
-			AddRow(1, 1, req.Name, "", 1, now, now))
+		WHERE id = $1 AND ($8 = 0 OR version = $8)
+		RETURNING id, user_id, name, description, category_id, created_at, updated_at, status, difficulty, total_time_minutes`)).
+		WithArgs(1, req.Name, req.Description, req.CategoryID, req.Status, req.Difficulty, req.TotalTimeMinutes, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes"}).
+			AddRow(1, 1, req.Name, "", 1, now, now, "published", nil, nil))
 
 	// Expect commit (no ingredient operations)
 	suite.mock.ExpectCommit()
 
 	// Mock GetByIDWithIngredients call
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
-		       c.id, c.name, c.description
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status, d.difficulty, d.total_time_minutes,
+		       c.id, c.name, c.description, d.version
 		FROM recipe_catalogue.recipes d
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
 		WHERE d.id = $1`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at",
-			"c_id", "c_name", "c_description",
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
+			"c_id", "c_name", "c_description", "version",
 		}).
-			AddRow(1, 1, req.Name, "", 1, now, now, 1, "Category", "Desc"))
+			AddRow(1, 1, req.Name, "", 1, now, now, "published", nil, nil, 1, "Category", "Desc", 1))
 
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at,
+		SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at, ri.product_id,
 		       i.id, i.name, i.description, i.category, i.created_at
 		FROM recipe_catalogue.recipe_ingredients ri
 		JOIN recipe_catalogue.ingredients i ON ri.ingredient_id = i.id
@@ -715,9 +949,10 @@ func (suite *RecipeRepositoryTestSuite) TestUpdateWithIngredients_SkipsIngredien
 		ORDER BY ri.id`)).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at",
+			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at", "product_id",
 			"i_id", "i_name", "i_description", "i_category", "i_created_at",
 		})) // Empty rows
+	suite.expectNoSteps(1)
 
 	// Act
 	result, err := suite.repo.UpdateWithIngredients(1, req)
@@ -740,30 +975,30 @@ func (suite *RecipeRepositoryTestSuite) TestSearchRecipesByIngredientsWithIngred
 		SELECT COUNT(DISTINCT r.id)
 		FROM recipe_catalogue.recipes r
 		JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
-		WHERE ri.ingredient_id = ANY($1)`)).
+		WHERE ri.ingredient_id = ANY($1) AND r.status = 'published'`)).
 		WithArgs(sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
 	// Mock SearchRecipesByIngredients call with LIMIT/OFFSET
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-        SELECT DISTINCT r.id, r.user_id, r.name, r.description, r.category_id, r.created_at, r.updated_at,
+        SELECT DISTINCT r.id, r.user_id, r.name, r.description, r.category_id, r.created_at, r.updated_at, r.status, r.difficulty, r.total_time_minutes,
                         c.id, c.name, c.description
         FROM recipe_catalogue.recipes r
         LEFT JOIN recipe_catalogue.categories c ON r.category_id = c.id
         JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
-        WHERE ri.ingredient_id = ANY($1)
+        WHERE ri.ingredient_id = ANY($1) AND r.status = 'published'
         ORDER BY r.name
         LIMIT $2 OFFSET $3`)).
 		WithArgs(sqlmock.AnyArg(), 20, 0).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at",
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
 			"c_id", "c_name", "c_description",
 		}).
-			AddRow(1, 1, "Recipe with Ingredients", "Uses ingredients", 1, now, now, 1, "Category", "Desc"))
+			AddRow(1, 1, "Recipe with Ingredients", "Uses ingredients", 1, now, now, "published", nil, nil, 1, "Category", "Desc"))
 
 	// Mock GetIngredientsForRecipes call
 	suite.mock.ExpectQuery(regexp.QuoteMeta(`
-        SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at,
+        SELECT ri.id, ri.recipe_id, ri.ingredient_id, ri.quantity, ri.unit, ri.notes, ri.created_at, ri.product_id,
                i.id, i.name, i.description, i.category, i.created_at
         FROM recipe_catalogue.recipe_ingredients ri
         JOIN recipe_catalogue.ingredients i ON ri.ingredient_id = i.id
@@ -771,10 +1006,10 @@ func (suite *RecipeRepositoryTestSuite) TestSearchRecipesByIngredientsWithIngred
         ORDER BY ri.recipe_id, ri.id`)).
 		WithArgs(sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at",
+			"ri_id", "recipe_id", "ingredient_id", "quantity", "unit", "notes", "ri_created_at", "product_id",
 			"i_id", "i_name", "i_description", "i_category", "i_created_at",
 		}).
-			AddRow(1, 1, 1, 100.0, "g", nil, now, 1, "Ingredient 1", "Desc", "Cat", now))
+			AddRow(1, 1, 1, 100.0, "g", nil, now, nil, 1, "Ingredient 1", "Desc", "Cat", now))
 
 	// Act
 	result, total, err := suite.repo.SearchRecipesByIngredientsWithIngredients(ingredientIDs, params)
@@ -788,6 +1023,85 @@ func (suite *RecipeRepositoryTestSuite) TestSearchRecipesByIngredientsWithIngred
 	assert.Equal(suite.T(), "Ingredient 1", result[0].Ingredients[0].Ingredient.Name)
 }
 
+func (suite *RecipeRepositoryTestSuite) TestGetSearchFacets_BucketsTotalTimes() {
+	ids := []int{4, 9}
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT c.id, c.name, COUNT(*)`)).
+		WithArgs(pq.Array(ids)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count"}).
+			AddRow(3, "Fish", 2).
+			AddRow(4, "Snacks", 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT r.total_time_minutes, COUNT(*)`)).
+		WithArgs(pq.Array(ids)).
+		WillReturnRows(sqlmock.NewRows([]string{"total_time_minutes", "count"}).
+			AddRow(20, 1).
+			AddRow(50, 1).
+			AddRow(240, 1))
+
+	facets, err := suite.repo.GetSearchFacets(ids, []int{15, 30, 60})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []models.CategoryFacet{{CategoryID: 3, Name: "Fish", Count: 2}, {CategoryID: 4, Name: "Snacks", Count: 1}}, facets.Categories)
+	assert.Equal(suite.T(), []models.TimeFacet{{MaxMinutes: 15, Count: 0}, {MaxMinutes: 30, Count: 1}, {MaxMinutes: 60, Count: 2}}, facets.MaxTime)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *RecipeRepositoryTestSuite) TestGetPublishedRecipes_OrdersByID() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.recipes
+		WHERE status = 'published'
+		ORDER BY id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "updated_at"}).AddRow(1, now).AddRow(4, now))
+
+	// Act
+	recipes, err := suite.repo.GetPublishedRecipes()
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []models.PublishedRecipe{{ID: 1, UpdatedAt: now}, {ID: 4, UpdatedAt: now}}, recipes)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestGetRecentlyUpdated_LimitsResults() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY d.updated_at DESC, d.id DESC
+		LIMIT $1`)).
+		WithArgs(50).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
+			"c_id", "c_name", "c_description",
+		}).AddRow(2, 1, "Salad", "Fresh salad", 2, now, now, "published", nil, nil, 2, "Healthy", "Healthy food"))
+
+	// Act
+	recipes, err := suite.repo.GetRecentlyUpdated(50)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	require.Len(suite.T(), recipes, 1)
+	assert.Equal(suite.T(), "Salad", recipes[0].Name)
+}
+
+func (suite *RecipeRepositoryTestSuite) TestGetCreatedSince_PublishedOnly() {
+	// Arrange
+	since := time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WHERE d.status = 'published' AND d.created_at > $1
+		ORDER BY d.created_at, d.id`)).
+		WithArgs(since).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "name", "description", "category_id", "created_at", "updated_at", "status", "difficulty", "total_time_minutes",
+			"c_id", "c_name", "c_description",
+		}).AddRow(3, 2, "Soup", nil, 2, since.Add(time.Minute), since.Add(time.Minute), "published", nil, 25, 2, "Healthy", nil))
+
+	// Act
+	recipes, err := suite.repo.GetCreatedSince(since)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	require.Len(suite.T(), recipes, 1)
+	assert.Equal(suite.T(), "Soup", recipes[0].Name)
+	assert.Equal(suite.T(), 25, *recipes[0].TotalTimeMinutes)
+}
+
 // =============================================================================
 // RUN TEST SUITE
 // =============================================================================
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"database/sql"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type RecipeTemplateRepository interface {
+	// GetAll returns every template ordered by name.
+	GetAll() ([]models.RecipeTemplate, error)
+	GetByID(id int) (*models.RecipeTemplate, error)
+	Create(userID int, req models.CreateRecipeTemplateRequest) (*models.RecipeTemplate, error)
+	Delete(id int) error
+}
+
+type recipeTemplateRepository struct {
+	db *database.DB
+}
+
+func NewRecipeTemplateRepository(db *database.DB) RecipeTemplateRepository {
+	return &recipeTemplateRepository{db: db}
+}
+
+const recipeTemplateSelect = `
+		SELECT t.id, t.recipe_id, d.name, t.description, d.category_id, t.created_by, t.created_at
+		FROM recipe_catalogue.recipe_templates t
+		JOIN recipe_catalogue.recipes d ON d.id = t.recipe_id`
+
+func (r *recipeTemplateRepository) GetAll() ([]models.RecipeTemplate, error) {
+	rows, err := r.db.Query(recipeTemplateSelect + `
+		ORDER BY d.name, t.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := make([]models.RecipeTemplate, 0)
+	for rows.Next() {
+		template, err := scanRecipeTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+	return templates, rows.Err()
+}
+
+func (r *recipeTemplateRepository) GetByID(id int) (*models.RecipeTemplate, error) {
+	return scanRecipeTemplate(r.db.QueryRow(recipeTemplateSelect+`
+		WHERE t.id = $1`, id))
+}
+
+func (r *recipeTemplateRepository) Create(userID int, req models.CreateRecipeTemplateRequest) (*models.RecipeTemplate, error) {
+	var id int
+	err := r.db.QueryRow(`
+		INSERT INTO recipe_catalogue.recipe_templates (recipe_id, description, created_by, created_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+		RETURNING id`, req.RecipeID, req.Description, userID).Scan(&id)
+	if database.IsUniqueViolation(err, "recipe_templates_recipe_id_key") {
+		return nil, domain.ErrTemplateExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+func (r *recipeTemplateRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM recipe_catalogue.recipe_templates WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanRecipeTemplate(scanner interface {
+	Scan(...interface{}) error
+}) (*models.RecipeTemplate, error) {
+	var template models.RecipeTemplate
+	var description sql.NullString
+	var categoryID sql.NullInt64
+	err := scanner.Scan(&template.ID, &template.RecipeID, &template.Name, &description, &categoryID,
+		&template.CreatedBy, &template.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if description.Valid {
+		template.Description = &description.String
+	}
+	if categoryID.Valid {
+		id := int(categoryID.Int64)
+		template.CategoryID = &id
+	}
+	return &template, nil
+}
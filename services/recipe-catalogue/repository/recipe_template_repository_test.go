@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var recipeTemplateColumns = []string{"id", "recipe_id", "name", "description", "category_id", "created_by", "created_at"}
+
+func TestRecipeTemplateRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewRecipeTemplateRepository(&database.DB{DB: db})
+	createdAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	description := "Swap in any vegetables"
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO recipe_catalogue.recipe_templates")).
+		WithArgs(1, &description, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE t.id = $1")).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows(recipeTemplateColumns).
+			AddRow(4, 1, "Basic stir fry", description, 2, 9, createdAt))
+
+	template, err := repo.Create(9, models.CreateRecipeTemplateRequest{RecipeID: 1, Description: &description})
+	require.NoError(t, err)
+	categoryID := 2
+	assert.Equal(t, &models.RecipeTemplate{
+		ID: 4, RecipeID: 1, Name: "Basic stir fry", Description: &description, CategoryID: &categoryID,
+		CreatedBy: 9, CreatedAt: createdAt,
+	}, template)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeTemplateRepository_CreateTwice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewRecipeTemplateRepository(&database.DB{DB: db})
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO recipe_catalogue.recipe_templates")).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "recipe_templates_recipe_id_key"})
+
+	_, err = repo.Create(9, models.CreateRecipeTemplateRequest{RecipeID: 1})
+	assert.Equal(t, domain.ErrTemplateExists, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeTemplateRepository_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewRecipeTemplateRepository(&database.DB{DB: db})
+	createdAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY d.name, t.id")).
+		WillReturnRows(sqlmock.NewRows(recipeTemplateColumns).
+			AddRow(5, 2, "Basic risotto", nil, nil, 9, createdAt).
+			AddRow(4, 1, "Basic stir fry", "Swap in any vegetables", 2, 9, createdAt))
+
+	templates, err := repo.GetAll()
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	assert.Nil(t, templates[0].Description)
+	assert.Nil(t, templates[0].CategoryID)
+	assert.Equal(t, "Swap in any vegetables", *templates[1].Description)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecipeTemplateRepository_DeleteMissing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewRecipeTemplateRepository(&database.DB{DB: db})
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.recipe_templates")).
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.Equal(t, sql.ErrNoRows, repo.Delete(4))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
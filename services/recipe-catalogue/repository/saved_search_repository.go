@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type SavedSearchRepository interface {
+	GetByUserID(userID int) ([]models.SavedSearch, error)
+	GetByID(id int) (*models.SavedSearch, error)
+	Create(userID int, req models.SavedSearchRequest) (*models.SavedSearch, error)
+	Update(id int, req models.SavedSearchRequest) (*models.SavedSearch, error)
+	Delete(id int) error
+
+	// Alerts
+	GetAlerting() ([]models.SavedSearch, error)
+	MarkChecked(id int, checkedAt time.Time) error
+}
+
+type savedSearchRepository struct {
+	db *database.DB
+}
+
+func NewSavedSearchRepository(db *database.DB) SavedSearchRepository {
+	return &savedSearchRepository{db: db}
+}
+
+const savedSearchColumns = `s.id, s.user_id, s.name, s.category_id, s.max_time_minutes, s.notify,
+		       s.last_checked_at, s.created_at, s.updated_at, si.ingredient_id`
+
+// GetByUserID returns the user's saved searches, newest first.
+func (r *savedSearchRepository) GetByUserID(userID int) ([]models.SavedSearch, error) {
+	rows, err := r.db.Query(`
+		SELECT `+savedSearchColumns+`
+		FROM recipe_catalogue.saved_searches s
+		LEFT JOIN recipe_catalogue.saved_search_ingredients si ON si.saved_search_id = s.id
+		WHERE s.user_id = $1
+		ORDER BY s.created_at DESC, s.id DESC, si.ingredient_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanSavedSearches(rows)
+}
+
+func (r *savedSearchRepository) GetByID(id int) (*models.SavedSearch, error) {
+	rows, err := r.db.Query(`
+		SELECT `+savedSearchColumns+`
+		FROM recipe_catalogue.saved_searches s
+		LEFT JOIN recipe_catalogue.saved_search_ingredients si ON si.saved_search_id = s.id
+		WHERE s.id = $1
+		ORDER BY si.ingredient_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	searches, err := scanSavedSearches(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(searches) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &searches[0], nil
+}
+
+// Create stores the search; only recipes created from now on can alert it.
+func (r *savedSearchRepository) Create(userID int, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	search := savedSearchFromRequest(req)
+	search.UserID = userID
+	err = tx.QueryRow(`
+		INSERT INTO recipe_catalogue.saved_searches
+			(user_id, name, category_id, max_time_minutes, notify, last_checked_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id, last_checked_at, created_at, updated_at`,
+		userID, req.Name, req.CategoryID, req.MaxTimeMinutes, req.Notify).
+		Scan(&search.ID, &search.LastCheckedAt, &search.CreatedAt, &search.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertSavedSearchIngredients(tx, search.ID, req.IngredientIDs); err != nil {
+		return nil, err
+	}
+
+	return &search, tx.Commit()
+}
+
+// Update replaces the search and its ingredients. Its alert window restarts,
+// as recipes created before the change were judged against the old filters.
+func (r *savedSearchRepository) Update(id int, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	search := savedSearchFromRequest(req)
+	search.ID = id
+	err = tx.QueryRow(`
+		UPDATE recipe_catalogue.saved_searches
+		SET name = $2, category_id = $3, max_time_minutes = $4, notify = $5,
+		    last_checked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING user_id, last_checked_at, created_at, updated_at`,
+		id, req.Name, req.CategoryID, req.MaxTimeMinutes, req.Notify).
+		Scan(&search.UserID, &search.LastCheckedAt, &search.CreatedAt, &search.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM recipe_catalogue.saved_search_ingredients WHERE saved_search_id = $1", id); err != nil {
+		return nil, err
+	}
+	if err := insertSavedSearchIngredients(tx, id, req.IngredientIDs); err != nil {
+		return nil, err
+	}
+
+	return &search, tx.Commit()
+}
+
+func (r *savedSearchRepository) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM recipe_catalogue.saved_searches WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetAlerting returns every search with notifications on, least recently
+// checked first.
+func (r *savedSearchRepository) GetAlerting() ([]models.SavedSearch, error) {
+	rows, err := r.db.Query(`
+		SELECT ` + savedSearchColumns + `
+		FROM recipe_catalogue.saved_searches s
+		LEFT JOIN recipe_catalogue.saved_search_ingredients si ON si.saved_search_id = s.id
+		WHERE s.notify
+		ORDER BY s.last_checked_at, s.id, si.ingredient_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanSavedSearches(rows)
+}
+
+// MarkChecked moves the search's alert window past checkedAt.
+func (r *savedSearchRepository) MarkChecked(id int, checkedAt time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE recipe_catalogue.saved_searches
+		SET last_checked_at = $2
+		WHERE id = $1`, id, checkedAt)
+	return err
+}
+
+// scanSavedSearches folds rows, grouped by search with one per ingredient,
+// into searches.
+func scanSavedSearches(rows *sql.Rows) ([]models.SavedSearch, error) {
+	searches := make([]models.SavedSearch, 0)
+	for rows.Next() {
+		var search models.SavedSearch
+		var categoryID, maxTime, ingredientID sql.NullInt64
+		err := rows.Scan(&search.ID, &search.UserID, &search.Name, &categoryID, &maxTime, &search.Notify,
+			&search.LastCheckedAt, &search.CreatedAt, &search.UpdatedAt, &ingredientID)
+		if err != nil {
+			return nil, err
+		}
+
+		if n := len(searches); n == 0 || searches[n-1].ID != search.ID {
+			search.CategoryID = nullableInt(categoryID)
+			search.MaxTimeMinutes = nullableInt(maxTime)
+			search.IngredientIDs = make([]int, 0)
+			searches = append(searches, search)
+		}
+		if ingredientID.Valid {
+			last := &searches[len(searches)-1]
+			last.IngredientIDs = append(last.IngredientIDs, int(ingredientID.Int64))
+		}
+	}
+	return searches, rows.Err()
+}
+
+func insertSavedSearchIngredients(tx *database.Tx, searchID int, ingredientIDs []int) error {
+	for _, ingredientID := range ingredientIDs {
+		_, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.saved_search_ingredients (saved_search_id, ingredient_id)
+			VALUES ($1, $2)`, searchID, ingredientID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func savedSearchFromRequest(req models.SavedSearchRequest) models.SavedSearch {
+	return models.SavedSearch{
+		Name:           req.Name,
+		IngredientIDs:  append([]int{}, req.IngredientIDs...),
+		CategoryID:     req.CategoryID,
+		MaxTimeMinutes: req.MaxTimeMinutes,
+		Notify:         req.Notify,
+	}
+}
+
+func nullableInt(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	n := int(value.Int64)
+	return &n
+}
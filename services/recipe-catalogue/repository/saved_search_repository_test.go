@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var savedSearchRowColumns = []string{"id", "user_id", "name", "category_id", "max_time_minutes", "notify",
+	"last_checked_at", "created_at", "updated_at", "ingredient_id"}
+
+type SavedSearchRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo SavedSearchRepository
+}
+
+func (suite *SavedSearchRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewSavedSearchRepository(suite.db)
+}
+
+func (suite *SavedSearchRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *SavedSearchRepositoryTestSuite) TestGetByUserID_GroupsIngredientsBySearch() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.saved_searches s`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(savedSearchRowColumns).
+			AddRow(2, 7, "Quick chicken", 2, 30, true, now, now, now, 2).
+			AddRow(2, 7, "Quick chicken", 2, 30, true, now, now, now, 5).
+			AddRow(1, 7, "Garlic", nil, nil, false, now, now, now, 6))
+
+	// Act
+	searches, err := suite.repo.GetByUserID(7)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), searches, 2)
+	assert.Equal(suite.T(), []int{2, 5}, searches[0].IngredientIDs)
+	require.NotNil(suite.T(), searches[0].CategoryID)
+	assert.Equal(suite.T(), 2, *searches[0].CategoryID)
+	assert.Equal(suite.T(), 30, *searches[0].MaxTimeMinutes)
+	assert.Nil(suite.T(), searches[1].CategoryID)
+	assert.Nil(suite.T(), searches[1].MaxTimeMinutes)
+	assert.Equal(suite.T(), []int{6}, searches[1].IngredientIDs)
+}
+
+func (suite *SavedSearchRepositoryTestSuite) TestGetByID_NotFound() {
+	// Arrange
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`FROM recipe_catalogue.saved_searches s`)).
+		WithArgs(99).
+		WillReturnRows(sqlmock.NewRows(savedSearchRowColumns))
+
+	// Act
+	search, err := suite.repo.GetByID(99)
+
+	// Assert
+	assert.Nil(suite.T(), search)
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+}
+
+func (suite *SavedSearchRepositoryTestSuite) TestCreate_InsertsIngredients() {
+	// Arrange
+	now := time.Now()
+	maxTime := 30
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.saved_searches`)).
+		WithArgs(7, "Quick chicken", nil, &maxTime, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_checked_at", "created_at", "updated_at"}).AddRow(3, now, now, now))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.saved_search_ingredients`)).
+		WithArgs(3, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.saved_search_ingredients`)).
+		WithArgs(3, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	search, err := suite.repo.Create(7, models.SavedSearchRequest{
+		Name: "Quick chicken", IngredientIDs: []int{2, 5}, MaxTimeMinutes: &maxTime, Notify: true,
+	})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, search.ID)
+	assert.Equal(suite.T(), 7, search.UserID)
+	assert.Equal(suite.T(), []int{2, 5}, search.IngredientIDs)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *SavedSearchRepositoryTestSuite) TestUpdate_ResetsAlertWindow() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`last_checked_at = CURRENT_TIMESTAMP`)).
+		WithArgs(3, "Garlic", nil, nil, false).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "last_checked_at", "created_at", "updated_at"}).AddRow(7, now, now, now))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recipe_catalogue.saved_search_ingredients WHERE saved_search_id = $1`)).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.saved_search_ingredients`)).
+		WithArgs(3, 6).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	search, err := suite.repo.Update(3, models.SavedSearchRequest{Name: "Garlic", IngredientIDs: []int{6}})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 7, search.UserID)
+	assert.Equal(suite.T(), now, search.LastCheckedAt)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *SavedSearchRepositoryTestSuite) TestDelete_NotFound() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.saved_searches WHERE id = $1")).
+		WithArgs(99).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := suite.repo.Delete(99)
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+}
+
+func (suite *SavedSearchRepositoryTestSuite) TestGetAlerting_OnlyNotifyingSearches() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`WHERE s.notify`)).
+		WillReturnRows(sqlmock.NewRows(savedSearchRowColumns).
+			AddRow(4, 8, "Onions", nil, nil, true, now, now, now, 5))
+
+	// Act
+	searches, err := suite.repo.GetAlerting()
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), searches, 1)
+	assert.Equal(suite.T(), 8, searches[0].UserID)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *SavedSearchRepositoryTestSuite) TestMarkChecked() {
+	// Arrange
+	checkedAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SET last_checked_at = $2`)).
+		WithArgs(4, checkedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := suite.repo.MarkChecked(4, checkedAt)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SavedSearchRepositoryTestSuite))
+}
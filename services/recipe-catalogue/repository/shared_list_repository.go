@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"database/sql"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type SharedListRepository interface {
+	// Create stores the list and its items. The caller picks the token and
+	// numbers the items.
+	Create(list models.SharedShoppingList) (*models.SharedShoppingList, error)
+	GetByToken(token string) (*models.SharedShoppingList, error)
+	// SetItemChecked returns sql.ErrNoRows when the list has no item at
+	// position.
+	SetItemChecked(token string, position int, checked bool) (*models.SharedShoppingListChange, error)
+	Delete(token string) error
+}
+
+type sharedListRepository struct {
+	db *database.DB
+}
+
+func NewSharedListRepository(db *database.DB) SharedListRepository {
+	return &sharedListRepository{db: db}
+}
+
+func (r *sharedListRepository) Create(list models.SharedShoppingList) (*models.SharedShoppingList, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO recipe_catalogue.shared_shopping_lists (token, user_id, version, created_at, updated_at)
+		VALUES ($1, $2, 0, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING version, created_at, updated_at`, list.Token, list.UserID).
+		Scan(&list.Version, &list.CreatedAt, &list.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range list.Items {
+		_, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.shared_shopping_list_items
+			    (token, position, ingredient_id, name, category, quantity, unit, note, checked)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, FALSE)`,
+			list.Token, item.Position, item.IngredientID, item.Name, item.Category, item.Quantity, item.Unit, item.Note)
+		if err != nil {
+			return nil, err
+		}
+		list.Items[i].Checked = false
+		list.Items[i].CheckedAt = nil
+	}
+
+	return &list, tx.Commit()
+}
+
+func (r *sharedListRepository) GetByToken(token string) (*models.SharedShoppingList, error) {
+	list := models.SharedShoppingList{Token: token}
+	err := r.db.QueryRow(`
+		SELECT user_id, version, created_at, updated_at
+		FROM recipe_catalogue.shared_shopping_lists
+		WHERE token = $1`, token).
+		Scan(&list.UserID, &list.Version, &list.CreatedAt, &list.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT position, ingredient_id, name, category, quantity, unit, note, checked, checked_at
+		FROM recipe_catalogue.shared_shopping_list_items
+		WHERE token = $1
+		ORDER BY position`, token)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list.Items = make([]models.SharedShoppingListItem, 0)
+	for rows.Next() {
+		var item models.SharedShoppingListItem
+		err := rows.Scan(&item.Position, &item.IngredientID, &item.Name, &item.Category, &item.Quantity, &item.Unit,
+			&item.Note, &item.Checked, &item.CheckedAt)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, item)
+	}
+
+	return &list, rows.Err()
+}
+
+// SetItemChecked stamps checked_at when the item is checked and clears it
+// when unchecked, and bumps the list's version in the same transaction so
+// changes are numbered in the order they were made.
+func (r *sharedListRepository) SetItemChecked(token string, position int, checked bool) (*models.SharedShoppingListChange, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var change models.SharedShoppingListChange
+	item := &change.Item
+	err = tx.QueryRow(`
+		UPDATE recipe_catalogue.shared_shopping_list_items
+		SET checked = $3,
+		    checked_at = CASE WHEN $3 THEN CURRENT_TIMESTAMP ELSE NULL END
+		WHERE token = $1 AND position = $2
+		RETURNING position, ingredient_id, name, category, quantity, unit, note, checked, checked_at`,
+		token, position, checked).
+		Scan(&item.Position, &item.IngredientID, &item.Name, &item.Category, &item.Quantity, &item.Unit,
+			&item.Note, &item.Checked, &item.CheckedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(`
+		UPDATE recipe_catalogue.shared_shopping_lists
+		SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE token = $1
+		RETURNING version`, token).
+		Scan(&change.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &change, tx.Commit()
+}
+
+func (r *sharedListRepository) Delete(token string) error {
+	result, err := r.db.Exec("DELETE FROM recipe_catalogue.shared_shopping_lists WHERE token = $1", token)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SharedListRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo SharedListRepository
+}
+
+func (suite *SharedListRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewSharedListRepository(suite.db)
+}
+
+func (suite *SharedListRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *SharedListRepositoryTestSuite) TestSetItemChecked_BumpsVersion() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`UPDATE recipe_catalogue.shared_shopping_list_items`)).
+		WithArgs("token-1", 2, true).
+		WillReturnRows(sqlmock.NewRows([]string{"position", "ingredient_id", "name", "category", "quantity", "unit", "note", "checked", "checked_at"}).
+			AddRow(2, 5, "Tomatoes", nil, 800.0, "g", nil, true, now))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SET version = version + 1`)).
+		WithArgs("token-1").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(6))
+	suite.mock.ExpectCommit()
+
+	// Act
+	change, err := suite.repo.SetItemChecked("token-1", 2, true)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 6, change.Version)
+	assert.Equal(suite.T(), "Tomatoes", change.Item.Name)
+	assert.True(suite.T(), change.Item.Checked)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *SharedListRepositoryTestSuite) TestSetItemChecked_MissingItem() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`UPDATE recipe_catalogue.shared_shopping_list_items`)).
+		WithArgs("token-1", 9, true).
+		WillReturnError(sql.ErrNoRows)
+	suite.mock.ExpectRollback()
+
+	// Act
+	_, err := suite.repo.SetItemChecked("token-1", 9, true)
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *SharedListRepositoryTestSuite) TestDelete_NotFound() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recipe_catalogue.shared_shopping_lists`)).
+		WithArgs("token-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := suite.repo.Delete("token-1")
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestSharedListRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SharedListRepositoryTestSuite))
+}
@@ -0,0 +1,358 @@
+package repository
+
+import (
+	"database/sql"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type StepRepository interface {
+	GetByRecipeID(recipeID int) ([]models.RecipeStep, error)
+	ReplaceForRecipe(recipeID int, steps []models.CreateStepRequest) ([]models.RecipeStep, error)
+	// Add appends a step to the end of the method
+	Add(recipeID int, step models.CreateStepRequest) (*models.RecipeStep, error)
+	// Update and Delete return sql.ErrNoRows when the step is not part of
+	// the recipe. Delete renumbers the steps after it.
+	Update(recipeID, stepID int, step models.CreateStepRequest) (*models.RecipeStep, error)
+	Delete(recipeID, stepID int) error
+	// Reorder numbers the recipe's steps in the order of stepIDs, which must
+	// list every one of them
+	Reorder(recipeID int, stepIDs []int) ([]models.RecipeStep, error)
+
+	// GetTechniques returns the recipe's technique tags sorted by name
+	GetTechniques(recipeID int) ([]string, error)
+	ReplaceTechniques(recipeID int, techniques []string) error
+}
+
+type stepRepository struct {
+	db *database.DB
+}
+
+func NewStepRepository(db *database.DB) StepRepository {
+	return &stepRepository{db: db}
+}
+
+func (r *stepRepository) GetByRecipeID(recipeID int) ([]models.RecipeStep, error) {
+	rows, err := r.db.Query(`
+		SELECT s.id, s.recipe_id, s.position, s.instruction, s.duration_minutes, s.created_at, si.ingredient_id
+		FROM recipe_catalogue.recipe_steps s
+		LEFT JOIN recipe_catalogue.recipe_step_ingredients si ON si.step_id = s.id
+		WHERE s.recipe_id = $1
+		ORDER BY s.position, si.ingredient_id`, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	steps := make([]models.RecipeStep, 0)
+	for rows.Next() {
+		var step models.RecipeStep
+		var duration, ingredientID sql.NullInt64
+		err := rows.Scan(&step.ID, &step.RecipeID, &step.Position, &step.Instruction, &duration, &step.CreatedAt, &ingredientID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Rows arrive grouped by step, one per linked ingredient
+		if n := len(steps); n == 0 || steps[n-1].ID != step.ID {
+			if duration.Valid {
+				minutes := int(duration.Int64)
+				step.DurationMinutes = &minutes
+			}
+			step.IngredientIDs = make([]int, 0)
+			step.Timers = make([]models.StepTimer, 0)
+			steps = append(steps, step)
+		}
+		if ingredientID.Valid {
+			last := &steps[len(steps)-1]
+			last.IngredientIDs = append(last.IngredientIDs, int(ingredientID.Int64))
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Release the connection first; SQLite runs with a single one
+	rows.Close()
+
+	if err := r.attachTimers(recipeID, steps); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// ReplaceForRecipe swaps the whole method of a recipe, numbering the steps
+// in the order given.
+func (r *stepRepository) ReplaceForRecipe(recipeID int, steps []models.CreateStepRequest) ([]models.RecipeStep, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM recipe_catalogue.recipe_steps WHERE recipe_id = $1", recipeID); err != nil {
+		return nil, err
+	}
+
+	result := make([]models.RecipeStep, 0, len(steps))
+	for i, req := range steps {
+		step := models.RecipeStep{
+			RecipeID:        recipeID,
+			Position:        i + 1,
+			Instruction:     req.Instruction,
+			DurationMinutes: req.DurationMinutes,
+			IngredientIDs:   append([]int{}, req.IngredientIDs...),
+		}
+		err := tx.QueryRow(`
+			INSERT INTO recipe_catalogue.recipe_steps (recipe_id, position, instruction, duration_minutes, created_at)
+			VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+			RETURNING id, created_at`, recipeID, step.Position, req.Instruction, req.DurationMinutes).
+			Scan(&step.ID, &step.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := insertStepDetails(tx, step.ID, req); err != nil {
+			return nil, err
+		}
+		step.Timers = append([]models.StepTimer{}, req.Timers...)
+		result = append(result, step)
+	}
+
+	return result, tx.Commit()
+}
+
+func (r *stepRepository) Add(recipeID int, req models.CreateStepRequest) (*models.RecipeStep, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	step := models.RecipeStep{
+		RecipeID:        recipeID,
+		Instruction:     req.Instruction,
+		DurationMinutes: req.DurationMinutes,
+		IngredientIDs:   append([]int{}, req.IngredientIDs...),
+		Timers:          append([]models.StepTimer{}, req.Timers...),
+	}
+	err = tx.QueryRow(`
+		INSERT INTO recipe_catalogue.recipe_steps (recipe_id, position, instruction, duration_minutes, created_at)
+		VALUES ($1, (SELECT COALESCE(MAX(position), 0) + 1 FROM recipe_catalogue.recipe_steps WHERE recipe_id = $1),
+		        $2, $3, CURRENT_TIMESTAMP)
+		RETURNING id, position, created_at`, recipeID, req.Instruction, req.DurationMinutes).
+		Scan(&step.ID, &step.Position, &step.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertStepDetails(tx, step.ID, req); err != nil {
+		return nil, err
+	}
+	return &step, tx.Commit()
+}
+
+func (r *stepRepository) Update(recipeID, stepID int, req models.CreateStepRequest) (*models.RecipeStep, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	step := models.RecipeStep{
+		ID:              stepID,
+		RecipeID:        recipeID,
+		Instruction:     req.Instruction,
+		DurationMinutes: req.DurationMinutes,
+		IngredientIDs:   append([]int{}, req.IngredientIDs...),
+		Timers:          append([]models.StepTimer{}, req.Timers...),
+	}
+	err = tx.QueryRow(`
+		UPDATE recipe_catalogue.recipe_steps
+		SET instruction = $1, duration_minutes = $2
+		WHERE id = $3 AND recipe_id = $4
+		RETURNING position, created_at`, req.Instruction, req.DurationMinutes, stepID, recipeID).
+		Scan(&step.Position, &step.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM recipe_catalogue.recipe_step_timers WHERE step_id = $1", stepID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("DELETE FROM recipe_catalogue.recipe_step_ingredients WHERE step_id = $1", stepID); err != nil {
+		return nil, err
+	}
+	if err := insertStepDetails(tx, stepID, req); err != nil {
+		return nil, err
+	}
+	return &step, tx.Commit()
+}
+
+func (r *stepRepository) Delete(recipeID, stepID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var position int
+	err = tx.QueryRow(`
+		DELETE FROM recipe_catalogue.recipe_steps
+		WHERE id = $1 AND recipe_id = $2
+		RETURNING position`, stepID, recipeID).Scan(&position)
+	if err != nil {
+		return err
+	}
+
+	// Positions are unique per recipe and the constraint is checked row by
+	// row, so the later steps are moved out of the way before moving them up
+	_, err = tx.Exec(`
+		UPDATE recipe_catalogue.recipe_steps
+		SET position = -(position - 1)
+		WHERE recipe_id = $1 AND position > $2`, recipeID, position)
+	if err != nil {
+		return err
+	}
+	if err := flipNegativePositions(tx, recipeID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *stepRepository) Reorder(recipeID int, stepIDs []int) ([]models.RecipeStep, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Number into negative positions first, for the same reason as Delete
+	for i, stepID := range stepIDs {
+		_, err := tx.Exec(`
+			UPDATE recipe_catalogue.recipe_steps
+			SET position = $1
+			WHERE id = $2 AND recipe_id = $3`, -(i + 1), stepID, recipeID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := flipNegativePositions(tx, recipeID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetByRecipeID(recipeID)
+}
+
+func (r *stepRepository) GetTechniques(recipeID int) ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT technique
+		FROM recipe_catalogue.recipe_techniques
+		WHERE recipe_id = $1
+		ORDER BY technique`, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	techniques := make([]string, 0)
+	for rows.Next() {
+		var technique string
+		if err := rows.Scan(&technique); err != nil {
+			return nil, err
+		}
+		techniques = append(techniques, technique)
+	}
+	return techniques, rows.Err()
+}
+
+func (r *stepRepository) ReplaceTechniques(recipeID int, techniques []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM recipe_catalogue.recipe_techniques WHERE recipe_id = $1", recipeID); err != nil {
+		return err
+	}
+	for _, technique := range techniques {
+		_, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.recipe_techniques (recipe_id, technique)
+			VALUES ($1, $2)`, recipeID, technique)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func flipNegativePositions(tx *database.Tx, recipeID int) error {
+	_, err := tx.Exec(`
+		UPDATE recipe_catalogue.recipe_steps
+		SET position = -position
+		WHERE recipe_id = $1 AND position < 0`, recipeID)
+	return err
+}
+
+// insertStepDetails stores the timers and ingredients of a new or updated step.
+func insertStepDetails(tx *database.Tx, stepID int, req models.CreateStepRequest) error {
+	for position, timer := range req.Timers {
+		_, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.recipe_step_timers (step_id, position, label, duration_seconds)
+			VALUES ($1, $2, $3, $4)`, stepID, position, timer.Label, timer.DurationSeconds)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, ingredientID := range req.IngredientIDs {
+		_, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.recipe_step_ingredients (step_id, ingredient_id)
+			VALUES ($1, $2)`, stepID, ingredientID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachTimers loads the timers of every step of the recipe in one query;
+// joining them into the step query would multiply its rows.
+func (r *stepRepository) attachTimers(recipeID int, steps []models.RecipeStep) error {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT t.step_id, t.label, t.duration_seconds
+		FROM recipe_catalogue.recipe_step_timers t
+		JOIN recipe_catalogue.recipe_steps s ON s.id = t.step_id
+		WHERE s.recipe_id = $1
+		ORDER BY t.step_id, t.position`, recipeID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	index := make(map[int]int, len(steps))
+	for i, step := range steps {
+		index[step.ID] = i
+	}
+
+	for rows.Next() {
+		var stepID int
+		var timer models.StepTimer
+		if err := rows.Scan(&stepID, &timer.Label, &timer.DurationSeconds); err != nil {
+			return err
+		}
+		if i, ok := index[stepID]; ok {
+			steps[i].Timers = append(steps[i].Timers, timer)
+		}
+	}
+	return rows.Err()
+}
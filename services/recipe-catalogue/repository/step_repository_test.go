@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type StepRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo StepRepository
+}
+
+func (suite *StepRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewStepRepository(suite.db)
+}
+
+func (suite *StepRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *StepRepositoryTestSuite) TestGetByRecipeID_GroupsIngredientsByStep() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT s.id, s.recipe_id, s.position, s.instruction, s.duration_minutes, s.created_at, si.ingredient_id
+		FROM recipe_catalogue.recipe_steps s
+		LEFT JOIN recipe_catalogue.recipe_step_ingredients si ON si.step_id = s.id
+		WHERE s.recipe_id = $1
+		ORDER BY s.position, si.ingredient_id`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "recipe_id", "position", "instruction", "duration_minutes", "created_at", "ingredient_id"}).
+			AddRow(10, 1, 1, "Boil the pasta", 10, now, 4).
+			AddRow(11, 1, 2, "Fry the garlic", nil, now, 6).
+			AddRow(11, 1, 2, "Fry the garlic", nil, now, 7).
+			AddRow(12, 1, 3, "Serve", nil, now, nil))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT t.step_id, t.label, t.duration_seconds
+		FROM recipe_catalogue.recipe_step_timers t
+		JOIN recipe_catalogue.recipe_steps s ON s.id = t.step_id
+		WHERE s.recipe_id = $1
+		ORDER BY t.step_id, t.position`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"step_id", "label", "duration_seconds"}).
+			AddRow(10, "Pasta", 600).
+			AddRow(10, "Sauce", 480))
+
+	// Act
+	steps, err := suite.repo.GetByRecipeID(1)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), steps, 3)
+	assert.Equal(suite.T(), 10, *steps[0].DurationMinutes)
+	assert.Equal(suite.T(), []int{6, 7}, steps[1].IngredientIDs)
+	assert.Nil(suite.T(), steps[1].DurationMinutes)
+	assert.Empty(suite.T(), steps[2].IngredientIDs)
+	assert.Equal(suite.T(), []models.StepTimer{{Label: "Pasta", DurationSeconds: 600}, {Label: "Sauce", DurationSeconds: 480}}, steps[0].Timers)
+	assert.Empty(suite.T(), steps[1].Timers)
+}
+
+func (suite *StepRepositoryTestSuite) TestReplaceForRecipe_NumbersStepsInOrder() {
+	// Arrange
+	now := time.Now()
+	minutes := 10
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.recipe_steps WHERE recipe_id = $1")).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipe_steps`)).
+		WithArgs(1, 1, "Boil the pasta", &minutes).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(20, now))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipe_step_timers`)).
+		WithArgs(20, 0, "Pasta", 600).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipe_step_ingredients`)).
+		WithArgs(20, 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipe_steps`)).
+		WithArgs(1, 2, "Serve", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(21, now))
+	suite.mock.ExpectCommit()
+
+	// Act
+	steps, err := suite.repo.ReplaceForRecipe(1, []models.CreateStepRequest{
+		{Instruction: "Boil the pasta", DurationMinutes: &minutes, IngredientIDs: []int{4},
+			Timers: []models.StepTimer{{Label: "Pasta", DurationSeconds: 600}}},
+		{Instruction: "Serve"},
+	})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), steps, 2)
+	assert.Equal(suite.T(), 2, steps[1].Position)
+	assert.Equal(suite.T(), 21, steps[1].ID)
+	assert.Len(suite.T(), steps[0].Timers, 1)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *StepRepositoryTestSuite) TestAdd_AppendsAfterLastStep() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		INSERT INTO recipe_catalogue.recipe_steps (recipe_id, position, instruction, duration_minutes, created_at)
+		VALUES ($1, (SELECT COALESCE(MAX(position), 0) + 1 FROM recipe_catalogue.recipe_steps WHERE recipe_id = $1),
+		        $2, $3, CURRENT_TIMESTAMP)
+		RETURNING id, position, created_at`)).
+		WithArgs(1, "Serve", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "position", "created_at"}).AddRow(30, 4, now))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.recipe_step_ingredients`)).
+		WithArgs(30, 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	step, err := suite.repo.Add(1, models.CreateStepRequest{Instruction: "Serve", IngredientIDs: []int{4}})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 30, step.ID)
+	assert.Equal(suite.T(), 4, step.Position)
+	assert.Equal(suite.T(), []int{4}, step.IngredientIDs)
+}
+
+func (suite *StepRepositoryTestSuite) TestUpdate_StepNotOnRecipe() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`UPDATE recipe_catalogue.recipe_steps`)).
+		WithArgs("Serve", nil, 30, 1).
+		WillReturnError(sql.ErrNoRows)
+	suite.mock.ExpectRollback()
+
+	// Act
+	_, err := suite.repo.Update(1, 30, models.CreateStepRequest{Instruction: "Serve"})
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+}
+
+func (suite *StepRepositoryTestSuite) TestDelete_MovesLaterStepsUp() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		DELETE FROM recipe_catalogue.recipe_steps
+		WHERE id = $1 AND recipe_id = $2
+		RETURNING position`)).
+		WithArgs(30, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(2))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SET position = -(position - 1)`)).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SET position = -position`)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectCommit()
+
+	// Act
+	err := suite.repo.Delete(1, 30)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *StepRepositoryTestSuite) TestReplaceTechniques() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.recipe_techniques WHERE recipe_id = $1")).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO recipe_catalogue.recipe_techniques")).
+		WithArgs(1, "braising").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO recipe_catalogue.recipe_techniques")).
+		WithArgs(1, "knife_work").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	err := suite.repo.ReplaceTechniques(1, []string{"braising", "knife_work"})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *StepRepositoryTestSuite) TestGetTechniques_EmptyIsNotNil() {
+	// Arrange
+	suite.mock.ExpectQuery(regexp.QuoteMeta("FROM recipe_catalogue.recipe_techniques")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"technique"}))
+
+	// Act
+	techniques, err := suite.repo.GetTechniques(1)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), techniques)
+	assert.Empty(suite.T(), techniques)
+}
+
+func TestStepRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(StepRepositoryTestSuite))
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"database/sql"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type StoreLayoutRepository interface {
+	GetByUserID(userID int) ([]models.StoreLayout, error)
+	GetByID(id int) (*models.StoreLayout, error)
+	Create(userID int, req models.StoreLayoutRequest) (*models.StoreLayout, error)
+	Update(id int, req models.StoreLayoutRequest) (*models.StoreLayout, error)
+	Delete(id int) error
+}
+
+type storeLayoutRepository struct {
+	db *database.DB
+}
+
+func NewStoreLayoutRepository(db *database.DB) StoreLayoutRepository {
+	return &storeLayoutRepository{db: db}
+}
+
+func (r *storeLayoutRepository) GetByUserID(userID int) ([]models.StoreLayout, error) {
+	rows, err := r.db.Query(`
+		SELECT l.id, l.user_id, l.name, l.created_at, l.updated_at, a.category
+		FROM recipe_catalogue.store_layouts l
+		LEFT JOIN recipe_catalogue.store_layout_aisles a ON a.layout_id = l.id
+		WHERE l.user_id = $1
+		ORDER BY l.name, l.id, a.position`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	layouts := make([]models.StoreLayout, 0)
+	for rows.Next() {
+		var layout models.StoreLayout
+		var category sql.NullString
+		err := rows.Scan(&layout.ID, &layout.UserID, &layout.Name, &layout.CreatedAt, &layout.UpdatedAt, &category)
+		if err != nil {
+			return nil, err
+		}
+
+		// Rows arrive grouped by layout, one per aisle
+		if n := len(layouts); n == 0 || layouts[n-1].ID != layout.ID {
+			layout.Categories = make([]string, 0)
+			layouts = append(layouts, layout)
+		}
+		if category.Valid {
+			last := &layouts[len(layouts)-1]
+			last.Categories = append(last.Categories, category.String)
+		}
+	}
+
+	return layouts, rows.Err()
+}
+
+func (r *storeLayoutRepository) GetByID(id int) (*models.StoreLayout, error) {
+	var layout models.StoreLayout
+	err := r.db.QueryRow(`
+		SELECT id, user_id, name, created_at, updated_at
+		FROM recipe_catalogue.store_layouts
+		WHERE id = $1`, id).
+		Scan(&layout.ID, &layout.UserID, &layout.Name, &layout.CreatedAt, &layout.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if layout.Categories, err = r.getAisles(id); err != nil {
+		return nil, err
+	}
+	return &layout, nil
+}
+
+func (r *storeLayoutRepository) Create(userID int, req models.StoreLayoutRequest) (*models.StoreLayout, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	layout := models.StoreLayout{UserID: userID, Name: req.Name, Categories: req.Categories}
+	err = tx.QueryRow(`
+		INSERT INTO recipe_catalogue.store_layouts (user_id, name, created_at, updated_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id, created_at, updated_at`, userID, req.Name).
+		Scan(&layout.ID, &layout.CreatedAt, &layout.UpdatedAt)
+	if err != nil {
+		if database.IsUniqueViolation(err, "store_layouts_unique_name_per_user") {
+			return nil, domain.ErrStoreLayoutExists
+		}
+		return nil, err
+	}
+
+	if err := insertAisles(tx, layout.ID, req.Categories); err != nil {
+		return nil, err
+	}
+
+	return &layout, tx.Commit()
+}
+
+// Update renames the layout and replaces its aisles.
+func (r *storeLayoutRepository) Update(id int, req models.StoreLayoutRequest) (*models.StoreLayout, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	layout := models.StoreLayout{ID: id, Name: req.Name, Categories: req.Categories}
+	err = tx.QueryRow(`
+		UPDATE recipe_catalogue.store_layouts
+		SET name = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING user_id, created_at, updated_at`, id, req.Name).
+		Scan(&layout.UserID, &layout.CreatedAt, &layout.UpdatedAt)
+	if err != nil {
+		if database.IsUniqueViolation(err, "store_layouts_unique_name_per_user") {
+			return nil, domain.ErrStoreLayoutExists
+		}
+		return nil, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM recipe_catalogue.store_layout_aisles WHERE layout_id = $1", id); err != nil {
+		return nil, err
+	}
+	if err := insertAisles(tx, id, req.Categories); err != nil {
+		return nil, err
+	}
+
+	return &layout, tx.Commit()
+}
+
+func (r *storeLayoutRepository) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM recipe_catalogue.store_layouts WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *storeLayoutRepository) getAisles(layoutID int) ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT category
+		FROM recipe_catalogue.store_layout_aisles
+		WHERE layout_id = $1
+		ORDER BY position`, layoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := make([]string, 0)
+	for rows.Next() {
+		var category string
+		if err := rows.Scan(&category); err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+func insertAisles(tx *database.Tx, layoutID int, categories []string) error {
+	for position, category := range categories {
+		_, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.store_layout_aisles (layout_id, position, category)
+			VALUES ($1, $2, $3)`, layoutID, position, category)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type StoreLayoutRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo StoreLayoutRepository
+}
+
+func (suite *StoreLayoutRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewStoreLayoutRepository(suite.db)
+}
+
+func (suite *StoreLayoutRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *StoreLayoutRepositoryTestSuite) TestGetByUserID_GroupsAislesByLayout() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT l.id, l.user_id, l.name, l.created_at, l.updated_at, a.category
+		FROM recipe_catalogue.store_layouts l
+		LEFT JOIN recipe_catalogue.store_layout_aisles a ON a.layout_id = l.id
+		WHERE l.user_id = $1
+		ORDER BY l.name, l.id, a.position`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "created_at", "updated_at", "category"}).
+			AddRow(2, 7, "Corner shop", now, now, nil).
+			AddRow(1, 7, "Supermarket", now, now, "Vegetables").
+			AddRow(1, 7, "Supermarket", now, now, "Dairy"))
+
+	// Act
+	layouts, err := suite.repo.GetByUserID(7)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), layouts, 2)
+	assert.Empty(suite.T(), layouts[0].Categories)
+	assert.Equal(suite.T(), []string{"Vegetables", "Dairy"}, layouts[1].Categories)
+}
+
+func (suite *StoreLayoutRepositoryTestSuite) TestCreate_DuplicateName() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.store_layouts`)).
+		WithArgs(7, "Supermarket").
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "store_layouts_unique_name_per_user"})
+	suite.mock.ExpectRollback()
+
+	// Act
+	layout, err := suite.repo.Create(7, models.StoreLayoutRequest{Name: "Supermarket", Categories: []string{"Dairy"}})
+
+	// Assert
+	assert.Nil(suite.T(), layout)
+	assert.Equal(suite.T(), domain.ErrStoreLayoutExists, err)
+}
+
+func (suite *StoreLayoutRepositoryTestSuite) TestCreate_InsertsAislesInOrder() {
+	// Arrange
+	now := time.Now()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.store_layouts`)).
+		WithArgs(7, "Supermarket").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(3, now, now))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.store_layout_aisles`)).
+		WithArgs(3, 0, "Vegetables").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recipe_catalogue.store_layout_aisles`)).
+		WithArgs(3, 1, "Dairy").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	// Act
+	layout, err := suite.repo.Create(7, models.StoreLayoutRequest{Name: "Supermarket", Categories: []string{"Vegetables", "Dairy"}})
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, layout.ID)
+}
+
+func (suite *StoreLayoutRepositoryTestSuite) TestDelete_NotFound() {
+	// Arrange
+	suite.mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.store_layouts WHERE id = $1")).
+		WithArgs(99).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := suite.repo.Delete(99)
+
+	// Assert
+	assert.Equal(suite.T(), sql.ErrNoRows, err)
+}
+
+func TestStoreLayoutRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(StoreLayoutRepositoryTestSuite))
+}
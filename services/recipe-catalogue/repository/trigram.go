@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"meal-prep/shared/models"
+)
+
+// SimilarityThreshold is pg_trgm's default similarity_threshold, the score
+// above which "name % query" matches. Suggestions computed in Go use the same
+// cut-off so every backend suggests alike.
+const SimilarityThreshold = 0.3
+
+// TrigramSimilarity mirrors pg_trgm's similarity(): the share of the two
+// strings' trigrams they have in common, from 0 to 1.
+func TrigramSimilarity(a, b string) float64 {
+	return setSimilarity(trigrams(a), trigrams(b))
+}
+
+func setSimilarity(setA, setB map[string]struct{}) float64 {
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0
+	}
+
+	common := 0
+	for trigram := range setA {
+		if _, ok := setB[trigram]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(setA)+len(setB)-common)
+}
+
+// trigrams splits s the way pg_trgm does: lowercase words of letters and
+// digits, each padded with two spaces in front and one behind.
+func trigrams(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			set[string(runes[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+// RankSuggestions returns up to limit distinct names similar to query,
+// closest first, for backends without pg_trgm.
+func RankSuggestions(query string, names []string, limit int) []string {
+	scores := make(map[string]float64)
+	for _, name := range names {
+		if score := TrigramSimilarity(name, query); score >= SimilarityThreshold && score > scores[name] {
+			scores[name] = score
+		}
+	}
+
+	suggestions := make([]string, 0, len(scores))
+	for name := range scores {
+		suggestions = append(suggestions, name)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if scores[suggestions[i]] != scores[suggestions[j]] {
+			return scores[suggestions[i]] > scores[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// RankDuplicates pairs up ingredients whose names have a trigram similarity
+// of at least minSimilarity, most alike first, for backends without pg_trgm.
+// The older ingredient of a pair comes first, the likely duplicate second.
+func RankDuplicates(ingredients []models.Ingredient, minSimilarity float64, limit int) []models.DuplicateIngredients {
+	sorted := append([]models.Ingredient(nil), ingredients...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	sets := make([]map[string]struct{}, len(sorted))
+	for i, ingredient := range sorted {
+		sets[i] = trigrams(ingredient.Name)
+	}
+
+	pairs := make([]models.DuplicateIngredients, 0)
+	for i := range sorted {
+		for j := i + 1; j < len(sorted); j++ {
+			if similarity := setSimilarity(sets[i], sets[j]); similarity >= minSimilarity {
+				pairs = append(pairs, models.DuplicateIngredients{
+					Ingredient: sorted[i],
+					Duplicate:  sorted[j],
+					Similarity: similarity,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Similarity > pairs[j].Similarity
+	})
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+	return pairs
+}
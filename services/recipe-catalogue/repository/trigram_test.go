@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrigramSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, TrigramSimilarity("Tomato", "tomato"))
+	assert.Equal(t, 0.0, TrigramSimilarity("Tomato", "Salt"))
+	assert.Equal(t, 0.0, TrigramSimilarity("", "Salt"))
+	// pg_trgm: SELECT similarity('Tomato', 'tomatoe') = 0.6666667
+	assert.InDelta(t, 0.6667, TrigramSimilarity("Tomato", "tomatoe"), 0.0001)
+}
+
+func TestRankSuggestions(t *testing.T) {
+	names := []string{"Garlic", "Garlic Powder", "Gherkin", "Salt", "Garlic"}
+
+	assert.Equal(t, []string{"Garlic", "Garlic Powder"}, RankSuggestions("garlik", names, 5))
+	assert.Equal(t, []string{"Garlic"}, RankSuggestions("garlik", names, 1))
+	assert.Empty(t, RankSuggestions("xyz", names, 5))
+}
+
+func TestRankDuplicates(t *testing.T) {
+	ingredients := []models.Ingredient{
+		{ID: 9, Name: "Eggs"},
+		{ID: 4, Name: "Egg"},
+		{ID: 5, Name: "Tomato"},
+		{ID: 7, Name: "tomatoe"},
+		{ID: 2, Name: "Salt"},
+	}
+
+	pairs := RankDuplicates(ingredients, 0.5, 10)
+	require.Len(t, pairs, 2)
+	assert.Equal(t, 5, pairs[0].Ingredient.ID)
+	assert.Equal(t, 7, pairs[0].Duplicate.ID)
+	assert.InDelta(t, 0.6667, pairs[0].Similarity, 0.0001)
+	assert.Equal(t, 4, pairs[1].Ingredient.ID, "the older ingredient comes first")
+	assert.Equal(t, 9, pairs[1].Duplicate.ID)
+
+	assert.Len(t, RankDuplicates(ingredients, 0.5, 1), 1)
+	assert.Len(t, RankDuplicates(ingredients, 0.7, 10), 0)
+}
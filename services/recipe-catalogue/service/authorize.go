@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+)
+
+// Resource types, which name resources in policy scopes, e.g.
+// "recipe:update".
+const (
+	recipeResource         = "recipe"
+	mealPlanResource       = "meal_plan"
+	storeLayoutResource    = "store_layout"
+	savedSearchResource    = "saved_search"
+	cookingSessionResource = "cooking_session"
+	prepSessionResource    = "prep_session"
+	sharedListResource     = "shared_list"
+	householdResource      = "household"
+)
+
+// authorizeRecipe asks the policy whether the user in ctx may act on the
+// recipe. A missing recipe is ErrRecipeNotFound and a refusal ErrForbidden.
+func authorizeRecipe(ctx context.Context, recipeRepo repository.RecipeRepository, action policy.Action, recipeID int) error {
+	if recipeID <= 0 {
+		return domain.ErrRecipeNotFound
+	}
+
+	// GetOwnerID confirms the recipe exists and returns who owns it, without
+	// loading the whole recipe
+	ownerID, err := recipeRepo.GetOwnerID(recipeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrRecipeNotFound
+		}
+		return err
+	}
+
+	return authorize(ctx, action, policy.Resource{Type: recipeResource, OwnerID: ownerID}, domain.ErrForbidden)
+}
+
+// authorize asks the policy whether the user in ctx may act on resource,
+// returning denied when not. Most callers pass their not-found error, so
+// that other users' IDs reveal nothing.
+func authorize(ctx context.Context, action policy.Action, resource policy.Resource, denied error) error {
+	if err := policy.Authorize(ctx, action, resource); err != nil {
+		return denied
+	}
+	return nil
+}
+
+// recipeAccess describes a loaded recipe to the policy. Anything but drafts
+// and private recipes is public.
+func recipeAccess(recipe *models.Recipe) policy.Resource {
+	return policy.Resource{Type: recipeResource, OwnerID: recipe.UserID, Public: isPubliclyVisible(recipe)}
+}
+
+// readableRecipe loads a recipe the user in ctx may read, treating the rest
+// as missing.
+func readableRecipe(ctx context.Context, recipeRepo repository.RecipeRepository, recipeID int) (*models.Recipe, error) {
+	if recipeID <= 0 {
+		return nil, domain.ErrRecipeNotFound
+	}
+
+	recipe, err := recipeRepo.GetByID(recipeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRecipeNotFound
+		}
+		return nil, err
+	}
+	if err := authorize(ctx, policy.Read, recipeAccess(recipe), domain.ErrRecipeNotFound); err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// asUser is the context the user context middleware builds for a signed-in
+// regular user.
+func asUser(userID int) context.Context {
+	return policy.WithSubject(context.Background(), policy.Subject{UserID: userID, Role: models.RoleUser})
+}
+
+// inHousehold is asUser for a member of householdID, as the households
+// middleware fills it in.
+func inHousehold(userID, householdID int) context.Context {
+	return policy.WithSubject(context.Background(), policy.Subject{UserID: userID, Role: models.RoleUser, Households: []int{householdID}})
+}
+
+func TestAuthorizeRecipe(t *testing.T) {
+	admin := policy.WithSubject(context.Background(), policy.Subject{UserID: 9, Role: models.RoleAdmin})
+	scoped := policy.WithSubject(context.Background(), policy.Subject{UserID: 8, Scopes: []string{"recipe:update"}})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		action   policy.Action
+		expected error
+	}{
+		{"owner may update", asUser(5), policy.Update, nil},
+		{"owner may delete", asUser(5), policy.Delete, nil},
+		{"other user may not update", asUser(6), policy.Update, domain.ErrForbidden},
+		{"anonymous may not update", context.Background(), policy.Update, domain.ErrForbidden},
+		{"admin may delete", admin, policy.Delete, nil},
+		{"admin may not update", admin, policy.Update, domain.ErrForbidden},
+		{"update scope may update", scoped, policy.Update, nil},
+		{"update scope may not delete", scoped, policy.Delete, domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipeRepo := new(mocks.MockRecipeRepository)
+			recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+
+			err := authorizeRecipe(tt.ctx, recipeRepo, tt.action, 1)
+
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestAuthorizeRecipe_NotFound(t *testing.T) {
+	recipeRepo := new(mocks.MockRecipeRepository)
+	recipeRepo.On("GetOwnerID", 404).Return(0, sql.ErrNoRows)
+
+	assert.Equal(t, domain.ErrRecipeNotFound, authorizeRecipe(asUser(5), recipeRepo, policy.Update, 404))
+	assert.Equal(t, domain.ErrRecipeNotFound, authorizeRecipe(asUser(5), recipeRepo, policy.Update, 0))
+}
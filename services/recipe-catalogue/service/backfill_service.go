@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+	"meal-prep/shared/units"
+)
+
+const (
+	// backfillBatchSize is how many recipes a cost backfill prices at once
+	backfillBatchSize = 100
+	// maxBackfillRuns bounds how many runs are remembered for GetBackfill
+	maxBackfillRuns = 20
+)
+
+type BackfillService interface {
+	// StartBackfill runs job in the background and returns the run as it
+	// starts; GetBackfill follows its progress
+	StartBackfill(ctx context.Context, job string) (*models.BackfillRun, error)
+	GetBackfill(id int) (*models.BackfillRun, error)
+	// ListBackfills returns the runs this instance remembers, newest first
+	ListBackfills() []models.BackfillRun
+
+	// RunBackfill runs job to completion, calling progress, when not nil,
+	// after every step
+	RunBackfill(ctx context.Context, job string, progress func(models.BackfillRun)) (*models.BackfillRun, error)
+}
+
+type backfillService struct {
+	backfillRepo repository.BackfillRepository
+	costService  CostService
+
+	mu     sync.Mutex
+	runs   []*models.BackfillRun // oldest first
+	nextID int
+}
+
+func NewBackfillService(backfillRepo repository.BackfillRepository, costService CostService) BackfillService {
+	return &backfillService{
+		backfillRepo: backfillRepo,
+		costService:  costService,
+	}
+}
+
+// StartBackfill keeps running after the request that started it is done.
+func (s *backfillService) StartBackfill(ctx context.Context, job string) (*models.BackfillRun, error) {
+	run, err := s.begin(job)
+	if err != nil {
+		return nil, err
+	}
+	started := s.snapshot(run)
+	go s.execute(context.WithoutCancel(ctx), run, nil)
+	return &started, nil
+}
+
+func (s *backfillService) RunBackfill(ctx context.Context, job string, progress func(models.BackfillRun)) (*models.BackfillRun, error) {
+	run, err := s.begin(job)
+	if err != nil {
+		return nil, err
+	}
+	finished, err := s.execute(ctx, run, progress)
+	return &finished, err
+}
+
+func (s *backfillService) GetBackfill(id int) (*models.BackfillRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, run := range s.runs {
+		if run.ID == id {
+			found := *run
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrBackfillNotFound
+}
+
+func (s *backfillService) ListBackfills() []models.BackfillRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := make([]models.BackfillRun, 0, len(s.runs))
+	for i := len(s.runs) - 1; i >= 0; i-- {
+		runs = append(runs, *s.runs[i])
+	}
+	return runs
+}
+
+// begin registers a run of job, refusing a second one of a job that is
+// still running, and forgets the oldest finished runs beyond the limit.
+func (s *backfillService) begin(job string) (*models.BackfillRun, error) {
+	if !slices.Contains(models.BackfillJobs, job) {
+		return nil, domain.ErrUnknownBackfillJob
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, run := range s.runs {
+		if run.Job == job && run.Status == models.BackfillRunning {
+			return nil, domain.ErrBackfillRunning
+		}
+	}
+
+	s.nextID++
+	run := &models.BackfillRun{ID: s.nextID, Job: job, Status: models.BackfillRunning, StartedAt: time.Now()}
+	s.runs = append(s.runs, run)
+
+	excess := len(s.runs) - maxBackfillRuns
+	kept := s.runs[:0]
+	for _, r := range s.runs {
+		if excess > 0 && r.Status != models.BackfillRunning {
+			excess--
+			continue
+		}
+		kept = append(kept, r)
+	}
+	s.runs = kept
+	return run, nil
+}
+
+func (s *backfillService) execute(ctx context.Context, run *models.BackfillRun, progress func(models.BackfillRun)) (models.BackfillRun, error) {
+	log := logging.WithContext(ctx).With("job", run.Job, "run_id", run.ID)
+	log.Info("Backfill started")
+
+	report := func(total, processed, updated int) {
+		current := s.update(run, func(r *models.BackfillRun) {
+			r.Total, r.Processed, r.Updated = total, processed, updated
+		})
+		if progress != nil {
+			progress(current)
+		}
+	}
+
+	var err error
+	switch run.Job {
+	case models.BackfillNormalizeUnits:
+		err = s.normalizeUnits(ctx, report)
+	case models.BackfillRecipeCosts:
+		err = s.refreshRecipeCosts(ctx, report)
+	case models.BackfillSearchIndexes:
+		err = s.rebuildSearchIndexes(ctx, report)
+	}
+
+	finished := s.update(run, func(r *models.BackfillRun) {
+		now := time.Now()
+		r.FinishedAt = &now
+		r.Status = models.BackfillCompleted
+		if err != nil {
+			r.Status = models.BackfillFailed
+			r.Error = err.Error()
+		}
+	})
+	if err != nil {
+		log.Error("Backfill failed", "processed", finished.Processed, "total", finished.Total, "error", err)
+	} else {
+		log.Info("Backfill completed", "processed", finished.Processed, "updated", finished.Updated,
+			"duration_ms", finished.FinishedAt.Sub(finished.StartedAt).Milliseconds())
+	}
+	return finished, err
+}
+
+// normalizeUnits goes through every unit spelling in use and rewrites the
+// ones that are not canonical. Updated counts the ingredients rewritten.
+func (s *backfillService) normalizeUnits(ctx context.Context, report func(total, processed, updated int)) error {
+	unitNames, err := s.backfillRepo.ListUnits()
+	if err != nil {
+		return err
+	}
+
+	updated := 0
+	report(len(unitNames), 0, 0)
+	for i, unit := range unitNames {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if normalized := units.Normalize(unit); normalized != "" && normalized != unit {
+			renamed, err := s.backfillRepo.RenameUnit(unit, normalized)
+			if err != nil {
+				return err
+			}
+			updated += renamed
+		}
+		report(len(unitNames), i+1, updated)
+	}
+	return nil
+}
+
+// refreshRecipeCosts reprices every recipe in batches, snapshotting those
+// whose cost moved since their last snapshot.
+func (s *backfillService) refreshRecipeCosts(ctx context.Context, report func(total, processed, updated int)) error {
+	recipeIDs, err := s.backfillRepo.ListRecipeIDs()
+	if err != nil {
+		return err
+	}
+
+	updated := 0
+	report(len(recipeIDs), 0, 0)
+	for start := 0; start < len(recipeIDs); start += backfillBatchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := min(start+backfillBatchSize, len(recipeIDs))
+		snapshotted, err := s.costService.RefreshRecipeCosts(recipeIDs[start:end])
+		if err != nil {
+			return err
+		}
+		updated += snapshotted
+		report(len(recipeIDs), end, updated)
+	}
+	return nil
+}
+
+func (s *backfillService) rebuildSearchIndexes(ctx context.Context, report func(total, processed, updated int)) error {
+	indexes, err := s.backfillRepo.ListSearchIndexes()
+	if err != nil {
+		return err
+	}
+
+	report(len(indexes), 0, 0)
+	for i, index := range indexes {
+		if err := s.backfillRepo.RebuildSearchIndex(ctx, index); err != nil {
+			return err
+		}
+		report(len(indexes), i+1, i+1)
+	}
+	return nil
+}
+
+// update changes run under the lock and returns a copy of the result.
+func (s *backfillService) update(run *models.BackfillRun, change func(*models.BackfillRun)) models.BackfillRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	change(run)
+	return *run
+}
+
+func (s *backfillService) snapshot(run *models.BackfillRun) models.BackfillRun {
+	return s.update(run, func(*models.BackfillRun) {})
+}
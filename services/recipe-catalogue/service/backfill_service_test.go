@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillService_RunBackfill_NormalizeUnits(t *testing.T) {
+	logging.Init("test")
+	backfillRepo := new(mocks.MockBackfillRepository)
+	service := NewBackfillService(backfillRepo, nil)
+
+	backfillRepo.On("ListUnits").Return([]string{"", "g", "grams", "Tbsp"}, nil)
+	backfillRepo.On("RenameUnit", "grams", "g").Return(3, nil)
+	backfillRepo.On("RenameUnit", "Tbsp", "tbsp").Return(1, nil)
+
+	var reports []models.BackfillRun
+	run, err := service.RunBackfill(context.Background(), models.BackfillNormalizeUnits, func(r models.BackfillRun) {
+		reports = append(reports, r)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.BackfillCompleted, run.Status)
+	assert.Equal(t, 4, run.Total)
+	assert.Equal(t, 4, run.Processed)
+	assert.Equal(t, 4, run.Updated)
+	assert.NotNil(t, run.FinishedAt)
+	require.Len(t, reports, 5)
+	assert.Equal(t, 0, reports[0].Processed)
+	assert.Equal(t, 3, reports[3].Processed)
+	assert.Equal(t, 3, reports[3].Updated)
+	backfillRepo.AssertExpectations(t)
+}
+
+func TestBackfillService_RunBackfill_RecipeCostsInBatches(t *testing.T) {
+	logging.Init("test")
+	setup := setupCostServiceTest()
+	backfillRepo := new(mocks.MockBackfillRepository)
+	service := NewBackfillService(backfillRepo, setup.service)
+
+	recipeIDs := make([]int, backfillBatchSize+1)
+	for i := range recipeIDs {
+		recipeIDs[i] = i + 1
+	}
+	backfillRepo.On("ListRecipeIDs").Return(recipeIDs, nil)
+	setup.ingredientRepo.On("GetIngredientsForRecipes", recipeIDs[:backfillBatchSize]).
+		Return(map[int][]models.RecipeIngredient{}, nil)
+	setup.ingredientRepo.On("GetIngredientsForRecipes", recipeIDs[backfillBatchSize:]).
+		Return(map[int][]models.RecipeIngredient{}, nil)
+	setup.costRepo.On("GetCurrentPrices", mock.Anything).Return(map[int]models.IngredientPrice{}, nil)
+	setup.ingredientRepo.On("GetDensities", mock.Anything).Return(map[int]float64{}, nil)
+	// Recipes without ingredients cost nothing, as their last snapshot says
+	setup.costRepo.On("GetSnapshots", mock.Anything).Return([]models.RecipeCostSnapshot{{}}, nil)
+
+	run, err := service.RunBackfill(context.Background(), models.BackfillRecipeCosts, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, backfillBatchSize+1, run.Processed)
+	assert.Equal(t, 0, run.Updated)
+	setup.ingredientRepo.AssertExpectations(t)
+	setup.costRepo.AssertNotCalled(t, "RecordSnapshots", mock.Anything)
+}
+
+func TestBackfillService_RunBackfill_SearchIndexFailure(t *testing.T) {
+	logging.Init("test")
+	backfillRepo := new(mocks.MockBackfillRepository)
+	service := NewBackfillService(backfillRepo, nil)
+
+	backfillRepo.On("ListSearchIndexes").Return([]string{"recipe_catalogue.idx_a", "recipe_catalogue.idx_b"}, nil)
+	backfillRepo.On("RebuildSearchIndex", mock.Anything, "recipe_catalogue.idx_a").Return(nil)
+	backfillRepo.On("RebuildSearchIndex", mock.Anything, "recipe_catalogue.idx_b").Return(errors.New("lock timeout"))
+
+	run, err := service.RunBackfill(context.Background(), models.BackfillSearchIndexes, nil)
+
+	require.Error(t, err)
+	assert.Equal(t, models.BackfillFailed, run.Status)
+	assert.Equal(t, "lock timeout", run.Error)
+	assert.Equal(t, 1, run.Processed)
+
+	stored, err := service.GetBackfill(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BackfillFailed, stored.Status)
+}
+
+func TestBackfillService_UnknownJob(t *testing.T) {
+	service := NewBackfillService(new(mocks.MockBackfillRepository), nil)
+
+	_, err := service.StartBackfill(context.Background(), "vacuum")
+	assert.Equal(t, domain.ErrUnknownBackfillJob, err)
+	_, err = service.RunBackfill(context.Background(), "vacuum", nil)
+	assert.Equal(t, domain.ErrUnknownBackfillJob, err)
+	assert.Empty(t, service.ListBackfills())
+}
+
+func TestBackfillService_RefusesConcurrentRunOfSameJob(t *testing.T) {
+	logging.Init("test")
+	backfillRepo := new(mocks.MockBackfillRepository)
+	service := NewBackfillService(backfillRepo, nil)
+
+	release := make(chan time.Time)
+	backfillRepo.On("ListUnits").WaitUntil(release).Return([]string{}, nil)
+	backfillRepo.On("ListSearchIndexes").Return([]string{}, nil)
+
+	started, err := service.StartBackfill(context.Background(), models.BackfillNormalizeUnits)
+	require.NoError(t, err)
+	assert.Equal(t, models.BackfillRunning, started.Status)
+
+	_, err = service.StartBackfill(context.Background(), models.BackfillNormalizeUnits)
+	assert.Equal(t, domain.ErrBackfillRunning, err)
+
+	// Other jobs still run
+	other, err := service.RunBackfill(context.Background(), models.BackfillSearchIndexes, nil)
+	require.NoError(t, err)
+	close(release)
+
+	runs := service.ListBackfills()
+	require.Len(t, runs, 2)
+	assert.Equal(t, other.ID, runs[0].ID)
+	assert.Equal(t, started.ID, runs[1].ID)
+
+	_, err = service.GetBackfill(99)
+	assert.Equal(t, domain.ErrBackfillNotFound, err)
+}
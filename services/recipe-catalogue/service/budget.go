@@ -0,0 +1,97 @@
+package service
+
+import (
+	"math"
+	"sort"
+
+	"meal-prep/shared/models"
+)
+
+// fitToBudget prices items and picks substitutions until the estimated total
+// is within budget or there are no savings left. Each item gets at most one
+// substitute, the one saving the most.
+func fitToBudget(items []models.GroceryListItem, budget float64, substitutions map[int][]models.IngredientSubstitution, prices map[int]models.IngredientPrice, densities map[int]float64) *models.BudgetedGroceryList {
+	list := &models.BudgetedGroceryList{
+		Items:       items,
+		Budget:      budget,
+		Suggestions: []models.SubstitutionSuggestion{},
+	}
+
+	var candidates []models.SubstitutionSuggestion
+	total := 0.0
+	for i := range list.Items {
+		item := &list.Items[i]
+		cost, ok := itemCost(item.TotalQuantity, item.Unit, item.IngredientID, prices, densities)
+		if !ok {
+			list.UnpricedItems++
+			continue
+		}
+		rounded := roundMoney(cost)
+		item.EstimatedCost = &rounded
+		total += cost
+
+		if suggestion, ok := bestSubstitution(*item, cost, substitutions[item.IngredientID], prices, densities); ok {
+			candidates = append(candidates, suggestion)
+		}
+	}
+	list.EstimatedTotal = roundMoney(total)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Saving != candidates[j].Saving {
+			return candidates[i].Saving > candidates[j].Saving
+		}
+		return candidates[i].Ingredient < candidates[j].Ingredient
+	})
+	for _, suggestion := range candidates {
+		if roundMoney(total) <= budget {
+			break
+		}
+		list.Suggestions = append(list.Suggestions, suggestion)
+		total -= suggestion.Saving
+	}
+
+	list.EstimatedTotalWithSuggestions = roundMoney(total)
+	list.WithinBudget = list.EstimatedTotalWithSuggestions <= budget
+	return list
+}
+
+// bestSubstitution returns the substitute that saves the most on an item
+// costing cost, if any saves at least a cent.
+func bestSubstitution(item models.GroceryListItem, cost float64, substitutes []models.IngredientSubstitution, prices map[int]models.IngredientPrice, densities map[int]float64) (models.SubstitutionSuggestion, bool) {
+	var best models.SubstitutionSuggestion
+	found := false
+	for _, substitute := range substitutes {
+		quantity := item.TotalQuantity * substitute.Ratio
+		substituteCost, ok := itemCost(quantity, item.Unit, substitute.SubstituteID, prices, densities)
+		if !ok {
+			continue
+		}
+
+		saving := roundMoney(cost - substituteCost)
+		if saving < 0.01 || (found && saving <= best.Saving) {
+			continue
+		}
+		best = models.SubstitutionSuggestion{
+			IngredientID: item.IngredientID,
+			Ingredient:   item.Ingredient.Name,
+			SubstituteID: substitute.SubstituteID,
+			Substitute:   substitute.Substitute.Name,
+			Quantity:     math.Round(quantity*100) / 100,
+			Unit:         item.Unit,
+			Saving:       saving,
+			Notes:        substitute.Notes,
+		}
+		found = true
+	}
+	return best, found
+}
+
+// itemCost prices a grocery list quantity. Items flagged for manual
+// calculation and ingredients without a price have no cost.
+func itemCost(quantity float64, unit string, ingredientID int, prices map[int]models.IngredientPrice, densities map[int]float64) (float64, bool) {
+	price, ok := prices[ingredientID]
+	if !ok || quantity < 0 {
+		return 0, false
+	}
+	return costOf(quantity, unit, price, densities[ingredientID])
+}
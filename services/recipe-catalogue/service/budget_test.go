@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// budgetFixture is a list of butter, parmesan, rice (never priced) and flour
+// (mixed units), with butter replaceable by margarine or oil and parmesan
+// by grana padano.
+func budgetFixture() ([]models.GroceryListItem, map[int][]models.IngredientSubstitution, map[int]models.IngredientPrice, map[int]float64) {
+	items := []models.GroceryListItem{
+		{IngredientID: 1, Ingredient: models.Ingredient{ID: 1, Name: "Butter"}, TotalQuantity: 250, Unit: "g"},
+		{IngredientID: 2, Ingredient: models.Ingredient{ID: 2, Name: "Parmesan"}, TotalQuantity: 100, Unit: "g"},
+		{IngredientID: 3, Ingredient: models.Ingredient{ID: 3, Name: "Rice"}, TotalQuantity: 500, Unit: "g"},
+		{IngredientID: 4, Ingredient: models.Ingredient{ID: 4, Name: "Flour"}, TotalQuantity: -1, Unit: "g"},
+	}
+	substitutions := map[int][]models.IngredientSubstitution{
+		1: {
+			{IngredientID: 1, SubstituteID: 10, Substitute: models.Ingredient{ID: 10, Name: "Margarine"}, Ratio: 1},
+			{IngredientID: 1, SubstituteID: 11, Substitute: models.Ingredient{ID: 11, Name: "Olive Oil"}, Ratio: 0.8},
+		},
+		2: {
+			{IngredientID: 2, SubstituteID: 12, Substitute: models.Ingredient{ID: 12, Name: "Grana Padano"}, Ratio: 1},
+		},
+	}
+	prices := map[int]models.IngredientPrice{
+		1:  {IngredientID: 1, Price: 2.50, Quantity: 250, Unit: "g"},
+		2:  {IngredientID: 2, Price: 20.00, Quantity: 1, Unit: "kg"},
+		4:  {IngredientID: 4, Price: 1.00, Quantity: 1, Unit: "kg"},
+		10: {IngredientID: 10, Price: 1.00, Quantity: 250, Unit: "g"},
+		11: {IngredientID: 11, Price: 6.00, Quantity: 1, Unit: "l"},
+		12: {IngredientID: 12, Price: 14.00, Quantity: 1, Unit: "kg"},
+	}
+	densities := map[int]float64{11: 0.92}
+	return items, substitutions, prices, densities
+}
+
+func TestFitToBudget_StopsOnceWithinBudget(t *testing.T) {
+	items, substitutions, prices, densities := budgetFixture()
+
+	list := fitToBudget(items, 3.50, substitutions, prices, densities)
+
+	assert.Equal(t, 4.50, list.EstimatedTotal)
+	assert.Equal(t, 2, list.UnpricedItems)
+	require.Len(t, list.Suggestions, 1)
+	assert.Equal(t, "Margarine", list.Suggestions[0].Substitute)
+	assert.Equal(t, 1.50, list.Suggestions[0].Saving)
+	assert.Equal(t, 3.00, list.EstimatedTotalWithSuggestions)
+	assert.True(t, list.WithinBudget)
+
+	require.NotNil(t, list.Items[0].EstimatedCost)
+	assert.Equal(t, 2.50, *list.Items[0].EstimatedCost)
+	assert.Nil(t, list.Items[2].EstimatedCost)
+	assert.Nil(t, list.Items[3].EstimatedCost)
+}
+
+func TestFitToBudget_ReportsWhenSavingsRunOut(t *testing.T) {
+	items, substitutions, prices, densities := budgetFixture()
+
+	list := fitToBudget(items, 2.00, substitutions, prices, densities)
+
+	require.Len(t, list.Suggestions, 2)
+	assert.Equal(t, "Grana Padano", list.Suggestions[1].Substitute)
+	assert.Equal(t, 100.0, list.Suggestions[1].Quantity)
+	assert.Equal(t, 2.40, list.EstimatedTotalWithSuggestions)
+	assert.False(t, list.WithinBudget)
+}
+
+func TestFitToBudget_NoSuggestionsWhenAlreadyWithinBudget(t *testing.T) {
+	items, substitutions, prices, densities := budgetFixture()
+
+	list := fitToBudget(items, 5.00, substitutions, prices, densities)
+
+	assert.Empty(t, list.Suggestions)
+	assert.Equal(t, 4.50, list.EstimatedTotalWithSuggestions)
+	assert.True(t, list.WithinBudget)
+}
+
+func TestBestSubstitution_ConvertsAcrossWeightAndVolume(t *testing.T) {
+	items, substitutions, prices, densities := budgetFixture()
+	delete(prices, 10) // leaves oil: 200 g is about 217 ml, 1.30
+
+	suggestion, ok := bestSubstitution(items[0], 2.50, substitutions[1], prices, densities)
+
+	require.True(t, ok)
+	assert.Equal(t, "Olive Oil", suggestion.Substitute)
+	assert.Equal(t, 200.0, suggestion.Quantity)
+	assert.Equal(t, "g", suggestion.Unit)
+	assert.Equal(t, 1.20, suggestion.Saving)
+}
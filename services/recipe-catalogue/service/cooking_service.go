@@ -0,0 +1,501 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"sort"
+	"strings"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+	"meal-prep/shared/units"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxCookingScale bounds the multiplier a cooking session may apply to
+	// ingredient amounts.
+	maxCookingScale = 20
+
+	// maxTimerSeconds caps a step timer at a day, enough for proving or
+	// marinating overnight.
+	maxTimerSeconds = 24 * 60 * 60
+)
+
+var knownTechniques = map[string]bool{
+	models.TechniqueKnifeWork:   true,
+	models.TechniqueSauteing:    true,
+	models.TechniqueBoiling:     true,
+	models.TechniqueRoasting:    true,
+	models.TechniqueGrilling:    true,
+	models.TechniqueBraising:    true,
+	models.TechniquePoaching:    true,
+	models.TechniqueSteaming:    true,
+	models.TechniqueDeepFrying:  true,
+	models.TechniqueEmulsifying: true,
+	models.TechniqueBaking:      true,
+	models.TechniqueBreadMaking: true,
+	models.TechniquePastry:      true,
+	models.TechniqueFermenting:  true,
+}
+
+type CookingService interface {
+	GetRecipeSteps(recipeID int) ([]models.RecipeStep, error)
+	SetRecipeSteps(ctx context.Context, recipeID int, steps []models.CreateStepRequest) ([]models.RecipeStep, error)
+	AddRecipeStep(ctx context.Context, recipeID int, step models.CreateStepRequest) (*models.RecipeStep, error)
+	UpdateRecipeStep(ctx context.Context, recipeID, stepID int, step models.CreateStepRequest) (*models.RecipeStep, error)
+	DeleteRecipeStep(ctx context.Context, recipeID, stepID int) error
+	ReorderRecipeSteps(ctx context.Context, recipeID int, stepIDs []int) ([]models.RecipeStep, error)
+
+	GetRecipeTechniques(recipeID int) (*models.RecipeTechniques, error)
+	SetRecipeTechniques(ctx context.Context, recipeID int, techniques []string) (*models.RecipeTechniques, error)
+
+	StartSession(ctx context.Context, userID, recipeID int, req models.StartCookingSessionRequest) (*models.CookingSessionView, error)
+	GetSession(ctx context.Context, userID int, token string) (*models.CookingSessionView, error)
+	UpdateSession(ctx context.Context, userID int, token string, req models.UpdateCookingSessionRequest) (*models.CookingSessionView, error)
+}
+
+type cookingService struct {
+	recipeRepo     repository.RecipeRepository
+	ingredientRepo repository.IngredientRepository
+	stepRepo       repository.StepRepository
+	sessionRepo    repository.CookingSessionRepository
+
+	newToken func() string
+}
+
+func NewCookingService(recipeRepo repository.RecipeRepository, ingredientRepo repository.IngredientRepository, stepRepo repository.StepRepository, sessionRepo repository.CookingSessionRepository) CookingService {
+	return &cookingService{
+		recipeRepo:     recipeRepo,
+		ingredientRepo: ingredientRepo,
+		stepRepo:       stepRepo,
+		sessionRepo:    sessionRepo,
+		newToken:       uuid.NewString,
+	}
+}
+
+func (s *cookingService) GetRecipeSteps(recipeID int) ([]models.RecipeStep, error) {
+	if err := s.checkPubliclyVisible(recipeID); err != nil {
+		return nil, err
+	}
+	return s.stepRepo.GetByRecipeID(recipeID)
+}
+
+// SetRecipeSteps replaces the method of a recipe the user owns. Steps may
+// only reference ingredients already on the recipe.
+func (s *cookingService) SetRecipeSteps(ctx context.Context, recipeID int, steps []models.CreateStepRequest) ([]models.RecipeStep, error) {
+	onRecipe, err := s.editableRecipeIngredients(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := make([]models.CreateStepRequest, 0, len(steps))
+	for _, step := range steps {
+		step, err := normalizeStep(step, onRecipe)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, step)
+	}
+
+	return s.stepRepo.ReplaceForRecipe(recipeID, normalized)
+}
+
+// AddRecipeStep appends a step to the method of a recipe the user owns.
+func (s *cookingService) AddRecipeStep(ctx context.Context, recipeID int, step models.CreateStepRequest) (*models.RecipeStep, error) {
+	onRecipe, err := s.editableRecipeIngredients(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	step, err = normalizeStep(step, onRecipe)
+	if err != nil {
+		return nil, err
+	}
+	return s.stepRepo.Add(recipeID, step)
+}
+
+// UpdateRecipeStep rewrites one step in place, keeping its position.
+func (s *cookingService) UpdateRecipeStep(ctx context.Context, recipeID, stepID int, step models.CreateStepRequest) (*models.RecipeStep, error) {
+	onRecipe, err := s.editableRecipeIngredients(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	step, err = normalizeStep(step, onRecipe)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.stepRepo.Update(recipeID, stepID, step)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrStepNotFound
+	}
+	return updated, err
+}
+
+// DeleteRecipeStep removes one step; the steps after it move up.
+func (s *cookingService) DeleteRecipeStep(ctx context.Context, recipeID, stepID int) error {
+	if _, err := s.editableRecipeIngredients(ctx, recipeID); err != nil {
+		return err
+	}
+
+	err := s.stepRepo.Delete(recipeID, stepID)
+	if err == sql.ErrNoRows {
+		return domain.ErrStepNotFound
+	}
+	return err
+}
+
+// ReorderRecipeSteps renumbers the method in the order of stepIDs, which
+// must name every step of the recipe once.
+func (s *cookingService) ReorderRecipeSteps(ctx context.Context, recipeID int, stepIDs []int) ([]models.RecipeStep, error) {
+	if _, err := s.editableRecipeIngredients(ctx, recipeID); err != nil {
+		return nil, err
+	}
+
+	steps, err := s.stepRepo.GetByRecipeID(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(stepIDs) != len(steps) {
+		return nil, domain.ErrInvalidStepOrder
+	}
+	remaining := make(map[int]bool, len(steps))
+	for _, step := range steps {
+		remaining[step.ID] = true
+	}
+	for _, id := range stepIDs {
+		if !remaining[id] {
+			return nil, domain.ErrInvalidStepOrder
+		}
+		delete(remaining, id)
+	}
+
+	return s.stepRepo.Reorder(recipeID, stepIDs)
+}
+
+func (s *cookingService) GetRecipeTechniques(recipeID int) (*models.RecipeTechniques, error) {
+	if err := s.checkPubliclyVisible(recipeID); err != nil {
+		return nil, err
+	}
+
+	techniques, err := s.stepRepo.GetTechniques(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RecipeTechniques{RecipeID: recipeID, Techniques: techniques}, nil
+}
+
+// SetRecipeTechniques replaces the technique tags of a recipe the user owns.
+func (s *cookingService) SetRecipeTechniques(ctx context.Context, recipeID int, techniques []string) (*models.RecipeTechniques, error) {
+	if err := authorizeRecipe(ctx, s.recipeRepo, policy.Update, recipeID); err != nil {
+		return nil, err
+	}
+
+	normalized, err := normalizeTechniques(techniques)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.stepRepo.ReplaceTechniques(recipeID, normalized); err != nil {
+		return nil, err
+	}
+	return &models.RecipeTechniques{RecipeID: recipeID, Techniques: normalized}, nil
+}
+
+// normalizeTechniques accepts "Knife work" for knife_work, dropping repeats
+// and rejecting techniques outside the vocabulary.
+func normalizeTechniques(techniques []string) ([]string, error) {
+	seen := make(map[string]bool, len(techniques))
+	normalized := make([]string, 0, len(techniques))
+	for _, technique := range techniques {
+		technique = strings.ToLower(strings.TrimSpace(technique))
+		technique = strings.Join(strings.Fields(strings.ReplaceAll(technique, "-", " ")), "_")
+		if !knownTechniques[technique] {
+			return nil, domain.ErrInvalidTechnique
+		}
+		if !seen[technique] {
+			seen[technique] = true
+			normalized = append(normalized, technique)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func (s *cookingService) checkPubliclyVisible(recipeID int) error {
+	if recipeID <= 0 {
+		return domain.ErrRecipeNotFound
+	}
+
+	recipe, err := s.recipeRepo.GetByID(recipeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrRecipeNotFound
+		}
+		return err
+	}
+	if !isPubliclyVisible(recipe) {
+		return domain.ErrRecipeNotFound
+	}
+	return nil
+}
+
+// editableRecipeIngredients checks the user may edit the recipe and returns
+// the IDs of its ingredients, which are all a step may reference.
+func (s *cookingService) editableRecipeIngredients(ctx context.Context, recipeID int) (map[int]bool, error) {
+	if err := authorizeRecipe(ctx, s.recipeRepo, policy.Update, recipeID); err != nil {
+		return nil, err
+	}
+
+	recipeIngredients, err := s.ingredientRepo.GetRecipeIngredients(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	onRecipe := make(map[int]bool, len(recipeIngredients))
+	for _, ri := range recipeIngredients {
+		onRecipe[ri.IngredientID] = true
+	}
+	return onRecipe, nil
+}
+
+// normalizeStep validates a step, trimming its text and dropping repeated
+// ingredients.
+func normalizeStep(step models.CreateStepRequest, onRecipe map[int]bool) (models.CreateStepRequest, error) {
+	step.Instruction = strings.TrimSpace(step.Instruction)
+	if step.Instruction == "" {
+		return step, domain.ErrStepInstructionRequired
+	}
+	if step.DurationMinutes != nil && *step.DurationMinutes <= 0 {
+		return step, domain.ErrInvalidStepDuration
+	}
+
+	seen := make(map[int]bool, len(step.IngredientIDs))
+	ingredientIDs := make([]int, 0, len(step.IngredientIDs))
+	for _, id := range step.IngredientIDs {
+		if !onRecipe[id] {
+			return step, domain.ErrStepIngredientNotInRecipe
+		}
+		if !seen[id] {
+			seen[id] = true
+			ingredientIDs = append(ingredientIDs, id)
+		}
+	}
+	step.IngredientIDs = ingredientIDs
+
+	var timers []models.StepTimer
+	for _, timer := range step.Timers {
+		timer.Label = strings.TrimSpace(timer.Label)
+		if timer.Label == "" || timer.DurationSeconds <= 0 || timer.DurationSeconds > maxTimerSeconds {
+			return step, domain.ErrInvalidStepTimer
+		}
+		timers = append(timers, timer)
+	}
+	step.Timers = timers
+
+	return step, nil
+}
+
+// StartSession opens a cooking session on a published recipe, or on any of
+// the user's own recipes, with ingredient amounts multiplied by req.Scale.
+func (s *cookingService) StartSession(ctx context.Context, userID, recipeID int, req models.StartCookingSessionRequest) (*models.CookingSessionView, error) {
+	if recipeID <= 0 {
+		return nil, domain.ErrRecipeNotFound
+	}
+
+	scale := req.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	if scale < 0 || scale > maxCookingScale {
+		return nil, domain.ErrInvalidScale
+	}
+
+	recipe, err := readableRecipe(ctx, s.recipeRepo, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.Create(models.CookingSession{
+		Token:    s.newToken(),
+		UserID:   userID,
+		RecipeID: recipeID,
+		Scale:    scale,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildView(session, recipe)
+}
+
+func (s *cookingService) GetSession(ctx context.Context, userID int, token string) (*models.CookingSessionView, error) {
+	session, err := s.ownedSession(ctx, policy.Read, token)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := s.sessionRecipe(session)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildView(session, recipe)
+}
+
+// UpdateSession moves the session to req.CurrentStep. Setting it to the
+// number of steps marks the session completed.
+func (s *cookingService) UpdateSession(ctx context.Context, userID int, token string, req models.UpdateCookingSessionRequest) (*models.CookingSessionView, error) {
+	session, err := s.ownedSession(ctx, policy.Update, token)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := s.sessionRecipe(session)
+	if err != nil {
+		return nil, err
+	}
+	view, err := s.buildView(session, recipe)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CurrentStep < 0 || req.CurrentStep > len(view.Steps) {
+		return nil, domain.ErrInvalidCookingStep
+	}
+
+	session, err = s.sessionRepo.UpdateProgress(token, req.CurrentStep, req.CurrentStep == len(view.Steps))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrCookingSessionNotFound
+		}
+		return nil, err
+	}
+
+	view.CookingSession = *session
+	return view, nil
+}
+
+// ownedSession hides other users' sessions behind ErrCookingSessionNotFound
+// so tokens cannot be probed.
+func (s *cookingService) ownedSession(ctx context.Context, action policy.Action, token string) (*models.CookingSession, error) {
+	session, err := s.sessionRepo.GetByToken(token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrCookingSessionNotFound
+		}
+		return nil, err
+	}
+	resource := policy.Resource{Type: cookingSessionResource, OwnerID: session.UserID}
+	if err := authorize(ctx, action, resource, domain.ErrCookingSessionNotFound); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *cookingService) sessionRecipe(session *models.CookingSession) (*models.Recipe, error) {
+	recipe, err := s.recipeRepo.GetByID(session.RecipeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrCookingSessionNotFound
+		}
+		return nil, err
+	}
+	return recipe, nil
+}
+
+// buildView lays the recipe out for cooking: a first step gathering every
+// ingredient at the session's scale, then the authored steps with the
+// amounts each one uses and their timers. Recipes without authored steps
+// fall back to their description as a single step.
+func (s *cookingService) buildView(session *models.CookingSession, recipe *models.Recipe) (*models.CookingSessionView, error) {
+	recipeIngredients, err := s.ingredientRepo.GetRecipeIngredients(recipe.ID)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := s.stepRepo.GetByRecipeID(recipe.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	scaled := make(map[int]models.CookingStepIngredient, len(recipeIngredients))
+	all := make([]models.CookingStepIngredient, 0, len(recipeIngredients))
+	for _, ri := range recipeIngredients {
+		quantity, unit := units.Scale(ri.Quantity, ri.Unit, session.Scale)
+		ingredient := models.CookingStepIngredient{
+			IngredientID: ri.IngredientID,
+			Name:         ri.Ingredient.Name,
+			Quantity:     quantity,
+			Unit:         unit,
+			Notes:        ri.Notes,
+		}
+		scaled[ri.IngredientID] = ingredient
+		all = append(all, ingredient)
+	}
+
+	view := &models.CookingSessionView{
+		CookingSession: *session,
+		RecipeName:     recipe.Name,
+		Steps:          make([]models.CookingStep, 0, len(steps)+1),
+	}
+	if len(all) > 0 {
+		view.Steps = append(view.Steps, models.CookingStep{
+			Instruction: "Gather the ingredients",
+			Ingredients: all,
+			Timers:      []models.StepTimer{},
+		})
+	}
+
+	for _, step := range steps {
+		// A step may still name an ingredient later removed from the recipe
+		ingredients := make([]models.CookingStepIngredient, 0, len(step.IngredientIDs))
+		for _, id := range step.IngredientIDs {
+			if ingredient, ok := scaled[id]; ok {
+				ingredients = append(ingredients, ingredient)
+			}
+		}
+		view.Steps = append(view.Steps, models.CookingStep{
+			Instruction:     step.Instruction,
+			DurationMinutes: step.DurationMinutes,
+			Ingredients:     ingredients,
+			Timers:          append([]models.StepTimer{}, step.Timers...),
+		})
+		view.TotalMinutes += stepMinutes(step)
+	}
+
+	if len(steps) == 0 && recipe.Description != nil && strings.TrimSpace(*recipe.Description) != "" {
+		view.Steps = append(view.Steps, models.CookingStep{
+			Instruction: strings.TrimSpace(*recipe.Description),
+			Ingredients: []models.CookingStepIngredient{},
+			Timers:      []models.StepTimer{},
+		})
+	}
+
+	for i := range view.Steps {
+		view.Steps[i].Number = i + 1
+	}
+	return view, nil
+}
+
+// stepMinutes is how long a step takes: its stated duration, or else its
+// longest timer rounded up to whole minutes, since timers run side by side.
+func stepMinutes(step models.RecipeStep) int {
+	if step.DurationMinutes != nil {
+		return *step.DurationMinutes
+	}
+
+	longest := 0
+	for _, timer := range step.Timers {
+		longest = max(longest, timer.DurationSeconds)
+	}
+	return (longest + 59) / 60
+}
+
+// scaleQuantity multiplies a recipe amount, keeping two decimals like the
+// recipe_ingredients column does.
+func scaleQuantity(quantity, scale float64) float64 {
+	return math.Round(quantity*scale*100) / 100
+}
@@ -0,0 +1,424 @@
+package service
+
+import (
+	"database/sql"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type cookingServiceTestSetup struct {
+	service        *cookingService
+	recipeRepo     *mocks.MockRecipeRepository
+	ingredientRepo *mocks.MockIngredientRepository
+	stepRepo       *mocks.MockStepRepository
+	sessionRepo    *mocks.MockCookingSessionRepository
+}
+
+func setupCookingServiceTest() *cookingServiceTestSetup {
+	recipeRepo := new(mocks.MockRecipeRepository)
+	ingredientRepo := new(mocks.MockIngredientRepository)
+	stepRepo := new(mocks.MockStepRepository)
+	sessionRepo := new(mocks.MockCookingSessionRepository)
+
+	service := NewCookingService(recipeRepo, ingredientRepo, stepRepo, sessionRepo).(*cookingService)
+	service.newToken = func() string { return "token-1" }
+
+	return &cookingServiceTestSetup{
+		service:        service,
+		recipeRepo:     recipeRepo,
+		ingredientRepo: ingredientRepo,
+		stepRepo:       stepRepo,
+		sessionRepo:    sessionRepo,
+	}
+}
+
+func cookingRecipe(status string, ownerID int) *models.Recipe {
+	description := "Boil the pasta and toss with the sauce."
+	return &models.Recipe{ID: 1, UserID: ownerID, Name: "Pasta", Description: &description, Status: status}
+}
+
+func cookingIngredients() []models.RecipeIngredient {
+	return []models.RecipeIngredient{
+		{IngredientID: 4, Ingredient: models.Ingredient{ID: 4, Name: "Pasta"}, Quantity: 200, Unit: "grams"},
+		{IngredientID: 7, Ingredient: models.Ingredient{ID: 7, Name: "Garlic"}, Quantity: 1.5, Unit: "cloves"},
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// =============================================================================
+// RECIPE STEP TESTS
+// =============================================================================
+
+func TestCookingService_SetRecipeSteps_Success(t *testing.T) {
+	setup := setupCookingServiceTest()
+	steps := []models.CreateStepRequest{
+		{Instruction: "  Boil the pasta  ", DurationMinutes: intPtr(10), IngredientIDs: []int{4, 4}},
+		{Instruction: "Fry the garlic", IngredientIDs: []int{7}},
+	}
+	expectedSteps := []models.CreateStepRequest{
+		{Instruction: "Boil the pasta", DurationMinutes: intPtr(10), IngredientIDs: []int{4}},
+		{Instruction: "Fry the garlic", IngredientIDs: []int{7}},
+	}
+	stored := []models.RecipeStep{{ID: 1, RecipeID: 1, Position: 1}, {ID: 2, RecipeID: 1, Position: 2}}
+
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+	setup.stepRepo.On("ReplaceForRecipe", 1, expectedSteps).Return(stored, nil)
+
+	result, err := setup.service.SetRecipeSteps(asUser(5), 1, steps)
+
+	assert.NoError(t, err)
+	assert.Equal(t, stored, result)
+	setup.stepRepo.AssertExpectations(t)
+}
+
+func TestCookingService_SetRecipeSteps_Forbidden(t *testing.T) {
+	setup := setupCookingServiceTest()
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+
+	_, err := setup.service.SetRecipeSteps(asUser(6), 1, []models.CreateStepRequest{{Instruction: "Boil"}})
+
+	assert.Equal(t, domain.ErrForbidden, err)
+	setup.stepRepo.AssertNotCalled(t, "ReplaceForRecipe", mock.Anything, mock.Anything)
+}
+
+func TestCookingService_SetRecipeSteps_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		step     models.CreateStepRequest
+		expected error
+	}{
+		{"blank instruction", models.CreateStepRequest{Instruction: "   "}, domain.ErrStepInstructionRequired},
+		{"zero duration", models.CreateStepRequest{Instruction: "Boil", DurationMinutes: intPtr(0)}, domain.ErrInvalidStepDuration},
+		{"foreign ingredient", models.CreateStepRequest{Instruction: "Boil", IngredientIDs: []int{99}}, domain.ErrStepIngredientNotInRecipe},
+		{"unlabelled timer", models.CreateStepRequest{Instruction: "Boil", Timers: []models.StepTimer{{Label: " ", DurationSeconds: 60}}}, domain.ErrInvalidStepTimer},
+		{"timer over a day", models.CreateStepRequest{Instruction: "Prove", Timers: []models.StepTimer{{Label: "Dough", DurationSeconds: 24*60*60 + 1}}}, domain.ErrInvalidStepTimer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupCookingServiceTest()
+			setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+			setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+
+			_, err := setup.service.SetRecipeSteps(asUser(5), 1, []models.CreateStepRequest{tt.step})
+
+			assert.Equal(t, tt.expected, err)
+			setup.stepRepo.AssertNotCalled(t, "ReplaceForRecipe", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCookingService_AddRecipeStep_Success(t *testing.T) {
+	setup := setupCookingServiceTest()
+	stored := &models.RecipeStep{ID: 3, RecipeID: 1, Position: 3, Instruction: "Serve"}
+
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+	setup.stepRepo.On("Add", 1, models.CreateStepRequest{Instruction: "Serve", IngredientIDs: []int{}}).Return(stored, nil)
+
+	result, err := setup.service.AddRecipeStep(asUser(5), 1, models.CreateStepRequest{Instruction: " Serve "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, stored, result)
+}
+
+func TestCookingService_AddRecipeStep_Validation(t *testing.T) {
+	setup := setupCookingServiceTest()
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+
+	_, err := setup.service.AddRecipeStep(asUser(5), 1, models.CreateStepRequest{Instruction: ""})
+
+	assert.Equal(t, domain.ErrStepInstructionRequired, err)
+	setup.stepRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+}
+
+func TestCookingService_UpdateRecipeStep_NotOnRecipe(t *testing.T) {
+	setup := setupCookingServiceTest()
+	req := models.CreateStepRequest{Instruction: "Boil", IngredientIDs: []int{4}}
+
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+	setup.stepRepo.On("Update", 1, 9, req).Return(nil, sql.ErrNoRows)
+
+	_, err := setup.service.UpdateRecipeStep(asUser(5), 1, 9, req)
+
+	assert.Equal(t, domain.ErrStepNotFound, err)
+}
+
+func TestCookingService_DeleteRecipeStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		ownerID  int
+		repoErr  error
+		expected error
+	}{
+		{"deleted", 5, nil, nil},
+		{"not on recipe", 5, sql.ErrNoRows, domain.ErrStepNotFound},
+		{"someone else's recipe", 6, nil, domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupCookingServiceTest()
+			setup.recipeRepo.On("GetOwnerID", 1).Return(tt.ownerID, nil)
+			setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+			setup.stepRepo.On("Delete", 1, 2).Return(tt.repoErr)
+
+			err := setup.service.DeleteRecipeStep(asUser(5), 1, 2)
+
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestCookingService_ReorderRecipeSteps(t *testing.T) {
+	current := []models.RecipeStep{{ID: 1, Position: 1}, {ID: 2, Position: 2}, {ID: 3, Position: 3}}
+	tests := []struct {
+		name     string
+		stepIDs  []int
+		expected error
+	}{
+		{"every step once", []int{3, 1, 2}, nil},
+		{"missing a step", []int{3, 1}, domain.ErrInvalidStepOrder},
+		{"repeated step", []int{3, 3, 1}, domain.ErrInvalidStepOrder},
+		{"foreign step", []int{3, 1, 9}, domain.ErrInvalidStepOrder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupCookingServiceTest()
+			setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+			setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+			setup.stepRepo.On("GetByRecipeID", 1).Return(current, nil)
+			setup.stepRepo.On("Reorder", 1, tt.stepIDs).Return(current, nil)
+
+			_, err := setup.service.ReorderRecipeSteps(asUser(5), 1, tt.stepIDs)
+
+			assert.Equal(t, tt.expected, err)
+			if tt.expected != nil {
+				setup.stepRepo.AssertNotCalled(t, "Reorder", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCookingService_GetRecipeSteps_HidesDrafts(t *testing.T) {
+	setup := setupCookingServiceTest()
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusDraft, 5), nil)
+
+	_, err := setup.service.GetRecipeSteps(1)
+
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+}
+
+// =============================================================================
+// TECHNIQUE TESTS
+// =============================================================================
+
+func TestCookingService_SetRecipeTechniques_Normalizes(t *testing.T) {
+	setup := setupCookingServiceTest()
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+	setup.stepRepo.On("ReplaceTechniques", 1, []string{"braising", "knife_work"}).Return(nil)
+
+	result, err := setup.service.SetRecipeTechniques(asUser(5), 1, []string{" Knife work ", "braising", "knife-work"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.RecipeTechniques{RecipeID: 1, Techniques: []string{"braising", "knife_work"}}, result)
+	setup.stepRepo.AssertExpectations(t)
+}
+
+func TestCookingService_SetRecipeTechniques_Rejects(t *testing.T) {
+	tests := []struct {
+		name       string
+		ownerID    int
+		techniques []string
+		expected   error
+	}{
+		{"unknown technique", 5, []string{"juggling"}, domain.ErrInvalidTechnique},
+		{"someone else's recipe", 6, []string{"baking"}, domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupCookingServiceTest()
+			setup.recipeRepo.On("GetOwnerID", 1).Return(tt.ownerID, nil)
+
+			_, err := setup.service.SetRecipeTechniques(asUser(5), 1, tt.techniques)
+
+			assert.Equal(t, tt.expected, err)
+			setup.stepRepo.AssertNotCalled(t, "ReplaceTechniques", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCookingService_GetRecipeTechniques(t *testing.T) {
+	setup := setupCookingServiceTest()
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPublished, 5), nil)
+	setup.stepRepo.On("GetTechniques", 1).Return([]string{"boiling"}, nil)
+
+	result, err := setup.service.GetRecipeTechniques(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"boiling"}, result.Techniques)
+
+	drafts := setupCookingServiceTest()
+	drafts.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusDraft, 5), nil)
+
+	_, err = drafts.service.GetRecipeTechniques(1)
+
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+}
+
+// =============================================================================
+// COOKING SESSION TESTS
+// =============================================================================
+
+func TestCookingService_StartSession_ScalesStepIngredients(t *testing.T) {
+	setup := setupCookingServiceTest()
+	steps := []models.RecipeStep{
+		{ID: 1, Position: 1, Instruction: "Boil the pasta", DurationMinutes: intPtr(10), IngredientIDs: []int{4}},
+		{ID: 2, Position: 2, Instruction: "Fry the garlic", IngredientIDs: []int{7, 12},
+			Timers: []models.StepTimer{{Label: "Garlic", DurationSeconds: 90}, {Label: "Pan", DurationSeconds: 30}}},
+	}
+
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPublished, 5), nil)
+	setup.sessionRepo.On("Create", models.CookingSession{Token: "token-1", UserID: 3, RecipeID: 1, Scale: 1.5}).
+		Return(&models.CookingSession{Token: "token-1", UserID: 3, RecipeID: 1, Scale: 1.5}, nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+	setup.stepRepo.On("GetByRecipeID", 1).Return(steps, nil)
+
+	view, err := setup.service.StartSession(asUser(3), 3, 1, models.StartCookingSessionRequest{Scale: 1.5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", view.Token)
+	assert.Equal(t, "Pasta", view.RecipeName)
+	assert.Equal(t, 12, view.TotalMinutes)
+	assert.Len(t, view.Steps, 3)
+
+	// Gathering step lists everything at the session's scale
+	assert.Equal(t, 1, view.Steps[0].Number)
+	assert.Len(t, view.Steps[0].Ingredients, 2)
+	assert.Equal(t, 300.0, view.Steps[0].Ingredients[0].Quantity)
+	assert.Equal(t, 2.25, view.Steps[0].Ingredients[1].Quantity)
+
+	// Ingredient 12 is no longer on the recipe and is dropped
+	assert.Equal(t, 3, view.Steps[2].Number)
+	assert.Len(t, view.Steps[2].Ingredients, 1)
+	assert.Equal(t, "Garlic", view.Steps[2].Ingredients[0].Name)
+	assert.Equal(t, []models.StepTimer{{Label: "Garlic", DurationSeconds: 90}, {Label: "Pan", DurationSeconds: 30}}, view.Steps[2].Timers)
+	assert.Empty(t, view.Steps[0].Timers)
+}
+
+func TestCookingService_StartSession_FitsScaledUnits(t *testing.T) {
+	setup := setupCookingServiceTest()
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPublished, 5), nil)
+	setup.sessionRepo.On("Create", mock.AnythingOfType("models.CookingSession")).
+		Return(&models.CookingSession{Token: "token-1", UserID: 3, RecipeID: 1, Scale: 6}, nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+	setup.stepRepo.On("GetByRecipeID", 1).Return([]models.RecipeStep{}, nil)
+
+	view, err := setup.service.StartSession(asUser(3), 3, 1, models.StartCookingSessionRequest{Scale: 6})
+
+	assert.NoError(t, err)
+	pasta, garlic := view.Steps[0].Ingredients[0], view.Steps[0].Ingredients[1]
+	assert.Equal(t, 1.2, pasta.Quantity)
+	assert.Equal(t, "kg", pasta.Unit)
+	assert.Equal(t, 9.0, garlic.Quantity)
+	assert.Equal(t, "cloves", garlic.Unit)
+}
+
+func TestCookingService_StartSession_FallsBackToDescription(t *testing.T) {
+	setup := setupCookingServiceTest()
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPrivate, 3), nil)
+	setup.sessionRepo.On("Create", mock.AnythingOfType("models.CookingSession")).
+		Return(&models.CookingSession{Token: "token-1", UserID: 3, RecipeID: 1, Scale: 1}, nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+	setup.stepRepo.On("GetByRecipeID", 1).Return([]models.RecipeStep{}, nil)
+
+	view, err := setup.service.StartSession(asUser(3), 3, 1, models.StartCookingSessionRequest{})
+
+	assert.NoError(t, err)
+	assert.Len(t, view.Steps, 2)
+	assert.Equal(t, "Boil the pasta and toss with the sauce.", view.Steps[1].Instruction)
+	assert.Equal(t, 200.0, view.Steps[0].Ingredients[0].Quantity)
+}
+
+func TestCookingService_StartSession_OthersPrivateRecipe(t *testing.T) {
+	setup := setupCookingServiceTest()
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPrivate, 5), nil)
+
+	_, err := setup.service.StartSession(asUser(3), 3, 1, models.StartCookingSessionRequest{})
+
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+	setup.sessionRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCookingService_StartSession_InvalidScale(t *testing.T) {
+	setup := setupCookingServiceTest()
+
+	_, err := setup.service.StartSession(asUser(3), 3, 1, models.StartCookingSessionRequest{Scale: 21})
+
+	assert.Equal(t, domain.ErrInvalidScale, err)
+	setup.recipeRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+}
+
+func TestCookingService_GetSession_OtherUser(t *testing.T) {
+	setup := setupCookingServiceTest()
+	setup.sessionRepo.On("GetByToken", "token-1").Return(&models.CookingSession{Token: "token-1", UserID: 3, RecipeID: 1}, nil)
+
+	_, err := setup.service.GetSession(asUser(4), 4, "token-1")
+
+	assert.Equal(t, domain.ErrCookingSessionNotFound, err)
+}
+
+func TestCookingService_GetSession_NotFound(t *testing.T) {
+	setup := setupCookingServiceTest()
+	setup.sessionRepo.On("GetByToken", "missing").Return(nil, sql.ErrNoRows)
+
+	_, err := setup.service.GetSession(asUser(3), 3, "missing")
+
+	assert.Equal(t, domain.ErrCookingSessionNotFound, err)
+}
+
+func TestCookingService_UpdateSession_CompletesAfterLastStep(t *testing.T) {
+	setup := setupCookingServiceTest()
+	session := &models.CookingSession{Token: "token-1", UserID: 3, RecipeID: 1, Scale: 1}
+
+	setup.sessionRepo.On("GetByToken", "token-1").Return(session, nil)
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPublished, 5), nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+	setup.stepRepo.On("GetByRecipeID", 1).Return([]models.RecipeStep{}, nil)
+	setup.sessionRepo.On("UpdateProgress", "token-1", 2, true).
+		Return(&models.CookingSession{Token: "token-1", UserID: 3, RecipeID: 1, Scale: 1, CurrentStep: 2}, nil)
+
+	view, err := setup.service.UpdateSession(asUser(3), 3, "token-1", models.UpdateCookingSessionRequest{CurrentStep: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, view.CurrentStep)
+	setup.sessionRepo.AssertExpectations(t)
+}
+
+func TestCookingService_UpdateSession_StepOutOfRange(t *testing.T) {
+	setup := setupCookingServiceTest()
+	session := &models.CookingSession{Token: "token-1", UserID: 3, RecipeID: 1, Scale: 1}
+
+	setup.sessionRepo.On("GetByToken", "token-1").Return(session, nil)
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPublished, 5), nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+	setup.stepRepo.On("GetByRecipeID", 1).Return([]models.RecipeStep{}, nil)
+
+	_, err := setup.service.UpdateSession(asUser(3), 3, "token-1", models.UpdateCookingSessionRequest{CurrentStep: 3})
+
+	assert.Equal(t, domain.ErrInvalidCookingStep, err)
+	setup.sessionRepo.AssertNotCalled(t, "UpdateProgress", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+	"strings"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/events"
+	"meal-prep/shared/models"
+	"meal-prep/shared/units"
+)
+
+type CostService interface {
+	SetIngredientPrice(userID, ingredientID int, req models.SetIngredientPriceRequest) (*models.IngredientPrice, error)
+	GetIngredientPrices(ingredientID int) ([]models.IngredientPrice, error)
+	GetRecipeCostHistory(recipeID int) (*models.RecipeCostHistory, error)
+	SnapshotRecipeCosts(event events.Event) error
+
+	// RefreshRecipeCosts snapshots each recipe whose cost differs from its
+	// latest snapshot, or that has none, and returns how many it snapshotted
+	RefreshRecipeCosts(recipeIDs []int) (int, error)
+}
+
+type costService struct {
+	costRepo       repository.CostRepository
+	recipeRepo     repository.RecipeRepository
+	ingredientRepo repository.IngredientRepository
+	events         events.Publisher
+}
+
+func NewCostService(costRepo repository.CostRepository, recipeRepo repository.RecipeRepository, ingredientRepo repository.IngredientRepository, publisher events.Publisher) CostService {
+	return &costService{
+		costRepo:       costRepo,
+		recipeRepo:     recipeRepo,
+		ingredientRepo: ingredientRepo,
+		events:         publisher,
+	}
+}
+
+// SetIngredientPrice records a new current price and announces it, so the
+// cost of every recipe using the ingredient is snapshotted.
+func (s *costService) SetIngredientPrice(userID, ingredientID int, req models.SetIngredientPriceRequest) (*models.IngredientPrice, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+	if req.Price < 0 {
+		return nil, domain.ErrInvalidPrice
+	}
+	if req.Quantity <= 0 {
+		return nil, domain.ErrInvalidQuantity
+	}
+	req.Unit = strings.TrimSpace(req.Unit)
+	if req.Unit == "" {
+		return nil, domain.ErrInvalidUnit
+	}
+
+	if err := s.requireIngredient(ingredientID); err != nil {
+		return nil, err
+	}
+
+	price, err := s.costRepo.AddPrice(ingredientID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(events.Event{
+		Type:      events.IngredientPriceUpdated,
+		UserID:    userID,
+		SubjectID: ingredientID,
+	})
+	return price, nil
+}
+
+func (s *costService) GetIngredientPrices(ingredientID int) ([]models.IngredientPrice, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+	if err := s.requireIngredient(ingredientID); err != nil {
+		return nil, err
+	}
+	return s.costRepo.GetPriceHistory(ingredientID)
+}
+
+func (s *costService) GetRecipeCostHistory(recipeID int) (*models.RecipeCostHistory, error) {
+	if recipeID <= 0 {
+		return nil, domain.ErrRecipeNotFound
+	}
+	recipe, err := s.recipeRepo.GetByID(recipeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRecipeNotFound
+		}
+		return nil, err
+	}
+	if !isPubliclyVisible(recipe) {
+		return nil, domain.ErrRecipeNotFound
+	}
+
+	snapshots, err := s.costRepo.GetSnapshots(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RecipeCostHistory{
+		RecipeID:      recipeID,
+		Snapshots:     snapshots,
+		ChangePercent: costChangePercent(snapshots),
+	}, nil
+}
+
+// SnapshotRecipeCosts reprices every recipe using the ingredient whose price
+// changed. It is subscribed to IngredientPriceUpdated.
+func (s *costService) SnapshotRecipeCosts(event events.Event) error {
+	if event.Type != events.IngredientPriceUpdated {
+		return nil
+	}
+
+	recipeIDs, err := s.costRepo.GetRecipeIDsUsingIngredient(event.SubjectID)
+	if err != nil || len(recipeIDs) == 0 {
+		return err
+	}
+
+	snapshots, err := s.currentCosts(recipeIDs)
+	if err != nil {
+		return err
+	}
+	return s.costRepo.RecordSnapshots(snapshots)
+}
+
+func (s *costService) RefreshRecipeCosts(recipeIDs []int) (int, error) {
+	if len(recipeIDs) == 0 {
+		return 0, nil
+	}
+	current, err := s.currentCosts(recipeIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	var changed []models.RecipeCostSnapshot
+	for _, snapshot := range current {
+		history, err := s.costRepo.GetSnapshots(snapshot.RecipeID)
+		if err != nil {
+			return 0, err
+		}
+		if len(history) == 0 || !sameCost(history[len(history)-1], snapshot) {
+			changed = append(changed, snapshot)
+		}
+	}
+	if len(changed) == 0 {
+		return 0, nil
+	}
+	if err := s.costRepo.RecordSnapshots(changed); err != nil {
+		return 0, err
+	}
+	return len(changed), nil
+}
+
+// currentCosts prices each recipe at the current ingredient prices.
+func (s *costService) currentCosts(recipeIDs []int) ([]models.RecipeCostSnapshot, error) {
+	ingredientsByRecipe, err := s.ingredientRepo.GetIngredientsForRecipes(recipeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var ingredientIDs []int
+	seen := make(map[int]bool)
+	for _, ingredients := range ingredientsByRecipe {
+		for _, ingredient := range ingredients {
+			if !seen[ingredient.IngredientID] {
+				seen[ingredient.IngredientID] = true
+				ingredientIDs = append(ingredientIDs, ingredient.IngredientID)
+			}
+		}
+	}
+	sort.Ints(ingredientIDs)
+
+	prices, err := s.costRepo.GetCurrentPrices(ingredientIDs)
+	if err != nil {
+		return nil, err
+	}
+	densities, err := s.ingredientRepo.GetDensities(ingredientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]models.RecipeCostSnapshot, 0, len(recipeIDs))
+	for _, recipeID := range recipeIDs {
+		snapshot := recipeCost(ingredientsByRecipe[recipeID], prices, densities)
+		snapshot.RecipeID = recipeID
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+func sameCost(a, b models.RecipeCostSnapshot) bool {
+	return a.Cost == b.Cost && a.PricedIngredients == b.PricedIngredients && a.TotalIngredients == b.TotalIngredients
+}
+
+func (s *costService) requireIngredient(ingredientID int) error {
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrIngredientNotFound
+	}
+	return nil
+}
+
+// recipeCost adds up the ingredients that have a price in a unit their
+// quantity converts to. The others are only counted in TotalIngredients.
+func recipeCost(ingredients []models.RecipeIngredient, prices map[int]models.IngredientPrice, densities map[int]float64) models.RecipeCostSnapshot {
+	snapshot := models.RecipeCostSnapshot{TotalIngredients: len(ingredients)}
+	for _, ingredient := range ingredients {
+		price, ok := prices[ingredient.IngredientID]
+		if !ok {
+			continue
+		}
+		cost, ok := costOf(ingredient.Quantity, ingredient.Unit, price, densities[ingredient.IngredientID])
+		if !ok {
+			continue
+		}
+		snapshot.Cost += cost
+		snapshot.PricedIngredients++
+	}
+	snapshot.Cost = roundMoney(snapshot.Cost)
+	return snapshot
+}
+
+// costOf prices quantity of unit at price, converting to the price's unit.
+// It reports false when the units cannot be converted.
+func costOf(quantity float64, unit string, price models.IngredientPrice, gramsPerML float64) (float64, bool) {
+	if units.Normalize(unit) != units.Normalize(price.Unit) {
+		converted, err := units.Convert(quantity, unit, price.Unit, gramsPerML)
+		if err != nil {
+			return 0, false
+		}
+		quantity = converted
+	}
+	return price.Price * quantity / price.Quantity, true
+}
+
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// costChangePercent compares the latest complete snapshot with the first.
+// Incomplete snapshots would show ingredients gaining a price as inflation.
+func costChangePercent(snapshots []models.RecipeCostSnapshot) *float64 {
+	var first, last *models.RecipeCostSnapshot
+	for i := range snapshots {
+		if !snapshots[i].Complete() {
+			continue
+		}
+		if first == nil {
+			first = &snapshots[i]
+		}
+		last = &snapshots[i]
+	}
+	if first == nil || first == last || first.Cost == 0 {
+		return nil
+	}
+
+	change := math.Round((last.Cost-first.Cost)/first.Cost*10000) / 100
+	return &change
+}
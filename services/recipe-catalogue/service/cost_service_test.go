@@ -0,0 +1,208 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/events"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type costServiceTestSetup struct {
+	service        CostService
+	costRepo       *mocks.MockCostRepository
+	recipeRepo     *mocks.MockRecipeRepository
+	ingredientRepo *mocks.MockIngredientRepository
+	bus            *events.Bus
+}
+
+func setupCostServiceTest() *costServiceTestSetup {
+	costRepo := new(mocks.MockCostRepository)
+	recipeRepo := new(mocks.MockRecipeRepository)
+	ingredientRepo := new(mocks.MockIngredientRepository)
+	bus := events.NewBus()
+
+	return &costServiceTestSetup{
+		service:        NewCostService(costRepo, recipeRepo, ingredientRepo, bus),
+		costRepo:       costRepo,
+		recipeRepo:     recipeRepo,
+		ingredientRepo: ingredientRepo,
+		bus:            bus,
+	}
+}
+
+func TestCostService_SetIngredientPrice_RecordsAndAnnounces(t *testing.T) {
+	setup := setupCostServiceTest()
+	var received []events.Event
+	setup.bus.Subscribe(events.IngredientPriceUpdated, func(e events.Event) error {
+		received = append(received, e)
+		return nil
+	})
+	req := models.SetIngredientPriceRequest{Price: 1.29, Quantity: 400, Unit: "g"}
+	price := &models.IngredientPrice{ID: 1, IngredientID: 4, Price: 1.29, Quantity: 400, Unit: "g"}
+
+	setup.ingredientRepo.On("IngredientExists", 4).Return(true, nil)
+	setup.costRepo.On("AddPrice", 4, req).Return(price, nil)
+
+	result, err := setup.service.SetIngredientPrice(2, 4, models.SetIngredientPriceRequest{Price: 1.29, Quantity: 400, Unit: " g "})
+
+	require.NoError(t, err)
+	assert.Equal(t, price, result)
+	require.Len(t, received, 1)
+	assert.Equal(t, 4, received[0].SubjectID)
+	assert.Equal(t, 2, received[0].UserID)
+}
+
+func TestCostService_SetIngredientPrice_ValidationErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		ingredientID  int
+		req           models.SetIngredientPriceRequest
+		expectedError error
+	}{
+		{"invalid ingredient", 0, models.SetIngredientPriceRequest{Price: 1, Quantity: 1, Unit: "kg"}, domain.ErrIngredientNotFound},
+		{"negative price", 4, models.SetIngredientPriceRequest{Price: -1, Quantity: 1, Unit: "kg"}, domain.ErrInvalidPrice},
+		{"zero quantity", 4, models.SetIngredientPriceRequest{Price: 1, Unit: "kg"}, domain.ErrInvalidQuantity},
+		{"blank unit", 4, models.SetIngredientPriceRequest{Price: 1, Quantity: 1, Unit: " "}, domain.ErrInvalidUnit},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setup := setupCostServiceTest()
+
+			_, err := setup.service.SetIngredientPrice(2, tc.ingredientID, tc.req)
+
+			assert.Equal(t, tc.expectedError, err)
+			setup.costRepo.AssertNotCalled(t, "AddPrice")
+		})
+	}
+}
+
+func TestCostService_SetIngredientPrice_UnknownIngredient(t *testing.T) {
+	setup := setupCostServiceTest()
+	setup.ingredientRepo.On("IngredientExists", 99).Return(false, nil)
+
+	_, err := setup.service.SetIngredientPrice(2, 99, models.SetIngredientPriceRequest{Price: 1, Quantity: 1, Unit: "kg"})
+
+	assert.Equal(t, domain.ErrIngredientNotFound, err)
+}
+
+func TestCostService_SnapshotRecipeCosts_ConvertsToPriceUnit(t *testing.T) {
+	setup := setupCostServiceTest()
+	ingredients := map[int][]models.RecipeIngredient{
+		1: {
+			{RecipeID: 1, IngredientID: 4, Quantity: 200, Unit: "g"},   // 1.50 per kg
+			{RecipeID: 1, IngredientID: 5, Quantity: 2, Unit: "tbsp"},  // 8.00 per l
+			{RecipeID: 1, IngredientID: 6, Quantity: 100, Unit: "ml"},  // priced by weight, no density
+			{RecipeID: 1, IngredientID: 7, Quantity: 1, Unit: "pinch"}, // never priced
+		},
+	}
+	prices := map[int]models.IngredientPrice{
+		4: {IngredientID: 4, Price: 1.50, Quantity: 1, Unit: "kg"},
+		5: {IngredientID: 5, Price: 8.00, Quantity: 1, Unit: "l"},
+		6: {IngredientID: 6, Price: 2.00, Quantity: 500, Unit: "g"},
+	}
+
+	setup.costRepo.On("GetRecipeIDsUsingIngredient", 4).Return([]int{1}, nil)
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1}).Return(ingredients, nil)
+	setup.costRepo.On("GetCurrentPrices", []int{4, 5, 6, 7}).Return(prices, nil)
+	setup.ingredientRepo.On("GetDensities", []int{4, 5, 6, 7}).Return(map[int]float64{}, nil)
+	setup.costRepo.On("RecordSnapshots", []models.RecipeCostSnapshot{
+		{RecipeID: 1, Cost: 0.54, PricedIngredients: 2, TotalIngredients: 4},
+	}).Return(nil)
+
+	err := setup.service.SnapshotRecipeCosts(events.Event{Type: events.IngredientPriceUpdated, SubjectID: 4})
+
+	assert.NoError(t, err)
+	setup.costRepo.AssertExpectations(t)
+}
+
+func TestCostService_SnapshotRecipeCosts_UnusedIngredient(t *testing.T) {
+	setup := setupCostServiceTest()
+	setup.costRepo.On("GetRecipeIDsUsingIngredient", 4).Return([]int{}, nil)
+
+	err := setup.service.SnapshotRecipeCosts(events.Event{Type: events.IngredientPriceUpdated, SubjectID: 4})
+
+	assert.NoError(t, err)
+	setup.costRepo.AssertNotCalled(t, "RecordSnapshots")
+}
+
+func TestCostService_RefreshRecipeCosts_OnlyChangedCosts(t *testing.T) {
+	setup := setupCostServiceTest()
+	ingredients := map[int][]models.RecipeIngredient{
+		1: {{RecipeID: 1, IngredientID: 4, Quantity: 500, Unit: "g"}},
+		2: {{RecipeID: 2, IngredientID: 4, Quantity: 1, Unit: "kg"}},
+		3: {{RecipeID: 3, IngredientID: 4, Quantity: 2, Unit: "kg"}},
+	}
+	prices := map[int]models.IngredientPrice{4: {IngredientID: 4, Price: 2.00, Quantity: 1, Unit: "kg"}}
+
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2, 3}).Return(ingredients, nil)
+	setup.costRepo.On("GetCurrentPrices", []int{4}).Return(prices, nil)
+	setup.ingredientRepo.On("GetDensities", []int{4}).Return(map[int]float64{}, nil)
+	setup.costRepo.On("GetSnapshots", 1).Return([]models.RecipeCostSnapshot{
+		{RecipeID: 1, Cost: 1.00, PricedIngredients: 1, TotalIngredients: 1},
+	}, nil)
+	setup.costRepo.On("GetSnapshots", 2).Return([]models.RecipeCostSnapshot{
+		{RecipeID: 2, Cost: 1.80, PricedIngredients: 1, TotalIngredients: 1},
+	}, nil)
+	setup.costRepo.On("GetSnapshots", 3).Return([]models.RecipeCostSnapshot{}, nil)
+	setup.costRepo.On("RecordSnapshots", []models.RecipeCostSnapshot{
+		{RecipeID: 2, Cost: 2.00, PricedIngredients: 1, TotalIngredients: 1},
+		{RecipeID: 3, Cost: 4.00, PricedIngredients: 1, TotalIngredients: 1},
+	}).Return(nil)
+
+	snapshotted, err := setup.service.RefreshRecipeCosts([]int{1, 2, 3})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, snapshotted)
+	setup.costRepo.AssertExpectations(t)
+}
+
+func TestCostService_GetRecipeCostHistory_ChangeOverCompleteSnapshots(t *testing.T) {
+	setup := setupCostServiceTest()
+	snapshots := []models.RecipeCostSnapshot{
+		{RecipeID: 1, Cost: 2.00, PricedIngredients: 1, TotalIngredients: 2},
+		{RecipeID: 1, Cost: 4.00, PricedIngredients: 2, TotalIngredients: 2},
+		{RecipeID: 1, Cost: 4.50, PricedIngredients: 2, TotalIngredients: 2},
+	}
+	setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, Status: models.RecipeStatusPublished}, nil)
+	setup.costRepo.On("GetSnapshots", 1).Return(snapshots, nil)
+
+	history, err := setup.service.GetRecipeCostHistory(1)
+
+	require.NoError(t, err)
+	assert.Len(t, history.Snapshots, 3)
+	require.NotNil(t, history.ChangePercent)
+	assert.Equal(t, 12.5, *history.ChangePercent)
+}
+
+func TestCostService_GetRecipeCostHistory_SingleSnapshotHasNoChange(t *testing.T) {
+	setup := setupCostServiceTest()
+	setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, Status: models.RecipeStatusPublished}, nil)
+	setup.costRepo.On("GetSnapshots", 1).Return([]models.RecipeCostSnapshot{
+		{RecipeID: 1, Cost: 4.00, PricedIngredients: 2, TotalIngredients: 2},
+	}, nil)
+
+	history, err := setup.service.GetRecipeCostHistory(1)
+
+	require.NoError(t, err)
+	assert.Nil(t, history.ChangePercent)
+}
+
+func TestCostService_GetRecipeCostHistory_HidesUnpublished(t *testing.T) {
+	setup := setupCostServiceTest()
+	setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, Status: models.RecipeStatusDraft}, nil)
+	setup.recipeRepo.On("GetByID", 2).Return(nil, sql.ErrNoRows)
+
+	_, err := setup.service.GetRecipeCostHistory(1)
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+
+	_, err = setup.service.GetRecipeCostHistory(2)
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+	setup.costRepo.AssertNotCalled(t, "GetSnapshots")
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/models"
+)
+
+// dietRanks orders the diets from most to least restrictive.
+var dietRanks = map[string]int{
+	models.DietVegan:       0,
+	models.DietVegetarian:  1,
+	models.DietPescatarian: 2,
+	models.DietOmnivore:    3,
+}
+
+// flagDiets is the most restrictive diet an ingredient with each flag suits.
+var flagDiets = map[string]string{
+	models.DietaryFlagMeat:       models.DietOmnivore,
+	models.DietaryFlagGelatin:    models.DietOmnivore,
+	models.DietaryFlagFish:       models.DietPescatarian,
+	models.DietaryFlagShellfish:  models.DietPescatarian,
+	models.DietaryFlagDairy:      models.DietVegetarian,
+	models.DietaryFlagEgg:        models.DietVegetarian,
+	models.DietaryFlagHoney:      models.DietVegetarian,
+	models.DietaryFlagPlantBased: models.DietVegan,
+}
+
+// categoryFlags are what an ingredient without flags is taken to contain,
+// so a profile excluding dairy also catches unflagged cheeses.
+var categoryFlags = map[string]string{
+	"Meat":  models.DietaryFlagMeat,
+	"Fish":  models.DietaryFlagFish,
+	"Dairy": models.DietaryFlagDairy,
+}
+
+type dietRule struct {
+	diet       string
+	confidence float64
+}
+
+// categoryRules classify ingredients without flags. Confidence reflects how
+// often a category hides exceptions: grains include egg pasta and milk
+// breads, oils include lard and ghee, and plant milks get filed as dairy.
+var categoryRules = map[string]dietRule{
+	"Meat":       {models.DietOmnivore, 0.95},
+	"Fish":       {models.DietPescatarian, 0.9},
+	"Dairy":      {models.DietVegetarian, 0.8},
+	"Vegetables": {models.DietVegan, 0.9},
+	"Fruits":     {models.DietVegan, 0.9},
+	"Spices":     {models.DietVegan, 0.85},
+	"Grains":     {models.DietVegan, 0.75},
+	"Oils":       {models.DietVegan, 0.75},
+}
+
+const (
+	overrideConfidence = 1.0
+	flagConfidence     = 0.95
+	// defaultConfidence is for ingredients nothing is known about, which are
+	// assumed to suit no diet rather than risk a wrong vegan label
+	defaultConfidence = 0.2
+)
+
+// classifyIngredient applies the rules in order of reliability: a manual
+// override, then the flags, then the category.
+func classifyIngredient(ingredient models.Ingredient, facts models.DietaryFacts) models.IngredientDietary {
+	result := models.IngredientDietary{
+		IngredientID: ingredient.ID,
+		Name:         ingredient.Name,
+		Flags:        append([]string{}, facts.Flags...),
+		Override:     facts.Override,
+	}
+	sort.Strings(result.Flags)
+
+	switch {
+	case facts.Override != "":
+		result.Diet, result.Confidence, result.Source = facts.Override, overrideConfidence, models.DietSourceOverride
+	case len(facts.Flags) > 0:
+		// Animal flags outweigh plant_based
+		result.Diet = models.DietVegan
+		for _, flag := range facts.Flags {
+			result.Diet = leastRestrictiveDiet(result.Diet, flagDiets[flag])
+		}
+		result.Confidence, result.Source = flagConfidence, models.DietSourceFlags
+	default:
+		rule, ok := dietRule{}, false
+		if ingredient.Category != nil {
+			rule, ok = categoryRules[*ingredient.Category]
+		}
+		if ok {
+			result.Diet, result.Confidence, result.Source = rule.diet, rule.confidence, models.DietSourceCategory
+		} else {
+			result.Diet, result.Confidence, result.Source = models.DietOmnivore, defaultConfidence, models.DietSourceDefault
+		}
+	}
+	return result
+}
+
+// classifyRecipe combines ingredient classifications. A recipe without
+// ingredients has nothing ruling out any diet, but no evidence either, so
+// it is vegan with confidence 0.
+func classifyRecipe(recipeID int, ingredients []models.IngredientDietary) models.RecipeDietary {
+	result := models.RecipeDietary{
+		RecipeID:    recipeID,
+		Diet:        models.DietVegan,
+		Ingredients: ingredients,
+	}
+	if len(ingredients) == 0 {
+		return result
+	}
+
+	result.Confidence = 1
+	for _, ingredient := range ingredients {
+		result.Diet = leastRestrictiveDiet(result.Diet, ingredient.Diet)
+		result.Confidence = min(result.Confidence, ingredient.Confidence)
+	}
+	return result
+}
+
+// checkRecipeCompliance classifies the recipe's ingredients and lists those
+// failing the profile. A recipe without ingredients has nothing to fail.
+func checkRecipeCompliance(recipe models.Recipe, ingredients []models.RecipeIngredient, facts map[int]models.DietaryFacts, profile models.DietProfile) models.RecipeCompliance {
+	classified := make([]models.IngredientDietary, 0, len(ingredients))
+	violations := make([]models.ComplianceViolation, 0)
+	for _, ri := range ingredients {
+		dietary := classifyIngredient(ri.Ingredient, facts[ri.IngredientID])
+		classified = append(classified, dietary)
+
+		if reasons := complianceReasons(ri.Ingredient, dietary, profile); len(reasons) > 0 {
+			violations = append(violations, models.ComplianceViolation{
+				IngredientID: dietary.IngredientID,
+				Name:         dietary.Name,
+				Diet:         dietary.Diet,
+				Confidence:   dietary.Confidence,
+				Flags:        dietary.Flags,
+				Reasons:      reasons,
+			})
+		}
+	}
+
+	dietary := classifyRecipe(recipe.ID, classified)
+	return models.RecipeCompliance{
+		RecipeID:   recipe.ID,
+		Name:       recipe.Name,
+		Passes:     len(violations) == 0,
+		Diet:       dietary.Diet,
+		Confidence: dietary.Confidence,
+		Violations: violations,
+	}
+}
+
+// complianceReasons returns why the ingredient fails the profile, if it does.
+func complianceReasons(ingredient models.Ingredient, dietary models.IngredientDietary, profile models.DietProfile) []string {
+	var reasons []string
+	if dietRanks[dietary.Diet] > dietRanks[profile.Diet] {
+		reasons = append(reasons, models.ComplianceDiet)
+	}
+
+	flags := dietary.Flags
+	if len(flags) == 0 && ingredient.Category != nil {
+		if flag, ok := categoryFlags[*ingredient.Category]; ok {
+			flags = []string{flag}
+		}
+	}
+	if hasAnyFlag(flags, profile.ExcludeFlags) {
+		reasons = append(reasons, models.ComplianceExcludedFlag)
+	}
+
+	if dietary.Confidence < profile.MinConfidence {
+		reasons = append(reasons, models.ComplianceLowConfidence)
+	}
+	return reasons
+}
+
+func hasAnyFlag(flags, wanted []string) bool {
+	for _, flag := range flags {
+		for _, w := range wanted {
+			if flag == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeDietaryFlags lowercases and dedupes flags, rejecting unknown ones.
+func normalizeDietaryFlags(flags []string) ([]string, error) {
+	seen := make(map[string]bool, len(flags))
+	normalized := make([]string, 0, len(flags))
+	for _, flag := range flags {
+		flag = strings.ToLower(strings.TrimSpace(flag))
+		if _, ok := flagDiets[flag]; !ok {
+			return nil, domain.ErrInvalidDietaryFlag
+		}
+		if !seen[flag] {
+			seen[flag] = true
+			normalized = append(normalized, flag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// leastRestrictiveDiet returns whichever of two diets allows more.
+func leastRestrictiveDiet(a, b string) string {
+	if dietRanks[b] > dietRanks[a] {
+		return b
+	}
+	return a
+}
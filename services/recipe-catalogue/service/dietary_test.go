@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyIngredient(t *testing.T) {
+	dairy, spices, unknown := "Dairy", "Spices", "Baking"
+	tests := []struct {
+		name       string
+		category   *string
+		facts      models.DietaryFacts
+		diet       string
+		confidence float64
+		source     string
+	}{
+		{"override wins over flags", &dairy, models.DietaryFacts{Flags: []string{"dairy"}, Override: "omnivore"}, models.DietOmnivore, 1, models.DietSourceOverride},
+		{"animal flag outweighs plant_based", &spices, models.DietaryFacts{Flags: []string{"plant_based", "honey"}}, models.DietVegetarian, 0.95, models.DietSourceFlags},
+		{"fish flag", nil, models.DietaryFacts{Flags: []string{"shellfish"}}, models.DietPescatarian, 0.95, models.DietSourceFlags},
+		{"category", &spices, models.DietaryFacts{}, models.DietVegan, 0.85, models.DietSourceCategory},
+		{"unknown category", &unknown, models.DietaryFacts{}, models.DietOmnivore, 0.2, models.DietSourceDefault},
+		{"no category", nil, models.DietaryFacts{}, models.DietOmnivore, 0.2, models.DietSourceDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := classifyIngredient(models.Ingredient{ID: 3, Name: "Thing", Category: tt.category}, tt.facts)
+
+			assert.Equal(t, tt.diet, result.Diet)
+			assert.Equal(t, tt.confidence, result.Confidence)
+			assert.Equal(t, tt.source, result.Source)
+		})
+	}
+}
+
+func TestClassifyRecipe_LeastRestrictiveIngredientAndLowestConfidence(t *testing.T) {
+	result := classifyRecipe(1, []models.IngredientDietary{
+		{IngredientID: 5, Diet: models.DietVegan, Confidence: 0.9},
+		{IngredientID: 12, Diet: models.DietVegetarian, Confidence: 0.95},
+		{IngredientID: 9, Diet: models.DietVegan, Confidence: 0.75},
+	})
+
+	assert.Equal(t, models.DietVegetarian, result.Diet)
+	assert.Equal(t, 0.75, result.Confidence)
+	assert.Len(t, result.Ingredients, 3)
+}
+
+func TestClassifyRecipe_NoIngredients(t *testing.T) {
+	result := classifyRecipe(1, nil)
+
+	assert.Equal(t, models.DietVegan, result.Diet)
+	assert.Zero(t, result.Confidence)
+}
+
+func TestCheckRecipeCompliance(t *testing.T) {
+	vegetables, dairy := "Vegetables", "Dairy"
+	ingredients := []models.RecipeIngredient{
+		{IngredientID: 5, Ingredient: models.Ingredient{ID: 5, Name: "Onion", Category: &vegetables}},
+		{IngredientID: 8, Ingredient: models.Ingredient{ID: 8, Name: "Parmesan Cheese", Category: &dairy}},
+		{IngredientID: 9, Ingredient: models.Ingredient{ID: 9, Name: "Stock"}},
+	}
+	facts := map[int]models.DietaryFacts{8: {Override: models.DietVegetarian}}
+	recipe := models.Recipe{ID: 1, Name: "Risotto"}
+
+	tests := []struct {
+		name       string
+		profile    models.DietProfile
+		violations map[int][]string
+	}{
+		{"omnivore excludes nothing", models.DietProfile{Diet: models.DietOmnivore}, map[int][]string{}},
+		{"vegetarian", models.DietProfile{Diet: models.DietVegetarian},
+			map[int][]string{9: {models.ComplianceDiet}}},
+		// The cheese has no flags, so its category stands in for them
+		{"dairy-free", models.DietProfile{Diet: models.DietOmnivore, ExcludeFlags: []string{"dairy"}},
+			map[int][]string{8: {models.ComplianceExcludedFlag}}},
+		{"vegan and sure", models.DietProfile{Diet: models.DietVegan, MinConfidence: 0.5},
+			map[int][]string{8: {models.ComplianceDiet}, 9: {models.ComplianceDiet, models.ComplianceLowConfidence}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checkRecipeCompliance(recipe, ingredients, facts, tt.profile)
+
+			violations := map[int][]string{}
+			for _, v := range result.Violations {
+				violations[v.IngredientID] = v.Reasons
+			}
+			assert.Equal(t, tt.violations, violations)
+			assert.Equal(t, len(tt.violations) == 0, result.Passes)
+			assert.Equal(t, models.DietOmnivore, result.Diet)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/models"
+)
+
+// difficultySignals are the recipe properties the estimate is based on.
+// TotalMinutes is zero when the author did not record a time.
+type difficultySignals struct {
+	Steps        int
+	Ingredients  int
+	TotalMinutes int
+}
+
+// estimateDifficulty scores each signal 0-2 and maps the sum onto a
+// difficulty level. The thresholds are deliberately coarse: the estimate is a
+// hint for browsing, not a substitute for the author's own judgement.
+func estimateDifficulty(s difficultySignals) string {
+	score := bucket(s.Steps, 4, 8) + bucket(s.Ingredients, 5, 10) + bucket(s.TotalMinutes, 30, 90)
+
+	switch {
+	case score <= 1:
+		return models.DifficultyEasy
+	case score <= 3:
+		return models.DifficultyMedium
+	default:
+		return models.DifficultyHard
+	}
+}
+
+func bucket(value, low, high int) int {
+	switch {
+	case value <= low:
+		return 0
+	case value <= high:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// applyEstimatedDifficulty fills in Difficulty for recipes whose author did
+// not set one and flags it as estimated. Recipes have no steps yet, so the
+// estimate uses ingredient count and total time.
+func applyEstimatedDifficulty(recipe *models.RecipeWithIngredients) {
+	if recipe == nil || recipe.Difficulty != nil {
+		return
+	}
+
+	signals := difficultySignals{Ingredients: len(recipe.Ingredients)}
+	if recipe.TotalTimeMinutes != nil {
+		signals.TotalMinutes = *recipe.TotalTimeMinutes
+	}
+
+	difficulty := estimateDifficulty(signals)
+	recipe.Difficulty = &difficulty
+	recipe.DifficultyEstimated = true
+}
+
+func applyEstimatedDifficulties(recipes []models.RecipeWithIngredients) {
+	for i := range recipes {
+		applyEstimatedDifficulty(&recipes[i])
+	}
+}
+
+func validateDifficultyAndTime(difficulty *string, totalTimeMinutes *int) error {
+	if difficulty != nil && !models.IsValidDifficulty(*difficulty) {
+		return domain.ErrInvalidDifficulty
+	}
+	if totalTimeMinutes != nil && *totalTimeMinutes < 0 {
+		return domain.ErrInvalidTotalTime
+	}
+	return nil
+}
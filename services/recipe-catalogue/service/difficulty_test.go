@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/models"
+	"meal-prep/shared/testing/factory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateDifficulty(t *testing.T) {
+	tests := []struct {
+		name     string
+		signals  difficultySignals
+		expected string
+	}{
+		{"nothing known", difficultySignals{}, models.DifficultyEasy},
+		{"few ingredients, quick", difficultySignals{Ingredients: 4, TotalMinutes: 20}, models.DifficultyEasy},
+		{"moderate ingredients and time", difficultySignals{Ingredients: 8, TotalMinutes: 60}, models.DifficultyMedium},
+		{"long ingredient list alone", difficultySignals{Ingredients: 14}, models.DifficultyMedium},
+		{"everything high", difficultySignals{Steps: 12, Ingredients: 15, TotalMinutes: 180}, models.DifficultyHard},
+		{"many steps and long cook", difficultySignals{Steps: 10, TotalMinutes: 120}, models.DifficultyHard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, estimateDifficulty(tt.signals))
+		})
+	}
+}
+
+func TestApplyEstimatedDifficulty_FillsMissingDifficulty(t *testing.T) {
+	recipe := factory.NewRecipeWithIngredientsBuilder().BuildPtr()
+	minutes := 200
+	recipe.TotalTimeMinutes = &minutes
+
+	applyEstimatedDifficulty(recipe)
+
+	require.NotNil(t, recipe.Difficulty)
+	assert.Equal(t, models.DifficultyMedium, *recipe.Difficulty)
+	assert.True(t, recipe.DifficultyEstimated)
+}
+
+func TestApplyEstimatedDifficulty_KeepsAuthorDifficulty(t *testing.T) {
+	recipe := factory.NewRecipeWithIngredientsBuilder().BuildPtr()
+	hard := models.DifficultyHard
+	recipe.Difficulty = &hard
+
+	applyEstimatedDifficulty(recipe)
+
+	assert.Equal(t, models.DifficultyHard, *recipe.Difficulty)
+	assert.False(t, recipe.DifficultyEstimated)
+}
+
+func TestValidateDifficultyAndTime(t *testing.T) {
+	easy, extreme := models.DifficultyEasy, "extreme"
+	zero, negative := 0, -5
+
+	assert.NoError(t, validateDifficultyAndTime(nil, nil))
+	assert.NoError(t, validateDifficultyAndTime(&easy, &zero))
+	assert.Equal(t, domain.ErrInvalidDifficulty, validateDifficultyAndTime(&extreme, nil))
+	assert.Equal(t, domain.ErrInvalidTotalTime, validateDifficultyAndTime(nil, &negative))
+}
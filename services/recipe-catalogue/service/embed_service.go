@@ -0,0 +1,54 @@
+package service
+
+import (
+	"database/sql"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+// EmbedService gathers what the embeddable recipe cards on other sites show.
+// Cards are read straight from the catalogue, so they follow recipe edits.
+type EmbedService interface {
+	GetRecipeEmbed(recipeID int) (*models.RecipeEmbed, error)
+}
+
+type embedService struct {
+	recipeRepo repository.RecipeRepository
+	imageRepo  repository.RecipeImageRepository
+}
+
+func NewEmbedService(recipeRepo repository.RecipeRepository, imageRepo repository.RecipeImageRepository) EmbedService {
+	return &embedService{
+		recipeRepo: recipeRepo,
+		imageRepo:  imageRepo,
+	}
+}
+
+// GetRecipeEmbed returns a published recipe with its ingredients and cover
+// photo. Drafts and private recipes are not found, so an embed of a recipe
+// that is later hidden stops showing it.
+func (s *embedService) GetRecipeEmbed(recipeID int) (*models.RecipeEmbed, error) {
+	if recipeID <= 0 {
+		return nil, domain.ErrRecipeNotFound
+	}
+
+	recipe, err := s.recipeRepo.GetByIDWithIngredients(recipeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRecipeNotFound
+		}
+		return nil, err
+	}
+	if !isPubliclyVisible(&recipe.Recipe) {
+		return nil, domain.ErrRecipeNotFound
+	}
+	applyEstimatedDifficulty(recipe)
+
+	images, err := s.imageRepo.GetByRecipeID(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RecipeEmbed{Recipe: *recipe, CoverImage: coverImage(images)}, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type embedServiceTestSetup struct {
+	service    EmbedService
+	recipeRepo *mocks.MockRecipeRepository
+	imageRepo  *mocks.MockRecipeImageRepository
+}
+
+func setupEmbedServiceTest() *embedServiceTestSetup {
+	recipeRepo := new(mocks.MockRecipeRepository)
+	imageRepo := new(mocks.MockRecipeImageRepository)
+
+	return &embedServiceTestSetup{
+		service:    NewEmbedService(recipeRepo, imageRepo),
+		recipeRepo: recipeRepo,
+		imageRepo:  imageRepo,
+	}
+}
+
+func TestEmbedService_GetRecipeEmbed(t *testing.T) {
+	setup := setupEmbedServiceTest()
+	minutes := 20
+	setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(&models.RecipeWithIngredients{
+		Recipe:      models.Recipe{ID: 1, Name: "Dal", Status: models.RecipeStatusPublished, TotalTimeMinutes: &minutes},
+		Ingredients: []models.RecipeIngredient{{IngredientID: 4, Quantity: 200, Unit: "g"}},
+	}, nil)
+	setup.imageRepo.On("GetByRecipeID", 1).Return([]models.RecipeImage{
+		{ID: 10, RecipeID: 1, Position: 1},
+		{ID: 11, RecipeID: 1, Position: 2, IsCover: true},
+	}, nil)
+
+	embed, err := setup.service.GetRecipeEmbed(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Dal", embed.Recipe.Name)
+	assert.Len(t, embed.Recipe.Ingredients, 1)
+	assert.Equal(t, 11, embed.CoverImage.ID)
+	assert.True(t, embed.Recipe.DifficultyEstimated)
+}
+
+func TestEmbedService_GetRecipeEmbed_WithoutImages(t *testing.T) {
+	setup := setupEmbedServiceTest()
+	setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(&models.RecipeWithIngredients{
+		Recipe: models.Recipe{ID: 1, Status: models.RecipeStatusPublished},
+	}, nil)
+	setup.imageRepo.On("GetByRecipeID", 1).Return([]models.RecipeImage{}, nil)
+
+	embed, err := setup.service.GetRecipeEmbed(1)
+
+	assert.NoError(t, err)
+	assert.Nil(t, embed.CoverImage)
+}
+
+func TestEmbedService_GetRecipeEmbed_HidesPrivateRecipes(t *testing.T) {
+	setup := setupEmbedServiceTest()
+	setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(&models.RecipeWithIngredients{
+		Recipe: models.Recipe{ID: 1, Status: models.RecipeStatusPrivate},
+	}, nil)
+
+	_, err := setup.service.GetRecipeEmbed(1)
+
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+	setup.imageRepo.AssertNotCalled(t, "GetByRecipeID", 1)
+}
+
+func TestEmbedService_GetRecipeEmbed_NotFound(t *testing.T) {
+	setup := setupEmbedServiceTest()
+	setup.recipeRepo.On("GetByIDWithIngredients", 9).Return(nil, sql.ErrNoRows)
+
+	_, err := setup.service.GetRecipeEmbed(9)
+
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+type FavoriteService interface {
+	AddFavorite(ctx context.Context, userID, recipeID int) error
+	RemoveFavorite(userID, recipeID int) error
+	GetFavorites(userID int, params models.PaginationParams) ([]models.Recipe, models.PaginationMeta, error)
+}
+
+type favoriteService struct {
+	favoriteRepo repository.FavoriteRepository
+	recipeRepo   repository.RecipeRepository
+	imageRepo    repository.RecipeImageRepository
+}
+
+func NewFavoriteService(favoriteRepo repository.FavoriteRepository, recipeRepo repository.RecipeRepository, imageRepo repository.RecipeImageRepository) FavoriteService {
+	return &favoriteService{
+		favoriteRepo: favoriteRepo,
+		recipeRepo:   recipeRepo,
+		imageRepo:    imageRepo,
+	}
+}
+
+// AddFavorite accepts the user's own recipes and published ones. Adding a
+// favorite twice is fine.
+func (s *favoriteService) AddFavorite(ctx context.Context, userID, recipeID int) error {
+	if recipeID <= 0 {
+		return domain.ErrRecipeNotFound
+	}
+
+	if _, err := readableRecipe(ctx, s.recipeRepo, recipeID); err != nil {
+		return err
+	}
+
+	return s.favoriteRepo.Add(userID, recipeID)
+}
+
+// RemoveFavorite works even when the recipe has since been unpublished.
+func (s *favoriteService) RemoveFavorite(userID, recipeID int) error {
+	err := s.favoriteRepo.Remove(userID, recipeID)
+	if err == sql.ErrNoRows {
+		return domain.ErrNotFavorite
+	}
+	return err
+}
+
+// GetFavorites leaves out favorites that have since been unpublished; they
+// come back if the recipe is published again.
+func (s *favoriteService) GetFavorites(userID int, params models.PaginationParams) ([]models.Recipe, models.PaginationMeta, error) {
+	recipes, total, err := s.favoriteRepo.GetFavorites(userID, params)
+	if err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	if err := attachRecipeExtras(s.imageRepo, s.favoriteRepo, recipePointers(recipes)...); err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	return recipes, models.NewPaginationMeta(params, total), nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"database/sql"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type favoriteServiceTestSetup struct {
+	service      FavoriteService
+	favoriteRepo *mocks.MockFavoriteRepository
+	recipeRepo   *mocks.MockRecipeRepository
+	imageRepo    *mocks.MockRecipeImageRepository
+}
+
+func setupFavoriteServiceTest() *favoriteServiceTestSetup {
+	favoriteRepo := new(mocks.MockFavoriteRepository)
+	recipeRepo := new(mocks.MockRecipeRepository)
+	imageRepo := new(mocks.MockRecipeImageRepository)
+	imageRepo.On("GetByRecipeIDs", mock.Anything).Return(map[int][]models.RecipeImage{}, nil).Maybe()
+
+	return &favoriteServiceTestSetup{
+		service:      NewFavoriteService(favoriteRepo, recipeRepo, imageRepo),
+		favoriteRepo: favoriteRepo,
+		recipeRepo:   recipeRepo,
+		imageRepo:    imageRepo,
+	}
+}
+
+func TestFavoriteService_AddFavorite_PublishedRecipe(t *testing.T) {
+	setup := setupFavoriteServiceTest()
+	setup.recipeRepo.On("GetByID", 9).Return(&models.Recipe{ID: 9, UserID: 2, Status: models.RecipeStatusPublished}, nil)
+	setup.favoriteRepo.On("Add", 1, 9).Return(nil)
+
+	err := setup.service.AddFavorite(asUser(1), 1, 9)
+
+	assert.NoError(t, err)
+	setup.favoriteRepo.AssertExpectations(t)
+}
+
+func TestFavoriteService_AddFavorite_SomeoneElsesDraft(t *testing.T) {
+	setup := setupFavoriteServiceTest()
+	setup.recipeRepo.On("GetByID", 9).Return(&models.Recipe{ID: 9, UserID: 2, Status: models.RecipeStatusDraft}, nil)
+
+	err := setup.service.AddFavorite(asUser(1), 1, 9)
+
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+	setup.favoriteRepo.AssertNotCalled(t, "Add")
+}
+
+func TestFavoriteService_AddFavorite_OwnDraft(t *testing.T) {
+	setup := setupFavoriteServiceTest()
+	setup.recipeRepo.On("GetByID", 9).Return(&models.Recipe{ID: 9, UserID: 1, Status: models.RecipeStatusDraft}, nil)
+	setup.favoriteRepo.On("Add", 1, 9).Return(nil)
+
+	assert.NoError(t, setup.service.AddFavorite(asUser(1), 1, 9))
+}
+
+func TestFavoriteService_AddFavorite_MissingRecipe(t *testing.T) {
+	setup := setupFavoriteServiceTest()
+	setup.recipeRepo.On("GetByID", 9).Return(nil, sql.ErrNoRows)
+
+	assert.Equal(t, domain.ErrRecipeNotFound, setup.service.AddFavorite(asUser(1), 1, 9))
+}
+
+func TestFavoriteService_RemoveFavorite_NotFavorite(t *testing.T) {
+	setup := setupFavoriteServiceTest()
+	setup.favoriteRepo.On("Remove", 1, 9).Return(sql.ErrNoRows)
+
+	err := setup.service.RemoveFavorite(1, 9)
+
+	assert.Equal(t, domain.ErrNotFavorite, err)
+}
+
+func TestFavoriteService_GetFavorites_SetsCounts(t *testing.T) {
+	setup := setupFavoriteServiceTest()
+	params := models.PaginationParams{Page: 1, PerPage: 10}
+	setup.favoriteRepo.On("GetFavorites", 1, params).Return([]models.Recipe{{ID: 9, Name: "Shakshuka"}}, 1, nil)
+	setup.favoriteRepo.On("CountByRecipeIDs", []int{9}).Return(map[int]int{9: 4}, nil)
+
+	recipes, meta, err := setup.service.GetFavorites(1, params)
+
+	assert.NoError(t, err)
+	assert.Len(t, recipes, 1)
+	assert.Equal(t, 4, *recipes[0].FavoriteCount)
+	assert.Equal(t, 1, meta.Total)
+	setup.favoriteRepo.AssertExpectations(t)
+}
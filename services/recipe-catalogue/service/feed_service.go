@@ -0,0 +1,114 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+const (
+	// SitemapChunkSize is how many recipes each sitemap lists; the sitemap
+	// protocol allows up to 50,000
+	SitemapChunkSize = 10000
+
+	// FeedSize is how many recently updated recipes the feed carries
+	FeedSize = 50
+
+	// feedCacheTTL bounds how stale the sitemap and feed get. Crawlers and
+	// feed readers poll them, so they are not rebuilt on every request.
+	feedCacheTTL = 10 * time.Minute
+)
+
+// FeedService publishes the public recipes for search engines and feed
+// readers.
+type FeedService interface {
+	GetSitemapIndex() ([]models.SitemapChunk, error)
+	GetSitemapChunk(number int) ([]models.PublishedRecipe, error)
+	GetRecipeFeed() ([]models.Recipe, error)
+}
+
+type feedService struct {
+	recipeRepo repository.RecipeRepository
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu          sync.Mutex
+	published   []models.PublishedRecipe
+	publishedAt time.Time
+	feed        []models.Recipe
+	feedAt      time.Time
+}
+
+func NewFeedService(recipeRepo repository.RecipeRepository) FeedService {
+	return &feedService{recipeRepo: recipeRepo, ttl: feedCacheTTL, now: time.Now}
+}
+
+// GetSitemapIndex lists the sitemap chunks. There is always at least one,
+// even when nothing is published yet.
+func (s *feedService) GetSitemapIndex() ([]models.SitemapChunk, error) {
+	published, err := s.publishedRecipes()
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := []models.SitemapChunk{{Number: 1}}
+	for i, recipe := range published {
+		number := i/SitemapChunkSize + 1
+		if number > len(chunks) {
+			chunks = append(chunks, models.SitemapChunk{Number: number})
+		}
+		if chunk := &chunks[number-1]; recipe.UpdatedAt.After(chunk.LastModified) {
+			chunk.LastModified = recipe.UpdatedAt
+		}
+	}
+	return chunks, nil
+}
+
+func (s *feedService) GetSitemapChunk(number int) ([]models.PublishedRecipe, error) {
+	published, err := s.publishedRecipes()
+	if err != nil {
+		return nil, err
+	}
+
+	start := (number - 1) * SitemapChunkSize
+	if number < 1 || (start >= len(published) && number != 1) {
+		return nil, domain.ErrSitemapChunkNotFound
+	}
+	end := min(start+SitemapChunkSize, len(published))
+	return published[start:end], nil
+}
+
+func (s *feedService) GetRecipeFeed() ([]models.Recipe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.feed != nil && s.now().Sub(s.feedAt) < s.ttl {
+		return s.feed, nil
+	}
+	feed, err := s.recipeRepo.GetRecentlyUpdated(FeedSize)
+	if err != nil {
+		return nil, err
+	}
+	s.feed, s.feedAt = feed, s.now()
+	return feed, nil
+}
+
+// publishedRecipes returns the cached list of published recipes, reloading
+// it when it is older than the TTL.
+func (s *feedService) publishedRecipes() ([]models.PublishedRecipe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.published != nil && s.now().Sub(s.publishedAt) < s.ttl {
+		return s.published, nil
+	}
+	published, err := s.recipeRepo.GetPublishedRecipes()
+	if err != nil {
+		return nil, err
+	}
+	s.published, s.publishedAt = published, s.now()
+	return published, nil
+}
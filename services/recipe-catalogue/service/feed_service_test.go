@@ -0,0 +1,108 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type feedServiceTestSetup struct {
+	service    *feedService
+	recipeRepo *mocks.MockRecipeRepository
+	clock      time.Time
+}
+
+func setupFeedServiceTest() *feedServiceTestSetup {
+	recipeRepo := new(mocks.MockRecipeRepository)
+	setup := &feedServiceTestSetup{recipeRepo: recipeRepo, clock: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+	setup.service = NewFeedService(recipeRepo).(*feedService)
+	setup.service.now = func() time.Time { return setup.clock }
+	return setup
+}
+
+func publishedRecipes(n int, updatedAt func(i int) time.Time) []models.PublishedRecipe {
+	recipes := make([]models.PublishedRecipe, n)
+	for i := range recipes {
+		recipes[i] = models.PublishedRecipe{ID: i + 1, UpdatedAt: updatedAt(i)}
+	}
+	return recipes
+}
+
+func TestFeedService_GetSitemapIndex_SplitsIntoChunks(t *testing.T) {
+	setup := setupFeedServiceTest()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	recipes := publishedRecipes(SitemapChunkSize+1, func(i int) time.Time {
+		if i == 10 {
+			return base.AddDate(0, 6, 0)
+		}
+		return base
+	})
+	setup.recipeRepo.On("GetPublishedRecipes").Return(recipes, nil)
+
+	chunks, err := setup.service.GetSitemapIndex()
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.SitemapChunk{
+		{Number: 1, LastModified: base.AddDate(0, 6, 0)},
+		{Number: 2, LastModified: base},
+	}, chunks)
+}
+
+func TestFeedService_GetSitemapIndex_NothingPublished(t *testing.T) {
+	setup := setupFeedServiceTest()
+	setup.recipeRepo.On("GetPublishedRecipes").Return([]models.PublishedRecipe{}, nil)
+
+	chunks, err := setup.service.GetSitemapIndex()
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.SitemapChunk{{Number: 1}}, chunks)
+
+	recipes, err := setup.service.GetSitemapChunk(1)
+	require.NoError(t, err)
+	assert.Empty(t, recipes)
+}
+
+func TestFeedService_GetSitemapChunk(t *testing.T) {
+	setup := setupFeedServiceTest()
+	recipes := publishedRecipes(SitemapChunkSize+3, func(int) time.Time { return time.Time{} })
+	setup.recipeRepo.On("GetPublishedRecipes").Return(recipes, nil)
+
+	second, err := setup.service.GetSitemapChunk(2)
+	require.NoError(t, err)
+	assert.Len(t, second, 3)
+	assert.Equal(t, SitemapChunkSize+1, second[0].ID)
+
+	_, err = setup.service.GetSitemapChunk(3)
+	assert.Equal(t, domain.ErrSitemapChunkNotFound, err)
+	_, err = setup.service.GetSitemapChunk(0)
+	assert.Equal(t, domain.ErrSitemapChunkNotFound, err)
+}
+
+func TestFeedService_CachesUntilTTL(t *testing.T) {
+	setup := setupFeedServiceTest()
+	setup.recipeRepo.On("GetPublishedRecipes").Return([]models.PublishedRecipe{{ID: 1}}, nil)
+	setup.recipeRepo.On("GetRecentlyUpdated", FeedSize).Return([]models.Recipe{{ID: 1}}, nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := setup.service.GetSitemapIndex()
+		require.NoError(t, err)
+		_, err = setup.service.GetRecipeFeed()
+		require.NoError(t, err)
+	}
+	setup.recipeRepo.AssertNumberOfCalls(t, "GetPublishedRecipes", 1)
+	setup.recipeRepo.AssertNumberOfCalls(t, "GetRecentlyUpdated", 1)
+
+	setup.clock = setup.clock.Add(feedCacheTTL)
+	_, err := setup.service.GetSitemapChunk(1)
+	require.NoError(t, err)
+	_, err = setup.service.GetRecipeFeed()
+	require.NoError(t, err)
+	setup.recipeRepo.AssertNumberOfCalls(t, "GetPublishedRecipes", 2)
+	setup.recipeRepo.AssertNumberOfCalls(t, "GetRecentlyUpdated", 2)
+}
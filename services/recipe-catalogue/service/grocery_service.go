@@ -1,82 +1,522 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
 	"meal-prep/services/recipe-catalogue/repository"
 	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+	"meal-prep/shared/units"
+
+	"golang.org/x/sync/errgroup"
 )
 
+// maxConcurrentLookups caps how many repository calls one grocery list runs
+// at once, leaving room in the connection pool for other requests.
+const maxConcurrentLookups = 4
+
 type GroceryService interface {
-	GenerateGroceryList(req models.GroceryListRequest) ([]models.GroceryListItem, error)
+	GenerateGroceryList(ctx context.Context, userID int, req models.GroceryListRequest) ([]models.GroceryListItem, error)
+	GenerateBudgetedGroceryList(ctx context.Context, userID int, req models.GroceryListRequest) (*models.BudgetedGroceryList, error)
+
+	GetStoreLayouts(userID int) ([]models.StoreLayout, error)
+	CreateStoreLayout(userID int, req models.StoreLayoutRequest) (*models.StoreLayout, error)
+	UpdateStoreLayout(ctx context.Context, userID, layoutID int, req models.StoreLayoutRequest) (*models.StoreLayout, error)
+	DeleteStoreLayout(ctx context.Context, userID, layoutID int) error
 }
 
 type groceryService struct {
-	ingredientRepo repository.IngredientRepository
-	recipeRepo     repository.RecipeRepository
+	ingredientRepo  repository.IngredientRepository
+	recipeRepo      repository.RecipeRepository
+	storeLayoutRepo repository.StoreLayoutRepository
+	costRepo        repository.CostRepository
+	mealPlanRepo    repository.MealPlanRepository
 }
 
-func NewGroceryService(ingredientRepo repository.IngredientRepository, recipeRepo repository.RecipeRepository) GroceryService {
+func NewGroceryService(ingredientRepo repository.IngredientRepository, recipeRepo repository.RecipeRepository, storeLayoutRepo repository.StoreLayoutRepository, costRepo repository.CostRepository, mealPlanRepo repository.MealPlanRepository) GroceryService {
 	return &groceryService{
-		ingredientRepo: ingredientRepo,
-		recipeRepo:     recipeRepo,
+		ingredientRepo:  ingredientRepo,
+		recipeRepo:      recipeRepo,
+		storeLayoutRepo: storeLayoutRepo,
+		costRepo:        costRepo,
+		mealPlanRepo:    mealPlanRepo,
 	}
 }
 
-func (s *groceryService) GenerateGroceryList(req models.GroceryListRequest) ([]models.GroceryListItem, error) {
-	if len(req.RecipeIDs) == 0 {
+// GenerateGroceryList aggregates the ingredients of the requested recipes,
+// ordered by the aisles of req.StoreLayoutID when given and by category
+// otherwise.
+func (s *groceryService) GenerateGroceryList(ctx context.Context, userID int, req models.GroceryListRequest) ([]models.GroceryListItem, error) {
+	recipeIDs, scales, err := s.recipesToShopFor(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(recipeIDs) == 0 {
 		return []models.GroceryListItem{}, nil
 	}
+	req.RecipeIDs = recipeIDs
 
-	// Get ingredients for all recipes
-	ingredientsMap, err := s.ingredientRepo.GetIngredientsForRecipes(req.RecipeIDs)
-	if err != nil {
+	var aisles []string
+	if req.StoreLayoutID != nil {
+		layout, err := s.storeLayout(ctx, policy.Read, *req.StoreLayoutID)
+		if err != nil {
+			return nil, err
+		}
+		aisles = layout.Categories
+	}
+
+	// The ingredient batch and the per-recipe name lookups are independent,
+	// so they run concurrently. The first error, or ctx ending, stops the
+	// lookups that haven't started.
+	var ingredientsMap map[int][]models.RecipeIngredient
+	recipeNames := make([]string, len(req.RecipeIDs))
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxConcurrentLookups)
+	group.Go(func() error {
+		if err := groupCtx.Err(); err != nil {
+			return err
+		}
+		var err error
+		ingredientsMap, err = s.ingredientRepo.GetIngredientsForRecipes(req.RecipeIDs)
+		return err
+	})
+	for i, recipeID := range req.RecipeIDs {
+		group.Go(func() error {
+			if err := groupCtx.Err(); err != nil {
+				return err
+			}
+			// A missing name only leaves the recipe unlabelled on the list
+			if recipe, err := s.recipeRepo.GetByID(recipeID); err == nil {
+				recipeNames[i] = recipe.Name
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
 		return nil, err
 	}
 
-	// Get recipe names for reference
-	recipeNamesMap := make(map[int]string)
-	for _, recipeID := range req.RecipeIDs {
-		recipe, err := s.recipeRepo.GetByID(recipeID)
-		if err == nil {
-			recipeNamesMap[recipeID] = recipe.Name
+	recipeNamesMap := make(map[int]string, len(req.RecipeIDs))
+	for i, recipeID := range req.RecipeIDs {
+		if recipeNames[i] != "" {
+			recipeNamesMap[recipeID] = recipeNames[i]
 		}
 	}
 
-	// Aggregate ingredients by ingredient ID
+	// Group ingredients by ingredient ID, walking recipes in request order so
+	// each item is totalled in the unit of the first recipe that uses it
 	aggregatedIngredients := make(map[int]*models.GroceryListItem)
+	entries := make(map[int][]models.RecipeIngredient)
+	seenRecipes := make(map[int]bool, len(req.RecipeIDs))
 
-	for recipeID, ingredients := range ingredientsMap {
+	for _, recipeID := range req.RecipeIDs {
+		if seenRecipes[recipeID] {
+			continue
+		}
+		seenRecipes[recipeID] = true
+		ingredients := ingredientsMap[recipeID]
 		recipeName := recipeNamesMap[recipeID]
 
 		for _, ingredient := range ingredients {
 			key := ingredient.IngredientID
 
 			if existing, exists := aggregatedIngredients[key]; exists {
-				// Same ingredient from another recipe - add quantities if same unit
-				if existing.Unit == ingredient.Unit {
-					existing.TotalQuantity += ingredient.Quantity
-				} else {
-					// Different units - note in recipes but don't add quantities
-					existing.TotalQuantity = -1 // Flag for manual calculation
-				}
 				existing.Recipes = append(existing.Recipes, recipeName)
 			} else {
-				// New ingredient
 				aggregatedIngredients[key] = &models.GroceryListItem{
-					IngredientID:  ingredient.IngredientID,
-					Ingredient:    ingredient.Ingredient,
-					TotalQuantity: ingredient.Quantity,
-					Unit:          ingredient.Unit,
-					Recipes:       []string{recipeName},
+					IngredientID: ingredient.IngredientID,
+					Ingredient:   ingredient.Ingredient,
+					Unit:         ingredient.Unit,
+					Recipes:      []string{recipeName},
 				}
 			}
+			if scale, ok := scales[recipeID]; ok {
+				ingredient.Quantity = scaleQuantity(ingredient.Quantity, scale)
+			}
+			entries[key] = append(entries[key], ingredient)
+		}
+	}
+
+	ingredientIDs := make([]int, 0, len(aggregatedIngredients))
+	for id := range aggregatedIngredients {
+		ingredientIDs = append(ingredientIDs, id)
+	}
+	sort.Ints(ingredientIDs)
+
+	packSizes := map[int][]models.PackSize{}
+	if len(ingredientIDs) > 0 {
+		// Without pack sizes the list still works, just without purchase hints
+		if fetched, err := s.ingredientRepo.GetPackSizesForIngredients(ingredientIDs); err == nil {
+			packSizes = fetched
 		}
 	}
 
+	// Densities are only needed where weight and volume meet, either across
+	// recipes or between the recipes and the pack sizes, so most lists never
+	// query them
+	var densityIDs []int
+	for _, id := range ingredientIDs {
+		item := aggregatedIngredients[id]
+		if crossesMassAndVolume(item.Unit, entries[id]) || packsCrossMassAndVolume(item.Unit, packSizes[id]) {
+			densityIDs = append(densityIDs, id)
+		}
+	}
+
+	densities := map[int]float64{}
+	if len(densityIDs) > 0 {
+		// Without densities those items fall back to manual calculation
+		if fetched, err := s.ingredientRepo.GetDensities(densityIDs); err == nil {
+			densities = fetched
+		}
+	}
+
+	for id, item := range aggregatedIngredients {
+		item.TotalQuantity = sumQuantities(item.Unit, entries[id], densities[id])
+		if item.TotalQuantity >= 0 {
+			// Totals in mixed units are summed in the first recipe's unit,
+			// which may no longer suit them: 500 g and 1 kg make 1.5 kg
+			item.TotalQuantity, item.Unit = units.Fit(item.TotalQuantity, item.Unit)
+			if req.MeasurementSystem != "" {
+				item.TotalQuantity, item.Unit = units.ToSystem(item.TotalQuantity, item.Unit, req.MeasurementSystem)
+			}
+		}
+		item.Purchase = suggestPurchase(item.TotalQuantity, item.Unit, packSizes[id], densities[id])
+	}
+
 	// Convert map to slice
 	var groceryList []models.GroceryListItem
 	for _, item := range aggregatedIngredients {
 		groceryList = append(groceryList, *item)
 	}
+	sortByAisle(groceryList, aisles)
 
 	return groceryList, nil
 }
+
+// recipesToShopFor resolves the request to recipe IDs and how much of each
+// to shop for, leaving recipes at their own amounts out of the scales.
+func (s *groceryService) recipesToShopFor(ctx context.Context, userID int, req models.GroceryListRequest) ([]int, map[int]float64, error) {
+	sources := 0
+	if len(req.RecipeIDs) > 0 {
+		sources++
+	}
+	if req.MealPlanID != nil {
+		sources++
+	}
+	if req.From != "" || req.To != "" {
+		sources++
+	}
+	if sources > 1 {
+		return nil, nil, domain.ErrInvalidGrocerySource
+	}
+
+	switch {
+	case req.MealPlanID != nil:
+		plan, err := visibleMealPlan(ctx, s.mealPlanRepo, *req.MealPlanID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s.plannedRecipes(ctx, []models.MealPlan{*plan})
+
+	case req.From != "" || req.To != "":
+		from, to, err := groceryDateRange(req.From, req.To)
+		if err != nil {
+			return nil, nil, err
+		}
+		plans, err := s.mealPlanRepo.GetByUserIDInRange(userID, from, to)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s.plannedRecipes(ctx, plans)
+
+	default:
+		scales := make(map[int]float64)
+		if req.Scale > 0 {
+			for _, recipeID := range req.RecipeIDs {
+				scales[recipeID] = req.Scale
+			}
+		}
+		return req.RecipeIDs, scales, nil
+	}
+}
+
+// plannedRecipes lists the recipes of the plans' entries in the order they
+// are first planned. As in a plan's printout, a recipe planned for several
+// meals of a plan is shopped for once, at the plan's scale; one in several
+// plans is shopped for once per plan. Recipes the user can no longer see are
+// left out.
+func (s *groceryService) plannedRecipes(ctx context.Context, plans []models.MealPlan) ([]int, map[int]float64, error) {
+	recipeIDs := make([]int, 0)
+	scales := make(map[int]float64)
+	for _, plan := range plans {
+		scale := plan.Scale
+		if scale <= 0 {
+			scale = 1
+		}
+
+		seen := make(map[int]bool, len(plan.Entries))
+		for _, entry := range plan.Entries {
+			if seen[entry.RecipeID] {
+				continue
+			}
+			seen[entry.RecipeID] = true
+
+			if _, ok := scales[entry.RecipeID]; !ok {
+				recipeIDs = append(recipeIDs, entry.RecipeID)
+			}
+			scales[entry.RecipeID] += scale
+		}
+	}
+
+	visible := make([]int, 0, len(recipeIDs))
+	for _, recipeID := range recipeIDs {
+		recipe, err := s.recipeRepo.GetByID(recipeID)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if policy.Authorize(ctx, policy.Read, recipeAccess(recipe)) == nil {
+			visible = append(visible, recipeID)
+		}
+	}
+	return visible, scales, nil
+}
+
+// groceryDateRange checks a from/to pair fits in a meal plan's longest
+// period.
+func groceryDateRange(from, to string) (string, string, error) {
+	start, err := time.Parse(mealPlanDateLayout, strings.TrimSpace(from))
+	if err != nil {
+		return "", "", domain.ErrInvalidGroceryDateRange
+	}
+	end, err := time.Parse(mealPlanDateLayout, strings.TrimSpace(to))
+	if err != nil || end.Before(start) || end.Sub(start) >= maxMealPlanDays*24*time.Hour {
+		return "", "", domain.ErrInvalidGroceryDateRange
+	}
+	return start.Format(mealPlanDateLayout), end.Format(mealPlanDateLayout), nil
+}
+
+// GenerateBudgetedGroceryList prices the grocery list and, when it comes to
+// more than req.Budget, suggests substitutes from the substitution table,
+// biggest saving first, until it fits.
+func (s *groceryService) GenerateBudgetedGroceryList(ctx context.Context, userID int, req models.GroceryListRequest) (*models.BudgetedGroceryList, error) {
+	if req.Budget == nil || *req.Budget <= 0 {
+		return nil, domain.ErrInvalidBudget
+	}
+
+	items, err := s.GenerateGroceryList(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredientIDs := make([]int, len(items))
+	for i, item := range items {
+		ingredientIDs[i] = item.IngredientID
+	}
+	substitutions, err := s.ingredientRepo.GetSubstitutionsForIngredients(ingredientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	pricedIDs := append([]int{}, ingredientIDs...)
+	for _, substitutes := range substitutions {
+		for _, substitute := range substitutes {
+			pricedIDs = append(pricedIDs, substitute.SubstituteID)
+		}
+	}
+	prices, err := s.costRepo.GetCurrentPrices(pricedIDs)
+	if err != nil {
+		return nil, err
+	}
+	densities, err := s.ingredientRepo.GetDensities(pricedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return fitToBudget(items, *req.Budget, substitutions, prices, densities), nil
+}
+
+func (s *groceryService) GetStoreLayouts(userID int) ([]models.StoreLayout, error) {
+	return s.storeLayoutRepo.GetByUserID(userID)
+}
+
+func (s *groceryService) CreateStoreLayout(userID int, req models.StoreLayoutRequest) (*models.StoreLayout, error) {
+	req, err := normalizeStoreLayoutRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.storeLayoutRepo.Create(userID, req)
+}
+
+func (s *groceryService) UpdateStoreLayout(ctx context.Context, userID, layoutID int, req models.StoreLayoutRequest) (*models.StoreLayout, error) {
+	req, err := normalizeStoreLayoutRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.storeLayout(ctx, policy.Update, layoutID); err != nil {
+		return nil, err
+	}
+
+	layout, err := s.storeLayoutRepo.Update(layoutID, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrStoreLayoutNotFound
+		}
+		return nil, err
+	}
+	return layout, nil
+}
+
+func (s *groceryService) DeleteStoreLayout(ctx context.Context, userID, layoutID int) error {
+	if _, err := s.storeLayout(ctx, policy.Delete, layoutID); err != nil {
+		return err
+	}
+
+	err := s.storeLayoutRepo.Delete(layoutID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrStoreLayoutNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// storeLayout loads a layout the user in ctx may act on, treating the rest
+// as missing so their IDs reveal nothing.
+func (s *groceryService) storeLayout(ctx context.Context, action policy.Action, layoutID int) (*models.StoreLayout, error) {
+	if layoutID <= 0 {
+		return nil, domain.ErrStoreLayoutNotFound
+	}
+
+	layout, err := s.storeLayoutRepo.GetByID(layoutID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrStoreLayoutNotFound
+		}
+		return nil, err
+	}
+	resource := policy.Resource{Type: storeLayoutResource, OwnerID: layout.UserID}
+	if err := authorize(ctx, action, resource, domain.ErrStoreLayoutNotFound); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+func normalizeStoreLayoutRequest(req models.StoreLayoutRequest) (models.StoreLayoutRequest, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return req, domain.ErrStoreLayoutNameRequired
+	}
+	if len(req.Categories) == 0 {
+		return req, domain.ErrInvalidStoreLayout
+	}
+
+	categories := make([]string, len(req.Categories))
+	seen := make(map[string]bool, len(req.Categories))
+	for i, category := range req.Categories {
+		category = strings.TrimSpace(category)
+		key := strings.ToLower(category)
+		if category == "" || seen[key] {
+			return req, domain.ErrInvalidStoreLayout
+		}
+		seen[key] = true
+		categories[i] = category
+	}
+	req.Categories = categories
+	return req, nil
+}
+
+// sortByAisle orders items by the position of their ingredient category in
+// aisles. Categories missing from aisles, and uncategorised ingredients, come
+// last; ties are broken by category and then ingredient name.
+func sortByAisle(items []models.GroceryListItem, aisles []string) {
+	position := make(map[string]int, len(aisles))
+	for i, category := range aisles {
+		position[strings.ToLower(category)] = i
+	}
+
+	rank := func(item models.GroceryListItem) (int, string) {
+		if item.Ingredient.Category == nil || *item.Ingredient.Category == "" {
+			return len(aisles) + 1, ""
+		}
+		category := strings.ToLower(*item.Ingredient.Category)
+		if p, ok := position[category]; ok {
+			return p, category
+		}
+		return len(aisles), category
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		rankI, categoryI := rank(items[i])
+		rankJ, categoryJ := rank(items[j])
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		if categoryI != categoryJ {
+			return categoryI < categoryJ
+		}
+		if items[i].Ingredient.Name != items[j].Ingredient.Name {
+			return items[i].Ingredient.Name < items[j].Ingredient.Name
+		}
+		return items[i].IngredientID < items[j].IngredientID
+	})
+}
+
+// sumQuantities adds up an ingredient's quantities in unit, converting
+// between units where possible. It returns -1 when some quantity cannot be
+// expressed in unit, flagging the item for manual calculation.
+func sumQuantities(unit string, ingredients []models.RecipeIngredient, gramsPerML float64) float64 {
+	total := 0.0
+	for _, ingredient := range ingredients {
+		if units.Normalize(ingredient.Unit) == units.Normalize(unit) {
+			total += ingredient.Quantity
+			continue
+		}
+
+		converted, err := units.Convert(ingredient.Quantity, ingredient.Unit, unit, gramsPerML)
+		if err != nil {
+			return -1
+		}
+		total += converted
+	}
+	return total
+}
+
+func crossesMassAndVolume(unit string, ingredients []models.RecipeIngredient) bool {
+	for _, ingredient := range ingredients {
+		if differentKinds(unit, ingredient.Unit) {
+			return true
+		}
+	}
+	return false
+}
+
+func packsCrossMassAndVolume(unit string, packSizes []models.PackSize) bool {
+	for _, packSize := range packSizes {
+		if differentKinds(unit, packSize.Unit) {
+			return true
+		}
+	}
+	return false
+}
+
+// differentKinds reports whether a and b are one a mass and the other a
+// volume, which only a density converts between.
+func differentKinds(a, b string) bool {
+	kindA, kindB := units.KindOf(a), units.KindOf(b)
+	return kindA != kindB && massOrVolume(kindA) && massOrVolume(kindB)
+}
+
+func massOrVolume(kind units.Kind) bool {
+	return kind == units.KindMass || kind == units.KindVolume
+}
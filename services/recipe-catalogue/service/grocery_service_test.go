@@ -1,30 +1,46 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"meal-prep/services/recipe-catalogue/domain"
 	"meal-prep/services/recipe-catalogue/service/mocks"
-	"meal-prep/services/recipe-catalogue/service/testdata"
 	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+	"meal-prep/shared/testing/factory"
+	"meal-prep/shared/units"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 type groceryServiceTestSetup struct {
-	service        GroceryService
-	ingredientRepo *mocks.MockIngredientRepository
-	recipeRepo     *mocks.MockRecipeRepository
+	service         GroceryService
+	ingredientRepo  *mocks.MockIngredientRepository
+	recipeRepo      *mocks.MockRecipeRepository
+	storeLayoutRepo *mocks.MockStoreLayoutRepository
+	costRepo        *mocks.MockCostRepository
+	mealPlanRepo    *mocks.MockMealPlanRepository
 }
 
 func setupGroceryServiceTest() *groceryServiceTestSetup {
 	ingredientRepo := new(mocks.MockIngredientRepository)
 	recipeRepo := new(mocks.MockRecipeRepository)
+	storeLayoutRepo := new(mocks.MockStoreLayoutRepository)
+	costRepo := new(mocks.MockCostRepository)
+	mealPlanRepo := new(mocks.MockMealPlanRepository)
 
-	service := NewGroceryService(ingredientRepo, recipeRepo)
+	service := NewGroceryService(ingredientRepo, recipeRepo, storeLayoutRepo, costRepo, mealPlanRepo)
 
 	return &groceryServiceTestSetup{
-		service:        service,
-		ingredientRepo: ingredientRepo,
-		recipeRepo:     recipeRepo,
+		service:         service,
+		ingredientRepo:  ingredientRepo,
+		recipeRepo:      recipeRepo,
+		storeLayoutRepo: storeLayoutRepo,
+		costRepo:        costRepo,
+		mealPlanRepo:    mealPlanRepo,
 	}
 }
 
@@ -34,21 +50,22 @@ func setupGroceryServiceTest() *groceryServiceTestSetup {
 
 func TestIngredientService_GenerateGroceryList_Success(t *testing.T) {
 	setup := setupGroceryServiceTest()
-	request := testdata.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
 
 	ingredientsMap := map[int][]models.RecipeIngredient{
-		1: {testdata.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(100.0).WithUnit("grams").Build()},
-		2: {testdata.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(1).WithQuantity(50.0).WithUnit("grams").Build()},
+		1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(100.0).WithUnit("grams").Build()},
+		2: {factory.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(1).WithQuantity(50.0).WithUnit("grams").Build()},
 	}
 
-	recipe1 := testdata.NewRecipeBuilder().WithID(1).WithName("Recipe 1").BuildPtr()
-	recipe2 := testdata.NewRecipeBuilder().WithID(2).WithName("Recipe 2").BuildPtr()
+	recipe1 := factory.NewRecipeBuilder().WithID(1).WithName("Recipe 1").BuildPtr()
+	recipe2 := factory.NewRecipeBuilder().WithID(2).WithName("Recipe 2").BuildPtr()
 
 	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2}).Return(ingredientsMap, nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{1}).Return(map[int][]models.PackSize{}, nil)
 	setup.recipeRepo.On("GetByID", 1).Return(recipe1, nil)
 	setup.recipeRepo.On("GetByID", 2).Return(recipe2, nil)
 
-	result, err := setup.service.GenerateGroceryList(request)
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)                        // Same ingredient from both recipes should be aggregated
@@ -59,11 +76,57 @@ func TestIngredientService_GenerateGroceryList_Success(t *testing.T) {
 	setup.recipeRepo.AssertExpectations(t)
 }
 
+func TestGroceryService_GenerateBudgetedGroceryList_PricesItems(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	budget := 1.00
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1}).Build()
+	request.Budget = &budget
+
+	ingredientsMap := map[int][]models.RecipeIngredient{
+		1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(500.0).WithUnit("grams").Build()},
+	}
+	substitutions := map[int][]models.IngredientSubstitution{
+		1: {{IngredientID: 1, SubstituteID: 2, Substitute: models.Ingredient{ID: 2, Name: "Store-brand pasta"}, Ratio: 1}},
+	}
+	prices := map[int]models.IngredientPrice{
+		1: {IngredientID: 1, Price: 2.40, Quantity: 1, Unit: "kg"},
+		2: {IngredientID: 2, Price: 0.90, Quantity: 500, Unit: "g"},
+	}
+
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1}).Return(ingredientsMap, nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{1}).Return(map[int][]models.PackSize{}, nil)
+	setup.recipeRepo.On("GetByID", 1).Return(factory.NewRecipeBuilder().WithID(1).BuildPtr(), nil)
+	setup.ingredientRepo.On("GetSubstitutionsForIngredients", []int{1}).Return(substitutions, nil)
+	setup.costRepo.On("GetCurrentPrices", []int{1, 2}).Return(prices, nil)
+	setup.ingredientRepo.On("GetDensities", []int{1, 2}).Return(map[int]float64{}, nil)
+
+	result, err := setup.service.GenerateBudgetedGroceryList(context.Background(), 1, request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1.20, result.EstimatedTotal)
+	assert.Len(t, result.Suggestions, 1)
+	assert.Equal(t, 0.90, result.EstimatedTotalWithSuggestions)
+	assert.True(t, result.WithinBudget)
+}
+
+func TestGroceryService_GenerateBudgetedGroceryList_InvalidBudget(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	budget := 0.0
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1}).Build()
+	request.Budget = &budget
+
+	result, err := setup.service.GenerateBudgetedGroceryList(context.Background(), 1, request)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrInvalidBudget, err)
+	setup.ingredientRepo.AssertNotCalled(t, "GetIngredientsForRecipes")
+}
+
 func TestIngredientService_GenerateGroceryList_EmptyRecipeList(t *testing.T) {
 	setup := setupGroceryServiceTest()
-	request := testdata.NewGroceryListRequestBuilder().WithNoRecipes().Build()
+	request := factory.NewGroceryListRequestBuilder().WithNoRecipes().Build()
 
-	result, err := setup.service.GenerateGroceryList(request)
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
 
 	assert.NoError(t, err)
 	assert.Empty(t, result)
@@ -72,26 +135,457 @@ func TestIngredientService_GenerateGroceryList_EmptyRecipeList(t *testing.T) {
 
 func TestIngredientService_GenerateGroceryList_DifferentUnits(t *testing.T) {
 	setup := setupGroceryServiceTest()
-	request := testdata.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
 
 	// Same ingredient but different units
 	ingredientsMap := map[int][]models.RecipeIngredient{
-		1: {testdata.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(100.0).WithUnit("grams").Build()},
-		2: {testdata.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(1).WithQuantity(1.0).WithUnit("cup").Build()},
+		1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(100.0).WithUnit("grams").Build()},
+		2: {factory.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(1).WithQuantity(1.0).WithUnit("cup").Build()},
 	}
 
-	recipe1 := testdata.NewRecipeBuilder().WithID(1).WithName("Recipe 1").BuildPtr()
-	recipe2 := testdata.NewRecipeBuilder().WithID(2).WithName("Recipe 2").BuildPtr()
+	recipe1 := factory.NewRecipeBuilder().WithID(1).WithName("Recipe 1").BuildPtr()
+	recipe2 := factory.NewRecipeBuilder().WithID(2).WithName("Recipe 2").BuildPtr()
 
 	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2}).Return(ingredientsMap, nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{1}).Return(map[int][]models.PackSize{}, nil)
 	setup.recipeRepo.On("GetByID", 1).Return(recipe1, nil)
 	setup.recipeRepo.On("GetByID", 2).Return(recipe2, nil)
+	setup.ingredientRepo.On("GetDensities", []int{1}).Return(map[int]float64{}, nil)
+
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, -1.0, result[0].TotalQuantity) // No density, so flag for manual calculation
+	setup.ingredientRepo.AssertExpectations(t)
+	setup.recipeRepo.AssertExpectations(t)
+}
+
+func TestIngredientService_GenerateGroceryList_ConvertsVolumeToWeightWithDensity(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
+
+	ingredientsMap := map[int][]models.RecipeIngredient{
+		1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(100.0).WithUnit("grams").Build()},
+		2: {factory.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(1).WithQuantity(1.0).WithUnit("cup").Build()},
+	}
 
-	result, err := setup.service.GenerateGroceryList(request)
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2}).Return(ingredientsMap, nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{1}).Return(map[int][]models.PackSize{}, nil)
+	setup.recipeRepo.On("GetByID", mock.Anything).Return(factory.NewRecipeBuilder().BuildPtr(), nil)
+	setup.ingredientRepo.On("GetDensities", []int{1}).Return(map[int]float64{1: 0.5}, nil)
+
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
-	assert.Equal(t, -1.0, result[0].TotalQuantity) // Flag for manual calculation
+	assert.InDelta(t, 218.29, result[0].TotalQuantity, 0.01) // 100g + 236.59ml * 0.5 g/ml
 	setup.ingredientRepo.AssertExpectations(t)
+}
+
+func TestIngredientService_GenerateGroceryList_ConvertsWithinVolumeWithoutDensity(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
+
+	ingredientsMap := map[int][]models.RecipeIngredient{
+		1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(1.0).WithUnit("tbsp").Build()},
+		2: {factory.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(1).WithQuantity(3.0).WithUnit("teaspoons").Build()},
+	}
+
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2}).Return(ingredientsMap, nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{1}).Return(map[int][]models.PackSize{}, nil)
+	setup.recipeRepo.On("GetByID", mock.Anything).Return(factory.NewRecipeBuilder().BuildPtr(), nil)
+
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.InDelta(t, 2.0, result[0].TotalQuantity, 1e-9)
+	setup.ingredientRepo.AssertNotCalled(t, "GetDensities", mock.Anything)
+}
+
+func TestIngredientService_GenerateGroceryList_CombinesPieces(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
+
+	ingredientsMap := map[int][]models.RecipeIngredient{
+		1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(2.0).WithUnit("pieces").Build()},
+		2: {factory.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(1).WithQuantity(1.0).WithUnit("piece").Build()},
+	}
+
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2}).Return(ingredientsMap, nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{1}).Return(map[int][]models.PackSize{}, nil)
+	setup.recipeRepo.On("GetByID", mock.Anything).Return(factory.NewRecipeBuilder().BuildPtr(), nil)
+
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 3.0, result[0].TotalQuantity)
+	assert.Equal(t, "pieces", result[0].Unit)
+	setup.ingredientRepo.AssertNotCalled(t, "GetDensities", mock.Anything)
+}
+
+func TestIngredientService_GenerateGroceryList_ShowsTotalsInMeasurementSystem(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
+	request.MeasurementSystem = units.Metric
+
+	ingredientsMap := map[int][]models.RecipeIngredient{
+		1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(1).WithUnit("lb").Build()},
+		2: {factory.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(1).WithQuantity(8).WithUnit("oz").Build()},
+	}
+
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2}).Return(ingredientsMap, nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{1}).Return(map[int][]models.PackSize{}, nil)
+	setup.recipeRepo.On("GetByID", mock.Anything).Return(factory.NewRecipeBuilder().BuildPtr(), nil)
+
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 680.39, result[0].TotalQuantity) // 1.5 lb
+	assert.Equal(t, units.Gram, result[0].Unit)
+}
+
+func TestIngredientService_GenerateGroceryList_SuggestsPackPurchase(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
+
+	ingredientsMap := map[int][]models.RecipeIngredient{
+		1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(3).WithQuantity(400.0).WithUnit("g").Build()},
+		2: {factory.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(3).WithQuantity(250.0).WithUnit("g").Build()},
+	}
+	label := "can"
+	packSizes := map[int][]models.PackSize{3: {{IngredientID: 3, Quantity: 400, Unit: "g", Label: &label}}}
+
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2}).Return(ingredientsMap, nil)
+	setup.recipeRepo.On("GetByID", mock.Anything).Return(factory.NewRecipeBuilder().BuildPtr(), nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{3}).Return(packSizes, nil)
+
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 650.0, result[0].TotalQuantity)
+	if assert.NotNil(t, result[0].Purchase) {
+		assert.Equal(t, 2, result[0].Purchase.Packs)
+		assert.Equal(t, 150.0, result[0].Purchase.Leftover)
+		assert.Equal(t, "Buy 2 × 400 g can; you'll have 150 g left over", result[0].Purchase.Note)
+	}
+	setup.ingredientRepo.AssertNotCalled(t, "GetDensities", mock.Anything)
+}
+
+func TestIngredientService_GenerateGroceryList_IngredientFetchError(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
+
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2}).Return(nil, errors.New("database error"))
+	setup.recipeRepo.On("GetByID", mock.Anything).Return(factory.NewRecipeBuilder().BuildPtr(), nil).Maybe()
+
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
+
+	assert.EqualError(t, err, "database error")
+	assert.Nil(t, result)
+}
+
+func TestIngredientService_GenerateGroceryList_MissingRecipeNameIsTolerated(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
+
+	ingredientsMap := map[int][]models.RecipeIngredient{
+		1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(100.0).WithUnit("grams").Build()},
+		2: {factory.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(2).WithQuantity(1.0).WithUnit("cup").Build()},
+	}
+
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2}).Return(ingredientsMap, nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{1, 2}).Return(map[int][]models.PackSize{}, nil)
+	setup.recipeRepo.On("GetByID", 1).Return(factory.NewRecipeBuilder().WithID(1).WithName("Recipe 1").BuildPtr(), nil)
+	setup.recipeRepo.On("GetByID", 2).Return(nil, sql.ErrNoRows)
+
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
 	setup.recipeRepo.AssertExpectations(t)
 }
+
+func TestIngredientService_GenerateGroceryList_CancelledContext(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := setup.service.GenerateGroceryList(ctx, 1, request)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, result)
+	setup.ingredientRepo.AssertNotCalled(t, "GetIngredientsForRecipes", mock.Anything)
+}
+
+// =============================================================================
+// STORE LAYOUT TESTS
+// =============================================================================
+
+func groceryItem(id int, name, category string) models.GroceryListItem {
+	ingredient := factory.NewIngredientBuilder().WithID(id).WithName(name).WithCategory(category).Build()
+	return models.GroceryListItem{IngredientID: id, Ingredient: ingredient}
+}
+
+func itemNames(items []models.GroceryListItem) []string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Ingredient.Name
+	}
+	return names
+}
+
+func TestSortByAisle_FollowsLayoutThenCategoryThenName(t *testing.T) {
+	items := []models.GroceryListItem{
+		groceryItem(1, "Salt", "Spices"),
+		groceryItem(2, "Milk", "Dairy"),
+		groceryItem(3, "Onion", "Vegetables"),
+		groceryItem(4, "Rice", "Grains"),
+		groceryItem(5, "Garlic", "Vegetables"),
+		groceryItem(6, "Cheese", "Dairy"),
+	}
+
+	sortByAisle(items, []string{"vegetables", "Dairy"})
+
+	assert.Equal(t, []string{"Garlic", "Onion", "Cheese", "Milk", "Rice", "Salt"}, itemNames(items))
+}
+
+func TestSortByAisle_WithoutLayoutGroupsByCategory(t *testing.T) {
+	items := []models.GroceryListItem{
+		groceryItem(1, "Salt", "Spices"),
+		{IngredientID: 2, Ingredient: models.Ingredient{ID: 2, Name: "Mystery"}},
+		groceryItem(3, "Milk", "Dairy"),
+	}
+
+	sortByAisle(items, nil)
+
+	assert.Equal(t, []string{"Milk", "Salt", "Mystery"}, itemNames(items))
+}
+
+func TestIngredientService_GenerateGroceryList_RejectsSomeoneElsesLayout(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	layoutID := 4
+	request := models.GroceryListRequest{RecipeIDs: []int{1}, StoreLayoutID: &layoutID}
+	setup.storeLayoutRepo.On("GetByID", 4).Return(&models.StoreLayout{ID: 4, UserID: 99}, nil)
+
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrStoreLayoutNotFound, err)
+	setup.ingredientRepo.AssertNotCalled(t, "GetIngredientsForRecipes", mock.Anything)
+}
+
+func TestIngredientService_CreateStoreLayout_NormalizesCategories(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	expected := models.StoreLayoutRequest{Name: "Supermarket", Categories: []string{"Vegetables", "Dairy"}}
+	setup.storeLayoutRepo.On("Create", 1, expected).Return(&models.StoreLayout{ID: 1, UserID: 1}, nil)
+
+	_, err := setup.service.CreateStoreLayout(1, models.StoreLayoutRequest{
+		Name: " Supermarket ", Categories: []string{" Vegetables", "Dairy "},
+	})
+
+	assert.NoError(t, err)
+	setup.storeLayoutRepo.AssertExpectations(t)
+}
+
+func TestIngredientService_CreateStoreLayout_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      models.StoreLayoutRequest
+		expected error
+	}{
+		{"blank name", models.StoreLayoutRequest{Name: " ", Categories: []string{"Dairy"}}, domain.ErrStoreLayoutNameRequired},
+		{"no categories", models.StoreLayoutRequest{Name: "Shop"}, domain.ErrInvalidStoreLayout},
+		{"blank category", models.StoreLayoutRequest{Name: "Shop", Categories: []string{"Dairy", " "}}, domain.ErrInvalidStoreLayout},
+		{"duplicate category", models.StoreLayoutRequest{Name: "Shop", Categories: []string{"Dairy", "dairy"}}, domain.ErrInvalidStoreLayout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupGroceryServiceTest()
+
+			_, err := setup.service.CreateStoreLayout(1, tt.req)
+
+			assert.Equal(t, tt.expected, err)
+			setup.storeLayoutRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestIngredientService_DeleteStoreLayout_OnlyOwner(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	setup.storeLayoutRepo.On("GetByID", 4).Return(&models.StoreLayout{ID: 4, UserID: 99}, nil)
+
+	err := setup.service.DeleteStoreLayout(asUser(1), 1, 4)
+
+	assert.Equal(t, domain.ErrStoreLayoutNotFound, err)
+	setup.storeLayoutRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestIngredientService_GenerateGroceryList_AppliesScale(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	request := factory.NewGroceryListRequestBuilder().WithRecipeIDs([]int{1, 2}).Build()
+	request.Scale = 1.5
+
+	ingredientsMap := map[int][]models.RecipeIngredient{
+		1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(100.0).WithUnit("grams").Build()},
+		2: {factory.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(1).WithQuantity(50.0).WithUnit("grams").Build()},
+	}
+
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2}).Return(ingredientsMap, nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{1}).Return(map[int][]models.PackSize{}, nil)
+	setup.recipeRepo.On("GetByID", mock.Anything).Return(nil, sql.ErrNoRows)
+
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 225.0, result[0].TotalQuantity)
+	assert.Equal(t, 100.0, ingredientsMap[1][0].Quantity) // the recipes' own amounts are untouched
+}
+
+func TestGroceryService_GenerateGroceryList_FromMealPlan(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	planID := 3
+	request := factory.NewGroceryListRequestBuilder().WithNoRecipes().Build()
+	request.MealPlanID = &planID
+
+	// Recipe 1 is planned twice but shopped for once; recipe 3 is someone
+	// else's draft
+	setup.mealPlanRepo.On("GetByID", 3).Return(&models.MealPlan{ID: 3, UserID: 1, Scale: 2, Entries: []models.MealPlanEntry{
+		{RecipeID: 1, Date: "2025-10-06", Slot: "dinner"},
+		{RecipeID: 3, Date: "2025-10-06", Slot: "dinner"},
+		{RecipeID: 1, Date: "2025-10-07", Slot: "lunch"},
+		{RecipeID: 2, Date: "2025-10-07", Slot: "dinner"},
+	}}, nil)
+	setup.recipeRepo.On("GetByID", 1).Return(factory.NewRecipeBuilder().WithID(1).WithName("Recipe 1").BuildPtr(), nil)
+	setup.recipeRepo.On("GetByID", 2).Return(factory.NewRecipeBuilder().WithID(2).WithName("Recipe 2").BuildPtr(), nil)
+	setup.recipeRepo.On("GetByID", 3).Return(&models.Recipe{ID: 3, UserID: 9, Status: models.RecipeStatusDraft}, nil)
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2}).Return(map[int][]models.RecipeIngredient{
+		1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(500.0).WithUnit("grams").Build()},
+		2: {factory.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(1).WithQuantity(0.25).WithUnit("kg").Build()},
+	}, nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{1}).Return(map[int][]models.PackSize{}, nil)
+
+	result, err := setup.service.GenerateGroceryList(asUser(1), 1, request)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 1.5, result[0].TotalQuantity) // (500 g + 0.25 kg) at twice the recipes
+	assert.Equal(t, "kg", result[0].Unit)
+	assert.Equal(t, []string{"Recipe 1", "Recipe 2"}, result[0].Recipes)
+	setup.ingredientRepo.AssertNotCalled(t, "GetDensities", mock.Anything)
+}
+
+func TestGroceryService_GenerateGroceryList_PlannedRecipesFollowPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject policy.Subject
+		recipes []string
+	}{
+		{"owner sees own private recipe only", policy.Subject{UserID: 1, Role: models.RoleUser}, []string{"Mine"}},
+		{"admin reads others' private recipes", policy.Subject{UserID: 1, Role: models.RoleAdmin}, []string{"Mine", "Theirs"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupGroceryServiceTest()
+			planID := 3
+			request := factory.NewGroceryListRequestBuilder().WithNoRecipes().Build()
+			request.MealPlanID = &planID
+
+			setup.mealPlanRepo.On("GetByID", 3).Return(&models.MealPlan{ID: 3, UserID: 1, Scale: 1, Entries: []models.MealPlanEntry{
+				{RecipeID: 1, Date: "2025-10-06", Slot: "dinner"},
+				{RecipeID: 2, Date: "2025-10-07", Slot: "dinner"},
+			}}, nil)
+			setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, UserID: 1, Name: "Mine", Status: models.RecipeStatusPrivate}, nil)
+			setup.recipeRepo.On("GetByID", 2).Return(&models.Recipe{ID: 2, UserID: 9, Name: "Theirs", Status: models.RecipeStatusPrivate}, nil)
+			ingredients := map[int][]models.RecipeIngredient{
+				1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(100.0).WithUnit("grams").Build()},
+				2: {factory.NewRecipeIngredientBuilder().WithRecipeID(2).WithIngredientID(1).WithQuantity(100.0).WithUnit("grams").Build()},
+			}
+			setup.ingredientRepo.On("GetIngredientsForRecipes", mock.Anything).Return(ingredients, nil)
+			setup.ingredientRepo.On("GetPackSizesForIngredients", []int{1}).Return(map[int][]models.PackSize{}, nil)
+
+			result, err := setup.service.GenerateGroceryList(policy.WithSubject(context.Background(), tt.subject), 1, request)
+
+			assert.NoError(t, err)
+			assert.Len(t, result, 1)
+			assert.Equal(t, tt.recipes, result[0].Recipes)
+		})
+	}
+}
+
+func TestGroceryService_GenerateGroceryList_FromDateRange(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	request := factory.NewGroceryListRequestBuilder().WithNoRecipes().Build()
+	request.From, request.To = "2025-10-11", "2025-10-14"
+
+	// Recipe 1 is in both plans, so it is shopped for once per plan
+	setup.mealPlanRepo.On("GetByUserIDInRange", 1, "2025-10-11", "2025-10-14").Return([]models.MealPlan{
+		{ID: 3, UserID: 1, Scale: 1, Entries: []models.MealPlanEntry{{RecipeID: 1, Date: "2025-10-12", Slot: "dinner"}}},
+		{ID: 4, UserID: 1, Scale: 2, Entries: []models.MealPlanEntry{{RecipeID: 1, Date: "2025-10-13", Slot: "dinner"}}},
+	}, nil)
+	setup.recipeRepo.On("GetByID", 1).Return(factory.NewRecipeBuilder().WithID(1).BuildPtr(), nil)
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1}).Return(map[int][]models.RecipeIngredient{
+		1: {factory.NewRecipeIngredientBuilder().WithRecipeID(1).WithIngredientID(1).WithQuantity(100.0).WithUnit("ml").Build()},
+	}, nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{1}).Return(map[int][]models.PackSize{}, nil)
+
+	result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 300.0, result[0].TotalQuantity)
+}
+
+func TestGroceryService_GenerateGroceryList_SourceErrors(t *testing.T) {
+	planID := 3
+	tests := []struct {
+		name    string
+		modify  func(*models.GroceryListRequest)
+		wantErr error
+	}{
+		{"recipes and a plan", func(r *models.GroceryListRequest) { r.RecipeIDs, r.MealPlanID = []int{1}, &planID },
+			domain.ErrInvalidGrocerySource},
+		{"plan and dates", func(r *models.GroceryListRequest) { r.MealPlanID, r.From, r.To = &planID, "2025-10-06", "2025-10-12" },
+			domain.ErrInvalidGrocerySource},
+		{"only from", func(r *models.GroceryListRequest) { r.From = "2025-10-06" }, domain.ErrInvalidGroceryDateRange},
+		{"to before from", func(r *models.GroceryListRequest) { r.From, r.To = "2025-10-12", "2025-10-06" },
+			domain.ErrInvalidGroceryDateRange},
+		{"longer than a month", func(r *models.GroceryListRequest) { r.From, r.To = "2025-10-01", "2025-11-01" },
+			domain.ErrInvalidGroceryDateRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupGroceryServiceTest()
+			request := factory.NewGroceryListRequestBuilder().WithNoRecipes().Build()
+			tt.modify(&request)
+
+			result, err := setup.service.GenerateGroceryList(context.Background(), 1, request)
+
+			assert.Nil(t, result)
+			assert.Equal(t, tt.wantErr, err)
+			setup.mealPlanRepo.AssertNotCalled(t, "GetByUserIDInRange", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGroceryService_GenerateGroceryList_HidesOtherUsersPrivatePlans(t *testing.T) {
+	setup := setupGroceryServiceTest()
+	planID := 3
+	request := factory.NewGroceryListRequestBuilder().WithNoRecipes().Build()
+	request.MealPlanID = &planID
+	setup.mealPlanRepo.On("GetByID", 3).Return(&models.MealPlan{ID: 3, UserID: 9}, nil)
+
+	result, err := setup.service.GenerateGroceryList(asUser(1), 1, request)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrMealPlanNotFound, err)
+}
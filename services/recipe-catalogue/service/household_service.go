@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+
+	"github.com/google/uuid"
+)
+
+const maxHouseholdNameLength = 100
+
+// HouseholdService groups users who share a pantry. Whoever creates a
+// household owns it; others join with its invite code.
+type HouseholdService interface {
+	// CreateHousehold starts a household with the user as owner and only
+	// member, its pantry a copy of theirs
+	CreateHousehold(userID int, req models.CreateHouseholdRequest) (*models.Household, error)
+	// GetHousehold returns the household the user belongs to. Only the owner
+	// sees the invite code
+	GetHousehold(ctx context.Context, userID int) (*models.Household, error)
+	JoinHousehold(userID int, req models.JoinHouseholdRequest) (*models.Household, error)
+	// RemoveMember takes memberID out of the user's household. Members may
+	// remove themselves and the owner anyone; the owner leaves last, which
+	// deletes the household and its pantry
+	RemoveMember(ctx context.Context, userID, memberID int) error
+	// Households lists the households the user belongs to, for the policy
+	// subject of their requests
+	Households(userID int) ([]int, error)
+}
+
+type householdService struct {
+	householdRepo repository.HouseholdRepository
+	events        *PantryEvents
+	newInviteCode func() string
+}
+
+func NewHouseholdService(householdRepo repository.HouseholdRepository, events *PantryEvents) HouseholdService {
+	return &householdService{
+		householdRepo: householdRepo,
+		events:        events,
+		newInviteCode: uuid.NewString,
+	}
+}
+
+func (s *householdService) CreateHousehold(userID int, req models.CreateHouseholdRequest) (*models.Household, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxHouseholdNameLength {
+		return nil, domain.ErrInvalidHouseholdName
+	}
+
+	household, err := s.householdRepo.Create(userID, name, s.newInviteCode())
+	if err != nil {
+		return nil, err
+	}
+	// Their pantry is the household's from now on
+	s.events.disconnect(userID)
+	return household, nil
+}
+
+func (s *householdService) GetHousehold(ctx context.Context, userID int) (*models.Household, error) {
+	household, err := s.memberHousehold(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, policy.Read, householdAccess(household), domain.ErrHouseholdNotFound); err != nil {
+		return nil, err
+	}
+	hideInviteCode(ctx, household)
+	return household, nil
+}
+
+func (s *householdService) JoinHousehold(userID int, req models.JoinHouseholdRequest) (*models.Household, error) {
+	code := strings.TrimSpace(req.InviteCode)
+	if code == "" {
+		return nil, domain.ErrInvalidInviteCode
+	}
+	household, err := s.householdRepo.GetByInviteCode(code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvalidInviteCode
+		}
+		return nil, err
+	}
+
+	if err := s.householdRepo.AddMember(household.ID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvalidInviteCode
+		}
+		return nil, err
+	}
+	s.events.disconnect(userID)
+
+	// The request's subject predates joining, so the household is read
+	// directly; a new member is never its owner
+	household, err = s.memberHousehold(userID)
+	if err != nil {
+		return nil, err
+	}
+	household.InviteCode = ""
+	return household, nil
+}
+
+func (s *householdService) RemoveMember(ctx context.Context, userID, memberID int) error {
+	household, err := s.GetHousehold(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Leaving updates your own membership; removing someone else is the
+	// owner's call, which the policy keeps to whoever may delete the
+	// household
+	action := policy.Update
+	if memberID != userID {
+		action = policy.Delete
+	}
+	if err := authorize(ctx, action, householdAccess(household), domain.ErrNotHouseholdOwner); err != nil {
+		return err
+	}
+
+	switch {
+	case memberID == household.OwnerID && len(household.Members) > 1:
+		return domain.ErrHouseholdOwnerLeaving
+	case memberID == household.OwnerID:
+		err = s.householdRepo.Delete(household.ID)
+	default:
+		err = s.householdRepo.RemoveMember(household.ID, memberID)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrHouseholdMemberNotFound
+		}
+		return err
+	}
+
+	// Back to their own pantry
+	s.events.disconnect(memberID)
+	return nil
+}
+
+func (s *householdService) Households(userID int) ([]int, error) {
+	return s.householdRepo.GetHouseholdIDs(userID)
+}
+
+func (s *householdService) memberHousehold(userID int) (*models.Household, error) {
+	household, err := s.householdRepo.GetByMember(userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrHouseholdNotFound
+		}
+		return nil, err
+	}
+	return household, nil
+}
+
+// householdAccess describes a household to the policy, which lets its
+// members read and update it and leaves deleting to the owner.
+func householdAccess(household *models.Household) policy.Resource {
+	return policy.Resource{Type: householdResource, OwnerID: household.OwnerID, HouseholdID: household.ID}
+}
+
+// hideInviteCode keeps the invite code for whoever may delete the household,
+// which is its owner.
+func hideInviteCode(ctx context.Context, household *models.Household) {
+	if policy.Authorize(ctx, policy.Delete, householdAccess(household)) != nil {
+		household.InviteCode = ""
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"database/sql"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type householdServiceTestSetup struct {
+	service       *householdService
+	householdRepo *mocks.MockHouseholdRepository
+	events        *PantryEvents
+}
+
+func setupHouseholdServiceTest() *householdServiceTestSetup {
+	householdRepo := new(mocks.MockHouseholdRepository)
+	events := NewPantryEvents()
+	svc := NewHouseholdService(householdRepo, events).(*householdService)
+	svc.newInviteCode = func() string { return "code-1" }
+
+	return &householdServiceTestSetup{service: svc, householdRepo: householdRepo, events: events}
+}
+
+// flat4 is household 2, owned by user 7 with user 8 as a member.
+func flat4() *models.Household {
+	return &models.Household{ID: 2, Name: "Flat 4", OwnerID: 7, InviteCode: "code-1",
+		Members: []models.HouseholdMember{{UserID: 7}, {UserID: 8}}}
+}
+
+func TestHouseholdService_CreateHousehold_EndsOwnPantryStream(t *testing.T) {
+	setup := setupHouseholdServiceTest()
+	setup.householdRepo.On("Create", 7, "Flat 4", "code-1").Return(&models.Household{ID: 2, Name: "Flat 4", OwnerID: 7}, nil)
+	changes, stop := setup.events.watch(pantryKey{userID: 7}, 7)
+	defer stop()
+
+	household, err := setup.service.CreateHousehold(7, models.CreateHouseholdRequest{Name: "  Flat 4 "})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, household.ID)
+	_, open := <-changes
+	assert.False(t, open)
+}
+
+func TestHouseholdService_CreateHousehold_InvalidName(t *testing.T) {
+	setup := setupHouseholdServiceTest()
+
+	for _, name := range []string{"", "   ", strings.Repeat("a", maxHouseholdNameLength+1)} {
+		_, err := setup.service.CreateHousehold(7, models.CreateHouseholdRequest{Name: name})
+		assert.Equal(t, domain.ErrInvalidHouseholdName, err)
+	}
+	setup.householdRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHouseholdService_GetHousehold_HidesInviteCodeFromMembers(t *testing.T) {
+	setup := setupHouseholdServiceTest()
+	setup.householdRepo.On("GetByMember", 8).Return(flat4(), nil)
+	setup.householdRepo.On("GetByMember", 9).Return(nil, sql.ErrNoRows)
+
+	household, err := setup.service.GetHousehold(inHousehold(8, 2), 8)
+	require.NoError(t, err)
+	assert.Empty(t, household.InviteCode)
+
+	_, err = setup.service.GetHousehold(asUser(9), 9)
+	assert.Equal(t, domain.ErrHouseholdNotFound, err)
+}
+
+func TestHouseholdService_GetHousehold_ShowsInviteCodeToOwner(t *testing.T) {
+	setup := setupHouseholdServiceTest()
+	setup.householdRepo.On("GetByMember", 7).Return(flat4(), nil)
+
+	household, err := setup.service.GetHousehold(asUser(7), 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, "code-1", household.InviteCode)
+}
+
+func TestHouseholdService_GetHousehold_RequiresMembershipInSubject(t *testing.T) {
+	setup := setupHouseholdServiceTest()
+	setup.householdRepo.On("GetByMember", 8).Return(flat4(), nil)
+
+	_, err := setup.service.GetHousehold(asUser(8), 8)
+
+	assert.Equal(t, domain.ErrHouseholdNotFound, err)
+}
+
+func TestHouseholdService_JoinHousehold(t *testing.T) {
+	setup := setupHouseholdServiceTest()
+	setup.householdRepo.On("GetByInviteCode", "code-1").Return(flat4(), nil)
+	setup.householdRepo.On("AddMember", 2, 8).Return(nil)
+	setup.householdRepo.On("GetByMember", 8).Return(flat4(), nil)
+
+	household, err := setup.service.JoinHousehold(8, models.JoinHouseholdRequest{InviteCode: "code-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, household.ID)
+	setup.householdRepo.AssertExpectations(t)
+}
+
+func TestHouseholdService_JoinHousehold_UnknownCode(t *testing.T) {
+	setup := setupHouseholdServiceTest()
+	setup.householdRepo.On("GetByInviteCode", "nope").Return(nil, sql.ErrNoRows)
+
+	_, err := setup.service.JoinHousehold(8, models.JoinHouseholdRequest{InviteCode: "nope"})
+
+	assert.Equal(t, domain.ErrInvalidInviteCode, err)
+	setup.householdRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestHouseholdService_RemoveMember_Rules(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int
+		memberID int
+		members  []models.HouseholdMember
+		expected error
+	}{
+		{name: "member removing someone else", userID: 8, memberID: 7, expected: domain.ErrNotHouseholdOwner},
+		{name: "owner leaving others behind", userID: 7, memberID: 7, expected: domain.ErrHouseholdOwnerLeaving},
+		{name: "member leaving", userID: 8, memberID: 8},
+		{name: "owner removing a member", userID: 7, memberID: 8},
+		{name: "owner leaving last", userID: 7, memberID: 7, members: []models.HouseholdMember{{UserID: 7}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupHouseholdServiceTest()
+			household := flat4()
+			if tt.members != nil {
+				household.Members = tt.members
+			}
+			setup.householdRepo.On("GetByMember", tt.userID).Return(household, nil)
+			setup.householdRepo.On("RemoveMember", 2, tt.memberID).Return(nil).Maybe()
+			setup.householdRepo.On("Delete", 2).Return(nil).Maybe()
+
+			err := setup.service.RemoveMember(inHousehold(tt.userID, 2), tt.userID, tt.memberID)
+
+			assert.Equal(t, tt.expected, err)
+			switch {
+			case tt.expected != nil:
+				setup.householdRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything)
+				setup.householdRepo.AssertNotCalled(t, "Delete", mock.Anything)
+			case len(household.Members) == 1:
+				setup.householdRepo.AssertCalled(t, "Delete", 2)
+			default:
+				setup.householdRepo.AssertCalled(t, "RemoveMember", 2, tt.memberID)
+			}
+		})
+	}
+}
+
+func TestHouseholdService_RemoveMember_NotAMember(t *testing.T) {
+	setup := setupHouseholdServiceTest()
+	setup.householdRepo.On("GetByMember", 7).Return(flat4(), nil)
+	setup.householdRepo.On("RemoveMember", 2, 9).Return(sql.ErrNoRows)
+
+	err := setup.service.RemoveMember(inHousehold(7, 2), 7, 9)
+
+	assert.Equal(t, domain.ErrHouseholdMemberNotFound, err)
+}
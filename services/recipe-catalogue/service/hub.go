@@ -0,0 +1,90 @@
+package service
+
+import "sync"
+
+// watcherBuffer is how many changes a watcher may have waiting before it is
+// disconnected.
+const watcherBuffer = 32
+
+// hub passes changes published under a key on to everyone watching that
+// key. Watchers live in this process, so changes reach the people watching
+// through the same instance they were made on. Shared lists and pantries
+// each keep one.
+type hub[K comparable, V any] struct {
+	mu       sync.Mutex
+	watchers map[K]map[chan V]int // to the user watching, 0 for anyone
+}
+
+func newHub[K comparable, V any]() *hub[K, V] {
+	return &hub[K, V]{watchers: make(map[K]map[chan V]int)}
+}
+
+// watch delivers every change published under key from now on until stop
+// is called or the hub closes the channel.
+func (h *hub[K, V]) watch(key K, userID int) (<-chan V, func()) {
+	watcher := make(chan V, watcherBuffer)
+	h.mu.Lock()
+	if h.watchers[key] == nil {
+		h.watchers[key] = make(map[chan V]int)
+	}
+	h.watchers[key][watcher] = userID
+	h.mu.Unlock()
+
+	stop := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.removeWatcher(key, watcher)
+	}
+	return watcher, stop
+}
+
+// publish never blocks the person making the change: a watcher whose
+// buffer is full is disconnected instead.
+func (h *hub[K, V]) publish(key K, change V) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for watcher := range h.watchers[key] {
+		select {
+		case watcher <- change:
+		default:
+			h.removeWatcher(key, watcher)
+		}
+	}
+}
+
+// closeKey ends every stream watching key.
+func (h *hub[K, V]) closeKey(key K) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for watcher := range h.watchers[key] {
+		h.removeWatcher(key, watcher)
+	}
+}
+
+// disconnect ends every stream userID is watching.
+func (h *hub[K, V]) disconnect(userID int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for key, watchers := range h.watchers {
+		for watcher, watching := range watchers {
+			if watching == userID {
+				h.removeWatcher(key, watcher)
+			}
+		}
+	}
+}
+
+// removeWatcher closes watcher unless it is already gone. h.mu must be held.
+func (h *hub[K, V]) removeWatcher(key K, watcher chan V) {
+	if _, ok := h.watchers[key][watcher]; !ok {
+		return
+	}
+	close(watcher)
+	delete(h.watchers[key], watcher)
+	if len(h.watchers[key]) == 0 {
+		delete(h.watchers, key)
+	}
+}
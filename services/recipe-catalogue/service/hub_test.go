@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_PublishesToWatchersOfTheKey(t *testing.T) {
+	h := newHub[string, int]()
+	first, stopFirst := h.watch("a", 0)
+	defer stopFirst()
+	other, stopOther := h.watch("b", 0)
+	defer stopOther()
+
+	h.publish("a", 1)
+
+	assert.Equal(t, 1, <-first)
+	assert.Empty(t, other)
+}
+
+func TestHub_DisconnectsSlowWatchers(t *testing.T) {
+	h := newHub[string, int]()
+	changes, stop := h.watch("a", 0)
+	defer stop()
+
+	for i := 0; i <= watcherBuffer; i++ {
+		h.publish("a", i)
+	}
+
+	received := 0
+	for range changes {
+		received++
+	}
+	assert.Equal(t, watcherBuffer, received)
+}
+
+func TestHub_CloseKeyAndDisconnect(t *testing.T) {
+	h := newHub[string, int]()
+	shared, _ := h.watch("list", 0)
+	mine, _ := h.watch("pantry", 7)
+	theirs, stopTheirs := h.watch("pantry", 8)
+	defer stopTheirs()
+
+	h.closeKey("list")
+	h.disconnect(7)
+
+	_, open := <-shared
+	assert.False(t, open)
+	_, open = <-mine
+	assert.False(t, open)
+	h.publish("pantry", 1)
+	assert.Equal(t, 1, <-theirs)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+
+	"meal-prep/services/recipe-catalogue/domain"
+)
+
+const (
+	// MaxImageUploadBytes bounds an uploaded photo file.
+	MaxImageUploadBytes = 10 << 20
+
+	// maxImagePixels keeps decoding a small file from taking hundreds of
+	// megabytes; 24 megapixels covers phone and most camera photos.
+	maxImagePixels = 24_000_000
+
+	thumbnailSize    = 320 // longer side, in pixels
+	thumbnailQuality = 85
+
+	// thumbnailSamples is how many source pixels along each axis are
+	// averaged into a thumbnail pixel. Sampling a grid rather than every
+	// pixel keeps large photos fast to shrink.
+	thumbnailSamples = 4
+)
+
+type imageFormat struct {
+	extension   string
+	contentType string
+	decode      func(data []byte) (image.Image, error)
+}
+
+// imageFormats are the upload formats, by the name image.DecodeConfig
+// reports.
+var imageFormats = map[string]imageFormat{
+	"jpeg": {".jpg", "image/jpeg", func(data []byte) (image.Image, error) { return jpeg.Decode(bytes.NewReader(data)) }},
+	"png":  {".png", "image/png", func(data []byte) (image.Image, error) { return png.Decode(bytes.NewReader(data)) }},
+	"gif":  {".gif", "image/gif", func(data []byte) (image.Image, error) { return gif.Decode(bytes.NewReader(data)) }},
+}
+
+// storedContentType is the content type to serve a stored key with.
+func storedContentType(key string) (string, bool) {
+	for _, format := range imageFormats {
+		if strings.HasSuffix(key, format.extension) {
+			return format.contentType, true
+		}
+	}
+	return "", false
+}
+
+// decodeUpload checks an upload is a JPEG, PNG or GIF within the size
+// limits and decodes it. GIFs decode to their first frame.
+func decodeUpload(data []byte) (image.Image, imageFormat, error) {
+	if len(data) > MaxImageUploadBytes {
+		return nil, imageFormat{}, domain.ErrImageTooLarge
+	}
+
+	config, name, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, imageFormat{}, domain.ErrUnsupportedImage
+	}
+	format, ok := imageFormats[name]
+	if !ok {
+		return nil, imageFormat{}, domain.ErrUnsupportedImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, imageFormat{}, domain.ErrUnsupportedImage
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, imageFormat{}, domain.ErrImageTooLarge
+	}
+
+	img, err := format.decode(data)
+	if err != nil {
+		return nil, imageFormat{}, domain.ErrUnsupportedImage
+	}
+	return img, format, nil
+}
+
+// thumbnailJPEG shrinks img so its longer side is at most thumbnailSize
+// and encodes it as a JPEG. Smaller images keep their size. Transparent
+// areas turn white, as JPEG has no alpha.
+func thumbnailJPEG(img image.Image) ([]byte, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if longer := max(width, height); longer > thumbnailSize {
+		width = max(1, width*thumbnailSize/longer)
+		height = max(1, height*thumbnailSize/longer)
+	}
+
+	thumb := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			thumb.SetRGBA(x, y, averageArea(img, bounds,
+				x*bounds.Dx()/width, (x+1)*bounds.Dx()/width,
+				y*bounds.Dy()/height, (y+1)*bounds.Dy()/height))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// averageArea averages a grid of samples from the source pixels [x0, x1)
+// by [y0, y1), relative to bounds, over a white background.
+func averageArea(img image.Image, bounds image.Rectangle, x0, x1, y0, y1 int) color.RGBA {
+	x1, y1 = max(x1, x0+1), max(y1, y0+1)
+	stepX := max(1, (x1-x0)/thumbnailSamples)
+	stepY := max(1, (y1-y0)/thumbnailSamples)
+
+	var r, g, b, n uint64
+	for y := y0; y < y1; y += stepY {
+		for x := x0; x < x1; x += stepX {
+			cr, cg, cb, ca := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			// Colours are premultiplied, so adding the missing alpha
+			// composites them over white
+			r += uint64(cr + 0xffff - ca)
+			g += uint64(cg + 0xffff - ca)
+			b += uint64(cb + 0xffff - ca)
+			n++
+		}
+	}
+	return color.RGBA{R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(b / n >> 8), A: 0xff}
+}
+
+// thumbnailKey is where the thumbnail of the upload stored at key lives.
+func thumbnailKey(key string) string {
+	if i := strings.LastIndex(key, "."); i > strings.LastIndex(key, "/") {
+		key = key[:i]
+	}
+	return key + "-thumb.jpg"
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+)
+
+const (
+	defaultIngredientUnusedMonths    = 6
+	defaultIngredientCleanupInterval = 24 * time.Hour
+	maxIngredientCleanupIDs          = 500
+
+	// defaultDuplicateSimilarity catches plurals ("Egg", "Eggs") and most
+	// misspellings without pairing up every ingredient sharing a word
+	defaultDuplicateSimilarity = 0.5
+	defaultDuplicateLimit      = 50
+	maxDuplicateLimit          = 200
+)
+
+// IngredientUnusedMonthsFromEnv reads how many months an ingredient must go
+// unused before cleanup picks it up (INGREDIENT_UNUSED_MONTHS, default 6).
+func IngredientUnusedMonthsFromEnv() int {
+	if months, err := strconv.Atoi(os.Getenv("INGREDIENT_UNUSED_MONTHS")); err == nil && months > 0 {
+		return months
+	}
+	return defaultIngredientUnusedMonths
+}
+
+// IngredientCleanupIntervalFromEnv reads how often RunIngredientCleanup
+// archives unused ingredients (INGREDIENT_CLEANUP_INTERVAL, default 24h; 0
+// disables the job).
+func IngredientCleanupIntervalFromEnv() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("INGREDIENT_CLEANUP_INTERVAL")); err == nil && d >= 0 {
+		return d
+	}
+	return defaultIngredientCleanupInterval
+}
+
+type IngredientCleanupService interface {
+	// ListUnusedIngredients returns ingredients no recipe has used for
+	// months, or for the configured period when months is 0
+	ListUnusedIngredients(months int, params models.PaginationParams) ([]models.UnusedIngredient, models.PaginationMeta, error)
+	CleanupIngredients(months int, req models.IngredientCleanupRequest) (*models.IngredientCleanupResult, error)
+
+	// ArchiveUnusedIngredients archives every ingredient that has gone unused
+	// for the configured period as of now and returns their IDs
+	ArchiveUnusedIngredients(now time.Time) ([]int, error)
+
+	// FindDuplicateIngredients lists pairs of ingredients that look like one
+	// entered twice. 0 asks for the default minSimilarity or limit.
+	FindDuplicateIngredients(minSimilarity float64, limit int) ([]models.DuplicateIngredients, error)
+	// MergeIngredients folds the ingredient duplicateID into targetID, which
+	// every recipe using the duplicate uses from then on
+	MergeIngredients(ctx context.Context, duplicateID, targetID int) (*models.IngredientMergeResult, error)
+}
+
+type ingredientCleanupService struct {
+	cleanupRepo  repository.IngredientCleanupRepository
+	unusedMonths int
+}
+
+func NewIngredientCleanupService(cleanupRepo repository.IngredientCleanupRepository, unusedMonths int) IngredientCleanupService {
+	return &ingredientCleanupService{
+		cleanupRepo:  cleanupRepo,
+		unusedMonths: unusedMonths,
+	}
+}
+
+func (s *ingredientCleanupService) ListUnusedIngredients(months int, params models.PaginationParams) ([]models.UnusedIngredient, models.PaginationMeta, error) {
+	unusedSince, err := s.unusedSince(months, time.Now())
+	if err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+
+	ingredients, total, err := s.cleanupRepo.ListUnused(unusedSince, params)
+	if err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	return ingredients, models.NewPaginationMeta(params, total), nil
+}
+
+// CleanupIngredients only touches the listed ingredients that are still
+// unused, so one put back into a recipe since the admin looked is left alone.
+func (s *ingredientCleanupService) CleanupIngredients(months int, req models.IngredientCleanupRequest) (*models.IngredientCleanupResult, error) {
+	if req.Action != models.IngredientCleanupArchive && req.Action != models.IngredientCleanupDelete {
+		return nil, domain.ErrInvalidCleanupAction
+	}
+	if len(req.IngredientIDs) == 0 || len(req.IngredientIDs) > maxIngredientCleanupIDs {
+		return nil, domain.ErrCleanupIngredientsNeeded
+	}
+	unusedSince, err := s.unusedSince(months, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	if req.Action == models.IngredientCleanupArchive {
+		ids, err = s.cleanupRepo.Archive(req.IngredientIDs, unusedSince)
+	} else {
+		ids, err = s.cleanupRepo.Delete(req.IngredientIDs, unusedSince)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &models.IngredientCleanupResult{Action: req.Action, IngredientIDs: ids}, nil
+}
+
+func (s *ingredientCleanupService) ArchiveUnusedIngredients(now time.Time) ([]int, error) {
+	unusedSince, err := s.unusedSince(0, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.cleanupRepo.Archive(nil, unusedSince)
+}
+
+func (s *ingredientCleanupService) FindDuplicateIngredients(minSimilarity float64, limit int) ([]models.DuplicateIngredients, error) {
+	if minSimilarity == 0 {
+		minSimilarity = defaultDuplicateSimilarity
+	}
+	// pg_trgm's % operator finds no pairs below its own threshold
+	if minSimilarity < repository.SimilarityThreshold || minSimilarity > 1 {
+		return nil, domain.ErrInvalidMinSimilarity
+	}
+	if limit <= 0 {
+		limit = defaultDuplicateLimit
+	}
+	if limit > maxDuplicateLimit {
+		limit = maxDuplicateLimit
+	}
+	return s.cleanupRepo.ListDuplicates(minSimilarity, limit)
+}
+
+func (s *ingredientCleanupService) MergeIngredients(ctx context.Context, duplicateID, targetID int) (*models.IngredientMergeResult, error) {
+	if duplicateID <= 0 || targetID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+	if duplicateID == targetID {
+		return nil, domain.ErrMergeIntoItself
+	}
+
+	result, err := s.cleanupRepo.Merge(duplicateID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	logging.WithContext(ctx).Info("Ingredients merged", "merged_id", duplicateID, "ingredient_id", targetID,
+		"recipes", len(result.RecipeIDs))
+	return result, nil
+}
+
+func (s *ingredientCleanupService) unusedSince(months int, now time.Time) (time.Time, error) {
+	if months < 0 {
+		return time.Time{}, domain.ErrInvalidUnusedMonths
+	}
+	if months == 0 {
+		months = s.unusedMonths
+	}
+	return now.AddDate(0, -months, 0), nil
+}
+
+// RunIngredientCleanup archives unused ingredients every interval until ctx
+// is cancelled. Deleting stays a decision for an admin. A failed run is logged
+// and retried on the next tick.
+func RunIngredientCleanup(ctx context.Context, cleanup IngredientCleanupService, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			archived, err := cleanup.ArchiveUnusedIngredients(now)
+			if err != nil {
+				logging.Logger.Error("Ingredient cleanup run failed", "error", err)
+				continue
+			}
+			if len(archived) > 0 {
+				logging.Logger.Info("Unused ingredients archived", "ingredient_ids", archived)
+			}
+		}
+	}
+}
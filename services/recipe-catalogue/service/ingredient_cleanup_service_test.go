@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// monthsBefore matches a cutoff about months before now.
+func monthsBefore(months int) interface{} {
+	return mock.MatchedBy(func(cutoff time.Time) bool {
+		expected := time.Now().AddDate(0, -months, 0)
+		return cutoff.Sub(expected).Abs() < time.Minute
+	})
+}
+
+func TestIngredientCleanupService_ListUnusedIngredients(t *testing.T) {
+	cleanupRepo := new(mocks.MockIngredientCleanupRepository)
+	service := NewIngredientCleanupService(cleanupRepo, 6)
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+
+	cleanupRepo.On("ListUnused", monthsBefore(6), params).
+		Return([]models.UnusedIngredient{{Ingredient: models.Ingredient{ID: 4, Name: "Saffron"}}}, 1, nil).Once()
+	cleanupRepo.On("ListUnused", monthsBefore(12), params).
+		Return([]models.UnusedIngredient{}, 0, nil).Once()
+
+	ingredients, meta, err := service.ListUnusedIngredients(0, params)
+	require.NoError(t, err)
+	assert.Len(t, ingredients, 1)
+	assert.Equal(t, 1, meta.Total)
+
+	_, _, err = service.ListUnusedIngredients(12, params)
+	require.NoError(t, err)
+
+	_, _, err = service.ListUnusedIngredients(-1, params)
+	assert.Equal(t, domain.ErrInvalidUnusedMonths, err)
+	cleanupRepo.AssertExpectations(t)
+}
+
+func TestIngredientCleanupService_CleanupIngredients(t *testing.T) {
+	cleanupRepo := new(mocks.MockIngredientCleanupRepository)
+	service := NewIngredientCleanupService(cleanupRepo, 6)
+
+	cleanupRepo.On("Archive", []int{4, 5}, monthsBefore(6)).Return([]int{4}, nil)
+	cleanupRepo.On("Delete", []int{9}, monthsBefore(3)).Return([]int{9}, nil)
+
+	result, err := service.CleanupIngredients(0, models.IngredientCleanupRequest{
+		Action: models.IngredientCleanupArchive, IngredientIDs: []int{4, 5},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &models.IngredientCleanupResult{Action: "archive", IngredientIDs: []int{4}}, result)
+
+	result, err = service.CleanupIngredients(3, models.IngredientCleanupRequest{
+		Action: models.IngredientCleanupDelete, IngredientIDs: []int{9},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{9}, result.IngredientIDs)
+	cleanupRepo.AssertExpectations(t)
+}
+
+func TestIngredientCleanupService_CleanupIngredients_Invalid(t *testing.T) {
+	service := NewIngredientCleanupService(new(mocks.MockIngredientCleanupRepository), 6)
+
+	tests := []struct {
+		name     string
+		req      models.IngredientCleanupRequest
+		expected error
+	}{
+		{"unknown action", models.IngredientCleanupRequest{Action: "purge", IngredientIDs: []int{1}}, domain.ErrInvalidCleanupAction},
+		{"no ingredients", models.IngredientCleanupRequest{Action: "archive"}, domain.ErrCleanupIngredientsNeeded},
+		{"too many ingredients", models.IngredientCleanupRequest{Action: "delete", IngredientIDs: make([]int, 501)}, domain.ErrCleanupIngredientsNeeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CleanupIngredients(0, tt.req)
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestIngredientCleanupService_ArchiveUnusedIngredients(t *testing.T) {
+	cleanupRepo := new(mocks.MockIngredientCleanupRepository)
+	service := NewIngredientCleanupService(cleanupRepo, 6)
+	now := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+
+	cleanupRepo.On("Archive", []int(nil), time.Date(2026, 4, 16, 3, 0, 0, 0, time.UTC)).Return([]int{4, 9}, nil).Once()
+	cleanupRepo.On("Archive", []int(nil), mock.Anything).Return(nil, errors.New("db down")).Once()
+
+	archived, err := service.ArchiveUnusedIngredients(now)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 9}, archived)
+
+	_, err = service.ArchiveUnusedIngredients(now)
+	assert.Error(t, err)
+	cleanupRepo.AssertExpectations(t)
+}
+
+func TestIngredientCleanupService_FindDuplicateIngredients(t *testing.T) {
+	cleanupRepo := new(mocks.MockIngredientCleanupRepository)
+	service := NewIngredientCleanupService(cleanupRepo, 6)
+	pairs := []models.DuplicateIngredients{{
+		Ingredient: models.Ingredient{ID: 4, Name: "Egg"},
+		Duplicate:  models.Ingredient{ID: 9, Name: "Eggs"},
+		Similarity: 0.5,
+	}}
+	cleanupRepo.On("ListDuplicates", 0.5, 50).Return(pairs, nil).Once()
+	cleanupRepo.On("ListDuplicates", 0.8, 200).Return([]models.DuplicateIngredients{}, nil).Once()
+
+	duplicates, err := service.FindDuplicateIngredients(0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, pairs, duplicates)
+
+	_, err = service.FindDuplicateIngredients(0.8, 1000)
+	require.NoError(t, err)
+
+	for _, minSimilarity := range []float64{0.1, 1.5} {
+		_, err = service.FindDuplicateIngredients(minSimilarity, 0)
+		assert.Equal(t, domain.ErrInvalidMinSimilarity, err)
+	}
+	cleanupRepo.AssertExpectations(t)
+}
+
+func TestIngredientCleanupService_MergeIngredients(t *testing.T) {
+	logging.Init("test")
+	cleanupRepo := new(mocks.MockIngredientCleanupRepository)
+	service := NewIngredientCleanupService(cleanupRepo, 6)
+	merged := &models.IngredientMergeResult{Ingredient: models.Ingredient{ID: 4, Name: "Egg"}, MergedID: 9, RecipeIDs: []int{2, 7}}
+	cleanupRepo.On("Merge", 9, 4).Return(merged, nil)
+	cleanupRepo.On("Merge", 9, 5).Return(nil, domain.ErrIngredientNotFound)
+
+	result, err := service.MergeIngredients(context.Background(), 9, 4)
+	require.NoError(t, err)
+	assert.Equal(t, merged, result)
+
+	_, err = service.MergeIngredients(context.Background(), 9, 5)
+	assert.Equal(t, domain.ErrIngredientNotFound, err)
+
+	_, err = service.MergeIngredients(context.Background(), 4, 4)
+	assert.Equal(t, domain.ErrMergeIntoItself, err)
+	cleanupRepo.AssertNumberOfCalls(t, "Merge", 2)
+}
+
+func TestIngredientCleanupFromEnv(t *testing.T) {
+	t.Setenv("INGREDIENT_UNUSED_MONTHS", "")
+	t.Setenv("INGREDIENT_CLEANUP_INTERVAL", "")
+	assert.Equal(t, 6, IngredientUnusedMonthsFromEnv())
+	assert.Equal(t, 24*time.Hour, IngredientCleanupIntervalFromEnv())
+
+	t.Setenv("INGREDIENT_UNUSED_MONTHS", "12")
+	t.Setenv("INGREDIENT_CLEANUP_INTERVAL", "0")
+	assert.Equal(t, 12, IngredientUnusedMonthsFromEnv())
+	assert.Equal(t, time.Duration(0), IngredientCleanupIntervalFromEnv())
+
+	t.Setenv("INGREDIENT_UNUSED_MONTHS", "-2")
+	assert.Equal(t, 6, IngredientUnusedMonthsFromEnv())
+}
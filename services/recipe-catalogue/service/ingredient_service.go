@@ -1,12 +1,16 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"meal-prep/services/recipe-catalogue/domain"
 	"strings"
+	"unicode/utf8"
 
 	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/middleware"
 	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
 )
 
 type IngredientService interface {
@@ -14,6 +18,7 @@ type IngredientService interface {
 	GetIngredientByID(id int) (*models.Ingredient, error)
 	GetIngredientsByCategory(category string, params models.PaginationParams) ([]models.Ingredient, models.PaginationMeta, error)
 	SearchIngredients(query string, params models.PaginationParams) ([]models.Ingredient, models.PaginationMeta, error)
+	SuggestIngredients(query string) ([]string, error)
 	CreateIngredient(req models.CreateIngredientRequest) (*models.Ingredient, error)
 	UpdateIngredient(id int, req models.UpdateIngredientRequest) (*models.Ingredient, error)
 	DeleteIngredient(id int) error
@@ -25,6 +30,44 @@ type IngredientService interface {
 	SetRecipeIngredients(recipeID int, ingredients []models.AddRecipeIngredientRequest) error
 
 	GetRecipesUsingIngredient(ingredientID int, params models.PaginationParams) ([]models.Recipe, models.PaginationMeta, error)
+	GetPopularIngredients(limit int) ([]models.IngredientUsage, error)
+
+	GetIngredientDensity(ingredientID int) (*models.IngredientDensity, error)
+	ListIngredientDensities(params models.PaginationParams) ([]models.IngredientDensity, models.PaginationMeta, error)
+	SetIngredientDensity(ingredientID int, req models.SetIngredientDensityRequest) (*models.IngredientDensity, error)
+	DeleteIngredientDensity(ingredientID int) error
+
+	GetPackSizes(ingredientID int) ([]models.PackSize, error)
+	SetPackSizes(ingredientID int, packSizes []models.PackSizeRequest) ([]models.PackSize, error)
+
+	GetIngredientProducts(ingredientID int) ([]models.IngredientProduct, error)
+	CreateIngredientProduct(ingredientID int, req models.IngredientProductRequest) (*models.IngredientProduct, error)
+	UpdateIngredientProduct(ingredientID, productID int, req models.IngredientProductRequest) (*models.IngredientProduct, error)
+	DeleteIngredientProduct(ingredientID, productID int) error
+
+	GetSubstitutions(ingredientID int) ([]models.IngredientSubstitution, error)
+	SetSubstitutions(ingredientID int, substitutions []models.SubstitutionRequest) ([]models.IngredientSubstitution, error)
+
+	GetTranslations(ingredientID int) ([]models.IngredientTranslation, error)
+	SetTranslations(ingredientID int, translations []models.IngredientTranslation) ([]models.IngredientTranslation, error)
+	// GetLocalizedNames names ingredients in the first of locales that has a
+	// translation; ingredients missing from the map keep their English name
+	GetLocalizedNames(ingredientIDs []int, locales []string) (map[int]string, error)
+
+	GetIngredientDietary(ingredientID int) (*models.IngredientDietary, error)
+	SetIngredientDietary(ingredientID int, facts models.DietaryFacts) (*models.IngredientDietary, error)
+	GetRecipeDietary(recipeID int) (*models.RecipeDietary, error)
+	// FilterCompliance checks the user's own and published recipes against a
+	// diet profile
+	FilterCompliance(ctx context.Context, userID int, req models.ComplianceRequest) (*models.ComplianceResult, error)
+
+	GetAllergens() ([]models.Allergen, error)
+	GetIngredientAllergens(ingredientID int) ([]models.Allergen, error)
+	// SetIngredientAllergens, AddIngredientAllergen and RemoveIngredientAllergen
+	// take allergen codes and return the ingredient's allergens after the change
+	SetIngredientAllergens(ingredientID int, codes []string) ([]models.Allergen, error)
+	AddIngredientAllergen(ingredientID int, code string) ([]models.Allergen, error)
+	RemoveIngredientAllergen(ingredientID int, code string) error
 }
 
 type ingredientService struct {
@@ -89,6 +132,19 @@ func (s *ingredientService) SearchIngredients(query string, params models.Pagina
 	return ingredients, models.NewPaginationMeta(params, total), nil
 }
 
+// maxSearchSuggestions caps the "did you mean" names offered for a search.
+const maxSearchSuggestions = 5
+
+// SuggestIngredients offers ingredient names close to a search that found
+// nothing, such as "Garlic" for "garlik".
+func (s *ingredientService) SuggestIngredients(query string) ([]string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	return s.ingredientRepo.SuggestIngredientNames(query, maxSearchSuggestions)
+}
+
 func (s *ingredientService) CreateIngredient(req models.CreateIngredientRequest) (*models.Ingredient, error) {
 	// Validate input
 	req.Name = strings.TrimSpace(req.Name)
@@ -218,6 +274,9 @@ func (s *ingredientService) AddRecipeIngredient(recipeID int, req models.AddReci
 	if !exists {
 		return nil, domain.ErrIngredientNotFound
 	}
+	if err := ensureProductsMatch(s.ingredientRepo, []models.AddRecipeIngredientRequest{req}); err != nil {
+		return nil, err
+	}
 
 	return s.ingredientRepo.AddRecipeIngredient(recipeID, req)
 }
@@ -246,6 +305,9 @@ func (s *ingredientService) UpdateRecipeIngredient(recipeID, ingredientID int, r
 
 	// Update uses the ingredientID from the URL, not the request
 	req.IngredientID = ingredientID
+	if err := ensureProductsMatch(s.ingredientRepo, []models.AddRecipeIngredientRequest{req}); err != nil {
+		return nil, err
+	}
 
 	return s.ingredientRepo.UpdateRecipeIngredient(recipeID, ingredientID, req)
 }
@@ -297,15 +359,14 @@ func (s *ingredientService) SetRecipeIngredients(recipeID int, ingredients []mod
 		if err := s.validateRecipeIngredientRequest(ingredient); err != nil {
 			return err
 		}
+	}
 
-		// Verify ingredient exists
-		exists, err := s.ingredientRepo.IngredientExists(ingredient.IngredientID)
-		if err != nil {
-			return err
-		}
-		if !exists {
-			return domain.ErrIngredientNotFound
-		}
+	// Verify all ingredients exist in one round trip
+	if err := ensureIngredientsExist(s.ingredientRepo, recipeIngredientIDs(ingredients)); err != nil {
+		return err
+	}
+	if err := ensureProductsMatch(s.ingredientRepo, ingredients); err != nil {
+		return err
 	}
 
 	return s.ingredientRepo.SetRecipeIngredients(recipeID, ingredients)
@@ -332,6 +393,309 @@ func (s *ingredientService) GetRecipesUsingIngredient(ingredientID int, params m
 	return recipes, models.NewPaginationMeta(params, total), nil
 }
 
+const (
+	defaultPopularLimit = 10
+	maxPopularLimit     = 50
+)
+
+// GetPopularIngredients returns the ingredients used by the most recipes. An
+// out-of-range limit is clamped rather than rejected.
+func (s *ingredientService) GetPopularIngredients(limit int) ([]models.IngredientUsage, error) {
+	if limit <= 0 {
+		limit = defaultPopularLimit
+	}
+	if limit > maxPopularLimit {
+		limit = maxPopularLimit
+	}
+	return s.ingredientRepo.GetPopularIngredients(limit)
+}
+
+// maxGramsPerML is comfortably above the densest kitchen ingredient (salt is
+// about 1.2, honey 1.4); anything larger is almost certainly a typo.
+const maxGramsPerML = 25
+
+func (s *ingredientService) GetIngredientDensity(ingredientID int) (*models.IngredientDensity, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	density, err := s.ingredientRepo.GetDensity(ingredientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrDensityNotFound
+		}
+		return nil, err
+	}
+
+	return density, nil
+}
+
+func (s *ingredientService) ListIngredientDensities(params models.PaginationParams) ([]models.IngredientDensity, models.PaginationMeta, error) {
+	densities, total, err := s.ingredientRepo.ListDensities(params)
+	if err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	return densities, models.NewPaginationMeta(params, total), nil
+}
+
+func (s *ingredientService) SetIngredientDensity(ingredientID int, req models.SetIngredientDensityRequest) (*models.IngredientDensity, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+	if req.GramsPerML <= 0 || req.GramsPerML > maxGramsPerML {
+		return nil, domain.ErrInvalidDensity
+	}
+
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	if req.Source != nil {
+		source := strings.TrimSpace(*req.Source)
+		req.Source = &source
+		if source == "" {
+			req.Source = nil
+		}
+	}
+
+	return s.ingredientRepo.UpsertDensity(ingredientID, req)
+}
+
+func (s *ingredientService) DeleteIngredientDensity(ingredientID int) error {
+	if ingredientID <= 0 {
+		return domain.ErrIngredientNotFound
+	}
+
+	err := s.ingredientRepo.DeleteDensity(ingredientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrDensityNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *ingredientService) GetPackSizes(ingredientID int) ([]models.PackSize, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	return s.ingredientRepo.GetPackSizes(ingredientID)
+}
+
+// SetPackSizes replaces the ingredient's pack sizes and returns the stored set.
+func (s *ingredientService) SetPackSizes(ingredientID int, packSizes []models.PackSizeRequest) ([]models.PackSize, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	for i := range packSizes {
+		if packSizes[i].Quantity <= 0 {
+			return nil, domain.ErrInvalidQuantity
+		}
+		packSizes[i].Unit = strings.TrimSpace(packSizes[i].Unit)
+		if packSizes[i].Unit == "" {
+			return nil, domain.ErrInvalidUnit
+		}
+		if packSizes[i].Label != nil {
+			label := strings.TrimSpace(*packSizes[i].Label)
+			packSizes[i].Label = &label
+		}
+	}
+
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	if err := s.ingredientRepo.SetPackSizes(ingredientID, packSizes); err != nil {
+		return nil, err
+	}
+	return s.ingredientRepo.GetPackSizes(ingredientID)
+}
+
+func (s *ingredientService) GetIngredientProducts(ingredientID int) ([]models.IngredientProduct, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	return s.ingredientRepo.GetProducts(ingredientID)
+}
+
+func (s *ingredientService) CreateIngredientProduct(ingredientID int, req models.IngredientProductRequest) (*models.IngredientProduct, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	req, err := normalizeProductRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	return s.ingredientRepo.CreateProduct(ingredientID, req)
+}
+
+func (s *ingredientService) UpdateIngredientProduct(ingredientID, productID int, req models.IngredientProductRequest) (*models.IngredientProduct, error) {
+	if ingredientID <= 0 || productID <= 0 {
+		return nil, domain.ErrProductNotFound
+	}
+
+	req, err := normalizeProductRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.ingredientRepo.UpdateProduct(ingredientID, productID, req)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrProductNotFound
+	}
+	return product, err
+}
+
+// DeleteIngredientProduct removes a product; recipes that used it go back
+// to the generic ingredient.
+func (s *ingredientService) DeleteIngredientProduct(ingredientID, productID int) error {
+	if ingredientID <= 0 || productID <= 0 {
+		return domain.ErrProductNotFound
+	}
+
+	err := s.ingredientRepo.DeleteProduct(ingredientID, productID)
+	if err == sql.ErrNoRows {
+		return domain.ErrProductNotFound
+	}
+	return err
+}
+
+// normalizeProductRequest trims the brand and name and checks the nutrition
+// is plausible for 100 g of anything.
+func normalizeProductRequest(req models.IngredientProductRequest) (models.IngredientProductRequest, error) {
+	req.Brand = strings.TrimSpace(req.Brand)
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Brand == "" || req.Name == "" || utf8.RuneCountInString(req.Brand) > 100 || utf8.RuneCountInString(req.Name) > 100 {
+		return req, domain.ErrInvalidProduct
+	}
+
+	n := req.Nutrition
+	if n.CaloriesKcal < 0 || n.CaloriesKcal > 900 {
+		return req, domain.ErrInvalidNutrition
+	}
+	for _, grams := range []float64{n.ProteinG, n.CarbsG, n.FatG, n.FiberG} {
+		if grams < 0 || grams > 100 {
+			return req, domain.ErrInvalidNutrition
+		}
+	}
+	return req, nil
+}
+
+func (s *ingredientService) GetSubstitutions(ingredientID int) ([]models.IngredientSubstitution, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	return s.ingredientRepo.GetSubstitutions(ingredientID)
+}
+
+// maxSubstitutionRatio keeps obvious typos (100 cups of oil for a cup of
+// butter) out of the substitution table.
+const maxSubstitutionRatio = 100
+
+// SetSubstitutions replaces the ingredient's substitutes and returns the
+// stored set. A zero ratio means one for one.
+func (s *ingredientService) SetSubstitutions(ingredientID int, substitutions []models.SubstitutionRequest) ([]models.IngredientSubstitution, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	substituteIDs := make([]int, len(substitutions))
+	seen := make(map[int]bool, len(substitutions))
+	for i := range substitutions {
+		substitution := &substitutions[i]
+		if substitution.SubstituteID <= 0 || substitution.SubstituteID == ingredientID || seen[substitution.SubstituteID] {
+			return nil, domain.ErrInvalidSubstitution
+		}
+		seen[substitution.SubstituteID] = true
+		substituteIDs[i] = substitution.SubstituteID
+
+		if substitution.Ratio == 0 {
+			substitution.Ratio = 1
+		}
+		if substitution.Ratio < 0 || substitution.Ratio > maxSubstitutionRatio {
+			return nil, domain.ErrInvalidSubstitution
+		}
+		if substitution.Notes != nil {
+			notes := strings.TrimSpace(*substitution.Notes)
+			substitution.Notes = &notes
+			if notes == "" {
+				substitution.Notes = nil
+			}
+		}
+	}
+
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	if len(substituteIDs) > 0 {
+		missing, err := s.ingredientRepo.FindMissingIngredientIDs(substituteIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(missing) > 0 {
+			return nil, domain.ErrInvalidSubstitution
+		}
+	}
+
+	if err := s.ingredientRepo.SetSubstitutions(ingredientID, substitutions); err != nil {
+		return nil, err
+	}
+	return s.ingredientRepo.GetSubstitutions(ingredientID)
+}
+
 func (s *ingredientService) validateRecipeIngredientRequest(req models.AddRecipeIngredientRequest) error {
 	if req.IngredientID <= 0 {
 		return domain.ErrIngredientNotFound
@@ -353,3 +717,421 @@ func (s *ingredientService) validateRecipeIngredientRequest(req models.AddRecipe
 
 	return nil
 }
+
+// ensureIngredientsExist returns ErrIngredientNotFound if any of ids is unknown.
+func ensureIngredientsExist(repo repository.IngredientRepository, ids []int) error {
+	missing, err := repo.FindMissingIngredientIDs(ids)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return domain.ErrIngredientNotFound
+	}
+	return nil
+}
+
+// ensureProductsMatch returns ErrProductNotForIngredient if any of
+// ingredients names a product that isn't one of its ingredient's.
+func ensureProductsMatch(repo repository.IngredientRepository, ingredients []models.AddRecipeIngredientRequest) error {
+	var productIDs []int
+	for _, ingredient := range ingredients {
+		if ingredient.ProductID != nil {
+			productIDs = append(productIDs, *ingredient.ProductID)
+		}
+	}
+	if len(productIDs) == 0 {
+		return nil
+	}
+
+	products, err := repo.GetProductsByID(productIDs)
+	if err != nil {
+		return err
+	}
+	for _, ingredient := range ingredients {
+		if ingredient.ProductID == nil {
+			continue
+		}
+		if product, ok := products[*ingredient.ProductID]; !ok || product.IngredientID != ingredient.IngredientID {
+			return domain.ErrProductNotForIngredient
+		}
+	}
+	return nil
+}
+
+func recipeIngredientIDs(ingredients []models.AddRecipeIngredientRequest) []int {
+	ids := make([]int, len(ingredients))
+	for i, ingredient := range ingredients {
+		ids[i] = ingredient.IngredientID
+	}
+	return ids
+}
+
+func (s *ingredientService) GetTranslations(ingredientID int) ([]models.IngredientTranslation, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	return s.ingredientRepo.GetTranslations(ingredientID)
+}
+
+// maxTranslationLength matches the ingredient_translations.name column.
+const maxTranslationLength = 100
+
+// SetTranslations replaces the ingredient's translations and returns the
+// stored set. English is not a translation, it is the ingredient's own name.
+func (s *ingredientService) SetTranslations(ingredientID int, translations []models.IngredientTranslation) ([]models.IngredientTranslation, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	seen := make(map[string]bool, len(translations))
+	for i := range translations {
+		translation := &translations[i]
+		locale, ok := middleware.NormalizeLocale(translation.Locale)
+		if !ok {
+			return nil, domain.ErrInvalidLocale
+		}
+		translation.Locale = locale
+		translation.Name = strings.TrimSpace(translation.Name)
+		if isEnglish(locale) || seen[locale] || translation.Name == "" ||
+			utf8.RuneCountInString(translation.Name) > maxTranslationLength {
+			return nil, domain.ErrInvalidTranslation
+		}
+		seen[locale] = true
+	}
+
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	if err := s.ingredientRepo.SetTranslations(ingredientID, translations); err != nil {
+		return nil, err
+	}
+	return s.ingredientRepo.GetTranslations(ingredientID)
+}
+
+func (s *ingredientService) GetLocalizedNames(ingredientIDs []int, locales []string) (map[int]string, error) {
+	if len(ingredientIDs) == 0 || len(locales) == 0 {
+		return map[int]string{}, nil
+	}
+	return s.ingredientRepo.GetLocalizedNames(ingredientIDs, locales)
+}
+
+func (s *ingredientService) GetIngredientDietary(ingredientID int) (*models.IngredientDietary, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	ingredient, err := s.ingredientRepo.GetIngredientByID(ingredientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrIngredientNotFound
+		}
+		return nil, err
+	}
+
+	facts, err := s.ingredientRepo.GetDietaryFacts([]int{ingredientID})
+	if err != nil {
+		return nil, err
+	}
+	dietary := classifyIngredient(*ingredient, facts[ingredientID])
+	return &dietary, nil
+}
+
+// SetIngredientDietary replaces the ingredient's dietary flags and override
+// and returns its classification under them.
+func (s *ingredientService) SetIngredientDietary(ingredientID int, facts models.DietaryFacts) (*models.IngredientDietary, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	flags, err := normalizeDietaryFlags(facts.Flags)
+	if err != nil {
+		return nil, err
+	}
+	facts.Flags = flags
+
+	facts.Override = strings.ToLower(strings.TrimSpace(facts.Override))
+	if facts.Override != "" && !models.IsValidDiet(facts.Override) {
+		return nil, domain.ErrInvalidDiet
+	}
+
+	ingredient, err := s.ingredientRepo.GetIngredientByID(ingredientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrIngredientNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.ingredientRepo.SetDietaryFacts(ingredientID, facts); err != nil {
+		return nil, err
+	}
+	dietary := classifyIngredient(*ingredient, facts)
+	return &dietary, nil
+}
+
+// GetRecipeDietary classifies a published recipe from its ingredients.
+func (s *ingredientService) GetRecipeDietary(recipeID int) (*models.RecipeDietary, error) {
+	if recipeID <= 0 {
+		return nil, domain.ErrRecipeNotFound
+	}
+
+	recipe, err := s.recipeRepo.GetByID(recipeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRecipeNotFound
+		}
+		return nil, err
+	}
+	if !isPubliclyVisible(recipe) {
+		return nil, domain.ErrRecipeNotFound
+	}
+
+	recipeIngredients, err := s.ingredientRepo.GetRecipeIngredients(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(recipeIngredients))
+	for i, ri := range recipeIngredients {
+		ids[i] = ri.IngredientID
+	}
+	facts, err := s.ingredientRepo.GetDietaryFacts(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredients := make([]models.IngredientDietary, 0, len(recipeIngredients))
+	for _, ri := range recipeIngredients {
+		ingredients = append(ingredients, classifyIngredient(ri.Ingredient, facts[ri.IngredientID]))
+	}
+	dietary := classifyRecipe(recipeID, ingredients)
+	return &dietary, nil
+}
+
+// maxComplianceRecipes is enough for a month of three meals a day.
+const maxComplianceRecipes = 100
+
+// FilterCompliance checks each recipe the user can see against the profile,
+// in request order, skipping repeats.
+func (s *ingredientService) FilterCompliance(ctx context.Context, userID int, req models.ComplianceRequest) (*models.ComplianceResult, error) {
+	if len(req.RecipeIDs) == 0 || len(req.RecipeIDs) > maxComplianceRecipes {
+		return nil, domain.ErrComplianceRecipesNeeded
+	}
+	profile, err := normalizeDietProfile(req.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ComplianceResult{
+		Recipes:  make([]models.RecipeCompliance, 0, len(req.RecipeIDs)),
+		NotFound: make([]int, 0),
+	}
+	recipes := make([]models.Recipe, 0, len(req.RecipeIDs))
+	seen := make(map[int]bool, len(req.RecipeIDs))
+	for _, recipeID := range req.RecipeIDs {
+		if seen[recipeID] {
+			continue
+		}
+		seen[recipeID] = true
+
+		recipe, err := s.recipeRepo.GetByID(recipeID)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		if err == sql.ErrNoRows || policy.Authorize(ctx, policy.Read, recipeAccess(recipe)) != nil {
+			result.NotFound = append(result.NotFound, recipeID)
+			continue
+		}
+		recipes = append(recipes, *recipe)
+	}
+	if len(recipes) == 0 {
+		return result, nil
+	}
+
+	recipeIDs := make([]int, len(recipes))
+	for i, recipe := range recipes {
+		recipeIDs[i] = recipe.ID
+	}
+	ingredients, err := s.ingredientRepo.GetIngredientsForRecipes(recipeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredientIDs := make([]int, 0)
+	seenIngredients := make(map[int]bool)
+	for _, recipeID := range recipeIDs {
+		for _, ri := range ingredients[recipeID] {
+			if !seenIngredients[ri.IngredientID] {
+				seenIngredients[ri.IngredientID] = true
+				ingredientIDs = append(ingredientIDs, ri.IngredientID)
+			}
+		}
+	}
+	facts, err := s.ingredientRepo.GetDietaryFacts(ingredientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, recipe := range recipes {
+		result.Recipes = append(result.Recipes, checkRecipeCompliance(recipe, ingredients[recipe.ID], facts, profile))
+	}
+	return result, nil
+}
+
+// normalizeDietProfile defaults the diet to omnivore, which excludes
+// nothing, and checks the flags and confidence.
+func normalizeDietProfile(profile models.DietProfile) (models.DietProfile, error) {
+	profile.Diet = strings.ToLower(strings.TrimSpace(profile.Diet))
+	if profile.Diet == "" {
+		profile.Diet = models.DietOmnivore
+	}
+	if !models.IsValidDiet(profile.Diet) {
+		return profile, domain.ErrInvalidDiet
+	}
+
+	flags, err := normalizeDietaryFlags(profile.ExcludeFlags)
+	if err != nil {
+		return profile, err
+	}
+	profile.ExcludeFlags = flags
+
+	if profile.MinConfidence < 0 || profile.MinConfidence > 1 {
+		return profile, domain.ErrInvalidMinConfidence
+	}
+	return profile, nil
+}
+
+func (s *ingredientService) GetAllergens() ([]models.Allergen, error) {
+	return s.ingredientRepo.GetAllergens()
+}
+
+func (s *ingredientService) GetIngredientAllergens(ingredientID int) ([]models.Allergen, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	return s.ingredientRepo.GetIngredientAllergens(ingredientID)
+}
+
+func (s *ingredientService) SetIngredientAllergens(ingredientID int, codes []string) ([]models.Allergen, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	allergenIDs, err := s.allergenIDs(codes...)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	if err := s.ingredientRepo.SetIngredientAllergens(ingredientID, allergenIDs); err != nil {
+		return nil, err
+	}
+	return s.ingredientRepo.GetIngredientAllergens(ingredientID)
+}
+
+func (s *ingredientService) AddIngredientAllergen(ingredientID int, code string) ([]models.Allergen, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	allergenIDs, err := s.allergenIDs(code)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrIngredientNotFound
+	}
+
+	if err := s.ingredientRepo.AddIngredientAllergen(ingredientID, allergenIDs[0]); err != nil {
+		return nil, err
+	}
+	return s.ingredientRepo.GetIngredientAllergens(ingredientID)
+}
+
+// RemoveIngredientAllergen untags an allergen. An unknown code can't be
+// tagged, so it is reported the same way as one the ingredient doesn't have.
+func (s *ingredientService) RemoveIngredientAllergen(ingredientID int, code string) error {
+	if ingredientID <= 0 {
+		return domain.ErrIngredientNotFound
+	}
+
+	exists, err := s.ingredientRepo.IngredientExists(ingredientID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrIngredientNotFound
+	}
+	allergenIDs, err := s.allergenIDs(code)
+	if err == domain.ErrInvalidAllergen {
+		return domain.ErrIngredientAllergenNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.ingredientRepo.RemoveIngredientAllergen(ingredientID, allergenIDs[0])
+	if err == sql.ErrNoRows {
+		return domain.ErrIngredientAllergenNotFound
+	}
+	return err
+}
+
+// allergenIDs looks up allergens by their codes, ignoring case, and drops
+// repeats.
+func (s *ingredientService) allergenIDs(codes ...string) ([]int, error) {
+	allergens, err := s.ingredientRepo.GetAllergens()
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]int, len(allergens))
+	for _, allergen := range allergens {
+		byCode[allergen.Code] = allergen.ID
+	}
+
+	ids := make([]int, 0, len(codes))
+	seen := make(map[int]bool, len(codes))
+	for _, code := range codes {
+		id, ok := byCode[strings.ToLower(strings.TrimSpace(code))]
+		if !ok {
+			return nil, domain.ErrInvalidAllergen
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
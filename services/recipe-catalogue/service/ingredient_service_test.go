@@ -5,14 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"meal-prep/services/recipe-catalogue/domain"
+	"strings"
 	"testing"
 
 	"meal-prep/services/recipe-catalogue/service/mocks"
-	"meal-prep/services/recipe-catalogue/service/testdata"
 	"meal-prep/shared/models"
+	"meal-prep/shared/testing/factory"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type ingredientServiceTestSetup struct {
@@ -41,8 +43,8 @@ func setupIngredientServiceTest() *ingredientServiceTestSetup {
 func TestIngredientService_GetAllIngredients_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	expectedIngredients := []models.Ingredient{
-		testdata.NewIngredientBuilder().WithID(1).WithName("Tomato").Build(),
-		testdata.NewIngredientBuilder().WithID(2).WithName("Basil").Build(),
+		factory.NewIngredientBuilder().WithID(1).WithName("Tomato").Build(),
+		factory.NewIngredientBuilder().WithID(2).WithName("Basil").Build(),
 	}
 
 	setup.ingredientRepo.On("GetAllIngredients", defaultParams).Return(expectedIngredients, 2, nil)
@@ -76,7 +78,7 @@ func TestIngredientService_GetAllIngredients_RepositoryError(t *testing.T) {
 
 func TestIngredientService_GetIngredientByID_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
-	expectedIngredient := testdata.NewIngredientBuilder().WithID(1).BuildPtr()
+	expectedIngredient := factory.NewIngredientBuilder().WithID(1).BuildPtr()
 
 	setup.ingredientRepo.On("GetIngredientByID", 1).Return(expectedIngredient, nil)
 
@@ -126,7 +128,7 @@ func TestIngredientService_GetIngredientsByCategory_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	category := "Vegetable"
 	expectedIngredients := []models.Ingredient{
-		testdata.NewIngredientBuilder().WithCategory(category).Build(),
+		factory.NewIngredientBuilder().WithCategory(category).Build(),
 	}
 
 	setup.ingredientRepo.On("GetIngredientsByCategory", category, defaultParams).Return(expectedIngredients, 1, nil)
@@ -143,7 +145,7 @@ func TestIngredientService_GetIngredientsByCategory_Success(t *testing.T) {
 func TestIngredientService_GetIngredientsByCategory_EmptyCategory(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	expectedIngredients := []models.Ingredient{
-		testdata.NewIngredientBuilder().Build(),
+		factory.NewIngredientBuilder().Build(),
 	}
 
 	setup.ingredientRepo.On("GetAllIngredients", defaultParams).Return(expectedIngredients, 1, nil)
@@ -158,7 +160,7 @@ func TestIngredientService_GetIngredientsByCategory_EmptyCategory(t *testing.T)
 func TestIngredientService_GetIngredientsByCategory_WhitespaceCategory(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	expectedIngredients := []models.Ingredient{
-		testdata.NewIngredientBuilder().Build(),
+		factory.NewIngredientBuilder().Build(),
 	}
 
 	setup.ingredientRepo.On("GetAllIngredients", defaultParams).Return(expectedIngredients, 1, nil)
@@ -178,7 +180,7 @@ func TestIngredientService_SearchIngredients_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	query := "tomato"
 	expectedIngredients := []models.Ingredient{
-		testdata.NewIngredientBuilder().WithName("Cherry Tomato").Build(),
+		factory.NewIngredientBuilder().WithName("Cherry Tomato").Build(),
 	}
 
 	setup.ingredientRepo.On("SearchIngredients", query, defaultParams).Return(expectedIngredients, 1, nil)
@@ -191,10 +193,20 @@ func TestIngredientService_SearchIngredients_Success(t *testing.T) {
 	setup.ingredientRepo.AssertExpectations(t)
 }
 
+func TestIngredientService_SuggestIngredients(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("SuggestIngredientNames", "garlik", 5).Return([]string{"Garlic"}, nil)
+
+	result, err := setup.service.SuggestIngredients("  garlik ")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Garlic"}, result)
+}
+
 func TestIngredientService_SearchIngredients_EmptyQuery(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	expectedIngredients := []models.Ingredient{
-		testdata.NewIngredientBuilder().Build(),
+		factory.NewIngredientBuilder().Build(),
 	}
 
 	setup.ingredientRepo.On("GetAllIngredients", defaultParams).Return(expectedIngredients, 1, nil)
@@ -212,8 +224,8 @@ func TestIngredientService_SearchIngredients_EmptyQuery(t *testing.T) {
 
 func TestIngredientService_CreateIngredient_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
-	request := testdata.NewCreateIngredientRequestBuilder().WithName("New Ingredient").Build()
-	expectedIngredient := testdata.NewIngredientBuilder().WithName("New Ingredient").BuildPtr()
+	request := factory.NewCreateIngredientRequestBuilder().WithName("New Ingredient").Build()
+	expectedIngredient := factory.NewIngredientBuilder().WithName("New Ingredient").BuildPtr()
 
 	setup.ingredientRepo.On("CreateIngredient", mock.AnythingOfType("models.CreateIngredientRequest")).
 		Return(expectedIngredient, nil)
@@ -234,12 +246,12 @@ func TestIngredientService_CreateIngredient_ValidationErrors(t *testing.T) {
 	}{
 		{
 			name:          "empty_name",
-			request:       testdata.NewCreateIngredientRequestBuilder().WithName("").Build(),
+			request:       factory.NewCreateIngredientRequestBuilder().WithName("").Build(),
 			expectedError: domain.ErrIngredientNameRequired,
 		},
 		{
 			name:          "whitespace_name",
-			request:       testdata.NewCreateIngredientRequestBuilder().WithName("   ").Build(),
+			request:       factory.NewCreateIngredientRequestBuilder().WithName("   ").Build(),
 			expectedError: domain.ErrIngredientNameRequired,
 		},
 	}
@@ -260,7 +272,7 @@ func TestIngredientService_CreateIngredient_ValidationErrors(t *testing.T) {
 
 func TestIngredientService_CreateIngredient_RepositoryError(t *testing.T) {
 	setup := setupIngredientServiceTest()
-	request := testdata.NewCreateIngredientRequestBuilder().Build()
+	request := factory.NewCreateIngredientRequestBuilder().Build()
 	expectedError := errors.New("database error")
 
 	setup.ingredientRepo.On("CreateIngredient", mock.AnythingOfType("models.CreateIngredientRequest")).
@@ -280,9 +292,9 @@ func TestIngredientService_CreateIngredient_RepositoryError(t *testing.T) {
 func TestIngredientService_UpdateIngredient_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	ingredientID := 1
-	request := testdata.NewUpdateIngredientRequestBuilder().WithName("Updated Ingredient").Build()
-	existingIngredient := testdata.NewIngredientBuilder().WithID(ingredientID).BuildPtr()
-	updatedIngredient := testdata.NewIngredientBuilder().WithID(ingredientID).WithName("Updated Ingredient").BuildPtr()
+	request := factory.NewUpdateIngredientRequestBuilder().WithName("Updated Ingredient").Build()
+	existingIngredient := factory.NewIngredientBuilder().WithID(ingredientID).BuildPtr()
+	updatedIngredient := factory.NewIngredientBuilder().WithID(ingredientID).WithName("Updated Ingredient").BuildPtr()
 
 	setup.ingredientRepo.On("GetIngredientByID", ingredientID).Return(existingIngredient, nil)
 	setup.ingredientRepo.On("UpdateIngredient", ingredientID, mock.AnythingOfType("models.UpdateIngredientRequest")).
@@ -302,7 +314,7 @@ func TestIngredientService_UpdateIngredient_InvalidID(t *testing.T) {
 	for _, id := range invalidIDs {
 		t.Run(fmt.Sprintf("id_%d", id), func(t *testing.T) {
 			setup := setupIngredientServiceTest()
-			request := testdata.NewUpdateIngredientRequestBuilder().Build()
+			request := factory.NewUpdateIngredientRequestBuilder().Build()
 
 			result, err := setup.service.UpdateIngredient(id, request)
 
@@ -317,7 +329,7 @@ func TestIngredientService_UpdateIngredient_InvalidID(t *testing.T) {
 func TestIngredientService_UpdateIngredient_NotFound(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	ingredientID := 999
-	request := testdata.NewUpdateIngredientRequestBuilder().Build()
+	request := factory.NewUpdateIngredientRequestBuilder().Build()
 
 	setup.ingredientRepo.On("GetIngredientByID", ingredientID).Return(nil, sql.ErrNoRows)
 
@@ -332,7 +344,7 @@ func TestIngredientService_UpdateIngredient_NotFound(t *testing.T) {
 func TestIngredientService_UpdateIngredient_ValidationErrors(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	ingredientID := 1
-	existingIngredient := testdata.NewIngredientBuilder().WithID(ingredientID).BuildPtr()
+	existingIngredient := factory.NewIngredientBuilder().WithID(ingredientID).BuildPtr()
 
 	testCases := []struct {
 		name          string
@@ -376,7 +388,7 @@ func TestIngredientService_UpdateIngredient_ValidationErrors(t *testing.T) {
 func TestIngredientService_DeleteIngredient_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	ingredientID := 1
-	existingIngredient := testdata.NewIngredientBuilder().WithID(ingredientID).BuildPtr()
+	existingIngredient := factory.NewIngredientBuilder().WithID(ingredientID).BuildPtr()
 	checkParams := models.PaginationParams{Page: 1, PerPage: 1}
 
 	setup.ingredientRepo.On("GetIngredientByID", ingredientID).Return(existingIngredient, nil)
@@ -421,7 +433,7 @@ func TestIngredientService_DeleteIngredient_NotFound(t *testing.T) {
 func TestIngredientService_DeleteIngredient_UsedInRecipes(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	ingredientID := 1
-	existingIngredient := testdata.NewIngredientBuilder().WithID(ingredientID).BuildPtr()
+	existingIngredient := factory.NewIngredientBuilder().WithID(ingredientID).BuildPtr()
 	checkParams := models.PaginationParams{Page: 1, PerPage: 1}
 
 	setup.ingredientRepo.On("GetIngredientByID", ingredientID).Return(existingIngredient, nil)
@@ -442,9 +454,9 @@ func TestIngredientService_DeleteIngredient_UsedInRecipes(t *testing.T) {
 func TestIngredientService_GetRecipeIngredients_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	recipeID := 1
-	existingRecipe := testdata.NewRecipeBuilder().WithID(recipeID).BuildPtr()
+	existingRecipe := factory.NewRecipeBuilder().WithID(recipeID).BuildPtr()
 	expectedIngredients := []models.RecipeIngredient{
-		testdata.NewRecipeIngredientBuilder().WithRecipeID(recipeID).Build(),
+		factory.NewRecipeIngredientBuilder().WithRecipeID(recipeID).Build(),
 	}
 
 	setup.recipeRepo.On("GetByID", recipeID).Return(existingRecipe, nil)
@@ -491,9 +503,9 @@ func TestIngredientService_GetRecipeIngredients_RecipeNotFound(t *testing.T) {
 func TestIngredientService_AddRecipeIngredient_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	recipeID := 1
-	request := testdata.NewAddRecipeIngredientRequestBuilder().Build()
-	existingRecipe := testdata.NewRecipeBuilder().WithID(recipeID).BuildPtr()
-	expectedRecipeIngredient := testdata.NewRecipeIngredientBuilder().BuildPtr()
+	request := factory.NewAddRecipeIngredientRequestBuilder().Build()
+	existingRecipe := factory.NewRecipeBuilder().WithID(recipeID).BuildPtr()
+	expectedRecipeIngredient := factory.NewRecipeIngredientBuilder().BuildPtr()
 
 	setup.recipeRepo.On("GetByID", recipeID).Return(existingRecipe, nil)
 	setup.ingredientRepo.On("IngredientExists", request.IngredientID).Return(true, nil)
@@ -559,7 +571,7 @@ func TestIngredientService_AddRecipeIngredient_ValidationErrors(t *testing.T) {
 
 func TestIngredientService_AddRecipeIngredient_InvalidRecipeID(t *testing.T) {
 	setup := setupIngredientServiceTest()
-	request := testdata.NewAddRecipeIngredientRequestBuilder().Build()
+	request := factory.NewAddRecipeIngredientRequestBuilder().Build()
 
 	result, err := setup.service.AddRecipeIngredient(0, request)
 
@@ -572,8 +584,8 @@ func TestIngredientService_AddRecipeIngredient_InvalidRecipeID(t *testing.T) {
 func TestIngredientService_AddRecipeIngredient_IngredientNotFound(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	recipeID := 1
-	request := testdata.NewAddRecipeIngredientRequestBuilder().WithIngredientID(999).Build()
-	existingRecipe := testdata.NewRecipeBuilder().WithID(recipeID).BuildPtr()
+	request := factory.NewAddRecipeIngredientRequestBuilder().WithIngredientID(999).Build()
+	existingRecipe := factory.NewRecipeBuilder().WithID(recipeID).BuildPtr()
 
 	setup.recipeRepo.On("GetByID", recipeID).Return(existingRecipe, nil)
 	setup.ingredientRepo.On("IngredientExists", 999).Return(false, nil)
@@ -595,9 +607,9 @@ func TestIngredientService_UpdateRecipeIngredient_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	recipeID := 1
 	ingredientID := 1
-	request := testdata.NewAddRecipeIngredientRequestBuilder().WithQuantity(200.0).Build()
-	existingRecipe := testdata.NewRecipeBuilder().WithID(recipeID).BuildPtr()
-	expectedRecipeIngredient := testdata.NewRecipeIngredientBuilder().WithQuantity(200.0).BuildPtr()
+	request := factory.NewAddRecipeIngredientRequestBuilder().WithQuantity(200.0).Build()
+	existingRecipe := factory.NewRecipeBuilder().WithID(recipeID).BuildPtr()
+	expectedRecipeIngredient := factory.NewRecipeIngredientBuilder().WithQuantity(200.0).BuildPtr()
 
 	setup.recipeRepo.On("GetByID", recipeID).Return(existingRecipe, nil)
 	setup.ingredientRepo.On("UpdateRecipeIngredient", recipeID, ingredientID, mock.AnythingOfType("models.AddRecipeIngredientRequest")).
@@ -614,7 +626,7 @@ func TestIngredientService_UpdateRecipeIngredient_Success(t *testing.T) {
 
 func TestIngredientService_UpdateRecipeIngredient_InvalidIDs(t *testing.T) {
 	setup := setupIngredientServiceTest()
-	request := testdata.NewAddRecipeIngredientRequestBuilder().Build()
+	request := factory.NewAddRecipeIngredientRequestBuilder().Build()
 
 	testCases := []struct {
 		name         string
@@ -645,7 +657,7 @@ func TestIngredientService_RemoveRecipeIngredient_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	recipeID := 1
 	ingredientID := 1
-	existingRecipe := testdata.NewRecipeBuilder().WithID(recipeID).BuildPtr()
+	existingRecipe := factory.NewRecipeBuilder().WithID(recipeID).BuildPtr()
 
 	setup.recipeRepo.On("GetByID", recipeID).Return(existingRecipe, nil)
 	setup.ingredientRepo.On("RemoveRecipeIngredient", recipeID, ingredientID).Return(nil)
@@ -684,7 +696,7 @@ func TestIngredientService_RemoveRecipeIngredient_NotFound(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	recipeID := 1
 	ingredientID := 1
-	existingRecipe := testdata.NewRecipeBuilder().WithID(recipeID).BuildPtr()
+	existingRecipe := factory.NewRecipeBuilder().WithID(recipeID).BuildPtr()
 
 	setup.recipeRepo.On("GetByID", recipeID).Return(existingRecipe, nil)
 	setup.ingredientRepo.On("RemoveRecipeIngredient", recipeID, ingredientID).Return(sql.ErrNoRows)
@@ -705,14 +717,13 @@ func TestIngredientService_SetRecipeIngredients_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	recipeID := 1
 	ingredients := []models.AddRecipeIngredientRequest{
-		testdata.NewAddRecipeIngredientRequestBuilder().WithIngredientID(1).Build(),
-		testdata.NewAddRecipeIngredientRequestBuilder().WithIngredientID(2).Build(),
+		factory.NewAddRecipeIngredientRequestBuilder().WithIngredientID(1).Build(),
+		factory.NewAddRecipeIngredientRequestBuilder().WithIngredientID(2).Build(),
 	}
-	existingRecipe := testdata.NewRecipeBuilder().WithID(recipeID).BuildPtr()
+	existingRecipe := factory.NewRecipeBuilder().WithID(recipeID).BuildPtr()
 
 	setup.recipeRepo.On("GetByID", recipeID).Return(existingRecipe, nil)
-	setup.ingredientRepo.On("IngredientExists", 1).Return(true, nil)
-	setup.ingredientRepo.On("IngredientExists", 2).Return(true, nil)
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{1, 2}).Return([]int{}, nil)
 	setup.ingredientRepo.On("SetRecipeIngredients", recipeID, ingredients).Return(nil)
 
 	err := setup.service.SetRecipeIngredients(recipeID, ingredients)
@@ -725,7 +736,7 @@ func TestIngredientService_SetRecipeIngredients_Success(t *testing.T) {
 func TestIngredientService_SetRecipeIngredients_InvalidRecipeID(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	ingredients := []models.AddRecipeIngredientRequest{
-		testdata.NewAddRecipeIngredientRequestBuilder().Build(),
+		factory.NewAddRecipeIngredientRequestBuilder().Build(),
 	}
 
 	err := setup.service.SetRecipeIngredients(0, ingredients)
@@ -735,6 +746,58 @@ func TestIngredientService_SetRecipeIngredients_InvalidRecipeID(t *testing.T) {
 	setup.recipeRepo.AssertNotCalled(t, "GetByID")
 }
 
+func TestIngredientService_SetRecipeIngredients_IngredientNotFound(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	recipeID := 1
+	ingredients := []models.AddRecipeIngredientRequest{
+		factory.NewAddRecipeIngredientRequestBuilder().WithIngredientID(1).Build(),
+		factory.NewAddRecipeIngredientRequestBuilder().WithIngredientID(999).Build(),
+	}
+	existingRecipe := factory.NewRecipeBuilder().WithID(recipeID).BuildPtr()
+
+	setup.recipeRepo.On("GetByID", recipeID).Return(existingRecipe, nil)
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{1, 999}).Return([]int{999}, nil)
+
+	err := setup.service.SetRecipeIngredients(recipeID, ingredients)
+
+	assert.Equal(t, domain.ErrIngredientNotFound, err)
+	setup.ingredientRepo.AssertExpectations(t)
+	setup.ingredientRepo.AssertNotCalled(t, "SetRecipeIngredients")
+}
+
+// =============================================================================
+// POPULAR INGREDIENTS TESTS
+// =============================================================================
+
+func TestIngredientService_GetPopularIngredients_ClampsLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested int
+		expected  int
+	}{
+		{"default when unset", 0, 10},
+		{"default when negative", -3, 10},
+		{"within range", 25, 25},
+		{"capped at maximum", 500, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientServiceTest()
+			usage := []models.IngredientUsage{
+				{Ingredient: factory.NewIngredientBuilder().WithID(1).Build(), RecipeCount: 3},
+			}
+			setup.ingredientRepo.On("GetPopularIngredients", tt.expected).Return(usage, nil)
+
+			result, err := setup.service.GetPopularIngredients(tt.requested)
+
+			assert.NoError(t, err)
+			assert.Equal(t, usage, result)
+			setup.ingredientRepo.AssertExpectations(t)
+		})
+	}
+}
+
 // =============================================================================
 // GET RECIPES USING INGREDIENT TESTS
 // =============================================================================
@@ -742,9 +805,9 @@ func TestIngredientService_SetRecipeIngredients_InvalidRecipeID(t *testing.T) {
 func TestIngredientService_GetRecipesUsingIngredient_Success(t *testing.T) {
 	setup := setupIngredientServiceTest()
 	ingredientID := 1
-	existingIngredient := testdata.NewIngredientBuilder().WithID(ingredientID).BuildPtr()
+	existingIngredient := factory.NewIngredientBuilder().WithID(ingredientID).BuildPtr()
 	expectedRecipes := []models.Recipe{
-		testdata.NewRecipeBuilder().WithID(1).Build(),
+		factory.NewRecipeBuilder().WithID(1).Build(),
 	}
 
 	setup.ingredientRepo.On("GetIngredientByID", ingredientID).Return(existingIngredient, nil)
@@ -782,3 +845,515 @@ func TestIngredientService_GetRecipesUsingIngredient_IngredientNotFound(t *testi
 	assert.Equal(t, domain.ErrIngredientNotFound, err)
 	setup.ingredientRepo.AssertExpectations(t)
 }
+
+// =============================================================================
+// INGREDIENT DENSITY TESTS
+// =============================================================================
+
+func TestIngredientService_SetIngredientDensity_Success(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	source := "  USDA  "
+	expected := &models.IngredientDensity{IngredientID: 4, GramsPerML: 0.85}
+
+	setup.ingredientRepo.On("IngredientExists", 4).Return(true, nil)
+	setup.ingredientRepo.On("UpsertDensity", 4, mock.MatchedBy(func(req models.SetIngredientDensityRequest) bool {
+		return req.GramsPerML == 0.85 && req.Source != nil && *req.Source == "USDA"
+	})).Return(expected, nil)
+
+	result, err := setup.service.SetIngredientDensity(4, models.SetIngredientDensityRequest{GramsPerML: 0.85, Source: &source})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	setup.ingredientRepo.AssertExpectations(t)
+}
+
+func TestIngredientService_SetIngredientDensity_RejectsImplausibleValues(t *testing.T) {
+	for _, gramsPerML := range []float64{0, -0.5, 26} {
+		t.Run(fmt.Sprintf("%v", gramsPerML), func(t *testing.T) {
+			setup := setupIngredientServiceTest()
+
+			result, err := setup.service.SetIngredientDensity(4, models.SetIngredientDensityRequest{GramsPerML: gramsPerML})
+
+			assert.Nil(t, result)
+			assert.Equal(t, domain.ErrInvalidDensity, err)
+			setup.ingredientRepo.AssertNotCalled(t, "UpsertDensity", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestIngredientService_SetIngredientDensity_IngredientNotFound(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("IngredientExists", 999).Return(false, nil)
+
+	result, err := setup.service.SetIngredientDensity(999, models.SetIngredientDensityRequest{GramsPerML: 1})
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrIngredientNotFound, err)
+	setup.ingredientRepo.AssertExpectations(t)
+}
+
+func TestIngredientService_GetIngredientDensity_MapsMissingRow(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("GetDensity", 5).Return(nil, sql.ErrNoRows)
+
+	result, err := setup.service.GetIngredientDensity(5)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrDensityNotFound, err)
+}
+
+func TestIngredientService_DeleteIngredientDensity_MapsMissingRow(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("DeleteDensity", 5).Return(sql.ErrNoRows)
+
+	err := setup.service.DeleteIngredientDensity(5)
+
+	assert.Equal(t, domain.ErrDensityNotFound, err)
+}
+
+// =============================================================================
+// PACK SIZE TESTS
+// =============================================================================
+
+func TestIngredientService_SetPackSizes_Success(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	label := " can "
+	request := []models.PackSizeRequest{{Quantity: 400, Unit: " g ", Label: &label}}
+	stored := []models.PackSize{{ID: 1, IngredientID: 3, Quantity: 400, Unit: "g"}}
+
+	setup.ingredientRepo.On("IngredientExists", 3).Return(true, nil)
+	setup.ingredientRepo.On("SetPackSizes", 3, mock.MatchedBy(func(sizes []models.PackSizeRequest) bool {
+		return len(sizes) == 1 && sizes[0].Unit == "g" && *sizes[0].Label == "can"
+	})).Return(nil)
+	setup.ingredientRepo.On("GetPackSizes", 3).Return(stored, nil)
+
+	result, err := setup.service.SetPackSizes(3, request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, stored, result)
+	setup.ingredientRepo.AssertExpectations(t)
+}
+
+func TestIngredientService_SetPackSizes_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		packSizes []models.PackSizeRequest
+		expected  error
+	}{
+		{"zero quantity", []models.PackSizeRequest{{Quantity: 0, Unit: "g"}}, domain.ErrInvalidQuantity},
+		{"blank unit", []models.PackSizeRequest{{Quantity: 400, Unit: "  "}}, domain.ErrInvalidUnit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientServiceTest()
+
+			result, err := setup.service.SetPackSizes(3, tt.packSizes)
+
+			assert.Nil(t, result)
+			assert.Equal(t, tt.expected, err)
+			setup.ingredientRepo.AssertNotCalled(t, "SetPackSizes", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestIngredientService_GetPackSizes_IngredientNotFound(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("IngredientExists", 999).Return(false, nil)
+
+	result, err := setup.service.GetPackSizes(999)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrIngredientNotFound, err)
+}
+
+func TestIngredientService_SetSubstitutions_DefaultsRatio(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	notes := "  "
+	request := []models.SubstitutionRequest{{SubstituteID: 7, Notes: &notes}, {SubstituteID: 8, Ratio: 0.75}}
+	stored := []models.IngredientSubstitution{{IngredientID: 3, SubstituteID: 7, Ratio: 1}}
+
+	setup.ingredientRepo.On("IngredientExists", 3).Return(true, nil)
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{7, 8}).Return([]int{}, nil)
+	setup.ingredientRepo.On("SetSubstitutions", 3, []models.SubstitutionRequest{
+		{SubstituteID: 7, Ratio: 1}, {SubstituteID: 8, Ratio: 0.75},
+	}).Return(nil)
+	setup.ingredientRepo.On("GetSubstitutions", 3).Return(stored, nil)
+
+	result, err := setup.service.SetSubstitutions(3, request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, stored, result)
+	setup.ingredientRepo.AssertExpectations(t)
+}
+
+func TestIngredientService_SetSubstitutions_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		substitutions []models.SubstitutionRequest
+	}{
+		{"itself", []models.SubstitutionRequest{{SubstituteID: 3}}},
+		{"listed twice", []models.SubstitutionRequest{{SubstituteID: 7}, {SubstituteID: 7}}},
+		{"negative ratio", []models.SubstitutionRequest{{SubstituteID: 7, Ratio: -1}}},
+		{"ratio too large", []models.SubstitutionRequest{{SubstituteID: 7, Ratio: 250}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientServiceTest()
+
+			result, err := setup.service.SetSubstitutions(3, tt.substitutions)
+
+			assert.Nil(t, result)
+			assert.Equal(t, domain.ErrInvalidSubstitution, err)
+			setup.ingredientRepo.AssertNotCalled(t, "SetSubstitutions", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestIngredientService_SetSubstitutions_UnknownSubstitute(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("IngredientExists", 3).Return(true, nil)
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{99}).Return([]int{99}, nil)
+
+	result, err := setup.service.SetSubstitutions(3, []models.SubstitutionRequest{{SubstituteID: 99}})
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrInvalidSubstitution, err)
+}
+
+func TestIngredientService_SetTranslations_NormalizesLocales(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	stored := []models.IngredientTranslation{{Locale: "pt-br", Name: "Tomate"}, {Locale: "ru", Name: "Помидор"}}
+	setup.ingredientRepo.On("IngredientExists", 10).Return(true, nil)
+	setup.ingredientRepo.On("SetTranslations", 10, []models.IngredientTranslation{
+		{Locale: "ru", Name: "Помидор"}, {Locale: "pt-br", Name: "Tomate"},
+	}).Return(nil)
+	setup.ingredientRepo.On("GetTranslations", 10).Return(stored, nil)
+
+	result, err := setup.service.SetTranslations(10, []models.IngredientTranslation{
+		{Locale: "RU", Name: " Помидор "}, {Locale: "pt_BR", Name: "Tomate"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, stored, result)
+}
+
+func TestIngredientService_SetTranslations_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		translations []models.IngredientTranslation
+		wantErr      error
+	}{
+		{"malformed locale", []models.IngredientTranslation{{Locale: "russian!", Name: "Помидор"}}, domain.ErrInvalidLocale},
+		{"english", []models.IngredientTranslation{{Locale: "en-GB", Name: "Tomato"}}, domain.ErrInvalidTranslation},
+		{"listed twice", []models.IngredientTranslation{{Locale: "de", Name: "Tomate"}, {Locale: "DE", Name: "Paradeiser"}}, domain.ErrInvalidTranslation},
+		{"blank name", []models.IngredientTranslation{{Locale: "de", Name: "  "}}, domain.ErrInvalidTranslation},
+		{"name too long", []models.IngredientTranslation{{Locale: "de", Name: strings.Repeat("ä", 101)}}, domain.ErrInvalidTranslation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientServiceTest()
+
+			result, err := setup.service.SetTranslations(10, tt.translations)
+
+			assert.Nil(t, result)
+			assert.Equal(t, tt.wantErr, err)
+			setup.ingredientRepo.AssertNotCalled(t, "SetTranslations", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestIngredientService_GetTranslations_NotFound(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("IngredientExists", 99).Return(false, nil)
+
+	result, err := setup.service.GetTranslations(99)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrIngredientNotFound, err)
+}
+
+func TestIngredientService_GetLocalizedNames_NoLocales(t *testing.T) {
+	setup := setupIngredientServiceTest()
+
+	names, err := setup.service.GetLocalizedNames([]int{1, 2}, nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, names)
+	setup.ingredientRepo.AssertNotCalled(t, "GetLocalizedNames", mock.Anything, mock.Anything)
+}
+
+func TestIngredientService_SetIngredientDietary_NormalizesFlags(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	dairy := "Dairy"
+	setup.ingredientRepo.On("GetIngredientByID", 8).Return(&models.Ingredient{ID: 8, Name: "Parmesan Cheese", Category: &dairy}, nil)
+	setup.ingredientRepo.On("SetDietaryFacts", 8, models.DietaryFacts{Flags: []string{"dairy", "plant_based"}, Override: "omnivore"}).Return(nil)
+
+	result, err := setup.service.SetIngredientDietary(8, models.DietaryFacts{
+		Flags:    []string{" Plant_Based", "dairy", "DAIRY"},
+		Override: "Omnivore",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.DietOmnivore, result.Diet)
+	assert.Equal(t, models.DietSourceOverride, result.Source)
+	assert.Equal(t, []string{"dairy", "plant_based"}, result.Flags)
+}
+
+func TestIngredientService_SetIngredientDietary_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		facts   models.DietaryFacts
+		wantErr error
+	}{
+		{"unknown flag", models.DietaryFacts{Flags: []string{"lactose"}}, domain.ErrInvalidDietaryFlag},
+		{"unknown diet", models.DietaryFacts{Override: "keto"}, domain.ErrInvalidDiet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientServiceTest()
+
+			result, err := setup.service.SetIngredientDietary(8, tt.facts)
+
+			assert.Nil(t, result)
+			assert.Equal(t, tt.wantErr, err)
+			setup.ingredientRepo.AssertNotCalled(t, "SetDietaryFacts", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestIngredientService_SetIngredientDietary_NotFound(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("GetIngredientByID", 99).Return(nil, sql.ErrNoRows)
+
+	result, err := setup.service.SetIngredientDietary(99, models.DietaryFacts{})
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrIngredientNotFound, err)
+}
+
+func TestIngredientService_GetRecipeDietary(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	vegetables, dairy := "Vegetables", "Dairy"
+	setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, Status: models.RecipeStatusPublished}, nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return([]models.RecipeIngredient{
+		{IngredientID: 5, Ingredient: models.Ingredient{ID: 5, Name: "Onion", Category: &vegetables}},
+		{IngredientID: 12, Ingredient: models.Ingredient{ID: 12, Name: "Eggs", Category: &dairy}},
+	}, nil)
+	setup.ingredientRepo.On("GetDietaryFacts", []int{5, 12}).
+		Return(map[int]models.DietaryFacts{12: {Flags: []string{"egg"}}}, nil)
+
+	result, err := setup.service.GetRecipeDietary(1)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.DietVegetarian, result.Diet)
+	assert.Equal(t, 0.9, result.Confidence)
+	require.Len(t, result.Ingredients, 2)
+	assert.Equal(t, models.DietSourceCategory, result.Ingredients[0].Source)
+	assert.Equal(t, models.DietSourceFlags, result.Ingredients[1].Source)
+}
+
+func TestIngredientService_GetRecipeDietary_HidesDrafts(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, Status: models.RecipeStatusDraft}, nil)
+
+	result, err := setup.service.GetRecipeDietary(1)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+	setup.ingredientRepo.AssertNotCalled(t, "GetRecipeIngredients", mock.Anything)
+}
+
+func TestIngredientService_FilterCompliance(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	vegetables, meat := "Vegetables", "Meat"
+	setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, Name: "Salad", Status: models.RecipeStatusPublished}, nil)
+	setup.recipeRepo.On("GetByID", 2).Return(&models.Recipe{ID: 2, UserID: 5, Name: "Stew", Status: models.RecipeStatusDraft}, nil)
+	setup.recipeRepo.On("GetByID", 3).Return(&models.Recipe{ID: 3, UserID: 9, Status: models.RecipeStatusDraft}, nil)
+	setup.recipeRepo.On("GetByID", 4).Return(nil, sql.ErrNoRows)
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2}).Return(map[int][]models.RecipeIngredient{
+		1: {{IngredientID: 5, Ingredient: models.Ingredient{ID: 5, Name: "Lettuce", Category: &vegetables}}},
+		2: {
+			{IngredientID: 5, Ingredient: models.Ingredient{ID: 5, Name: "Lettuce", Category: &vegetables}},
+			{IngredientID: 6, Ingredient: models.Ingredient{ID: 6, Name: "Beef", Category: &meat}},
+		},
+	}, nil)
+	setup.ingredientRepo.On("GetDietaryFacts", []int{5, 6}).Return(map[int]models.DietaryFacts{}, nil)
+
+	// The user's own draft can be checked, other drafts can't
+	result, err := setup.service.FilterCompliance(asUser(5), 5, models.ComplianceRequest{
+		Profile:   models.DietProfile{Diet: " Vegetarian"},
+		RecipeIDs: []int{1, 2, 3, 4, 1},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Recipes, 2)
+	assert.True(t, result.Recipes[0].Passes)
+	assert.False(t, result.Recipes[1].Passes)
+	require.Len(t, result.Recipes[1].Violations, 1)
+	assert.Equal(t, "Beef", result.Recipes[1].Violations[0].Name)
+	assert.Equal(t, []int{3, 4}, result.NotFound)
+}
+
+func TestIngredientService_FilterCompliance_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.ComplianceRequest
+		wantErr error
+	}{
+		{"no recipes", models.ComplianceRequest{}, domain.ErrComplianceRecipesNeeded},
+		{"too many recipes", models.ComplianceRequest{RecipeIDs: make([]int, 101)}, domain.ErrComplianceRecipesNeeded},
+		{"unknown diet", models.ComplianceRequest{Profile: models.DietProfile{Diet: "keto"}, RecipeIDs: []int{1}}, domain.ErrInvalidDiet},
+		{"unknown flag", models.ComplianceRequest{Profile: models.DietProfile{ExcludeFlags: []string{"lactose"}}, RecipeIDs: []int{1}},
+			domain.ErrInvalidDietaryFlag},
+		{"confidence above 1", models.ComplianceRequest{Profile: models.DietProfile{MinConfidence: 1.5}, RecipeIDs: []int{1}},
+			domain.ErrInvalidMinConfidence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientServiceTest()
+
+			result, err := setup.service.FilterCompliance(asUser(5), 5, tt.req)
+
+			assert.Nil(t, result)
+			assert.Equal(t, tt.wantErr, err)
+			setup.recipeRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+		})
+	}
+}
+
+func testAllergens() []models.Allergen {
+	return []models.Allergen{
+		{ID: 2, Code: "dairy", Name: "Milk"},
+		{ID: 9, Code: "soy", Name: "Soy"},
+	}
+}
+
+func TestIngredientService_SetIngredientAllergens_NormalizesCodes(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("GetAllergens").Return(testAllergens(), nil)
+	setup.ingredientRepo.On("IngredientExists", 8).Return(true, nil)
+	setup.ingredientRepo.On("SetIngredientAllergens", 8, []int{9, 2}).Return(nil)
+	setup.ingredientRepo.On("GetIngredientAllergens", 8).Return(testAllergens(), nil)
+
+	result, err := setup.service.SetIngredientAllergens(8, []string{" SOY", "dairy", "soy"})
+
+	require.NoError(t, err)
+	assert.Equal(t, testAllergens(), result)
+	setup.ingredientRepo.AssertExpectations(t)
+}
+
+func TestIngredientService_SetIngredientAllergens_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		codes   []string
+		exists  bool
+		wantErr error
+	}{
+		{"unknown allergen", 8, []string{"dairy", "lactose"}, true, domain.ErrInvalidAllergen},
+		{"unknown ingredient", 99, []string{"dairy"}, false, domain.ErrIngredientNotFound},
+		{"invalid ingredient id", 0, []string{"dairy"}, false, domain.ErrIngredientNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientServiceTest()
+			setup.ingredientRepo.On("GetAllergens").Return(testAllergens(), nil).Maybe()
+			setup.ingredientRepo.On("IngredientExists", tt.id).Return(tt.exists, nil).Maybe()
+
+			result, err := setup.service.SetIngredientAllergens(tt.id, tt.codes)
+
+			assert.Nil(t, result)
+			assert.Equal(t, tt.wantErr, err)
+			setup.ingredientRepo.AssertNotCalled(t, "SetIngredientAllergens", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestIngredientService_AddIngredientAllergen(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("GetAllergens").Return(testAllergens(), nil)
+	setup.ingredientRepo.On("IngredientExists", 8).Return(true, nil)
+	setup.ingredientRepo.On("AddIngredientAllergen", 8, 9).Return(nil)
+	setup.ingredientRepo.On("GetIngredientAllergens", 8).Return(testAllergens(), nil)
+
+	result, err := setup.service.AddIngredientAllergen(8, "Soy")
+
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+	setup.ingredientRepo.AssertExpectations(t)
+}
+
+func TestIngredientService_RemoveIngredientAllergen_NotTagged(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("GetAllergens").Return(testAllergens(), nil)
+	setup.ingredientRepo.On("IngredientExists", 8).Return(true, nil)
+	setup.ingredientRepo.On("RemoveIngredientAllergen", 8, 2).Return(sql.ErrNoRows)
+
+	assert.Equal(t, domain.ErrIngredientAllergenNotFound, setup.service.RemoveIngredientAllergen(8, "dairy"))
+	// An unknown code can't be tagged either
+	assert.Equal(t, domain.ErrIngredientAllergenNotFound, setup.service.RemoveIngredientAllergen(8, "lactose"))
+}
+
+func TestIngredientService_AddRecipeIngredient_ProductOfAnotherIngredient(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	productID := 7
+	request := models.AddRecipeIngredientRequest{IngredientID: 4, Quantity: 100, Unit: "g", ProductID: &productID}
+	setup.recipeRepo.On("GetByID", 1).Return(factory.NewRecipeBuilder().WithID(1).BuildPtr(), nil)
+	setup.ingredientRepo.On("IngredientExists", 4).Return(true, nil)
+	setup.ingredientRepo.On("GetProductsByID", []int{7}).
+		Return(map[int]models.IngredientProduct{7: {ID: 7, IngredientID: 5, Brand: "Barilla", Name: "Spaghetti"}}, nil)
+
+	result, err := setup.service.AddRecipeIngredient(1, request)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrProductNotForIngredient, err)
+	setup.ingredientRepo.AssertNotCalled(t, "AddRecipeIngredient", mock.Anything, mock.Anything)
+}
+
+func TestIngredientService_CreateIngredientProduct_TrimsAndValidates(t *testing.T) {
+	nutrition := models.ProductNutrition{CaloriesKcal: 359, ProteinG: 12.5, CarbsG: 71.2, FatG: 2, FiberG: 3}
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("IngredientExists", 5).Return(true, nil)
+	setup.ingredientRepo.On("CreateProduct", 5, models.IngredientProductRequest{Brand: "Barilla", Name: "Spaghetti", Nutrition: nutrition}).
+		Return(&models.IngredientProduct{ID: 1, IngredientID: 5, Brand: "Barilla", Name: "Spaghetti", Nutrition: nutrition}, nil)
+
+	result, err := setup.service.CreateIngredientProduct(5, models.IngredientProductRequest{Brand: " Barilla ", Name: "Spaghetti ", Nutrition: nutrition})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ID)
+
+	tests := []struct {
+		name    string
+		req     models.IngredientProductRequest
+		wantErr error
+	}{
+		{"missing brand", models.IngredientProductRequest{Name: "Spaghetti", Nutrition: nutrition}, domain.ErrInvalidProduct},
+		{"negative fat", models.IngredientProductRequest{Brand: "Barilla", Name: "Spaghetti", Nutrition: models.ProductNutrition{FatG: -1}}, domain.ErrInvalidNutrition},
+		{"over 100 g of protein", models.IngredientProductRequest{Brand: "Barilla", Name: "Spaghetti", Nutrition: models.ProductNutrition{ProteinG: 120}}, domain.ErrInvalidNutrition},
+		{"over 900 kcal", models.IngredientProductRequest{Brand: "Barilla", Name: "Spaghetti", Nutrition: models.ProductNutrition{CaloriesKcal: 1000}}, domain.ErrInvalidNutrition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupIngredientServiceTest()
+
+			result, err := setup.service.CreateIngredientProduct(5, tt.req)
+
+			assert.Nil(t, result)
+			assert.Equal(t, tt.wantErr, err)
+			setup.ingredientRepo.AssertNotCalled(t, "CreateProduct", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestIngredientService_DeleteIngredientProduct_NotFound(t *testing.T) {
+	setup := setupIngredientServiceTest()
+	setup.ingredientRepo.On("DeleteProduct", 5, 9).Return(sql.ErrNoRows)
+
+	assert.Equal(t, domain.ErrProductNotFound, setup.service.DeleteIngredientProduct(5, 9))
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+)
+
+type LineageService interface {
+	ForkRecipe(ctx context.Context, userID, recipeID int, req models.ForkRecipeRequest) (*models.RecipeWithIngredients, error)
+	GetLineage(recipeID int) (*models.RecipeLineage, error)
+	GetForks(recipeID int) ([]models.RecipeFork, error)
+}
+
+type lineageService struct {
+	lineageRepo  repository.LineageRepository
+	recipeRepo   repository.RecipeRepository
+	stepRepo     repository.StepRepository
+	categoryRepo repository.CategoryRepository
+}
+
+func NewLineageService(lineageRepo repository.LineageRepository, recipeRepo repository.RecipeRepository, stepRepo repository.StepRepository, categoryRepo repository.CategoryRepository) LineageService {
+	return &lineageService{
+		lineageRepo:  lineageRepo,
+		recipeRepo:   recipeRepo,
+		stepRepo:     stepRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// ForkRecipe copies a recipe the user can see, with its ingredients and
+// steps, into a new draft owned by the user and records where it came from.
+func (s *lineageService) ForkRecipe(ctx context.Context, userID, recipeID int, req models.ForkRecipeRequest) (*models.RecipeWithIngredients, error) {
+	if recipeID <= 0 {
+		return nil, domain.ErrRecipeNotFound
+	}
+
+	original, err := s.recipeRepo.GetByIDWithIngredients(recipeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRecipeNotFound
+		}
+		return nil, err
+	}
+	if err := authorize(ctx, policy.Read, recipeAccess(&original.Recipe), domain.ErrRecipeNotFound); err != nil {
+		return nil, err
+	}
+
+	created, err := copyRecipe(s.recipeRepo, s.stepRepo, s.categoryRepo, userID, original, req.Name, req.CategoryID,
+		domain.ErrForkCategoryRequired)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lineageRepo.Record(created.ID, recipeID); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// copyRecipe creates a draft owned by the user from original, with its
+// ingredients, steps and techniques. The draft takes the original's name and
+// category unless name or categoryID are given; noCategory is returned when
+// neither has a category. Forks and recipes made from templates are both
+// copies.
+func copyRecipe(recipeRepo repository.RecipeRepository, stepRepo repository.StepRepository, categoryRepo repository.CategoryRepository,
+	userID int, original *models.RecipeWithIngredients, name *string, categoryID *int, noCategory error) (*models.RecipeWithIngredients, error) {
+	copied := models.CreateRecipeWithIngredientsRequest{
+		Name:             original.Name,
+		Description:      original.Description,
+		Status:           models.RecipeStatusDraft,
+		Difficulty:       original.Difficulty,
+		TotalTimeMinutes: original.TotalTimeMinutes,
+	}
+	if name != nil {
+		copied.Name = strings.TrimSpace(*name)
+		if copied.Name == "" {
+			return nil, domain.ErrRecipeNameRequired
+		}
+	}
+	switch {
+	case categoryID != nil:
+		if *categoryID <= 0 {
+			return nil, domain.ErrInvalidCategory
+		}
+		exists, err := categoryRepo.Exists(*categoryID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, domain.ErrCategoryNotFound
+		}
+		copied.CategoryID = *categoryID
+	case original.CategoryID != nil:
+		copied.CategoryID = *original.CategoryID
+	default:
+		return nil, noCategory
+	}
+	for _, ingredient := range original.Ingredients {
+		copied.Ingredients = append(copied.Ingredients, models.AddRecipeIngredientRequest{
+			IngredientID: ingredient.IngredientID,
+			Quantity:     ingredient.Quantity,
+			Unit:         ingredient.Unit,
+			Notes:        ingredient.Notes,
+			ProductID:    ingredient.ProductID,
+		})
+	}
+
+	created, err := recipeRepo.CreateWithIngredients(userID, copied)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := stepRepo.GetByRecipeID(original.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(steps) > 0 {
+		copies := make([]models.CreateStepRequest, len(steps))
+		for i, step := range steps {
+			copies[i] = models.CreateStepRequest{
+				Instruction:     step.Instruction,
+				DurationMinutes: step.DurationMinutes,
+				IngredientIDs:   step.IngredientIDs,
+				Timers:          step.Timers,
+			}
+		}
+		if _, err := stepRepo.ReplaceForRecipe(created.ID, copies); err != nil {
+			return nil, err
+		}
+	}
+
+	techniques, err := stepRepo.GetTechniques(original.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(techniques) > 0 {
+		if err := stepRepo.ReplaceTechniques(created.ID, techniques); err != nil {
+			return nil, err
+		}
+	}
+	return created, nil
+}
+
+// GetLineage lists the recipes a public recipe was adapted from, nearest
+// first. Originals that are no longer public stay in the chain without
+// their name, so the chain does not silently skip a step.
+func (s *lineageService) GetLineage(recipeID int) (*models.RecipeLineage, error) {
+	if err := s.requirePublicRecipe(recipeID); err != nil {
+		return nil, err
+	}
+
+	ancestors, err := s.lineageRepo.GetAncestors(recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	lineage := &models.RecipeLineage{
+		RecipeID:    recipeID,
+		AdaptedFrom: make([]models.RecipeAttribution, 0, len(ancestors)),
+	}
+	for _, ancestor := range ancestors {
+		attribution := models.RecipeAttribution{
+			RecipeID: ancestor.ID,
+			UserID:   ancestor.UserID,
+			Public:   isPubliclyVisible(&ancestor),
+		}
+		if attribution.Public {
+			attribution.Name = ancestor.Name
+		}
+		lineage.AdaptedFrom = append(lineage.AdaptedFrom, attribution)
+	}
+	return lineage, nil
+}
+
+func (s *lineageService) GetForks(recipeID int) ([]models.RecipeFork, error) {
+	if err := s.requirePublicRecipe(recipeID); err != nil {
+		return nil, err
+	}
+	return s.lineageRepo.GetForks(recipeID)
+}
+
+func (s *lineageService) requirePublicRecipe(recipeID int) error {
+	if recipeID <= 0 {
+		return domain.ErrRecipeNotFound
+	}
+	recipe, err := s.recipeRepo.GetByID(recipeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrRecipeNotFound
+		}
+		return err
+	}
+	if !isPubliclyVisible(recipe) {
+		return domain.ErrRecipeNotFound
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type lineageServiceTestSetup struct {
+	service      LineageService
+	lineageRepo  *mocks.MockLineageRepository
+	recipeRepo   *mocks.MockRecipeRepository
+	stepRepo     *mocks.MockStepRepository
+	categoryRepo *mocks.MockCategoryRepository
+}
+
+func setupLineageServiceTest() *lineageServiceTestSetup {
+	lineageRepo := new(mocks.MockLineageRepository)
+	recipeRepo := new(mocks.MockRecipeRepository)
+	stepRepo := new(mocks.MockStepRepository)
+	categoryRepo := new(mocks.MockCategoryRepository)
+
+	return &lineageServiceTestSetup{
+		service:      NewLineageService(lineageRepo, recipeRepo, stepRepo, categoryRepo),
+		lineageRepo:  lineageRepo,
+		recipeRepo:   recipeRepo,
+		stepRepo:     stepRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func shakshukaWithIngredients(status string) *models.RecipeWithIngredients {
+	categoryID := 2
+	notes := "ripe"
+	return &models.RecipeWithIngredients{
+		Recipe: models.Recipe{ID: 1, UserID: 3, Name: "Shakshuka", CategoryID: &categoryID, Status: status},
+		Ingredients: []models.RecipeIngredient{
+			{IngredientID: 5, Quantity: 4, Unit: "pieces", Notes: &notes},
+		},
+	}
+}
+
+func TestLineageService_ForkRecipe_CopiesRecipeAndSteps(t *testing.T) {
+	setup := setupLineageServiceTest()
+	original := shakshukaWithIngredients(models.RecipeStatusPublished)
+	name := "  Green shakshuka "
+	steps := []models.RecipeStep{{
+		ID: 7, RecipeID: 1, Position: 1, Instruction: "Simmer the tomatoes",
+		IngredientIDs: []int{5}, Timers: []models.StepTimer{{Label: "Simmer", DurationSeconds: 600}},
+	}}
+	forked := &models.RecipeWithIngredients{Recipe: models.Recipe{ID: 10, UserID: 8, Name: "Green shakshuka", Status: models.RecipeStatusDraft}}
+
+	setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(original, nil)
+	setup.recipeRepo.On("CreateWithIngredients", 8, mock.MatchedBy(func(req models.CreateRecipeWithIngredientsRequest) bool {
+		return req.Name == "Green shakshuka" && req.CategoryID == 2 && req.Status == models.RecipeStatusDraft &&
+			len(req.Ingredients) == 1 && req.Ingredients[0].IngredientID == 5 && *req.Ingredients[0].Notes == "ripe"
+	})).Return(forked, nil)
+	setup.stepRepo.On("GetByRecipeID", 1).Return(steps, nil)
+	setup.stepRepo.On("ReplaceForRecipe", 10, []models.CreateStepRequest{{
+		Instruction: "Simmer the tomatoes", IngredientIDs: []int{5}, Timers: steps[0].Timers,
+	}}).Return([]models.RecipeStep{}, nil)
+	setup.stepRepo.On("GetTechniques", 1).Return([]string{"sauteing"}, nil)
+	setup.stepRepo.On("ReplaceTechniques", 10, []string{"sauteing"}).Return(nil)
+	setup.lineageRepo.On("Record", 10, 1).Return(nil)
+
+	result, err := setup.service.ForkRecipe(asUser(8), 8, 1, models.ForkRecipeRequest{Name: &name})
+
+	assert.NoError(t, err)
+	assert.Equal(t, forked, result)
+	setup.recipeRepo.AssertExpectations(t)
+	setup.stepRepo.AssertExpectations(t)
+	setup.lineageRepo.AssertExpectations(t)
+}
+
+func TestLineageService_ForkRecipe_OthersDraftIsHidden(t *testing.T) {
+	setup := setupLineageServiceTest()
+	setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(shakshukaWithIngredients(models.RecipeStatusDraft), nil)
+
+	_, err := setup.service.ForkRecipe(asUser(8), 8, 1, models.ForkRecipeRequest{})
+
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+	setup.recipeRepo.AssertNotCalled(t, "CreateWithIngredients")
+}
+
+func TestLineageService_ForkRecipe_OwnDraft(t *testing.T) {
+	setup := setupLineageServiceTest()
+	forked := &models.RecipeWithIngredients{Recipe: models.Recipe{ID: 10, UserID: 3}}
+
+	setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(shakshukaWithIngredients(models.RecipeStatusDraft), nil)
+	setup.recipeRepo.On("CreateWithIngredients", 3, mock.Anything).Return(forked, nil)
+	setup.stepRepo.On("GetByRecipeID", 1).Return([]models.RecipeStep{}, nil)
+	setup.stepRepo.On("GetTechniques", 1).Return([]string{}, nil)
+	setup.lineageRepo.On("Record", 10, 1).Return(nil)
+
+	_, err := setup.service.ForkRecipe(asUser(3), 3, 1, models.ForkRecipeRequest{})
+
+	assert.NoError(t, err)
+	setup.stepRepo.AssertNotCalled(t, "ReplaceForRecipe")
+	setup.stepRepo.AssertNotCalled(t, "ReplaceTechniques")
+}
+
+func TestLineageService_ForkRecipe_Validation(t *testing.T) {
+	blank := " "
+	zero := 0
+	tests := []struct {
+		name          string
+		uncategorised bool
+		req           models.ForkRecipeRequest
+		expected      error
+	}{
+		{name: "blank name", req: models.ForkRecipeRequest{Name: &blank}, expected: domain.ErrRecipeNameRequired},
+		{name: "invalid category", req: models.ForkRecipeRequest{CategoryID: &zero}, expected: domain.ErrInvalidCategory},
+		{name: "original has no category", uncategorised: true, expected: domain.ErrForkCategoryRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupLineageServiceTest()
+			original := shakshukaWithIngredients(models.RecipeStatusPublished)
+			if tt.uncategorised {
+				original.CategoryID = nil
+			}
+			setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(original, nil)
+
+			_, err := setup.service.ForkRecipe(asUser(8), 8, 1, tt.req)
+
+			assert.Equal(t, tt.expected, err)
+			setup.recipeRepo.AssertNotCalled(t, "CreateWithIngredients")
+		})
+	}
+}
+
+func TestLineageService_ForkRecipe_UnknownCategory(t *testing.T) {
+	setup := setupLineageServiceTest()
+	categoryID := 42
+	setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(shakshukaWithIngredients(models.RecipeStatusPublished), nil)
+	setup.categoryRepo.On("Exists", 42).Return(false, nil)
+
+	_, err := setup.service.ForkRecipe(asUser(8), 8, 1, models.ForkRecipeRequest{CategoryID: &categoryID})
+
+	assert.Equal(t, domain.ErrCategoryNotFound, err)
+	setup.recipeRepo.AssertNotCalled(t, "CreateWithIngredients")
+}
+
+func TestLineageService_ForkRecipe_NotFound(t *testing.T) {
+	setup := setupLineageServiceTest()
+	setup.recipeRepo.On("GetByIDWithIngredients", 99).Return(nil, sql.ErrNoRows)
+
+	_, err := setup.service.ForkRecipe(asUser(8), 8, 99, models.ForkRecipeRequest{})
+
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+}
+
+func TestLineageService_GetLineage_HidesNonPublicNames(t *testing.T) {
+	setup := setupLineageServiceTest()
+	setup.recipeRepo.On("GetByID", 10).Return(&models.Recipe{ID: 10, Status: models.RecipeStatusPublished}, nil)
+	setup.lineageRepo.On("GetAncestors", 10).Return([]models.Recipe{
+		{ID: 4, UserID: 5, Name: "Secret shakshuka", Status: models.RecipeStatusPrivate},
+		{ID: 1, UserID: 3, Name: "Shakshuka", Status: models.RecipeStatusPublished},
+	}, nil)
+
+	lineage, err := setup.service.GetLineage(10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.RecipeAttribution{
+		{RecipeID: 4, UserID: 5, Public: false},
+		{RecipeID: 1, UserID: 3, Name: "Shakshuka", Public: true},
+	}, lineage.AdaptedFrom)
+}
+
+func TestLineageService_GetForks_PrivateRecipe(t *testing.T) {
+	setup := setupLineageServiceTest()
+	setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, Status: models.RecipeStatusPrivate}, nil)
+
+	_, err := setup.service.GetForks(1)
+
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+	setup.lineageRepo.AssertNotCalled(t, "GetForks")
+}
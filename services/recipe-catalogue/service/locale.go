@@ -0,0 +1,9 @@
+package service
+
+import "strings"
+
+// isEnglish reports whether locale is a variant of English, which the
+// ingredient names themselves are written in.
+func isEnglish(locale string) bool {
+	return locale == "en" || strings.HasPrefix(locale, "en-")
+}
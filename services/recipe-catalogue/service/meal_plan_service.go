@@ -0,0 +1,766 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+	"meal-prep/shared/units"
+)
+
+const (
+	maxMealPlanTitleLength = 200
+	// maxMealPlanDays fits a plan for the longest month
+	maxMealPlanDays = 31
+
+	defaultGeneratedDays = 7
+	maxGeneratedDays     = 14
+	// maxRecommendedRecipes is the most the recommendations service returns
+	// at once; longer generated plans repeat recipes
+	maxRecommendedRecipes = 50
+
+	mealPlanDateLayout = "2006-01-02"
+
+	// maxPreferredMinutes is a whole day
+	maxPreferredMinutes = 24 * 60
+
+	maxMealPlanMembers  = 12
+	maxMemberNameLength = 50
+)
+
+var mealPlanPeriodTypes = map[string]bool{"week": true, "biweek": true, "month": true, "custom": true}
+
+type MealPlanService interface {
+	GetMealPlans(userID int) ([]models.MealPlan, error)
+	GetMealPlan(ctx context.Context, userID, planID int) (*models.MealPlan, error)
+	CreateMealPlan(userID int, req models.MealPlanRequest) (*models.MealPlan, error)
+	UpdateMealPlan(ctx context.Context, userID, planID int, req models.MealPlanRequest) (*models.MealPlan, error)
+	DeleteMealPlan(ctx context.Context, userID, planID int) error
+
+	// Entries - recipes planned for a meal of a day
+	AddEntry(ctx context.Context, userID, planID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error)
+	UpdateEntry(ctx context.Context, userID, planID, entryID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error)
+	DeleteEntry(ctx context.Context, userID, planID, entryID int) error
+
+	// Members - the household taking turns cooking the plan's meals
+	SetMembers(ctx context.Context, userID, planID int, req models.MealPlanMembersRequest) (*models.MealPlan, error)
+	GetMemberMeals(ctx context.Context, userID, planID, memberID int) ([]models.MealPlanEntry, error)
+
+	// GenerateMealPlan creates a plan filled from the user's recommendations
+	GenerateMealPlan(ctx context.Context, userID int, req models.GenerateMealPlanRequest) (*models.MealPlan, error)
+
+	// Preferences - how generated plans fit the user's week
+	GetPreferences(userID int) (*models.MealPlanPreferences, error)
+	UpdatePreferences(userID int, req models.MealPlanPreferencesRequest) (*models.MealPlanPreferences, error)
+
+	GetPrintout(ctx context.Context, userID, planID int, measurementSystem string) (*models.MealPlanPrintout, error)
+}
+
+type mealPlanService struct {
+	mealPlanRepo   repository.MealPlanRepository
+	recipeRepo     repository.RecipeRepository
+	ingredientRepo repository.IngredientRepository
+	stepRepo       repository.StepRepository
+	recommender    RecipeRecommender
+
+	// groceries totals the plan's shopping list exactly as a grocery list
+	// request would; it never touches store layouts or prices
+	groceries *groceryService
+	now       func() time.Time
+}
+
+func NewMealPlanService(mealPlanRepo repository.MealPlanRepository, recipeRepo repository.RecipeRepository, ingredientRepo repository.IngredientRepository, stepRepo repository.StepRepository, recommender RecipeRecommender) MealPlanService {
+	return &mealPlanService{
+		mealPlanRepo:   mealPlanRepo,
+		recipeRepo:     recipeRepo,
+		ingredientRepo: ingredientRepo,
+		stepRepo:       stepRepo,
+		recommender:    recommender,
+		groceries:      &groceryService{ingredientRepo: ingredientRepo, recipeRepo: recipeRepo},
+		now:            time.Now,
+	}
+}
+
+func (s *mealPlanService) GetMealPlans(userID int) ([]models.MealPlan, error) {
+	return s.mealPlanRepo.GetByUserID(userID)
+}
+
+// GetMealPlan returns the user's own plans and anyone's public ones.
+func (s *mealPlanService) GetMealPlan(ctx context.Context, userID, planID int) (*models.MealPlan, error) {
+	return s.visiblePlan(ctx, planID)
+}
+
+func (s *mealPlanService) CreateMealPlan(userID int, req models.MealPlanRequest) (*models.MealPlan, error) {
+	req, err := normalizeMealPlanRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.mealPlanRepo.Create(userID, req, mealPlanSlug(req.Title), nil)
+}
+
+// UpdateMealPlan replaces the plan's details; entries that fall outside a
+// shortened period are dropped.
+func (s *mealPlanService) UpdateMealPlan(ctx context.Context, userID, planID int, req models.MealPlanRequest) (*models.MealPlan, error) {
+	req, err := normalizeMealPlanRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ownedPlan(ctx, planID); err != nil {
+		return nil, err
+	}
+
+	plan, err := s.mealPlanRepo.Update(planID, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrMealPlanNotFound
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *mealPlanService) DeleteMealPlan(ctx context.Context, userID, planID int) error {
+	if _, err := s.ownedPlan(ctx, planID); err != nil {
+		return err
+	}
+
+	err := s.mealPlanRepo.Delete(planID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrMealPlanNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *mealPlanService) AddEntry(ctx context.Context, userID, planID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error) {
+	plan, err := s.ownedPlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if req, err = s.normalizeEntryRequest(ctx, plan, req); err != nil {
+		return nil, err
+	}
+	if req.AssigneeID, err = assignEntry(plan, 0, req); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.mealPlanRepo.AddEntry(planID, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrMealPlanNotFound
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *mealPlanService) UpdateEntry(ctx context.Context, userID, planID, entryID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error) {
+	plan, err := s.ownedPlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if entryID <= 0 {
+		return nil, domain.ErrMealPlanEntryNotFound
+	}
+	if req, err = s.normalizeEntryRequest(ctx, plan, req); err != nil {
+		return nil, err
+	}
+	if req.AssigneeID, err = assignEntry(plan, entryID, req); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.mealPlanRepo.UpdateEntry(planID, entryID, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrMealPlanEntryNotFound
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *mealPlanService) DeleteEntry(ctx context.Context, userID, planID, entryID int) error {
+	if _, err := s.ownedPlan(ctx, planID); err != nil {
+		return err
+	}
+	if entryID <= 0 {
+		return domain.ErrMealPlanEntryNotFound
+	}
+
+	err := s.mealPlanRepo.DeleteEntry(planID, entryID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrMealPlanEntryNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// SetMembers replaces the plan's household and deals every meal out again,
+// the first to the first member, so turns go round in the order given. A
+// meal's recipes all go to the same member. No members unassigns everything.
+func (s *mealPlanService) SetMembers(ctx context.Context, userID, planID int, req models.MealPlanMembersRequest) (*models.MealPlan, error) {
+	names, err := normalizeMemberNames(req.Members)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.ownedPlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.mealPlanRepo.SetMembers(planID, names, planMeals(plan.Entries)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrMealPlanNotFound
+		}
+		return nil, err
+	}
+	return s.visiblePlan(ctx, planID)
+}
+
+// GetMemberMeals is what a member has left to cook: their entries from today
+// on, by date and slot. Reminders for the member are built from this list.
+func (s *mealPlanService) GetMemberMeals(ctx context.Context, userID, planID, memberID int) ([]models.MealPlanEntry, error) {
+	plan, err := s.visiblePlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if memberIndex(plan.Members, memberID) < 0 {
+		return nil, domain.ErrMealPlanMemberNotFound
+	}
+
+	today := s.now().Format(mealPlanDateLayout)
+	meals := make([]models.MealPlanEntry, 0)
+	for _, entry := range plan.Entries {
+		if entry.AssigneeID != nil && *entry.AssigneeID == memberID && entry.Date >= today {
+			meals = append(meals, entry)
+		}
+	}
+	return meals, nil
+}
+
+// GenerateMealPlan plans one recommended recipe for each slot of each day,
+// best recommendations first. Recipes repeat only once every recommended
+// recipe that fits the day has been used. With the user's preferences, each
+// day only gets recipes within its max total time, and slots no recipe fits
+// stay empty.
+func (s *mealPlanService) GenerateMealPlan(ctx context.Context, userID int, req models.GenerateMealPlanRequest) (*models.MealPlan, error) {
+	if req.Days == 0 {
+		req.Days = defaultGeneratedDays
+	}
+	if req.Days < 0 || req.Days > maxGeneratedDays {
+		return nil, domain.ErrInvalidMealPlanGeneration
+	}
+	slots, err := normalizeMealSlots(req.Slots)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := time.Parse(mealPlanDateLayout, strings.TrimSpace(req.PeriodStart))
+	if err != nil {
+		return nil, domain.ErrInvalidMealPlanPeriod
+	}
+	planReq := models.MealPlanRequest{
+		Title:       req.Title,
+		PeriodStart: start.Format(mealPlanDateLayout),
+		PeriodEnd:   start.AddDate(0, 0, req.Days-1).Format(mealPlanDateLayout),
+		PeriodType:  generatedPeriodType(req.Days),
+		IsPublic:    req.IsPublic,
+		Scale:       req.Scale,
+	}
+	if strings.TrimSpace(planReq.Title) == "" {
+		planReq.Title = "Meal plan from " + planReq.PeriodStart
+	}
+	if planReq, err = normalizeMealPlanRequest(planReq); err != nil {
+		return nil, err
+	}
+
+	prefs, err := s.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	timed := prefs.WeekdayMaxMinutes != nil || prefs.WeekendMaxMinutes != nil || prefs.ElaborateWeekends
+
+	needed := req.Days * len(slots)
+	limit := min(needed, maxRecommendedRecipes)
+	if timed {
+		// Some recommendations will not fit some days
+		limit = maxRecommendedRecipes
+	}
+	recipeIDs, err := s.recommender.RecommendRecipes(ctx, userID, limit)
+	if err != nil {
+		logging.Logger.Warn("Failed to fetch recommendations for meal plan", "user_id", userID, "error", err)
+		return nil, domain.ErrRecommendationsUnavailable
+	}
+	if len(recipeIDs) == 0 {
+		return nil, domain.ErrNoRecommendations
+	}
+
+	candidates, err := s.planCandidates(recipeIDs, timed)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.MealPlanEntryRequest, 0, needed)
+	uses := make(map[int]int, len(candidates))
+	for day := 0; day < req.Days; day++ {
+		date := start.AddDate(0, 0, day)
+		fitting := dayCandidates(candidates, *prefs, date.Weekday())
+		if len(fitting) == 0 {
+			continue
+		}
+		for _, slot := range slots {
+			recipeID := leastUsed(fitting, uses)
+			uses[recipeID]++
+			entries = append(entries, models.MealPlanEntryRequest{
+				RecipeID: recipeID,
+				Date:     date.Format(mealPlanDateLayout),
+				Slot:     slot,
+			})
+		}
+	}
+	if len(entries) == 0 {
+		return nil, domain.ErrNoRecipesFitPreferences
+	}
+
+	return s.mealPlanRepo.Create(userID, planReq, mealPlanSlug(planReq.Title), entries)
+}
+
+// GetPreferences returns no limits for users who never set any.
+func (s *mealPlanService) GetPreferences(userID int) (*models.MealPlanPreferences, error) {
+	prefs, err := s.mealPlanRepo.GetPreferences(userID)
+	if err == sql.ErrNoRows {
+		return &models.MealPlanPreferences{UserID: userID}, nil
+	}
+	return prefs, err
+}
+
+func (s *mealPlanService) UpdatePreferences(userID int, req models.MealPlanPreferencesRequest) (*models.MealPlanPreferences, error) {
+	for _, minutes := range []*int{req.WeekdayMaxMinutes, req.WeekendMaxMinutes} {
+		if minutes != nil && (*minutes <= 0 || *minutes > maxPreferredMinutes) {
+			return nil, domain.ErrInvalidMealPlanPreferences
+		}
+	}
+
+	return s.mealPlanRepo.SavePreferences(models.MealPlanPreferences{
+		UserID:            userID,
+		WeekdayMaxMinutes: req.WeekdayMaxMinutes,
+		WeekendMaxMinutes: req.WeekendMaxMinutes,
+		ElaborateWeekends: req.ElaborateWeekends,
+	})
+}
+
+// planCandidate is a recommended recipe and, when preferences need it, how
+// long it takes.
+type planCandidate struct {
+	recipeID     int
+	totalMinutes *int
+}
+
+// planCandidates keeps the recommendations' order. Total times are only
+// looked up when timed; recipes that have since gone are dropped then.
+func (s *mealPlanService) planCandidates(recipeIDs []int, timed bool) ([]planCandidate, error) {
+	candidates := make([]planCandidate, 0, len(recipeIDs))
+	for _, id := range recipeIDs {
+		if !timed {
+			candidates = append(candidates, planCandidate{recipeID: id})
+			continue
+		}
+
+		recipe, err := s.recipeRepo.GetByID(id)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, planCandidate{recipeID: id, totalMinutes: recipe.TotalTimeMinutes})
+	}
+	return candidates, nil
+}
+
+// dayCandidates are the candidates within the day's max total time. Recipes
+// without a total time only fit days without a limit. At weekends, when the
+// user likes them elaborate, the longest recipes come first.
+func dayCandidates(candidates []planCandidate, prefs models.MealPlanPreferences, day time.Weekday) []planCandidate {
+	weekend := day == time.Saturday || day == time.Sunday
+	maxMinutes := prefs.WeekdayMaxMinutes
+	if weekend {
+		maxMinutes = prefs.WeekendMaxMinutes
+	}
+
+	fitting := make([]planCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if maxMinutes == nil || (candidate.totalMinutes != nil && *candidate.totalMinutes <= *maxMinutes) {
+			fitting = append(fitting, candidate)
+		}
+	}
+
+	if weekend && prefs.ElaborateWeekends {
+		minutes := func(c planCandidate) int {
+			if c.totalMinutes == nil {
+				return 0
+			}
+			return *c.totalMinutes
+		}
+		sort.SliceStable(fitting, func(i, j int) bool { return minutes(fitting[i]) > minutes(fitting[j]) })
+	}
+	return fitting
+}
+
+// leastUsed picks the first of the candidates planned the fewest times so
+// far.
+func leastUsed(candidates []planCandidate, uses map[int]int) int {
+	best := candidates[0].recipeID
+	for _, candidate := range candidates[1:] {
+		if uses[candidate.recipeID] < uses[best] {
+			best = candidate.recipeID
+		}
+	}
+	return best
+}
+
+// GetPrintout gathers the plan's recipes scaled by the plan, with their
+// steps, and one shopping list for all of them in measurementSystem ("" keeps
+// the recipes' units). Plans are visible to their owner and, when public, to
+// everyone; recipes the user cannot see are left out. A recipe planned for
+// several meals is printed and shopped for once.
+func (s *mealPlanService) GetPrintout(ctx context.Context, userID, planID int, measurementSystem string) (*models.MealPlanPrintout, error) {
+	plan, err := s.visiblePlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Scale <= 0 {
+		plan.Scale = 1
+	}
+
+	printout := &models.MealPlanPrintout{
+		MealPlan:     *plan,
+		Recipes:      make([]models.PrintedRecipe, 0, len(plan.Entries)),
+		ShoppingList: make([]models.GroceryListItem, 0),
+	}
+	recipeIDs := make([]int, 0, len(plan.Entries))
+	seen := make(map[int]bool, len(plan.Entries))
+	for _, entry := range plan.Entries {
+		if seen[entry.RecipeID] {
+			continue
+		}
+		seen[entry.RecipeID] = true
+
+		printed, err := s.printRecipe(ctx, entry.RecipeID, plan.Scale)
+		if err == domain.ErrRecipeNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		printout.Recipes = append(printout.Recipes, *printed)
+		recipeIDs = append(recipeIDs, entry.RecipeID)
+	}
+
+	if len(recipeIDs) > 0 {
+		items, err := s.groceries.GenerateGroceryList(ctx, userID, models.GroceryListRequest{
+			RecipeIDs:         recipeIDs,
+			MeasurementSystem: measurementSystem,
+			Scale:             plan.Scale,
+		})
+		if err != nil {
+			return nil, err
+		}
+		printout.ShoppingList = append(printout.ShoppingList, items...)
+	}
+
+	return printout, nil
+}
+
+func (s *mealPlanService) printRecipe(ctx context.Context, recipeID int, scale float64) (*models.PrintedRecipe, error) {
+	recipe, err := readableRecipe(ctx, s.recipeRepo, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredients, err := s.ingredientRepo.GetRecipeIngredients(recipe.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ingredients {
+		ingredients[i].Quantity, ingredients[i].Unit = units.Scale(ingredients[i].Quantity, ingredients[i].Unit, scale)
+	}
+
+	steps, err := s.stepRepo.GetByRecipeID(recipe.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PrintedRecipe{
+		Recipe:      *recipe,
+		Ingredients: ingredients,
+		Steps:       steps,
+	}, nil
+}
+
+func (s *mealPlanService) visiblePlan(ctx context.Context, planID int) (*models.MealPlan, error) {
+	return visibleMealPlan(ctx, s.mealPlanRepo, planID)
+}
+
+// visibleMealPlan returns plans the user in ctx may read, their own and
+// public ones, treating the rest as missing.
+func visibleMealPlan(ctx context.Context, mealPlanRepo repository.MealPlanRepository, planID int) (*models.MealPlan, error) {
+	return mealPlan(ctx, mealPlanRepo, policy.Read, planID)
+}
+
+// ownedPlan treats plans the user may not change as missing, public or not.
+func (s *mealPlanService) ownedPlan(ctx context.Context, planID int) (*models.MealPlan, error) {
+	return mealPlan(ctx, s.mealPlanRepo, policy.Update, planID)
+}
+
+func mealPlan(ctx context.Context, mealPlanRepo repository.MealPlanRepository, action policy.Action, planID int) (*models.MealPlan, error) {
+	if planID <= 0 {
+		return nil, domain.ErrMealPlanNotFound
+	}
+
+	plan, err := mealPlanRepo.GetByID(planID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrMealPlanNotFound
+		}
+		return nil, err
+	}
+	resource := policy.Resource{Type: mealPlanResource, OwnerID: plan.UserID, Public: plan.IsPublic}
+	if err := authorize(ctx, action, resource, domain.ErrMealPlanNotFound); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// normalizeEntryRequest checks that the entry falls within the plan and
+// plans a recipe the user may cook.
+func (s *mealPlanService) normalizeEntryRequest(ctx context.Context, plan *models.MealPlan, req models.MealPlanEntryRequest) (models.MealPlanEntryRequest, error) {
+	req.Slot = strings.ToLower(strings.TrimSpace(req.Slot))
+	if !models.IsValidMealSlot(req.Slot) {
+		return req, domain.ErrInvalidMealPlanEntry
+	}
+
+	date, err := time.Parse(mealPlanDateLayout, strings.TrimSpace(req.Date))
+	if err != nil {
+		return req, domain.ErrInvalidMealPlanEntry
+	}
+	// Dates are YYYY-MM-DD, so they compare as strings
+	req.Date = date.Format(mealPlanDateLayout)
+	if req.Date < plan.PeriodStart || req.Date > plan.PeriodEnd {
+		return req, domain.ErrInvalidMealPlanEntry
+	}
+
+	if _, err := readableRecipe(ctx, s.recipeRepo, req.RecipeID); err != nil {
+		return req, err
+	}
+	return req, nil
+}
+
+// assignEntry checks an entry's assignee is one of the plan's members or,
+// without one, gives the entry to whoever's turn its meal is. entryID is the
+// entry being changed, 0 for a new one.
+func assignEntry(plan *models.MealPlan, entryID int, req models.MealPlanEntryRequest) (*int, error) {
+	if req.AssigneeID != nil {
+		if memberIndex(plan.Members, *req.AssigneeID) < 0 {
+			return nil, domain.ErrInvalidMealPlanAssignee
+		}
+		return req.AssigneeID, nil
+	}
+	return mealTurn(plan, entryID, req.Date, req.Slot), nil
+}
+
+// mealTurn is who cooks a meal: whoever already cooks another of its recipes,
+// otherwise the member after whoever cooks the closest earlier meal, going
+// back to the first after the last. Plans without members have no turns.
+func mealTurn(plan *models.MealPlan, entryID int, date, slot string) *int {
+	if len(plan.Members) == 0 {
+		return nil
+	}
+
+	previous := -1
+	for _, entry := range plan.Entries {
+		if entry.ID == entryID || entry.AssigneeID == nil {
+			continue
+		}
+		member := memberIndex(plan.Members, *entry.AssigneeID)
+		if member < 0 {
+			continue
+		}
+		switch compareMeals(entry.Date, entry.Slot, date, slot) {
+		case 0:
+			return entry.AssigneeID
+		case -1:
+			// Entries are in meal order, so the last earlier one is closest
+			previous = member
+		}
+	}
+
+	next := plan.Members[(previous+1)%len(plan.Members)].ID
+	return &next
+}
+
+// planMeals groups the IDs of entries, in meal order, by meal.
+func planMeals(entries []models.MealPlanEntry) [][]int {
+	meals := make([][]int, 0)
+	for i, entry := range entries {
+		if i > 0 && compareMeals(entries[i-1].Date, entries[i-1].Slot, entry.Date, entry.Slot) == 0 {
+			meals[len(meals)-1] = append(meals[len(meals)-1], entry.ID)
+			continue
+		}
+		meals = append(meals, []int{entry.ID})
+	}
+	return meals
+}
+
+// compareMeals orders meals by date, then through the day.
+func compareMeals(dateA, slotA, dateB, slotB string) int {
+	// Dates are YYYY-MM-DD, so they compare as strings
+	if c := strings.Compare(dateA, dateB); c != 0 {
+		return c
+	}
+	return cmp.Compare(models.MealSlotOrder(slotA), models.MealSlotOrder(slotB))
+}
+
+func memberIndex(members []models.MealPlanMember, memberID int) int {
+	for i, member := range members {
+		if member.ID == memberID {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeMemberNames trims the names, which must differ ignoring case.
+func normalizeMemberNames(names []string) ([]string, error) {
+	if len(names) > maxMealPlanMembers {
+		return nil, domain.ErrInvalidMealPlanMembers
+	}
+
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || utf8.RuneCountInString(name) > maxMemberNameLength || seen[key] {
+			return nil, domain.ErrInvalidMealPlanMembers
+		}
+		seen[key] = true
+		normalized = append(normalized, name)
+	}
+	return normalized, nil
+}
+
+// normalizeMealPlanRequest trims the title and fills in the default period
+// type and scale.
+func normalizeMealPlanRequest(req models.MealPlanRequest) (models.MealPlanRequest, error) {
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" || utf8.RuneCountInString(req.Title) > maxMealPlanTitleLength {
+		return req, domain.ErrMealPlanTitleRequired
+	}
+
+	start, err := time.Parse(mealPlanDateLayout, strings.TrimSpace(req.PeriodStart))
+	if err != nil {
+		return req, domain.ErrInvalidMealPlanPeriod
+	}
+	end, err := time.Parse(mealPlanDateLayout, strings.TrimSpace(req.PeriodEnd))
+	if err != nil || end.Before(start) || end.Sub(start) >= maxMealPlanDays*24*time.Hour {
+		return req, domain.ErrInvalidMealPlanPeriod
+	}
+	req.PeriodStart = start.Format(mealPlanDateLayout)
+	req.PeriodEnd = end.Format(mealPlanDateLayout)
+
+	req.PeriodType = strings.ToLower(strings.TrimSpace(req.PeriodType))
+	if req.PeriodType == "" {
+		req.PeriodType = "week"
+	}
+	if !mealPlanPeriodTypes[req.PeriodType] {
+		return req, domain.ErrInvalidMealPlanPeriod
+	}
+
+	if req.Scale == 0 {
+		req.Scale = 1
+	}
+	if req.Scale < 0 || req.Scale > maxCookingScale {
+		return req, domain.ErrInvalidScale
+	}
+	return req, nil
+}
+
+// normalizeMealSlots defaults to dinner only and puts slots in the order
+// they are eaten.
+func normalizeMealSlots(slots []string) ([]string, error) {
+	if len(slots) == 0 {
+		return []string{models.MealSlotDinner}, nil
+	}
+
+	seen := make(map[string]bool, len(slots))
+	for _, slot := range slots {
+		slot = strings.ToLower(strings.TrimSpace(slot))
+		if !models.IsValidMealSlot(slot) || seen[slot] {
+			return nil, domain.ErrInvalidMealPlanGeneration
+		}
+		seen[slot] = true
+	}
+
+	normalized := make([]string, 0, len(seen))
+	for _, slot := range []string{models.MealSlotBreakfast, models.MealSlotLunch, models.MealSlotDinner} {
+		if seen[slot] {
+			normalized = append(normalized, slot)
+		}
+	}
+	return normalized, nil
+}
+
+func generatedPeriodType(days int) string {
+	switch days {
+	case 7:
+		return "week"
+	case 14:
+		return "biweek"
+	default:
+		return "custom"
+	}
+}
+
+// mealPlanSlug makes the unique slug the meal_plans table requires from the
+// title and a random suffix.
+func mealPlanSlug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if b.Len() >= 40 {
+			break
+		}
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	prefix := strings.TrimSuffix(b.String(), "-")
+	if prefix == "" {
+		prefix = "meal-plan"
+	}
+
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return prefix + "-" + hex.EncodeToString(suffix)
+}
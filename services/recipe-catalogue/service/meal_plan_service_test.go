@@ -0,0 +1,455 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mealPlanServiceTestSetup struct {
+	service        MealPlanService
+	mealPlanRepo   *mocks.MockMealPlanRepository
+	recipeRepo     *mocks.MockRecipeRepository
+	ingredientRepo *mocks.MockIngredientRepository
+	stepRepo       *mocks.MockStepRepository
+	recommender    *fakeRecommender
+}
+
+type fakeRecommender struct {
+	recipeIDs []int
+	err       error
+	limits    []int
+}
+
+func (f *fakeRecommender) RecommendRecipes(ctx context.Context, userID, limit int) ([]int, error) {
+	f.limits = append(f.limits, limit)
+	return f.recipeIDs, f.err
+}
+
+func setupMealPlanServiceTest() *mealPlanServiceTestSetup {
+	mealPlanRepo := new(mocks.MockMealPlanRepository)
+	recipeRepo := new(mocks.MockRecipeRepository)
+	ingredientRepo := new(mocks.MockIngredientRepository)
+	stepRepo := new(mocks.MockStepRepository)
+	recommender := &fakeRecommender{}
+
+	// Users have no preferences unless a test says otherwise
+	mealPlanRepo.On("GetPreferences", mock.Anything).Return(nil, sql.ErrNoRows).Maybe()
+
+	return &mealPlanServiceTestSetup{
+		service:        NewMealPlanService(mealPlanRepo, recipeRepo, ingredientRepo, stepRepo, recommender),
+		mealPlanRepo:   mealPlanRepo,
+		recipeRepo:     recipeRepo,
+		ingredientRepo: ingredientRepo,
+		stepRepo:       stepRepo,
+		recommender:    recommender,
+	}
+}
+
+func TestMealPlanService_GetPrintout_ScalesRecipesAndShoppingList(t *testing.T) {
+	setup := setupMealPlanServiceTest()
+	setup.mealPlanRepo.On("GetByID", 3).Return(&models.MealPlan{ID: 3, UserID: 5, Title: "Week of Oct 6-12", Scale: 2,
+		Entries: []models.MealPlanEntry{
+			{RecipeID: 1, Date: "2025-10-06", Slot: "breakfast"},
+			{RecipeID: 2, Date: "2025-10-06", Slot: "dinner"},
+			{RecipeID: 1, Date: "2025-10-07", Slot: "dinner"},
+		},
+	}, nil)
+
+	// Recipe 2 is someone else's draft
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPublished, 5), nil)
+	setup.recipeRepo.On("GetByID", 2).Return(&models.Recipe{ID: 2, UserID: 9, Name: "Secret", Status: models.RecipeStatusDraft}, nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+	setup.stepRepo.On("GetByRecipeID", 1).Return([]models.RecipeStep{{Position: 1, Instruction: "Boil the pasta"}}, nil)
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1}).Return(map[int][]models.RecipeIngredient{1: cookingIngredients()}, nil)
+	setup.ingredientRepo.On("GetPackSizesForIngredients", []int{4, 7}).Return(map[int][]models.PackSize{}, nil)
+
+	printout, err := setup.service.GetPrintout(asUser(5), 5, 3, "")
+
+	require.NoError(t, err)
+	require.Len(t, printout.Recipes, 1)
+	assert.Equal(t, "Pasta", printout.Recipes[0].Recipe.Name)
+	assert.Equal(t, 400.0, printout.Recipes[0].Ingredients[0].Quantity)
+	assert.Equal(t, 3.0, printout.Recipes[0].Ingredients[1].Quantity)
+	assert.Len(t, printout.Recipes[0].Steps, 1)
+
+	require.Len(t, printout.ShoppingList, 2)
+	totals := map[int]float64{}
+	for _, item := range printout.ShoppingList {
+		totals[item.IngredientID] = item.TotalQuantity
+	}
+	assert.Equal(t, map[int]float64{4: 400, 7: 3}, totals)
+}
+
+func TestMealPlanService_GetPrintout_HidesOtherUsersPrivatePlans(t *testing.T) {
+	setup := setupMealPlanServiceTest()
+	setup.mealPlanRepo.On("GetByID", 3).Return(&models.MealPlan{ID: 3, UserID: 9, Scale: 1}, nil)
+	setup.mealPlanRepo.On("GetByID", 4).Return(nil, sql.ErrNoRows)
+
+	_, err := setup.service.GetPrintout(asUser(5), 5, 3, "")
+	assert.Equal(t, domain.ErrMealPlanNotFound, err)
+
+	_, err = setup.service.GetPrintout(asUser(5), 5, 4, "")
+	assert.Equal(t, domain.ErrMealPlanNotFound, err)
+}
+
+func TestMealPlanService_GetPrintout_PublicPlanWithoutRecipes(t *testing.T) {
+	setup := setupMealPlanServiceTest()
+	setup.mealPlanRepo.On("GetByID", 3).Return(&models.MealPlan{ID: 3, UserID: 9, IsPublic: true}, nil)
+
+	printout, err := setup.service.GetPrintout(asUser(5), 5, 3, "metric")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, printout.MealPlan.Scale)
+	assert.Empty(t, printout.Recipes)
+	assert.Empty(t, printout.ShoppingList)
+	setup.ingredientRepo.AssertNotCalled(t, "GetIngredientsForRecipes")
+}
+
+func TestMealPlanService_CreateMealPlan_NormalizesRequest(t *testing.T) {
+	setup := setupMealPlanServiceTest()
+	expected := models.MealPlanRequest{Title: "Week of Oct 6-12", PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12",
+		PeriodType: "week", Scale: 1}
+	setup.mealPlanRepo.On("Create", 5, expected, mock.AnythingOfType("string"), []models.MealPlanEntryRequest(nil)).
+		Return(&models.MealPlan{ID: 3, UserID: 5}, nil)
+
+	plan, err := setup.service.CreateMealPlan(5, models.MealPlanRequest{Title: "  Week of Oct 6-12 ",
+		PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, plan.ID)
+	setup.mealPlanRepo.AssertExpectations(t)
+}
+
+func TestMealPlanService_CreateMealPlan_Validation(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         models.MealPlanRequest
+		expectedErr error
+	}{
+		{"missing title", models.MealPlanRequest{Title: " ", PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12"},
+			domain.ErrMealPlanTitleRequired},
+		{"end before start", models.MealPlanRequest{Title: "Week", PeriodStart: "2025-10-12", PeriodEnd: "2025-10-06"},
+			domain.ErrInvalidMealPlanPeriod},
+		{"longer than a month", models.MealPlanRequest{Title: "Autumn", PeriodStart: "2025-10-01", PeriodEnd: "2025-11-01"},
+			domain.ErrInvalidMealPlanPeriod},
+		{"unknown period type", models.MealPlanRequest{Title: "Week", PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12",
+			PeriodType: "fortnight"}, domain.ErrInvalidMealPlanPeriod},
+		{"negative scale", models.MealPlanRequest{Title: "Week", PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12",
+			Scale: -1}, domain.ErrInvalidScale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupMealPlanServiceTest()
+
+			_, err := setup.service.CreateMealPlan(5, tt.req)
+
+			assert.Equal(t, tt.expectedErr, err)
+			setup.mealPlanRepo.AssertNotCalled(t, "Create")
+		})
+	}
+}
+
+func TestMealPlanService_UpdateMealPlan_OnlyOwner(t *testing.T) {
+	setup := setupMealPlanServiceTest()
+	setup.mealPlanRepo.On("GetByID", 3).Return(&models.MealPlan{ID: 3, UserID: 9, IsPublic: true}, nil)
+
+	_, err := setup.service.UpdateMealPlan(asUser(5), 5, 3, models.MealPlanRequest{Title: "Mine now",
+		PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12"})
+
+	assert.Equal(t, domain.ErrMealPlanNotFound, err)
+	setup.mealPlanRepo.AssertNotCalled(t, "Update")
+}
+
+func TestMealPlanService_AddEntry(t *testing.T) {
+	plan := &models.MealPlan{ID: 3, UserID: 5, PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12"}
+
+	t.Run("plans a recipe for a meal", func(t *testing.T) {
+		setup := setupMealPlanServiceTest()
+		setup.mealPlanRepo.On("GetByID", 3).Return(plan, nil)
+		setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPublished, 9), nil)
+		expected := models.MealPlanEntryRequest{RecipeID: 1, Date: "2025-10-08", Slot: "lunch"}
+		setup.mealPlanRepo.On("AddEntry", 3, expected).Return(&models.MealPlanEntry{ID: 11, RecipeID: 1,
+			Date: "2025-10-08", Slot: "lunch"}, nil)
+
+		entry, err := setup.service.AddEntry(asUser(5), 5, 3, models.MealPlanEntryRequest{RecipeID: 1, Date: "2025-10-08", Slot: " Lunch"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 11, entry.ID)
+	})
+
+	t.Run("rejects dates outside the period", func(t *testing.T) {
+		setup := setupMealPlanServiceTest()
+		setup.mealPlanRepo.On("GetByID", 3).Return(plan, nil)
+
+		_, err := setup.service.AddEntry(asUser(5), 5, 3, models.MealPlanEntryRequest{RecipeID: 1, Date: "2025-10-13", Slot: "lunch"})
+
+		assert.Equal(t, domain.ErrInvalidMealPlanEntry, err)
+	})
+
+	t.Run("rejects unknown slots", func(t *testing.T) {
+		setup := setupMealPlanServiceTest()
+		setup.mealPlanRepo.On("GetByID", 3).Return(plan, nil)
+
+		_, err := setup.service.AddEntry(asUser(5), 5, 3, models.MealPlanEntryRequest{RecipeID: 1, Date: "2025-10-08", Slot: "brunch"})
+
+		assert.Equal(t, domain.ErrInvalidMealPlanEntry, err)
+	})
+
+	t.Run("rejects other users' drafts", func(t *testing.T) {
+		setup := setupMealPlanServiceTest()
+		setup.mealPlanRepo.On("GetByID", 3).Return(plan, nil)
+		setup.recipeRepo.On("GetByID", 2).Return(&models.Recipe{ID: 2, UserID: 9, Status: models.RecipeStatusDraft}, nil)
+
+		_, err := setup.service.AddEntry(asUser(5), 5, 3, models.MealPlanEntryRequest{RecipeID: 2, Date: "2025-10-08", Slot: "dinner"})
+
+		assert.Equal(t, domain.ErrRecipeNotFound, err)
+		setup.mealPlanRepo.AssertNotCalled(t, "AddEntry")
+	})
+}
+
+func TestMealPlanService_AddEntry_TakesTurns(t *testing.T) {
+	ann, bo := 20, 21
+	plan := &models.MealPlan{ID: 3, UserID: 5, PeriodStart: "2025-10-06", PeriodEnd: "2025-10-12",
+		Members: []models.MealPlanMember{{ID: ann, Name: "Ann"}, {ID: bo, Name: "Bo"}},
+		Entries: []models.MealPlanEntry{
+			{ID: 10, RecipeID: 1, Date: "2025-10-06", Slot: "dinner", AssigneeID: &ann},
+			{ID: 11, RecipeID: 1, Date: "2025-10-07", Slot: "dinner", AssigneeID: &bo},
+		}}
+
+	tests := []struct {
+		name     string
+		req      models.MealPlanEntryRequest
+		expected *int
+	}{
+		{"after the last cook", models.MealPlanEntryRequest{RecipeID: 1, Date: "2025-10-08", Slot: "dinner"}, &ann},
+		{"after the closest earlier meal", models.MealPlanEntryRequest{RecipeID: 1, Date: "2025-10-07", Slot: "lunch"}, &bo},
+		{"first member before any meal", models.MealPlanEntryRequest{RecipeID: 1, Date: "2025-10-06", Slot: "breakfast"}, &ann},
+		{"same cook as the meal's other recipes", models.MealPlanEntryRequest{RecipeID: 2, Date: "2025-10-07", Slot: "dinner"}, &bo},
+		{"chosen assignee", models.MealPlanEntryRequest{RecipeID: 1, Date: "2025-10-08", Slot: "dinner", AssigneeID: &bo}, &bo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupMealPlanServiceTest()
+			setup.mealPlanRepo.On("GetByID", 3).Return(plan, nil)
+			setup.recipeRepo.On("GetByID", tt.req.RecipeID).Return(cookingRecipe(models.RecipeStatusPublished, 5), nil)
+			expected := tt.req
+			expected.AssigneeID = tt.expected
+			setup.mealPlanRepo.On("AddEntry", 3, expected).Return(&models.MealPlanEntry{ID: 12}, nil)
+
+			_, err := setup.service.AddEntry(asUser(5), 5, 3, tt.req)
+
+			require.NoError(t, err)
+			setup.mealPlanRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("rejects assignees who are not members", func(t *testing.T) {
+		setup := setupMealPlanServiceTest()
+		setup.mealPlanRepo.On("GetByID", 3).Return(plan, nil)
+		setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPublished, 5), nil)
+		stranger := 99
+
+		_, err := setup.service.AddEntry(asUser(5), 5, 3, models.MealPlanEntryRequest{RecipeID: 1, Date: "2025-10-08", Slot: "dinner", AssigneeID: &stranger})
+
+		assert.Equal(t, domain.ErrInvalidMealPlanAssignee, err)
+		setup.mealPlanRepo.AssertNotCalled(t, "AddEntry")
+	})
+}
+
+func TestMealPlanService_SetMembers(t *testing.T) {
+	plan := &models.MealPlan{ID: 3, UserID: 5, Entries: []models.MealPlanEntry{
+		{ID: 10, Date: "2025-10-06", Slot: "dinner"},
+		{ID: 11, Date: "2025-10-06", Slot: "dinner"},
+		{ID: 12, Date: "2025-10-07", Slot: "lunch"},
+	}}
+
+	t.Run("deals out meals to the trimmed names", func(t *testing.T) {
+		setup := setupMealPlanServiceTest()
+		setup.mealPlanRepo.On("GetByID", 3).Return(plan, nil)
+		setup.mealPlanRepo.On("SetMembers", 3, []string{"Ann", "Bo"}, [][]int{{10, 11}, {12}}).
+			Return([]models.MealPlanMember{{ID: 20, Name: "Ann"}, {ID: 21, Name: "Bo"}}, nil)
+
+		_, err := setup.service.SetMembers(asUser(5), 5, 3, models.MealPlanMembersRequest{Members: []string{" Ann", "Bo "}})
+
+		require.NoError(t, err)
+		setup.mealPlanRepo.AssertExpectations(t)
+	})
+
+	for name, members := range map[string][]string{
+		"blank name":      {"Ann", " "},
+		"same name":       {"Ann", "ann"},
+		"too many":        {"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m"},
+		"too long a name": {strings.Repeat("a", 51)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			setup := setupMealPlanServiceTest()
+
+			_, err := setup.service.SetMembers(asUser(5), 5, 3, models.MealPlanMembersRequest{Members: members})
+
+			assert.Equal(t, domain.ErrInvalidMealPlanMembers, err)
+			setup.mealPlanRepo.AssertNotCalled(t, "SetMembers")
+		})
+	}
+}
+
+func TestMealPlanService_GetMemberMeals_FromToday(t *testing.T) {
+	ann, bo := 20, 21
+	setup := setupMealPlanServiceTest()
+	setup.service.(*mealPlanService).now = func() time.Time { return time.Date(2025, 10, 7, 18, 0, 0, 0, time.UTC) }
+	setup.mealPlanRepo.On("GetByID", 3).Return(&models.MealPlan{ID: 3, UserID: 5,
+		Members: []models.MealPlanMember{{ID: ann, Name: "Ann"}, {ID: bo, Name: "Bo"}},
+		Entries: []models.MealPlanEntry{
+			{ID: 10, Date: "2025-10-06", Slot: "dinner", AssigneeID: &ann},
+			{ID: 11, Date: "2025-10-07", Slot: "dinner", AssigneeID: &ann},
+			{ID: 12, Date: "2025-10-08", Slot: "dinner", AssigneeID: &bo},
+			{ID: 13, Date: "2025-10-09", Slot: "dinner", AssigneeID: &ann},
+		}}, nil)
+
+	meals, err := setup.service.GetMemberMeals(asUser(5), 5, 3, ann)
+	require.NoError(t, err)
+	require.Len(t, meals, 2)
+	assert.Equal(t, 11, meals[0].ID)
+	assert.Equal(t, 13, meals[1].ID)
+
+	_, err = setup.service.GetMemberMeals(asUser(5), 5, 3, 99)
+	assert.Equal(t, domain.ErrMealPlanMemberNotFound, err)
+}
+
+func TestMealPlanService_DeleteEntry_NotFound(t *testing.T) {
+	setup := setupMealPlanServiceTest()
+	setup.mealPlanRepo.On("GetByID", 3).Return(&models.MealPlan{ID: 3, UserID: 5}, nil)
+	setup.mealPlanRepo.On("DeleteEntry", 3, 11).Return(sql.ErrNoRows)
+
+	err := setup.service.DeleteEntry(asUser(5), 5, 3, 11)
+
+	assert.Equal(t, domain.ErrMealPlanEntryNotFound, err)
+}
+
+func TestMealPlanService_GenerateMealPlan_CyclesThroughRecommendations(t *testing.T) {
+	setup := setupMealPlanServiceTest()
+	setup.recommender.recipeIDs = []int{4, 5, 6}
+	expectedPlan := models.MealPlanRequest{Title: "Meal plan from 2025-10-06", PeriodStart: "2025-10-06",
+		PeriodEnd: "2025-10-07", PeriodType: "custom", Scale: 1}
+	expectedEntries := []models.MealPlanEntryRequest{
+		{RecipeID: 4, Date: "2025-10-06", Slot: "lunch"},
+		{RecipeID: 5, Date: "2025-10-06", Slot: "dinner"},
+		{RecipeID: 6, Date: "2025-10-07", Slot: "lunch"},
+		{RecipeID: 4, Date: "2025-10-07", Slot: "dinner"},
+	}
+	setup.mealPlanRepo.On("Create", 5, expectedPlan, mock.AnythingOfType("string"), expectedEntries).
+		Return(&models.MealPlan{ID: 3, UserID: 5}, nil)
+
+	plan, err := setup.service.GenerateMealPlan(context.Background(), 5, models.GenerateMealPlanRequest{PeriodStart: "2025-10-06", Days: 2,
+		Slots: []string{"dinner", "lunch"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, plan.ID)
+	assert.Equal(t, []int{4}, setup.recommender.limits)
+	setup.mealPlanRepo.AssertExpectations(t)
+}
+
+func TestMealPlanService_GenerateMealPlan_FollowsTimePreferences(t *testing.T) {
+	setup := setupMealPlanServiceTest()
+	weekday := 30
+	setup.mealPlanRepo.ExpectedCalls = nil
+	setup.mealPlanRepo.On("GetPreferences", 5).Return(&models.MealPlanPreferences{UserID: 5,
+		WeekdayMaxMinutes: &weekday, ElaborateWeekends: true}, nil)
+	setup.recommender.recipeIDs = []int{1, 2, 3, 4}
+	for id, minutes := range map[int]int{1: 20, 2: 90, 4: 45} {
+		setup.recipeRepo.On("GetByID", id).Return(&models.Recipe{ID: id, TotalTimeMinutes: &minutes}, nil)
+	}
+	// Recipe 3 has no total time, so it only fits days without a limit
+	setup.recipeRepo.On("GetByID", 3).Return(&models.Recipe{ID: 3}, nil)
+
+	// Friday to Monday
+	expectedEntries := []models.MealPlanEntryRequest{
+		{RecipeID: 1, Date: "2025-10-10", Slot: "dinner"},
+		{RecipeID: 2, Date: "2025-10-11", Slot: "dinner"},
+		{RecipeID: 4, Date: "2025-10-12", Slot: "dinner"},
+		{RecipeID: 1, Date: "2025-10-13", Slot: "dinner"},
+	}
+	setup.mealPlanRepo.On("Create", 5, mock.Anything, mock.AnythingOfType("string"), expectedEntries).
+		Return(&models.MealPlan{ID: 3, UserID: 5}, nil)
+
+	_, err := setup.service.GenerateMealPlan(context.Background(), 5, models.GenerateMealPlanRequest{PeriodStart: "2025-10-10", Days: 4})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{maxRecommendedRecipes}, setup.recommender.limits)
+	setup.mealPlanRepo.AssertExpectations(t)
+}
+
+func TestMealPlanService_GenerateMealPlan_NothingFitsPreferences(t *testing.T) {
+	setup := setupMealPlanServiceTest()
+	limit := 15
+	setup.mealPlanRepo.ExpectedCalls = nil
+	setup.mealPlanRepo.On("GetPreferences", 5).Return(&models.MealPlanPreferences{UserID: 5,
+		WeekdayMaxMinutes: &limit, WeekendMaxMinutes: &limit}, nil)
+	setup.recommender.recipeIDs = []int{1}
+	minutes := 40
+	setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, TotalTimeMinutes: &minutes}, nil)
+
+	_, err := setup.service.GenerateMealPlan(context.Background(), 5, models.GenerateMealPlanRequest{PeriodStart: "2025-10-10"})
+
+	assert.Equal(t, domain.ErrNoRecipesFitPreferences, err)
+	setup.mealPlanRepo.AssertNotCalled(t, "Create")
+}
+
+func TestMealPlanService_UpdatePreferences_Invalid(t *testing.T) {
+	for _, minutes := range []int{0, -5, 1441} {
+		setup := setupMealPlanServiceTest()
+
+		_, err := setup.service.UpdatePreferences(5, models.MealPlanPreferencesRequest{WeekendMaxMinutes: &minutes})
+
+		assert.Equal(t, domain.ErrInvalidMealPlanPreferences, err, minutes)
+		setup.mealPlanRepo.AssertNotCalled(t, "SavePreferences")
+	}
+}
+
+func TestMealPlanService_GenerateMealPlan_Errors(t *testing.T) {
+	logging.Init("test")
+
+	tests := []struct {
+		name        string
+		req         models.GenerateMealPlanRequest
+		recipeIDs   []int
+		recErr      error
+		expectedErr error
+	}{
+		{"too many days", models.GenerateMealPlanRequest{PeriodStart: "2025-10-06", Days: 15}, []int{4}, nil,
+			domain.ErrInvalidMealPlanGeneration},
+		{"unknown slot", models.GenerateMealPlanRequest{PeriodStart: "2025-10-06", Slots: []string{"supper"}}, []int{4}, nil,
+			domain.ErrInvalidMealPlanGeneration},
+		{"missing start", models.GenerateMealPlanRequest{}, []int{4}, nil, domain.ErrInvalidMealPlanPeriod},
+		{"nothing recommended", models.GenerateMealPlanRequest{PeriodStart: "2025-10-06"}, nil, nil,
+			domain.ErrNoRecommendations},
+		{"recommendations down", models.GenerateMealPlanRequest{PeriodStart: "2025-10-06"}, nil, errors.New("connection refused"),
+			domain.ErrRecommendationsUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupMealPlanServiceTest()
+			setup.recommender.recipeIDs, setup.recommender.err = tt.recipeIDs, tt.recErr
+
+			_, err := setup.service.GenerateMealPlan(context.Background(), 5, tt.req)
+
+			assert.Equal(t, tt.expectedErr, err)
+			setup.mealPlanRepo.AssertNotCalled(t, "Create")
+		})
+	}
+}
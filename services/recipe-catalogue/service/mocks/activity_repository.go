@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockActivityRepository struct {
+	mock.Mock
+}
+
+func (m *MockActivityRepository) Record(activity models.Activity) error {
+	args := m.Called(activity)
+	return args.Error(0)
+}
+
+func (m *MockActivityRepository) GetFeed(userID int, params models.PaginationParams) ([]models.Activity, int, error) {
+	args := m.Called(userID, params)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.Activity), args.Int(1), args.Error(2)
+}
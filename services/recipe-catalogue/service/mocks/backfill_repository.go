@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBackfillRepository struct {
+	mock.Mock
+}
+
+func (m *MockBackfillRepository) ListUnits() ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockBackfillRepository) RenameUnit(from, to string) (int, error) {
+	args := m.Called(from, to)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockBackfillRepository) ListRecipeIDs() ([]int, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockBackfillRepository) ListSearchIndexes() ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockBackfillRepository) RebuildSearchIndex(ctx context.Context, index string) error {
+	args := m.Called(ctx, index)
+	return args.Error(0)
+}
@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCookingSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockCookingSessionRepository) Create(session models.CookingSession) (*models.CookingSession, error) {
+	args := m.Called(session)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CookingSession), args.Error(1)
+}
+
+func (m *MockCookingSessionRepository) GetByToken(token string) (*models.CookingSession, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CookingSession), args.Error(1)
+}
+
+func (m *MockCookingSessionRepository) UpdateProgress(token string, currentStep int, completed bool) (*models.CookingSession, error) {
+	args := m.Called(token, currentStep, completed)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CookingSession), args.Error(1)
+}
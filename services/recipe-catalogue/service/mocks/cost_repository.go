@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCostRepository struct {
+	mock.Mock
+}
+
+func (m *MockCostRepository) AddPrice(ingredientID int, req models.SetIngredientPriceRequest) (*models.IngredientPrice, error) {
+	args := m.Called(ingredientID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientPrice), args.Error(1)
+}
+
+func (m *MockCostRepository) GetPriceHistory(ingredientID int) ([]models.IngredientPrice, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IngredientPrice), args.Error(1)
+}
+
+func (m *MockCostRepository) GetCurrentPrices(ingredientIDs []int) (map[int]models.IngredientPrice, error) {
+	args := m.Called(ingredientIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]models.IngredientPrice), args.Error(1)
+}
+
+func (m *MockCostRepository) GetRecipeIDsUsingIngredient(ingredientID int) ([]int, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockCostRepository) RecordSnapshots(snapshots []models.RecipeCostSnapshot) error {
+	args := m.Called(snapshots)
+	return args.Error(0)
+}
+
+func (m *MockCostRepository) GetSnapshots(recipeID int) ([]models.RecipeCostSnapshot, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeCostSnapshot), args.Error(1)
+}
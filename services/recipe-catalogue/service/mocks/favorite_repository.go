@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFavoriteRepository struct {
+	mock.Mock
+}
+
+func (m *MockFavoriteRepository) Add(userID, recipeID int) error {
+	args := m.Called(userID, recipeID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteRepository) Remove(userID, recipeID int) error {
+	args := m.Called(userID, recipeID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteRepository) GetFavorites(userID int, params models.PaginationParams) ([]models.Recipe, int, error) {
+	args := m.Called(userID, params)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.Recipe), args.Int(1), args.Error(2)
+}
+
+func (m *MockFavoriteRepository) CountByRecipeIDs(recipeIDs []int) (map[int]int, error) {
+	args := m.Called(recipeIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]int), args.Error(1)
+}
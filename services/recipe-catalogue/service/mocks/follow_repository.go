@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFollowRepository struct {
+	mock.Mock
+}
+
+func (m *MockFollowRepository) Follow(followerID, followeeID int) error {
+	args := m.Called(followerID, followeeID)
+	return args.Error(0)
+}
+
+func (m *MockFollowRepository) Unfollow(followerID, followeeID int) error {
+	args := m.Called(followerID, followeeID)
+	return args.Error(0)
+}
+
+func (m *MockFollowRepository) GetFollowing(userID int) ([]models.Follow, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Follow), args.Error(1)
+}
+
+func (m *MockFollowRepository) GetFollowers(userID int) ([]models.Follow, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Follow), args.Error(1)
+}
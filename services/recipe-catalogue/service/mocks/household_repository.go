@@ -0,0 +1,58 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHouseholdRepository struct {
+	mock.Mock
+}
+
+func (m *MockHouseholdRepository) Create(ownerID int, name, inviteCode string) (*models.Household, error) {
+	args := m.Called(ownerID, name, inviteCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Household), args.Error(1)
+}
+
+func (m *MockHouseholdRepository) GetByMember(userID int) (*models.Household, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Household), args.Error(1)
+}
+
+func (m *MockHouseholdRepository) GetByInviteCode(inviteCode string) (*models.Household, error) {
+	args := m.Called(inviteCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Household), args.Error(1)
+}
+
+func (m *MockHouseholdRepository) GetHouseholdIDs(userID int) ([]int, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockHouseholdRepository) AddMember(householdID, userID int) error {
+	args := m.Called(householdID, userID)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdRepository) RemoveMember(householdID, userID int) error {
+	args := m.Called(householdID, userID)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdRepository) Delete(householdID int) error {
+	args := m.Called(householdID)
+	return args.Error(0)
+}
@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"time"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIngredientCleanupRepository struct {
+	mock.Mock
+}
+
+func (m *MockIngredientCleanupRepository) ListUnused(unusedSince time.Time, params models.PaginationParams) ([]models.UnusedIngredient, int, error) {
+	args := m.Called(unusedSince, params)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.UnusedIngredient), args.Int(1), args.Error(2)
+}
+
+func (m *MockIngredientCleanupRepository) Archive(ids []int, unusedSince time.Time) ([]int, error) {
+	args := m.Called(ids, unusedSince)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockIngredientCleanupRepository) Delete(ids []int, unusedSince time.Time) ([]int, error) {
+	args := m.Called(ids, unusedSince)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockIngredientCleanupRepository) ListDuplicates(minSimilarity float64, limit int) ([]models.DuplicateIngredients, error) {
+	args := m.Called(minSimilarity, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DuplicateIngredients), args.Error(1)
+}
+
+func (m *MockIngredientCleanupRepository) Merge(duplicateID, targetID int) (*models.IngredientMergeResult, error) {
+	args := m.Called(duplicateID, targetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientMergeResult), args.Error(1)
+}
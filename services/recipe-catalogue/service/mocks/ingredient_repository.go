@@ -46,6 +46,14 @@ func (m *MockIngredientRepository) SearchIngredients(query string, params models
 	return args.Get(0).([]models.Ingredient), args.Int(1), args.Error(2)
 }
 
+func (m *MockIngredientRepository) SuggestIngredientNames(query string, limit int) ([]string, error) {
+	args := m.Called(query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockIngredientRepository) CreateIngredient(req models.CreateIngredientRequest) (*models.Ingredient, error) {
 	args := m.Called(req)
 	if args.Get(0) == nil {
@@ -72,6 +80,22 @@ func (m *MockIngredientRepository) IngredientExists(id int) (bool, error) {
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockIngredientRepository) FindMissingIngredientIDs(ids []int) ([]int, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockIngredientRepository) MatchIngredientNames(names []string) (map[string]int, error) {
+	args := m.Called(names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
 // =============================================================================
 // RECIPE-INGREDIENT RELATIONSHIP OPERATIONS
 // =============================================================================
@@ -129,3 +153,200 @@ func (m *MockIngredientRepository) GetRecipesUsingIngredient(ingredientID int, p
 	}
 	return args.Get(0).([]models.Recipe), args.Int(1), args.Error(2)
 }
+
+func (m *MockIngredientRepository) GetPopularIngredients(limit int) ([]models.IngredientUsage, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IngredientUsage), args.Error(1)
+}
+
+func (m *MockIngredientRepository) GetDensity(ingredientID int) (*models.IngredientDensity, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientDensity), args.Error(1)
+}
+
+func (m *MockIngredientRepository) ListDensities(params models.PaginationParams) ([]models.IngredientDensity, int, error) {
+	args := m.Called(params)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.IngredientDensity), args.Int(1), args.Error(2)
+}
+
+func (m *MockIngredientRepository) UpsertDensity(ingredientID int, req models.SetIngredientDensityRequest) (*models.IngredientDensity, error) {
+	args := m.Called(ingredientID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientDensity), args.Error(1)
+}
+
+func (m *MockIngredientRepository) DeleteDensity(ingredientID int) error {
+	args := m.Called(ingredientID)
+	return args.Error(0)
+}
+
+func (m *MockIngredientRepository) GetDensities(ingredientIDs []int) (map[int]float64, error) {
+	args := m.Called(ingredientIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]float64), args.Error(1)
+}
+
+func (m *MockIngredientRepository) GetPackSizes(ingredientID int) ([]models.PackSize, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PackSize), args.Error(1)
+}
+
+func (m *MockIngredientRepository) SetPackSizes(ingredientID int, packSizes []models.PackSizeRequest) error {
+	args := m.Called(ingredientID, packSizes)
+	return args.Error(0)
+}
+
+func (m *MockIngredientRepository) GetPackSizesForIngredients(ingredientIDs []int) (map[int][]models.PackSize, error) {
+	args := m.Called(ingredientIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int][]models.PackSize), args.Error(1)
+}
+
+func (m *MockIngredientRepository) GetProducts(ingredientID int) ([]models.IngredientProduct, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IngredientProduct), args.Error(1)
+}
+
+func (m *MockIngredientRepository) CreateProduct(ingredientID int, req models.IngredientProductRequest) (*models.IngredientProduct, error) {
+	args := m.Called(ingredientID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientProduct), args.Error(1)
+}
+
+func (m *MockIngredientRepository) UpdateProduct(ingredientID, productID int, req models.IngredientProductRequest) (*models.IngredientProduct, error) {
+	args := m.Called(ingredientID, productID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IngredientProduct), args.Error(1)
+}
+
+func (m *MockIngredientRepository) DeleteProduct(ingredientID, productID int) error {
+	args := m.Called(ingredientID, productID)
+	return args.Error(0)
+}
+
+func (m *MockIngredientRepository) GetProductsByID(productIDs []int) (map[int]models.IngredientProduct, error) {
+	args := m.Called(productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]models.IngredientProduct), args.Error(1)
+}
+
+func (m *MockIngredientRepository) GetSubstitutions(ingredientID int) ([]models.IngredientSubstitution, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IngredientSubstitution), args.Error(1)
+}
+
+func (m *MockIngredientRepository) SetSubstitutions(ingredientID int, substitutions []models.SubstitutionRequest) error {
+	args := m.Called(ingredientID, substitutions)
+	return args.Error(0)
+}
+
+func (m *MockIngredientRepository) GetSubstitutionsForIngredients(ingredientIDs []int) (map[int][]models.IngredientSubstitution, error) {
+	args := m.Called(ingredientIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int][]models.IngredientSubstitution), args.Error(1)
+}
+
+func (m *MockIngredientRepository) GetTranslations(ingredientID int) ([]models.IngredientTranslation, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IngredientTranslation), args.Error(1)
+}
+
+func (m *MockIngredientRepository) SetTranslations(ingredientID int, translations []models.IngredientTranslation) error {
+	args := m.Called(ingredientID, translations)
+	return args.Error(0)
+}
+
+func (m *MockIngredientRepository) GetLocalizedNames(ingredientIDs []int, locales []string) (map[int]string, error) {
+	args := m.Called(ingredientIDs, locales)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]string), args.Error(1)
+}
+
+func (m *MockIngredientRepository) GetDietaryFacts(ingredientIDs []int) (map[int]models.DietaryFacts, error) {
+	args := m.Called(ingredientIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]models.DietaryFacts), args.Error(1)
+}
+
+func (m *MockIngredientRepository) SetDietaryFacts(ingredientID int, facts models.DietaryFacts) error {
+	args := m.Called(ingredientID, facts)
+	return args.Error(0)
+}
+
+func (m *MockIngredientRepository) GetAllergens() ([]models.Allergen, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Allergen), args.Error(1)
+}
+
+func (m *MockIngredientRepository) GetIngredientAllergens(ingredientID int) ([]models.Allergen, error) {
+	args := m.Called(ingredientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Allergen), args.Error(1)
+}
+
+func (m *MockIngredientRepository) SetIngredientAllergens(ingredientID int, allergenIDs []int) error {
+	args := m.Called(ingredientID, allergenIDs)
+	return args.Error(0)
+}
+
+func (m *MockIngredientRepository) AddIngredientAllergen(ingredientID, allergenID int) error {
+	args := m.Called(ingredientID, allergenID)
+	return args.Error(0)
+}
+
+func (m *MockIngredientRepository) RemoveIngredientAllergen(ingredientID, allergenID int) error {
+	args := m.Called(ingredientID, allergenID)
+	return args.Error(0)
+}
+
+func (m *MockIngredientRepository) GetRecipeAllergens(recipeID int) ([]models.RecipeAllergen, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeAllergen), args.Error(1)
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLineageRepository struct {
+	mock.Mock
+}
+
+func (m *MockLineageRepository) Record(recipeID, parentID int) error {
+	args := m.Called(recipeID, parentID)
+	return args.Error(0)
+}
+
+func (m *MockLineageRepository) GetAncestors(recipeID int) ([]models.Recipe, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Recipe), args.Error(1)
+}
+
+func (m *MockLineageRepository) GetForks(recipeID int) ([]models.RecipeFork, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeFork), args.Error(1)
+}
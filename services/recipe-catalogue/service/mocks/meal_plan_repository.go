@@ -0,0 +1,101 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMealPlanRepository struct {
+	mock.Mock
+}
+
+func (m *MockMealPlanRepository) GetByUserID(userID int) ([]models.MealPlan, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MealPlan), args.Error(1)
+}
+
+func (m *MockMealPlanRepository) GetByUserIDInRange(userID int, from, to string) ([]models.MealPlan, error) {
+	args := m.Called(userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MealPlan), args.Error(1)
+}
+
+func (m *MockMealPlanRepository) GetByID(id int) (*models.MealPlan, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlan), args.Error(1)
+}
+
+func (m *MockMealPlanRepository) Create(userID int, req models.MealPlanRequest, slug string, entries []models.MealPlanEntryRequest) (*models.MealPlan, error) {
+	args := m.Called(userID, req, slug, entries)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlan), args.Error(1)
+}
+
+func (m *MockMealPlanRepository) Update(id int, req models.MealPlanRequest) (*models.MealPlan, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlan), args.Error(1)
+}
+
+func (m *MockMealPlanRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockMealPlanRepository) AddEntry(planID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error) {
+	args := m.Called(planID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlanEntry), args.Error(1)
+}
+
+func (m *MockMealPlanRepository) UpdateEntry(planID, entryID int, req models.MealPlanEntryRequest) (*models.MealPlanEntry, error) {
+	args := m.Called(planID, entryID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlanEntry), args.Error(1)
+}
+
+func (m *MockMealPlanRepository) DeleteEntry(planID, entryID int) error {
+	args := m.Called(planID, entryID)
+	return args.Error(0)
+}
+
+func (m *MockMealPlanRepository) SetMembers(planID int, names []string, meals [][]int) ([]models.MealPlanMember, error) {
+	args := m.Called(planID, names, meals)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MealPlanMember), args.Error(1)
+}
+
+func (m *MockMealPlanRepository) GetPreferences(userID int) (*models.MealPlanPreferences, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlanPreferences), args.Error(1)
+}
+
+func (m *MockMealPlanRepository) SavePreferences(prefs models.MealPlanPreferences) (*models.MealPlanPreferences, error) {
+	args := m.Called(prefs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MealPlanPreferences), args.Error(1)
+}
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPantryRepository struct {
+	mock.Mock
+}
+
+func (m *MockPantryRepository) GetPantry(userID int) (*models.Pantry, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Pantry), args.Error(1)
+}
+
+func (m *MockPantryRepository) SetPantry(userID int, ingredientIDs []int, version int) (*models.PantryChange, error) {
+	args := m.Called(userID, ingredientIDs, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PantryChange), args.Error(1)
+}
+
+func (m *MockPantryRepository) EditPantry(userID int, add, remove []int) (*models.PantryChange, error) {
+	args := m.Called(userID, add, remove)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PantryChange), args.Error(1)
+}
+
+func (m *MockPantryRepository) FindCookable(ingredientIDs []int, params models.PaginationParams) ([]models.CookableRecipe, int, error) {
+	args := m.Called(ingredientIDs, params)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.CookableRecipe), args.Int(1), args.Error(2)
+}
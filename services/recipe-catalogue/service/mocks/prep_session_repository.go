@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPrepSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPrepSessionRepository) GetByUserID(userID int) ([]models.PrepSession, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PrepSession), args.Error(1)
+}
+
+func (m *MockPrepSessionRepository) GetByID(id int) (*models.PrepSession, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PrepSession), args.Error(1)
+}
+
+func (m *MockPrepSessionRepository) Create(userID int, req models.PrepSessionRequest) (*models.PrepSession, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PrepSession), args.Error(1)
+}
+
+func (m *MockPrepSessionRepository) Update(id int, req models.PrepSessionRequest) (*models.PrepSession, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PrepSession), args.Error(1)
+}
+
+func (m *MockPrepSessionRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
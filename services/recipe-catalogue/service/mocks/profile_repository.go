@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProfileRepository struct {
+	mock.Mock
+}
+
+func (m *MockProfileRepository) GetSettings(userID int) (*models.ProfileSettings, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProfileSettings), args.Error(1)
+}
+
+func (m *MockProfileRepository) SaveSettings(settings models.ProfileSettings) (*models.ProfileSettings, error) {
+	args := m.Called(settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProfileSettings), args.Error(1)
+}
+
+func (m *MockProfileRepository) GetRatingSummary(userID int) (*float64, int, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).(*float64), args.Int(1), args.Error(2)
+}
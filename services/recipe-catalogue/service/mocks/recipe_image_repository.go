@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecipeImageRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecipeImageRepository) GetByRecipeID(recipeID int) ([]models.RecipeImage, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeImage), args.Error(1)
+}
+
+func (m *MockRecipeImageRepository) Add(recipeID int, req models.AddRecipeImageRequest) (*models.RecipeImage, error) {
+	args := m.Called(recipeID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeImage), args.Error(1)
+}
+
+func (m *MockRecipeImageRepository) Save(recipeID int, images []models.RecipeImage) error {
+	args := m.Called(recipeID, images)
+	return args.Error(0)
+}
+
+func (m *MockRecipeImageRepository) Delete(recipeID, imageID int) error {
+	args := m.Called(recipeID, imageID)
+	return args.Error(0)
+}
+
+func (m *MockRecipeImageRepository) GetByRecipeIDs(recipeIDs []int) (map[int][]models.RecipeImage, error) {
+	args := m.Called(recipeIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int][]models.RecipeImage), args.Error(1)
+}
@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"time"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecipeOwnershipRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecipeOwnershipRepository) ListOwners(inactiveSince time.Time, params models.PaginationParams) ([]models.RecipeOwnership, int, error) {
+	args := m.Called(inactiveSince, params)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.RecipeOwnership), args.Int(1), args.Error(2)
+}
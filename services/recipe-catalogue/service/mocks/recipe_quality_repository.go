@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecipeQualityRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecipeQualityRepository) GetFacts(recipeID int) (*models.RecipeQualityFacts, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeQualityFacts), args.Error(1)
+}
+
+func (m *MockRecipeQualityRepository) ListFacts(status string) ([]models.RecipeQualityFacts, error) {
+	args := m.Called(status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeQualityFacts), args.Error(1)
+}
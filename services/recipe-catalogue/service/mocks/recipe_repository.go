@@ -3,6 +3,7 @@ package mocks
 import (
 	"github.com/stretchr/testify/mock"
 	"meal-prep/shared/models"
+	"time"
 )
 
 type MockRecipeRepository struct {
@@ -33,6 +34,14 @@ func (m *MockRecipeRepository) GetByCategory(categoryID int, params models.Pagin
 	return args.Get(0).([]models.Recipe), args.Int(1), args.Error(2)
 }
 
+func (m *MockRecipeRepository) GetByOwner(userID int, status string, params models.PaginationParams) ([]models.Recipe, int, error) {
+	args := m.Called(userID, status, params)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.Recipe), args.Int(1), args.Error(2)
+}
+
 func (m *MockRecipeRepository) GetOwnerID(id int) (int, error) {
 	args := m.Called(id)
 	return args.Int(0), args.Error(1)
@@ -114,3 +123,35 @@ func (m *MockRecipeRepository) SearchRecipesByIngredientsWithIngredients(ingredi
 	}
 	return args.Get(0).([]models.RecipeWithIngredients), args.Int(1), args.Error(2)
 }
+
+func (m *MockRecipeRepository) GetSearchFacets(ingredientIDs []int, maxMinutes []int) (*models.RecipeFacets, error) {
+	args := m.Called(ingredientIDs, maxMinutes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeFacets), args.Error(1)
+}
+
+func (m *MockRecipeRepository) GetPublishedRecipes() ([]models.PublishedRecipe, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PublishedRecipe), args.Error(1)
+}
+
+func (m *MockRecipeRepository) GetRecentlyUpdated(limit int) ([]models.Recipe, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Recipe), args.Error(1)
+}
+
+func (m *MockRecipeRepository) GetCreatedSince(since time.Time) ([]models.Recipe, error) {
+	args := m.Called(since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Recipe), args.Error(1)
+}
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecipeTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecipeTemplateRepository) GetAll() ([]models.RecipeTemplate, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeTemplate), args.Error(1)
+}
+
+func (m *MockRecipeTemplateRepository) GetByID(id int) (*models.RecipeTemplate, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeTemplate), args.Error(1)
+}
+
+func (m *MockRecipeTemplateRepository) Create(userID int, req models.CreateRecipeTemplateRequest) (*models.RecipeTemplate, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeTemplate), args.Error(1)
+}
+
+func (m *MockRecipeTemplateRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"time"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSavedSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchRepository) GetByUserID(userID int) ([]models.SavedSearch, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) GetByID(id int) (*models.SavedSearch, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) Create(userID int, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) Update(id int, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepository) GetAlerting() ([]models.SavedSearch, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) MarkChecked(id int, checkedAt time.Time) error {
+	args := m.Called(id, checkedAt)
+	return args.Error(0)
+}
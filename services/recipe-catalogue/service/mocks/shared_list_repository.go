@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSharedListRepository struct {
+	mock.Mock
+}
+
+func (m *MockSharedListRepository) Create(list models.SharedShoppingList) (*models.SharedShoppingList, error) {
+	args := m.Called(list)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SharedShoppingList), args.Error(1)
+}
+
+func (m *MockSharedListRepository) GetByToken(token string) (*models.SharedShoppingList, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SharedShoppingList), args.Error(1)
+}
+
+func (m *MockSharedListRepository) SetItemChecked(token string, position int, checked bool) (*models.SharedShoppingListChange, error) {
+	args := m.Called(token, position, checked)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SharedShoppingListChange), args.Error(1)
+}
+
+func (m *MockSharedListRepository) Delete(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStepRepository struct {
+	mock.Mock
+}
+
+func (m *MockStepRepository) GetByRecipeID(recipeID int) ([]models.RecipeStep, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeStep), args.Error(1)
+}
+
+func (m *MockStepRepository) ReplaceForRecipe(recipeID int, steps []models.CreateStepRequest) ([]models.RecipeStep, error) {
+	args := m.Called(recipeID, steps)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeStep), args.Error(1)
+}
+
+func (m *MockStepRepository) Add(recipeID int, step models.CreateStepRequest) (*models.RecipeStep, error) {
+	args := m.Called(recipeID, step)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeStep), args.Error(1)
+}
+
+func (m *MockStepRepository) Update(recipeID, stepID int, step models.CreateStepRequest) (*models.RecipeStep, error) {
+	args := m.Called(recipeID, stepID, step)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecipeStep), args.Error(1)
+}
+
+func (m *MockStepRepository) Delete(recipeID, stepID int) error {
+	args := m.Called(recipeID, stepID)
+	return args.Error(0)
+}
+
+func (m *MockStepRepository) Reorder(recipeID int, stepIDs []int) ([]models.RecipeStep, error) {
+	args := m.Called(recipeID, stepIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeStep), args.Error(1)
+}
+
+func (m *MockStepRepository) GetTechniques(recipeID int) ([]string, error) {
+	args := m.Called(recipeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockStepRepository) ReplaceTechniques(recipeID int, techniques []string) error {
+	args := m.Called(recipeID, techniques)
+	return args.Error(0)
+}
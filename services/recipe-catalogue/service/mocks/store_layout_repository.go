@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStoreLayoutRepository struct {
+	mock.Mock
+}
+
+func (m *MockStoreLayoutRepository) GetByUserID(userID int) ([]models.StoreLayout, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StoreLayout), args.Error(1)
+}
+
+func (m *MockStoreLayoutRepository) GetByID(id int) (*models.StoreLayout, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StoreLayout), args.Error(1)
+}
+
+func (m *MockStoreLayoutRepository) Create(userID int, req models.StoreLayoutRequest) (*models.StoreLayout, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StoreLayout), args.Error(1)
+}
+
+func (m *MockStoreLayoutRepository) Update(id int, req models.StoreLayoutRequest) (*models.StoreLayout, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StoreLayout), args.Error(1)
+}
+
+func (m *MockStoreLayoutRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+)
+
+const (
+	// nutritionLookupMatches is how many foods a lookup returns
+	nutritionLookupMatches = 10
+
+	minNutritionQuery = 2
+	maxNutritionQuery = 100
+
+	// maxCachedNutritionLookups bounds the cache, which any client can fill
+	// with queries of their own
+	maxCachedNutritionLookups = 1000
+
+	defaultNutritionLookupsPerMinute = 15
+	defaultNutritionCacheTTL         = 24 * time.Hour
+)
+
+// NutritionLookupsPerMinuteFromEnv reads how many lookups a minute may reach
+// the nutrition API across all clients (NUTRITION_LOOKUPS_PER_MINUTE,
+// default 15; 0 leaves them uncapped).
+func NutritionLookupsPerMinuteFromEnv() int {
+	if limit, err := strconv.Atoi(os.Getenv("NUTRITION_LOOKUPS_PER_MINUTE")); err == nil && limit >= 0 {
+		return limit
+	}
+	return defaultNutritionLookupsPerMinute
+}
+
+// NutritionCacheTTLFromEnv reads how long a lookup's answer is reused
+// (NUTRITION_CACHE_TTL, default 24h).
+func NutritionCacheTTLFromEnv() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("NUTRITION_CACHE_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return defaultNutritionCacheTTL
+}
+
+type NutritionLookupService interface {
+	// LookupNutrition searches the nutrition database for foods matching
+	// query, answering repeated queries from a cache. Every client shares
+	// the one API key, so lookups reaching the database are capped per
+	// minute across all of them.
+	LookupNutrition(ctx context.Context, query string) (*models.NutritionLookup, error)
+}
+
+type nutritionLookupService struct {
+	source    NutritionSource
+	ttl       time.Duration
+	perMinute int
+	now       func() time.Time
+
+	mu         sync.Mutex
+	cache      map[string]cachedNutritionLookup
+	window     time.Time // start of the current minute
+	windowUsed int
+}
+
+type cachedNutritionLookup struct {
+	lookup  models.NutritionLookup
+	expires time.Time
+}
+
+// NewNutritionLookupService looks nutrition up in source, or reports lookups
+// unavailable when source is nil.
+func NewNutritionLookupService(source NutritionSource, ttl time.Duration, perMinute int) NutritionLookupService {
+	return &nutritionLookupService{
+		source:    source,
+		ttl:       ttl,
+		perMinute: perMinute,
+		now:       time.Now,
+		cache:     make(map[string]cachedNutritionLookup),
+	}
+}
+
+func (s *nutritionLookupService) LookupNutrition(ctx context.Context, query string) (*models.NutritionLookup, error) {
+	query = strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if length := utf8.RuneCountInString(query); length < minNutritionQuery || length > maxNutritionQuery {
+		return nil, domain.ErrInvalidNutritionQuery
+	}
+	if s.source == nil {
+		return nil, domain.ErrNutritionLookupUnavailable
+	}
+
+	if lookup, ok := s.cached(query); ok {
+		return lookup, nil
+	}
+	if !s.reserve() {
+		return nil, domain.ErrNutritionLookupLimited
+	}
+
+	matches, err := s.source.SearchFoods(ctx, query, nutritionLookupMatches)
+	if err != nil {
+		logging.WithContext(ctx).Warn("Failed to look up nutrition", "query", query, "error", err)
+		return nil, domain.ErrNutritionSourceUnavailable
+	}
+	lookup := models.NutritionLookup{Query: query, Matches: matches}
+	s.store(query, lookup)
+	return &lookup, nil
+}
+
+func (s *nutritionLookupService) cached(query string) (*models.NutritionLookup, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.cache[query]
+	if !ok || !s.now().Before(entry.expires) {
+		return nil, false
+	}
+	lookup := entry.lookup
+	return &lookup, true
+}
+
+// reserve counts a lookup against the current minute, reporting false when
+// the minute's lookups are used up. Windows are aligned to the clock.
+func (s *nutritionLookupService) reserve() bool {
+	if s.perMinute == 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if minute := s.now().Truncate(time.Minute); !minute.Equal(s.window) {
+		s.window, s.windowUsed = minute, 0
+	}
+	if s.windowUsed >= s.perMinute {
+		return false
+	}
+	s.windowUsed++
+	return true
+}
+
+// store caches a lookup, making room first by dropping expired lookups and
+// then the one closest to expiring.
+func (s *nutritionLookupService) store(query string, lookup models.NutritionLookup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if _, ok := s.cache[query]; !ok && len(s.cache) >= maxCachedNutritionLookups {
+		var oldest string
+		for key, entry := range s.cache {
+			if !now.Before(entry.expires) {
+				delete(s.cache, key)
+			} else if oldest == "" || entry.expires.Before(s.cache[oldest].expires) {
+				oldest = key
+			}
+		}
+		if len(s.cache) >= maxCachedNutritionLookups {
+			delete(s.cache, oldest)
+		}
+	}
+	s.cache[query] = cachedNutritionLookup{lookup: lookup, expires: now.Add(s.ttl)}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNutritionSource finds one food named after each query and records
+// the queries it was asked.
+type fakeNutritionSource struct {
+	queries []string
+	err     error
+}
+
+func (f *fakeNutritionSource) SearchFoods(ctx context.Context, query string, limit int) ([]models.NutritionMatch, error) {
+	f.queries = append(f.queries, query)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []models.NutritionMatch{{SourceID: "1", Name: query, Nutrition: models.ProductNutrition{CaloriesKcal: 100}}}, nil
+}
+
+func setupNutritionLookupServiceTest(perMinute int) (*nutritionLookupService, *fakeNutritionSource, *time.Time) {
+	logging.Init("test")
+	source := &fakeNutritionSource{}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewNutritionLookupService(source, time.Hour, perMinute).(*nutritionLookupService)
+	s.now = func() time.Time { return now }
+	return s, source, &now
+}
+
+func TestNutritionLookupService_LookupNutrition_CachesNormalizedQueries(t *testing.T) {
+	s, source, _ := setupNutritionLookupServiceTest(10)
+
+	first, err := s.LookupNutrition(context.Background(), "  Rolled   Oats ")
+	require.NoError(t, err)
+	second, err := s.LookupNutrition(context.Background(), "rolled oats")
+	require.NoError(t, err)
+
+	assert.Equal(t, "rolled oats", first.Query)
+	assert.Equal(t, first, second)
+	assert.Equal(t, []string{"rolled oats"}, source.queries)
+}
+
+func TestNutritionLookupService_LookupNutrition_ExpiredLookupIsRefetched(t *testing.T) {
+	s, source, now := setupNutritionLookupServiceTest(10)
+
+	_, err := s.LookupNutrition(context.Background(), "oats")
+	require.NoError(t, err)
+	*now = now.Add(time.Hour)
+	_, err = s.LookupNutrition(context.Background(), "oats")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"oats", "oats"}, source.queries)
+}
+
+func TestNutritionLookupService_LookupNutrition_CapsLookupsPerMinute(t *testing.T) {
+	s, source, now := setupNutritionLookupServiceTest(1)
+
+	_, err := s.LookupNutrition(context.Background(), "oats")
+	require.NoError(t, err)
+
+	_, err = s.LookupNutrition(context.Background(), "barley")
+	assert.Equal(t, domain.ErrNutritionLookupLimited, err)
+
+	// Cached lookups don't count against the cap
+	_, err = s.LookupNutrition(context.Background(), "oats")
+	assert.NoError(t, err)
+
+	*now = now.Add(time.Minute)
+	_, err = s.LookupNutrition(context.Background(), "barley")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"oats", "barley"}, source.queries)
+}
+
+func TestNutritionLookupService_LookupNutrition_InvalidQuery(t *testing.T) {
+	s, source, _ := setupNutritionLookupServiceTest(10)
+
+	for _, query := range []string{"", " a ", strings.Repeat("a", 101)} {
+		_, err := s.LookupNutrition(context.Background(), query)
+		assert.Equal(t, domain.ErrInvalidNutritionQuery, err)
+	}
+	assert.Empty(t, source.queries)
+}
+
+func TestNutritionLookupService_LookupNutrition_Unconfigured(t *testing.T) {
+	s := NewNutritionLookupService(nil, time.Hour, 10)
+
+	_, err := s.LookupNutrition(context.Background(), "oats")
+
+	assert.Equal(t, domain.ErrNutritionLookupUnavailable, err)
+}
+
+func TestNutritionLookupService_LookupNutrition_SourceFailureIsNotCached(t *testing.T) {
+	s, source, _ := setupNutritionLookupServiceTest(10)
+	source.err = errors.New("nutrition search failed with status 503")
+
+	_, err := s.LookupNutrition(context.Background(), "oats")
+	assert.Equal(t, domain.ErrNutritionSourceUnavailable, err)
+
+	source.err = nil
+	lookup, err := s.LookupNutrition(context.Background(), "oats")
+	require.NoError(t, err)
+	assert.Len(t, lookup.Matches, 1)
+	assert.Len(t, source.queries, 2)
+}
+
+func TestNutritionLookupService_LookupNutrition_FullCacheDropsOldestLookup(t *testing.T) {
+	s, source, now := setupNutritionLookupServiceTest(0)
+
+	for i := 0; i < maxCachedNutritionLookups; i++ {
+		_, err := s.LookupNutrition(context.Background(), fmt.Sprintf("food %d", i))
+		require.NoError(t, err)
+		*now = now.Add(time.Millisecond)
+	}
+	_, err := s.LookupNutrition(context.Background(), "one more")
+	require.NoError(t, err)
+
+	assert.Len(t, s.cache, maxCachedNutritionLookups)
+	assert.NotContains(t, s.cache, "food 0")
+	assert.Contains(t, s.cache, "food 1")
+	assert.Len(t, source.queries, maxCachedNutritionLookups+1)
+}
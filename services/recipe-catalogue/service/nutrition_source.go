@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"meal-prep/shared/models"
+)
+
+// NutritionSource searches an external nutrition database. Handlers wire in
+// a FoodDataClient when an API key is configured.
+type NutritionSource interface {
+	SearchFoods(ctx context.Context, query string, limit int) ([]models.NutritionMatch, error)
+}
+
+// FoodDataClient searches USDA FoodData Central, whose nutrients are given
+// per 100 g.
+type FoodDataClient struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewFoodDataClient searches the foods search endpoint at endpoint with
+// apiKey, which is sent in a header so it stays out of logged URLs.
+func NewFoodDataClient(endpoint, apiKey string, client *http.Client) *FoodDataClient {
+	return &FoodDataClient{endpoint: endpoint, apiKey: apiKey, client: client}
+}
+
+// FoodData Central nutrient numbers
+const (
+	nutrientEnergyKcal = "208"
+	nutrientProtein    = "203"
+	nutrientFat        = "204"
+	nutrientCarbs      = "205"
+	nutrientFiber      = "291"
+)
+
+type foodDataSearchResponse struct {
+	Foods []struct {
+		FdcID         int    `json:"fdcId"`
+		Description   string `json:"description"`
+		BrandOwner    string `json:"brandOwner"`
+		BrandName     string `json:"brandName"`
+		FoodNutrients []struct {
+			NutrientNumber string  `json:"nutrientNumber"`
+			Value          float64 `json:"value"`
+		} `json:"foodNutrients"`
+	} `json:"foods"`
+}
+
+func (c *FoodDataClient) SearchFoods(ctx context.Context, query string, limit int) ([]models.NutritionMatch, error) {
+	params := url.Values{"query": {query}, "pageSize": {strconv.Itoa(limit)}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Api-Key", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nutrition search failed with status %d", resp.StatusCode)
+	}
+	var found foodDataSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&found); err != nil {
+		return nil, err
+	}
+
+	matches := make([]models.NutritionMatch, 0, len(found.Foods))
+	for _, food := range found.Foods {
+		match := models.NutritionMatch{SourceID: strconv.Itoa(food.FdcID), Name: food.Description}
+		if brand := food.BrandName; brand != "" {
+			match.Brand = &brand
+		} else if owner := food.BrandOwner; owner != "" {
+			match.Brand = &owner
+		}
+		for _, nutrient := range food.FoodNutrients {
+			switch nutrient.NutrientNumber {
+			case nutrientEnergyKcal:
+				match.Nutrition.CaloriesKcal = nutrient.Value
+			case nutrientProtein:
+				match.Nutrition.ProteinG = nutrient.Value
+			case nutrientFat:
+				match.Nutrition.FatG = nutrient.Value
+			case nutrientCarbs:
+				match.Nutrition.CarbsG = nutrient.Value
+			case nutrientFiber:
+				match.Nutrition.FiberG = nutrient.Value
+			}
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoodDataClient_SearchFoods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		assert.Empty(t, r.URL.Query().Get("api_key"))
+		assert.Equal(t, "rolled oats", r.URL.Query().Get("query"))
+		assert.Equal(t, "10", r.URL.Query().Get("pageSize"))
+		w.Write([]byte(`{"foods": [
+			{"fdcId": 173904, "description": "Oats", "foodNutrients": [
+				{"nutrientNumber": "208", "unitName": "KCAL", "value": 389},
+				{"nutrientNumber": "268", "unitName": "kJ", "value": 1628},
+				{"nutrientNumber": "203", "unitName": "G", "value": 16.9},
+				{"nutrientNumber": "204", "unitName": "G", "value": 6.9},
+				{"nutrientNumber": "205", "unitName": "G", "value": 66.3},
+				{"nutrientNumber": "291", "unitName": "G", "value": 10.6}]},
+			{"fdcId": 2001, "description": "Porridge Oats", "brandOwner": "Mills Ltd", "foodNutrients": []}]}`))
+	}))
+	defer server.Close()
+
+	matches, err := NewFoodDataClient(server.URL, "secret", server.Client()).SearchFoods(context.Background(), "rolled oats", 10)
+
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "173904", matches[0].SourceID)
+	assert.Nil(t, matches[0].Brand)
+	assert.Equal(t, models.ProductNutrition{CaloriesKcal: 389, ProteinG: 16.9, CarbsG: 66.3, FatG: 6.9, FiberG: 10.6}, matches[0].Nutrition)
+	require.NotNil(t, matches[1].Brand)
+	assert.Equal(t, "Mills Ltd", *matches[1].Brand)
+}
+
+func TestFoodDataClient_SearchFoods_Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	_, err := NewFoodDataClient(server.URL, "secret", server.Client()).SearchFoods(context.Background(), "oats", 10)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"meal-prep/shared/models"
+	"meal-prep/shared/units"
+)
+
+// packTolerance absorbs floating point noise from unit conversion so that
+// exactly two packs' worth is not rounded up to three.
+const packTolerance = 1e-6
+
+// suggestPurchase picks the pack size that covers quantity (in unit) with the
+// least left over, preferring fewer packs on a tie. Pack sizes that cannot be
+// expressed in unit are skipped; nil means none applies.
+func suggestPurchase(quantity float64, unit string, packSizes []models.PackSize, gramsPerML float64) *models.PackPurchase {
+	if quantity <= 0 {
+		return nil
+	}
+
+	var best *models.PackPurchase
+	for _, packSize := range packSizes {
+		size, ok := packSizeIn(packSize, unit, gramsPerML)
+		if !ok {
+			continue
+		}
+
+		packs := int(math.Ceil(quantity/size - packTolerance))
+		if packs < 1 {
+			packs = 1
+		}
+		leftover := math.Max(0, float64(packs)*size-quantity)
+
+		if best == nil || leftover < best.Leftover-packTolerance ||
+			(math.Abs(leftover-best.Leftover) <= packTolerance && packs < best.Packs) {
+			best = &models.PackPurchase{PackSize: packSize, Packs: packs, Leftover: leftover}
+		}
+	}
+
+	if best != nil {
+		best.Leftover = math.Round(best.Leftover*100) / 100
+		best.Note = purchaseNote(best, unit)
+	}
+	return best
+}
+
+// packSizeIn expresses a pack size in unit.
+func packSizeIn(packSize models.PackSize, unit string, gramsPerML float64) (float64, bool) {
+	if units.Normalize(packSize.Unit) == units.Normalize(unit) {
+		return packSize.Quantity, true
+	}
+	converted, err := units.Convert(packSize.Quantity, packSize.Unit, unit, gramsPerML)
+	if err != nil || converted <= 0 {
+		return 0, false
+	}
+	return converted, true
+}
+
+func purchaseNote(purchase *models.PackPurchase, unit string) string {
+	pack := formatQuantity(purchase.PackSize.Quantity) + " " + purchase.PackSize.Unit
+	if purchase.PackSize.Label != nil && *purchase.PackSize.Label != "" {
+		pack += " " + *purchase.PackSize.Label
+	}
+
+	note := fmt.Sprintf("Buy %d × %s", purchase.Packs, pack)
+	if purchase.Leftover > 0 {
+		note += fmt.Sprintf("; you'll have %s %s left over", formatQuantity(purchase.Leftover), unit)
+	}
+	return note
+}
+
+func formatQuantity(quantity float64) string {
+	return strconv.FormatFloat(math.Round(quantity*100)/100, 'f', -1, 64)
+}
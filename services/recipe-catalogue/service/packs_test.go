@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func packSize(quantity float64, unit, label string) models.PackSize {
+	return models.PackSize{Quantity: quantity, Unit: unit, Label: &label}
+}
+
+func TestSuggestPurchase_RoundsUpToWholePacks(t *testing.T) {
+	purchase := suggestPurchase(650, "g", []models.PackSize{packSize(400, "g", "can")}, 0)
+
+	require.NotNil(t, purchase)
+	assert.Equal(t, 2, purchase.Packs)
+	assert.Equal(t, 150.0, purchase.Leftover)
+	assert.Equal(t, "Buy 2 × 400 g can; you'll have 150 g left over", purchase.Note)
+}
+
+func TestSuggestPurchase_ExactFitHasNoLeftoverNote(t *testing.T) {
+	purchase := suggestPurchase(0.8, "kg", []models.PackSize{packSize(400, "grams", "can")}, 0)
+
+	require.NotNil(t, purchase)
+	assert.Equal(t, 2, purchase.Packs)
+	assert.Equal(t, 0.0, purchase.Leftover)
+	assert.Equal(t, "Buy 2 × 400 grams can", purchase.Note)
+}
+
+func TestSuggestPurchase_PicksSizeWithLeastWaste(t *testing.T) {
+	sizes := []models.PackSize{packSize(6, "pieces", "box"), packSize(12, "pieces", "box")}
+
+	purchase := suggestPurchase(10, "pieces", sizes, 0)
+
+	require.NotNil(t, purchase)
+	assert.Equal(t, 12.0, purchase.PackSize.Quantity)
+	assert.Equal(t, 1, purchase.Packs)
+	assert.Equal(t, 2.0, purchase.Leftover)
+}
+
+func TestSuggestPurchase_PrefersFewerPacksOnTie(t *testing.T) {
+	sizes := []models.PackSize{packSize(6, "pieces", "box"), packSize(12, "pieces", "box")}
+
+	purchase := suggestPurchase(12, "pieces", sizes, 0)
+
+	require.NotNil(t, purchase)
+	assert.Equal(t, 1, purchase.Packs)
+	assert.Equal(t, 12.0, purchase.PackSize.Quantity)
+}
+
+func TestSuggestPurchase_UsesDensityAcrossWeightAndVolume(t *testing.T) {
+	// 2 cups of oil at 0.91 g/ml against a 500 g bottle
+	purchase := suggestPurchase(2, "cups", []models.PackSize{packSize(500, "g", "bottle")}, 0.91)
+
+	require.NotNil(t, purchase)
+	assert.Equal(t, 1, purchase.Packs)
+	assert.InDelta(t, 0.32, purchase.Leftover, 0.01)
+}
+
+func TestSuggestPurchase_NothingApplicable(t *testing.T) {
+	assert.Nil(t, suggestPurchase(2, "cups", []models.PackSize{packSize(500, "g", "bag")}, 0))
+	assert.Nil(t, suggestPurchase(3, "cloves", []models.PackSize{packSize(1, "bulb", "")}, 0))
+	assert.Nil(t, suggestPurchase(-1, "g", []models.PackSize{packSize(400, "g", "can")}, 0))
+	assert.Nil(t, suggestPurchase(100, "g", nil, 0))
+}
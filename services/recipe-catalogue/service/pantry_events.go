@@ -0,0 +1,30 @@
+package service
+
+import (
+	"meal-prep/shared/models"
+)
+
+// PantryEvents passes pantry changes on to everyone watching the pantry.
+// The pantry and household services share one, so that joining or leaving
+// a household disconnects the user from the pantry they no longer see.
+type PantryEvents struct {
+	*hub[pantryKey, models.PantryChange]
+}
+
+// pantryKey names a household's pantry, or a user's own when householdID
+// is 0.
+type pantryKey struct {
+	householdID int
+	userID      int
+}
+
+func pantryKeyOf(userID int, householdID *int) pantryKey {
+	if householdID != nil {
+		return pantryKey{householdID: *householdID}
+	}
+	return pantryKey{userID: userID}
+}
+
+func NewPantryEvents() *PantryEvents {
+	return &PantryEvents{hub: newHub[pantryKey, models.PantryChange]()}
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"database/sql"
+	"math"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+// MaxPantryIngredients caps both a pantry and an ingredient list checked
+// for cookable recipes.
+const MaxPantryIngredients = 500
+
+type PantryService interface {
+	// GetPantry returns the pantry of the user's household when they belong
+	// to one, and their own otherwise
+	GetPantry(userID int) (*models.Pantry, error)
+	// UpdatePantry replaces everything in the pantry. With req.Version it
+	// returns a *models.VersionConflictError when someone has changed the
+	// pantry since
+	UpdatePantry(userID int, req models.UpdatePantryRequest) (*models.Pantry, error)
+	// AddPantryItem and RemovePantryItem change one ingredient whatever the
+	// version, so household members changing different items never conflict
+	AddPantryItem(userID, ingredientID int) (*models.PantryChange, error)
+	RemovePantryItem(userID, ingredientID int) (*models.PantryChange, error)
+	// Watch delivers every change made to the user's pantry from now on
+	// until stop is called. The channel is closed when the user joins or
+	// leaves a household, or falls too far behind; the client should then
+	// reload the pantry.
+	Watch(userID int) (changes <-chan models.PantryChange, stop func(), err error)
+
+	// GetCookableRecipes ranks published recipes by the fraction of their
+	// ingredients in ingredientIDs, or in the user's pantry when
+	// ingredientIDs is nil, listing the ones each recipe still needs
+	GetCookableRecipes(userID int, ingredientIDs []int, params models.PaginationParams) ([]models.CookableRecipe, models.PaginationMeta, error)
+}
+
+type pantryService struct {
+	pantryRepo     repository.PantryRepository
+	ingredientRepo repository.IngredientRepository
+	imageRepo      repository.RecipeImageRepository
+	favoriteRepo   repository.FavoriteRepository
+	events         *PantryEvents
+}
+
+func NewPantryService(pantryRepo repository.PantryRepository, ingredientRepo repository.IngredientRepository, imageRepo repository.RecipeImageRepository, favoriteRepo repository.FavoriteRepository, events *PantryEvents) PantryService {
+	return &pantryService{
+		pantryRepo:     pantryRepo,
+		ingredientRepo: ingredientRepo,
+		imageRepo:      imageRepo,
+		favoriteRepo:   favoriteRepo,
+		events:         events,
+	}
+}
+
+func (s *pantryService) GetPantry(userID int) (*models.Pantry, error) {
+	return s.pantryRepo.GetPantry(userID)
+}
+
+// UpdatePantry ignores ingredients listed twice; an empty list empties the
+// pantry.
+func (s *pantryService) UpdatePantry(userID int, req models.UpdatePantryRequest) (*models.Pantry, error) {
+	ingredientIDs, err := s.validateIngredientIDs(req.IngredientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	version := 0
+	if req.Version != nil {
+		version = *req.Version
+	}
+	change, err := s.pantryRepo.SetPantry(userID, ingredientIDs, version)
+	if err == sql.ErrNoRows {
+		current, err := s.pantryRepo.GetPantry(userID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &models.VersionConflictError{CurrentVersion: current.Version}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(change)
+	return s.pantryRepo.GetPantry(userID)
+}
+
+func (s *pantryService) AddPantryItem(userID, ingredientID int) (*models.PantryChange, error) {
+	if ingredientID <= 0 {
+		return nil, domain.ErrIngredientNotFound
+	}
+	if err := ensureIngredientsExist(s.ingredientRepo, []int{ingredientID}); err != nil {
+		return nil, err
+	}
+
+	change, err := s.pantryRepo.EditPantry(userID, []int{ingredientID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(change)
+	return change, nil
+}
+
+func (s *pantryService) RemovePantryItem(userID, ingredientID int) (*models.PantryChange, error) {
+	change, err := s.pantryRepo.EditPantry(userID, nil, []int{ingredientID})
+	if err != nil {
+		return nil, err
+	}
+	if change.Empty() {
+		return nil, domain.ErrPantryItemNotFound
+	}
+	s.publish(change)
+	return change, nil
+}
+
+func (s *pantryService) Watch(userID int) (<-chan models.PantryChange, func(), error) {
+	pantry, err := s.pantryRepo.GetPantry(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, stop := s.events.watch(pantryKeyOf(userID, pantry.HouseholdID), userID)
+	return changes, stop, nil
+}
+
+// publish tells everyone watching the pantry about a change, unless it
+// changed nothing.
+func (s *pantryService) publish(change *models.PantryChange) {
+	if !change.Empty() {
+		s.events.publish(pantryKeyOf(change.ChangedBy, change.HouseholdID), *change)
+	}
+}
+
+func (s *pantryService) GetCookableRecipes(userID int, ingredientIDs []int, params models.PaginationParams) ([]models.CookableRecipe, models.PaginationMeta, error) {
+	if ingredientIDs == nil {
+		pantry, err := s.pantryRepo.GetPantry(userID)
+		if err != nil {
+			return nil, models.PaginationMeta{}, err
+		}
+		ingredientIDs = make([]int, len(pantry.Items))
+		for i, item := range pantry.Items {
+			ingredientIDs[i] = item.IngredientID
+		}
+	} else {
+		var err error
+		if ingredientIDs, err = s.validateIngredientIDs(ingredientIDs); err != nil {
+			return nil, models.PaginationMeta{}, err
+		}
+	}
+
+	recipes, total, err := s.pantryRepo.FindCookable(ingredientIDs, params)
+	if err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	if len(recipes) == 0 {
+		return recipes, models.NewPaginationMeta(params, total), nil
+	}
+
+	recipeIDs := make([]int, len(recipes))
+	pointers := make([]*models.Recipe, len(recipes))
+	for i := range recipes {
+		recipeIDs[i] = recipes[i].ID
+		pointers[i] = &recipes[i].Recipe
+	}
+	ingredients, err := s.ingredientRepo.GetIngredientsForRecipes(recipeIDs)
+	if err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	if err := attachRecipeExtras(s.imageRepo, s.favoriteRepo, pointers...); err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+
+	onHand := make(map[int]bool, len(ingredientIDs))
+	for _, id := range ingredientIDs {
+		onHand[id] = true
+	}
+	for i := range recipes {
+		recipe := &recipes[i]
+		recipe.Coverage = math.Round(float64(recipe.OnHand)/float64(recipe.TotalIngredients)*100) / 100
+		recipe.MissingIngredients = make([]models.RecipeIngredient, 0)
+		for _, ingredient := range ingredients[recipe.ID] {
+			if !onHand[ingredient.IngredientID] {
+				recipe.MissingIngredients = append(recipe.MissingIngredients, ingredient)
+			}
+		}
+	}
+	return recipes, models.NewPaginationMeta(params, total), nil
+}
+
+// validateIngredientIDs dedupes ids, which must all be known ingredients.
+func (s *pantryService) validateIngredientIDs(ids []int) ([]int, error) {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, domain.ErrIngredientNotFound
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > MaxPantryIngredients {
+		return nil, domain.ErrTooManyPantryIngredients
+	}
+
+	if len(unique) > 0 {
+		if err := ensureIngredientsExist(s.ingredientRepo, unique); err != nil {
+			return nil, err
+		}
+	}
+	return unique, nil
+}
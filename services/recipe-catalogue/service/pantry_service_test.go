@@ -0,0 +1,194 @@
+package service
+
+import (
+	"database/sql"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type pantryServiceTestSetup struct {
+	service        PantryService
+	pantryRepo     *mocks.MockPantryRepository
+	ingredientRepo *mocks.MockIngredientRepository
+	events         *PantryEvents
+}
+
+func setupPantryServiceTest() *pantryServiceTestSetup {
+	pantryRepo := new(mocks.MockPantryRepository)
+	ingredientRepo := new(mocks.MockIngredientRepository)
+	imageRepo := new(mocks.MockRecipeImageRepository)
+	imageRepo.On("GetByRecipeIDs", mock.Anything).Return(map[int][]models.RecipeImage{}, nil).Maybe()
+	favoriteRepo := new(mocks.MockFavoriteRepository)
+	favoriteRepo.On("CountByRecipeIDs", mock.Anything).Return(map[int]int{}, nil).Maybe()
+
+	events := NewPantryEvents()
+
+	return &pantryServiceTestSetup{
+		service:        NewPantryService(pantryRepo, ingredientRepo, imageRepo, favoriteRepo, events),
+		pantryRepo:     pantryRepo,
+		ingredientRepo: ingredientRepo,
+		events:         events,
+	}
+}
+
+func TestPantryService_UpdatePantry_DedupesIngredients(t *testing.T) {
+	setup := setupPantryServiceTest()
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{3, 1}).Return([]int{}, nil)
+	setup.pantryRepo.On("SetPantry", 7, []int{3, 1}, 0).Return(&models.PantryChange{Version: 1, ChangedBy: 7}, nil)
+	pantry := &models.Pantry{Version: 1, Items: []models.PantryItem{{IngredientID: 1, Name: "Chicken Breast"}, {IngredientID: 3, Name: "Onion"}}}
+	setup.pantryRepo.On("GetPantry", 7).Return(pantry, nil)
+
+	result, err := setup.service.UpdatePantry(7, models.UpdatePantryRequest{IngredientIDs: []int{3, 1, 3}})
+
+	require.NoError(t, err)
+	assert.Equal(t, pantry, result)
+	setup.pantryRepo.AssertExpectations(t)
+}
+
+func TestPantryService_UpdatePantry_EmptyListEmptiesPantry(t *testing.T) {
+	setup := setupPantryServiceTest()
+	setup.pantryRepo.On("SetPantry", 7, []int{}, 0).Return(&models.PantryChange{ChangedBy: 7}, nil)
+	setup.pantryRepo.On("GetPantry", 7).Return(&models.Pantry{Items: []models.PantryItem{}}, nil)
+
+	result, err := setup.service.UpdatePantry(7, models.UpdatePantryRequest{})
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Items)
+	setup.ingredientRepo.AssertNotCalled(t, "FindMissingIngredientIDs", mock.Anything)
+}
+
+func TestPantryService_UpdatePantry_VersionConflict(t *testing.T) {
+	setup := setupPantryServiceTest()
+	version := 3
+	setup.pantryRepo.On("SetPantry", 7, []int{}, 3).Return(nil, sql.ErrNoRows)
+	setup.pantryRepo.On("GetPantry", 7).Return(&models.Pantry{Version: 5, Items: []models.PantryItem{}}, nil)
+
+	_, err := setup.service.UpdatePantry(7, models.UpdatePantryRequest{Version: &version})
+
+	var conflict *models.VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, 5, conflict.CurrentVersion)
+}
+
+func TestPantryService_UpdatePantry_UnknownIngredient(t *testing.T) {
+	setup := setupPantryServiceTest()
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{1, 99}).Return([]int{99}, nil)
+
+	_, err := setup.service.UpdatePantry(7, models.UpdatePantryRequest{IngredientIDs: []int{1, 99}})
+
+	assert.Equal(t, domain.ErrIngredientNotFound, err)
+	setup.pantryRepo.AssertNotCalled(t, "SetPantry", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPantryService_UpdatePantry_TooManyIngredients(t *testing.T) {
+	setup := setupPantryServiceTest()
+	ids := make([]int, MaxPantryIngredients+1)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	_, err := setup.service.UpdatePantry(7, models.UpdatePantryRequest{IngredientIDs: ids})
+
+	assert.Equal(t, domain.ErrTooManyPantryIngredients, err)
+}
+
+func TestPantryService_AddPantryItem_TellsHouseholdWatchers(t *testing.T) {
+	setup := setupPantryServiceTest()
+	householdID := 2
+	setup.pantryRepo.On("GetPantry", 8).Return(&models.Pantry{HouseholdID: &householdID, Version: 4}, nil)
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{6}).Return([]int{}, nil)
+	change := &models.PantryChange{HouseholdID: &householdID, Version: 5, ChangedBy: 7,
+		Added: []models.PantryItem{{IngredientID: 6, Name: "Garlic", AddedBy: 7}}, Removed: []int{}}
+	setup.pantryRepo.On("EditPantry", 7, []int{6}, []int(nil)).Return(change, nil)
+	changes, stop, err := setup.service.Watch(8)
+	require.NoError(t, err)
+	defer stop()
+
+	result, err := setup.service.AddPantryItem(7, 6)
+
+	require.NoError(t, err)
+	assert.Equal(t, change, result)
+	select {
+	case got := <-changes:
+		assert.Equal(t, *change, got)
+	default:
+		t.Fatal("watcher was not told about the change")
+	}
+}
+
+func TestPantryService_RemovePantryItem_NotInPantry(t *testing.T) {
+	setup := setupPantryServiceTest()
+	setup.pantryRepo.On("EditPantry", 7, []int(nil), []int{6}).
+		Return(&models.PantryChange{Version: 2, ChangedBy: 7, Added: []models.PantryItem{}, Removed: []int{}}, nil)
+
+	_, err := setup.service.RemovePantryItem(7, 6)
+
+	assert.Equal(t, domain.ErrPantryItemNotFound, err)
+}
+
+func TestPantryService_Watch_EndsOnJoiningHousehold(t *testing.T) {
+	setup := setupPantryServiceTest()
+	setup.pantryRepo.On("GetPantry", 7).Return(&models.Pantry{Version: 1}, nil)
+	changes, stop, err := setup.service.Watch(7)
+	require.NoError(t, err)
+	defer stop()
+
+	setup.events.disconnect(7)
+
+	_, open := <-changes
+	assert.False(t, open)
+}
+
+func TestPantryService_GetCookableRecipes_FromPantry(t *testing.T) {
+	setup := setupPantryServiceTest()
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+	setup.pantryRepo.On("GetPantry", 7).Return(&models.Pantry{Items: []models.PantryItem{{IngredientID: 1}, {IngredientID: 6}}}, nil)
+	setup.pantryRepo.On("FindCookable", []int{1, 6}, params).Return([]models.CookableRecipe{
+		{Recipe: models.Recipe{ID: 4, Name: "Garlic Chicken"}, TotalIngredients: 2, OnHand: 2},
+		{Recipe: models.Recipe{ID: 5, Name: "Chicken Curry"}, TotalIngredients: 3, OnHand: 1},
+	}, 2, nil)
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{4, 5}).Return(map[int][]models.RecipeIngredient{
+		4: {{RecipeID: 4, IngredientID: 1}, {RecipeID: 4, IngredientID: 6}},
+		5: {{RecipeID: 5, IngredientID: 1}, {RecipeID: 5, IngredientID: 8, Unit: "ml"}, {RecipeID: 5, IngredientID: 9}},
+	}, nil)
+
+	recipes, meta, err := setup.service.GetCookableRecipes(7, nil, params)
+
+	require.NoError(t, err)
+	require.Len(t, recipes, 2)
+	assert.Equal(t, 1.0, recipes[0].Coverage)
+	assert.Empty(t, recipes[0].MissingIngredients)
+	assert.Equal(t, 0.33, recipes[1].Coverage)
+	require.Len(t, recipes[1].MissingIngredients, 2)
+	assert.Equal(t, 8, recipes[1].MissingIngredients[0].IngredientID)
+	assert.Equal(t, 9, recipes[1].MissingIngredients[1].IngredientID)
+	assert.Equal(t, 2, meta.Total)
+}
+
+func TestPantryService_GetCookableRecipes_ExplicitIngredients(t *testing.T) {
+	setup := setupPantryServiceTest()
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{6}).Return([]int{}, nil)
+	setup.pantryRepo.On("FindCookable", []int{6}, params).Return([]models.CookableRecipe{}, 0, nil)
+
+	recipes, _, err := setup.service.GetCookableRecipes(7, []int{6, 6}, params)
+
+	require.NoError(t, err)
+	assert.Empty(t, recipes)
+	setup.pantryRepo.AssertNotCalled(t, "GetPantry", mock.Anything)
+}
+
+func TestPantryService_GetCookableRecipes_UnknownIngredient(t *testing.T) {
+	setup := setupPantryServiceTest()
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{99}).Return([]int{99}, nil)
+
+	_, _, err := setup.service.GetCookableRecipes(7, []int{99}, models.PaginationParams{Page: 1, PerPage: 20})
+
+	assert.Equal(t, domain.ErrIngredientNotFound, err)
+}
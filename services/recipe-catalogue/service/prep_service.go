@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+	"meal-prep/shared/units"
+)
+
+const (
+	maxPrepRecipes           = 10
+	maxPrepPortions          = 50
+	maxPrepSessionNameLength = 100
+	maxKeepsDays             = 365
+	maxReheatNoteLength      = 200
+
+	// defaultKeepsDays is the usual fridge life of cooked food
+	defaultKeepsDays = 4
+
+	prepDateLayout = "2006-01-02"
+)
+
+type PrepService interface {
+	GetSessions(userID int) ([]models.PrepSession, error)
+	GetSession(ctx context.Context, userID, sessionID int) (*models.PrepSessionPlan, error)
+	CreateSession(ctx context.Context, userID int, req models.PrepSessionRequest) (*models.PrepSessionPlan, error)
+	UpdateSession(ctx context.Context, userID, sessionID int, req models.PrepSessionRequest) (*models.PrepSessionPlan, error)
+	DeleteSession(ctx context.Context, userID, sessionID int) error
+}
+
+type prepService struct {
+	prepRepo       repository.PrepSessionRepository
+	recipeRepo     repository.RecipeRepository
+	ingredientRepo repository.IngredientRepository
+	stepRepo       repository.StepRepository
+}
+
+func NewPrepService(prepRepo repository.PrepSessionRepository, recipeRepo repository.RecipeRepository, ingredientRepo repository.IngredientRepository, stepRepo repository.StepRepository) PrepService {
+	return &prepService{
+		prepRepo:       prepRepo,
+		recipeRepo:     recipeRepo,
+		ingredientRepo: ingredientRepo,
+		stepRepo:       stepRepo,
+	}
+}
+
+func (s *prepService) GetSessions(userID int) ([]models.PrepSession, error) {
+	return s.prepRepo.GetByUserID(userID)
+}
+
+func (s *prepService) GetSession(ctx context.Context, userID, sessionID int) (*models.PrepSessionPlan, error) {
+	session, err := s.ownedSession(ctx, policy.Read, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildPlan(ctx, session)
+}
+
+func (s *prepService) CreateSession(ctx context.Context, userID int, req models.PrepSessionRequest) (*models.PrepSessionPlan, error) {
+	req, err := s.normalizeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.prepRepo.Create(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildPlan(ctx, session)
+}
+
+func (s *prepService) UpdateSession(ctx context.Context, userID, sessionID int, req models.PrepSessionRequest) (*models.PrepSessionPlan, error) {
+	req, err := s.normalizeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ownedSession(ctx, policy.Update, sessionID); err != nil {
+		return nil, err
+	}
+
+	session, err := s.prepRepo.Update(sessionID, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrPrepSessionNotFound
+		}
+		return nil, err
+	}
+	return s.buildPlan(ctx, session)
+}
+
+func (s *prepService) DeleteSession(ctx context.Context, userID, sessionID int) error {
+	if _, err := s.ownedSession(ctx, policy.Delete, sessionID); err != nil {
+		return err
+	}
+
+	err := s.prepRepo.Delete(sessionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrPrepSessionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ownedSession treats other users' sessions as missing so their IDs reveal
+// nothing.
+func (s *prepService) ownedSession(ctx context.Context, action policy.Action, sessionID int) (*models.PrepSession, error) {
+	if sessionID <= 0 {
+		return nil, domain.ErrPrepSessionNotFound
+	}
+
+	session, err := s.prepRepo.GetByID(sessionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrPrepSessionNotFound
+		}
+		return nil, err
+	}
+	resource := policy.Resource{Type: prepSessionResource, OwnerID: session.UserID}
+	if err := authorize(ctx, action, resource, domain.ErrPrepSessionNotFound); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// normalizeRequest fills in default scales and portions and checks that
+// every recipe is one the user may cook: their own or a published one.
+func (s *prepService) normalizeRequest(ctx context.Context, req models.PrepSessionRequest) (models.PrepSessionRequest, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || utf8.RuneCountInString(req.Name) > maxPrepSessionNameLength {
+		return req, domain.ErrPrepSessionNameRequired
+	}
+
+	req.PrepDate = strings.TrimSpace(req.PrepDate)
+	if req.PrepDate != "" {
+		if _, err := time.Parse(prepDateLayout, req.PrepDate); err != nil {
+			return req, domain.ErrInvalidPrepDate
+		}
+	}
+
+	if len(req.Recipes) == 0 || len(req.Recipes) > maxPrepRecipes {
+		return req, domain.ErrInvalidPrepRecipes
+	}
+
+	seen := make(map[int]bool, len(req.Recipes))
+	recipes := make([]models.PrepSessionRecipe, 0, len(req.Recipes))
+	for _, recipe := range req.Recipes {
+		if seen[recipe.RecipeID] {
+			return req, domain.ErrInvalidPrepRecipes
+		}
+		seen[recipe.RecipeID] = true
+
+		if recipe.Scale == 0 {
+			recipe.Scale = 1
+		}
+		if recipe.Scale < 0 || recipe.Scale > maxCookingScale {
+			return req, domain.ErrInvalidScale
+		}
+		if recipe.Portions == 0 {
+			recipe.Portions = 1
+		}
+		if recipe.Portions < 0 || recipe.Portions > maxPrepPortions {
+			return req, domain.ErrInvalidPortions
+		}
+		if recipe.KeepsDays == 0 {
+			recipe.KeepsDays = defaultKeepsDays
+		}
+		if recipe.KeepsDays < 0 || recipe.KeepsDays > maxKeepsDays {
+			return req, domain.ErrInvalidKeepsDays
+		}
+		recipe.ReheatInstructions = strings.TrimSpace(recipe.ReheatInstructions)
+		if utf8.RuneCountInString(recipe.ReheatInstructions) > maxReheatNoteLength {
+			return req, domain.ErrInvalidReheatNote
+		}
+		recipes = append(recipes, recipe)
+	}
+	req.Recipes = recipes
+
+	for _, recipe := range recipes {
+		if _, err := readableRecipe(ctx, s.recipeRepo, recipe.RecipeID); err != nil {
+			return req, err
+		}
+	}
+
+	return req, nil
+}
+
+// buildPlan works out the batches, timeline and labels of a session.
+// Recipes that have since been unpublished by their author are left out.
+func (s *prepService) buildPlan(ctx context.Context, session *models.PrepSession) (*models.PrepSessionPlan, error) {
+	plan := &models.PrepSessionPlan{
+		PrepSession: *session,
+		Batches:     make([]models.PrepBatch, 0, len(session.Recipes)),
+		Labels:      make([]models.PrepLabel, 0),
+	}
+
+	preparedOn, err := time.Parse(prepDateLayout, session.PrepDate)
+	if err != nil {
+		preparedOn = session.CreatedAt
+	}
+
+	// Recipes are judged as the session's owner sees them, whoever is
+	// viewing the plan.
+	owner := policy.WithSubject(ctx, policy.Subject{UserID: session.UserID, Role: models.RoleUser})
+
+	tracks := make([]prepTrack, 0, len(session.Recipes))
+	for _, item := range session.Recipes {
+		recipe, err := readableRecipe(owner, s.recipeRepo, item.RecipeID)
+		if err == domain.ErrRecipeNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		batch, err := s.buildBatch(recipe, item)
+		if err != nil {
+			return nil, err
+		}
+		plan.Batches = append(plan.Batches, *batch)
+
+		track, err := s.buildTrack(recipe)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, track)
+
+		plan.Labels = append(plan.Labels, prepLabels(recipe, item, preparedOn)...)
+	}
+
+	plan.Timeline, plan.TotalMinutes = schedulePrep(tracks)
+	return plan, nil
+}
+
+func (s *prepService) buildBatch(recipe *models.Recipe, item models.PrepSessionRecipe) (*models.PrepBatch, error) {
+	recipeIngredients, err := s.ingredientRepo.GetRecipeIngredients(recipe.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &models.PrepBatch{
+		RecipeID:    recipe.ID,
+		RecipeName:  recipe.Name,
+		Scale:       item.Scale,
+		Portions:    item.Portions,
+		Ingredients: make([]models.CookingStepIngredient, 0, len(recipeIngredients)),
+	}
+	for _, ri := range recipeIngredients {
+		quantity, unit := units.Scale(ri.Quantity, ri.Unit, item.Scale)
+		batch.Ingredients = append(batch.Ingredients, models.CookingStepIngredient{
+			IngredientID: ri.IngredientID,
+			Name:         ri.Ingredient.Name,
+			Quantity:     quantity,
+			Unit:         unit,
+			Notes:        ri.Notes,
+		})
+	}
+	return batch, nil
+}
+
+// buildTrack turns the recipe's method into timeline tasks. Steps with
+// timers are hands-off. A recipe without authored steps becomes a single
+// hands-on task lasting its total time, when known.
+func (s *prepService) buildTrack(recipe *models.Recipe) (prepTrack, error) {
+	steps, err := s.stepRepo.GetByRecipeID(recipe.ID)
+	if err != nil {
+		return prepTrack{}, err
+	}
+
+	track := prepTrack{recipeID: recipe.ID, recipeName: recipe.Name}
+	for i, step := range steps {
+		track.tasks = append(track.tasks, prepTask{
+			step:        i + 1,
+			instruction: step.Instruction,
+			minutes:     stepMinutes(step),
+			handsOff:    len(step.Timers) > 0,
+		})
+	}
+
+	if len(steps) == 0 {
+		task := prepTask{step: 1, instruction: "Prepare " + recipe.Name}
+		if recipe.Description != nil && strings.TrimSpace(*recipe.Description) != "" {
+			task.instruction = strings.TrimSpace(*recipe.Description)
+		}
+		if recipe.TotalTimeMinutes != nil {
+			task.minutes = *recipe.TotalTimeMinutes
+		}
+		track.tasks = append(track.tasks, task)
+	}
+	return track, nil
+}
+
+// prepLabels makes one label per container of a batch, dated from
+// preparedOn.
+func prepLabels(recipe *models.Recipe, item models.PrepSessionRecipe, preparedOn time.Time) []models.PrepLabel {
+	prepared := preparedOn.Format(prepDateLayout)
+	useBy := preparedOn.AddDate(0, 0, item.KeepsDays).Format(prepDateLayout)
+
+	labels := make([]models.PrepLabel, 0, item.Portions)
+	for portion := 1; portion <= item.Portions; portion++ {
+		labels = append(labels, models.PrepLabel{
+			RecipeID:           recipe.ID,
+			RecipeName:         recipe.Name,
+			Portion:            portion,
+			Portions:           item.Portions,
+			PreparedOn:         prepared,
+			UseBy:              useBy,
+			ReheatInstructions: item.ReheatInstructions,
+			Text:               fmt.Sprintf("%s (%d/%d) - prepared %s, use by %s", recipe.Name, portion, item.Portions, prepared, useBy),
+		})
+	}
+	return labels
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type prepServiceTestSetup struct {
+	service        PrepService
+	prepRepo       *mocks.MockPrepSessionRepository
+	recipeRepo     *mocks.MockRecipeRepository
+	ingredientRepo *mocks.MockIngredientRepository
+	stepRepo       *mocks.MockStepRepository
+}
+
+func setupPrepServiceTest() *prepServiceTestSetup {
+	prepRepo := new(mocks.MockPrepSessionRepository)
+	recipeRepo := new(mocks.MockRecipeRepository)
+	ingredientRepo := new(mocks.MockIngredientRepository)
+	stepRepo := new(mocks.MockStepRepository)
+
+	return &prepServiceTestSetup{
+		service:        NewPrepService(prepRepo, recipeRepo, ingredientRepo, stepRepo),
+		prepRepo:       prepRepo,
+		recipeRepo:     recipeRepo,
+		ingredientRepo: ingredientRepo,
+		stepRepo:       stepRepo,
+	}
+}
+
+func TestPrepService_CreateSession_BuildsPlan(t *testing.T) {
+	setup := setupPrepServiceTest()
+	req := models.PrepSessionRequest{
+		Name:     "  Sunday prep  ",
+		PrepDate: "2026-10-18",
+		Recipes:  []models.PrepSessionRecipe{{RecipeID: 1, Scale: 2, Portions: 3, KeepsDays: 3, ReheatInstructions: " Microwave 2 minutes "}},
+	}
+	expected := models.PrepSessionRequest{
+		Name:     "Sunday prep",
+		PrepDate: "2026-10-18",
+		Recipes:  []models.PrepSessionRecipe{{RecipeID: 1, Scale: 2, Portions: 3, KeepsDays: 3, ReheatInstructions: "Microwave 2 minutes"}},
+	}
+	stored := &models.PrepSession{ID: 9, UserID: 5, Name: "Sunday prep", PrepDate: "2026-10-18", Recipes: expected.Recipes}
+
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPublished, 3), nil)
+	setup.prepRepo.On("Create", 5, expected).Return(stored, nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return(cookingIngredients(), nil)
+	setup.stepRepo.On("GetByRecipeID", 1).Return([]models.RecipeStep{
+		{Instruction: "Boil the pasta", DurationMinutes: intPtr(10), Timers: []models.StepTimer{{Label: "Boil", DurationSeconds: 600}}},
+		{Instruction: "Toss with sauce", DurationMinutes: intPtr(2)},
+	}, nil)
+
+	plan, err := setup.service.CreateSession(asUser(5), 5, req)
+
+	require.NoError(t, err)
+	require.Len(t, plan.Batches, 1)
+	assert.Equal(t, 400.0, plan.Batches[0].Ingredients[0].Quantity)
+	assert.Equal(t, 3.0, plan.Batches[0].Ingredients[1].Quantity)
+	require.Len(t, plan.Timeline, 2)
+	assert.True(t, plan.Timeline[0].HandsOff)
+	assert.Equal(t, 12, plan.TotalMinutes)
+	require.Len(t, plan.Labels, 3)
+	assert.Equal(t, "Pasta (2/3) - prepared 2026-10-18, use by 2026-10-21", plan.Labels[1].Text)
+	assert.Equal(t, "2026-10-21", plan.Labels[1].UseBy)
+	assert.Equal(t, "Microwave 2 minutes", plan.Labels[1].ReheatInstructions)
+	setup.prepRepo.AssertExpectations(t)
+}
+
+func TestPrepService_CreateSession_DefaultsScaleAndPortions(t *testing.T) {
+	setup := setupPrepServiceTest()
+	expected := models.PrepSessionRequest{
+		Name:    "Lunches",
+		Recipes: []models.PrepSessionRecipe{{RecipeID: 1, Scale: 1, Portions: 1, KeepsDays: 4}},
+	}
+	createdAt := time.Date(2026, 10, 16, 18, 30, 0, 0, time.UTC)
+
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPrivate, 5), nil)
+	setup.prepRepo.On("Create", 5, expected).Return(&models.PrepSession{ID: 1, UserID: 5, Recipes: expected.Recipes, CreatedAt: createdAt}, nil)
+	setup.ingredientRepo.On("GetRecipeIngredients", 1).Return([]models.RecipeIngredient{}, nil)
+	setup.stepRepo.On("GetByRecipeID", 1).Return([]models.RecipeStep{}, nil)
+
+	plan, err := setup.service.CreateSession(asUser(5), 5, models.PrepSessionRequest{
+		Name:    "Lunches",
+		Recipes: []models.PrepSessionRecipe{{RecipeID: 1}},
+	})
+
+	require.NoError(t, err)
+	// Without authored steps the description is the only task
+	require.Len(t, plan.Timeline, 1)
+	assert.Equal(t, "Boil the pasta and toss with the sauce.", plan.Timeline[0].Instruction)
+	// Without a prep date labels are dated from when the session was planned
+	assert.Equal(t, "Pasta (1/1) - prepared 2026-10-16, use by 2026-10-20", plan.Labels[0].Text)
+}
+
+func TestPrepService_CreateSession_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      models.PrepSessionRequest
+		expected error
+	}{
+		{"missing name", models.PrepSessionRequest{Name: " ", Recipes: []models.PrepSessionRecipe{{RecipeID: 1}}}, domain.ErrPrepSessionNameRequired},
+		{"bad date", models.PrepSessionRequest{Name: "Prep", PrepDate: "18/10/2026", Recipes: []models.PrepSessionRecipe{{RecipeID: 1}}}, domain.ErrInvalidPrepDate},
+		{"no recipes", models.PrepSessionRequest{Name: "Prep"}, domain.ErrInvalidPrepRecipes},
+		{"duplicate recipe", models.PrepSessionRequest{Name: "Prep", Recipes: []models.PrepSessionRecipe{{RecipeID: 1}, {RecipeID: 1}}}, domain.ErrInvalidPrepRecipes},
+		{"scale too large", models.PrepSessionRequest{Name: "Prep", Recipes: []models.PrepSessionRecipe{{RecipeID: 1, Scale: 21}}}, domain.ErrInvalidScale},
+		{"too many portions", models.PrepSessionRequest{Name: "Prep", Recipes: []models.PrepSessionRecipe{{RecipeID: 1, Portions: 51}}}, domain.ErrInvalidPortions},
+		{"keeps too long", models.PrepSessionRequest{Name: "Prep", Recipes: []models.PrepSessionRecipe{{RecipeID: 1, KeepsDays: 366}}}, domain.ErrInvalidKeepsDays},
+		{"reheat note too long", models.PrepSessionRequest{Name: "Prep", Recipes: []models.PrepSessionRecipe{{RecipeID: 1, ReheatInstructions: strings.Repeat("a", 201)}}}, domain.ErrInvalidReheatNote},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPrepServiceTest()
+
+			plan, err := setup.service.CreateSession(asUser(5), 5, tt.req)
+
+			assert.Nil(t, plan)
+			assert.Equal(t, tt.expected, err)
+			setup.prepRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPrepService_CreateSession_OtherUsersPrivateRecipe(t *testing.T) {
+	setup := setupPrepServiceTest()
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusPrivate, 3), nil)
+
+	plan, err := setup.service.CreateSession(asUser(5), 5, models.PrepSessionRequest{
+		Name:    "Prep",
+		Recipes: []models.PrepSessionRecipe{{RecipeID: 1}},
+	})
+
+	assert.Nil(t, plan)
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+}
+
+func TestPrepService_GetSession_OtherUsersSessionIsNotFound(t *testing.T) {
+	setup := setupPrepServiceTest()
+	setup.prepRepo.On("GetByID", 9).Return(&models.PrepSession{ID: 9, UserID: 3}, nil)
+
+	plan, err := setup.service.GetSession(asUser(5), 5, 9)
+
+	assert.Nil(t, plan)
+	assert.Equal(t, domain.ErrPrepSessionNotFound, err)
+}
+
+func TestPrepService_GetSession_SkipsRecipesNoLongerVisible(t *testing.T) {
+	setup := setupPrepServiceTest()
+	setup.prepRepo.On("GetByID", 9).Return(&models.PrepSession{
+		ID: 9, UserID: 5,
+		Recipes: []models.PrepSessionRecipe{{RecipeID: 1, Scale: 1, Portions: 2}},
+	}, nil)
+	setup.recipeRepo.On("GetByID", 1).Return(cookingRecipe(models.RecipeStatusDraft, 3), nil)
+
+	plan, err := setup.service.GetSession(asUser(5), 5, 9)
+
+	require.NoError(t, err)
+	assert.Empty(t, plan.Batches)
+	assert.Empty(t, plan.Timeline)
+	assert.Empty(t, plan.Labels)
+}
+
+func TestPrepService_DeleteSession_NotFound(t *testing.T) {
+	setup := setupPrepServiceTest()
+	setup.prepRepo.On("GetByID", 9).Return(nil, sql.ErrNoRows)
+
+	err := setup.service.DeleteSession(asUser(5), 5, 9)
+
+	assert.Equal(t, domain.ErrPrepSessionNotFound, err)
+	setup.prepRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
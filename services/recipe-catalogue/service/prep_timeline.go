@@ -0,0 +1,82 @@
+package service
+
+import (
+	"sort"
+
+	"meal-prep/shared/models"
+)
+
+// prepTask is one recipe step waiting to be put on a prep timeline.
+type prepTask struct {
+	step        int
+	instruction string
+	minutes     int
+	handsOff    bool
+}
+
+// prepTrack is the steps of one batch, which must run in order.
+type prepTrack struct {
+	recipeID   int
+	recipeName string
+	tasks      []prepTask
+}
+
+// schedulePrep interleaves several recipes into one timeline for a single
+// cook. Hands-on steps need the cook and never overlap; hands-off steps
+// only wait for the previous step of their own recipe. At each point the
+// step that can start earliest goes next, preferring the batch with the
+// most work left so long recipes are not left until the end.
+func schedulePrep(tracks []prepTrack) ([]models.PrepTimelineEntry, int) {
+	next := make([]int, len(tracks))
+	ready := make([]int, len(tracks))
+	remaining := make([]int, len(tracks))
+	pending := 0
+	for i, track := range tracks {
+		for _, task := range track.tasks {
+			remaining[i] += task.minutes
+		}
+		pending += len(track.tasks)
+	}
+
+	timeline := make([]models.PrepTimelineEntry, 0, pending)
+	cookFree, total := 0, 0
+	for ; pending > 0; pending-- {
+		best, bestStart := -1, 0
+		for i, track := range tracks {
+			if next[i] == len(track.tasks) {
+				continue
+			}
+			start := ready[i]
+			if !track.tasks[next[i]].handsOff {
+				start = max(start, cookFree)
+			}
+			if best < 0 || start < bestStart || (start == bestStart && remaining[i] > remaining[best]) {
+				best, bestStart = i, start
+			}
+		}
+
+		track := tracks[best]
+		task := track.tasks[next[best]]
+		end := bestStart + task.minutes
+		if !task.handsOff {
+			cookFree = end
+		}
+		ready[best] = end
+		remaining[best] -= task.minutes
+		next[best]++
+		total = max(total, end)
+
+		timeline = append(timeline, models.PrepTimelineEntry{
+			StartMinute: bestStart,
+			EndMinute:   end,
+			RecipeID:    track.recipeID,
+			RecipeName:  track.recipeName,
+			Step:        task.step,
+			Instruction: task.instruction,
+			HandsOff:    task.handsOff,
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].StartMinute < timeline[j].StartMinute })
+	return timeline, total
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulePrep_SingleRecipeRunsInOrder(t *testing.T) {
+	timeline, total := schedulePrep([]prepTrack{{
+		recipeID: 1, recipeName: "Dal",
+		tasks: []prepTask{
+			{step: 1, instruction: "Chop", minutes: 10},
+			{step: 2, instruction: "Simmer", minutes: 30, handsOff: true},
+		},
+	}})
+
+	require.Len(t, timeline, 2)
+	assert.Equal(t, 0, timeline[0].StartMinute)
+	assert.Equal(t, 10, timeline[1].StartMinute)
+	assert.Equal(t, 40, total)
+}
+
+func TestSchedulePrep_HandsOffStepsOverlapOtherBatches(t *testing.T) {
+	timeline, total := schedulePrep([]prepTrack{
+		{
+			recipeID: 1, recipeName: "Salad",
+			tasks: []prepTask{{step: 1, instruction: "Chop vegetables", minutes: 15}},
+		},
+		{
+			recipeID: 2, recipeName: "Chili",
+			tasks: []prepTask{
+				{step: 1, instruction: "Brown the mince", minutes: 10},
+				{step: 2, instruction: "Simmer", minutes: 60, handsOff: true},
+			},
+		},
+	})
+
+	// Chili has more work left, so it starts first; the salad is chopped
+	// while it simmers.
+	require.Len(t, timeline, 3)
+	assert.Equal(t, "Brown the mince", timeline[0].Instruction)
+	assert.Equal(t, 10, timeline[1].StartMinute)
+	assert.Equal(t, 10, timeline[2].StartMinute)
+	assert.ElementsMatch(t, []string{"Simmer", "Chop vegetables"}, []string{timeline[1].Instruction, timeline[2].Instruction})
+	assert.Equal(t, 70, total)
+}
+
+func TestSchedulePrep_HandsOnStepsNeverOverlap(t *testing.T) {
+	timeline, total := schedulePrep([]prepTrack{
+		{recipeID: 1, recipeName: "A", tasks: []prepTask{{step: 1, instruction: "Knead", minutes: 10}}},
+		{recipeID: 2, recipeName: "B", tasks: []prepTask{{step: 1, instruction: "Whisk", minutes: 5}}},
+	})
+
+	require.Len(t, timeline, 2)
+	assert.Equal(t, timeline[0].EndMinute, timeline[1].StartMinute)
+	assert.Equal(t, 15, total)
+}
+
+func TestSchedulePrep_Empty(t *testing.T) {
+	timeline, total := schedulePrep(nil)
+
+	assert.Empty(t, timeline)
+	assert.Zero(t, total)
+}
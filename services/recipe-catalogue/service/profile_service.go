@@ -0,0 +1,173 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"meal-prep/shared/units"
+)
+
+const (
+	// Limits match the recipe_catalogue.user_profiles columns
+	maxDisplayNameLength = 50
+	maxBioLength         = 500
+
+	// profileRecipeLimit is how many of the newest public recipes a profile shows
+	profileRecipeLimit = 12
+)
+
+type ProfileService interface {
+	GetPublicProfile(userID int) (*models.UserProfile, error)
+	GetSettings(userID int) (*models.ProfileSettings, error)
+	UpdateSettings(userID int, req models.UpdateProfileSettingsRequest) (*models.ProfileSettings, error)
+}
+
+type profileService struct {
+	profileRepo repository.ProfileRepository
+	recipeRepo  repository.RecipeRepository
+}
+
+func NewProfileService(profileRepo repository.ProfileRepository, recipeRepo repository.RecipeRepository) ProfileService {
+	return &profileService{
+		profileRepo: profileRepo,
+		recipeRepo:  recipeRepo,
+	}
+}
+
+// GetPublicProfile hides private profiles behind ErrProfileNotFound, so they
+// cannot be told apart from users who do not exist.
+func (s *profileService) GetPublicProfile(userID int) (*models.UserProfile, error) {
+	if userID <= 0 {
+		return nil, domain.ErrProfileNotFound
+	}
+
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.IsPublic {
+		return nil, domain.ErrProfileNotFound
+	}
+
+	profile := &models.UserProfile{
+		UserID:      userID,
+		DisplayName: fmt.Sprintf("Cook %d", userID),
+		Bio:         settings.Bio,
+	}
+	if settings.DisplayName != nil {
+		profile.DisplayName = *settings.DisplayName
+	}
+
+	if !settings.ShowRecipes && !settings.ShowStats {
+		return profile, nil
+	}
+
+	recipes, published, err := s.recipeRepo.GetByOwner(userID, models.RecipeStatusPublished,
+		models.PaginationParams{Page: 1, PerPage: profileRecipeLimit})
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.ShowRecipes {
+		profile.Recipes = recipes
+	}
+	if settings.ShowStats {
+		average, count, err := s.profileRepo.GetRatingSummary(userID)
+		if err != nil {
+			return nil, err
+		}
+		profile.Stats = &models.ProfileStats{
+			RecipesPublished: published,
+			AverageRating:    average,
+			RatingsCount:     count,
+		}
+	}
+	return profile, nil
+}
+
+// GetSettings returns the defaults for users who never changed them.
+func (s *profileService) GetSettings(userID int) (*models.ProfileSettings, error) {
+	settings, err := s.profileRepo.GetSettings(userID)
+	if err == sql.ErrNoRows {
+		return &models.ProfileSettings{
+			UserID:      userID,
+			IsPublic:    true,
+			ShowRecipes: true,
+			ShowStats:   true,
+		}, nil
+	}
+	return settings, err
+}
+
+func (s *profileService) UpdateSettings(userID int, req models.UpdateProfileSettingsRequest) (*models.ProfileSettings, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DisplayName != nil {
+		name, err := normalizeProfileText(*req.DisplayName, maxDisplayNameLength, domain.ErrInvalidDisplayName)
+		if err != nil {
+			return nil, err
+		}
+		settings.DisplayName = name
+	}
+	if req.Bio != nil {
+		bio, err := normalizeProfileText(*req.Bio, maxBioLength, domain.ErrInvalidBio)
+		if err != nil {
+			return nil, err
+		}
+		settings.Bio = bio
+	}
+	if req.IsPublic != nil {
+		settings.IsPublic = *req.IsPublic
+	}
+	if req.ShowRecipes != nil {
+		settings.ShowRecipes = *req.ShowRecipes
+	}
+	if req.ShowStats != nil {
+		settings.ShowStats = *req.ShowStats
+	}
+	if req.MeasurementSystem != nil {
+		system := strings.ToLower(strings.TrimSpace(*req.MeasurementSystem))
+		switch {
+		case system == "":
+			settings.MeasurementSystem = nil
+		case units.IsValidSystem(system):
+			settings.MeasurementSystem = &system
+		default:
+			return nil, domain.ErrInvalidMeasurementSystem
+		}
+	}
+	if req.Locale != nil {
+		if strings.TrimSpace(*req.Locale) == "" {
+			settings.Locale = nil
+		} else {
+			locale, ok := middleware.NormalizeLocale(*req.Locale)
+			if !ok {
+				return nil, domain.ErrInvalidLocale
+			}
+			settings.Locale = &locale
+		}
+	}
+
+	return s.profileRepo.SaveSettings(*settings)
+}
+
+// normalizeProfileText trims a profile field; a blank one clears it.
+func normalizeProfileText(text string, maxLength int, tooLong error) (*string, error) {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) > maxLength {
+		return nil, tooLong
+	}
+	if text == "" {
+		return nil, nil
+	}
+	return &text, nil
+}
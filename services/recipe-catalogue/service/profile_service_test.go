@@ -0,0 +1,161 @@
+package service
+
+import (
+	"database/sql"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+	"meal-prep/shared/units"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type profileServiceTestSetup struct {
+	service     ProfileService
+	profileRepo *mocks.MockProfileRepository
+	recipeRepo  *mocks.MockRecipeRepository
+}
+
+func setupProfileServiceTest() *profileServiceTestSetup {
+	profileRepo := new(mocks.MockProfileRepository)
+	recipeRepo := new(mocks.MockRecipeRepository)
+
+	return &profileServiceTestSetup{
+		service:     NewProfileService(profileRepo, recipeRepo),
+		profileRepo: profileRepo,
+		recipeRepo:  recipeRepo,
+	}
+}
+
+var profileRecipesParams = models.PaginationParams{Page: 1, PerPage: profileRecipeLimit}
+
+func TestProfileService_GetPublicProfile_Defaults(t *testing.T) {
+	setup := setupProfileServiceTest()
+	average := 4.5
+	recipes := []models.Recipe{{ID: 9, UserID: 3, Name: "Shakshuka", Status: models.RecipeStatusPublished}}
+
+	setup.profileRepo.On("GetSettings", 3).Return(nil, sql.ErrNoRows)
+	setup.recipeRepo.On("GetByOwner", 3, models.RecipeStatusPublished, profileRecipesParams).Return(recipes, 14, nil)
+	setup.profileRepo.On("GetRatingSummary", 3).Return(&average, 8, nil)
+
+	profile, err := setup.service.GetPublicProfile(3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Cook 3", profile.DisplayName)
+	assert.Equal(t, recipes, profile.Recipes)
+	assert.Equal(t, &models.ProfileStats{RecipesPublished: 14, AverageRating: &average, RatingsCount: 8}, profile.Stats)
+}
+
+func TestProfileService_GetPublicProfile_Private(t *testing.T) {
+	setup := setupProfileServiceTest()
+	setup.profileRepo.On("GetSettings", 3).Return(&models.ProfileSettings{UserID: 3, IsPublic: false}, nil)
+
+	_, err := setup.service.GetPublicProfile(3)
+
+	assert.Equal(t, domain.ErrProfileNotFound, err)
+	setup.recipeRepo.AssertNotCalled(t, "GetByOwner")
+}
+
+func TestProfileService_GetPublicProfile_HidesStats(t *testing.T) {
+	setup := setupProfileServiceTest()
+	name := "Hanna"
+	setup.profileRepo.On("GetSettings", 3).Return(&models.ProfileSettings{
+		UserID: 3, DisplayName: &name, IsPublic: true, ShowRecipes: true, ShowStats: false,
+	}, nil)
+	setup.recipeRepo.On("GetByOwner", 3, models.RecipeStatusPublished, profileRecipesParams).Return([]models.Recipe{}, 0, nil)
+
+	profile, err := setup.service.GetPublicProfile(3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Hanna", profile.DisplayName)
+	assert.Nil(t, profile.Stats)
+	setup.profileRepo.AssertNotCalled(t, "GetRatingSummary")
+}
+
+func TestProfileService_UpdateSettings_PartialUpdate(t *testing.T) {
+	setup := setupProfileServiceTest()
+	name := "Old name"
+	bio := "Loves soup"
+	stored := &models.ProfileSettings{UserID: 3, DisplayName: &name, Bio: &bio, IsPublic: true, ShowRecipes: true, ShowStats: true}
+	newName := "  Hanna  "
+	hideStats := false
+	clearBio := " "
+
+	setup.profileRepo.On("GetSettings", 3).Return(stored, nil)
+	expectedName := "Hanna"
+	expected := models.ProfileSettings{UserID: 3, DisplayName: &expectedName, IsPublic: true, ShowRecipes: true, ShowStats: false}
+	setup.profileRepo.On("SaveSettings", expected).Return(&expected, nil)
+
+	settings, err := setup.service.UpdateSettings(3, models.UpdateProfileSettingsRequest{
+		DisplayName: &newName, Bio: &clearBio, ShowStats: &hideStats,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Hanna", *settings.DisplayName)
+	setup.profileRepo.AssertExpectations(t)
+}
+
+func TestProfileService_UpdateSettings_TooLong(t *testing.T) {
+	setup := setupProfileServiceTest()
+	setup.profileRepo.On("GetSettings", 3).Return(nil, sql.ErrNoRows)
+	name := strings.Repeat("a", maxDisplayNameLength+1)
+
+	_, err := setup.service.UpdateSettings(3, models.UpdateProfileSettingsRequest{DisplayName: &name})
+
+	assert.Equal(t, domain.ErrInvalidDisplayName, err)
+	setup.profileRepo.AssertNotCalled(t, "SaveSettings")
+}
+
+func TestProfileService_UpdateSettings_MeasurementSystem(t *testing.T) {
+	setup := setupProfileServiceTest()
+	setup.profileRepo.On("GetSettings", 3).Return(nil, sql.ErrNoRows)
+	system := " Imperial "
+
+	imperial := units.Imperial
+	expected := models.ProfileSettings{UserID: 3, IsPublic: true, ShowRecipes: true, ShowStats: true, MeasurementSystem: &imperial}
+	setup.profileRepo.On("SaveSettings", expected).Return(&expected, nil)
+
+	settings, err := setup.service.UpdateSettings(3, models.UpdateProfileSettingsRequest{MeasurementSystem: &system})
+
+	assert.NoError(t, err)
+	assert.Equal(t, units.Imperial, *settings.MeasurementSystem)
+}
+
+func TestProfileService_UpdateSettings_InvalidMeasurementSystem(t *testing.T) {
+	setup := setupProfileServiceTest()
+	setup.profileRepo.On("GetSettings", 3).Return(nil, sql.ErrNoRows)
+	system := "nautical"
+
+	_, err := setup.service.UpdateSettings(3, models.UpdateProfileSettingsRequest{MeasurementSystem: &system})
+
+	assert.Equal(t, domain.ErrInvalidMeasurementSystem, err)
+	setup.profileRepo.AssertNotCalled(t, "SaveSettings")
+}
+
+func TestProfileService_UpdateSettings_Locale(t *testing.T) {
+	setup := setupProfileServiceTest()
+	setup.profileRepo.On("GetSettings", 3).Return(nil, sql.ErrNoRows)
+	locale := " pt_BR "
+
+	normalized := "pt-br"
+	expected := models.ProfileSettings{UserID: 3, IsPublic: true, ShowRecipes: true, ShowStats: true, Locale: &normalized}
+	setup.profileRepo.On("SaveSettings", expected).Return(&expected, nil)
+
+	settings, err := setup.service.UpdateSettings(3, models.UpdateProfileSettingsRequest{Locale: &locale})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pt-br", *settings.Locale)
+}
+
+func TestProfileService_UpdateSettings_InvalidLocale(t *testing.T) {
+	setup := setupProfileServiceTest()
+	setup.profileRepo.On("GetSettings", 3).Return(nil, sql.ErrNoRows)
+	locale := "klingon!"
+
+	_, err := setup.service.UpdateSettings(3, models.UpdateProfileSettingsRequest{Locale: &locale})
+
+	assert.Equal(t, domain.ErrInvalidLocale, err)
+	setup.profileRepo.AssertNotCalled(t, "SaveSettings")
+}
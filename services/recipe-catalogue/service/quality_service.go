@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+	"meal-prep/shared/units"
+)
+
+// Amounts outside these bounds are most likely a unit mix-up, such as 500 kg
+// of flour meant as 500 g. Mass is in grams, volume in millilitres and
+// anything else, like pieces or cloves, is a plain count.
+const (
+	minPlausibleAmount = 0.1
+	maxPlausibleAmount = 20000
+	maxPlausibleCount  = 100
+)
+
+// qualityChecks lists every check with its weight; the weights add up to 100.
+// The catalogue stores no nutrition data yet, so nutrition is not scored.
+var qualityChecks = []struct {
+	name   string
+	weight int
+	hint   string
+}{
+	{models.QualityCheckDescription, 10, "Add a short description so cooks know what they are making"},
+	{models.QualityCheckIngredients, 20, "List the ingredients"},
+	{models.QualityCheckIngredientUnits, 15, "Give every ingredient an amount and a unit that make sense"},
+	{models.QualityCheckSteps, 25, "Write out the method as steps"},
+	{models.QualityCheckImage, 15, "Add a photo of the finished dish"},
+	{models.QualityCheckTotalTime, 15, "Say how long the recipe takes"},
+}
+
+type RecipeQualityService interface {
+	// GetRecipeQuality scores one of the author's own recipes
+	GetRecipeQuality(ctx context.Context, userID, recipeID int) (*models.RecipeQuality, error)
+
+	// ListRecipeQuality reports on every recipe matching filter, lowest
+	// score first
+	ListRecipeQuality(filter models.RecipeQualityFilter, params models.PaginationParams) ([]models.RecipeQuality, models.PaginationMeta, error)
+}
+
+type recipeQualityService struct {
+	qualityRepo    repository.RecipeQualityRepository
+	ingredientRepo repository.IngredientRepository
+}
+
+func NewRecipeQualityService(qualityRepo repository.RecipeQualityRepository, ingredientRepo repository.IngredientRepository) RecipeQualityService {
+	return &recipeQualityService{
+		qualityRepo:    qualityRepo,
+		ingredientRepo: ingredientRepo,
+	}
+}
+
+func (s *recipeQualityService) GetRecipeQuality(ctx context.Context, userID, recipeID int) (*models.RecipeQuality, error) {
+	if recipeID <= 0 {
+		return nil, domain.ErrRecipeNotFound
+	}
+
+	facts, err := s.qualityRepo.GetFacts(recipeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRecipeNotFound
+		}
+		return nil, err
+	}
+	resource := policy.Resource{Type: recipeResource, OwnerID: facts.UserID}
+	if err := authorize(ctx, policy.Update, resource, domain.ErrForbidden); err != nil {
+		return nil, err
+	}
+
+	ingredients, err := s.ingredientRepo.GetIngredientsForRecipes([]int{recipeID})
+	if err != nil {
+		return nil, err
+	}
+
+	quality := scoreRecipe(*facts, ingredients[recipeID])
+	return &quality, nil
+}
+
+// ListRecipeQuality scores recipes as it goes rather than storing scores, so
+// the report always reflects the latest edits.
+func (s *recipeQualityService) ListRecipeQuality(filter models.RecipeQualityFilter, params models.PaginationParams) ([]models.RecipeQuality, models.PaginationMeta, error) {
+	if filter.Status != "" && !models.IsValidRecipeStatus(filter.Status) {
+		return nil, models.PaginationMeta{}, domain.ErrInvalidRecipeStatus
+	}
+	if filter.Failing != "" && !isQualityCheck(filter.Failing) {
+		return nil, models.PaginationMeta{}, domain.ErrInvalidQualityCheck
+	}
+
+	facts, err := s.qualityRepo.ListFacts(filter.Status)
+	if err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+
+	recipeIDs := make([]int, len(facts))
+	for i, f := range facts {
+		recipeIDs[i] = f.ID
+	}
+	ingredients, err := s.ingredientRepo.GetIngredientsForRecipes(recipeIDs)
+	if err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+
+	report := make([]models.RecipeQuality, 0, len(facts))
+	for _, f := range facts {
+		quality := scoreRecipe(f, ingredients[f.ID])
+		if matchesQualityFilter(quality, filter) {
+			report = append(report, quality)
+		}
+	}
+	sort.SliceStable(report, func(i, j int) bool { return report[i].Score < report[j].Score })
+
+	meta := models.NewPaginationMeta(params, len(report))
+	start := min(params.Offset(), len(report))
+	end := min(start+params.PerPage, len(report))
+	return report[start:end], meta, nil
+}
+
+func scoreRecipe(facts models.RecipeQualityFacts, ingredients []models.RecipeIngredient) models.RecipeQuality {
+	implausible := implausibleIngredients(ingredients)
+	passed := map[string]bool{
+		models.QualityCheckDescription:     facts.Description != nil && strings.TrimSpace(*facts.Description) != "",
+		models.QualityCheckIngredients:     len(ingredients) > 0,
+		models.QualityCheckIngredientUnits: len(ingredients) > 0 && len(implausible) == 0,
+		models.QualityCheckSteps:           facts.StepCount > 0,
+		models.QualityCheckImage:           facts.ImageCount > 0,
+		models.QualityCheckTotalTime:       facts.TotalTimeMinutes != nil && *facts.TotalTimeMinutes > 0,
+	}
+
+	quality := models.RecipeQuality{
+		RecipeID: facts.ID,
+		UserID:   facts.UserID,
+		Name:     facts.Name,
+		Status:   facts.Status,
+		Checks:   make([]models.QualityCheck, 0, len(qualityChecks)),
+	}
+	for _, c := range qualityChecks {
+		check := models.QualityCheck{Name: c.name, Weight: c.weight, Passed: passed[c.name]}
+		if check.Passed {
+			quality.Score += c.weight
+		} else {
+			check.Hint = c.hint
+			if c.name == models.QualityCheckIngredientUnits && len(implausible) > 0 {
+				check.Hint += ": check " + strings.Join(implausible, ", ")
+			}
+		}
+		quality.Checks = append(quality.Checks, check)
+	}
+	return quality
+}
+
+// implausibleIngredients describes the ingredients whose amount or unit looks
+// wrong, such as "Flour (500 kg)".
+func implausibleIngredients(ingredients []models.RecipeIngredient) []string {
+	var implausible []string
+	for _, ingredient := range ingredients {
+		if !plausibleAmount(ingredient.Quantity, ingredient.Unit) {
+			implausible = append(implausible,
+				fmt.Sprintf("%s (%g %s)", ingredient.Ingredient.Name, ingredient.Quantity, strings.TrimSpace(ingredient.Unit)))
+		}
+	}
+	return implausible
+}
+
+func plausibleAmount(quantity float64, unit string) bool {
+	if quantity <= 0 || strings.TrimSpace(unit) == "" {
+		return false
+	}
+
+	base := ""
+	switch units.KindOf(unit) {
+	case units.KindMass:
+		base = units.Gram
+	case units.KindVolume:
+		base = units.Millilitre
+	default:
+		return quantity <= maxPlausibleCount
+	}
+
+	amount, err := units.Convert(quantity, unit, base, 0)
+	return err == nil && amount >= minPlausibleAmount && amount <= maxPlausibleAmount
+}
+
+func matchesQualityFilter(quality models.RecipeQuality, filter models.RecipeQualityFilter) bool {
+	if filter.MaxScore != nil && quality.Score > *filter.MaxScore {
+		return false
+	}
+	if filter.Failing != "" {
+		for _, check := range quality.Checks {
+			if check.Name == filter.Failing {
+				return !check.Passed
+			}
+		}
+	}
+	return true
+}
+
+func isQualityCheck(name string) bool {
+	for _, c := range qualityChecks {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type qualityServiceTestSetup struct {
+	service        RecipeQualityService
+	qualityRepo    *mocks.MockRecipeQualityRepository
+	ingredientRepo *mocks.MockIngredientRepository
+}
+
+func setupQualityServiceTest() *qualityServiceTestSetup {
+	qualityRepo := new(mocks.MockRecipeQualityRepository)
+	ingredientRepo := new(mocks.MockIngredientRepository)
+
+	return &qualityServiceTestSetup{
+		service:        NewRecipeQualityService(qualityRepo, ingredientRepo),
+		qualityRepo:    qualityRepo,
+		ingredientRepo: ingredientRepo,
+	}
+}
+
+func completeRecipeFacts(id int) models.RecipeQualityFacts {
+	description := "Weeknight soup"
+	return models.RecipeQualityFacts{
+		Recipe: models.Recipe{
+			ID: id, UserID: 7, Name: "Soup", Status: models.RecipeStatusPublished,
+			Description: &description, TotalTimeMinutes: intPtr(40),
+		},
+		StepCount:  4,
+		ImageCount: 1,
+	}
+}
+
+func qualityIngredient(name string, quantity float64, unit string) models.RecipeIngredient {
+	return models.RecipeIngredient{Ingredient: models.Ingredient{Name: name}, Quantity: quantity, Unit: unit}
+}
+
+func failedChecks(quality models.RecipeQuality) map[string]string {
+	failed := map[string]string{}
+	for _, check := range quality.Checks {
+		if !check.Passed {
+			failed[check.Name] = check.Hint
+		}
+	}
+	return failed
+}
+
+func TestScoreRecipe(t *testing.T) {
+	soupIngredients := []models.RecipeIngredient{
+		qualityIngredient("Onion", 2, "pieces"),
+		qualityIngredient("Stock", 1, "litre"),
+		qualityIngredient("Salt", 1, "tsp"),
+	}
+
+	t.Run("complete recipe scores 100", func(t *testing.T) {
+		quality := scoreRecipe(completeRecipeFacts(1), soupIngredients)
+
+		assert.Equal(t, 100, quality.Score)
+		assert.Empty(t, failedChecks(quality))
+		assert.Len(t, quality.Checks, len(qualityChecks))
+	})
+
+	t.Run("bare recipe scores 0 with a hint per check", func(t *testing.T) {
+		blank := "  "
+		facts := models.RecipeQualityFacts{Recipe: models.Recipe{ID: 2, Name: "Soup", Description: &blank}}
+
+		quality := scoreRecipe(facts, nil)
+
+		assert.Equal(t, 0, quality.Score)
+		failed := failedChecks(quality)
+		assert.Len(t, failed, len(qualityChecks))
+		for name, hint := range failed {
+			assert.NotEmpty(t, hint, name)
+		}
+	})
+
+	t.Run("implausible amounts are named", func(t *testing.T) {
+		ingredients := []models.RecipeIngredient{
+			qualityIngredient("Flour", 500, "kg"),
+			qualityIngredient("Saffron", 0.01, "g"),
+			qualityIngredient("Eggs", 300, "pieces"),
+			qualityIngredient("Water", 0, "ml"),
+			qualityIngredient("Pepper", 1, " "),
+			qualityIngredient("Milk", 2, "cups"),
+		}
+
+		quality := scoreRecipe(completeRecipeFacts(1), ingredients)
+
+		assert.Equal(t, 85, quality.Score)
+		hint := failedChecks(quality)[models.QualityCheckIngredientUnits]
+		assert.Contains(t, hint, "Flour (500 kg)")
+		assert.Contains(t, hint, "Saffron (0.01 g)")
+		assert.Contains(t, hint, "Eggs (300 pieces)")
+		assert.Contains(t, hint, "Water (0 ml)")
+		assert.Contains(t, hint, "Pepper (1 )")
+		assert.NotContains(t, hint, "Milk")
+	})
+}
+
+func TestRecipeQualityService_GetRecipeQuality(t *testing.T) {
+	setup := setupQualityServiceTest()
+	facts := completeRecipeFacts(3)
+	facts.ImageCount = 0
+	setup.qualityRepo.On("GetFacts", 3).Return(&facts, nil)
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{3}).Return(map[int][]models.RecipeIngredient{
+		3: {qualityIngredient("Onion", 2, "pieces")},
+	}, nil)
+
+	quality, err := setup.service.GetRecipeQuality(asUser(7), 7, 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, 85, quality.Score)
+	assert.Equal(t, []string{models.QualityCheckImage}, keys(failedChecks(*quality)))
+}
+
+func TestRecipeQualityService_GetRecipeQuality_Errors(t *testing.T) {
+	setup := setupQualityServiceTest()
+	facts := completeRecipeFacts(3)
+	setup.qualityRepo.On("GetFacts", 3).Return(&facts, nil)
+	setup.qualityRepo.On("GetFacts", 4).Return(nil, sql.ErrNoRows)
+
+	_, err := setup.service.GetRecipeQuality(asUser(8), 8, 3)
+	assert.Equal(t, domain.ErrForbidden, err, "only the author sees the score")
+
+	_, err = setup.service.GetRecipeQuality(asUser(7), 7, 4)
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+
+	_, err = setup.service.GetRecipeQuality(asUser(7), 7, 0)
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+	setup.ingredientRepo.AssertNotCalled(t, "GetIngredientsForRecipes")
+}
+
+func TestRecipeQualityService_ListRecipeQuality(t *testing.T) {
+	setup := setupQualityServiceTest()
+	complete := completeRecipeFacts(1)
+	noPhoto := completeRecipeFacts(2)
+	noPhoto.ImageCount = 0
+	bare := models.RecipeQualityFacts{Recipe: models.Recipe{ID: 3, Name: "Draft", Status: models.RecipeStatusPublished}}
+	setup.qualityRepo.On("ListFacts", models.RecipeStatusPublished).Return([]models.RecipeQualityFacts{complete, noPhoto, bare}, nil)
+	onion := []models.RecipeIngredient{qualityIngredient("Onion", 2, "pieces")}
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{1, 2, 3}).Return(map[int][]models.RecipeIngredient{
+		1: onion, 2: onion,
+	}, nil)
+
+	t.Run("lowest score first", func(t *testing.T) {
+		report, meta, err := setup.service.ListRecipeQuality(
+			models.RecipeQualityFilter{Status: models.RecipeStatusPublished}, models.PaginationParams{Page: 1, PerPage: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, meta.Total)
+		assert.Equal(t, 2, meta.TotalPages)
+		require.Len(t, report, 2)
+		assert.Equal(t, 3, report[0].RecipeID)
+		assert.Equal(t, 2, report[1].RecipeID)
+	})
+
+	t.Run("max score and failing check", func(t *testing.T) {
+		report, _, err := setup.service.ListRecipeQuality(models.RecipeQualityFilter{
+			Status: models.RecipeStatusPublished, MaxScore: intPtr(90), Failing: models.QualityCheckImage,
+		}, models.PaginationParams{Page: 1, PerPage: 20})
+
+		require.NoError(t, err)
+		require.Len(t, report, 2)
+		assert.Equal(t, 3, report[0].RecipeID)
+		assert.Equal(t, 2, report[1].RecipeID)
+
+		report, _, err = setup.service.ListRecipeQuality(models.RecipeQualityFilter{
+			Status: models.RecipeStatusPublished, Failing: models.QualityCheckSteps,
+		}, models.PaginationParams{Page: 1, PerPage: 20})
+
+		require.NoError(t, err)
+		require.Len(t, report, 1)
+		assert.Equal(t, 3, report[0].RecipeID)
+	})
+
+	t.Run("page past the end", func(t *testing.T) {
+		report, _, err := setup.service.ListRecipeQuality(
+			models.RecipeQualityFilter{Status: models.RecipeStatusPublished}, models.PaginationParams{Page: 5, PerPage: 20})
+
+		require.NoError(t, err)
+		assert.Empty(t, report)
+	})
+}
+
+func TestRecipeQualityService_ListRecipeQuality_InvalidFilter(t *testing.T) {
+	setup := setupQualityServiceTest()
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+
+	_, _, err := setup.service.ListRecipeQuality(models.RecipeQualityFilter{Failing: "nutrition"}, params)
+	assert.Equal(t, domain.ErrInvalidQualityCheck, err)
+
+	_, _, err = setup.service.ListRecipeQuality(models.RecipeQualityFilter{Status: "deleted"}, params)
+	assert.Equal(t, domain.ErrInvalidRecipeStatus, err)
+	setup.qualityRepo.AssertNotCalled(t, "ListFacts")
+}
+
+func keys(m map[string]string) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+	"meal-prep/shared/units"
+)
+
+// CompareRecipes diffs two recipes the user can see: public recipes and,
+// when signed in, their own. userID is 0 for anonymous users.
+func (s *recipeService) CompareRecipes(ctx context.Context, userID, firstID, secondID int) (*models.RecipeComparison, error) {
+	if firstID == secondID {
+		return nil, domain.ErrCompareSameRecipe
+	}
+
+	first, err := s.comparableRecipe(ctx, firstID)
+	if err != nil {
+		return nil, err
+	}
+	second, err := s.comparableRecipe(ctx, secondID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ingredientIDs []int
+	for _, recipe := range []*models.RecipeWithIngredients{first, second} {
+		for _, ingredient := range recipe.Ingredients {
+			ingredientIDs = append(ingredientIDs, ingredient.IngredientID)
+		}
+	}
+	densities, err := s.ingredientRepo.GetDensities(ingredientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareRecipes(first, second, densities), nil
+}
+
+func (s *recipeService) comparableRecipe(ctx context.Context, id int) (*models.RecipeWithIngredients, error) {
+	if id <= 0 {
+		return nil, domain.ErrRecipeNotFound
+	}
+	recipe, err := s.recipeRepo.GetByIDWithIngredients(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRecipeNotFound
+		}
+		return nil, err
+	}
+	if err := authorize(ctx, policy.Read, recipeAccess(&recipe.Recipe), domain.ErrRecipeNotFound); err != nil {
+		return nil, err
+	}
+	applyEstimatedDifficulty(recipe)
+	return recipe, nil
+}
+
+func compareRecipes(first, second *models.RecipeWithIngredients, densities map[int]float64) *models.RecipeComparison {
+	comparison := &models.RecipeComparison{
+		First:       comparedRecipe(first),
+		Second:      comparedRecipe(second),
+		Ingredients: compareIngredients(first.Ingredients, second.Ingredients, densities),
+		Time: models.TimeComparison{
+			FirstMinutes:  first.TotalTimeMinutes,
+			SecondMinutes: second.TotalTimeMinutes,
+		},
+		Nutrition: models.NutritionComparison{
+			First:  recipeNutrition(first.Ingredients, densities),
+			Second: recipeNutrition(second.Ingredients, densities),
+		},
+	}
+	if first.TotalTimeMinutes != nil && second.TotalTimeMinutes != nil {
+		difference := *second.TotalTimeMinutes - *first.TotalTimeMinutes
+		comparison.Time.DifferenceMinutes = &difference
+	}
+
+	a, b := comparison.Nutrition.First.Totals, comparison.Nutrition.Second.Totals
+	comparison.Nutrition.Difference = models.ProductNutrition{
+		CaloriesKcal: roundNutrient(b.CaloriesKcal - a.CaloriesKcal),
+		ProteinG:     roundNutrient(b.ProteinG - a.ProteinG),
+		CarbsG:       roundNutrient(b.CarbsG - a.CarbsG),
+		FatG:         roundNutrient(b.FatG - a.FatG),
+		FiberG:       roundNutrient(b.FiberG - a.FiberG),
+	}
+	return comparison
+}
+
+func comparedRecipe(recipe *models.RecipeWithIngredients) models.ComparedRecipe {
+	return models.ComparedRecipe{
+		ID:               recipe.ID,
+		Name:             recipe.Name,
+		Difficulty:       recipe.Difficulty,
+		TotalTimeMinutes: recipe.TotalTimeMinutes,
+		IngredientCount:  len(recipe.Ingredients),
+		StepCount:        len(recipe.Steps),
+	}
+}
+
+// compareIngredients pairs up the recipes' ingredient lines by ingredient,
+// in name order. Amounts in different units are compared in the first
+// recipe's unit when they convert to it.
+func compareIngredients(first, second []models.RecipeIngredient, densities map[int]float64) []models.IngredientComparison {
+	byID := make(map[int]*models.IngredientComparison)
+	comparisons := make([]*models.IngredientComparison, 0, len(first)+len(second))
+	entry := func(ingredient models.RecipeIngredient) *models.IngredientComparison {
+		comparison, ok := byID[ingredient.IngredientID]
+		if !ok {
+			comparison = &models.IngredientComparison{IngredientID: ingredient.IngredientID, Name: ingredient.Ingredient.Name}
+			byID[ingredient.IngredientID] = comparison
+			comparisons = append(comparisons, comparison)
+		}
+		return comparison
+	}
+	for _, ingredient := range first {
+		entry(ingredient).First = &models.ComparedQuantity{Quantity: ingredient.Quantity, Unit: ingredient.Unit}
+	}
+	for _, ingredient := range second {
+		entry(ingredient).Second = &models.ComparedQuantity{Quantity: ingredient.Quantity, Unit: ingredient.Unit}
+	}
+
+	result := make([]models.IngredientComparison, 0, len(comparisons))
+	for _, comparison := range comparisons {
+		switch {
+		case comparison.First == nil:
+			comparison.Change = models.IngredientAdded
+		case comparison.Second == nil:
+			comparison.Change = models.IngredientRemoved
+		default:
+			comparison.Change = models.IngredientChanged
+			if converted, ok := quantityIn(*comparison.Second, comparison.First.Unit, densities[comparison.IngredientID]); ok {
+				difference := roundQuantity(converted - comparison.First.Quantity)
+				if difference == 0 {
+					comparison.Change = models.IngredientUnchanged
+				} else {
+					comparison.Difference = &difference
+				}
+			}
+		}
+		result = append(result, *comparison)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Name == result[j].Name {
+			return result[i].IngredientID < result[j].IngredientID
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// quantityIn expresses a quantity in unit, reporting false when it does not
+// convert.
+func quantityIn(quantity models.ComparedQuantity, unit string, gramsPerML float64) (float64, bool) {
+	if units.Normalize(quantity.Unit) == units.Normalize(unit) {
+		return quantity.Quantity, true
+	}
+	converted, err := units.Convert(quantity.Quantity, quantity.Unit, unit, gramsPerML)
+	if err != nil {
+		return 0, false
+	}
+	return converted, true
+}
+
+func recipeNutrition(ingredients []models.RecipeIngredient, densities map[int]float64) models.RecipeNutrition {
+	nutrition := models.RecipeNutrition{TotalIngredients: len(ingredients)}
+	totals := &nutrition.Totals
+	for _, ingredient := range ingredients {
+		if ingredient.Product == nil {
+			continue
+		}
+		portions, ok := labelPortions(ingredient.Quantity, ingredient.Unit, densities[ingredient.IngredientID])
+		if !ok {
+			continue
+		}
+		label := ingredient.Product.Nutrition
+		totals.CaloriesKcal += label.CaloriesKcal * portions
+		totals.ProteinG += label.ProteinG * portions
+		totals.CarbsG += label.CarbsG * portions
+		totals.FatG += label.FatG * portions
+		totals.FiberG += label.FiberG * portions
+		nutrition.CountedIngredients++
+	}
+
+	totals.CaloriesKcal = roundNutrient(totals.CaloriesKcal)
+	totals.ProteinG = roundNutrient(totals.ProteinG)
+	totals.CarbsG = roundNutrient(totals.CarbsG)
+	totals.FatG = roundNutrient(totals.FatG)
+	totals.FiberG = roundNutrient(totals.FiberG)
+	return nutrition
+}
+
+// labelPortions is how many of the 100 g or 100 ml a nutrition label is
+// given for make up quantity. Volumes become grams when the ingredient has
+// a density, and are otherwise taken as a liquid labelled per 100 ml.
+func labelPortions(quantity float64, unit string, gramsPerML float64) (float64, bool) {
+	if grams, err := units.Convert(quantity, unit, units.Gram, gramsPerML); err == nil {
+		return grams / 100, true
+	}
+	if millilitres, err := units.Convert(quantity, unit, units.Millilitre, 0); err == nil {
+		return millilitres / 100, true
+	}
+	return 0, false
+}
+
+func roundQuantity(quantity float64) float64 {
+	return math.Round(quantity*100) / 100
+}
+
+func roundNutrient(amount float64) float64 {
+	return math.Round(amount*10) / 10
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func comparisonRecipe(id, userID int, status string, minutes int, ingredients ...models.RecipeIngredient) *models.RecipeWithIngredients {
+	return &models.RecipeWithIngredients{
+		Recipe:      models.Recipe{ID: id, UserID: userID, Name: "Stir fry", Status: status, TotalTimeMinutes: &minutes},
+		Ingredients: ingredients,
+	}
+}
+
+func comparisonIngredient(id int, name string, quantity float64, unit string) models.RecipeIngredient {
+	return models.RecipeIngredient{IngredientID: id, Ingredient: models.Ingredient{ID: id, Name: name}, Quantity: quantity, Unit: unit}
+}
+
+func TestRecipeService_CompareRecipes(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	tofu := &models.IngredientProduct{ID: 1, IngredientID: 1, Nutrition: models.ProductNutrition{CaloriesKcal: 100, ProteinG: 10}}
+
+	firstTofu := comparisonIngredient(1, "Tofu", 200, "g")
+	firstTofu.Product = tofu
+	first := comparisonRecipe(1, 9, models.RecipeStatusPublished, 30,
+		firstTofu, comparisonIngredient(2, "Rice", 1, "cup"), comparisonIngredient(3, "Soy sauce", 2, "tbsp"))
+
+	secondTofu := comparisonIngredient(1, "Tofu", 0.25, "kg")
+	secondTofu.Product = tofu
+	second := comparisonRecipe(2, 8, models.RecipeStatusPrivate, 45,
+		secondTofu, comparisonIngredient(2, "Rice", 1, "cup"), comparisonIngredient(4, "Chilli", 1, "piece"))
+
+	setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(first, nil)
+	setup.recipeRepo.On("GetByIDWithIngredients", 2).Return(second, nil)
+	setup.ingredientRepo.On("GetDensities", mock.Anything).Return(map[int]float64{}, nil)
+
+	comparison, err := setup.service.CompareRecipes(asUser(8), 8, 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, comparison.First.ID)
+	assert.Equal(t, 2, comparison.Second.ID)
+	assert.NotNil(t, comparison.First.Difficulty)
+
+	changes := map[string]string{}
+	for _, ingredient := range comparison.Ingredients {
+		changes[ingredient.Name] = ingredient.Change
+	}
+	assert.Equal(t, map[string]string{
+		"Chilli":    models.IngredientAdded,
+		"Rice":      models.IngredientUnchanged,
+		"Soy sauce": models.IngredientRemoved,
+		"Tofu":      models.IngredientChanged,
+	}, changes)
+	assert.Equal(t, "Chilli", comparison.Ingredients[0].Name)
+
+	tofuComparison := comparison.Ingredients[3]
+	assert.Equal(t, "Tofu", tofuComparison.Name)
+	if assert.NotNil(t, tofuComparison.Difference) {
+		assert.Equal(t, 50.0, *tofuComparison.Difference)
+	}
+
+	if assert.NotNil(t, comparison.Time.DifferenceMinutes) {
+		assert.Equal(t, 15, *comparison.Time.DifferenceMinutes)
+	}
+
+	assert.Equal(t, 200.0, comparison.Nutrition.First.Totals.CaloriesKcal)
+	assert.Equal(t, 250.0, comparison.Nutrition.Second.Totals.CaloriesKcal)
+	assert.Equal(t, 1, comparison.Nutrition.First.CountedIngredients)
+	assert.Equal(t, 3, comparison.Nutrition.First.TotalIngredients)
+	assert.Equal(t, 50.0, comparison.Nutrition.Difference.CaloriesKcal)
+	assert.Equal(t, 5.0, comparison.Nutrition.Difference.ProteinG)
+}
+
+func TestRecipeService_CompareRecipes_Errors(t *testing.T) {
+	t.Run("same recipe", func(t *testing.T) {
+		setup := setupRecipeServiceTest()
+
+		_, err := setup.service.CompareRecipes(asUser(0), 0, 1, 1)
+
+		assert.Equal(t, domain.ErrCompareSameRecipe, err)
+		setup.recipeRepo.AssertNotCalled(t, "GetByIDWithIngredients", mock.Anything)
+	})
+
+	t.Run("someone else's private recipe", func(t *testing.T) {
+		setup := setupRecipeServiceTest()
+		setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(comparisonRecipe(1, 9, models.RecipeStatusPublished, 30), nil)
+		setup.recipeRepo.On("GetByIDWithIngredients", 2).Return(comparisonRecipe(2, 8, models.RecipeStatusPrivate, 45), nil)
+
+		_, err := setup.service.CompareRecipes(asUser(0), 0, 1, 2)
+
+		assert.Equal(t, domain.ErrRecipeNotFound, err)
+		setup.ingredientRepo.AssertNotCalled(t, "GetDensities", mock.Anything)
+	})
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"math"
+	"sort"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+)
+
+const (
+	// duplicateThreshold is the similarity at which an existing recipe is
+	// taken for a likely duplicate of a new one
+	duplicateThreshold = 0.6
+
+	// maxPossibleDuplicates bounds the warning on a created recipe
+	maxPossibleDuplicates = 5
+)
+
+// duplicateCandidateParams picks the author's recipes a new one is checked
+// against: the most recently updated, so prolific authors' creates stay
+// quick.
+var duplicateCandidateParams = models.PaginationParams{Page: 1, PerPage: 500}
+
+// warnPossibleDuplicates sets recipe.PossibleDuplicates to the author's
+// other recipes it looks like a copy of. The warning never fails a create,
+// so errors are only logged.
+func warnPossibleDuplicates(recipeRepo repository.RecipeRepository, ingredientRepo repository.IngredientRepository, recipe *models.Recipe, ingredients []models.RecipeIngredient) {
+	duplicates, err := findPossibleDuplicates(recipeRepo, ingredientRepo, recipe, ingredients)
+	if err != nil {
+		logging.Logger.Warn("Failed to check recipe for duplicates", "recipe_id", recipe.ID, "error", err)
+		return
+	}
+	recipe.PossibleDuplicates = duplicates
+}
+
+// findPossibleDuplicates ranks the author's recipes, archived ones aside, by
+// how alike their names are and how much their ingredients overlap with
+// the new recipe's, most similar first.
+func findPossibleDuplicates(recipeRepo repository.RecipeRepository, ingredientRepo repository.IngredientRepository, recipe *models.Recipe, ingredients []models.RecipeIngredient) ([]models.RecipeDuplicate, error) {
+	owned, _, err := recipeRepo.GetByOwner(recipe.UserID, "", duplicateCandidateParams)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []models.Recipe
+	for _, other := range owned {
+		if other.ID != recipe.ID && other.Status != models.RecipeStatusArchived {
+			candidates = append(candidates, other)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	var candidateIngredients map[int][]models.RecipeIngredient
+	if len(ingredients) > 0 {
+		ids := make([]int, len(candidates))
+		for i, candidate := range candidates {
+			ids[i] = candidate.ID
+		}
+		if candidateIngredients, err = ingredientRepo.GetIngredientsForRecipes(ids); err != nil {
+			return nil, err
+		}
+	}
+
+	var duplicates []models.RecipeDuplicate
+	for _, candidate := range candidates {
+		duplicate := scoreDuplicate(recipe.Name, ingredients, candidate, candidateIngredients[candidate.ID])
+		if duplicate.Similarity >= duplicateThreshold {
+			duplicates = append(duplicates, duplicate)
+		}
+	}
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		if duplicates[i].Similarity != duplicates[j].Similarity {
+			return duplicates[i].Similarity > duplicates[j].Similarity
+		}
+		return duplicates[i].RecipeID < duplicates[j].RecipeID
+	})
+	if len(duplicates) > maxPossibleDuplicates {
+		duplicates = duplicates[:maxPossibleDuplicates]
+	}
+	return duplicates, nil
+}
+
+// scoreDuplicate compares a new recipe with an existing one. Names are
+// compared by trigrams; when both recipes have ingredients the score is
+// the mean of that and the share of their combined ingredients they have
+// in common.
+func scoreDuplicate(name string, ingredients []models.RecipeIngredient, other models.Recipe, otherIngredients []models.RecipeIngredient) models.RecipeDuplicate {
+	duplicate := models.RecipeDuplicate{RecipeID: other.ID, Name: other.Name, Status: other.Status}
+	score := repository.TrigramSimilarity(name, other.Name)
+
+	if len(ingredients) > 0 && len(otherIngredients) > 0 {
+		mine := make(map[int]bool, len(ingredients))
+		for _, ingredient := range ingredients {
+			mine[ingredient.IngredientID] = true
+		}
+		theirs := make(map[int]bool, len(otherIngredients))
+		for _, ingredient := range otherIngredients {
+			if !theirs[ingredient.IngredientID] && mine[ingredient.IngredientID] {
+				duplicate.SharedIngredients++
+			}
+			theirs[ingredient.IngredientID] = true
+		}
+		overlap := float64(duplicate.SharedIngredients) / float64(len(mine)+len(theirs)-duplicate.SharedIngredients)
+		score = (score + overlap) / 2
+	}
+
+	duplicate.Similarity = math.Round(score*100) / 100
+	return duplicate
+}
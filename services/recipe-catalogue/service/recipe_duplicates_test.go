@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+	"meal-prep/shared/testing/factory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func duplicateIngredients(ids ...int) []models.RecipeIngredient {
+	ingredients := make([]models.RecipeIngredient, len(ids))
+	for i, id := range ids {
+		ingredients[i] = models.RecipeIngredient{IngredientID: id}
+	}
+	return ingredients
+}
+
+func TestRecipeService_CreateRecipe_WarnsOfSimilarNames(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	setup.recipeRepo.ExpectedCalls = nil
+	created := &models.Recipe{ID: 7, UserID: 3, Name: "Chicken Curry", Status: models.RecipeStatusDraft}
+
+	setup.categoryRepo.On("Exists", 1).Return(true, nil)
+	setup.recipeRepo.On("Create", 3, mock.AnythingOfType("models.CreateRecipeRequest")).Return(created, nil)
+	setup.recipeRepo.On("GetByOwner", 3, "", duplicateCandidateParams).Return([]models.Recipe{
+		*created,
+		{ID: 3, UserID: 3, Name: "chicken curry", Status: models.RecipeStatusPublished},
+		{ID: 4, UserID: 3, Name: "Chicken Curry", Status: models.RecipeStatusArchived},
+		{ID: 5, UserID: 3, Name: "Beef Stew", Status: models.RecipeStatusPublished},
+	}, 4, nil)
+
+	result, err := setup.service.CreateRecipe(3, factory.NewCreateRecipeRequestBuilder().WithName("Chicken Curry").WithCategoryID(1).Build())
+
+	require.NoError(t, err)
+	// Neither the new recipe itself nor archived ones count
+	assert.Equal(t, []models.RecipeDuplicate{
+		{RecipeID: 3, Name: "chicken curry", Status: models.RecipeStatusPublished, Similarity: 1},
+	}, result.PossibleDuplicates)
+	setup.ingredientRepo.AssertNotCalled(t, "GetIngredientsForRecipes", mock.Anything)
+}
+
+func TestRecipeService_CreateRecipeWithIngredients_WeighsSharedIngredients(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	setup.recipeRepo.ExpectedCalls = nil
+	created := &models.RecipeWithIngredients{
+		Recipe:      models.Recipe{ID: 7, UserID: 3, Name: "Chicken Curry", Status: models.RecipeStatusDraft},
+		Ingredients: duplicateIngredients(1, 2, 3),
+	}
+
+	setup.categoryRepo.On("Exists", 1).Return(true, nil)
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{1, 2, 3}).Return([]int{}, nil)
+	setup.recipeRepo.On("CreateWithIngredients", 3, mock.Anything).Return(created, nil)
+	setup.recipeRepo.On("GetByOwner", 3, "", duplicateCandidateParams).Return([]models.Recipe{
+		{ID: 4, UserID: 3, Name: "Chicken Curry", Status: models.RecipeStatusPublished},
+		{ID: 5, UserID: 3, Name: "Curry", Status: models.RecipeStatusPrivate},
+		{ID: 6, UserID: 3, Name: "Chicken Curry", Status: models.RecipeStatusPublished},
+	}, 3, nil)
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{4, 5, 6}).Return(map[int][]models.RecipeIngredient{
+		4: duplicateIngredients(8, 9),
+		5: duplicateIngredients(1, 2, 3),
+		6: duplicateIngredients(1, 2, 3, 4),
+	}, nil)
+
+	result, err := setup.service.CreateRecipeWithIngredients(3, models.CreateRecipeWithIngredientsRequest{
+		Name:        "Chicken Curry",
+		CategoryID:  1,
+		Ingredients: []models.AddRecipeIngredientRequest{{IngredientID: 1, Quantity: 1, Unit: "g"}, {IngredientID: 2, Quantity: 1, Unit: "g"}, {IngredientID: 3, Quantity: 1, Unit: "g"}},
+	})
+
+	require.NoError(t, err)
+	// The same name with none of the same ingredients is a different dish
+	assert.Equal(t, []models.RecipeDuplicate{
+		{RecipeID: 6, Name: "Chicken Curry", Status: models.RecipeStatusPublished, Similarity: 0.88, SharedIngredients: 3},
+		{RecipeID: 5, Name: "Curry", Status: models.RecipeStatusPrivate, Similarity: 0.73, SharedIngredients: 3},
+	}, result.PossibleDuplicates)
+}
+
+func TestRecipeService_CreateRecipe_DuplicateCheckNeverFailsCreate(t *testing.T) {
+	logging.Init("test")
+	setup := setupRecipeServiceTest()
+	setup.recipeRepo.ExpectedCalls = nil
+	created := &models.Recipe{ID: 7, UserID: 3, Name: "Chicken Curry"}
+
+	setup.categoryRepo.On("Exists", 1).Return(true, nil)
+	setup.recipeRepo.On("Create", 3, mock.AnythingOfType("models.CreateRecipeRequest")).Return(created, nil)
+	setup.recipeRepo.On("GetByOwner", 3, "", duplicateCandidateParams).Return(nil, 0, errors.New("connection reset"))
+
+	result, err := setup.service.CreateRecipe(3, factory.NewCreateRecipeRequestBuilder().WithName("Chicken Curry").WithCategoryID(1).Build())
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, result.ID)
+	assert.Empty(t, result.PossibleDuplicates)
+}
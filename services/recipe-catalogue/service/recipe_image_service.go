@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/services/recipe-catalogue/storage"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+
+	"github.com/google/uuid"
+)
+
+// maxImageCaptionLength matches the recipe_images.caption column.
+const maxImageCaptionLength = 200
+
+// storedImagePrefix is where uploads live in the image store. Only keys
+// under it are served.
+const storedImagePrefix = "recipes/"
+
+type RecipeImageService interface {
+	GetRecipeImages(recipeID int) ([]models.RecipeImage, error)
+	AddRecipeImage(ctx context.Context, recipeID int, req models.AddRecipeImageRequest) (*models.RecipeImage, error)
+	UpdateRecipeImages(ctx context.Context, recipeID int, req models.UpdateRecipeImagesRequest) ([]models.RecipeImage, error)
+	DeleteRecipeImage(ctx context.Context, recipeID, imageID int) error
+
+	// UploadRecipeImage stores an uploaded photo and a thumbnail of it and
+	// adds it to the recipe's images
+	UploadRecipeImage(ctx context.Context, recipeID int, upload models.ImageUpload) (*models.RecipeImage, error)
+	// GetStoredImage returns a stored photo or thumbnail and its content type
+	GetStoredImage(key string) ([]byte, string, error)
+
+	// GetShareCard gathers what a published recipe's share card shows
+	GetShareCard(recipeID int) (*models.RecipeShareCard, error)
+}
+
+type recipeImageService struct {
+	recipeRepo repository.RecipeRepository
+	imageRepo  repository.RecipeImageRepository
+	store      storage.Store
+
+	// newKey names stored uploads; tests swap it for fixed names
+	newKey func() string
+}
+
+func NewRecipeImageService(recipeRepo repository.RecipeRepository, imageRepo repository.RecipeImageRepository, store storage.Store) RecipeImageService {
+	return &recipeImageService{
+		recipeRepo: recipeRepo,
+		imageRepo:  imageRepo,
+		store:      store,
+		newKey:     uuid.NewString,
+	}
+}
+
+func (s *recipeImageService) GetRecipeImages(recipeID int) ([]models.RecipeImage, error) {
+	if _, err := s.visibleRecipe(recipeID); err != nil {
+		return nil, err
+	}
+	return s.imageRepo.GetByRecipeID(recipeID)
+}
+
+// GetShareCard uses the recipe's cover image.
+func (s *recipeImageService) GetShareCard(recipeID int) (*models.RecipeShareCard, error) {
+	recipe, err := s.visibleRecipe(recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := s.imageRepo.GetByRecipeID(recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RecipeShareCard{Recipe: *recipe, CoverImage: coverImage(images)}, nil
+}
+
+func (s *recipeImageService) AddRecipeImage(ctx context.Context, recipeID int, req models.AddRecipeImageRequest) (*models.RecipeImage, error) {
+	if err := authorizeRecipe(ctx, s.recipeRepo, policy.Update, recipeID); err != nil {
+		return nil, err
+	}
+
+	req.URL = strings.TrimSpace(req.URL)
+	parsed, err := url.Parse(req.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, domain.ErrInvalidImageURL
+	}
+
+	if req.Caption != nil {
+		caption, err := normalizeCaption(*req.Caption)
+		if err != nil {
+			return nil, err
+		}
+		req.Caption = caption
+	}
+
+	return s.imageRepo.Add(recipeID, req)
+}
+
+// UploadRecipeImage keeps the photo as uploaded and adds a JPEG thumbnail
+// next to it. Both are removed again if the image can't be added.
+func (s *recipeImageService) UploadRecipeImage(ctx context.Context, recipeID int, upload models.ImageUpload) (*models.RecipeImage, error) {
+	if err := authorizeRecipe(ctx, s.recipeRepo, policy.Update, recipeID); err != nil {
+		return nil, err
+	}
+	if len(upload.Data) == 0 {
+		return nil, domain.ErrImageFileRequired
+	}
+
+	var caption *string
+	if upload.Caption != nil {
+		var err error
+		if caption, err = normalizeCaption(*upload.Caption); err != nil {
+			return nil, err
+		}
+	}
+
+	img, format, err := decodeUpload(upload.Data)
+	if err != nil {
+		return nil, err
+	}
+	thumb, err := thumbnailJPEG(img)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s%d/%s%s", storedImagePrefix, recipeID, s.newKey(), format.extension)
+	thumbKey := thumbnailKey(key)
+
+	if err := s.store.Put(key, upload.Data, format.contentType); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(thumbKey, thumb, "image/jpeg"); err != nil {
+		s.removeStored(key)
+		return nil, err
+	}
+
+	thumbURL := s.store.URL(thumbKey)
+	image, err := s.imageRepo.Add(recipeID, models.AddRecipeImageRequest{
+		URL:          s.store.URL(key),
+		Caption:      caption,
+		ThumbnailURL: &thumbURL,
+		StorageKey:   &key,
+	})
+	if err != nil {
+		s.removeStored(key)
+		return nil, err
+	}
+	return image, nil
+}
+
+func (s *recipeImageService) GetStoredImage(key string) ([]byte, string, error) {
+	contentType, ok := storedContentType(key)
+	if !ok || !strings.HasPrefix(key, storedImagePrefix) || !storage.ValidKey(key) {
+		return nil, "", domain.ErrRecipeImageNotFound
+	}
+
+	data, err := s.store.Get(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, "", domain.ErrRecipeImageNotFound
+		}
+		return nil, "", err
+	}
+	return data, contentType, nil
+}
+
+// UpdateRecipeImages applies a new order, captions and cover in one go and
+// returns the images as they will now be shown.
+func (s *recipeImageService) UpdateRecipeImages(ctx context.Context, recipeID int, req models.UpdateRecipeImagesRequest) ([]models.RecipeImage, error) {
+	if err := authorizeRecipe(ctx, s.recipeRepo, policy.Update, recipeID); err != nil {
+		return nil, err
+	}
+
+	images, err := s.imageRepo.GetByRecipeID(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]models.RecipeImage, len(images))
+	for _, image := range images {
+		byID[image.ID] = image
+	}
+
+	if req.Order != nil {
+		if len(req.Order) != len(images) {
+			return nil, domain.ErrInvalidImageOrder
+		}
+		ordered := make([]models.RecipeImage, 0, len(images))
+		seen := make(map[int]bool, len(images))
+		for _, id := range req.Order {
+			image, ok := byID[id]
+			if !ok || seen[id] {
+				return nil, domain.ErrInvalidImageOrder
+			}
+			seen[id] = true
+			ordered = append(ordered, image)
+		}
+		images = ordered
+	}
+
+	for id, text := range req.Captions {
+		if _, ok := byID[id]; !ok {
+			return nil, domain.ErrRecipeImageNotFound
+		}
+		if _, err := normalizeCaption(text); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.CoverImageID != nil {
+		if _, ok := byID[*req.CoverImageID]; !ok {
+			return nil, domain.ErrRecipeImageNotFound
+		}
+	}
+
+	for i := range images {
+		images[i].Position = i + 1
+		if text, ok := req.Captions[images[i].ID]; ok {
+			images[i].Caption, _ = normalizeCaption(text)
+		}
+		if req.CoverImageID != nil {
+			images[i].IsCover = images[i].ID == *req.CoverImageID
+		}
+	}
+
+	if err := s.imageRepo.Save(recipeID, images); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (s *recipeImageService) DeleteRecipeImage(ctx context.Context, recipeID, imageID int) error {
+	if err := authorizeRecipe(ctx, s.recipeRepo, policy.Update, recipeID); err != nil {
+		return err
+	}
+
+	images, err := s.imageRepo.GetByRecipeID(recipeID)
+	if err != nil {
+		return err
+	}
+	var storageKey *string
+	for _, image := range images {
+		if image.ID == imageID {
+			storageKey = image.StorageKey
+		}
+	}
+
+	if err := s.imageRepo.Delete(recipeID, imageID); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrRecipeImageNotFound
+		}
+		return err
+	}
+
+	if storageKey != nil {
+		s.removeStored(*storageKey)
+	}
+	return nil
+}
+
+// removeStored deletes an uploaded photo and its thumbnail. It only logs
+// failures: the image is already gone from the recipe, and a leftover file
+// does no harm.
+func (s *recipeImageService) removeStored(key string) {
+	for _, k := range []string{key, thumbnailKey(key)} {
+		if err := s.store.Delete(k); err != nil {
+			logging.Logger.Warn("Failed to delete stored recipe image", "key", k, "error", err)
+		}
+	}
+}
+
+func (s *recipeImageService) visibleRecipe(recipeID int) (*models.Recipe, error) {
+	if recipeID <= 0 {
+		return nil, domain.ErrRecipeNotFound
+	}
+
+	recipe, err := s.recipeRepo.GetByID(recipeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRecipeNotFound
+		}
+		return nil, err
+	}
+	if !isPubliclyVisible(recipe) {
+		return nil, domain.ErrRecipeNotFound
+	}
+	return recipe, nil
+}
+
+// coverImage picks the cover, falling back to the first image for recipes
+// whose images predate covers. It is nil when there are no images.
+func coverImage(images []models.RecipeImage) *models.RecipeImage {
+	for i := range images {
+		if images[i].IsCover {
+			return &images[i]
+		}
+	}
+	if len(images) > 0 {
+		return &images[0]
+	}
+	return nil
+}
+
+// normalizeCaption trims a caption; a blank one clears it.
+func normalizeCaption(caption string) (*string, error) {
+	caption = strings.TrimSpace(caption)
+	if utf8.RuneCountInString(caption) > maxImageCaptionLength {
+		return nil, domain.ErrInvalidImageCaption
+	}
+	if caption == "" {
+		return nil, nil
+	}
+	return &caption, nil
+}
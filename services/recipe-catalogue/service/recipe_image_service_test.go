@@ -0,0 +1,325 @@
+package service
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/services/recipe-catalogue/storage"
+	"meal-prep/shared/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type recipeImageServiceTestSetup struct {
+	service    RecipeImageService
+	recipeRepo *mocks.MockRecipeRepository
+	imageRepo  *mocks.MockRecipeImageRepository
+	store      *storage.LocalStore
+}
+
+func setupRecipeImageServiceTest(t *testing.T) *recipeImageServiceTestSetup {
+	recipeRepo := new(mocks.MockRecipeRepository)
+	imageRepo := new(mocks.MockRecipeImageRepository)
+	store := storage.NewLocalStore(t.TempDir(), "http://localhost:8000/images")
+
+	service := NewRecipeImageService(recipeRepo, imageRepo, store)
+	service.(*recipeImageService).newKey = func() string { return "photo" }
+
+	return &recipeImageServiceTestSetup{
+		service:    service,
+		recipeRepo: recipeRepo,
+		imageRepo:  imageRepo,
+		store:      store,
+	}
+}
+
+func encodedPNG(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func storedImages() []models.RecipeImage {
+	caption := "Plated"
+	return []models.RecipeImage{
+		{ID: 10, RecipeID: 1, URL: "https://img.example/10.jpg", Caption: &caption, Position: 1, IsCover: true},
+		{ID: 11, RecipeID: 1, URL: "https://img.example/11.jpg", Position: 2},
+		{ID: 12, RecipeID: 1, URL: "https://img.example/12.jpg", Position: 3},
+	}
+}
+
+func TestRecipeImageService_UpdateRecipeImages_OrderCaptionsAndCover(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+	cover := 12
+	request := models.UpdateRecipeImagesRequest{
+		Order:        []int{12, 10, 11},
+		Captions:     map[int]string{11: "  Prep  ", 10: ""},
+		CoverImageID: &cover,
+	}
+
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+	setup.imageRepo.On("GetByRecipeID", 1).Return(storedImages(), nil)
+	setup.imageRepo.On("Save", 1, mock.AnythingOfType("[]models.RecipeImage")).Return(nil)
+
+	images, err := setup.service.UpdateRecipeImages(asUser(5), 1, request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{12, 10, 11}, []int{images[0].ID, images[1].ID, images[2].ID})
+	assert.Equal(t, []int{1, 2, 3}, []int{images[0].Position, images[1].Position, images[2].Position})
+	assert.True(t, images[0].IsCover)
+	assert.False(t, images[1].IsCover)
+	assert.Nil(t, images[1].Caption)
+	assert.Equal(t, "Prep", *images[2].Caption)
+	setup.imageRepo.AssertCalled(t, "Save", 1, images)
+}
+
+func TestRecipeImageService_UpdateRecipeImages_CaptionOnlyKeepsOrderAndCover(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+	setup.imageRepo.On("GetByRecipeID", 1).Return(storedImages(), nil)
+	setup.imageRepo.On("Save", 1, mock.AnythingOfType("[]models.RecipeImage")).Return(nil)
+
+	images, err := setup.service.UpdateRecipeImages(asUser(5), 1, models.UpdateRecipeImagesRequest{Captions: map[int]string{12: "Leftovers"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10, images[0].ID)
+	assert.True(t, images[0].IsCover)
+	assert.Equal(t, "Leftovers", *images[2].Caption)
+}
+
+func TestRecipeImageService_UpdateRecipeImages_Invalid(t *testing.T) {
+	missing := 99
+	tests := []struct {
+		name     string
+		request  models.UpdateRecipeImagesRequest
+		expected error
+	}{
+		{"order missing an image", models.UpdateRecipeImagesRequest{Order: []int{10, 11}}, domain.ErrInvalidImageOrder},
+		{"order repeats an image", models.UpdateRecipeImagesRequest{Order: []int{10, 10, 11}}, domain.ErrInvalidImageOrder},
+		{"order names a foreign image", models.UpdateRecipeImagesRequest{Order: []int{10, 11, 99}}, domain.ErrInvalidImageOrder},
+		{"caption for unknown image", models.UpdateRecipeImagesRequest{Captions: map[int]string{99: "x"}}, domain.ErrRecipeImageNotFound},
+		{"caption too long", models.UpdateRecipeImagesRequest{Captions: map[int]string{10: strings.Repeat("a", 201)}}, domain.ErrInvalidImageCaption},
+		{"unknown cover", models.UpdateRecipeImagesRequest{CoverImageID: &missing}, domain.ErrRecipeImageNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupRecipeImageServiceTest(t)
+			setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+			setup.imageRepo.On("GetByRecipeID", 1).Return(storedImages(), nil)
+
+			_, err := setup.service.UpdateRecipeImages(asUser(5), 1, tt.request)
+
+			assert.Equal(t, tt.expected, err)
+			setup.imageRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRecipeImageService_UpdateRecipeImages_Forbidden(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+
+	_, err := setup.service.UpdateRecipeImages(asUser(6), 1, models.UpdateRecipeImagesRequest{})
+
+	assert.Equal(t, domain.ErrForbidden, err)
+	setup.imageRepo.AssertNotCalled(t, "GetByRecipeID", mock.Anything)
+}
+
+func TestRecipeImageService_AddRecipeImage_InvalidURL(t *testing.T) {
+	for _, rawURL := range []string{"", "not a url", "ftp://img.example/a.jpg", "/relative.jpg"} {
+		setup := setupRecipeImageServiceTest(t)
+		setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+
+		_, err := setup.service.AddRecipeImage(asUser(5), 1, models.AddRecipeImageRequest{URL: rawURL})
+
+		assert.Equal(t, domain.ErrInvalidImageURL, err, rawURL)
+	}
+}
+
+func TestRecipeImageService_AddRecipeImage_BlankCaptionIsDropped(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+	blank := "   "
+	expected := models.AddRecipeImageRequest{URL: "https://img.example/a.jpg"}
+
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+	setup.imageRepo.On("Add", 1, expected).Return(&models.RecipeImage{ID: 1, RecipeID: 1, URL: expected.URL, Position: 1, IsCover: true}, nil)
+
+	image, err := setup.service.AddRecipeImage(asUser(5), 1, models.AddRecipeImageRequest{URL: " https://img.example/a.jpg ", Caption: &blank})
+
+	assert.NoError(t, err)
+	assert.True(t, image.IsCover)
+	setup.imageRepo.AssertExpectations(t)
+}
+
+func TestRecipeImageService_DeleteRecipeImage_NotFound(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+	setup.imageRepo.On("GetByRecipeID", 1).Return(storedImages(), nil)
+	setup.imageRepo.On("Delete", 1, 99).Return(sql.ErrNoRows)
+
+	err := setup.service.DeleteRecipeImage(asUser(5), 1, 99)
+
+	assert.Equal(t, domain.ErrRecipeImageNotFound, err)
+}
+
+func TestRecipeImageService_DeleteRecipeImage_RemovesUpload(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+	key := "recipes/1/photo.png"
+	assert.NoError(t, setup.store.Put(key, []byte("png"), "image/png"))
+	assert.NoError(t, setup.store.Put("recipes/1/photo-thumb.jpg", []byte("jpg"), "image/jpeg"))
+	images := storedImages()
+	images[1].StorageKey = &key
+
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+	setup.imageRepo.On("GetByRecipeID", 1).Return(images, nil)
+	setup.imageRepo.On("Delete", 1, 11).Return(nil)
+
+	err := setup.service.DeleteRecipeImage(asUser(5), 1, 11)
+
+	assert.NoError(t, err)
+	_, err = setup.store.Get(key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = setup.store.Get("recipes/1/photo-thumb.jpg")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestRecipeImageService_UploadRecipeImage_StoresPhotoAndThumbnail(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+	data := encodedPNG(t, 1000, 500)
+	caption := "  Plated  "
+
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+	setup.imageRepo.On("Add", 1, mock.AnythingOfType("models.AddRecipeImageRequest")).
+		Return(&models.RecipeImage{ID: 20, RecipeID: 1}, nil)
+
+	image, err := setup.service.UploadRecipeImage(asUser(5), 1, models.ImageUpload{Data: data, Caption: &caption})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 20, image.ID)
+
+	req := setup.imageRepo.Calls[0].Arguments.Get(1).(models.AddRecipeImageRequest)
+	assert.Equal(t, "http://localhost:8000/images/recipes/1/photo.png", req.URL)
+	assert.Equal(t, "http://localhost:8000/images/recipes/1/photo-thumb.jpg", *req.ThumbnailURL)
+	assert.Equal(t, "recipes/1/photo.png", *req.StorageKey)
+	assert.Equal(t, "Plated", *req.Caption)
+
+	stored, contentType, err := setup.service.GetStoredImage("recipes/1/photo.png")
+	assert.NoError(t, err)
+	assert.Equal(t, data, stored)
+	assert.Equal(t, "image/png", contentType)
+
+	thumb, contentType, err := setup.service.GetStoredImage("recipes/1/photo-thumb.jpg")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/jpeg", contentType)
+	config, err := jpeg.DecodeConfig(bytes.NewReader(thumb))
+	assert.NoError(t, err)
+	assert.Equal(t, 320, config.Width)
+	assert.Equal(t, 160, config.Height)
+}
+
+func TestRecipeImageService_UploadRecipeImage_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected error
+	}{
+		{"empty", nil, domain.ErrImageFileRequired},
+		{"not an image", []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"/>"), domain.ErrUnsupportedImage},
+		{"too many pixels", encodedPNG(t, 6000, 5000), domain.ErrImageTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupRecipeImageServiceTest(t)
+			setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+
+			_, err := setup.service.UploadRecipeImage(asUser(5), 1, models.ImageUpload{Data: tt.data})
+
+			assert.Equal(t, tt.expected, err)
+			setup.imageRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRecipeImageService_UploadRecipeImage_RemovesFilesWhenAddFails(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+	setup.recipeRepo.On("GetOwnerID", 1).Return(5, nil)
+	setup.imageRepo.On("Add", 1, mock.Anything).Return(nil, errors.New("db down"))
+
+	_, err := setup.service.UploadRecipeImage(asUser(5), 1, models.ImageUpload{Data: encodedPNG(t, 10, 10)})
+
+	assert.Error(t, err)
+	_, err = setup.store.Get("recipes/1/photo.png")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = setup.store.Get("recipes/1/photo-thumb.jpg")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestRecipeImageService_GetStoredImage_OnlyServesUploads(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+
+	for _, key := range []string{"recipes/1/missing.jpg", "other/1.jpg", "recipes/../secret.png", "recipes/1/notes.txt"} {
+		_, _, err := setup.service.GetStoredImage(key)
+		assert.Equal(t, domain.ErrRecipeImageNotFound, err, key)
+	}
+}
+
+func TestRecipeImageService_GetRecipeImages_HidesPrivateRecipes(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+	setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, Status: models.RecipeStatusPrivate}, nil)
+
+	_, err := setup.service.GetRecipeImages(1)
+
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+}
+
+func TestRecipeImageService_GetShareCard_UsesCover(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+	setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, Name: "Dal", Status: models.RecipeStatusPublished}, nil)
+	setup.imageRepo.On("GetByRecipeID", 1).Return([]models.RecipeImage{
+		{ID: 10, RecipeID: 1, Position: 1},
+		{ID: 11, RecipeID: 1, Position: 2, IsCover: true},
+	}, nil)
+
+	card, err := setup.service.GetShareCard(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Dal", card.Recipe.Name)
+	assert.Equal(t, 11, card.CoverImage.ID)
+}
+
+func TestRecipeImageService_GetShareCard_WithoutImages(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+	setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, Status: models.RecipeStatusPublished}, nil)
+	setup.imageRepo.On("GetByRecipeID", 1).Return([]models.RecipeImage{}, nil)
+
+	card, err := setup.service.GetShareCard(1)
+
+	assert.NoError(t, err)
+	assert.Nil(t, card.CoverImage)
+}
+
+func TestRecipeImageService_GetShareCard_HidesDrafts(t *testing.T) {
+	setup := setupRecipeImageServiceTest(t)
+	setup.recipeRepo.On("GetByID", 1).Return(&models.Recipe{ID: 1, Status: models.RecipeStatusDraft}, nil)
+
+	_, err := setup.service.GetShareCard(1)
+
+	assert.Equal(t, domain.ErrRecipeNotFound, err)
+}
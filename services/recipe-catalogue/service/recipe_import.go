@@ -0,0 +1,573 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"meal-prep/shared/units"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// pageRecipe is what a web page's schema.org markup says about its recipe.
+type pageRecipe struct {
+	Name             string
+	Description      string
+	Categories       []string
+	Ingredients      []string
+	Instructions     []string
+	TotalTimeMinutes *int
+}
+
+// parseRecipePage finds the schema.org Recipe on an HTML page, in JSON-LD,
+// which most recipe sites publish, or else in microdata. ok is false when
+// the page has neither.
+func parseRecipePage(page []byte) (pageRecipe, bool) {
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return pageRecipe{}, false
+	}
+	if recipe, ok := jsonLDRecipe(doc); ok {
+		return recipe, true
+	}
+	return microdataRecipe(doc)
+}
+
+func jsonLDRecipe(doc *html.Node) (pageRecipe, bool) {
+	var recipe pageRecipe
+	found := false
+	walkNodes(doc, func(n *html.Node) bool {
+		if found || n.DataAtom != atom.Script || !strings.EqualFold(strings.TrimSpace(attribute(n, "type")), "application/ld+json") {
+			return !found
+		}
+		var data interface{}
+		if err := json.Unmarshal([]byte(textContent(n)), &data); err != nil {
+			return false
+		}
+		if object, ok := findJSONLDRecipe(data); ok {
+			recipe, found = recipeFromJSONLD(object), true
+		}
+		return false
+	})
+	return recipe, found
+}
+
+// findJSONLDRecipe looks through a JSON-LD document, lists and @graph
+// included, for the first object typed Recipe.
+func findJSONLDRecipe(data interface{}) (map[string]interface{}, bool) {
+	switch value := data.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if object, ok := findJSONLDRecipe(item); ok {
+				return object, true
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range jsonStrings(value["@type"]) {
+			if isSchemaType(name, "Recipe") {
+				return value, true
+			}
+		}
+		if graph, ok := value["@graph"]; ok {
+			return findJSONLDRecipe(graph)
+		}
+	}
+	return nil, false
+}
+
+func recipeFromJSONLD(object map[string]interface{}) pageRecipe {
+	recipe := pageRecipe{
+		Name:        firstString(jsonStrings(object["name"])),
+		Description: firstString(jsonStrings(object["description"])),
+		Categories:  splitCategories(jsonStrings(object["recipeCategory"])),
+	}
+	ingredients := object["recipeIngredient"]
+	if ingredients == nil {
+		// The property's name before schema.org renamed it
+		ingredients = object["ingredients"]
+	}
+	for _, line := range jsonStrings(ingredients) {
+		if line = cleanText(line); line != "" {
+			recipe.Ingredients = append(recipe.Ingredients, line)
+		}
+	}
+	recipe.Instructions = jsonLDInstructions(object["recipeInstructions"])
+	recipe.TotalTimeMinutes = recipeMinutes(
+		firstString(jsonStrings(object["totalTime"])),
+		firstString(jsonStrings(object["prepTime"])),
+		firstString(jsonStrings(object["cookTime"])))
+	return recipe
+}
+
+// jsonLDInstructions flattens recipeInstructions, given as text, a list of
+// texts or HowToSteps, or HowToSections of steps, into one step per entry.
+func jsonLDInstructions(value interface{}) []string {
+	var steps []string
+	switch value := value.(type) {
+	case string:
+		steps = append(steps, textLines(value)...)
+	case []interface{}:
+		for _, item := range value {
+			steps = append(steps, jsonLDInstructions(item)...)
+		}
+	case map[string]interface{}:
+		if elements, ok := value["itemListElement"]; ok {
+			return jsonLDInstructions(elements)
+		}
+		text := firstString(jsonStrings(value["text"]))
+		if text == "" {
+			text = firstString(jsonStrings(value["name"]))
+		}
+		if text = cleanText(text); text != "" {
+			steps = append(steps, text)
+		}
+	}
+	return steps
+}
+
+func microdataRecipe(doc *html.Node) (pageRecipe, bool) {
+	var scope *html.Node
+	walkNodes(doc, func(n *html.Node) bool {
+		if scope == nil && hasAttribute(n, "itemscope") && isSchemaType(attribute(n, "itemtype"), "Recipe") {
+			scope = n
+		}
+		return scope == nil
+	})
+	if scope == nil {
+		return pageRecipe{}, false
+	}
+
+	var recipe pageRecipe
+	var totalTime, prepTime, cookTime string
+	microdataProperties(scope, func(property string, n *html.Node) {
+		switch property {
+		case "name":
+			if recipe.Name == "" {
+				recipe.Name = cleanText(microdataValue(n))
+			}
+		case "description":
+			if recipe.Description == "" {
+				recipe.Description = cleanText(microdataValue(n))
+			}
+		case "recipeCategory":
+			recipe.Categories = append(recipe.Categories, splitCategories([]string{microdataValue(n)})...)
+		case "recipeIngredient", "ingredients":
+			if line := cleanText(microdataValue(n)); line != "" {
+				recipe.Ingredients = append(recipe.Ingredients, line)
+			}
+		case "recipeInstructions":
+			recipe.Instructions = append(recipe.Instructions, microdataInstructions(n)...)
+		case "totalTime":
+			totalTime = microdataValue(n)
+		case "prepTime":
+			prepTime = microdataValue(n)
+		case "cookTime":
+			cookTime = microdataValue(n)
+		}
+	})
+	recipe.TotalTimeMinutes = recipeMinutes(totalTime, prepTime, cookTime)
+	return recipe, true
+}
+
+// microdataProperties calls fn with each property of the item scope, not
+// descending into the items nested in it.
+func microdataProperties(scope *html.Node, fn func(property string, n *html.Node)) {
+	for child := scope.FirstChild; child != nil; child = child.NextSibling {
+		for _, property := range strings.Fields(attribute(child, "itemprop")) {
+			fn(property, child)
+		}
+		if !hasAttribute(child, "itemscope") {
+			microdataProperties(child, fn)
+		}
+	}
+}
+
+// microdataInstructions reads a recipeInstructions property: a HowToStep
+// item, a list with a step per item, or text with a step per line.
+func microdataInstructions(n *html.Node) []string {
+	if hasAttribute(n, "itemscope") {
+		var text string
+		microdataProperties(n, func(property string, step *html.Node) {
+			if property == "text" && text == "" {
+				text = cleanText(microdataValue(step))
+			}
+		})
+		if text == "" {
+			text = cleanText(textContent(n))
+		}
+		if text == "" {
+			return nil
+		}
+		return []string{text}
+	}
+
+	var steps []string
+	walkNodes(n, func(item *html.Node) bool {
+		if item.DataAtom != atom.Li {
+			return true
+		}
+		if text := cleanText(textContent(item)); text != "" {
+			steps = append(steps, text)
+		}
+		return false
+	})
+	if len(steps) > 0 {
+		return steps
+	}
+	return textLines(textContent(n))
+}
+
+// microdataValue is a property's value: the attribute that carries it on
+// meta, time and link elements, or else the element's text.
+func microdataValue(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.Meta:
+		return attribute(n, "content")
+	case atom.Time:
+		if datetime := attribute(n, "datetime"); datetime != "" {
+			return datetime
+		}
+	case atom.Link, atom.A:
+		if href := attribute(n, "href"); href != "" {
+			return href
+		}
+	}
+	if content := attribute(n, "content"); content != "" {
+		return content
+	}
+	return textContent(n)
+}
+
+// walkNodes visits n and its descendants in document order, skipping the
+// descendants of nodes fn returns false for.
+func walkNodes(n *html.Node, fn func(*html.Node) bool) {
+	if !fn(n) {
+		return
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		walkNodes(child, fn)
+	}
+}
+
+// textContent is the text under n, with line breaks where block elements
+// and <br> break it.
+func textContent(n *html.Node) string {
+	var text strings.Builder
+	walkNodes(n, func(node *html.Node) bool {
+		switch {
+		case node.Type == html.TextNode:
+			text.WriteString(node.Data)
+		case node.DataAtom == atom.Br:
+			text.WriteString("\n")
+		case node.DataAtom == atom.Script || node.DataAtom == atom.Style:
+			return node == n
+		}
+		return true
+	})
+	return text.String()
+}
+
+// blockTags break text into lines in textLines, as browsers show them.
+var blockTags = regexp.MustCompile(`(?i)<\s*(br|/p|/li|/div)\s*/?>`)
+
+var anyTag = regexp.MustCompile(`<[^>]*>`)
+
+// textLines splits text, which JSON-LD sometimes gives as HTML, into its
+// non-empty lines.
+func textLines(text string) []string {
+	text = anyTag.ReplaceAllString(blockTags.ReplaceAllString(text, "\n"), " ")
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = cleanText(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// cleanText unescapes HTML entities left in the text and collapses its
+// whitespace.
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
+
+func attribute(n *html.Node, name string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func hasAttribute(n *html.Node, name string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isSchemaType reports whether a type given as "Recipe",
+// "https://schema.org/Recipe" or "schema:Recipe" is name.
+func isSchemaType(value, name string) bool {
+	for _, typ := range strings.Fields(value) {
+		if i := strings.LastIndexAny(typ, "/:"); i >= 0 {
+			typ = typ[i+1:]
+		}
+		if typ == name {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonStrings reads a JSON-LD value given as a string or a list of them;
+// objects stand for their name, as in {"@type": "Thing", "name": "Dinner"}.
+func jsonStrings(value interface{}) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case float64:
+		return []string{strconv.FormatFloat(value, 'f', -1, 64)}
+	case []interface{}:
+		var values []string
+		for _, item := range value {
+			values = append(values, jsonStrings(item)...)
+		}
+		return values
+	case map[string]interface{}:
+		if name, ok := value["name"].(string); ok {
+			return []string{name}
+		}
+	}
+	return nil
+}
+
+func firstString(values []string) string {
+	for _, value := range values {
+		if value = cleanText(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// splitCategories splits categories given as "Dinner, Main course" apart.
+func splitCategories(values []string) []string {
+	var categories []string
+	for _, value := range values {
+		for _, category := range strings.Split(value, ",") {
+			if category = cleanText(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
+	}
+	return categories
+}
+
+var isoDuration = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// recipeMinutes is the recipe's total time in whole minutes, from its ISO
+// 8601 totalTime, or else from its prep and cook times. nil when the page
+// gives none.
+func recipeMinutes(totalTime, prepTime, cookTime string) *int {
+	if minutes, ok := durationMinutes(totalTime); ok {
+		return &minutes
+	}
+	prep, prepOK := durationMinutes(prepTime)
+	cook, cookOK := durationMinutes(cookTime)
+	if !prepOK && !cookOK {
+		return nil
+	}
+	minutes := prep + cook
+	return &minutes
+}
+
+func durationMinutes(duration string) (int, bool) {
+	match := isoDuration.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(duration)))
+	if match == nil || duration == "" {
+		return 0, false
+	}
+	days, _ := strconv.Atoi(match[1])
+	hours, _ := strconv.Atoi(match[2])
+	minutes, _ := strconv.Atoi(match[3])
+	seconds, _ := strconv.ParseFloat(match[4], 64)
+	total := days*24*60 + hours*60 + minutes + int(seconds+59)/60
+	return total, total > 0
+}
+
+// ingredientLine is an ingredient line of a recipe page taken apart, e.g.
+// "2 ½ cups plain flour (sifted)" into 2.5, "cup", "plain flour" and
+// "sifted".
+type ingredientLine struct {
+	Quantity float64 // 0 when the line gives none, as in "salt to taste"
+	Unit     string  // canonical, or empty when the line gives none
+	Name     string
+	Notes    string
+}
+
+var parenthetical = regexp.MustCompile(`\(([^)]*)\)`)
+
+// vulgarFractions are the fraction characters recipe sites use.
+var vulgarFractions = map[rune]float64{
+	'½': 1.0 / 2, '⅓': 1.0 / 3, '⅔': 2.0 / 3, '¼': 1.0 / 4, '¾': 3.0 / 4,
+	'⅕': 1.0 / 5, '⅖': 2.0 / 5, '⅗': 3.0 / 5, '⅘': 4.0 / 5, '⅙': 1.0 / 6,
+	'⅚': 5.0 / 6, '⅛': 1.0 / 8, '⅜': 3.0 / 8, '⅝': 5.0 / 8, '⅞': 7.0 / 8,
+}
+
+func parseIngredientLine(line string) ingredientLine {
+	var parsed ingredientLine
+	var notes []string
+	text := parenthetical.ReplaceAllStringFunc(cleanText(line), func(match string) string {
+		if note := strings.TrimSpace(match[1 : len(match)-1]); note != "" {
+			notes = append(notes, note)
+		}
+		return " "
+	})
+	if name, note, ok := strings.Cut(text, ","); ok {
+		text = name
+		if note = strings.TrimSpace(note); note != "" {
+			notes = append(notes, note)
+		}
+	}
+
+	words := strings.Fields(text)
+	i := 0
+	if len(words) > 0 {
+		if quantity, suffix, ok := parseAmount(words[0]); ok {
+			parsed.Quantity, i = quantity, 1
+			// A whole number and a fraction, as in "1 1/2"
+			if suffix == "" && i < len(words) && quantity == float64(int(quantity)) {
+				if fraction, fractionSuffix, ok := parseAmount(words[i]); ok && fraction < 1 {
+					parsed.Quantity += fraction
+					suffix = fractionSuffix
+					i++
+				}
+			}
+			// Ranges, as in "2 to 3", take their lower end
+			if suffix == "" && i+1 < len(words) && (words[i] == "-" || words[i] == "–" || words[i] == "to") {
+				if _, rangeSuffix, ok := parseAmount(words[i+1]); ok {
+					suffix = rangeSuffix
+					i += 2
+				}
+			}
+			if suffix != "" {
+				parsed.Unit = unitName(suffix)
+			} else if i < len(words) {
+				// Two-word units first, as in "fl oz"
+				if i+1 < len(words) {
+					if unit := unitName(words[i] + " " + words[i+1]); unit != "" {
+						parsed.Unit, i = unit, i+2
+					}
+				}
+				if parsed.Unit == "" {
+					if unit := unitName(words[i]); unit != "" {
+						parsed.Unit, i = unit, i+1
+					}
+				}
+			}
+		}
+	}
+
+	name := strings.Join(words[i:], " ")
+	name = strings.TrimPrefix(name, "of ")
+	for _, suffix := range []string{" to taste", " as needed"} {
+		if trimmed, ok := strings.CutSuffix(name, suffix); ok {
+			name = trimmed
+			notes = append(notes, strings.TrimSpace(suffix))
+		}
+	}
+	parsed.Name = strings.TrimSpace(name)
+	parsed.Notes = strings.Join(notes, ", ")
+	return parsed
+}
+
+// parseAmount reads a quantity as recipe sites write it: "2", "1.5",
+// "1,5", "1/2", "½", "1½", the lower end of "2-3", and with the unit
+// attached, as in "200g", which comes back as suffix.
+func parseAmount(word string) (quantity float64, suffix string, ok bool) {
+	end := strings.IndexFunc(word, func(r rune) bool {
+		_, fraction := vulgarFractions[r]
+		return !unicode.IsDigit(r) && r != '.' && r != ',' && r != '/' && !fraction
+	})
+	number, rest := word, ""
+	if end >= 0 {
+		number, rest = word[:end], word[end:]
+	}
+	if number == "" {
+		return 0, "", false
+	}
+	// Ranges, as in "2-3", take their lower end
+	if r, size := firstRune(rest); r == '-' || r == '–' {
+		rest = strings.TrimLeftFunc(rest[size:], func(r rune) bool {
+			_, fraction := vulgarFractions[r]
+			return unicode.IsDigit(r) || r == '.' || r == ',' || r == '/' || fraction
+		})
+	}
+	if rest != "" && unitName(rest) == "" {
+		return 0, "", false
+	}
+
+	for r, value := range vulgarFractions {
+		if whole, found := strings.CutSuffix(number, string(r)); found {
+			quantity = value
+			if whole != "" {
+				n, err := strconv.Atoi(whole)
+				if err != nil {
+					return 0, "", false
+				}
+				quantity += float64(n)
+			}
+			return quantity, rest, true
+		}
+	}
+	if numerator, denominator, found := strings.Cut(number, "/"); found {
+		n, err1 := strconv.Atoi(numerator)
+		d, err2 := strconv.Atoi(denominator)
+		if err1 != nil || err2 != nil || d == 0 || n <= 0 {
+			return 0, "", false
+		}
+		return float64(n) / float64(d), rest, true
+	}
+	quantity, err := strconv.ParseFloat(strings.Replace(number, ",", ".", 1), 64)
+	if err != nil || quantity <= 0 {
+		return 0, "", false
+	}
+	return quantity, rest, true
+}
+
+func firstRune(s string) (rune, int) {
+	for _, r := range s {
+		return r, len(string(r))
+	}
+	return 0, 0
+}
+
+// unitName is the canonical name of a unit written as word, trailing dot
+// and all ("tbsp."), or empty if word is no unit.
+func unitName(word string) string {
+	word = strings.TrimSuffix(strings.ToLower(word), ".")
+	if units.KindOf(word) == units.KindUnknown {
+		return ""
+	}
+	return units.Normalize(word)
+}
+
+// nameVariants lists the names an ingredient line's name could be in the
+// catalogue under, dropping its leading words one by one, so "2 large
+// free-range eggs" can match "Eggs". Longer names come first.
+func nameVariants(name string) []string {
+	words := strings.Fields(name)
+	variants := make([]string, 0, len(words))
+	for i := range words {
+		variants = append(variants, strings.Join(words[i:], " "))
+	}
+	return variants
+}
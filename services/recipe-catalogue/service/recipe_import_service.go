@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+	"meal-prep/shared/units"
+)
+
+const (
+	// maxImportedRecipeName is the recipes table's limit; longer page
+	// titles are cut
+	maxImportedRecipeName = 200
+
+	// maxImportedIngredients and maxImportedSteps bound what a page can add
+	// to one draft
+	maxImportedIngredients = 100
+	maxImportedSteps       = 100
+)
+
+// PageFetcher downloads the web page a recipe is imported from. Handlers
+// wire in one that refuses internal addresses.
+type PageFetcher interface {
+	FetchPage(ctx context.Context, pageURL string) ([]byte, error)
+}
+
+type RecipeImportService interface {
+	// ImportFromURL makes a draft of the user's from the schema.org recipe
+	// on a web page, its ingredient lines matched to the catalogue's
+	// ingredients. Lines matching none are left for the user to add.
+	ImportFromURL(ctx context.Context, userID int, req models.ImportRecipeRequest) (*models.ImportedRecipe, error)
+}
+
+type recipeImportService struct {
+	recipeRepo     repository.RecipeRepository
+	stepRepo       repository.StepRepository
+	categoryRepo   repository.CategoryRepository
+	ingredientRepo repository.IngredientRepository
+	fetcher        PageFetcher
+}
+
+func NewRecipeImportService(recipeRepo repository.RecipeRepository, stepRepo repository.StepRepository, categoryRepo repository.CategoryRepository, ingredientRepo repository.IngredientRepository, fetcher PageFetcher) RecipeImportService {
+	return &recipeImportService{
+		recipeRepo:     recipeRepo,
+		stepRepo:       stepRepo,
+		categoryRepo:   categoryRepo,
+		ingredientRepo: ingredientRepo,
+		fetcher:        fetcher,
+	}
+}
+
+func (s *recipeImportService) ImportFromURL(ctx context.Context, userID int, req models.ImportRecipeRequest) (*models.ImportedRecipe, error) {
+	pageURL, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		return nil, domain.ErrInvalidImportURL
+	}
+
+	page, err := s.fetcher.FetchPage(ctx, pageURL.String())
+	if err != nil {
+		logging.WithContext(ctx).Warn("Failed to fetch recipe page", "url", pageURL.String(), "error", err)
+		return nil, domain.ErrImportPageUnavailable
+	}
+	found, ok := parseRecipePage(page)
+	if !ok || found.Name == "" {
+		return nil, domain.ErrNoRecipeOnPage
+	}
+
+	categoryID, err := s.importCategory(req.CategoryID, found.Categories)
+	if err != nil {
+		return nil, err
+	}
+
+	draft := models.CreateRecipeWithIngredientsRequest{
+		Name:       truncateRunes(found.Name, maxImportedRecipeName),
+		CategoryID: categoryID,
+		Status:     models.RecipeStatusDraft,
+	}
+	// Credit the page the recipe came from
+	description := "Imported from " + pageURL.String()
+	if found.Description != "" {
+		description = found.Description + "\n\n" + description
+	}
+	draft.Description = &description
+	if found.TotalTimeMinutes != nil && validateDifficultyAndTime(nil, found.TotalTimeMinutes) == nil {
+		draft.TotalTimeMinutes = found.TotalTimeMinutes
+	}
+
+	var unmatched []string
+	draft.Ingredients, unmatched, err = s.matchIngredients(found.Ingredients)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.recipeRepo.CreateWithIngredients(userID, draft)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(found.Instructions) > maxImportedSteps {
+		found.Instructions = found.Instructions[:maxImportedSteps]
+	}
+	if len(found.Instructions) > 0 {
+		steps := make([]models.CreateStepRequest, len(found.Instructions))
+		for i, instruction := range found.Instructions {
+			steps[i] = models.CreateStepRequest{Instruction: instruction}
+		}
+		created.Steps, err = s.stepRepo.ReplaceForRecipe(created.ID, steps)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Importing the same page twice is the likeliest way to end up with a
+	// copy of a recipe
+	warnPossibleDuplicates(s.recipeRepo, s.ingredientRepo, &created.Recipe, created.Ingredients)
+
+	logging.WithContext(ctx).Info("Recipe imported", "recipe_id", created.ID, "url", pageURL.String(),
+		"ingredients", len(draft.Ingredients), "unmatched", len(unmatched))
+	return &models.ImportedRecipe{
+		Draft:                *created,
+		SourceURL:            pageURL.String(),
+		UnmatchedIngredients: unmatched,
+	}, nil
+}
+
+// importCategory is the category asked for, or else the first of the page's
+// categories the catalogue has a category of the same name for.
+func (s *recipeImportService) importCategory(categoryID *int, pageCategories []string) (int, error) {
+	if categoryID != nil {
+		if *categoryID <= 0 {
+			return 0, domain.ErrInvalidCategory
+		}
+		exists, err := s.categoryRepo.Exists(*categoryID)
+		if err != nil {
+			return 0, err
+		}
+		if !exists {
+			return 0, domain.ErrCategoryNotFound
+		}
+		return *categoryID, nil
+	}
+
+	if len(pageCategories) > 0 {
+		categories, err := s.categoryRepo.GetAll()
+		if err != nil {
+			return 0, err
+		}
+		for _, name := range pageCategories {
+			for _, category := range categories {
+				if strings.EqualFold(category.Name, name) {
+					return category.ID, nil
+				}
+			}
+		}
+	}
+	return 0, domain.ErrImportCategoryRequired
+}
+
+// matchIngredients turns the page's ingredient lines into recipe lines for
+// the catalogue ingredients they match. Lines matching none, or an
+// ingredient an earlier line already matched, come back as unmatched.
+func (s *recipeImportService) matchIngredients(lines []string) ([]models.AddRecipeIngredientRequest, []string, error) {
+	if len(lines) > maxImportedIngredients {
+		lines = lines[:maxImportedIngredients]
+	}
+
+	parsed := make([]ingredientLine, len(lines))
+	var names []string
+	seen := make(map[string]bool)
+	for i, line := range lines {
+		parsed[i] = parseIngredientLine(line)
+		for _, name := range nameVariants(parsed[i].Name) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	matches := map[string]int{}
+	if len(names) > 0 {
+		var err error
+		if matches, err = s.ingredientRepo.MatchIngredientNames(names); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var ingredients []models.AddRecipeIngredientRequest
+	unmatched := []string{}
+	used := make(map[int]bool)
+	for i, line := range parsed {
+		ingredientID := 0
+		for _, name := range nameVariants(line.Name) {
+			if id, ok := matches[name]; ok {
+				ingredientID = id
+				break
+			}
+		}
+		if ingredientID == 0 || used[ingredientID] {
+			unmatched = append(unmatched, lines[i])
+			continue
+		}
+		used[ingredientID] = true
+
+		ingredient := models.AddRecipeIngredientRequest{
+			IngredientID: ingredientID,
+			Quantity:     line.Quantity,
+			Unit:         line.Unit,
+		}
+		// Lines without an amount, as in "salt to taste", keep the line as
+		// written for the user to settle
+		if ingredient.Quantity == 0 {
+			ingredient.Quantity = 1
+			line.Notes = lines[i]
+		}
+		if ingredient.Unit == "" {
+			ingredient.Unit = units.Piece
+		}
+		if line.Notes != "" {
+			notes := line.Notes
+			ingredient.Notes = &notes
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+	return ingredients, unmatched, nil
+}
+
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return strings.TrimSpace(string([]rune(s)[:limit]))
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePages serves pages by URL; others fail to fetch.
+type fakePages map[string]string
+
+func (f fakePages) FetchPage(ctx context.Context, pageURL string) ([]byte, error) {
+	page, ok := f[pageURL]
+	if !ok {
+		return nil, errors.New("404 Not Found")
+	}
+	return []byte(page), nil
+}
+
+const importPageURL = "https://example.com/recipes/shakshuka"
+
+const importPage = `<script type="application/ld+json">
+{"@type": "Recipe", "name": "Shakshuka", "recipeCategory": "Breakfast", "totalTime": "PT35M",
+ "recipeIngredient": ["2 tbsp olive oil", "4 large eggs", "1 tin chopped tomatoes", "2 eggs", "Salt to taste"],
+ "recipeInstructions": [{"@type": "HowToStep", "text": "Simmer the sauce."}, {"@type": "HowToStep", "text": "Poach the eggs."}]}
+</script>`
+
+type recipeImportServiceTestSetup struct {
+	service        RecipeImportService
+	recipeRepo     *mocks.MockRecipeRepository
+	stepRepo       *mocks.MockStepRepository
+	categoryRepo   *mocks.MockCategoryRepository
+	ingredientRepo *mocks.MockIngredientRepository
+}
+
+func setupRecipeImportServiceTest() *recipeImportServiceTestSetup {
+	logging.Init("test")
+	recipeRepo := new(mocks.MockRecipeRepository)
+	stepRepo := new(mocks.MockStepRepository)
+	categoryRepo := new(mocks.MockCategoryRepository)
+	ingredientRepo := new(mocks.MockIngredientRepository)
+	// The user has no other recipes unless a test says otherwise
+	recipeRepo.On("GetByOwner", mock.Anything, "", duplicateCandidateParams).Return([]models.Recipe{}, 0, nil).Maybe()
+
+	return &recipeImportServiceTestSetup{
+		service:        NewRecipeImportService(recipeRepo, stepRepo, categoryRepo, ingredientRepo, fakePages{importPageURL: importPage}),
+		recipeRepo:     recipeRepo,
+		stepRepo:       stepRepo,
+		categoryRepo:   categoryRepo,
+		ingredientRepo: ingredientRepo,
+	}
+}
+
+func TestRecipeImportService_ImportFromURL_CreatesDraft(t *testing.T) {
+	setup := setupRecipeImportServiceTest()
+	setup.categoryRepo.On("GetAll").Return([]models.Category{{ID: 1, Name: "Dinner"}, {ID: 3, Name: "breakfast"}}, nil)
+	setup.ingredientRepo.On("MatchIngredientNames", mock.Anything).Return(map[string]int{
+		"olive oil": 11,
+		"eggs":      12,
+		"Salt":      13,
+	}, nil)
+	created := &models.RecipeWithIngredients{Recipe: models.Recipe{ID: 20, Name: "Shakshuka", Status: models.RecipeStatusDraft}}
+	var draft models.CreateRecipeWithIngredientsRequest
+	setup.recipeRepo.On("CreateWithIngredients", 7, mock.Anything).Run(func(args mock.Arguments) {
+		draft = args.Get(1).(models.CreateRecipeWithIngredientsRequest)
+	}).Return(created, nil)
+	steps := []models.RecipeStep{{ID: 1, Instruction: "Simmer the sauce."}, {ID: 2, Instruction: "Poach the eggs."}}
+	setup.stepRepo.On("ReplaceForRecipe", 20, []models.CreateStepRequest{
+		{Instruction: "Simmer the sauce."}, {Instruction: "Poach the eggs."},
+	}).Return(steps, nil)
+
+	result, err := setup.service.ImportFromURL(context.Background(), 7, models.ImportRecipeRequest{URL: " " + importPageURL + " "})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.RecipeStatusDraft, draft.Status)
+	assert.Equal(t, 3, draft.CategoryID, "the page's category is matched by name")
+	assert.Equal(t, "Imported from "+importPageURL, *draft.Description)
+	assert.Equal(t, 35, *draft.TotalTimeMinutes)
+	require.Len(t, draft.Ingredients, 3)
+	assert.Equal(t, models.AddRecipeIngredientRequest{IngredientID: 11, Quantity: 2, Unit: "tbsp"}, draft.Ingredients[0])
+	assert.Equal(t, models.AddRecipeIngredientRequest{IngredientID: 12, Quantity: 4, Unit: "piece"}, draft.Ingredients[1])
+	assert.Equal(t, 13, draft.Ingredients[2].IngredientID)
+	assert.Equal(t, "Salt to taste", *draft.Ingredients[2].Notes, "lines without an amount keep the line as written")
+
+	assert.Equal(t, importPageURL, result.SourceURL)
+	assert.Equal(t, steps, result.Draft.Steps)
+	assert.Equal(t, []string{"1 tin chopped tomatoes", "2 eggs"}, result.UnmatchedIngredients)
+	setup.recipeRepo.AssertExpectations(t)
+	setup.stepRepo.AssertExpectations(t)
+}
+
+func TestRecipeImportService_ImportFromURL_Errors(t *testing.T) {
+	t.Run("not a web address", func(t *testing.T) {
+		setup := setupRecipeImportServiceTest()
+		for _, pageURL := range []string{"", "example.com/recipe", "ftp://example.com/recipe", "file:///etc/passwd"} {
+			_, err := setup.service.ImportFromURL(context.Background(), 7, models.ImportRecipeRequest{URL: pageURL})
+			assert.Equal(t, domain.ErrInvalidImportURL, err, pageURL)
+		}
+	})
+
+	t.Run("page unavailable", func(t *testing.T) {
+		setup := setupRecipeImportServiceTest()
+		_, err := setup.service.ImportFromURL(context.Background(), 7, models.ImportRecipeRequest{URL: "https://example.com/gone"})
+		assert.Equal(t, domain.ErrImportPageUnavailable, err)
+	})
+
+	t.Run("no recipe on the page", func(t *testing.T) {
+		setup := setupRecipeImportServiceTest()
+		setup.service = NewRecipeImportService(setup.recipeRepo, setup.stepRepo, setup.categoryRepo, setup.ingredientRepo,
+			fakePages{importPageURL: "<p>Just a blog post</p>"})
+		_, err := setup.service.ImportFromURL(context.Background(), 7, models.ImportRecipeRequest{URL: importPageURL})
+		assert.Equal(t, domain.ErrNoRecipeOnPage, err)
+	})
+
+	t.Run("page category unknown", func(t *testing.T) {
+		setup := setupRecipeImportServiceTest()
+		setup.categoryRepo.On("GetAll").Return([]models.Category{{ID: 1, Name: "Dinner"}}, nil)
+		_, err := setup.service.ImportFromURL(context.Background(), 7, models.ImportRecipeRequest{URL: importPageURL})
+		assert.Equal(t, domain.ErrImportCategoryRequired, err)
+		setup.recipeRepo.AssertNotCalled(t, "CreateWithIngredients", mock.Anything, mock.Anything)
+	})
+
+	t.Run("chosen category missing", func(t *testing.T) {
+		setup := setupRecipeImportServiceTest()
+		categoryID := 9
+		setup.categoryRepo.On("Exists", 9).Return(false, nil)
+		_, err := setup.service.ImportFromURL(context.Background(), 7, models.ImportRecipeRequest{URL: importPageURL, CategoryID: &categoryID})
+		assert.Equal(t, domain.ErrCategoryNotFound, err)
+	})
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecipePage_JSONLD(t *testing.T) {
+	page := `<html><head>
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "WebSite", "name": "Cooking blog"}</script>
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@graph": [
+  {"@type": "WebPage", "name": "Shakshuka | Cooking blog"},
+  {"@type": ["Recipe"], "name": "Shakshuka",
+   "description": "Eggs poached in a spiced tomato &amp; pepper sauce.",
+   "recipeCategory": "Breakfast, Brunch",
+   "recipeIngredient": ["2 tbsp olive oil", "4  large eggs"],
+   "recipeInstructions": [
+     {"@type": "HowToSection", "name": "Sauce", "itemListElement": [
+       {"@type": "HowToStep", "text": "Soften the onion."},
+       {"@type": "HowToStep", "text": "Add the tomatoes."}]},
+     "Crack in the eggs."],
+   "prepTime": "PT10M", "cookTime": "PT25M"}
+]}
+</script></head><body></body></html>`
+
+	recipe, ok := parseRecipePage([]byte(page))
+
+	require.True(t, ok)
+	assert.Equal(t, "Shakshuka", recipe.Name)
+	assert.Equal(t, "Eggs poached in a spiced tomato & pepper sauce.", recipe.Description)
+	assert.Equal(t, []string{"Breakfast", "Brunch"}, recipe.Categories)
+	assert.Equal(t, []string{"2 tbsp olive oil", "4 large eggs"}, recipe.Ingredients)
+	assert.Equal(t, []string{"Soften the onion.", "Add the tomatoes.", "Crack in the eggs."}, recipe.Instructions)
+	require.NotNil(t, recipe.TotalTimeMinutes)
+	assert.Equal(t, 35, *recipe.TotalTimeMinutes)
+}
+
+func TestParseRecipePage_Microdata(t *testing.T) {
+	page := `<html><body>
+<div itemscope itemtype="http://schema.org/Recipe">
+  <h1 itemprop="name">Lemon Drizzle Cake</h1>
+  <div itemprop="author" itemscope itemtype="http://schema.org/Person"><span itemprop="name">Sam</span></div>
+  <meta itemprop="totalTime" content="PT1H15M">
+  <ul>
+    <li itemprop="recipeIngredient">225 g butter</li>
+    <li itemprop="recipeIngredient">2 lemons, zested</li>
+  </ul>
+  <ol itemprop="recipeInstructions"><li>Cream the butter.</li><li>Bake for 45 minutes.</li></ol>
+</div>
+</body></html>`
+
+	recipe, ok := parseRecipePage([]byte(page))
+
+	require.True(t, ok)
+	assert.Equal(t, "Lemon Drizzle Cake", recipe.Name, "the author's name belongs to a nested item")
+	assert.Equal(t, []string{"225 g butter", "2 lemons, zested"}, recipe.Ingredients)
+	assert.Equal(t, []string{"Cream the butter.", "Bake for 45 minutes."}, recipe.Instructions)
+	require.NotNil(t, recipe.TotalTimeMinutes)
+	assert.Equal(t, 75, *recipe.TotalTimeMinutes)
+}
+
+func TestParseRecipePage_NoRecipe(t *testing.T) {
+	_, ok := parseRecipePage([]byte(`<html><script type="application/ld+json">{"@type": "Article"}</script><p>Hello</p></html>`))
+	assert.False(t, ok)
+}
+
+func TestParseIngredientLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want ingredientLine
+	}{
+		{"2 cups plain flour, sifted", ingredientLine{2, "cup", "plain flour", "sifted"}},
+		{"1 1/2 tsp. salt", ingredientLine{1.5, "tsp", "salt", ""}},
+		{"1½ tablespoons of olive oil", ingredientLine{1.5, "tbsp", "olive oil", ""}},
+		{"200g butter (softened)", ingredientLine{200, "g", "butter", "softened"}},
+		{"2-3 cloves garlic", ingredientLine{2, "", "cloves garlic", ""}},
+		{"3 large eggs", ingredientLine{3, "", "large eggs", ""}},
+		{"8 fl oz milk", ingredientLine{8, "fl oz", "milk", ""}},
+		{"Salt to taste", ingredientLine{0, "", "Salt", "to taste"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := parseIngredientLine(tt.line)
+			assert.InDelta(t, tt.want.Quantity, got.Quantity, 0.001)
+			got.Quantity = tt.want.Quantity
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDurationMinutes(t *testing.T) {
+	for duration, want := range map[string]int{"PT45M": 45, "PT1H30M": 90, "P0DT2H": 120, "PT90S": 2, "pt20m": 20} {
+		minutes, ok := durationMinutes(duration)
+		assert.True(t, ok, duration)
+		assert.Equal(t, want, minutes, duration)
+	}
+	for _, duration := range []string{"", "P", "PT0M", "45 minutes"} {
+		_, ok := durationMinutes(duration)
+		assert.False(t, ok, duration)
+	}
+}
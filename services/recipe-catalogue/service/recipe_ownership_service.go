@@ -0,0 +1,76 @@
+package service
+
+import (
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+// ownershipExportPageSize is how many owners ExportRecipeOwners reads per
+// query.
+const ownershipExportPageSize = 500
+
+type RecipeOwnershipService interface {
+	// ListRecipeOwners returns a page of recipe owners, least recently active
+	// first. A positive inactiveDays keeps only owners who haven't created or
+	// edited a recipe for that many days; 0 lists everyone.
+	ListRecipeOwners(inactiveDays int, params models.PaginationParams) ([]models.RecipeOwnership, models.PaginationMeta, error)
+
+	// ExportRecipeOwners returns every owner ListRecipeOwners would, unpaged
+	ExportRecipeOwners(inactiveDays int) ([]models.RecipeOwnership, error)
+}
+
+type recipeOwnershipService struct {
+	ownershipRepo repository.RecipeOwnershipRepository
+}
+
+func NewRecipeOwnershipService(ownershipRepo repository.RecipeOwnershipRepository) RecipeOwnershipService {
+	return &recipeOwnershipService{ownershipRepo: ownershipRepo}
+}
+
+func (s *recipeOwnershipService) ListRecipeOwners(inactiveDays int, params models.PaginationParams) ([]models.RecipeOwnership, models.PaginationMeta, error) {
+	inactiveSince, err := inactivityCutoff(inactiveDays, time.Now())
+	if err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+
+	owners, total, err := s.ownershipRepo.ListOwners(inactiveSince, params)
+	if err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	return owners, models.NewPaginationMeta(params, total), nil
+}
+
+func (s *recipeOwnershipService) ExportRecipeOwners(inactiveDays int) ([]models.RecipeOwnership, error) {
+	inactiveSince, err := inactivityCutoff(inactiveDays, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	all := make([]models.RecipeOwnership, 0)
+	for page := 1; ; page++ {
+		owners, total, err := s.ownershipRepo.ListOwners(inactiveSince,
+			models.PaginationParams{Page: page, PerPage: ownershipExportPageSize})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, owners...)
+		if len(owners) < ownershipExportPageSize || len(all) >= total {
+			return all, nil
+		}
+	}
+}
+
+// inactivityCutoff turns inactive days into the cutoff the repository takes; the
+// zero time means no cutoff.
+func inactivityCutoff(days int, now time.Time) (time.Time, error) {
+	if days < 0 {
+		return time.Time{}, domain.ErrInvalidInactiveDays
+	}
+	if days == 0 {
+		return time.Time{}, nil
+	}
+	return now.AddDate(0, 0, -days), nil
+}
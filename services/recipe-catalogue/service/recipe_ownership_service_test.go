@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// daysBefore matches a cutoff about days before now.
+func daysBefore(days int) interface{} {
+	return mock.MatchedBy(func(cutoff time.Time) bool {
+		expected := time.Now().AddDate(0, 0, -days)
+		return cutoff.Sub(expected).Abs() < time.Minute
+	})
+}
+
+func TestRecipeOwnershipService_ListRecipeOwners(t *testing.T) {
+	ownershipRepo := new(mocks.MockRecipeOwnershipRepository)
+	service := NewRecipeOwnershipService(ownershipRepo)
+	params := models.PaginationParams{Page: 1, PerPage: 20}
+
+	ownershipRepo.On("ListOwners", time.Time{}, params).
+		Return([]models.RecipeOwnership{{UserID: 7, RecipeCount: 3}}, 1, nil).Once()
+	ownershipRepo.On("ListOwners", daysBefore(180), params).
+		Return([]models.RecipeOwnership{}, 0, nil).Once()
+
+	owners, meta, err := service.ListRecipeOwners(0, params)
+	require.NoError(t, err)
+	assert.Len(t, owners, 1)
+	assert.Equal(t, 1, meta.Total)
+
+	_, meta, err = service.ListRecipeOwners(180, params)
+	require.NoError(t, err)
+	assert.Equal(t, 0, meta.Total)
+
+	_, _, err = service.ListRecipeOwners(-1, params)
+	assert.Equal(t, domain.ErrInvalidInactiveDays, err)
+	ownershipRepo.AssertExpectations(t)
+}
+
+func TestRecipeOwnershipService_ExportRecipeOwnersReadsEveryPage(t *testing.T) {
+	ownershipRepo := new(mocks.MockRecipeOwnershipRepository)
+	service := NewRecipeOwnershipService(ownershipRepo)
+
+	firstPage := make([]models.RecipeOwnership, ownershipExportPageSize)
+	ownershipRepo.On("ListOwners", daysBefore(90), models.PaginationParams{Page: 1, PerPage: ownershipExportPageSize}).
+		Return(firstPage, ownershipExportPageSize+1, nil).Once()
+	ownershipRepo.On("ListOwners", daysBefore(90), models.PaginationParams{Page: 2, PerPage: ownershipExportPageSize}).
+		Return([]models.RecipeOwnership{{UserID: 9}}, ownershipExportPageSize+1, nil).Once()
+
+	owners, err := service.ExportRecipeOwners(90)
+	require.NoError(t, err)
+	assert.Len(t, owners, ownershipExportPageSize+1)
+	assert.Equal(t, 9, owners[ownershipExportPageSize].UserID)
+	ownershipRepo.AssertExpectations(t)
+}
+
+func TestRecipeOwnershipService_ExportRecipeOwnersRepositoryError(t *testing.T) {
+	ownershipRepo := new(mocks.MockRecipeOwnershipRepository)
+	service := NewRecipeOwnershipService(ownershipRepo)
+
+	ownershipRepo.On("ListOwners", time.Time{}, mock.Anything).Return(nil, 0, errors.New("db down"))
+
+	_, err := service.ExportRecipeOwners(0)
+	assert.Error(t, err)
+}
@@ -1,44 +1,58 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"meal-prep/services/recipe-catalogue/domain"
 	"strings"
+	"unicode/utf8"
 
 	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/events"
 	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
 )
 
 type RecipeService interface {
 	GetAllRecipes(params models.PaginationParams) ([]models.Recipe, models.PaginationMeta, error)
 	GetRecipeByID(id int) (*models.Recipe, error)
 	GetRecipesByCategory(categoryID int, params models.PaginationParams) ([]models.Recipe, models.PaginationMeta, error)
+	GetMyRecipes(userID int, status string, params models.PaginationParams) ([]models.Recipe, models.PaginationMeta, error)
 	CreateRecipe(userID int, req models.CreateRecipeRequest) (*models.Recipe, error)
-	UpdateRecipe(userID int, id int, req models.UpdateRecipeRequest) (*models.Recipe, error)
-	DeleteRecipe(userID int, id int) error
+	UpdateRecipe(ctx context.Context, id int, req models.UpdateRecipeRequest) (*models.Recipe, error)
+	DeleteRecipe(ctx context.Context, id int) error
 	GetAllCategories() ([]models.Category, error)
 
 	GetAllRecipesWithIngredients(params models.PaginationParams) ([]models.RecipeWithIngredients, models.PaginationMeta, error)
 	GetRecipeByIDWithIngredients(id int) (*models.RecipeWithIngredients, error)
 	GetRecipesByCategoryWithIngredients(categoryID int, params models.PaginationParams) ([]models.RecipeWithIngredients, models.PaginationMeta, error)
 	CreateRecipeWithIngredients(userID int, req models.CreateRecipeWithIngredientsRequest) (*models.RecipeWithIngredients, error)
-	UpdateRecipeWithIngredients(userID int, id int, req models.UpdateRecipeWithIngredientsRequest) (*models.RecipeWithIngredients, error)
+	UpdateRecipeWithIngredients(ctx context.Context, id int, req models.UpdateRecipeWithIngredientsRequest) (*models.RecipeWithIngredients, error)
 
 	SearchRecipesByIngredients(ingredientIDs []int, params models.PaginationParams) ([]models.Recipe, models.PaginationMeta, error)
 	SearchRecipesByIngredientsWithIngredients(ingredientIDs []int, params models.PaginationParams) ([]models.RecipeWithIngredients, models.PaginationMeta, error)
+	GetSearchFacets(ingredientIDs []int) (*models.RecipeFacets, error)
+
+	CompareRecipes(ctx context.Context, userID, firstID, secondID int) (*models.RecipeComparison, error)
 }
 
 type recipeService struct {
 	recipeRepo     repository.RecipeRepository
 	categoryRepo   repository.CategoryRepository
 	ingredientRepo repository.IngredientRepository
+	imageRepo      repository.RecipeImageRepository
+	favoriteRepo   repository.FavoriteRepository
+	events         events.Publisher
 }
 
-func NewRecipeService(recipeRepo repository.RecipeRepository, categoryRepo repository.CategoryRepository, ingredientRepo repository.IngredientRepository) RecipeService {
+func NewRecipeService(recipeRepo repository.RecipeRepository, categoryRepo repository.CategoryRepository, ingredientRepo repository.IngredientRepository, imageRepo repository.RecipeImageRepository, favoriteRepo repository.FavoriteRepository, publisher events.Publisher) RecipeService {
 	return &recipeService{
 		recipeRepo:     recipeRepo,
 		categoryRepo:   categoryRepo,
 		ingredientRepo: ingredientRepo,
+		imageRepo:      imageRepo,
+		favoriteRepo:   favoriteRepo,
+		events:         publisher,
 	}
 }
 
@@ -47,6 +61,9 @@ func (s *recipeService) GetAllRecipes(params models.PaginationParams) ([]models.
 	if err != nil {
 		return nil, models.PaginationMeta{}, err
 	}
+	if err := s.attachExtras(recipePointers(recipes)...); err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
 	return recipes, models.NewPaginationMeta(params, total), nil
 }
 
@@ -63,6 +80,16 @@ func (s *recipeService) GetRecipeByID(id int) (*models.Recipe, error) {
 		return nil, err
 	}
 
+	if !isPubliclyVisible(recipe) {
+		return nil, domain.ErrRecipeNotFound
+	}
+
+	if err := s.attachExtras(recipe); err != nil {
+		return nil, err
+	}
+	if err := s.attachAllergens(recipe); err != nil {
+		return nil, err
+	}
 	return recipe, nil
 }
 
@@ -83,6 +110,26 @@ func (s *recipeService) GetRecipesByCategory(categoryID int, params models.Pagin
 	if err != nil {
 		return nil, models.PaginationMeta{}, err
 	}
+	if err := s.attachExtras(recipePointers(recipes)...); err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	return recipes, models.NewPaginationMeta(params, total), nil
+}
+
+// GetMyRecipes lists the user's own recipes, including drafts and private
+// ones. An empty status lists all of them.
+func (s *recipeService) GetMyRecipes(userID int, status string, params models.PaginationParams) ([]models.Recipe, models.PaginationMeta, error) {
+	if status != "" && !models.IsValidRecipeStatus(status) {
+		return nil, models.PaginationMeta{}, domain.ErrInvalidRecipeStatus
+	}
+
+	recipes, total, err := s.recipeRepo.GetByOwner(userID, status, params)
+	if err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	if err := s.attachExtras(recipePointers(recipes)...); err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
 	return recipes, models.NewPaginationMeta(params, total), nil
 }
 
@@ -98,6 +145,14 @@ func (s *recipeService) CreateRecipe(userID int, req models.CreateRecipeRequest)
 		return nil, domain.ErrInvalidCategory
 	}
 
+	if req.Status != "" && !models.IsValidRecipeStatus(req.Status) {
+		return nil, domain.ErrInvalidRecipeStatus
+	}
+
+	if err := validateDifficultyAndTime(req.Difficulty, req.TotalTimeMinutes); err != nil {
+		return nil, err
+	}
+
 	exists, err := s.categoryRepo.Exists(req.CategoryID)
 	if err != nil {
 		return nil, err
@@ -106,10 +161,17 @@ func (s *recipeService) CreateRecipe(userID int, req models.CreateRecipeRequest)
 		return nil, domain.ErrCategoryNotFound
 	}
 
-	return s.recipeRepo.Create(userID, req)
+	recipe, err := s.recipeRepo.Create(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishIfPublic(recipe)
+	warnPossibleDuplicates(s.recipeRepo, s.ingredientRepo, recipe, nil)
+	return recipe, nil
 }
 
-func (s *recipeService) UpdateRecipe(userID int, id int, req models.UpdateRecipeRequest) (*models.Recipe, error) {
+func (s *recipeService) UpdateRecipe(ctx context.Context, id int, req models.UpdateRecipeRequest) (*models.Recipe, error) {
 	if req.Name == "" {
 		return nil, domain.ErrRecipeNameRequired
 	}
@@ -118,22 +180,16 @@ func (s *recipeService) UpdateRecipe(userID int, id int, req models.UpdateRecipe
 		return nil, domain.ErrInvalidCategory
 	}
 
-	if id <= 0 {
-		return nil, domain.ErrRecipeNotFound
+	if req.Status != "" && !models.IsValidRecipeStatus(req.Status) {
+		return nil, domain.ErrInvalidRecipeStatus
 	}
 
-	// GetOwnerID confirms the recipe exists and returns who owns it.
-	// This replaces the old GetByID call, saving one round-trip to the DB.
-	ownerID, err := s.recipeRepo.GetOwnerID(id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, domain.ErrRecipeNotFound
-		}
+	if err := validateDifficultyAndTime(req.Difficulty, req.TotalTimeMinutes); err != nil {
 		return nil, err
 	}
 
-	if ownerID != userID {
-		return nil, domain.ErrForbidden
+	if err := authorizeRecipe(ctx, s.recipeRepo, policy.Update, id); err != nil {
+		return nil, err
 	}
 
 	req.Name = strings.TrimSpace(req.Name)
@@ -149,24 +205,21 @@ func (s *recipeService) UpdateRecipe(userID int, id int, req models.UpdateRecipe
 		}
 	}
 
-	return s.recipeRepo.Update(id, req)
-}
-
-func (s *recipeService) DeleteRecipe(userID int, id int) error {
-	if id <= 0 {
-		return domain.ErrRecipeNotFound
+	recipe, err := s.recipeRepo.Update(id, req)
+	if err == sql.ErrNoRows {
+		return nil, s.versionConflict(id)
 	}
-
-	ownerID, err := s.recipeRepo.GetOwnerID(id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return domain.ErrRecipeNotFound
-		}
-		return err
+		return nil, err
 	}
 
-	if ownerID != userID {
-		return domain.ErrForbidden
+	s.publishIfPublic(recipe)
+	return recipe, nil
+}
+
+func (s *recipeService) DeleteRecipe(ctx context.Context, id int) error {
+	if err := authorizeRecipe(ctx, s.recipeRepo, policy.Delete, id); err != nil {
+		return err
 	}
 
 	return s.recipeRepo.Delete(id)
@@ -181,6 +234,10 @@ func (s *recipeService) GetAllRecipesWithIngredients(params models.PaginationPar
 	if err != nil {
 		return nil, models.PaginationMeta{}, err
 	}
+	if err := s.attachExtras(recipePointersWithIngredients(recipes)...); err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	applyEstimatedDifficulties(recipes)
 	return recipes, models.NewPaginationMeta(params, total), nil
 }
 
@@ -197,6 +254,17 @@ func (s *recipeService) GetRecipeByIDWithIngredients(id int) (*models.RecipeWith
 		return nil, err
 	}
 
+	if !isPubliclyVisible(&recipe.Recipe) {
+		return nil, domain.ErrRecipeNotFound
+	}
+
+	if err := s.attachExtras(&recipe.Recipe); err != nil {
+		return nil, err
+	}
+	if err := s.attachAllergens(&recipe.Recipe); err != nil {
+		return nil, err
+	}
+	applyEstimatedDifficulty(recipe)
 	return recipe, nil
 }
 
@@ -217,6 +285,10 @@ func (s *recipeService) GetRecipesByCategoryWithIngredients(categoryID int, para
 	if err != nil {
 		return nil, models.PaginationMeta{}, err
 	}
+	if err := s.attachExtras(recipePointersWithIngredients(recipes)...); err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	applyEstimatedDifficulties(recipes)
 	return recipes, models.NewPaginationMeta(params, total), nil
 }
 
@@ -235,6 +307,14 @@ func (s *recipeService) CreateRecipeWithIngredients(userID int, req models.Creat
 		return nil, domain.ErrInvalidCategory
 	}
 
+	if req.Status != "" && !models.IsValidRecipeStatus(req.Status) {
+		return nil, domain.ErrInvalidRecipeStatus
+	}
+
+	if err := validateDifficultyAndTime(req.Difficulty, req.TotalTimeMinutes); err != nil {
+		return nil, err
+	}
+
 	exists, err := s.categoryRepo.Exists(req.CategoryID)
 	if err != nil {
 		return nil, err
@@ -244,9 +324,10 @@ func (s *recipeService) CreateRecipeWithIngredients(userID int, req models.Creat
 	}
 
 	if len(req.Ingredients) > 0 {
-		for _, ingredient := range req.Ingredients {
-			if ingredient.IngredientID <= 0 {
-				return nil, domain.ErrIngredientNotFound
+		for i := range req.Ingredients {
+			ingredient := &req.Ingredients[i]
+			if err := validateNamedIngredient(ingredient); err != nil {
+				return nil, err
 			}
 
 			if ingredient.Quantity <= 0 {
@@ -257,35 +338,70 @@ func (s *recipeService) CreateRecipeWithIngredients(userID int, req models.Creat
 			if ingredient.Unit == "" {
 				return nil, domain.ErrInvalidUnit
 			}
+		}
 
-			exists, err := s.ingredientRepo.IngredientExists(ingredient.IngredientID)
-			if err != nil {
-				return nil, err
-			}
-			if !exists {
-				return nil, domain.ErrIngredientNotFound
-			}
+		// Lines given by name are matched or created by the repository, in
+		// the transaction that creates the recipe
+		if err := ensureIngredientsExist(s.ingredientRepo, knownIngredientIDs(req.Ingredients)); err != nil {
+			return nil, err
+		}
+		if err := ensureProductsMatch(s.ingredientRepo, req.Ingredients); err != nil {
+			return nil, err
 		}
 	}
 
-	return s.recipeRepo.CreateWithIngredients(userID, req)
+	recipe, err := s.recipeRepo.CreateWithIngredients(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishIfPublic(&recipe.Recipe)
+	applyEstimatedDifficulty(recipe)
+	warnPossibleDuplicates(s.recipeRepo, s.ingredientRepo, &recipe.Recipe, recipe.Ingredients)
+	return recipe, nil
 }
 
-func (s *recipeService) UpdateRecipeWithIngredients(userID int, id int, req models.UpdateRecipeWithIngredientsRequest) (*models.RecipeWithIngredients, error) {
-	if id <= 0 {
-		return nil, domain.ErrRecipeNotFound
+// maxIngredientNameLength matches the ingredients.name column.
+const maxIngredientNameLength = 100
+
+// validateNamedIngredient checks that a recipe line names its ingredient,
+// by ID or by a name that fits the catalogue, trimming the name.
+func validateNamedIngredient(ingredient *models.AddRecipeIngredientRequest) error {
+	ingredient.IngredientName = strings.TrimSpace(ingredient.IngredientName)
+	ingredient.IngredientCategory = strings.TrimSpace(ingredient.IngredientCategory)
+	if ingredient.IngredientID != 0 {
+		if ingredient.IngredientID < 0 {
+			return domain.ErrIngredientNotFound
+		}
+		return nil
 	}
 
-	ownerID, err := s.recipeRepo.GetOwnerID(id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, domain.ErrRecipeNotFound
+	if ingredient.IngredientName == "" {
+		return domain.ErrIngredientNotFound
+	}
+	if utf8.RuneCountInString(ingredient.IngredientName) > maxIngredientNameLength {
+		return domain.ErrInvalidIngredientName
+	}
+	if ingredient.IngredientCategory != "" && !models.IsValidIngredientCategory(ingredient.IngredientCategory) {
+		return domain.ErrInvalidIngredientCategory
+	}
+	return nil
+}
+
+// knownIngredientIDs returns the IDs of the lines given by ID.
+func knownIngredientIDs(ingredients []models.AddRecipeIngredientRequest) []int {
+	var ids []int
+	for _, ingredient := range ingredients {
+		if ingredient.IngredientID != 0 {
+			ids = append(ids, ingredient.IngredientID)
 		}
-		return nil, err
 	}
+	return ids
+}
 
-	if ownerID != userID {
-		return nil, domain.ErrForbidden
+func (s *recipeService) UpdateRecipeWithIngredients(ctx context.Context, id int, req models.UpdateRecipeWithIngredientsRequest) (*models.RecipeWithIngredients, error) {
+	if err := authorizeRecipe(ctx, s.recipeRepo, policy.Update, id); err != nil {
+		return nil, err
 	}
 
 	name := strings.TrimSpace(req.Name)
@@ -296,6 +412,14 @@ func (s *recipeService) UpdateRecipeWithIngredients(userID int, id int, req mode
 
 	req.Description = strings.TrimSpace(req.Description)
 
+	if req.Status != "" && !models.IsValidRecipeStatus(req.Status) {
+		return nil, domain.ErrInvalidRecipeStatus
+	}
+
+	if err := validateDifficultyAndTime(req.Difficulty, req.TotalTimeMinutes); err != nil {
+		return nil, err
+	}
+
 	exists, err := s.categoryRepo.Exists(req.CategoryID)
 	if err != nil {
 		return nil, err
@@ -318,18 +442,40 @@ func (s *recipeService) UpdateRecipeWithIngredients(userID int, id int, req mode
 			if ingredient.Unit == "" {
 				return nil, domain.ErrInvalidUnit
 			}
+		}
 
-			exists, err := s.ingredientRepo.IngredientExists(ingredient.IngredientID)
-			if err != nil {
-				return nil, err
-			}
-			if !exists {
-				return nil, domain.ErrIngredientNotFound
-			}
+		if err := ensureIngredientsExist(s.ingredientRepo, recipeIngredientIDs(req.Ingredients)); err != nil {
+			return nil, err
+		}
+		if err := ensureProductsMatch(s.ingredientRepo, req.Ingredients); err != nil {
+			return nil, err
 		}
 	}
 
-	return s.recipeRepo.UpdateWithIngredients(id, req)
+	recipe, err := s.recipeRepo.UpdateWithIngredients(id, req)
+	if err == sql.ErrNoRows {
+		return nil, s.versionConflict(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishIfPublic(&recipe.Recipe)
+	applyEstimatedDifficulty(recipe)
+	return recipe, nil
+}
+
+// versionConflict explains an update that matched no row: someone edited
+// the recipe since the client fetched it, or it was deleted meanwhile.
+func (s *recipeService) versionConflict(id int) error {
+	current, err := s.recipeRepo.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrRecipeNotFound
+		}
+		return err
+	}
+	return &models.VersionConflictError{CurrentVersion: current.Version}
 }
 
 func (s *recipeService) SearchRecipesByIngredients(ingredientIDs []int, params models.PaginationParams) ([]models.Recipe, models.PaginationMeta, error) {
@@ -341,20 +487,19 @@ func (s *recipeService) SearchRecipesByIngredients(ingredientIDs []int, params m
 		if ingredientID <= 0 {
 			return nil, models.PaginationMeta{}, domain.ErrIngredientNotFound
 		}
+	}
 
-		exists, err := s.ingredientRepo.IngredientExists(ingredientID)
-		if err != nil {
-			return nil, models.PaginationMeta{}, err
-		}
-		if !exists {
-			return nil, models.PaginationMeta{}, domain.ErrIngredientNotFound
-		}
+	if err := ensureIngredientsExist(s.ingredientRepo, ingredientIDs); err != nil {
+		return nil, models.PaginationMeta{}, err
 	}
 
 	recipes, total, err := s.recipeRepo.SearchRecipesByIngredients(ingredientIDs, params)
 	if err != nil {
 		return nil, models.PaginationMeta{}, err
 	}
+	if err := s.attachExtras(recipePointers(recipes)...); err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
 	return recipes, models.NewPaginationMeta(params, total), nil
 }
 
@@ -367,19 +512,119 @@ func (s *recipeService) SearchRecipesByIngredientsWithIngredients(ingredientIDs
 		if ingredientID <= 0 {
 			return nil, models.PaginationMeta{}, domain.ErrIngredientNotFound
 		}
+	}
 
-		exists, err := s.ingredientRepo.IngredientExists(ingredientID)
-		if err != nil {
-			return nil, models.PaginationMeta{}, err
-		}
-		if !exists {
-			return nil, models.PaginationMeta{}, domain.ErrIngredientNotFound
-		}
+	if err := ensureIngredientsExist(s.ingredientRepo, ingredientIDs); err != nil {
+		return nil, models.PaginationMeta{}, err
 	}
 
 	recipes, total, err := s.recipeRepo.SearchRecipesByIngredientsWithIngredients(ingredientIDs, params)
 	if err != nil {
 		return nil, models.PaginationMeta{}, err
 	}
+	if err := s.attachExtras(recipePointersWithIngredients(recipes)...); err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	applyEstimatedDifficulties(recipes)
 	return recipes, models.NewPaginationMeta(params, total), nil
 }
+
+// attachExtras fills in what recipe responses show beyond the recipes
+// table.
+func (s *recipeService) attachExtras(recipes ...*models.Recipe) error {
+	return attachRecipeExtras(s.imageRepo, s.favoriteRepo, recipes...)
+}
+
+// attachRecipeExtras fills in each recipe's image URLs from its cover
+// image, if it has any images, and how many users favorited it.
+func attachRecipeExtras(imageRepo repository.RecipeImageRepository, favoriteRepo repository.FavoriteRepository, recipes ...*models.Recipe) error {
+	if len(recipes) == 0 {
+		return nil
+	}
+
+	recipeIDs := make([]int, len(recipes))
+	for i, recipe := range recipes {
+		recipeIDs[i] = recipe.ID
+	}
+	images, err := imageRepo.GetByRecipeIDs(recipeIDs)
+	if err != nil {
+		return err
+	}
+	favorites, err := favoriteRepo.CountByRecipeIDs(recipeIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, recipe := range recipes {
+		if cover := coverImage(images[recipe.ID]); cover != nil {
+			recipe.ImageURL = &cover.URL
+			recipe.ThumbnailURL = cover.ThumbnailURL
+		}
+		count := favorites[recipe.ID]
+		recipe.FavoriteCount = &count
+	}
+	return nil
+}
+
+// attachAllergens rolls up the allergens of a single recipe's ingredients,
+// so clients can warn before anyone cooks it.
+func (s *recipeService) attachAllergens(recipe *models.Recipe) error {
+	allergens, err := s.ingredientRepo.GetRecipeAllergens(recipe.ID)
+	if err != nil {
+		return err
+	}
+	recipe.Allergens = allergens
+	return nil
+}
+
+func recipePointers(recipes []models.Recipe) []*models.Recipe {
+	pointers := make([]*models.Recipe, len(recipes))
+	for i := range recipes {
+		pointers[i] = &recipes[i]
+	}
+	return pointers
+}
+
+func recipePointersWithIngredients(recipes []models.RecipeWithIngredients) []*models.Recipe {
+	pointers := make([]*models.Recipe, len(recipes))
+	for i := range recipes {
+		pointers[i] = &recipes[i].Recipe
+	}
+	return pointers
+}
+
+// publishIfPublic announces a recipe saved as published. Saving an already
+// published recipe announces it again; subscribers treat that as a no-op.
+func (s *recipeService) publishIfPublic(recipe *models.Recipe) {
+	if recipe.Status != models.RecipeStatusPublished {
+		return
+	}
+	s.events.Publish(events.Event{
+		Type:      events.RecipePublished,
+		UserID:    recipe.UserID,
+		SubjectID: recipe.ID,
+	})
+}
+
+// isPubliclyVisible reports whether an anonymous caller may read the recipe.
+// Public routes carry no user identity, so drafts and private recipes are only
+// reachable through the owner's /me/recipes listing. Archived recipes stay
+// readable, though no listing or search returns them.
+func isPubliclyVisible(recipe *models.Recipe) bool {
+	return recipe.Status != models.RecipeStatusDraft && recipe.Status != models.RecipeStatusPrivate
+}
+
+// searchTimeBuckets are the max-time facets offered with search results, in
+// minutes.
+var searchTimeBuckets = []int{15, 30, 60, 120}
+
+// GetSearchFacets counts the recipes an ingredient search finds per category
+// and per max-time bucket, across all pages of the results.
+func (s *recipeService) GetSearchFacets(ingredientIDs []int) (*models.RecipeFacets, error) {
+	for _, ingredientID := range ingredientIDs {
+		if ingredientID <= 0 {
+			return nil, domain.ErrIngredientNotFound
+		}
+	}
+	return s.recipeRepo.GetSearchFacets(ingredientIDs, searchTimeBuckets)
+}
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"meal-prep/services/recipe-catalogue/domain"
+	"strings"
 	"testing"
 
 	"meal-prep/services/recipe-catalogue/service/mocks"
-	"meal-prep/services/recipe-catalogue/service/testdata"
+	"meal-prep/shared/events"
 	"meal-prep/shared/models"
+	"meal-prep/shared/testing/factory"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -20,20 +22,36 @@ type recipeServiceTestSetup struct {
 	recipeRepo     *mocks.MockRecipeRepository
 	categoryRepo   *mocks.MockCategoryRepository
 	ingredientRepo *mocks.MockIngredientRepository
+	imageRepo      *mocks.MockRecipeImageRepository
+	favoriteRepo   *mocks.MockFavoriteRepository
+	bus            *events.Bus
 }
 
 func setupRecipeServiceTest() *recipeServiceTestSetup {
 	recipeRepo := new(mocks.MockRecipeRepository)
 	categoryRepo := new(mocks.MockCategoryRepository)
 	ingredientRepo := new(mocks.MockIngredientRepository)
+	imageRepo := new(mocks.MockRecipeImageRepository)
+	favoriteRepo := new(mocks.MockFavoriteRepository)
+	bus := events.NewBus()
 
-	service := NewRecipeService(recipeRepo, categoryRepo, ingredientRepo)
+	// Recipes have no images, favorites or allergens unless a test says otherwise
+	imageRepo.On("GetByRecipeIDs", mock.Anything).Return(map[int][]models.RecipeImage{}, nil).Maybe()
+	favoriteRepo.On("CountByRecipeIDs", mock.Anything).Return(map[int]int{}, nil).Maybe()
+	ingredientRepo.On("GetRecipeAllergens", mock.Anything).Return([]models.RecipeAllergen{}, nil).Maybe()
+	// and their authors have no other recipes to be duplicates of
+	recipeRepo.On("GetByOwner", mock.Anything, "", duplicateCandidateParams).Return([]models.Recipe{}, 0, nil).Maybe()
+
+	service := NewRecipeService(recipeRepo, categoryRepo, ingredientRepo, imageRepo, favoriteRepo, bus)
 
 	return &recipeServiceTestSetup{
 		service:        service,
 		recipeRepo:     recipeRepo,
 		categoryRepo:   categoryRepo,
 		ingredientRepo: ingredientRepo,
+		imageRepo:      imageRepo,
+		favoriteRepo:   favoriteRepo,
+		bus:            bus,
 	}
 }
 
@@ -46,8 +64,8 @@ var defaultParams = models.PaginationParams{Page: 1, PerPage: 20}
 func TestRecipeService_GetAllRecipes_Success(t *testing.T) {
 	setup := setupRecipeServiceTest()
 	expectedRecipes := []models.Recipe{
-		testdata.NewRecipeBuilder().WithID(1).WithName("Recipe 1").Build(),
-		testdata.NewRecipeBuilder().WithID(2).WithName("Recipe 2").Build(),
+		factory.NewRecipeBuilder().WithID(1).WithName("Recipe 1").Build(),
+		factory.NewRecipeBuilder().WithID(2).WithName("Recipe 2").Build(),
 	}
 
 	setup.recipeRepo.On("GetAll", defaultParams).Return(expectedRecipes, 2, nil)
@@ -61,6 +79,23 @@ func TestRecipeService_GetAllRecipes_Success(t *testing.T) {
 	setup.recipeRepo.AssertExpectations(t)
 }
 
+func TestRecipeService_GetAllRecipes_SetsFavoriteCounts(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	setup.favoriteRepo.ExpectedCalls = nil
+	setup.favoriteRepo.On("CountByRecipeIDs", []int{1, 2}).Return(map[int]int{2: 3}, nil)
+	setup.recipeRepo.On("GetAll", defaultParams).Return([]models.Recipe{
+		factory.NewRecipeBuilder().WithID(1).Build(),
+		factory.NewRecipeBuilder().WithID(2).Build(),
+	}, 2, nil)
+
+	result, _, err := setup.service.GetAllRecipes(defaultParams)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, *result[0].FavoriteCount)
+	assert.Equal(t, 3, *result[1].FavoriteCount)
+	setup.favoriteRepo.AssertExpectations(t)
+}
+
 func TestRecipeService_GetAllRecipes_RepositoryError(t *testing.T) {
 	setup := setupRecipeServiceTest()
 	expectedError := errors.New("database error")
@@ -81,7 +116,7 @@ func TestRecipeService_GetAllRecipes_RepositoryError(t *testing.T) {
 
 func TestRecipeService_GetRecipeByID_Success(t *testing.T) {
 	setup := setupRecipeServiceTest()
-	expectedRecipe := testdata.NewRecipeBuilder().WithID(1).BuildPtr()
+	expectedRecipe := factory.NewRecipeBuilder().WithID(1).BuildPtr()
 
 	setup.recipeRepo.On("GetByID", 1).Return(expectedRecipe, nil)
 
@@ -93,6 +128,40 @@ func TestRecipeService_GetRecipeByID_Success(t *testing.T) {
 	setup.recipeRepo.AssertExpectations(t)
 }
 
+func TestRecipeService_GetRecipeByID_SetsCoverImageURLs(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	thumb := "http://localhost:8000/images/recipes/1/b-thumb.jpg"
+	setup.imageRepo.ExpectedCalls = nil
+	setup.imageRepo.On("GetByRecipeIDs", []int{1}).Return(map[int][]models.RecipeImage{
+		1: {
+			{ID: 1, RecipeID: 1, URL: "https://img.example/a.jpg", Position: 1},
+			{ID: 2, RecipeID: 1, URL: "http://localhost:8000/images/recipes/1/b.jpg", ThumbnailURL: &thumb, Position: 2, IsCover: true},
+		},
+	}, nil)
+	setup.recipeRepo.On("GetByID", 1).Return(factory.NewRecipeBuilder().WithID(1).BuildPtr(), nil)
+
+	result, err := setup.service.GetRecipeByID(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8000/images/recipes/1/b.jpg", *result.ImageURL)
+	assert.Equal(t, thumb, *result.ThumbnailURL)
+	setup.imageRepo.AssertExpectations(t)
+}
+
+func TestRecipeService_GetRecipeByID_RollsUpAllergens(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	allergens := []models.RecipeAllergen{{Code: "dairy", Name: "Milk", Ingredients: []string{"Butter", "Parmesan Cheese"}}}
+	setup.ingredientRepo.ExpectedCalls = nil
+	setup.ingredientRepo.On("GetRecipeAllergens", 1).Return(allergens, nil)
+	setup.recipeRepo.On("GetByID", 1).Return(factory.NewRecipeBuilder().WithID(1).BuildPtr(), nil)
+
+	result, err := setup.service.GetRecipeByID(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, allergens, result.Allergens)
+	setup.ingredientRepo.AssertExpectations(t)
+}
+
 func TestRecipeService_GetRecipeByID_InvalidID(t *testing.T) {
 	invalidIDs := []int{0, -1, -999}
 
@@ -123,6 +192,101 @@ func TestRecipeService_GetRecipeByID_NotFound(t *testing.T) {
 	setup.recipeRepo.AssertExpectations(t)
 }
 
+func TestRecipeService_GetRecipeByID_HidesUnpublished(t *testing.T) {
+	for _, status := range []string{models.RecipeStatusDraft, models.RecipeStatusPrivate} {
+		t.Run(status, func(t *testing.T) {
+			setup := setupRecipeServiceTest()
+			recipe := factory.NewRecipeBuilder().WithID(1).WithStatus(status).BuildPtr()
+
+			setup.recipeRepo.On("GetByID", 1).Return(recipe, nil)
+
+			result, err := setup.service.GetRecipeByID(1)
+
+			assert.Nil(t, result)
+			assert.Equal(t, domain.ErrRecipeNotFound, err)
+			setup.recipeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecipeService_GetRecipeByID_ArchivedStaysReadable(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	recipe := factory.NewRecipeBuilder().WithID(1).WithStatus(models.RecipeStatusArchived).BuildPtr()
+
+	setup.recipeRepo.On("GetByID", 1).Return(recipe, nil)
+
+	result, err := setup.service.GetRecipeByID(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RecipeStatusArchived, result.Status)
+	setup.recipeRepo.AssertExpectations(t)
+}
+
+// =============================================================================
+// GET MY RECIPES TESTS
+// =============================================================================
+
+func TestRecipeService_GetMyRecipes_Success(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	expectedRecipes := []models.Recipe{
+		factory.NewRecipeBuilder().WithID(1).WithStatus(models.RecipeStatusDraft).Build(),
+	}
+
+	setup.recipeRepo.On("GetByOwner", 7, models.RecipeStatusDraft, defaultParams).Return(expectedRecipes, 1, nil)
+
+	result, meta, err := setup.service.GetMyRecipes(7, models.RecipeStatusDraft, defaultParams)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 1, meta.Total)
+	setup.recipeRepo.AssertExpectations(t)
+}
+
+func TestRecipeService_GetMyRecipes_AllStatuses(t *testing.T) {
+	setup := setupRecipeServiceTest()
+
+	setup.recipeRepo.On("GetByOwner", 7, "", defaultParams).Return([]models.Recipe{}, 0, nil)
+
+	result, _, err := setup.service.GetMyRecipes(7, "", defaultParams)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+	setup.recipeRepo.AssertExpectations(t)
+}
+
+func TestRecipeService_GetMyRecipes_InvalidStatus(t *testing.T) {
+	setup := setupRecipeServiceTest()
+
+	result, _, err := setup.service.GetMyRecipes(7, "deleted", defaultParams)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrInvalidRecipeStatus, err)
+	setup.recipeRepo.AssertNotCalled(t, "GetByOwner")
+}
+
+func TestRecipeService_CreateRecipe_InvalidDifficulty(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	difficulty := "extreme"
+	req := models.CreateRecipeRequest{Name: "Soup", CategoryID: 1, Difficulty: &difficulty}
+
+	result, err := setup.service.CreateRecipe(1, req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrInvalidDifficulty, err)
+	setup.recipeRepo.AssertNotCalled(t, "Create")
+}
+
+func TestRecipeService_CreateRecipe_InvalidStatus(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	req := models.CreateRecipeRequest{Name: "Soup", CategoryID: 1, Status: "hidden"}
+
+	result, err := setup.service.CreateRecipe(1, req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrInvalidRecipeStatus, err)
+	setup.recipeRepo.AssertNotCalled(t, "Create")
+}
+
 // =============================================================================
 // GET RECIPES BY CATEGORY TESTS
 // =============================================================================
@@ -131,7 +295,7 @@ func TestRecipeService_GetRecipesByCategory_Success(t *testing.T) {
 	setup := setupRecipeServiceTest()
 	categoryID := 1
 	expectedRecipes := []models.Recipe{
-		testdata.NewRecipeBuilder().WithID(1).WithCategoryID(categoryID).Build(),
+		factory.NewRecipeBuilder().WithID(1).WithCategoryID(categoryID).Build(),
 	}
 
 	setup.categoryRepo.On("Exists", categoryID).Return(true, nil)
@@ -184,11 +348,11 @@ func TestRecipeService_GetRecipesByCategory_CategoryNotFound(t *testing.T) {
 
 func TestRecipeService_CreateRecipe_Success(t *testing.T) {
 	setup := setupRecipeServiceTest()
-	request := testdata.NewCreateRecipeRequestBuilder().
+	request := factory.NewCreateRecipeRequestBuilder().
 		WithName("Test Recipe").
 		WithCategoryID(1).
 		Build()
-	expectedRecipe := testdata.NewRecipeBuilder().WithName("Test Recipe").BuildPtr()
+	expectedRecipe := factory.NewRecipeBuilder().WithName("Test Recipe").BuildPtr()
 
 	setup.categoryRepo.On("Exists", 1).Return(true, nil)
 	setup.recipeRepo.On("Create", 1, mock.AnythingOfType("models.CreateRecipeRequest")).
@@ -203,6 +367,44 @@ func TestRecipeService_CreateRecipe_Success(t *testing.T) {
 	setup.recipeRepo.AssertExpectations(t)
 }
 
+func TestRecipeService_CreateRecipe_PublishesOnlyPublicRecipes(t *testing.T) {
+	testCases := []struct {
+		status    string
+		published bool
+	}{
+		{status: models.RecipeStatusPublished, published: true},
+		{status: models.RecipeStatusDraft, published: false},
+		{status: models.RecipeStatusPrivate, published: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.status, func(t *testing.T) {
+			setup := setupRecipeServiceTest()
+			var received []events.Event
+			setup.bus.Subscribe(events.RecipePublished, func(e events.Event) error {
+				received = append(received, e)
+				return nil
+			})
+			created := factory.NewRecipeBuilder().WithID(7).WithStatus(tc.status).BuildPtr()
+			created.UserID = 3
+
+			setup.categoryRepo.On("Exists", 1).Return(true, nil)
+			setup.recipeRepo.On("Create", 3, mock.AnythingOfType("models.CreateRecipeRequest")).Return(created, nil)
+
+			_, err := setup.service.CreateRecipe(3, factory.NewCreateRecipeRequestBuilder().WithCategoryID(1).Build())
+
+			assert.NoError(t, err)
+			if tc.published {
+				assert.Len(t, received, 1)
+				assert.Equal(t, 3, received[0].UserID)
+				assert.Equal(t, 7, received[0].SubjectID)
+			} else {
+				assert.Empty(t, received)
+			}
+		})
+	}
+}
+
 func TestRecipeService_CreateRecipe_ValidationErrors(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -211,22 +413,22 @@ func TestRecipeService_CreateRecipe_ValidationErrors(t *testing.T) {
 	}{
 		{
 			name:          "empty_name",
-			request:       testdata.NewCreateRecipeRequestBuilder().WithName("").Build(),
+			request:       factory.NewCreateRecipeRequestBuilder().WithName("").Build(),
 			expectedError: domain.ErrRecipeNameRequired,
 		},
 		{
 			name:          "whitespace_name",
-			request:       testdata.NewCreateRecipeRequestBuilder().WithName("   ").Build(),
+			request:       factory.NewCreateRecipeRequestBuilder().WithName("   ").Build(),
 			expectedError: domain.ErrRecipeNameRequired,
 		},
 		{
 			name:          "zero_category",
-			request:       testdata.NewCreateRecipeRequestBuilder().WithCategoryID(0).Build(),
+			request:       factory.NewCreateRecipeRequestBuilder().WithCategoryID(0).Build(),
 			expectedError: domain.ErrInvalidCategory,
 		},
 		{
 			name:          "negative_category",
-			request:       testdata.NewCreateRecipeRequestBuilder().WithCategoryID(-1).Build(),
+			request:       factory.NewCreateRecipeRequestBuilder().WithCategoryID(-1).Build(),
 			expectedError: domain.ErrInvalidCategory,
 		},
 	}
@@ -248,7 +450,7 @@ func TestRecipeService_CreateRecipe_ValidationErrors(t *testing.T) {
 
 func TestRecipeService_CreateRecipe_CategoryNotFound(t *testing.T) {
 	setup := setupRecipeServiceTest()
-	request := testdata.NewCreateRecipeRequestBuilder().WithCategoryID(999).Build()
+	request := factory.NewCreateRecipeRequestBuilder().WithCategoryID(999).Build()
 
 	setup.categoryRepo.On("Exists", 999).Return(false, nil)
 
@@ -268,15 +470,15 @@ func TestRecipeService_CreateRecipe_CategoryNotFound(t *testing.T) {
 func TestRecipeService_UpdateRecipe_Success(t *testing.T) {
 	setup := setupRecipeServiceTest()
 	recipeID := 1
-	request := testdata.NewUpdateRecipeRequestBuilder().WithName("Updated Recipe").Build()
-	updatedRecipe := testdata.NewRecipeBuilder().WithID(recipeID).WithName("Updated Recipe").BuildPtr()
+	request := factory.NewUpdateRecipeRequestBuilder().WithName("Updated Recipe").Build()
+	updatedRecipe := factory.NewRecipeBuilder().WithID(recipeID).WithName("Updated Recipe").BuildPtr()
 
 	setup.recipeRepo.On("GetOwnerID", recipeID).Return(1, nil)
 	setup.categoryRepo.On("Exists", request.CategoryID).Return(true, nil)
 	setup.recipeRepo.On("Update", recipeID, mock.AnythingOfType("models.UpdateRecipeRequest")).
 		Return(updatedRecipe, nil)
 
-	result, err := setup.service.UpdateRecipe(1, recipeID, request)
+	result, err := setup.service.UpdateRecipe(asUser(1), recipeID, request)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -294,7 +496,7 @@ func TestRecipeService_UpdateRecipe_RequiresName(t *testing.T) {
 		CategoryID:  1,
 	}
 
-	result, err := setup.service.UpdateRecipe(1, recipeID, updateReq)
+	result, err := setup.service.UpdateRecipe(asUser(1), recipeID, updateReq)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -311,7 +513,7 @@ func TestRecipeService_UpdateRecipe_InvalidCategoryId(t *testing.T) {
 		CategoryID:  0, // Should fail validation
 	}
 
-	result, err := setup.service.UpdateRecipe(1, recipeID, updateReq)
+	result, err := setup.service.UpdateRecipe(asUser(1), recipeID, updateReq)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -325,9 +527,9 @@ func TestRecipeService_UpdateRecipe_InvalidID(t *testing.T) {
 	for _, id := range invalidIDs {
 		t.Run(fmt.Sprintf("id_%d", id), func(t *testing.T) {
 			setup := setupRecipeServiceTest()
-			request := testdata.NewUpdateRecipeRequestBuilder().Build()
+			request := factory.NewUpdateRecipeRequestBuilder().Build()
 
-			result, err := setup.service.UpdateRecipe(1, id, request)
+			result, err := setup.service.UpdateRecipe(asUser(1), id, request)
 
 			assert.Error(t, err)
 			assert.Nil(t, result)
@@ -340,11 +542,11 @@ func TestRecipeService_UpdateRecipe_InvalidID(t *testing.T) {
 func TestRecipeService_UpdateRecipe_RecipeNotFound(t *testing.T) {
 	setup := setupRecipeServiceTest()
 	recipeID := 999
-	request := testdata.NewUpdateRecipeRequestBuilder().Build()
+	request := factory.NewUpdateRecipeRequestBuilder().Build()
 
 	setup.recipeRepo.On("GetOwnerID", recipeID).Return(0, sql.ErrNoRows)
 
-	result, err := setup.service.UpdateRecipe(1, recipeID, request)
+	result, err := setup.service.UpdateRecipe(asUser(1), recipeID, request)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -352,6 +554,30 @@ func TestRecipeService_UpdateRecipe_RecipeNotFound(t *testing.T) {
 	setup.recipeRepo.AssertExpectations(t)
 }
 
+func TestRecipeService_UpdateRecipe_VersionConflict(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	recipeID := 1
+	version := 3
+	request := factory.NewUpdateRecipeRequestBuilder().Build()
+	request.Version = &version
+	current := factory.NewRecipeBuilder().WithID(recipeID).BuildPtr()
+	current.Version = 4
+
+	setup.recipeRepo.On("GetOwnerID", recipeID).Return(1, nil)
+	setup.categoryRepo.On("Exists", request.CategoryID).Return(true, nil)
+	setup.recipeRepo.On("Update", recipeID, mock.AnythingOfType("models.UpdateRecipeRequest")).
+		Return(nil, sql.ErrNoRows)
+	setup.recipeRepo.On("GetByID", recipeID).Return(current, nil)
+
+	result, err := setup.service.UpdateRecipe(asUser(1), recipeID, request)
+
+	assert.Nil(t, result)
+	var conflict *models.VersionConflictError
+	assert.ErrorAs(t, err, &conflict)
+	assert.Equal(t, 4, conflict.CurrentVersion)
+	setup.recipeRepo.AssertExpectations(t)
+}
+
 // =============================================================================
 // DELETE RECIPE TESTS
 // =============================================================================
@@ -363,7 +589,7 @@ func TestRecipeService_DeleteRecipe_Success(t *testing.T) {
 	setup.recipeRepo.On("GetOwnerID", recipeID).Return(1, nil)
 	setup.recipeRepo.On("Delete", recipeID).Return(nil)
 
-	err := setup.service.DeleteRecipe(1, recipeID)
+	err := setup.service.DeleteRecipe(asUser(1), recipeID)
 
 	assert.NoError(t, err)
 	setup.recipeRepo.AssertExpectations(t)
@@ -376,7 +602,7 @@ func TestRecipeService_DeleteRecipe_InvalidID(t *testing.T) {
 		t.Run(fmt.Sprintf("id_%d", id), func(t *testing.T) {
 			setup := setupRecipeServiceTest()
 
-			err := setup.service.DeleteRecipe(1, id)
+			err := setup.service.DeleteRecipe(asUser(1), id)
 
 			assert.Error(t, err)
 			assert.Equal(t, domain.ErrRecipeNotFound, err)
@@ -391,7 +617,7 @@ func TestRecipeService_DeleteRecipe_NotFound(t *testing.T) {
 
 	setup.recipeRepo.On("GetOwnerID", recipeID).Return(0, sql.ErrNoRows)
 
-	err := setup.service.DeleteRecipe(1, recipeID)
+	err := setup.service.DeleteRecipe(asUser(1), recipeID)
 
 	assert.Error(t, err)
 	assert.Equal(t, domain.ErrRecipeNotFound, err)
@@ -405,12 +631,12 @@ func TestRecipeService_DeleteRecipe_NotFound(t *testing.T) {
 func TestRecipeService_UpdateRecipe_Forbidden(t *testing.T) {
 	setup := setupRecipeServiceTest()
 	recipeID := 1
-	request := testdata.NewUpdateRecipeRequestBuilder().WithName("Updated Recipe").Build()
+	request := factory.NewUpdateRecipeRequestBuilder().WithName("Updated Recipe").Build()
 
 	// Recipe exists but is owned by user 2, not the caller (user 1)
 	setup.recipeRepo.On("GetOwnerID", recipeID).Return(2, nil)
 
-	result, err := setup.service.UpdateRecipe(1, recipeID, request)
+	result, err := setup.service.UpdateRecipe(asUser(1), recipeID, request)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -425,7 +651,7 @@ func TestRecipeService_DeleteRecipe_Forbidden(t *testing.T) {
 	// Recipe exists but is owned by user 2, not the caller (user 1)
 	setup.recipeRepo.On("GetOwnerID", recipeID).Return(2, nil)
 
-	err := setup.service.DeleteRecipe(1, recipeID)
+	err := setup.service.DeleteRecipe(asUser(1), recipeID)
 
 	assert.Error(t, err)
 	assert.Equal(t, domain.ErrForbidden, err)
@@ -439,8 +665,8 @@ func TestRecipeService_DeleteRecipe_Forbidden(t *testing.T) {
 func TestRecipeService_GetAllCategories_Success(t *testing.T) {
 	setup := setupRecipeServiceTest()
 	expectedCategories := []models.Category{
-		testdata.NewCategoryBuilder().WithID(1).WithName("Italian").Build(),
-		testdata.NewCategoryBuilder().WithID(2).WithName("Mexican").Build(),
+		factory.NewCategoryBuilder().WithID(1).WithName("Italian").Build(),
+		factory.NewCategoryBuilder().WithID(2).WithName("Mexican").Build(),
 	}
 
 	setup.categoryRepo.On("GetAll").Return(expectedCategories, nil)
@@ -474,7 +700,7 @@ func TestRecipeService_GetAllCategories_RepositoryError(t *testing.T) {
 func TestRecipeService_GetAllRecipesWithIngredients_Success(t *testing.T) {
 	setup := setupRecipeServiceTest()
 	expectedRecipes := []models.RecipeWithIngredients{
-		testdata.NewRecipeWithIngredientsBuilder().Build(),
+		factory.NewRecipeWithIngredientsBuilder().Build(),
 	}
 
 	setup.recipeRepo.On("GetAllWithIngredients", defaultParams).Return(expectedRecipes, 1, nil)
@@ -506,7 +732,7 @@ func TestRecipeService_GetAllRecipesWithIngredients_RepositoryError(t *testing.T
 
 func TestRecipeService_GetRecipeByIDWithIngredients_Success(t *testing.T) {
 	setup := setupRecipeServiceTest()
-	expectedRecipe := testdata.NewRecipeWithIngredientsBuilder().BuildPtr()
+	expectedRecipe := factory.NewRecipeWithIngredientsBuilder().BuildPtr()
 
 	setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(expectedRecipe, nil)
 
@@ -517,6 +743,22 @@ func TestRecipeService_GetRecipeByIDWithIngredients_Success(t *testing.T) {
 	setup.recipeRepo.AssertExpectations(t)
 }
 
+func TestRecipeService_GetRecipeByIDWithIngredients_EstimatesDifficulty(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	expectedRecipe := factory.NewRecipeWithIngredientsBuilder().BuildPtr()
+
+	setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(expectedRecipe, nil)
+
+	result, err := setup.service.GetRecipeByIDWithIngredients(1)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, result.Difficulty) {
+		assert.Equal(t, models.DifficultyEasy, *result.Difficulty)
+	}
+	assert.True(t, result.DifficultyEstimated)
+	setup.recipeRepo.AssertExpectations(t)
+}
+
 func TestRecipeService_GetRecipeByIDWithIngredients_InvalidID(t *testing.T) {
 	setup := setupRecipeServiceTest()
 
@@ -549,7 +791,7 @@ func TestRecipeService_GetRecipesByCategoryWithIngredients_Success(t *testing.T)
 	setup := setupRecipeServiceTest()
 	categoryID := 1
 	expectedRecipes := []models.RecipeWithIngredients{
-		testdata.NewRecipeWithIngredientsBuilder().Build(),
+		factory.NewRecipeWithIngredientsBuilder().Build(),
 	}
 
 	setup.categoryRepo.On("Exists", categoryID).Return(true, nil)
@@ -588,10 +830,10 @@ func TestRecipeService_CreateRecipeWithIngredients_Success(t *testing.T) {
 			{IngredientID: 1, Quantity: 100.0, Unit: "grams"},
 		},
 	}
-	expectedResult := testdata.NewRecipeWithIngredientsBuilder().BuildPtr()
+	expectedResult := factory.NewRecipeWithIngredientsBuilder().BuildPtr()
 
 	setup.categoryRepo.On("Exists", 1).Return(true, nil)
-	setup.ingredientRepo.On("IngredientExists", 1).Return(true, nil)
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{1}).Return([]int{}, nil)
 	setup.recipeRepo.On("CreateWithIngredients", 1, mock.AnythingOfType("models.CreateRecipeWithIngredientsRequest")).
 		Return(expectedResult, nil)
 
@@ -604,6 +846,34 @@ func TestRecipeService_CreateRecipeWithIngredients_Success(t *testing.T) {
 	setup.recipeRepo.AssertExpectations(t)
 }
 
+func TestRecipeService_CreateRecipeWithIngredients_NamedIngredients(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	request := models.CreateRecipeWithIngredientsRequest{
+		Name:       "Shakshuka",
+		CategoryID: 1,
+		Ingredients: []models.AddRecipeIngredientRequest{
+			{IngredientID: 12, Quantity: 4, Unit: "pieces"},
+			{IngredientName: "  Sumac ", IngredientCategory: " Spices", Quantity: 1, Unit: " tsp "},
+		},
+	}
+	expectedResult := factory.NewRecipeWithIngredientsBuilder().BuildPtr()
+
+	setup.categoryRepo.On("Exists", 1).Return(true, nil)
+	// Only lines given by ID are checked up front
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{12}).Return([]int{}, nil)
+	setup.recipeRepo.On("CreateWithIngredients", 1, mock.MatchedBy(func(req models.CreateRecipeWithIngredientsRequest) bool {
+		named := req.Ingredients[1]
+		return named.IngredientName == "Sumac" && named.IngredientCategory == "Spices" && named.Unit == "tsp"
+	})).Return(expectedResult, nil)
+
+	result, err := setup.service.CreateRecipeWithIngredients(1, request)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	setup.ingredientRepo.AssertExpectations(t)
+	setup.recipeRepo.AssertExpectations(t)
+}
+
 func TestRecipeService_CreateRecipeWithIngredients_ValidationErrors(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -668,6 +938,34 @@ func TestRecipeService_CreateRecipeWithIngredients_ValidationErrors(t *testing.T
 				setup.categoryRepo.On("Exists", 1).Return(true, nil)
 			},
 		},
+		{
+			name: "ingredient_name_too_long",
+			request: models.CreateRecipeWithIngredientsRequest{
+				Name:       "Valid",
+				CategoryID: 1,
+				Ingredients: []models.AddRecipeIngredientRequest{
+					{IngredientName: strings.Repeat("a", 101), Quantity: 1, Unit: "grams"},
+				},
+			},
+			expectedError: domain.ErrInvalidIngredientName,
+			setupMocks: func(setup *recipeServiceTestSetup) {
+				setup.categoryRepo.On("Exists", 1).Return(true, nil)
+			},
+		},
+		{
+			name: "invalid_ingredient_category",
+			request: models.CreateRecipeWithIngredientsRequest{
+				Name:       "Valid",
+				CategoryID: 1,
+				Ingredients: []models.AddRecipeIngredientRequest{
+					{IngredientName: "Sumac", IngredientCategory: "Herbs", Quantity: 1, Unit: "tsp"},
+				},
+			},
+			expectedError: domain.ErrInvalidIngredientCategory,
+			setupMocks: func(setup *recipeServiceTestSetup) {
+				setup.categoryRepo.On("Exists", 1).Return(true, nil)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -705,15 +1003,15 @@ func TestRecipeService_UpdateRecipeWithIngredients_Success(t *testing.T) {
 			{IngredientID: 1, Quantity: 150.0, Unit: "grams"},
 		},
 	}
-	expectedResult := testdata.NewRecipeWithIngredientsBuilder().BuildPtr()
+	expectedResult := factory.NewRecipeWithIngredientsBuilder().BuildPtr()
 
 	setup.recipeRepo.On("GetOwnerID", recipeID).Return(1, nil)
 	setup.categoryRepo.On("Exists", 1).Return(true, nil)
-	setup.ingredientRepo.On("IngredientExists", 1).Return(true, nil)
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{1}).Return([]int{}, nil)
 	setup.recipeRepo.On("UpdateWithIngredients", recipeID, mock.AnythingOfType("models.UpdateRecipeWithIngredientsRequest")).
 		Return(expectedResult, nil)
 
-	result, err := setup.service.UpdateRecipeWithIngredients(1, recipeID, request)
+	result, err := setup.service.UpdateRecipeWithIngredients(asUser(1), recipeID, request)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -725,7 +1023,7 @@ func TestRecipeService_UpdateRecipeWithIngredients_InvalidID(t *testing.T) {
 	setup := setupRecipeServiceTest()
 	request := models.UpdateRecipeWithIngredientsRequest{}
 
-	result, err := setup.service.UpdateRecipeWithIngredients(1, 0, request)
+	result, err := setup.service.UpdateRecipeWithIngredients(asUser(1), 0, request)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -741,11 +1039,10 @@ func TestRecipeService_SearchRecipesByIngredients_Success(t *testing.T) {
 	setup := setupRecipeServiceTest()
 	ingredientIDs := []int{1, 2}
 	expectedRecipes := []models.Recipe{
-		testdata.NewRecipeBuilder().WithID(1).Build(),
+		factory.NewRecipeBuilder().WithID(1).Build(),
 	}
 
-	setup.ingredientRepo.On("IngredientExists", 1).Return(true, nil)
-	setup.ingredientRepo.On("IngredientExists", 2).Return(true, nil)
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{1, 2}).Return([]int{}, nil)
 	setup.recipeRepo.On("SearchRecipesByIngredients", ingredientIDs, defaultParams).Return(expectedRecipes, 1, nil)
 
 	result, meta, err := setup.service.SearchRecipesByIngredients(ingredientIDs, defaultParams)
@@ -757,6 +1054,17 @@ func TestRecipeService_SearchRecipesByIngredients_Success(t *testing.T) {
 	setup.recipeRepo.AssertExpectations(t)
 }
 
+func TestRecipeService_GetSearchFacets_UsesTimeBuckets(t *testing.T) {
+	setup := setupRecipeServiceTest()
+	facets := &models.RecipeFacets{Categories: []models.CategoryFacet{{CategoryID: 3, Name: "Fish", Count: 2}}}
+	setup.recipeRepo.On("GetSearchFacets", []int{1, 2}, []int{15, 30, 60, 120}).Return(facets, nil)
+
+	result, err := setup.service.GetSearchFacets([]int{1, 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, facets, result)
+}
+
 func TestRecipeService_SearchRecipesByIngredients_EmptyInput(t *testing.T) {
 	setup := setupRecipeServiceTest()
 
@@ -765,7 +1073,7 @@ func TestRecipeService_SearchRecipesByIngredients_EmptyInput(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Empty(t, result)
 	assert.Equal(t, 0, meta.Total)
-	setup.ingredientRepo.AssertNotCalled(t, "IngredientExists")
+	setup.ingredientRepo.AssertNotCalled(t, "FindMissingIngredientIDs")
 	setup.recipeRepo.AssertNotCalled(t, "SearchRecipesByIngredients")
 }
 
@@ -778,15 +1086,14 @@ func TestRecipeService_SearchRecipesByIngredients_InvalidIngredientID(t *testing
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Equal(t, domain.ErrIngredientNotFound, err)
-	setup.ingredientRepo.AssertNotCalled(t, "IngredientExists")
+	setup.ingredientRepo.AssertNotCalled(t, "FindMissingIngredientIDs")
 }
 
 func TestRecipeService_SearchRecipesByIngredients_IngredientNotFound(t *testing.T) {
 	setup := setupRecipeServiceTest()
 	ingredientIDs := []int{1, 999}
 
-	setup.ingredientRepo.On("IngredientExists", 1).Return(true, nil)
-	setup.ingredientRepo.On("IngredientExists", 999).Return(false, nil)
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{1, 999}).Return([]int{999}, nil)
 
 	result, _, err := setup.service.SearchRecipesByIngredients(ingredientIDs, defaultParams)
 
@@ -805,11 +1112,10 @@ func TestRecipeService_SearchRecipesByIngredientsWithIngredients_Success(t *test
 	setup := setupRecipeServiceTest()
 	ingredientIDs := []int{1, 2}
 	expectedRecipes := []models.RecipeWithIngredients{
-		testdata.NewRecipeWithIngredientsBuilder().Build(),
+		factory.NewRecipeWithIngredientsBuilder().Build(),
 	}
 
-	setup.ingredientRepo.On("IngredientExists", 1).Return(true, nil)
-	setup.ingredientRepo.On("IngredientExists", 2).Return(true, nil)
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{1, 2}).Return([]int{}, nil)
 	setup.recipeRepo.On("SearchRecipesByIngredientsWithIngredients", ingredientIDs, defaultParams).
 		Return(expectedRecipes, 1, nil)
 
@@ -830,6 +1136,6 @@ func TestRecipeService_SearchRecipesByIngredientsWithIngredients_EmptyInput(t *t
 	assert.NoError(t, err)
 	assert.Empty(t, result)
 	assert.Equal(t, 0, meta.Total)
-	setup.ingredientRepo.AssertNotCalled(t, "IngredientExists")
+	setup.ingredientRepo.AssertNotCalled(t, "FindMissingIngredientIDs")
 	setup.recipeRepo.AssertNotCalled(t, "SearchRecipesByIngredientsWithIngredients")
 }
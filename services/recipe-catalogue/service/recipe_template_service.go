@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"unicode/utf8"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+)
+
+const maxTemplateDescriptionLength = 500
+
+type RecipeTemplateService interface {
+	GetTemplates() ([]models.RecipeTemplate, error)
+	GetTemplate(id int) (*models.RecipeTemplate, error)
+	CreateTemplate(ctx context.Context, userID int, req models.CreateRecipeTemplateRequest) (*models.RecipeTemplate, error)
+	DeleteTemplate(id int) error
+	InstantiateTemplate(userID, id int, req models.InstantiateTemplateRequest) (*models.RecipeWithIngredients, error)
+}
+
+type recipeTemplateService struct {
+	templateRepo repository.RecipeTemplateRepository
+	recipeRepo   repository.RecipeRepository
+	stepRepo     repository.StepRepository
+	categoryRepo repository.CategoryRepository
+}
+
+func NewRecipeTemplateService(templateRepo repository.RecipeTemplateRepository, recipeRepo repository.RecipeRepository, stepRepo repository.StepRepository, categoryRepo repository.CategoryRepository) RecipeTemplateService {
+	return &recipeTemplateService{
+		templateRepo: templateRepo,
+		recipeRepo:   recipeRepo,
+		stepRepo:     stepRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (s *recipeTemplateService) GetTemplates() ([]models.RecipeTemplate, error) {
+	return s.templateRepo.GetAll()
+}
+
+// GetTemplate returns the template with its recipe, ingredients and steps,
+// whoever can otherwise see the recipe.
+func (s *recipeTemplateService) GetTemplate(id int) (*models.RecipeTemplate, error) {
+	template, err := s.getTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Recipe, err = s.recipeRepo.GetByIDWithIngredients(template.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// CreateTemplate makes a recipe the admin can see into a template. Admins
+// usually keep template recipes private, so they only reach users as
+// templates.
+func (s *recipeTemplateService) CreateTemplate(ctx context.Context, userID int, req models.CreateRecipeTemplateRequest) (*models.RecipeTemplate, error) {
+	if req.RecipeID <= 0 {
+		return nil, domain.ErrRecipeNotFound
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		if utf8.RuneCountInString(description) > maxTemplateDescriptionLength {
+			return nil, domain.ErrTemplateDescriptionTooLong
+		}
+		req.Description = &description
+		if description == "" {
+			req.Description = nil
+		}
+	}
+
+	if _, err := readableRecipe(ctx, s.recipeRepo, req.RecipeID); err != nil {
+		return nil, err
+	}
+
+	return s.templateRepo.Create(userID, req)
+}
+
+func (s *recipeTemplateService) DeleteTemplate(id int) error {
+	if id <= 0 {
+		return domain.ErrTemplateNotFound
+	}
+	if err := s.templateRepo.Delete(id); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrTemplateNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// InstantiateTemplate copies the template's recipe, with its ingredients
+// and steps, into a new draft owned by the user for them to make their own.
+func (s *recipeTemplateService) InstantiateTemplate(userID, id int, req models.InstantiateTemplateRequest) (*models.RecipeWithIngredients, error) {
+	template, err := s.getTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := s.recipeRepo.GetByIDWithIngredients(template.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+	return copyRecipe(s.recipeRepo, s.stepRepo, s.categoryRepo, userID, original, req.Name, req.CategoryID,
+		domain.ErrTemplateCategoryRequired)
+}
+
+func (s *recipeTemplateService) getTemplate(id int) (*models.RecipeTemplate, error) {
+	if id <= 0 {
+		return nil, domain.ErrTemplateNotFound
+	}
+	template, err := s.templateRepo.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return template, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type recipeTemplateServiceTestSetup struct {
+	service      RecipeTemplateService
+	templateRepo *mocks.MockRecipeTemplateRepository
+	recipeRepo   *mocks.MockRecipeRepository
+	stepRepo     *mocks.MockStepRepository
+	categoryRepo *mocks.MockCategoryRepository
+}
+
+func setupRecipeTemplateServiceTest() *recipeTemplateServiceTestSetup {
+	templateRepo := new(mocks.MockRecipeTemplateRepository)
+	recipeRepo := new(mocks.MockRecipeRepository)
+	stepRepo := new(mocks.MockStepRepository)
+	categoryRepo := new(mocks.MockCategoryRepository)
+
+	return &recipeTemplateServiceTestSetup{
+		service:      NewRecipeTemplateService(templateRepo, recipeRepo, stepRepo, categoryRepo),
+		templateRepo: templateRepo,
+		recipeRepo:   recipeRepo,
+		stepRepo:     stepRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func TestRecipeTemplateService_InstantiateTemplate_CopiesPrivateRecipe(t *testing.T) {
+	setup := setupRecipeTemplateServiceTest()
+	categoryID := 4
+	original := &models.RecipeWithIngredients{
+		Recipe:      models.Recipe{ID: 1, UserID: 9, Name: "Basic stir fry", CategoryID: &categoryID, Status: models.RecipeStatusPrivate},
+		Ingredients: []models.RecipeIngredient{{IngredientID: 5, Quantity: 300, Unit: "g"}},
+	}
+	created := &models.RecipeWithIngredients{Recipe: models.Recipe{ID: 10, UserID: 8, Name: "Basic stir fry", Status: models.RecipeStatusDraft}}
+
+	setup.templateRepo.On("GetByID", 3).Return(&models.RecipeTemplate{ID: 3, RecipeID: 1}, nil)
+	setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(original, nil)
+	setup.recipeRepo.On("CreateWithIngredients", 8, mock.MatchedBy(func(req models.CreateRecipeWithIngredientsRequest) bool {
+		return req.Name == "Basic stir fry" && req.CategoryID == 4 && req.Status == models.RecipeStatusDraft &&
+			len(req.Ingredients) == 1 && req.Ingredients[0].IngredientID == 5
+	})).Return(created, nil)
+	setup.stepRepo.On("GetByRecipeID", 1).Return([]models.RecipeStep{{Instruction: "Stir fry everything"}}, nil)
+	setup.stepRepo.On("ReplaceForRecipe", 10, []models.CreateStepRequest{{Instruction: "Stir fry everything"}}).
+		Return([]models.RecipeStep{}, nil)
+	setup.stepRepo.On("GetTechniques", 1).Return([]string{}, nil)
+
+	result, err := setup.service.InstantiateTemplate(8, 3, models.InstantiateTemplateRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, created, result)
+	setup.recipeRepo.AssertExpectations(t)
+	setup.stepRepo.AssertExpectations(t)
+}
+
+func TestRecipeTemplateService_InstantiateTemplate_Errors(t *testing.T) {
+	t.Run("missing template", func(t *testing.T) {
+		setup := setupRecipeTemplateServiceTest()
+		setup.templateRepo.On("GetByID", 3).Return(nil, sql.ErrNoRows)
+
+		_, err := setup.service.InstantiateTemplate(8, 3, models.InstantiateTemplateRequest{})
+
+		assert.Equal(t, domain.ErrTemplateNotFound, err)
+	})
+
+	t.Run("uncategorised template", func(t *testing.T) {
+		setup := setupRecipeTemplateServiceTest()
+		setup.templateRepo.On("GetByID", 3).Return(&models.RecipeTemplate{ID: 3, RecipeID: 1}, nil)
+		setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(&models.RecipeWithIngredients{Recipe: models.Recipe{ID: 1, UserID: 9}}, nil)
+
+		_, err := setup.service.InstantiateTemplate(8, 3, models.InstantiateTemplateRequest{})
+
+		assert.Equal(t, domain.ErrTemplateCategoryRequired, err)
+		setup.recipeRepo.AssertNotCalled(t, "CreateWithIngredients")
+	})
+}
+
+func TestRecipeTemplateService_CreateTemplate(t *testing.T) {
+	padded := "  Swap in any vegetables "
+	trimmed := "Swap in any vegetables"
+	blank := " "
+	tooLong := strings.Repeat("a", 501)
+	tests := []struct {
+		name     string
+		req      models.CreateRecipeTemplateRequest
+		recipe   *models.Recipe
+		expected models.CreateRecipeTemplateRequest
+		err      error
+	}{
+		{
+			name:     "own private recipe",
+			req:      models.CreateRecipeTemplateRequest{RecipeID: 1, Description: &padded},
+			recipe:   &models.Recipe{ID: 1, UserID: 9, Status: models.RecipeStatusPrivate},
+			expected: models.CreateRecipeTemplateRequest{RecipeID: 1, Description: &trimmed},
+		},
+		{
+			name:     "someone's published recipe without a description",
+			req:      models.CreateRecipeTemplateRequest{RecipeID: 1, Description: &blank},
+			recipe:   &models.Recipe{ID: 1, UserID: 3, Status: models.RecipeStatusPublished},
+			expected: models.CreateRecipeTemplateRequest{RecipeID: 1},
+		},
+		{
+			name:   "someone's draft",
+			req:    models.CreateRecipeTemplateRequest{RecipeID: 1},
+			recipe: &models.Recipe{ID: 1, UserID: 3, Status: models.RecipeStatusDraft},
+			err:    domain.ErrRecipeNotFound,
+		},
+		{name: "no recipe", req: models.CreateRecipeTemplateRequest{}, err: domain.ErrRecipeNotFound},
+		{name: "long description", req: models.CreateRecipeTemplateRequest{RecipeID: 1, Description: &tooLong}, err: domain.ErrTemplateDescriptionTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupRecipeTemplateServiceTest()
+			if tt.recipe != nil {
+				setup.recipeRepo.On("GetByID", tt.req.RecipeID).Return(tt.recipe, nil)
+			}
+			template := &models.RecipeTemplate{ID: 3, RecipeID: 1}
+			setup.templateRepo.On("Create", 9, tt.expected).Return(template, nil)
+
+			result, err := setup.service.CreateTemplate(asUser(9), 9, tt.req)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				setup.templateRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, template, result)
+			setup.templateRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecipeTemplateService_GetTemplate_WithRecipeAndSteps(t *testing.T) {
+	setup := setupRecipeTemplateServiceTest()
+	steps := []models.RecipeStep{{ID: 2, RecipeID: 1, Position: 1, Instruction: "Stir fry everything"}}
+	setup.templateRepo.On("GetByID", 3).Return(&models.RecipeTemplate{ID: 3, RecipeID: 1, Name: "Basic stir fry"}, nil)
+	setup.recipeRepo.On("GetByIDWithIngredients", 1).Return(&models.RecipeWithIngredients{Recipe: models.Recipe{ID: 1}, Steps: steps}, nil)
+
+	template, err := setup.service.GetTemplate(3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, template.Recipe.ID)
+	assert.Equal(t, steps, template.Recipe.Steps)
+}
+
+func TestRecipeTemplateService_DeleteTemplate_Missing(t *testing.T) {
+	setup := setupRecipeTemplateServiceTest()
+	setup.templateRepo.On("Delete", 3).Return(sql.ErrNoRows)
+
+	assert.Equal(t, domain.ErrTemplateNotFound, setup.service.DeleteTemplate(3))
+}
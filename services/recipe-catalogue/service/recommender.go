@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"meal-prep/shared/models"
+)
+
+// RecipeRecommender suggests published recipes for a user to cook, best
+// first. The recommendations service does the scoring; main wires in a
+// RecommendationsClient.
+type RecipeRecommender interface {
+	RecommendRecipes(ctx context.Context, userID, limit int) ([]int, error)
+}
+
+// RecommendationsClient fetches recommendations from the recommendations
+// service's internal endpoint.
+type RecommendationsClient struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewRecommendationsClient calls the recommendations endpoint served at
+// endpoint.
+func NewRecommendationsClient(endpoint string, client *http.Client) *RecommendationsClient {
+	return &RecommendationsClient{endpoint: endpoint, client: client}
+}
+
+func (c *RecommendationsClient) RecommendRecipes(ctx context.Context, userID, limit int) ([]int, error) {
+	query := url.Values{"user_id": {strconv.Itoa(userID)}, "limit": {strconv.Itoa(limit)}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("recommendations request failed with status %d", resp.StatusCode)
+	}
+	var recommendations models.RecommendationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&recommendations); err != nil {
+		return nil, err
+	}
+
+	recipeIDs := make([]int, len(recommendations.Recipes))
+	for i, recipe := range recommendations.Recipes {
+		recipeIDs[i] = recipe.ID
+	}
+	return recipeIDs, nil
+}
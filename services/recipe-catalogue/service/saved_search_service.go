@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+)
+
+const (
+	maxSavedSearchNameLength  = 100
+	maxSavedSearchIngredients = 20
+
+	defaultSavedSearchCheckInterval = time.Hour
+)
+
+// SearchAlertNotifier delivers saved search alerts. There is no notification
+// service yet, so main uses the log notifier; a mailer only has to implement
+// this.
+type SearchAlertNotifier interface {
+	NotifySearchAlert(alert models.SavedSearchAlert) error
+}
+
+type logSearchAlertNotifier struct{}
+
+// NewLogSearchAlertNotifier returns a SearchAlertNotifier that writes alerts
+// to the service log.
+func NewLogSearchAlertNotifier() SearchAlertNotifier {
+	return logSearchAlertNotifier{}
+}
+
+func (logSearchAlertNotifier) NotifySearchAlert(alert models.SavedSearchAlert) error {
+	recipeIDs := make([]int, len(alert.Recipes))
+	for i, recipe := range alert.Recipes {
+		recipeIDs[i] = recipe.ID
+	}
+	logging.Logger.Info("Saved search alert", "user_id", alert.UserID, "saved_search_id", alert.SavedSearchID,
+		"search_name", alert.SearchName, "recipe_ids", recipeIDs)
+	return nil
+}
+
+// SavedSearchCheckIntervalFromEnv reads how often RunSavedSearchAlerts looks
+// for new matching recipes (SAVED_SEARCH_CHECK_INTERVAL, default 1h; 0
+// disables alerts).
+func SavedSearchCheckIntervalFromEnv() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("SAVED_SEARCH_CHECK_INTERVAL")); err == nil && d >= 0 {
+		return d
+	}
+	return defaultSavedSearchCheckInterval
+}
+
+type SavedSearchService interface {
+	GetSavedSearches(userID int) ([]models.SavedSearch, error)
+	CreateSavedSearch(userID int, req models.SavedSearchRequest) (*models.SavedSearch, error)
+	UpdateSavedSearch(ctx context.Context, userID, searchID int, req models.SavedSearchRequest) (*models.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, userID, searchID int) error
+
+	// CheckAlerts tells the owners of searches with notifications on about
+	// recipes created since the last check and returns how many alerts went out
+	CheckAlerts(now time.Time) (int, error)
+}
+
+type savedSearchService struct {
+	savedSearchRepo repository.SavedSearchRepository
+	recipeRepo      repository.RecipeRepository
+	categoryRepo    repository.CategoryRepository
+	ingredientRepo  repository.IngredientRepository
+	notifier        SearchAlertNotifier
+}
+
+func NewSavedSearchService(savedSearchRepo repository.SavedSearchRepository, recipeRepo repository.RecipeRepository, categoryRepo repository.CategoryRepository, ingredientRepo repository.IngredientRepository, notifier SearchAlertNotifier) SavedSearchService {
+	return &savedSearchService{
+		savedSearchRepo: savedSearchRepo,
+		recipeRepo:      recipeRepo,
+		categoryRepo:    categoryRepo,
+		ingredientRepo:  ingredientRepo,
+		notifier:        notifier,
+	}
+}
+
+func (s *savedSearchService) GetSavedSearches(userID int) ([]models.SavedSearch, error) {
+	return s.savedSearchRepo.GetByUserID(userID)
+}
+
+func (s *savedSearchService) CreateSavedSearch(userID int, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	req, err := s.normalizeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.savedSearchRepo.Create(userID, req)
+}
+
+func (s *savedSearchService) UpdateSavedSearch(ctx context.Context, userID, searchID int, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	req, err := s.normalizeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ownedSavedSearch(ctx, policy.Update, searchID); err != nil {
+		return nil, err
+	}
+
+	search, err := s.savedSearchRepo.Update(searchID, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrSavedSearchNotFound
+		}
+		return nil, err
+	}
+	return search, nil
+}
+
+func (s *savedSearchService) DeleteSavedSearch(ctx context.Context, userID, searchID int) error {
+	if _, err := s.ownedSavedSearch(ctx, policy.Delete, searchID); err != nil {
+		return err
+	}
+
+	err := s.savedSearchRepo.Delete(searchID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrSavedSearchNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// CheckAlerts looks at recipes created after each search's last check, up to
+// now. It keeps going when one alert fails so a single bad search does not
+// hold up everybody else's; failed searches stay unchecked and are retried on
+// the next run.
+func (s *savedSearchService) CheckAlerts(now time.Time) (int, error) {
+	searches, err := s.savedSearchRepo.GetAlerting()
+	if err != nil || len(searches) == 0 {
+		return 0, err
+	}
+
+	// Searches come least recently checked first
+	recipes, err := s.recipeRepo.GetCreatedSince(searches[0].LastCheckedAt)
+	if err != nil {
+		return 0, err
+	}
+	recipeIDs := make([]int, 0, len(recipes))
+	for _, recipe := range recipes {
+		recipeIDs = append(recipeIDs, recipe.ID)
+	}
+	ingredients := map[int][]models.RecipeIngredient{}
+	if len(recipeIDs) > 0 {
+		if ingredients, err = s.ingredientRepo.GetIngredientsForRecipes(recipeIDs); err != nil {
+			return 0, err
+		}
+	}
+
+	sent := 0
+	for _, search := range searches {
+		matches := matchingNewRecipes(search, recipes, ingredients, now)
+		if len(matches) > 0 {
+			err = s.notifier.NotifySearchAlert(models.SavedSearchAlert{
+				UserID:        search.UserID,
+				SavedSearchID: search.ID,
+				SearchName:    search.Name,
+				Recipes:       matches,
+			})
+			if err != nil {
+				logging.Logger.Error("Saved search alert failed", "saved_search_id", search.ID, "error", err)
+				continue
+			}
+			sent++
+		}
+		if err := s.savedSearchRepo.MarkChecked(search.ID, now); err != nil {
+			logging.Logger.Error("Saved search check failed", "saved_search_id", search.ID, "error", err)
+		}
+	}
+	return sent, nil
+}
+
+// matchingNewRecipes picks the recipes created in the search's alert window
+// that use at least one of its ingredients and pass its filters, leaving out
+// the owner's own recipes.
+func matchingNewRecipes(search models.SavedSearch, recipes []models.Recipe, ingredients map[int][]models.RecipeIngredient, now time.Time) []models.Recipe {
+	wanted := make(map[int]bool, len(search.IngredientIDs))
+	for _, id := range search.IngredientIDs {
+		wanted[id] = true
+	}
+
+	matches := make([]models.Recipe, 0)
+	for _, recipe := range recipes {
+		if !recipe.CreatedAt.After(search.LastCheckedAt) || recipe.CreatedAt.After(now) || recipe.UserID == search.UserID {
+			continue
+		}
+		if search.CategoryID != nil && (recipe.CategoryID == nil || *recipe.CategoryID != *search.CategoryID) {
+			continue
+		}
+		if search.MaxTimeMinutes != nil && (recipe.TotalTimeMinutes == nil || *recipe.TotalTimeMinutes > *search.MaxTimeMinutes) {
+			continue
+		}
+		for _, ingredient := range ingredients[recipe.ID] {
+			if wanted[ingredient.IngredientID] {
+				matches = append(matches, recipe)
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// ownedSavedSearch treats other users' searches as missing so their IDs
+// reveal nothing.
+func (s *savedSearchService) ownedSavedSearch(ctx context.Context, action policy.Action, searchID int) (*models.SavedSearch, error) {
+	if searchID <= 0 {
+		return nil, domain.ErrSavedSearchNotFound
+	}
+
+	search, err := s.savedSearchRepo.GetByID(searchID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrSavedSearchNotFound
+		}
+		return nil, err
+	}
+	resource := policy.Resource{Type: savedSearchResource, OwnerID: search.UserID}
+	if err := authorize(ctx, action, resource, domain.ErrSavedSearchNotFound); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// normalizeRequest trims the name and checks that the ingredients and
+// category exist, as the search they describe would.
+func (s *savedSearchService) normalizeRequest(req models.SavedSearchRequest) (models.SavedSearchRequest, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || utf8.RuneCountInString(req.Name) > maxSavedSearchNameLength {
+		return req, domain.ErrSavedSearchNameRequired
+	}
+
+	if len(req.IngredientIDs) == 0 || len(req.IngredientIDs) > maxSavedSearchIngredients {
+		return req, domain.ErrInvalidSavedSearch
+	}
+	seen := make(map[int]bool, len(req.IngredientIDs))
+	for _, id := range req.IngredientIDs {
+		if id <= 0 {
+			return req, domain.ErrIngredientNotFound
+		}
+		if seen[id] {
+			return req, domain.ErrInvalidSavedSearch
+		}
+		seen[id] = true
+	}
+
+	if req.MaxTimeMinutes != nil && *req.MaxTimeMinutes <= 0 {
+		return req, domain.ErrInvalidSavedSearch
+	}
+
+	if req.CategoryID != nil {
+		exists, err := s.categoryRepo.Exists(*req.CategoryID)
+		if err != nil {
+			return req, err
+		}
+		if !exists {
+			return req, domain.ErrCategoryNotFound
+		}
+	}
+
+	if err := ensureIngredientsExist(s.ingredientRepo, req.IngredientIDs); err != nil {
+		return req, err
+	}
+	return req, nil
+}
+
+// RunSavedSearchAlerts checks saved searches every interval until ctx is
+// cancelled. A failed run is logged and retried on the next tick.
+func RunSavedSearchAlerts(ctx context.Context, savedSearches SavedSearchService, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			sent, err := savedSearches.CheckAlerts(now)
+			if err != nil {
+				logging.Logger.Error("Saved search alert run failed", "error", err)
+				continue
+			}
+			if sent > 0 {
+				logging.Logger.Info("Saved search alerts sent", "count", sent)
+			}
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier keeps the alerts it is given and fails for the users in
+// failFor.
+type recordingNotifier struct {
+	alerts  []models.SavedSearchAlert
+	failFor map[int]bool
+}
+
+func (n *recordingNotifier) NotifySearchAlert(alert models.SavedSearchAlert) error {
+	if n.failFor[alert.UserID] {
+		return errors.New("mailer unavailable")
+	}
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+type savedSearchServiceTestSetup struct {
+	service         SavedSearchService
+	savedSearchRepo *mocks.MockSavedSearchRepository
+	recipeRepo      *mocks.MockRecipeRepository
+	categoryRepo    *mocks.MockCategoryRepository
+	ingredientRepo  *mocks.MockIngredientRepository
+	notifier        *recordingNotifier
+}
+
+func setupSavedSearchServiceTest() *savedSearchServiceTestSetup {
+	savedSearchRepo := new(mocks.MockSavedSearchRepository)
+	recipeRepo := new(mocks.MockRecipeRepository)
+	categoryRepo := new(mocks.MockCategoryRepository)
+	ingredientRepo := new(mocks.MockIngredientRepository)
+	notifier := &recordingNotifier{}
+
+	return &savedSearchServiceTestSetup{
+		service:         NewSavedSearchService(savedSearchRepo, recipeRepo, categoryRepo, ingredientRepo, notifier),
+		savedSearchRepo: savedSearchRepo,
+		recipeRepo:      recipeRepo,
+		categoryRepo:    categoryRepo,
+		ingredientRepo:  ingredientRepo,
+		notifier:        notifier,
+	}
+}
+
+func TestSavedSearchService_CreateSavedSearch(t *testing.T) {
+	setup := setupSavedSearchServiceTest()
+	req := models.SavedSearchRequest{Name: "  Quick chicken  ", IngredientIDs: []int{2, 5}, CategoryID: intPtr(2), MaxTimeMinutes: intPtr(30)}
+	expected := req
+	expected.Name = "Quick chicken"
+	stored := &models.SavedSearch{ID: 4, UserID: 7, Name: "Quick chicken", IngredientIDs: []int{2, 5}}
+
+	setup.categoryRepo.On("Exists", 2).Return(true, nil)
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{2, 5}).Return([]int{}, nil)
+	setup.savedSearchRepo.On("Create", 7, expected).Return(stored, nil)
+
+	search, err := setup.service.CreateSavedSearch(7, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, stored, search)
+	setup.savedSearchRepo.AssertExpectations(t)
+}
+
+func TestSavedSearchService_CreateSavedSearch_Validation(t *testing.T) {
+	manyIngredients := make([]int, maxSavedSearchIngredients+1)
+	for i := range manyIngredients {
+		manyIngredients[i] = i + 1
+	}
+
+	tests := []struct {
+		name     string
+		req      models.SavedSearchRequest
+		expected error
+	}{
+		{"missing name", models.SavedSearchRequest{Name: "  ", IngredientIDs: []int{1}}, domain.ErrSavedSearchNameRequired},
+		{"long name", models.SavedSearchRequest{Name: strings.Repeat("a", 101), IngredientIDs: []int{1}}, domain.ErrSavedSearchNameRequired},
+		{"no ingredients", models.SavedSearchRequest{Name: "Search"}, domain.ErrInvalidSavedSearch},
+		{"too many ingredients", models.SavedSearchRequest{Name: "Search", IngredientIDs: manyIngredients}, domain.ErrInvalidSavedSearch},
+		{"duplicate ingredient", models.SavedSearchRequest{Name: "Search", IngredientIDs: []int{1, 1}}, domain.ErrInvalidSavedSearch},
+		{"invalid ingredient", models.SavedSearchRequest{Name: "Search", IngredientIDs: []int{0}}, domain.ErrIngredientNotFound},
+		{"zero max time", models.SavedSearchRequest{Name: "Search", IngredientIDs: []int{1}, MaxTimeMinutes: intPtr(0)}, domain.ErrInvalidSavedSearch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupSavedSearchServiceTest()
+
+			_, err := setup.service.CreateSavedSearch(7, tt.req)
+
+			assert.Equal(t, tt.expected, err)
+			setup.savedSearchRepo.AssertNotCalled(t, "Create")
+		})
+	}
+}
+
+func TestSavedSearchService_CreateSavedSearch_UnknownReferences(t *testing.T) {
+	setup := setupSavedSearchServiceTest()
+	setup.categoryRepo.On("Exists", 99).Return(false, nil)
+
+	_, err := setup.service.CreateSavedSearch(7, models.SavedSearchRequest{Name: "Search", IngredientIDs: []int{1}, CategoryID: intPtr(99)})
+	assert.Equal(t, domain.ErrCategoryNotFound, err)
+
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{1, 999}).Return([]int{999}, nil)
+
+	_, err = setup.service.CreateSavedSearch(7, models.SavedSearchRequest{Name: "Search", IngredientIDs: []int{1, 999}})
+	assert.Equal(t, domain.ErrIngredientNotFound, err)
+	setup.savedSearchRepo.AssertNotCalled(t, "Create")
+}
+
+func TestSavedSearchService_UpdateSavedSearch_OtherUsersSearch(t *testing.T) {
+	setup := setupSavedSearchServiceTest()
+	setup.ingredientRepo.On("FindMissingIngredientIDs", []int{1}).Return([]int{}, nil)
+	setup.savedSearchRepo.On("GetByID", 4).Return(&models.SavedSearch{ID: 4, UserID: 8}, nil)
+
+	_, err := setup.service.UpdateSavedSearch(asUser(7), 7, 4, models.SavedSearchRequest{Name: "Search", IngredientIDs: []int{1}})
+
+	assert.Equal(t, domain.ErrSavedSearchNotFound, err)
+	setup.savedSearchRepo.AssertNotCalled(t, "Update")
+}
+
+func TestSavedSearchService_DeleteSavedSearch(t *testing.T) {
+	setup := setupSavedSearchServiceTest()
+	setup.savedSearchRepo.On("GetByID", 4).Return(&models.SavedSearch{ID: 4, UserID: 7}, nil)
+	setup.savedSearchRepo.On("Delete", 4).Return(nil)
+	setup.savedSearchRepo.On("GetByID", 5).Return(nil, sql.ErrNoRows)
+
+	assert.NoError(t, setup.service.DeleteSavedSearch(asUser(7), 7, 4))
+	assert.Equal(t, domain.ErrSavedSearchNotFound, setup.service.DeleteSavedSearch(asUser(7), 7, 5))
+	setup.savedSearchRepo.AssertExpectations(t)
+}
+
+func TestSavedSearchService_CheckAlerts(t *testing.T) {
+	logging.Init("test")
+	setup := setupSavedSearchServiceTest()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-2 * time.Hour)
+	lastHour := now.Add(-time.Hour)
+
+	searches := []models.SavedSearch{
+		// Checked two hours ago: anything with chicken in the Chicken category
+		{ID: 1, UserID: 7, Name: "Chicken dinners", IngredientIDs: []int{2}, CategoryID: intPtr(2), Notify: true, LastCheckedAt: earlier},
+		// Checked an hour ago: quick onion recipes, but the notifier fails for user 9
+		{ID: 2, UserID: 9, Name: "Quick onions", IngredientIDs: []int{5}, MaxTimeMinutes: intPtr(30), Notify: true, LastCheckedAt: lastHour},
+		// Nothing new uses garlic
+		{ID: 3, UserID: 10, Name: "Garlic", IngredientIDs: []int{6}, Notify: true, LastCheckedAt: lastHour},
+		// Quick onion recipes for user 11, who wrote the only one
+		{ID: 4, UserID: 11, Name: "My onions", IngredientIDs: []int{5}, Notify: true, LastCheckedAt: lastHour},
+	}
+	recipes := []models.Recipe{
+		{ID: 20, UserID: 3, Name: "Roast chicken", CategoryID: intPtr(2), TotalTimeMinutes: intPtr(90), CreatedAt: earlier.Add(30 * time.Minute)},
+		{ID: 21, UserID: 3, Name: "Chicken salad", CategoryID: intPtr(4), TotalTimeMinutes: intPtr(15), CreatedAt: earlier.Add(45 * time.Minute)},
+		{ID: 22, UserID: 11, Name: "Onion soup", CategoryID: intPtr(4), TotalTimeMinutes: intPtr(25), CreatedAt: lastHour.Add(10 * time.Minute)},
+		// Created after this run started, so the next run reports it
+		{ID: 23, UserID: 3, Name: "Chicken stew", CategoryID: intPtr(2), CreatedAt: now.Add(time.Second)},
+	}
+	setup.savedSearchRepo.On("GetAlerting").Return(searches, nil)
+	setup.recipeRepo.On("GetCreatedSince", earlier).Return(recipes, nil)
+	setup.ingredientRepo.On("GetIngredientsForRecipes", []int{20, 21, 22, 23}).Return(map[int][]models.RecipeIngredient{
+		20: {{RecipeID: 20, IngredientID: 2}},
+		21: {{RecipeID: 21, IngredientID: 2}, {RecipeID: 21, IngredientID: 5}},
+		22: {{RecipeID: 22, IngredientID: 5}},
+		23: {{RecipeID: 23, IngredientID: 2}},
+	}, nil)
+	setup.notifier.failFor = map[int]bool{9: true}
+	setup.savedSearchRepo.On("MarkChecked", 1, now).Return(nil)
+	setup.savedSearchRepo.On("MarkChecked", 3, now).Return(nil)
+	setup.savedSearchRepo.On("MarkChecked", 4, now).Return(nil)
+
+	sent, err := setup.service.CheckAlerts(now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, setup.notifier.alerts, 1)
+	alert := setup.notifier.alerts[0]
+	assert.Equal(t, 7, alert.UserID)
+	assert.Equal(t, "Chicken dinners", alert.SearchName)
+	require.Len(t, alert.Recipes, 1)
+	assert.Equal(t, 20, alert.Recipes[0].ID)
+	// The failed alert is retried on the next run
+	setup.savedSearchRepo.AssertNotCalled(t, "MarkChecked", 2, now)
+	setup.savedSearchRepo.AssertExpectations(t)
+}
+
+func TestSavedSearchService_CheckAlerts_NothingToCheck(t *testing.T) {
+	setup := setupSavedSearchServiceTest()
+	setup.savedSearchRepo.On("GetAlerting").Return([]models.SavedSearch{}, nil)
+
+	sent, err := setup.service.CheckAlerts(time.Now())
+
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	setup.recipeRepo.AssertNotCalled(t, "GetCreatedSince")
+}
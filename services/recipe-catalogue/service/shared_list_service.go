@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+
+	"github.com/google/uuid"
+)
+
+// SharedListService shares grocery lists by link. The token is the only
+// credential: anyone who has it can read the list and check items off.
+type SharedListService interface {
+	ShareGroceryList(userID int, items []models.GroceryListItem) (*models.SharedShoppingList, error)
+	RevokeSharedList(ctx context.Context, userID int, token string) error
+
+	GetSharedList(token string) (*models.SharedShoppingList, error)
+	CheckItem(token string, position int, checked bool) (*models.SharedShoppingListChange, error)
+	// Watch delivers every change made to the list from now on until stop
+	// is called. The channel is closed when the list is revoked or the
+	// watcher falls too far behind; the client should then reload the list.
+	Watch(token string) (changes <-chan models.SharedShoppingListChange, stop func(), err error)
+}
+
+type sharedListService struct {
+	sharedListRepo repository.SharedListRepository
+	newToken       func() string
+	watchers       *hub[string, models.SharedShoppingListChange]
+}
+
+func NewSharedListService(sharedListRepo repository.SharedListRepository) SharedListService {
+	return &sharedListService{
+		sharedListRepo: sharedListRepo,
+		newToken:       uuid.NewString,
+		watchers:       newHub[string, models.SharedShoppingListChange](),
+	}
+}
+
+// ShareGroceryList keeps a snapshot of items, numbered in their order.
+func (s *sharedListService) ShareGroceryList(userID int, items []models.GroceryListItem) (*models.SharedShoppingList, error) {
+	if len(items) == 0 {
+		return nil, domain.ErrEmptyShoppingList
+	}
+
+	list := models.SharedShoppingList{
+		Token:  s.newToken(),
+		UserID: userID,
+		Items:  make([]models.SharedShoppingListItem, len(items)),
+	}
+	for i, item := range items {
+		list.Items[i] = models.SharedShoppingListItem{
+			Position:     i + 1,
+			IngredientID: item.IngredientID,
+			Name:         item.Ingredient.Name,
+			Category:     item.Ingredient.Category,
+			Quantity:     item.TotalQuantity,
+			Unit:         item.Unit,
+		}
+		if item.Purchase != nil {
+			note := item.Purchase.Note
+			list.Items[i].Note = &note
+		}
+	}
+
+	return s.sharedListRepo.Create(list)
+}
+
+// RevokeSharedList deletes the owner's list, which ends the link and
+// disconnects everyone watching it.
+func (s *sharedListService) RevokeSharedList(ctx context.Context, userID int, token string) error {
+	list, err := s.GetSharedList(token)
+	if err != nil {
+		return err
+	}
+	resource := policy.Resource{Type: sharedListResource, OwnerID: list.UserID}
+	if err := authorize(ctx, policy.Delete, resource, domain.ErrSharedListNotFound); err != nil {
+		return err
+	}
+
+	if err := s.sharedListRepo.Delete(token); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrSharedListNotFound
+		}
+		return err
+	}
+
+	s.watchers.closeKey(token)
+	return nil
+}
+
+func (s *sharedListService) GetSharedList(token string) (*models.SharedShoppingList, error) {
+	list, err := s.sharedListRepo.GetByToken(token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrSharedListNotFound
+		}
+		return nil, err
+	}
+	return list, nil
+}
+
+// CheckItem changes one item only, so people checking different items at the
+// same time never overwrite each other; on the same item the last one wins.
+func (s *sharedListService) CheckItem(token string, position int, checked bool) (*models.SharedShoppingListChange, error) {
+	change, err := s.sharedListRepo.SetItemChecked(token, position, checked)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return nil, err
+		}
+		if _, err := s.GetSharedList(token); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrSharedListItemNotFound
+	}
+
+	s.watchers.publish(token, *change)
+	return change, nil
+}
+
+func (s *sharedListService) Watch(token string) (<-chan models.SharedShoppingListChange, func(), error) {
+	if _, err := s.GetSharedList(token); err != nil {
+		return nil, nil, err
+	}
+
+	// Anyone with the link may watch, so the watcher is nobody in particular
+	changes, stop := s.watchers.watch(token, 0)
+	return changes, stop, nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"database/sql"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type sharedListServiceTestSetup struct {
+	service        *sharedListService
+	sharedListRepo *mocks.MockSharedListRepository
+}
+
+func setupSharedListServiceTest() *sharedListServiceTestSetup {
+	sharedListRepo := new(mocks.MockSharedListRepository)
+	svc := NewSharedListService(sharedListRepo).(*sharedListService)
+	svc.newToken = func() string { return "token-1" }
+
+	return &sharedListServiceTestSetup{service: svc, sharedListRepo: sharedListRepo}
+}
+
+func TestSharedListService_ShareGroceryList_NumbersItems(t *testing.T) {
+	setup := setupSharedListServiceTest()
+	vegetables := "Vegetables"
+	var list models.SharedShoppingList
+	setup.sharedListRepo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		list = args.Get(0).(models.SharedShoppingList)
+	}).Return(&models.SharedShoppingList{Token: "token-1"}, nil)
+
+	_, err := setup.service.ShareGroceryList(7, []models.GroceryListItem{
+		{IngredientID: 3, Ingredient: models.Ingredient{Name: "Onion", Category: &vegetables}, TotalQuantity: 2, Unit: "piece"},
+		{IngredientID: 5, Ingredient: models.Ingredient{Name: "Tomatoes"}, TotalQuantity: 800, Unit: "g",
+			Purchase: &models.PackPurchase{Note: "Buy 2 × 400 g can"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", list.Token)
+	assert.Equal(t, 7, list.UserID)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, 1, list.Items[0].Position)
+	assert.Equal(t, "Vegetables", *list.Items[0].Category)
+	assert.Equal(t, 2, list.Items[1].Position)
+	assert.Equal(t, "Buy 2 × 400 g can", *list.Items[1].Note)
+}
+
+func TestSharedListService_ShareGroceryList_Empty(t *testing.T) {
+	setup := setupSharedListServiceTest()
+
+	_, err := setup.service.ShareGroceryList(7, nil)
+
+	assert.Equal(t, domain.ErrEmptyShoppingList, err)
+	setup.sharedListRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestSharedListService_CheckItem_ReachesWatchers(t *testing.T) {
+	setup := setupSharedListServiceTest()
+	setup.sharedListRepo.On("GetByToken", "token-1").Return(&models.SharedShoppingList{Token: "token-1"}, nil)
+	change := &models.SharedShoppingListChange{Version: 4, Item: models.SharedShoppingListItem{Position: 2, Checked: true}}
+	setup.sharedListRepo.On("SetItemChecked", "token-1", 2, true).Return(change, nil)
+
+	changes, stop, err := setup.service.Watch("token-1")
+	require.NoError(t, err)
+	defer stop()
+
+	_, err = setup.service.CheckItem("token-1", 2, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, *change, <-changes)
+}
+
+func TestSharedListService_CheckItem_SlowWatcherIsDisconnected(t *testing.T) {
+	setup := setupSharedListServiceTest()
+	setup.sharedListRepo.On("GetByToken", "token-1").Return(&models.SharedShoppingList{Token: "token-1"}, nil)
+	setup.sharedListRepo.On("SetItemChecked", "token-1", 1, true).Return(&models.SharedShoppingListChange{Version: 1}, nil)
+
+	changes, stop, err := setup.service.Watch("token-1")
+	require.NoError(t, err)
+	defer stop()
+
+	for i := 0; i <= watcherBuffer; i++ {
+		_, err := setup.service.CheckItem("token-1", 1, true)
+		require.NoError(t, err)
+	}
+
+	received := 0
+	for range changes {
+		received++
+	}
+	assert.Equal(t, watcherBuffer, received)
+}
+
+func TestSharedListService_CheckItem_NotFound(t *testing.T) {
+	setup := setupSharedListServiceTest()
+	setup.sharedListRepo.On("SetItemChecked", "token-1", 9, true).Return(nil, sql.ErrNoRows)
+	setup.sharedListRepo.On("SetItemChecked", "gone", 1, true).Return(nil, sql.ErrNoRows)
+	setup.sharedListRepo.On("GetByToken", "token-1").Return(&models.SharedShoppingList{Token: "token-1"}, nil)
+	setup.sharedListRepo.On("GetByToken", "gone").Return(nil, sql.ErrNoRows)
+
+	_, err := setup.service.CheckItem("token-1", 9, true)
+	assert.Equal(t, domain.ErrSharedListItemNotFound, err)
+
+	_, err = setup.service.CheckItem("gone", 1, true)
+	assert.Equal(t, domain.ErrSharedListNotFound, err)
+}
+
+func TestSharedListService_RevokeSharedList_ClosesWatchers(t *testing.T) {
+	setup := setupSharedListServiceTest()
+	setup.sharedListRepo.On("GetByToken", "token-1").Return(&models.SharedShoppingList{Token: "token-1", UserID: 7}, nil)
+	setup.sharedListRepo.On("Delete", "token-1").Return(nil)
+
+	changes, stop, err := setup.service.Watch("token-1")
+	require.NoError(t, err)
+	defer stop()
+
+	assert.Equal(t, domain.ErrSharedListNotFound, setup.service.RevokeSharedList(asUser(8), 8, "token-1"))
+	require.NoError(t, setup.service.RevokeSharedList(asUser(7), 7, "token-1"))
+
+	_, open := <-changes
+	assert.False(t, open)
+	setup.sharedListRepo.AssertNumberOfCalls(t, "Delete", 1)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"database/sql"
+
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/events"
+	"meal-prep/shared/models"
+)
+
+type SocialService interface {
+	Follow(userID, targetID int) error
+	Unfollow(userID, targetID int) error
+	GetFollowing(userID int) ([]models.Follow, error)
+	GetFollowers(userID int) ([]models.Follow, error)
+	GetFeed(userID int, params models.PaginationParams) ([]models.Activity, models.PaginationMeta, error)
+
+	// RecordActivity is subscribed to the domain event bus and turns events
+	// into feed entries.
+	RecordActivity(event events.Event) error
+}
+
+type socialService struct {
+	followRepo   repository.FollowRepository
+	activityRepo repository.ActivityRepository
+}
+
+func NewSocialService(followRepo repository.FollowRepository, activityRepo repository.ActivityRepository) SocialService {
+	return &socialService{
+		followRepo:   followRepo,
+		activityRepo: activityRepo,
+	}
+}
+
+// Follow does not check that the target exists: users live in the auth
+// service, and following an unknown user only ever yields an empty feed.
+func (s *socialService) Follow(userID, targetID int) error {
+	if err := validateFollowTarget(userID, targetID); err != nil {
+		return err
+	}
+	return s.followRepo.Follow(userID, targetID)
+}
+
+func (s *socialService) Unfollow(userID, targetID int) error {
+	if err := validateFollowTarget(userID, targetID); err != nil {
+		return err
+	}
+
+	err := s.followRepo.Unfollow(userID, targetID)
+	if err == sql.ErrNoRows {
+		return domain.ErrNotFollowing
+	}
+	return err
+}
+
+func (s *socialService) GetFollowing(userID int) ([]models.Follow, error) {
+	return s.followRepo.GetFollowing(userID)
+}
+
+func (s *socialService) GetFollowers(userID int) ([]models.Follow, error) {
+	return s.followRepo.GetFollowers(userID)
+}
+
+func (s *socialService) GetFeed(userID int, params models.PaginationParams) ([]models.Activity, models.PaginationMeta, error) {
+	activities, total, err := s.activityRepo.GetFeed(userID, params)
+	if err != nil {
+		return nil, models.PaginationMeta{}, err
+	}
+	return activities, models.NewPaginationMeta(params, total), nil
+}
+
+func (s *socialService) RecordActivity(event events.Event) error {
+	switch event.Type {
+	case events.RecipePublished:
+		return s.activityRepo.Record(models.Activity{
+			UserID:    event.UserID,
+			Type:      event.Type,
+			RecipeID:  event.SubjectID,
+			CreatedAt: event.OccurredAt,
+		})
+	default:
+		return nil
+	}
+}
+
+func validateFollowTarget(userID, targetID int) error {
+	if targetID <= 0 {
+		return domain.ErrInvalidFollowTarget
+	}
+	if targetID == userID {
+		return domain.ErrCannotFollowSelf
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"database/sql"
+	"meal-prep/services/recipe-catalogue/domain"
+	"meal-prep/services/recipe-catalogue/service/mocks"
+	"meal-prep/shared/events"
+	"meal-prep/shared/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type socialServiceTestSetup struct {
+	service      SocialService
+	followRepo   *mocks.MockFollowRepository
+	activityRepo *mocks.MockActivityRepository
+}
+
+func setupSocialServiceTest() *socialServiceTestSetup {
+	followRepo := new(mocks.MockFollowRepository)
+	activityRepo := new(mocks.MockActivityRepository)
+
+	return &socialServiceTestSetup{
+		service:      NewSocialService(followRepo, activityRepo),
+		followRepo:   followRepo,
+		activityRepo: activityRepo,
+	}
+}
+
+func TestSocialService_Follow(t *testing.T) {
+	setup := setupSocialServiceTest()
+	setup.followRepo.On("Follow", 1, 2).Return(nil)
+
+	err := setup.service.Follow(1, 2)
+
+	assert.NoError(t, err)
+	setup.followRepo.AssertExpectations(t)
+}
+
+func TestSocialService_Follow_InvalidTargets(t *testing.T) {
+	setup := setupSocialServiceTest()
+
+	assert.Equal(t, domain.ErrCannotFollowSelf, setup.service.Follow(1, 1))
+	assert.Equal(t, domain.ErrInvalidFollowTarget, setup.service.Follow(1, 0))
+	setup.followRepo.AssertNotCalled(t, "Follow")
+}
+
+func TestSocialService_Unfollow_NotFollowing(t *testing.T) {
+	setup := setupSocialServiceTest()
+	setup.followRepo.On("Unfollow", 1, 2).Return(sql.ErrNoRows)
+
+	err := setup.service.Unfollow(1, 2)
+
+	assert.Equal(t, domain.ErrNotFollowing, err)
+}
+
+func TestSocialService_GetFeed(t *testing.T) {
+	setup := setupSocialServiceTest()
+	params := models.PaginationParams{Page: 2, PerPage: 10}
+	feed := []models.Activity{{ID: 4, UserID: 2, Type: events.RecipePublished, RecipeID: 9}}
+	setup.activityRepo.On("GetFeed", 1, params).Return(feed, 11, nil)
+
+	activities, meta, err := setup.service.GetFeed(1, params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, feed, activities)
+	assert.Equal(t, 2, meta.TotalPages)
+}
+
+func TestSocialService_RecordActivity(t *testing.T) {
+	setup := setupSocialServiceTest()
+	occurredAt := time.Now()
+	setup.activityRepo.On("Record", models.Activity{
+		UserID: 2, Type: events.RecipePublished, RecipeID: 9, CreatedAt: occurredAt,
+	}).Return(nil)
+
+	err := setup.service.RecordActivity(events.Event{Type: events.RecipePublished, UserID: 2, SubjectID: 9, OccurredAt: occurredAt})
+	assert.NoError(t, err)
+
+	// Events the feed does not show are ignored
+	err = setup.service.RecordActivity(events.Event{Type: "ingredient.merged", UserID: 2, SubjectID: 9})
+	assert.NoError(t, err)
+	setup.activityRepo.AssertNumberOfCalls(t, "Record", 1)
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	store := NewLocalStore(t.TempDir(), "http://localhost:8000/images")
+
+	require.NoError(t, store.Put("recipes/3/photo.jpg", []byte("jpeg"), "image/jpeg"))
+	data, err := store.Get("recipes/3/photo.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("jpeg"), data)
+	assert.Equal(t, "http://localhost:8000/images/recipes/3/photo.jpg", store.URL("recipes/3/photo.jpg"))
+
+	require.NoError(t, store.Delete("recipes/3/photo.jpg"))
+	_, err = store.Get("recipes/3/photo.jpg")
+	assert.Equal(t, ErrNotFound, err)
+	assert.NoError(t, store.Delete("recipes/3/photo.jpg"))
+}
+
+func TestLocalStore_RejectsKeysOutsideTheDirectory(t *testing.T) {
+	store := NewLocalStore(t.TempDir(), "")
+
+	for _, key := range []string{"", "../secret", "recipes/../../secret", "/etc/passwd", "recipes//photo.jpg", `recipes\photo.jpg`} {
+		_, err := store.Get(key)
+		assert.Equal(t, ErrInvalidKey, err, key)
+		assert.Equal(t, ErrInvalidKey, store.Put(key, nil, ""), key)
+	}
+}
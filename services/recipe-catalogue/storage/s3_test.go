@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3Store_SignsRequests(t *testing.T) {
+	store := NewS3Store(S3Config{
+		Region: "eu-west-1", Bucket: "meal-prep-images", AccessKeyID: "AKID", SecretAccessKey: "secret",
+	}, "", http.DefaultClient)
+	store.now = func() time.Time { return time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC) }
+
+	req, err := store.request(http.MethodPut, "recipes/3/a b.jpg", []byte("hello"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://s3.eu-west-1.amazonaws.com/meal-prep-images/recipes/3/a%20b.jpg", req.URL.String())
+	assert.Equal(t, "20260314T093000Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKID/20260314/eu-west-1/s3/aws4_request, "+
+		"SignedHeaders=host;x-amz-content-sha256;x-amz-date, "+
+		"Signature=5ef4be06c0919a2f2924495efb67800a1b2c9feec0685f02417da2e306470856",
+		req.Header.Get("Authorization"))
+}
+
+// fakeS3 keeps objects in memory, keyed by request path.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.Path], _ = io.ReadAll(r.Body)
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Store_PutGetDelete(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store := NewS3Store(S3Config{
+		Endpoint: server.URL, Region: "eu-west-1", Bucket: "images", AccessKeyID: "AKID", SecretAccessKey: "secret",
+	}, "https://images.example.com", server.Client())
+
+	require.NoError(t, store.Put("recipes/3/photo.jpg", []byte("jpeg"), "image/jpeg"))
+	assert.Contains(t, fake.objects, "/images/recipes/3/photo.jpg")
+
+	data, err := store.Get("recipes/3/photo.jpg")
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal([]byte("jpeg"), data))
+	assert.Equal(t, "https://images.example.com/recipes/3/photo.jpg", store.URL("recipes/3/photo.jpg"))
+
+	require.NoError(t, store.Delete("recipes/3/photo.jpg"))
+	_, err = store.Get("recipes/3/photo.jpg")
+	assert.Equal(t, ErrNotFound, err)
+}
@@ -0,0 +1,83 @@
+// Package storage keeps uploaded files, such as recipe photos, on local disk
+// or in an S3 bucket.
+package storage
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"meal-prep/shared/metrics"
+)
+
+var (
+	ErrNotFound   = errors.New("stored object not found")
+	ErrInvalidKey = errors.New("invalid storage key")
+)
+
+// Store saves objects under slash-separated keys such as
+// "recipes/3/9b2f.jpg".
+type Store interface {
+	Put(key string, data []byte, contentType string) error
+	// Get returns ErrNotFound for keys nothing was stored under
+	Get(key string) ([]byte, error)
+	// Delete succeeds for keys nothing was stored under
+	Delete(key string) error
+	// URL is where clients fetch the object from
+	URL(key string) string
+}
+
+const (
+	defaultImageDir = "data/images"
+	s3Timeout       = 30 * time.Second
+)
+
+// FromEnv picks the store named by IMAGE_STORAGE. "local", the default,
+// writes under IMAGE_DIR; "s3" uses S3_BUCKET in S3_REGION, signing with
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY. S3_ENDPOINT points it at an
+// S3-compatible service instead of AWS. Objects are linked at
+// IMAGE_PUBLIC_URL, or at publicURL when that is unset.
+func FromEnv(publicURL string) (Store, error) {
+	if value := strings.TrimRight(os.Getenv("IMAGE_PUBLIC_URL"), "/"); value != "" {
+		publicURL = value
+	}
+
+	switch kind := os.Getenv("IMAGE_STORAGE"); kind {
+	case "", "local":
+		dir := os.Getenv("IMAGE_DIR")
+		if dir == "" {
+			dir = defaultImageDir
+		}
+		return NewLocalStore(dir, publicURL), nil
+	case "s3":
+		cfg := S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		}
+		if cfg.Bucket == "" || cfg.Region == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			return nil, errors.New("S3 image storage needs S3_BUCKET, S3_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+		}
+		return NewS3Store(cfg, publicURL, &http.Client{Timeout: s3Timeout, Transport: metrics.Transport("s3", nil)}), nil
+	default:
+		return nil, errors.New("unknown IMAGE_STORAGE " + kind + ", expected local or s3")
+	}
+}
+
+// ValidKey reports whether key is a relative path without empty, "." or
+// ".." segments, so it cannot escape the store.
+func ValidKey(key string) bool {
+	if key == "" || strings.ContainsAny(key, "\\\x00") {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
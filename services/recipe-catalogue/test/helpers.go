@@ -3,6 +3,7 @@ package test
 import (
 	"context"
 	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
 	"net/http"
 )
 
@@ -14,3 +15,13 @@ func AddAuthContext(req *http.Request, userID int, email string) *http.Request {
 	ctx := context.WithValue(req.Context(), middleware.UserCtxKey, userCtx)
 	return req.WithContext(ctx)
 }
+
+func AddAdminContext(req *http.Request, userID int, email string) *http.Request {
+	userCtx := &middleware.UserContext{
+		UserID: userID,
+		Email:  email,
+		Role:   models.RoleAdmin,
+	}
+	ctx := context.WithValue(req.Context(), middleware.UserCtxKey, userCtx)
+	return req.WithContext(ctx)
+}
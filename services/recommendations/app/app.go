@@ -0,0 +1,132 @@
+// Package app wires the recommendations service's repositories, services,
+// background jobs and routes into one handler, which
+// services/recommendations serves on its own port and cmd/allinone mounts
+// beside the other services.
+package app
+
+import (
+	"context"
+	"net/http"
+
+	"meal-prep/services/recommendations/handlers"
+	"meal-prep/services/recommendations/repository"
+	"meal-prep/services/recommendations/service"
+	"meal-prep/shared/database"
+	"meal-prep/shared/metrics"
+	"meal-prep/shared/middleware"
+
+	"github.com/gorilla/mux"
+)
+
+// New returns the recommendations service's routes on db, which must be
+// PostgreSQL. Its background jobs run until ctx is cancelled.
+func New(ctx context.Context, db *database.DB) http.Handler {
+	// Keep the stats views behind /recommendations/popular and /public fresh
+	go db.RunViewRefresher(ctx, database.ViewRefreshIntervalFromEnv(),
+		"recommendations.recipe_popularity", "recommendations.recipe_seasonality")
+
+	// Dependency injection chain
+	recRepo := repository.NewRecommendationRepository(db)
+	recService := service.NewRecommendationService(recRepo, service.ScoringConfigFromEnv())
+	recHandler := handlers.NewRecommendationHandler(recService)
+	digestService := service.NewDigestService(recRepo, service.NewLogNotifier())
+	digestHandler := handlers.NewDigestHandler(digestService)
+	wasteHandler := handlers.NewWasteHandler(service.NewWasteService(recRepo))
+	freezerHandler := handlers.NewFreezerHandler(service.NewFreezerService(recRepo))
+	slateHandler := handlers.NewSlateHandler(service.NewSlateService(recRepo))
+
+	// Send weekly digests to subscribers as they fall due
+	go service.RunDigestScheduler(ctx, digestService, service.DigestCheckIntervalFromEnv())
+
+	// Recompute which recipes the same people cook for hybrid recommendations
+	go service.RunSimilarityRefresher(ctx, recRepo, service.SimilarityRefreshIntervalFromEnv())
+
+	// Request and connection pool metrics for Prometheus to scrape
+	serviceMetrics := metrics.New("recommendations-service")
+	serviceMetrics.RegisterDB(db, "recommendations")
+
+	// Routes - require authentication
+	router := mux.NewRouter()
+	router.Use(middleware.TracingMiddleware("recommendations-service"))
+	router.Use(middleware.LoggingMiddleware("recommendations-service"))
+	router.Use(serviceMetrics.Middleware)
+	router.Use(middleware.ExtractUserFromGatewayHeaders)
+	// Count requests against the gateway's recommendations quota and warn
+	// users nearing the limit; the recipe catalogue reads the counts to serve
+	// /me/usage
+	usageMeter := middleware.NewUsageMeter("recommendations",
+		middleware.UsageLimit("RECOMMENDATIONS_RATE_LIMIT_PER_MINUTE", 50),
+		middleware.UsageLimit("RECOMMENDATIONS_RATE_LIMIT_PER_HOUR", 2000)).
+		WarnAt(middleware.UsageWarningThresholdFromEnv(), middleware.NewLogUsageWarningNotifier())
+	router.Use(usageMeter.Middleware)
+
+	router.HandleFunc("/recommendations", recHandler.GetRecommendations).Methods("GET")
+	router.HandleFunc("/preferences", recHandler.GetUserPreferences).Methods("GET")
+	router.HandleFunc("/preferences", recHandler.UpdateUserPreferences).Methods("PUT")
+	router.HandleFunc("/cooking", recHandler.LogCooking).Methods("POST")
+	router.HandleFunc("/cooking/history", recHandler.GetCookingHistory).Methods("GET")
+	router.HandleFunc("/cooking/history/calendar", recHandler.GetCookingCalendar).Methods("GET")
+	router.HandleFunc("/recommendations/popular", recHandler.GetPopularRecipes).Methods("GET")
+	router.HandleFunc("/recommendations/slates/{id:[0-9]+}/impressions", slateHandler.RecordImpressions).Methods("POST")
+	router.HandleFunc("/recommendations/slates/{id:[0-9]+}/clicks", slateHandler.RecordClick).Methods("POST")
+	router.Handle("/recommendations/engagement", middleware.RequireAdmin(http.HandlerFunc(slateHandler.GetEngagement))).Methods("GET")
+	router.HandleFunc("/cooking/history/{id:[0-9]+}/photo", recHandler.SetCookingPhoto).Methods("PUT")
+	router.HandleFunc("/cooking/history/{id:[0-9]+}/photo", recHandler.RemoveCookingPhoto).Methods("DELETE")
+	router.HandleFunc("/digest/subscription", digestHandler.GetSubscription).Methods("GET")
+	router.Handle("/digest/subscription", middleware.Locale(nil)(http.HandlerFunc(digestHandler.Subscribe))).Methods("PUT")
+	router.HandleFunc("/digest/subscription", digestHandler.Unsubscribe).Methods("DELETE")
+	router.HandleFunc("/digest/preview", digestHandler.PreviewDigest).Methods("GET")
+	router.HandleFunc("/waste", wasteHandler.LogWaste).Methods("POST")
+	router.HandleFunc("/waste", wasteHandler.GetWasteLog).Methods("GET")
+	router.HandleFunc("/waste/summary", wasteHandler.GetMonthlySummary).Methods("GET")
+	router.HandleFunc("/waste/{id:[0-9]+}", wasteHandler.DeleteWasteEntry).Methods("DELETE")
+	router.HandleFunc("/freezer", freezerHandler.AddItem).Methods("POST")
+	router.HandleFunc("/freezer", freezerHandler.GetInventory).Methods("GET")
+	router.HandleFunc("/freezer/{id:[0-9]+}", freezerHandler.UpdatePortions).Methods("PUT")
+	router.HandleFunc("/freezer/{id:[0-9]+}", freezerHandler.RemoveItem).Methods("DELETE")
+
+	// Photo moderation - admins only
+	admin := router.PathPrefix("/cooking/photos").Subrouter()
+	admin.Use(middleware.RequireAdmin)
+	admin.HandleFunc("/pending", recHandler.GetPendingPhotos).Methods("GET")
+	admin.HandleFunc("/{id:[0-9]+}/review", recHandler.ReviewCookingPhoto).Methods("PUT")
+
+	// Approved community photos are shown on public recipe pages, and the
+	// landing page shows non-personalized picks to anonymous visitors
+	publicRouter := mux.NewRouter()
+	publicRouter.Use(middleware.TracingMiddleware("recommendations-service"))
+	publicRouter.Use(middleware.LoggingMiddleware("recommendations-service"))
+	publicRouter.Use(serviceMetrics.Middleware)
+	publicRouter.HandleFunc("/community-photos", recHandler.GetCommunityPhotos).Methods("GET")
+	publicRouter.HandleFunc("/recommendations/public", recHandler.GetPublicRecommendations).Methods("GET")
+
+	// Health check without auth
+	healthRouter := mux.NewRouter()
+	healthRouter.Use(middleware.TracingMiddleware("recommendations-service"))
+	healthRouter.Use(middleware.LoggingMiddleware("recommendations-service"))
+	healthRouter.Use(serviceMetrics.Middleware)
+	healthRouter.HandleFunc("/health", healthCheck).Methods("GET")
+	healthRouter.Handle("/metrics", serviceMetrics.Handler()).Methods("GET")
+	healthRouter.Handle("/debug/queries", db.SlowQueriesHandler(10)).Methods("GET")
+	healthRouter.Handle("/internal/usage", usageMeter.Handler()).Methods("GET")
+	healthRouter.HandleFunc("/internal/recommendations", recHandler.GetInternalRecommendations).Methods("GET")
+
+	// Combine routers
+	mainRouter := mux.NewRouter()
+	mainRouter.PathPrefix("/health").Handler(healthRouter)
+	mainRouter.Path("/metrics").Handler(healthRouter)
+	mainRouter.PathPrefix("/debug").Handler(healthRouter)
+	mainRouter.PathPrefix("/internal").Handler(healthRouter)
+	mainRouter.PathPrefix("/community-photos").Handler(publicRouter)
+	mainRouter.Path("/recommendations/public").Handler(publicRouter)
+	mainRouter.PathPrefix("/").Handler(router)
+	return mainRouter
+}
+
+func healthCheck(w http.ResponseWriter, r *http.Request) {
+	//logging.WithContext(r.Context()).Debug("Health check requested")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status": "healthy", "service": "recommendations"}`))
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"meal-prep/services/recommendations/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+)
+
+type DigestHandler struct {
+	digestService service.DigestService
+}
+
+func NewDigestHandler(digestService service.DigestService) *DigestHandler {
+	return &DigestHandler{digestService: digestService}
+}
+
+func (h *DigestHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	sub, err := h.digestService.GetSubscription(user.UserID)
+	if err != nil {
+		switch err {
+		case service.ErrDigestNotSubscribed:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch digest subscription", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, sub, http.StatusOK)
+}
+
+func (h *DigestHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	// The body is optional; without a locale the digest is in the request's
+	// language if there is a digest in it, otherwise in English
+	var req models.SubscribeDigestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Locale == "" {
+		req.Locale = service.PreferredDigestLocale(middleware.LocalesFromContext(r.Context()))
+	}
+
+	sub, err := h.digestService.Subscribe(user.UserID, req)
+	if err != nil {
+		switch err {
+		case service.ErrUnsupportedLocale:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to subscribe to digest", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, sub, http.StatusOK)
+}
+
+func (h *DigestHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.digestService.Unsubscribe(user.UserID); err != nil {
+		switch err {
+		case service.ErrDigestNotSubscribed:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to unsubscribe from digest", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Unsubscribed from the weekly digest"}, http.StatusNoContent)
+}
+
+func (h *DigestHandler) PreviewDigest(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	msg, err := h.digestService.Preview(user.UserID, r.URL.Query().Get("locale"))
+	if err != nil {
+		switch err {
+		case service.ErrUnsupportedLocale:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to render digest", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if r.URL.Query().Get("format") == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, msg.Subject+"\n\n"+msg.Body)
+		return
+	}
+
+	models.WriteSuccessResponse(w, msg, http.StatusOK)
+}
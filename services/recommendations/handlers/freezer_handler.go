@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"meal-prep/services/recommendations/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+type FreezerHandler struct {
+	freezerService service.FreezerService
+}
+
+func NewFreezerHandler(freezerService service.FreezerService) *FreezerHandler {
+	return &FreezerHandler{freezerService: freezerService}
+}
+
+func (h *FreezerHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.AddFreezerItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.freezerService.AddItem(user.UserID, req)
+	if err != nil {
+		switch err {
+		case service.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case service.ErrInvalidFreezerPortions, service.ErrInvalidFrozenOn, service.ErrInvalidEatBefore:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to add freezer item", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, item, http.StatusCreated)
+}
+
+func (h *FreezerHandler) GetInventory(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	items, err := h.freezerService.GetInventory(user.UserID)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch freezer inventory", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, items, http.StatusOK)
+}
+
+func (h *FreezerHandler) UpdatePortions(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	itemID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid freezer item ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateFreezerItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.freezerService.UpdatePortions(user.UserID, itemID, req); err != nil {
+		switch err {
+		case service.ErrFreezerItemNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case service.ErrInvalidFreezerPortions:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to update freezer item", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Freezer item updated successfully"}, http.StatusOK)
+}
+
+func (h *FreezerHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	itemID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid freezer item ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.freezerService.RemoveItem(user.UserID, itemID); err != nil {
+		switch err {
+		case service.ErrFreezerItemNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to remove freezer item", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Freezer item removed successfully"}, http.StatusNoContent)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -9,6 +10,8 @@ import (
 	"meal-prep/services/recommendations/service"
 	"meal-prep/shared/middleware"
 	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
 )
 
 type RecommendationHandler struct {
@@ -37,7 +40,7 @@ func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *htt
 		}
 	}
 
-	recommendations, err := h.recService.GetRecommendations(user.UserID, req)
+	recommendations, err := h.recService.ServeRecommendations(r.Context(), user.UserID, req)
 	if err != nil {
 		switch err {
 		case service.ErrPreferencesNotSet:
@@ -55,6 +58,37 @@ func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *htt
 	models.WriteSuccessResponse(w, recommendations, http.StatusOK)
 }
 
+// GetInternalRecommendations serves hybrid recommendations for the user named
+// in the query to other services. The recipe catalogue uses it to fill
+// generated meal plans. Like the usage endpoint, the response is written bare.
+func (h *RecommendationHandler) GetInternalRecommendations(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(r.URL.Query().Get("user_id"))
+	if err != nil || userID < 1 {
+		models.WriteErrorResponse(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	req := models.RecommendationRequest{Algorithm: service.AlgorithmHybrid}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			req.Limit = limit
+		}
+	}
+
+	recommendations, err := h.recService.GetRecommendations(r.Context(), userID, req)
+	if err != nil {
+		if err == service.ErrInvalidLimit {
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		models.WriteErrorResponse(w, "Failed to generate recommendations", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recommendations)
+}
+
 func (h *RecommendationHandler) GetUserPreferences(w http.ResponseWriter, r *http.Request) {
 	user, ok := middleware.GetUserFromGatewayContext(r.Context())
 	if !ok {
@@ -68,6 +102,7 @@ func (h *RecommendationHandler) GetUserPreferences(w http.ResponseWriter, r *htt
 		return
 	}
 
+	models.SetETag(w, preferences.Version)
 	models.WriteSuccessResponse(w, preferences, http.StatusOK)
 }
 
@@ -90,14 +125,34 @@ func (h *RecommendationHandler) UpdateUserPreferences(w http.ResponseWriter, r *
 
 	log.Printf("INFO: Preferences request: %+v", req)
 
+	// A version in the body takes precedence over If-Match
+	ifMatch, ok := models.IfMatchVersion(r)
+	if !ok {
+		models.WriteErrorResponse(w, "Invalid If-Match header", http.StatusBadRequest)
+		return
+	}
+	if req.Version == nil {
+		req.Version = ifMatch
+	}
+
 	preferences, err := h.recService.UpdateUserPreferences(user.UserID, req)
 	if err != nil {
+		var conflict *models.VersionConflictError
+		if errors.As(err, &conflict) {
+			models.WriteVersionConflictResponse(w, conflict.CurrentVersion)
+			return
+		}
+		if err == service.ErrInvalidDiet || err == service.ErrInvalidAllergens {
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		log.Printf("ERROR: UpdateUserPreferences failed: %v", err)
 		models.WriteErrorResponse(w, "Failed to update preferences", http.StatusInternalServerError)
 		return
 	}
 
 	log.Printf("INFO: Preferences updated successfully: %+v", preferences)
+	models.SetETag(w, preferences.Version)
 	models.WriteSuccessResponse(w, preferences, http.StatusOK)
 }
 
@@ -121,6 +176,10 @@ func (h *RecommendationHandler) LogCooking(w http.ResponseWriter, r *http.Reques
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		case service.ErrInvalidRating:
 			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case service.ErrInvalidPhotoURL:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case service.ErrInvalidPhotoCaption:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		default:
 			models.WriteErrorResponse(w, "Failed to log cooking", http.StatusInternalServerError)
 		}
@@ -153,3 +212,209 @@ func (h *RecommendationHandler) GetCookingHistory(w http.ResponseWriter, r *http
 	models.WriteSuccessResponse(w, history, http.StatusOK)
 }
 
+func (h *RecommendationHandler) GetCookingCalendar(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	calendar, err := h.recService.GetCookingCalendar(user.UserID, r.URL.Query().Get("month"))
+	if err != nil {
+		switch err {
+		case service.ErrInvalidMonth:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch cooking calendar", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, calendar, http.StatusOK)
+}
+
+func (h *RecommendationHandler) GetPopularRecipes(w http.ResponseWriter, r *http.Request) {
+	if _, ok := middleware.GetUserFromGatewayContext(r.Context()); !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+
+	popular, err := h.recService.GetPopularRecipes(limit)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch popular recipes", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, popular, http.StatusOK)
+}
+
+// GetPublicRecommendations needs no authentication: the same list is shown to
+// every visitor, so shared caches may keep it for a few minutes.
+func (h *RecommendationHandler) GetPublicRecommendations(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+
+	recipes, err := h.recService.GetPublicRecommendations(limit)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch recommendations", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	models.WriteSuccessResponse(w, recipes, http.StatusOK)
+}
+
+func (h *RecommendationHandler) SetCookingPhoto(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	cookingID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid cooking entry ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CookingPhotoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	err = h.recService.SetCookingPhoto(user.UserID, cookingID, req)
+	if err != nil {
+		switch err {
+		case service.ErrCookingEntryNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case service.ErrInvalidPhotoURL:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case service.ErrInvalidPhotoCaption:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to save cooking photo", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Cooking photo saved successfully"}, http.StatusOK)
+}
+
+func (h *RecommendationHandler) RemoveCookingPhoto(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	cookingID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid cooking entry ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.recService.RemoveCookingPhoto(user.UserID, cookingID)
+	if err != nil {
+		switch err {
+		case service.ErrCookingEntryNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to remove cooking photo", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Cooking photo removed successfully"}, http.StatusNoContent)
+}
+
+// GetCommunityPhotos is public: it backs the photo strip on recipe pages and
+// only ever returns approved photos.
+func (h *RecommendationHandler) GetCommunityPhotos(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := strconv.Atoi(r.URL.Query().Get("recipe_id"))
+	if err != nil {
+		models.WriteErrorResponse(w, "recipe_id query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+
+	photos, err := h.recService.GetCommunityPhotos(recipeID, limit)
+	if err != nil {
+		switch err {
+		case service.ErrRecipeNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to fetch community photos", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, photos, http.StatusOK)
+}
+
+// GetPendingPhotos lists the moderation queue. It must be mounted behind
+// middleware.RequireAdmin.
+func (h *RecommendationHandler) GetPendingPhotos(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+
+	pending, err := h.recService.GetPendingPhotos(limit)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch pending photos", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, pending, http.StatusOK)
+}
+
+// ReviewCookingPhoto approves or rejects a shared photo. It must be mounted
+// behind middleware.RequireAdmin.
+func (h *RecommendationHandler) ReviewCookingPhoto(w http.ResponseWriter, r *http.Request) {
+	cookingID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid cooking entry ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ReviewCookingPhotoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	err = h.recService.ReviewPhoto(cookingID, req)
+	if err != nil {
+		switch err {
+		case service.ErrCookingPhotoNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case service.ErrInvalidPhotoStatus:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to review cooking photo", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Cooking photo reviewed successfully"}, http.StatusOK)
+}
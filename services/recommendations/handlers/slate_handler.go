@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"meal-prep/services/recommendations/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+// SlateHandler tracks what users do with the recommendations served to them.
+type SlateHandler struct {
+	slateService service.SlateService
+}
+
+func NewSlateHandler(slateService service.SlateService) *SlateHandler {
+	return &SlateHandler{slateService: slateService}
+}
+
+func (h *SlateHandler) RecordImpressions(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	slateID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid slate ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SlateImpressionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	err = h.slateService.RecordImpressions(user.UserID, slateID, req)
+	if err != nil {
+		writeSlateError(w, err, "Failed to record impressions")
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Impressions recorded"}, http.StatusNoContent)
+}
+
+func (h *SlateHandler) RecordClick(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	slateID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid slate ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SlateClickRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	err = h.slateService.RecordClick(user.UserID, slateID, req)
+	if err != nil {
+		writeSlateError(w, err, "Failed to record click")
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Click recorded"}, http.StatusNoContent)
+}
+
+// GetEngagement reports click-through rates by algorithm. It must be mounted
+// behind middleware.RequireAdmin.
+func (h *SlateHandler) GetEngagement(w http.ResponseWriter, r *http.Request) {
+	days := 0
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		var err error
+		if days, err = strconv.Atoi(daysStr); err != nil {
+			models.WriteErrorResponse(w, service.ErrInvalidEngagementDays.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	engagement, err := h.slateService.GetEngagement(days)
+	if err != nil {
+		writeSlateError(w, err, "Failed to fetch recommendation engagement")
+		return
+	}
+
+	models.WriteSuccessResponse(w, engagement, http.StatusOK)
+}
+
+func writeSlateError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case service.ErrSlateNotFound, service.ErrRecipeNotInSlate:
+		models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+	case service.ErrInvalidImpressions, service.ErrInvalidEngagementDays:
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		models.WriteErrorResponse(w, fallback, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"meal-prep/services/recommendations/service"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+type WasteHandler struct {
+	wasteService service.WasteService
+}
+
+func NewWasteHandler(wasteService service.WasteService) *WasteHandler {
+	return &WasteHandler{wasteService: wasteService}
+}
+
+func (h *WasteHandler) LogWaste(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.LogWasteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.WriteErrorResponse(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.wasteService.LogWaste(user.UserID, req)
+	if err != nil {
+		switch err {
+		case service.ErrIngredientNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		case service.ErrInvalidWasteQuantity, service.ErrInvalidWasteUnit,
+			service.ErrInvalidWasteReason, service.ErrInvalidWasteDate:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to log waste", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, entry, http.StatusCreated)
+}
+
+func (h *WasteHandler) GetWasteLog(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+
+	entries, err := h.wasteService.GetWasteLog(user.UserID, limit)
+	if err != nil {
+		models.WriteErrorResponse(w, "Failed to fetch waste log", http.StatusInternalServerError)
+		return
+	}
+
+	models.WriteSuccessResponse(w, entries, http.StatusOK)
+}
+
+func (h *WasteHandler) DeleteWasteEntry(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	entryID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		models.WriteErrorResponse(w, "Invalid waste entry ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.wasteService.DeleteWasteEntry(user.UserID, entryID); err != nil {
+		switch err {
+		case service.ErrWasteEntryNotFound:
+			models.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		default:
+			models.WriteErrorResponse(w, "Failed to delete waste entry", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, map[string]string{"message": "Waste entry deleted successfully"}, http.StatusNoContent)
+}
+
+func (h *WasteHandler) GetMonthlySummary(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromGatewayContext(r.Context())
+	if !ok {
+		models.WriteErrorResponse(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	summary, err := h.wasteService.GetMonthlySummary(user.UserID, r.URL.Query().Get("month"))
+	if err != nil {
+		switch err {
+		case service.ErrInvalidMonth:
+			models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			models.WriteErrorResponse(w, "Failed to summarise waste", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	models.WriteSuccessResponse(w, summary, http.StatusOK)
+}
@@ -1,21 +1,27 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"net/http"
 	"os"
 
-	"meal-prep/services/recommendations/handlers"
-	"meal-prep/services/recommendations/repository"
-	"meal-prep/services/recommendations/service"
+	"meal-prep/migrations"
+	"meal-prep/services/recommendations/app"
 	"meal-prep/shared/database"
 	"meal-prep/shared/logging"
 	"meal-prep/shared/middleware"
+	"meal-prep/shared/server"
+	"meal-prep/shared/tracing"
 
-	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 )
 
 func main() {
+	waitForDeps := flag.Bool("wait-for-deps", false, "retry the database connection with backoff instead of exiting")
+	migrate := flag.Bool("migrate", false, "apply pending schema migrations before serving")
+	flag.Parse()
+
 	// Initialize logging first
 	logging.Init("recommendations-service")
 
@@ -23,8 +29,21 @@ func main() {
 		logging.Logger.Debug("No .env file found, using system environment variables")
 	}
 
+	// Cancelled on SIGTERM or SIGINT: background jobs stop and the server
+	// drains in-flight requests before main returns
+	ctx, stop := server.SignalContext()
+	defer stop()
+
+	// Export request and query spans when an OTLP endpoint is configured
+	shutdownTracing, err := tracing.Init(ctx, "recommendations-service")
+	if err != nil {
+		logging.Logger.Error("Failed to initialize tracing", "error", err)
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Database connection
-	db, err := database.NewPostgresConnection()
+	db, err := database.ConnectWithRetry(ctx, database.RetryConfigFromEnv(*waitForDeps), database.NewPostgresConnection)
 	if err != nil {
 		logging.Logger.Error("Failed to connect to database", "error", err)
 		os.Exit(1)
@@ -33,47 +52,41 @@ func main() {
 
 	logging.Logger.Info("Database connected successfully")
 
-	// Dependency injection chain
-	recRepo := repository.NewRecommendationRepository(db)
-	recService := service.NewRecommendationService(recRepo)
-	recHandler := handlers.NewRecommendationHandler(recService)
-
-	// Routes - all require authentication
-	router := mux.NewRouter()
-	router.Use(middleware.LoggingMiddleware("recommendations-service"))
-	router.Use(middleware.ExtractUserFromGatewayHeaders)
-
-	router.HandleFunc("/recommendations", recHandler.GetRecommendations).Methods("GET")
-	router.HandleFunc("/preferences", recHandler.GetUserPreferences).Methods("GET")
-	router.HandleFunc("/preferences", recHandler.UpdateUserPreferences).Methods("PUT")
-	router.HandleFunc("/cooking", recHandler.LogCooking).Methods("POST")
-	router.HandleFunc("/cooking/history", recHandler.GetCookingHistory).Methods("GET")
-
-	// Health check without auth
-	healthRouter := mux.NewRouter()
-	healthRouter.Use(middleware.LoggingMiddleware("recommendations-service"))
-	healthRouter.HandleFunc("/health", healthCheck).Methods("GET")
+	if *migrate {
+		if err := migrations.Apply(ctx, db, "recommendations"); err != nil {
+			logging.Logger.Error("Failed to migrate database", "error", err)
+			os.Exit(1)
+		}
+	}
 
-	// Combine routers
-	mainRouter := mux.NewRouter()
-	mainRouter.PathPrefix("/health").Handler(healthRouter)
-	mainRouter.PathPrefix("/").Handler(router)
+	// Users tagged with a data region are served from that region's
+	// database
+	regionDBs, err := database.ConnectRegions(ctx, database.RetryConfigFromEnv(*waitForDeps), database.RegionsFromEnv())
+	if err != nil {
+		logging.Logger.Error("Failed to connect to region database", "error", err)
+		os.Exit(1)
+	}
+	regional := make(map[string]http.Handler, len(regionDBs))
+	for region, regionDB := range regionDBs {
+		defer regionDB.Close()
+
+		if *migrate {
+			if err := migrations.Apply(ctx, regionDB, "recommendations"); err != nil {
+				logging.Logger.Error("Failed to migrate database", "region", region, "error", err)
+				os.Exit(1)
+			}
+		}
+		regional[region] = app.New(ctx, regionDB)
+	}
 
 	port := os.Getenv("RECOMMENDATIONS_PORT")
 	if port == "" {
 		port = "8003"
 	}
 
-	logging.Logger.Info("Starting recommendations service", "port", port)
-	if err := http.ListenAndServe(":"+port, mainRouter); err != nil {
-		logging.Logger.Error("Server failed to start", "error", err)
+	logging.Logger.Info("Starting recommendations service", "port", port, "regions", len(regional))
+	handler := middleware.RouteByRegion(app.New(ctx, db), regional, nil)
+	if err := server.Run(ctx, ":"+port, handler, server.ConfigFromEnv()); err != nil {
+		logging.Logger.Error("Server failed", "error", err)
 	}
 }
-
-func healthCheck(w http.ResponseWriter, r *http.Request) {
-	//logging.WithContext(r.Context()).Debug("Health check requested")
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status": "healthy", "service": "recommendations"}`))
-}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"meal-prep/shared/models"
+)
+
+func (r *recommendationRepository) GetDigestSubscription(userID int) (*models.DigestSubscription, error) {
+	var sub models.DigestSubscription
+	err := r.db.QueryRow(`
+		SELECT user_id, locale, subscribed_at, last_sent_at
+		FROM recommendations.digest_subscriptions
+		WHERE user_id = $1`, userID).
+		Scan(&sub.UserID, &sub.Locale, &sub.SubscribedAt, &sub.LastSentAt)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("ERROR: Failed to get digest subscription for user %d: %v", userID, err)
+		}
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// SaveDigestSubscription subscribes the user, or changes the locale of an
+// existing subscription without resetting when the last digest went out.
+func (r *recommendationRepository) SaveDigestSubscription(userID int, locale string) (*models.DigestSubscription, error) {
+	var sub models.DigestSubscription
+	err := r.db.QueryRow(`
+		INSERT INTO recommendations.digest_subscriptions (user_id, locale, subscribed_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE SET locale = EXCLUDED.locale
+		RETURNING user_id, locale, subscribed_at, last_sent_at`, userID, locale).
+		Scan(&sub.UserID, &sub.Locale, &sub.SubscribedAt, &sub.LastSentAt)
+	if err != nil {
+		log.Printf("ERROR: Failed to save digest subscription for user %d: %v", userID, err)
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *recommendationRepository) DeleteDigestSubscription(userID int) error {
+	result, err := r.db.Exec(`DELETE FROM recommendations.digest_subscriptions WHERE user_id = $1`, userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to delete digest subscription for user %d: %v", userID, err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetDueDigestSubscriptions returns subscriptions that have never been sent
+// a digest or were last sent one before sentBefore.
+func (r *recommendationRepository) GetDueDigestSubscriptions(sentBefore time.Time) ([]models.DigestSubscription, error) {
+	rows, err := r.db.Query(`
+		SELECT user_id, locale, subscribed_at, last_sent_at
+		FROM recommendations.digest_subscriptions
+		WHERE last_sent_at IS NULL OR last_sent_at < $1
+		ORDER BY user_id`, sentBefore)
+	if err != nil {
+		log.Printf("ERROR: Failed to query due digest subscriptions: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := make([]models.DigestSubscription, 0)
+	for rows.Next() {
+		var sub models.DigestSubscription
+		if err := rows.Scan(&sub.UserID, &sub.Locale, &sub.SubscribedAt, &sub.LastSentAt); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (r *recommendationRepository) MarkDigestSent(userID int, sentAt time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE recommendations.digest_subscriptions
+		SET last_sent_at = $2
+		WHERE user_id = $1`, userID, sentAt)
+	if err != nil {
+		log.Printf("ERROR: Failed to mark digest sent for user %d: %v", userID, err)
+	}
+	return err
+}
+
+// GetCookedRecipesSince lists the user's cooking log from since onwards,
+// oldest first, with recipe names from the catalogue.
+func (r *recommendationRepository) GetCookedRecipesSince(userID int, since time.Time) ([]models.DigestCookedRecipe, error) {
+	rows, err := r.db.Query(`
+		SELECT ch.recipe_id, d.name, ch.cooked_at, ch.rating
+		FROM recommendations.cooking_history ch
+		JOIN recipe_catalogue.recipes d ON d.id = ch.recipe_id
+		WHERE ch.user_id = $1 AND ch.cooked_at >= $2
+		ORDER BY ch.cooked_at`, userID, since)
+	if err != nil {
+		log.Printf("ERROR: Failed to query cooked recipes for user %d: %v", userID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	cooked := make([]models.DigestCookedRecipe, 0)
+	for rows.Next() {
+		var c models.DigestCookedRecipe
+		if err := rows.Scan(&c.RecipeID, &c.Name, &c.CookedAt, &c.Rating); err != nil {
+			return nil, err
+		}
+		cooked = append(cooked, c)
+	}
+	return cooked, rows.Err()
+}
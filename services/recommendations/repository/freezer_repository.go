@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"meal-prep/shared/models"
+)
+
+// freezerDateLayout is how freezer dates travel in requests and responses.
+const freezerDateLayout = "2006-01-02"
+
+// AddFreezerItem stores frozen portions. Returns sql.ErrNoRows when the
+// recipe does not exist in the catalogue.
+func (r *recommendationRepository) AddFreezerItem(item models.FreezerItem) (*models.FreezerItem, error) {
+	log.Printf("INFO: Freezing %d portions of recipe %d for user %d", item.Portions, item.RecipeID, item.UserID)
+
+	saved := item
+	err := r.db.QueryRow(`
+		WITH inserted AS (
+			INSERT INTO recommendations.freezer_items (user_id, recipe_id, portions, frozen_on, eat_before)
+			SELECT $1, rc.id, $3, $4::date, $5::date
+			FROM recipe_catalogue.recipes rc
+			WHERE rc.id = $2
+			RETURNING id, recipe_id, created_at
+		)
+		SELECT inserted.id, inserted.created_at, rc.name
+		FROM inserted
+		JOIN recipe_catalogue.recipes rc ON rc.id = inserted.recipe_id`,
+		item.UserID, item.RecipeID, item.Portions, item.FrozenOn, item.EatBefore).
+		Scan(&saved.ID, &saved.CreatedAt, &saved.RecipeName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("ERROR: Recipe %d does not exist", item.RecipeID)
+		} else {
+			log.Printf("ERROR: Failed to add freezer item for user %d: %v", item.UserID, err)
+		}
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// GetFreezerItems returns the user's frozen portions, soonest eat-before
+// first. Items whose recipe has since been deleted are left out.
+func (r *recommendationRepository) GetFreezerItems(userID int) ([]models.FreezerItem, error) {
+	rows, err := r.db.Query(`
+		SELECT f.id, f.user_id, f.recipe_id, rc.name, f.portions, f.frozen_on, f.eat_before, f.created_at
+		FROM recommendations.freezer_items f
+		JOIN recipe_catalogue.recipes rc ON rc.id = f.recipe_id
+		WHERE f.user_id = $1
+		ORDER BY f.eat_before, f.id`, userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to query freezer items for user %d: %v", userID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.FreezerItem{}
+	for rows.Next() {
+		var item models.FreezerItem
+		var frozenOn, eatBefore time.Time
+		if err := rows.Scan(&item.ID, &item.UserID, &item.RecipeID, &item.RecipeName,
+			&item.Portions, &frozenOn, &eatBefore, &item.CreatedAt); err != nil {
+			log.Printf("ERROR: Failed to scan freezer item for user %d: %v", userID, err)
+			return nil, err
+		}
+		item.FrozenOn = frozenOn.Format(freezerDateLayout)
+		item.EatBefore = eatBefore.Format(freezerDateLayout)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// UpdateFreezerPortions sets how many portions of an item are left. Returns
+// sql.ErrNoRows when the item is not the user's.
+func (r *recommendationRepository) UpdateFreezerPortions(userID, itemID, portions int) error {
+	result, err := r.db.Exec(`
+		UPDATE recommendations.freezer_items SET portions = $3
+		WHERE id = $1 AND user_id = $2`, itemID, userID, portions)
+	if err != nil {
+		log.Printf("ERROR: Failed to update freezer item %d for user %d: %v", itemID, userID, err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteFreezerItem removes one of the user's items. Returns sql.ErrNoRows
+// when the item is not theirs.
+func (r *recommendationRepository) DeleteFreezerItem(userID, itemID int) error {
+	result, err := r.db.Exec(`
+		DELETE FROM recommendations.freezer_items WHERE id = $1 AND user_id = $2`, itemID, userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to delete freezer item %d for user %d: %v", itemID, userID, err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
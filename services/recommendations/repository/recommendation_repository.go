@@ -1,30 +1,81 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"github.com/lib/pq"
 	"log"
 	"meal-prep/shared/database"
 	"meal-prep/shared/models"
+	"strings"
 	"time"
 )
 
 type RecommendationRepository interface {
 	GetUserPreferences(userID int) (*models.UserPreferences, error)
-	UpdateUserPreferences(userID int, categories []int) (*models.UserPreferences, error)
+	// UpdateUserPreferences returns sql.ErrNoRows when the preferences are no
+	// longer at version; 0 updates any version.
+	UpdateUserPreferences(userID int, categories []int, restrictions models.DietaryRestrictions, version int) (*models.UserPreferences, error)
+	// GetAllergenCodes lists the allergens the catalogue tags ingredients
+	// with, which users can avoid alongside its dietary flags.
+	GetAllergenCodes() ([]string, error)
 
 	// Cooking history methods
-	LogCooking(userID, recipeID int, rating *int) error
+	LogCooking(userID, recipeID int, rating *int, photo *models.CookingPhoto) error
 	GetUserCookingHistory(userID int, limit int) ([]models.CookingHistory, error)
+	GetCookedBetween(userID int, from, to time.Time) ([]models.CookedOnDay, error)
 	GetLastCookedTimes(userID int) (map[int]time.Time, error)
 
-	// Recommendation queries
-	GetRecipesWithTimeDecayScore(userID int, limit int) ([]models.RecipeWithScore, error)
-	GetRecipesByPreferences(userID int, limit int) ([]models.RecipeWithScore, error)
-	GetHybridRecommendations(userID int, limit int) ([]models.RecipeWithScore, error)
+	// Cooking photos and their moderation
+	SetCookingPhoto(userID, cookingID int, photo *models.CookingPhoto) error
+	GetRecipeCommunityPhotos(recipeID int, limit int) ([]models.CommunityPhoto, error)
+	GetPendingCookingPhotos(limit int) ([]models.CookingHistory, error)
+	ReviewCookingPhoto(cookingID int, status string) error
+
+	// Recommendation queries score a pool of at most candidates recipes
+	// besides those the user has cooked, or the whole catalogue when 0.
+	// They stop when ctx is done.
+	GetRecipesWithTimeDecayScore(ctx context.Context, userID, limit, candidates int) ([]models.RecipeWithScore, error)
+	GetRecipesByPreferences(ctx context.Context, userID, limit, candidates int) ([]models.RecipeWithScore, error)
+	GetHybridRecommendations(ctx context.Context, userID, limit, candidates int) ([]models.RecipeWithScore, error)
+	GetLevelUpRecommendations(ctx context.Context, userID, limit, candidates int) ([]models.RecipeWithScore, error)
+	// GetPopularRecommendations ranks what everybody cooks, as for public
+	// recommendations, within the user's dietary restrictions
+	GetPopularRecommendations(ctx context.Context, userID int, months []int, limit int) ([]models.RecipeWithScore, error)
+
+	// Item-based collaborative filtering: which recipes the same people cook
+	RebuildRecipeSimilarities(ctx context.Context, minCoCooks, neighbors int) (int, error)
+
+	// Weekly digest subscriptions and content
+	GetDigestSubscription(userID int) (*models.DigestSubscription, error)
+	SaveDigestSubscription(userID int, locale string) (*models.DigestSubscription, error)
+	DeleteDigestSubscription(userID int) error
+	GetDueDigestSubscriptions(sentBefore time.Time) ([]models.DigestSubscription, error)
+	MarkDigestSent(userID int, sentAt time.Time) error
+	GetCookedRecipesSince(userID int, since time.Time) ([]models.DigestCookedRecipe, error)
+
+	// Food waste log
+	LogWaste(entry models.WasteEntry) (*models.WasteEntry, error)
+	GetWasteLog(userID int, limit int) ([]models.WasteEntry, error)
+	DeleteWasteEntry(userID, entryID int) error
+	GetWasteSummary(userID int, from, to time.Time) (*models.WasteSummary, error)
+
+	// Freezer inventory of prepped portions
+	AddFreezerItem(item models.FreezerItem) (*models.FreezerItem, error)
+	GetFreezerItems(userID int) ([]models.FreezerItem, error)
+	UpdateFreezerPortions(userID, itemID, portions int) error
+	DeleteFreezerItem(userID, itemID int) error
+
+	// Served recommendation slates and how users engaged with them
+	SaveSlate(userID int, algorithm string, recipes []models.RecipeWithScore) (int, error)
+	RecordSlateImpressions(userID, slateID int, recipeIDs []int) (int, error)
+	RecordSlateClick(userID, slateID, recipeID int) (bool, error)
+	GetIgnoredRecipeIDs(userID int, since time.Time, minImpressions int) ([]int, error)
+	GetAlgorithmEngagement(since time.Time) ([]models.AlgorithmEngagement, error)
 
 	// Analytics
-	LogRecommendation(userID, recipeID int, algorithm string) error
+	GetPopularRecipes(limit int) ([]models.RecipePopularity, error)
+	GetPublicRecommendations(months []int, limit int) ([]models.RecipeWithScore, error)
 }
 
 type recommendationRepository struct {
@@ -56,46 +107,85 @@ func int64ArrayToIntSlice(array pq.Int64Array) []int {
 func (r *recommendationRepository) GetUserPreferences(userID int) (*models.UserPreferences, error) {
 	log.Printf("INFO: Getting preferences for user %d", userID)
 
+	prefs, err := r.queryUserPreferences(userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("INFO: No preferences found for user %d", userID)
+		} else {
+			log.Printf("ERROR: Failed to get preferences for user %d: %v", userID, err)
+		}
+		return nil, err
+	}
+
+	log.Printf("INFO: Retrieved preferences for user %d: categories=%v diet=%q allergens=%v",
+		userID, prefs.PreferredCategories, prefs.Diet, prefs.Allergens)
+	return prefs, nil
+}
+
+func (r *recommendationRepository) queryUserPreferences(userID int) (*models.UserPreferences, error) {
 	var prefs models.UserPreferences
 	var categoriesArray pq.Int64Array
+	var diet sql.NullString
+	var allergens pq.StringArray
 
 	err := r.db.QueryRow(`
-		SELECT id, user_id, preferred_categories, created_at, updated_at
+		SELECT id, user_id, preferred_categories, diet, allergens, version, created_at, updated_at
 		FROM recommendations.user_preferences WHERE user_id = $1`,
-		userID).Scan(&prefs.ID, &prefs.UserID, &categoriesArray,
+		userID).Scan(&prefs.ID, &prefs.UserID, &categoriesArray, &diet, &allergens, &prefs.Version,
 		&prefs.CreatedAt, &prefs.UpdatedAt)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Printf("INFO: No preferences found for user %d", userID)
-		} else {
-			log.Printf("ERROR: Failed to get preferences for user %d: %v", userID, err)
-		}
 		return nil, err
 	}
 
 	// Convert pq.Int64Array to []int
 	prefs.PreferredCategories = int64ArrayToIntSlice(categoriesArray)
-
-	log.Printf("INFO: Retrieved preferences for user %d: categories=%v", userID, prefs.PreferredCategories)
+	prefs.Diet = diet.String
+	prefs.Allergens = append([]string{}, allergens...)
 	return &prefs, nil
 }
 
-func (r *recommendationRepository) UpdateUserPreferences(userID int, categories []int) (*models.UserPreferences, error) {
-	log.Printf("INFO: Updating preferences for user %d with categories: %v", userID, categories)
+func (r *recommendationRepository) GetAllergenCodes() ([]string, error) {
+	rows, err := r.db.Query(`SELECT code FROM recipe_catalogue.allergens ORDER BY code`)
+	if err != nil {
+		log.Printf("ERROR: Failed to get allergen codes: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, rows.Err()
+}
+
+func (r *recommendationRepository) UpdateUserPreferences(userID int, categories []int, restrictions models.DietaryRestrictions, version int) (*models.UserPreferences, error) {
+	log.Printf("INFO: Updating preferences for user %d with categories: %v, diet: %q, allergens: %v",
+		userID, categories, restrictions.Diet, restrictions.Allergens)
 
 	// Convert []int to pq.Int64Array for PostgreSQL
 	categoriesArray := intSliceToInt64Array(categories)
+	diet := sql.NullString{String: restrictions.Diet, Valid: restrictions.Diet != ""}
+	allergens := pq.StringArray(append([]string{}, restrictions.Allergens...))
 
-	// First, try to insert or update
+	// First, try to insert or update; an update made against another
+	// version than the stored one changes nothing
 	result, err := r.db.Exec(`
-		INSERT INTO recommendations.user_preferences (user_id, preferred_categories, created_at, updated_at)
-		VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		INSERT INTO recommendations.user_preferences (user_id, preferred_categories, diet, allergens, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		ON CONFLICT (user_id) 
 		DO UPDATE SET 
 			preferred_categories = $2,
-			updated_at = CURRENT_TIMESTAMP`,
-		userID, categoriesArray)
+			diet = $3,
+			allergens = $4,
+			version = user_preferences.version + 1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE $5 = 0 OR user_preferences.version = $5`,
+		userID, categoriesArray, diet, allergens, version)
 
 	if err != nil {
 		log.Printf("ERROR: Failed to update preferences for user %d: %v", userID, err)
@@ -103,31 +193,24 @@ func (r *recommendationRepository) UpdateUserPreferences(userID int, categories
 	}
 
 	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		log.Printf("INFO: Preferences of user %d are no longer at version %d", userID, version)
+		return nil, sql.ErrNoRows
+	}
 	log.Printf("INFO: Preferences update successful for user %d, rows affected: %d", userID, rowsAffected)
 
 	// Then fetch the updated record
-	var prefs models.UserPreferences
-	var fetchedArray pq.Int64Array
-
-	err = r.db.QueryRow(`
-		SELECT id, user_id, preferred_categories, created_at, updated_at
-		FROM recommendations.user_preferences WHERE user_id = $1`,
-		userID).Scan(&prefs.ID, &prefs.UserID, &fetchedArray,
-		&prefs.CreatedAt, &prefs.UpdatedAt)
-
+	prefs, err := r.queryUserPreferences(userID)
 	if err != nil {
 		log.Printf("ERROR: Failed to fetch updated preferences for user %d: %v", userID, err)
 		return nil, err
 	}
 
-	// Convert back to []int
-	prefs.PreferredCategories = int64ArrayToIntSlice(fetchedArray)
-
 	log.Printf("INFO: Successfully updated and fetched preferences for user %d: %+v", userID, prefs.PreferredCategories)
-	return &prefs, nil
+	return prefs, nil
 }
 
-func (r *recommendationRepository) LogCooking(userID, recipeID int, rating *int) error {
+func (r *recommendationRepository) LogCooking(userID, recipeID int, rating *int, photo *models.CookingPhoto) error {
 	log.Printf("INFO: Logging cooking for user %d, recipe %d, rating %v", userID, recipeID, rating)
 
 	// First, check if the recipe exists
@@ -146,10 +229,12 @@ func (r *recommendationRepository) LogCooking(userID, recipeID int, rating *int)
 		return sql.ErrNoRows // This will be caught by the service layer
 	}
 
+	photoURL, photoCaption, photoShared, photoStatus := photoColumns(photo)
 	_, err = r.db.Exec(`
-		INSERT INTO recommendations.cooking_history (user_id, recipe_id, cooked_at, rating)
-		VALUES ($1, $2, CURRENT_TIMESTAMP, $3)`,
-		userID, recipeID, rating)
+		INSERT INTO recommendations.cooking_history
+			(user_id, recipe_id, cooked_at, rating, photo_url, photo_caption, photo_shared, photo_status)
+		VALUES ($1, $2, CURRENT_TIMESTAMP, $3, $4, $5, $6, $7)`,
+		userID, recipeID, rating, photoURL, photoCaption, photoShared, photoStatus)
 
 	if err != nil {
 		log.Printf("ERROR: Failed to log cooking for user %d, recipe %d: %v", userID, recipeID, err)
@@ -164,7 +249,7 @@ func (r *recommendationRepository) GetUserCookingHistory(userID int, limit int)
 	log.Printf("INFO: Getting cooking history for user %d, limit %d", userID, limit)
 
 	query := `
-		SELECT id, user_id, recipe_id, cooked_at, rating
+		SELECT id, user_id, recipe_id, cooked_at, rating, photo_url, photo_caption, photo_shared, photo_status
 		FROM recommendations.cooking_history 
 		WHERE user_id = $1
 		ORDER BY cooked_at DESC
@@ -177,19 +262,166 @@ func (r *recommendationRepository) GetUserCookingHistory(userID int, limit int)
 	}
 	defer rows.Close()
 
+	history, err := scanCookingHistory(rows)
+	if err != nil {
+		log.Printf("ERROR: Failed to scan cooking history row for user %d: %v", userID, err)
+		return nil, err
+	}
+
+	log.Printf("INFO: Retrieved %d cooking history records for user %d", len(history), userID)
+	return history, nil
+}
+
+// GetCookedBetween lists the user's cooking log in [from, to), oldest first,
+// with recipe names from the catalogue.
+func (r *recommendationRepository) GetCookedBetween(userID int, from, to time.Time) ([]models.CookedOnDay, error) {
+	rows, err := r.db.Query(`
+		SELECT ch.id, ch.recipe_id, d.name, ch.cooked_at, ch.rating
+		FROM recommendations.cooking_history ch
+		JOIN recipe_catalogue.recipes d ON d.id = ch.recipe_id
+		WHERE ch.user_id = $1 AND ch.cooked_at >= $2 AND ch.cooked_at < $3
+		ORDER BY ch.cooked_at, ch.id`, userID, from, to)
+	if err != nil {
+		log.Printf("ERROR: Failed to query cooking log for user %d: %v", userID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	cooked := make([]models.CookedOnDay, 0)
+	for rows.Next() {
+		var c models.CookedOnDay
+		if err := rows.Scan(&c.CookingID, &c.RecipeID, &c.Name, &c.CookedAt, &c.Rating); err != nil {
+			return nil, err
+		}
+		cooked = append(cooked, c)
+	}
+	return cooked, rows.Err()
+}
+
+// SetCookingPhoto replaces the photo of one of the user's cooking-log entries;
+// a nil photo removes it. Returns sql.ErrNoRows when the entry is not theirs.
+func (r *recommendationRepository) SetCookingPhoto(userID, cookingID int, photo *models.CookingPhoto) error {
+	log.Printf("INFO: Setting photo on cooking entry %d for user %d", cookingID, userID)
+
+	photoURL, photoCaption, photoShared, photoStatus := photoColumns(photo)
+	result, err := r.db.Exec(`
+		UPDATE recommendations.cooking_history
+		SET photo_url = $3, photo_caption = $4, photo_shared = $5, photo_status = $6
+		WHERE id = $1 AND user_id = $2`,
+		cookingID, userID, photoURL, photoCaption, photoShared, photoStatus)
+	if err != nil {
+		log.Printf("ERROR: Failed to set photo on cooking entry %d: %v", cookingID, err)
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetRecipeCommunityPhotos returns approved photos of a recipe, newest first.
+func (r *recommendationRepository) GetRecipeCommunityPhotos(recipeID int, limit int) ([]models.CommunityPhoto, error) {
+	rows, err := r.db.Query(`
+		SELECT id, recipe_id, photo_url, photo_caption, rating, cooked_at
+		FROM recommendations.cooking_history
+		WHERE recipe_id = $1 AND photo_status = 'approved'
+		ORDER BY cooked_at DESC, id DESC
+		LIMIT $2`, recipeID, limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to query community photos for recipe %d: %v", recipeID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	photos := make([]models.CommunityPhoto, 0)
+	for rows.Next() {
+		var p models.CommunityPhoto
+		if err := rows.Scan(&p.CookingID, &p.RecipeID, &p.URL, &p.Caption, &p.Rating, &p.CookedAt); err != nil {
+			return nil, err
+		}
+		photos = append(photos, p)
+	}
+	return photos, rows.Err()
+}
+
+// GetPendingCookingPhotos returns the moderation queue, oldest first.
+func (r *recommendationRepository) GetPendingCookingPhotos(limit int) ([]models.CookingHistory, error) {
+	rows, err := r.db.Query(`
+		SELECT id, user_id, recipe_id, cooked_at, rating, photo_url, photo_caption, photo_shared, photo_status
+		FROM recommendations.cooking_history
+		WHERE photo_status = 'pending'
+		ORDER BY cooked_at, id
+		LIMIT $1`, limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to query pending cooking photos: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCookingHistory(rows)
+}
+
+// ReviewCookingPhoto records a moderation decision. Returns sql.ErrNoRows when
+// the entry has no shared photo.
+func (r *recommendationRepository) ReviewCookingPhoto(cookingID int, status string) error {
+	log.Printf("INFO: Reviewing cooking photo %d as %s", cookingID, status)
+
+	result, err := r.db.Exec(`
+		UPDATE recommendations.cooking_history
+		SET photo_status = $2
+		WHERE id = $1 AND photo_shared AND photo_url IS NOT NULL`, cookingID, status)
+	if err != nil {
+		log.Printf("ERROR: Failed to review cooking photo %d: %v", cookingID, err)
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// photoColumns flattens a photo into its cooking_history columns. Sharing a
+// photo (again) puts it back in the moderation queue.
+func photoColumns(photo *models.CookingPhoto) (*string, *string, bool, *string) {
+	if photo == nil {
+		return nil, nil, false, nil
+	}
+	var status *string
+	if photo.Shared {
+		pending := models.PhotoStatusPending
+		status = &pending
+	}
+	return &photo.URL, photo.Caption, photo.Shared, status
+}
+
+func scanCookingHistory(rows *sql.Rows) ([]models.CookingHistory, error) {
 	var history []models.CookingHistory
 	for rows.Next() {
 		var h models.CookingHistory
-		err := rows.Scan(&h.ID, &h.UserID, &h.RecipeID, &h.CookedAt, &h.Rating)
+		var photo models.CookingPhoto
+		var photoURL sql.NullString
+		err := rows.Scan(&h.ID, &h.UserID, &h.RecipeID, &h.CookedAt, &h.Rating,
+			&photoURL, &photo.Caption, &photo.Shared, &photo.Status)
 		if err != nil {
-			log.Printf("ERROR: Failed to scan cooking history row for user %d: %v", userID, err)
 			return nil, err
 		}
+		if photoURL.Valid {
+			photo.URL = photoURL.String
+			h.Photo = &photo
+		}
 		history = append(history, h)
 	}
-
-	log.Printf("INFO: Retrieved %d cooking history records for user %d", len(history), userID)
-	return history, nil
+	return history, rows.Err()
 }
 
 func (r *recommendationRepository) GetLastCookedTimes(userID int) (map[int]time.Time, error) {
@@ -223,11 +455,56 @@ func (r *recommendationRepository) GetLastCookedTimes(userID int) (map[int]time.
 	return lastCooked, nil
 }
 
-func (r *recommendationRepository) GetRecipesWithTimeDecayScore(userID int, limit int) ([]models.RecipeWithScore, error) {
+// dietaryRestrictionFilter extends a WHERE clause on recipes d to drop those
+// with an ingredient that breaks the dietary restrictions of user $1: one
+// suiting a less restrictive diet than theirs, or carrying an allergen they
+// avoid. Users without restrictions are unaffected.
+const dietaryRestrictionFilter = `
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM recipe_catalogue.recipe_ingredients ri
+			JOIN recipe_catalogue.ingredient_diets idt ON idt.ingredient_id = ri.ingredient_id
+			JOIN recommendations.user_preferences up ON up.user_id = $1
+			WHERE ri.recipe_id = d.id
+			  AND (idt.diet_rank > ARRAY_POSITION(ARRAY['vegan', 'vegetarian', 'pescatarian', 'omnivore']::text[], up.diet::text) - 1
+				OR idt.flags && up.allergens)
+		)`
+
+// candidatePool is a CTE of the recipes scored for user $1: the $3
+// published recipes cooked most lately, newest first among the rest, every
+// recipe the user has cooked, so revisits still come up, and the recipes
+// most often cooked alongside those, so collaborative picks are not lost to
+// the popularity cut. A NULL $3 puts the whole catalogue in the pool.
+const candidatePool = `
+		candidates AS (
+			(SELECT d.id
+			 FROM recipe_catalogue.recipes d
+			 LEFT JOIN recommendations.recipe_popularity p ON p.recipe_id = d.id
+			 WHERE d.status = 'published'
+			 ORDER BY COALESCE(p.cooked_last_30_days, 0) DESC, COALESCE(p.times_cooked, 0) DESC, d.created_at DESC, d.id
+			 LIMIT $3)
+			UNION
+			SELECT recipe_id FROM recommendations.cooking_history WHERE user_id = $1
+			UNION
+			SELECT rs.similar_recipe_id
+			FROM recommendations.recipe_similarities rs
+			WHERE rs.recipe_id IN (SELECT recipe_id FROM recommendations.cooking_history WHERE user_id = $1)
+		)`
+
+// candidateLimit is the candidate pool's LIMIT, NULL for no limit.
+func candidateLimit(candidates int) interface{} {
+	if candidates <= 0 {
+		return nil
+	}
+	return candidates
+}
+
+func (r *recommendationRepository) GetRecipesWithTimeDecayScore(ctx context.Context, userID, limit, candidates int) ([]models.RecipeWithScore, error) {
 	log.Printf("INFO: Getting time decay recommendations for user %d, limit %d", userID, limit)
 
 	query := `
-		WITH recipe_last_cooked AS (
+		WITH` + candidatePool + `,
+		recipe_last_cooked AS (
 			SELECT DISTINCT ON (ch.recipe_id) 
 				ch.recipe_id,
 				ch.cooked_at,
@@ -250,8 +527,10 @@ func (r *recommendationRepository) GetRecipesWithTimeDecayScore(userID int, limi
 					ELSE 1.2
 				END as time_score
 			FROM recipe_catalogue.recipes d
+			JOIN candidates cd ON cd.id = d.id
 			LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
 			LEFT JOIN recipe_last_cooked dlc ON d.id = dlc.recipe_id
+			WHERE d.status = 'published'` + dietaryRestrictionFilter + `
 		)
 		SELECT 
 			id, name, description, category_id, created_at, updated_at,
@@ -260,7 +539,7 @@ func (r *recommendationRepository) GetRecipesWithTimeDecayScore(userID int, limi
 		ORDER BY time_score DESC, RANDOM()
 		LIMIT $2`
 
-	rows, err := r.db.Query(query, userID, limit)
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, candidateLimit(candidates))
 	if err != nil {
 		log.Printf("ERROR: Failed to query time decay recommendations for user %d: %v", userID, err)
 		return nil, err
@@ -277,10 +556,11 @@ func (r *recommendationRepository) GetRecipesWithTimeDecayScore(userID int, limi
 	return recipes, nil
 }
 
-func (r *recommendationRepository) GetRecipesByPreferences(userID int, limit int) ([]models.RecipeWithScore, error) {
+func (r *recommendationRepository) GetRecipesByPreferences(ctx context.Context, userID, limit, candidates int) ([]models.RecipeWithScore, error) {
 	log.Printf("INFO: Getting preference-based recommendations for user %d, limit %d", userID, limit)
 
 	query := `
+		WITH` + candidatePool + `
 		SELECT 
 			d.id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
 			c.id, c.name, c.description,
@@ -288,16 +568,18 @@ func (r *recommendationRepository) GetRecipesByPreferences(userID int, limit int
 			NULL::numeric as days_since,
 			1.0 as preference_score
 		FROM recipe_catalogue.recipes d
+		JOIN candidates cd ON cd.id = d.id
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
-		WHERE d.category_id IN (
+		WHERE d.status = 'published'
+		  AND d.category_id IN (
 			SELECT unnest(preferred_categories) 
 			FROM recommendations.user_preferences 
 			WHERE user_id = $1
-		)
+		)` + dietaryRestrictionFilter + `
 		ORDER BY RANDOM()
 		LIMIT $2`
 
-	rows, err := r.db.Query(query, userID, limit)
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, candidateLimit(candidates))
 	if err != nil {
 		log.Printf("ERROR: Failed to query preference recommendations for user %d: %v", userID, err)
 		return nil, err
@@ -313,18 +595,28 @@ func (r *recommendationRepository) GetRecipesByPreferences(userID int, limit int
 	// If no recipes found (user has no preferences set), get random recipes instead
 	if len(recipes) == 0 {
 		log.Printf("INFO: No preference-based recipes found for user %d, falling back to random recipes", userID)
-		return r.getRandomRecipes(limit)
+		return r.getRandomRecipes(ctx, userID, limit, candidates)
 	}
 
 	log.Printf("INFO: Generated %d preference-based recommendations for user %d", len(recipes), userID)
 	return recipes, nil
 }
 
-func (r *recommendationRepository) GetHybridRecommendations(userID int, limit int) ([]models.RecipeWithScore, error) {
+// hybridCollaborativeReasonScore is the collaborative boost, out of 0.5, at
+// which a hybrid pick is explained by what similar cooks make
+const hybridCollaborativeReasonScore = 0.2
+
+// GetHybridRecommendations blends how long ago the user cooked a recipe,
+// their preferred categories, the ingredients they tend to waste, and item
+// based collaborative filtering: recipes often cooked by people who cook
+// what the user cooks get up to 0.5 more, by the summed similarity in
+// recipe_similarities.
+func (r *recommendationRepository) GetHybridRecommendations(ctx context.Context, userID, limit, candidates int) ([]models.RecipeWithScore, error) {
 	log.Printf("INFO: Getting hybrid recommendations for user %d, limit %d", userID, limit)
 
 	query := `
-		WITH user_pref_categories AS (
+		WITH` + candidatePool + `,
+		user_pref_categories AS (
 			SELECT unnest(preferred_categories) as category_id
 			FROM recommendations.user_preferences 
 			WHERE user_id = $1
@@ -338,12 +630,41 @@ func (r *recommendationRepository) GetHybridRecommendations(userID int, limit in
 			WHERE ch.user_id = $1
 			ORDER BY ch.recipe_id, ch.cooked_at DESC
 		),
+		-- Ingredients the user threw away at least twice in the last 90 days
+		frequently_wasted AS (
+			SELECT ingredient_id
+			FROM recommendations.waste_log
+			WHERE user_id = $1 AND wasted_at > CURRENT_TIMESTAMP - INTERVAL '90 days'
+			GROUP BY ingredient_id
+			HAVING COUNT(*) >= 2
+		),
+		recipe_waste_matches AS (
+			SELECT ri.recipe_id, COUNT(DISTINCT ri.ingredient_id) as wasted_count
+			FROM recipe_catalogue.recipe_ingredients ri
+			JOIN frequently_wasted fw ON fw.ingredient_id = ri.ingredient_id
+			GROUP BY ri.recipe_id
+		),
+		-- How much people who cook what the user cooks also cook each recipe,
+		-- and the user's recipe that points to it most strongly
+		collaborative AS (
+			SELECT 
+				rs.similar_recipe_id as recipe_id,
+				SUM(rs.score) as collab_score,
+				(ARRAY_AGG(src.name ORDER BY rs.score DESC, rs.recipe_id))[1] as because_of
+			FROM recommendations.recipe_similarities rs
+			JOIN recipe_last_cooked rlc ON rlc.recipe_id = rs.recipe_id
+			JOIN recipe_catalogue.recipes src ON src.id = rs.recipe_id
+			GROUP BY rs.similar_recipe_id
+		),
 		scored_recipes AS (
 			SELECT 
 				d.id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
 				c.id as cat_id, c.name as cat_name, c.description as cat_desc,
 				dlc.cooked_at,
 				dlc.days_since,
+				LEAST(COALESCE(rwm.wasted_count, 0), 3) * 0.1 as waste_score,
+				LEAST(COALESCE(col.collab_score, 0), 1.0) * 0.5 as collab_score,
+				col.because_of,
 				CASE 
 					WHEN dlc.days_since IS NULL THEN 0.5
 					WHEN dlc.days_since < 7 THEN 0.1
@@ -358,26 +679,47 @@ func (r *recommendationRepository) GetHybridRecommendations(userID int, limit in
 					ELSE 0.3
 				END as preference_score
 			FROM recipe_catalogue.recipes d
+			JOIN candidates cd ON cd.id = d.id
 			LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
 			LEFT JOIN recipe_last_cooked dlc ON d.id = dlc.recipe_id
+			LEFT JOIN recipe_waste_matches rwm ON d.id = rwm.recipe_id
+			LEFT JOIN collaborative col ON d.id = col.recipe_id
+			WHERE d.status = 'published'` + dietaryRestrictionFilter + `
 		)
 		SELECT 
 			id, name, description, category_id, created_at, updated_at,
 			cat_id, cat_name, cat_desc, cooked_at, days_since,
-			(time_score * 0.6 + preference_score * 0.4) as final_score
+			(time_score * 0.6 + preference_score * 0.4 + waste_score + collab_score) as final_score,
+			collab_score,
+			because_of
 		FROM scored_recipes
 		ORDER BY final_score DESC, RANDOM()
 		LIMIT $2`
 
-	rows, err := r.db.Query(query, userID, limit)
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, candidateLimit(candidates))
 	if err != nil {
 		log.Printf("ERROR: Failed to query hybrid recommendations for user %d: %v", userID, err)
 		return nil, err
 	}
 	defer rows.Close()
 
-	recipes, err := r.scanScoredRecipes(rows, "hybrid")
-	if err != nil {
+	var recipes []models.RecipeWithScore
+	for rows.Next() {
+		var collabScore float64
+		var becauseOf sql.NullString
+		dws, err := r.scanScoredRecipe(rows, "hybrid", &collabScore, &becauseOf)
+		if err != nil {
+			return nil, err
+		}
+		// Credit the people who cook the same things when they tipped the pick
+		if becauseOf.Valid && collabScore >= hybridCollaborativeReasonScore && dws.DaysSinceCooked == nil {
+			dws.Reason = "People who cook " + becauseOf.String + " also cook this"
+		} else {
+			dws.Reason = r.generateReason("hybrid", dws.DaysSinceCooked, dws.Category.Name)
+		}
+		recipes = append(recipes, dws)
+	}
+	if err := rows.Err(); err != nil {
 		log.Printf("ERROR: Failed to scan hybrid recipes for user %d: %v", userID, err)
 		return nil, err
 	}
@@ -386,26 +728,240 @@ func (r *recommendationRepository) GetHybridRecommendations(userID int, limit in
 	return recipes, nil
 }
 
-func (r *recommendationRepository) LogRecommendation(userID, recipeID int, algorithm string) error {
-	_, err := r.db.Exec(`
-		INSERT INTO recommendations.recommendation_history (user_id, recipe_id, recommended_at, algorithm_used)
-		VALUES ($1, $2, CURRENT_TIMESTAMP, $3)`,
-		userID, recipeID, algorithm)
+// GetLevelUpRecommendations suggests uncooked recipes that need exactly one
+// technique the user hasn't practised yet. A technique counts as practised
+// once the user has cooked any recipe tagged with it. Recipes that also use
+// more of the practised techniques rank higher, as the one new thing is
+// learnt among familiar ones.
+func (r *recommendationRepository) GetLevelUpRecommendations(ctx context.Context, userID, limit, candidates int) ([]models.RecipeWithScore, error) {
+	log.Printf("INFO: Getting level up recommendations for user %d, limit %d", userID, limit)
 
+	query := `
+		WITH` + candidatePool + `,
+		practised AS (
+			SELECT DISTINCT rt.technique
+			FROM recommendations.cooking_history ch
+			JOIN recipe_catalogue.recipe_techniques rt ON rt.recipe_id = ch.recipe_id
+			WHERE ch.user_id = $1
+		),
+		recipe_progress AS (
+			SELECT 
+				rt.recipe_id,
+				COUNT(p.technique) as practised_count,
+				COUNT(*) - COUNT(p.technique) as new_count,
+				MIN(CASE WHEN p.technique IS NULL THEN rt.technique END) as new_technique
+			FROM recipe_catalogue.recipe_techniques rt
+			LEFT JOIN practised p ON p.technique = rt.technique
+			GROUP BY rt.recipe_id
+		)
+		SELECT 
+			d.id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
+			c.id, c.name, c.description,
+			NULL::timestamp as cooked_at,
+			NULL::numeric as days_since,
+			1.0 + LEAST(rp.practised_count, 5) * 0.1 as level_up_score,
+			rp.new_technique
+		FROM recipe_progress rp
+		JOIN recipe_catalogue.recipes d ON d.id = rp.recipe_id
+		JOIN candidates cd ON cd.id = d.id
+		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		WHERE d.status = 'published'
+		  AND rp.new_count = 1
+		  AND NOT EXISTS (
+			SELECT 1 FROM recommendations.cooking_history ch
+			WHERE ch.user_id = $1 AND ch.recipe_id = d.id
+		)` + dietaryRestrictionFilter + `
+		ORDER BY level_up_score DESC, RANDOM()
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, candidateLimit(candidates))
 	if err != nil {
-		log.Printf("ERROR: Failed to log recommendation for user %d, recipe %d, algorithm %s: %v", userID, recipeID, algorithm, err)
-		return err
+		log.Printf("ERROR: Failed to query level up recommendations for user %d: %v", userID, err)
+		return nil, err
 	}
+	defer rows.Close()
 
-	// Only log successful analytics logs at debug level to avoid spam
-	return nil
+	var recipes []models.RecipeWithScore
+	for rows.Next() {
+		var technique string
+		dws, err := r.scanScoredRecipe(rows, "level_up", &technique)
+		if err != nil {
+			return nil, err
+		}
+		dws.Reason = "Learn something new: " + strings.ReplaceAll(technique, "_", " ")
+		recipes = append(recipes, dws)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("ERROR: Failed to scan level up recipes for user %d: %v", userID, err)
+		return nil, err
+	}
+
+	log.Printf("INFO: Generated %d level up recommendations for user %d", len(recipes), userID)
+	return recipes, nil
+}
+
+// GetPopularRecipes ranks recipes by how often they were cooked in the last
+// 30 days, then by all-time count. Stats come from the recipe_popularity
+// materialized view, so they lag cooking logs until the next refresh.
+func (r *recommendationRepository) GetPopularRecipes(limit int) ([]models.RecipePopularity, error) {
+	log.Printf("INFO: Getting popular recipes, limit %d", limit)
+
+	query := `
+		SELECT
+			d.id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
+			c.id, c.name, c.description,
+			p.times_cooked, p.unique_cooks, p.cooked_last_30_days, p.average_rating, p.last_cooked_at
+		FROM recommendations.recipe_popularity p
+		JOIN recipe_catalogue.recipes d ON d.id = p.recipe_id
+		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		WHERE d.status = 'published'
+		ORDER BY p.cooked_last_30_days DESC, p.times_cooked DESC, d.name
+		LIMIT $1`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to query popular recipes: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	popular := make([]models.RecipePopularity, 0)
+	for rows.Next() {
+		var p models.RecipePopularity
+		var categoryID sql.NullInt64
+		var categoryName, categoryDesc sql.NullString
+		var averageRating sql.NullFloat64
+		var lastCookedAt sql.NullTime
+
+		err := rows.Scan(
+			&p.ID, &p.Name, &p.Description, &p.CategoryID, &p.CreatedAt, &p.UpdatedAt,
+			&categoryID, &categoryName, &categoryDesc,
+			&p.TimesCooked, &p.UniqueCooks, &p.CookedLast30Days, &averageRating, &lastCookedAt,
+		)
+		if err != nil {
+			log.Printf("ERROR: Failed to scan popular recipe row: %v", err)
+			return nil, err
+		}
+
+		if categoryID.Valid && categoryName.Valid {
+			category := models.Category{ID: int(categoryID.Int64), Name: categoryName.String}
+			if categoryDesc.Valid {
+				category.Description = &categoryDesc.String
+			}
+			p.Category = &category
+		}
+		if averageRating.Valid {
+			p.AverageRating = &averageRating.Float64
+		}
+		if lastCookedAt.Valid {
+			p.LastCookedAt = &lastCookedAt.Time
+		}
+
+		popular = append(popular, p)
+	}
+
+	log.Printf("INFO: Retrieved %d popular recipes", len(popular))
+	return popular, nil
+}
+
+// popularityScore ranks recipes p by recent and all-time popularity and by
+// cooks in season s, for visitors and users alike.
+const popularityScore = `
+			LN(1 + COALESCE(p.cooked_last_30_days, 0)) * 0.5
+				+ LN(1 + COALESCE(p.times_cooked, 0)) * 0.2
+				+ LN(1 + COALESCE(s.cooked_in_season, 0)) * 0.3`
+
+// GetPublicRecommendations ranks published recipes for visitors nobody knows
+// anything about: by recent and all-time popularity, and by how often they
+// were cooked in the given calendar months of any year. Recipes nobody cooked
+// yet come last, newest first, so a fresh install still has something to show.
+func (r *recommendationRepository) GetPublicRecommendations(months []int, limit int) ([]models.RecipeWithScore, error) {
+	log.Printf("INFO: Getting public recommendations for months %v, limit %d", months, limit)
+
+	query := `
+		WITH seasonal AS (
+			SELECT recipe_id, SUM(times_cooked) as cooked_in_season
+			FROM recommendations.recipe_seasonality
+			WHERE month = ANY($1)
+			GROUP BY recipe_id
+		)
+		SELECT
+			d.id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
+			c.id, c.name, c.description,
+			NULL::timestamp as cooked_at,
+			NULL::numeric as days_since,` + popularityScore + ` as public_score
+		FROM recipe_catalogue.recipes d
+		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		LEFT JOIN recommendations.recipe_popularity p ON p.recipe_id = d.id
+		LEFT JOIN seasonal s ON s.recipe_id = d.id
+		WHERE d.status = 'published'
+		ORDER BY public_score DESC, d.created_at DESC, d.id
+		LIMIT $2`
+
+	rows, err := r.db.Query(query, intSliceToInt64Array(months), limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to query public recommendations: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes, err := r.scanScoredRecipes(rows, "public")
+	if err != nil {
+		log.Printf("ERROR: Failed to scan public recommendations: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: Generated %d public recommendations", len(recipes))
+	return recipes, nil
 }
 
-// Helper method for random recipes (fallback when no preferences/history)
-func (r *recommendationRepository) getRandomRecipes(limit int) ([]models.RecipeWithScore, error) {
-	log.Printf("INFO: Getting random recipes, limit %d", limit)
+// GetPopularRecommendations ranks like GetPublicRecommendations from the
+// stats views alone, which stays fast however large the catalogue grows.
+func (r *recommendationRepository) GetPopularRecommendations(ctx context.Context, userID int, months []int, limit int) ([]models.RecipeWithScore, error) {
+	log.Printf("INFO: Getting popular recommendations for user %d, limit %d", userID, limit)
 
 	query := `
+		WITH seasonal AS (
+			SELECT recipe_id, SUM(times_cooked) as cooked_in_season
+			FROM recommendations.recipe_seasonality
+			WHERE month = ANY($3)
+			GROUP BY recipe_id
+		)
+		SELECT
+			d.id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
+			c.id, c.name, c.description,
+			NULL::timestamp as cooked_at,
+			NULL::numeric as days_since,` + popularityScore + ` as popular_score
+		FROM recipe_catalogue.recipes d
+		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		LEFT JOIN recommendations.recipe_popularity p ON p.recipe_id = d.id
+		LEFT JOIN seasonal s ON s.recipe_id = d.id
+		WHERE d.status = 'published'` + dietaryRestrictionFilter + `
+		ORDER BY popular_score DESC, d.created_at DESC, d.id
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, intSliceToInt64Array(months))
+	if err != nil {
+		log.Printf("ERROR: Failed to query popular recommendations for user %d: %v", userID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes, err := r.scanScoredRecipes(rows, "public")
+	if err != nil {
+		log.Printf("ERROR: Failed to scan popular recommendations for user %d: %v", userID, err)
+		return nil, err
+	}
+	return recipes, nil
+}
+
+// Helper method for random recipes (fallback when no preferences/history);
+// the user's dietary restrictions still apply
+func (r *recommendationRepository) getRandomRecipes(ctx context.Context, userID, limit, candidates int) ([]models.RecipeWithScore, error) {
+	log.Printf("INFO: Getting random recipes for user %d, limit %d", userID, limit)
+
+	query := `
+		WITH` + candidatePool + `
 		SELECT 
 			d.id, d.name, d.description, d.category_id, d.created_at, d.updated_at,
 			c.id, c.name, c.description,
@@ -413,11 +969,13 @@ func (r *recommendationRepository) getRandomRecipes(limit int) ([]models.RecipeW
 			NULL::numeric as days_since,
 			0.5 as random_score
 		FROM recipe_catalogue.recipes d
+		JOIN candidates cd ON cd.id = d.id
 		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		WHERE d.status = 'published'` + dietaryRestrictionFilter + `
 		ORDER BY RANDOM()
-		LIMIT $1`
+		LIMIT $2`
 
-	rows, err := r.db.Query(query, limit)
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, candidateLimit(candidates))
 	if err != nil {
 		log.Printf("ERROR: Failed to query random recipes: %v", err)
 		return nil, err
@@ -438,51 +996,11 @@ func (r *recommendationRepository) scanScoredRecipes(rows *sql.Rows, algorithm s
 	var recipes []models.RecipeWithScore
 
 	for rows.Next() {
-		var dws models.RecipeWithScore
-		var category models.Category
-		var categoryID sql.NullInt64
-		var categoryName sql.NullString
-		var categoryDesc sql.NullString
-		var cookedAt sql.NullTime
-		var daysSince sql.NullInt64
-		var score float64
-
-		err := rows.Scan(
-			&dws.ID, &dws.Name, &dws.Description, &dws.CategoryID,
-			&dws.CreatedAt, &dws.UpdatedAt,
-			&categoryID, &categoryName, &categoryDesc,
-			&cookedAt, &daysSince, &score,
-		)
+		dws, err := r.scanScoredRecipe(rows, algorithm)
 		if err != nil {
-			log.Printf("ERROR: Failed to scan scored recipe row for %s: %v", algorithm, err)
 			return nil, err
 		}
 
-		// Handle category data safely
-		if categoryID.Valid && categoryName.Valid {
-			category.ID = int(categoryID.Int64)
-			category.Name = categoryName.String
-			if categoryDesc.Valid {
-				category.Description = &categoryDesc.String
-			}
-			dws.Category = &category
-		} else {
-			// Create a default category for recipes without categories
-			category.ID = 0
-			category.Name = "Uncategorized"
-			dws.Category = &category
-		}
-
-		dws.RecommendationScore = score
-
-		if cookedAt.Valid {
-			dws.LastCookedAt = &cookedAt.Time
-		}
-		if daysSince.Valid {
-			days := int(daysSince.Int64)
-			dws.DaysSinceCooked = &days
-		}
-
 		// Generate reason based on algorithm and data
 		dws.Reason = r.generateReason(algorithm, dws.DaysSinceCooked, dws.Category.Name)
 
@@ -493,6 +1011,56 @@ func (r *recommendationRepository) scanScoredRecipes(rows *sql.Rows, algorithm s
 	return recipes, nil
 }
 
+// scanScoredRecipe scans the columns every algorithm selects, followed by
+// any extra columns into extra.
+func (r *recommendationRepository) scanScoredRecipe(rows *sql.Rows, algorithm string, extra ...interface{}) (models.RecipeWithScore, error) {
+	var dws models.RecipeWithScore
+	var category models.Category
+	var categoryID sql.NullInt64
+	var categoryName sql.NullString
+	var categoryDesc sql.NullString
+	var cookedAt sql.NullTime
+	var daysSince sql.NullInt64
+	var score float64
+
+	dest := []interface{}{
+		&dws.ID, &dws.Name, &dws.Description, &dws.CategoryID,
+		&dws.CreatedAt, &dws.UpdatedAt,
+		&categoryID, &categoryName, &categoryDesc,
+		&cookedAt, &daysSince, &score,
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		log.Printf("ERROR: Failed to scan scored recipe row for %s: %v", algorithm, err)
+		return dws, err
+	}
+
+	// Handle category data safely
+	if categoryID.Valid && categoryName.Valid {
+		category.ID = int(categoryID.Int64)
+		category.Name = categoryName.String
+		if categoryDesc.Valid {
+			category.Description = &categoryDesc.String
+		}
+		dws.Category = &category
+	} else {
+		// Create a default category for recipes without categories
+		category.ID = 0
+		category.Name = "Uncategorized"
+		dws.Category = &category
+	}
+
+	dws.RecommendationScore = score
+
+	if cookedAt.Valid {
+		dws.LastCookedAt = &cookedAt.Time
+	}
+	if daysSince.Valid {
+		days := int(daysSince.Int64)
+		dws.DaysSinceCooked = &days
+	}
+	return dws, nil
+}
+
 func (r *recommendationRepository) generateReason(algorithm string, daysSince *int, categoryName string) string {
 	switch algorithm {
 	case "time_decay":
@@ -516,6 +1084,8 @@ func (r *recommendationRepository) generateReason(algorithm string, daysSince *i
 		return "Great match for your " + categoryName + " preference"
 	case "random":
 		return "Discover something new in " + categoryName
+	case "public":
+		return "Popular with home cooks this time of year"
 	default:
 		return "Recommended for you"
 	}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"log"
+)
+
+// RebuildRecipeSimilarities recomputes recipe_similarities from everyone's
+// cooking history. Two recipes are alike when the same people cook both:
+// the score is the cosine similarity of their sets of cooks, kept for pairs
+// at least minCoCooks people share and for each recipe's neighbors closest
+// ones. The table is replaced in one transaction, so recommendations keep
+// reading the previous build until it commits. Returns how many pairs were
+// stored.
+func (r *recommendationRepository) RebuildRecipeSimilarities(ctx context.Context, minCoCooks, neighbors int) (int, error) {
+	log.Printf("INFO: Rebuilding recipe similarities, min co-cooks %d, neighbors %d", minCoCooks, neighbors)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Readers go on seeing the old rows; a second rebuild waits its turn
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE recommendations.recipe_similarities IN EXCLUSIVE MODE`); err != nil {
+		log.Printf("ERROR: Failed to lock recipe similarities: %v", err)
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recommendations.recipe_similarities`); err != nil {
+		log.Printf("ERROR: Failed to clear recipe similarities: %v", err)
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		WITH user_recipes AS (
+			SELECT DISTINCT user_id, recipe_id
+			FROM recommendations.cooking_history
+		),
+		recipe_cooks AS (
+			SELECT recipe_id, COUNT(*) as cooks
+			FROM user_recipes
+			GROUP BY recipe_id
+		),
+		pairs AS (
+			SELECT a.recipe_id, b.recipe_id as similar_recipe_id, COUNT(*) as co_cooks
+			FROM user_recipes a
+			JOIN user_recipes b ON b.user_id = a.user_id AND b.recipe_id <> a.recipe_id
+			GROUP BY a.recipe_id, b.recipe_id
+			HAVING COUNT(*) >= $1
+		),
+		ranked AS (
+			SELECT
+				p.recipe_id,
+				p.similar_recipe_id,
+				p.co_cooks,
+				p.co_cooks / SQRT(ca.cooks * cb.cooks::float8) as score,
+				ROW_NUMBER() OVER (PARTITION BY p.recipe_id ORDER BY p.co_cooks / SQRT(ca.cooks * cb.cooks::float8) DESC, p.co_cooks DESC, p.similar_recipe_id) as rank
+			FROM pairs p
+			JOIN recipe_cooks ca ON ca.recipe_id = p.recipe_id
+			JOIN recipe_cooks cb ON cb.recipe_id = p.similar_recipe_id
+		)
+		INSERT INTO recommendations.recipe_similarities (recipe_id, similar_recipe_id, score, co_cooks)
+		SELECT recipe_id, similar_recipe_id, score, co_cooks
+		FROM ranked
+		WHERE rank <= $2`, minCoCooks, neighbors)
+	if err != nil {
+		log.Printf("ERROR: Failed to compute recipe similarities: %v", err)
+		return 0, err
+	}
+	stored, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("ERROR: Failed to commit recipe similarities: %v", err)
+		return 0, err
+	}
+
+	log.Printf("INFO: Stored %d recipe similarities", stored)
+	return int(stored), nil
+}
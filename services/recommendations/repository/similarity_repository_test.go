@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"meal-prep/shared/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SimilarityRepositoryTestSuite struct {
+	suite.Suite
+	db   *database.DB
+	mock sqlmock.Sqlmock
+	repo RecommendationRepository
+}
+
+func (suite *SimilarityRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	require.NoError(suite.T(), err)
+
+	suite.db = &database.DB{DB: db}
+	suite.mock = mock
+	suite.repo = NewRecommendationRepository(suite.db)
+}
+
+func (suite *SimilarityRepositoryTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *SimilarityRepositoryTestSuite) expectCleared() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`LOCK TABLE recommendations.recipe_similarities IN EXCLUSIVE MODE`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM recommendations.recipe_similarities`)).
+		WillReturnResult(sqlmock.NewResult(0, 40))
+}
+
+func (suite *SimilarityRepositoryTestSuite) TestRebuildRecipeSimilarities_ReplacesTableInOneTransaction() {
+	// Arrange
+	suite.expectCleared()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recommendations.recipe_similarities (recipe_id, similar_recipe_id, score, co_cooks)`)).
+		WithArgs(2, 20).
+		WillReturnResult(sqlmock.NewResult(0, 12))
+	suite.mock.ExpectCommit()
+
+	// Act
+	stored, err := suite.repo.RebuildRecipeSimilarities(context.Background(), 2, 20)
+
+	// Assert
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 12, stored)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *SimilarityRepositoryTestSuite) TestRebuildRecipeSimilarities_KeepsOldRowsOnFailure() {
+	// Arrange
+	suite.expectCleared()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO recommendations.recipe_similarities`)).
+		WithArgs(2, 20).
+		WillReturnError(errors.New("canceling statement due to statement timeout"))
+	suite.mock.ExpectRollback()
+
+	// Act
+	stored, err := suite.repo.RebuildRecipeSimilarities(context.Background(), 2, 20)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Zero(suite.T(), stored)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *SimilarityRepositoryTestSuite) TestRebuildRecipeSimilarities_LockFailure() {
+	// Arrange
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`LOCK TABLE recommendations.recipe_similarities`)).
+		WillReturnError(errors.New("lock timeout"))
+	suite.mock.ExpectRollback()
+
+	// Act
+	_, err := suite.repo.RebuildRecipeSimilarities(context.Background(), 2, 20)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func TestSimilarityRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SimilarityRepositoryTestSuite))
+}
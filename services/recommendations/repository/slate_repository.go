@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"meal-prep/shared/models"
+
+	"github.com/lib/pq"
+)
+
+// SaveSlate stores the recipes served to a user, in the order served, and
+// returns the slate's ID.
+func (r *recommendationRepository) SaveSlate(userID int, algorithm string, recipes []models.RecipeWithScore) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var slateID int
+	err = tx.QueryRow(`
+		INSERT INTO recommendations.recommendation_slates (user_id, algorithm)
+		VALUES ($1, $2)
+		RETURNING id`, userID, algorithm).Scan(&slateID)
+	if err != nil {
+		log.Printf("ERROR: Failed to save %s slate for user %d: %v", algorithm, userID, err)
+		return 0, err
+	}
+
+	for i, recipe := range recipes {
+		_, err := tx.Exec(`
+			INSERT INTO recommendations.recommendation_slate_items (slate_id, recipe_id, position, score)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (slate_id, recipe_id) DO NOTHING`,
+			slateID, recipe.ID, i+1, recipe.RecommendationScore)
+		if err != nil {
+			log.Printf("ERROR: Failed to save recipe %d of slate %d: %v", recipe.ID, slateID, err)
+			return 0, err
+		}
+	}
+	return slateID, tx.Commit()
+}
+
+// RecordSlateImpressions marks recipes of the user's slate as seen, keeping
+// the time they were first seen, and returns how many of them are in the
+// slate. Returns sql.ErrNoRows when the slate is not the user's.
+func (r *recommendationRepository) RecordSlateImpressions(userID, slateID int, recipeIDs []int) (int, error) {
+	var slates, marked int
+	err := r.db.QueryRow(`
+		WITH slate AS (
+			SELECT id FROM recommendations.recommendation_slates WHERE id = $1 AND user_id = $2
+		),
+		marked AS (
+			UPDATE recommendations.recommendation_slate_items
+			SET impressed_at = COALESCE(impressed_at, CURRENT_TIMESTAMP)
+			WHERE slate_id IN (SELECT id FROM slate) AND recipe_id = ANY($3)
+			RETURNING recipe_id
+		)
+		SELECT (SELECT COUNT(*) FROM slate), (SELECT COUNT(*) FROM marked)`,
+		slateID, userID, intSliceToInt64Array(recipeIDs)).Scan(&slates, &marked)
+	if err != nil {
+		log.Printf("ERROR: Failed to record impressions of slate %d for user %d: %v", slateID, userID, err)
+		return 0, err
+	}
+	if slates == 0 {
+		return 0, sql.ErrNoRows
+	}
+	return marked, nil
+}
+
+// RecordSlateClick marks a recipe of the user's slate as clicked, and as
+// seen if no impression was reported first. It reports false when the
+// recipe is not in the slate, and returns sql.ErrNoRows when the slate is
+// not the user's.
+func (r *recommendationRepository) RecordSlateClick(userID, slateID, recipeID int) (bool, error) {
+	var slates, marked int
+	err := r.db.QueryRow(`
+		WITH slate AS (
+			SELECT id FROM recommendations.recommendation_slates WHERE id = $1 AND user_id = $2
+		),
+		marked AS (
+			UPDATE recommendations.recommendation_slate_items
+			SET clicked_at = COALESCE(clicked_at, CURRENT_TIMESTAMP),
+			    impressed_at = COALESCE(impressed_at, CURRENT_TIMESTAMP)
+			WHERE slate_id IN (SELECT id FROM slate) AND recipe_id = $3
+			RETURNING recipe_id
+		)
+		SELECT (SELECT COUNT(*) FROM slate), (SELECT COUNT(*) FROM marked)`,
+		slateID, userID, recipeID).Scan(&slates, &marked)
+	if err != nil {
+		log.Printf("ERROR: Failed to record click on slate %d for user %d: %v", slateID, userID, err)
+		return false, err
+	}
+	if slates == 0 {
+		return false, sql.ErrNoRows
+	}
+	return marked > 0, nil
+}
+
+// GetIgnoredRecipeIDs returns the recipes the user has seen in at least
+// minImpressions slates served since then without clicking any of them.
+func (r *recommendationRepository) GetIgnoredRecipeIDs(userID int, since time.Time, minImpressions int) ([]int, error) {
+	var ids pq.Int64Array
+	err := r.db.QueryRow(`
+		SELECT COALESCE(ARRAY_AGG(recipe_id ORDER BY recipe_id), '{}')
+		FROM (
+			SELECT i.recipe_id
+			FROM recommendations.recommendation_slate_items i
+			JOIN recommendations.recommendation_slates s ON s.id = i.slate_id
+			WHERE s.user_id = $1 AND s.served_at >= $2
+			GROUP BY i.recipe_id
+			HAVING COUNT(i.impressed_at) >= $3 AND COUNT(i.clicked_at) = 0
+		) ignored`, userID, since, minImpressions).Scan(&ids)
+	if err != nil {
+		log.Printf("ERROR: Failed to get ignored recipes for user %d: %v", userID, err)
+		return nil, err
+	}
+	return int64ArrayToIntSlice(ids), nil
+}
+
+// GetAlgorithmEngagement totals the slates each algorithm served since then
+// and the impressions and clicks on them, by algorithm name.
+func (r *recommendationRepository) GetAlgorithmEngagement(since time.Time) ([]models.AlgorithmEngagement, error) {
+	rows, err := r.db.Query(`
+		SELECT s.algorithm, COUNT(DISTINCT s.id), COUNT(i.recipe_id), COUNT(i.impressed_at), COUNT(i.clicked_at)
+		FROM recommendations.recommendation_slates s
+		LEFT JOIN recommendations.recommendation_slate_items i ON i.slate_id = s.id
+		WHERE s.served_at >= $1
+		GROUP BY s.algorithm
+		ORDER BY s.algorithm`, since)
+	if err != nil {
+		log.Printf("ERROR: Failed to query recommendation engagement: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	engagement := []models.AlgorithmEngagement{}
+	for rows.Next() {
+		var e models.AlgorithmEngagement
+		if err := rows.Scan(&e.Algorithm, &e.Slates, &e.Served, &e.Impressions, &e.Clicks); err != nil {
+			return nil, err
+		}
+		engagement = append(engagement, e)
+	}
+	return engagement, rows.Err()
+}
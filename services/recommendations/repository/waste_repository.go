@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"meal-prep/shared/models"
+)
+
+// LogWaste records a waste entry. Returns sql.ErrNoRows when the ingredient
+// does not exist in the catalogue.
+func (r *recommendationRepository) LogWaste(entry models.WasteEntry) (*models.WasteEntry, error) {
+	log.Printf("INFO: Logging waste of ingredient %d for user %d", entry.IngredientID, entry.UserID)
+
+	saved := entry
+	err := r.db.QueryRow(`
+		WITH inserted AS (
+			INSERT INTO recommendations.waste_log (user_id, ingredient_id, quantity, unit, reason, wasted_at)
+			SELECT $1, i.id, $3, $4, $5, $6
+			FROM recipe_catalogue.ingredients i
+			WHERE i.id = $2
+			RETURNING id, ingredient_id, wasted_at
+		)
+		SELECT inserted.id, inserted.wasted_at, i.name
+		FROM inserted
+		JOIN recipe_catalogue.ingredients i ON i.id = inserted.ingredient_id`,
+		entry.UserID, entry.IngredientID, entry.Quantity, entry.Unit, entry.Reason, entry.WastedAt).
+		Scan(&saved.ID, &saved.WastedAt, &saved.IngredientName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("ERROR: Ingredient %d does not exist", entry.IngredientID)
+		} else {
+			log.Printf("ERROR: Failed to log waste for user %d: %v", entry.UserID, err)
+		}
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+func (r *recommendationRepository) GetWasteLog(userID int, limit int) ([]models.WasteEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT w.id, w.user_id, w.ingredient_id, i.name, w.quantity, w.unit, w.reason, w.wasted_at
+		FROM recommendations.waste_log w
+		JOIN recipe_catalogue.ingredients i ON i.id = w.ingredient_id
+		WHERE w.user_id = $1
+		ORDER BY w.wasted_at DESC, w.id DESC
+		LIMIT $2`, userID, limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to query waste log for user %d: %v", userID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.WasteEntry{}
+	for rows.Next() {
+		var e models.WasteEntry
+		if err := rows.Scan(&e.ID, &e.UserID, &e.IngredientID, &e.IngredientName,
+			&e.Quantity, &e.Unit, &e.Reason, &e.WastedAt); err != nil {
+			log.Printf("ERROR: Failed to scan waste log row for user %d: %v", userID, err)
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// DeleteWasteEntry removes one of the user's entries. Returns sql.ErrNoRows
+// when the entry is not theirs.
+func (r *recommendationRepository) DeleteWasteEntry(userID, entryID int) error {
+	result, err := r.db.Exec(`
+		DELETE FROM recommendations.waste_log WHERE id = $1 AND user_id = $2`, entryID, userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to delete waste entry %d for user %d: %v", entryID, userID, err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetWasteSummary totals the waste logged in [from, to), per ingredient and
+// unit and per reason. Month is left for the caller to fill in.
+func (r *recommendationRepository) GetWasteSummary(userID int, from, to time.Time) (*models.WasteSummary, error) {
+	summary := &models.WasteSummary{
+		ByReason:    map[string]int{},
+		Ingredients: []models.WastedIngredientTotal{},
+	}
+
+	rows, err := r.db.Query(`
+		SELECT w.reason, COUNT(*)
+		FROM recommendations.waste_log w
+		WHERE w.user_id = $1 AND w.wasted_at >= $2 AND w.wasted_at < $3
+		GROUP BY w.reason`, userID, from, to)
+	if err != nil {
+		log.Printf("ERROR: Failed to summarise waste reasons for user %d: %v", userID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reason string
+		var count int
+		if err := rows.Scan(&reason, &count); err != nil {
+			return nil, err
+		}
+		summary.ByReason[reason] = count
+		summary.Entries += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	totals, err := r.db.Query(`
+		SELECT w.ingredient_id, i.name, w.unit, SUM(w.quantity), COUNT(*) AS times
+		FROM recommendations.waste_log w
+		JOIN recipe_catalogue.ingredients i ON i.id = w.ingredient_id
+		WHERE w.user_id = $1 AND w.wasted_at >= $2 AND w.wasted_at < $3
+		GROUP BY w.ingredient_id, i.name, w.unit
+		ORDER BY times DESC, i.name, w.unit`, userID, from, to)
+	if err != nil {
+		log.Printf("ERROR: Failed to summarise wasted ingredients for user %d: %v", userID, err)
+		return nil, err
+	}
+	defer totals.Close()
+
+	for totals.Next() {
+		var t models.WastedIngredientTotal
+		if err := totals.Scan(&t.IngredientID, &t.IngredientName, &t.Unit, &t.Quantity, &t.Times); err != nil {
+			return nil, err
+		}
+		summary.Ingredients = append(summary.Ingredients, t)
+	}
+	return summary, totals.Err()
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"meal-prep/services/recommendations/repository"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+)
+
+var (
+	ErrDigestNotSubscribed = errors.New("not subscribed to the weekly digest")
+	ErrUnsupportedLocale   = fmt.Errorf("locale must be one of: %s", strings.Join(SupportedDigestLocales(), ", "))
+)
+
+const (
+	DefaultDigestLocale = "en"
+
+	// digestPeriod is both what a digest covers and how often one is sent
+	digestPeriod = 7 * 24 * time.Hour
+
+	// digestSuggestionCount is how many hybrid recommendations a digest suggests
+	digestSuggestionCount = 5
+
+	defaultDigestCheckInterval = time.Hour
+)
+
+//go:embed templates/digest.*.tmpl
+var digestTemplateFS embed.FS
+
+// digestTemplates holds one template set per locale, each defining a
+// "subject" and a "body" template. Locales come from the file names.
+var digestTemplates = mustParseDigestTemplates()
+
+func mustParseDigestTemplates() map[string]*template.Template {
+	files, err := digestTemplateFS.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+	templates := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		locale := strings.TrimSuffix(strings.TrimPrefix(file.Name(), "digest."), ".tmpl")
+		templates[locale] = template.Must(template.ParseFS(digestTemplateFS, "templates/"+file.Name()))
+	}
+	return templates
+}
+
+// SupportedDigestLocales lists the locales digests can be written in.
+func SupportedDigestLocales() []string {
+	locales := make([]string, 0, len(digestTemplates))
+	for locale := range digestTemplates {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// normalizeLocale maps a language tag such as "en-GB" to a supported locale.
+func normalizeLocale(locale string) (string, bool) {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if locale == "" {
+		return DefaultDigestLocale, true
+	}
+	if _, ok := digestTemplates[locale]; ok {
+		return locale, true
+	}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		if _, ok := digestTemplates[locale[:i]]; ok {
+			return locale[:i], true
+		}
+	}
+	return "", false
+}
+
+// PreferredDigestLocale returns the first of locales, most preferred first,
+// that digests can be written in, or "" when there is none.
+func PreferredDigestLocale(locales []string) string {
+	for _, locale := range locales {
+		if supported, ok := normalizeLocale(locale); ok {
+			return supported
+		}
+	}
+	return ""
+}
+
+// Notifier delivers rendered digests. There is no notification service yet,
+// so main uses LogNotifier; a mailer only has to implement this.
+type Notifier interface {
+	Notify(msg models.DigestMessage) error
+}
+
+type logNotifier struct{}
+
+// NewLogNotifier returns a Notifier that writes digests to the service log.
+func NewLogNotifier() Notifier {
+	return logNotifier{}
+}
+
+func (logNotifier) Notify(msg models.DigestMessage) error {
+	logging.Logger.Info("Weekly digest", "user_id", msg.UserID, "locale", msg.Locale,
+		"subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// DigestCheckIntervalFromEnv reads how often RunDigestScheduler looks for
+// due digests (DIGEST_CHECK_INTERVAL, default 1h; 0 disables sending).
+func DigestCheckIntervalFromEnv() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("DIGEST_CHECK_INTERVAL")); err == nil && d >= 0 {
+		return d
+	}
+	return defaultDigestCheckInterval
+}
+
+type DigestService interface {
+	GetSubscription(userID int) (*models.DigestSubscription, error)
+	Subscribe(userID int, req models.SubscribeDigestRequest) (*models.DigestSubscription, error)
+	Unsubscribe(userID int) error
+
+	// Preview renders the digest the user would get now, subscribed or not
+	Preview(userID int, locale string) (*models.DigestMessage, error)
+
+	// SendDue sends a digest to every subscriber whose last one is at least
+	// a week old and returns how many went out
+	SendDue(now time.Time) (int, error)
+}
+
+type digestService struct {
+	repo     repository.RecommendationRepository
+	notifier Notifier
+}
+
+func NewDigestService(repo repository.RecommendationRepository, notifier Notifier) DigestService {
+	return &digestService{repo: repo, notifier: notifier}
+}
+
+func (s *digestService) GetSubscription(userID int) (*models.DigestSubscription, error) {
+	if userID <= 0 {
+		return nil, ErrUserNotFound
+	}
+
+	sub, err := s.repo.GetDigestSubscription(userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrDigestNotSubscribed
+		}
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *digestService) Subscribe(userID int, req models.SubscribeDigestRequest) (*models.DigestSubscription, error) {
+	if userID <= 0 {
+		return nil, ErrUserNotFound
+	}
+
+	locale, ok := normalizeLocale(req.Locale)
+	if !ok {
+		return nil, ErrUnsupportedLocale
+	}
+	return s.repo.SaveDigestSubscription(userID, locale)
+}
+
+func (s *digestService) Unsubscribe(userID int) error {
+	if userID <= 0 {
+		return ErrUserNotFound
+	}
+
+	if err := s.repo.DeleteDigestSubscription(userID); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrDigestNotSubscribed
+		}
+		return err
+	}
+	return nil
+}
+
+// Preview uses the given locale, falling back to the subscription's and
+// then the default.
+func (s *digestService) Preview(userID int, locale string) (*models.DigestMessage, error) {
+	if userID <= 0 {
+		return nil, ErrUserNotFound
+	}
+
+	if locale == "" {
+		sub, err := s.repo.GetDigestSubscription(userID)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		if sub != nil {
+			locale = sub.Locale
+		}
+	}
+	locale, ok := normalizeLocale(locale)
+	if !ok {
+		return nil, ErrUnsupportedLocale
+	}
+
+	return s.render(userID, locale, time.Now())
+}
+
+// SendDue keeps going when one digest fails so a single bad user does not
+// hold up everybody else's; failed digests are retried on the next run.
+func (s *digestService) SendDue(now time.Time) (int, error) {
+	subs, err := s.repo.GetDueDigestSubscriptions(now.Add(-digestPeriod))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, sub := range subs {
+		msg, err := s.render(sub.UserID, sub.Locale, now)
+		if err == nil {
+			err = s.notifier.Notify(*msg)
+		}
+		if err == nil {
+			err = s.repo.MarkDigestSent(sub.UserID, now)
+		}
+		if err != nil {
+			logging.Logger.Error("Weekly digest failed", "user_id", sub.UserID, "error", err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *digestService) render(userID int, locale string, now time.Time) (*models.DigestMessage, error) {
+	digest, err := s.buildDigest(userID, locale, now)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, ok := digestTemplates[locale]
+	if !ok {
+		// Subscriptions saved before a locale was removed
+		tmpl = digestTemplates[DefaultDigestLocale]
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", digest); err != nil {
+		return nil, err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", digest); err != nil {
+		return nil, err
+	}
+
+	return &models.DigestMessage{
+		UserID:  userID,
+		Locale:  locale,
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    strings.TrimSpace(body.String()) + "\n",
+	}, nil
+}
+
+func (s *digestService) buildDigest(userID int, locale string, now time.Time) (*models.WeeklyDigest, error) {
+	since := now.Add(-digestPeriod)
+
+	cooked, err := s.repo.GetCookedRecipesSince(userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.repo.GetHybridRecommendations(context.Background(), userID, digestSuggestionCount, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WeeklyDigest{
+		UserID:      userID,
+		Locale:      locale,
+		WeekStart:   since,
+		WeekEnd:     now,
+		Cooked:      cooked,
+		Suggestions: suggestions,
+	}, nil
+}
+
+// RunDigestScheduler sends due digests every interval until ctx is
+// cancelled. A failed run is logged and retried on the next tick.
+func RunDigestScheduler(ctx context.Context, digests DigestService, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			sent, err := digests.SendDue(now)
+			if err != nil {
+				logging.Logger.Error("Weekly digest run failed", "error", err)
+				continue
+			}
+			if sent > 0 {
+				logging.Logger.Info("Weekly digests sent", "count", sent)
+			}
+		}
+	}
+}
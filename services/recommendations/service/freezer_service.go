@@ -0,0 +1,168 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"meal-prep/services/recommendations/repository"
+	"meal-prep/shared/models"
+)
+
+var (
+	ErrFreezerItemNotFound    = errors.New("freezer item not found")
+	ErrInvalidFreezerPortions = errors.New("portions must be between 1 and 100")
+	ErrInvalidFrozenOn        = errors.New("frozen_on must be a YYYY-MM-DD date, not in the future")
+	ErrInvalidEatBefore       = errors.New("eat_before must be a YYYY-MM-DD date after frozen_on")
+)
+
+const (
+	MaxFreezerPortions = 100
+
+	// DefaultFreezerMonths is how long frozen cooked food keeps its quality
+	// when no eat_before date is given
+	DefaultFreezerMonths = 3
+
+	// FreezerEatSoonDays is how close to its eat_before date an item gets
+	// flagged to eat soon
+	FreezerEatSoonDays = 14
+
+	freezerDateLayout = "2006-01-02"
+)
+
+type FreezerService interface {
+	AddItem(userID int, req models.AddFreezerItemRequest) (*models.FreezerItem, error)
+	GetInventory(userID int) ([]models.FreezerItem, error)
+
+	// UpdatePortions records portions taken out; zero left removes the item
+	UpdatePortions(userID, itemID int, req models.UpdateFreezerItemRequest) error
+	RemoveItem(userID, itemID int) error
+}
+
+type freezerService struct {
+	repo repository.RecommendationRepository
+	now  func() time.Time
+}
+
+func NewFreezerService(repo repository.RecommendationRepository) FreezerService {
+	return &freezerService{repo: repo, now: time.Now}
+}
+
+func (s *freezerService) AddItem(userID int, req models.AddFreezerItemRequest) (*models.FreezerItem, error) {
+	if userID <= 0 {
+		return nil, ErrUserNotFound
+	}
+	if req.RecipeID <= 0 {
+		return nil, ErrRecipeNotFound
+	}
+	if req.Portions < 1 || req.Portions > MaxFreezerPortions {
+		return nil, ErrInvalidFreezerPortions
+	}
+
+	today := freezerToday(s.now())
+	frozenOn := today
+	if req.FrozenOn != "" {
+		var err error
+		if frozenOn, err = time.Parse(freezerDateLayout, req.FrozenOn); err != nil || frozenOn.After(today) {
+			return nil, ErrInvalidFrozenOn
+		}
+	}
+
+	eatBefore := frozenOn.AddDate(0, DefaultFreezerMonths, 0)
+	if req.EatBefore != "" {
+		var err error
+		if eatBefore, err = time.Parse(freezerDateLayout, req.EatBefore); err != nil || !eatBefore.After(frozenOn) {
+			return nil, ErrInvalidEatBefore
+		}
+	}
+
+	item, err := s.repo.AddFreezerItem(models.FreezerItem{
+		UserID:    userID,
+		RecipeID:  req.RecipeID,
+		Portions:  req.Portions,
+		FrozenOn:  frozenOn.Format(freezerDateLayout),
+		EatBefore: eatBefore.Format(freezerDateLayout),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRecipeNotFound
+		}
+		return nil, err
+	}
+
+	annotateFreezerItems([]models.FreezerItem{*item}, today)
+	return item, nil
+}
+
+func (s *freezerService) GetInventory(userID int) ([]models.FreezerItem, error) {
+	if userID <= 0 {
+		return nil, ErrUserNotFound
+	}
+
+	items, err := s.repo.GetFreezerItems(userID)
+	if err != nil {
+		return nil, err
+	}
+	annotateFreezerItems(items, freezerToday(s.now()))
+	return items, nil
+}
+
+func (s *freezerService) UpdatePortions(userID, itemID int, req models.UpdateFreezerItemRequest) error {
+	if userID <= 0 {
+		return ErrUserNotFound
+	}
+	if req.Portions < 0 || req.Portions > MaxFreezerPortions {
+		return ErrInvalidFreezerPortions
+	}
+	if req.Portions == 0 {
+		return s.RemoveItem(userID, itemID)
+	}
+	if itemID <= 0 {
+		return ErrFreezerItemNotFound
+	}
+
+	err := s.repo.UpdateFreezerPortions(userID, itemID, req.Portions)
+	if err == sql.ErrNoRows {
+		return ErrFreezerItemNotFound
+	}
+	return err
+}
+
+func (s *freezerService) RemoveItem(userID, itemID int) error {
+	if userID <= 0 {
+		return ErrUserNotFound
+	}
+	if itemID <= 0 {
+		return ErrFreezerItemNotFound
+	}
+
+	err := s.repo.DeleteFreezerItem(userID, itemID)
+	if err == sql.ErrNoRows {
+		return ErrFreezerItemNotFound
+	}
+	return err
+}
+
+// freezerToday is the calendar date of now, as UTC midnight so that whole
+// days can be counted without daylight saving getting in the way.
+func freezerToday(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// annotateFreezerItems fills in DaysLeft and the eat-before warning of each
+// item as of today.
+func annotateFreezerItems(items []models.FreezerItem, today time.Time) {
+	for i := range items {
+		eatBefore, err := time.Parse(freezerDateLayout, items[i].EatBefore)
+		if err != nil {
+			continue
+		}
+		items[i].DaysLeft = int(eatBefore.Sub(today).Hours() / 24)
+		switch {
+		case items[i].DaysLeft <= 0:
+			items[i].Warning = models.FreezerWarningPastEatBefore
+		case items[i].DaysLeft <= FreezerEatSoonDays:
+			items[i].Warning = models.FreezerWarningEatSoon
+		}
+	}
+}
@@ -1,11 +1,21 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"meal-prep/services/recommendations/repository"
+	"meal-prep/shared/database"
+	"meal-prep/shared/logging"
 	"meal-prep/shared/models"
 )
 
@@ -16,19 +26,74 @@ var (
 	ErrInvalidRating     = errors.New("rating must be between 1 and 5")
 	ErrInvalidLimit      = errors.New("limit must be between 1 and 50")
 	ErrPreferencesNotSet = errors.New("user preferences not configured")
+
+	ErrCookingEntryNotFound = errors.New("cooking entry not found")
+	ErrCookingPhotoNotFound = errors.New("cooking photo not found")
+	ErrInvalidPhotoURL      = errors.New("photo url must be an absolute http or https URL")
+	ErrInvalidPhotoCaption  = errors.New("photo caption must be at most 200 characters")
+	ErrInvalidPhotoStatus   = errors.New("status must be approved or rejected")
+
+	ErrInvalidDiet      = errors.New("diet must be vegan, vegetarian, pescatarian or omnivore")
+	ErrInvalidAllergens = errors.New("allergens must be dietary flags or allergen codes listed at /allergens")
 )
 
 const (
 	AlgorithmPreference = "preference"
 	AlgorithmTimeDecay  = "time_decay"
 	AlgorithmHybrid     = "hybrid"
+	AlgorithmLevelUp    = "level_up"
 	DefaultLimit        = 10
 	MaxLimit            = 50
+
+	// AlgorithmPopular is served in place of the algorithm asked for when
+	// scoring runs out of time
+	AlgorithmPopular = "popular"
+
+	// A recipe the user has seen in IgnoredAfterImpressions slates within
+	// IgnoredSuggestionWindow without clicking it is shown after fresher ones
+	IgnoredAfterImpressions = 3
+	IgnoredSuggestionWindow = 14 * 24 * time.Hour
+
+	// MaxPhotoCaptionLength matches the cooking_history.photo_caption column
+	MaxPhotoCaptionLength = 200
+
+	defaultMaxCandidates  = 2000
+	defaultScoringTimeout = 2 * time.Second
 )
 
+// ScoringConfig bounds the work behind each set of recommendations so their
+// latency stays flat as the catalogue grows.
+type ScoringConfig struct {
+	// MaxCandidates caps how many of the most popular recipes are scored,
+	// on top of the ones the user has cooked; 0 scores the whole catalogue
+	MaxCandidates int
+	// Timeout is how long scoring may run before the most popular recipes
+	// are served instead; 0 waits for scoring however long it takes
+	Timeout time.Duration
+}
+
+// ScoringConfigFromEnv reads RECOMMENDATION_MAX_CANDIDATES and
+// RECOMMENDATION_SCORING_TIMEOUT, defaulting to 2000 candidates and 2s.
+func ScoringConfigFromEnv() ScoringConfig {
+	config := ScoringConfig{MaxCandidates: defaultMaxCandidates, Timeout: defaultScoringTimeout}
+	if n, err := strconv.Atoi(os.Getenv("RECOMMENDATION_MAX_CANDIDATES")); err == nil && n >= 0 {
+		config.MaxCandidates = n
+	}
+	if d, err := time.ParseDuration(os.Getenv("RECOMMENDATION_SCORING_TIMEOUT")); err == nil && d >= 0 {
+		config.Timeout = d
+	}
+	return config
+}
+
 type RecommendationService interface {
 	// Core recommendation methods
-	GetRecommendations(userID int, req models.RecommendationRequest) (*models.RecommendationResponse, error)
+	// GetRecommendations falls back to the most popular recipes the user can
+	// eat when scoring takes longer than the configured timeout
+	GetRecommendations(ctx context.Context, userID int, req models.RecommendationRequest) (*models.RecommendationResponse, error)
+	// ServeRecommendations is GetRecommendations for showing to the user:
+	// recipes they have been shown again and again without clicking move
+	// behind the rest, and the slate served is saved for tracking
+	ServeRecommendations(ctx context.Context, userID int, req models.RecommendationRequest) (*models.RecommendationResponse, error)
 
 	// User preferences
 	GetUserPreferences(userID int) (*models.UserPreferences, error)
@@ -37,17 +102,36 @@ type RecommendationService interface {
 	// Cooking history
 	LogCooking(userID int, req models.LogCookingRequest) error
 	GetCookingHistory(userID int, limit int) ([]models.CookingHistory, error)
+	// GetCookingCalendar buckets a month of the cooking log (YYYY-MM, or this
+	// month when empty) by day
+	GetCookingCalendar(userID int, month string) (*models.CookingCalendar, error)
+
+	// Cooking photos - shared ones are only shown once a moderator approves them
+	SetCookingPhoto(userID, cookingID int, req models.CookingPhotoRequest) error
+	RemoveCookingPhoto(userID, cookingID int) error
+	GetCommunityPhotos(recipeID int, limit int) ([]models.CommunityPhoto, error)
+	GetPendingPhotos(limit int) ([]models.CookingHistory, error)
+	ReviewPhoto(cookingID int, req models.ReviewCookingPhotoRequest) error
+
+	// Stats across all users
+	GetPopularRecipes(limit int) ([]models.RecipePopularity, error)
+
+	// GetPublicRecommendations suggests recipes to anonymous visitors from
+	// popularity and what gets cooked this time of year
+	GetPublicRecommendations(limit int) ([]models.RecipeWithScore, error)
 }
 
 type recommendationService struct {
-	repo repository.RecommendationRepository
+	repo    repository.RecommendationRepository
+	scoring ScoringConfig
+	now     func() time.Time
 }
 
-func NewRecommendationService(repo repository.RecommendationRepository) RecommendationService {
-	return &recommendationService{repo: repo}
+func NewRecommendationService(repo repository.RecommendationRepository, scoring ScoringConfig) RecommendationService {
+	return &recommendationService{repo: repo, scoring: scoring, now: time.Now}
 }
 
-func (s *recommendationService) GetRecommendations(userID int, req models.RecommendationRequest) (*models.RecommendationResponse, error) {
+func (s *recommendationService) GetRecommendations(ctx context.Context, userID int, req models.RecommendationRequest) (*models.RecommendationResponse, error) {
 	if userID <= 0 {
 		return nil, ErrUserNotFound
 	}
@@ -56,40 +140,125 @@ func (s *recommendationService) GetRecommendations(userID int, req models.Recomm
 	algorithm := s.validateAlgorithm(req.Algorithm)
 	limit := s.validateLimit(req.Limit)
 
-	var recipes []models.RecipeWithScore
-	var err error
+	recipes, err := s.score(ctx, algorithm, userID, limit)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		logging.Logger.Warn("Recommendation scoring timed out, serving popular recipes",
+			"user_id", userID, "algorithm", algorithm, "timeout", s.scoring.Timeout)
+		algorithm = AlgorithmPopular
+		recipes, err = s.repo.GetPopularRecommendations(ctx, userID, seasonMonths(s.now()), limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Frozen portions are offered alongside as meals that need no cooking
+	freezerMeals, err := s.repo.GetFreezerItems(userID)
+	if err != nil {
+		return nil, err
+	}
+	generatedAt := time.Now()
+	annotateFreezerItems(freezerMeals, freezerToday(generatedAt))
 
-	// Execute appropriate algorithm
+	return &models.RecommendationResponse{
+		Recipes:      recipes,
+		Algorithm:    algorithm,
+		GeneratedAt:  generatedAt,
+		TotalScored:  len(recipes),
+		FreezerMeals: freezerMeals,
+	}, nil
+}
+
+func (s *recommendationService) ServeRecommendations(ctx context.Context, userID int, req models.RecommendationRequest) (*models.RecommendationResponse, error) {
+	if userID <= 0 {
+		return nil, ErrUserNotFound
+	}
+
+	ignored, err := s.repo.GetIgnoredRecipeIDs(userID, s.now().Add(-IgnoredSuggestionWindow), IgnoredAfterImpressions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Score enough recipes that the slate stays full once the ignored ones
+	// move to the back
+	limit := s.validateLimit(req.Limit)
+	req.Limit = limit + len(ignored)
+	response, err := s.GetRecommendations(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	response.Recipes = demoteIgnored(response.Recipes, ignored)
+	if len(response.Recipes) > limit {
+		response.Recipes = response.Recipes[:limit]
+	}
+	response.TotalScored = len(response.Recipes)
+
+	// Tracking is secondary to serving the recommendations
+	if slateID, err := s.repo.SaveSlate(userID, response.Algorithm, response.Recipes); err != nil {
+		logging.Logger.Error("Failed to save recommendation slate", "user_id", userID, "error", err)
+	} else {
+		response.SlateID = slateID
+	}
+	return response, nil
+}
+
+// score runs the algorithm within the scoring timeout. A statement the
+// timeout cancelled reports context.DeadlineExceeded, whichever way the
+// driver put it.
+func (s *recommendationService) score(ctx context.Context, algorithm string, userID, limit int) ([]models.RecipeWithScore, error) {
+	if s.scoring.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.scoring.Timeout)
+		defer cancel()
+	}
+
+	recipes, err := s.runAlgorithm(ctx, algorithm, userID, limit)
+	if err != nil && database.IsTimeout(ctx, err) {
+		return nil, fmt.Errorf("%w: %v", context.DeadlineExceeded, err)
+	}
+	return recipes, err
+}
+
+func (s *recommendationService) runAlgorithm(ctx context.Context, algorithm string, userID, limit int) ([]models.RecipeWithScore, error) {
+	candidates := s.scoring.MaxCandidates
 	switch algorithm {
 	case AlgorithmPreference:
-		recipes, err = s.repo.GetRecipesByPreferences(userID, limit)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				return nil, ErrPreferencesNotSet
-			}
-			return nil, err
+		recipes, err := s.repo.GetRecipesByPreferences(ctx, userID, limit, candidates)
+		if err == sql.ErrNoRows {
+			return nil, ErrPreferencesNotSet
 		}
+		return recipes, err
 	case AlgorithmTimeDecay:
-		recipes, err = s.repo.GetRecipesWithTimeDecayScore(userID, limit)
+		return s.repo.GetRecipesWithTimeDecayScore(ctx, userID, limit, candidates)
 	case AlgorithmHybrid:
-		recipes, err = s.repo.GetHybridRecommendations(userID, limit)
+		return s.repo.GetHybridRecommendations(ctx, userID, limit, candidates)
+	case AlgorithmLevelUp:
+		return s.repo.GetLevelUpRecommendations(ctx, userID, limit, candidates)
 	default:
 		return nil, ErrInvalidAlgorithm
 	}
+}
 
-	if err != nil {
-		return nil, err
+// demoteIgnored moves the ignored recipes behind the others, keeping the
+// order within each.
+func demoteIgnored(recipes []models.RecipeWithScore, ignored []int) []models.RecipeWithScore {
+	if len(ignored) == 0 {
+		return recipes
+	}
+	isIgnored := make(map[int]bool, len(ignored))
+	for _, id := range ignored {
+		isIgnored[id] = true
 	}
 
-	// Log recommendations for analytics (async in production)
-	go s.logRecommendations(userID, recipes, algorithm)
-
-	return &models.RecommendationResponse{
-		Recipes:     recipes,
-		Algorithm:   algorithm,
-		GeneratedAt: time.Now(),
-		TotalScored: len(recipes),
-	}, nil
+	ordered := make([]models.RecipeWithScore, 0, len(recipes))
+	var demoted []models.RecipeWithScore
+	for _, recipe := range recipes {
+		if isIgnored[recipe.ID] {
+			demoted = append(demoted, recipe)
+		} else {
+			ordered = append(ordered, recipe)
+		}
+	}
+	return append(ordered, demoted...)
 }
 
 func (s *recommendationService) GetUserPreferences(userID int) (*models.UserPreferences, error) {
@@ -104,6 +273,7 @@ func (s *recommendationService) GetUserPreferences(userID int) (*models.UserPref
 			return &models.UserPreferences{
 				UserID:              userID,
 				PreferredCategories: []int{},
+				DietaryRestrictions: models.DietaryRestrictions{Allergens: []string{}},
 				CreatedAt:           time.Now(),
 				UpdatedAt:           time.Now(),
 			}, nil
@@ -127,7 +297,64 @@ func (s *recommendationService) UpdateUserPreferences(userID int, req models.Upd
 		}
 	}
 
-	return s.repo.UpdateUserPreferences(userID, validCategories)
+	allergenCodes, err := s.repo.GetAllergenCodes()
+	if err != nil {
+		return nil, err
+	}
+	restrictions, err := normalizeDietaryRestrictions(req.DietaryRestrictions, allergenCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs, err := s.repo.UpdateUserPreferences(userID, validCategories, restrictions, expectedVersion(req.Version))
+	if err == sql.ErrNoRows {
+		current, err := s.repo.GetUserPreferences(userID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &models.VersionConflictError{CurrentVersion: current.Version}
+	}
+	return prefs, err
+}
+
+// normalizeDietaryRestrictions lowercases the diet and allergens and dedupes
+// the allergens, rejecting unknown ones. Allergens are any of the catalogue's
+// dietary flags, so a dairy-free user lists dairy, or of its allergen codes,
+// like tree_nut.
+func normalizeDietaryRestrictions(restrictions models.DietaryRestrictions, allergenCodes []string) (models.DietaryRestrictions, error) {
+	diet := strings.ToLower(strings.TrimSpace(restrictions.Diet))
+	if diet != "" && !models.IsValidDiet(diet) {
+		return models.DietaryRestrictions{}, ErrInvalidDiet
+	}
+
+	known := make(map[string]bool, len(allergenCodes))
+	for _, code := range allergenCodes {
+		known[code] = true
+	}
+
+	seen := make(map[string]bool, len(restrictions.Allergens))
+	allergens := make([]string, 0, len(restrictions.Allergens))
+	for _, allergen := range restrictions.Allergens {
+		allergen = strings.ToLower(strings.TrimSpace(allergen))
+		if !models.IsValidDietaryFlag(allergen) && !known[allergen] {
+			return models.DietaryRestrictions{}, ErrInvalidAllergens
+		}
+		if !seen[allergen] {
+			seen[allergen] = true
+			allergens = append(allergens, allergen)
+		}
+	}
+	sort.Strings(allergens)
+
+	return models.DietaryRestrictions{Diet: diet, Allergens: allergens}, nil
+}
+
+// expectedVersion is the version an update is conditional on, 0 for any.
+func expectedVersion(version *int) int {
+	if version == nil {
+		return 0
+	}
+	return *version
 }
 
 func (s *recommendationService) LogCooking(userID int, req models.LogCookingRequest) error {
@@ -143,7 +370,15 @@ func (s *recommendationService) LogCooking(userID int, req models.LogCookingRequ
 		return ErrInvalidRating
 	}
 
-	err := s.repo.LogCooking(userID, req.RecipeID, req.Rating)
+	var photo *models.CookingPhoto
+	if req.Photo != nil {
+		var err error
+		if photo, err = validatePhoto(*req.Photo); err != nil {
+			return err
+		}
+	}
+
+	err := s.repo.LogCooking(userID, req.RecipeID, req.Rating, photo)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return ErrRecipeNotFound
@@ -163,10 +398,138 @@ func (s *recommendationService) GetCookingHistory(userID int, limit int) ([]mode
 	return s.repo.GetUserCookingHistory(userID, limit)
 }
 
+func (s *recommendationService) GetCookingCalendar(userID int, month string) (*models.CookingCalendar, error) {
+	if userID <= 0 {
+		return nil, ErrUserNotFound
+	}
+
+	var start time.Time
+	if month == "" {
+		now := s.now()
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	} else {
+		var err error
+		if start, err = time.ParseInLocation(wasteMonthLayout, month, s.now().Location()); err != nil {
+			return nil, ErrInvalidMonth
+		}
+	}
+	end := start.AddDate(0, 1, 0)
+
+	cooked, err := s.repo.GetCookedBetween(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &models.CookingCalendar{Month: start.Format(wasteMonthLayout), Total: len(cooked)}
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		_, week := day.ISOWeek()
+		weekday := int(day.Weekday())
+		if weekday == 0 {
+			weekday = 7
+		}
+		calendar.Days = append(calendar.Days, models.CookingCalendarDay{
+			Date:    day.Format("2006-01-02"),
+			Week:    week,
+			Weekday: weekday,
+			Recipes: make([]models.CookedOnDay, 0),
+		})
+	}
+
+	// The log is in time order, so each day's recipes stay in the order
+	// they were cooked
+	for _, c := range cooked {
+		cookedAt := c.CookedAt.In(start.Location())
+		if cookedAt.Before(start) || !cookedAt.Before(end) {
+			continue
+		}
+		day := &calendar.Days[cookedAt.Day()-1]
+		day.Recipes = append(day.Recipes, c)
+		day.Count++
+		if day.Count > calendar.MaxCount {
+			calendar.MaxCount = day.Count
+		}
+	}
+	return calendar, nil
+}
+
+func (s *recommendationService) SetCookingPhoto(userID, cookingID int, req models.CookingPhotoRequest) error {
+	if userID <= 0 {
+		return ErrUserNotFound
+	}
+
+	photo, err := validatePhoto(req)
+	if err != nil {
+		return err
+	}
+	return s.setCookingPhoto(userID, cookingID, photo)
+}
+
+func (s *recommendationService) RemoveCookingPhoto(userID, cookingID int) error {
+	if userID <= 0 {
+		return ErrUserNotFound
+	}
+	return s.setCookingPhoto(userID, cookingID, nil)
+}
+
+func (s *recommendationService) GetCommunityPhotos(recipeID int, limit int) ([]models.CommunityPhoto, error) {
+	if recipeID <= 0 {
+		return nil, ErrRecipeNotFound
+	}
+	return s.repo.GetRecipeCommunityPhotos(recipeID, s.validateLimit(limit))
+}
+
+func (s *recommendationService) GetPendingPhotos(limit int) ([]models.CookingHistory, error) {
+	history, err := s.repo.GetPendingCookingPhotos(s.validateLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		history = []models.CookingHistory{}
+	}
+	return history, nil
+}
+
+func (s *recommendationService) ReviewPhoto(cookingID int, req models.ReviewCookingPhotoRequest) error {
+	if req.Status != models.PhotoStatusApproved && req.Status != models.PhotoStatusRejected {
+		return ErrInvalidPhotoStatus
+	}
+	if cookingID <= 0 {
+		return ErrCookingPhotoNotFound
+	}
+
+	err := s.repo.ReviewCookingPhoto(cookingID, req.Status)
+	if err == sql.ErrNoRows {
+		return ErrCookingPhotoNotFound
+	}
+	return err
+}
+
+func (s *recommendationService) GetPopularRecipes(limit int) ([]models.RecipePopularity, error) {
+	return s.repo.GetPopularRecipes(s.validateLimit(limit))
+}
+
+func (s *recommendationService) GetPublicRecommendations(limit int) ([]models.RecipeWithScore, error) {
+	recipes, err := s.repo.GetPublicRecommendations(seasonMonths(time.Now()), s.validateLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	if recipes == nil {
+		recipes = []models.RecipeWithScore{}
+	}
+	return recipes, nil
+}
+
+// seasonMonths returns the month of now with the ones either side of it, so
+// seasonal picks do not jump at the turn of a month.
+func seasonMonths(now time.Time) []int {
+	month := int(now.Month())
+	return []int{(month+10)%12 + 1, month, month%12 + 1}
+}
+
 // Helper methods
 func (s *recommendationService) validateAlgorithm(algorithm string) string {
 	switch algorithm {
-	case AlgorithmPreference, AlgorithmTimeDecay, AlgorithmHybrid:
+	case AlgorithmPreference, AlgorithmTimeDecay, AlgorithmHybrid, AlgorithmLevelUp:
 		return algorithm
 	default:
 		// Default to hybrid as it's the most sophisticated
@@ -184,9 +547,36 @@ func (s *recommendationService) validateLimit(limit int) int {
 	return limit
 }
 
-func (s *recommendationService) logRecommendations(userID int, recipes []models.RecipeWithScore, algorithm string) {
-	// In production, this would be in a goroutine or async queue
-	for _, recipe := range recipes {
-		s.repo.LogRecommendation(userID, recipe.ID, algorithm)
+func (s *recommendationService) setCookingPhoto(userID, cookingID int, photo *models.CookingPhoto) error {
+	if cookingID <= 0 {
+		return ErrCookingEntryNotFound
+	}
+
+	err := s.repo.SetCookingPhoto(userID, cookingID, photo)
+	if err == sql.ErrNoRows {
+		return ErrCookingEntryNotFound
+	}
+	return err
+}
+
+// validatePhoto checks a photo reference; the image itself is hosted elsewhere.
+// A blank caption is dropped.
+func validatePhoto(req models.CookingPhotoRequest) (*models.CookingPhoto, error) {
+	photoURL := strings.TrimSpace(req.URL)
+	parsed, err := url.Parse(photoURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, ErrInvalidPhotoURL
+	}
+
+	photo := &models.CookingPhoto{URL: photoURL, Shared: req.Share}
+	if req.Caption != nil {
+		caption := strings.TrimSpace(*req.Caption)
+		if utf8.RuneCountInString(caption) > MaxPhotoCaptionLength {
+			return nil, ErrInvalidPhotoCaption
+		}
+		if caption != "" {
+			photo.Caption = &caption
+		}
 	}
+	return photo, nil
 }
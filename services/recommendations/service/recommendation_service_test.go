@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"meal-prep/services/recommendations/repository"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowScoringRepository scores recipes only once the context gives up, as a
+// scoring query over a large catalogue would, failing with ctx.Err() or
+// canceled when set. Calling anything it does not stub panics.
+type slowScoringRepository struct {
+	repository.RecommendationRepository
+	canceled     error
+	popular      []models.RecipeWithScore
+	popularCalls int
+}
+
+func (r *slowScoringRepository) GetHybridRecommendations(ctx context.Context, userID, limit, candidates int) ([]models.RecipeWithScore, error) {
+	<-ctx.Done()
+	if r.canceled != nil {
+		return nil, r.canceled
+	}
+	return nil, ctx.Err()
+}
+
+func (r *slowScoringRepository) GetPopularRecommendations(ctx context.Context, userID int, months []int, limit int) ([]models.RecipeWithScore, error) {
+	r.popularCalls++
+	return r.popular, nil
+}
+
+func (r *slowScoringRepository) GetFreezerItems(userID int) ([]models.FreezerItem, error) {
+	return nil, nil
+}
+
+func TestGetRecommendations_ServesPopularWhenScoringTimesOut(t *testing.T) {
+	logging.Init("test")
+
+	// Arrange
+	repo := &slowScoringRepository{popular: []models.RecipeWithScore{
+		{Recipe: models.Recipe{ID: 7, Name: "Shakshuka"}, RecommendationScore: 0.9, Reason: "Popular this season"},
+	}}
+	svc := NewRecommendationService(repo, ScoringConfig{Timeout: 10 * time.Millisecond})
+
+	// Act
+	response, err := svc.GetRecommendations(context.Background(), 1, models.RecommendationRequest{Algorithm: AlgorithmHybrid})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmPopular, response.Algorithm)
+	assert.Equal(t, repo.popular, response.Recipes)
+	assert.Equal(t, 1, response.TotalScored)
+	assert.Equal(t, 1, repo.popularCalls)
+}
+
+func TestGetRecommendations_ServesPopularWhenPostgresCancelsScoring(t *testing.T) {
+	logging.Init("test")
+
+	// Arrange
+	repo := &slowScoringRepository{
+		canceled: &pq.Error{Code: "57014", Message: "canceling statement due to user request"},
+		popular:  []models.RecipeWithScore{{Recipe: models.Recipe{ID: 7, Name: "Shakshuka"}}},
+	}
+	svc := NewRecommendationService(repo, ScoringConfig{Timeout: 10 * time.Millisecond})
+
+	// Act
+	response, err := svc.GetRecommendations(context.Background(), 1, models.RecommendationRequest{Algorithm: AlgorithmHybrid})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmPopular, response.Algorithm)
+	assert.Equal(t, repo.popular, response.Recipes)
+}
+
+func TestGetRecommendations_CancelledRequestIsNotServedPopular(t *testing.T) {
+	logging.Init("test")
+
+	// Arrange
+	repo := &slowScoringRepository{}
+	svc := NewRecommendationService(repo, ScoringConfig{Timeout: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act
+	response, err := svc.GetRecommendations(ctx, 1, models.RecommendationRequest{Algorithm: AlgorithmHybrid})
+
+	// Assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, response)
+	assert.Zero(t, repo.popularCalls)
+}
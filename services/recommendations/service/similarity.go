@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"meal-prep/services/recommendations/repository"
+	"meal-prep/shared/logging"
+)
+
+const (
+	// similarityMinCoCooks is how many people must cook both recipes before
+	// they count as alike, so one person's habits don't make a pair
+	similarityMinCoCooks = 2
+
+	// similarityNeighbors is how many similar recipes are kept per recipe
+	similarityNeighbors = 50
+
+	defaultSimilarityRefreshInterval = 6 * time.Hour
+)
+
+// SimilarityRefreshIntervalFromEnv reads how often RunSimilarityRefresher
+// rebuilds recipe similarities (RECIPE_SIMILARITY_REFRESH_INTERVAL, default
+// 6h; 0 disables it).
+func SimilarityRefreshIntervalFromEnv() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("RECIPE_SIMILARITY_REFRESH_INTERVAL")); err == nil && d >= 0 {
+		return d
+	}
+	return defaultSimilarityRefreshInterval
+}
+
+// RunSimilarityRefresher rebuilds the recipe similarities hybrid
+// recommendations blend in, once at start and then every interval until
+// ctx is cancelled. A failed rebuild is logged and the previous one keeps
+// being used until the next tick.
+func RunSimilarityRefresher(ctx context.Context, repo repository.RecommendationRepository, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		pairs, err := repo.RebuildRecipeSimilarities(ctx, similarityMinCoCooks, similarityNeighbors)
+		if err != nil {
+			logging.Logger.Error("Recipe similarity rebuild failed", "error", err)
+		} else {
+			logging.Logger.Info("Recipe similarities rebuilt", "pairs", pairs,
+				"duration_ms", time.Since(start).Milliseconds())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
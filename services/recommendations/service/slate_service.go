@@ -0,0 +1,107 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"meal-prep/services/recommendations/repository"
+	"meal-prep/shared/models"
+)
+
+var (
+	ErrSlateNotFound         = errors.New("recommendation slate not found")
+	ErrRecipeNotInSlate      = errors.New("recipe is not in this slate")
+	ErrInvalidImpressions    = errors.New("recipe_ids must list between 1 and 50 recipes")
+	ErrInvalidEngagementDays = errors.New("days must be between 1 and 365")
+)
+
+const (
+	DefaultEngagementDays = 30
+	MaxEngagementDays     = 365
+)
+
+type SlateService interface {
+	// RecordImpressions notes which recipes of a served slate the user saw;
+	// recipes not in the slate are ignored
+	RecordImpressions(userID, slateID int, req models.SlateImpressionsRequest) error
+	RecordClick(userID, slateID int, req models.SlateClickRequest) error
+
+	// GetEngagement reports the click-through rate of each algorithm's
+	// slates over the last days, or DefaultEngagementDays when 0
+	GetEngagement(days int) (*models.RecommendationEngagement, error)
+}
+
+type slateService struct {
+	repo repository.RecommendationRepository
+	now  func() time.Time
+}
+
+func NewSlateService(repo repository.RecommendationRepository) SlateService {
+	return &slateService{repo: repo, now: time.Now}
+}
+
+func (s *slateService) RecordImpressions(userID, slateID int, req models.SlateImpressionsRequest) error {
+	if userID <= 0 {
+		return ErrUserNotFound
+	}
+	if len(req.RecipeIDs) == 0 || len(req.RecipeIDs) > MaxLimit {
+		return ErrInvalidImpressions
+	}
+	if slateID <= 0 {
+		return ErrSlateNotFound
+	}
+
+	_, err := s.repo.RecordSlateImpressions(userID, slateID, req.RecipeIDs)
+	if err == sql.ErrNoRows {
+		return ErrSlateNotFound
+	}
+	return err
+}
+
+func (s *slateService) RecordClick(userID, slateID int, req models.SlateClickRequest) error {
+	if userID <= 0 {
+		return ErrUserNotFound
+	}
+	if slateID <= 0 {
+		return ErrSlateNotFound
+	}
+	if req.RecipeID <= 0 {
+		return ErrRecipeNotInSlate
+	}
+
+	inSlate, err := s.repo.RecordSlateClick(userID, slateID, req.RecipeID)
+	if err == sql.ErrNoRows {
+		return ErrSlateNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !inSlate {
+		return ErrRecipeNotInSlate
+	}
+	return nil
+}
+
+func (s *slateService) GetEngagement(days int) (*models.RecommendationEngagement, error) {
+	if days == 0 {
+		days = DefaultEngagementDays
+	}
+	if days < 1 || days > MaxEngagementDays {
+		return nil, ErrInvalidEngagementDays
+	}
+
+	from := s.now().AddDate(0, 0, -days)
+	algorithms, err := s.repo.GetAlgorithmEngagement(from)
+	if err != nil {
+		return nil, err
+	}
+	for i := range algorithms {
+		if algorithms[i].Impressions > 0 {
+			ctr := float64(algorithms[i].Clicks) / float64(algorithms[i].Impressions)
+			algorithms[i].CTR = math.Round(ctr*10000) / 10000
+		}
+	}
+	return &models.RecommendationEngagement{From: from, Algorithms: algorithms}, nil
+}
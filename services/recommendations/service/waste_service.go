@@ -0,0 +1,145 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"meal-prep/services/recommendations/repository"
+	"meal-prep/shared/models"
+)
+
+var (
+	ErrIngredientNotFound   = errors.New("ingredient not found")
+	ErrWasteEntryNotFound   = errors.New("waste entry not found")
+	ErrInvalidWasteQuantity = errors.New("quantity must be greater than zero")
+	ErrInvalidWasteUnit     = errors.New("unit is required and must be at most 20 characters")
+	ErrInvalidWasteReason   = errors.New("reason must be one of: expired, spoiled, leftover, overbought, other")
+	ErrInvalidWasteDate     = errors.New("wasted_at cannot be in the future")
+	ErrInvalidMonth         = errors.New("month must be in YYYY-MM format")
+)
+
+const (
+	// MaxWasteUnitLength matches the waste_log.unit column
+	MaxWasteUnitLength = 20
+
+	wasteMonthLayout = "2006-01"
+)
+
+type WasteService interface {
+	LogWaste(userID int, req models.LogWasteRequest) (*models.WasteEntry, error)
+	GetWasteLog(userID int, limit int) ([]models.WasteEntry, error)
+	DeleteWasteEntry(userID, entryID int) error
+
+	// GetMonthlySummary totals the given YYYY-MM month; an empty month means
+	// the current one.
+	GetMonthlySummary(userID int, month string) (*models.WasteSummary, error)
+}
+
+type wasteService struct {
+	repo repository.RecommendationRepository
+	now  func() time.Time
+}
+
+func NewWasteService(repo repository.RecommendationRepository) WasteService {
+	return &wasteService{repo: repo, now: time.Now}
+}
+
+func (s *wasteService) LogWaste(userID int, req models.LogWasteRequest) (*models.WasteEntry, error) {
+	if userID <= 0 {
+		return nil, ErrUserNotFound
+	}
+	if req.IngredientID <= 0 {
+		return nil, ErrIngredientNotFound
+	}
+	if req.Quantity <= 0 {
+		return nil, ErrInvalidWasteQuantity
+	}
+	unit := strings.TrimSpace(req.Unit)
+	if unit == "" || len(unit) > MaxWasteUnitLength {
+		return nil, ErrInvalidWasteUnit
+	}
+	if !isWasteReason(req.Reason) {
+		return nil, ErrInvalidWasteReason
+	}
+
+	wastedAt := s.now()
+	if req.WastedAt != nil {
+		if req.WastedAt.After(wastedAt) {
+			return nil, ErrInvalidWasteDate
+		}
+		wastedAt = *req.WastedAt
+	}
+
+	entry, err := s.repo.LogWaste(models.WasteEntry{
+		UserID:       userID,
+		IngredientID: req.IngredientID,
+		Quantity:     req.Quantity,
+		Unit:         unit,
+		Reason:       req.Reason,
+		WastedAt:     wastedAt,
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrIngredientNotFound
+	}
+	return entry, err
+}
+
+func (s *wasteService) GetWasteLog(userID int, limit int) ([]models.WasteEntry, error) {
+	if userID <= 0 {
+		return nil, ErrUserNotFound
+	}
+	if limit <= 0 || limit > MaxLimit {
+		limit = DefaultLimit
+	}
+	return s.repo.GetWasteLog(userID, limit)
+}
+
+func (s *wasteService) DeleteWasteEntry(userID, entryID int) error {
+	if userID <= 0 {
+		return ErrUserNotFound
+	}
+	if entryID <= 0 {
+		return ErrWasteEntryNotFound
+	}
+
+	err := s.repo.DeleteWasteEntry(userID, entryID)
+	if err == sql.ErrNoRows {
+		return ErrWasteEntryNotFound
+	}
+	return err
+}
+
+func (s *wasteService) GetMonthlySummary(userID int, month string) (*models.WasteSummary, error) {
+	if userID <= 0 {
+		return nil, ErrUserNotFound
+	}
+
+	var start time.Time
+	if month == "" {
+		now := s.now()
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	} else {
+		var err error
+		if start, err = time.ParseInLocation(wasteMonthLayout, month, s.now().Location()); err != nil {
+			return nil, ErrInvalidMonth
+		}
+	}
+
+	summary, err := s.repo.GetWasteSummary(userID, start, start.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+	summary.Month = start.Format(wasteMonthLayout)
+	return summary, nil
+}
+
+func isWasteReason(reason string) bool {
+	switch reason {
+	case models.WasteReasonExpired, models.WasteReasonSpoiled, models.WasteReasonLeftover,
+		models.WasteReasonOverbought, models.WasteReasonOther:
+		return true
+	}
+	return false
+}
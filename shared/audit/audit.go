@@ -0,0 +1,256 @@
+// Package audit records who changed what through a service's API. The
+// Auditor's middleware wraps the authenticated routes: every successful POST,
+// PUT, PATCH and DELETE is written to a Store with the user who made it, the
+// entity the route acts on and the entity's JSON before and after, so the
+// handlers and services themselves don't need to know about auditing. POST
+// routes that only compute an answer are left out with Skip.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"meal-prep/shared/idempotency"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+)
+
+// maxSnapshotSize caps the JSON kept of an entity. Larger bodies, such as
+// whole lists, are recorded as null.
+const maxSnapshotSize = 64 << 10
+
+// Store keeps the audit log.
+type Store interface {
+	Record(entry models.AuditEntry) error
+	// List returns matching entries newest first, and how many match.
+	List(filter models.AuditFilter, params models.PaginationParams) ([]models.AuditEntry, int, error)
+}
+
+type Auditor struct {
+	store   Store
+	now     func() time.Time
+	skipped map[*mux.Route]bool
+}
+
+func NewAuditor(store Store) *Auditor {
+	return &Auditor{store: store, now: time.Now, skipped: make(map[*mux.Route]bool)}
+}
+
+// Skip leaves route out of the audit log and returns it. It is for POST
+// routes that change nothing, such as filters and generated lists, which
+// would otherwise be recorded as creations. Routes are skipped while they
+// are registered, before the router serves.
+func (a *Auditor) Skip(route *mux.Route) *mux.Route {
+	a.skipped[route] = true
+	return route
+}
+
+// Middleware records the mutations it wraps. It must run after
+// ExtractUserFromGatewayHeaders, and router is the service's root router:
+// the state before an update or delete is what the GET route of the same
+// path returns. Failing to record is logged and does not fail the request.
+func (a *Auditor) Middleware(router *mux.Router) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			action, ok := actionOf(r.Method)
+			if !ok || a.skipped[mux.CurrentRoute(r)] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var before json.RawMessage
+			if action != models.AuditActionCreate {
+				before = snapshot(router, r)
+			}
+
+			capture := &captureWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(capture, r)
+			if capture.status < 200 || capture.status >= 300 {
+				return
+			}
+			// A replayed response repeats a change that is already recorded
+			if capture.Header().Get(idempotency.ReplayedHeader) == "true" {
+				return
+			}
+
+			entry := models.AuditEntry{
+				Action:    action,
+				Path:      r.URL.Path,
+				Before:    before,
+				CreatedAt: a.now().UTC(),
+			}
+			if action != models.AuditActionDelete {
+				entry.After = capture.json()
+			}
+			entry.Entity, entry.EntityID = entityOf(r, entry.After)
+			if user, ok := middleware.GetUserFromGatewayContext(r.Context()); ok {
+				entry.UserID = &user.UserID
+			}
+
+			if err := a.store.Record(entry); err != nil {
+				logging.WithContext(r.Context()).Error("Failed to record audit entry",
+					"entity", entry.Entity, "action", entry.Action, "error", err)
+			}
+		})
+	}
+}
+
+func actionOf(method string) (string, bool) {
+	switch method {
+	case http.MethodPost:
+		return models.AuditActionCreate, true
+	case http.MethodPut, http.MethodPatch:
+		return models.AuditActionUpdate, true
+	case http.MethodDelete:
+		return models.AuditActionDelete, true
+	}
+	return "", false
+}
+
+// snapshot serves a GET of the request's path to the route that would
+// handle it, without the router's middleware, and returns its JSON body.
+// Paths without a GET route have no snapshot.
+func snapshot(router *mux.Router, r *http.Request) json.RawMessage {
+	get := r.Clone(r.Context())
+	get.Method = http.MethodGet
+	get.Body = http.NoBody
+	get.ContentLength = 0
+
+	var match mux.RouteMatch
+	if !router.Match(get, &match) || match.MatchErr != nil || match.Route == nil {
+		return nil
+	}
+	handler := match.Route.GetHandler()
+	if handler == nil {
+		return nil
+	}
+
+	capture := &captureWriter{ResponseWriter: discardWriter{header: make(http.Header)}, status: http.StatusOK}
+	handler.ServeHTTP(capture, mux.SetURLVars(get, match.Vars))
+	if capture.status != http.StatusOK {
+		return nil
+	}
+	return capture.json()
+}
+
+// entityOf names what a request changed from its route: the static segments
+// of the path template joined by dots, e.g. recipes.ingredients for
+// /recipes/{id}/ingredients/{ingredientId}. The entity ID is the last path
+// variable, or for creations the id of the created entity when the response
+// has one.
+func entityOf(r *http.Request, after json.RawMessage) (string, *string) {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return strings.Trim(r.URL.Path, "/"), nil
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return strings.Trim(r.URL.Path, "/"), nil
+	}
+
+	var segments []string
+	var lastVar string
+	for _, segment := range strings.Split(template, "/") {
+		switch {
+		case segment == "":
+		case strings.HasPrefix(segment, "{"):
+			name := strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+			lastVar, _, _ = strings.Cut(name, ":")
+		default:
+			segments = append(segments, segment)
+		}
+	}
+	entity := strings.Join(segments, ".")
+
+	if r.Method == http.MethodPost {
+		if id, ok := createdID(after); ok {
+			return entity, &id
+		}
+	}
+	if id, ok := mux.Vars(r)[lastVar]; ok && lastVar != "" {
+		return entity, &id
+	}
+	return entity, nil
+}
+
+// createdID reads the id field of a created entity, a number or a string.
+func createdID(after json.RawMessage) (string, bool) {
+	var created struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if len(after) == 0 || json.Unmarshal(after, &created) != nil || len(created.ID) == 0 {
+		return "", false
+	}
+
+	var number int64
+	if json.Unmarshal(created.ID, &number) == nil {
+		return strconv.FormatInt(number, 10), true
+	}
+	var text string
+	if json.Unmarshal(created.ID, &text) == nil && text != "" {
+		return text, true
+	}
+	return "", false
+}
+
+// captureWriter passes a response through while keeping its status and the
+// start of its body.
+type captureWriter struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	overflowed  bool
+	wroteHeader bool
+}
+
+func (c *captureWriter) WriteHeader(status int) {
+	if !c.wroteHeader {
+		c.status = status
+		c.wroteHeader = true
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *captureWriter) Write(p []byte) (int, error) {
+	c.wroteHeader = true
+	if !c.overflowed {
+		if c.body.Len()+len(p) > maxSnapshotSize {
+			c.overflowed = true
+			c.body.Reset()
+		} else {
+			c.body.Write(p)
+		}
+	}
+	return c.ResponseWriter.Write(p)
+}
+
+// json returns the body if it is a JSON document small enough to keep.
+func (c *captureWriter) json() json.RawMessage {
+	if c.overflowed || c.body.Len() == 0 {
+		return nil
+	}
+	if !strings.HasPrefix(c.Header().Get("Content-Type"), "application/json") {
+		return nil
+	}
+	body := bytes.TrimSpace(c.body.Bytes())
+	if !json.Valid(body) {
+		return nil
+	}
+	return json.RawMessage(append([]byte(nil), body...))
+}
+
+// discardWriter is where snapshots are served; only their body is wanted.
+type discardWriter struct {
+	header http.Header
+}
+
+func (d discardWriter) Header() http.Header         { return d.header }
+func (d discardWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (d discardWriter) WriteHeader(int)             {}
@@ -0,0 +1,239 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"meal-prep/shared/idempotency"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuditedRouter serves a tiny recipe API from a map, with every route
+// behind the auditor the way the catalogue mounts it.
+func newAuditedRouter(store Store, now time.Time) *mux.Router {
+	recipes := map[string]string{"1": "Pancakes"}
+	writeRecipe := func(w http.ResponseWriter, id string, status int) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": json.Number(id), "name": recipes[id]})
+	}
+
+	auditor := NewAuditor(store)
+	auditor.now = func() time.Time { return now }
+
+	router := mux.NewRouter()
+	protected := router.PathPrefix("").Subrouter()
+	protected.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), middleware.UserCtxKey, &middleware.UserContext{UserID: 7})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	protected.Use(auditor.Middleware(router))
+
+	protected.HandleFunc("/recipes/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		if _, ok := recipes[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeRecipe(w, id, http.StatusOK)
+	}).Methods("GET")
+	protected.HandleFunc("/recipes", func(w http.ResponseWriter, r *http.Request) {
+		recipes["2"] = "Waffles"
+		writeRecipe(w, "2", http.StatusCreated)
+	}).Methods("POST")
+	protected.HandleFunc("/recipes/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		if _, ok := recipes[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		recipes[id] = "Buttermilk pancakes"
+		writeRecipe(w, id, http.StatusOK)
+	}).Methods("PUT")
+	protected.HandleFunc("/recipes/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		delete(recipes, mux.Vars(r)["id"])
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+	protected.HandleFunc("/recipes/{id:[0-9]+}/ingredients/{ingredientId:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+	return router
+}
+
+func serve(router http.Handler, method, path string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(method, path, strings.NewReader(`{}`)))
+	return rr
+}
+
+func TestAuditorMiddleware_RecordsMutations(t *testing.T) {
+	logging.Init("test")
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	router := newAuditedRouter(store, now)
+
+	require.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/recipes/1").Code)
+	require.Equal(t, http.StatusCreated, serve(router, http.MethodPost, "/recipes").Code)
+	require.Equal(t, http.StatusOK, serve(router, http.MethodPut, "/recipes/1").Code)
+	require.Equal(t, http.StatusNoContent, serve(router, http.MethodDelete, "/recipes/2").Code)
+	require.Equal(t, http.StatusNoContent, serve(router, http.MethodDelete, "/recipes/1/ingredients/5").Code)
+
+	entries, total, err := store.List(models.AuditFilter{}, models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	require.Equal(t, 4, total) // the GET is not a change
+	for _, entry := range entries {
+		require.NotNil(t, entry.UserID)
+		assert.Equal(t, 7, *entry.UserID)
+		assert.Equal(t, now, entry.CreatedAt)
+	}
+
+	// Newest first
+	removed := entries[0]
+	assert.Equal(t, "recipes.ingredients", removed.Entity)
+	assert.Equal(t, "5", *removed.EntityID)
+	assert.Equal(t, models.AuditActionDelete, removed.Action)
+	assert.Nil(t, removed.Before) // no GET route for a single recipe ingredient
+	assert.Nil(t, removed.After)
+
+	deleted := entries[1]
+	assert.Equal(t, "recipes", deleted.Entity)
+	assert.Equal(t, "2", *deleted.EntityID)
+	assert.Equal(t, "/recipes/2", deleted.Path)
+	assert.JSONEq(t, `{"id": 2, "name": "Waffles"}`, string(deleted.Before))
+	assert.Nil(t, deleted.After)
+
+	updated := entries[2]
+	assert.Equal(t, models.AuditActionUpdate, updated.Action)
+	assert.Equal(t, "1", *updated.EntityID)
+	assert.JSONEq(t, `{"id": 1, "name": "Pancakes"}`, string(updated.Before))
+	assert.JSONEq(t, `{"id": 1, "name": "Buttermilk pancakes"}`, string(updated.After))
+
+	created := entries[3]
+	assert.Equal(t, models.AuditActionCreate, created.Action)
+	assert.Equal(t, "2", *created.EntityID) // from the response
+	assert.Nil(t, created.Before)
+	assert.JSONEq(t, `{"id": 2, "name": "Waffles"}`, string(created.After))
+}
+
+func TestAuditorMiddleware_SkipsFailedRequests(t *testing.T) {
+	store := NewMemoryStore()
+	router := newAuditedRouter(store, time.Now())
+
+	assert.Equal(t, http.StatusNotFound, serve(router, http.MethodPut, "/recipes/99").Code)
+
+	_, total, err := store.List(models.AuditFilter{}, models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+}
+
+func TestAuditorMiddleware_SkipsIdempotentReplays(t *testing.T) {
+	logging.Init("test")
+	store := NewMemoryStore()
+	router := mux.NewRouter()
+	protected := router.PathPrefix("").Subrouter()
+	protected.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), middleware.UserCtxKey, &middleware.UserContext{UserID: 7})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	protected.Use(NewAuditor(store).Middleware(router))
+	protected.Handle("/recipes", idempotency.NewKeys(idempotency.NewMemoryStore()).Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 2}`))
+		}))).Methods("POST")
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/recipes", strings.NewReader(`{}`))
+		req.Header.Set(idempotency.Header, "abc")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusCreated, rr.Code)
+	}
+
+	_, total, err := store.List(models.AuditFilter{}, models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+}
+
+func TestAuditorMiddleware_SkipsQueryRoutes(t *testing.T) {
+	store := NewMemoryStore()
+	auditor := NewAuditor(store)
+	router := mux.NewRouter()
+	protected := router.PathPrefix("").Subrouter()
+	protected.Use(auditor.Middleware(router))
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": []}`))
+	}
+	auditor.Skip(protected.HandleFunc("/grocery-list", ok).Methods("POST"))
+	protected.HandleFunc("/grocery-list/shares", ok).Methods("POST")
+
+	require.Equal(t, http.StatusOK, serve(router, http.MethodPost, "/grocery-list").Code)
+	require.Equal(t, http.StatusOK, serve(router, http.MethodPost, "/grocery-list/shares").Code)
+
+	entries, total, err := store.List(models.AuditFilter{}, models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, "/grocery-list/shares", entries[0].Path)
+}
+
+func TestAuditor_ListEntries(t *testing.T) {
+	store := NewMemoryStore()
+	userID, otherID := 7, 8
+	recipeID := "1"
+	require.NoError(t, store.Record(models.AuditEntry{UserID: &userID, Entity: "recipes", EntityID: &recipeID, Action: models.AuditActionCreate}))
+	require.NoError(t, store.Record(models.AuditEntry{UserID: &otherID, Entity: "meal-plans", Action: models.AuditActionCreate}))
+	require.NoError(t, store.Record(models.AuditEntry{UserID: &userID, Entity: "recipes", EntityID: &recipeID, Action: models.AuditActionUpdate}))
+	auditor := NewAuditor(store)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedIDs    []int
+	}{
+		{name: "everything newest first", query: "", expectedStatus: http.StatusOK, expectedIDs: []int{3, 2, 1}},
+		{name: "by user", query: "?user_id=7", expectedStatus: http.StatusOK, expectedIDs: []int{3, 1}},
+		{name: "by entity and action", query: "?entity=recipes&entity_id=1&action=create", expectedStatus: http.StatusOK, expectedIDs: []int{1}},
+		{name: "paginated", query: "?page=2&per_page=2", expectedStatus: http.StatusOK, expectedIDs: []int{1}},
+		{name: "bad user", query: "?user_id=abc", expectedStatus: http.StatusBadRequest},
+		{name: "bad action", query: "?action=archive", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			auditor.ListEntries(rr, httptest.NewRequest(http.MethodGet, "/audit"+tt.query, nil))
+
+			require.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var response struct {
+				Data []models.AuditEntry `json:"data"`
+			}
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+			ids := make([]int, 0)
+			for _, entry := range response.Data {
+				ids = append(ids, entry.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
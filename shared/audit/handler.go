@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+)
+
+// ListEntries serves GET /audit, the audit log newest first. ?user_id=,
+// ?entity=, ?entity_id= and ?action= narrow it down. Services mount it on
+// their admin routes.
+func (a *Auditor) ListEntries(w http.ResponseWriter, r *http.Request) {
+	params, err := models.ParsePaginationParams(r)
+	if err != nil {
+		models.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := models.AuditFilter{
+		Entity:   query.Get("entity"),
+		EntityID: query.Get("entity_id"),
+		Action:   query.Get("action"),
+	}
+	if userID := query.Get("user_id"); userID != "" {
+		if filter.UserID, err = strconv.Atoi(userID); err != nil || filter.UserID <= 0 {
+			models.WriteErrorResponse(w, "user_id must be a positive whole number", http.StatusBadRequest)
+			return
+		}
+	}
+	if filter.Action != "" && !models.IsValidAuditAction(filter.Action) {
+		models.WriteErrorResponse(w, "action must be create, update or delete", http.StatusBadRequest)
+		return
+	}
+
+	entries, total, err := a.store.List(filter, params)
+	if err != nil {
+		logging.WithContext(r.Context()).Error("Failed to list audit entries", "error", err)
+		models.WriteErrorResponse(w, "Failed to fetch audit log", http.StatusInternalServerError)
+		return
+	}
+
+	models.WritePaginatedResponse(w, entries, models.NewPaginationMeta(params, total), http.StatusOK)
+}
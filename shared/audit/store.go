@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sync"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+type sqlStore struct {
+	db    *database.DB
+	table string
+}
+
+// NewSQLStore keeps the audit log in table, e.g. recipe_catalogue.audit_log,
+// so each service's entries stay in its own schema.
+func NewSQLStore(db *database.DB, table string) Store {
+	return &sqlStore{db: db, table: table}
+}
+
+func (s *sqlStore) Record(entry models.AuditEntry) error {
+	_, err := s.db.Exec(`
+		INSERT INTO `+s.table+` (user_id, entity, entity_id, action, path, before_state, after_state, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		entry.UserID, entry.Entity, entry.EntityID, entry.Action, entry.Path,
+		jsonArg(entry.Before), jsonArg(entry.After), entry.CreatedAt)
+	return err
+}
+
+// auditFilterCondition matches entries against an AuditFilter passed as $1
+// to $4, each of which matches everything when zero.
+const auditFilterCondition = `($1 = 0 OR user_id = $1)
+		  AND ($2 = '' OR entity = $2)
+		  AND ($3 = '' OR entity_id = $3)
+		  AND ($4 = '' OR action = $4)`
+
+func (s *sqlStore) List(filter models.AuditFilter, params models.PaginationParams) ([]models.AuditEntry, int, error) {
+	var total int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM `+s.table+` WHERE `+auditFilterCondition,
+		filter.UserID, filter.Entity, filter.EntityID, filter.Action).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, user_id, entity, entity_id, action, path, before_state, after_state, created_at
+		FROM `+s.table+`
+		WHERE `+auditFilterCondition+`
+		ORDER BY created_at DESC, id DESC
+		LIMIT $5 OFFSET $6`,
+		filter.UserID, filter.Entity, filter.EntityID, filter.Action, params.PerPage, params.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditEntry, 0)
+	for rows.Next() {
+		var entry models.AuditEntry
+		var userID sql.NullInt64
+		var entityID, before, after sql.NullString
+		err := rows.Scan(&entry.ID, &userID, &entry.Entity, &entityID, &entry.Action, &entry.Path,
+			&before, &after, &entry.CreatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if userID.Valid {
+			id := int(userID.Int64)
+			entry.UserID = &id
+		}
+		if entityID.Valid {
+			entry.EntityID = &entityID.String
+		}
+		if before.Valid {
+			entry.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			entry.After = json.RawMessage(after.String)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, rows.Err()
+}
+
+// jsonArg passes a snapshot as text, which PostgreSQL casts to JSONB, or as
+// NULL when there is none.
+func jsonArg(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
+
+type memoryStore struct {
+	mu      sync.RWMutex
+	entries []models.AuditEntry // oldest first
+}
+
+// NewMemoryStore keeps the audit log in process memory, for services running
+// with STORAGE=memory.
+func NewMemoryStore() Store {
+	return &memoryStore{}
+}
+
+func (s *memoryStore) Record(entry models.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.ID = len(s.entries) + 1
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *memoryStore) List(filter models.AuditFilter, params models.PaginationParams) ([]models.AuditEntry, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matching []models.AuditEntry
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		if entry := s.entries[i]; matches(entry, filter) {
+			matching = append(matching, entry)
+		}
+	}
+
+	entries := make([]models.AuditEntry, 0)
+	if start := params.Offset(); start < len(matching) {
+		end := start + params.PerPage
+		if end > len(matching) {
+			end = len(matching)
+		}
+		entries = append(entries, matching[start:end]...)
+	}
+	return entries, len(matching), nil
+}
+
+func matches(entry models.AuditEntry, filter models.AuditFilter) bool {
+	switch {
+	case filter.UserID != 0 && (entry.UserID == nil || *entry.UserID != filter.UserID):
+		return false
+	case filter.Entity != "" && entry.Entity != filter.Entity:
+		return false
+	case filter.EntityID != "" && (entry.EntityID == nil || *entry.EntityID != filter.EntityID):
+		return false
+	case filter.Action != "" && entry.Action != filter.Action:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLStore_RecordAndList(t *testing.T) {
+	logging.Init("test")
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	store := NewSQLStore(&database.DB{DB: sqlDB}, "recipe_catalogue.audit_log")
+
+	userID := 7
+	recipeID := "1"
+	createdAt := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO recipe_catalogue.audit_log")).
+		WithArgs(7, "recipes", "1", models.AuditActionDelete, "/recipes/1", `{"id":1}`, nil, createdAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	require.NoError(t, store.Record(models.AuditEntry{
+		UserID: &userID, Entity: "recipes", EntityID: &recipeID, Action: models.AuditActionDelete,
+		Path: "/recipes/1", Before: json.RawMessage(`{"id":1}`), CreatedAt: createdAt,
+	}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM recipe_catalogue.audit_log")).
+		WithArgs(7, "recipes", "", "").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY created_at DESC, id DESC")).
+		WithArgs(7, "recipes", "", "", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "entity", "entity_id", "action", "path", "before_state", "after_state", "created_at"}).
+			AddRow(1, 7, "recipes", "1", models.AuditActionDelete, "/recipes/1", `{"id":1}`, nil, createdAt))
+
+	entries, total, err := store.List(models.AuditFilter{UserID: 7, Entity: "recipes"}, models.PaginationParams{Page: 1, PerPage: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []models.AuditEntry{{
+		ID: 1, UserID: &userID, Entity: "recipes", EntityID: &recipeID, Action: models.AuditActionDelete,
+		Path: "/recipes/1", Before: json.RawMessage(`{"id":1}`), CreatedAt: createdAt,
+	}}, entries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
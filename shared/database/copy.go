@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Dialect reports the SQL flavour the transaction runs against.
+func (tx *Tx) Dialect() Dialect {
+	if tx.dialect == "" {
+		return DialectPostgres
+	}
+	return tx.dialect
+}
+
+// CopyRows bulk-loads rows into table. On PostgreSQL the rows are streamed
+// with COPY FROM STDIN, which is an order of magnitude faster than one INSERT
+// per row for imports of thousands of rows; other dialects fall back to
+// per-row inserts. table may be schema-qualified.
+//
+// COPY has no ON CONFLICT clause, so callers that need upsert semantics
+// should copy into a temporary staging table and INSERT ... SELECT from it.
+func (tx *Tx) CopyRows(table string, columns []string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if tx.Dialect() != DialectPostgres {
+		return tx.insertRows(table, columns, rows)
+	}
+
+	defer tx.stats.track(context.Background(),
+		fmt.Sprintf("COPY %s (%s) FROM STDIN", table, strings.Join(columns, ", ")), nil, time.Now())
+
+	var copyQuery string
+	if schema, name, ok := strings.Cut(table, "."); ok {
+		copyQuery = pq.CopyInSchema(schema, name, columns...)
+	} else {
+		copyQuery = pq.CopyIn(table, columns...)
+	}
+
+	stmt, err := tx.Tx.Prepare(copyQuery)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if _, err := stmt.Exec(row...); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+
+	// An Exec without arguments flushes the buffered rows to the server.
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return err
+	}
+	return stmt.Close()
+}
+
+func (tx *Tx) insertRows(table string, columns []string, rows [][]interface{}) error {
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+
+	for _, row := range rows {
+		if _, err := tx.Exec(query, row...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
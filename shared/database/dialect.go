@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Dialect identifies the SQL flavour behind a DB. Repositories are written
+// for PostgreSQL; other dialects get their queries rewritten on the way out.
+type Dialect string
+
+const (
+	DialectPostgres Dialect = "postgres"
+	DialectSQLite   Dialect = "sqlite"
+)
+
+var (
+	schemaPrefixPattern = regexp.MustCompile(`\b(auth|recipe_catalogue|recommendations)\.`)
+	anyPattern          = regexp.MustCompile(`=\s*ANY\s*\(\s*(\$\d+)\s*\)`)
+	castPattern         = regexp.MustCompile(`::[a-zA-Z_]+(\[\])?`)
+	placeholderPattern  = regexp.MustCompile(`\$(\d+)`)
+)
+
+// rewriteForSQLite turns a PostgreSQL repository query into its SQLite
+// variant:
+//
+//   - schema prefixes are dropped, since SQLite keeps every table in one file
+//   - ILIKE becomes LIKE, which SQLite already matches case-insensitively
+//   - type casts such as $3::text are removed
+//   - "= ANY($n)" over a pq.Array argument is expanded into "IN (?, ?, ...)"
+//   - numbered $n placeholders become positional ? with args reordered
+func rewriteForSQLite(query string, args []interface{}) (string, []interface{}) {
+	query = schemaPrefixPattern.ReplaceAllString(query, "")
+	query = strings.ReplaceAll(query, "ILIKE", "LIKE")
+	query = castPattern.ReplaceAllString(query, "")
+	query = anyPattern.ReplaceAllString(query, "IN ($1)")
+
+	var rewrittenArgs []interface{}
+	query = placeholderPattern.ReplaceAllStringFunc(query, func(placeholder string) string {
+		n, _ := strconv.Atoi(placeholder[1:])
+		if n < 1 || n > len(args) {
+			return placeholder
+		}
+
+		arg := args[n-1]
+		if values, ok := arrayValues(arg); ok {
+			if len(values) == 0 {
+				return "NULL"
+			}
+			rewrittenArgs = append(rewrittenArgs, values...)
+			return strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		}
+
+		rewrittenArgs = append(rewrittenArgs, arg)
+		return "?"
+	})
+
+	return query, rewrittenArgs
+}
+
+// Dialect returns the SQL flavour of the connection; a DB without an explicit
+// dialect is PostgreSQL.
+func (db *DB) Dialect() Dialect {
+	if db.dialect == "" {
+		return DialectPostgres
+	}
+	return db.dialect
+}
+
+func rewriteQuery(dialect Dialect, query string, args []interface{}) (string, []interface{}) {
+	if dialect == DialectSQLite {
+		return rewriteForSQLite(query, args)
+	}
+	return query, args
+}
+
+// arrayValues unwraps the slice behind a pq.Array argument.
+func arrayValues(arg interface{}) ([]interface{}, bool) {
+	generic, ok := arg.(pq.GenericArray)
+	if !ok {
+		return nil, false
+	}
+
+	v := reflect.ValueOf(generic.A)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return nil, false
+	}
+
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values, true
+}
+
+// sqliteUniqueConstraints maps SQLite's "UNIQUE constraint failed: <columns>"
+// detail to the PostgreSQL constraint names repositories check for.
+var sqliteUniqueConstraints = map[string]string{
+	"users.email":      "users_email_key",
+	"categories.name":  "categories_name_key",
+	"ingredients.name": "ingredients_name_key",
+	"recipe_ingredients.recipe_id, recipe_ingredients.ingredient_id": "recipe_ingredients_unique_per_recipe",
+	"store_layouts.user_id, store_layouts.name":                      "store_layouts_unique_name_per_user",
+	"recipe_templates.recipe_id":                                     "recipe_templates_recipe_id_key",
+	"household_members.user_id":                                      "household_members_one_household",
+
+	"meal_plan_entries.meal_plan_id, meal_plan_entries.planned_on, meal_plan_entries.slot, meal_plan_entries.recipe_id": "meal_plan_entries_unique_slot_recipe",
+}
+
+// IsUniqueViolation reports whether err is a unique constraint violation of
+// the named PostgreSQL constraint, on either supported backend.
+func IsUniqueViolation(err error, constraint string) bool {
+	if err == nil {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505" && pqErr.Constraint == constraint
+	}
+
+	msg := err.Error()
+	const marker = "UNIQUE constraint failed: "
+	idx := strings.Index(msg, marker)
+	if idx < 0 {
+		return false
+	}
+
+	columns := msg[idx+len(marker):]
+	if end := strings.Index(columns, " ("); end >= 0 {
+		columns = columns[:end]
+	}
+	return sqliteUniqueConstraints[columns] == constraint
+}
+
+// IsTimeout reports whether err ended a query because ctx's deadline
+// passed. lib/pq cancels a statement still running when its context is
+// done, and the server's query_canceled (57014) comes back instead of
+// ctx.Err(), so that counts too once ctx is past its deadline.
+func IsTimeout(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "57014" && errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
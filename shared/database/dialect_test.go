@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewriteForSQLite_StripsPostgresOnlySyntax(t *testing.T) {
+	query, args := rewriteForSQLite(`
+		SELECT id FROM recipe_catalogue.ingredients
+		WHERE name ILIKE $1 OR description ILIKE $1
+		LIMIT $2 OFFSET $3`, []interface{}{"%egg%", 20, 0})
+
+	assert.NotContains(t, query, "recipe_catalogue.")
+	assert.NotContains(t, query, "ILIKE")
+	assert.NotContains(t, query, "$")
+	assert.Equal(t, []interface{}{"%egg%", "%egg%", 20, 0}, args)
+}
+
+func TestRewriteForSQLite_ExpandsArrayParameters(t *testing.T) {
+	query, args := rewriteForSQLite(
+		`SELECT id FROM recipe_catalogue.recipe_ingredients WHERE recipe_id = ANY($1) AND unit = $2::text`,
+		[]interface{}{pq.Array([]int{3, 5, 8}), "g"})
+
+	assert.Equal(t, `SELECT id FROM recipe_ingredients WHERE recipe_id IN (?, ?, ?) AND unit = ?`, query)
+	assert.Equal(t, []interface{}{3, 5, 8, "g"}, args)
+}
+
+func TestRewriteForSQLite_EmptyArrayMatchesNothing(t *testing.T) {
+	query, args := rewriteForSQLite(`SELECT 1 FROM auth.users WHERE id = ANY($1)`, []interface{}{pq.Array([]int{})})
+
+	assert.Equal(t, `SELECT 1 FROM users WHERE id IN (NULL)`, query)
+	assert.Empty(t, args)
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	pgErr := &pq.Error{Code: "23505", Constraint: "ingredients_name_key"}
+	sqliteErr := errors.New("constraint failed: UNIQUE constraint failed: ingredients.name (2067)")
+
+	assert.True(t, IsUniqueViolation(pgErr, "ingredients_name_key"))
+	assert.False(t, IsUniqueViolation(pgErr, "users_email_key"))
+	assert.True(t, IsUniqueViolation(sqliteErr, "ingredients_name_key"))
+	assert.False(t, IsUniqueViolation(errors.New("connection refused"), "ingredients_name_key"))
+	assert.False(t, IsUniqueViolation(nil, "ingredients_name_key"))
+}
+
+func TestIsTimeout(t *testing.T) {
+	canceled := &pq.Error{Code: "57014", Message: "canceling statement due to user request"}
+	expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	abandoned, abandon := context.WithCancel(context.Background())
+	abandon()
+
+	assert.True(t, IsTimeout(expired, canceled))
+	assert.True(t, IsTimeout(context.Background(), fmt.Errorf("scoring: %w", context.DeadlineExceeded)))
+	assert.False(t, IsTimeout(abandoned, canceled), "cancelled by the caller, not timed out")
+	assert.False(t, IsTimeout(context.Background(), canceled))
+	assert.False(t, IsTimeout(expired, &pq.Error{Code: "23505"}))
+	assert.False(t, IsTimeout(expired, nil))
+}
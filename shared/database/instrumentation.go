@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"meal-prep/shared/logging"
+)
+
+// The methods below shadow the ones promoted from *sql.DB so every repository
+// query is timed, traced as a child span of the request, and served from the
+// prepared statement cache when enabled, without the repositories having to
+// change. Transactions are not cached:
+// they are short-lived and mostly used for bulk writes.
+
+// QueryStats returns the collector used by this connection, creating it on
+// first use so a DB built from a bare *sql.DB (as in tests) still works.
+func (db *DB) QueryStats() *QueryStats {
+	db.statsOnce.Do(func() {
+		if db.stats == nil {
+			db.stats = newQueryStats(slowQueryThreshold())
+		}
+	})
+	return db.stats
+}
+
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	ctx, span := startQuerySpan(ctx, db.dialect, query)
+	defer func() { endQuerySpan(span, err) }()
+	defer db.QueryStats().track(ctx, query, args, time.Now())
+	query, args = rewriteQuery(db.dialect, query, args)
+	if stmt := db.stmts.stmt(ctx, db.DB, query); stmt != nil {
+		return stmt.QueryContext(ctx, args...)
+	}
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) (row *sql.Row) {
+	ctx, span := startQuerySpan(ctx, db.dialect, query)
+	defer func() { endQuerySpan(span, row.Err()) }()
+	defer db.QueryStats().track(ctx, query, args, time.Now())
+	query, args = rewriteQuery(db.dialect, query, args)
+	if stmt := db.stmts.stmt(ctx, db.DB, query); stmt != nil {
+		return stmt.QueryRowContext(ctx, args...)
+	}
+	return db.DB.QueryRowContext(ctx, query, args...)
+}
+
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
+	ctx, span := startQuerySpan(ctx, db.dialect, query)
+	defer func() { endQuerySpan(span, err) }()
+	defer db.QueryStats().track(ctx, query, args, time.Now())
+	query, args = rewriteQuery(db.dialect, query, args)
+	if stmt := db.stmts.stmt(ctx, db.DB, query); stmt != nil {
+		return stmt.ExecContext(ctx, args...)
+	}
+	return db.DB.ExecContext(ctx, query, args...)
+}
+
+func (db *DB) Begin() (*Tx, error) {
+	return db.BeginTx(context.Background(), nil)
+}
+
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, stats: db.QueryStats(), dialect: db.dialect}, nil
+}
+
+// Tx is an instrumented transaction; it records into the same collector as
+// the DB that started it.
+type Tx struct {
+	*sql.Tx
+	stats   *QueryStats
+	dialect Dialect
+}
+
+func (tx *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.QueryContext(context.Background(), query, args...)
+}
+
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	ctx, span := startQuerySpan(ctx, tx.dialect, query)
+	defer func() { endQuerySpan(span, err) }()
+	defer tx.stats.track(ctx, query, args, time.Now())
+	query, args = rewriteQuery(tx.dialect, query, args)
+	return tx.Tx.QueryContext(ctx, query, args...)
+}
+
+func (tx *Tx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return tx.QueryRowContext(context.Background(), query, args...)
+}
+
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) (row *sql.Row) {
+	ctx, span := startQuerySpan(ctx, tx.dialect, query)
+	defer func() { endQuerySpan(span, row.Err()) }()
+	defer tx.stats.track(ctx, query, args, time.Now())
+	query, args = rewriteQuery(tx.dialect, query, args)
+	return tx.Tx.QueryRowContext(ctx, query, args...)
+}
+
+func (tx *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.ExecContext(context.Background(), query, args...)
+}
+
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
+	ctx, span := startQuerySpan(ctx, tx.dialect, query)
+	defer func() { endQuerySpan(span, err) }()
+	defer tx.stats.track(ctx, query, args, time.Now())
+	query, args = rewriteQuery(tx.dialect, query, args)
+	return tx.Tx.ExecContext(ctx, query, args...)
+}
+
+// track records the elapsed time since start and logs the statement when it
+// was slow. Parameter values are never logged, only their types, since they
+// routinely carry emails, password hashes and other user data.
+func (s *QueryStats) track(ctx context.Context, query string, args []interface{}, start time.Time) {
+	elapsed := time.Since(start)
+	normalized := normalizeQuery(query)
+
+	if !s.observe(normalized, elapsed) || logging.Logger == nil {
+		return
+	}
+
+	logging.WithContext(ctx).Warn("Slow database query",
+		"query", normalized,
+		"params", redactArgs(args),
+		"duration_ms", toMs(elapsed),
+		"threshold_ms", toMs(s.slowThreshold),
+	)
+}
+
+func redactArgs(args []interface{}) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			redacted[i] = fmt.Sprintf("$%d=NULL", i+1)
+			continue
+		}
+		redacted[i] = fmt.Sprintf("$%d=<%T>", i+1, arg)
+	}
+	return redacted
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"meal-prep/shared/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []interface{}
+		expected []string
+	}{
+		{"no args", nil, []string{}},
+		{"types only", []interface{}{"cook@example.com", 42, 1.5, true},
+			[]string{"$1=<string>", "$2=<int>", "$3=<float64>", "$4=<bool>"}},
+		{"null", []interface{}{nil, "x"}, []string{"$1=NULL", "$2=<string>"}},
+		{"bytes and times", []interface{}{[]byte("$2a$10$hash"), time.Time{}},
+			[]string{"$1=<[]uint8>", "$2=<time.Time>"}},
+		{"pointers", []interface{}{new(string)}, []string{"$1=<*string>"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, redactArgs(tt.args))
+		})
+	}
+}
+
+// captureLogs sends the shared logger to a buffer for the rest of the test.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := logging.Logger
+	logging.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+	t.Cleanup(func() { logging.Logger = previous })
+	return &buf
+}
+
+func TestQueryStats_TrackLogsSlowQueriesWithoutValues(t *testing.T) {
+	logs := captureLogs(t)
+	stats := newQueryStats(time.Millisecond)
+
+	stats.track(context.Background(), "SELECT id\n\tFROM auth.users WHERE email = $1 AND password_hash = $2",
+		[]interface{}{"cook@example.com", "$2a$10$secret"}, time.Now().Add(-10*time.Millisecond))
+
+	logged := logs.String()
+	assert.Contains(t, logged, "Slow database query")
+	assert.Contains(t, logged, "SELECT id FROM auth.users WHERE email = $1 AND password_hash = $2")
+	assert.Contains(t, logged, `"$1=<string>"`)
+	assert.NotContains(t, logged, "cook@example.com")
+	assert.NotContains(t, logged, "secret")
+}
+
+func TestQueryStats_TrackSkipsFastQueries(t *testing.T) {
+	logs := captureLogs(t)
+	stats := newQueryStats(time.Hour)
+
+	stats.track(context.Background(), "SELECT 1", nil, time.Now())
+
+	assert.Empty(t, logs.String())
+	require.Len(t, stats.Snapshot(), 1)
+	assert.Equal(t, uint64(1), stats.Snapshot()[0].Count)
+}
+
+func TestDB_RecordsEveryStatement(t *testing.T) {
+	captureLogs(t)
+	db, _ := newCountingDB(t)
+	db.stats = newQueryStats(time.Hour)
+
+	var n int
+	require.NoError(t, db.QueryRow("SELECT  1").Scan(&n))
+	rows, err := db.Query("SELECT 1")
+	require.NoError(t, err)
+	rows.Close()
+	_, err = db.Exec("SELECT\n1")
+	require.NoError(t, err)
+
+	stats := db.QueryStats().Snapshot()
+	require.Len(t, stats, 1)
+	assert.Equal(t, "SELECT 1", stats[0].Query)
+	assert.Equal(t, uint64(3), stats[0].Count)
+}
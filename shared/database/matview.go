@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"meal-prep/shared/logging"
+)
+
+const defaultViewRefreshInterval = 15 * time.Minute
+
+// ViewRefreshIntervalFromEnv reads STATS_REFRESH_INTERVAL, the pause between
+// materialized view refreshes. "0s" turns the job off.
+func ViewRefreshIntervalFromEnv() time.Duration {
+	return envDuration("STATS_REFRESH_INTERVAL", defaultViewRefreshInterval)
+}
+
+// RefreshMaterializedViews recomputes each view in turn. CONCURRENTLY keeps
+// the old contents readable while the refresh runs; it needs a unique index
+// on the view, which every stats view migration creates. Dialects without
+// materialized views (SQLite defines them as plain views) have nothing to do.
+func (db *DB) RefreshMaterializedViews(ctx context.Context, views ...string) error {
+	if db.Dialect() != DialectPostgres {
+		return nil
+	}
+	for _, view := range views {
+		if _, err := db.ExecContext(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY "+view); err != nil {
+			return fmt.Errorf("refresh %s: %w", view, err)
+		}
+	}
+	return nil
+}
+
+// RunViewRefresher refreshes views every interval until ctx is cancelled. A
+// failed refresh is logged and retried on the next tick; readers keep seeing
+// the previous snapshot in the meantime.
+func (db *DB) RunViewRefresher(ctx context.Context, interval time.Duration, views ...string) {
+	if interval <= 0 || len(views) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if err := db.RefreshMaterializedViews(ctx, views...); err != nil {
+				logging.Logger.Error("Materialized view refresh failed", "views", views, "error", err)
+				continue
+			}
+			logging.Logger.Debug("Materialized views refreshed", "views", views,
+				"duration_ms", toMs(time.Since(start)))
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"meal-prep/shared/logging"
+)
+
+// migrationFilePattern matches the Flyway-style names the migration files
+// already use, e.g. V007__add_user_id_to_recipes.sql.
+var migrationFilePattern = regexp.MustCompile(`^V(\d+)__(\w+)\.sql$`)
+
+// Migration is one versioned SQL file of a schema.
+type Migration struct {
+	Version     int
+	Description string
+	SQL         string
+	Checksum    string
+}
+
+// MigrationSet is the migrations of one service schema. Which of them have
+// run is recorded in the schema's own schema_migrations table, so each
+// service's schema is versioned on its own.
+type MigrationSet struct {
+	Schema     string
+	Migrations []Migration
+}
+
+// MigrationState is a migration and whether it has been applied.
+type MigrationState struct {
+	Migration
+	Applied bool
+}
+
+// LoadMigrations reads the V<version>__<description>.sql files at the root
+// of fsys, ordered by version. Other files are ignored.
+func LoadMigrations(schema string, fsys fs.FS) (MigrationSet, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return MigrationSet{}, err
+	}
+
+	set := MigrationSet{Schema: schema}
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(match[1])
+		if other, ok := seen[version]; ok {
+			return MigrationSet{}, fmt.Errorf("%s: version %d used by both %s and %s", schema, version, other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return MigrationSet{}, err
+		}
+		sum := sha256.Sum256(data)
+		set.Migrations = append(set.Migrations, Migration{
+			Version:     version,
+			Description: strings.ReplaceAll(match[2], "_", " "),
+			SQL:         string(data),
+			Checksum:    hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(set.Migrations, func(i, j int) bool {
+		return set.Migrations[i].Version < set.Migrations[j].Version
+	})
+	return set, nil
+}
+
+// Migrate applies the migrations of set that haven't run yet, each in its own
+// transaction, and returns them. A session advisory lock keeps replicas
+// starting together from applying the same migration twice. An applied
+// migration whose file has since changed stops the run, since the schema no
+// longer matches what the file describes. Only PostgreSQL is migrated; SQLite
+// gets its whole schema when the file is opened.
+func (db *DB) Migrate(ctx context.Context, set MigrationSet) ([]Migration, error) {
+	if db.Dialect() != DialectPostgres {
+		return nil, nil
+	}
+
+	conn, err := db.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", set.Schema); err != nil {
+		return nil, fmt.Errorf("lock %s migrations: %w", set.Schema, err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", set.Schema)
+
+	applied, err := appliedMigrations(ctx, conn, set.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, m := range set.Migrations {
+		if checksum, ok := applied[m.Version]; ok {
+			if checksum != "" && checksum != m.Checksum {
+				return ran, fmt.Errorf("%s migration V%03d was changed after it was applied", set.Schema, m.Version)
+			}
+			continue
+		}
+
+		if err := applyMigration(ctx, conn, set.Schema, m); err != nil {
+			return ran, fmt.Errorf("%s migration V%03d (%s): %w", set.Schema, m.Version, m.Description, err)
+		}
+		logging.Logger.Info("Applied migration", "schema", set.Schema, "version", m.Version, "description", m.Description)
+		ran = append(ran, m)
+	}
+	return ran, nil
+}
+
+// MigrationStatus reports which migrations of set have been applied.
+func (db *DB) MigrationStatus(ctx context.Context, set MigrationSet) ([]MigrationState, error) {
+	if db.Dialect() != DialectPostgres {
+		return nil, fmt.Errorf("migrations are only tracked on PostgreSQL")
+	}
+
+	conn, err := db.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := appliedMigrations(ctx, conn, set.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, len(set.Migrations))
+	for i, m := range set.Migrations {
+		_, ok := applied[m.Version]
+		states[i] = MigrationState{Migration: m, Applied: ok}
+	}
+	return states, nil
+}
+
+// appliedMigrations creates the history table if needed and returns the
+// checksum of every applied version. Versions the Flyway containers applied
+// are adopted, with no checksum, so neither runner repeats the other's work.
+func appliedMigrations(ctx context.Context, conn *sql.Conn, schema string) (map[int]string, error) {
+	_, err := conn.ExecContext(ctx, fmt.Sprintf(`
+		CREATE SCHEMA IF NOT EXISTS %[1]s;
+		CREATE TABLE IF NOT EXISTS %[1]s.schema_migrations (
+			version INTEGER PRIMARY KEY,
+			description TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`, schema))
+	if err != nil {
+		return nil, fmt.Errorf("create %s.schema_migrations: %w", schema, err)
+	}
+
+	_, err = conn.ExecContext(ctx, fmt.Sprintf(`
+		DO $$
+		BEGIN
+			IF to_regclass('%[1]s.flyway_schema_history') IS NOT NULL THEN
+				INSERT INTO %[1]s.schema_migrations (version, description, checksum)
+				SELECT version::int, description, ''
+				FROM %[1]s.flyway_schema_history
+				WHERE success AND version IS NOT NULL
+				ON CONFLICT (version) DO NOTHING;
+			END IF;
+		END $$`, schema))
+	if err != nil {
+		return nil, fmt.Errorf("adopt %s Flyway history: %w", schema, err)
+	}
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT version, checksum FROM %s.schema_migrations", schema))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]string)
+	for rows.Next() {
+		var version int
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, err
+		}
+		applied[version] = checksum
+	}
+	return applied, rows.Err()
+}
+
+func applyMigration(ctx context.Context, conn *sql.Conn, schema string, m Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(
+		"INSERT INTO %s.schema_migrations (version, description, checksum) VALUES ($1, $2, $3)", schema),
+		m.Version, m.Description, m.Checksum)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
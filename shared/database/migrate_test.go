@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"meal-prep/shared/logging"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMigrations(t *testing.T) MigrationSet {
+	t.Helper()
+	set, err := LoadMigrations("cookbook", fstest.MapFS{
+		"V002__add_notes.sql":     {Data: []byte("ALTER TABLE cookbook.recipes ADD COLUMN notes TEXT;")},
+		"V001__create_schema.sql": {Data: []byte("CREATE TABLE cookbook.recipes (id SERIAL PRIMARY KEY);")},
+		"README.md":               {Data: []byte("not a migration")},
+	})
+	require.NoError(t, err)
+	return set
+}
+
+func TestLoadMigrations_OrdersByVersion(t *testing.T) {
+	set := testMigrations(t)
+
+	assert.Equal(t, "cookbook", set.Schema)
+	require.Len(t, set.Migrations, 2)
+	assert.Equal(t, 1, set.Migrations[0].Version)
+	assert.Equal(t, "create schema", set.Migrations[0].Description)
+	assert.Equal(t, 2, set.Migrations[1].Version)
+	assert.Len(t, set.Migrations[1].Checksum, 64)
+}
+
+func TestLoadMigrations_RejectsDuplicateVersions(t *testing.T) {
+	_, err := LoadMigrations("cookbook", fstest.MapFS{
+		"V001__create_schema.sql": {Data: []byte("SELECT 1;")},
+		"V1__create_tables.sql":   {Data: []byte("SELECT 1;")},
+	})
+
+	assert.ErrorContains(t, err, "version 1 used by both")
+}
+
+func expectMigrationHistory(mock sqlmock.Sqlmock, rows *sqlmock.Rows) {
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS cookbook.schema_migrations")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("to_regclass('cookbook.flyway_schema_history')")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, checksum FROM cookbook.schema_migrations")).
+		WillReturnRows(rows)
+}
+
+func TestMigrate_AppliesPendingMigrationsUnderLock(t *testing.T) {
+	logging.Init("test")
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	db := &DB{DB: sqlDB}
+	set := testMigrations(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock(hashtext($1))")).
+		WithArgs("cookbook").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectMigrationHistory(mock, sqlmock.NewRows([]string{"version", "checksum"}).AddRow(1, set.Migrations[0].Checksum))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("ADD COLUMN notes")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO cookbook.schema_migrations")).
+		WithArgs(2, "add notes", set.Migrations[1].Checksum).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock(hashtext($1))")).
+		WithArgs("cookbook").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ran, err := db.Migrate(context.Background(), set)
+
+	require.NoError(t, err)
+	require.Len(t, ran, 1)
+	assert.Equal(t, 2, ran[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrate_StopsWhenAnAppliedMigrationChanged(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	db := &DB{DB: sqlDB}
+
+	mock.ExpectExec(regexp.QuoteMeta("pg_advisory_lock")).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectMigrationHistory(mock, sqlmock.NewRows([]string{"version", "checksum"}).AddRow(1, "edited"))
+	mock.ExpectExec(regexp.QuoteMeta("pg_advisory_unlock")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ran, err := db.Migrate(context.Background(), testMigrations(t))
+
+	assert.ErrorContains(t, err, "cookbook migration V001 was changed after it was applied")
+	assert.Empty(t, ran)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrationStatus_TreatsAdoptedFlywayVersionsAsApplied(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	db := &DB{DB: sqlDB}
+
+	expectMigrationHistory(mock, sqlmock.NewRows([]string{"version", "checksum"}).AddRow(1, ""))
+
+	states, err := db.MigrationStatus(context.Background(), testMigrations(t))
+
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	assert.True(t, states[0].Applied)
+	assert.False(t, states[1].Applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrate_SkipsSQLite(t *testing.T) {
+	ran, err := (&DB{dialect: DialectSQLite}).Migrate(context.Background(), testMigrations(t))
+
+	assert.NoError(t, err)
+	assert.Empty(t, ran)
+}
@@ -3,7 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
-	"os"
+	"sync"
 
 	"meal-prep/shared/logging"
 
@@ -12,14 +12,27 @@ import (
 
 type DB struct {
 	*sql.DB
+
+	dialect   Dialect
+	statsOnce sync.Once
+	stats     *QueryStats
+	stmts     *stmtCache
 }
 
+// NewPostgresConnection opens the home database from DB_HOST, DB_PORT,
+// DB_USER, DB_PASSWORD and DB_NAME.
 func NewPostgresConnection() (*DB, error) {
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
+	return newPostgresConnection("")
+}
+
+// newPostgresConnection opens the database of region, or the home database
+// when region is empty; see regionEnv for the variables read.
+func newPostgresConnection(region string) (*DB, error) {
+	host := regionEnv(region, "HOST")
+	port := regionEnv(region, "PORT")
+	user := regionEnv(region, "USER")
+	password := regionEnv(region, "PASSWORD")
+	dbname := regionEnv(region, "NAME")
 
 	if host == "" {
 		host = "localhost"
@@ -31,7 +44,7 @@ func NewPostgresConnection() (*DB, error) {
 	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		host, port, user, password, dbname)
 
-	logging.Logger.Debug("Connecting to database", "host", host, "port", port, "database", dbname)
+	logging.Logger.Debug("Connecting to database", "host", host, "port", port, "database", dbname, "region", region)
 
 	db, err := sql.Open("postgres", psqlInfo)
 	if err != nil {
@@ -39,9 +52,13 @@ func NewPostgresConnection() (*DB, error) {
 	}
 
 	if err = db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	logging.Logger.Info("Successfully connected to PostgreSQL")
-	return &DB{db}, nil
+	threshold := slowQueryThreshold()
+	cacheSize := statementCacheSize()
+	logging.Logger.Info("Successfully connected to PostgreSQL", "region", region,
+		"slow_query_threshold", threshold.String(), "statement_cache_size", cacheSize)
+	return &DB{DB: db, dialect: DialectPostgres, stats: newQueryStats(threshold), stmts: newStmtCache(cacheSize)}, nil
 }
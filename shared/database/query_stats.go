@@ -0,0 +1,188 @@
+package database
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultSlowQueryThreshold = 200 * time.Millisecond
+
+// queryDurationBuckets are the upper bounds (inclusive) of the duration histogram.
+var queryDurationBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// HistogramBucket is a cumulative count of executions at or below UpperBoundMs.
+// The last bucket of every histogram has a zero bound and stands for +Inf.
+type HistogramBucket struct {
+	UpperBoundMs float64 `json:"le_ms"`
+	Count        uint64  `json:"count"`
+}
+
+// QueryStat summarises every execution of one normalized query.
+type QueryStat struct {
+	Query     string            `json:"query"`
+	Count     uint64            `json:"count"`
+	SlowCount uint64            `json:"slow_count"`
+	TotalMs   float64           `json:"total_ms"`
+	MaxMs     float64           `json:"max_ms"`
+	AvgMs     float64           `json:"avg_ms"`
+	Buckets   []HistogramBucket `json:"buckets"`
+}
+
+type queryHistogram struct {
+	counts    []uint64 // one per bucket plus the +Inf bucket
+	count     uint64
+	slowCount uint64
+	total     time.Duration
+	max       time.Duration
+}
+
+// QueryStats collects per-query duration histograms in memory.
+type QueryStats struct {
+	mu            sync.Mutex
+	slowThreshold time.Duration
+	queries       map[string]*queryHistogram
+}
+
+func newQueryStats(slowThreshold time.Duration) *QueryStats {
+	return &QueryStats{
+		slowThreshold: slowThreshold,
+		queries:       make(map[string]*queryHistogram),
+	}
+}
+
+// slowQueryThreshold reads DB_SLOW_QUERY_THRESHOLD, accepting either a Go
+// duration ("150ms") or a bare number of milliseconds.
+func slowQueryThreshold() time.Duration {
+	value := os.Getenv("DB_SLOW_QUERY_THRESHOLD")
+	if value == "" {
+		return defaultSlowQueryThreshold
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return defaultSlowQueryThreshold
+}
+
+// observe records one execution and reports whether it crossed the slow threshold.
+func (s *QueryStats) observe(query string, elapsed time.Duration) bool {
+	slow := elapsed >= s.slowThreshold
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, ok := s.queries[query]
+	if !ok {
+		h = &queryHistogram{counts: make([]uint64, len(queryDurationBuckets)+1)}
+		s.queries[query] = h
+	}
+
+	idx := sort.Search(len(queryDurationBuckets), func(i int) bool {
+		return elapsed <= queryDurationBuckets[i]
+	})
+	h.counts[idx]++
+	h.count++
+	h.total += elapsed
+	if elapsed > h.max {
+		h.max = elapsed
+	}
+	if slow {
+		h.slowCount++
+	}
+
+	return slow
+}
+
+// Snapshot returns the statistics of every query seen so far.
+func (s *QueryStats) Snapshot() []QueryStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]QueryStat, 0, len(s.queries))
+	for query, h := range s.queries {
+		buckets := make([]HistogramBucket, len(h.counts))
+		var cumulative uint64
+		for i, c := range h.counts {
+			cumulative += c
+			buckets[i].Count = cumulative
+			if i < len(queryDurationBuckets) {
+				buckets[i].UpperBoundMs = toMs(queryDurationBuckets[i])
+			}
+		}
+
+		stats = append(stats, QueryStat{
+			Query:     query,
+			Count:     h.count,
+			SlowCount: h.slowCount,
+			TotalMs:   toMs(h.total),
+			MaxMs:     toMs(h.max),
+			AvgMs:     toMs(h.total) / float64(h.count),
+			Buckets:   buckets,
+		})
+	}
+
+	return stats
+}
+
+// TopSlowQueries returns up to n queries ordered by their slowest execution.
+func (s *QueryStats) TopSlowQueries(n int) []QueryStat {
+	stats := s.Snapshot()
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].MaxMs == stats[j].MaxMs {
+			return stats[i].AvgMs > stats[j].AvgMs
+		}
+		return stats[i].MaxMs > stats[j].MaxMs
+	})
+
+	if n > 0 && len(stats) > n {
+		stats = stats[:n]
+	}
+	return stats
+}
+
+// SlowQueriesHandler serves the top-N slow queries as JSON. The limit can be
+// overridden per request with ?limit=.
+func (db *DB) SlowQueriesHandler(defaultLimit int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := defaultLimit
+		if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+			limit = l
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"slow_threshold_ms": toMs(db.QueryStats().slowThreshold),
+			"queries":           db.QueryStats().TopSlowQueries(limit),
+		})
+	})
+}
+
+// normalizeQuery collapses whitespace so the same statement always maps to
+// the same histogram regardless of how it was indented in the source.
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+func toMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryStats_BucketsAreCumulativeAndInclusive(t *testing.T) {
+	stats := newQueryStats(time.Second)
+	for _, elapsed := range []time.Duration{
+		500 * time.Microsecond, // le 1ms
+		time.Millisecond,       // le 1ms, bounds are inclusive
+		3 * time.Millisecond,   // le 5ms
+		300 * time.Millisecond, // le 500ms
+		10 * time.Second,       // +Inf
+	} {
+		stats.observe("SELECT 1", elapsed)
+	}
+
+	snapshot := stats.Snapshot()
+	require.Len(t, snapshot, 1)
+	buckets := snapshot[0].Buckets
+	require.Len(t, buckets, len(queryDurationBuckets)+1)
+
+	expected := map[float64]uint64{1: 2, 5: 3, 10: 3, 250: 3, 500: 4, 5000: 4}
+	for _, bucket := range buckets[:len(buckets)-1] {
+		if count, ok := expected[bucket.UpperBoundMs]; ok {
+			assert.Equal(t, count, bucket.Count, "le %vms", bucket.UpperBoundMs)
+		}
+	}
+	inf := buckets[len(buckets)-1]
+	assert.Zero(t, inf.UpperBoundMs)
+	assert.Equal(t, uint64(5), inf.Count)
+}
+
+func TestQueryStats_Totals(t *testing.T) {
+	stats := newQueryStats(100 * time.Millisecond)
+	stats.observe("SELECT 1", 50*time.Millisecond)
+	stats.observe("SELECT 1", 150*time.Millisecond)
+
+	stat := stats.Snapshot()[0]
+	assert.Equal(t, "SELECT 1", stat.Query)
+	assert.Equal(t, uint64(2), stat.Count)
+	assert.Equal(t, uint64(1), stat.SlowCount)
+	assert.Equal(t, 200.0, stat.TotalMs)
+	assert.Equal(t, 150.0, stat.MaxMs)
+	assert.Equal(t, 100.0, stat.AvgMs)
+}
+
+func TestQueryStats_SlowThreshold(t *testing.T) {
+	stats := newQueryStats(100 * time.Millisecond)
+
+	tests := []struct {
+		elapsed time.Duration
+		slow    bool
+	}{
+		{99 * time.Millisecond, false},
+		{100 * time.Millisecond, true},
+		{time.Second, true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.slow, stats.observe("SELECT 1", tt.elapsed), "%v", tt.elapsed)
+	}
+}
+
+func TestSlowQueryThreshold(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", defaultSlowQueryThreshold},
+		{"150ms", 150 * time.Millisecond},
+		{"2s", 2 * time.Second},
+		{"75", 75 * time.Millisecond},
+		{"0", defaultSlowQueryThreshold},
+		{"-5ms", defaultSlowQueryThreshold},
+		{"fast", defaultSlowQueryThreshold},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("DB_SLOW_QUERY_THRESHOLD", tt.value)
+			assert.Equal(t, tt.expected, slowQueryThreshold())
+		})
+	}
+}
+
+func TestQueryStats_TopSlowQueries(t *testing.T) {
+	stats := newQueryStats(time.Second)
+	stats.observe("SELECT fast", time.Millisecond)
+	stats.observe("SELECT slowest", 400*time.Millisecond)
+	// Ties on the slowest execution go to the higher average
+	stats.observe("SELECT steady", 200*time.Millisecond)
+	stats.observe("SELECT steady", 200*time.Millisecond)
+	stats.observe("SELECT spiky", 200*time.Millisecond)
+	stats.observe("SELECT spiky", 10*time.Millisecond)
+
+	var queries []string
+	for _, stat := range stats.TopSlowQueries(3) {
+		queries = append(queries, stat.Query)
+	}
+	assert.Equal(t, []string{"SELECT slowest", "SELECT steady", "SELECT spiky"}, queries)
+
+	assert.Len(t, stats.TopSlowQueries(0), 4)
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	assert.Equal(t, "SELECT id FROM recipes WHERE id = $1",
+		normalizeQuery("\n\t\tSELECT id\n\t\tFROM recipes\n\t\tWHERE id = $1  "))
+}
+
+func TestSlowQueriesHandler(t *testing.T) {
+	db := &DB{stats: newQueryStats(250 * time.Millisecond)}
+	db.QueryStats().observe("SELECT a", 300*time.Millisecond)
+	db.QueryStats().observe("SELECT b", 20*time.Millisecond)
+	db.QueryStats().observe("SELECT c", 5*time.Millisecond)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"default limit", "", []string{"SELECT a", "SELECT b"}},
+		{"limit parameter", "?limit=1", []string{"SELECT a"}},
+		{"bad limit", "?limit=none", []string{"SELECT a", "SELECT b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			db.SlowQueriesHandler(2).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/queries"+tt.query, nil))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			var body struct {
+				SlowThresholdMs float64     `json:"slow_threshold_ms"`
+				Queries         []QueryStat `json:"queries"`
+			}
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+			assert.Equal(t, 250.0, body.SlowThresholdMs)
+			var queries []string
+			for _, stat := range body.Queries {
+				queries = append(queries, stat.Query)
+			}
+			assert.Equal(t, tt.expected, queries)
+		})
+	}
+}
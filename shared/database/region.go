@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"meal-prep/shared/logging"
+)
+
+// Data residency: a user can be tied to a region, e.g. "eu", whose data must
+// stay in that region's database. DATA_REGIONS lists the regions an
+// instance serves beside its home database, where users without a region
+// live. Each region's database is configured like the home one, with the
+// region in the variable names: DB_EU_HOST, DB_EU_NAME and so on, each
+// falling back to the home setting.
+
+var regionPattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// RegionsFromEnv returns the regions listed in DATA_REGIONS, comma
+// separated, lower-cased.
+func RegionsFromEnv() []string {
+	var regions []string
+	for _, region := range strings.Split(os.Getenv("DATA_REGIONS"), ",") {
+		if region = strings.ToLower(strings.TrimSpace(region)); region != "" {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// NewRegionConnection opens the PostgreSQL database of region. Region names
+// are letters and digits, as they become part of variable names.
+func NewRegionConnection(region string) (*DB, error) {
+	if !regionPattern.MatchString(region) {
+		return nil, fmt.Errorf("invalid data region %q", region)
+	}
+	return newPostgresConnection(region)
+}
+
+// ConnectRegions opens the database of each region with ConnectWithRetry.
+// If one fails, those already opened are closed.
+func ConnectRegions(ctx context.Context, cfg RetryConfig, regions []string) (map[string]*DB, error) {
+	dbs := make(map[string]*DB, len(regions))
+	for _, region := range regions {
+		db, err := ConnectWithRetry(ctx, cfg, func() (*DB, error) { return NewRegionConnection(region) })
+		if err != nil {
+			for _, opened := range dbs {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("region %s: %w", region, err)
+		}
+		logging.Logger.Info("Region database connected", "region", region)
+		dbs[region] = db
+	}
+	return dbs, nil
+}
+
+// regionEnv reads DB_<REGION>_<name>, falling back to DB_<name>, or just
+// DB_<name> for the home database.
+func regionEnv(region, name string) string {
+	if region != "" {
+		if value := os.Getenv("DB_" + strings.ToUpper(region) + "_" + name); value != "" {
+			return value
+		}
+	}
+	return os.Getenv("DB_" + name)
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegionsFromEnv(t *testing.T) {
+	t.Setenv("DATA_REGIONS", " EU, us ,,")
+	assert.Equal(t, []string{"eu", "us"}, RegionsFromEnv())
+
+	t.Setenv("DATA_REGIONS", "")
+	assert.Empty(t, RegionsFromEnv())
+}
+
+func TestRegionEnv_FallsBackToHomeSettings(t *testing.T) {
+	t.Setenv("DB_HOST", "db.home")
+	t.Setenv("DB_NAME", "mealprep")
+	t.Setenv("DB_EU_HOST", "db.eu")
+
+	assert.Equal(t, "db.eu", regionEnv("eu", "HOST"))
+	assert.Equal(t, "mealprep", regionEnv("eu", "NAME"))
+	assert.Equal(t, "db.home", regionEnv("", "HOST"))
+}
+
+func TestNewRegionConnection_RejectsInvalidNames(t *testing.T) {
+	for _, region := range []string{"", "eu-west", "1eu", "EU"} {
+		_, err := NewRegionConnection(region)
+		assert.Error(t, err, region)
+	}
+}
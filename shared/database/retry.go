@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"meal-prep/shared/logging"
+)
+
+const (
+	defaultWaitForDepsMaxWait = 2 * time.Minute
+	// minRetryBackoff keeps a zero or tiny configured backoff from retrying
+	// in a tight loop
+	minRetryBackoff = 100 * time.Millisecond
+)
+
+// RetryConfig controls how long a service keeps trying to reach its database
+// on start-up. A zero MaxWait means a single attempt.
+type RetryConfig struct {
+	MaxWait        time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// RetryConfigFromEnv reads DB_CONNECT_MAX_WAIT, DB_CONNECT_INITIAL_BACKOFF and
+// DB_CONNECT_MAX_BACKOFF. waitForDeps (the --wait-for-deps flag) turns retries
+// on with a two minute budget when no explicit maximum wait is configured.
+func RetryConfigFromEnv(waitForDeps bool) RetryConfig {
+	cfg := RetryConfig{
+		MaxWait:        envDuration("DB_CONNECT_MAX_WAIT", 0),
+		InitialBackoff: envDuration("DB_CONNECT_INITIAL_BACKOFF", 500*time.Millisecond),
+		MaxBackoff:     envDuration("DB_CONNECT_MAX_BACKOFF", 10*time.Second),
+	}
+	if waitForDeps && cfg.MaxWait == 0 {
+		cfg.MaxWait = defaultWaitForDepsMaxWait
+	}
+	return cfg
+}
+
+// ConnectWithRetry calls connect until it succeeds or cfg.MaxWait has
+// elapsed, doubling the pause between attempts up to cfg.MaxBackoff. Pauses
+// are at least minRetryBackoff. The last connection error is returned when the
+// budget runs out, and ctx's error if ctx is done first, so a shutdown signal
+// stops the wait.
+func ConnectWithRetry(ctx context.Context, cfg RetryConfig, connect func() (*DB, error)) (*DB, error) {
+	deadline := time.Now().Add(cfg.MaxWait)
+	backoff := max(cfg.InitialBackoff, minRetryBackoff)
+	maxBackoff := max(cfg.MaxBackoff, backoff)
+
+	for attempt := 1; ; attempt++ {
+		db, err := connect()
+		if err == nil {
+			return db, nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, err
+		}
+
+		wait := min(backoff, remaining)
+		logging.Logger.Warn("Database not reachable yet, retrying",
+			"attempt", attempt,
+			"retry_in", wait.String(),
+			"error", err,
+		)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d >= 0 {
+		return d
+	}
+	return fallback
+}
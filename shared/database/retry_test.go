@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"meal-prep/shared/logging"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectWithRetry_SucceedsOnceDatabaseIsUp(t *testing.T) {
+	logging.Init("test")
+
+	attempts := 0
+	db, err := ConnectWithRetry(context.Background(), RetryConfig{MaxWait: time.Second, InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond},
+		func() (*DB, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, errors.New("connection refused")
+			}
+			return &DB{}, nil
+		})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, db)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestConnectWithRetry_ZeroMaxWaitTriesOnce(t *testing.T) {
+	attempts := 0
+	_, err := ConnectWithRetry(context.Background(), RetryConfig{InitialBackoff: time.Millisecond}, func() (*DB, error) {
+		attempts++
+		return nil, errors.New("connection refused")
+	})
+
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, 1, attempts)
+}
+
+func TestConnectWithRetry_StopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	start := time.Now()
+	_, err := ConnectWithRetry(ctx, RetryConfig{MaxWait: time.Minute, MaxBackoff: time.Minute}, func() (*DB, error) {
+		attempts++
+		cancel()
+		return nil, errors.New("connection refused")
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestConnectWithRetry_ZeroBackoffStillPauses(t *testing.T) {
+	attempts := 0
+	start := time.Now()
+	_, err := ConnectWithRetry(context.Background(), RetryConfig{MaxWait: 250 * time.Millisecond}, func() (*DB, error) {
+		attempts++
+		return nil, errors.New("connection refused")
+	})
+
+	assert.EqualError(t, err, "connection refused")
+	// Attempts are minRetryBackoff apart rather than in a tight loop
+	assert.LessOrEqual(t, attempts, 4)
+	assert.GreaterOrEqual(t, time.Since(start), 250*time.Millisecond)
+}
+
+func TestRetryConfigFromEnv_WaitForDepsDefaultsMaxWait(t *testing.T) {
+	t.Setenv("DB_CONNECT_MAX_WAIT", "")
+
+	assert.Equal(t, time.Duration(0), RetryConfigFromEnv(false).MaxWait)
+	assert.Equal(t, defaultWaitForDepsMaxWait, RetryConfigFromEnv(true).MaxWait)
+
+	t.Setenv("DB_CONNECT_MAX_WAIT", "30s")
+	assert.Equal(t, 30*time.Second, RetryConfigFromEnv(true).MaxWait)
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"meal-prep/shared/logging"
+)
+
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+// sqliteDriverName is set by sqlite_driver.go when the binary is built with
+// -tags sqlite; default builds carry no SQLite driver at all.
+var sqliteDriverName string
+
+// UseSQLiteStorage reports whether STORAGE=sqlite was requested.
+func UseSQLiteStorage() bool {
+	return os.Getenv("STORAGE") == string(DialectSQLite)
+}
+
+// NewConnection opens the database selected by STORAGE: PostgreSQL by
+// default, or a single SQLite file for hobby deployments.
+func NewConnection() (*DB, error) {
+	if UseSQLiteStorage() {
+		return NewSQLiteConnection()
+	}
+	return NewPostgresConnection()
+}
+
+// NewSQLiteConnection opens (creating if needed) the SQLite database at
+// SQLITE_PATH and applies the bundled schema. All service schemas share the
+// one file, so auth and the recipe catalogue can point at the same path.
+func NewSQLiteConnection() (*DB, error) {
+	if sqliteDriverName == "" {
+		return nil, fmt.Errorf("SQLite support is not compiled in, rebuild with -tags sqlite")
+	}
+
+	path := os.Getenv("SQLITE_PATH")
+	if path == "" {
+		path = "data/mealprep.db"
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	logging.Logger.Debug("Opening SQLite database", "path", path)
+
+	// Foreign keys are off by default in SQLite and the busy timeout keeps
+	// concurrent writers from failing immediately with SQLITE_BUSY.
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", path)
+	db, err := sql.Open(sqliteDriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite allows a single writer; one connection avoids lock contention.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to apply SQLite schema: %w", err)
+	}
+
+	threshold := slowQueryThreshold()
+	logging.Logger.Info("Successfully opened SQLite database", "path", path)
+	return &DB{DB: db, dialect: DialectSQLite, stats: newQueryStats(threshold), stmts: newStmtCache(statementCacheSize())}, nil
+}
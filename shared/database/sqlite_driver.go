@@ -0,0 +1,9 @@
+//go:build sqlite
+
+package database
+
+import _ "modernc.org/sqlite"
+
+func init() {
+	sqliteDriverName = "sqlite"
+}
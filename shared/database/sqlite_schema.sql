@@ -0,0 +1,670 @@
+-- SQLite schema for single-binary deployments (STORAGE=sqlite).
+-- Mirrors the Flyway migrations for the auth and recipe_catalogue schemas;
+-- schema prefixes are stripped from queries at runtime so every table lives
+-- in one file. Keep in sync when adding migrations.
+
+CREATE TABLE IF NOT EXISTS users
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    email         VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role          VARCHAR(20)  NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin')),
+    region        VARCHAR(32)  NOT NULL DEFAULT '',
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash CHAR(64)  NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS categories
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT categories_name_not_empty CHECK (length(trim(name)) > 0)
+);
+
+CREATE TABLE IF NOT EXISTS recipes
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER      NOT NULL,
+    name        VARCHAR(200) NOT NULL,
+    description TEXT,
+    category_id INTEGER REFERENCES categories (id),
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    status      VARCHAR(20)  NOT NULL DEFAULT 'published' CHECK (status IN ('draft', 'private', 'published', 'archived')),
+    difficulty  VARCHAR(10) CHECK (difficulty IN ('easy', 'medium', 'hard')),
+    total_time_minutes INTEGER CHECK (total_time_minutes >= 0),
+    version     INTEGER      NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipes_category_name ON recipes (category_id, name);
+CREATE INDEX IF NOT EXISTS idx_recipes_name ON recipes (name);
+CREATE INDEX IF NOT EXISTS idx_recipes_user_id ON recipes (user_id);
+CREATE INDEX IF NOT EXISTS idx_recipes_user_status ON recipes (user_id, status, updated_at DESC);
+
+CREATE TABLE IF NOT EXISTS ingredients
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    category    VARCHAR(50)  NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP,
+    archived_at TIMESTAMP,
+    CONSTRAINT ingredients_name_not_empty CHECK (length(trim(name)) > 0),
+    CONSTRAINT ingredients_category_valid CHECK (category IN
+                                                 ('Meat', 'Vegetables', 'Dairy', 'Grains', 'Spices', 'Oils', 'Fish',
+                                                  'Fruits'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_ingredients_category_name ON ingredients (category, name);
+
+CREATE TABLE IF NOT EXISTS ingredient_products
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    ingredient_id INTEGER       NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    brand         VARCHAR(100)  NOT NULL,
+    name          VARCHAR(100)  NOT NULL,
+    calories_kcal NUMERIC(6, 1) NOT NULL,
+    protein_g     NUMERIC(5, 1) NOT NULL,
+    carbs_g       NUMERIC(5, 1) NOT NULL,
+    fat_g         NUMERIC(5, 1) NOT NULL,
+    fiber_g       NUMERIC(5, 1) NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT ingredient_products_nutrition_not_negative
+        CHECK (calories_kcal >= 0 AND protein_g >= 0 AND carbs_g >= 0 AND fat_g >= 0 AND fiber_g >= 0),
+    CONSTRAINT ingredient_products_unique UNIQUE (ingredient_id, brand, name)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_ingredients
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    recipe_id     INTEGER       NOT NULL REFERENCES recipes (id),
+    ingredient_id INTEGER       NOT NULL REFERENCES ingredients (id),
+    quantity      DECIMAL(8, 2) NOT NULL,
+    unit          VARCHAR(20)   NOT NULL,
+    notes         TEXT,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    product_id    INTEGER REFERENCES ingredient_products (id) ON DELETE SET NULL,
+    CONSTRAINT recipe_ingredients_quantity_positive CHECK (quantity > 0),
+    CONSTRAINT recipe_ingredients_unit_not_empty CHECK (length(trim(unit)) > 0),
+    CONSTRAINT recipe_ingredients_unique_per_recipe UNIQUE (recipe_id, ingredient_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_recipe_id ON recipe_ingredients (recipe_id);
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_ingredient_recipe ON recipe_ingredients (ingredient_id, recipe_id);
+
+-- Mirrors recipe_catalogue.track_ingredient_use (V028)
+CREATE TRIGGER IF NOT EXISTS recipe_ingredients_track_insert
+    AFTER INSERT
+    ON recipe_ingredients
+BEGIN
+    UPDATE ingredients SET archived_at = NULL WHERE id = NEW.ingredient_id AND archived_at IS NOT NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS recipe_ingredients_track_delete
+    AFTER DELETE
+    ON recipe_ingredients
+BEGIN
+    UPDATE ingredients SET last_used_at = CURRENT_TIMESTAMP WHERE id = OLD.ingredient_id;
+END;
+
+CREATE TABLE IF NOT EXISTS ingredient_densities
+(
+    ingredient_id INTEGER PRIMARY KEY REFERENCES ingredients (id) ON DELETE CASCADE,
+    grams_per_ml  NUMERIC(8, 4) NOT NULL CHECK (grams_per_ml > 0),
+    source        TEXT,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_pack_sizes
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    ingredient_id INTEGER       NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    quantity      DECIMAL(8, 2) NOT NULL CHECK (quantity > 0),
+    unit          VARCHAR(20)   NOT NULL,
+    label         VARCHAR(50),
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT ingredient_pack_sizes_unique UNIQUE (ingredient_id, quantity, unit)
+);
+
+CREATE TABLE IF NOT EXISTS store_layouts
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER      NOT NULL,
+    name       VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT store_layouts_unique_name_per_user UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS store_layout_aisles
+(
+    layout_id INTEGER     NOT NULL REFERENCES store_layouts (id) ON DELETE CASCADE,
+    position  INTEGER     NOT NULL,
+    category  VARCHAR(50) NOT NULL,
+    PRIMARY KEY (layout_id, position),
+    CONSTRAINT store_layout_aisles_unique_category UNIQUE (layout_id, category)
+);
+
+CREATE TABLE IF NOT EXISTS shared_shopping_lists
+(
+    token      VARCHAR(36) PRIMARY KEY,
+    user_id    INTEGER NOT NULL,
+    version    INTEGER   DEFAULT 0 NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_shared_shopping_lists_user ON shared_shopping_lists (user_id);
+
+CREATE TABLE IF NOT EXISTS shared_shopping_list_items
+(
+    token         VARCHAR(36)  NOT NULL REFERENCES shared_shopping_lists (token) ON DELETE CASCADE,
+    position      INTEGER      NOT NULL,
+    ingredient_id INTEGER      NOT NULL,
+    name          VARCHAR(255) NOT NULL,
+    category      VARCHAR(100),
+    quantity      REAL         NOT NULL,
+    unit          VARCHAR(50)  NOT NULL,
+    note          TEXT,
+    checked       BOOLEAN   DEFAULT FALSE NOT NULL,
+    checked_at    TIMESTAMP,
+    PRIMARY KEY (token, position)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_steps
+(
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    recipe_id        INTEGER NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+    position         INTEGER NOT NULL,
+    instruction      TEXT    NOT NULL,
+    duration_minutes INTEGER CHECK (duration_minutes > 0),
+    created_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT recipe_steps_unique_position UNIQUE (recipe_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_step_ingredients
+(
+    step_id       INTEGER NOT NULL REFERENCES recipe_steps (id) ON DELETE CASCADE,
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    PRIMARY KEY (step_id, ingredient_id)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_step_timers
+(
+    step_id          INTEGER      NOT NULL REFERENCES recipe_steps (id) ON DELETE CASCADE,
+    position         INTEGER      NOT NULL,
+    label            VARCHAR(100) NOT NULL,
+    duration_seconds INTEGER      NOT NULL CHECK (duration_seconds > 0),
+    PRIMARY KEY (step_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_techniques
+(
+    recipe_id INTEGER     NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+    technique VARCHAR(30) NOT NULL CHECK (technique IN
+                                          ('knife_work', 'sauteing', 'boiling', 'roasting', 'grilling',
+                                           'braising', 'poaching', 'steaming', 'deep_frying', 'emulsifying',
+                                           'baking', 'bread_making', 'pastry', 'fermenting')),
+    PRIMARY KEY (recipe_id, technique)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_techniques_technique ON recipe_techniques (technique);
+
+CREATE TABLE IF NOT EXISTS cooking_sessions
+(
+    token        VARCHAR(36) PRIMARY KEY,
+    user_id      INTEGER       NOT NULL,
+    recipe_id    INTEGER       NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+    scale        NUMERIC(6, 2) NOT NULL DEFAULT 1 CHECK (scale > 0),
+    current_step INTEGER       NOT NULL DEFAULT 0 CHECK (current_step >= 0),
+    started_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS recipe_images
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    recipe_id     INTEGER   NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+    url           TEXT      NOT NULL,
+    caption       VARCHAR(200),
+    position      INTEGER   NOT NULL,
+    is_cover      BOOLEAN   DEFAULT FALSE NOT NULL,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    thumbnail_url TEXT,
+    storage_key   VARCHAR(255)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_images_one_cover ON recipe_images (recipe_id) WHERE is_cover;
+
+CREATE TABLE IF NOT EXISTS user_follows
+(
+    follower_id INTEGER   NOT NULL,
+    followee_id INTEGER   NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_favorites
+(
+    user_id    INTEGER   NOT NULL,
+    recipe_id  INTEGER   NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, recipe_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_favorites_recipe ON user_favorites (recipe_id);
+
+CREATE TABLE IF NOT EXISTS activities
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER     NOT NULL,
+    type       VARCHAR(50) NOT NULL,
+    recipe_id  INTEGER     NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+    created_at TIMESTAMP   DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (type, recipe_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_profiles
+(
+    user_id      INTEGER PRIMARY KEY,
+    display_name VARCHAR(50),
+    bio          VARCHAR(500),
+    is_public    BOOLEAN   DEFAULT TRUE NOT NULL,
+    show_recipes BOOLEAN   DEFAULT TRUE NOT NULL,
+    show_stats   BOOLEAN   DEFAULT TRUE NOT NULL,
+    measurement_system VARCHAR(10) CHECK (measurement_system IN ('metric', 'imperial')),
+    locale       VARCHAR(20),
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS recipe_lineage
+(
+    recipe_id  INTEGER PRIMARY KEY REFERENCES recipes (id) ON DELETE CASCADE,
+    parent_id  INTEGER REFERENCES recipes (id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_prices
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    ingredient_id INTEGER        NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    price         DECIMAL(10, 2) NOT NULL CHECK (price >= 0),
+    quantity      DECIMAL(8, 2)  NOT NULL CHECK (quantity > 0),
+    unit          VARCHAR(20)    NOT NULL,
+    recorded_at   TIMESTAMP      DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS recipe_cost_snapshots
+(
+    id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+    recipe_id          INTEGER        NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+    cost               DECIMAL(10, 2) NOT NULL,
+    priced_ingredients INTEGER        NOT NULL,
+    total_ingredients  INTEGER        NOT NULL,
+    recorded_at        TIMESTAMP      DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_substitutions
+(
+    ingredient_id INTEGER      NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    substitute_id INTEGER      NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    ratio         DECIMAL(6, 3) NOT NULL DEFAULT 1 CHECK (ratio > 0),
+    notes         VARCHAR(200),
+    created_at    TIMESTAMP    DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (ingredient_id, substitute_id),
+    CHECK (ingredient_id <> substitute_id)
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_translations
+(
+    ingredient_id INTEGER      NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    locale        VARCHAR(12)  NOT NULL,
+    name          VARCHAR(100) NOT NULL CHECK (length(trim(name)) > 0),
+    created_at    TIMESTAMP    DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (ingredient_id, locale)
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_aliases
+(
+    ingredient_id INTEGER      NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    name          VARCHAR(100) NOT NULL CHECK (length(trim(name)) > 0),
+    created_at    TIMESTAMP    DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (ingredient_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_dietary_flags
+(
+    ingredient_id INTEGER     NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    flag          VARCHAR(20) NOT NULL CHECK (flag IN
+                                              ('meat', 'gelatin', 'fish', 'shellfish', 'dairy', 'egg', 'honey',
+                                               'plant_based')),
+    PRIMARY KEY (ingredient_id, flag)
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_diet_overrides
+(
+    ingredient_id INTEGER PRIMARY KEY REFERENCES ingredients (id) ON DELETE CASCADE,
+    diet          VARCHAR(20) NOT NULL CHECK (diet IN ('vegan', 'vegetarian', 'pescatarian', 'omnivore')),
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS allergens
+(
+    id   INTEGER PRIMARY KEY AUTOINCREMENT,
+    code VARCHAR(30)  NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_allergens
+(
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    allergen_id   INTEGER NOT NULL REFERENCES allergens (id) ON DELETE CASCADE,
+    PRIMARY KEY (ingredient_id, allergen_id)
+);
+
+CREATE TABLE IF NOT EXISTS prep_sessions
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER      NOT NULL,
+    name       VARCHAR(100) NOT NULL,
+    prep_date  DATE,
+    created_at TIMESTAMP    DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP    DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS prep_session_recipes
+(
+    session_id          INTEGER       NOT NULL REFERENCES prep_sessions (id) ON DELETE CASCADE,
+    recipe_id           INTEGER       NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+    position            INTEGER       NOT NULL,
+    scale               NUMERIC(6, 2) NOT NULL DEFAULT 1 CHECK (scale > 0),
+    portions            INTEGER       NOT NULL DEFAULT 1 CHECK (portions > 0),
+    keeps_days          INTEGER       NOT NULL DEFAULT 4 CHECK (keeps_days BETWEEN 1 AND 365),
+    reheat_instructions VARCHAR(200),
+    PRIMARY KEY (session_id, recipe_id)
+);
+
+CREATE TABLE IF NOT EXISTS meal_plans
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id      INTEGER             NOT NULL,
+    title        VARCHAR(200)        NOT NULL,
+    period_start DATE                NOT NULL,
+    period_end   DATE                NOT NULL,
+    period_type  VARCHAR(20)   DEFAULT 'week' CHECK (period_type IN ('week', 'biweek', 'month', 'custom')),
+    is_public    BOOLEAN       DEFAULT FALSE,
+    slug         VARCHAR(255) UNIQUE NOT NULL,
+    scale        NUMERIC(6, 2) NOT NULL DEFAULT 1 CHECK (scale > 0),
+    created_at   TIMESTAMP     DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP     DEFAULT CURRENT_TIMESTAMP,
+    deleted_at   TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS meal_plan_members
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    meal_plan_id INTEGER     NOT NULL REFERENCES meal_plans (id) ON DELETE CASCADE,
+    position     INTEGER     NOT NULL,
+    name         VARCHAR(50) NOT NULL,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (meal_plan_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS meal_plan_entries
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    meal_plan_id INTEGER     NOT NULL REFERENCES meal_plans (id) ON DELETE CASCADE,
+    recipe_id    INTEGER     NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+    planned_on   DATE        NOT NULL,
+    slot         VARCHAR(20) NOT NULL CHECK (slot IN ('breakfast', 'lunch', 'dinner')),
+    assignee_id  INTEGER REFERENCES meal_plan_members (id) ON DELETE SET NULL,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (meal_plan_id, planned_on, slot, recipe_id)
+);
+
+CREATE TABLE IF NOT EXISTS meal_plan_preferences
+(
+    user_id             INTEGER PRIMARY KEY,
+    weekday_max_minutes INTEGER CHECK (weekday_max_minutes BETWEEN 1 AND 1440),
+    weekend_max_minutes INTEGER CHECK (weekend_max_minutes BETWEEN 1 AND 1440),
+    elaborate_weekends  BOOLEAN   DEFAULT FALSE NOT NULL,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS saved_searches
+(
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id          INTEGER      NOT NULL,
+    name             VARCHAR(100) NOT NULL,
+    category_id      INTEGER REFERENCES categories (id) ON DELETE SET NULL,
+    max_time_minutes INTEGER CHECK (max_time_minutes > 0),
+    notify           BOOLEAN      DEFAULT FALSE NOT NULL,
+    last_checked_at  TIMESTAMP    DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    created_at       TIMESTAMP    DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at       TIMESTAMP    DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS saved_search_ingredients
+(
+    saved_search_id INTEGER NOT NULL REFERENCES saved_searches (id) ON DELETE CASCADE,
+    ingredient_id   INTEGER NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    PRIMARY KEY (saved_search_id, ingredient_id)
+);
+
+CREATE TABLE IF NOT EXISTS households
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        TEXT      NOT NULL,
+    owner_id    INTEGER   NOT NULL,
+    invite_code TEXT      NOT NULL UNIQUE,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS household_members
+(
+    household_id INTEGER   NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    user_id      INTEGER   NOT NULL UNIQUE,
+    joined_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (household_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS pantries
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id      INTEGER UNIQUE,
+    household_id INTEGER UNIQUE REFERENCES households (id) ON DELETE CASCADE,
+    version      INTEGER   DEFAULT 0 NOT NULL,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CHECK ((user_id IS NULL) <> (household_id IS NULL))
+);
+
+CREATE TABLE IF NOT EXISTS pantry_items
+(
+    pantry_id     INTEGER   NOT NULL REFERENCES pantries (id) ON DELETE CASCADE,
+    ingredient_id INTEGER   NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE,
+    added_by      INTEGER   NOT NULL,
+    added_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (pantry_id, ingredient_id)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_templates
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    recipe_id   INTEGER   NOT NULL UNIQUE REFERENCES recipes (id) ON DELETE CASCADE,
+    description TEXT,
+    created_by  INTEGER   NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS audit_log
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id      INTEGER,
+    entity       VARCHAR(100) NOT NULL,
+    entity_id    VARCHAR(100),
+    action       VARCHAR(10)  NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    path         TEXT         NOT NULL,
+    before_state TEXT,
+    after_state  TEXT,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log (entity, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_user ON audit_log (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys
+(
+    user_id         INTEGER      NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash    CHAR(64)     NOT NULL,
+    status_code     INTEGER,
+    content_type    VARCHAR(100),
+    response_body   BLOB,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys (created_at);
+
+-- PostgreSQL keeps this as a materialized view refreshed in the background;
+-- SQLite has none, and at hobby scale a plain view is cheap enough.
+CREATE VIEW IF NOT EXISTS ingredient_usage AS
+SELECT i.id AS ingredient_id,
+       COUNT(DISTINCT ri.recipe_id) AS recipe_count
+FROM ingredients i
+LEFT JOIN recipe_ingredients ri ON ri.ingredient_id = i.id
+GROUP BY i.id;
+
+INSERT OR IGNORE INTO categories (name, description)
+VALUES ('Meat', 'Meat-based dishes'),
+       ('Chicken', 'Chicken dishes'),
+       ('Fish', 'Fish and seafood'),
+       ('Snacks', 'Light meals and snacks'),
+       ('Desserts', 'Sweet treats');
+
+INSERT OR IGNORE INTO ingredients (name, description, category)
+VALUES ('Chicken Breast', 'Boneless skinless chicken breast', 'Meat'),
+       ('Ground Beef', 'Lean ground beef', 'Meat'),
+       ('Salmon Fillet', 'Fresh salmon fillet', 'Fish'),
+       ('Rice', 'Long grain rice', 'Grains'),
+       ('Onion', 'Yellow onion', 'Vegetables'),
+       ('Garlic', 'Fresh garlic cloves', 'Vegetables'),
+       ('Lettuce', 'Romaine lettuce', 'Vegetables'),
+       ('Parmesan Cheese', 'Grated parmesan', 'Dairy'),
+       ('Olive Oil', 'Extra virgin olive oil', 'Oils'),
+       ('Salt', 'Table salt', 'Spices'),
+       ('Black Pepper', 'Ground black pepper', 'Spices'),
+       ('Eggs', 'Large eggs', 'Dairy');
+
+INSERT OR IGNORE INTO ingredient_densities (ingredient_id, grams_per_ml, source)
+SELECT i.id, d.grams_per_ml, 'USDA FoodData Central'
+FROM (SELECT 'Rice' AS name, 0.85 AS grams_per_ml
+      UNION ALL SELECT 'Parmesan Cheese', 0.42
+      UNION ALL SELECT 'Olive Oil', 0.91
+      UNION ALL SELECT 'Salt', 1.22
+      UNION ALL SELECT 'Black Pepper', 0.46) d
+JOIN ingredients i ON i.name = d.name;
+
+INSERT OR IGNORE INTO ingredient_pack_sizes (ingredient_id, quantity, unit, label)
+SELECT i.id, p.quantity, p.unit, p.label
+FROM (SELECT 'Rice' AS name, 1000 AS quantity, 'g' AS unit, 'bag' AS label
+      UNION ALL SELECT 'Olive Oil', 500, 'ml', 'bottle'
+      UNION ALL SELECT 'Parmesan Cheese', 200, 'g', 'block'
+      UNION ALL SELECT 'Eggs', 12, 'pieces', 'box'
+      UNION ALL SELECT 'Eggs', 6, 'pieces', 'box') p
+JOIN ingredients i ON i.name = p.name;
+
+INSERT OR IGNORE INTO ingredient_translations (ingredient_id, locale, name)
+SELECT i.id, t.locale, t.name
+FROM (SELECT 'Chicken Breast' AS ingredient, 'ru' AS locale, 'Куриная грудка' AS name
+      UNION ALL SELECT 'Chicken Breast', 'de', 'Hähnchenbrust'
+      UNION ALL SELECT 'Chicken Breast', 'es', 'Pechuga de pollo'
+      UNION ALL SELECT 'Ground Beef', 'ru', 'Говяжий фарш'
+      UNION ALL SELECT 'Ground Beef', 'de', 'Rinderhackfleisch'
+      UNION ALL SELECT 'Ground Beef', 'es', 'Carne molida de res'
+      UNION ALL SELECT 'Salmon Fillet', 'ru', 'Филе лосося'
+      UNION ALL SELECT 'Salmon Fillet', 'de', 'Lachsfilet'
+      UNION ALL SELECT 'Salmon Fillet', 'es', 'Filete de salmón'
+      UNION ALL SELECT 'Rice', 'ru', 'Рис'
+      UNION ALL SELECT 'Rice', 'de', 'Reis'
+      UNION ALL SELECT 'Rice', 'es', 'Arroz'
+      UNION ALL SELECT 'Onion', 'ru', 'Лук'
+      UNION ALL SELECT 'Onion', 'de', 'Zwiebel'
+      UNION ALL SELECT 'Onion', 'es', 'Cebolla'
+      UNION ALL SELECT 'Garlic', 'ru', 'Чеснок'
+      UNION ALL SELECT 'Garlic', 'de', 'Knoblauch'
+      UNION ALL SELECT 'Garlic', 'es', 'Ajo'
+      UNION ALL SELECT 'Tomato', 'ru', 'Помидор'
+      UNION ALL SELECT 'Tomato', 'de', 'Tomate'
+      UNION ALL SELECT 'Tomato', 'es', 'Tomate'
+      UNION ALL SELECT 'Lettuce', 'ru', 'Салат ромэн'
+      UNION ALL SELECT 'Lettuce', 'de', 'Römersalat'
+      UNION ALL SELECT 'Lettuce', 'es', 'Lechuga romana'
+      UNION ALL SELECT 'Parmesan Cheese', 'ru', 'Сыр пармезан'
+      UNION ALL SELECT 'Parmesan Cheese', 'de', 'Parmesan'
+      UNION ALL SELECT 'Parmesan Cheese', 'es', 'Queso parmesano'
+      UNION ALL SELECT 'Olive Oil', 'ru', 'Оливковое масло'
+      UNION ALL SELECT 'Olive Oil', 'de', 'Olivenöl'
+      UNION ALL SELECT 'Olive Oil', 'es', 'Aceite de oliva'
+      UNION ALL SELECT 'Salt', 'ru', 'Соль'
+      UNION ALL SELECT 'Salt', 'de', 'Salz'
+      UNION ALL SELECT 'Salt', 'es', 'Sal'
+      UNION ALL SELECT 'Black Pepper', 'ru', 'Чёрный перец'
+      UNION ALL SELECT 'Black Pepper', 'de', 'Schwarzer Pfeffer'
+      UNION ALL SELECT 'Black Pepper', 'es', 'Pimienta negra'
+      UNION ALL SELECT 'Eggs', 'ru', 'Яйца'
+      UNION ALL SELECT 'Eggs', 'de', 'Eier'
+      UNION ALL SELECT 'Eggs', 'es', 'Huevos') t
+JOIN ingredients i ON i.name = t.ingredient;
+
+INSERT OR IGNORE INTO ingredient_dietary_flags (ingredient_id, flag)
+SELECT i.id, f.flag
+FROM (SELECT 'Eggs' AS ingredient, 'egg' AS flag
+      UNION ALL SELECT 'Parmesan Cheese', 'dairy') f
+JOIN ingredients i ON i.name = f.ingredient;
+
+INSERT OR IGNORE INTO ingredient_diet_overrides (ingredient_id, diet)
+SELECT id, 'omnivore'
+FROM ingredients
+WHERE name = 'Parmesan Cheese';
+
+INSERT OR IGNORE INTO allergens (code, name)
+VALUES ('gluten', 'Gluten'),
+       ('dairy', 'Milk'),
+       ('egg', 'Eggs'),
+       ('fish', 'Fish'),
+       ('shellfish', 'Crustacean shellfish'),
+       ('mollusc', 'Molluscs'),
+       ('peanut', 'Peanuts'),
+       ('tree_nut', 'Tree nuts'),
+       ('soy', 'Soy'),
+       ('sesame', 'Sesame'),
+       ('mustard', 'Mustard'),
+       ('celery', 'Celery'),
+       ('lupin', 'Lupin'),
+       ('sulphites', 'Sulphites');
+
+INSERT OR IGNORE INTO ingredient_allergens (ingredient_id, allergen_id)
+SELECT i.id, a.id
+FROM (SELECT 'Eggs' AS ingredient, 'egg' AS allergen
+      UNION ALL SELECT 'Parmesan Cheese', 'dairy') t
+JOIN ingredients i ON i.name = t.ingredient
+JOIN allergens a ON a.code = t.allergen;
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strconv"
+	"sync"
+)
+
+const defaultStatementCacheSize = 256
+
+// stmtCache holds one prepared statement per distinct query text so hot
+// repository queries are parsed and planned once per connection rather than
+// on every call. database/sql re-prepares a statement transparently on each
+// pooled connection it lands on.
+//
+// The cache is bounded: once full, further queries run unprepared instead of
+// evicting, since repository query texts are a small fixed set and anything
+// beyond the limit is almost certainly dynamic SQL that would not be reused.
+// Queries that fail to prepare are remembered too, and count towards the
+// limit, so they are not prepared again on every call.
+type stmtCache struct {
+	limit int
+
+	mu     sync.RWMutex
+	stmts  map[string]*sql.Stmt
+	failed map[string]struct{}
+}
+
+func newStmtCache(limit int) *stmtCache {
+	if limit <= 0 {
+		return nil
+	}
+	return &stmtCache{limit: limit, stmts: make(map[string]*sql.Stmt), failed: make(map[string]struct{})}
+}
+
+// statementCacheSize reads DB_STATEMENT_CACHE_SIZE; 0 disables the cache.
+func statementCacheSize() int {
+	value := os.Getenv("DB_STATEMENT_CACHE_SIZE")
+	if value == "" {
+		return defaultStatementCacheSize
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return defaultStatementCacheSize
+	}
+	return n
+}
+
+// EnableStatementCache turns on prepared statement reuse for up to limit
+// distinct queries. Connections opened by NewConnection already have it; a DB
+// built from a bare *sql.DB (as in tests) runs every query unprepared until
+// this is called. It must be called before the DB is shared.
+func (db *DB) EnableStatementCache(limit int) {
+	db.stmts = newStmtCache(limit)
+}
+
+// stmt returns the cached prepared statement for query, preparing it on first
+// use. A nil statement means the caller should run the query unprepared:
+// either caching is off, the cache is full, or preparing failed (in which case
+// running it directly surfaces the same error through the normal path).
+//
+// Preparing is a round trip to the database, so it happens outside the lock.
+// Two callers may race to prepare the same query; the first to store its
+// statement wins and the other closes its own.
+func (c *stmtCache) stmt(ctx context.Context, db *sql.DB, query string) *sql.Stmt {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.RLock()
+	stmt, ok := c.stmts[query]
+	_, failed := c.failed[query]
+	full := c.full()
+	c.mu.RUnlock()
+	if ok {
+		return stmt
+	}
+	if failed || full {
+		return nil
+	}
+
+	prepared, err := db.PrepareContext(ctx, query)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		// A cancelled caller says nothing about the query itself
+		if ctx.Err() == nil && !c.full() {
+			c.failed[query] = struct{}{}
+		}
+		return nil
+	}
+	if stmt, ok := c.stmts[query]; ok {
+		prepared.Close()
+		return stmt
+	}
+	if c.full() {
+		prepared.Close()
+		return nil
+	}
+	c.stmts[query] = prepared
+	return prepared
+}
+
+// full reports whether the cache holds limit queries. c.mu must be held.
+func (c *stmtCache) full() bool {
+	return len(c.stmts)+len(c.failed) >= c.limit
+}
+
+// size reports how many statements are cached.
+func (c *stmtCache) size() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.stmts)
+}
+
+func (c *stmtCache) close() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for query, stmt := range c.stmts {
+		stmt.Close()
+		delete(c.stmts, query)
+	}
+	clear(c.failed)
+}
+
+// Close releases cached statements before closing the pool.
+func (db *DB) Close() error {
+	db.stmts.close()
+	return db.DB.Close()
+}
@@ -0,0 +1,218 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingConnector is a minimal database/sql driver that answers every query
+// with a single row holding 1 and counts how often each query is prepared.
+// The query "BROKEN" fails to prepare.
+type countingConnector struct {
+	mu       sync.Mutex
+	prepared map[string]int
+	failed   int
+	closed   int
+}
+
+func newCountingDB(t *testing.T) (*DB, *countingConnector) {
+	t.Helper()
+	c := &countingConnector{prepared: make(map[string]int)}
+	sqlDB := sql.OpenDB(c)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return &DB{DB: sqlDB}, c
+}
+
+func (c *countingConnector) preparedCount(query string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.prepared[query]
+}
+
+func (c *countingConnector) Connect(context.Context) (driver.Conn, error) {
+	return &countingConn{c: c}, nil
+}
+
+func (c *countingConnector) Driver() driver.Driver { return nil }
+
+type countingConn struct{ c *countingConnector }
+
+func (conn *countingConn) Prepare(query string) (driver.Stmt, error) {
+	if query == "BROKEN" {
+		conn.c.mu.Lock()
+		conn.c.failed++
+		conn.c.mu.Unlock()
+		return nil, errors.New("syntax error")
+	}
+	conn.c.mu.Lock()
+	conn.c.prepared[query]++
+	conn.c.mu.Unlock()
+	return &countingStmt{c: conn.c}, nil
+}
+
+func (conn *countingConn) Close() error              { return nil }
+func (conn *countingConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type countingStmt struct{ c *countingConnector }
+
+func (s *countingStmt) Close() error {
+	s.c.mu.Lock()
+	s.c.closed++
+	s.c.mu.Unlock()
+	return nil
+}
+
+func (s *countingStmt) NumInput() int { return -1 }
+func (s *countingStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+func (s *countingStmt) Query([]driver.Value) (driver.Rows, error) { return &oneRow{}, nil }
+
+type oneRow struct{ done bool }
+
+func (r *oneRow) Columns() []string { return []string{"n"} }
+func (r *oneRow) Close() error      { return nil }
+func (r *oneRow) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func TestStatementCache_PreparesEachQueryOnce(t *testing.T) {
+	db, conn := newCountingDB(t)
+	db.EnableStatementCache(10)
+
+	const query = "SELECT EXISTS(SELECT 1 FROM recipe_catalogue.ingredients WHERE id = $1)"
+	for i := 0; i < 5; i++ {
+		var n int
+		require.NoError(t, db.QueryRow(query, i).Scan(&n))
+		assert.Equal(t, 1, n)
+	}
+
+	rows, err := db.Query(query, 1)
+	require.NoError(t, err)
+	rows.Close()
+
+	_, err = db.Exec(query, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, conn.preparedCount(query))
+	assert.Equal(t, 1, db.stmts.size())
+}
+
+func TestStatementCache_DisabledPreparesEveryCall(t *testing.T) {
+	db, conn := newCountingDB(t)
+
+	const query = "SELECT 1"
+	for i := 0; i < 3; i++ {
+		var n int
+		require.NoError(t, db.QueryRow(query).Scan(&n))
+	}
+
+	// The counting driver has no Queryer, so database/sql prepares an
+	// unnamed statement for every unprepared call.
+	assert.Equal(t, 3, conn.preparedCount(query))
+	assert.Equal(t, 0, db.stmts.size())
+}
+
+func TestStatementCache_RunsUnpreparedWhenFull(t *testing.T) {
+	db, conn := newCountingDB(t)
+	db.EnableStatementCache(1)
+
+	for i := 0; i < 2; i++ {
+		_, err := db.Exec("SELECT 1")
+		require.NoError(t, err)
+		_, err = db.Exec("SELECT 2")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, conn.preparedCount("SELECT 1"))
+	assert.Equal(t, 2, conn.preparedCount("SELECT 2"))
+	assert.Equal(t, 1, db.stmts.size())
+}
+
+func TestStatementCache_PrepareErrorSurfacesThroughQuery(t *testing.T) {
+	db, conn := newCountingDB(t)
+	db.EnableStatementCache(10)
+
+	var n int
+	err := db.QueryRow("BROKEN").Scan(&n)
+	assert.EqualError(t, err, "syntax error")
+	assert.Equal(t, 0, db.stmts.size())
+
+	// The failure is remembered: the second call runs unprepared straight
+	// away instead of trying the cache again
+	err = db.QueryRow("BROKEN").Scan(&n)
+	assert.EqualError(t, err, "syntax error")
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	assert.Equal(t, 3, conn.failed)
+}
+
+func TestStatementCache_ConcurrentPreparesKeepOneStatement(t *testing.T) {
+	db, conn := newCountingDB(t)
+	db.SetMaxOpenConns(4)
+	db.EnableStatementCache(10)
+
+	const query = "SELECT 1"
+	stmts := make([]*sql.Stmt, 8)
+	var wg sync.WaitGroup
+	for i := range stmts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stmts[i] = db.stmts.stmt(context.Background(), db.DB, query)
+		}()
+	}
+	wg.Wait()
+
+	require.NotNil(t, stmts[0])
+	for _, stmt := range stmts {
+		assert.Same(t, stmts[0], stmt)
+	}
+	// Callers that lost the race closed the statements they prepared
+	assert.Equal(t, 1, db.stmts.size())
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	assert.Equal(t, conn.prepared[query]-1, conn.closed)
+}
+
+func TestStatementCache_CloseReleasesStatements(t *testing.T) {
+	db, conn := newCountingDB(t)
+	db.EnableStatementCache(10)
+
+	_, err := db.Exec("SELECT 1")
+	require.NoError(t, err)
+	_, err = db.Exec("SELECT 2")
+	require.NoError(t, err)
+
+	require.NoError(t, db.Close())
+	assert.Equal(t, 0, db.stmts.size())
+	assert.Equal(t, 2, conn.closed)
+}
+
+func TestStatementCacheSize(t *testing.T) {
+	tests := map[string]int{
+		"":      defaultStatementCacheSize,
+		"64":    64,
+		"0":     0,
+		"-1":    defaultStatementCacheSize,
+		"bogus": defaultStatementCacheSize,
+	}
+	for value, want := range tests {
+		t.Setenv("DB_STATEMENT_CACHE_SIZE", value)
+		assert.Equal(t, want, statementCacheSize(), "DB_STATEMENT_CACHE_SIZE=%q", value)
+	}
+}
@@ -0,0 +1,14 @@
+package database
+
+import (
+	"os"
+	"strings"
+)
+
+// UseMemoryStorage reports whether STORAGE=memory was requested, in which
+// case services that support it run on in-memory repositories instead of
+// PostgreSQL. Intended for local development only. The recommendations
+// service has no in-memory repositories and always needs PostgreSQL.
+func UseMemoryStorage() bool {
+	return strings.EqualFold(os.Getenv("STORAGE"), "memory")
+}
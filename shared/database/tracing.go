@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"meal-prep/shared/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startQuerySpan starts a child span for a query made while handling a
+// traced request. Queries outside any span, like those of background jobs,
+// are not traced, so they don't each start a trace of their own. Only the
+// statement is recorded, never its parameters.
+func startQuerySpan(ctx context.Context, dialect Dialect, query string) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	normalized := normalizeQuery(query)
+	operation := queryOperation(normalized)
+	return tracing.Tracer().Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			dbSystem(dialect),
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(normalized),
+		),
+	)
+}
+
+// endQuerySpan ends span, marking it failed when the query was. No rows is
+// an answer, not a failure.
+func endQuerySpan(span trace.Span, err error) {
+	if err != nil && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// queryOperation is the statement's leading keyword, e.g. "SELECT", which
+// names the span.
+func queryOperation(normalized string) string {
+	operation, _, _ := strings.Cut(normalized, " ")
+	return strings.ToUpper(operation)
+}
+
+func dbSystem(dialect Dialect) attribute.KeyValue {
+	if dialect == DialectSQLite {
+		return semconv.DBSystemNameSQLite
+	}
+	return semconv.DBSystemNamePostgreSQL
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestQuerySpans_ChildOfRequestSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	db, _ := newCountingDB(t)
+
+	ctx, request := otel.Tracer("test").Start(context.Background(), "GET /recipes/{id}")
+	var n int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT  1\n FROM recipes WHERE id = $1", 4).Scan(&n))
+	request.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	query := spans[0]
+	assert.Equal(t, "SELECT", query.Name())
+	assert.Equal(t, request.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Contains(t, query.Attributes(), dbSystem(DialectPostgres))
+	for _, attr := range query.Attributes() {
+		if attr.Key == "db.query.text" {
+			assert.Equal(t, "SELECT 1 FROM recipes WHERE id = $1", attr.Value.AsString())
+		}
+	}
+}
+
+func TestQuerySpans_NotStartedOutsideRequests(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	db, _ := newCountingDB(t)
+
+	_, err := db.Exec("SELECT 1")
+	require.NoError(t, err)
+
+	assert.Empty(t, recorder.Ended())
+}
@@ -0,0 +1,68 @@
+// Package events is a small in-process domain event bus. Services publish
+// facts about what happened ("a recipe was published") and other parts of the
+// same process subscribe to them, so the publisher does not need to know who
+// reacts.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"meal-prep/shared/logging"
+)
+
+// Event types
+const (
+	RecipePublished        = "recipe.published"
+	IngredientPriceUpdated = "ingredient.price_updated"
+)
+
+// Event is something that happened to a subject, caused by a user.
+type Event struct {
+	Type       string
+	UserID     int
+	SubjectID  int
+	OccurredAt time.Time
+}
+
+type Handler func(Event) error
+
+type Publisher interface {
+	Publish(event Event)
+}
+
+// Bus delivers events synchronously to the handlers subscribed to their
+// type. A failing handler is logged and does not stop the others or the
+// publisher.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+func (b *Bus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+func (b *Bus) Publish(event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers[event.Type]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(event); err != nil {
+			logging.Logger.Error("Event handler failed",
+				"event_type", event.Type, "subject_id", event.SubjectID, "error", err)
+		}
+	}
+}
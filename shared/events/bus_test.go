@@ -0,0 +1,47 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"meal-prep/shared/logging"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus_DeliversToSubscribersOfType(t *testing.T) {
+	bus := NewBus()
+	var received []Event
+	bus.Subscribe(RecipePublished, func(e Event) error {
+		received = append(received, e)
+		return nil
+	})
+	bus.Subscribe("other", func(e Event) error {
+		t.Fatal("handler for another type must not run")
+		return nil
+	})
+
+	bus.Publish(Event{Type: RecipePublished, UserID: 3, SubjectID: 9})
+
+	assert.Len(t, received, 1)
+	assert.Equal(t, 9, received[0].SubjectID)
+	assert.False(t, received[0].OccurredAt.IsZero())
+}
+
+func TestBus_FailingHandlerDoesNotStopOthers(t *testing.T) {
+	logging.Init("test")
+	bus := NewBus()
+	calls := 0
+	bus.Subscribe(RecipePublished, func(Event) error {
+		calls++
+		return errors.New("boom")
+	})
+	bus.Subscribe(RecipePublished, func(Event) error {
+		calls++
+		return nil
+	})
+
+	bus.Publish(Event{Type: RecipePublished})
+
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,193 @@
+// Package idempotency makes retrying a create safe. A client that sends an
+// Idempotency-Key header with a POST gets the response to the first request
+// with that key replayed on every retry, instead of the retry creating the
+// same recipe twice when only the first response was lost.
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+)
+
+const (
+	// Header is the request header carrying the client's key.
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set to true on responses replayed for a retry.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	// maxResponseSize caps the response kept for a key. Larger responses are
+	// not kept, and a retry runs the request again.
+	maxResponseSize = 256 << 10
+	// keyTTL is how long a key is remembered.
+	keyTTL = 24 * time.Hour
+)
+
+// Record is what is kept for a key: a fingerprint of the request first sent
+// with it and, once that has finished, its response.
+type Record struct {
+	UserID      int
+	Key         string
+	RequestHash string
+	StatusCode  int // 0 while the first request is still running
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+func (r *Record) completed() bool {
+	return r.StatusCode != 0
+}
+
+// Store keeps the records of keys, each user's keys apart from everyone
+// else's.
+type Store interface {
+	// Begin claims record's key for a new request and returns nil, or returns
+	// the record already kept for the key. Records created before
+	// expiredBefore are forgotten first.
+	Begin(record Record, expiredBefore time.Time) (*Record, error)
+	// Complete keeps the response of the request that claimed the key.
+	Complete(record Record) error
+	// Release forgets a key, so that a retry runs the request again.
+	Release(userID int, key string) error
+}
+
+type Keys struct {
+	store Store
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewKeys remembers keys in store for a day.
+func NewKeys(store Store) *Keys {
+	return &Keys{store: store, ttl: keyTTL, now: time.Now}
+}
+
+// Middleware replays the response to the first request sent with a key. It
+// must run after ExtractUserFromGatewayHeaders; requests without a key or a
+// user pass straight through. A key reused for a different request is
+// rejected with 422, and a retry that arrives while the first request is
+// still running with 409. Server errors are not kept, so they can be
+// retried.
+func (k *Keys) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(Header)
+		user, ok := middleware.GetUserFromGatewayContext(r.Context())
+		if key == "" || !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxKeyLength {
+			models.WriteErrorResponse(w, "Idempotency-Key must be at most 255 characters", http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			models.WriteErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := k.now().UTC()
+		record := Record{UserID: user.UserID, Key: key, RequestHash: requestHash(r, body), CreatedAt: now}
+		existing, err := k.store.Begin(record, now.Add(-k.ttl))
+		if err != nil {
+			logging.WithContext(r.Context()).Error("Failed to claim idempotency key", "error", err)
+			models.WriteErrorResponse(w, "Failed to check Idempotency-Key", http.StatusInternalServerError)
+			return
+		}
+		if existing != nil {
+			replay(w, existing, record.RequestHash)
+			return
+		}
+
+		capture := &captureWriter{ResponseWriter: w, status: http.StatusOK}
+		kept := false
+		defer func() {
+			if !kept {
+				if err := k.store.Release(record.UserID, record.Key); err != nil {
+					logging.WithContext(r.Context()).Error("Failed to release idempotency key", "error", err)
+				}
+			}
+		}()
+
+		next.ServeHTTP(capture, r)
+		if capture.status >= 500 || capture.overflowed {
+			return
+		}
+
+		record.StatusCode = capture.status
+		record.ContentType = capture.Header().Get("Content-Type")
+		record.Body = capture.body.Bytes()
+		if err := k.store.Complete(record); err != nil {
+			logging.WithContext(r.Context()).Error("Failed to keep idempotent response", "error", err)
+			return
+		}
+		kept = true
+	})
+}
+
+// replay answers a retry from the record kept for its key.
+func replay(w http.ResponseWriter, record *Record, requestHash string) {
+	switch {
+	case record.RequestHash != requestHash:
+		models.WriteErrorResponse(w, "Idempotency-Key was already used for a different request", http.StatusUnprocessableEntity)
+	case !record.completed():
+		models.WriteErrorResponse(w, "A request with this Idempotency-Key is still in progress", http.StatusConflict)
+	default:
+		if record.ContentType != "" {
+			w.Header().Set("Content-Type", record.ContentType)
+		}
+		w.Header().Set(ReplayedHeader, "true")
+		w.WriteHeader(record.StatusCode)
+		w.Write(record.Body)
+	}
+}
+
+// requestHash fingerprints a request by its method, path and body, so a key
+// can't be reused to replay a response to some other request.
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// captureWriter passes a response through while keeping its status and body.
+type captureWriter struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	overflowed  bool
+	wroteHeader bool
+}
+
+func (c *captureWriter) WriteHeader(status int) {
+	if !c.wroteHeader {
+		c.status = status
+		c.wroteHeader = true
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *captureWriter) Write(p []byte) (int, error) {
+	c.wroteHeader = true
+	if !c.overflowed {
+		if c.body.Len()+len(p) > maxResponseSize {
+			c.overflowed = true
+			c.body.Reset()
+		} else {
+			c.body.Write(p)
+		}
+	}
+	return c.ResponseWriter.Write(p)
+}
@@ -0,0 +1,133 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newCreateHandler creates a recipe per request it serves, failing while
+// failing is set, behind the keys the way the catalogue mounts them.
+func newCreateHandler(keys *Keys, failing *bool) (http.Handler, *int) {
+	created := 0
+	create := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if *failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		created++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]int{"id": created})
+	})
+	return keys.Middleware(create), &created
+}
+
+func post(handler http.Handler, userID int, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/recipes", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(Header, key)
+	}
+	if userID != 0 {
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserCtxKey, &middleware.UserContext{UserID: userID}))
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestKeys_ReplaysFirstResponse(t *testing.T) {
+	logging.Init("test")
+	failing := false
+	handler, created := newCreateHandler(NewKeys(NewMemoryStore()), &failing)
+
+	first := post(handler, 7, "abc", `{"name":"Pancakes"}`)
+	retry := post(handler, 7, "abc", `{"name":"Pancakes"}`)
+
+	assert.Equal(t, 1, *created)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+	assert.Equal(t, "true", retry.Header().Get(ReplayedHeader))
+	assert.Empty(t, first.Header().Get(ReplayedHeader))
+}
+
+func TestKeys_KeysAreScoped(t *testing.T) {
+	logging.Init("test")
+	failing := false
+	handler, created := newCreateHandler(NewKeys(NewMemoryStore()), &failing)
+
+	post(handler, 7, "abc", `{}`)
+	post(handler, 8, "abc", `{}`)
+	post(handler, 7, "", `{}`)
+	post(handler, 0, "abc", `{}`)
+
+	assert.Equal(t, 4, *created)
+}
+
+func TestKeys_RejectsReuseForDifferentRequest(t *testing.T) {
+	logging.Init("test")
+	failing := false
+	handler, created := newCreateHandler(NewKeys(NewMemoryStore()), &failing)
+
+	post(handler, 7, "abc", `{"name":"Pancakes"}`)
+	rr := post(handler, 7, "abc", `{"name":"Waffles"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Equal(t, 1, *created)
+}
+
+func TestKeys_RetriesAfterServerError(t *testing.T) {
+	logging.Init("test")
+	failing := true
+	handler, created := newCreateHandler(NewKeys(NewMemoryStore()), &failing)
+
+	assert.Equal(t, http.StatusServiceUnavailable, post(handler, 7, "abc", `{}`).Code)
+	failing = false
+	rr := post(handler, 7, "abc", `{}`)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Empty(t, rr.Header().Get(ReplayedHeader))
+	assert.Equal(t, 1, *created)
+}
+
+func TestKeys_InProgress(t *testing.T) {
+	logging.Init("test")
+	store := NewMemoryStore()
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	body := `{}`
+	req := httptest.NewRequest("POST", "/recipes", strings.NewReader(body))
+	store.Begin(Record{UserID: 7, Key: "abc", RequestHash: requestHash(req, []byte(body)), CreatedAt: now}, now.Add(-keyTTL))
+
+	keys := NewKeys(store)
+	keys.now = func() time.Time { return now.Add(time.Second) }
+	failing := false
+	handler, created := newCreateHandler(keys, &failing)
+
+	assert.Equal(t, http.StatusConflict, post(handler, 7, "abc", body).Code)
+	assert.Equal(t, 0, *created)
+
+	// A day later the key is forgotten and can be used afresh
+	keys.now = func() time.Time { return now.Add(keyTTL + time.Second) }
+	assert.Equal(t, http.StatusCreated, post(handler, 7, "abc", body).Code)
+	assert.Equal(t, 1, *created)
+}
+
+func TestKeys_KeyTooLong(t *testing.T) {
+	failing := false
+	handler, created := newCreateHandler(NewKeys(NewMemoryStore()), &failing)
+
+	rr := post(handler, 7, strings.Repeat("a", 256), `{}`)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, 0, *created)
+}
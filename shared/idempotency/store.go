@@ -0,0 +1,124 @@
+package idempotency
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"meal-prep/shared/database"
+)
+
+type sqlStore struct {
+	db    *database.DB
+	table string
+}
+
+// NewSQLStore keeps keys in table, e.g. recipe_catalogue.idempotency_keys,
+// so each service's keys stay in its own schema.
+func NewSQLStore(db *database.DB, table string) Store {
+	return &sqlStore{db: db, table: table}
+}
+
+func (s *sqlStore) Begin(record Record, expiredBefore time.Time) (*Record, error) {
+	if _, err := s.db.Exec(`DELETE FROM `+s.table+` WHERE created_at < $1`, expiredBefore); err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO `+s.table+` (user_id, idempotency_key, request_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, idempotency_key) DO NOTHING`,
+		record.UserID, record.Key, record.RequestHash, record.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if claimed == 1 {
+		return nil, nil
+	}
+
+	existing := Record{UserID: record.UserID, Key: record.Key}
+	var statusCode sql.NullInt64
+	var contentType sql.NullString
+	err = s.db.QueryRow(`
+		SELECT request_hash, status_code, content_type, response_body, created_at
+		FROM `+s.table+`
+		WHERE user_id = $1 AND idempotency_key = $2`,
+		record.UserID, record.Key).Scan(&existing.RequestHash, &statusCode, &contentType, &existing.Body, &existing.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	existing.StatusCode = int(statusCode.Int64)
+	existing.ContentType = contentType.String
+	return &existing, nil
+}
+
+func (s *sqlStore) Complete(record Record) error {
+	_, err := s.db.Exec(`
+		UPDATE `+s.table+`
+		SET status_code = $3, content_type = $4, response_body = $5
+		WHERE user_id = $1 AND idempotency_key = $2`,
+		record.UserID, record.Key, record.StatusCode, record.ContentType, record.Body)
+	return err
+}
+
+func (s *sqlStore) Release(userID int, key string) error {
+	_, err := s.db.Exec(`DELETE FROM `+s.table+` WHERE user_id = $1 AND idempotency_key = $2`, userID, key)
+	return err
+}
+
+type memoryKey struct {
+	userID int
+	key    string
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[memoryKey]Record
+}
+
+// NewMemoryStore keeps keys in process memory, for services running with
+// STORAGE=memory.
+func NewMemoryStore() Store {
+	return &memoryStore{records: make(map[memoryKey]Record)}
+}
+
+func (s *memoryStore) Begin(record Record, expiredBefore time.Time) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, kept := range s.records {
+		if kept.CreatedAt.Before(expiredBefore) {
+			delete(s.records, key)
+		}
+	}
+
+	key := memoryKey{userID: record.UserID, key: record.Key}
+	if existing, ok := s.records[key]; ok {
+		return &existing, nil
+	}
+	s.records[key] = record
+	return nil, nil
+}
+
+func (s *memoryStore) Complete(record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memoryKey{userID: record.UserID, key: record.Key}
+	if _, ok := s.records[key]; ok {
+		s.records[key] = record
+	}
+	return nil
+}
+
+func (s *memoryStore) Release(userID int, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, memoryKey{userID: userID, key: key})
+	return nil
+}
@@ -0,0 +1,72 @@
+package idempotency
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"meal-prep/shared/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLStore_BeginCompleteAndReplay(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	store := NewSQLStore(&database.DB{DB: sqlDB}, "recipe_catalogue.idempotency_keys")
+
+	createdAt := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	expiredBefore := createdAt.Add(-keyTTL)
+	record := Record{UserID: 7, Key: "abc", RequestHash: "hash", CreatedAt: createdAt}
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.idempotency_keys WHERE created_at < $1")).
+		WithArgs(expiredBefore).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("ON CONFLICT (user_id, idempotency_key) DO NOTHING")).
+		WithArgs(7, "abc", "hash", createdAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	existing, err := store.Begin(record, expiredBefore)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE recipe_catalogue.idempotency_keys")).
+		WithArgs(7, "abc", 201, "application/json", []byte(`{"id":1}`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	record.StatusCode = 201
+	record.ContentType = "application/json"
+	record.Body = []byte(`{"id":1}`)
+	require.NoError(t, store.Complete(record))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.idempotency_keys WHERE created_at < $1")).
+		WithArgs(expiredBefore).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("ON CONFLICT (user_id, idempotency_key) DO NOTHING")).
+		WithArgs(7, "abc", "hash", createdAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT request_hash, status_code, content_type, response_body, created_at")).
+		WithArgs(7, "abc").
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "content_type", "response_body", "created_at"}).
+			AddRow("hash", 201, "application/json", []byte(`{"id":1}`), createdAt))
+	existing, err = store.Begin(Record{UserID: 7, Key: "abc", RequestHash: "hash", CreatedAt: createdAt}, expiredBefore)
+	require.NoError(t, err)
+	assert.Equal(t, &record, existing)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLStore_Release(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	store := NewSQLStore(&database.DB{DB: sqlDB}, "recipe_catalogue.idempotency_keys")
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recipe_catalogue.idempotency_keys WHERE user_id = $1 AND idempotency_key = $2")).
+		WithArgs(7, "abc").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, store.Release(7, "abc"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
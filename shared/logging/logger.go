@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type contextKey string
@@ -90,6 +92,11 @@ func WithContext(ctx context.Context) *slog.Logger {
 		logger = logger.With("user_id", userID)
 	}
 
+	// Lets a log line be found from its trace, and the other way round
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		logger = logger.With("trace_id", spanContext.TraceID().String())
+	}
+
 	return logger
 }
 
@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestIDHeader carries a request's ID between clients, the gateway and
+// the services, so their log lines for one request can be found together.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength caps the IDs accepted from callers.
+const maxRequestIDLength = 128
+
+// RequestIDFromContext returns the ID WithRequestID stored.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(RequestIDKey).(string)
+	return requestID, ok && requestID != ""
+}
+
+// ValidRequestID reports whether a caller's request ID is safe to log and
+// pass on: at most 128 letters, digits, dots, dashes, underscores and colons.
+func ValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '.', c == '-', c == '_', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Transport wraps base so every outgoing request carries the request ID of
+// its request context, and the service called logs under the same ID. A
+// nil base means http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &requestIDTransport{base: base}
+}
+
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestID, ok := RequestIDFromContext(req.Context())
+	if !ok || req.Header.Get(RequestIDHeader) != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set(RequestIDHeader, requestID)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport_ForwardsRequestID(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(RequestIDHeader))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: Transport(nil)}
+
+	get := func(ctx context.Context, header string) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		if header != "" {
+			req.Header.Set(RequestIDHeader, header)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, header, req.Header.Get(RequestIDHeader)) // the caller's request is left alone
+	}
+
+	get(WithRequestID(context.Background(), "abc-123"), "")
+	get(WithRequestID(context.Background(), "abc-123"), "set-by-caller")
+	get(context.Background(), "")
+
+	assert.Equal(t, []string{"abc-123", "set-by-caller", ""}, received)
+}
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, ValidRequestID("0b5e8f4c-9a1d-4f3e-8c7b-2d6a1e9f0c3b"))
+	assert.True(t, ValidRequestID("trace:abc_1.2"))
+	assert.False(t, ValidRequestID(""))
+	assert.False(t, ValidRequestID("abc\n{\"level\":\"error\"}"))
+	assert.False(t, ValidRequestID(strings.Repeat("a", 129)))
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"meal-prep/shared/logging"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxRetries bounds how often a call that could not reach its target is
+// tried again, waiting retryBackoff longer before each attempt.
+const (
+	maxRetries   = 2
+	retryBackoff = 100 * time.Millisecond
+)
+
+// The outbound collectors are shared by every client in the process; New
+// registers them with each service's registry under its service label.
+var (
+	clientRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_client_requests_total",
+		Help: "Calls made to other services and external APIs, by target, method and status code, or \"error\" when no response came back.",
+	}, []string{"target", "method", "code"})
+	clientDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_client_request_duration_seconds",
+		Help:    "Time taken by calls to other services and external APIs, retries included, by target and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"target", "method"})
+	clientRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_client_retries_total",
+		Help: "Calls retried after failing to reach their target, by target.",
+	}, []string{"target"})
+)
+
+// Transport wraps base so every call to target, the service or API it
+// reaches such as "recommendations-service" or "s3", is logged and counted
+// with its latency and status. GET and HEAD calls that fail to get any
+// response are retried up to twice. A nil base means http.DefaultTransport.
+func Transport(target string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &clientTransport{target: target, base: base}
+}
+
+type clientTransport struct {
+	target string
+	base   http.RoundTripper
+}
+
+func (t *clientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	log := logging.WithContext(req.Context())
+	start := time.Now()
+
+	resp, err := t.base.RoundTrip(req)
+	attempts := 1
+	for err != nil && attempts <= maxRetries && retryable(req, err) {
+		wait := retryBackoff * time.Duration(attempts)
+		log.Warn("Retrying outbound request",
+			"target", t.target, "method", req.Method, "path", req.URL.Path,
+			"attempt", attempts+1, "retry_in", wait.String(), "error", err)
+		clientRetries.WithLabelValues(t.target).Inc()
+
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		resp, err = t.base.RoundTrip(req)
+		attempts++
+	}
+
+	duration := time.Since(start)
+	clientDuration.WithLabelValues(t.target, req.Method).Observe(duration.Seconds())
+	if err != nil {
+		clientRequests.WithLabelValues(t.target, req.Method, "error").Inc()
+		log.Warn("Outbound request failed",
+			"target", t.target, "method", req.Method, "path", req.URL.Path,
+			"attempts", attempts, "duration_ms", duration.Milliseconds(), "error", err)
+		return nil, err
+	}
+
+	clientRequests.WithLabelValues(t.target, req.Method, strconv.Itoa(resp.StatusCode)).Inc()
+	log.Debug("Outbound request completed",
+		"target", t.target, "method", req.Method, "path", req.URL.Path,
+		"status_code", resp.StatusCode, "attempts", attempts, "duration_ms", duration.Milliseconds())
+	return resp, nil
+}
+
+// retryable reports whether req can safely be sent again: it is a GET or
+// HEAD without a body, and failed for a reason other than its context
+// ending.
+func retryable(req *http.Request, err error) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		return false
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) && req.Context().Err() == nil
+}
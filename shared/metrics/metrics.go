@@ -0,0 +1,122 @@
+// Package metrics exposes Prometheus metrics for the services on /metrics:
+// request counts, latencies and requests in flight per route, calls made to
+// other services and APIs per target, database connection pool stats, and
+// the Go runtime and process collectors.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"meal-prep/shared/database"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds one service's collectors. Each service has its own registry
+// rather than the global one, so tests and several routers in one process
+// don't register the same metric twice.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+func New(serviceName string) *Metrics {
+	labels := prometheus.Labels{"service": serviceName}
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "http_requests_total",
+			Help:        "HTTP requests handled, by method, route and status code.",
+			ConstLabels: labels,
+		}, []string{"method", "route", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "http_request_duration_seconds",
+			Help:        "Time taken to handle HTTP requests, by method and route.",
+			ConstLabels: labels,
+			Buckets:     prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "http_requests_in_flight",
+			Help:        "HTTP requests currently being handled.",
+			ConstLabels: labels,
+		}),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		m.inFlight,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	prometheus.WrapRegistererWith(labels, m.registry).MustRegister(clientRequests, clientDuration, clientRetries)
+	return m
+}
+
+// RegisterDB adds the connection pool stats of db, e.g. open and idle
+// connections and time spent waiting for one, labelled with dbName.
+func (m *Metrics) RegisterDB(db *database.DB, dbName string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db.DB, dbName))
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware records every request but health checks and scrapes. Register
+// it with Use on each router so the route template is known; requests are
+// labelled by template, e.g. "/recipes/{id}", to keep one series per
+// endpoint rather than per recipe.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" || r.URL.Path == "/metrics" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		start := time.Now()
+		ww := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(ww, r)
+
+		route := routeTemplate(r)
+		m.requests.WithLabelValues(r.Method, route, strconv.Itoa(ww.statusCode)).Inc()
+		m.duration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return "unmatched"
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (rw *statusRecorder) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, so
+// streaming handlers still work behind the middleware.
+func (rw *statusRecorder) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
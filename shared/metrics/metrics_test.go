@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"meal-prep/shared/logging"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_RecordsRequestsByRouteTemplate(t *testing.T) {
+	m := New("test-service")
+
+	router := mux.NewRouter()
+	router.Use(m.Middleware)
+	router.HandleFunc("/recipes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
+	router.Handle("/metrics", m.Handler())
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/recipes/7", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/recipes/8", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, body, `http_requests_total{code="404",method="GET",route="/recipes/{id}",service="test-service"} 2`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/recipes/{id}",service="test-service"} 2`)
+	assert.Contains(t, body, `http_requests_in_flight{service="test-service"} 0`)
+	assert.NotContains(t, body, `route="/health"`)
+	assert.NotContains(t, body, `route="/metrics"`)
+}
+
+func TestMiddleware_CountsRequestsInFlight(t *testing.T) {
+	m := New("test-service")
+
+	var during string
+	handler := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		during = rec.Body.String()
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/cooking", nil))
+
+	assert.Contains(t, during, `http_requests_in_flight{service="test-service"} 1`)
+}
+
+func scrape(m *Metrics) string {
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	return rec.Body.String()
+}
+
+func TestTransport_RecordsCallsByTarget(t *testing.T) {
+	logging.Init("test")
+	m := New("test-service")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: Transport("record-target", nil)}
+
+	resp, err := client.Post(server.URL+"/internal/usage", "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	body := scrape(m)
+	assert.Contains(t, body, `http_client_requests_total{code="202",method="POST",service="test-service",target="record-target"} 1`)
+	assert.Contains(t, body, `http_client_request_duration_seconds_count{method="POST",service="test-service",target="record-target"} 1`)
+	assert.NotContains(t, body, `http_client_retries_total{service="test-service",target="record-target"}`)
+}
+
+func TestTransport_RetriesOnlyIdempotentCalls(t *testing.T) {
+	logging.Init("test")
+	m := New("test-service")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachable := server.URL
+	server.Close()
+	client := &http.Client{Transport: Transport("retry-target", nil)}
+
+	_, err := client.Get(unreachable + "/internal/recommendations")
+	assert.Error(t, err)
+	_, err = client.Post(unreachable+"/internal/usage", "application/json", strings.NewReader(`{}`))
+	assert.Error(t, err)
+
+	body := scrape(m)
+	assert.Contains(t, body, `http_client_retries_total{service="test-service",target="retry-target"} 2`)
+	assert.Contains(t, body, `http_client_requests_total{code="error",method="GET",service="test-service",target="retry-target"} 1`)
+	assert.Contains(t, body, `http_client_requests_total{code="error",method="POST",service="test-service",target="retry-target"} 1`)
+}
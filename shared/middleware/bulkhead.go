@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+)
+
+// Bulkhead caps how many requests may run the wrapped handler at once. It is
+// meant for expensive endpoints that hold DB connections for a long time, so
+// a burst of them cannot starve the pool for everything else. Requests over
+// the limit are rejected straight away with 503 and a Retry-After hint
+// rather than queued.
+func Bulkhead(name string, limit int, retryAfter time.Duration) func(http.Handler) http.Handler {
+	if limit < 1 {
+		limit = 1
+	}
+	slots := make(chan struct{}, limit)
+	retryAfterSeconds := strconv.Itoa(int((retryAfter + time.Second - 1) / time.Second))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+				next.ServeHTTP(w, r)
+			default:
+				logging.WithContext(r.Context()).Warn("Bulkhead saturated, rejecting request",
+					"bulkhead", name,
+					"limit", limit,
+				)
+				w.Header().Set("Retry-After", retryAfterSeconds)
+				models.WriteErrorResponse(w, "Server is busy, please retry later", http.StatusServiceUnavailable)
+			}
+		})
+	}
+}
+
+// BulkheadLimit reads a concurrency limit from the environment, falling back
+// when the variable is unset or not a positive integer.
+func BulkheadLimit(envKey string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(envKey)); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
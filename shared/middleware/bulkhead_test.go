@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"meal-prep/shared/logging"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkhead_RejectsWhenSaturated(t *testing.T) {
+	logging.Init("test")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := Bulkhead("test", 1, 1500*time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(first, httptest.NewRequest(http.MethodPost, "/grocery-list", nil))
+		close(done)
+	}()
+	<-started
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, httptest.NewRequest(http.MethodPost, "/grocery-list", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, second.Code)
+	assert.Equal(t, "2", second.Header().Get("Retry-After"))
+
+	close(release)
+	<-done
+	assert.Equal(t, http.StatusOK, first.Code)
+
+}
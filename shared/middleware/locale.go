@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"meal-prep/shared/models"
+)
+
+// DefaultLocale is the language responses are written in when the request
+// states no preference.
+const DefaultLocale = "en"
+
+// localePattern accepts a language with an optional region or script
+// ("ru", "pt-br", "zh-hant").
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// decimalCommaLanguages write numbers with a decimal comma, 1,5 kg rather
+// than 1.5 kg.
+var decimalCommaLanguages = map[string]bool{
+	"cs": true, "da": true, "de": true, "es": true, "fi": true, "fr": true, "id": true, "it": true,
+	"nb": true, "nl": true, "pl": true, "pt": true, "ru": true, "sv": true, "tr": true, "uk": true,
+}
+
+type localeCtxKey struct{}
+
+// LocalePreference returns the locale the signed-in user chose in their
+// settings, or "" when they chose none.
+type LocalePreference func(r *http.Request) string
+
+// Locale decides which locales the wrapped handler answers in: ?lang= wins,
+// then the user's preference, then Accept-Language. Handlers read them with
+// LocalesFromContext. An invalid ?lang= is rejected with 400; preference may
+// be nil.
+func Locale(preference LocalePreference) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Shared caches must keep one copy per language
+			w.Header().Add("Vary", "Accept-Language")
+
+			var locales []string
+			if lang := r.URL.Query().Get("lang"); lang != "" {
+				locale, ok := NormalizeLocale(lang)
+				if !ok {
+					models.WriteErrorResponse(w, "lang must be a language tag such as ru or pt-BR", http.StatusBadRequest)
+					return
+				}
+				locales = []string{locale}
+			} else if preferred := preferredLocale(preference, r); preferred != "" {
+				locales = []string{preferred}
+			} else {
+				locales = AcceptedLanguages(r.Header.Get("Accept-Language"))
+			}
+
+			ctx := context.WithValue(r.Context(), localeCtxKey{}, locales)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func preferredLocale(preference LocalePreference, r *http.Request) string {
+	if preference == nil {
+		return ""
+	}
+	locale, _ := NormalizeLocale(preference(r))
+	return locale
+}
+
+// LocalesFromContext returns the locales Locale chose, most preferred first,
+// or nil when the request states no preference.
+func LocalesFromContext(ctx context.Context) []string {
+	locales, _ := ctx.Value(localeCtxKey{}).([]string)
+	return locales
+}
+
+// LocaleFromContext returns the most preferred locale, or DefaultLocale.
+func LocaleFromContext(ctx context.Context) string {
+	if locales := LocalesFromContext(ctx); len(locales) > 0 {
+		return locales[0]
+	}
+	return DefaultLocale
+}
+
+// NormalizeLocale lower-cases a language tag and turns underscores into
+// hyphens, so "pt_BR" and "pt-br" name the same locale.
+func NormalizeLocale(tag string) (string, bool) {
+	locale := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(tag)), "_", "-")
+	if !localePattern.MatchString(locale) {
+		return "", false
+	}
+	return locale, true
+}
+
+// AcceptedLanguages returns the language tags of an Accept-Language header,
+// most preferred first. Wildcards, malformed tags and q=0 are skipped.
+func AcceptedLanguages(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var accepted []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale, ok := NormalizeLocale(tag)
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if value, found := strings.CutPrefix(strings.TrimSpace(param), "q="); found {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			accepted = append(accepted, weighted{locale: locale, q: q})
+		}
+	}
+
+	sort.SliceStable(accepted, func(i, j int) bool { return accepted[i].q > accepted[j].q })
+	tags := make([]string, len(accepted))
+	for i, a := range accepted {
+		tags[i] = a.locale
+	}
+	return tags
+}
+
+// FormatNumber writes value the way locale does, with precision decimals or
+// as few as it needs when precision is -1.
+func FormatNumber(locale string, value float64, precision int) string {
+	formatted := strconv.FormatFloat(value, 'f', precision, 64)
+	language, _, _ := strings.Cut(locale, "-")
+	if decimalCommaLanguages[language] {
+		formatted = strings.Replace(formatted, ".", ",", 1)
+	}
+	return formatted
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcceptedLanguages(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{"ordered by quality", "de;q=0.5, ru-RU, es;q=0.8", []string{"ru-ru", "es", "de"}},
+		{"underscores normalized", "pt_BR", []string{"pt-br"}},
+		{"wildcard and refusals skipped", "*, fr;q=0, it", []string{"it"}},
+		{"empty header", "", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AcceptedLanguages(tt.header))
+		})
+	}
+}
+
+func TestLocale_Precedence(t *testing.T) {
+	preference := func(locale string) LocalePreference {
+		return func(r *http.Request) string { return locale }
+	}
+	tests := []struct {
+		name       string
+		query      string
+		preference LocalePreference
+		want       []string
+	}{
+		{name: "lang wins", query: "?lang=ru", preference: preference("de"), want: []string{"ru"}},
+		{name: "then the user's setting", preference: preference("de"), want: []string{"de"}},
+		{name: "then Accept-Language", preference: preference(""), want: []string{"et", "fr"}},
+		{name: "without a preference", want: []string{"et", "fr"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var locales []string
+			handler := Locale(tt.preference)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				locales = LocalesFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/ingredients"+tt.query, nil)
+			req.Header.Set("Accept-Language", "et, fr;q=0.5")
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.want, locales)
+			assert.Equal(t, "Accept-Language", rr.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestLocale_InvalidLang(t *testing.T) {
+	called := false
+	handler := Locale(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ingredients?lang=klingon!", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.False(t, called)
+}
+
+func TestLocaleFromContext_DefaultsToEnglish(t *testing.T) {
+	assert.Equal(t, DefaultLocale, LocaleFromContext(context.Background()))
+	ctx := context.WithValue(context.Background(), localeCtxKey{}, []string{"de-at", "de"})
+	assert.Equal(t, "de-at", LocaleFromContext(ctx))
+}
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "1.5", FormatNumber("en", 1.5, -1))
+	assert.Equal(t, "1,5", FormatNumber("de-at", 1.5, -1))
+	assert.Equal(t, "12,50", FormatNumber("fr", 12.5, 2))
+	assert.Equal(t, "3", FormatNumber("ru", 3, -1))
+}
@@ -14,13 +14,14 @@ func LoggingMiddleware(serviceName string) func(next http.Handler) http.Handler
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 
-			// Generate request ID
-			requestID := r.Header.Get("X-Request-ID")
-			if requestID == "" {
+			// Keep the caller's request ID so logs correlate across services,
+			// or start one; IDs that are unsafe to log are replaced
+			requestID := r.Header.Get(logging.RequestIDHeader)
+			if !logging.ValidRequestID(requestID) {
 				requestID = uuid.New().String()
 			}
 
-			w.Header().Set("X-Request-ID", requestID)
+			w.Header().Set(logging.RequestIDHeader, requestID)
 
 			// Add to context
 			ctx := logging.WithRequestID(r.Context(), requestID)
@@ -66,3 +67,10 @@ func (rw *responseWriterWrapper) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap lets http.ResponseController reach the underlying writer, so
+// handlers that stream (server-sent events) can flush and lift the write
+// deadline through the logging middleware.
+func (rw *responseWriterWrapper) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meal-prep/shared/logging"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggingMiddleware_StreamingHandlersCanFlush(t *testing.T) {
+	logging.Init("test")
+
+	handler := LoggingMiddleware("test")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("event: ping\n\n"))
+		assert.NoError(t, http.NewResponseController(w).Flush())
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/shared-lists/token/events", nil))
+
+	assert.True(t, recorder.Flushed)
+}
+
+func TestLoggingMiddleware_RequestID(t *testing.T) {
+	logging.Init("test")
+	tests := []struct {
+		name    string
+		header  string
+		keepsIt bool
+	}{
+		{name: "propagates the caller's", header: "abc-123", keepsIt: true},
+		{name: "starts one", header: ""},
+		{name: "replaces one unsafe to log", header: "abc 123\nforged"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inContext string
+			handler := LoggingMiddleware("test")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				inContext, _ = logging.RequestIDFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/recipes", nil)
+			req.Header.Set(logging.RequestIDHeader, tt.header)
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			assert.NotEmpty(t, inContext)
+			assert.Equal(t, inContext, recorder.Header().Get(logging.RequestIDHeader))
+			if tt.keepsIt {
+				assert.Equal(t, tt.header, inContext)
+			} else {
+				assert.NotEqual(t, tt.header, inContext)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+)
+
+// RouteByRegion serves each request with the handler wired to the database
+// of its user's data residency region, so their data is read from and
+// written to that region only. Anonymous requests and users without a
+// region go to home. Users of a region this instance doesn't serve get 421
+// rather than having their data written to the wrong database.
+//
+// Requests shared reports true for read or write what every region shares,
+// such as the recipe catalogue, and are served by home whoever makes them,
+// so that what they create is numbered once for every region. shared may be
+// nil when everything is the user's own.
+//
+// The region is read from the token like the user is. Calls between
+// services identify the user by ID alone and are served by home.
+func RouteByRegion(home http.Handler, regional map[string]http.Handler, shared func(*http.Request) bool) http.Handler {
+	if len(regional) == 0 {
+		return home
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if shared != nil && shared(r) {
+			home.ServeHTTP(w, r)
+			return
+		}
+
+		user, err := userFromAuthorization(r.Header.Get("Authorization"))
+		if err != nil || user.Region == "" {
+			home.ServeHTTP(w, r)
+			return
+		}
+
+		handler, ok := regional[user.Region]
+		if !ok {
+			logging.WithContext(r.Context()).Warn("Request for a region not served here",
+				"user_id", user.UserID, "region", user.Region)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusMisdirectedRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error:   "wrong_region",
+				Code:    http.StatusMisdirectedRequest,
+				Message: "Your data is kept in region " + user.Region + ", which this instance does not serve",
+			})
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// PathsUnder returns a shared predicate for RouteByRegion matching requests
+// under any of prefixes, on whole path segments, unless they are also under
+// one of except.
+func PathsUnder(prefixes []string, except ...string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		return underAny(r.URL.Path, prefixes) && !underAny(r.URL.Path, except)
+	}
+}
+
+func underAny(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
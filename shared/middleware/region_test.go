@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meal-prep/shared/logging"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteByRegion(t *testing.T) {
+	logging.Init("test")
+
+	serve := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, name)
+		})
+	}
+	handler := RouteByRegion(serve("home"), map[string]http.Handler{"eu": serve("eu")}, nil)
+
+	tokenFor := func(region string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: 7, Email: "cook@example.com", Region: region})
+		signed, err := token.SignedString([]byte("test-secret"))
+		require.NoError(t, err)
+		return "Bearer " + signed
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		expectedCode  int
+		expectedBody  string
+	}{
+		{"anonymous", "", http.StatusOK, "home"},
+		{"no region", tokenFor(""), http.StatusOK, "home"},
+		{"served region", tokenFor("eu"), http.StatusOK, "eu"},
+		{"other region", tokenFor("us"), http.StatusMisdirectedRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/recipes", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestRouteByRegion_SharedPathsStayHome(t *testing.T) {
+	logging.Init("test")
+
+	serve := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, name)
+		})
+	}
+	shared := PathsUnder([]string{"/recipes", "/ingredients"}, "/recipes/cookable")
+	handler := RouteByRegion(serve("home"), map[string]http.Handler{"eu": serve("eu")}, shared)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: 7, Region: "eu"})
+	signed, err := token.SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{http.MethodGet, "/recipes", "home"},
+		{http.MethodGet, "/recipes/3/ingredients", "home"},
+		{http.MethodHead, "/ingredients/5", "home"},
+		{http.MethodPost, "/recipes", "home"},
+		{http.MethodPut, "/recipes/3", "home"},
+		{http.MethodGet, "/recipes/cookable", "eu"},
+		{http.MethodGet, "/recipes-archive", "eu"},
+		{http.MethodGet, "/meal-plans", "eu"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+signed)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expected, recorder.Body.String())
+		})
+	}
+}
+
+func TestExtractUserFromGatewayHeaders_ReadsRegion(t *testing.T) {
+	logging.Init("test")
+
+	var got *UserContext
+	handler := ExtractUserFromGatewayHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = GetUserFromGatewayContext(r.Context())
+	}))
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: 7, Region: "eu"})
+	signed, err := token.SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, got)
+	assert.Equal(t, "eu", got.Region)
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+
+	"meal-prep/shared/tracing"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware starts a server span per request, continuing the trace
+// of the caller when it sent a traceparent header. Register it before
+// LoggingMiddleware so log lines carry the trace ID.
+func TracingMiddleware(serviceName string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/health" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			route := routeTemplate(r)
+			ctx, span := tracing.Tracer().Start(ctx, r.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.ServiceName(serviceName),
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.HTTPRoute(route),
+					semconv.URLPath(r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			ww := &responseWriterWrapper{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			span.SetAttributes(semconv.HTTPResponseStatusCode(ww.statusCode))
+			if ww.statusCode >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(ww.statusCode))
+			}
+		})
+	}
+}
+
+// routeTemplate names the span after the matched route, e.g.
+// "/recipes/{id}", so spans group by endpoint rather than by recipe.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return r.URL.Path
+}
@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+)
+
+// UsageReporter reports how much of a quota a user has used, whether it is
+// counted in this process or fetched from another service.
+type UsageReporter interface {
+	Usage(ctx context.Context, userID int) (models.QuotaUsage, error)
+}
+
+// UsageMeter counts each user's requests against one gateway quota in fixed
+// minute, hour and day windows aligned to the clock, like the gateway's own
+// rate limiting. It does not reject anything: the gateway answers 429 once a
+// limit is hit, the meter tells users how close they are before that.
+type UsageMeter struct {
+	quota       string
+	minuteLimit int
+	hourLimit   int
+	now         func() time.Time
+
+	warnThreshold float64
+	notifier      UsageWarningNotifier
+
+	mu         sync.Mutex
+	users      map[int]*userUsage
+	lastPruned time.Time
+}
+
+type userUsage struct {
+	minute, hour, day             time.Time // start of each window
+	minuteUsed, hourUsed, dayUsed int
+}
+
+func NewUsageMeter(quota string, minuteLimit, hourLimit int) *UsageMeter {
+	return &UsageMeter{
+		quota:       quota,
+		minuteLimit: minuteLimit,
+		hourLimit:   hourLimit,
+		now:         time.Now,
+		users:       make(map[int]*userUsage),
+	}
+}
+
+// WarnAt has the meter notify a user once per window when their requests
+// reach threshold, a fraction of the window's limit. 0 disables warnings.
+func (m *UsageMeter) WarnAt(threshold float64, notifier UsageWarningNotifier) *UsageMeter {
+	m.warnThreshold = threshold
+	m.notifier = notifier
+	return m
+}
+
+// Middleware counts requests by the authenticated user and warns them as they
+// near a limit. It sets no rate limit headers: the gateway's own headers
+// report the limit it enforces. It must run after
+// ExtractUserFromGatewayHeaders; anonymous requests are not counted.
+func (m *UsageMeter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, ok := GetUserFromGatewayContext(r.Context()); ok {
+			_, warnings := m.record(user.UserID)
+			for _, warning := range warnings {
+				if err := m.notifier.NotifyUsageWarning(warning); err != nil {
+					logging.WithContext(r.Context()).Warn("Failed to send usage warning", "quota", warning.Quota, "error", err)
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// record counts a request and returns the user's usage after it, with a
+// warning for each window it took to the warning threshold.
+func (m *UsageMeter) record(userID int) (models.QuotaUsage, []models.UsageWarning) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now().UTC()
+	m.prune(now)
+
+	u, ok := m.users[userID]
+	if !ok {
+		u = &userUsage{}
+		m.users[userID] = u
+	}
+	u.roll(now)
+	u.minuteUsed++
+	u.hourUsed++
+	u.dayUsed++
+
+	usage := m.quotaUsage(*u)
+	var warnings []models.UsageWarning
+	for _, window := range []struct {
+		name   string
+		window models.UsageWindow
+	}{{"minute", usage.Minute}, {"hour", usage.Hour}} {
+		if m.warnThreshold > 0 && window.window.Used == warnAt(m.warnThreshold, window.window.Limit) {
+			warnings = append(warnings, models.UsageWarning{
+				UserID: userID,
+				Quota:  m.quota,
+				Window: window.name,
+				Usage:  window.window,
+			})
+		}
+	}
+	return usage, warnings
+}
+
+func (m *UsageMeter) Usage(ctx context.Context, userID int) (models.QuotaUsage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var u userUsage
+	if counted, ok := m.users[userID]; ok {
+		u = *counted
+	}
+	u.roll(m.now().UTC())
+	return m.quotaUsage(u), nil
+}
+
+func (m *UsageMeter) quotaUsage(u userUsage) models.QuotaUsage {
+	return models.QuotaUsage{
+		Quota:         m.quota,
+		Minute:        usageWindow(u.minuteUsed, m.minuteLimit, u.minute.Add(time.Minute)),
+		Hour:          usageWindow(u.hourUsed, m.hourLimit, u.hour.Add(time.Hour)),
+		RequestsToday: u.dayUsed,
+	}
+}
+
+// Handler serves a user's usage as JSON for ?user_id=, for UsageClient in
+// other services. It is internal and must not be routed by the gateway.
+func (m *UsageMeter) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := strconv.Atoi(r.URL.Query().Get("user_id"))
+		if err != nil || userID < 1 {
+			models.WriteErrorResponse(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		usage, _ := m.Usage(r.Context(), userID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(usage)
+	})
+}
+
+// prune drops users with nothing counted today, at most once a minute.
+// Callers must hold mu.
+func (m *UsageMeter) prune(now time.Time) {
+	if now.Sub(m.lastPruned) < time.Minute {
+		return
+	}
+	m.lastPruned = now
+
+	today := now.Truncate(24 * time.Hour)
+	for id, u := range m.users {
+		if u.day.Before(today) {
+			delete(m.users, id)
+		}
+	}
+}
+
+// roll starts new windows for any that have ended by now.
+func (u *userUsage) roll(now time.Time) {
+	if minute := now.Truncate(time.Minute); !u.minute.Equal(minute) {
+		u.minute, u.minuteUsed = minute, 0
+	}
+	if hour := now.Truncate(time.Hour); !u.hour.Equal(hour) {
+		u.hour, u.hourUsed = hour, 0
+	}
+	if day := now.Truncate(24 * time.Hour); !u.day.Equal(day) {
+		u.day, u.dayUsed = day, 0
+	}
+}
+
+// warnAt is the request count at which a window with limit reaches threshold.
+func warnAt(threshold float64, limit int) int {
+	return max(int(math.Ceil(threshold*float64(limit))), 1)
+}
+
+func usageWindow(used, limit int, resetsAt time.Time) models.UsageWindow {
+	return models.UsageWindow{
+		Used:      used,
+		Limit:     limit,
+		Remaining: max(limit-used, 0),
+		ResetsAt:  resetsAt,
+	}
+}
+
+// UsageClient fetches usage from another service's UsageMeter.Handler.
+type UsageClient struct {
+	url    string
+	client *http.Client
+}
+
+// NewUsageClient calls the meter handler served at url.
+func NewUsageClient(url string, client *http.Client) *UsageClient {
+	return &UsageClient{url: url, client: client}
+}
+
+func (c *UsageClient) Usage(ctx context.Context, userID int) (models.QuotaUsage, error) {
+	var usage models.QuotaUsage
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"?"+url.Values{"user_id": {strconv.Itoa(userID)}}.Encode(), nil)
+	if err != nil {
+		return usage, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return usage, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return usage, fmt.Errorf("usage request failed with status %d", resp.StatusCode)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&usage)
+	return usage, err
+}
+
+// UsageWarningNotifier tells users they are close to a rate limit. There is
+// no notification service yet, so mains use the log notifier.
+type UsageWarningNotifier interface {
+	NotifyUsageWarning(warning models.UsageWarning) error
+}
+
+type logUsageWarningNotifier struct{}
+
+// NewLogUsageWarningNotifier returns a UsageWarningNotifier that writes
+// warnings to the service log.
+func NewLogUsageWarningNotifier() UsageWarningNotifier {
+	return logUsageWarningNotifier{}
+}
+
+func (logUsageWarningNotifier) NotifyUsageWarning(warning models.UsageWarning) error {
+	logging.Logger.Warn("User approaching rate limit", "user_id", warning.UserID, "quota", warning.Quota,
+		"window", warning.Window, "used", warning.Usage.Used, "limit", warning.Usage.Limit,
+		"resets_at", warning.Usage.ResetsAt)
+	return nil
+}
+
+const defaultUsageWarningThreshold = 0.8
+
+// UsageWarningThresholdFromEnv reads RATE_LIMIT_WARNING_THRESHOLD, the
+// fraction of a limit at which users are warned (default 0.8; 0 disables).
+func UsageWarningThresholdFromEnv() float64 {
+	if f, err := strconv.ParseFloat(os.Getenv("RATE_LIMIT_WARNING_THRESHOLD"), 64); err == nil && f >= 0 && f <= 1 {
+		return f
+	}
+	return defaultUsageWarningThreshold
+}
+
+// UsageLimit reads a quota limit from the environment, falling back when the
+// variable is unset or not a positive integer. Set it to match the gateway.
+func UsageLimit(envKey string, fallback int) int {
+	return BulkheadLimit(envKey, fallback)
+}
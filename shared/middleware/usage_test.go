@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageMeter_CountsPerUserWindows(t *testing.T) {
+	now := time.Date(2026, 3, 14, 9, 59, 30, 0, time.UTC)
+	meter := NewUsageMeter("recipes", 3, 10)
+	meter.now = func() time.Time { return now }
+
+	handler := meter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	request := func(userID int) {
+		req := httptest.NewRequest(http.MethodGet, "/me/recipes", nil)
+		if userID != 0 {
+			req = req.WithContext(context.WithValue(req.Context(), UserCtxKey, &UserContext{UserID: userID}))
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	request(1)
+	request(1)
+	request(2)
+	request(0) // anonymous, not counted
+
+	usage, err := meter.Usage(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.QuotaUsage{
+		Quota: "recipes",
+		Minute: models.UsageWindow{Used: 2, Limit: 3, Remaining: 1,
+			ResetsAt: time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)},
+		Hour: models.UsageWindow{Used: 2, Limit: 10, Remaining: 8,
+			ResetsAt: time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)},
+		RequestsToday: 2,
+	}, usage)
+
+	// The minute and hour roll over, the day keeps counting
+	now = now.Add(time.Minute)
+	request(1)
+	request(1)
+	request(1)
+	request(1)
+
+	usage, _ = meter.Usage(context.Background(), 1)
+	assert.Equal(t, 4, usage.Minute.Used)
+	assert.Equal(t, 0, usage.Minute.Remaining)
+	assert.Equal(t, 4, usage.Hour.Used)
+	assert.Equal(t, 6, usage.RequestsToday)
+
+	usage, _ = meter.Usage(context.Background(), 3)
+	assert.Equal(t, 0, usage.RequestsToday)
+	assert.Equal(t, 3, usage.Minute.Remaining)
+}
+
+type recordingWarningNotifier struct {
+	warnings []models.UsageWarning
+}
+
+func (n *recordingWarningNotifier) NotifyUsageWarning(warning models.UsageWarning) error {
+	n.warnings = append(n.warnings, warning)
+	return nil
+}
+
+func TestUsageMeter_WarnsOncePerWindow(t *testing.T) {
+	now := time.Date(2026, 3, 14, 9, 59, 45, 0, time.UTC)
+	notifier := &recordingWarningNotifier{}
+	meter := NewUsageMeter("recipes", 5, 100).WarnAt(0.8, notifier)
+	meter.now = func() time.Time { return now }
+
+	handler := meter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	var rec *httptest.ResponseRecorder
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/me/recipes", nil)
+		req = req.WithContext(context.WithValue(req.Context(), UserCtxKey, &UserContext{UserID: 1}))
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+	}
+
+	assert.Empty(t, rec.Header().Get("X-RateLimit-Remaining"))
+
+	require.Len(t, notifier.warnings, 1)
+	assert.Equal(t, models.UsageWarning{
+		UserID: 1,
+		Quota:  "recipes",
+		Window: "minute",
+		Usage: models.UsageWindow{Used: 4, Limit: 5, Remaining: 1,
+			ResetsAt: time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)},
+	}, notifier.warnings[0])
+}
+
+func TestUsageMeter_PrunesUsersFromPreviousDays(t *testing.T) {
+	now := time.Date(2026, 3, 14, 23, 59, 0, 0, time.UTC)
+	meter := NewUsageMeter("recipes", 3, 10)
+	meter.now = func() time.Time { return now }
+
+	meter.record(1)
+	now = now.Add(2 * time.Minute)
+	meter.record(2)
+
+	assert.Len(t, meter.users, 1)
+	usage, _ := meter.Usage(context.Background(), 1)
+	assert.Equal(t, 0, usage.RequestsToday)
+}
+
+func TestUsageClient_FetchesFromMeterHandler(t *testing.T) {
+	meter := NewUsageMeter("recommendations", 50, 2000)
+	meter.record(7)
+	meter.record(7)
+
+	server := httptest.NewServer(meter.Handler())
+	defer server.Close()
+
+	usage, err := NewUsageClient(server.URL, server.Client()).Usage(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, "recommendations", usage.Quota)
+	assert.Equal(t, 2, usage.Minute.Used)
+	assert.Equal(t, 48, usage.Minute.Remaining)
+	assert.Equal(t, 2, usage.RequestsToday)
+}
+
+func TestUsageMeter_HandlerRejectsInvalidUserID(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewUsageMeter("recipes", 3, 10).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/internal/usage?user_id=abc", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
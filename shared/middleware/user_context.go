@@ -3,19 +3,31 @@ package middleware
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 
 	"meal-prep/shared/logging"
 	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 // UserContext holds user info from gateway headers
 type UserContext struct {
-	UserID int    `json:"user_id"`
-	Email  string `json:"email"`
+	UserID int      `json:"user_id"`
+	Email  string   `json:"email"`
+	Role   string   `json:"role,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+	// Region is the user's data residency region; empty for the home
+	// database
+	Region string `json:"region,omitempty"`
+}
+
+// IsAdmin reports whether the user carries the admin role.
+func (u *UserContext) IsAdmin() bool {
+	return u != nil && u.Role == models.RoleAdmin
 }
 
 type userContextKey string
@@ -26,56 +38,115 @@ const UserCtxKey userContextKey = "gateway_user"
 type Claims struct {
 	UserID int    `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role,omitempty"`
+	Scope  string `json:"scope,omitempty"` // space-separated, for service tokens
+	Region string `json:"region,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// ExtractUserFromGatewayHeaders reads user info from Kong headers
-func ExtractUserFromGatewayHeaders(next http.Handler) http.Handler {
+// OptionalUserFromGatewayHeaders adds the user to the context like
+// ExtractUserFromGatewayHeaders when the request carries a readable token,
+// and lets every other request through anonymously. Public routes are not
+// behind Kong's JWT check, so the user must only be used for harmless
+// personalisation there.
+func OptionalUserFromGatewayHeaders(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		logger := logging.WithContext(r.Context())
-
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			logger.Warn("No Authorization header")
-			writeContextError(w, "Missing user context", http.StatusUnauthorized)
+		if _, ok := GetUserFromGatewayContext(r.Context()); ok {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			logger.Warn("Invalid Authorization header format")
-			writeContextError(w, "Invalid authorization format", http.StatusUnauthorized)
+		user, err := userFromAuthorization(r.Header.Get("Authorization"))
+		if err != nil {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Parse JWT to extract claims (no validation - Kong already validated)
-		token, _, err := new(jwt.Parser).ParseUnverified(tokenString, &Claims{})
+		next.ServeHTTP(w, r.WithContext(withUser(r.Context(), user)))
+	})
+}
+
+// ExtractUserFromGatewayHeaders reads user info from Kong headers
+func ExtractUserFromGatewayHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := logging.WithContext(r.Context())
+
+		user, err := userFromAuthorization(r.Header.Get("Authorization"))
 		if err != nil {
-			logger.Warn("Failed to parse JWT", "error", err)
-			writeContextError(w, "Invalid token format", http.StatusUnauthorized)
+			logger.Warn("Rejected user context", "reason", err.Error())
+			writeContextError(w, err.Error(), http.StatusUnauthorized)
 			return
 		}
 
-		claims, ok := token.Claims.(*Claims)
+		logger.Debug("User extracted from JWT token", "user_id", user.UserID)
+
+		next.ServeHTTP(w, r.WithContext(withUser(r.Context(), user)))
+	})
+}
+
+// withUser adds the user to ctx for handlers, for logging and as the
+// subject of policy decisions.
+func withUser(ctx context.Context, user *UserContext) context.Context {
+	ctx = context.WithValue(ctx, UserCtxKey, user)
+	ctx = logging.WithUserID(ctx, user.UserID)
+	return policy.WithSubject(ctx, policy.Subject{UserID: user.UserID, Role: user.Role, Scopes: user.Scopes})
+}
+
+// userFromAuthorization reads the user from a bearer token. The error text is
+// what clients are told.
+func userFromAuthorization(authHeader string) (*UserContext, error) {
+	if authHeader == "" {
+		return nil, errors.New("Missing user context")
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		return nil, errors.New("Invalid authorization format")
+	}
+
+	// Parse JWT to extract claims (no validation - Kong already validated)
+	token, _, err := new(jwt.Parser).ParseUnverified(tokenString, &Claims{})
+	if err != nil {
+		return nil, errors.New("Invalid token format")
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok {
+		return nil, errors.New("Invalid token claims")
+	}
+
+	// Create user context from JWT claims
+	return &UserContext{
+		UserID: claims.UserID,
+		Email:  claims.Email,
+		Role:   claims.Role,
+		Scopes: policy.ParseScopes(claims.Scope),
+		Region: claims.Region,
+	}, nil
+}
+
+// RequireAdmin rejects requests whose user is not an admin. It must be
+// mounted after ExtractUserFromGatewayHeaders.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := GetUserFromGatewayContext(r.Context())
 		if !ok {
-			logger.Warn("Invalid JWT claims type")
-			writeContextError(w, "Invalid token claims", http.StatusUnauthorized)
+			writeContextError(w, "Missing user context", http.StatusUnauthorized)
 			return
 		}
-
-		// Create user context from JWT claims
-		user := &UserContext{
-			UserID: claims.UserID,
-			Email:  claims.Email,
+		if !user.IsAdmin() {
+			logging.WithContext(r.Context()).Warn("Admin route denied", "user_id", user.UserID)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error:   "forbidden",
+				Code:    http.StatusForbidden,
+				Message: "Admin role required",
+			})
+			return
 		}
 
-		logger.Debug("User extracted from JWT token", "user_id", user.UserID)
-
-		// Add to request context
-		ctx := context.WithValue(r.Context(), UserCtxKey, user)
-		ctx = logging.WithUserID(ctx, user.UserID)
-
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r)
 	})
 }
 
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/models"
+	"meal-prep/shared/policy"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedToken(t *testing.T, role string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: 7, Email: "cook@example.com", Role: role})
+	signed, err := token.SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+	return signed
+}
+
+func TestExtractUserFromGatewayHeaders_ReadsRole(t *testing.T) {
+	logging.Init("test")
+
+	var got *UserContext
+	handler := ExtractUserFromGatewayHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = GetUserFromGatewayContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signedToken(t, models.RoleAdmin))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, got)
+	assert.Equal(t, 7, got.UserID)
+	assert.True(t, got.IsAdmin())
+}
+
+func TestExtractUserFromGatewayHeaders_SetsPolicySubject(t *testing.T) {
+	logging.Init("test")
+
+	var subject policy.Subject
+	var ok bool
+	handler := ExtractUserFromGatewayHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject, ok = policy.SubjectFrom(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signedToken(t, models.RoleAdmin))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.True(t, ok)
+	assert.Equal(t, policy.Subject{UserID: 7, Role: models.RoleAdmin, Scopes: []string{}}, subject)
+}
+
+func TestRequireAdmin(t *testing.T) {
+	logging.Init("test")
+
+	handler := ExtractUserFromGatewayHeaders(RequireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	tests := []struct {
+		name     string
+		role     string
+		expected int
+	}{
+		{"admin passes", models.RoleAdmin, http.StatusNoContent},
+		{"regular user is forbidden", models.RoleUser, http.StatusForbidden},
+		{"token without role is forbidden", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/ingredients/1/density", nil)
+			req.Header.Set("Authorization", "Bearer "+signedToken(t, tt.role))
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expected, recorder.Code)
+		})
+	}
+}
+
+func TestOptionalUserFromGatewayHeaders(t *testing.T) {
+	logging.Init("test")
+
+	var got *UserContext
+	handler := OptionalUserFromGatewayHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = GetUserFromGatewayContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		expectedUser  bool
+	}{
+		{"signed in", "Bearer " + signedToken(t, models.RoleUser), true},
+		{"anonymous", "", false},
+		{"unreadable token", "Bearer not-a-jwt", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			req := httptest.NewRequest(http.MethodGet, "/recipes/1", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusNoContent, recorder.Code)
+			assert.Equal(t, tt.expectedUser, got != nil)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+// Allergen is one of the allergens ingredients can be tagged with. Code is
+// what requests use, e.g. tree_nut; Name is for showing to people.
+type Allergen struct {
+	ID   int    `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// IngredientAllergensRequest replaces an ingredient's allergens with those
+// listed by code.
+type IngredientAllergensRequest struct {
+	Allergens []string `json:"allergens"`
+}
+
+// AddIngredientAllergenRequest tags an ingredient with one allergen.
+type AddIngredientAllergenRequest struct {
+	Allergen string `json:"allergen"`
+}
+
+// RecipeAllergen is an allergen some of a recipe's ingredients carry, and
+// which ingredients those are, so clients can say why they warn.
+type RecipeAllergen struct {
+	Code        string   `json:"code"`
+	Name        string   `json:"name"`
+	Ingredients []string `json:"ingredients"`
+}
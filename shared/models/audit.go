@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audit actions
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditEntry records one change made through the API: who made it, to what,
+// and the entity as the API showed it before and after. Before is null for
+// creations and after for deletions, or when the API has no JSON view of the
+// entity.
+type AuditEntry struct {
+	ID        int             `json:"id"`
+	UserID    *int            `json:"user_id,omitempty"`
+	Entity    string          `json:"entity"` // e.g. recipes, recipes.ingredients
+	EntityID  *string         `json:"entity_id,omitempty"`
+	Action    string          `json:"action"`
+	Path      string          `json:"path"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditFilter narrows the audit log; zero fields match everything.
+type AuditFilter struct {
+	UserID   int
+	Entity   string
+	EntityID string
+	Action   string
+}
+
+// IsValidAuditAction reports whether action is one of the audit actions.
+func IsValidAuditAction(action string) bool {
+	switch action {
+	case AuditActionCreate, AuditActionUpdate, AuditActionDelete:
+		return true
+	}
+	return false
+}
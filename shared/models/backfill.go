@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Backfill jobs repair data written before the rules that now keep it
+// consistent, or left stale by changes elsewhere.
+const (
+	// BackfillNormalizeUnits rewrites recipe ingredient units to their
+	// canonical spelling, "grams" to "g"
+	BackfillNormalizeUnits = "normalize-units"
+	// BackfillRecipeCosts snapshots the cost of recipes whose ingredient
+	// data changed since their last snapshot
+	BackfillRecipeCosts = "recipe-costs"
+	// BackfillSearchIndexes rebuilds the ingredient search indexes
+	BackfillSearchIndexes = "search-indexes"
+)
+
+// BackfillJobs lists every backfill job.
+var BackfillJobs = []string{BackfillNormalizeUnits, BackfillRecipeCosts, BackfillSearchIndexes}
+
+const (
+	BackfillRunning   = "running"
+	BackfillCompleted = "completed"
+	BackfillFailed    = "failed"
+)
+
+// BackfillRun is the progress of one backfill: Processed of its Total units,
+// recipes or indexes are done, and Updated counts the records it repaired.
+type BackfillRun struct {
+	ID         int        `json:"id"`
+	Job        string     `json:"job"`
+	Status     string     `json:"status"`
+	Total      int        `json:"total"`
+	Processed  int        `json:"processed"`
+	Updated    int        `json:"updated"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+type StartBackfillRequest struct {
+	Job string `json:"job"`
+}
@@ -0,0 +1,112 @@
+package models
+
+import "time"
+
+// RecipeStep is one instruction of a recipe's method. IngredientIDs name the
+// recipe ingredients used in the step.
+type RecipeStep struct {
+	ID              int         `json:"id"`
+	RecipeID        int         `json:"recipe_id"`
+	Position        int         `json:"position"` // 1-based
+	Instruction     string      `json:"instruction"`
+	DurationMinutes *int        `json:"duration_minutes,omitempty"`
+	IngredientIDs   []int       `json:"ingredient_ids"`
+	Timers          []StepTimer `json:"timers"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+// StepTimer is a countdown a cooking UI can start when the user reaches the
+// step, e.g. {"label": "Simmer", "duration_seconds": 1200}.
+type StepTimer struct {
+	Label           string `json:"label"`
+	DurationSeconds int    `json:"duration_seconds"`
+}
+
+type CreateStepRequest struct {
+	Instruction     string      `json:"instruction"`
+	DurationMinutes *int        `json:"duration_minutes,omitempty"`
+	IngredientIDs   []int       `json:"ingredient_ids,omitempty"`
+	Timers          []StepTimer `json:"timers,omitempty"`
+}
+
+// ReorderStepsRequest lists every step ID of a recipe in the new order.
+type ReorderStepsRequest struct {
+	StepIDs []int `json:"step_ids"`
+}
+
+// Cooking techniques a recipe can be tagged with. Recommendations compare a
+// recipe's techniques with those of recipes the user has cooked.
+const (
+	TechniqueKnifeWork   = "knife_work"
+	TechniqueSauteing    = "sauteing"
+	TechniqueBoiling     = "boiling"
+	TechniqueRoasting    = "roasting"
+	TechniqueGrilling    = "grilling"
+	TechniqueBraising    = "braising"
+	TechniquePoaching    = "poaching"
+	TechniqueSteaming    = "steaming"
+	TechniqueDeepFrying  = "deep_frying"
+	TechniqueEmulsifying = "emulsifying"
+	TechniqueBaking      = "baking"
+	TechniqueBreadMaking = "bread_making"
+	TechniquePastry      = "pastry"
+	TechniqueFermenting  = "fermenting"
+)
+
+// RecipeTechniques lists the techniques a recipe practises, sorted by name.
+type RecipeTechniques struct {
+	RecipeID   int      `json:"recipe_id"`
+	Techniques []string `json:"techniques"`
+}
+
+// SetTechniquesRequest replaces every technique of a recipe.
+type SetTechniquesRequest struct {
+	Techniques []string `json:"techniques"`
+}
+
+// CookingSession records a user's progress through a recipe in cooking mode.
+// CurrentStep indexes CookingSessionView.Steps and equals len(Steps) once
+// the last step is done.
+type CookingSession struct {
+	Token       string     `json:"token"`
+	UserID      int        `json:"user_id"`
+	RecipeID    int        `json:"recipe_id"`
+	Scale       float64    `json:"scale"`
+	CurrentStep int        `json:"current_step"`
+	StartedAt   time.Time  `json:"started_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+type StartCookingSessionRequest struct {
+	Scale float64 `json:"scale,omitempty"` // defaults to 1
+}
+
+type UpdateCookingSessionRequest struct {
+	CurrentStep int `json:"current_step"`
+}
+
+// CookingSessionView is what a cooking UI renders: the session plus the
+// recipe as a sequence of steps with ingredient amounts already scaled.
+type CookingSessionView struct {
+	CookingSession
+	RecipeName   string        `json:"recipe_name"`
+	Steps        []CookingStep `json:"steps"`
+	TotalMinutes int           `json:"total_minutes"` // step durations, or longest timer when a step has none
+}
+
+type CookingStep struct {
+	Number          int                     `json:"number"` // 1-based
+	Instruction     string                  `json:"instruction"`
+	DurationMinutes *int                    `json:"duration_minutes,omitempty"`
+	Ingredients     []CookingStepIngredient `json:"ingredients"`
+	Timers          []StepTimer             `json:"timers"`
+}
+
+type CookingStepIngredient struct {
+	IngredientID int     `json:"ingredient_id"`
+	Name         string  `json:"name"`
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+	Notes        *string `json:"notes,omitempty"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Moderation states of a cooking photo shared with the community.
+const (
+	PhotoStatusPending  = "pending"
+	PhotoStatusApproved = "approved"
+	PhotoStatusRejected = "rejected"
+)
+
+// CookingPhoto is a picture attached to a cooking-log entry. Status is only
+// set once the cook shares the photo.
+type CookingPhoto struct {
+	URL     string  `json:"url"`
+	Caption *string `json:"caption,omitempty"`
+	Shared  bool    `json:"shared"`
+	Status  *string `json:"status,omitempty"`
+}
+
+type CookingPhotoRequest struct {
+	URL     string  `json:"url"`
+	Caption *string `json:"caption,omitempty"`
+	Share   bool    `json:"share"`
+}
+
+type ReviewCookingPhotoRequest struct {
+	Status string `json:"status"`
+}
+
+// CommunityPhoto is an approved cooking photo as shown on a recipe page. It
+// deliberately leaves out who cooked the dish.
+type CommunityPhoto struct {
+	CookingID int       `json:"cooking_id"`
+	RecipeID  int       `json:"recipe_id"`
+	URL       string    `json:"url"`
+	Caption   *string   `json:"caption,omitempty"`
+	Rating    *int      `json:"rating,omitempty"`
+	CookedAt  time.Time `json:"cooked_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// IngredientPrice is what an amount of an ingredient costs, e.g. 1.29 for
+// 400 g. Prices are kept as a history; the newest one is current.
+type IngredientPrice struct {
+	ID           int       `json:"id"`
+	IngredientID int       `json:"ingredient_id"`
+	Price        float64   `json:"price"`
+	Quantity     float64   `json:"quantity"`
+	Unit         string    `json:"unit"`
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+type SetIngredientPriceRequest struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+}
+
+// RecipeCostSnapshot is the cost of a recipe when one of its ingredients
+// changed price. Ingredients without a usable price are not in Cost.
+type RecipeCostSnapshot struct {
+	RecipeID          int       `json:"recipe_id"`
+	Cost              float64   `json:"cost"`
+	PricedIngredients int       `json:"priced_ingredients"`
+	TotalIngredients  int       `json:"total_ingredients"`
+	RecordedAt        time.Time `json:"recorded_at"`
+}
+
+// Complete reports whether every ingredient of the recipe had a price.
+func (s RecipeCostSnapshot) Complete() bool {
+	return s.PricedIngredients == s.TotalIngredients
+}
+
+// RecipeCostHistory lists a recipe's cost snapshots, oldest first.
+// ChangePercent compares the latest complete snapshot with the first one,
+// and is left out until there are two to compare.
+type RecipeCostHistory struct {
+	RecipeID      int                  `json:"recipe_id"`
+	Snapshots     []RecipeCostSnapshot `json:"snapshots"`
+	ChangePercent *float64             `json:"change_percent,omitempty"`
+}
@@ -0,0 +1,128 @@
+package models
+
+// Diets an ingredient or recipe can be classified as, from most to least
+// restrictive. Something classified as vegan also suits every later diet.
+const (
+	DietVegan       = "vegan"
+	DietVegetarian  = "vegetarian"
+	DietPescatarian = "pescatarian"
+	DietOmnivore    = "omnivore" // contains meat or another product of slaughter
+)
+
+// IsValidDiet reports whether diet is one of the diets.
+func IsValidDiet(diet string) bool {
+	switch diet {
+	case DietVegan, DietVegetarian, DietPescatarian, DietOmnivore:
+		return true
+	}
+	return false
+}
+
+// Dietary flags record what an ingredient is made from, for the cases its
+// category does not settle.
+const (
+	DietaryFlagMeat       = "meat"
+	DietaryFlagGelatin    = "gelatin"
+	DietaryFlagFish       = "fish"
+	DietaryFlagShellfish  = "shellfish"
+	DietaryFlagDairy      = "dairy"
+	DietaryFlagEgg        = "egg"
+	DietaryFlagHoney      = "honey"
+	DietaryFlagPlantBased = "plant_based" // contains no animal products at all
+)
+
+// IsValidDietaryFlag reports whether flag is one of the dietary flags.
+func IsValidDietaryFlag(flag string) bool {
+	switch flag {
+	case DietaryFlagMeat, DietaryFlagGelatin, DietaryFlagFish, DietaryFlagShellfish, DietaryFlagDairy,
+		DietaryFlagEgg, DietaryFlagHoney, DietaryFlagPlantBased:
+		return true
+	}
+	return false
+}
+
+// How an ingredient's diet was decided, from most to least reliable.
+const (
+	DietSourceOverride = "override"
+	DietSourceFlags    = "flags"
+	DietSourceCategory = "category"
+	DietSourceDefault  = "default" // nothing known, assumed the least restrictive
+)
+
+// DietaryFacts are what the classification rules know about an ingredient
+// beyond its category. Override, when set, decides the diet outright.
+type DietaryFacts struct {
+	Flags    []string `json:"flags"`
+	Override string   `json:"override,omitempty"`
+}
+
+// IngredientDietary is an ingredient's diet and how sure the classification
+// is, from 0 (a guess) to 1 (set by hand).
+type IngredientDietary struct {
+	IngredientID int      `json:"ingredient_id"`
+	Name         string   `json:"name"`
+	Diet         string   `json:"diet"`
+	Confidence   float64  `json:"confidence"`
+	Source       string   `json:"source"`
+	Flags        []string `json:"flags"`
+	Override     string   `json:"override,omitempty"`
+}
+
+// RecipeDietary is the least restrictive diet among a recipe's ingredients.
+// Confidence is that of the least certain ingredient.
+type RecipeDietary struct {
+	RecipeID    int                 `json:"recipe_id"`
+	Diet        string              `json:"diet"`
+	Confidence  float64             `json:"confidence"`
+	Ingredients []IngredientDietary `json:"ingredients"`
+}
+
+// Why an ingredient fails a diet profile.
+const (
+	ComplianceDiet          = "diet"           // suits a less restrictive diet than the profile's
+	ComplianceExcludedFlag  = "excluded_flag"  // contains something the profile excludes
+	ComplianceLowConfidence = "low_confidence" // classified less surely than the profile requires
+)
+
+// DietProfile is what a menu must suit. Diet defaults to omnivore, so a
+// profile can exclude flags alone, e.g. dairy for a lactose-free menu.
+// Ingredients classified with less than MinConfidence fail too.
+type DietProfile struct {
+	Diet          string   `json:"diet,omitempty"`
+	ExcludeFlags  []string `json:"exclude_flags,omitempty"`
+	MinConfidence float64  `json:"min_confidence,omitempty"`
+}
+
+type ComplianceRequest struct {
+	Profile   DietProfile `json:"profile"`
+	RecipeIDs []int       `json:"recipe_ids"`
+}
+
+// ComplianceViolation is an ingredient that fails the profile and every
+// reason it does.
+type ComplianceViolation struct {
+	IngredientID int      `json:"ingredient_id"`
+	Name         string   `json:"name"`
+	Diet         string   `json:"diet"`
+	Confidence   float64  `json:"confidence"`
+	Flags        []string `json:"flags"`
+	Reasons      []string `json:"reasons"`
+}
+
+// RecipeCompliance is whether a recipe suits the profile. Diet and
+// Confidence are the recipe's classification.
+type RecipeCompliance struct {
+	RecipeID   int                   `json:"recipe_id"`
+	Name       string                `json:"name"`
+	Passes     bool                  `json:"passes"`
+	Diet       string                `json:"diet"`
+	Confidence float64               `json:"confidence"`
+	Violations []ComplianceViolation `json:"violations"`
+}
+
+// ComplianceResult lists the recipes in request order. Recipes that don't
+// exist or can't be seen are listed in NotFound instead.
+type ComplianceResult struct {
+	Recipes  []RecipeCompliance `json:"recipes"`
+	NotFound []int              `json:"not_found"`
+}
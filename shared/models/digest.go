@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// DigestSubscription is a user's opt-in to the weekly summary email.
+type DigestSubscription struct {
+	UserID       int        `json:"user_id"`
+	Locale       string     `json:"locale"`
+	SubscribedAt time.Time  `json:"subscribed_at"`
+	LastSentAt   *time.Time `json:"last_sent_at,omitempty"`
+}
+
+type SubscribeDigestRequest struct {
+	Locale string `json:"locale,omitempty"` // defaults to en
+}
+
+// WeeklyDigest is what a digest is rendered from.
+type WeeklyDigest struct {
+	UserID      int                  `json:"user_id"`
+	Locale      string               `json:"locale"`
+	WeekStart   time.Time            `json:"week_start"`
+	WeekEnd     time.Time            `json:"week_end"`
+	Cooked      []DigestCookedRecipe `json:"cooked"`
+	Suggestions []RecipeWithScore    `json:"suggestions"`
+}
+
+type DigestCookedRecipe struct {
+	RecipeID int       `json:"recipe_id"`
+	Name     string    `json:"name"`
+	CookedAt time.Time `json:"cooked_at"`
+	Rating   *int      `json:"rating,omitempty"`
+}
+
+// DigestMessage is a rendered digest, ready to hand to a notifier.
+type DigestMessage struct {
+	UserID  int    `json:"user_id"`
+	Locale  string `json:"locale"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
@@ -0,0 +1,25 @@
+package models
+
+// RecipeEmbed is what the embeddable recipe card shows: the recipe with its
+// ingredients and its cover photo, if it has any images.
+type RecipeEmbed struct {
+	Recipe     RecipeWithIngredients `json:"recipe"`
+	CoverImage *RecipeImage          `json:"cover_image,omitempty"`
+}
+
+// OEmbedResponse is an oEmbed 1.0 "rich" response. The thumbnail fields are
+// either all set or all left out, as the spec requires.
+type OEmbedResponse struct {
+	Type            string `json:"type"`
+	Version         string `json:"version"`
+	Title           string `json:"title"`
+	ProviderName    string `json:"provider_name"`
+	ProviderURL     string `json:"provider_url"`
+	CacheAge        int    `json:"cache_age"`
+	HTML            string `json:"html"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+	ThumbnailURL    string `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth  int    `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight int    `json:"thumbnail_height,omitempty"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// WriteCacheableResponse is WriteSuccessResponse with 200 for GETs clients
+// poll. The response keeps an ETag the handler already set, such as the
+// version, or gets one hashed from the payload, and a request whose
+// If-None-Match still matches it is answered 304 without a body.
+func WriteCacheableResponse(w http.ResponseWriter, r *http.Request, data interface{}) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		WriteErrorResponse(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	if w.Header().Get("ETag") == "" {
+		sum := sha256.Sum256(buf.Bytes())
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	}
+	if NotModified(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// NotModified answers 304 when the request's If-None-Match names the ETag
+// already set on w, reporting whether it did. Tags compare weakly, as
+// RFC 9110 asks for If-None-Match.
+func NotModified(w http.ResponseWriter, r *http.Request) bool {
+	etag := w.Header().Get("ETag")
+	header := r.Header.Get("If-None-Match")
+	if etag == "" || header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
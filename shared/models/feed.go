@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// PublishedRecipe is a public recipe's entry in the sitemap.
+type PublishedRecipe struct {
+	ID        int       `json:"id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SitemapChunk is one page of the recipe sitemap. Number is 1-based and
+// LastModified is the latest update among its recipes.
+type SitemapChunk struct {
+	Number       int       `json:"number"`
+	LastModified time.Time `json:"last_modified"`
+}
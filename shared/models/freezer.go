@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Warnings on freezer items nearing or past their eat-before date
+const (
+	FreezerWarningEatSoon       = "eat_soon"
+	FreezerWarningPastEatBefore = "past_eat_before"
+)
+
+// FreezerItem is a batch of frozen portions of one recipe. DaysLeft and
+// Warning are worked out against today when the item is read.
+type FreezerItem struct {
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	RecipeID   int       `json:"recipe_id"`
+	RecipeName string    `json:"recipe_name"`
+	Portions   int       `json:"portions"`
+	FrozenOn   string    `json:"frozen_on"`  // YYYY-MM-DD
+	EatBefore  string    `json:"eat_before"` // YYYY-MM-DD
+	DaysLeft   int       `json:"days_left"`  // zero or less once past eat_before
+	Warning    string    `json:"warning,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AddFreezerItemRequest struct {
+	RecipeID  int    `json:"recipe_id"`
+	Portions  int    `json:"portions"`
+	FrozenOn  string `json:"frozen_on,omitempty"`  // defaults to today
+	EatBefore string `json:"eat_before,omitempty"` // defaults to three months after frozen_on
+}
+
+// UpdateFreezerItemRequest sets how many portions are left; zero removes
+// the item.
+type UpdateFreezerItemRequest struct {
+	Portions int `json:"portions"`
+}
@@ -1,13 +1,108 @@
 package models
 
+import "time"
+
 type GroceryListItem struct {
-	IngredientID  int        `json:"ingredient_id"`
-	Ingredient    Ingredient `json:"ingredient"`
-	TotalQuantity float64    `json:"total_quantity"`
-	Unit          string     `json:"unit"`
-	Recipes       []string   `json:"recipes"` // List of recipe names using this ingredient
+	IngredientID  int           `json:"ingredient_id"`
+	Ingredient    Ingredient    `json:"ingredient"`
+	TotalQuantity float64       `json:"total_quantity"`
+	Unit          string        `json:"unit"`
+	Recipes       []string      `json:"recipes"`                  // List of recipe names using this ingredient
+	Purchase      *PackPurchase `json:"purchase,omitempty"`       // Set when the ingredient has known pack sizes
+	EstimatedCost *float64      `json:"estimated_cost,omitempty"` // Set on budgeted lists for priced ingredients
+}
+
+// PackPurchase is how many packs cover a grocery list item and what is left
+// over, in the item's unit.
+type PackPurchase struct {
+	PackSize PackSize `json:"pack_size"`
+	Packs    int      `json:"packs"`
+	Leftover float64  `json:"leftover"`
+	Note     string   `json:"note"`
 }
 
+// GroceryListRequest names the recipes to shop for in one of three ways: by
+// ID, as a meal plan, or as everything the user has planned between two
+// dates.
 type GroceryListRequest struct {
-	RecipeIDs []int `json:"recipe_ids"`
+	RecipeIDs     []int    `json:"recipe_ids"`
+	MealPlanID    *int     `json:"meal_plan_id,omitempty"`
+	From          string   `json:"from,omitempty"` // YYYY-MM-DD, with To
+	To            string   `json:"to,omitempty"`
+	StoreLayoutID *int     `json:"store_layout_id,omitempty"` // order items by this layout's aisles
+	Budget        *float64 `json:"budget,omitempty"`          // price the list and suggest substitutions to fit
+
+	// MeasurementSystem totals are shown in; set from ?units= or the
+	// user's profile, "" keeps the recipes' units
+	MeasurementSystem string `json:"-"`
+
+	// Scale multiplies every recipe's amounts, e.g. a meal plan's scale;
+	// 0 means 1
+	Scale float64 `json:"-"`
+}
+
+// BudgetedGroceryList is a grocery list priced against a budget. Items
+// without a current price are not in the totals and are counted in
+// UnpricedItems.
+type BudgetedGroceryList struct {
+	Items                         []GroceryListItem        `json:"items"`
+	Budget                        float64                  `json:"budget"`
+	EstimatedTotal                float64                  `json:"estimated_total"`
+	UnpricedItems                 int                      `json:"unpriced_items"`
+	Suggestions                   []SubstitutionSuggestion `json:"suggestions"`
+	EstimatedTotalWithSuggestions float64                  `json:"estimated_total_with_suggestions"`
+	WithinBudget                  bool                     `json:"within_budget"`
+}
+
+// SubstitutionSuggestion replaces a grocery list item with a cheaper
+// substitute, in the quantity and unit given.
+type SubstitutionSuggestion struct {
+	IngredientID int     `json:"ingredient_id"`
+	Ingredient   string  `json:"ingredient"`
+	SubstituteID int     `json:"substitute_id"`
+	Substitute   string  `json:"substitute"`
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+	Saving       float64 `json:"saving"`
+	Notes        *string `json:"notes,omitempty"`
+}
+
+// SharedShoppingList is a grocery list shared by link: anyone with the token
+// can open it and check items off without an account. Version goes up with
+// every checked or unchecked item.
+type SharedShoppingList struct {
+	Token     string                   `json:"token"`
+	UserID    int                      `json:"-"`
+	URL       string                   `json:"url,omitempty"` // the link to share, returned to the owner
+	Version   int                      `json:"version"`
+	Items     []SharedShoppingListItem `json:"items"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+// SharedShoppingListItem is a grocery list item as it was when the list was
+// shared. Quantity is -1 for mixed units, as on the grocery list.
+type SharedShoppingListItem struct {
+	Position     int        `json:"position"` // 1-based, in the grocery list's order
+	IngredientID int        `json:"ingredient_id"`
+	Name         string     `json:"name"`
+	Category     *string    `json:"category,omitempty"`
+	Quantity     float64    `json:"quantity"`
+	Unit         string     `json:"unit"`
+	Note         *string    `json:"note,omitempty"` // the pack purchase hint
+	Checked      bool       `json:"checked"`
+	CheckedAt    *time.Time `json:"checked_at,omitempty"`
+}
+
+type CheckShoppingListItemRequest struct {
+	Checked *bool `json:"checked"`
+}
+
+// SharedShoppingListChange is one item checked or unchecked, sent to
+// everyone watching the list. Changes can arrive out of order, so a client
+// applies one only when its Version is newer than both the list it loaded
+// and the last change it applied to the same item.
+type SharedShoppingListChange struct {
+	Version int                    `json:"version"`
+	Item    SharedShoppingListItem `json:"item"`
 }
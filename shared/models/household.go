@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Household is people sharing a pantry. The invite code lets others join,
+// so only the owner gets to see it.
+type Household struct {
+	ID         int               `json:"id"`
+	Name       string            `json:"name"`
+	OwnerID    int               `json:"owner_id"`
+	InviteCode string            `json:"invite_code,omitempty"`
+	Members    []HouseholdMember `json:"members"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+type HouseholdMember struct {
+	UserID   int       `json:"user_id"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type CreateHouseholdRequest struct {
+	Name string `json:"name"`
+}
+
+type JoinHouseholdRequest struct {
+	InviteCode string `json:"invite_code"`
+}
@@ -10,6 +10,12 @@ type Ingredient struct {
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+// IngredientUsage is an ingredient with the number of recipes that use it.
+type IngredientUsage struct {
+	Ingredient
+	RecipeCount int `json:"recipe_count"`
+}
+
 type RecipeIngredient struct {
 	ID           int        `json:"id"`
 	RecipeID     int        `json:"recipe_id"`
@@ -19,6 +25,25 @@ type RecipeIngredient struct {
 	Unit         string     `json:"unit"` // cups, grams, pieces, tablespoons, etc.
 	Notes        *string    `json:"notes,omitempty"`
 	CreatedAt    time.Time  `json:"created_at"`
+
+	// ProductID names the branded product the recipe uses, if it is
+	// specific about one; Product is its details.
+	ProductID *int               `json:"product_id,omitempty"`
+	Product   *IngredientProduct `json:"product,omitempty"`
+}
+
+// IngredientCategories are the categories an ingredient may be in.
+var IngredientCategories = []string{"Meat", "Vegetables", "Dairy", "Grains", "Spices", "Oils", "Fish", "Fruits"}
+
+// IsValidIngredientCategory reports whether category is one of
+// IngredientCategories.
+func IsValidIngredientCategory(category string) bool {
+	for _, c := range IngredientCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
 }
 
 type CreateIngredientRequest struct {
@@ -32,3 +57,141 @@ type UpdateIngredientRequest struct {
 	Description string `json:"description"`
 	Category    string `json:"category"`
 }
+
+// IngredientDensity lets volume and weight measures of an ingredient be
+// converted into each other (e.g. cups of flour to grams).
+type IngredientDensity struct {
+	IngredientID int       `json:"ingredient_id"`
+	GramsPerML   float64   `json:"grams_per_ml"`
+	Source       *string   `json:"source,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type SetIngredientDensityRequest struct {
+	GramsPerML float64 `json:"grams_per_ml"`
+	Source     *string `json:"source,omitempty"` // where the figure came from, for reviewers
+}
+
+// PackSize is a size an ingredient is typically sold in, e.g. a 400 g can.
+type PackSize struct {
+	ID           int       `json:"id"`
+	IngredientID int       `json:"ingredient_id"`
+	Quantity     float64   `json:"quantity"`
+	Unit         string    `json:"unit"`
+	Label        *string   `json:"label,omitempty"` // can, bag, bottle, etc.
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PackSizeRequest struct {
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Label    *string `json:"label,omitempty"`
+}
+
+// IngredientProduct is a branded product of a generic ingredient, e.g. one
+// brand's spaghetti under Pasta, with the exact nutrition from its label.
+type IngredientProduct struct {
+	ID           int              `json:"id"`
+	IngredientID int              `json:"ingredient_id"`
+	Brand        string           `json:"brand"`
+	Name         string           `json:"name"`
+	Nutrition    ProductNutrition `json:"nutrition"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+// ProductNutrition is per 100 g, or 100 ml for liquids, as labels give it.
+type ProductNutrition struct {
+	CaloriesKcal float64 `json:"calories_kcal"`
+	ProteinG     float64 `json:"protein_g"`
+	CarbsG       float64 `json:"carbs_g"`
+	FatG         float64 `json:"fat_g"`
+	FiberG       float64 `json:"fiber_g"`
+}
+
+// NutritionLookup is what the external nutrition database has for a search,
+// for previewing nutrition while authoring an ingredient or product.
+type NutritionLookup struct {
+	Query   string           `json:"query"`
+	Matches []NutritionMatch `json:"matches"`
+}
+
+// NutritionMatch is one food the nutrition database found, with its
+// nutrition per 100 g in the same shape as a product's.
+type NutritionMatch struct {
+	SourceID  string           `json:"source_id"`
+	Name      string           `json:"name"`
+	Brand     *string          `json:"brand,omitempty"`
+	Nutrition ProductNutrition `json:"nutrition"`
+}
+
+type IngredientProductRequest struct {
+	Brand     string           `json:"brand"`
+	Name      string           `json:"name"`
+	Nutrition ProductNutrition `json:"nutrition"`
+}
+
+// IngredientSubstitution is an ingredient that can stand in for another.
+// Ratio is how much of the substitute replaces one unit of the original.
+type IngredientSubstitution struct {
+	IngredientID int        `json:"ingredient_id"`
+	SubstituteID int        `json:"substitute_id"`
+	Substitute   Ingredient `json:"substitute"`
+	Ratio        float64    `json:"ratio"`
+	Notes        *string    `json:"notes,omitempty"`
+}
+
+type SubstitutionRequest struct {
+	SubstituteID int     `json:"substitute_id"`
+	Ratio        float64 `json:"ratio"` // defaults to 1
+	Notes        *string `json:"notes,omitempty"`
+}
+
+// IngredientTranslation is an ingredient's name in another language. Locale
+// is a lower-case language tag such as "ru" or "pt-br".
+type IngredientTranslation struct {
+	Locale string `json:"locale"`
+	Name   string `json:"name"`
+}
+
+const (
+	IngredientCleanupArchive = "archive"
+	IngredientCleanupDelete  = "delete"
+)
+
+// UnusedIngredient is an ingredient no recipe uses. LastUsedAt is when it
+// last left a recipe; it is nil for ingredients that were never used.
+type UnusedIngredient struct {
+	Ingredient
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+}
+
+// IngredientCleanupRequest archives or deletes the listed unused ingredients.
+type IngredientCleanupRequest struct {
+	Action        string `json:"action"` // archive or delete
+	IngredientIDs []int  `json:"ingredient_ids"`
+}
+
+// IngredientCleanupResult lists the ingredients a cleanup acted on; requested
+// ingredients that are missing or back in use are left out.
+type IngredientCleanupResult struct {
+	Action        string `json:"action"`
+	IngredientIDs []int  `json:"ingredient_ids"`
+}
+
+// DuplicateIngredients is a pair of ingredients whose names are alike enough
+// to be one ingredient entered twice. Similarity is the names' trigram
+// similarity, from 0 to 1.
+type DuplicateIngredients struct {
+	Ingredient Ingredient `json:"ingredient"`
+	Duplicate  Ingredient `json:"duplicate"`
+	Similarity float64    `json:"similarity"`
+}
+
+// IngredientMergeResult is the ingredient another was merged into, with the
+// recipes that now use it in place of the merged one.
+type IngredientMergeResult struct {
+	Ingredient Ingredient `json:"ingredient"`
+	MergedID   int        `json:"merged_id"`
+	RecipeIDs  []int      `json:"recipe_ids"`
+}
@@ -0,0 +1,132 @@
+package models
+
+import "time"
+
+// Meal slots of a day, in the order they are eaten.
+const (
+	MealSlotBreakfast = "breakfast"
+	MealSlotLunch     = "lunch"
+	MealSlotDinner    = "dinner"
+)
+
+var mealSlotOrder = map[string]int{
+	MealSlotBreakfast: 0,
+	MealSlotLunch:     1,
+	MealSlotDinner:    2,
+}
+
+// IsValidMealSlot reports whether slot is breakfast, lunch or dinner.
+func IsValidMealSlot(slot string) bool {
+	_, ok := mealSlotOrder[slot]
+	return ok
+}
+
+// MealSlotOrder ranks slots through the day, for sorting entries.
+func MealSlotOrder(slot string) int {
+	return mealSlotOrder[slot]
+}
+
+// MealPlan is the recipes a user plans to cook over a period. Scale
+// multiplies every planned recipe's ingredient amounts; recipes do not
+// record a yield, so this is how a plan is cooked for more or fewer people.
+type MealPlan struct {
+	ID          int              `json:"id"`
+	UserID      int              `json:"user_id"`
+	Title       string           `json:"title"`
+	PeriodStart string           `json:"period_start"` // YYYY-MM-DD
+	PeriodEnd   string           `json:"period_end"`   // YYYY-MM-DD
+	PeriodType  string           `json:"period_type"`  // week, biweek, month or custom
+	IsPublic    bool             `json:"is_public"`
+	Scale       float64          `json:"scale"`
+	Members     []MealPlanMember `json:"members"` // in the order they take turns
+	Entries     []MealPlanEntry  `json:"entries"` // by date, then slot
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// MealPlanEntry is a recipe planned for one meal of one day. A meal can have
+// several recipes, say a main and a side. AssigneeID is the member cooking
+// it, nil while the plan has no members.
+type MealPlanEntry struct {
+	ID         int    `json:"id"`
+	RecipeID   int    `json:"recipe_id"`
+	Date       string `json:"date"` // YYYY-MM-DD, within the plan's period
+	Slot       string `json:"slot"` // breakfast, lunch or dinner
+	AssigneeID *int   `json:"assignee_id"`
+}
+
+// MealPlanMember is someone in the household taking turns cooking a plan's
+// meals. Members need no account; they are names on the plan.
+type MealPlanMember struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type MealPlanRequest struct {
+	Title       string  `json:"title"`
+	PeriodStart string  `json:"period_start"`
+	PeriodEnd   string  `json:"period_end"`
+	PeriodType  string  `json:"period_type,omitempty"` // defaults to week
+	IsPublic    bool    `json:"is_public"`
+	Scale       float64 `json:"scale,omitempty"` // defaults to 1
+}
+
+// MealPlanEntryRequest plans a recipe. Without an assignee, the entry goes to
+// whoever's turn the meal is.
+type MealPlanEntryRequest struct {
+	RecipeID   int    `json:"recipe_id"`
+	Date       string `json:"date"`
+	Slot       string `json:"slot"`
+	AssigneeID *int   `json:"assignee_id,omitempty"`
+}
+
+// MealPlanMembersRequest replaces a plan's members, in the order they take
+// turns, and deals the plan's meals out among them again.
+type MealPlanMembersRequest struct {
+	Members []string `json:"members"`
+}
+
+// GenerateMealPlanRequest asks for a plan filled from the user's
+// recommendations: one recipe for each slot of each day from PeriodStart.
+type GenerateMealPlanRequest struct {
+	Title       string   `json:"title,omitempty"`
+	PeriodStart string   `json:"period_start"`
+	Days        int      `json:"days,omitempty"`  // defaults to 7
+	Slots       []string `json:"slots,omitempty"` // defaults to dinner only
+	IsPublic    bool     `json:"is_public"`
+	Scale       float64  `json:"scale,omitempty"`
+}
+
+// MealPlanPreferences are how a user likes generated plans: the longest
+// total time a recipe may take on weekdays (Monday to Friday) and at
+// weekends, nil for no limit, and whether weekends get the most elaborate
+// of the recommended recipes.
+type MealPlanPreferences struct {
+	UserID            int       `json:"user_id"`
+	WeekdayMaxMinutes *int      `json:"weekday_max_minutes"`
+	WeekendMaxMinutes *int      `json:"weekend_max_minutes"`
+	ElaborateWeekends bool      `json:"elaborate_weekends"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// MealPlanPreferencesRequest replaces the preferences; a missing or null
+// limit removes it.
+type MealPlanPreferencesRequest struct {
+	WeekdayMaxMinutes *int `json:"weekday_max_minutes"`
+	WeekendMaxMinutes *int `json:"weekend_max_minutes"`
+	ElaborateWeekends bool `json:"elaborate_weekends"`
+}
+
+// MealPlanPrintout is everything printed for a meal plan: each recipe the
+// user can see, scaled by the plan, and one shopping list for all of them.
+type MealPlanPrintout struct {
+	MealPlan     MealPlan          `json:"meal_plan"`
+	Recipes      []PrintedRecipe   `json:"recipes"`
+	ShoppingList []GroceryListItem `json:"shopping_list"`
+}
+
+type PrintedRecipe struct {
+	Recipe      Recipe             `json:"recipe"`
+	Ingredients []RecipeIngredient `json:"ingredients"` // scaled by the plan
+	Steps       []RecipeStep       `json:"steps"`
+}
@@ -1,7 +1,10 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 )
 
@@ -11,6 +14,40 @@ const (
 	maxPerPage     = 100
 )
 
+var ErrInvalidPage = errors.New("page must be a positive whole number")
+
+// PaginationConfig is the per_page a list gets when none is asked for and
+// the most it may be asked for.
+type PaginationConfig struct {
+	DefaultPerPage int
+	MaxPerPage     int
+}
+
+var paginationConfig = PaginationConfig{DefaultPerPage: defaultPerPage, MaxPerPage: maxPerPage}
+
+// PaginationConfigFromEnv reads PAGINATION_DEFAULT_PER_PAGE (default 20) and
+// PAGINATION_MAX_PER_PAGE (default 100). A default above the maximum is
+// lowered to it.
+func PaginationConfigFromEnv() PaginationConfig {
+	cfg := PaginationConfig{DefaultPerPage: defaultPerPage, MaxPerPage: maxPerPage}
+	if v, err := strconv.Atoi(os.Getenv("PAGINATION_DEFAULT_PER_PAGE")); err == nil && v > 0 {
+		cfg.DefaultPerPage = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("PAGINATION_MAX_PER_PAGE")); err == nil && v > 0 {
+		cfg.MaxPerPage = v
+	}
+	if cfg.DefaultPerPage > cfg.MaxPerPage {
+		cfg.DefaultPerPage = cfg.MaxPerPage
+	}
+	return cfg
+}
+
+// ConfigurePagination sets the limits ParsePaginationParams enforces. It is
+// meant to be called once at startup, before requests are served.
+func ConfigurePagination(cfg PaginationConfig) {
+	paginationConfig = cfg
+}
+
 type PaginationParams struct {
 	Page    int
 	PerPage int
@@ -40,28 +77,28 @@ func NewPaginationMeta(params PaginationParams, total int) PaginationMeta {
 	}
 }
 
-func ParsePaginationParams(r *http.Request) PaginationParams {
-	page := defaultPage
-	perPage := defaultPerPage
+// ParsePaginationParams reads ?page= and ?per_page=. Values that are not
+// whole numbers or are out of range are rejected rather than replaced, so a
+// client asking for more than the maximum learns it isn't getting it.
+func ParsePaginationParams(r *http.Request) (PaginationParams, error) {
+	cfg := paginationConfig
+	params := PaginationParams{Page: defaultPage, PerPage: cfg.DefaultPerPage}
 
 	if p := r.URL.Query().Get("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
+		v, err := strconv.Atoi(p)
+		if err != nil || v < 1 {
+			return PaginationParams{}, ErrInvalidPage
 		}
+		params.Page = v
 	}
 
 	if pp := r.URL.Query().Get("per_page"); pp != "" {
-		if v, err := strconv.Atoi(pp); err == nil && v > 0 {
-			perPage = v
+		v, err := strconv.Atoi(pp)
+		if err != nil || v < 1 || v > cfg.MaxPerPage {
+			return PaginationParams{}, fmt.Errorf("per_page must be between 1 and %d", cfg.MaxPerPage)
 		}
+		params.PerPage = v
 	}
 
-	if perPage > maxPerPage {
-		perPage = maxPerPage
-	}
-
-	return PaginationParams{
-		Page:    page,
-		PerPage: perPage,
-	}
+	return params, nil
 }
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Pantry is what a user has at home, shared with everyone in their
+// household when they belong to one. Version goes up with every change.
+type Pantry struct {
+	HouseholdID *int         `json:"household_id,omitempty"`
+	Version     int          `json:"version"`
+	Items       []PantryItem `json:"items"`
+}
+
+// PantryItem is an ingredient in a pantry.
+type PantryItem struct {
+	IngredientID int       `json:"ingredient_id"`
+	Name         string    `json:"name"`
+	AddedBy      int       `json:"added_by"`
+	AddedAt      time.Time `json:"added_at"`
+}
+
+// UpdatePantryRequest replaces everything in the pantry with the ingredients
+// listed. With a Version, only if nobody has changed the pantry since.
+type UpdatePantryRequest struct {
+	IngredientIDs []int `json:"ingredient_ids"`
+	Version       *int  `json:"version,omitempty"`
+}
+
+// PantryChange is one edit of a pantry, sent to everyone watching it. A
+// client applies it to a pantry whose Version is one less, and reloads the
+// pantry when it has missed one.
+type PantryChange struct {
+	HouseholdID *int         `json:"household_id,omitempty"`
+	Version     int          `json:"version"`
+	ChangedBy   int          `json:"changed_by"`
+	Added       []PantryItem `json:"added"`
+	Removed     []int        `json:"removed"` // ingredient IDs
+}
+
+// Empty reports whether the edit changed nothing.
+func (c PantryChange) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0
+}
+
+// CookableRecipe is a recipe ranked by how many of its ingredients are on
+// hand, with the ones still to buy.
+type CookableRecipe struct {
+	Recipe             `json:"recipe"`
+	TotalIngredients   int                `json:"total_ingredients"`
+	OnHand             int                `json:"on_hand"`
+	Coverage           float64            `json:"coverage"` // OnHand as a fraction of TotalIngredients
+	MissingIngredients []RecipeIngredient `json:"missing_ingredients"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// PrepSession is a batch-cooking plan: recipes cooked together in one go,
+// each scaled up and split into portions.
+type PrepSession struct {
+	ID        int                 `json:"id"`
+	UserID    int                 `json:"user_id"`
+	Name      string              `json:"name"`
+	PrepDate  string              `json:"prep_date,omitempty"` // YYYY-MM-DD
+	Recipes   []PrepSessionRecipe `json:"recipes"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// PrepSessionRecipe is one batch of a prep session. Scale multiplies the
+// recipe's ingredient amounts; the batch is then split into Portions
+// containers, which keep for KeepsDays after the prep date.
+type PrepSessionRecipe struct {
+	RecipeID           int     `json:"recipe_id"`
+	Scale              float64 `json:"scale,omitempty"`      // defaults to 1
+	Portions           int     `json:"portions,omitempty"`   // defaults to 1
+	KeepsDays          int     `json:"keeps_days,omitempty"` // defaults to 4
+	ReheatInstructions string  `json:"reheat_instructions,omitempty"`
+}
+
+type PrepSessionRequest struct {
+	Name     string              `json:"name"`
+	PrepDate string              `json:"prep_date,omitempty"` // YYYY-MM-DD
+	Recipes  []PrepSessionRecipe `json:"recipes"`
+}
+
+// PrepSessionPlan is a prep session worked out: what to cook at which
+// scale, one timeline across all batches and a label per container.
+type PrepSessionPlan struct {
+	PrepSession
+	Batches      []PrepBatch         `json:"batches"`
+	Timeline     []PrepTimelineEntry `json:"timeline"`
+	TotalMinutes int                 `json:"total_minutes"`
+	Labels       []PrepLabel         `json:"labels"`
+}
+
+type PrepBatch struct {
+	RecipeID    int                     `json:"recipe_id"`
+	RecipeName  string                  `json:"recipe_name"`
+	Scale       float64                 `json:"scale"`
+	Portions    int                     `json:"portions"`
+	Ingredients []CookingStepIngredient `json:"ingredients"` // scaled for the whole batch
+}
+
+// PrepTimelineEntry schedules one recipe step, in minutes from the start of
+// the session. Hands-off steps (those with timers, like baking or
+// simmering) leave the cook free to work on another batch meanwhile.
+type PrepTimelineEntry struct {
+	StartMinute int    `json:"start_minute"`
+	EndMinute   int    `json:"end_minute"`
+	RecipeID    int    `json:"recipe_id"`
+	RecipeName  string `json:"recipe_name"`
+	Step        int    `json:"step"` // 1-based within the recipe
+	Instruction string `json:"instruction"`
+	HandsOff    bool   `json:"hands_off"`
+}
+
+// PrepLabel is printed on one container of a batch. PreparedOn is the
+// session's prep date, or the day it was planned when it has none.
+type PrepLabel struct {
+	RecipeID           int    `json:"recipe_id"`
+	RecipeName         string `json:"recipe_name"`
+	Portion            int    `json:"portion"` // 1-based
+	Portions           int    `json:"portions"`
+	PreparedOn         string `json:"prepared_on"`
+	UseBy              string `json:"use_by"`
+	ReheatInstructions string `json:"reheat_instructions,omitempty"`
+	Text               string `json:"text"`
+}
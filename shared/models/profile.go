@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// ProfileSettings are what a user controls about their public profile, and
+// the measurement system (metric or imperial) and locale they read recipes
+// in. Without a measurement system, recipes keep the units they were written
+// in; without a locale, Accept-Language decides.
+type ProfileSettings struct {
+	UserID            int       `json:"user_id"`
+	DisplayName       *string   `json:"display_name,omitempty"`
+	Bio               *string   `json:"bio,omitempty"`
+	IsPublic          bool      `json:"is_public"`
+	ShowRecipes       bool      `json:"show_recipes"`
+	ShowStats         bool      `json:"show_stats"`
+	MeasurementSystem *string   `json:"measurement_system,omitempty"`
+	Locale            *string   `json:"locale,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// UpdateProfileSettingsRequest changes only the fields that are set. An
+// empty display name, bio, measurement system or locale clears it.
+type UpdateProfileSettingsRequest struct {
+	DisplayName       *string `json:"display_name,omitempty"`
+	Bio               *string `json:"bio,omitempty"`
+	IsPublic          *bool   `json:"is_public,omitempty"`
+	ShowRecipes       *bool   `json:"show_recipes,omitempty"`
+	ShowStats         *bool   `json:"show_stats,omitempty"`
+	MeasurementSystem *string `json:"measurement_system,omitempty"`
+	Locale            *string `json:"locale,omitempty"`
+}
+
+// UserProfile is the public view of a user. Recipes and Stats are left out
+// when the user hides them.
+type UserProfile struct {
+	UserID      int           `json:"user_id"`
+	DisplayName string        `json:"display_name"`
+	Bio         *string       `json:"bio,omitempty"`
+	Recipes     []Recipe      `json:"recipes,omitempty"`
+	Stats       *ProfileStats `json:"stats,omitempty"`
+}
+
+type ProfileStats struct {
+	RecipesPublished int      `json:"recipes_published"`
+	AverageRating    *float64 `json:"average_rating,omitempty"`
+	RatingsCount     int      `json:"ratings_count"`
+}
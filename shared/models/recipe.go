@@ -27,25 +27,110 @@ type Recipe struct {
 	Description *string   `json:"description,omitempty"`
 	CategoryID  *int      `json:"category_id,omitempty"`
 	Category    *Category `json:"category,omitempty"`
+	Status      string    `json:"status"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	Difficulty          *string `json:"difficulty,omitempty"`
+	DifficultyEstimated bool    `json:"difficulty_estimated,omitempty"` // true when the service filled in Difficulty
+	TotalTimeMinutes    *int    `json:"total_time_minutes,omitempty"`
+
+	// The cover photo and how many users favorited the recipe, filled in
+	// by the service
+	ImageURL      *string `json:"image_url,omitempty"`
+	ThumbnailURL  *string `json:"thumbnail_url,omitempty"`
+	FavoriteCount *int    `json:"favorite_count,omitempty"`
+
+	// Version counts edits. It is set when a single recipe is fetched or
+	// updated, and sent back with an update to guard against overwriting
+	// someone else's edit.
+	Version int `json:"version,omitempty"`
+
+	// Allergens rolls up the allergens of the recipe's ingredients. Like
+	// Version, it is only set when a single recipe is fetched.
+	Allergens []RecipeAllergen `json:"allergens,omitempty"`
+
+	// PossibleDuplicates warns the author of their recipes this one looks
+	// like a copy of. It is only set when a recipe is created, which it is
+	// either way.
+	PossibleDuplicates []RecipeDuplicate `json:"possible_duplicates,omitempty"`
+}
+
+// RecipeDuplicate is one of the author's recipes a new recipe resembles.
+// Similarity runs from 0 to 1 and weighs the names and, when both recipes
+// have ingredients, how many of them they share.
+type RecipeDuplicate struct {
+	RecipeID          int     `json:"recipe_id"`
+	Name              string  `json:"name"`
+	Status            string  `json:"status"`
+	Similarity        float64 `json:"similarity"`
+	SharedIngredients int     `json:"shared_ingredients"`
+}
+
+// Recipe visibility. Only published recipes appear in public listings and
+// search; drafts and private recipes are visible to their owner through
+// /me/recipes. Archived recipes are left out of listings, search and
+// recommendations too, but anyone can still open them by ID.
+const (
+	RecipeStatusDraft     = "draft"
+	RecipeStatusPrivate   = "private"
+	RecipeStatusPublished = "published"
+	RecipeStatusArchived  = "archived"
+)
+
+// IsValidRecipeStatus reports whether status is one of the recipe statuses.
+func IsValidRecipeStatus(status string) bool {
+	switch status {
+	case RecipeStatusDraft, RecipeStatusPrivate, RecipeStatusPublished, RecipeStatusArchived:
+		return true
+	}
+	return false
+}
+
+// Recipe difficulty levels. Authors may set one explicitly; otherwise the
+// catalogue estimates it when the recipe is returned with its ingredients.
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
+
+// IsValidDifficulty reports whether difficulty is one of the difficulty levels.
+func IsValidDifficulty(difficulty string) bool {
+	switch difficulty {
+	case DifficultyEasy, DifficultyMedium, DifficultyHard:
+		return true
+	}
+	return false
 }
 
 type CreateRecipeRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
 	CategoryID  int    `json:"category_id"`
+	Status      string `json:"status,omitempty"` // defaults to published
+
+	Difficulty       *string `json:"difficulty,omitempty"`
+	TotalTimeMinutes *int    `json:"total_time_minutes,omitempty"`
 }
 
 type UpdateRecipeRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	CategoryID  int    `json:"category_id"`
+	Status      string `json:"status,omitempty"` // unchanged when empty
+
+	Difficulty       *string `json:"difficulty,omitempty"`         // unchanged when nil
+	TotalTimeMinutes *int    `json:"total_time_minutes,omitempty"` // unchanged when nil
+	Version          *int    `json:"version,omitempty"`            // the version edited; any when nil
 }
 
+// RecipeWithIngredients carries the recipe's method in Steps only when a
+// single recipe is fetched; lists leave it out.
 type RecipeWithIngredients struct {
 	Recipe      `json:"recipe"`
 	Ingredients []RecipeIngredient `json:"ingredients"`
+	Steps       []RecipeStep       `json:"steps,omitempty"`
 }
 
 type AddRecipeIngredientRequest struct {
@@ -53,18 +138,52 @@ type AddRecipeIngredientRequest struct {
 	Quantity     float64 `json:"quantity"`
 	Unit         string  `json:"unit"`
 	Notes        *string `json:"notes,omitempty"`
+	ProductID    *int    `json:"product_id,omitempty"` // one of the ingredient's products
+
+	// IngredientName stands in for IngredientID when a recipe is created:
+	// it is matched to an ingredient the catalogue has, or a new one is
+	// added in IngredientCategory.
+	IngredientName     string `json:"ingredient_name,omitempty"`
+	IngredientCategory string `json:"ingredient_category,omitempty"`
 }
 
 type CreateRecipeWithIngredientsRequest struct {
-	Name        string                       `json:"name"`
-	Description *string                      `json:"description,omitempty"`
-	CategoryID  int                          `json:"category_id"`
-	Ingredients []AddRecipeIngredientRequest `json:"ingredients,omitempty"`
+	Name             string                       `json:"name"`
+	Description      *string                      `json:"description,omitempty"`
+	CategoryID       int                          `json:"category_id"`
+	Status           string                       `json:"status,omitempty"` // defaults to published
+	Difficulty       *string                      `json:"difficulty,omitempty"`
+	TotalTimeMinutes *int                         `json:"total_time_minutes,omitempty"`
+	Ingredients      []AddRecipeIngredientRequest `json:"ingredients,omitempty"`
 }
 
 type UpdateRecipeWithIngredientsRequest struct {
-	Name        string                       `json:"name"`
-	Description string                       `json:"description"`
-	CategoryID  int                          `json:"category_id"`
-	Ingredients []AddRecipeIngredientRequest `json:"ingredients"`
+	Name             string                       `json:"name"`
+	Description      string                       `json:"description"`
+	CategoryID       int                          `json:"category_id"`
+	Status           string                       `json:"status,omitempty"`             // unchanged when empty
+	Difficulty       *string                      `json:"difficulty,omitempty"`         // unchanged when nil
+	TotalTimeMinutes *int                         `json:"total_time_minutes,omitempty"` // unchanged when nil
+	Ingredients      []AddRecipeIngredientRequest `json:"ingredients"`
+	Version          *int                         `json:"version,omitempty"` // the version edited; any when nil
+}
+
+// RecipeFacets counts the recipes matching a search, across every page, per
+// value the results can be filtered on.
+type RecipeFacets struct {
+	Categories []CategoryFacet `json:"categories"`
+	MaxTime    []TimeFacet     `json:"max_time"`
+}
+
+type CategoryFacet struct {
+	CategoryID int    `json:"category_id"`
+	Name       string `json:"name"`
+	Count      int    `json:"count"`
+}
+
+// TimeFacet counts the recipes ready in at most MaxMinutes, so the buckets
+// are cumulative. Recipes without a total time are in none of them.
+type TimeFacet struct {
+	MaxMinutes int `json:"max_minutes"`
+	Count      int `json:"count"`
 }
@@ -0,0 +1,69 @@
+package models
+
+// RecipeComparison sets two recipes side by side, such as a recipe and a
+// fork of it, to help choose which to cook. First and second follow the
+// order of ?ids=, and every difference is the second minus the first.
+type RecipeComparison struct {
+	First       ComparedRecipe         `json:"first"`
+	Second      ComparedRecipe         `json:"second"`
+	Ingredients []IngredientComparison `json:"ingredients"` // by name
+	Time        TimeComparison         `json:"time"`
+	Nutrition   NutritionComparison    `json:"nutrition"`
+}
+
+type ComparedRecipe struct {
+	ID               int     `json:"id"`
+	Name             string  `json:"name"`
+	Difficulty       *string `json:"difficulty,omitempty"`
+	TotalTimeMinutes *int    `json:"total_time_minutes,omitempty"`
+	IngredientCount  int     `json:"ingredient_count"`
+	StepCount        int     `json:"step_count"`
+}
+
+// How an ingredient differs between compared recipes
+const (
+	IngredientAdded     = "added"   // only in the second recipe
+	IngredientRemoved   = "removed" // only in the first recipe
+	IngredientChanged   = "changed" // in both, in different amounts
+	IngredientUnchanged = "unchanged"
+)
+
+type IngredientComparison struct {
+	IngredientID int               `json:"ingredient_id"`
+	Name         string            `json:"name"`
+	Change       string            `json:"change"`
+	First        *ComparedQuantity `json:"first,omitempty"`
+	Second       *ComparedQuantity `json:"second,omitempty"`
+
+	// Difference is in the first recipe's unit, set when the second
+	// recipe's amount converts to it
+	Difference *float64 `json:"difference,omitempty"`
+}
+
+type ComparedQuantity struct {
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+}
+
+// TimeComparison leaves the difference out unless both recipes give a total
+// time.
+type TimeComparison struct {
+	FirstMinutes      *int `json:"first_minutes,omitempty"`
+	SecondMinutes     *int `json:"second_minutes,omitempty"`
+	DifferenceMinutes *int `json:"difference_minutes,omitempty"`
+}
+
+type NutritionComparison struct {
+	First      RecipeNutrition  `json:"first"`
+	Second     RecipeNutrition  `json:"second"`
+	Difference ProductNutrition `json:"difference"`
+}
+
+// RecipeNutrition adds up the ingredients made with a product whose label
+// gives its nutrition, in an amount that converts to the label's grams or
+// millilitres. The others are only counted in TotalIngredients.
+type RecipeNutrition struct {
+	Totals             ProductNutrition `json:"totals"`
+	CountedIngredients int              `json:"counted_ingredients"`
+	TotalIngredients   int              `json:"total_ingredients"`
+}
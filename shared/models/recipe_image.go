@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// RecipeImage is a photo of a recipe. Images are returned in Position order;
+// at most one per recipe is the cover. Uploaded images have a thumbnail and
+// a StorageKey locating the original in image storage.
+type RecipeImage struct {
+	ID           int       `json:"id"`
+	RecipeID     int       `json:"recipe_id"`
+	URL          string    `json:"url"`
+	ThumbnailURL *string   `json:"thumbnail_url,omitempty"`
+	Caption      *string   `json:"caption,omitempty"`
+	Position     int       `json:"position"` // 1-based
+	IsCover      bool      `json:"is_cover"`
+	CreatedAt    time.Time `json:"created_at"`
+	StorageKey   *string   `json:"-"`
+}
+
+// AddRecipeImageRequest links a photo hosted elsewhere. Uploads fill in
+// ThumbnailURL and StorageKey as well.
+type AddRecipeImageRequest struct {
+	URL          string  `json:"url"`
+	Caption      *string `json:"caption,omitempty"`
+	ThumbnailURL *string `json:"-"`
+	StorageKey   *string `json:"-"`
+}
+
+// ImageUpload is a photo file uploaded for a recipe.
+type ImageUpload struct {
+	Data    []byte
+	Caption *string
+}
+
+// UpdateRecipeImagesRequest rearranges a recipe's images. Every field is
+// optional: Order must list all of the recipe's image IDs, Captions replaces
+// the captions of the images it names ("" clears one) and CoverImageID
+// picks the cover.
+type UpdateRecipeImagesRequest struct {
+	Order        []int          `json:"order,omitempty"`
+	Captions     map[int]string `json:"captions,omitempty"`
+	CoverImageID *int           `json:"cover_image_id,omitempty"`
+}
+
+// RecipeShareCard is what goes on a recipe's link-preview image: the recipe
+// and its cover photo, if it has any images.
+type RecipeShareCard struct {
+	Recipe     Recipe       `json:"recipe"`
+	CoverImage *RecipeImage `json:"cover_image,omitempty"`
+}
@@ -0,0 +1,17 @@
+package models
+
+type ImportRecipeRequest struct {
+	URL        string `json:"url"`
+	CategoryID *int   `json:"category_id,omitempty"` // defaults to the category the page names, if the catalogue has it
+}
+
+// ImportedRecipe is the draft made from a recipe's web page, for the user to
+// check and publish.
+type ImportedRecipe struct {
+	Draft     RecipeWithIngredients `json:"draft"`
+	SourceURL string                `json:"source_url"`
+	// UnmatchedIngredients are the page's ingredient lines left out of the
+	// draft: none of the catalogue's ingredients matched them, or the draft
+	// already has the one matched
+	UnmatchedIngredients []string `json:"unmatched_ingredients"`
+}
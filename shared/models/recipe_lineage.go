@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type ForkRecipeRequest struct {
+	Name       *string `json:"name,omitempty"`        // defaults to the original's name
+	CategoryID *int    `json:"category_id,omitempty"` // defaults to the original's category
+}
+
+// RecipeLineage is the "adapted from" chain of a recipe, nearest original
+// first.
+type RecipeLineage struct {
+	RecipeID    int                 `json:"recipe_id"`
+	AdaptedFrom []RecipeAttribution `json:"adapted_from"`
+}
+
+// RecipeAttribution credits one recipe in a lineage. Recipes that are no
+// longer public keep their place in the chain but not their name.
+type RecipeAttribution struct {
+	RecipeID int    `json:"recipe_id"`
+	UserID   int    `json:"user_id"`
+	Name     string `json:"name,omitempty"`
+	Public   bool   `json:"public"`
+}
+
+// RecipeFork is a published recipe somewhere below another in the lineage
+// tree. Depth 1 is a direct fork.
+type RecipeFork struct {
+	RecipeID      int       `json:"recipe_id"`
+	AdaptedFromID int       `json:"adapted_from_id"`
+	UserID        int       `json:"user_id"`
+	Name          string    `json:"name"`
+	Depth         int       `json:"depth"`
+	ForkedAt      time.Time `json:"forked_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// RecipeOwnership summarises one user's recipes for the admin ownership
+// report. LastActivityAt is when they last created or edited a recipe.
+type RecipeOwnership struct {
+	UserID         int       `json:"user_id"`
+	DisplayName    *string   `json:"display_name,omitempty"`
+	RecipeCount    int       `json:"recipe_count"`
+	PublishedCount int       `json:"published_count"`
+	DraftCount     int       `json:"draft_count"`
+	PrivateCount   int       `json:"private_count"`
+	ArchivedCount  int       `json:"archived_count"`
+	FirstRecipeAt  time.Time `json:"first_recipe_at"`
+	LastActivityAt time.Time `json:"last_activity_at"`
+}
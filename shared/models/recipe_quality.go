@@ -0,0 +1,44 @@
+package models
+
+// Recipe quality checks, weighted so a recipe passing all of them scores 100.
+const (
+	QualityCheckDescription     = "description"
+	QualityCheckIngredients     = "ingredients"
+	QualityCheckIngredientUnits = "ingredient_units"
+	QualityCheckSteps           = "steps"
+	QualityCheckImage           = "image"
+	QualityCheckTotalTime       = "total_time"
+)
+
+// RecipeQualityFacts is what the quality checks look at besides the recipe's
+// ingredients.
+type RecipeQualityFacts struct {
+	Recipe
+	StepCount  int
+	ImageCount int
+}
+
+type QualityCheck struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+	Passed bool   `json:"passed"`
+	Hint   string `json:"hint,omitempty"` // what to do to pass, when it did not
+}
+
+// RecipeQuality scores how complete a recipe is, out of 100.
+type RecipeQuality struct {
+	RecipeID int            `json:"recipe_id"`
+	UserID   int            `json:"user_id"`
+	Name     string         `json:"name"`
+	Status   string         `json:"status"`
+	Score    int            `json:"score"`
+	Checks   []QualityCheck `json:"checks"`
+}
+
+// RecipeQualityFilter narrows the admin quality report. Zero values match
+// every recipe.
+type RecipeQualityFilter struct {
+	MaxScore *int
+	Failing  string // a quality check name
+	Status   string
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// RecipeTemplate is a recipe admins have picked as a starting point, such as
+// a basic stir fry, for users to copy into a recipe of their own. Its name
+// and category are the recipe's.
+type RecipeTemplate struct {
+	ID          int       `json:"id"`
+	RecipeID    int       `json:"recipe_id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"` // what to make of it, e.g. "swap in any vegetables"
+	CategoryID  *int      `json:"category_id,omitempty"`
+	CreatedBy   int       `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Recipe is set, with its steps, when a single template is fetched
+	Recipe *RecipeWithIngredients `json:"recipe,omitempty"`
+}
+
+type CreateRecipeTemplateRequest struct {
+	RecipeID    int     `json:"recipe_id"`
+	Description *string `json:"description,omitempty"`
+}
+
+type InstantiateTemplateRequest struct {
+	Name       *string `json:"name,omitempty"`        // defaults to the template's name
+	CategoryID *int    `json:"category_id,omitempty"` // defaults to the template's category
+}
@@ -2,12 +2,23 @@ package models
 
 import "time"
 
+// DietaryRestrictions keep recipes a user can't eat out of their
+// recommendations. Diet, when set, is the most restrictive diet every
+// ingredient must suit; Allergens are dietary flags no ingredient may carry,
+// e.g. gluten for a gluten-free user.
+type DietaryRestrictions struct {
+	Diet      string   `json:"diet,omitempty"`
+	Allergens []string `json:"allergens"`
+}
+
 type UserPreferences struct {
-	ID                  int       `json:"id"`
-	UserID              int       `json:"user_id"`
-	PreferredCategories []int     `json:"preferred_categories"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	ID                  int   `json:"id"`
+	UserID              int   `json:"user_id"`
+	PreferredCategories []int `json:"preferred_categories"`
+	DietaryRestrictions
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type CookingHistory struct {
@@ -16,6 +27,36 @@ type CookingHistory struct {
 	RecipeID int       `json:"recipe_id"`
 	CookedAt time.Time `json:"cooked_at"`
 	Rating   *int      `json:"rating,omitempty"`
+
+	Photo *CookingPhoto `json:"photo,omitempty"`
+}
+
+// CookingCalendar is what a user cooked each day of one month, laid out for
+// a calendar heatmap: every day of the month is listed, uncooked ones with a
+// count of 0, along with its ISO week and weekday so clients can place it in
+// the grid without date arithmetic.
+type CookingCalendar struct {
+	Month    string               `json:"month"` // YYYY-MM
+	Total    int                  `json:"total"`
+	MaxCount int                  `json:"max_count"` // the busiest day's count, for scaling the colours
+	Days     []CookingCalendarDay `json:"days"`
+}
+
+type CookingCalendarDay struct {
+	Date    string        `json:"date"`    // YYYY-MM-DD
+	Week    int           `json:"week"`    // ISO week number
+	Weekday int           `json:"weekday"` // 1 for Monday to 7 for Sunday
+	Count   int           `json:"count"`
+	Recipes []CookedOnDay `json:"recipes"` // in the order they were cooked
+}
+
+// CookedOnDay is one entry of the cooking log on a calendar day.
+type CookedOnDay struct {
+	CookingID int       `json:"cooking_id"`
+	RecipeID  int       `json:"recipe_id"`
+	Name      string    `json:"name"`
+	CookedAt  time.Time `json:"cooked_at"`
+	Rating    *int      `json:"rating,omitempty"`
 }
 
 type RecommendationRequest struct {
@@ -32,18 +73,67 @@ type RecipeWithScore struct {
 	Reason              string     `json:"reason"`
 }
 
+// RecipePopularity carries cooking stats for a recipe across all users.
+type RecipePopularity struct {
+	Recipe           `json:"recipe"`
+	TimesCooked      int        `json:"times_cooked"`
+	UniqueCooks      int        `json:"unique_cooks"`
+	CookedLast30Days int        `json:"cooked_last_30_days"`
+	AverageRating    *float64   `json:"average_rating,omitempty"`
+	LastCookedAt     *time.Time `json:"last_cooked_at,omitempty"`
+}
+
 type RecommendationResponse struct {
 	Recipes     []RecipeWithScore `json:"recipes"`
 	Algorithm   string            `json:"algorithm"`
 	GeneratedAt time.Time         `json:"generated_at"`
 	TotalScored int               `json:"total_scored"`
+
+	// FreezerMeals are the user's frozen portions, soonest eat-before
+	// first: meals that need no cooking at all
+	FreezerMeals []FreezerItem `json:"freezer_meals"`
+
+	// SlateID identifies the recipes as served, for reporting which of
+	// them the user saw and clicked; unset when the slate was not saved
+	SlateID int `json:"slate_id,omitempty"`
+}
+
+// SlateImpressionsRequest lists the recipes of a slate the user has seen.
+type SlateImpressionsRequest struct {
+	RecipeIDs []int `json:"recipe_ids"`
+}
+
+// SlateClickRequest names the recipe of a slate the user opened.
+type SlateClickRequest struct {
+	RecipeID int `json:"recipe_id"`
+}
+
+// RecommendationEngagement is how users responded to the slates each
+// algorithm served since From.
+type RecommendationEngagement struct {
+	From       time.Time             `json:"from"`
+	Algorithms []AlgorithmEngagement `json:"algorithms"`
+}
+
+type AlgorithmEngagement struct {
+	Algorithm   string  `json:"algorithm"`
+	Slates      int     `json:"slates"`
+	Served      int     `json:"served"`      // recipes across its slates
+	Impressions int     `json:"impressions"` // of those, seen by the user
+	Clicks      int     `json:"clicks"`
+	CTR         float64 `json:"ctr"` // clicks per impression, 0 without impressions
 }
 
 type UpdatePreferencesRequest struct {
 	PreferredCategories []int `json:"preferred_categories"`
+	DietaryRestrictions
+	Version *int `json:"version,omitempty"` // the version edited; any when nil
 }
 
 type LogCookingRequest struct {
 	RecipeID int  `json:"recipe_id"`
 	Rating   *int `json:"rating,omitempty"`
+
+	// Photo optionally attaches a picture of the finished dish to the entry
+	Photo *CookingPhotoRequest `json:"photo,omitempty"`
 }
@@ -33,8 +33,10 @@ func WriteSuccessResponse(w http.ResponseWriter, data interface{}, code int) {
 }
 
 type PaginatedResponse struct {
-	Data       interface{}    `json:"data"`
-	Pagination PaginationMeta `json:"pagination"`
+	Data        interface{}    `json:"data"`
+	Pagination  PaginationMeta `json:"pagination"`
+	Suggestions []string       `json:"suggestions,omitempty"`
+	Facets      interface{}    `json:"facets,omitempty"`
 }
 
 func WritePaginatedResponse(w http.ResponseWriter, data interface{}, meta PaginationMeta, code int) {
@@ -45,3 +47,15 @@ func WritePaginatedResponse(w http.ResponseWriter, data interface{}, meta Pagina
 		Pagination: meta,
 	})
 }
+
+// WriteSearchResponse is WritePaginatedResponse for searches; suggestions are
+// "did you mean" alternatives offered when nothing matched.
+func WriteSearchResponse(w http.ResponseWriter, data interface{}, meta PaginationMeta, suggestions []string, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(PaginatedResponse{
+		Data:        data,
+		Pagination:  meta,
+		Suggestions: suggestions,
+	})
+}
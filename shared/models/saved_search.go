@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// SavedSearch is a recipe search a user keeps: the ingredients searched for
+// plus optional category and max-time filters. With Notify set, the user is
+// alerted when newly created recipes match it.
+type SavedSearch struct {
+	ID             int       `json:"id"`
+	UserID         int       `json:"user_id"`
+	Name           string    `json:"name"`
+	IngredientIDs  []int     `json:"ingredient_ids"`
+	CategoryID     *int      `json:"category_id,omitempty"`
+	MaxTimeMinutes *int      `json:"max_time_minutes,omitempty"`
+	Notify         bool      `json:"notify"`
+	LastCheckedAt  time.Time `json:"last_checked_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type SavedSearchRequest struct {
+	Name           string `json:"name"`
+	IngredientIDs  []int  `json:"ingredient_ids"`
+	CategoryID     *int   `json:"category_id,omitempty"`
+	MaxTimeMinutes *int   `json:"max_time_minutes,omitempty"`
+	Notify         bool   `json:"notify"`
+}
+
+// SavedSearchAlert tells a user about new recipes matching one of their
+// saved searches, ready to hand to a notifier.
+type SavedSearchAlert struct {
+	UserID        int      `json:"user_id"`
+	SavedSearchID int      `json:"saved_search_id"`
+	SearchName    string   `json:"search_name"`
+	Recipes       []Recipe `json:"recipes"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Follow is one edge of the follow graph as seen from the listing user: the
+// other user and when the follow started.
+type Follow struct {
+	UserID     int       `json:"user_id"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// Activity is an entry in the activity feed: something a user did in the
+// catalogue. Type is the domain event it was recorded from.
+type Activity struct {
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	Type       string    `json:"type"`
+	RecipeID   int       `json:"recipe_id"`
+	RecipeName string    `json:"recipe_name"`
+	CreatedAt  time.Time `json:"created_at"`
+}
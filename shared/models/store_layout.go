@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// StoreLayout orders ingredient categories the way a shop's aisles are laid
+// out. Grocery lists generated against a layout follow that order.
+type StoreLayout struct {
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	Name       string    `json:"name"`
+	Categories []string  `json:"categories"` // first aisle first
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type StoreLayoutRequest struct {
+	Name       string   `json:"name"`
+	Categories []string `json:"categories"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// UsageWindow is a user's standing in one fixed rate-limit window, such as
+// the current minute.
+type UsageWindow struct {
+	Used      int       `json:"used"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+// QuotaUsage is how much of one gateway quota a user has used. Quotas follow
+// the gateway routes: "recipes" for the recipe catalogue and
+// "recommendations" for the recommendations service.
+type QuotaUsage struct {
+	Quota         string      `json:"quota"`
+	Minute        UsageWindow `json:"minute"`
+	Hour          UsageWindow `json:"hour"`
+	RequestsToday int         `json:"requests_today"` // since midnight UTC
+}
+
+type APIUsage struct {
+	UserID int          `json:"user_id"`
+	Quotas []QuotaUsage `json:"quotas"`
+}
+
+// UsageWarning is sent when a user's requests in a window reach the warning
+// threshold, so they can slow down before the gateway starts answering 429.
+type UsageWarning struct {
+	UserID int         `json:"user_id"`
+	Quota  string      `json:"quota"`
+	Window string      `json:"window"` // minute or hour
+	Usage  UsageWindow `json:"usage"`
+}
@@ -6,10 +6,19 @@ type User struct {
 	ID           int       `json:"id"`
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"` // Never expose in JSON
+	Role         string    `json:"role,omitempty"`
+	Region       string    `json:"region,omitempty"` // Data residency region, empty for the home database
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// User roles. Admins may manage shared reference data such as ingredient
+// densities; everyone else is a regular user.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
@@ -18,9 +27,32 @@ type LoginRequest struct {
 type RegisterRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Region   string `json:"region,omitempty"`
 }
 
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string `json:"token"`
+	ExpiresIn    int    `json:"expires_in,omitempty"` // seconds until Token expires
+	RefreshToken string `json:"refresh_token,omitempty"`
+	User         User   `json:"user"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	All          bool   `json:"all"` // revoke every refresh token of the user, signing out all devices
+}
+
+// RefreshToken is a stored refresh token. The token itself is only ever
+// handed to the client; the server keeps its hash.
+type RefreshToken struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
 }
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// VersionConflictError is returned by an update made against a version that
+// is no longer current, so it would overwrite someone else's changes.
+type VersionConflictError struct {
+	CurrentVersion int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict: current version is %d", e.CurrentVersion)
+}
+
+type VersionConflictResponse struct {
+	ErrorResponse
+	CurrentVersion int `json:"current_version"`
+}
+
+// WriteVersionConflictResponse answers 409 with the current version, which
+// the client fetches and merges into before retrying.
+func WriteVersionConflictResponse(w http.ResponseWriter, currentVersion int) {
+	SetETag(w, currentVersion)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(VersionConflictResponse{
+		ErrorResponse: ErrorResponse{
+			Error:   "error",
+			Code:    http.StatusConflict,
+			Message: "Modified by someone else since it was fetched",
+		},
+		CurrentVersion: currentVersion,
+	})
+}
+
+// SetETag sends version as a strong entity tag, for clients to send back
+// in If-Match.
+func SetETag(w http.ResponseWriter, version int) {
+	if version > 0 {
+		w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+	}
+}
+
+// IfMatchVersion reads the version an update is conditional on from the
+// If-Match header. It is nil when the header is absent or "*", meaning any
+// version, and ok is false when the header is not a version tag.
+func IfMatchVersion(r *http.Request) (version *int, ok bool) {
+	tag := strings.TrimSpace(r.Header.Get("If-Match"))
+	if tag == "" || tag == "*" {
+		return nil, true
+	}
+
+	tag = strings.Trim(strings.TrimPrefix(tag, "W/"), `"`)
+	n, err := strconv.Atoi(tag)
+	if err != nil || n < 1 {
+		return nil, false
+	}
+	return &n, true
+}
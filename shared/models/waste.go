@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Why food ended up in the bin
+const (
+	WasteReasonExpired    = "expired"
+	WasteReasonSpoiled    = "spoiled"
+	WasteReasonLeftover   = "leftover"
+	WasteReasonOverbought = "overbought"
+	WasteReasonOther      = "other"
+)
+
+// WasteEntry is one thing a user threw away.
+type WasteEntry struct {
+	ID             int       `json:"id"`
+	UserID         int       `json:"user_id"`
+	IngredientID   int       `json:"ingredient_id"`
+	IngredientName string    `json:"ingredient_name"`
+	Quantity       float64   `json:"quantity"`
+	Unit           string    `json:"unit"`
+	Reason         string    `json:"reason"`
+	WastedAt       time.Time `json:"wasted_at"`
+}
+
+type LogWasteRequest struct {
+	IngredientID int        `json:"ingredient_id"`
+	Quantity     float64    `json:"quantity"`
+	Unit         string     `json:"unit"`
+	Reason       string     `json:"reason"`
+	WastedAt     *time.Time `json:"wasted_at,omitempty"` // defaults to now
+}
+
+// WasteSummary totals a user's waste over one calendar month.
+type WasteSummary struct {
+	Month       string                  `json:"month"` // YYYY-MM
+	Entries     int                     `json:"entries"`
+	ByReason    map[string]int          `json:"by_reason"`
+	Ingredients []WastedIngredientTotal `json:"ingredients"`
+}
+
+// WastedIngredientTotal adds up the waste of one ingredient in one unit;
+// quantities in different units are listed separately.
+type WastedIngredientTotal struct {
+	IngredientID   int     `json:"ingredient_id"`
+	IngredientName string  `json:"ingredient_name"`
+	Unit           string  `json:"unit"`
+	Quantity       float64 `json:"quantity"`
+	Times          int     `json:"times"`
+}
@@ -0,0 +1,184 @@
+// Package mtls configures optional mutual TLS for service-to-service traffic.
+//
+// It is driven entirely by environment variables so every service opts in the
+// same way:
+//
+//	MTLS_CERT_FILE      PEM certificate presented by this service
+//	MTLS_KEY_FILE       PEM private key for MTLS_CERT_FILE
+//	MTLS_CA_FILE        PEM bundle of the internal CA used to verify peers
+//	MTLS_ALLOWED_PEERS  comma-separated DNS or URI SANs peers must carry
+//
+// When MTLS_CERT_FILE is unset mTLS is disabled and services keep serving
+// plain HTTP, which is what local development and docker-compose use.
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/tracing"
+)
+
+var ErrNoAllowedPeer = errors.New("peer certificate does not carry an allowed SAN")
+
+type Config struct {
+	CertFile     string
+	KeyFile      string
+	CAFile       string
+	AllowedPeers []string
+}
+
+// LoadConfig reads the mTLS settings from the environment. It returns nil
+// when mTLS is not enabled.
+func LoadConfig() (*Config, error) {
+	cfg := &Config{
+		CertFile: os.Getenv("MTLS_CERT_FILE"),
+		KeyFile:  os.Getenv("MTLS_KEY_FILE"),
+		CAFile:   os.Getenv("MTLS_CA_FILE"),
+	}
+	if cfg.CertFile == "" {
+		return nil, nil
+	}
+
+	if cfg.KeyFile == "" || cfg.CAFile == "" {
+		return nil, fmt.Errorf("mtls: MTLS_KEY_FILE and MTLS_CA_FILE are required when MTLS_CERT_FILE is set")
+	}
+
+	for _, peer := range strings.Split(os.Getenv("MTLS_ALLOWED_PEERS"), ",") {
+		if peer = strings.TrimSpace(peer); peer != "" {
+			cfg.AllowedPeers = append(cfg.AllowedPeers, peer)
+		}
+	}
+
+	return cfg, nil
+}
+
+// ServerTLSConfig requires every client to present a certificate signed by
+// the internal CA whose SANs include one of the allowed peers.
+func (c *Config) ServerTLSConfig() (*tls.Config, error) {
+	cert, pool, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:       tls.VersionTLS12,
+		Certificates:     []tls.Certificate{cert},
+		ClientCAs:        pool,
+		ClientAuth:       tls.RequireAndVerifyClientCert,
+		VerifyConnection: c.verifyPeer,
+	}, nil
+}
+
+// ClientTLSConfig presents this service's certificate and only trusts
+// servers signed by the internal CA that carry an allowed SAN.
+func (c *Config) ClientTLSConfig() (*tls.Config, error) {
+	cert, pool, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:       tls.VersionTLS12,
+		Certificates:     []tls.Certificate{cert},
+		RootCAs:          pool,
+		VerifyConnection: c.verifyPeer,
+	}, nil
+}
+
+// HTTPClient returns a client for calling other internal services over mTLS.
+// Requests carry the trace context and request ID of their request context.
+func (c *Config) HTTPClient(timeout time.Duration) (*http.Client, error) {
+	tlsConfig, err := c.ClientTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: tracing.Transport(logging.Transport(transport)), Timeout: timeout}, nil
+}
+
+func (c *Config) load() (tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("mtls: failed to load key pair: %w", err)
+	}
+
+	caPEM, err := os.ReadFile(c.CAFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("mtls: failed to read CA bundle: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return tls.Certificate{}, nil, fmt.Errorf("mtls: no certificates found in %s", c.CAFile)
+	}
+
+	return cert, pool, nil
+}
+
+// verifyPeer runs after the chain has been verified against the internal CA
+// and additionally pins the peer identity to the allowed SANs. With no
+// allowlist configured any certificate from the CA is accepted.
+func (c *Config) verifyPeer(cs tls.ConnectionState) error {
+	if len(c.AllowedPeers) == 0 {
+		return nil
+	}
+	if len(cs.PeerCertificates) == 0 {
+		return ErrNoAllowedPeer
+	}
+
+	if peerAllowed(cs.PeerCertificates[0], c.AllowedPeers) {
+		return nil
+	}
+	return ErrNoAllowedPeer
+}
+
+func peerAllowed(cert *x509.Certificate, allowed []string) bool {
+	for _, want := range allowed {
+		for _, name := range cert.DNSNames {
+			if strings.EqualFold(name, want) {
+				return true
+			}
+		}
+		for _, uri := range cert.URIs {
+			if uri.String() == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ServerTLSConfigFromEnv returns the server TLS config for the mTLS settings
+// in the environment, or nil when mTLS is not enabled and the service should
+// serve plain HTTP.
+func ServerTLSConfigFromEnv() (*tls.Config, error) {
+	cfg, err := LoadConfig()
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+	return cfg.ServerTLSConfig()
+}
+
+// HTTPClientFromEnv returns a client for calling other internal services,
+// over mTLS when it is configured and plain HTTP otherwise.
+func HTTPClientFromEnv(timeout time.Duration) (*http.Client, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return &http.Client{Transport: tracing.Transport(logging.Transport(nil)), Timeout: timeout}, nil
+	}
+	return cfg.HTTPClient(timeout)
+}
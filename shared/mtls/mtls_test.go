@@ -0,0 +1,51 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyPeer_MatchesDNSAndURISANs(t *testing.T) {
+	spiffe, _ := url.Parse("spiffe://mealprep/recommendations")
+	cert := &x509.Certificate{
+		DNSNames: []string{"Recipe-Catalogue"},
+		URIs:     []*url.URL{spiffe},
+	}
+	state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+
+	assert.NoError(t, (&Config{AllowedPeers: []string{"recipe-catalogue"}}).verifyPeer(state))
+	assert.NoError(t, (&Config{AllowedPeers: []string{"spiffe://mealprep/recommendations"}}).verifyPeer(state))
+	assert.ErrorIs(t, (&Config{AllowedPeers: []string{"auth"}}).verifyPeer(state), ErrNoAllowedPeer)
+}
+
+func TestVerifyPeer_NoAllowlistAcceptsAnyCASignedPeer(t *testing.T) {
+	assert.NoError(t, (&Config{}).verifyPeer(tls.ConnectionState{}))
+}
+
+func TestVerifyPeer_RejectsMissingCertificate(t *testing.T) {
+	cfg := &Config{AllowedPeers: []string{"gateway"}}
+
+	assert.ErrorIs(t, cfg.verifyPeer(tls.ConnectionState{}), ErrNoAllowedPeer)
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("MTLS_CERT_FILE", "")
+	cfg, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	t.Setenv("MTLS_CERT_FILE", "/certs/svc.pem")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+
+	t.Setenv("MTLS_KEY_FILE", "/certs/svc-key.pem")
+	t.Setenv("MTLS_CA_FILE", "/certs/ca.pem")
+	t.Setenv("MTLS_ALLOWED_PEERS", "kong, recommendations ,")
+	cfg, err = LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kong", "recommendations"}, cfg.AllowedPeers)
+}
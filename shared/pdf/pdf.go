@@ -0,0 +1,213 @@
+// Package pdf writes simple PDF documents: A4 pages of text and outlined
+// boxes in the standard Helvetica fonts. It covers printable output such as
+// labels and recipe sheets without pulling in a layout library.
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Page size and unit helpers. Coordinates are in points (1/72 inch)
+// measured from the top-left corner of the page.
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+
+	// MM is one millimetre in points
+	MM = 72 / 25.4
+)
+
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+// Document is a PDF being built page by page.
+type Document struct {
+	pages []*Page
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage appends an empty A4 portrait page.
+func (d *Document) AddPage() *Page {
+	page := &Page{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Page collects the drawing operators of one page.
+type Page struct {
+	content bytes.Buffer
+}
+
+// Text draws s with its baseline at y.
+func (p *Page) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F%d %.2f Tf %.2f %.2f Td (", int(font)+1, size, x, A4Height-y)
+	p.content.Write(escape(encodeWinAnsi(s)))
+	p.content.WriteString(") Tj ET\n")
+}
+
+// Rect outlines a box whose top-left corner is at x, y.
+func (p *Page) Rect(x, y, width, height float64) {
+	fmt.Fprintf(&p.content, "0.5 w 0.6 G %.2f %.2f %.2f %.2f re S\n", x, A4Height-y-height, width, height)
+}
+
+// WriteTo writes the document. A document without pages gets one blank
+// page, as PDF readers expect at least one.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = []*Page{{}}
+	}
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	var offsets []int64
+	object := func(body string) {
+		offsets = append(offsets, cw.n)
+		fmt.Fprintf(cw, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	cw.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-4 are fixed; each page then takes a page and a content object
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", A4Width, A4Height, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xref := cw.n
+	fmt.Fprintf(cw, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(cw, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(cw, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, cw.w.Flush()
+}
+
+// TextWidth estimates how wide s is in the regular font; bold text runs
+// about 5% wider.
+func TextWidth(s string, size float64) float64 {
+	units := 0
+	for _, r := range s {
+		if r >= ' ' && r <= '~' {
+			units += helveticaWidths[r-' ']
+		} else {
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// Wrap breaks s into lines no wider than width, splitting at spaces. A
+// single word wider than width gets a line of its own.
+func Wrap(s string, size, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && TextWidth(candidate, size) > width {
+			lines = append(lines, line)
+			candidate = word
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// helveticaWidths are the Helvetica advance widths of ' ' through '~' in
+// thousandths of the font size.
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// winAnsiExtras maps the characters WinAnsiEncoding places in 0x80-0x9F.
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// encodeWinAnsi converts s to the encoding of the standard fonts. Latin-1
+// maps directly; anything else the fonts cannot show becomes '?'.
+func encodeWinAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		case winAnsiExtras[r] != 0:
+			out = append(out, winAnsiExtras[r])
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// escape protects the characters that end or escape a PDF string literal.
+func escape(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for _, c := range b {
+		switch c {
+		case '\\', '(', ')':
+			out = append(out, '\\', c)
+		case '\n', '\r':
+			out = append(out, ' ')
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+func (c *countingWriter) WriteString(s string) {
+	c.Write([]byte(s))
+}
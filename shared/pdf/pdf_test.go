@@ -0,0 +1,61 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTo_ProducesValidStructure(t *testing.T) {
+	doc := New()
+	doc.AddPage().Text(20, 40, Bold, 12, "Chili (1/6)")
+	doc.AddPage().Rect(10, 10, 100, 50)
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	out := buf.String()
+	assert.Regexp(t, `^%PDF-1\.4\n`, out)
+	assert.Contains(t, out, "/Count 2")
+	assert.Contains(t, out, "(Chili \\(1/6\\)) Tj")
+	assert.Regexp(t, `%%EOF\n$`, out)
+
+	// Every xref entry must point at the object it names
+	xref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(out)
+	require.Len(t, xref, 2)
+	start, _ := strconv.Atoi(xref[1])
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(out[start:], -1)
+	require.Len(t, entries, 8)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		assert.True(t, bytes.HasPrefix(buf.Bytes()[offset:], []byte(strconv.Itoa(i+1)+" 0 obj")), "object %d", i+1)
+	}
+}
+
+func TestWriteTo_EmptyDocumentHasOnePage(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := New().WriteTo(&buf)
+
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "/Count 1")
+}
+
+func TestEncodeWinAnsi(t *testing.T) {
+	assert.Equal(t, []byte{'C', 'a', 'f', 0xE9, ' ', 0x96, ' ', '?'}, encodeWinAnsi("Café – 好"))
+}
+
+func TestWrap(t *testing.T) {
+	lines := Wrap("Reheat covered in the microwave for three minutes, stirring halfway", 10, 150)
+
+	require.Greater(t, len(lines), 1)
+	for _, line := range lines {
+		assert.LessOrEqual(t, TextWidth(line, 10), 150.0)
+	}
+	assert.Empty(t, Wrap("   ", 10, 150))
+}
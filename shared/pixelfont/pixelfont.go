@@ -0,0 +1,190 @@
+// Package pixelfont draws text onto images with a built-in 5×7 bitmap font.
+// It is meant for generated graphics such as share cards, where a blocky
+// look is fine and shipping a font renderer is not worth it.
+package pixelfont
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+// Glyph size in font pixels. Each character advances one column more than
+// its width; every font pixel is drawn as a scale×scale square.
+const (
+	GlyphWidth  = 5
+	GlyphHeight = 7
+	advance     = GlyphWidth + 1
+)
+
+// Width is how many image pixels s takes at the given scale.
+func Width(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*advance - 1) * scale
+}
+
+// Height is the height of one line of text at the given scale.
+func Height(scale int) int {
+	return GlyphHeight * scale
+}
+
+// Draw writes s with its top-left corner at x, y.
+func Draw(dst draw.Image, x, y, scale int, c color.Color, s string) {
+	src := image.NewUniform(c)
+	for _, r := range s {
+		rows := glyph(r)
+		for row, bits := range rows {
+			for col := 0; col < GlyphWidth; col++ {
+				if bits&(1<<(GlyphWidth-1-col)) == 0 {
+					continue
+				}
+				px := image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale)
+				draw.Draw(dst, px, src, image.Point{}, draw.Over)
+			}
+		}
+		x += advance * scale
+	}
+}
+
+// Wrap breaks s into lines no wider than width, splitting at spaces. A
+// single word wider than width gets a line of its own.
+func Wrap(s string, scale, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && Width(candidate, scale) > width {
+			lines = append(lines, line)
+			candidate = word
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// glyph returns the rows of r, top to bottom, with the leftmost pixel in
+// bit 4. Accented Latin letters are drawn without their accent and anything
+// else outside ASCII as '?'.
+func glyph(r rune) [GlyphHeight]uint8 {
+	if i := strings.IndexRune(accented, r); i >= 0 {
+		r = []rune(unaccented)[len([]rune(accented[:i]))]
+	}
+	if r < ' ' || r > '~' {
+		r = '?'
+	}
+	return glyphs[r-' ']
+}
+
+const (
+	accented   = "ÀÁÂÃÄÅàáâãäåÇçÈÉÊËèéêëÌÍÎÏìíîïÑñÒÓÔÕÖØòóôõöøÙÚÛÜùúûüÝýÿŠšŽž"
+	unaccented = "AAAAAAaaaaaaCcEEEEeeeeIIIIiiiiNnOOOOOOooooooUUUUuuuuYyySsZz"
+)
+
+// glyphs holds ' ' through '~'.
+var glyphs = [...][GlyphHeight]uint8{
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // ' '
+	{0x04, 0x04, 0x04, 0x04, 0x00, 0x00, 0x04}, // '!'
+	{0x0A, 0x0A, 0x0A, 0x00, 0x00, 0x00, 0x00}, // '"'
+	{0x0A, 0x0A, 0x1F, 0x0A, 0x1F, 0x0A, 0x0A}, // '#'
+	{0x04, 0x0F, 0x14, 0x0E, 0x05, 0x1E, 0x04}, // '$'
+	{0x18, 0x19, 0x02, 0x04, 0x08, 0x13, 0x03}, // '%'
+	{0x0C, 0x12, 0x14, 0x08, 0x15, 0x12, 0x0D}, // '&'
+	{0x0C, 0x04, 0x08, 0x00, 0x00, 0x00, 0x00}, // '\''
+	{0x02, 0x04, 0x08, 0x08, 0x08, 0x04, 0x02}, // '('
+	{0x08, 0x04, 0x02, 0x02, 0x02, 0x04, 0x08}, // ')'
+	{0x00, 0x04, 0x15, 0x0E, 0x15, 0x04, 0x00}, // '*'
+	{0x00, 0x04, 0x04, 0x1F, 0x04, 0x04, 0x00}, // '+'
+	{0x00, 0x00, 0x00, 0x00, 0x0C, 0x04, 0x08}, // ','
+	{0x00, 0x00, 0x00, 0x1F, 0x00, 0x00, 0x00}, // '-'
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x0C, 0x0C}, // '.'
+	{0x00, 0x01, 0x02, 0x04, 0x08, 0x10, 0x00}, // '/'
+	{0x0E, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0E}, // '0'
+	{0x04, 0x0C, 0x04, 0x04, 0x04, 0x04, 0x0E}, // '1'
+	{0x0E, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1F}, // '2'
+	{0x1F, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0E}, // '3'
+	{0x02, 0x06, 0x0A, 0x12, 0x1F, 0x02, 0x02}, // '4'
+	{0x1F, 0x10, 0x1E, 0x01, 0x01, 0x11, 0x0E}, // '5'
+	{0x06, 0x08, 0x10, 0x1E, 0x11, 0x11, 0x0E}, // '6'
+	{0x1F, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08}, // '7'
+	{0x0E, 0x11, 0x11, 0x0E, 0x11, 0x11, 0x0E}, // '8'
+	{0x0E, 0x11, 0x11, 0x0F, 0x01, 0x02, 0x0C}, // '9'
+	{0x00, 0x0C, 0x0C, 0x00, 0x0C, 0x0C, 0x00}, // ':'
+	{0x00, 0x0C, 0x0C, 0x00, 0x0C, 0x04, 0x08}, // ';'
+	{0x02, 0x04, 0x08, 0x10, 0x08, 0x04, 0x02}, // '<'
+	{0x00, 0x00, 0x1F, 0x00, 0x1F, 0x00, 0x00}, // '='
+	{0x08, 0x04, 0x02, 0x01, 0x02, 0x04, 0x08}, // '>'
+	{0x0E, 0x11, 0x01, 0x02, 0x04, 0x00, 0x04}, // '?'
+	{0x0E, 0x11, 0x01, 0x0D, 0x15, 0x15, 0x0E}, // '@'
+	{0x0E, 0x11, 0x11, 0x11, 0x1F, 0x11, 0x11}, // 'A'
+	{0x1E, 0x11, 0x11, 0x1E, 0x11, 0x11, 0x1E}, // 'B'
+	{0x0E, 0x11, 0x10, 0x10, 0x10, 0x11, 0x0E}, // 'C'
+	{0x1C, 0x12, 0x11, 0x11, 0x11, 0x12, 0x1C}, // 'D'
+	{0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x1F}, // 'E'
+	{0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x10}, // 'F'
+	{0x0E, 0x11, 0x10, 0x17, 0x11, 0x11, 0x0F}, // 'G'
+	{0x11, 0x11, 0x11, 0x1F, 0x11, 0x11, 0x11}, // 'H'
+	{0x0E, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0E}, // 'I'
+	{0x07, 0x02, 0x02, 0x02, 0x02, 0x12, 0x0C}, // 'J'
+	{0x11, 0x12, 0x14, 0x18, 0x14, 0x12, 0x11}, // 'K'
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x1F}, // 'L'
+	{0x11, 0x1B, 0x15, 0x15, 0x11, 0x11, 0x11}, // 'M'
+	{0x11, 0x11, 0x19, 0x15, 0x13, 0x11, 0x11}, // 'N'
+	{0x0E, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E}, // 'O'
+	{0x1E, 0x11, 0x11, 0x1E, 0x10, 0x10, 0x10}, // 'P'
+	{0x0E, 0x11, 0x11, 0x11, 0x15, 0x12, 0x0D}, // 'Q'
+	{0x1E, 0x11, 0x11, 0x1E, 0x14, 0x12, 0x11}, // 'R'
+	{0x0F, 0x10, 0x10, 0x0E, 0x01, 0x01, 0x1E}, // 'S'
+	{0x1F, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04}, // 'T'
+	{0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E}, // 'U'
+	{0x11, 0x11, 0x11, 0x11, 0x11, 0x0A, 0x04}, // 'V'
+	{0x11, 0x11, 0x11, 0x15, 0x15, 0x15, 0x0A}, // 'W'
+	{0x11, 0x11, 0x0A, 0x04, 0x0A, 0x11, 0x11}, // 'X'
+	{0x11, 0x11, 0x11, 0x0A, 0x04, 0x04, 0x04}, // 'Y'
+	{0x1F, 0x01, 0x02, 0x04, 0x08, 0x10, 0x1F}, // 'Z'
+	{0x0E, 0x08, 0x08, 0x08, 0x08, 0x08, 0x0E}, // '['
+	{0x00, 0x10, 0x08, 0x04, 0x02, 0x01, 0x00}, // '\\'
+	{0x0E, 0x02, 0x02, 0x02, 0x02, 0x02, 0x0E}, // ']'
+	{0x04, 0x0A, 0x11, 0x00, 0x00, 0x00, 0x00}, // '^'
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x1F}, // '_'
+	{0x08, 0x04, 0x02, 0x00, 0x00, 0x00, 0x00}, // '`'
+	{0x00, 0x00, 0x0E, 0x01, 0x0F, 0x11, 0x0F}, // 'a'
+	{0x10, 0x10, 0x16, 0x19, 0x11, 0x11, 0x1E}, // 'b'
+	{0x00, 0x00, 0x0E, 0x10, 0x10, 0x11, 0x0E}, // 'c'
+	{0x01, 0x01, 0x0D, 0x13, 0x11, 0x11, 0x0F}, // 'd'
+	{0x00, 0x00, 0x0E, 0x11, 0x1F, 0x10, 0x0E}, // 'e'
+	{0x06, 0x09, 0x08, 0x1C, 0x08, 0x08, 0x08}, // 'f'
+	{0x00, 0x0F, 0x11, 0x11, 0x0F, 0x01, 0x0E}, // 'g'
+	{0x10, 0x10, 0x16, 0x19, 0x11, 0x11, 0x11}, // 'h'
+	{0x04, 0x00, 0x0C, 0x04, 0x04, 0x04, 0x0E}, // 'i'
+	{0x02, 0x00, 0x06, 0x02, 0x02, 0x12, 0x0C}, // 'j'
+	{0x10, 0x10, 0x12, 0x14, 0x18, 0x14, 0x12}, // 'k'
+	{0x0C, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0E}, // 'l'
+	{0x00, 0x00, 0x1A, 0x15, 0x15, 0x11, 0x11}, // 'm'
+	{0x00, 0x00, 0x16, 0x19, 0x11, 0x11, 0x11}, // 'n'
+	{0x00, 0x00, 0x0E, 0x11, 0x11, 0x11, 0x0E}, // 'o'
+	{0x00, 0x1E, 0x11, 0x11, 0x1E, 0x10, 0x10}, // 'p'
+	{0x00, 0x0F, 0x11, 0x11, 0x0F, 0x01, 0x01}, // 'q'
+	{0x00, 0x00, 0x16, 0x19, 0x10, 0x10, 0x10}, // 'r'
+	{0x00, 0x00, 0x0E, 0x10, 0x0E, 0x01, 0x1E}, // 's'
+	{0x08, 0x08, 0x1C, 0x08, 0x08, 0x09, 0x06}, // 't'
+	{0x00, 0x00, 0x11, 0x11, 0x11, 0x13, 0x0D}, // 'u'
+	{0x00, 0x00, 0x11, 0x11, 0x11, 0x0A, 0x04}, // 'v'
+	{0x00, 0x00, 0x11, 0x11, 0x15, 0x15, 0x0A}, // 'w'
+	{0x00, 0x00, 0x11, 0x0A, 0x04, 0x0A, 0x11}, // 'x'
+	{0x00, 0x00, 0x11, 0x11, 0x0F, 0x01, 0x0E}, // 'y'
+	{0x00, 0x00, 0x1F, 0x02, 0x04, 0x08, 0x1F}, // 'z'
+	{0x02, 0x04, 0x04, 0x08, 0x04, 0x04, 0x02}, // '{'
+	{0x04, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04}, // '|'
+	{0x08, 0x04, 0x04, 0x02, 0x04, 0x04, 0x08}, // '}'
+	{0x00, 0x00, 0x08, 0x15, 0x02, 0x00, 0x00}, // '~'
+}
@@ -0,0 +1,41 @@
+package pixelfont
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlyphsCoverPrintableASCII(t *testing.T) {
+	assert.Len(t, glyphs, '~'-' '+1)
+	assert.Equal(t, len([]rune(accented)), len([]rune(unaccented)))
+}
+
+func TestDraw_ScalesGlyphPixels(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 30))
+	Draw(img, 0, 0, 2, color.Black, "L")
+
+	// 'L' is a left column with a bottom row
+	assert.Equal(t, uint8(0xFF), img.RGBAAt(1, 1).A)
+	assert.Equal(t, uint8(0xFF), img.RGBAAt(9, 13).A)
+	assert.Equal(t, uint8(0), img.RGBAAt(9, 1).A)
+}
+
+func TestGlyph_FallsBack(t *testing.T) {
+	assert.Equal(t, glyphs['e'-' '], glyph('é'))
+	assert.Equal(t, glyphs['?'-' '], glyph('好'))
+}
+
+func TestWrap(t *testing.T) {
+	lines := Wrap("Slow cooked smoky black bean chili", 3, 200)
+
+	require.Greater(t, len(lines), 1)
+	for _, line := range lines {
+		assert.LessOrEqual(t, Width(line, 3), 200)
+	}
+	assert.Equal(t, 0, Width("", 3))
+	assert.Equal(t, 3*(2*6-1), Width("ab", 3))
+}
@@ -0,0 +1,133 @@
+// Package policy makes the authorization decisions the services share. A
+// service describes the resource it is about to touch and asks Authorize
+// whether the subject in the request context may act on it, instead of
+// comparing owner IDs itself. The rules, in order:
+//
+//   - anyone, signed in or not, may read a public resource
+//   - the owner may do anything
+//   - a role may do what roleGrants lists, e.g. admins read and delete for
+//     moderation but never edit other users' content
+//   - a token scope "<type>:<action>" (e.g. "recipe:read") grants that action
+//     on every resource of the type
+//   - members of the household a resource is shared with may read and
+//     update it; deleting stays with the owner
+package policy
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"meal-prep/shared/models"
+)
+
+type Action string
+
+const (
+	Read   Action = "read"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// ErrDenied is returned when the subject may not perform the action. Services
+// turn it into their own forbidden or not-found error.
+var ErrDenied = errors.New("not authorized")
+
+// Subject is who is asking.
+type Subject struct {
+	UserID     int
+	Role       string
+	Scopes     []string
+	Households []int // households the user belongs to
+}
+
+// Resource is what the rules need to know about the thing being accessed.
+type Resource struct {
+	Type        string // names the resource in scopes, e.g. "recipe"
+	OwnerID     int
+	Public      bool
+	HouseholdID int // 0 when not shared with a household
+}
+
+// roleGrants lists what a role may do to resources it does not own.
+var roleGrants = map[string][]Action{
+	models.RoleAdmin: {Read, Delete},
+}
+
+// householdGrants is what household members may do to a shared resource.
+var householdGrants = []Action{Read, Update}
+
+type subjectKey struct{}
+
+// WithSubject returns a copy of ctx carrying subject. The user context
+// middleware calls it for every request with a token.
+func WithSubject(ctx context.Context, subject Subject) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+func SubjectFrom(ctx context.Context) (Subject, bool) {
+	subject, ok := ctx.Value(subjectKey{}).(Subject)
+	return subject, ok
+}
+
+// ParseScopes splits a space-separated OAuth scope claim.
+func ParseScopes(scope string) []string {
+	return strings.Fields(scope)
+}
+
+// Authorize returns nil when the subject in ctx may perform action on
+// resource, and ErrDenied otherwise. A context without a subject is
+// anonymous.
+func Authorize(ctx context.Context, action Action, resource Resource) error {
+	if action == Read && resource.Public {
+		return nil
+	}
+
+	subject, ok := SubjectFrom(ctx)
+	if !ok || subject.UserID <= 0 {
+		return ErrDenied
+	}
+
+	switch {
+	case subject.UserID == resource.OwnerID:
+		return nil
+	case allows(roleGrants[subject.Role], action):
+		return nil
+	case hasScope(subject.Scopes, resource.Type, action):
+		return nil
+	case resource.HouseholdID != 0 && memberOf(subject.Households, resource.HouseholdID) && allows(householdGrants, action):
+		return nil
+	}
+	return ErrDenied
+}
+
+func allows(granted []Action, action Action) bool {
+	for _, grant := range granted {
+		if grant == action {
+			return true
+		}
+	}
+	return false
+}
+
+func hasScope(scopes []string, resourceType string, action Action) bool {
+	if resourceType == "" {
+		return false
+	}
+	want := resourceType + ":" + string(action)
+	for _, scope := range scopes {
+		if scope == want {
+			return true
+		}
+	}
+	return false
+}
+
+func memberOf(households []int, householdID int) bool {
+	for _, id := range households {
+		if id == householdID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"meal-prep/shared/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func asUser(subject Subject) context.Context {
+	return WithSubject(context.Background(), subject)
+}
+
+func TestAuthorize_Owner(t *testing.T) {
+	ctx := asUser(Subject{UserID: 7})
+	recipe := Resource{Type: "recipe", OwnerID: 7}
+
+	assert.NoError(t, Authorize(ctx, Update, recipe))
+	assert.NoError(t, Authorize(ctx, Delete, recipe))
+	assert.Equal(t, ErrDenied, Authorize(asUser(Subject{UserID: 8}), Update, recipe))
+}
+
+func TestAuthorize_PublicIsReadOnly(t *testing.T) {
+	recipe := Resource{Type: "recipe", OwnerID: 7, Public: true}
+
+	assert.NoError(t, Authorize(context.Background(), Read, recipe))
+	assert.Equal(t, ErrDenied, Authorize(context.Background(), Update, recipe))
+	assert.Equal(t, ErrDenied, Authorize(asUser(Subject{UserID: 8}), Delete, recipe))
+}
+
+func TestAuthorize_AnonymousOrUnknownUser(t *testing.T) {
+	recipe := Resource{Type: "recipe"}
+
+	assert.Equal(t, ErrDenied, Authorize(context.Background(), Read, recipe))
+	// Owner ID 0 must not match a subject without a user
+	assert.Equal(t, ErrDenied, Authorize(asUser(Subject{}), Read, recipe))
+}
+
+func TestAuthorize_AdminModeratesButDoesNotEdit(t *testing.T) {
+	ctx := asUser(Subject{UserID: 1, Role: models.RoleAdmin})
+	recipe := Resource{Type: "recipe", OwnerID: 7}
+
+	assert.NoError(t, Authorize(ctx, Read, recipe))
+	assert.NoError(t, Authorize(ctx, Delete, recipe))
+	assert.Equal(t, ErrDenied, Authorize(ctx, Update, recipe))
+}
+
+func TestAuthorize_Scopes(t *testing.T) {
+	ctx := asUser(Subject{UserID: 1, Scopes: ParseScopes("recipe:read meal_plan:update")})
+
+	assert.NoError(t, Authorize(ctx, Read, Resource{Type: "recipe", OwnerID: 7}))
+	assert.Equal(t, ErrDenied, Authorize(ctx, Update, Resource{Type: "recipe", OwnerID: 7}))
+	assert.Equal(t, ErrDenied, Authorize(ctx, Read, Resource{OwnerID: 7}))
+}
+
+func TestAuthorize_HouseholdMembers(t *testing.T) {
+	ctx := asUser(Subject{UserID: 2, Households: []int{4}})
+	plan := Resource{Type: "meal_plan", OwnerID: 7, HouseholdID: 4}
+
+	assert.NoError(t, Authorize(ctx, Read, plan))
+	assert.NoError(t, Authorize(ctx, Update, plan))
+	assert.Equal(t, ErrDenied, Authorize(ctx, Delete, plan))
+	assert.Equal(t, ErrDenied, Authorize(ctx, Read, Resource{Type: "meal_plan", OwnerID: 7, HouseholdID: 5}))
+}
@@ -0,0 +1,90 @@
+package seed
+
+import (
+	"fmt"
+
+	"meal-prep/shared/database"
+)
+
+// bulkLoad stages ingredients, recipes and recipe ingredients in temporary
+// tables with COPY, then merges them with set-based INSERT ... SELECT
+// statements. COPY itself cannot skip conflicting rows, so the staging step
+// is what keeps a bulk load as idempotent as the row-by-row path.
+func bulkLoad(tx *database.Tx, ds Dataset) error {
+	if _, err := tx.Exec(`
+		CREATE TEMP TABLE seed_ingredients (name TEXT, description TEXT, category TEXT) ON COMMIT DROP;
+		CREATE TEMP TABLE seed_recipes (name TEXT, description TEXT, category TEXT, owner_email TEXT) ON COMMIT DROP;
+		CREATE TEMP TABLE seed_recipe_ingredients (recipe TEXT, owner_email TEXT, ingredient TEXT, quantity NUMERIC, unit TEXT, notes TEXT) ON COMMIT DROP`,
+	); err != nil {
+		return fmt.Errorf("create staging tables: %w", err)
+	}
+
+	ingredientRows := make([][]interface{}, 0, len(ds.Ingredients))
+	for _, i := range ds.Ingredients {
+		ingredientRows = append(ingredientRows, []interface{}{i.Name, nullIfEmpty(i.Description), i.Category})
+	}
+
+	recipeRows := make([][]interface{}, 0, len(ds.Recipes))
+	var recipeIngredientRows [][]interface{}
+	for _, r := range ds.Recipes {
+		owner := recipeOwner(r)
+		recipeRows = append(recipeRows, []interface{}{r.Name, nullIfEmpty(r.Description), r.Category, owner})
+		for _, ri := range r.Ingredients {
+			recipeIngredientRows = append(recipeIngredientRows,
+				[]interface{}{r.Name, owner, ri.Ingredient, ri.Quantity, ri.Unit, nullIfEmpty(ri.Notes)})
+		}
+	}
+
+	if err := tx.CopyRows("seed_ingredients", []string{"name", "description", "category"}, ingredientRows); err != nil {
+		return fmt.Errorf("copy ingredients: %w", err)
+	}
+	if err := tx.CopyRows("seed_recipes", []string{"name", "description", "category", "owner_email"}, recipeRows); err != nil {
+		return fmt.Errorf("copy recipes: %w", err)
+	}
+	if err := tx.CopyRows("seed_recipe_ingredients",
+		[]string{"recipe", "owner_email", "ingredient", "quantity", "unit", "notes"}, recipeIngredientRows); err != nil {
+		return fmt.Errorf("copy recipe ingredients: %w", err)
+	}
+
+	merges := []struct {
+		name  string
+		query string
+	}{
+		{"ingredients", `
+			INSERT INTO recipe_catalogue.ingredients (name, description, category)
+			SELECT DISTINCT ON (name) name, description, category
+			FROM seed_ingredients
+			ON CONFLICT (name) DO NOTHING`},
+		{"recipes", `
+			INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id)
+			SELECT DISTINCT ON (u.id, s.name) s.name, s.description, c.id, u.id
+			FROM seed_recipes s
+			LEFT JOIN recipe_catalogue.categories c ON c.name = s.category
+			JOIN auth.users u ON u.email = s.owner_email
+			WHERE NOT EXISTS (
+				SELECT 1 FROM recipe_catalogue.recipes r WHERE r.user_id = u.id AND r.name = s.name
+			)`},
+		// Like seedRecipe, ingredients go to the owner's first recipe of that
+		// name and never to other users' recipes that share it
+		{"recipe ingredients", `
+			INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes)
+			SELECT DISTINCT ON (r.id, i.id) r.id, i.id, s.quantity, s.unit, s.notes
+			FROM seed_recipe_ingredients s
+			JOIN auth.users u ON u.email = s.owner_email
+			JOIN LATERAL (
+				SELECT id FROM recipe_catalogue.recipes
+				WHERE user_id = u.id AND name = s.recipe
+				ORDER BY id
+				LIMIT 1
+			) r ON true
+			JOIN recipe_catalogue.ingredients i ON i.name = s.ingredient
+			ON CONFLICT (recipe_id, ingredient_id) DO NOTHING`},
+	}
+	for _, m := range merges {
+		if _, err := tx.Exec(m.query); err != nil {
+			return fmt.Errorf("merge %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
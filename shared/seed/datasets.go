@@ -0,0 +1,88 @@
+package seed
+
+// Default is the reference data every environment needs: the recipe
+// categories and a pantry of common ingredients.
+var Default = Dataset{
+	Categories: []Category{
+		{Name: "Meat", Description: "Meat-based dishes"},
+		{Name: "Chicken", Description: "Chicken dishes"},
+		{Name: "Fish", Description: "Fish and seafood"},
+		{Name: "Vegetarian", Description: "Vegetarian dishes"},
+		{Name: "Snacks", Description: "Light meals and snacks"},
+		{Name: "Desserts", Description: "Sweet treats"},
+	},
+	Ingredients: []Ingredient{
+		{Name: "Chicken Breast", Description: "Boneless skinless chicken breast", Category: "Meat"},
+		{Name: "Ground Beef", Description: "Lean ground beef", Category: "Meat"},
+		{Name: "Bacon", Description: "Smoked streaky bacon", Category: "Meat"},
+		{Name: "Salmon Fillet", Description: "Fresh salmon fillet", Category: "Fish"},
+		{Name: "Tuna", Description: "Canned tuna in water", Category: "Fish"},
+		{Name: "Rice", Description: "Long grain rice", Category: "Grains"},
+		{Name: "Pasta", Description: "Dry pasta noodles", Category: "Grains"},
+		{Name: "Flour", Description: "All-purpose wheat flour", Category: "Grains"},
+		{Name: "Oats", Description: "Rolled oats", Category: "Grains"},
+		{Name: "Onion", Description: "Yellow onion", Category: "Vegetables"},
+		{Name: "Garlic", Description: "Fresh garlic cloves", Category: "Vegetables"},
+		{Name: "Tomato", Description: "Fresh tomatoes", Category: "Vegetables"},
+		{Name: "Lettuce", Description: "Romaine lettuce", Category: "Vegetables"},
+		{Name: "Carrot", Description: "Fresh carrots", Category: "Vegetables"},
+		{Name: "Potato", Description: "Floury potatoes", Category: "Vegetables"},
+		{Name: "Bell Pepper", Description: "Red bell pepper", Category: "Vegetables"},
+		{Name: "Eggs", Description: "Large eggs", Category: "Dairy"},
+		{Name: "Milk", Description: "Whole milk", Category: "Dairy"},
+		{Name: "Butter", Description: "Unsalted butter", Category: "Dairy"},
+		{Name: "Parmesan Cheese", Description: "Grated parmesan", Category: "Dairy"},
+		{Name: "Olive Oil", Description: "Extra virgin olive oil", Category: "Oils"},
+		{Name: "Vegetable Oil", Description: "Neutral cooking oil", Category: "Oils"},
+		{Name: "Salt", Description: "Table salt", Category: "Spices"},
+		{Name: "Black Pepper", Description: "Ground black pepper", Category: "Spices"},
+		{Name: "Paprika", Description: "Sweet smoked paprika", Category: "Spices"},
+		{Name: "Banana", Description: "Ripe bananas", Category: "Fruits"},
+		{Name: "Lemon", Description: "Fresh lemons", Category: "Fruits"},
+		{Name: "Apple", Description: "Crisp eating apples", Category: "Fruits"},
+	},
+}
+
+// Demo adds a few users and recipes on top of Default for local
+// development and demos. The users share the password "demo-password".
+var Demo = Dataset{
+	Users: []User{
+		{Email: "demo@mealprep.local", Password: "demo-password"},
+		{Email: "chef@mealprep.local", Password: "demo-password"},
+	},
+	Recipes: []Recipe{
+		{
+			Name: "Garlic Butter Chicken", Description: "Pan-fried chicken in garlic butter",
+			Category: "Chicken", OwnerEmail: "demo@mealprep.local",
+			Ingredients: []RecipeIngredient{
+				{Ingredient: "Chicken Breast", Quantity: 400, Unit: "grams"},
+				{Ingredient: "Garlic", Quantity: 4, Unit: "cloves", Notes: "minced"},
+				{Ingredient: "Butter", Quantity: 30, Unit: "grams"},
+			},
+		},
+		{
+			Name: "Tomato Pasta", Description: "Weeknight pasta with a quick tomato sauce",
+			Category: "Vegetarian", OwnerEmail: "demo@mealprep.local",
+			Ingredients: []RecipeIngredient{
+				{Ingredient: "Pasta", Quantity: 200, Unit: "grams"},
+				{Ingredient: "Tomato", Quantity: 4, Unit: "pieces", Notes: "chopped"},
+				{Ingredient: "Olive Oil", Quantity: 2, Unit: "tablespoons"},
+			},
+		},
+		{
+			Name: "Banana Oat Pancakes", Description: "Three-ingredient breakfast pancakes",
+			Category: "Snacks", OwnerEmail: "chef@mealprep.local",
+			Ingredients: []RecipeIngredient{
+				{Ingredient: "Banana", Quantity: 2, Unit: "pieces"},
+				{Ingredient: "Eggs", Quantity: 2, Unit: "pieces"},
+				{Ingredient: "Oats", Quantity: 60, Unit: "grams"},
+			},
+		},
+	},
+}
+
+// Datasets lists the named datasets mealctl can load.
+var Datasets = map[string][]Dataset{
+	"default": {Default},
+	"demo":    {Default, Demo},
+}
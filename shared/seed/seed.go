@@ -0,0 +1,186 @@
+// Package seed loads reference and demo data into the database. Every
+// statement is an upsert keyed on a natural key (names, emails), so running
+// a dataset twice leaves the database unchanged.
+package seed
+
+import (
+	"database/sql"
+	"fmt"
+
+	"meal-prep/shared/database"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// systemUserEmail is the user the catalogue migrations create to own the
+// recipes that predate user accounts.
+const systemUserEmail = "system@mealprep.internal"
+
+type Category struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type Ingredient struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Category    string `json:"category"`
+}
+
+type RecipeIngredient struct {
+	Ingredient string  `json:"ingredient"` // ingredient name
+	Quantity   float64 `json:"quantity"`
+	Unit       string  `json:"unit"`
+	Notes      string  `json:"notes,omitempty"`
+}
+
+type Recipe struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Category    string `json:"category"` // category name
+	// OwnerEmail links the recipe to a seeded user. When empty the recipe
+	// belongs to the system user, like the catalogue's own recipes.
+	OwnerEmail  string             `json:"owner_email,omitempty"`
+	Ingredients []RecipeIngredient `json:"ingredients,omitempty"`
+}
+
+// User is seeded with either a plain Password, hashed with bcrypt at seed
+// time, or a precomputed PasswordHash.
+type User struct {
+	Email        string `json:"email"`
+	Password     string `json:"password,omitempty"`
+	PasswordHash string `json:"password_hash,omitempty"`
+}
+
+// Dataset is a self-contained set of rows. Categories and ingredients are
+// inserted before recipes, which reference them by name.
+type Dataset struct {
+	Users       []User       `json:"users,omitempty"`
+	Categories  []Category   `json:"categories,omitempty"`
+	Ingredients []Ingredient `json:"ingredients,omitempty"`
+	Recipes     []Recipe     `json:"recipes,omitempty"`
+}
+
+// Run applies the dataset in a single transaction. On PostgreSQL ingredients
+// and recipes are bulk-loaded with COPY, so imports of thousands of rows
+// finish in seconds; other dialects insert them one row at a time.
+func Run(db *database.DB, ds Dataset) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, u := range ds.Users {
+		hash := u.PasswordHash
+		if hash == "" {
+			b, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+			if err != nil {
+				return fmt.Errorf("seed user %s: %w", u.Email, err)
+			}
+			hash = string(b)
+		}
+
+		if _, err := tx.Exec(`
+			INSERT INTO auth.users (email, password_hash, created_at, updated_at)
+			VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+			ON CONFLICT (email) DO NOTHING`,
+			u.Email, hash); err != nil {
+			return fmt.Errorf("seed user %s: %w", u.Email, err)
+		}
+	}
+
+	for _, c := range ds.Categories {
+		if _, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.categories (name, description)
+			VALUES ($1, $2)
+			ON CONFLICT (name) DO NOTHING`,
+			c.Name, nullIfEmpty(c.Description)); err != nil {
+			return fmt.Errorf("seed category %s: %w", c.Name, err)
+		}
+	}
+
+	if tx.Dialect() == database.DialectPostgres {
+		if err := bulkLoad(tx, ds); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	for _, i := range ds.Ingredients {
+		if _, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.ingredients (name, description, category)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (name) DO NOTHING`,
+			i.Name, nullIfEmpty(i.Description), i.Category); err != nil {
+			return fmt.Errorf("seed ingredient %s: %w", i.Name, err)
+		}
+	}
+
+	for _, r := range ds.Recipes {
+		if err := seedRecipe(tx, r); err != nil {
+			return fmt.Errorf("seed recipe %s: %w", r.Name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// seedRecipe inserts the recipe unless its owner already has one with the
+// same name, then links its ingredients to that recipe. Recipe names are not
+// unique in the schema, so the lookup stands in for ON CONFLICT, and keying
+// it on the owner keeps other users' recipes of the same name untouched.
+func seedRecipe(tx *database.Tx, r Recipe) error {
+	owner := recipeOwner(r)
+
+	var recipeID int
+	err := tx.QueryRow(`
+		SELECT r.id
+		FROM recipe_catalogue.recipes r
+		JOIN auth.users u ON u.id = r.user_id
+		WHERE u.email = $1 AND r.name = $2
+		ORDER BY r.id
+		LIMIT 1`,
+		owner, r.Name).Scan(&recipeID)
+	if err == sql.ErrNoRows {
+		err = tx.QueryRow(`
+			INSERT INTO recipe_catalogue.recipes (name, description, category_id, user_id)
+			VALUES ($1, $2, (SELECT id FROM recipe_catalogue.categories WHERE name = $3),
+			        (SELECT id FROM auth.users WHERE email = $4))
+			RETURNING id`,
+			r.Name, nullIfEmpty(r.Description), r.Category, owner).Scan(&recipeID)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, ri := range r.Ingredients {
+		_, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes)
+			SELECT $1, i.id, $3::numeric, $4, $5
+			FROM recipe_catalogue.ingredients i
+			WHERE i.name = $2
+			ON CONFLICT (recipe_id, ingredient_id) DO NOTHING`,
+			recipeID, ri.Ingredient, ri.Quantity, ri.Unit, nullIfEmpty(ri.Notes))
+		if err != nil {
+			return fmt.Errorf("ingredient %s: %w", ri.Ingredient, err)
+		}
+	}
+
+	return nil
+}
+
+// recipeOwner is the email of the user a seeded recipe belongs to.
+func recipeOwner(r Recipe) string {
+	if r.OwnerEmail == "" {
+		return systemUserEmail
+	}
+	return r.OwnerEmail
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
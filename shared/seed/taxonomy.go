@@ -0,0 +1,270 @@
+package seed
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+)
+
+// Taxonomy is a subset of a standard food classification, such as FoodEx2
+// or LanguaL, with its terms mapped onto the catalogue's categories and
+// allergen codes. Terms form a tree through Parent: group terms carry the
+// category and allergens their descendants inherit, and the leaves become
+// ingredients.
+type Taxonomy struct {
+	Source string         `json:"source,omitempty"` // e.g. "foodex2"
+	Terms  []TaxonomyTerm `json:"terms"`
+}
+
+type TaxonomyTerm struct {
+	Code   string `json:"code"`
+	Parent string `json:"parent,omitempty"` // code of the group term
+	Name   string `json:"name"`
+	// Category overrides the one inherited from the nearest group that has
+	// one. Allergens add to the inherited ones.
+	Category  string   `json:"category,omitempty"`
+	Allergens []string `json:"allergens,omitempty"`
+	Aliases   []string `json:"aliases,omitempty"`
+}
+
+// TaxonomyIngredient is a leaf term with what it inherits resolved.
+// Aliases hold the other names the term goes by, its own name included.
+type TaxonomyIngredient struct {
+	Code      string
+	Name      string
+	Category  string
+	Allergens []string
+	Aliases   []string
+}
+
+// TaxonomyResult counts what an import changed.
+type TaxonomyResult struct {
+	Ingredients     int // leaf terms imported
+	Created         int // ingredients new to the catalogue
+	Recategorized   int // existing ingredients whose category changed
+	AllergensTagged int
+	AliasesAdded    int
+}
+
+// ResolveTaxonomy checks the terms form a tree and returns its leaves, each
+// with the category and allergens it inherits, in file order.
+func ResolveTaxonomy(t Taxonomy) ([]TaxonomyIngredient, error) {
+	terms := make(map[string]TaxonomyTerm, len(t.Terms))
+	for _, term := range t.Terms {
+		if strings.TrimSpace(term.Code) == "" || strings.TrimSpace(term.Name) == "" {
+			return nil, fmt.Errorf("taxonomy term %q: code and name are required", term.Code)
+		}
+		if _, dup := terms[term.Code]; dup {
+			return nil, fmt.Errorf("taxonomy term %s is listed twice", term.Code)
+		}
+		if term.Category != "" && !models.IsValidIngredientCategory(term.Category) {
+			return nil, fmt.Errorf("taxonomy term %s: unknown category %q", term.Code, term.Category)
+		}
+		terms[term.Code] = term
+	}
+
+	groups := make(map[string]bool)
+	for _, term := range t.Terms {
+		if term.Parent == "" {
+			continue
+		}
+		if _, ok := terms[term.Parent]; !ok {
+			return nil, fmt.Errorf("taxonomy term %s: unknown parent %s", term.Code, term.Parent)
+		}
+		groups[term.Parent] = true
+	}
+
+	var ingredients []TaxonomyIngredient
+	for _, term := range t.Terms {
+		if groups[term.Code] {
+			continue
+		}
+
+		ingredient := TaxonomyIngredient{Code: term.Code, Name: strings.TrimSpace(term.Name)}
+		allergens := make(map[string]bool)
+		seen := make(map[string]bool)
+		for ancestor := term; ; ancestor = terms[ancestor.Parent] {
+			if seen[ancestor.Code] {
+				return nil, fmt.Errorf("taxonomy term %s is its own ancestor", ancestor.Code)
+			}
+			seen[ancestor.Code] = true
+
+			if ingredient.Category == "" {
+				ingredient.Category = ancestor.Category
+			}
+			for _, code := range ancestor.Allergens {
+				allergens[code] = true
+			}
+			if ancestor.Parent == "" {
+				break
+			}
+		}
+		if ingredient.Category == "" {
+			return nil, fmt.Errorf("taxonomy term %s: no category on it or its groups", term.Code)
+		}
+
+		for code := range allergens {
+			ingredient.Allergens = append(ingredient.Allergens, code)
+		}
+		sort.Strings(ingredient.Allergens)
+
+		names := map[string]bool{strings.ToLower(ingredient.Name): true}
+		ingredient.Aliases = []string{ingredient.Name}
+		for _, alias := range term.Aliases {
+			alias = strings.TrimSpace(alias)
+			if alias != "" && !names[strings.ToLower(alias)] {
+				names[strings.ToLower(alias)] = true
+				ingredient.Aliases = append(ingredient.Aliases, alias)
+			}
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+	return ingredients, nil
+}
+
+// ImportTaxonomy applies a taxonomy in a single transaction. A leaf term
+// updates the ingredient with its name or, failing that, the first of its
+// aliases one has, and creates it otherwise. The ingredient takes the term's
+// category, gains its allergens, and keeps the term's other names as
+// aliases. Allergens and aliases are only ever added, so tags set by hand
+// survive and re-importing a file changes nothing.
+func ImportTaxonomy(db *database.DB, t Taxonomy) (TaxonomyResult, error) {
+	ingredients, err := ResolveTaxonomy(t)
+	if err != nil {
+		return TaxonomyResult{}, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return TaxonomyResult{}, err
+	}
+	defer tx.Rollback()
+
+	known, err := allergenCodes(tx)
+	if err != nil {
+		return TaxonomyResult{}, err
+	}
+	for _, ingredient := range ingredients {
+		for _, code := range ingredient.Allergens {
+			if !known[code] {
+				return TaxonomyResult{}, fmt.Errorf("taxonomy term %s: unknown allergen %q", ingredient.Code, code)
+			}
+		}
+	}
+
+	result := TaxonomyResult{Ingredients: len(ingredients)}
+	for _, ingredient := range ingredients {
+		if err := importTaxonomyIngredient(tx, ingredient, &result); err != nil {
+			return TaxonomyResult{}, fmt.Errorf("taxonomy term %s: %w", ingredient.Code, err)
+		}
+	}
+	return result, tx.Commit()
+}
+
+func importTaxonomyIngredient(tx *database.Tx, ingredient TaxonomyIngredient, result *TaxonomyResult) error {
+	id, name, err := findIngredientByNames(tx, ingredient.Aliases)
+	if err != nil {
+		return err
+	}
+
+	if id == 0 {
+		name = ingredient.Name
+		if err := tx.QueryRow(`
+			INSERT INTO recipe_catalogue.ingredients (name, category)
+			VALUES ($1, $2)
+			RETURNING id`,
+			name, ingredient.Category).Scan(&id); err != nil {
+			return err
+		}
+		result.Created++
+	} else {
+		res, err := tx.Exec(`
+			UPDATE recipe_catalogue.ingredients
+			SET category = $2, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND category <> $2`,
+			id, ingredient.Category)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			result.Recategorized += int(n)
+		}
+	}
+
+	for _, code := range ingredient.Allergens {
+		res, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.ingredient_allergens (ingredient_id, allergen_id)
+			SELECT $1, id FROM recipe_catalogue.allergens WHERE code = $2
+			ON CONFLICT (ingredient_id, allergen_id) DO NOTHING`,
+			id, code)
+		if err != nil {
+			return fmt.Errorf("allergen %s: %w", code, err)
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			result.AllergensTagged += int(n)
+		}
+	}
+
+	for _, alias := range ingredient.Aliases {
+		if strings.EqualFold(alias, name) {
+			continue
+		}
+		res, err := tx.Exec(`
+			INSERT INTO recipe_catalogue.ingredient_aliases (ingredient_id, name)
+			VALUES ($1, $2)
+			ON CONFLICT (ingredient_id, name) DO NOTHING`,
+			id, alias)
+		if err != nil {
+			return fmt.Errorf("alias %s: %w", alias, err)
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			result.AliasesAdded += int(n)
+		}
+	}
+	return nil
+}
+
+// findIngredientByNames returns the ingredient named, ignoring case, the
+// first of names any ingredient has, or 0 when none does.
+func findIngredientByNames(tx *database.Tx, names []string) (int, string, error) {
+	var id int
+	var name string
+	for _, candidate := range names {
+		err := tx.QueryRow(`
+			SELECT id, name FROM recipe_catalogue.ingredients
+			WHERE lower(name) = lower($1)
+			ORDER BY id
+			LIMIT 1`,
+			candidate).Scan(&id, &name)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return 0, "", err
+		}
+		return id, name, nil
+	}
+	return 0, "", nil
+}
+
+func allergenCodes(tx *database.Tx) (map[string]bool, error) {
+	rows, err := tx.Query(`SELECT code FROM recipe_catalogue.allergens`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes := make(map[string]bool)
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		codes[code] = true
+	}
+	return codes, rows.Err()
+}
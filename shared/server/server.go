@@ -0,0 +1,122 @@
+// Package server runs a service's HTTP server and shuts it down gracefully,
+// so requests in flight when the service is told to stop still get answered.
+//
+// Timeouts are configured from the environment, the same for every service:
+//
+//	HTTP_READ_TIMEOUT      time to read a whole request, body included (30s)
+//	HTTP_WRITE_TIMEOUT     time to write a response (60s)
+//	HTTP_IDLE_TIMEOUT      how long a keep-alive connection may sit idle (120s)
+//	SHUTDOWN_DRAIN_PERIOD  how long in-flight requests get to finish on
+//	                       SIGTERM or SIGINT before connections are cut (15s)
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/mtls"
+)
+
+// readHeaderTimeout bounds slow-loris clients even when HTTP_READ_TIMEOUT is 0.
+const readHeaderTimeout = 10 * time.Second
+
+type Config struct {
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	DrainPeriod  time.Duration
+}
+
+// ConfigFromEnv reads the timeouts listed in the package documentation.
+func ConfigFromEnv() Config {
+	return Config{
+		ReadTimeout:  envDuration("HTTP_READ_TIMEOUT", 30*time.Second),
+		WriteTimeout: envDuration("HTTP_WRITE_TIMEOUT", 60*time.Second),
+		IdleTimeout:  envDuration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+		DrainPeriod:  envDuration("SHUTDOWN_DRAIN_PERIOD", 15*time.Second),
+	}
+}
+
+// SignalContext is cancelled when the process receives SIGTERM or SIGINT.
+// Mains pass it to Run and to their background jobs so everything stops
+// together; stop releases the signal handlers.
+func SignalContext() (ctx context.Context, stop context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+}
+
+// New builds the server for handler, over mTLS when it is configured (see
+// package mtls) and plain HTTP otherwise.
+func New(addr string, handler http.Handler, cfg Config) (*http.Server, error) {
+	tlsConfig, err := mtls.ServerTLSConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}, nil
+}
+
+// Run serves handler on addr until ctx is cancelled, then stops accepting
+// connections and gives in-flight requests up to cfg.DrainPeriod to finish.
+// It returns nil after a clean shutdown, or the error that kept the server
+// from starting or draining.
+func Run(ctx context.Context, addr string, handler http.Handler, cfg Config) error {
+	srv, err := New(addr, handler, cfg)
+	if err != nil {
+		return err
+	}
+	return Serve(ctx, srv, cfg.DrainPeriod)
+}
+
+// Serve runs an already built server; see Run.
+func Serve(ctx context.Context, srv *http.Server, drainPeriod time.Duration) error {
+	served := make(chan error, 1)
+	go func() {
+		if srv.TLSConfig != nil {
+			served <- srv.ListenAndServeTLS("", "")
+		} else {
+			served <- srv.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	logging.Logger.Info("Shutting down, draining in-flight requests", "drain_period", drainPeriod.String())
+	drainCtx, cancel := context.WithTimeout(context.Background(), drainPeriod)
+	defer cancel()
+
+	if err := srv.Shutdown(drainCtx); err != nil {
+		// Requests still running after the drain period are cut off
+		srv.Close()
+		return err
+	}
+	if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	logging.Logger.Info("Server stopped")
+	return nil
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d >= 0 {
+		return d
+	}
+	return fallback
+}
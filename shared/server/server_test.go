@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"meal-prep/shared/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// freeAddr returns a loopback address nothing is listening on.
+func freeAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
+
+// serveSlowly starts a server whose handler waits for release, and returns
+// once a request is in flight.
+func serveSlowly(t *testing.T, drainPeriod time.Duration) (release chan struct{}, response chan *http.Response, stop context.CancelFunc, done chan error) {
+	logging.Init("test")
+	addr := freeAddr(t)
+	started := make(chan struct{})
+	release = make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("done"))
+	})
+
+	ctx, stop := context.WithCancel(context.Background())
+	done = make(chan error, 1)
+	go func() { done <- Run(ctx, addr, handler, Config{DrainPeriod: drainPeriod}) }()
+
+	response = make(chan *http.Response, 1)
+	go func() {
+		for {
+			resp, err := http.Get("http://" + addr)
+			if err == nil {
+				response <- resp
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+	<-started
+	return release, response, stop, done
+}
+
+func TestRun_DrainsInFlightRequests(t *testing.T) {
+	release, response, stop, done := serveSlowly(t, 5*time.Second)
+
+	stop()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	resp := <-response
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "done", string(body))
+	assert.NoError(t, <-done)
+}
+
+func TestRun_CutsOffRequestsAfterDrainPeriod(t *testing.T) {
+	release, _, stop, done := serveSlowly(t, 20*time.Millisecond)
+	defer close(release)
+
+	stop()
+	assert.ErrorIs(t, <-done, context.DeadlineExceeded)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("SHUTDOWN_DRAIN_PERIOD", "")
+	t.Setenv("HTTP_WRITE_TIMEOUT", "")
+	assert.Equal(t, 15*time.Second, ConfigFromEnv().DrainPeriod)
+	assert.Equal(t, 60*time.Second, ConfigFromEnv().WriteTimeout)
+
+	t.Setenv("SHUTDOWN_DRAIN_PERIOD", "30s")
+	t.Setenv("HTTP_WRITE_TIMEOUT", "0s")
+	assert.Equal(t, 30*time.Second, ConfigFromEnv().DrainPeriod)
+	assert.Equal(t, time.Duration(0), ConfigFromEnv().WriteTimeout)
+}
@@ -1,4 +1,4 @@
-package testdata
+package factory
 
 import (
 	"meal-prep/shared/models"
@@ -19,6 +19,7 @@ func NewRecipeBuilder() *RecipeBuilder {
 			CategoryID:  intPtr(1),
 			CreatedAt:   now,
 			UpdatedAt:   now,
+			Status:      models.RecipeStatusPublished,
 			Category: &models.Category{
 				ID:   1,
 				Name: "Default Category",
@@ -66,6 +67,11 @@ func (b *RecipeBuilder) WithCategory(category models.Category) *RecipeBuilder {
 	return b
 }
 
+func (b *RecipeBuilder) WithStatus(status string) *RecipeBuilder {
+	b.recipe.Status = status
+	return b
+}
+
 func (b *RecipeBuilder) Build() models.Recipe {
 	return b.recipe
 }
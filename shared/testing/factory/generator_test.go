@@ -0,0 +1,50 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerator_SameSeedProducesSameData(t *testing.T) {
+	a, b := NewGenerator(42), NewGenerator(42)
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, a.Recipe(1, 2, 3), b.Recipe(1, 2, 3))
+		assert.Equal(t, a.CookingHistory(1, i), b.CookingHistory(1, i))
+		assert.Equal(t, a.UserPreferences(1, 1, 2, 3, 4), b.UserPreferences(1, 1, 2, 3, 4))
+	}
+}
+
+func TestGenerator_AssignsUniqueIDs(t *testing.T) {
+	g := NewGenerator(1)
+	seen := map[int]bool{}
+
+	for i := 0; i < 50; i++ {
+		user := g.User()
+		assert.False(t, seen[user.ID])
+		seen[user.ID] = true
+	}
+}
+
+func TestGenerator_RecipeUsesGivenCategories(t *testing.T) {
+	g := NewGenerator(7)
+
+	for i := 0; i < 20; i++ {
+		recipe := g.Recipe(4, 5)
+		assert.Contains(t, []int{4, 5}, *recipe.CategoryID)
+	}
+	assert.Nil(t, g.Recipe().CategoryID)
+}
+
+func TestGenerator_CookingHistoryRatingInRange(t *testing.T) {
+	g := NewGenerator(3)
+
+	for i := 0; i < 100; i++ {
+		entry := g.CookingHistory(1, 1)
+		if entry.Rating != nil {
+			assert.GreaterOrEqual(t, *entry.Rating, 1)
+			assert.LessOrEqual(t, *entry.Rating, 5)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package factory
+
+import (
+	"meal-prep/shared/models"
+	"time"
+)
+
+type UserBuilder struct {
+	user models.User
+}
+
+func NewUserBuilder() *UserBuilder {
+	now := time.Now()
+	return &UserBuilder{
+		user: models.User{
+			ID:           1,
+			Email:        "test@example.com",
+			PasswordHash: "$2a$10$hashedpassword",
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		},
+	}
+}
+
+func (b *UserBuilder) WithID(id int) *UserBuilder {
+	b.user.ID = id
+	return b
+}
+
+func (b *UserBuilder) WithEmail(email string) *UserBuilder {
+	b.user.Email = email
+	return b
+}
+
+func (b *UserBuilder) WithPasswordHash(hash string) *UserBuilder {
+	b.user.PasswordHash = hash
+	return b
+}
+
+func (b *UserBuilder) Build() models.User {
+	return b.user
+}
+
+func (b *UserBuilder) BuildPtr() *models.User {
+	user := b.Build()
+	return &user
+}
+
+type UserPreferencesBuilder struct {
+	preferences models.UserPreferences
+}
+
+func NewUserPreferencesBuilder() *UserPreferencesBuilder {
+	now := time.Now()
+	return &UserPreferencesBuilder{
+		preferences: models.UserPreferences{
+			ID:                  1,
+			UserID:              1,
+			PreferredCategories: []int{1},
+			CreatedAt:           now,
+			UpdatedAt:           now,
+		},
+	}
+}
+
+func (b *UserPreferencesBuilder) WithID(id int) *UserPreferencesBuilder {
+	b.preferences.ID = id
+	return b
+}
+
+func (b *UserPreferencesBuilder) WithUserID(userID int) *UserPreferencesBuilder {
+	b.preferences.UserID = userID
+	return b
+}
+
+func (b *UserPreferencesBuilder) WithPreferredCategories(categoryIDs ...int) *UserPreferencesBuilder {
+	b.preferences.PreferredCategories = categoryIDs
+	return b
+}
+
+func (b *UserPreferencesBuilder) WithNoPreferredCategories() *UserPreferencesBuilder {
+	b.preferences.PreferredCategories = []int{}
+	return b
+}
+
+func (b *UserPreferencesBuilder) Build() models.UserPreferences {
+	return b.preferences
+}
+
+func (b *UserPreferencesBuilder) BuildPtr() *models.UserPreferences {
+	preferences := b.Build()
+	return &preferences
+}
+
+type CookingHistoryBuilder struct {
+	entry models.CookingHistory
+}
+
+func NewCookingHistoryBuilder() *CookingHistoryBuilder {
+	return &CookingHistoryBuilder{
+		entry: models.CookingHistory{
+			ID:       1,
+			UserID:   1,
+			RecipeID: 1,
+			CookedAt: time.Now(),
+		},
+	}
+}
+
+func (b *CookingHistoryBuilder) WithID(id int) *CookingHistoryBuilder {
+	b.entry.ID = id
+	return b
+}
+
+func (b *CookingHistoryBuilder) WithUserID(userID int) *CookingHistoryBuilder {
+	b.entry.UserID = userID
+	return b
+}
+
+func (b *CookingHistoryBuilder) WithRecipeID(recipeID int) *CookingHistoryBuilder {
+	b.entry.RecipeID = recipeID
+	return b
+}
+
+func (b *CookingHistoryBuilder) WithCookedAt(cookedAt time.Time) *CookingHistoryBuilder {
+	b.entry.CookedAt = cookedAt
+	return b
+}
+
+func (b *CookingHistoryBuilder) CookedDaysAgo(days int) *CookingHistoryBuilder {
+	b.entry.CookedAt = time.Now().AddDate(0, 0, -days)
+	return b
+}
+
+func (b *CookingHistoryBuilder) WithRating(rating int) *CookingHistoryBuilder {
+	b.entry.Rating = intPtr(rating)
+	return b
+}
+
+func (b *CookingHistoryBuilder) WithNoRating() *CookingHistoryBuilder {
+	b.entry.Rating = nil
+	return b
+}
+
+func (b *CookingHistoryBuilder) Build() models.CookingHistory {
+	return b.entry
+}
+
+func (b *CookingHistoryBuilder) BuildPtr() *models.CookingHistory {
+	entry := b.Build()
+	return &entry
+}
+
+// User and preference test scenarios
+func ValidUser() models.User {
+	return NewUserBuilder().
+		WithEmail("user@example.com").
+		Build()
+}
+
+func ValidUserPreferences() models.UserPreferences {
+	return NewUserPreferencesBuilder().
+		WithPreferredCategories(1, 2).
+		Build()
+}
+
+func ValidCookingHistory() models.CookingHistory {
+	return NewCookingHistoryBuilder().
+		CookedDaysAgo(3).
+		WithRating(4).
+		Build()
+}
@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInit_WithoutEndpointOnlyPropagates(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	shutdown, err := Init(context.Background(), "test-service")
+
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+}
+
+func TestTransport_PropagatesTraceContext(t *testing.T) {
+	_, err := Init(context.Background(), "test-service")
+	require.NoError(t, err)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "GET /me/usage")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/internal/usage", nil)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	client := spans[0]
+	assert.Equal(t, trace.SpanKindClient, client.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), client.Parent().SpanID())
+	// The server sees the client span as its parent
+	assert.Contains(t, traceparent, client.SpanContext().SpanID().String())
+	assert.Empty(t, req.Header.Get("traceparent"), "the caller's request must not be modified")
+}
@@ -0,0 +1,51 @@
+package units
+
+// ladders are the units a quantity may move between as it grows or shrinks,
+// largest first. A quantity never leaves its ladder, so scaling keeps a
+// recipe in the system it was written in. Fluid ounces and pieces have no
+// ladder and keep their unit.
+var ladders = [][]string{
+	{Kilogram, Gram},
+	{Litre, Millilitre},
+	{Pound, Ounce},
+	{Cup, Tablespoon, Teaspoon},
+}
+
+var ladderOf = func() map[string][]string {
+	byUnit := make(map[string][]string)
+	for _, ladder := range ladders {
+		for _, name := range ladder {
+			byUnit[name] = ladder
+		}
+	}
+	return byUnit
+}()
+
+// Fit expresses quantity in the largest unit of its ladder that it makes at
+// least one of (1200 g is 1.2 kg, 0.25 kg is 250 g, 6 tsp is 2 tbsp), rounded
+// to two decimals. The unit is returned as given when it still fits, and
+// units without a ladder come back unchanged.
+func Fit(quantity float64, unit string) (float64, string) {
+	name := Normalize(unit)
+	ladder, ok := ladderOf[name]
+	if !ok || quantity <= 0 {
+		return quantity, unit
+	}
+
+	base := quantity * table[name].factor
+	to := pick(base, ladder...)
+	if to == name {
+		return round2(quantity), unit
+	}
+	return round2(base / table[to].factor), to
+}
+
+// Scale multiplies quantity by factor and fits the result, so that doubling
+// 600 g asks for 1.2 kg and a third of 1 tbsp asks for 1 tsp. A factor of 1
+// keeps the amount as the recipe was written.
+func Scale(quantity float64, unit string, factor float64) (float64, string) {
+	if factor == 1 {
+		return quantity, unit
+	}
+	return Fit(quantity*factor, unit)
+}
@@ -0,0 +1,48 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		quantity     float64
+		unit         string
+		expected     float64
+		expectedUnit string
+	}{
+		{1200, "grams", 1.2, Kilogram},
+		{0.25, "kg", 250, Gram},
+		{500, "grams", 500, "grams"},
+		{6, "tsp", 2, Tablespoon},
+		{6, "tbsp", 6, "tbsp"},
+		{32, "tablespoons", 2, Cup},
+		{1500, "ml", 1.5, Litre},
+		{20, "oz", 1.25, Pound},
+		{12, "pieces", 12, "pieces"},
+		{3, "cloves", 3, "cloves"},
+		{16, "fl oz", 16, "fl oz"},
+	}
+
+	for _, tt := range tests {
+		quantity, unit := Fit(tt.quantity, tt.unit)
+		assert.InDelta(t, tt.expected, quantity, 1e-9, "%v %s", tt.quantity, tt.unit)
+		assert.Equal(t, tt.expectedUnit, unit, "%v %s", tt.quantity, tt.unit)
+	}
+}
+
+func TestScale(t *testing.T) {
+	quantity, unit := Scale(600, "g", 2)
+	assert.Equal(t, 1.2, quantity)
+	assert.Equal(t, Kilogram, unit)
+
+	quantity, unit = Scale(1, "tbsp", 1.0/3)
+	assert.Equal(t, 1.0, quantity)
+	assert.Equal(t, Teaspoon, unit)
+
+	quantity, unit = Scale(2, "pieces", 1.5)
+	assert.Equal(t, 3.0, quantity)
+	assert.Equal(t, "pieces", unit)
+}
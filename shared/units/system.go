@@ -0,0 +1,76 @@
+package units
+
+import "math"
+
+// Measurement systems a quantity can be presented in.
+const (
+	Metric   = "metric"
+	Imperial = "imperial"
+)
+
+// IsValidSystem reports whether system is Metric or Imperial.
+func IsValidSystem(system string) bool {
+	return system == Metric || system == Imperial
+}
+
+// systemOf maps units to the system they belong to. Spoons are used the same
+// way everywhere, so they belong to neither and are never converted.
+var systemOf = map[string]string{
+	Gram:       Metric,
+	Kilogram:   Metric,
+	Millilitre: Metric,
+	Litre:      Metric,
+	Ounce:      Imperial,
+	Pound:      Imperial,
+	Cup:        Imperial,
+	FluidOunce: Imperial,
+}
+
+// ToSystem expresses quantity in the given system, picking a unit that suits
+// the amount (500 g, 1.5 kg, 12 oz, 2 lb, 3 tbsp, 1.5 cup). Quantities that
+// are already in the system, spoon measures and units that are not mass or
+// volume (e.g. "cloves") come back unchanged. Converted quantities are
+// rounded to two decimals.
+func ToSystem(quantity float64, unit, system string) (float64, string) {
+	name := Normalize(unit)
+	from, ok := systemOf[name]
+	if !ok || from == system || !IsValidSystem(system) {
+		return quantity, unit
+	}
+
+	src := table[name]
+	base := quantity * src.factor
+
+	var to string
+	switch {
+	case system == Metric && src.kind == KindMass:
+		to = pick(base, Kilogram, Gram)
+	case system == Metric && src.kind == KindVolume:
+		to = pick(base, Litre, Millilitre)
+	case system == Imperial && src.kind == KindMass:
+		to = pick(base, Pound, Ounce)
+	default:
+		to = pick(base, Cup, Tablespoon, Teaspoon)
+		// A quarter cup reads better than four tablespoons
+		if to != Cup && base >= table[Cup].factor/4 {
+			to = Cup
+		}
+	}
+
+	return round2(base / table[to].factor), to
+}
+
+// pick returns the first of candidates, largest first, of which base makes
+// at least one whole unit, or the last candidate.
+func pick(base float64, candidates ...string) string {
+	for _, candidate := range candidates[:len(candidates)-1] {
+		if base >= table[candidate].factor {
+			return candidate
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+func round2(quantity float64) float64 {
+	return math.Round(quantity*100) / 100
+}
@@ -0,0 +1,51 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToSystem(t *testing.T) {
+	tests := []struct {
+		quantity     float64
+		unit, system string
+		expected     float64
+		expectedUnit string
+	}{
+		{8, "oz", Metric, 226.8, Gram},
+		{3, "pounds", Metric, 1.36, Kilogram},
+		{2, "cups", Metric, 473.18, Millilitre},
+		{6, "cups", Metric, 1.42, Litre},
+		{250, "g", Imperial, 8.82, Ounce},
+		{1, "kg", Imperial, 2.2, Pound},
+		{500, "ml", Imperial, 2.11, Cup},
+		{60, "ml", Imperial, 0.25, Cup},
+		{30, "ml", Imperial, 2.03, Tablespoon},
+		{5, "ml", Imperial, 1.01, Teaspoon},
+	}
+
+	for _, tt := range tests {
+		got, unit := ToSystem(tt.quantity, tt.unit, tt.system)
+		assert.Equal(t, tt.expectedUnit, unit, "%v %s -> %s", tt.quantity, tt.unit, tt.system)
+		assert.InDelta(t, tt.expected, got, 1e-9, "%v %s -> %s", tt.quantity, tt.unit, tt.system)
+	}
+}
+
+func TestToSystem_Unchanged(t *testing.T) {
+	tests := []struct {
+		unit, system string
+	}{
+		{"grams", Metric},  // already metric
+		{"cups", Imperial}, // already imperial
+		{"tbsp", Metric},   // spoons belong to both
+		{"cloves", Imperial},
+		{"g", "kelvin"},
+	}
+
+	for _, tt := range tests {
+		got, unit := ToSystem(3, tt.unit, tt.system)
+		assert.Equal(t, 3.0, got, tt.unit)
+		assert.Equal(t, tt.unit, unit, tt.unit)
+	}
+}
@@ -0,0 +1,126 @@
+// Package units converts recipe quantities between units of mass, volume and
+// count.
+//
+// Recipe units are free-form strings, so lookups go through Normalize, which
+// folds case, plurals and common abbreviations onto a canonical name. Moving
+// between mass and volume needs the ingredient's density in grams per
+// millilitre; without one those conversions fail with ErrDensityRequired.
+// Pieces only convert to pieces: how much one weighs differs too much from
+// one egg or onion to the next.
+package units
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrUnknownUnit     = errors.New("unknown unit")
+	ErrDensityRequired = errors.New("converting between mass and volume requires a density")
+	ErrIncompatible    = errors.New("pieces only convert to pieces")
+)
+
+// Kind is the physical quantity a unit measures.
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindMass
+	KindVolume
+	KindCount
+)
+
+// Canonical unit names.
+const (
+	Gram       = "g"
+	Kilogram   = "kg"
+	Ounce      = "oz"
+	Pound      = "lb"
+	Millilitre = "ml"
+	Litre      = "l"
+	Teaspoon   = "tsp"
+	Tablespoon = "tbsp"
+	Cup        = "cup"
+	FluidOunce = "fl oz"
+	Piece      = "piece"
+)
+
+type unit struct {
+	kind Kind
+	// factor converts one of this unit into the base unit of its kind:
+	// grams for mass, millilitres for volume, pieces for count.
+	factor float64
+}
+
+var table = map[string]unit{
+	Gram:       {KindMass, 1},
+	Kilogram:   {KindMass, 1000},
+	Ounce:      {KindMass, 28.349523125},
+	Pound:      {KindMass, 453.59237},
+	Millilitre: {KindVolume, 1},
+	Litre:      {KindVolume, 1000},
+	Teaspoon:   {KindVolume, 4.92892159375},
+	Tablespoon: {KindVolume, 14.78676478125},
+	Cup:        {KindVolume, 236.5882365},
+	FluidOunce: {KindVolume, 29.5735295625},
+	Piece:      {KindCount, 1},
+}
+
+var aliases = map[string]string{
+	"gram": Gram, "grams": Gram, "gr": Gram,
+	"kilogram": Kilogram, "kilograms": Kilogram, "kilo": Kilogram, "kilos": Kilogram,
+	"ounce": Ounce, "ounces": Ounce,
+	"pound": Pound, "pounds": Pound, "lbs": Pound,
+	"millilitre": Millilitre, "millilitres": Millilitre, "milliliter": Millilitre, "milliliters": Millilitre,
+	"litre": Litre, "litres": Litre, "liter": Litre, "liters": Litre,
+	"teaspoon": Teaspoon, "teaspoons": Teaspoon, "tsps": Teaspoon,
+	"tablespoon": Tablespoon, "tablespoons": Tablespoon, "tbsps": Tablespoon, "tbs": Tablespoon,
+	"cups": Cup, "cupful": Cup,
+	"fluid ounce": FluidOunce, "fluid ounces": FluidOunce, "floz": FluidOunce, "fl. oz": FluidOunce,
+	"pieces": Piece, "pc": Piece, "pcs": Piece,
+}
+
+// Normalize returns the canonical name for a unit, or the trimmed lower-case
+// input when it is not a known unit (e.g. "cloves").
+func Normalize(name string) string {
+	n := strings.ToLower(strings.TrimSpace(name))
+	if canonical, ok := aliases[n]; ok {
+		return canonical
+	}
+	return n
+}
+
+// KindOf reports what a unit measures.
+func KindOf(name string) Kind {
+	return table[Normalize(name)].kind
+}
+
+// Convert expresses quantity in from as an amount of to. gramsPerML is only
+// used to cross between mass and volume and may be zero otherwise.
+func Convert(quantity float64, from, to string, gramsPerML float64) (float64, error) {
+	src, ok := table[Normalize(from)]
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+	dst, ok := table[Normalize(to)]
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+
+	base := quantity * src.factor
+	if src.kind != dst.kind {
+		if src.kind == KindCount || dst.kind == KindCount {
+			return 0, ErrIncompatible
+		}
+		if gramsPerML <= 0 {
+			return 0, ErrDensityRequired
+		}
+		if src.kind == KindVolume {
+			base *= gramsPerML
+		} else {
+			base /= gramsPerML
+		}
+	}
+
+	return base / dst.factor, nil
+}
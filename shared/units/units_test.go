@@ -0,0 +1,68 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, Gram, Normalize(" Grams "))
+	assert.Equal(t, Tablespoon, Normalize("tablespoons"))
+	assert.Equal(t, FluidOunce, Normalize("Fluid Ounces"))
+	assert.Equal(t, Piece, Normalize("Pcs"))
+	assert.Equal(t, "cloves", Normalize("Cloves"))
+}
+
+func TestConvert_SameKind(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		from, to string
+		expected float64
+	}{
+		{1.5, "kg", "grams", 1500},
+		{1, "lb", "oz", 16},
+		{3, "tsp", "tbsp", 1},
+		{2, "cups", "ml", 473.176473},
+		{500, "ml", "l", 0.5},
+	}
+
+	for _, tt := range tests {
+		got, err := Convert(tt.quantity, tt.from, tt.to, 0)
+		require.NoError(t, err, "%v %s -> %s", tt.quantity, tt.from, tt.to)
+		assert.InDelta(t, tt.expected, got, 1e-6, "%v %s -> %s", tt.quantity, tt.from, tt.to)
+	}
+}
+
+func TestConvert_AcrossMassAndVolume(t *testing.T) {
+	// Flour is roughly 0.53 g/ml
+	grams, err := Convert(1, "cup", "g", 0.53)
+	require.NoError(t, err)
+	assert.InDelta(t, 125.39, grams, 0.01)
+
+	cups, err := Convert(grams, "g", "cup", 0.53)
+	require.NoError(t, err)
+	assert.InDelta(t, 1, cups, 1e-9)
+}
+
+func TestConvert_Errors(t *testing.T) {
+	_, err := Convert(1, "cup", "g", 0)
+	assert.ErrorIs(t, err, ErrDensityRequired)
+
+	_, err = Convert(1, "cloves", "g", 1)
+	assert.ErrorIs(t, err, ErrUnknownUnit)
+
+	_, err = Convert(1, "g", "pinch", 1)
+	assert.ErrorIs(t, err, ErrUnknownUnit)
+
+	_, err = Convert(2, "pieces", "g", 1)
+	assert.ErrorIs(t, err, ErrIncompatible)
+}
+
+func TestKindOf(t *testing.T) {
+	assert.Equal(t, KindMass, KindOf("pounds"))
+	assert.Equal(t, KindVolume, KindOf("Tbsp"))
+	assert.Equal(t, KindCount, KindOf("pieces"))
+	assert.Equal(t, KindUnknown, KindOf("cloves"))
+}
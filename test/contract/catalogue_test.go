@@ -0,0 +1,106 @@
+package contract
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/handlers"
+	"meal-prep/services/recipe-catalogue/repository/memory"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/services/recipe-catalogue/storage"
+	"meal-prep/shared/audit"
+	"meal-prep/shared/events"
+	"meal-prep/shared/idempotency"
+	"meal-prep/shared/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+// Provider states the catalogue fixtures may refer to in "given".
+const (
+	stateDefaultCatalogue   = "the default catalogue"
+	stateRecipeOwnedByUser7 = "recipe 1 is owned by user 7 and has ingredients"
+)
+
+func TestGatewayCatalogueContract(t *testing.T) {
+	verifyContract(t, loadContract(t, "gateway-recipe-catalogue.json"), newCatalogueProvider)
+}
+
+// newCatalogueProvider wires the real catalogue handlers and services on top
+// of the in-memory repositories, seeded for the requested provider state.
+func newCatalogueProvider(t *testing.T, state string) http.Handler {
+	t.Helper()
+
+	store := memory.NewStore()
+	store.SeedDefaults()
+
+	recipeRepo := memory.NewRecipeRepository(store)
+	categoryRepo := memory.NewCategoryRepository(store)
+	ingredientRepo := memory.NewIngredientRepository(store)
+
+	switch state {
+	case stateDefaultCatalogue:
+	case stateRecipeOwnedByUser7:
+		recipe, err := recipeRepo.Create(7, models.CreateRecipeRequest{
+			Name:        "Garlic Chicken",
+			Description: "Chicken breast roasted with garlic",
+			CategoryID:  2,
+		})
+		require.NoError(t, err)
+		notes := "minced"
+		require.NoError(t, ingredientRepo.SetRecipeIngredients(recipe.ID, []models.AddRecipeIngredientRequest{
+			{IngredientID: 1, Quantity: 300, Unit: "grams"},
+			{IngredientID: 6, Quantity: 3, Unit: "cloves", Notes: &notes},
+		}))
+	default:
+		t.Fatalf("unknown provider state %q", state)
+	}
+
+	pantryEvents := service.NewPantryEvents()
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router,
+		handlers.NewRecipeHandler(service.NewRecipeService(recipeRepo, categoryRepo, ingredientRepo, memory.NewRecipeImageRepository(store), memory.NewFavoriteRepository(store), events.NewBus())),
+		handlers.NewIngredientHandler(service.NewIngredientService(ingredientRepo, recipeRepo)),
+		handlers.NewGroceryHandler(service.NewGroceryService(ingredientRepo, recipeRepo, memory.NewStoreLayoutRepository(store), memory.NewCostRepository(store),
+			memory.NewMealPlanRepository(store))),
+		handlers.NewCookingHandler(service.NewCookingService(recipeRepo, ingredientRepo,
+			memory.NewStepRepository(store), memory.NewCookingSessionRepository(store))),
+		handlers.NewRecipeImageHandler(service.NewRecipeImageService(recipeRepo, memory.NewRecipeImageRepository(store),
+			storage.NewLocalStore(t.TempDir(), "http://localhost:8000/images"))),
+		handlers.NewSocialHandler(service.NewSocialService(memory.NewFollowRepository(store), memory.NewActivityRepository(store))),
+		handlers.NewProfileHandler(service.NewProfileService(memory.NewProfileRepository(store), recipeRepo)),
+		handlers.NewLineageHandler(service.NewLineageService(memory.NewLineageRepository(store), recipeRepo,
+			memory.NewStepRepository(store), categoryRepo)),
+		handlers.NewCostHandler(service.NewCostService(memory.NewCostRepository(store), recipeRepo, ingredientRepo, events.NewBus())),
+		handlers.NewPrepHandler(service.NewPrepService(memory.NewPrepSessionRepository(store), recipeRepo, ingredientRepo,
+			memory.NewStepRepository(store))),
+		handlers.NewFeedHandler(service.NewFeedService(recipeRepo), "http://localhost:8000"),
+		handlers.NewEmbedHandler(service.NewEmbedService(recipeRepo, memory.NewRecipeImageRepository(store)), "http://localhost:8000"),
+		handlers.NewSavedSearchHandler(service.NewSavedSearchService(memory.NewSavedSearchRepository(store), recipeRepo,
+			categoryRepo, ingredientRepo, service.NewLogSearchAlertNotifier())),
+		handlers.NewQualityHandler(service.NewRecipeQualityService(memory.NewRecipeQualityRepository(store), ingredientRepo)),
+		handlers.NewIngredientCleanupHandler(service.NewIngredientCleanupService(memory.NewIngredientCleanupRepository(store), 6)),
+		handlers.NewUsageHandler(handlers.UsageMeterFromEnv()),
+		handlers.NewMealPlanHandler(service.NewMealPlanService(memory.NewMealPlanRepository(store), recipeRepo, ingredientRepo,
+			memory.NewStepRepository(store), nil)),
+		handlers.NewFavoriteHandler(service.NewFavoriteService(memory.NewFavoriteRepository(store), recipeRepo, memory.NewRecipeImageRepository(store))),
+		handlers.NewSharedListHandler(service.NewGroceryService(ingredientRepo, recipeRepo, memory.NewStoreLayoutRepository(store), memory.NewCostRepository(store),
+			memory.NewMealPlanRepository(store)), service.NewSharedListService(memory.NewSharedListRepository(store)), "http://localhost:8000"),
+		handlers.NewRecipeOwnershipHandler(service.NewRecipeOwnershipService(memory.NewRecipeOwnershipRepository(store))),
+		handlers.NewRecipeTemplateHandler(service.NewRecipeTemplateService(memory.NewRecipeTemplateRepository(store), recipeRepo,
+			memory.NewStepRepository(store), categoryRepo)),
+		handlers.NewBackfillHandler(service.NewBackfillService(memory.NewBackfillRepository(store),
+			service.NewCostService(memory.NewCostRepository(store), recipeRepo, ingredientRepo, events.NewBus()))),
+		handlers.NewRecipeImportHandler(service.NewRecipeImportService(recipeRepo, memory.NewStepRepository(store), categoryRepo, ingredientRepo,
+			handlers.NewPageFetcher())),
+		handlers.NewNutritionHandler(service.NewNutritionLookupService(nil, time.Hour, 0)),
+		handlers.NewPantryHandler(service.NewPantryService(memory.NewPantryRepository(store), ingredientRepo,
+			memory.NewRecipeImageRepository(store), memory.NewFavoriteRepository(store), pantryEvents)),
+		handlers.NewHouseholdHandler(service.NewHouseholdService(memory.NewHouseholdRepository(store), pantryEvents)),
+		audit.NewAuditor(audit.NewMemoryStore()),
+		idempotency.NewKeys(idempotency.NewMemoryStore()),
+	)
+	return router
+}
@@ -0,0 +1,226 @@
+// Package contract holds consumer-driven contract tests. Each consumer of a
+// service records the interactions it relies on as a golden fixture under
+// fixtures/, and the tests here replay them against the real provider code.
+// A failing contract test means an API change would break a consumer.
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// Contract is a golden fixture describing what a consumer expects from a provider.
+type Contract struct {
+	Consumer     string        `json:"consumer"`
+	Provider     string        `json:"provider"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Description string   `json:"description"`
+	Given       string   `json:"given"`
+	Request     Request  `json:"request"`
+	Response    Response `json:"response"`
+}
+
+// Request is sent as the gateway would forward it. When AsUser is set the
+// request carries a bearer token for that user, mirroring what Kong passes on
+// after the jwt plugin has validated it.
+type Request struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	AsUser int             `json:"as_user,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Response is matched against the provider's reply. Within Body the strings
+// "<string>", "<number>", "<timestamp>" and "<any>" match any value of that
+// kind; everything else must be equal. Keys absent from the fixture are
+// ignored, so providers may add fields without breaking consumers.
+type Response struct {
+	Status int               `json:"status"`
+	Body   interface{}       `json:"body,omitempty"`
+	Header map[string]string `json:"headers,omitempty"`
+}
+
+func TestMain(m *testing.M) {
+	os.Setenv("LOG_LEVEL", "error")
+	logging.Init("contract-test")
+	os.Exit(m.Run())
+}
+
+func loadContract(t *testing.T, name string) Contract {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("fixtures", name))
+	require.NoError(t, err)
+
+	var c Contract
+	require.NoError(t, json.Unmarshal(data, &c), "fixture %s is not valid JSON", name)
+	require.NotEmpty(t, c.Interactions, "fixture %s has no interactions", name)
+	return c
+}
+
+// verifyContract replays every interaction against a fresh provider built by
+// newProvider for the interaction's provider state.
+func verifyContract(t *testing.T, c Contract, newProvider func(t *testing.T, state string) http.Handler) {
+	for _, interaction := range c.Interactions {
+		interaction := interaction
+		t.Run(interaction.Description, func(t *testing.T) {
+			provider := newProvider(t, interaction.Given)
+
+			rec := httptest.NewRecorder()
+			provider.ServeHTTP(rec, buildRequest(t, interaction.Request))
+
+			require.Equal(t, interaction.Response.Status, rec.Code, "unexpected status, body: %s", rec.Body.String())
+
+			for key, want := range interaction.Response.Header {
+				require.Equal(t, want, rec.Header().Get(key), "header %s", key)
+			}
+
+			if interaction.Response.Body == nil {
+				return
+			}
+
+			var actual interface{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &actual), "response is not JSON: %s", rec.Body.String())
+
+			if mismatches := matchBody("$", interaction.Response.Body, actual); len(mismatches) > 0 {
+				t.Errorf("%s %s broke the %s contract:\n  %s\nbody: %s",
+					interaction.Request.Method, interaction.Request.Path, c.Consumer,
+					strings.Join(mismatches, "\n  "), rec.Body.String())
+			}
+		})
+	}
+}
+
+func buildRequest(t *testing.T, r Request) *http.Request {
+	t.Helper()
+
+	var body io.Reader
+	if len(r.Body) > 0 {
+		body = bytes.NewReader(r.Body)
+	}
+
+	req := httptest.NewRequest(r.Method, r.Path, body)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if r.AsUser != 0 {
+		req.Header.Set("Authorization", "Bearer "+gatewayToken(t, r.AsUser))
+	}
+	return req
+}
+
+// gatewayToken signs a token shaped like the ones the auth service issues.
+// The catalogue does not verify signatures (Kong already has), so any key works.
+func gatewayToken(t *testing.T, userID int) string {
+	t.Helper()
+
+	claims := middleware.Claims{
+		UserID: userID,
+		Email:  fmt.Sprintf("user%d@example.com", userID),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("contract-test"))
+	require.NoError(t, err)
+	return token
+}
+
+// matchBody compares a fixture value against the actual JSON and returns a
+// description of every mismatch, addressed by JSON path.
+func matchBody(path string, expected, actual interface{}) []string {
+	if s, ok := expected.(string); ok {
+		switch s {
+		case "<any>":
+			if actual == nil {
+				return []string{path + ": expected a value, got null"}
+			}
+			return nil
+		case "<string>":
+			if _, ok := actual.(string); !ok {
+				return []string{fmt.Sprintf("%s: expected a string, got %v", path, actual)}
+			}
+			return nil
+		case "<number>":
+			if _, ok := actual.(float64); !ok {
+				return []string{fmt.Sprintf("%s: expected a number, got %v", path, actual)}
+			}
+			return nil
+		case "<timestamp>":
+			if str, ok := actual.(string); !ok || !isTimestamp(str) {
+				return []string{fmt.Sprintf("%s: expected an RFC 3339 timestamp, got %v", path, actual)}
+			}
+			return nil
+		}
+	}
+
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an object, got %v", path, actual)}
+		}
+		keys := make([]string, 0, len(exp))
+		for k := range exp {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var mismatches []string
+		for _, k := range keys {
+			v, present := act[k]
+			if !present {
+				mismatches = append(mismatches, fmt.Sprintf("%s.%s: missing", path, k))
+				continue
+			}
+			mismatches = append(mismatches, matchBody(path+"."+k, exp[k], v)...)
+		}
+		return mismatches
+
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an array, got %v", path, actual)}
+		}
+		if len(exp) != len(act) {
+			return []string{fmt.Sprintf("%s: expected %d elements, got %d", path, len(exp), len(act))}
+		}
+		var mismatches []string
+		for i := range exp {
+			mismatches = append(mismatches, matchBody(fmt.Sprintf("%s[%d]", path, i), exp[i], act[i])...)
+		}
+		return mismatches
+
+	default:
+		if !reflect.DeepEqual(expected, actual) {
+			return []string{fmt.Sprintf("%s: expected %v, got %v", path, expected, actual)}
+		}
+		return nil
+	}
+}
+
+func isTimestamp(s string) bool {
+	_, err := time.Parse(time.RFC3339Nano, s)
+	return err == nil
+}
@@ -0,0 +1,114 @@
+package contract
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SchemaContract lists the provider tables and columns a consumer reads
+// directly from the shared database rather than through the provider's API.
+type SchemaContract struct {
+	Consumer string              `json:"consumer"`
+	Provider string              `json:"provider"`
+	Tables   map[string][]string `json:"tables"`
+}
+
+var (
+	createTablePattern  = regexp.MustCompile(`(?is)CREATE TABLE\s+(?:IF NOT EXISTS\s+)?([\w.]+)\s*\((.*?)\n\s*\);`)
+	addColumnPattern    = regexp.MustCompile(`(?is)ALTER TABLE\s+([\w.]+)\s+ADD COLUMN\s+(?:IF NOT EXISTS\s+)?(\w+)`)
+	dropColumnPattern   = regexp.MustCompile(`(?is)ALTER TABLE\s+([\w.]+)\s+DROP COLUMN\s+(?:IF EXISTS\s+)?(\w+)`)
+	renameColumnPattern = regexp.MustCompile(`(?is)ALTER TABLE\s+([\w.]+)\s+RENAME COLUMN\s+(\w+)\s+TO\s+(\w+)`)
+)
+
+func TestRecommendationsCatalogueSchemaContract(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("fixtures", "recommendations-recipe-catalogue.json"))
+	require.NoError(t, err)
+
+	var contract SchemaContract
+	require.NoError(t, json.Unmarshal(data, &contract))
+
+	columns := migratedColumns(t, filepath.Join("..", "..", "migrations", "recipe-catalogue"))
+
+	for table, wanted := range contract.Tables {
+		have, ok := columns[table]
+		if !assert.True(t, ok, "%s reads %s, which the %s migrations no longer create", contract.Consumer, table, contract.Provider) {
+			continue
+		}
+		for _, column := range wanted {
+			assert.True(t, have[column], "%s reads %s.%s, which the %s migrations no longer provide", contract.Consumer, table, column, contract.Provider)
+		}
+	}
+}
+
+// migratedColumns replays the Flyway migrations in version order and returns
+// the resulting columns of every table they create.
+func migratedColumns(t *testing.T, dir string) map[string]map[string]bool {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "V*.sql"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files, "no migrations found in %s", dir)
+	sort.Slice(files, func(i, j int) bool { return migrationVersion(files[i]) < migrationVersion(files[j]) })
+
+	tables := make(map[string]map[string]bool)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		sql := string(data)
+
+		for _, m := range createTablePattern.FindAllStringSubmatch(sql, -1) {
+			cols := make(map[string]bool)
+			for _, line := range strings.Split(m[2], "\n") {
+				fields := strings.Fields(strings.TrimSpace(line))
+				if len(fields) == 0 || isConstraintKeyword(fields[0]) {
+					continue
+				}
+				cols[strings.ToLower(fields[0])] = true
+			}
+			tables[strings.ToLower(m[1])] = cols
+		}
+		for _, m := range addColumnPattern.FindAllStringSubmatch(sql, -1) {
+			if cols, ok := tables[strings.ToLower(m[1])]; ok {
+				cols[strings.ToLower(m[2])] = true
+			}
+		}
+		for _, m := range dropColumnPattern.FindAllStringSubmatch(sql, -1) {
+			delete(tables[strings.ToLower(m[1])], strings.ToLower(m[2]))
+		}
+		for _, m := range renameColumnPattern.FindAllStringSubmatch(sql, -1) {
+			if cols, ok := tables[strings.ToLower(m[1])]; ok {
+				delete(cols, strings.ToLower(m[2]))
+				cols[strings.ToLower(m[3])] = true
+			}
+		}
+	}
+	return tables
+}
+
+func migrationVersion(path string) int {
+	name := filepath.Base(path)
+	version := 0
+	for _, r := range strings.TrimPrefix(name, "V") {
+		if r < '0' || r > '9' {
+			break
+		}
+		version = version*10 + int(r-'0')
+	}
+	return version
+}
+
+func isConstraintKeyword(word string) bool {
+	switch strings.ToUpper(strings.TrimSuffix(word, ",")) {
+	case "CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "EXCLUDE":
+		return true
+	}
+	return false
+}
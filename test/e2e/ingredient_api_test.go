@@ -7,6 +7,7 @@ import (
 	"meal-prep/services/recipe-catalogue/repository"
 	"meal-prep/services/recipe-catalogue/service"
 	"meal-prep/shared/middleware"
+	"meal-prep/shared/seed"
 	"meal-prep/test/helpers"
 	"net/http"
 	"net/http/httptest"
@@ -36,7 +37,8 @@ func (suite *IngredientE2ETestSuite) SetupSuite() {
 	ingredientRepo := repository.NewIngredientRepository(suite.testDB.DB)
 
 	ingredientService := service.NewIngredientService(ingredientRepo, recipeRepo)
-	groceryService := service.NewGroceryService(ingredientRepo, recipeRepo)
+	groceryService := service.NewGroceryService(ingredientRepo, recipeRepo, repository.NewStoreLayoutRepository(suite.testDB.DB), repository.NewCostRepository(suite.testDB.DB),
+		repository.NewMealPlanRepository(suite.testDB.DB))
 
 	ingredientHandler := handlers.NewIngredientHandler(ingredientService)
 	groceryHandler := handlers.NewGroceryHandler(groceryService)
@@ -93,68 +95,36 @@ func (suite *IngredientE2ETestSuite) SetupTest() {
 }
 
 func (suite *IngredientE2ETestSuite) seedTestData() {
-	// Insert test categories
-	categoriesSQL := `
-		INSERT INTO recipe_catalogue.categories (name, description) VALUES
-			('Vegetables', 'Fresh vegetables'),
-			('Meat', 'Various meats'),
-			('Fish', 'Fish and seafood'),
-			('Dairy', 'Dairy products')
-		ON CONFLICT (name) DO NOTHING;
-	`
-	if _, err := suite.testDB.DB.Exec(categoriesSQL); err != nil {
-		suite.T().Fatalf("Failed to seed categories: %v", err)
-	}
-
-	// Insert test ingredients
-	ingredientsSQL := `
-		INSERT INTO recipe_catalogue.ingredients (name, category) VALUES
-			('Tomato', 'Vegetables'),
-			('Chicken Breast', 'Meat'),
-			('Mozzarella', 'Dairy'),
-			('Basil', 'Vegetables'),
-			('Onion', 'Vegetables'),
-			('Lettuce', 'Vegetables')
-		ON CONFLICT (name) DO NOTHING;
-	`
-	if _, err := suite.testDB.DB.Exec(ingredientsSQL); err != nil {
-		suite.T().Fatalf("Failed to seed ingredients: %v", err)
-	}
-
-	// Insert test recipes
-	recipesSQL := `
-		INSERT INTO recipe_catalogue.recipes (name, description, category_id)
-		SELECT 'Caprese Salad', 'Fresh tomato and mozzarella salad', c.id
-		FROM recipe_catalogue.categories c WHERE c.name = 'Vegetables'
-		UNION ALL
-		SELECT 'Grilled Chicken', 'Simple grilled chicken breast', c.id
-		FROM recipe_catalogue.categories c WHERE c.name = 'Meat'
-		UNION ALL
-		SELECT 'Grilled Salmon', 'Simple grilled salmon fillet', c.id
-		FROM recipe_catalogue.categories c WHERE c.name = 'Fish';
-	`
-	if _, err := suite.testDB.DB.Exec(recipesSQL); err != nil {
-		suite.T().Fatalf("Failed to seed recipes: %v", err)
-	}
-
-	// Insert recipe-ingredient relationships
-	recipeIngredientsSQL := `
-		INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes)
-		SELECT r.id, i.id, 2.0, 'piece', 'large tomatoes'
-		FROM recipe_catalogue.recipes r, recipe_catalogue.ingredients i
-		WHERE r.name = 'Caprese Salad' AND i.name = 'Tomato'
-		UNION ALL
-		SELECT r.id, i.id, 150.0, 'grams', 'fresh mozzarella'
-		FROM recipe_catalogue.recipes r, recipe_catalogue.ingredients i
-		WHERE r.name = 'Caprese Salad' AND i.name = 'Mozzarella'
-		UNION ALL
-		SELECT r.id, i.id, 200.0, 'grams', 'boneless skinless'
-		FROM recipe_catalogue.recipes r, recipe_catalogue.ingredients i
-		WHERE r.name = 'Grilled Chicken' AND i.name = 'Chicken Breast'
-		ON CONFLICT (recipe_id, ingredient_id) DO NOTHING;
-	`
-	if _, err := suite.testDB.DB.Exec(recipeIngredientsSQL); err != nil {
-		suite.T().Fatalf("Failed to seed recipe ingredients: %v", err)
+	err := seed.Run(suite.testDB.DB, seed.Dataset{
+		Categories: []seed.Category{
+			{Name: "Vegetables", Description: "Fresh vegetables"},
+			{Name: "Meat", Description: "Various meats"},
+			{Name: "Fish", Description: "Fish and seafood"},
+			{Name: "Dairy", Description: "Dairy products"},
+		},
+		Ingredients: []seed.Ingredient{
+			{Name: "Tomato", Category: "Vegetables"},
+			{Name: "Chicken Breast", Category: "Meat"},
+			{Name: "Mozzarella", Category: "Dairy"},
+			{Name: "Basil", Category: "Vegetables"},
+			{Name: "Onion", Category: "Vegetables"},
+			{Name: "Lettuce", Category: "Vegetables"},
+		},
+		Recipes: []seed.Recipe{
+			{Name: "Caprese Salad", Description: "Fresh tomato and mozzarella salad", Category: "Vegetables",
+				Ingredients: []seed.RecipeIngredient{
+					{Ingredient: "Tomato", Quantity: 2, Unit: "piece", Notes: "large tomatoes"},
+					{Ingredient: "Mozzarella", Quantity: 150, Unit: "grams", Notes: "fresh mozzarella"},
+				}},
+			{Name: "Grilled Chicken", Description: "Simple grilled chicken breast", Category: "Meat",
+				Ingredients: []seed.RecipeIngredient{
+					{Ingredient: "Chicken Breast", Quantity: 200, Unit: "grams", Notes: "boneless skinless"},
+				}},
+			{Name: "Grilled Salmon", Description: "Simple grilled salmon fillet", Category: "Fish"},
+		},
+	})
+	if err != nil {
+		suite.T().Fatalf("Failed to seed test data: %v", err)
 	}
 }
 
@@ -6,7 +6,9 @@ import (
 	"meal-prep/services/recipe-catalogue/handlers"
 	"meal-prep/services/recipe-catalogue/repository"
 	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/events"
 	"meal-prep/shared/middleware"
+	"meal-prep/shared/seed"
 	"meal-prep/test/helpers"
 	"net/http"
 	"net/http/httptest"
@@ -35,7 +37,8 @@ func (suite *RecipeE2ETestSuite) SetupSuite() {
 	categoryRepo := repository.NewCategoryRepository(suite.testDB.DB)
 	ingredientRepo := repository.NewIngredientRepository(suite.testDB.DB)
 
-	recipeService := service.NewRecipeService(recipeRepo, categoryRepo, ingredientRepo)
+	recipeService := service.NewRecipeService(recipeRepo, categoryRepo, ingredientRepo, repository.NewRecipeImageRepository(suite.testDB.DB),
+		repository.NewFavoriteRepository(suite.testDB.DB), events.NewBus())
 	recipeHandler := handlers.NewRecipeHandler(recipeService)
 
 	// Setup HTTP server with recipe routes only
@@ -86,91 +89,42 @@ func (suite *RecipeE2ETestSuite) SetupTest() {
 
 // seedTestData creates test categories and ingredients for recipe tests
 func (suite *RecipeE2ETestSuite) seedTestData() {
-	// Insert test categories
-	categoriesSQL := `
-		INSERT INTO recipe_catalogue.categories (name, description) VALUES
-			('Meat', 'Meat-based dishes'),
-			('Chicken', 'Chicken dishes'), 
-			('Fish', 'Fish and seafood'),
-			('Vegetarian', 'Vegetarian dishes'),
-			('Desserts', 'Sweet treats')
-		ON CONFLICT (name) DO NOTHING;
-	`
-
-	if _, err := suite.testDB.DB.Exec(categoriesSQL); err != nil {
-		suite.T().Fatalf("Failed to seed test categories: %v", err)
-	}
-
-	// Insert test recipes using subqueries to get actual category IDs
-	recipesSQL := `
-		INSERT INTO recipe_catalogue.recipes (name, description, category_id) VALUES
-			('Test Pasta Carbonara', 'Classic Italian pasta with eggs and cheese', 
-			 (SELECT id FROM recipe_catalogue.categories WHERE name = 'Meat')),
-			('Test Grilled Chicken', 'Simple grilled chicken breast', 
-			 (SELECT id FROM recipe_catalogue.categories WHERE name = 'Chicken')),
-			('Test Salmon Teriyaki', 'Glazed salmon with teriyaki sauce', 
-			 (SELECT id FROM recipe_catalogue.categories WHERE name = 'Fish')),
-			('Test Veggie Stir Fry', 'Mixed vegetables stir fried', 
-			 (SELECT id FROM recipe_catalogue.categories WHERE name = 'Vegetarian')),
-			('Test Chocolate Cake', 'Rich chocolate layer cake', 
-			 (SELECT id FROM recipe_catalogue.categories WHERE name = 'Desserts'))
-		ON CONFLICT DO NOTHING;
-	`
-
-	if _, err := suite.testDB.DB.Exec(recipesSQL); err != nil {
-		suite.T().Fatalf("Failed to seed test recipes: %v", err)
-	}
-
-	// Insert test ingredients
-	ingredientsSQL := `
-		INSERT INTO recipe_catalogue.ingredients (name, description, category) VALUES
-			('Test Chicken Breast', 'Boneless skinless chicken breast', 'Meat'),
-			('Test Ground Beef', 'Lean ground beef', 'Meat'),
-			('Test Salmon Fillet', 'Fresh salmon fillet', 'Fish'),
-			('Test Pasta', 'Dry pasta noodles', 'Grains'),
-			('Test Rice', 'Long grain rice', 'Grains'),
-			('Test Onion', 'Yellow onion', 'Vegetables'),
-			('Test Garlic', 'Fresh garlic cloves', 'Vegetables'),
-			('Test Tomato', 'Fresh tomatoes', 'Vegetables'),
-			('Test Cheese', 'Grated parmesan', 'Dairy'),
-			('Test Olive Oil', 'Extra virgin olive oil', 'Oils')
-		ON CONFLICT (name) DO NOTHING;
-	`
-
-	if _, err := suite.testDB.DB.Exec(ingredientsSQL); err != nil {
-		suite.T().Fatalf("Failed to seed test ingredients: %v", err)
-	}
-
-	// Insert test recipe-ingredient relationships
-	recipeIngredientsSQL := `
-		INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes)
-		SELECT r.id, i.id, 100.0, 'grams', 'test ingredient'
-		FROM recipe_catalogue.recipes r, recipe_catalogue.ingredients i
-		WHERE r.name = 'Test Pasta Carbonara' AND i.name = 'Test Pasta'
-		UNION ALL
-		SELECT r.id, i.id, 200.0, 'grams', 'test ingredient'  
-		FROM recipe_catalogue.recipes r, recipe_catalogue.ingredients i
-		WHERE r.name = 'Test Grilled Chicken' AND i.name = 'Test Chicken Breast'
-		UNION ALL
-		SELECT r.id, i.id, 150.0, 'grams', 'test ingredient'
-		FROM recipe_catalogue.recipes r, recipe_catalogue.ingredients i
-		WHERE r.name = 'Test Salmon Teriyaki' AND i.name = 'Test Salmon Fillet'
-		ON CONFLICT (recipe_id, ingredient_id) DO NOTHING;
-	`
-
-	if _, err := suite.testDB.DB.Exec(recipeIngredientsSQL); err != nil {
-		suite.T().Fatalf("Failed to seed test recipe ingredients: %v", err)
-	}
-
-	// Insert test user for auth tests
-	userSQL := `
-		INSERT INTO auth.users (email, password_hash) VALUES
-			('test@example.com', '$2a$10$test.hash.for.e2e.testing')
-		ON CONFLICT (email) DO NOTHING;
-	`
-
-	if _, err := suite.testDB.DB.Exec(userSQL); err != nil {
-		suite.T().Fatalf("Failed to seed test user: %v", err)
+	err := seed.Run(suite.testDB.DB, seed.Dataset{
+		Users: []seed.User{
+			{Email: "test@example.com", PasswordHash: "$2a$10$test.hash.for.e2e.testing"},
+		},
+		Categories: []seed.Category{
+			{Name: "Meat", Description: "Meat-based dishes"},
+			{Name: "Chicken", Description: "Chicken dishes"},
+			{Name: "Fish", Description: "Fish and seafood"},
+			{Name: "Vegetarian", Description: "Vegetarian dishes"},
+			{Name: "Desserts", Description: "Sweet treats"},
+		},
+		Ingredients: []seed.Ingredient{
+			{Name: "Test Chicken Breast", Description: "Boneless skinless chicken breast", Category: "Meat"},
+			{Name: "Test Ground Beef", Description: "Lean ground beef", Category: "Meat"},
+			{Name: "Test Salmon Fillet", Description: "Fresh salmon fillet", Category: "Fish"},
+			{Name: "Test Pasta", Description: "Dry pasta noodles", Category: "Grains"},
+			{Name: "Test Rice", Description: "Long grain rice", Category: "Grains"},
+			{Name: "Test Onion", Description: "Yellow onion", Category: "Vegetables"},
+			{Name: "Test Garlic", Description: "Fresh garlic cloves", Category: "Vegetables"},
+			{Name: "Test Tomato", Description: "Fresh tomatoes", Category: "Vegetables"},
+			{Name: "Test Cheese", Description: "Grated parmesan", Category: "Dairy"},
+			{Name: "Test Olive Oil", Description: "Extra virgin olive oil", Category: "Oils"},
+		},
+		Recipes: []seed.Recipe{
+			{Name: "Test Pasta Carbonara", Description: "Classic Italian pasta with eggs and cheese", Category: "Meat",
+				Ingredients: []seed.RecipeIngredient{{Ingredient: "Test Pasta", Quantity: 100, Unit: "grams", Notes: "test ingredient"}}},
+			{Name: "Test Grilled Chicken", Description: "Simple grilled chicken breast", Category: "Chicken",
+				Ingredients: []seed.RecipeIngredient{{Ingredient: "Test Chicken Breast", Quantity: 200, Unit: "grams", Notes: "test ingredient"}}},
+			{Name: "Test Salmon Teriyaki", Description: "Glazed salmon with teriyaki sauce", Category: "Fish",
+				Ingredients: []seed.RecipeIngredient{{Ingredient: "Test Salmon Fillet", Quantity: 150, Unit: "grams", Notes: "test ingredient"}}},
+			{Name: "Test Veggie Stir Fry", Description: "Mixed vegetables stir fried", Category: "Vegetarian"},
+			{Name: "Test Chocolate Cake", Description: "Rich chocolate layer cake", Category: "Desserts"},
+		},
+	})
+	if err != nil {
+		suite.T().Fatalf("Failed to seed test data: %v", err)
 	}
 }
 
@@ -6,7 +6,9 @@ import (
 	"meal-prep/services/recipe-catalogue/handlers"
 	"meal-prep/services/recipe-catalogue/repository"
 	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/shared/events"
 	"meal-prep/shared/middleware"
+	"meal-prep/shared/seed"
 	"meal-prep/test/helpers"
 	"net/http"
 	"net/http/httptest"
@@ -36,9 +38,11 @@ func (suite *RecipeIngredientsE2ETestSuite) SetupSuite() {
 	categoryRepo := repository.NewCategoryRepository(suite.testDB.DB)
 	ingredientRepo := repository.NewIngredientRepository(suite.testDB.DB)
 
-	recipeService := service.NewRecipeService(recipeRepo, categoryRepo, ingredientRepo)
+	recipeService := service.NewRecipeService(recipeRepo, categoryRepo, ingredientRepo, repository.NewRecipeImageRepository(suite.testDB.DB),
+		repository.NewFavoriteRepository(suite.testDB.DB), events.NewBus())
 	ingredientService := service.NewIngredientService(ingredientRepo, recipeRepo)
-	groceryService := service.NewGroceryService(ingredientRepo, recipeRepo)
+	groceryService := service.NewGroceryService(ingredientRepo, recipeRepo, repository.NewStoreLayoutRepository(suite.testDB.DB), repository.NewCostRepository(suite.testDB.DB),
+		repository.NewMealPlanRepository(suite.testDB.DB))
 
 	recipeHandler := handlers.NewRecipeHandler(recipeService)
 	ingredientHandler := handlers.NewIngredientHandler(ingredientService)
@@ -103,87 +107,40 @@ func (suite *RecipeIngredientsE2ETestSuite) SetupTest() {
 }
 
 func (suite *RecipeIngredientsE2ETestSuite) seedTestData() {
-	// Insert test categories
-	categoriesSQL := `
-		INSERT INTO recipe_catalogue.categories (name, description) VALUES
-			('Meat', 'Meat-based dishes'),
-			('Chicken', 'Chicken dishes'),
-			('Fish', 'Fish and seafood'),
-			('Desserts', 'Sweet treats')
-		ON CONFLICT (name) DO NOTHING;
-	`
-	if _, err := suite.testDB.DB.Exec(categoriesSQL); err != nil {
-		suite.T().Fatalf("Failed to seed categories: %v", err)
-	}
-
-	// Insert test recipes using subqueries to get actual category IDs
-	recipesSQL := `
-		INSERT INTO recipe_catalogue.recipes (name, description, category_id) VALUES
-			('Pasta Carbonara', 'Classic Italian pasta with eggs and cheese', 
-			 (SELECT id FROM recipe_catalogue.categories WHERE name = 'Meat')),
-			('Grilled Chicken', 'Simple grilled chicken breast', 
-			 (SELECT id FROM recipe_catalogue.categories WHERE name = 'Chicken')),
-			('Salmon Teriyaki', 'Glazed salmon with teriyaki sauce', 
-			 (SELECT id FROM recipe_catalogue.categories WHERE name = 'Fish')),
-			('Chocolate Cake', 'Rich chocolate layer cake', 
-			 (SELECT id FROM recipe_catalogue.categories WHERE name = 'Desserts'))
-		ON CONFLICT DO NOTHING;
-	`
-
-	if _, err := suite.testDB.DB.Exec(recipesSQL); err != nil {
-		suite.T().Fatalf("Failed to seed test recipes: %v", err)
-	}
-
-	// Insert test ingredients
-	ingredientsSQL := `
-		INSERT INTO recipe_catalogue.ingredients (name, description, category) VALUES
-			('Chicken Breast', 'Boneless skinless chicken breast', 'Meat'),
-			('Ground Beef', 'Lean ground beef', 'Meat'),
-			('Salmon Fillet', 'Fresh salmon fillet', 'Fish'),
-			('Pasta', 'Dry pasta noodles', 'Grains'),
-			('Rice', 'Long grain rice', 'Grains'),
-			('Onion', 'Yellow onion', 'Vegetables'),
-			('Garlic', 'Fresh garlic cloves', 'Vegetables'),
-			('Tomato', 'Fresh tomatoes', 'Vegetables'),
-			('Cheese', 'Grated parmesan', 'Dairy'),
-			('Olive Oil', 'Extra virgin olive oil', 'Oils')
-		ON CONFLICT (name) DO NOTHING;
-	`
-
-	if _, err := suite.testDB.DB.Exec(ingredientsSQL); err != nil {
-		suite.T().Fatalf("Failed to seed test ingredients: %v", err)
-	}
-
-	// Insert test recipe-ingredient relationships
-	recipeIngredientsSQL := `
-		INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit, notes)
-		SELECT r.id, i.id, 100.0, 'grams', 'test ingredient'
-		FROM recipe_catalogue.recipes r, recipe_catalogue.ingredients i
-		WHERE r.name = 'Pasta Carbonara' AND i.name = 'Pasta'
-		UNION ALL
-		SELECT r.id, i.id, 200.0, 'grams', 'test ingredient'  
-		FROM recipe_catalogue.recipes r, recipe_catalogue.ingredients i
-		WHERE r.name = 'Grilled Chicken' AND i.name = 'Chicken Breast'
-		UNION ALL
-		SELECT r.id, i.id, 150.0, 'grams', 'test ingredient'
-		FROM recipe_catalogue.recipes r, recipe_catalogue.ingredients i
-		WHERE r.name = 'Salmon Teriyaki' AND i.name = 'Salmon Fillet'
-		ON CONFLICT (recipe_id, ingredient_id) DO NOTHING;
-	`
-
-	if _, err := suite.testDB.DB.Exec(recipeIngredientsSQL); err != nil {
-		suite.T().Fatalf("Failed to seed test recipe ingredients: %v", err)
-	}
-
-	// Insert test user for auth tests
-	userSQL := `
-		INSERT INTO auth.users (email, password_hash) VALUES
-			('test@example.com', '$2a$10$test.hash.for.e2e.testing')
-		ON CONFLICT (email) DO NOTHING;
-	`
-
-	if _, err := suite.testDB.DB.Exec(userSQL); err != nil {
-		suite.T().Fatalf("Failed to seed test user: %v", err)
+	err := seed.Run(suite.testDB.DB, seed.Dataset{
+		Users: []seed.User{
+			{Email: "test@example.com", PasswordHash: "$2a$10$test.hash.for.e2e.testing"},
+		},
+		Categories: []seed.Category{
+			{Name: "Meat", Description: "Meat-based dishes"},
+			{Name: "Chicken", Description: "Chicken dishes"},
+			{Name: "Fish", Description: "Fish and seafood"},
+			{Name: "Desserts", Description: "Sweet treats"},
+		},
+		Ingredients: []seed.Ingredient{
+			{Name: "Chicken Breast", Description: "Boneless skinless chicken breast", Category: "Meat"},
+			{Name: "Ground Beef", Description: "Lean ground beef", Category: "Meat"},
+			{Name: "Salmon Fillet", Description: "Fresh salmon fillet", Category: "Fish"},
+			{Name: "Pasta", Description: "Dry pasta noodles", Category: "Grains"},
+			{Name: "Rice", Description: "Long grain rice", Category: "Grains"},
+			{Name: "Onion", Description: "Yellow onion", Category: "Vegetables"},
+			{Name: "Garlic", Description: "Fresh garlic cloves", Category: "Vegetables"},
+			{Name: "Tomato", Description: "Fresh tomatoes", Category: "Vegetables"},
+			{Name: "Cheese", Description: "Grated parmesan", Category: "Dairy"},
+			{Name: "Olive Oil", Description: "Extra virgin olive oil", Category: "Oils"},
+		},
+		Recipes: []seed.Recipe{
+			{Name: "Pasta Carbonara", Description: "Classic Italian pasta with eggs and cheese", Category: "Meat",
+				Ingredients: []seed.RecipeIngredient{{Ingredient: "Pasta", Quantity: 100, Unit: "grams", Notes: "test ingredient"}}},
+			{Name: "Grilled Chicken", Description: "Simple grilled chicken breast", Category: "Chicken",
+				Ingredients: []seed.RecipeIngredient{{Ingredient: "Chicken Breast", Quantity: 200, Unit: "grams", Notes: "test ingredient"}}},
+			{Name: "Salmon Teriyaki", Description: "Glazed salmon with teriyaki sauce", Category: "Fish",
+				Ingredients: []seed.RecipeIngredient{{Ingredient: "Salmon Fillet", Quantity: 150, Unit: "grams", Notes: "test ingredient"}}},
+			{Name: "Chocolate Cake", Description: "Rich chocolate layer cake", Category: "Desserts"},
+		},
+	})
+	if err != nil {
+		suite.T().Fatalf("Failed to seed test data: %v", err)
 	}
 }
 
@@ -28,7 +28,7 @@ type TestDatabase struct {
 }
 
 // SetupPostgresContainer starts a PostgreSQL container for integration tests
-func SetupPostgresContainer(t *testing.T) *TestDatabase {
+func SetupPostgresContainer(t testing.TB) *TestDatabase {
 	t.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
 
 	ctx := context.Background()
@@ -73,7 +73,7 @@ func SetupPostgresContainer(t *testing.T) *TestDatabase {
 }
 
 // Cleanup terminates the test database
-func (td *TestDatabase) Cleanup(t *testing.T) {
+func (td *TestDatabase) Cleanup(t testing.TB) {
 	ctx := context.Background()
 
 	if td.DB != nil {
@@ -88,16 +88,61 @@ func (td *TestDatabase) Cleanup(t *testing.T) {
 }
 
 // CleanupTestData removes all test data from database tables
-func (td *TestDatabase) CleanupTestData(t *testing.T) {
+func (td *TestDatabase) CleanupTestData(t testing.TB) {
 	queries := []string{
+		"DELETE FROM auth.refresh_tokens",
 		"DELETE FROM auth.users",
 		"DELETE FROM recipe_catalogue.recipe_ingredients",
+		"DELETE FROM recipe_catalogue.ingredient_products",
+		"DELETE FROM recipe_catalogue.ingredient_densities",
+		"DELETE FROM recipe_catalogue.ingredient_pack_sizes",
+		"DELETE FROM recipe_catalogue.ingredient_prices",
+		"DELETE FROM recipe_catalogue.ingredient_substitutions",
+		"DELETE FROM recipe_catalogue.ingredient_dietary_flags",
+		"DELETE FROM recipe_catalogue.ingredient_allergens",
+		"DELETE FROM recipe_catalogue.ingredient_aliases",
+		"DELETE FROM recipe_catalogue.ingredient_diet_overrides",
+		"DELETE FROM recipe_catalogue.audit_log",
+		"DELETE FROM recipe_catalogue.idempotency_keys",
+		"DELETE FROM recipe_catalogue.meal_plan_entries",
+		"DELETE FROM recipe_catalogue.meal_plan_members",
+		"DELETE FROM recipe_catalogue.meal_plans",
+		"DELETE FROM recipe_catalogue.meal_plan_preferences",
+		"DELETE FROM recipe_catalogue.prep_session_recipes",
+		"DELETE FROM recipe_catalogue.prep_sessions",
+		"DELETE FROM recipe_catalogue.recipe_cost_snapshots",
+		"DELETE FROM recipe_catalogue.shared_shopping_list_items",
+		"DELETE FROM recipe_catalogue.shared_shopping_lists",
+		"DELETE FROM recipe_catalogue.store_layout_aisles",
+		"DELETE FROM recipe_catalogue.store_layouts",
+		"DELETE FROM recipe_catalogue.cooking_sessions",
+		"DELETE FROM recipe_catalogue.user_profiles",
+		"DELETE FROM recipe_catalogue.recipe_lineage",
+		"DELETE FROM recipe_catalogue.recipe_templates",
+		"DELETE FROM recipe_catalogue.activities",
+		"DELETE FROM recipe_catalogue.user_follows",
+		"DELETE FROM recipe_catalogue.user_favorites",
+		"DELETE FROM recipe_catalogue.pantry_items",
+		"DELETE FROM recipe_catalogue.pantries",
+		"DELETE FROM recipe_catalogue.household_members",
+		"DELETE FROM recipe_catalogue.households",
+		"DELETE FROM recipe_catalogue.recipe_images",
+		"DELETE FROM recipe_catalogue.recipe_techniques",
+		"DELETE FROM recipe_catalogue.recipe_step_timers",
+		"DELETE FROM recipe_catalogue.recipe_step_ingredients",
+		"DELETE FROM recipe_catalogue.recipe_steps",
 		"DELETE FROM recipe_catalogue.recipes",
 		"DELETE FROM recipe_catalogue.ingredients",
 		"DELETE FROM recipe_catalogue.categories",
 		"DELETE FROM recommendations.cooking_history",
 		"DELETE FROM recommendations.user_preferences",
 		"DELETE FROM recommendations.recommendation_history",
+		"DELETE FROM recommendations.recommendation_slate_items",
+		"DELETE FROM recommendations.recommendation_slates",
+		"DELETE FROM recommendations.digest_subscriptions",
+		"DELETE FROM recommendations.waste_log",
+		"DELETE FROM recommendations.freezer_items",
+		"DELETE FROM recommendations.recipe_similarities",
 	}
 
 	for _, query := range queries {
@@ -120,10 +165,21 @@ func initializeTestSchema(db *database.DB) error {
 			id SERIAL PRIMARY KEY,
 			email VARCHAR(255) UNIQUE NOT NULL,
 			password_hash VARCHAR(255) NOT NULL,
+			role VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin')),
+			region VARCHAR(32) NOT NULL DEFAULT '',
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		);
 
+		CREATE TABLE IF NOT EXISTS auth.refresh_tokens (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+			token_hash CHAR(64) NOT NULL UNIQUE,
+			expires_at TIMESTAMP NOT NULL,
+			revoked_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
 		-- Recipe catalogue tables
 		CREATE TABLE IF NOT EXISTS recipe_catalogue.categories (
 			id SERIAL PRIMARY KEY,
@@ -142,7 +198,11 @@ func initializeTestSchema(db *database.DB) error {
 			description TEXT,
 			category_id INTEGER REFERENCES recipe_catalogue.categories(id),
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			status VARCHAR(20) NOT NULL DEFAULT 'published' CHECK (status IN ('draft', 'private', 'published', 'archived')),
+			difficulty VARCHAR(10) CHECK (difficulty IN ('easy', 'medium', 'hard')),
+			total_time_minutes INTEGER CHECK (total_time_minutes >= 0),
+			version INTEGER NOT NULL DEFAULT 1
 		);
 
 		CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredients (
@@ -152,11 +212,28 @@ func initializeTestSchema(db *database.DB) error {
 			category VARCHAR(50) NOT NULL,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			last_used_at TIMESTAMP,
+			archived_at TIMESTAMP,
 			-- Basic constraints
 			CONSTRAINT ingredients_name_not_empty CHECK (length(trim(name)) > 0),
 			CONSTRAINT ingredients_category_valid CHECK (category IN ('Meat', 'Vegetables', 'Dairy', 'Grains', 'Spices', 'Oils', 'Fish', 'Fruits'))
 		);
 
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_products (
+			id SERIAL PRIMARY KEY,
+			ingredient_id INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients(id) ON DELETE CASCADE,
+			brand VARCHAR(100) NOT NULL,
+			name VARCHAR(100) NOT NULL,
+			calories_kcal NUMERIC(6,1) NOT NULL,
+			protein_g NUMERIC(5,1) NOT NULL,
+			carbs_g NUMERIC(5,1) NOT NULL,
+			fat_g NUMERIC(5,1) NOT NULL,
+			fiber_g NUMERIC(5,1) NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			CONSTRAINT ingredient_products_nutrition_not_negative CHECK (calories_kcal >= 0 AND protein_g >= 0 AND carbs_g >= 0 AND fat_g >= 0 AND fiber_g >= 0),
+			CONSTRAINT ingredient_products_unique UNIQUE (ingredient_id, brand, name)
+		);
+
 		CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_ingredients (
 			id SERIAL PRIMARY KEY,
 			recipe_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipes(id) ON DELETE CASCADE,
@@ -165,17 +242,394 @@ func initializeTestSchema(db *database.DB) error {
 			unit VARCHAR(20) NOT NULL,
 			notes TEXT,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			product_id INTEGER REFERENCES recipe_catalogue.ingredient_products(id) ON DELETE SET NULL,
 			-- Business constraints
 			CONSTRAINT recipe_ingredients_quantity_positive CHECK (quantity > 0),
 			CONSTRAINT recipe_ingredients_unit_not_empty CHECK (length(trim(unit)) > 0),
 			CONSTRAINT recipe_ingredients_unique_per_recipe UNIQUE (recipe_id, ingredient_id)
 		);
 
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_densities (
+			ingredient_id INTEGER PRIMARY KEY REFERENCES recipe_catalogue.ingredients(id) ON DELETE CASCADE,
+			grams_per_ml NUMERIC(8,4) NOT NULL CHECK (grams_per_ml > 0),
+			source TEXT,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_pack_sizes (
+			id SERIAL PRIMARY KEY,
+			ingredient_id INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients(id) ON DELETE CASCADE,
+			quantity DECIMAL(8,2) NOT NULL CHECK (quantity > 0),
+			unit VARCHAR(20) NOT NULL,
+			label VARCHAR(50),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			CONSTRAINT ingredient_pack_sizes_unique UNIQUE (ingredient_id, quantity, unit)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.store_layouts (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			name VARCHAR(100) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			CONSTRAINT store_layouts_unique_name_per_user UNIQUE (user_id, name)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.store_layout_aisles (
+			layout_id INTEGER NOT NULL REFERENCES recipe_catalogue.store_layouts(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			category VARCHAR(50) NOT NULL,
+			PRIMARY KEY (layout_id, position),
+			CONSTRAINT store_layout_aisles_unique_category UNIQUE (layout_id, category)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.shared_shopping_lists (
+			token VARCHAR(36) PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			version INTEGER DEFAULT 0 NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.shared_shopping_list_items (
+			token VARCHAR(36) NOT NULL REFERENCES recipe_catalogue.shared_shopping_lists(token) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			ingredient_id INTEGER NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			category VARCHAR(100),
+			quantity DOUBLE PRECISION NOT NULL,
+			unit VARCHAR(50) NOT NULL,
+			note TEXT,
+			checked BOOLEAN DEFAULT FALSE NOT NULL,
+			checked_at TIMESTAMP,
+			PRIMARY KEY (token, position)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_steps (
+			id SERIAL PRIMARY KEY,
+			recipe_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipes(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			instruction TEXT NOT NULL,
+			duration_minutes INTEGER CHECK (duration_minutes > 0),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			CONSTRAINT recipe_steps_unique_position UNIQUE (recipe_id, position)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_step_ingredients (
+			step_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipe_steps(id) ON DELETE CASCADE,
+			ingredient_id INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients(id) ON DELETE CASCADE,
+			PRIMARY KEY (step_id, ingredient_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_step_timers (
+			step_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipe_steps(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			label VARCHAR(100) NOT NULL,
+			duration_seconds INTEGER NOT NULL CHECK (duration_seconds > 0),
+			PRIMARY KEY (step_id, position)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_techniques (
+			recipe_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipes(id) ON DELETE CASCADE,
+			technique VARCHAR(30) NOT NULL CHECK (technique IN ('knife_work', 'sauteing', 'boiling', 'roasting', 'grilling', 'braising', 'poaching', 'steaming', 'deep_frying', 'emulsifying', 'baking', 'bread_making', 'pastry', 'fermenting')),
+			PRIMARY KEY (recipe_id, technique)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.cooking_sessions (
+			token VARCHAR(36) PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			recipe_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipes(id) ON DELETE CASCADE,
+			scale NUMERIC(6,2) NOT NULL DEFAULT 1 CHECK (scale > 0),
+			current_step INTEGER NOT NULL DEFAULT 0 CHECK (current_step >= 0),
+			started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			completed_at TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_images (
+			id SERIAL PRIMARY KEY,
+			recipe_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipes(id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			caption VARCHAR(200),
+			position INTEGER NOT NULL,
+			is_cover BOOLEAN DEFAULT FALSE NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			thumbnail_url TEXT,
+			storage_key VARCHAR(255)
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_images_one_cover ON recipe_catalogue.recipe_images (recipe_id) WHERE is_cover;
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.user_follows (
+			follower_id INTEGER NOT NULL,
+			followee_id INTEGER NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			PRIMARY KEY (follower_id, followee_id),
+			CHECK (follower_id <> followee_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.user_favorites (
+			user_id INTEGER NOT NULL,
+			recipe_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipes(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			PRIMARY KEY (user_id, recipe_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.households (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			owner_id INTEGER NOT NULL,
+			invite_code VARCHAR(36) NOT NULL UNIQUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.household_members (
+			household_id INTEGER NOT NULL REFERENCES recipe_catalogue.households(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL,
+			joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			PRIMARY KEY (household_id, user_id),
+			CONSTRAINT household_members_one_household UNIQUE (user_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.pantries (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER UNIQUE,
+			household_id INTEGER UNIQUE REFERENCES recipe_catalogue.households(id) ON DELETE CASCADE,
+			version INTEGER DEFAULT 0 NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			CONSTRAINT pantries_one_owner CHECK ((user_id IS NULL) <> (household_id IS NULL))
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.pantry_items (
+			pantry_id INTEGER NOT NULL REFERENCES recipe_catalogue.pantries(id) ON DELETE CASCADE,
+			ingredient_id INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients(id) ON DELETE CASCADE,
+			added_by INTEGER NOT NULL,
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			PRIMARY KEY (pantry_id, ingredient_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.activities (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			type VARCHAR(50) NOT NULL,
+			recipe_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipes(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			UNIQUE (type, recipe_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.user_profiles (
+			user_id INTEGER PRIMARY KEY,
+			display_name VARCHAR(50),
+			bio VARCHAR(500),
+			is_public BOOLEAN DEFAULT TRUE NOT NULL,
+			show_recipes BOOLEAN DEFAULT TRUE NOT NULL,
+			show_stats BOOLEAN DEFAULT TRUE NOT NULL,
+			measurement_system VARCHAR(10) CHECK (measurement_system IN ('metric', 'imperial')),
+			locale VARCHAR(20),
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_lineage (
+			recipe_id INTEGER PRIMARY KEY REFERENCES recipe_catalogue.recipes(id) ON DELETE CASCADE,
+			parent_id INTEGER REFERENCES recipe_catalogue.recipes(id) ON DELETE SET NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_prices (
+			id SERIAL PRIMARY KEY,
+			ingredient_id INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients(id) ON DELETE CASCADE,
+			price DECIMAL(10,2) NOT NULL CHECK (price >= 0),
+			quantity DECIMAL(8,2) NOT NULL CHECK (quantity > 0),
+			unit VARCHAR(20) NOT NULL,
+			recorded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_cost_snapshots (
+			id SERIAL PRIMARY KEY,
+			recipe_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipes(id) ON DELETE CASCADE,
+			cost DECIMAL(10,2) NOT NULL,
+			priced_ingredients INTEGER NOT NULL,
+			total_ingredients INTEGER NOT NULL,
+			recorded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_substitutions (
+			ingredient_id INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients(id) ON DELETE CASCADE,
+			substitute_id INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients(id) ON DELETE CASCADE,
+			ratio DECIMAL(6,3) NOT NULL DEFAULT 1 CHECK (ratio > 0),
+			notes VARCHAR(200),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			PRIMARY KEY (ingredient_id, substitute_id),
+			CHECK (ingredient_id <> substitute_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_dietary_flags (
+			ingredient_id INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients(id) ON DELETE CASCADE,
+			flag VARCHAR(20) NOT NULL CHECK (flag IN ('meat', 'gelatin', 'fish', 'shellfish', 'dairy', 'egg', 'honey', 'plant_based')),
+			PRIMARY KEY (ingredient_id, flag)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_diet_overrides (
+			ingredient_id INTEGER PRIMARY KEY REFERENCES recipe_catalogue.ingredients(id) ON DELETE CASCADE,
+			diet VARCHAR(20) NOT NULL CHECK (diet IN ('vegan', 'vegetarian', 'pescatarian', 'omnivore')),
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.allergens (
+			id SERIAL PRIMARY KEY,
+			code VARCHAR(30) NOT NULL UNIQUE,
+			name VARCHAR(100) NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_allergens (
+			ingredient_id INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients(id) ON DELETE CASCADE,
+			allergen_id INTEGER NOT NULL REFERENCES recipe_catalogue.allergens(id) ON DELETE CASCADE,
+			PRIMARY KEY (ingredient_id, allergen_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.ingredient_aliases (
+			ingredient_id INTEGER NOT NULL REFERENCES recipe_catalogue.ingredients(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			PRIMARY KEY (ingredient_id, name)
+		);
+
+		INSERT INTO recipe_catalogue.allergens (code, name)
+		VALUES ('gluten', 'Gluten'), ('dairy', 'Milk'), ('egg', 'Eggs'), ('fish', 'Fish'),
+		       ('shellfish', 'Crustacean shellfish'), ('mollusc', 'Molluscs'), ('peanut', 'Peanuts'),
+		       ('tree_nut', 'Tree nuts'), ('soy', 'Soy'), ('sesame', 'Sesame'), ('mustard', 'Mustard'),
+		       ('celery', 'Celery'), ('lupin', 'Lupin'), ('sulphites', 'Sulphites')
+		ON CONFLICT (code) DO NOTHING;
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.prep_sessions (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			name VARCHAR(100) NOT NULL,
+			prep_date DATE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.prep_session_recipes (
+			session_id INTEGER NOT NULL REFERENCES recipe_catalogue.prep_sessions(id) ON DELETE CASCADE,
+			recipe_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipes(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			scale NUMERIC(6,2) NOT NULL DEFAULT 1 CHECK (scale > 0),
+			portions INTEGER NOT NULL DEFAULT 1 CHECK (portions > 0),
+			keeps_days INTEGER NOT NULL DEFAULT 4 CHECK (keeps_days BETWEEN 1 AND 365),
+			reheat_instructions VARCHAR(200),
+			PRIMARY KEY (session_id, recipe_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.meal_plans (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			title VARCHAR(200) NOT NULL,
+			period_start DATE NOT NULL,
+			period_end DATE NOT NULL,
+			period_type VARCHAR(20) DEFAULT 'week' CHECK (period_type IN ('week', 'biweek', 'month', 'custom')),
+			is_public BOOLEAN DEFAULT false,
+			slug VARCHAR(255) UNIQUE NOT NULL,
+			scale NUMERIC(6,2) NOT NULL DEFAULT 1 CHECK (scale > 0),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.meal_plan_members (
+			id SERIAL PRIMARY KEY,
+			meal_plan_id INTEGER NOT NULL REFERENCES recipe_catalogue.meal_plans(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			name VARCHAR(50) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			CONSTRAINT meal_plan_members_unique_position UNIQUE (meal_plan_id, position)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.meal_plan_entries (
+			id SERIAL PRIMARY KEY,
+			meal_plan_id INTEGER NOT NULL REFERENCES recipe_catalogue.meal_plans(id) ON DELETE CASCADE,
+			recipe_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipes(id) ON DELETE CASCADE,
+			planned_on DATE NOT NULL,
+			slot VARCHAR(20) NOT NULL CHECK (slot IN ('breakfast', 'lunch', 'dinner')),
+			assignee_id INTEGER REFERENCES recipe_catalogue.meal_plan_members(id) ON DELETE SET NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			CONSTRAINT meal_plan_entries_unique_slot_recipe UNIQUE (meal_plan_id, planned_on, slot, recipe_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.meal_plan_preferences (
+			user_id INTEGER PRIMARY KEY,
+			weekday_max_minutes INTEGER CHECK (weekday_max_minutes BETWEEN 1 AND 1440),
+			weekend_max_minutes INTEGER CHECK (weekend_max_minutes BETWEEN 1 AND 1440),
+			elaborate_weekends BOOLEAN DEFAULT false NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.recipe_templates (
+			id SERIAL PRIMARY KEY,
+			recipe_id INTEGER NOT NULL REFERENCES recipe_catalogue.recipes(id) ON DELETE CASCADE,
+			description TEXT,
+			created_by INTEGER NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			CONSTRAINT recipe_templates_recipe_id_key UNIQUE (recipe_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.audit_log (
+			id BIGSERIAL PRIMARY KEY,
+			user_id INTEGER,
+			entity VARCHAR(100) NOT NULL,
+			entity_id VARCHAR(100),
+			action VARCHAR(10) NOT NULL,
+			path TEXT NOT NULL,
+			before_state JSONB,
+			after_state JSONB,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			CONSTRAINT audit_log_action_valid CHECK (action IN ('create', 'update', 'delete'))
+		);
+
+		CREATE TABLE IF NOT EXISTS recipe_catalogue.idempotency_keys (
+			user_id INTEGER NOT NULL,
+			idempotency_key VARCHAR(255) NOT NULL,
+			request_hash CHAR(64) NOT NULL,
+			status_code INTEGER,
+			content_type VARCHAR(100),
+			response_body BYTEA,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			PRIMARY KEY (user_id, idempotency_key)
+		);
+
+		-- Search indexes (mirrors migrations/recipe-catalogue/V008)
+		CREATE EXTENSION IF NOT EXISTS pg_trgm;
+		CREATE INDEX IF NOT EXISTS idx_ingredients_name_trgm ON recipe_catalogue.ingredients USING GIN (name gin_trgm_ops);
+		CREATE INDEX IF NOT EXISTS idx_ingredients_description_trgm ON recipe_catalogue.ingredients USING GIN (description gin_trgm_ops);
+		CREATE INDEX IF NOT EXISTS idx_recipes_category_name ON recipe_catalogue.recipes (category_id, name);
+		CREATE INDEX IF NOT EXISTS idx_recipes_user_status ON recipe_catalogue.recipes (user_id, status, updated_at DESC);
+		CREATE INDEX IF NOT EXISTS idx_ingredients_category_name ON recipe_catalogue.ingredients (category, name);
+		CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_ingredient_recipe ON recipe_catalogue.recipe_ingredients (ingredient_id, recipe_id);
+
+		-- Ingredient usage tracking (mirrors migrations/recipe-catalogue/V028)
+		CREATE OR REPLACE FUNCTION recipe_catalogue.track_ingredient_use() RETURNS TRIGGER AS $$
+		BEGIN
+			IF TG_OP = 'INSERT' THEN
+				UPDATE recipe_catalogue.ingredients SET archived_at = NULL
+				WHERE id = NEW.ingredient_id AND archived_at IS NOT NULL;
+			ELSE
+				UPDATE recipe_catalogue.ingredients SET last_used_at = CURRENT_TIMESTAMP WHERE id = OLD.ingredient_id;
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql;
+
+		DROP TRIGGER IF EXISTS recipe_ingredients_track_use ON recipe_catalogue.recipe_ingredients;
+		CREATE TRIGGER recipe_ingredients_track_use AFTER INSERT OR DELETE ON recipe_catalogue.recipe_ingredients
+			FOR EACH ROW EXECUTE FUNCTION recipe_catalogue.track_ingredient_use();
+
 		-- Recommendations tables
 		CREATE TABLE IF NOT EXISTS recommendations.user_preferences (
 			id SERIAL PRIMARY KEY,
 			user_id INTEGER NOT NULL,
 			preferred_categories INTEGER[],
+			diet VARCHAR(20) CHECK (diet IN ('vegan', 'vegetarian', 'pescatarian', 'omnivore')),
+			allergens TEXT[] NOT NULL DEFAULT '{}',
+			version INTEGER NOT NULL DEFAULT 1,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			UNIQUE(user_id)
@@ -186,7 +640,11 @@ func initializeTestSchema(db *database.DB) error {
 			user_id INTEGER NOT NULL,
 			recipe_id INTEGER NOT NULL,
 			cooked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			rating INTEGER CHECK (rating BETWEEN 1 AND 5)
+			rating INTEGER CHECK (rating BETWEEN 1 AND 5),
+			photo_url TEXT,
+			photo_caption VARCHAR(200),
+			photo_shared BOOLEAN NOT NULL DEFAULT FALSE,
+			photo_status VARCHAR(20) CHECK (photo_status IN ('pending', 'approved', 'rejected'))
 		);
 
 		CREATE TABLE IF NOT EXISTS recommendations.recommendation_history (
@@ -197,6 +655,132 @@ func initializeTestSchema(db *database.DB) error {
 			clicked BOOLEAN DEFAULT FALSE,
 			algorithm_used VARCHAR(50) 
 		);
+
+		CREATE TABLE IF NOT EXISTS recommendations.recommendation_slates (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			algorithm VARCHAR(50) NOT NULL,
+			served_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recommendations.recommendation_slate_items (
+			slate_id INTEGER NOT NULL REFERENCES recommendations.recommendation_slates(id) ON DELETE CASCADE,
+			recipe_id INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			score DOUBLE PRECISION NOT NULL,
+			impressed_at TIMESTAMP,
+			clicked_at TIMESTAMP,
+			PRIMARY KEY (slate_id, recipe_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS recommendations.digest_subscriptions (
+			user_id INTEGER PRIMARY KEY,
+			locale VARCHAR(10) DEFAULT 'en' NOT NULL,
+			subscribed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			last_sent_at TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS recommendations.waste_log (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			ingredient_id INTEGER NOT NULL,
+			quantity DECIMAL(10,2) NOT NULL CHECK (quantity > 0),
+			unit VARCHAR(20) NOT NULL,
+			reason VARCHAR(20) NOT NULL CHECK (reason IN ('expired', 'spoiled', 'leftover', 'overbought', 'other')),
+			wasted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS recommendations.freezer_items (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			recipe_id INTEGER NOT NULL,
+			portions INTEGER NOT NULL CHECK (portions > 0),
+			frozen_on DATE NOT NULL,
+			eat_before DATE NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			CHECK (eat_before > frozen_on)
+		);
+
+		CREATE TABLE IF NOT EXISTS recommendations.recipe_similarities (
+			recipe_id INTEGER NOT NULL,
+			similar_recipe_id INTEGER NOT NULL,
+			score DOUBLE PRECISION NOT NULL,
+			co_cooks INTEGER NOT NULL,
+			computed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			PRIMARY KEY (recipe_id, similar_recipe_id),
+			CHECK (recipe_id <> similar_recipe_id)
+		);
+
+		-- Ingredient diets (mirrors migrations/recipe-catalogue/V040)
+		CREATE OR REPLACE VIEW recipe_catalogue.ingredient_diets AS
+		WITH ingredient_flags AS (
+			SELECT i.id AS ingredient_id, i.category, o.diet AS override,
+			       ARRAY_REMOVE(ARRAY_AGG(f.flag::text), NULL) AS flags
+			FROM recipe_catalogue.ingredients i
+			LEFT JOIN recipe_catalogue.ingredient_diet_overrides o ON o.ingredient_id = i.id
+			LEFT JOIN recipe_catalogue.ingredient_dietary_flags f ON f.ingredient_id = i.id
+			GROUP BY i.id, i.category, o.diet
+		),
+		ingredient_allergen_codes AS (
+			SELECT ia.ingredient_id, ARRAY_AGG(a.code::text) AS codes
+			FROM recipe_catalogue.ingredient_allergens ia
+			JOIN recipe_catalogue.allergens a ON a.id = ia.allergen_id
+			GROUP BY ia.ingredient_id
+		),
+		classified AS (
+			SELECT ingredient_id, flags, category,
+			       CASE
+			           WHEN override IS NOT NULL THEN override
+			           WHEN flags && ARRAY['meat', 'gelatin']::text[] THEN 'omnivore'
+			           WHEN flags && ARRAY['fish', 'shellfish']::text[] THEN 'pescatarian'
+			           WHEN flags && ARRAY['dairy', 'egg', 'honey']::text[] THEN 'vegetarian'
+			           WHEN flags && ARRAY['plant_based']::text[] THEN 'vegan'
+			           WHEN category = 'Meat' THEN 'omnivore'
+			           WHEN category = 'Fish' THEN 'pescatarian'
+			           WHEN category = 'Dairy' THEN 'vegetarian'
+			           WHEN category IN ('Vegetables', 'Fruits', 'Spices', 'Grains', 'Oils') THEN 'vegan'
+			           ELSE 'omnivore'
+			       END AS diet
+			FROM ingredient_flags
+		)
+		SELECT c.ingredient_id, c.diet,
+		       ARRAY_POSITION(ARRAY['vegan', 'vegetarian', 'pescatarian', 'omnivore']::text[], c.diet::text) - 1 AS diet_rank,
+		       CASE
+		           WHEN CARDINALITY(c.flags) > 0 THEN c.flags
+		           WHEN c.category = 'Meat' THEN ARRAY['meat']::text[]
+		           WHEN c.category = 'Fish' THEN ARRAY['fish']::text[]
+		           WHEN c.category = 'Dairy' THEN ARRAY['dairy']::text[]
+		           ELSE c.flags
+		       END || COALESCE(iac.codes, ARRAY[]::text[]) AS flags
+		FROM classified c
+		LEFT JOIN ingredient_allergen_codes iac ON iac.ingredient_id = c.ingredient_id;
+
+		-- Stats views
+		CREATE MATERIALIZED VIEW IF NOT EXISTS recipe_catalogue.ingredient_usage AS
+		SELECT i.id AS ingredient_id, COUNT(DISTINCT ri.recipe_id) AS recipe_count
+		FROM recipe_catalogue.ingredients i
+		LEFT JOIN recipe_catalogue.recipe_ingredients ri ON ri.ingredient_id = i.id
+		GROUP BY i.id;
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_ingredient_usage_ingredient_id ON recipe_catalogue.ingredient_usage(ingredient_id);
+
+		CREATE MATERIALIZED VIEW IF NOT EXISTS recommendations.recipe_popularity AS
+		SELECT recipe_id,
+		       COUNT(*) AS times_cooked,
+		       COUNT(DISTINCT user_id) AS unique_cooks,
+		       COUNT(*) FILTER (WHERE cooked_at > CURRENT_TIMESTAMP - INTERVAL '30 days') AS cooked_last_30_days,
+		       AVG(rating)::numeric(3, 2) AS average_rating,
+		       MAX(cooked_at) AS last_cooked_at
+		FROM recommendations.cooking_history
+		GROUP BY recipe_id;
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_popularity_recipe_id ON recommendations.recipe_popularity(recipe_id);
+
+		CREATE MATERIALIZED VIEW IF NOT EXISTS recommendations.recipe_seasonality AS
+		SELECT recipe_id,
+		       EXTRACT(MONTH FROM cooked_at)::int AS month,
+		       COUNT(*) AS times_cooked
+		FROM recommendations.cooking_history
+		GROUP BY recipe_id, EXTRACT(MONTH FROM cooked_at)::int;
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_seasonality_recipe_month ON recommendations.recipe_seasonality(recipe_id, month);
 	`
 
 	_, err := db.Exec(schemaSQL)
@@ -40,7 +40,7 @@ func (suite *AuthRepositoryIntegrationSuite) TestCreate_Success_RealDatabase() {
 	passwordHash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
 
 	// Act
-	user, err := suite.repo.Create(email, string(passwordHash))
+	user, err := suite.repo.Create(email, string(passwordHash), "")
 
 	// Assert
 	assert.NoError(suite.T(), err)
@@ -61,12 +61,12 @@ func (suite *AuthRepositoryIntegrationSuite) TestCreate_DuplicateEmail_RealConst
 	passwordHash := "hashed_password"
 
 	// Create first user - should succeed
-	user1, err := suite.repo.Create(email, passwordHash)
+	user1, err := suite.repo.Create(email, passwordHash, "")
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), user1)
 
 	// Try to create user with same email - should fail due to UNIQUE constraint
-	user2, err := suite.repo.Create(email, passwordHash+"different")
+	user2, err := suite.repo.Create(email, passwordHash+"different", "")
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), user2)
 	assert.Contains(suite.T(), err.Error(), "duplicate key value") // PostgreSQL constraint error
@@ -81,7 +81,7 @@ func (suite *AuthRepositoryIntegrationSuite) TestEmailExists_RealQueries() {
 	assert.False(suite.T(), exists)
 
 	// Create user
-	_, err = suite.repo.Create(email, "hashed_password")
+	_, err = suite.repo.Create(email, "hashed_password", "")
 	assert.NoError(suite.T(), err)
 
 	// Now should exist
@@ -95,7 +95,7 @@ func (suite *AuthRepositoryIntegrationSuite) TestGetByEmail_RealData() {
 	passwordHash := "test_hash_value"
 
 	// Create user first
-	createdUser, err := suite.repo.Create(email, passwordHash)
+	createdUser, err := suite.repo.Create(email, passwordHash, "")
 	assert.NoError(suite.T(), err)
 
 	// Retrieve user
@@ -120,7 +120,7 @@ func (suite *AuthRepositoryIntegrationSuite) TestGetByID_RealData() {
 	passwordHash := "test_hash_value"
 
 	// Create user first
-	createdUser, err := suite.repo.Create(email, passwordHash)
+	createdUser, err := suite.repo.Create(email, passwordHash, "")
 	assert.NoError(suite.T(), err)
 
 	// Retrieve user by ID
@@ -149,7 +149,7 @@ func (suite *AuthRepositoryIntegrationSuite) TestEmailExists_SQLInjectionProtect
 	assert.False(suite.T(), exists)
 
 	// Verify table still exists by creating a user
-	_, err = suite.repo.Create("safe@example.com", "password")
+	_, err = suite.repo.Create("safe@example.com", "password", "")
 	assert.NoError(suite.T(), err) // Table wasn't dropped
 }
 
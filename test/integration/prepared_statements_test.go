@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/seed"
+	"meal-prep/test/helpers"
+
+	"github.com/stretchr/testify/require"
+)
+
+// BenchmarkHotQueries times the per-request lookups against a real server
+// with and without the statement cache, where the parse and plan savings
+// actually show up:
+//
+//	go test ./test/integration/ -run '^$' -bench HotQueries
+func BenchmarkHotQueries(b *testing.B) {
+	testDB := helpers.SetupPostgresContainer(b)
+	defer testDB.Cleanup(b)
+	require.NoError(b, seed.Run(testDB.DB, seed.Default))
+
+	var recipeID, ingredientID int
+	require.NoError(b, testDB.DB.QueryRow("SELECT MIN(recipe_id), MIN(ingredient_id) FROM recipe_catalogue.recipe_ingredients").
+		Scan(&recipeID, &ingredientID))
+
+	for _, mode := range []struct {
+		name      string
+		cacheSize int
+	}{
+		{"unprepared", 0},
+		{"prepared", 256},
+	} {
+		testDB.DB.EnableStatementCache(mode.cacheSize)
+		recipeRepo := repository.NewRecipeRepository(testDB.DB)
+		ingredientRepo := repository.NewIngredientRepository(testDB.DB)
+
+		b.Run(mode.name+"/GetByID", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := recipeRepo.GetByID(recipeID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(mode.name+"/IngredientExists", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := ingredientRepo.IngredientExists(ingredientID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(mode.name+"/GetRecipeIngredients", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := ingredientRepo.GetRecipeIngredients(recipeID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
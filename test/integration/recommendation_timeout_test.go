@@ -0,0 +1,64 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"meal-prep/services/recommendations/repository"
+	"meal-prep/shared/database"
+	"meal-prep/test/helpers"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// RecommendationTimeoutIntegrationSuite runs scoring against PostgreSQL with
+// a deadline that passes mid-statement, which lib/pq reports as the server
+// cancelling the query rather than as the context's error.
+type RecommendationTimeoutIntegrationSuite struct {
+	suite.Suite
+	testDB  *helpers.TestDatabase
+	recRepo repository.RecommendationRepository
+}
+
+func (suite *RecommendationTimeoutIntegrationSuite) SetupSuite() {
+	helpers.SuppressTestLogs()
+	suite.testDB = helpers.SetupPostgresContainer(suite.T())
+
+	suite.recRepo = repository.NewRecommendationRepository(suite.testDB.DB)
+}
+
+func (suite *RecommendationTimeoutIntegrationSuite) TearDownSuite() {
+	suite.testDB.Cleanup(suite.T())
+	helpers.RestoreTestLogs()
+}
+
+func (suite *RecommendationTimeoutIntegrationSuite) SetupTest() {
+	suite.testDB.CleanupTestData(suite.T())
+}
+
+func (suite *RecommendationTimeoutIntegrationSuite) TestHybridRecommendations_DeadlineCancelsStatement() {
+	// Hold the recipes table so scoring is still waiting when its deadline passes
+	lock, err := suite.testDB.DB.DB.Begin()
+	require.NoError(suite.T(), err)
+	defer lock.Rollback()
+	_, err = lock.Exec(`LOCK TABLE recipe_catalogue.recipes IN ACCESS EXCLUSIVE MODE`)
+	require.NoError(suite.T(), err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = suite.recRepo.GetHybridRecommendations(ctx, 1, 10, 0)
+
+	require.Error(suite.T(), err)
+	var pqErr *pq.Error
+	require.ErrorAs(suite.T(), err, &pqErr)
+	assert.Equal(suite.T(), pq.ErrorCode("57014"), pqErr.Code)
+	assert.True(suite.T(), database.IsTimeout(ctx, err))
+}
+
+func TestRecommendationTimeoutIntegrationSuite(t *testing.T) {
+	suite.Run(t, new(RecommendationTimeoutIntegrationSuite))
+}
@@ -0,0 +1,245 @@
+package integration
+
+import (
+	"fmt"
+	"testing"
+
+	"meal-prep/services/recipe-catalogue/repository"
+	"meal-prep/shared/database"
+	"meal-prep/shared/models"
+	"meal-prep/shared/seed"
+	"meal-prep/test/helpers"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	searchIngredientCount = 20000
+	searchRecipeCount     = 5000
+)
+
+var searchIngredientCategories = []string{"Meat", "Vegetables", "Dairy", "Grains", "Spices", "Oils", "Fish", "Fruits"}
+
+// SearchIndexIntegrationSuite checks that the catalogue's search queries are
+// planned against the indexes added in V008 once the tables hold enough rows
+// for the planner to prefer them over a sequential scan.
+type SearchIndexIntegrationSuite struct {
+	suite.Suite
+	testDB *helpers.TestDatabase
+}
+
+func (suite *SearchIndexIntegrationSuite) SetupSuite() {
+	helpers.SuppressTestLogs()
+	suite.testDB = helpers.SetupPostgresContainer(suite.T())
+	seedSearchData(suite.T(), suite.testDB.DB)
+}
+
+func (suite *SearchIndexIntegrationSuite) TearDownSuite() {
+	suite.testDB.Cleanup(suite.T())
+	helpers.RestoreTestLogs()
+}
+
+// seedSearchData bulk-loads a catalogue large enough for index plans to win.
+// About one ingredient in five hundred is a chicken cut, so searches for
+// "chick" are selective.
+func seedSearchData(t testing.TB, db *database.DB) {
+	ds := seed.Dataset{Categories: seed.Default.Categories}
+
+	for i := 0; i < searchIngredientCount; i++ {
+		name := fmt.Sprintf("Pantry Item %05d", i)
+		if i%500 == 0 {
+			name = fmt.Sprintf("Chicken Cut %05d", i)
+		}
+		ds.Ingredients = append(ds.Ingredients, seed.Ingredient{
+			Name:        name,
+			Description: fmt.Sprintf("Generated ingredient number %d", i),
+			Category:    searchIngredientCategories[i%len(searchIngredientCategories)],
+		})
+	}
+
+	for i := 0; i < searchRecipeCount; i++ {
+		recipe := seed.Recipe{
+			Name:     fmt.Sprintf("Generated Recipe %05d", i),
+			Category: seed.Default.Categories[i%len(seed.Default.Categories)].Name,
+		}
+		for j := 0; j < 3; j++ {
+			recipe.Ingredients = append(recipe.Ingredients, seed.RecipeIngredient{
+				Ingredient: ds.Ingredients[(i*7+j*131)%searchIngredientCount].Name,
+				Quantity:   100,
+				Unit:       "grams",
+			})
+		}
+		ds.Recipes = append(ds.Recipes, recipe)
+	}
+
+	if err := seed.Run(db, ds); err != nil {
+		t.Fatalf("Failed to seed search data: %v", err)
+	}
+	if _, err := db.Exec("ANALYZE"); err != nil {
+		t.Fatalf("Failed to analyze: %v", err)
+	}
+}
+
+// plan returns the JSON query plan for query.
+func (suite *SearchIndexIntegrationSuite) plan(query string, args ...interface{}) string {
+	var plan string
+	err := suite.testDB.DB.QueryRow("EXPLAIN (FORMAT JSON) "+query, args...).Scan(&plan)
+	require.NoError(suite.T(), err)
+	return plan
+}
+
+func (suite *SearchIndexIntegrationSuite) categoryID(name string) int {
+	var id int
+	err := suite.testDB.DB.QueryRow("SELECT id FROM recipe_catalogue.categories WHERE name = $1", name).Scan(&id)
+	require.NoError(suite.T(), err)
+	return id
+}
+
+func (suite *SearchIndexIntegrationSuite) TestIngredientSearch_UsesTrigramIndex() {
+	plan := suite.plan(`
+		SELECT id, name, description, category, created_at
+		FROM recipe_catalogue.ingredients
+		WHERE name ILIKE $1 OR description ILIKE $1
+		ORDER BY name
+		LIMIT $2 OFFSET $3`, "%chick%", 20, 0)
+
+	assert.Contains(suite.T(), plan, "idx_ingredients_name_trgm")
+	assert.NotContains(suite.T(), plan, `"Seq Scan"`)
+}
+
+func (suite *SearchIndexIntegrationSuite) TestRecipesByCategory_UsesCompositeIndex() {
+	plan := suite.plan(`
+		SELECT d.id, d.user_id, d.name, d.description, d.category_id, d.created_at, d.updated_at, d.status,
+		       c.id, c.name, c.description
+		FROM recipe_catalogue.recipes d
+		LEFT JOIN recipe_catalogue.categories c ON d.category_id = c.id
+		WHERE d.category_id = $1 AND d.status = 'published'
+		ORDER BY d.name
+		LIMIT $2 OFFSET $3`, suite.categoryID("Chicken"), 20, 0)
+
+	assert.Contains(suite.T(), plan, "idx_recipes_category_name")
+}
+
+func (suite *SearchIndexIntegrationSuite) TestIngredientsByCategory_UsesCompositeIndex() {
+	plan := suite.plan(`
+		SELECT id, name, description, category, created_at
+		FROM recipe_catalogue.ingredients
+		WHERE category = $1
+		ORDER BY name
+		LIMIT $2 OFFSET $3`, "Fish", 20, 0)
+
+	assert.Contains(suite.T(), plan, "idx_ingredients_category_name")
+}
+
+func (suite *SearchIndexIntegrationSuite) TestRecipeSearchByIngredients_UsesIngredientIndex() {
+	var ids []int
+	rows, err := suite.testDB.DB.Query("SELECT id FROM recipe_catalogue.ingredients ORDER BY id LIMIT 2")
+	require.NoError(suite.T(), err)
+	for rows.Next() {
+		var id int
+		require.NoError(suite.T(), rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	plan := suite.plan(`
+		SELECT COUNT(DISTINCT r.id)
+		FROM recipe_catalogue.recipes r
+		JOIN recipe_catalogue.recipe_ingredients ri ON r.id = ri.recipe_id
+		WHERE ri.ingredient_id = ANY($1)`, pq.Array(ids))
+
+	assert.Contains(suite.T(), plan, "idx_recipe_ingredients_ingredient_recipe")
+}
+
+func TestSearchIndexIntegrationSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+	suite.Run(t, new(SearchIndexIntegrationSuite))
+}
+
+// =============================================================================
+// BENCHMARKS
+// =============================================================================
+
+// benchmarkWithAndWithoutIndexes runs fn once with the planner free to use
+// indexes and once with index scans disabled, so the two timings show what
+// the V008 indexes buy on the same data.
+//
+//	go test ./test/integration/ -run '^$' -bench Search -benchtime 200x
+func benchmarkWithAndWithoutIndexes(b *testing.B, fn func(b *testing.B, db *database.DB)) {
+	testDB := helpers.SetupPostgresContainer(b)
+	defer testDB.Cleanup(b)
+	seedSearchData(b, testDB.DB)
+
+	// A single connection makes the SET statements below apply to every
+	// query the repository sends.
+	testDB.DB.SetMaxOpenConns(1)
+
+	for _, mode := range []struct {
+		name    string
+		enabled string
+	}{
+		{"indexed", "on"},
+		{"seq_scan", "off"},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			for _, setting := range []string{"enable_indexscan", "enable_bitmapscan", "enable_indexonlyscan"} {
+				_, err := testDB.DB.Exec(fmt.Sprintf("SET %s = %s", setting, mode.enabled))
+				require.NoError(b, err)
+			}
+			b.ResetTimer()
+			fn(b, testDB.DB)
+		})
+	}
+}
+
+func BenchmarkSearchIngredients(b *testing.B) {
+	benchmarkWithAndWithoutIndexes(b, func(b *testing.B, db *database.DB) {
+		repo := repository.NewIngredientRepository(db)
+		params := models.PaginationParams{Page: 1, PerPage: 20}
+
+		for i := 0; i < b.N; i++ {
+			results, _, err := repo.SearchIngredients("chick", params)
+			if err != nil || len(results) == 0 {
+				b.Fatalf("search failed: %v (%d results)", err, len(results))
+			}
+		}
+	})
+}
+
+func BenchmarkSearchRecipesByIngredients(b *testing.B) {
+	benchmarkWithAndWithoutIndexes(b, func(b *testing.B, db *database.DB) {
+		repo := repository.NewRecipeRepository(db)
+		params := models.PaginationParams{Page: 1, PerPage: 20}
+
+		var firstID int
+		require.NoError(b, db.QueryRow("SELECT MIN(id) FROM recipe_catalogue.ingredients").Scan(&firstID))
+
+		for i := 0; i < b.N; i++ {
+			if _, _, err := repo.SearchRecipesByIngredients([]int{firstID + i%100, firstID + 131}, params); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkGetRecipesByCategory(b *testing.B) {
+	benchmarkWithAndWithoutIndexes(b, func(b *testing.B, db *database.DB) {
+		repo := repository.NewRecipeRepository(db)
+
+		var categoryID int
+		require.NoError(b, db.QueryRow(
+			"SELECT id FROM recipe_catalogue.categories WHERE name = $1", seed.Default.Categories[0].Name).Scan(&categoryID))
+
+		for i := 0; i < b.N; i++ {
+			params := models.PaginationParams{Page: 1 + i%10, PerPage: 20}
+			if _, _, err := repo.GetByCategory(categoryID, params); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"fmt"
+	"testing"
+
+	"meal-prep/shared/seed"
+	"meal-prep/test/helpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// SeedIntegrationSuite verifies the seed datasets load into the real schema
+// and that loading them repeatedly is a no-op.
+type SeedIntegrationSuite struct {
+	suite.Suite
+	testDB *helpers.TestDatabase
+}
+
+func (suite *SeedIntegrationSuite) SetupSuite() {
+	helpers.SuppressTestLogs()
+	suite.testDB = helpers.SetupPostgresContainer(suite.T())
+}
+
+func (suite *SeedIntegrationSuite) TearDownSuite() {
+	suite.testDB.Cleanup(suite.T())
+	helpers.RestoreTestLogs()
+}
+
+func (suite *SeedIntegrationSuite) SetupTest() {
+	suite.testDB.CleanupTestData(suite.T())
+}
+
+func (suite *SeedIntegrationSuite) count(table string) int {
+	var n int
+	err := suite.testDB.DB.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n)
+	require.NoError(suite.T(), err)
+	return n
+}
+
+func (suite *SeedIntegrationSuite) TestDemoDataset_IsIdempotent() {
+	for i := 0; i < 2; i++ {
+		for _, ds := range seed.Datasets["demo"] {
+			require.NoError(suite.T(), seed.Run(suite.testDB.DB, ds))
+		}
+	}
+
+	assert.Equal(suite.T(), len(seed.Default.Categories), suite.count("recipe_catalogue.categories"))
+	assert.Equal(suite.T(), len(seed.Default.Ingredients), suite.count("recipe_catalogue.ingredients"))
+	assert.Equal(suite.T(), len(seed.Demo.Users), suite.count("auth.users"))
+	assert.Equal(suite.T(), len(seed.Demo.Recipes), suite.count("recipe_catalogue.recipes"))
+	assert.Equal(suite.T(), 9, suite.count("recipe_catalogue.recipe_ingredients"))
+}
+
+func (suite *SeedIntegrationSuite) TestDemoRecipes_AreOwnedBySeededUsers() {
+	for _, ds := range seed.Datasets["demo"] {
+		require.NoError(suite.T(), seed.Run(suite.testDB.DB, ds))
+	}
+
+	var owner string
+	err := suite.testDB.DB.QueryRow(`
+		SELECT u.email FROM recipe_catalogue.recipes r
+		JOIN auth.users u ON u.id = r.user_id
+		WHERE r.name = 'Banana Oat Pancakes'`).Scan(&owner)
+
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "chef@mealprep.local", owner)
+}
+
+func (suite *SeedIntegrationSuite) TestBulkImport_LoadsThousandsOfRowsIdempotently() {
+	ds := seed.Dataset{Categories: seed.Default.Categories}
+	for i := 0; i < 2000; i++ {
+		ds.Ingredients = append(ds.Ingredients, seed.Ingredient{
+			Name:     fmt.Sprintf("Bulk Ingredient %d", i),
+			Category: "Bulk",
+		})
+	}
+	for i := 0; i < 500; i++ {
+		ds.Recipes = append(ds.Recipes, seed.Recipe{
+			Name:     fmt.Sprintf("Bulk Recipe %d", i),
+			Category: "Vegetarian",
+			Ingredients: []seed.RecipeIngredient{
+				{Ingredient: fmt.Sprintf("Bulk Ingredient %d", i), Quantity: 100, Unit: "grams"},
+				{Ingredient: fmt.Sprintf("Bulk Ingredient %d", i+500), Quantity: 2.5, Unit: "pieces", Notes: "diced"},
+			},
+		})
+	}
+
+	require.NoError(suite.T(), seed.Run(suite.testDB.DB, ds))
+	require.NoError(suite.T(), seed.Run(suite.testDB.DB, ds))
+
+	assert.Equal(suite.T(), 2000, suite.count("recipe_catalogue.ingredients"))
+	assert.Equal(suite.T(), 500, suite.count("recipe_catalogue.recipes"))
+	assert.Equal(suite.T(), 1000, suite.count("recipe_catalogue.recipe_ingredients"))
+
+	var notes string
+	err := suite.testDB.DB.QueryRow(`
+		SELECT ri.notes FROM recipe_catalogue.recipe_ingredients ri
+		JOIN recipe_catalogue.recipes r ON r.id = ri.recipe_id
+		WHERE r.name = 'Bulk Recipe 7' AND ri.quantity = 2.5`).Scan(&notes)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "diced", notes)
+}
+
+func TestSeedIntegrationSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+	suite.Run(t, new(SeedIntegrationSuite))
+}
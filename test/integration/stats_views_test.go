@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	catalogue "meal-prep/services/recipe-catalogue/repository"
+	recommendations "meal-prep/services/recommendations/repository"
+	"meal-prep/test/helpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// StatsViewIntegrationSuite checks the popularity and usage materialized
+// views: readers see a snapshot until RefreshMaterializedViews runs.
+type StatsViewIntegrationSuite struct {
+	suite.Suite
+	testDB         *helpers.TestDatabase
+	ingredientRepo catalogue.IngredientRepository
+	recRepo        recommendations.RecommendationRepository
+}
+
+func (suite *StatsViewIntegrationSuite) SetupSuite() {
+	helpers.SuppressTestLogs()
+	suite.testDB = helpers.SetupPostgresContainer(suite.T())
+
+	suite.ingredientRepo = catalogue.NewIngredientRepository(suite.testDB.DB)
+	suite.recRepo = recommendations.NewRecommendationRepository(suite.testDB.DB)
+}
+
+func (suite *StatsViewIntegrationSuite) TearDownSuite() {
+	suite.testDB.Cleanup(suite.T())
+	helpers.RestoreTestLogs()
+}
+
+func (suite *StatsViewIntegrationSuite) SetupTest() {
+	suite.testDB.CleanupTestData(suite.T())
+	suite.refresh()
+}
+
+func (suite *StatsViewIntegrationSuite) refresh() {
+	err := suite.testDB.DB.RefreshMaterializedViews(context.Background(),
+		"recipe_catalogue.ingredient_usage", "recommendations.recipe_popularity", "recommendations.recipe_seasonality")
+	require.NoError(suite.T(), err)
+}
+
+func (suite *StatsViewIntegrationSuite) exec(query string, args ...interface{}) int {
+	var id int
+	require.NoError(suite.T(), suite.testDB.DB.QueryRow(query, args...).Scan(&id))
+	return id
+}
+
+func (suite *StatsViewIntegrationSuite) TestPopularity_ReflectsCookingAfterRefresh() {
+	soup := suite.exec(`INSERT INTO recipe_catalogue.recipes (name) VALUES ('Soup') RETURNING id`)
+	stew := suite.exec(`INSERT INTO recipe_catalogue.recipes (name) VALUES ('Stew') RETURNING id`)
+
+	for _, cook := range []struct{ userID, recipeID, rating int }{
+		{1, soup, 4}, {2, soup, 5}, {1, soup, 3}, {3, stew, 2},
+	} {
+		require.NoError(suite.T(), suite.recRepo.LogCooking(cook.userID, cook.recipeID, &cook.rating, nil))
+	}
+
+	popular, err := suite.recRepo.GetPopularRecipes(10)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), popular, "views only change on refresh")
+
+	suite.refresh()
+
+	popular, err = suite.recRepo.GetPopularRecipes(10)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), popular, 2)
+
+	assert.Equal(suite.T(), "Soup", popular[0].Name)
+	assert.Equal(suite.T(), 3, popular[0].TimesCooked)
+	assert.Equal(suite.T(), 2, popular[0].UniqueCooks)
+	assert.Equal(suite.T(), 3, popular[0].CookedLast30Days)
+	require.NotNil(suite.T(), popular[0].AverageRating)
+	assert.InDelta(suite.T(), 4.0, *popular[0].AverageRating, 0.001)
+	assert.NotNil(suite.T(), popular[0].LastCookedAt)
+
+	assert.Equal(suite.T(), "Stew", popular[1].Name)
+}
+
+func (suite *StatsViewIntegrationSuite) TestPublicRecommendations_FavourPopularInSeason() {
+	soup := suite.exec(`INSERT INTO recipe_catalogue.recipes (name) VALUES ('Soup') RETURNING id`)
+	salad := suite.exec(`INSERT INTO recipe_catalogue.recipes (name) VALUES ('Salad') RETURNING id`)
+	suite.exec(`INSERT INTO recipe_catalogue.recipes (name) VALUES ('Stew') RETURNING id`)
+
+	// Salad was cooked more often overall, but soup is what people cook in January
+	for _, cook := range []struct {
+		recipeID int
+		cookedAt string
+	}{
+		{soup, "2024-01-10"}, {soup, "2025-01-05"},
+		{salad, "2024-07-01"}, {salad, "2024-07-02"}, {salad, "2024-07-03"},
+	} {
+		suite.exec(`INSERT INTO recommendations.cooking_history (user_id, recipe_id, cooked_at)
+			VALUES (1, $1, $2) RETURNING id`, cook.recipeID, cook.cookedAt)
+	}
+	suite.refresh()
+
+	recipes, err := suite.recRepo.GetPublicRecommendations([]int{12, 1, 2}, 10)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), recipes, 3)
+
+	assert.Equal(suite.T(), "Soup", recipes[0].Name)
+	assert.Equal(suite.T(), "Salad", recipes[1].Name)
+	assert.Equal(suite.T(), "Stew", recipes[2].Name, "recipes nobody cooked still fill the list")
+	assert.Nil(suite.T(), recipes[0].LastCookedAt, "nothing personal is returned")
+}
+
+func (suite *StatsViewIntegrationSuite) TestIngredientUsage_CountsDistinctRecipes() {
+	soup := suite.exec(`INSERT INTO recipe_catalogue.recipes (name) VALUES ('Soup') RETURNING id`)
+	stew := suite.exec(`INSERT INTO recipe_catalogue.recipes (name) VALUES ('Stew') RETURNING id`)
+	onion := suite.exec(`INSERT INTO recipe_catalogue.ingredients (name, category) VALUES ('Onion', 'Vegetables') RETURNING id`)
+	salt := suite.exec(`INSERT INTO recipe_catalogue.ingredients (name, category) VALUES ('Salt', 'Spices') RETURNING id`)
+	suite.exec(`INSERT INTO recipe_catalogue.ingredients (name, category) VALUES ('Saffron', 'Spices') RETURNING id`)
+
+	for _, link := range []struct{ recipeID, ingredientID int }{{soup, onion}, {stew, onion}, {soup, salt}} {
+		suite.exec(`INSERT INTO recipe_catalogue.recipe_ingredients (recipe_id, ingredient_id, quantity, unit)
+			VALUES ($1, $2, 1, 'piece') RETURNING id`, link.recipeID, link.ingredientID)
+	}
+
+	suite.refresh()
+
+	usage, err := suite.ingredientRepo.GetPopularIngredients(10)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), usage, 2, "unused ingredients are left out")
+
+	assert.Equal(suite.T(), "Onion", usage[0].Name)
+	assert.Equal(suite.T(), 2, usage[0].RecipeCount)
+	assert.Equal(suite.T(), "Salt", usage[1].Name)
+	assert.Equal(suite.T(), 1, usage[1].RecipeCount)
+}
+
+func TestStatsViewIntegrationSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+	suite.Run(t, new(StatsViewIntegrationSuite))
+}
@@ -0,0 +1,222 @@
+//go:build load
+
+package load
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"meal-prep/services/recipe-catalogue/handlers"
+	"meal-prep/services/recipe-catalogue/repository/memory"
+	"meal-prep/services/recipe-catalogue/service"
+	"meal-prep/services/recipe-catalogue/storage"
+	"meal-prep/shared/audit"
+	"meal-prep/shared/events"
+	"meal-prep/shared/idempotency"
+	"meal-prep/shared/logging"
+	"meal-prep/shared/middleware"
+	"meal-prep/shared/models"
+	"meal-prep/shared/testing/factory"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCatalogueLoad drives every catalogue scenario and fails if any of them
+// exceeds its latency baseline. By default it runs against an in-process
+// catalogue on in-memory storage; set LOAD_TARGET_URL to aim it at a running
+// service instead (recipes 1..LOAD_RECIPE_COUNT and ingredients
+// 1..LOAD_INGREDIENT_COUNT must exist there).
+//
+//	go test -tags load ./test/load/... -v
+func TestCatalogueLoad(t *testing.T) {
+	os.Setenv("LOG_LEVEL", "error")
+	logging.Init("load-test")
+
+	cfg := Config{
+		Rate:     envInt("LOAD_RATE", 50),
+		Duration: envDuration("LOAD_DURATION", 10*time.Second),
+		Workers:  envInt("LOAD_WORKERS", 50),
+	}
+
+	target := os.Getenv("LOAD_TARGET_URL")
+	recipeIDs := sequence(envInt("LOAD_RECIPE_COUNT", 500))
+	ingredientIDs := sequence(envInt("LOAD_INGREDIENT_COUNT", 100))
+
+	if target == "" {
+		server := httptest.NewServer(newSeededCatalogue(t, len(recipeIDs), len(ingredientIDs)))
+		defer server.Close()
+		target = server.URL
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	for _, scenario := range CatalogueScenarios(recipeIDs, ingredientIDs, loadTestToken(t)) {
+		scenario := scenario
+		t.Run(scenario.Name, func(t *testing.T) {
+			result := Run(context.Background(), client, target, scenario, cfg)
+			t.Log(result)
+
+			for _, violation := range result.Check(scenario.Baseline) {
+				t.Errorf("%s: %s", scenario.Name, violation)
+			}
+		})
+	}
+}
+
+// newSeededCatalogue builds the catalogue router on an in-memory store filled
+// with deterministic generated data.
+func newSeededCatalogue(t *testing.T, recipes, ingredients int) http.Handler {
+	t.Helper()
+
+	store := memory.NewStore()
+	store.SeedDefaults()
+	recipeRepo := memory.NewRecipeRepository(store)
+	categoryRepo := memory.NewCategoryRepository(store)
+	ingredientRepo := memory.NewIngredientRepository(store)
+
+	gen := factory.NewGenerator(2212)
+
+	existing, total, err := ingredientRepo.GetAllIngredients(models.PaginationParams{Page: 1, PerPage: 1000})
+	require.NoError(t, err)
+	pool := existing
+	for i := total; i < ingredients; i++ {
+		generated := gen.Ingredient()
+		created, err := ingredientRepo.CreateIngredient(models.CreateIngredientRequest{Name: generated.Name})
+		require.NoError(t, err)
+		pool = append(pool, *created)
+	}
+
+	categories, err := categoryRepo.GetAll()
+	require.NoError(t, err)
+	categoryIDs := make([]int, len(categories))
+	for i, c := range categories {
+		categoryIDs[i] = c.ID
+	}
+
+	for i := 0; i < recipes; i++ {
+		generated := gen.Recipe(categoryIDs...)
+		recipe, err := recipeRepo.Create(1+i%20, models.CreateRecipeRequest{
+			Name:        generated.Name,
+			Description: *generated.Description,
+			CategoryID:  *generated.CategoryID,
+		})
+		require.NoError(t, err)
+
+		var recipeIngredients []models.AddRecipeIngredientRequest
+		for _, idx := range randomIndexes(gen, len(pool), 3+gen.Intn(5)) {
+			ri := gen.RecipeIngredient(recipe.ID, pool[idx])
+			recipeIngredients = append(recipeIngredients, models.AddRecipeIngredientRequest{
+				IngredientID: pool[idx].ID,
+				Quantity:     ri.Quantity,
+				Unit:         ri.Unit,
+			})
+		}
+		require.NoError(t, ingredientRepo.SetRecipeIngredients(recipe.ID, recipeIngredients))
+	}
+
+	pantryEvents := service.NewPantryEvents()
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router,
+		handlers.NewRecipeHandler(service.NewRecipeService(recipeRepo, categoryRepo, ingredientRepo, memory.NewRecipeImageRepository(store), memory.NewFavoriteRepository(store), events.NewBus())),
+		handlers.NewIngredientHandler(service.NewIngredientService(ingredientRepo, recipeRepo)),
+		handlers.NewGroceryHandler(service.NewGroceryService(ingredientRepo, recipeRepo, memory.NewStoreLayoutRepository(store), memory.NewCostRepository(store),
+			memory.NewMealPlanRepository(store))),
+		handlers.NewCookingHandler(service.NewCookingService(recipeRepo, ingredientRepo,
+			memory.NewStepRepository(store), memory.NewCookingSessionRepository(store))),
+		handlers.NewRecipeImageHandler(service.NewRecipeImageService(recipeRepo, memory.NewRecipeImageRepository(store),
+			storage.NewLocalStore(t.TempDir(), "http://localhost:8000/images"))),
+		handlers.NewSocialHandler(service.NewSocialService(memory.NewFollowRepository(store), memory.NewActivityRepository(store))),
+		handlers.NewProfileHandler(service.NewProfileService(memory.NewProfileRepository(store), recipeRepo)),
+		handlers.NewLineageHandler(service.NewLineageService(memory.NewLineageRepository(store), recipeRepo,
+			memory.NewStepRepository(store), categoryRepo)),
+		handlers.NewCostHandler(service.NewCostService(memory.NewCostRepository(store), recipeRepo, ingredientRepo, events.NewBus())),
+		handlers.NewPrepHandler(service.NewPrepService(memory.NewPrepSessionRepository(store), recipeRepo, ingredientRepo,
+			memory.NewStepRepository(store))),
+		handlers.NewFeedHandler(service.NewFeedService(recipeRepo), "http://localhost:8000"),
+		handlers.NewEmbedHandler(service.NewEmbedService(recipeRepo, memory.NewRecipeImageRepository(store)), "http://localhost:8000"),
+		handlers.NewSavedSearchHandler(service.NewSavedSearchService(memory.NewSavedSearchRepository(store), recipeRepo,
+			categoryRepo, ingredientRepo, service.NewLogSearchAlertNotifier())),
+		handlers.NewQualityHandler(service.NewRecipeQualityService(memory.NewRecipeQualityRepository(store), ingredientRepo)),
+		handlers.NewIngredientCleanupHandler(service.NewIngredientCleanupService(memory.NewIngredientCleanupRepository(store), 6)),
+		handlers.NewUsageHandler(handlers.UsageMeterFromEnv()),
+		handlers.NewMealPlanHandler(service.NewMealPlanService(memory.NewMealPlanRepository(store), recipeRepo, ingredientRepo,
+			memory.NewStepRepository(store), nil)),
+		handlers.NewFavoriteHandler(service.NewFavoriteService(memory.NewFavoriteRepository(store), recipeRepo, memory.NewRecipeImageRepository(store))),
+		handlers.NewSharedListHandler(service.NewGroceryService(ingredientRepo, recipeRepo, memory.NewStoreLayoutRepository(store), memory.NewCostRepository(store),
+			memory.NewMealPlanRepository(store)), service.NewSharedListService(memory.NewSharedListRepository(store)), "http://localhost:8000"),
+		handlers.NewRecipeOwnershipHandler(service.NewRecipeOwnershipService(memory.NewRecipeOwnershipRepository(store))),
+		handlers.NewRecipeTemplateHandler(service.NewRecipeTemplateService(memory.NewRecipeTemplateRepository(store), recipeRepo,
+			memory.NewStepRepository(store), categoryRepo)),
+		handlers.NewBackfillHandler(service.NewBackfillService(memory.NewBackfillRepository(store),
+			service.NewCostService(memory.NewCostRepository(store), recipeRepo, ingredientRepo, events.NewBus()))),
+		handlers.NewRecipeImportHandler(service.NewRecipeImportService(recipeRepo, memory.NewStepRepository(store), categoryRepo, ingredientRepo,
+			handlers.NewPageFetcher())),
+		handlers.NewNutritionHandler(service.NewNutritionLookupService(nil, time.Hour, 0)),
+		handlers.NewPantryHandler(service.NewPantryService(memory.NewPantryRepository(store), ingredientRepo,
+			memory.NewRecipeImageRepository(store), memory.NewFavoriteRepository(store), pantryEvents)),
+		handlers.NewHouseholdHandler(service.NewHouseholdService(memory.NewHouseholdRepository(store), pantryEvents)),
+		audit.NewAuditor(audit.NewMemoryStore()),
+		idempotency.NewKeys(idempotency.NewMemoryStore()),
+	)
+	return router
+}
+
+func randomIndexes(gen *factory.Generator, n, k int) []int {
+	seen := make(map[int]bool, k)
+	var indexes []int
+	for len(indexes) < k && len(indexes) < n {
+		i := gen.Intn(n)
+		if !seen[i] {
+			seen[i] = true
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func loadTestToken(t *testing.T) string {
+	t.Helper()
+
+	if token := os.Getenv("LOAD_TOKEN"); token != "" {
+		return token
+	}
+
+	claims := middleware.Claims{
+		UserID: 1,
+		Email:  "load@example.com",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("load-test"))
+	require.NoError(t, err)
+	return token
+}
+
+func sequence(n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	return ids
+}
+
+func envInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
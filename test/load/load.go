@@ -0,0 +1,189 @@
+// Package load is a small constant-rate load generator for the HTTP services.
+// Scenarios describe the request to send and the latency baseline it must
+// stay within; Run fires them at a fixed rate and reports percentiles so
+// performance regressions show up as failing assertions rather than anecdotes.
+package load
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Scenario is one kind of request sent repeatedly during a run. NewRequest
+// receives the sequence number of the request so scenarios can vary inputs.
+type Scenario struct {
+	Name       string
+	NewRequest func(baseURL string, seq int) (*http.Request, error)
+	// ExpectStatus is the status that counts as success. Zero means any 2xx.
+	ExpectStatus int
+	Baseline     Baseline
+}
+
+// Baseline is the latency budget a scenario must meet. Zero values are not checked.
+type Baseline struct {
+	P50          time.Duration
+	P95          time.Duration
+	P99          time.Duration
+	MaxErrorRate float64
+}
+
+// Config controls how hard a scenario is driven.
+type Config struct {
+	Rate     int           // requests per second
+	Duration time.Duration // how long to keep sending
+	Workers  int           // maximum requests in flight
+}
+
+// Result summarises one scenario run.
+type Result struct {
+	Scenario  string
+	Requests  int
+	Errors    int
+	Elapsed   time.Duration
+	latencies []time.Duration
+}
+
+// Run sends requests for s at cfg.Rate until cfg.Duration elapses or ctx is
+// cancelled. Requests that cannot be dispatched because every worker is busy
+// are counted as errors, so an overloaded target fails the error-rate check
+// instead of silently lowering the offered load.
+func Run(ctx context.Context, client *http.Client, baseURL string, s Scenario, cfg Config) Result {
+	if cfg.Workers <= 0 {
+		cfg.Workers = cfg.Rate
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		result = Result{Scenario: s.Name}
+		slots  = make(chan struct{}, cfg.Workers)
+	)
+
+	record := func(latency time.Duration, ok bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		result.Requests++
+		if ok {
+			result.latencies = append(result.latencies, latency)
+		} else {
+			result.Errors++
+		}
+	}
+
+	ticker := time.NewTicker(time.Second / time.Duration(cfg.Rate))
+	defer ticker.Stop()
+
+	start := time.Now()
+	for seq := 0; ; seq++ {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			result.Elapsed = time.Since(start)
+			return result
+		case <-ticker.C:
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			record(0, false)
+			continue
+		}
+
+		wg.Add(1)
+		go func(seq int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			latency, ok := send(client, baseURL, s, seq)
+			record(latency, ok)
+		}(seq)
+	}
+}
+
+func send(client *http.Client, baseURL string, s Scenario, seq int) (time.Duration, bool) {
+	req, err := s.NewRequest(baseURL, seq)
+	if err != nil {
+		return 0, false
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, false
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	latency := time.Since(start)
+
+	if s.ExpectStatus != 0 {
+		return latency, resp.StatusCode == s.ExpectStatus
+	}
+	return latency, resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// Percentile returns the latency below which p percent of successful
+// requests completed, using the nearest-rank method.
+func (r Result) Percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration(nil), r.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(p/100*float64(len(sorted)) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+func (r Result) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// Check compares the result against b and describes every budget it exceeded.
+func (r Result) Check(b Baseline) []string {
+	var violations []string
+
+	check := func(name string, p float64, limit time.Duration) {
+		if limit > 0 {
+			if got := r.Percentile(p); got > limit {
+				violations = append(violations, fmt.Sprintf("%s %s exceeds baseline %s", name, got, limit))
+			}
+		}
+	}
+	check("p50", 50, b.P50)
+	check("p95", 95, b.P95)
+	check("p99", 99, b.P99)
+
+	if r.ErrorRate() > b.MaxErrorRate {
+		violations = append(violations, fmt.Sprintf("error rate %.2f%% exceeds baseline %.2f%%",
+			r.ErrorRate()*100, b.MaxErrorRate*100))
+	}
+	if r.Requests == 0 {
+		violations = append(violations, "no requests were sent")
+	}
+	return violations
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%s: %d requests in %s, %d errors, p50=%s p95=%s p99=%s",
+		r.Scenario, r.Requests, r.Elapsed.Round(time.Millisecond), r.Errors,
+		r.Percentile(50), r.Percentile(95), r.Percentile(99))
+}
@@ -0,0 +1,74 @@
+package load
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resultWithLatencies(ms ...int) Result {
+	r := Result{Scenario: "test", Requests: len(ms)}
+	for _, m := range ms {
+		r.latencies = append(r.latencies, time.Duration(m)*time.Millisecond)
+	}
+	return r
+}
+
+func TestPercentile_NearestRank(t *testing.T) {
+	r := resultWithLatencies(50, 10, 40, 20, 30, 60, 70, 80, 90, 100)
+
+	assert.Equal(t, 50*time.Millisecond, r.Percentile(50))
+	assert.Equal(t, 100*time.Millisecond, r.Percentile(95))
+	assert.Equal(t, 10*time.Millisecond, r.Percentile(1))
+	assert.Equal(t, time.Duration(0), Result{}.Percentile(95))
+}
+
+func TestCheck_ReportsExceededBudgets(t *testing.T) {
+	r := resultWithLatencies(10, 10, 10, 10, 200)
+	r.Requests, r.Errors = 10, 5
+
+	violations := r.Check(Baseline{P50: 20 * time.Millisecond, P99: 100 * time.Millisecond, MaxErrorRate: 0.1})
+
+	require.Len(t, violations, 2)
+	assert.Contains(t, violations[0], "p99")
+	assert.Contains(t, violations[1], "error rate")
+}
+
+func TestCheck_WithinBaseline(t *testing.T) {
+	r := resultWithLatencies(5, 6, 7)
+
+	assert.Empty(t, r.Check(Baseline{P50: 10 * time.Millisecond, P95: 10 * time.Millisecond}))
+}
+
+func TestRun_CountsUnexpectedStatusAsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") == "true" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	scenario := Scenario{
+		Name: "alternating",
+		NewRequest: func(baseURL string, seq int) (*http.Request, error) {
+			if seq%2 == 1 {
+				return http.NewRequest(http.MethodGet, baseURL+"?fail=true", nil)
+			}
+			return http.NewRequest(http.MethodGet, baseURL, nil)
+		},
+	}
+
+	result := Run(context.Background(), server.Client(), server.URL, scenario,
+		Config{Rate: 200, Duration: 200 * time.Millisecond, Workers: 10})
+
+	require.Greater(t, result.Requests, 10)
+	assert.InDelta(t, 0.5, result.ErrorRate(), 0.15)
+	assert.Greater(t, result.Percentile(50), time.Duration(0))
+}
@@ -0,0 +1,55 @@
+package load
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CatalogueScenarios returns the recipe listing, ingredient search and
+// grocery list scenarios. recipeIDs and ingredientIDs must exist on the
+// target; token is sent as the bearer token for the protected endpoint.
+func CatalogueScenarios(recipeIDs, ingredientIDs []int, token string) []Scenario {
+	return []Scenario{
+		{
+			Name: "list-recipes",
+			NewRequest: func(baseURL string, seq int) (*http.Request, error) {
+				return http.NewRequest(http.MethodGet, fmt.Sprintf("%s/recipes?page=%d&per_page=20", baseURL, 1+seq%5), nil)
+			},
+			Baseline: Baseline{P50: 20 * time.Millisecond, P95: 75 * time.Millisecond, P99: 150 * time.Millisecond, MaxErrorRate: 0.01},
+		},
+		{
+			Name: "search-by-ingredients",
+			NewRequest: func(baseURL string, seq int) (*http.Request, error) {
+				first := ingredientIDs[seq%len(ingredientIDs)]
+				second := ingredientIDs[(seq*7+3)%len(ingredientIDs)]
+				return http.NewRequest(http.MethodGet, fmt.Sprintf("%s/recipes/search?ingredient_ids=%d,%d", baseURL, first, second), nil)
+			},
+			Baseline: Baseline{P50: 25 * time.Millisecond, P95: 100 * time.Millisecond, P99: 200 * time.Millisecond, MaxErrorRate: 0.01},
+		},
+		{
+			Name: "grocery-list",
+			NewRequest: func(baseURL string, seq int) (*http.Request, error) {
+				ids := make([]int, 0, 5)
+				for i := 0; i < 5; i++ {
+					ids = append(ids, recipeIDs[(seq*5+i)%len(recipeIDs)])
+				}
+				body, err := json.Marshal(map[string][]int{"recipe_ids": ids})
+				if err != nil {
+					return nil, err
+				}
+
+				req, err := http.NewRequest(http.MethodPost, baseURL+"/grocery-list", bytes.NewReader(body))
+				if err != nil {
+					return nil, err
+				}
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+token)
+				return req, nil
+			},
+			Baseline: Baseline{P50: 30 * time.Millisecond, P95: 120 * time.Millisecond, P99: 250 * time.Millisecond, MaxErrorRate: 0.01},
+		},
+	}
+}